@@ -2,41 +2,171 @@ package app
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/netip"
+	"os"
+	"sync"
 	"time"
 
+	"google.golang.org/grpc"
+
 	"kitadoc-backend/config"
 	"kitadoc-backend/data"
+	"kitadoc-backend/graphqlapi"
+	"kitadoc-backend/grpcapi"
 	"kitadoc-backend/handlers"
+	"kitadoc-backend/internal/buildinfo"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/internal/errorreport"
+	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 )
 
+// Password confirmations for bulk exports are limited independently of the download limit, so that a
+// stolen session cannot be used to guess the password.
+const (
+	maxReauthenticationAttempts   = 5
+	reauthenticationAttemptWindow = 15 * time.Minute
+)
+
 // Application holds the application's services and router.
 type Application struct {
-	AuthHandler               *handlers.AuthHandler
-	ChildHandler              *handlers.ChildHandler
-	TeacherHandler            *handlers.TeacherHandler
-	CategoryHandler           *handlers.CategoryHandler
-	AssignmentHandler         *handlers.AssignmentHandler
-	DocumentationEntryHandler *handlers.DocumentationEntryHandler
-	AudioRecordingHandler     *handlers.AudioRecordingHandler
-	DocumentGenerationHandler *handlers.DocumentGenerationHandler
-	BulkOperationsHandler     *handlers.BulkOperationsHandler
-	KitaMasterdataHandler     *handlers.KitaMasterdataHandler
-	ProcessHandler            *handlers.ProcessHandler
-	Router                    *http.ServeMux
-	Config                    config.Config
+	AuthHandler                *handlers.AuthHandler
+	ChildHandler               *handlers.ChildHandler
+	TeacherHandler             *handlers.TeacherHandler
+	CategoryHandler            *handlers.CategoryHandler
+	AssignmentHandler          *handlers.AssignmentHandler
+	DocumentationEntryHandler  *handlers.DocumentationEntryHandler
+	DocumentationEventHandler  *handlers.DocumentationEventHandler
+	AudioRecordingHandler      *handlers.AudioRecordingHandler
+	DocumentGenerationHandler  *handlers.DocumentGenerationHandler
+	BulkOperationsHandler      *handlers.BulkOperationsHandler
+	KitaMasterdataHandler      *handlers.KitaMasterdataHandler
+	ProcessHandler             *handlers.ProcessHandler
+	NotificationHandler        *handlers.NotificationHandler
+	AnnouncementHandler        *handlers.AnnouncementHandler
+	SchoolYearHandler          *handlers.SchoolYearHandler
+	RedactionProfileHandler    *handlers.RedactionProfileHandler
+	ApprovalDelegationHandler  *handlers.ApprovalDelegationHandler
+	AuditLogHandler            *handlers.AuditLogHandler
+	ValidationRuleHandler      *handlers.ValidationRuleHandler
+	CompletenessHandler        *handlers.CompletenessHandler
+	DemoModeHandler            *handlers.DemoModeHandler
+	RoutePolicyHandler         *handlers.RoutePolicyHandler
+	GraphQLHandler             *graphqlapi.Handler
+	OutboxHandler              *handlers.OutboxHandler
+	BootstrapHandler           *handlers.BootstrapHandler
+	BreakGlassHandler          *handlers.BreakGlassHandler
+	GroupHandler               *handlers.GroupHandler
+	SchoolHandler              *handlers.SchoolHandler
+	SupportProviderHandler     *handlers.SupportProviderHandler
+	DailyCareHandler           *handlers.DailyCareHandler
+	IncidentHandler            *handlers.IncidentHandler
+	MedicationHandler          *handlers.MedicationHandler
+	GalleryHandler             *handlers.GalleryHandler
+	ChildDocumentHandler       *handlers.ChildDocumentHandler
+	AttendanceHandler          *handlers.AttendanceHandler
+	CareContractHandler        *handlers.CareContractHandler
+	ProjectHandler             *handlers.ProjectHandler
+	TeamMeetingHandler         *handlers.TeamMeetingHandler
+	GroupHandoverHandler       *handlers.GroupHandoverHandler
+	FeeExportHandler           *handlers.FeeExportHandler
+	InvitationHandler          *handlers.InvitationHandler
+	AnonymousStatisticsHandler *handlers.AnonymousStatisticsHandler
+	QueryPlanHandler           *handlers.QueryPlanHandler
+	DigestHandler              *handlers.DigestHandler
+	QualityReportHandler       *handlers.QualityReportHandler
+	BenchmarkHandler           *handlers.BenchmarkHandler
+	UploadScanHandler          *handlers.UploadScanHandler
+	ResumableUploadHandler     *handlers.ResumableUploadHandler
+	TermsHandler               *handlers.TermsHandler
+	StatusHandler              *handlers.StatusHandler
+	EmailTemplateHandler       *handlers.EmailTemplateHandler
+	UptimeHandler              *handlers.UptimeHandler
+	EditLockHandler            *handlers.EditLockHandler
+	ReportShareLinkHandler     *handlers.ReportShareLinkHandler
+	FixtureHandler             *handlers.FixtureHandler // Only set for the fixture profile
+	Router                     *http.ServeMux
+	Policies                   *middleware.PolicyEngine // Access policies of the routes registered on Router
+	ReportingServer            *grpcapi.ReportingServer
+	OutboxDispatcher           *services.OutboxDispatcher
+	DigestSender               *services.DigestServiceImpl        // Enqueues the weekly digests, nil when emails are disabled
+	QualityReports             *services.QualityReportServiceImpl // Archives the monthly quality reports
+	Uptime                     *services.UptimeServiceImpl        // Records the health checks of the uptime history
+	Fixtures                   *services.FixtureServiceImpl       // Seeds the fixture server, nil outside of the fixture profile
+	Config                     config.Config
+	ErrorReporter              errorreport.Reporter // Receives panics and internal errors, nil disables reporting
+
+	termsService           services.TermsService     // Checks on every authenticated request that the current terms are accepted
+	downloadThrottle       *middleware.UserThrottle  // Shared by all routes handing out reports and exports
+	reauthenticateThrottle *middleware.UserThrottle  // Limits password guessing on the re-authentication route
+	breakGlassThrottle     *middleware.UserThrottle  // Limits the attempts to activate the break-glass access per client
+	documentPool           *services.DocumentPool    // Limits the reports generated at the same time, nil if unlimited
+	clock                  clock.Clock               // Frozen in the fixture profile
+	chaos                  *middleware.ChaosInjector // Injects latency and errors for the frontend, only set by the -chaos flag
+
+	adminAllowlist      *middleware.NetworkAllowlist // Networks the admin routes are reachable from
+	bulkExportAllowlist *middleware.NetworkAllowlist // Networks the bulk exports are reachable from
+	auditLogService     services.AuditLogService
+	authSessions        data.AuthSessionStore // The sessions of the production database, also checked in demo mode
+
+	demoModeService services.DemoModeService
+	isDemo          bool // Serves the anonymized demo dataset
+	demoMu          sync.Mutex
+	demoDAL         *data.DAL
+	demoRouter      http.Handler
 }
 
 // NewApplication initializes a new Application with all handlers and services.
 func NewApplication(cfg config.Config, dal *data.DAL) *Application {
+	// Date-only values are compared to today in the time zone of the facility, see models.Today.
+	if cfg.Facility.Location != nil {
+		models.SetFacilityLocation(cfg.Facility.Location)
+	}
+	var appClock clock.Clock = clock.System{}
+	var frozen *clock.Frozen
+	if cfg.Environment == config.ProfileFixture {
+		frozen = clock.NewFrozen(services.FixtureTime)
+		appClock = frozen
+	}
+	app := newApplication(cfg, dal, dal.AuthSessions, services.NewDemoModeService(dal.DemoSnapshots, cfg.Session.RefreshTokenValidity, appClock), newDocumentPool(cfg), appClock)
+	app.ErrorReporter = newErrorReporter(cfg)
+	if frozen != nil {
+		app.Fixtures = services.NewFixtureService(dal, frozen)
+		app.FixtureHandler = handlers.NewFixtureHandler(app.Fixtures)
+	}
+	return app
+}
+
+// newApplication initializes an Application on the given data. The sessions, the demo mode service, the document
+// pool and the clock are shared between the production application and the one serving the demo dataset, so that
+// logins after the demo snapshot was taken and logouts in demo mode apply to both.
+func newApplication(cfg config.Config, dal *data.DAL, authSessions data.AuthSessionStore, demoModeService services.DemoModeService, documentPool *services.DocumentPool, appClock clock.Clock) *Application {
 	// Initialize Services
-	userService := services.NewUserService(dal.Users, &cfg)
-	childService := services.NewChildService(dal.Children)
-	teacherService := services.NewTeacherService(dal.Teachers)
-	categoryService := services.NewCategoryService(dal.Categories)
-	assignmentService := services.NewAssignmentService(dal.Assignments, dal.Children, dal.Teachers)
+	userService := services.NewUserService(dal.Users, authSessions, &cfg, appClock)
+	childService := services.NewChildService(dal.Children, dal.Groups, dal.Assignments, dal.Teachers, dal.DocumentationEntries, appClock)
+	teacherService := services.NewTeacherService(dal.Teachers, appClock)
+	categoryService := services.NewCategoryService(dal.Categories, dal.Teachers, appClock)
+	validationRuleService := services.NewValidationRuleService(dal.ValidationRules, dal.DocumentationEntries, appClock)
+	assignmentService := services.NewAssignmentService(dal.Assignments, dal.Children, dal.Teachers, validationRuleService, appClock)
+	pushGateways, vapidPublicKey := newPushGateways(cfg)
+	auditLogService := services.NewAuditLogService(dal.AuditLog)
+	uploadScanService := services.NewUploadScanService(newVirusScanner(cfg), dal.QuarantinedUploads, auditLogService, appClock)
+	resumableUploadService := services.NewResumableUploadService(dal.ResumableUploads, &cfg, appClock)
+	notificationService := services.NewNotificationService(
+		dal.Devices,
+		dal.NotificationPreferences,
+		dal.Teachers,
+		dal.Users,
+		auditLogService,
+		pushGateways,
+		appClock,
+	)
+	approvalDelegationService := services.NewApprovalDelegationService(dal.ApprovalDelegations, dal.Users)
+	documentationEventService := services.NewDocumentationEventService(dal.DocumentationEvents, dal.Children, appClock)
 	documentationEntryService := services.NewDocumentationEntryService(
 		dal.DocumentationEntries,
 		dal.Children,
@@ -44,6 +174,14 @@ func NewApplication(cfg config.Config, dal *data.DAL) *Application {
 		dal.Categories,
 		dal.Users,
 		dal.KitaMasterdata,
+		notificationService,
+		approvalDelegationService,
+		auditLogService,
+		validationRuleService,
+		documentationEventService,
+		documentPool,
+		dal.Projects,
+		appClock,
 	)
 	audioAnalysisService := services.NewAudioAnalysisService(
 		&http.Client{Timeout: 10 * time.Minute},
@@ -55,130 +193,791 @@ func NewApplication(cfg config.Config, dal *data.DAL) *Application {
 	)
 	kitaMasterdataService := services.NewKitaMasterdataService(dal.KitaMasterdata)
 	processService := services.NewProcessService(dal.Processes)
+	announcementService := services.NewAnnouncementService(dal.Announcements)
+	schoolYearService := services.NewSchoolYearService(dal.SchoolYears, dal.Teachers)
+	redactionProfileService := services.NewRedactionProfileService(dal.RedactionProfiles)
+	completenessService := services.NewCompletenessService(dal.Children, dal.Categories, dal.DocumentationEntries, appClock)
+	bootstrapService := services.NewBootstrapService(dal.Bootstrap, appClock)
+	groupService := services.NewGroupService(dal.Groups, dal.Children, dal.Teachers, dal.CareContracts, appClock)
+	schoolService := services.NewSchoolService(dal.Schools, dal.Children)
+	supportProviderService := services.NewSupportProviderService(dal.SupportProviders, dal.Children)
+	galleryService := services.NewGalleryService(dal.Gallery, dal.Children, dal.Groups, cfg.ClientEncryption.Mode)
+	childDocumentService := services.NewChildDocumentService(dal.ChildDocuments, dal.Children, appClock, cfg.ClientEncryption.Mode)
+	attendanceService := services.NewAttendanceService(dal.Attendance, dal.Children, appClock)
+	careContractService := services.NewCareContractService(dal.CareContracts, dal.Children, dal.Attendance, appClock)
+	projectService := services.NewProjectService(dal.Projects, dal.Groups, dal.Categories, dal.DocumentationEntries)
+	teamMeetingService := services.NewTeamMeetingService(dal.TeamMeetings, dal.Teachers, dal.Children, appClock)
+	groupHandoverService := services.NewGroupHandoverService(dal.GroupHandovers, dal.Children, dal.Groups, dal.Teachers, dal.Categories,
+		dal.DocumentationEntries, dal.DailyCare, dal.Medications, dal.SupportProviders, dal.TeamMeetings, appClock)
+	feeExportService := services.NewFeeExportService(dal.Children, dal.CareContracts, dal.Attendance, auditLogService, &cfg, appClock)
+	dailyCareService := services.NewDailyCareService(dal.DailyCare, dal.Children, dal.Groups, appClock)
+	incidentService := services.NewIncidentService(dal.Incidents, dal.Children, dal.KitaMasterdata, appClock)
+	medicationService := services.NewMedicationService(dal.Medications, dal.Children, dal.Teachers, appClock)
+	importJobService := services.NewImportJobService(dal.ImportJobs)
+	invitationService := services.NewInvitationService(dal.Invitations, userService, &cfg, appClock)
+	anonymousStatisticsService := services.NewAnonymousStatisticsService(dal.Children, dal.Categories, dal.DocumentationEntries, auditLogService, pseudonymKey(cfg), appClock)
+	documentationImportService := services.NewDocumentationImportService(
+		dal.Children,
+		dal.Teachers,
+		dal.Categories,
+		dal.DocumentationEntries,
+		documentationEventService,
+		appClock,
+	)
+	queryPlanService := services.NewQueryPlanService(dal.QueryPlans)
+	digestService := services.NewDigestService(
+		dal.Users,
+		dal.Teachers,
+		dal.Children,
+		dal.Assignments,
+		dal.DocumentationEntries,
+		dal.DocumentationEvents,
+		dal.NotificationPreferences,
+		dal.Digests,
+		appClock,
+	)
+	qualityReportService := services.NewQualityReportService(
+		dal.Groups,
+		dal.Teachers,
+		dal.Assignments,
+		dal.DocumentationEntries,
+		dal.DocumentationEvents,
+		dal.AuditLog,
+		dal.KitaMasterdata,
+		dal.QualityReports,
+		appClock,
+	)
+	benchmarkService := services.NewBenchmarkService(qualityReportService, dal.Benchmarks, pseudonymKey(cfg), cfg.Benchmarking.Enabled, cfg.Benchmarking.MinPeers, appClock)
+	termsService := services.NewTermsService(dal.Terms, auditLogService)
+	emailTemplateService := services.NewEmailTemplateService(dal.EmailTemplates, auditLogService, appClock)
+	uptimeService := services.NewUptimeService(dal.Uptime, cfg.Monitoring.HealthCheckInterval, cfg.Monitoring.UptimeRetention, appClock)
+	outboxDeliverers := map[string]services.OutboxDeliverer{
+		models.OutboxChannelPush: notificationService,
+	}
+	var digestSender *services.DigestServiceImpl
+	if mailer := newMailer(cfg); mailer != nil {
+		outboxDeliverers[models.OutboxChannelEmail] = mailer
+		digestSender = digestService
+	}
+	outboxDispatcher := services.NewOutboxDispatcher(dal.Outbox, outboxDeliverers, cfg.Outbox.MaxAttempts, appClock)
+	statusService := services.NewStatusService(dal.StatusBanner, map[string]bool{
+		"open_registration":  cfg.Registration.Open,
+		"push_notifications": len(pushGateways) > 0,
+		"web_push":           vapidPublicKey != "",
+		"email_digest":       digestSender != nil,
+		"audio_analysis":     cfg.TranscriptionServiceURL != "" && cfg.LLMAnalysisServiceURL != "",
+	}, appClock)
 
 	// Initialize Handlers
-	authHandler := handlers.NewAuthHandler(userService)
+	authHandler := handlers.NewAuthHandler(userService, &cfg)
 	childHandler := handlers.NewChildHandler(childService)
 	teacherHandler := handlers.NewTeacherHandler(teacherService)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
-	assignmentHandler := handlers.NewAssignmentHandler(assignmentService)
+	assignmentHandler := handlers.NewAssignmentHandler(assignmentService, appClock)
 	documentationEntryHandler := handlers.NewDocumentationEntryHandler(documentationEntryService)
-	audioRecordingHandler := handlers.NewAudioRecordingHandler(audioAnalysisService, documentationEntryService, processService, &cfg)
-	documentGenerationHandler := handlers.NewDocumentGenerationHandler(documentationEntryService, assignmentService)
-	bulkOperationsHandler := handlers.NewBulkOperationsHandler(childService)
-	kitaMasterdataHandler := handlers.NewKitaMasterdataHandler(kitaMasterdataService)
+	documentationEventHandler := handlers.NewDocumentationEventHandler(documentationEventService)
+	audioRecordingHandler := handlers.NewAudioRecordingHandler(audioAnalysisService, documentationEntryService, processService, uploadScanService, resumableUploadService, &cfg)
+	documentGenerationHandler := handlers.NewDocumentGenerationHandler(documentationEntryService, assignmentService, redactionProfileService, completenessService, supportProviderService, galleryService, appClock)
+	bulkOperationsHandler := handlers.NewBulkOperationsHandler(importJobService, documentationImportService, uploadScanService)
+	kitaMasterdataHandler := handlers.NewKitaMasterdataHandler(kitaMasterdataService, uploadScanService)
 	processHandler := handlers.NewProcessHandler(processService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, vapidPublicKey)
+	announcementHandler := handlers.NewAnnouncementHandler(announcementService)
+	schoolYearHandler := handlers.NewSchoolYearHandler(schoolYearService)
+	redactionProfileHandler := handlers.NewRedactionProfileHandler(redactionProfileService)
+	approvalDelegationHandler := handlers.NewApprovalDelegationHandler(approvalDelegationService)
+	auditLogHandler := handlers.NewAuditLogHandler(auditLogService)
+	validationRuleHandler := handlers.NewValidationRuleHandler(validationRuleService)
+	completenessHandler := handlers.NewCompletenessHandler(completenessService)
+	demoModeHandler := handlers.NewDemoModeHandler(demoModeService)
+	outboxHandler := handlers.NewOutboxHandler(outboxDispatcher)
+	bootstrapHandler := handlers.NewBootstrapHandler(bootstrapService)
+	breakGlassHandler := handlers.NewBreakGlassHandler(services.NewBreakGlassService(dal.BreakGlass, dal.Users, authSessions, auditLogService, &cfg, appClock))
+	groupHandler := handlers.NewGroupHandler(groupService)
+	schoolHandler := handlers.NewSchoolHandler(schoolService)
+	supportProviderHandler := handlers.NewSupportProviderHandler(supportProviderService)
+	dailyCareHandler := handlers.NewDailyCareHandler(dailyCareService, appClock)
+	incidentHandler := handlers.NewIncidentHandler(incidentService)
+	medicationHandler := handlers.NewMedicationHandler(medicationService)
+	galleryHandler := handlers.NewGalleryHandler(galleryService, uploadScanService, resumableUploadService)
+	childDocumentHandler := handlers.NewChildDocumentHandler(childDocumentService, uploadScanService, resumableUploadService)
+	attendanceHandler := handlers.NewAttendanceHandler(attendanceService, appClock)
+	careContractHandler := handlers.NewCareContractHandler(careContractService)
+	projectHandler := handlers.NewProjectHandler(projectService)
+	teamMeetingHandler := handlers.NewTeamMeetingHandler(teamMeetingService)
+	groupHandoverHandler := handlers.NewGroupHandoverHandler(groupHandoverService)
+	feeExportHandler := handlers.NewFeeExportHandler(feeExportService, appClock)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	anonymousStatisticsHandler := handlers.NewAnonymousStatisticsHandler(anonymousStatisticsService)
+	queryPlanHandler := handlers.NewQueryPlanHandler(queryPlanService)
+	digestHandler := handlers.NewDigestHandler(digestService, appClock)
+	qualityReportHandler := handlers.NewQualityReportHandler(qualityReportService)
+	benchmarkHandler := handlers.NewBenchmarkHandler(benchmarkService, appClock)
+	uploadScanHandler := handlers.NewUploadScanHandler(uploadScanService)
+	resumableUploadHandler := handlers.NewResumableUploadHandler(resumableUploadService)
+	termsHandler := handlers.NewTermsHandler(termsService)
+	statusHandler := handlers.NewStatusHandler(statusService)
+	emailTemplateHandler := handlers.NewEmailTemplateHandler(emailTemplateService)
+	uptimeHandler := handlers.NewUptimeHandler(uptimeService, appClock)
+	editLockHandler := handlers.NewEditLockHandler(services.NewEditLockService(dal.EditLocks, dal.DocumentationEntries, dal.Users, dal.Teachers, appClock))
+	reportShareLinkHandler := handlers.NewReportShareLinkHandler(services.NewReportShareLinkService(dal.ReportShareLinks, dal.DocumentationEntries, auditLogService, &cfg, appClock))
+	policies := middleware.NewPolicyEngine()
+	routePolicyHandler := handlers.NewRoutePolicyHandler(policies)
+	graphQLHandler := graphqlapi.NewHandler(&graphqlapi.Resolver{
+		ChildService:              childService,
+		DocumentationEntryService: documentationEntryService,
+		AssignmentService:         assignmentService,
+		CompletenessService:       completenessService,
+		CategoryService:           categoryService,
+		TeacherService:            teacherService,
+		TeamMeetingService:        teamMeetingService,
+	})
+	reportingServer := grpcapi.NewReportingServer(childService, documentationEntryService, completenessService)
 
 	app := &Application{
-		AuthHandler:               authHandler,
-		ChildHandler:              childHandler,
-		TeacherHandler:            teacherHandler,
-		CategoryHandler:           categoryHandler,
-		AssignmentHandler:         assignmentHandler,
-		DocumentationEntryHandler: documentationEntryHandler,
-		AudioRecordingHandler:     audioRecordingHandler,
-		DocumentGenerationHandler: documentGenerationHandler,
-		BulkOperationsHandler:     bulkOperationsHandler,
-		KitaMasterdataHandler:     kitaMasterdataHandler,
-		ProcessHandler:            processHandler,
-		Router:                    http.NewServeMux(),
-		Config:                    cfg,
+		AuthHandler:                authHandler,
+		ChildHandler:               childHandler,
+		TeacherHandler:             teacherHandler,
+		CategoryHandler:            categoryHandler,
+		AssignmentHandler:          assignmentHandler,
+		DocumentationEntryHandler:  documentationEntryHandler,
+		DocumentationEventHandler:  documentationEventHandler,
+		AudioRecordingHandler:      audioRecordingHandler,
+		DocumentGenerationHandler:  documentGenerationHandler,
+		BulkOperationsHandler:      bulkOperationsHandler,
+		KitaMasterdataHandler:      kitaMasterdataHandler,
+		ProcessHandler:             processHandler,
+		NotificationHandler:        notificationHandler,
+		AnnouncementHandler:        announcementHandler,
+		SchoolYearHandler:          schoolYearHandler,
+		RedactionProfileHandler:    redactionProfileHandler,
+		ApprovalDelegationHandler:  approvalDelegationHandler,
+		AuditLogHandler:            auditLogHandler,
+		ValidationRuleHandler:      validationRuleHandler,
+		CompletenessHandler:        completenessHandler,
+		DemoModeHandler:            demoModeHandler,
+		RoutePolicyHandler:         routePolicyHandler,
+		GraphQLHandler:             graphQLHandler,
+		OutboxHandler:              outboxHandler,
+		BootstrapHandler:           bootstrapHandler,
+		BreakGlassHandler:          breakGlassHandler,
+		GroupHandler:               groupHandler,
+		SchoolHandler:              schoolHandler,
+		SupportProviderHandler:     supportProviderHandler,
+		DailyCareHandler:           dailyCareHandler,
+		IncidentHandler:            incidentHandler,
+		MedicationHandler:          medicationHandler,
+		GalleryHandler:             galleryHandler,
+		ChildDocumentHandler:       childDocumentHandler,
+		AttendanceHandler:          attendanceHandler,
+		CareContractHandler:        careContractHandler,
+		ProjectHandler:             projectHandler,
+		TeamMeetingHandler:         teamMeetingHandler,
+		GroupHandoverHandler:       groupHandoverHandler,
+		FeeExportHandler:           feeExportHandler,
+		InvitationHandler:          invitationHandler,
+		AnonymousStatisticsHandler: anonymousStatisticsHandler,
+		QueryPlanHandler:           queryPlanHandler,
+		DigestHandler:              digestHandler,
+		QualityReportHandler:       qualityReportHandler,
+		BenchmarkHandler:           benchmarkHandler,
+		UploadScanHandler:          uploadScanHandler,
+		ResumableUploadHandler:     resumableUploadHandler,
+		TermsHandler:               termsHandler,
+		StatusHandler:              statusHandler,
+		EmailTemplateHandler:       emailTemplateHandler,
+		UptimeHandler:              uptimeHandler,
+		EditLockHandler:            editLockHandler,
+		ReportShareLinkHandler:     reportShareLinkHandler,
+		Router:                     http.NewServeMux(),
+		Policies:                   policies,
+		downloadThrottle:           middleware.NewUserThrottle(cfg.Exports.MaxDownloads, cfg.Exports.DownloadWindow),
+		reauthenticateThrottle:     middleware.NewUserThrottle(maxReauthenticationAttempts, reauthenticationAttemptWindow),
+		breakGlassThrottle:         middleware.NewUserThrottle(cfg.BreakGlass.MaxAttempts, cfg.BreakGlass.AttemptWindow),
+		ReportingServer:            reportingServer,
+		OutboxDispatcher:           outboxDispatcher,
+		DigestSender:               digestSender,
+		QualityReports:             qualityReportService,
+		Uptime:                     uptimeService,
+		Config:                     cfg,
+		termsService:               termsService,
+		documentPool:               documentPool,
+		demoModeService:            demoModeService,
+		clock:                      appClock,
+		auditLogService:            auditLogService,
+		authSessions:               authSessions,
+	}
+	app.adminAllowlist = middleware.NewNetworkAllowlist(cfg.NetworkAllowlist.AdminNetworks, cfg.NetworkAllowlist.TrustedProxyNetworks, app.recordNetworkDenial)
+	app.bulkExportAllowlist = middleware.NewNetworkAllowlist(cfg.NetworkAllowlist.BulkExportNetworks, cfg.NetworkAllowlist.TrustedProxyNetworks, app.recordNetworkDenial)
+	if cfg.Chaos.Enabled {
+		app.chaos = middleware.NewChaosInjector(cfg.Chaos.Rules)
 	}
 
 	// Don't set up routes automatically here
 	return app
 }
 
+// pseudonymKey returns the key the pseudonyms of the anonymous statistics are derived from.
+func pseudonymKey(cfg config.Config) []byte {
+	if cfg.Exports.PseudonymKey != "" {
+		return []byte(cfg.Exports.PseudonymKey)
+	}
+	return []byte(cfg.Server.JWTSecret + ":pseudonyms")
+}
+
+// newPushGateways creates a push gateway for every platform that is configured.
+// It also returns the VAPID public key, which is empty when Web Push is disabled.
+func newPushGateways(cfg config.Config) (map[string]services.PushGateway, string) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	gateways := make(map[string]services.PushGateway)
+	if cfg.Push.FCMServerKey != "" {
+		gateways[models.DevicePlatformFCM] = services.NewFCMGateway(httpClient, cfg.Push.FCMEndpoint, cfg.Push.FCMServerKey)
+	}
+
+	vapidPublicKey := ""
+	if cfg.Push.VAPIDPrivateKey != "" {
+		webPushGateway, err := services.NewWebPushGateway(httpClient, cfg.Push.VAPIDPrivateKey, cfg.Push.VAPIDSubject)
+		if err != nil {
+			logger.GetGlobalLogger().Errorf("Web Push disabled due to invalid VAPID configuration: %v", err)
+		} else {
+			gateways[models.DevicePlatformWebPush] = webPushGateway
+			vapidPublicKey = webPushGateway.PublicKey()
+		}
+	}
+	return gateways, vapidPublicKey
+}
+
+// newVirusScanner returns the scanner uploads are checked with, a scanner accepting every file if none is configured.
+func newVirusScanner(cfg config.Config) services.VirusScanner {
+	if cfg.VirusScan.Provider != config.VirusScanClamAV {
+		return services.NoopVirusScanner{}
+	}
+	scanner, _ := services.NewClamAVScanner(cfg.VirusScan.Address, cfg.VirusScan.Timeout) // Validated with the configuration
+	return scanner
+}
+
+// newDocumentPool returns the pool limiting the reports generated at the same time, nil if they are not limited.
+func newDocumentPool(cfg config.Config) *services.DocumentPool {
+	if cfg.Exports.MaxConcurrentDocuments == 0 {
+		return nil
+	}
+	return services.NewDocumentPool(cfg.Exports.MaxConcurrentDocuments, int64(cfg.Exports.DocumentMemoryMB)<<20, cfg.Exports.MaxQueuedDocuments)
+}
+
+// newErrorReporter returns the reporter of the configured error tracker, nil if error reporting is disabled.
+func newErrorReporter(cfg config.Config) errorreport.Reporter {
+	release := buildinfo.Get().Version
+	switch cfg.ErrorReporting.Provider {
+	case config.ErrorReportingSentry:
+		reporter, err := errorreport.NewSentryReporter(&http.Client{Timeout: 10 * time.Second}, cfg.ErrorReporting.DSN, cfg.ErrorReporting.Environment, release)
+		if err != nil {
+			logger.GetGlobalLogger().Errorf("Error reporting disabled due to invalid Sentry configuration: %v", err)
+			return nil
+		}
+		return reporter
+	case config.ErrorReportingStackdriver:
+		return errorreport.NewStackdriverReporter(os.Stderr, cfg.ErrorReporting.Environment, release)
+	}
+	return nil
+}
+
+// newMailer returns the mailer of the configured SMTP server, nil if emails are disabled.
+func newMailer(cfg config.Config) *services.SMTPMailer {
+	if cfg.Email.SMTPHost == "" {
+		return nil
+	}
+	mailer, err := services.NewSMTPMailer(cfg.Email.SMTPHost, cfg.Email.SMTPPort, cfg.Email.Username, cfg.Email.Password, cfg.Email.From)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Emails disabled due to invalid SMTP configuration: %v", err)
+		return nil
+	}
+	return mailer
+}
+
 // GetRouter returns the router with all routes set up
 func (app *Application) GetRouter() http.Handler {
 	// Just return the router without applying CORS again
-	return app.Router
+	return app.withDemoMode(app.Router)
+}
+
+// GRPCServer returns a gRPC server serving the reporting API on the same services as the HTTP routes.
+func (app *Application) GRPCServer() *grpc.Server {
+	return grpcapi.NewServer(app.ReportingServer, app.AuthHandler.UserService, app.Config.Server.JWTSecret)
 }
 
 // Routes sets up all the HTTP routes and applies middleware.
 func (app *Application) Routes() http.Handler {
 	// Public routes
-	app.Router.Handle("POST /api/v1/auth/register", middleware.RequestIDMiddleware(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AuthHandler.RegisterUser)))))
-	app.Router.Handle("POST /api/v1/auth/login", middleware.RequestIDMiddleware(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AuthHandler.Login)))))
-	app.Router.Handle("GET /health", middleware.RequestIDMiddleware(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(healthCheckHandler)))))
-
-	// Add a generic OPTIONS handler for all paths that need CORS
-	// This handler will be wrapped by the CORS middleware later
-	app.Router.HandleFunc("OPTIONS /", func(w http.ResponseWriter, r *http.Request) {
-		// The CORS middleware will handle setting the appropriate headers
-		// and writing the status. We just need to ensure this handler is called.
-		w.WriteHeader(http.StatusOK)
-	})
-
-	// Authenticated routes (Teacher and Admin roles)
-	authMiddleware := middleware.Authenticate(app.AuthHandler.UserService, &app.Config)
+	app.handle("POST /api/v1/auth/register", middleware.PublicAccess, app.AuthHandler.RegisterUser)
+	app.handle("POST /api/v1/auth/register/invitation", middleware.PublicAccess, app.InvitationHandler.AcceptInvitation)
+	app.handle("POST /api/v1/auth/login", middleware.PublicAccess, app.AuthHandler.Login)
+	app.handle("POST /api/v1/auth/refresh", middleware.PublicAccess, app.AuthHandler.Refresh)
+	app.handle("GET /health", middleware.PublicAccess, healthCheckHandler)
+	app.handle("GET /version", middleware.PublicAccess, versionHandler)
+	app.handle("GET /api/v1/status", middleware.PublicAccess, app.StatusHandler.GetStatus)
 
 	// Auth Endpoints
-	app.Router.Handle("POST /api/v1/auth/logout", middleware.RequestIDMiddleware(authMiddleware(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AuthHandler.Logout))))))
-	app.Router.Handle("GET /api/v1/auth/me", middleware.RequestIDMiddleware(authMiddleware(middleware.RequestLogger(http.HandlerFunc(app.AuthHandler.GetMe)))))
-	app.Router.Handle("PUT /api/v1/auth/change-password", middleware.RequestIDMiddleware(authMiddleware(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AuthHandler.ChangePassword))))))
+	app.handle("POST /api/v1/auth/logout", middleware.AuthenticatedAccess.BeforeTermsAcceptance(), app.AuthHandler.Logout)
+	app.handle("GET /api/v1/auth/me", middleware.AuthenticatedAccess.BeforeTermsAcceptance(), app.AuthHandler.GetMe)
+	app.handle("PUT /api/v1/auth/change-password", middleware.AuthenticatedAccess.BeforeTermsAcceptance(), app.AuthHandler.ChangePassword)
+	app.handle("POST /api/v1/auth/reauthenticate", middleware.AuthenticatedAccess, app.reauthenticateThrottle.Limit(http.HandlerFunc(app.AuthHandler.Reauthenticate)).ServeHTTP)
+
+	// Terms of Use Endpoints, every other authenticated route requires the current version to be accepted
+	app.handle("GET /api/v1/terms", middleware.AuthenticatedAccess.BeforeTermsAcceptance(), app.TermsHandler.GetTermsStatus)
+	app.handle("POST /api/v1/terms/accept", middleware.AuthenticatedAccess.BeforeTermsAcceptance(), app.TermsHandler.AcceptTerms)
+	app.handle("GET /api/v1/terms/versions", middleware.RoleAccess(data.RoleAdmin), app.TermsHandler.GetTermsVersions)
+	app.handle("POST /api/v1/terms/versions", middleware.RoleAccess(data.RoleAdmin), app.TermsHandler.PublishTerms)
 
 	// User Management Endpoints
-	app.Router.Handle("GET /api/v1/users", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleAdmin)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AuthHandler.GetAllUsers)))))))
+	app.handle("GET /api/v1/users", middleware.RoleAccess(data.RoleAdmin), app.AuthHandler.GetAllUsers)
+
+	// Invitations, single-use signup links when open registration is disabled
+	app.handle("POST /api/v1/invitations", middleware.RoleAccess(data.RoleAdmin), app.InvitationHandler.CreateInvitation)
+	app.handle("GET /api/v1/invitations", middleware.RoleAccess(data.RoleAdmin), app.InvitationHandler.GetInvitations)
+	app.handle("DELETE /api/v1/invitations/{invitation_id}", middleware.RoleAccess(data.RoleAdmin), app.InvitationHandler.DeleteInvitation)
 
 	// Children Management Endpoints
-	app.Router.Handle("POST /api/v1/children", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.ChildHandler.CreateChild)))))))
-	app.Router.Handle("GET /api/v1/children", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.ChildHandler.GetAllChildren)))))))
-	app.Router.Handle("GET /api/v1/children/{child_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.ChildHandler.GetChildByID)))))))
-	app.Router.Handle("PUT /api/v1/children/{child_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.ChildHandler.UpdateChild)))))))
-	app.Router.Handle("DELETE /api/v1/children/{child_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleAdmin)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.ChildHandler.DeleteChild)))))))
+	app.handle("POST /api/v1/children", middleware.RoleAccess(data.RoleTeacher), app.ChildHandler.CreateChild)
+	app.handle("GET /api/v1/children", middleware.RoleAccess(data.RoleTeacher), app.ChildHandler.GetAllChildren)
+	app.handle("GET /api/v1/children/count", middleware.RoleAccess(data.RoleTeacher), app.ChildHandler.CountChildren)
+	app.handle("GET /api/v1/children/{child_id}", middleware.RoleAccess(data.RoleTeacher), app.ChildHandler.GetChildByID)
+	app.handle("PUT /api/v1/children/{child_id}", middleware.RoleAccess(data.RoleTeacher), app.ChildHandler.UpdateChild)
+	app.handle("DELETE /api/v1/children/{child_id}", middleware.RoleAccess(data.RoleAdmin), app.ChildHandler.DeleteChild)
 
 	// Teachers Management Endpoints
-	app.Router.Handle("POST /api/v1/teachers", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.TeacherHandler.CreateTeacher)))))))
-	app.Router.Handle("GET /api/v1/teachers", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.TeacherHandler.GetAllTeachers)))))))
-	app.Router.Handle("GET /api/v1/teachers/{teacher_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.TeacherHandler.GetTeacherByID)))))))
-	app.Router.Handle("PUT /api/v1/teachers/{teacher_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleAdmin)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.TeacherHandler.UpdateTeacher)))))))
-	app.Router.Handle("DELETE /api/v1/teachers/{teacher_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleAdmin)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.TeacherHandler.DeleteTeacher)))))))
+	app.handle("POST /api/v1/teachers", middleware.RoleAccess(data.RoleTeacher), app.TeacherHandler.CreateTeacher)
+	app.handle("GET /api/v1/teachers", middleware.RoleAccess(data.RoleTeacher), app.TeacherHandler.GetAllTeachers)
+	app.handle("GET /api/v1/teachers/{teacher_id}", middleware.RoleAccess(data.RoleTeacher), app.TeacherHandler.GetTeacherByID)
+	app.handle("PUT /api/v1/teachers/{teacher_id}", middleware.RoleAccess(data.RoleAdmin), app.TeacherHandler.UpdateTeacher)
+	app.handle("DELETE /api/v1/teachers/{teacher_id}", middleware.RoleAccess(data.RoleAdmin), app.TeacherHandler.DeleteTeacher)
+	app.handle("POST /api/v1/teachers/{teacher_id}/deactivate", middleware.RoleAccess(data.RoleAdmin), app.TeacherHandler.DeactivateTeacher)
+	app.handle("POST /api/v1/teachers/{teacher_id}/reactivate", middleware.RoleAccess(data.RoleAdmin), app.TeacherHandler.ReactivateTeacher)
+	app.handle("POST /api/v1/teachers/{teacher_id}/merge", middleware.RoleAccess(data.RoleAdmin), app.TeacherHandler.MergeTeacher)
 
 	// Categories Management Endpoints
-	app.Router.Handle("POST /api/v1/categories", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleAdmin)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.CategoryHandler.CreateCategory)))))))
-	app.Router.Handle("GET /api/v1/categories", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.CategoryHandler.GetAllCategories)))))))
-	app.Router.Handle("PUT /api/v1/categories/{category_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleAdmin)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.CategoryHandler.UpdateCategory)))))))
-	app.Router.Handle("DELETE /api/v1/categories/{category_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleAdmin)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.CategoryHandler.DeleteCategory)))))))
+	app.handle("POST /api/v1/categories", middleware.RoleAccess(data.RoleAdmin), app.CategoryHandler.CreateCategory)
+	app.handle("GET /api/v1/categories", middleware.RoleAccess(data.RoleTeacher), app.CategoryHandler.GetAllCategories)
+	app.handle("GET /api/v1/categories/{category_id}", middleware.RoleAccess(data.RoleTeacher), app.CategoryHandler.GetCategoryByID)
+	app.handle("PUT /api/v1/categories/{category_id}", middleware.RoleAccess(data.RoleAdmin), app.CategoryHandler.UpdateCategory)
+	app.handle("DELETE /api/v1/categories/{category_id}", middleware.RoleAccess(data.RoleAdmin), app.CategoryHandler.DeleteCategory)
+	app.handle("POST /api/v1/categories/{category_id}/archive", middleware.RoleAccess(data.RoleAdmin), app.CategoryHandler.ArchiveCategory)
+	app.handle("POST /api/v1/categories/{category_id}/restore", middleware.RoleAccess(data.RoleAdmin), app.CategoryHandler.RestoreCategory)
+	app.handle("POST /api/v1/categories/{category_id}/reassign-entries", middleware.RoleAccess(data.RoleAdmin), app.CategoryHandler.ReassignEntries)
+	app.handle("POST /api/v1/categories/{category_id}/pin", middleware.RoleAccess(data.RoleTeacher), app.CategoryHandler.PinCategory)
+	app.handle("DELETE /api/v1/categories/{category_id}/pin", middleware.RoleAccess(data.RoleTeacher), app.CategoryHandler.UnpinCategory)
 
 	// Child-Teacher Assignments Endpoints
-	app.Router.Handle("POST /api/v1/assignments", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AssignmentHandler.CreateAssignment)))))))
-	app.Router.Handle("GET /api/v1/assignments", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AssignmentHandler.GetAllAssignments)))))))
-	app.Router.Handle("GET /api/v1/assignments/child/{child_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AssignmentHandler.GetAssignmentsByChildID)))))))
-	app.Router.Handle("PUT /api/v1/assignments/{assignment_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AssignmentHandler.UpdateAssignment)))))))
-	app.Router.Handle("DELETE /api/v1/assignments/{assignment_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleAdmin)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AssignmentHandler.DeleteAssignment)))))))
+	app.handle("POST /api/v1/assignments", middleware.RoleAccess(data.RoleTeacher), app.AssignmentHandler.CreateAssignment)
+	app.handle("GET /api/v1/assignments", middleware.RoleAccess(data.RoleTeacher), app.AssignmentHandler.GetAllAssignments)
+	app.handle("GET /api/v1/assignments/child/{child_id}", middleware.RoleAccess(data.RoleTeacher), app.AssignmentHandler.GetAssignmentsByChildID)
+	app.handle("GET /api/v1/assignments/{assignment_id}", middleware.RoleAccess(data.RoleTeacher), app.AssignmentHandler.GetAssignmentByID)
+	app.handle("PUT /api/v1/assignments/{assignment_id}", middleware.RoleAccess(data.RoleTeacher), app.AssignmentHandler.UpdateAssignment)
+	app.handle("DELETE /api/v1/assignments/{assignment_id}", middleware.RoleAccess(data.RoleAdmin), app.AssignmentHandler.DeleteAssignment)
 
 	// Documentation Entries Endpoints
-	app.Router.Handle("POST /api/v1/documentation", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.DocumentationEntryHandler.CreateDocumentationEntry)))))))
-	app.Router.Handle("GET /api/v1/documentation/child/{child_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.DocumentationEntryHandler.GetDocumentationEntriesByChildID)))))))
-	app.Router.Handle("PUT /api/v1/documentation/{entry_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.DocumentationEntryHandler.UpdateDocumentationEntry)))))))
-	app.Router.Handle("DELETE /api/v1/documentation/{entry_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.DocumentationEntryHandler.DeleteDocumentationEntry)))))))
-	app.Router.Handle("PUT /api/v1/documentation/{entry_id}/approve", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.DocumentationEntryHandler.ApproveDocumentationEntry)))))))
+	app.handle("POST /api/v1/documentation", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.CreateDocumentationEntry)
+	app.handle("GET /api/v1/documentation/child/{child_id}", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.GetDocumentationEntriesByChildID)
+	app.handle("GET /api/v1/documentation/pending/count", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.CountPendingDocumentation)
+	app.handleLong("GET /api/v1/documentation/pending/poll", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.PollPendingDocumentation)
+	app.handle("GET /api/v1/documentation/{entry_id}", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.GetDocumentationEntryByID)
+	app.handle("PUT /api/v1/documentation/{entry_id}", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.UpdateDocumentationEntry)
+	app.handle("DELETE /api/v1/documentation/{entry_id}", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.DeleteDocumentationEntry)
+	app.handle("PUT /api/v1/documentation/{entry_id}/approve", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.ApproveDocumentationEntry)
+	app.handle("PUT /api/v1/documentation/{entry_id}/submit", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.SubmitDocumentationEntry)
+	app.handle("PUT /api/v1/documentation/{entry_id}/reject", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.RejectDocumentationEntry)
+	app.handle("POST /api/v1/documentation/{entry_id}/revisions", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEntryHandler.CreateDocumentationEntryRevision)
+	app.handle("PUT /api/v1/documentation/{entry_id}/unlock", middleware.RoleAccess(data.RoleAdmin), app.DocumentationEntryHandler.UnlockDocumentationEntry)
+	// Advisory locks while an entry is edited, unrelated to the lock of entries included in a report
+	app.handle("GET /api/v1/documentation/entry/{entry_id}/edit-lock", middleware.RoleAccess(data.RoleTeacher), app.EditLockHandler.GetEditLock)
+	app.handle("PUT /api/v1/documentation/entry/{entry_id}/edit-lock", middleware.RoleAccess(data.RoleTeacher), app.EditLockHandler.AcquireEditLock)
+	app.handle("DELETE /api/v1/documentation/entry/{entry_id}/edit-lock", middleware.RoleAccess(data.RoleTeacher), app.EditLockHandler.ReleaseEditLock)
+
+	// Documentation Event Endpoints
+	app.handle("GET /api/v1/documentation/entry/{entry_id}/history", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEventHandler.GetEntryHistory)
+	app.handle("GET /api/v1/children/{child_id}/timeline", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEventHandler.GetChildTimeline)
+	app.handle("GET /api/v1/documentation/lifecycle-stats", middleware.RoleAccess(data.RoleTeacher), app.DocumentationEventHandler.GetLifecycleStats)
 
 	// Audio Recordings Endpoints
-	app.Router.Handle("POST /api/v1/audio/upload", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.AudioRecordingHandler.UploadAudio)))))))
+	app.handleLong("POST /api/v1/audio/upload", middleware.RoleAccess(data.RoleTeacher), app.AudioRecordingHandler.UploadAudio)
 
 	// Process Endpoints
-	app.Router.Handle("GET /api/v1/process/{process_id}/status", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.ProcessHandler.GetStatus)))))))
+	app.handle("GET /api/v1/process/{process_id}/status", middleware.RoleAccess(data.RoleTeacher), app.ProcessHandler.GetStatus)
 
 	// Document Generation Endpoints
-	app.Router.Handle("GET /api/v1/documents/child-report/{child_id}", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.DocumentGenerationHandler.GenerateChildReport)))))))
+	app.handleLong("GET /api/v1/documents/child-report/{child_id}", middleware.RoleAccess(data.RoleTeacher), app.throttled(app.DocumentGenerationHandler.GenerateChildReport))
+	app.handle("GET /api/v1/children/{child_id}/reports", middleware.RoleAccess(data.RoleTeacher), app.DocumentGenerationHandler.GetGeneratedReports)
+	app.handle("GET /api/v1/children/{child_id}/reports/{report_id}", middleware.RoleAccess(data.RoleTeacher), app.throttled(app.DocumentGenerationHandler.DownloadGeneratedReport))
+	app.handle("POST /api/v1/children/{child_id}/reports/{report_id}/share-links", middleware.RoleAccess(data.RoleTeacher), app.ReportShareLinkHandler.CreateShareLink)
+	app.handle("GET /api/v1/children/{child_id}/reports/{report_id}/share-links", middleware.RoleAccess(data.RoleTeacher), app.ReportShareLinkHandler.GetShareLinks)
+	app.handle("GET /api/v1/share-links/{share_link_id}/accesses", middleware.RoleAccess(data.RoleTeacher), app.ReportShareLinkHandler.GetShareLinkAccesses)
+	app.handle("DELETE /api/v1/share-links/{share_link_id}", middleware.RoleAccess(data.RoleTeacher), app.ReportShareLinkHandler.RevokeShareLink)
+	// Recipients of a share link have no account, the access code protects the report
+	app.handle("POST /api/v1/shared-reports", middleware.PublicAccess, app.ReportShareLinkHandler.OpenSharedReport)
+	app.handleLong("GET /api/v1/reports/export", middleware.RoleAccess(data.RoleAdmin), app.bulkExport(app.DocumentGenerationHandler.ExportGeneratedReports))
+	app.handle("GET /api/v1/quality-reports", middleware.RoleAccess(data.RoleAdmin), app.QualityReportHandler.GetQualityReports)
+	app.handle("GET /api/v1/quality-reports/{month}", middleware.RoleAccess(data.RoleAdmin), app.QualityReportHandler.GetQualityReport)
+	app.handleLong("GET /api/v1/quality-reports/{month}/pdf", middleware.RoleAccess(data.RoleAdmin), app.throttled(app.QualityReportHandler.DownloadQualityReport))
+
+	// Benchmark Endpoints (opt-in comparison with other facilities by anonymized monthly aggregates)
+	app.handle("GET /api/v1/benchmarks", middleware.RoleAccess(data.RoleAdmin), app.BenchmarkHandler.GetComparison)
+	app.handle("GET /api/v1/benchmarks/aggregate", middleware.RoleAccess(data.RoleAdmin), app.BenchmarkHandler.ExportAggregate)
+	app.handle("POST /api/v1/benchmarks/aggregates", middleware.RoleAccess(data.RoleAdmin), app.BenchmarkHandler.ImportAggregate)
+
+	// Quarantine Endpoints (uploads the virus scanner rejected)
+	app.handle("GET /api/v1/quarantine", middleware.RoleAccess(data.RoleAdmin), app.UploadScanHandler.GetQuarantinedUploads)
+	app.handle("GET /api/v1/quarantine/{quarantine_id}/content", middleware.RoleAccess(data.RoleAdmin), app.UploadScanHandler.DownloadQuarantinedUpload)
+	app.handle("DELETE /api/v1/quarantine/{quarantine_id}", middleware.RoleAccess(data.RoleAdmin), app.UploadScanHandler.DeleteQuarantinedUpload)
+
+	// Resumable Upload Endpoints (files sent in chunks, then passed to their upload endpoint by upload_id)
+	app.handle("POST /api/v1/uploads", middleware.RoleAccess(data.RoleTeacher), app.ResumableUploadHandler.CreateUpload)
+	app.handle("GET /api/v1/uploads/{upload_id}", middleware.RoleAccess(data.RoleTeacher), app.ResumableUploadHandler.GetUpload)
+	app.handleLong("PATCH /api/v1/uploads/{upload_id}", middleware.RoleAccess(data.RoleTeacher), app.ResumableUploadHandler.AppendChunk)
+	app.handle("DELETE /api/v1/uploads/{upload_id}", middleware.RoleAccess(data.RoleTeacher), app.ResumableUploadHandler.DeleteUpload)
 
 	// Bulk Operations Endpoints
-	app.Router.Handle("POST /api/v1/bulk/import-children", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleAdmin)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.BulkOperationsHandler.ImportChildren)))))))
+	app.handleLong("POST /api/v1/bulk/import-children", middleware.RoleAccess(data.RoleAdmin), app.BulkOperationsHandler.ImportChildren)
+	app.handleLong("POST /api/v1/bulk/import-documentation", middleware.RoleAccess(data.RoleAdmin), app.BulkOperationsHandler.ImportDocumentation)
+	app.handle("GET /api/v1/bulk/import-jobs", middleware.RoleAccess(data.RoleAdmin), app.BulkOperationsHandler.GetImportJobs)
+	app.handle("GET /api/v1/bulk/import-jobs/{job_id}", middleware.RoleAccess(data.RoleAdmin), app.BulkOperationsHandler.GetImportJob)
+	app.handleLong("POST /api/v1/bulk/import-jobs/{job_id}/resume", middleware.RoleAccess(data.RoleAdmin), app.BulkOperationsHandler.ResumeImportJob)
+	app.handle("POST /api/v1/bulk/import-jobs/{job_id}/rollback", middleware.RoleAccess(data.RoleAdmin), app.BulkOperationsHandler.RollbackImportJob)
 
 	// Kita Masterdata Endpoints
-	app.Router.Handle("GET /api/v1/kita-masterdata", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleTeacher)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.KitaMasterdataHandler.GetKitaMasterdata)))))))
-	app.Router.Handle("PUT /api/v1/kita-masterdata", middleware.RequestIDMiddleware(authMiddleware(middleware.Authorize(data.RoleAdmin)(middleware.RequestLogger(middleware.Recovery(http.HandlerFunc(app.KitaMasterdataHandler.UpdateKitaMasterdata)))))))
+	app.handle("GET /api/v1/kita-masterdata", middleware.RoleAccess(data.RoleTeacher), app.KitaMasterdataHandler.GetKitaMasterdata)
+	app.handle("PUT /api/v1/kita-masterdata", middleware.RoleAccess(data.RoleAdmin), app.KitaMasterdataHandler.UpdateKitaMasterdata)
+	app.handle("PUT /api/v1/kita-masterdata/theme", middleware.RoleAccess(data.RoleAdmin), app.KitaMasterdataHandler.UpdateDocumentTheme)
+	app.handle("GET /api/v1/kita-masterdata/terminology", middleware.RoleAccess(data.RoleTeacher), app.KitaMasterdataHandler.GetTerminology)
+	app.handle("PUT /api/v1/kita-masterdata/terminology", middleware.RoleAccess(data.RoleAdmin), app.KitaMasterdataHandler.UpdateTerminology)
+	app.handle("GET /api/v1/kita-masterdata/logo", middleware.RoleAccess(data.RoleTeacher), app.KitaMasterdataHandler.GetDocumentLogo)
+	app.handleLong("PUT /api/v1/kita-masterdata/logo", middleware.RoleAccess(data.RoleAdmin), app.KitaMasterdataHandler.UploadDocumentLogo)
+	app.handle("DELETE /api/v1/kita-masterdata/logo", middleware.RoleAccess(data.RoleAdmin), app.KitaMasterdataHandler.DeleteDocumentLogo)
+
+	// Device and Notification Endpoints
+	app.handle("POST /api/v1/devices", middleware.RoleAccess(data.RoleTeacher), app.NotificationHandler.RegisterDevice)
+	app.handle("GET /api/v1/devices", middleware.RoleAccess(data.RoleTeacher), app.NotificationHandler.GetDevices)
+	app.handle("DELETE /api/v1/devices/{device_id}", middleware.RoleAccess(data.RoleTeacher), app.NotificationHandler.UnregisterDevice)
+	app.handle("GET /api/v1/notifications/preferences", middleware.RoleAccess(data.RoleTeacher), app.NotificationHandler.GetPreferences)
+	app.handle("PUT /api/v1/notifications/preferences", middleware.RoleAccess(data.RoleTeacher), app.NotificationHandler.UpdatePreferences)
+	app.handle("GET /api/v1/notifications/vapid-public-key", middleware.RoleAccess(data.RoleTeacher), app.NotificationHandler.GetVAPIDPublicKey)
+	app.handle("POST /api/v1/notifications/reminders/{teacher_id}", middleware.RoleAccess(data.RoleAdmin), app.NotificationHandler.SendReminder)
+	app.handle("GET /api/v1/notifications/digest", middleware.RoleAccess(data.RoleTeacher), app.DigestHandler.GetDigest)
 
-	// Apply CORS middleware globally
-	return middleware.CORS(app.Router)
+	// Announcement Endpoints
+	app.handle("POST /api/v1/announcements", middleware.RoleAccess(data.RoleAdmin), app.AnnouncementHandler.CreateAnnouncement)
+	app.handle("GET /api/v1/announcements", middleware.RoleAccess(data.RoleTeacher), app.AnnouncementHandler.GetAllAnnouncements)
+	app.handle("GET /api/v1/announcements/unread", middleware.RoleAccess(data.RoleTeacher), app.AnnouncementHandler.GetUnreadAnnouncements)
+	app.handle("DELETE /api/v1/announcements/{announcement_id}", middleware.RoleAccess(data.RoleAdmin), app.AnnouncementHandler.DeleteAnnouncement)
+	app.handle("POST /api/v1/announcements/{announcement_id}/acknowledge", middleware.RoleAccess(data.RoleTeacher), app.AnnouncementHandler.AcknowledgeAnnouncement)
+	app.handle("GET /api/v1/announcements/{announcement_id}/acknowledgements", middleware.RoleAccess(data.RoleAdmin), app.AnnouncementHandler.GetAcknowledgements)
+
+	// School Year Endpoints
+	app.handle("POST /api/v1/school-year/rollover", middleware.RoleAccess(data.RoleAdmin), app.SchoolYearHandler.Rollover)
+
+	// Redaction Profile Endpoints
+	app.handle("POST /api/v1/redaction-profiles", middleware.RoleAccess(data.RoleAdmin), app.RedactionProfileHandler.CreateRedactionProfile)
+	app.handle("GET /api/v1/redaction-profiles", middleware.RoleAccess(data.RoleTeacher), app.RedactionProfileHandler.GetAllRedactionProfiles)
+	app.handle("GET /api/v1/redaction-profiles/{redaction_profile_id}", middleware.RoleAccess(data.RoleTeacher), app.RedactionProfileHandler.GetRedactionProfileByID)
+	app.handle("PUT /api/v1/redaction-profiles/{redaction_profile_id}", middleware.RoleAccess(data.RoleAdmin), app.RedactionProfileHandler.UpdateRedactionProfile)
+	app.handle("DELETE /api/v1/redaction-profiles/{redaction_profile_id}", middleware.RoleAccess(data.RoleAdmin), app.RedactionProfileHandler.DeleteRedactionProfile)
+
+	// Group Endpoints
+	app.handle("POST /api/v1/groups", middleware.RoleAccess(data.RoleAdmin), app.GroupHandler.CreateGroup)
+	app.handle("GET /api/v1/groups", middleware.RoleAccess(data.RoleTeacher), app.GroupHandler.GetAllGroups)
+	app.handle("GET /api/v1/groups/statistics", middleware.RoleAccess(data.RoleTeacher), app.GroupHandler.GetGroupStatistics)
+	app.handle("GET /api/v1/groups/{group_id}", middleware.RoleAccess(data.RoleTeacher), app.GroupHandler.GetGroupByID)
+	app.handle("PUT /api/v1/groups/{group_id}", middleware.RoleAccess(data.RoleAdmin), app.GroupHandler.UpdateGroup)
+	app.handle("DELETE /api/v1/groups/{group_id}", middleware.RoleAccess(data.RoleAdmin), app.GroupHandler.DeleteGroup)
+	app.handle("PUT /api/v1/groups/{group_id}/children/{child_id}", middleware.RoleAccess(data.RoleTeacher), app.GroupHandler.AssignChild)
+	app.handle("DELETE /api/v1/groups/{group_id}/children/{child_id}", middleware.RoleAccess(data.RoleTeacher), app.GroupHandler.RemoveChild)
+
+	// Group Handover Endpoints (moving a child into another group with a handover packet for the receiving teachers)
+	app.handle("POST /api/v1/children/{child_id}/handovers", middleware.RoleAccess(data.RoleTeacher), app.GroupHandoverHandler.CreateHandover)
+	app.handle("GET /api/v1/children/{child_id}/handovers", middleware.RoleAccess(data.RoleTeacher), app.GroupHandoverHandler.GetHandoversForChild)
+	app.handle("GET /api/v1/handovers/unconfirmed", middleware.RoleAccess(data.RoleTeacher), app.GroupHandoverHandler.GetUnconfirmedHandovers)
+	app.handle("GET /api/v1/handovers/{handover_id}", middleware.RoleAccess(data.RoleTeacher), app.GroupHandoverHandler.GetHandoverPacket)
+	app.handle("POST /api/v1/handovers/{handover_id}/confirm", middleware.RoleAccess(data.RoleTeacher), app.GroupHandoverHandler.ConfirmHandover)
+
+	// School Directory Endpoints
+	app.handle("POST /api/v1/schools", middleware.RoleAccess(data.RoleAdmin), app.SchoolHandler.CreateSchool)
+	app.handle("GET /api/v1/schools", middleware.RoleAccess(data.RoleTeacher), app.SchoolHandler.GetAllSchools)
+	app.handle("GET /api/v1/schools/{school_id}", middleware.RoleAccess(data.RoleTeacher), app.SchoolHandler.GetSchoolByID)
+	app.handle("PUT /api/v1/schools/{school_id}", middleware.RoleAccess(data.RoleAdmin), app.SchoolHandler.UpdateSchool)
+	app.handle("DELETE /api/v1/schools/{school_id}", middleware.RoleAccess(data.RoleAdmin), app.SchoolHandler.DeleteSchool)
+	app.handle("GET /api/v1/schools/{school_id}/children", middleware.RoleAccess(data.RoleTeacher), app.SchoolHandler.GetSchoolChildren)
+	app.handle("PUT /api/v1/schools/{school_id}/children/{child_id}", middleware.RoleAccess(data.RoleTeacher), app.SchoolHandler.LinkChild)
+	app.handle("DELETE /api/v1/schools/{school_id}/children/{child_id}", middleware.RoleAccess(data.RoleTeacher), app.SchoolHandler.UnlinkChild)
+
+	// External Support Provider Endpoints
+	app.handle("POST /api/v1/support-providers", middleware.RoleAccess(data.RoleAdmin), app.SupportProviderHandler.CreateSupportProvider)
+	app.handle("GET /api/v1/support-providers", middleware.RoleAccess(data.RoleTeacher), app.SupportProviderHandler.GetAllSupportProviders)
+	app.handle("GET /api/v1/support-providers/{provider_id}", middleware.RoleAccess(data.RoleTeacher), app.SupportProviderHandler.GetSupportProviderByID)
+	app.handle("PUT /api/v1/support-providers/{provider_id}", middleware.RoleAccess(data.RoleAdmin), app.SupportProviderHandler.UpdateSupportProvider)
+	app.handle("DELETE /api/v1/support-providers/{provider_id}", middleware.RoleAccess(data.RoleAdmin), app.SupportProviderHandler.DeleteSupportProvider)
+	app.handle("POST /api/v1/children/{child_id}/supports", middleware.RoleAccess(data.RoleTeacher), app.SupportProviderHandler.CreateChildSupport)
+	app.handle("GET /api/v1/children/{child_id}/supports", middleware.RoleAccess(data.RoleTeacher), app.SupportProviderHandler.GetChildSupports)
+	app.handle("PUT /api/v1/children/{child_id}/supports/{support_id}", middleware.RoleAccess(data.RoleTeacher), app.SupportProviderHandler.UpdateChildSupport)
+	app.handle("DELETE /api/v1/children/{child_id}/supports/{support_id}", middleware.RoleAccess(data.RoleTeacher), app.SupportProviderHandler.DeleteChildSupport)
+
+	// Daily Care Endpoints (U3 children)
+	app.handle("GET /api/v1/children/{child_id}/daily-care", middleware.RoleAccess(data.RoleTeacher), app.DailyCareHandler.GetChildDailyCare)
+	app.handle("PUT /api/v1/children/{child_id}/daily-care/{date}", middleware.RoleAccess(data.RoleTeacher), app.DailyCareHandler.RecordDailyCare)
+	app.handle("DELETE /api/v1/children/{child_id}/daily-care/{date}", middleware.RoleAccess(data.RoleTeacher), app.DailyCareHandler.DeleteDailyCare)
+	app.handle("GET /api/v1/groups/{group_id}/daily-care/{date}", middleware.RoleAccess(data.RoleTeacher), app.DailyCareHandler.GetGroupDailyCare)
+	app.handle("PUT /api/v1/groups/{group_id}/daily-care/{date}", middleware.RoleAccess(data.RoleTeacher), app.DailyCareHandler.RecordGroupDailyCare)
+
+	// Incident Endpoints
+	app.handle("POST /api/v1/children/{child_id}/incidents", middleware.RoleAccess(data.RoleTeacher), app.IncidentHandler.CreateIncident)
+	app.handle("GET /api/v1/children/{child_id}/incidents", middleware.RoleAccess(data.RoleTeacher), app.IncidentHandler.GetIncidentsForChild)
+	app.handle("GET /api/v1/incidents/{incident_id}", middleware.RoleAccess(data.RoleTeacher), app.IncidentHandler.GetIncidentByID)
+	app.handle("PUT /api/v1/incidents/{incident_id}", middleware.RoleAccess(data.RoleTeacher), app.IncidentHandler.UpdateIncident)
+	app.handle("DELETE /api/v1/incidents/{incident_id}", middleware.RoleAccess(data.RoleAdmin), app.IncidentHandler.DeleteIncident)
+	app.handle("GET /api/v1/incidents/{incident_id}/unfallanzeige", middleware.RoleAccess(data.RoleTeacher), app.IncidentHandler.DownloadIncidentReport)
+
+	// Medication Administration Endpoints (read-only log, no updates or deletes)
+	app.handle("POST /api/v1/children/{child_id}/medication-administrations", middleware.RoleAccess(data.RoleTeacher), app.MedicationHandler.RecordAdministration)
+	app.handle("GET /api/v1/children/{child_id}/medication-administrations", middleware.RoleAccess(data.RoleTeacher), app.MedicationHandler.GetAdministrationsForChild)
+	app.handle("GET /api/v1/medication-administrations/{administration_id}", middleware.RoleAccess(data.RoleTeacher), app.MedicationHandler.GetAdministrationByID)
+	app.handle("POST /api/v1/medication-administrations/{administration_id}/confirm", middleware.RoleAccess(data.RoleTeacher), app.MedicationHandler.ConfirmAdministration)
+
+	// Photo Gallery Endpoints (photos of children without photo consent are redacted)
+	app.handleLong("POST /api/v1/groups/{group_id}/photos", middleware.RoleAccess(data.RoleTeacher), app.GalleryHandler.UploadPhoto)
+	app.handle("GET /api/v1/groups/{group_id}/photos", middleware.RoleAccess(data.RoleTeacher), app.GalleryHandler.GetGroupPhotos)
+	app.handle("GET /api/v1/children/{child_id}/photos", middleware.RoleAccess(data.RoleTeacher), app.GalleryHandler.GetChildPhotos)
+	app.handle("GET /api/v1/photos/{photo_id}", middleware.RoleAccess(data.RoleTeacher), app.GalleryHandler.GetPhotoByID)
+	app.handle("PUT /api/v1/photos/{photo_id}", middleware.RoleAccess(data.RoleTeacher), app.GalleryHandler.UpdatePhoto)
+	app.handle("DELETE /api/v1/photos/{photo_id}", middleware.RoleAccess(data.RoleTeacher), app.GalleryHandler.DeletePhoto)
+	app.handle("GET /api/v1/photos/{photo_id}/content", middleware.RoleAccess(data.RoleTeacher), app.GalleryHandler.GetPhotoContent)
+	app.handle("GET /api/v1/children/{child_id}/photo-consent", middleware.RoleAccess(data.RoleTeacher), app.GalleryHandler.GetPhotoConsent)
+	app.handle("PUT /api/v1/children/{child_id}/photo-consent", middleware.RoleAccess(data.RoleTeacher), app.GalleryHandler.SetPhotoConsent)
+	app.handle("DELETE /api/v1/children/{child_id}/photo-consent", middleware.RoleAccess(data.RoleTeacher), app.GalleryHandler.RevokePhotoConsent)
+
+	// Child Document Endpoints (scanned paperwork, reminders for missing and expired documents)
+	app.handleLong("POST /api/v1/children/{child_id}/documents", middleware.RoleAccess(data.RoleTeacher), app.ChildDocumentHandler.UploadDocument)
+	app.handle("GET /api/v1/children/{child_id}/documents", middleware.RoleAccess(data.RoleTeacher), app.ChildDocumentHandler.GetChildDocuments)
+	app.handle("GET /api/v1/child-documents/reminders", middleware.RoleAccess(data.RoleTeacher), app.ChildDocumentHandler.GetReminders)
+	app.handle("GET /api/v1/child-documents/{document_id}", middleware.RoleAccess(data.RoleTeacher), app.ChildDocumentHandler.GetDocumentByID)
+	app.handle("PUT /api/v1/child-documents/{document_id}", middleware.RoleAccess(data.RoleTeacher), app.ChildDocumentHandler.UpdateDocument)
+	app.handle("DELETE /api/v1/child-documents/{document_id}", middleware.RoleAccess(data.RoleTeacher), app.ChildDocumentHandler.DeleteDocument)
+	app.handle("GET /api/v1/child-documents/{document_id}/content", middleware.RoleAccess(data.RoleTeacher), app.ChildDocumentHandler.GetDocumentContent)
+
+	// Attendance Endpoints (arrivals and departures, recorded by teachers or by scanning QR codes at the door tablet)
+	app.handle("POST /api/v1/attendance/check-in", middleware.RoleAccess(data.RoleTeacher), app.AttendanceHandler.CheckIn)
+	app.handle("GET /api/v1/attendance", middleware.RoleAccess(data.RoleTeacher), app.AttendanceHandler.GetDayAttendance)
+	app.handle("GET /api/v1/attendance/{record_id}", middleware.RoleAccess(data.RoleTeacher), app.AttendanceHandler.GetAttendanceByID)
+	app.handle("DELETE /api/v1/attendance/{record_id}", middleware.RoleAccess(data.RoleTeacher), app.AttendanceHandler.DeleteAttendance)
+	app.handle("POST /api/v1/children/{child_id}/attendance", middleware.RoleAccess(data.RoleTeacher), app.AttendanceHandler.RecordAttendance)
+	app.handle("GET /api/v1/children/{child_id}/attendance", middleware.RoleAccess(data.RoleTeacher), app.AttendanceHandler.GetChildAttendance)
+	app.handle("GET /api/v1/children/{child_id}/checkin-qr", middleware.RoleAccess(data.RoleTeacher), app.AttendanceHandler.GetCheckInQRCode)
+	app.handle("DELETE /api/v1/children/{child_id}/checkin-qr", middleware.RoleAccess(data.RoleTeacher), app.AttendanceHandler.RevokeCheckInCode)
+
+	// Care Contract Endpoints (booked weekly care hours, warnings when the attendance exceeds them)
+	app.handle("POST /api/v1/children/{child_id}/care-contracts", middleware.RoleAccess(data.RoleAdmin), app.CareContractHandler.CreateContract)
+	app.handle("GET /api/v1/children/{child_id}/care-contracts", middleware.RoleAccess(data.RoleTeacher), app.CareContractHandler.GetContractsForChild)
+	app.handle("GET /api/v1/care-contracts/warnings", middleware.RoleAccess(data.RoleAdmin), app.CareContractHandler.GetCareHoursWarnings)
+	app.handle("GET /api/v1/care-contracts/{contract_id}", middleware.RoleAccess(data.RoleTeacher), app.CareContractHandler.GetContractByID)
+	app.handle("PUT /api/v1/care-contracts/{contract_id}", middleware.RoleAccess(data.RoleAdmin), app.CareContractHandler.UpdateContract)
+	app.handle("DELETE /api/v1/care-contracts/{contract_id}", middleware.RoleAccess(data.RoleAdmin), app.CareContractHandler.DeleteContract)
+	app.handleLong("GET /api/v1/exports/fees", middleware.RoleAccess(data.RoleAdmin), app.bulkExport(app.FeeExportHandler.ExportFees))
+
+	// Project Endpoints (activities planned for groups, goals mapped to categories, entries observed in them)
+	app.handle("POST /api/v1/projects", middleware.RoleAccess(data.RoleTeacher), app.ProjectHandler.CreateProject)
+	app.handle("GET /api/v1/projects", middleware.RoleAccess(data.RoleTeacher), app.ProjectHandler.GetAllProjects)
+	app.handle("GET /api/v1/projects/{project_id}", middleware.RoleAccess(data.RoleTeacher), app.ProjectHandler.GetProjectByID)
+	app.handle("PUT /api/v1/projects/{project_id}", middleware.RoleAccess(data.RoleTeacher), app.ProjectHandler.UpdateProject)
+	app.handle("DELETE /api/v1/projects/{project_id}", middleware.RoleAccess(data.RoleAdmin), app.ProjectHandler.DeleteProject)
+	app.handle("GET /api/v1/projects/{project_id}/documentation", middleware.RoleAccess(data.RoleTeacher), app.ProjectHandler.GetProjectDocumentation)
+	app.handle("GET /api/v1/projects/{project_id}/summary", middleware.RoleAccess(data.RoleTeacher), app.ProjectHandler.GetProjectSummary)
+
+	// Team Meeting Endpoints (minutes of team meetings, action items assigned to teachers)
+	app.handle("POST /api/v1/meetings", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.CreateMeeting)
+	app.handle("GET /api/v1/meetings", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.GetAllMeetings)
+	app.handle("GET /api/v1/meetings/{meeting_id}", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.GetMeetingByID)
+	app.handle("PUT /api/v1/meetings/{meeting_id}", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.UpdateMeeting)
+	app.handle("DELETE /api/v1/meetings/{meeting_id}", middleware.RoleAccess(data.RoleAdmin), app.TeamMeetingHandler.DeleteMeeting)
+	app.handle("POST /api/v1/meetings/{meeting_id}/action-items", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.CreateActionItem)
+	app.handle("GET /api/v1/children/{child_id}/meetings", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.GetMeetingsForChild)
+	app.handle("GET /api/v1/action-items/open", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.GetOpenActionItems)
+	app.handle("PUT /api/v1/action-items/{action_item_id}", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.UpdateActionItem)
+	app.handle("PUT /api/v1/action-items/{action_item_id}/complete", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.CompleteActionItem)
+	app.handle("PUT /api/v1/action-items/{action_item_id}/reopen", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.ReopenActionItem)
+	app.handle("DELETE /api/v1/action-items/{action_item_id}", middleware.RoleAccess(data.RoleTeacher), app.TeamMeetingHandler.DeleteActionItem)
+
+	// Approval Delegation Endpoints
+	app.handle("POST /api/v1/approval-delegations", middleware.RoleAccess(data.RoleAdmin), app.ApprovalDelegationHandler.CreateDelegation)
+	app.handle("GET /api/v1/approval-delegations", middleware.RoleAccess(data.RoleTeacher), app.ApprovalDelegationHandler.GetDelegations)
+	app.handle("DELETE /api/v1/approval-delegations/{delegation_id}", middleware.RoleAccess(data.RoleAdmin), app.ApprovalDelegationHandler.DeleteDelegation)
+
+	// Audit Log Endpoints
+	app.handle("GET /api/v1/audit-log", middleware.RoleAccess(data.RoleAdmin), app.AuditLogHandler.GetAuditLog)
+
+	// Bootstrap Endpoints
+	app.handle("POST /api/v1/admin/bootstrap", middleware.RoleAccess(data.RoleAdmin), app.BootstrapHandler.Bootstrap)
+
+	// Break-Glass Endpoints, sealing needs a recent password confirmation since the code grants admin access.
+	// Activation is public but only reachable from the admin networks, like the admin routes.
+	app.handle("POST /api/v1/admin/break-glass", middleware.RoleAccess(data.RoleAdmin), middleware.RequireReauthentication(app.Config.Server.JWTSecret)(http.HandlerFunc(app.BreakGlassHandler.SealBreakGlass)).ServeHTTP)
+	app.handle("GET /api/v1/admin/break-glass", middleware.RoleAccess(data.RoleAdmin), app.BreakGlassHandler.GetBreakGlassCredentials)
+	app.handle("DELETE /api/v1/admin/break-glass/{credential_id}", middleware.RoleAccess(data.RoleAdmin), app.BreakGlassHandler.RevokeBreakGlass)
+	app.handle("POST /api/v1/auth/break-glass", middleware.PublicAccess, app.adminAllowlist.Restrict(app.breakGlassThrottle.LimitClients(app.adminAllowlist, http.HandlerFunc(app.BreakGlassHandler.ActivateBreakGlass))).ServeHTTP)
+
+	// Query Plan Endpoints
+	app.handle("GET /api/v1/admin/query-plans", middleware.RoleAccess(data.RoleAdmin), app.QueryPlanHandler.GetQueryPlans)
+
+	// Uptime Endpoints
+	app.handle("GET /api/v1/admin/uptime", middleware.RoleAccess(data.RoleAdmin), app.UptimeHandler.GetUptime)
+	app.handle("POST /api/v1/admin/uptime/incidents", middleware.RoleAccess(data.RoleAdmin), app.UptimeHandler.CreateIncident)
+	app.handle("DELETE /api/v1/admin/uptime/incidents/{incident_id}", middleware.RoleAccess(data.RoleAdmin), app.UptimeHandler.DeleteIncident)
+
+	// Outbox Endpoints
+	app.handle("GET /api/v1/outbox", middleware.RoleAccess(data.RoleAdmin), app.OutboxHandler.GetOutboxMessages)
+
+	// Validation Rule Endpoints
+	app.handle("GET /api/v1/validation-rules", middleware.RoleAccess(data.RoleTeacher), app.ValidationRuleHandler.GetRules)
+	app.handle("PUT /api/v1/validation-rules", middleware.RoleAccess(data.RoleAdmin), app.ValidationRuleHandler.UpdateRules)
+
+	// Completeness Endpoints
+	app.handle("GET /api/v1/completeness", middleware.RoleAccess(data.RoleTeacher), app.CompletenessHandler.GetAllCompleteness)
+	app.handle("GET /api/v1/children/{child_id}/completeness", middleware.RoleAccess(data.RoleTeacher), app.CompletenessHandler.GetChildCompleteness)
+	app.handle("GET /api/v1/children/{child_id}/category-suggestions", middleware.RoleAccess(data.RoleTeacher), app.CompletenessHandler.GetCategorySuggestions)
+
+	// Anonymous Statistics Endpoints (a bulk export, the statistics cover all children)
+	app.handleLong("GET /api/v1/statistics/anonymous", middleware.RoleAccess(data.RoleAdmin), app.bulkExport(app.AnonymousStatisticsHandler.ExportAnonymousStatistics))
+
+	// GraphQL Endpoints
+	app.handle("POST /api/v1/graphql", middleware.RoleAccess(data.RoleTeacher), app.GraphQLHandler.Query)
+
+	// Route Policy Endpoints
+	app.handle("GET /api/v1/route-policies", middleware.RoleAccess(data.RoleAdmin), app.RoutePolicyHandler.GetRoutePolicies)
+	app.handle("GET /api/v1/openapi.json", middleware.AuthenticatedAccess.BeforeTermsAcceptance(), app.RoutePolicyHandler.GetOpenAPIDocument)
+
+	// Status Banner Endpoints, the banner is shown by GET /api/v1/status
+	app.handle("GET /api/v1/status/banner", middleware.RoleAccess(data.RoleAdmin), app.StatusHandler.GetBanner)
+	app.handle("PUT /api/v1/status/banner", middleware.RoleAccess(data.RoleAdmin), app.StatusHandler.SetBanner)
+	app.handle("DELETE /api/v1/status/banner", middleware.RoleAccess(data.RoleAdmin), app.StatusHandler.DeleteBanner)
+
+	// Email Template Endpoints
+	app.handle("GET /api/v1/email-templates", middleware.RoleAccess(data.RoleAdmin), app.EmailTemplateHandler.GetEmailTemplates)
+	app.handle("GET /api/v1/email-templates/{template_key}/versions", middleware.RoleAccess(data.RoleAdmin), app.EmailTemplateHandler.GetEmailTemplateVersions)
+	app.handle("POST /api/v1/email-templates/{template_key}/versions", middleware.RoleAccess(data.RoleAdmin), app.EmailTemplateHandler.PublishEmailTemplate)
+	app.handle("POST /api/v1/email-templates/{template_key}/preview", middleware.RoleAccess(data.RoleAdmin), app.EmailTemplateHandler.PreviewEmailTemplate)
+
+	// Demo Mode Endpoints
+	app.handle("GET /api/v1/demo-mode", middleware.RoleAccess(data.RoleAdmin), app.DemoModeHandler.GetStatus)
+	app.handle("POST /api/v1/demo-mode", middleware.RoleAccess(data.RoleAdmin), app.DemoModeHandler.Enable)
+	app.handle("DELETE /api/v1/demo-mode", middleware.RoleAccess(data.RoleAdmin), app.DemoModeHandler.Disable)
+
+	// Fixture Endpoints, only served by the fixture server of the frontend tests
+	if app.FixtureHandler != nil {
+		app.handle("GET /api/v1/fixture", middleware.PublicAccess, app.FixtureHandler.GetState)
+		app.handle("POST /api/v1/fixture/reset", middleware.PublicAccess, app.FixtureHandler.Reset)
+		app.handle("PUT /api/v1/fixture/clock", middleware.PublicAccess, app.FixtureHandler.SetClock)
+	}
+
+	if app.isDemo {
+		// The production application dispatches to this router and already applies CORS.
+		return app.Router
+	}
+
+	// Apply the security headers and CORS middleware globally. OPTIONS and 405 are answered from the routes of
+	// the production router, the demo router has the same routes.
+	return middleware.SecurityHeaders(&app.Config)(middleware.CORS(middleware.AllowedMethods(app.Router)(app.withDemoMode(app.Router))))
+}
+
+// handle registers a route with the standard middleware chain and records its policy in the policy engine,
+// which the chain consults on every request.
+func (app *Application) handle(pattern string, policy middleware.Policy, handler http.HandlerFunc) {
+	app.handleWithTimeout(pattern, policy, app.Config.Server.RequestTimeout, handler)
+}
+
+// throttled limits a route handing out reports to the configured number of downloads per user.
+func (app *Application) throttled(handler http.HandlerFunc) http.HandlerFunc {
+	return app.downloadThrottle.Limit(handler).ServeHTTP
+}
+
+// bulkExport additionally requires a recent password confirmation and a request from the allowed networks, bulk
+// exports contain the data of many children.
+func (app *Application) bulkExport(handler http.HandlerFunc) http.HandlerFunc {
+	return app.bulkExportAllowlist.Restrict(middleware.RequireReauthentication(app.Config.Server.JWTSecret)(app.throttled(handler))).ServeHTTP
+}
+
+// recordNetworkDenial records a request from outside of the allowed networks in the audit log.
+func (app *Application) recordNetworkDenial(request *http.Request, client netip.Addr) {
+	entry := &models.AuditLogEntry{
+		Action:     models.AuditActionDenyNetwork,
+		EntityType: "route",
+		Details:    fmt.Sprintf("route=%q client=%s", request.Pattern, client),
+	}
+	if user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User); ok {
+		entry.ActorUserID = &user.ID
+	}
+	// The request is rejected anyway, so a failing audit write is only logged.
+	_ = app.auditLogService.Record(middleware.GetLoggerWithReqID(request.Context()), request.Context(), entry)
+}
+
+// handleLong registers a route that generates documents, processes uploads or long polls, it gets the long request timeout.
+func (app *Application) handleLong(pattern string, policy middleware.Policy, handler http.HandlerFunc) {
+	app.handleWithTimeout(pattern, policy, app.Config.Server.LongRequestTimeout, handler)
+}
+
+func (app *Application) handleWithTimeout(pattern string, policy middleware.Policy, timeout time.Duration, handler http.HandlerFunc) {
+	app.Policies.Register(pattern, policy)
+	var chained http.Handler = middleware.Recovery(app.ErrorReporter)(handler)
+	if policy.AdminOnly() {
+		chained = app.adminAllowlist.Restrict(chained)
+	}
+	if app.chaos != nil {
+		// Within the timeout, so that injected latency can also time out like a slow handler
+		chained = app.chaos.For(pattern)(chained)
+	}
+	chain := app.Policies.Authorize(middleware.RequestLogger(middleware.Timeout(timeout)(chained)))
+	if policy.Access != models.RouteAccessPublic {
+		if !policy.TermsExempt {
+			chain = middleware.RequireTermsAcceptance(app.termsService)(chain)
+		}
+		chain = middleware.Authenticate(app.AuthHandler.UserService, &app.Config)(chain)
+	}
+	app.Router.Handle(pattern, middleware.RequestIDMiddleware(chain))
+}
+
+// withDemoMode routes requests of sessions in demo mode to the demo dataset.
+func (app *Application) withDemoMode(handler http.Handler) http.Handler {
+	if app.isDemo {
+		return handler
+	}
+	return middleware.DemoMode(&app.Config, app.demoModeService.IsEnabled, app.demoHandler)(handler)
+}
+
+// demoHandler returns the routes serving the current demo dataset, rebuilding them when the dataset was refreshed.
+func (app *Application) demoHandler() http.Handler {
+	dal := app.demoModeService.Dataset()
+	if dal == nil {
+		return nil
+	}
+	app.demoMu.Lock()
+	defer app.demoMu.Unlock()
+	if app.demoDAL != dal {
+		demoApp := newApplication(app.Config, dal, app.authSessions, app.demoModeService, app.documentPool, app.clock)
+		demoApp.isDemo = true
+		demoApp.ErrorReporter = app.ErrorReporter
+		app.demoDAL = dal
+		app.demoRouter = demoApp.Routes()
+	}
+	return app.demoRouter
 }
 
 // healthCheckHandler provides a simple health check endpoint.
 func healthCheckHandler(writer http.ResponseWriter, request *http.Request) {
 	writer.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(writer).Encode(map[string]string{"status": "ok"}); err != nil {
+	if err := json.NewEncoder(writer).Encode(map[string]string{"status": "ok", "version": buildinfo.Get().Version}); err != nil {
+		http.Error(writer, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// versionHandler reports the build of the server, for support requests.
+func versionHandler(writer http.ResponseWriter, request *http.Request) {
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(buildinfo.Get()); err != nil {
 		http.Error(writer, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,111 @@
+// Command anonymize creates a pseudonymized copy of a kitadoc database so that developers can debug with
+// production-shaped data. Names, login names, the facility's address and all observation texts are replaced
+// irreversibly, no mapping to the real data is kept. The source database is only read.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	_ "modernc.org/sqlite"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/services"
+)
+
+// cleanup removes data that has no use for debugging but may identify people or devices.
+// The documentation events carry the original observations, which the anonymization replaces,
+// and the outbox carries notification texts.
+var cleanup = []string{
+	`DELETE FROM devices`,
+	`DELETE FROM audit_log`,
+	`DELETE FROM documentation_events`,
+	`DELETE FROM outbox`,
+}
+
+func main() {
+	source := flag.String("source", "kitadoc.db", "Path to the production SQLite database (read only)")
+	target := flag.String("target", "kitadoc_anonymized.db", "Path of the anonymized copy, must not exist")
+	key := flag.String("key", "", "32-byte encryption key of the source database (raw string)")
+	targetKey := flag.String("target-key", "0123456789abcdef0123456789abcdef", "32-byte encryption key for the anonymized copy (raw string)")
+	password := flag.String("password", "", "If set, every user account of the copy gets this password")
+	flag.Parse()
+
+	if len(*key) != 32 || len(*targetKey) != 32 {
+		log.Fatalf("-key and -target-key must be 32 bytes long")
+	}
+	if _, err := os.Stat(*source); err != nil {
+		log.Fatalf("failed to open source database: %v", err)
+	}
+	if _, err := os.Stat(*target); !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("target %s already exists", *target)
+	}
+
+	sourceDB, err := sql.Open("sqlite", "file:"+*source+"?mode=ro")
+	if err != nil {
+		log.Fatalf("failed to open source database: %v", err)
+	}
+	// VACUUM INTO writes a consistent copy without touching the source.
+	_, err = sourceDB.Exec(`VACUUM INTO ?`, *target)
+	sourceDB.Close() // nolint:errcheck
+	if err != nil {
+		log.Fatalf("failed to copy database: %v", err)
+	}
+
+	if err := anonymize(*target, []byte(*key), []byte(*targetKey), *password); err != nil {
+		os.Remove(*target) // nolint:errcheck
+		log.Fatalf("failed to anonymize database, removed %s: %v", *target, err)
+	}
+	fmt.Printf("Anonymized copy written to %s\n", *target)
+}
+
+func anonymize(path string, key []byte, targetKey []byte, password string) error {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=secure_delete(1)")
+	if err != nil {
+		return fmt.Errorf("failed to open copy: %w", err)
+	}
+	defer db.Close() // nolint:errcheck
+
+	if err := data.MigrateDB(db, migrations.Files); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	for _, statement := range cleanup {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("failed to clean up copy: %w", err)
+		}
+	}
+
+	options := services.AnonymizationOptions{
+		IncludeArchived:     true,
+		FamilyNames:         true,
+		ReplaceObservations: true,
+		Users:               true,
+		Now:                 time.Now(),
+	}
+	if password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return fmt.Errorf("failed to hash password: %w", err)
+		}
+		options.PasswordHash = string(hash)
+	}
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	if err := services.AnonymizeDataset(logger, context.Background(), data.NewDAL(db, data.DriverSQLite, key), data.NewDAL(db, data.DriverSQLite, targetKey), options); err != nil {
+		return err
+	}
+
+	// Rebuild the file so that no freed page still holds the original data.
+	if _, err := db.Exec(`VACUUM`); err != nil {
+		return fmt.Errorf("failed to compact copy: %w", err)
+	}
+	return nil
+}
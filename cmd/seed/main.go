@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -14,11 +16,19 @@ import (
 	"kitadoc-backend/models"
 )
 
+// Seeding works on existing databases: categories and teachers that already exist (by name and username)
+// are reused, everything else is added. IDs in the seed data below refer to positions in the seeded lists
+// (1 = first element) and are translated to the IDs returned by the database.
 func main() {
 	dsn := flag.String("dsn", "file:test.db?_pragma=foreign_keys(1)", "SQLite DSN")
 	key := flag.String("key", "0123456789abcdef0123456789abcdef", "32-byte hex encryption key (raw string)")
+	only := flag.String("only", "", "Seed only part of the data, \"categories\" creates the missing categories and nothing else")
 	flag.Parse()
 
+	if *only != "" && *only != "categories" {
+		log.Fatalf("unknown -only value %q, supported: categories", *only)
+	}
+
 	db, err := sql.Open("sqlite", *dsn)
 	if err != nil {
 		log.Fatalf("failed to open database: %v", err)
@@ -31,28 +41,18 @@ func main() {
 	}
 
 	// Create DAL
-	dal := data.NewDAL(db, []byte(*key))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte(*key))
+	ctx := context.Background()
 
 	// Seed categories
-	categories := []models.Category{
-		{Name: "Bewegung", Description: models.StringPtr("Beobachtungen zur Bewegungsfreude, Koordination, Grundbewegungen (Robben, Klettern, Springen, Balancieren etc.) und Selbstständigkeit bei motorischen Aufgaben.")},
-		{Name: "Körper, Gesundheit, Ernährung", Description: models.StringPtr("Körperwahrnehmung, Körperschema, Spannungsverhalten, Essverhalten, Gesundheitsfragen, U-Untersuchungen und Impfstatus.")},
-		{Name: "Sprache und Kommunikation", Description: models.StringPtr("Sprachgebrauch, Lautbildung, Wortschatz, Erzählen, Hörverständnis, Zuhören, Grammatik und frühe Schriftsprache.")},
-		{Name: "Soziale und (inter-) kulturelle Bildung", Description: models.StringPtr("Sozialverhalten in Gruppen und gegenüber Erwachsenen, Trennung, Spielverhalten, Kooperation, Konfliktlösung, Empathie und interkulturelle Anpassung.")},
-		{Name: "Musisch- ästhetische Bildung", Description: models.StringPtr("Kreativität beim Gestalten, Umgang mit Farben und Materialien, Musizieren, Rhythmusgefühl und Gedächtnis für Lieder/Reime.")},
-		{Name: "Religion und Ethik", Description: models.StringPtr("Interesse an religiösen Ritualen und Festen, Kenntnis biblischer Geschichten, Gerechtigkeitssinn, Solidarität und philosophische Fragen zu Leben und Tod.")},
-		{Name: "Mathematische Bildung", Description: models.StringPtr("Zahlenverständnis, Mengenverständnis, Puzzeln, räumliches Vorstellungsvermögen, Vergleichen (mehr/weniger) und erste mathematische Zusammenhänge.")},
-		{Name: "Naturwissenschaftlich- technische Bildung", Description: models.StringPtr("Neugier für Natur und Technik, Experimentieren mit Materialien, Beobachtung von Prozessen und Teilen von Wissen.")},
-		{Name: "Ökologische Bildung", Description: models.StringPtr("Umweltbewusstsein, Kreisläufe der Natur, Trennen/Recycle von Rohstoffen und nachhaltiges Verhalten.")},
-		{Name: "Medien", Description: models.StringPtr("Umgang mit Bilderbüchern und digitalen Medien, Zuhören bei Geschichten, Wiedergeben/Weitererzählen und kreativer Medieneinsatz.")},
-		{Name: "Eingewöhnung", Description: models.StringPtr("Trennungs- und Bindungsfähigkeit, Erkundungsverhalten in der Kita, Nähe-Distanz-Regulation und Wohlbefinden.")},
-		{Name: "Inklusion", Description: models.StringPtr("Orientierung an Teil- und Förderplan, individuelle Förderung und inklusive Unterstützung.")},
-	}
-
-	for _, c := range categories {
-		if _, err := dal.Categories.Create(&c); err != nil {
-			log.Fatalf("failed to create category %s: %v", c.Name, err)
-		}
+	categories := models.DefaultCategories()
+	categoryIDs := make([]int, len(categories))
+	for i := range categories {
+		categoryIDs[i] = ensureCategory(ctx, dal, &categories[i])
+	}
+	if *only == "categories" {
+		fmt.Println("Categories seeded successfully")
+		return
 	}
 
 	// Seed teachers
@@ -64,10 +64,13 @@ func main() {
 		{FirstName: "Michael", LastName: "Wagner", Username: "michael.wagner"},
 	}
 
+	existingTeachers, err := dal.Teachers.GetAll(ctx)
+	if err != nil {
+		log.Fatalf("failed to fetch teachers: %v", err)
+	}
+	teacherIDs := make([]int, len(teachers))
 	for i := range teachers {
-		if _, err := dal.Teachers.Create(&teachers[i]); err != nil {
-			log.Fatalf("failed to create teacher %s: %v", teachers[i].Username, err)
-		}
+		teacherIDs[i] = ensureTeacher(ctx, dal, existingTeachers, &teachers[i])
 	}
 
 	// Helper to parse date strings in sample_data.sql (YYYY-MM-DD)
@@ -75,26 +78,29 @@ func main() {
 		t, _ := time.Parse("2006-01-02", s)
 		return t
 	}
+	parseDay := func(s string) models.Date {
+		d, _ := models.ParseDate(s)
+		return d
+	}
 
 	// Seed children
 	children := []models.Child{
-		{FirstName: "Anna", LastName: "Müller", Birthdate: parseDate("2019-03-15"), AdmissionDate: timePtr(parseDate("2023-08-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2025-08-01"))},
-		{FirstName: "Liam", LastName: "Kowalski", Birthdate: parseDate("2018-11-20"), AdmissionDate: timePtr(parseDate("2023-08-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2024-08-01"))},
-		{FirstName: "Ben", LastName: "Springer", Birthdate: parseDate("2019-07-08"), AdmissionDate: timePtr(parseDate("2023-09-15")), ExpectedSchoolEnrollment: timePtr(parseDate("2025-08-01"))},
-		{FirstName: "Noah", LastName: "Brown", Birthdate: parseDate("2019-01-12"), AdmissionDate: timePtr(parseDate("2023-08-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2025-08-01"))},
-		{FirstName: "Mia", LastName: "Schneider", Birthdate: parseDate("2018-09-30"), AdmissionDate: timePtr(parseDate("2023-08-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2024-08-01"))},
-		{FirstName: "Lucas", LastName: "Ahmed", Birthdate: parseDate("2019-05-22"), AdmissionDate: timePtr(parseDate("2023-10-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2025-08-01"))},
-		{FirstName: "Charlotte", LastName: "Becker", Birthdate: parseDate("2019-02-18"), AdmissionDate: timePtr(parseDate("2023-08-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2025-08-01"))},
-		{FirstName: "Oliver", LastName: "Popovic", Birthdate: parseDate("2018-12-05"), AdmissionDate: timePtr(parseDate("2023-09-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2024-08-01"))},
-	}
-
-	for i := range children {
-		if _, err := dal.Children.Create(&children[i]); err != nil {
-			log.Fatalf("failed to create child %s %s: %v", children[i].FirstName, children[i].LastName, err)
-		}
+		{FirstName: "Anna", LastName: "Müller", Birthdate: parseDay("2019-03-15"), AdmissionDate: timePtr(parseDate("2023-08-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2025-08-01"))},
+		{FirstName: "Liam", LastName: "Kowalski", Birthdate: parseDay("2018-11-20"), AdmissionDate: timePtr(parseDate("2023-08-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2024-08-01"))},
+		{FirstName: "Ben", LastName: "Springer", Birthdate: parseDay("2019-07-08"), AdmissionDate: timePtr(parseDate("2023-09-15")), ExpectedSchoolEnrollment: timePtr(parseDate("2025-08-01"))},
+		{FirstName: "Noah", LastName: "Brown", Birthdate: parseDay("2019-01-12"), AdmissionDate: timePtr(parseDate("2023-08-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2025-08-01"))},
+		{FirstName: "Mia", LastName: "Schneider", Birthdate: parseDay("2018-09-30"), AdmissionDate: timePtr(parseDate("2023-08-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2024-08-01"))},
+		{FirstName: "Lucas", LastName: "Ahmed", Birthdate: parseDay("2019-05-22"), AdmissionDate: timePtr(parseDate("2023-10-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2025-08-01"))},
+		{FirstName: "Charlotte", LastName: "Becker", Birthdate: parseDay("2019-02-18"), AdmissionDate: timePtr(parseDate("2023-08-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2025-08-01"))},
+		{FirstName: "Oliver", LastName: "Popovic", Birthdate: parseDay("2018-12-05"), AdmissionDate: timePtr(parseDate("2023-09-01")), ExpectedSchoolEnrollment: timePtr(parseDate("2024-08-01"))},
+	}
+
+	childIDs, err := dal.Children.CreateMany(ctx, children)
+	if err != nil {
+		log.Fatalf("failed to create children: %v", err)
 	}
 
-	// Seed assignments. We need teacher and child IDs — for simplicity we'll assume insertion order and AUTOINCREMENT starting from 1
+	// Seed assignments
 	assignments := []models.Assignment{
 		{ChildID: 1, TeacherID: 1, StartDate: parseDate("2023-08-01")},
 		{ChildID: 2, TeacherID: 1, StartDate: parseDate("2023-08-01")},
@@ -113,34 +119,74 @@ func main() {
 	assignments = append(assignments, models.Assignment{ChildID: 3, TeacherID: 1, StartDate: parseDate("2023-09-15"), EndDate: &end2})
 
 	for i := range assignments {
-		if _, err := dal.Assignments.Create(&assignments[i]); err != nil {
+		assignments[i].ChildID = childIDs[assignments[i].ChildID-1]
+		assignments[i].TeacherID = teacherIDs[assignments[i].TeacherID-1]
+		assignments[i].AssignmentType = models.AssignmentTypePrimary
+		if _, err := dal.Assignments.Create(ctx, &assignments[i]); err != nil {
 			log.Fatalf("failed to create assignment: %v", err)
 		}
 	}
 
 	// Seed documentation entries.
 	docEntries := []models.DocumentationEntry{
-		{ChildID: 1, TeacherID: 1, CategoryID: 1, ObservationDescription: "Anna zeigt große Hilfsbereitschaft gegenüber anderen Kindern. Sie hilft beim Aufräumen und tröstet weinende Kinder.", ObservationDate: parseDate("2024-01-15"), IsApproved: true, ApprovedByUserID: intPtr(1)},
-		{ChildID: 1, TeacherID: 1, CategoryID: 2, ObservationDescription: "Anna verwendet komplexe Sätze und erzählt zusammenhängende Geschichten. Ihr Wortschatz erweitert sich täglich.", ObservationDate: parseDate("2024-02-10"), IsApproved: true, ApprovedByUserID: intPtr(1)},
-		{ChildID: 2, TeacherID: 1, CategoryID: 2, ObservationDescription: "Liam macht große Fortschritte in der deutschen Sprache. Er kommuniziert zunehmend auf Deutsch mit anderen Kindern.", ObservationDate: parseDate("2024-01-20"), IsApproved: true, ApprovedByUserID: intPtr(1)},
-		{ChildID: 3, TeacherID: 2, CategoryID: 4, ObservationDescription: "Ben löst Puzzles mit 50+ Teilen selbständig und zeigt dabei große Ausdauer und logisches Denken.", ObservationDate: parseDate("2024-02-05"), IsApproved: true, ApprovedByUserID: intPtr(2)},
-		{ChildID: 4, TeacherID: 2, CategoryID: 3, ObservationDescription: "Noah zeigt ausgezeichnete Feinmotorik beim Basteln und kann bereits seinen Namen schreiben.", ObservationDate: parseDate("2024-01-25"), IsApproved: false, ApprovedByUserID: nil},
-		{ChildID: 5, TeacherID: 3, CategoryID: 6, ObservationDescription: "Mia reguliert ihre Emotionen sehr gut und kann Konflikte verbal lösen, anstatt zu weinen oder zu schreien.", ObservationDate: parseDate("2024-02-12"), IsApproved: true, ApprovedByUserID: intPtr(3)},
-		{ChildID: 6, TeacherID: 3, CategoryID: 1, ObservationDescription: "Lucas integriert sich gut in die Gruppe und hat bereits enge Freundschaften entwickelt.", ObservationDate: parseDate("2024-01-30"), IsApproved: true, ApprovedByUserID: intPtr(3)},
-		{ChildID: 7, TeacherID: 4, CategoryID: 5, ObservationDescription: "Charlotte zeigt große Kreativität beim Malen und Basteln. Ihre Kunstwerke sind sehr detailreich und fantasievoll.", ObservationDate: parseDate("2024-02-08"), IsApproved: true, ApprovedByUserID: intPtr(4)},
-		{ChildID: 8, TeacherID: 4, CategoryID: 3, ObservationDescription: "Oliver turnt gerne und zeigt gute Koordination beim Klettern und Balancieren.", ObservationDate: parseDate("2024-02-01"), IsApproved: false, ApprovedByUserID: nil},
-		{ChildID: 1, TeacherID: 1, CategoryID: 4, ObservationDescription: "Anna zeigt Interesse an mathematischen Konzepten und kann bis 20 zählen.", ObservationDate: parseDate("2024-02-20"), IsApproved: false, ApprovedByUserID: nil},
+		{ChildID: 1, TeacherID: 1, CategoryID: 1, ObservationDescription: "Anna zeigt große Hilfsbereitschaft gegenüber anderen Kindern. Sie hilft beim Aufräumen und tröstet weinende Kinder.", ObservationDate: parseDay("2024-01-15"), IsApproved: true, ApprovedByUserID: intPtr(1)},
+		{ChildID: 1, TeacherID: 1, CategoryID: 2, ObservationDescription: "Anna verwendet komplexe Sätze und erzählt zusammenhängende Geschichten. Ihr Wortschatz erweitert sich täglich.", ObservationDate: parseDay("2024-02-10"), IsApproved: true, ApprovedByUserID: intPtr(1)},
+		{ChildID: 2, TeacherID: 1, CategoryID: 2, ObservationDescription: "Liam macht große Fortschritte in der deutschen Sprache. Er kommuniziert zunehmend auf Deutsch mit anderen Kindern.", ObservationDate: parseDay("2024-01-20"), IsApproved: true, ApprovedByUserID: intPtr(1)},
+		{ChildID: 3, TeacherID: 2, CategoryID: 4, ObservationDescription: "Ben löst Puzzles mit 50+ Teilen selbständig und zeigt dabei große Ausdauer und logisches Denken.", ObservationDate: parseDay("2024-02-05"), IsApproved: true, ApprovedByUserID: intPtr(2)},
+		{ChildID: 4, TeacherID: 2, CategoryID: 3, ObservationDescription: "Noah zeigt ausgezeichnete Feinmotorik beim Basteln und kann bereits seinen Namen schreiben.", ObservationDate: parseDay("2024-01-25"), IsApproved: false, ApprovedByUserID: nil},
+		{ChildID: 5, TeacherID: 3, CategoryID: 6, ObservationDescription: "Mia reguliert ihre Emotionen sehr gut und kann Konflikte verbal lösen, anstatt zu weinen oder zu schreien.", ObservationDate: parseDay("2024-02-12"), IsApproved: true, ApprovedByUserID: intPtr(3)},
+		{ChildID: 6, TeacherID: 3, CategoryID: 1, ObservationDescription: "Lucas integriert sich gut in die Gruppe und hat bereits enge Freundschaften entwickelt.", ObservationDate: parseDay("2024-01-30"), IsApproved: true, ApprovedByUserID: intPtr(3)},
+		{ChildID: 7, TeacherID: 4, CategoryID: 5, ObservationDescription: "Charlotte zeigt große Kreativität beim Malen und Basteln. Ihre Kunstwerke sind sehr detailreich und fantasievoll.", ObservationDate: parseDay("2024-02-08"), IsApproved: true, ApprovedByUserID: intPtr(4)},
+		{ChildID: 8, TeacherID: 4, CategoryID: 3, ObservationDescription: "Oliver turnt gerne und zeigt gute Koordination beim Klettern und Balancieren.", ObservationDate: parseDay("2024-02-01"), IsApproved: false, ApprovedByUserID: nil},
+		{ChildID: 1, TeacherID: 1, CategoryID: 4, ObservationDescription: "Anna zeigt Interesse an mathematischen Konzepten und kann bis 20 zählen.", ObservationDate: parseDay("2024-02-20"), IsApproved: false, ApprovedByUserID: nil},
 	}
 
 	for i := range docEntries {
-		if _, err := dal.DocumentationEntries.Create(&docEntries[i]); err != nil {
-			log.Fatalf("failed to create documentation entry: %v", err)
+		entry := &docEntries[i]
+		entry.ChildID = childIDs[entry.ChildID-1]
+		entry.TeacherID = teacherIDs[entry.TeacherID-1]
+		entry.CategoryID = categoryIDs[entry.CategoryID-1]
+		if entry.ApprovedByUserID != nil {
+			entry.ApprovedByUserID = intPtr(teacherIDs[*entry.ApprovedByUserID-1])
 		}
 	}
+	if _, err := dal.DocumentationEntries.CreateMany(ctx, docEntries); err != nil {
+		log.Fatalf("failed to create documentation entries: %v", err)
+	}
 
 	fmt.Println("Database seeded successfully")
 }
 
+// ensureCategory returns the ID of the category with the same name, creating it if it does not exist.
+func ensureCategory(ctx context.Context, dal *data.DAL, category *models.Category) int {
+	existing, err := dal.Categories.GetByName(ctx, category.Name)
+	if err == nil {
+		return existing.ID
+	}
+	if !errors.Is(err, data.ErrNotFound) {
+		log.Fatalf("failed to look up category %s: %v", category.Name, err)
+	}
+	id, err := dal.Categories.Create(ctx, category)
+	if err != nil {
+		log.Fatalf("failed to create category %s: %v", category.Name, err)
+	}
+	return id
+}
+
+// ensureTeacher returns the ID of the teacher with the same username, creating it if it does not exist.
+func ensureTeacher(ctx context.Context, dal *data.DAL, existing []models.Teacher, teacher *models.Teacher) int {
+	for _, t := range existing {
+		if t.Username == teacher.Username {
+			return t.ID
+		}
+	}
+	id, err := dal.Teachers.Create(ctx, teacher)
+	if err != nil {
+		log.Fatalf("failed to create teacher %s: %v", teacher.Username, err)
+	}
+	return id
+}
+
 func intPtr(i int) *int { return &i }
 
 func timePtr(t time.Time) *time.Time {
@@ -0,0 +1,359 @@
+package config
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("KINDERGARTEN_SERVER_JWT_SECRET", "a-jwt-secret-that-is-long-enough-for-staging")
+	t.Setenv("KINDERGARTEN_DATABASE_ENCRYPTION_KEY", "0123456789abcdef0123456789abcdef")
+}
+
+func TestLoadConfigProfiles(t *testing.T) {
+	t.Run("defaults to development", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, ProfileDevelopment, cfg.Environment)
+		assert.Equal(t, "debug", cfg.Log.Level)
+		assert.Equal(t, "text", cfg.Log.Format)
+		assert.True(t, cfg.Registration.Open)
+	})
+
+	t.Run("profile from the environment", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("KINDERGARTEN_PROFILE", ProfileStaging)
+		cfg, err := LoadConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, ProfileStaging, cfg.Environment)
+		assert.Equal(t, "json", cfg.Log.Format)
+		assert.False(t, cfg.Registration.Open, "registration is invitation-only outside of development")
+	})
+
+	t.Run("environment variables override profile defaults", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("KINDERGARTEN_DATABASE_DSN", "file:kitadoc.db")
+		t.Setenv("KINDERGARTEN_LOG_FORMAT", "text")
+		cfg, err := LoadConfig(ProfileProduction)
+		require.NoError(t, err)
+		assert.Equal(t, "info", cfg.Log.Level)
+		assert.Equal(t, "text", cfg.Log.Format)
+	})
+
+	t.Run("profile config file", func(t *testing.T) {
+		setRequiredEnv(t)
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("log:\n  level: warn\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.staging.yaml"), []byte("outbox:\n  max_attempts: 3\n"), 0o600))
+		t.Chdir(dir)
+
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, "warn", cfg.Log.Level)
+		assert.Equal(t, 3, cfg.Outbox.MaxAttempts)
+	})
+
+	t.Run("facility time zone", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Berlin", cfg.Facility.Location.String())
+
+		t.Setenv("KINDERGARTEN_FACILITY_TIMEZONE", "Berlin")
+		_, err = LoadConfig("")
+		assert.ErrorContains(t, err, "not a known time zone")
+	})
+
+	t.Run("fixture needs no configuration", func(t *testing.T) {
+		cfg, err := LoadConfig(ProfileFixture)
+		require.NoError(t, err)
+		assert.Contains(t, cfg.Database.DSN, "mode=memory")
+		assert.Zero(t, cfg.Monitoring.HealthCheckInterval)
+
+		t.Setenv("KINDERGARTEN_DATABASE_DSN", "file:kitadoc.db")
+		_, err = LoadConfig(ProfileFixture)
+		assert.ErrorContains(t, err, "in-memory database")
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		setRequiredEnv(t)
+		_, err := LoadConfig("prod")
+		assert.ErrorContains(t, err, "unknown profile")
+	})
+
+	t.Run("production requires a database", func(t *testing.T) {
+		setRequiredEnv(t)
+		_, err := LoadConfig(ProfileProduction)
+		assert.ErrorContains(t, err, "database DSN cannot be empty")
+	})
+
+	t.Run("production rejects debug logging", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("KINDERGARTEN_DATABASE_DSN", "file:kitadoc.db")
+		t.Setenv("KINDERGARTEN_LOG_LEVEL", "debug")
+		_, err := LoadConfig(ProfileProduction)
+		assert.ErrorContains(t, err, "not allowed in the production profile")
+	})
+
+	t.Run("staging requires a long JWT secret", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("KINDERGARTEN_SERVER_JWT_SECRET", "short")
+		_, err := LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "at least 32 characters")
+
+		_, err = LoadConfig(ProfileDevelopment)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error reporting", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Empty(t, cfg.ErrorReporting.Provider)
+		assert.Equal(t, ProfileStaging, cfg.ErrorReporting.Environment, "reports are tagged with the profile by default")
+
+		t.Setenv("KINDERGARTEN_ERROR_REPORTING_PROVIDER", ErrorReportingSentry)
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "DSN cannot be empty")
+
+		t.Setenv("KINDERGARTEN_ERROR_REPORTING_PROVIDER", "rollbar")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "unknown error reporting provider")
+	})
+
+	t.Run("virus scan", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Empty(t, cfg.VirusScan.Provider, "uploads are not scanned by default")
+
+		t.Setenv("KINDERGARTEN_VIRUS_SCAN_PROVIDER", VirusScanClamAV)
+		cfg, err = LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, "tcp://127.0.0.1:3310", cfg.VirusScan.Address)
+
+		t.Setenv("KINDERGARTEN_VIRUS_SCAN_ADDRESS", "127.0.0.1:3310")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "must start with tcp:// or unix://")
+
+		t.Setenv("KINDERGARTEN_VIRUS_SCAN_PROVIDER", "sophos")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "unknown virus scan provider")
+	})
+
+	t.Run("session cookies", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.False(t, cfg.Session.Cookies, "cookie sessions are disabled by default")
+		assert.Equal(t, SameSiteStrict, cfg.Session.SameSite)
+		assert.True(t, cfg.Session.SecureCookies)
+
+		cfg, err = LoadConfig(ProfileDevelopment)
+		require.NoError(t, err)
+		assert.False(t, cfg.Session.SecureCookies, "the development server is reached over plain HTTP")
+
+		t.Setenv("KINDERGARTEN_SESSION_SAME_SITE", "none")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "session same site")
+
+		t.Setenv("KINDERGARTEN_SESSION_SAME_SITE", SameSiteLax)
+		t.Setenv("KINDERGARTEN_SESSION_COOKIES", "true")
+		t.Setenv("KINDERGARTEN_SESSION_SECURE_COOKIES", "false")
+		t.Setenv("KINDERGARTEN_DATABASE_DSN", "file:kitadoc.db")
+		_, err = LoadConfig(ProfileProduction)
+		assert.ErrorContains(t, err, "session cookies must be secure")
+	})
+
+	t.Run("session tokens", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, 15*time.Minute, cfg.Session.AccessTokenValidity)
+		assert.Equal(t, 7*24*time.Hour, cfg.Session.RefreshTokenValidity)
+
+		t.Setenv("KINDERGARTEN_SESSION_ACCESS_TOKEN_VALIDITY", "0s")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "session access token validity")
+
+		t.Setenv("KINDERGARTEN_SESSION_ACCESS_TOKEN_VALIDITY", "2h")
+		t.Setenv("KINDERGARTEN_SESSION_REFRESH_TOKEN_VALIDITY", "1h")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "session refresh token validity")
+	})
+
+	t.Run("security headers", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, 365*24*time.Hour, cfg.SecurityHeaders.HSTSMaxAge)
+		assert.Equal(t, "strict-origin-when-cross-origin", cfg.SecurityHeaders.ReferrerPolicy)
+		assert.Contains(t, cfg.SecurityHeaders.ContentSecurityPolicy, "frame-ancestors 'none'")
+
+		cfg, err = LoadConfig(ProfileDevelopment)
+		require.NoError(t, err)
+		assert.Zero(t, cfg.SecurityHeaders.HSTSMaxAge, "the development server is reached over plain HTTP")
+		assert.Contains(t, cfg.SecurityHeaders.ContentSecurityPolicy, "'unsafe-eval'")
+
+		t.Setenv("KINDERGARTEN_SECURITY_HEADERS_REFERRER_POLICY", "never")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "unknown referrer policy")
+
+		t.Setenv("KINDERGARTEN_SECURITY_HEADERS_REFERRER_POLICY", "no-referrer")
+		t.Setenv("KINDERGARTEN_SECURITY_HEADERS_CONTENT_SECURITY_POLICY", "default-src 'self'\r\nSet-Cookie: x=y")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "cannot contain line breaks")
+	})
+
+	t.Run("network allowlist", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("KINDERGARTEN_NETWORK_ALLOWLIST_ADMIN", "192.168.10.0/24,10.8.0.5")
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("192.168.10.0/24"), netip.MustParsePrefix("10.8.0.5/32")},
+			cfg.NetworkAllowlist.AdminNetworks)
+		assert.Empty(t, cfg.NetworkAllowlist.BulkExportNetworks, "every network is allowed by default")
+
+		t.Setenv("KINDERGARTEN_NETWORK_ALLOWLIST_BULK_EXPORT", "192.168.10.0/33")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "bulk export network allowlist")
+	})
+
+	t.Run("database driver", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, DatabaseSQLite, cfg.Database.Driver)
+
+		t.Setenv("KINDERGARTEN_DATABASE_DRIVER", DatabasePostgres)
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "requires a DSN like postgres://")
+		t.Setenv("KINDERGARTEN_DATABASE_DSN", "postgres://kitadoc:secret@db/kitadoc")
+		cfg, err = LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, DatabasePostgres, cfg.Database.Driver)
+		_, err = LoadConfig(ProfileFixture)
+		assert.ErrorContains(t, err, "the fixture profile requires the sqlite driver")
+
+		t.Setenv("KINDERGARTEN_DATABASE_DRIVER", "mysql")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, `unknown database driver "mysql"`)
+	})
+
+	t.Run("break glass", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, 2*time.Hour, cfg.BreakGlass.Validity)
+		assert.Equal(t, 5, cfg.BreakGlass.MaxAttempts)
+		assert.Equal(t, 15*time.Minute, cfg.BreakGlass.AttemptWindow)
+
+		t.Setenv("KINDERGARTEN_BREAK_GLASS_ALERT_EMAIL", "traeger@kita.example")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "break glass alert email requires an email smtp host")
+		t.Setenv("KINDERGARTEN_EMAIL_SMTP_HOST", "mail.kita.example")
+		t.Setenv("KINDERGARTEN_EMAIL_FROM", "Kitadoc <kitadoc@kita.example>")
+		cfg, err = LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, "traeger@kita.example", cfg.BreakGlass.AlertEmail)
+
+		t.Setenv("KINDERGARTEN_BREAK_GLASS_ATTEMPT_WINDOW", "0s")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "break glass attempt window must be greater than 0")
+		t.Setenv("KINDERGARTEN_BREAK_GLASS_MAX_ATTEMPTS", "0")
+		_, err = LoadConfig(ProfileStaging)
+		require.NoError(t, err, "without a limit the window is not needed")
+
+		t.Setenv("KINDERGARTEN_BREAK_GLASS_VALIDITY", "0s")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "break glass validity must be greater than 0")
+	})
+
+	t.Run("client encryption", func(t *testing.T) {
+		setRequiredEnv(t)
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Empty(t, cfg.ClientEncryption.Mode, "client-side encryption is disabled by default")
+
+		t.Setenv("KINDERGARTEN_CLIENT_ENCRYPTION_MODE", ClientEncryptionRequired)
+		cfg, err = LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, ClientEncryptionRequired, cfg.ClientEncryption.Mode)
+
+		t.Setenv("KINDERGARTEN_CLIENT_ENCRYPTION_MODE", "always")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "unknown client encryption mode")
+	})
+
+	t.Run("chaos rules", func(t *testing.T) {
+		setRequiredEnv(t)
+		dir := t.TempDir()
+		rules := "chaos:\n  rules:\n    - route: \"GET /api/v1/children\"\n      latency: 2s\n      error_rate: 0.2\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(rules), 0o600))
+		t.Chdir(dir)
+
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		require.Len(t, cfg.Chaos.Rules, 1)
+		assert.Equal(t, 2*time.Second, cfg.Chaos.Rules[0].Latency)
+		assert.Equal(t, 0.2, cfg.Chaos.Rules[0].ErrorRate)
+		assert.False(t, cfg.Chaos.Enabled, "only the flag enables chaos injection")
+		require.NoError(t, cfg.EnableChaos())
+		assert.True(t, cfg.Chaos.Enabled)
+
+		cfg.Environment = ProfileProduction
+		assert.ErrorContains(t, cfg.EnableChaos(), "not allowed in the production profile")
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("chaos:\n  rules:\n    - route: \"*\"\n      error_rate: 1.5\n"), 0o600))
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "between 0 and 1")
+	})
+
+	t.Run("fee export", func(t *testing.T) {
+		setRequiredEnv(t)
+		dir := t.TempDir()
+		t.Chdir(dir)
+
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, ";", cfg.FeeExport.Delimiter)
+		assert.True(t, cfg.FeeExport.DecimalComma)
+		assert.Empty(t, cfg.FeeExport.Columns, "all fields are exported by default")
+
+		columns := "fee_export:\n  delimiter: \",\"\n  columns:\n    - field: child_id\n      header: Kind-Nr\n    - field: attended_hours\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(columns), 0o600))
+		cfg, err = LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.Equal(t, ",", cfg.FeeExport.Delimiter)
+		assert.Equal(t, []FeeExportColumn{{Field: "child_id", Header: "Kind-Nr"}, {Field: "attended_hours"}}, cfg.FeeExport.Columns)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("fee_export:\n  columns:\n    - field: iban\n"), 0o600))
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "unknown fee export field")
+
+		t.Setenv("KINDERGARTEN_FEE_EXPORT_DELIMITER", ";;")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), nil, 0o600))
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "single character")
+	})
+	t.Run("benchmarking", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Chdir(t.TempDir())
+
+		cfg, err := LoadConfig(ProfileStaging)
+		require.NoError(t, err)
+		assert.False(t, cfg.Benchmarking.Enabled, "benchmarking is opt-in")
+		assert.Equal(t, 5, cfg.Benchmarking.MinPeers)
+
+		t.Setenv("KINDERGARTEN_BENCHMARKING_MIN_PEERS", "1")
+		_, err = LoadConfig(ProfileStaging)
+		assert.ErrorContains(t, err, "min peers")
+	})
+}
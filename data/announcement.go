@@ -0,0 +1,147 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"kitadoc-backend/models"
+)
+
+// AnnouncementStore defines the interface for Announcement data operations.
+type AnnouncementStore interface {
+	Create(ctx context.Context, announcement *models.Announcement) (int, error)
+	GetByID(ctx context.Context, id int) (*models.Announcement, error)
+	GetAll(ctx context.Context) ([]models.Announcement, error)
+	Delete(ctx context.Context, id int) error
+	GetUnreadForUser(ctx context.Context, userID int) ([]models.Announcement, error)
+	Acknowledge(ctx context.Context, announcementID int, userID int) error
+	GetAcknowledgements(ctx context.Context, announcementID int) ([]models.AnnouncementAcknowledgement, error)
+}
+
+// SQLAnnouncementStore implements AnnouncementStore using database/sql.
+type SQLAnnouncementStore struct {
+	db *sql.DB
+}
+
+// NewSQLAnnouncementStore creates a new SQLAnnouncementStore.
+func NewSQLAnnouncementStore(db *sql.DB) *SQLAnnouncementStore {
+	return &SQLAnnouncementStore{db: db}
+}
+
+// Create inserts a new announcement into the database.
+func (s *SQLAnnouncementStore) Create(ctx context.Context, announcement *models.Announcement) (int, error) {
+	query := `INSERT INTO announcements (title, body, created_by_user_id) VALUES (?, ?, ?) RETURNING announcement_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, announcement.Title, announcement.Body, announcement.CreatedByUserID).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches an announcement by ID from the database.
+func (s *SQLAnnouncementStore) GetByID(ctx context.Context, id int) (*models.Announcement, error) {
+	query := `SELECT announcement_id, title, body, created_by_user_id, created_at, updated_at FROM announcements WHERE announcement_id = ?`
+	row := s.db.QueryRowContext(ctx, query, id)
+	announcement := &models.Announcement{}
+	err := row.Scan(&announcement.ID, &announcement.Title, &announcement.Body, &announcement.CreatedByUserID, &announcement.CreatedAt, &announcement.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return announcement, nil
+}
+
+// GetAll fetches all announcements, newest first.
+func (s *SQLAnnouncementStore) GetAll(ctx context.Context) ([]models.Announcement, error) {
+	query := `SELECT announcement_id, title, body, created_by_user_id, created_at, updated_at FROM announcements ORDER BY created_at DESC, announcement_id DESC`
+	return s.queryAnnouncements(ctx, query)
+}
+
+// GetUnreadForUser fetches all announcements the user has not acknowledged yet, newest first.
+func (s *SQLAnnouncementStore) GetUnreadForUser(ctx context.Context, userID int) ([]models.Announcement, error) {
+	query := `SELECT a.announcement_id, a.title, a.body, a.created_by_user_id, a.created_at, a.updated_at
+		FROM announcements a
+		WHERE NOT EXISTS (
+			SELECT 1 FROM announcement_acknowledgements aa
+			WHERE aa.announcement_id = a.announcement_id AND aa.user_id = ?
+		)
+		ORDER BY a.created_at DESC, a.announcement_id DESC`
+	return s.queryAnnouncements(ctx, query, userID)
+}
+
+func (s *SQLAnnouncementStore) queryAnnouncements(ctx context.Context, query string, args ...any) ([]models.Announcement, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var announcements []models.Announcement
+	for rows.Next() {
+		announcement := models.Announcement{}
+		err := rows.Scan(&announcement.ID, &announcement.Title, &announcement.Body, &announcement.CreatedByUserID, &announcement.CreatedAt, &announcement.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, announcement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return announcements, nil
+}
+
+// Delete deletes an announcement by ID from the database. Acknowledgements are removed by cascade.
+func (s *SQLAnnouncementStore) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM announcements WHERE announcement_id = ?`
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Acknowledge records that a user has read an announcement. Acknowledging twice keeps the first timestamp.
+func (s *SQLAnnouncementStore) Acknowledge(ctx context.Context, announcementID int, userID int) error {
+	query := `INSERT INTO announcement_acknowledgements (announcement_id, user_id) VALUES (?, ?)
+		ON CONFLICT(announcement_id, user_id) DO NOTHING`
+	_, err := s.db.ExecContext(ctx, query, announcementID, userID)
+	return err
+}
+
+// GetAcknowledgements fetches all read receipts of an announcement.
+func (s *SQLAnnouncementStore) GetAcknowledgements(ctx context.Context, announcementID int) ([]models.AnnouncementAcknowledgement, error) {
+	query := `SELECT announcement_id, user_id, acknowledged_at FROM announcement_acknowledgements WHERE announcement_id = ? ORDER BY acknowledged_at, user_id`
+	rows, err := s.db.QueryContext(ctx, query, announcementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var acknowledgements []models.AnnouncementAcknowledgement
+	for rows.Next() {
+		acknowledgement := models.AnnouncementAcknowledgement{}
+		if err := rows.Scan(&acknowledgement.AnnouncementID, &acknowledgement.UserID, &acknowledgement.AcknowledgedAt); err != nil {
+			return nil, err
+		}
+		acknowledgements = append(acknowledgements, acknowledgement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return acknowledgements, nil
+}
@@ -0,0 +1,63 @@
+package data_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLAnnouncementStore_GetUnreadForUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLAnnouncementStore(db)
+	query := regexp.QuoteMeta(`SELECT a.announcement_id, a.title, a.body, a.created_by_user_id, a.created_at, a.updated_at`)
+
+	t.Run("success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(query).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"announcement_id", "title", "body", "created_by_user_id", "created_at", "updated_at"}).
+				AddRow(1, "Hygieneplan", "Bitte lesen", 1, now, now))
+
+		announcements, err := store.GetUnreadForUser(t.Context(), 2)
+		assert.NoError(t, err)
+		assert.Len(t, announcements, 1)
+		assert.Equal(t, "Hygieneplan", announcements[0].Title)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(2).WillReturnError(errors.New("db error"))
+
+		announcements, err := store.GetUnreadForUser(t.Context(), 2)
+		assert.Error(t, err)
+		assert.Nil(t, announcements)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLAnnouncementStore_Acknowledge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLAnnouncementStore(db)
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO announcement_acknowledgements (announcement_id, user_id) VALUES (?, ?)`)).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, store.Acknowledge(t.Context(), 1, 2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
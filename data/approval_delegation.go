@@ -0,0 +1,106 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"kitadoc-backend/models"
+)
+
+// ApprovalDelegationStore defines the interface for ApprovalDelegation data operations.
+type ApprovalDelegationStore interface {
+	Create(ctx context.Context, delegation *models.ApprovalDelegation) (int, error)
+	GetByID(ctx context.Context, id int) (*models.ApprovalDelegation, error)
+	GetAllForUser(ctx context.Context, userID int) ([]models.ApprovalDelegation, error)
+	GetBetween(ctx context.Context, delegatorUserID int, delegateUserID int) ([]models.ApprovalDelegation, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// SQLApprovalDelegationStore implements ApprovalDelegationStore using database/sql.
+type SQLApprovalDelegationStore struct {
+	db *sql.DB
+}
+
+// NewSQLApprovalDelegationStore creates a new SQLApprovalDelegationStore.
+func NewSQLApprovalDelegationStore(db *sql.DB) *SQLApprovalDelegationStore {
+	return &SQLApprovalDelegationStore{db: db}
+}
+
+// Create inserts a new approval delegation into the database.
+func (s *SQLApprovalDelegationStore) Create(ctx context.Context, delegation *models.ApprovalDelegation) (int, error) {
+	query := `INSERT INTO approval_delegations (delegator_user_id, delegate_user_id, start_date, end_date) VALUES (?, ?, ?, ?) RETURNING delegation_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, delegation.DelegatorUserID, delegation.DelegateUserID, delegation.StartDate, delegation.EndDate).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches an approval delegation by ID from the database.
+func (s *SQLApprovalDelegationStore) GetByID(ctx context.Context, id int) (*models.ApprovalDelegation, error) {
+	query := `SELECT delegation_id, delegator_user_id, delegate_user_id, start_date, end_date, created_at FROM approval_delegations WHERE delegation_id = ?`
+	row := s.db.QueryRowContext(ctx, query, id)
+	delegation := &models.ApprovalDelegation{}
+	err := row.Scan(&delegation.ID, &delegation.DelegatorUserID, &delegation.DelegateUserID, &delegation.StartDate, &delegation.EndDate, &delegation.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return delegation, nil
+}
+
+// GetAllForUser fetches all delegations the user has granted or received, latest start first.
+func (s *SQLApprovalDelegationStore) GetAllForUser(ctx context.Context, userID int) ([]models.ApprovalDelegation, error) {
+	query := `SELECT delegation_id, delegator_user_id, delegate_user_id, start_date, end_date, created_at FROM approval_delegations WHERE delegator_user_id = ? OR delegate_user_id = ? ORDER BY start_date DESC, delegation_id DESC`
+	return s.queryDelegations(ctx, query, userID, userID)
+}
+
+// GetBetween fetches all delegations from the delegator to the delegate, regardless of their date range.
+func (s *SQLApprovalDelegationStore) GetBetween(ctx context.Context, delegatorUserID int, delegateUserID int) ([]models.ApprovalDelegation, error) {
+	query := `SELECT delegation_id, delegator_user_id, delegate_user_id, start_date, end_date, created_at FROM approval_delegations WHERE delegator_user_id = ? AND delegate_user_id = ? ORDER BY start_date DESC, delegation_id DESC`
+	return s.queryDelegations(ctx, query, delegatorUserID, delegateUserID)
+}
+
+func (s *SQLApprovalDelegationStore) queryDelegations(ctx context.Context, query string, args ...any) ([]models.ApprovalDelegation, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var delegations []models.ApprovalDelegation
+	for rows.Next() {
+		delegation := models.ApprovalDelegation{}
+		err := rows.Scan(&delegation.ID, &delegation.DelegatorUserID, &delegation.DelegateUserID, &delegation.StartDate, &delegation.EndDate, &delegation.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, delegation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+// Delete deletes an approval delegation by ID from the database.
+func (s *SQLApprovalDelegationStore) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM approval_delegations WHERE delegation_id = ?`
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
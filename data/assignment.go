@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"kitadoc-backend/internal/logger"
@@ -9,21 +10,45 @@ import (
 
 // AssignmentStore defines the interface for Assignment data operations.
 type AssignmentStore interface {
-	Create(assignment *models.Assignment) (int, error)
-	GetByID(id int) (*models.Assignment, error)
-	Update(assignment *models.Assignment) error
-	Delete(id int) error
-	GetAssignmentHistoryForChild(childID int) ([]models.Assignment, error)
-	GetAllAssignments() ([]models.Assignment, error)
-	EndAssignment(assignmentID int) error
+	Create(ctx context.Context, assignment *models.Assignment) (int, error)
+	GetByID(ctx context.Context, id int) (*models.Assignment, error)
+	Update(ctx context.Context, assignment *models.Assignment) error
+	Delete(ctx context.Context, id int) error
+	GetAssignmentHistoryForChild(ctx context.Context, childID int) ([]models.Assignment, error)
+	GetAllAssignments(ctx context.Context) ([]models.Assignment, error)
+	// List fetches the assignments matching the query, by default the latest start date first.
+	List(ctx context.Context, query models.ListQuery) ([]models.Assignment, error)
+	EndAssignment(ctx context.Context, assignmentID int) error
+}
+
+const assignmentSelect = `SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments`
+
+// assignmentListColumns are the fields assignments can be filtered and sorted by.
+var assignmentListColumns = listColumns{
+	"id":              "assignment_id",
+	"child_id":        "child_id",
+	"teacher_id":      "teacher_id",
+	"assignment_type": "assignment_type",
+	"start_date":      "start_date",
+	"end_date":        "end_date",
+	"created_at":      "created_at",
+	"updated_at":      "updated_at",
 }
 
 // GetAllAssignments fetches all assignments from the database.
-func (s *SQLAssignmentStore) GetAllAssignments() ([]models.Assignment, error) {
-	query := `SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments ORDER BY start_date DESC`
-	rows, err := s.db.Query(query)
+func (s *SQLAssignmentStore) GetAllAssignments(ctx context.Context) ([]models.Assignment, error) {
+	return s.List(ctx, models.ListQuery{})
+}
+
+// List fetches the assignments matching the query, by default the latest start date first.
+func (s *SQLAssignmentStore) List(ctx context.Context, query models.ListQuery) ([]models.Assignment, error) {
+	statement, args, err := buildListQuery(assignmentSelect, assignmentListColumns, query, models.SortField{Field: "start_date", Descending: true})
 	if err != nil {
-		logger.GetGlobalLogger().Errorf("Error fetching all assignments: %v", err)
+		return nil, err
+	}
+	rows, err := s.statements.query(ctx, statement, args...)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error fetching assignments: %v", err)
 		return nil, err
 	}
 	defer rows.Close() //nolint:errcheck
@@ -31,7 +56,7 @@ func (s *SQLAssignmentStore) GetAllAssignments() ([]models.Assignment, error) {
 	var assignments []models.Assignment
 	for rows.Next() {
 		assignment := &models.Assignment{}
-		err := rows.Scan(&assignment.ID, &assignment.ChildID, &assignment.TeacherID, &assignment.StartDate, &assignment.EndDate, &assignment.CreatedAt, &assignment.UpdatedAt)
+		err := rows.Scan(&assignment.ID, &assignment.ChildID, &assignment.TeacherID, &assignment.AssignmentType, &assignment.StartDate, &assignment.EndDate, &assignment.CreatedAt, &assignment.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -39,7 +64,7 @@ func (s *SQLAssignmentStore) GetAllAssignments() ([]models.Assignment, error) {
 	}
 
 	if err = rows.Err(); err != nil {
-		logger.GetGlobalLogger().Errorf("Error iterating over all assignments: %v", err)
+		logger.GetGlobalLogger().Errorf("Error iterating over assignments: %v", err)
 		return nil, err
 	}
 
@@ -47,10 +72,13 @@ func (s *SQLAssignmentStore) GetAllAssignments() ([]models.Assignment, error) {
 }
 
 // Update updates an existing assignment in the database.
-func (s *SQLAssignmentStore) Update(assignment *models.Assignment) error {
-	query := `UPDATE child_teacher_assignments SET child_id = ?, teacher_id = ?, start_date = ?, end_date = ?, updated_at = ? WHERE assignment_id = ?`
-	result, err := s.db.Exec(query, assignment.ChildID, assignment.TeacherID, assignment.StartDate, assignment.EndDate, assignment.UpdatedAt, assignment.ID)
+func (s *SQLAssignmentStore) Update(ctx context.Context, assignment *models.Assignment) error {
+	query := `UPDATE child_teacher_assignments SET child_id = ?, teacher_id = ?, assignment_type = ?, start_date = ?, end_date = ?, updated_at = ? WHERE assignment_id = ?`
+	result, err := s.db.ExecContext(ctx, query, assignment.ChildID, assignment.TeacherID, assignment.AssignmentType, assignment.StartDate, assignment.EndDate, assignment.UpdatedAt, assignment.ID)
 	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrConflict // The child already has an open primary assignment
+		}
 		logger.GetGlobalLogger().Errorf("Error updating assignment: %v", err)
 		return err
 	}
@@ -67,36 +95,35 @@ func (s *SQLAssignmentStore) Update(assignment *models.Assignment) error {
 
 // SQLAssignmentStore implements AssignmentStore using database/sql.
 type SQLAssignmentStore struct {
-	db *sql.DB
+	db         *sql.DB
+	statements *statementCache
 }
 
 // NewSQLAssignmentStore creates a new SQLAssignmentStore.
 func NewSQLAssignmentStore(db *sql.DB) *SQLAssignmentStore {
-	return &SQLAssignmentStore{db: db}
+	return &SQLAssignmentStore{db: db, statements: newStatementCache(db, maxCachedStatements)}
 }
 
 // Create inserts a new assignment into the database.
-func (s *SQLAssignmentStore) Create(assignment *models.Assignment) (int, error) {
-	query := `INSERT INTO child_teacher_assignments (child_id, teacher_id, start_date, end_date) VALUES (?, ?, ?, ?)`
-	result, err := s.db.Exec(query, assignment.ChildID, assignment.TeacherID, assignment.StartDate, assignment.EndDate)
-	if err != nil {
+func (s *SQLAssignmentStore) Create(ctx context.Context, assignment *models.Assignment) (int, error) {
+	query := `INSERT INTO child_teacher_assignments (child_id, teacher_id, assignment_type, start_date, end_date) VALUES (?, ?, ?, ?, ?) RETURNING assignment_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, assignment.ChildID, assignment.TeacherID, assignment.AssignmentType, assignment.StartDate, assignment.EndDate).Scan(&id); err != nil {
+		if isUniqueConstraintError(err) {
+			return 0, ErrConflict // The child already has an open primary assignment
+		}
 		logger.GetGlobalLogger().Errorf("Error inserting assignment: %v", err)
 		return 0, err
 	}
-	id, err := result.LastInsertId()
-	if err != nil {
-		logger.GetGlobalLogger().Errorf("Error getting last insert ID: %v", err)
-		return 0, err
-	}
-	return int(id), nil
+	return id, nil
 }
 
 // GetByID fetches an assignment by ID from the database.
-func (s *SQLAssignmentStore) GetByID(id int) (*models.Assignment, error) {
-	query := `SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE assignment_id = ?`
-	row := s.db.QueryRow(query, id)
+func (s *SQLAssignmentStore) GetByID(ctx context.Context, id int) (*models.Assignment, error) {
+	query := `SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE assignment_id = ?`
+	row := s.db.QueryRowContext(ctx, query, id)
 	assignment := &models.Assignment{}
-	err := row.Scan(&assignment.ID, &assignment.ChildID, &assignment.TeacherID, &assignment.StartDate, &assignment.EndDate, &assignment.CreatedAt, &assignment.UpdatedAt)
+	err := row.Scan(&assignment.ID, &assignment.ChildID, &assignment.TeacherID, &assignment.AssignmentType, &assignment.StartDate, &assignment.EndDate, &assignment.CreatedAt, &assignment.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -108,9 +135,9 @@ func (s *SQLAssignmentStore) GetByID(id int) (*models.Assignment, error) {
 }
 
 // Delete deletes an assignment by ID from the database.
-func (s *SQLAssignmentStore) Delete(id int) error {
+func (s *SQLAssignmentStore) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM child_teacher_assignments WHERE assignment_id = ?`
-	result, err := s.db.Exec(query, id)
+	result, err := s.db.ExecContext(ctx, query, id)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error deleting assignment with ID %d: %v", id, err)
 		return err
@@ -127,37 +154,14 @@ func (s *SQLAssignmentStore) Delete(id int) error {
 }
 
 // GetAssignmentHistoryForChild fetches all assignments for a specific child.
-func (s *SQLAssignmentStore) GetAssignmentHistoryForChild(childID int) ([]models.Assignment, error) {
-	query := `SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE child_id = ? ORDER BY start_date DESC`
-	rows, err := s.db.Query(query, childID)
-	if err != nil {
-		logger.GetGlobalLogger().Errorf("Error fetching assignment history for child ID %d: %v", childID, err)
-		return nil, err
-	}
-	defer rows.Close() //nolint:errcheck
-
-	var assignments []models.Assignment
-	for rows.Next() {
-		assignment := &models.Assignment{}
-		err := rows.Scan(&assignment.ID, &assignment.ChildID, &assignment.TeacherID, &assignment.StartDate, &assignment.EndDate, &assignment.CreatedAt, &assignment.UpdatedAt)
-		if err != nil {
-			return nil, err
-		}
-		assignments = append(assignments, *assignment)
-	}
-
-	if err = rows.Err(); err != nil {
-		logger.GetGlobalLogger().Errorf("Error iterating over assignment history for child ID %d: %v", childID, err)
-		return nil, err
-	}
-
-	return assignments, nil
+func (s *SQLAssignmentStore) GetAssignmentHistoryForChild(ctx context.Context, childID int) ([]models.Assignment, error) {
+	return s.List(ctx, models.ListQuery{}.Where("child_id", models.OperatorEqual, childID))
 }
 
 // EndAssignment sets the end_date for an assignment to the current time.
-func (s *SQLAssignmentStore) EndAssignment(assignmentID int) error {
+func (s *SQLAssignmentStore) EndAssignment(ctx context.Context, assignmentID int) error {
 	query := `UPDATE assignments SET end_date = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE assignment_id = ? AND end_date IS NULL`
-	result, err := s.db.Exec(query, assignmentID)
+	result, err := s.db.ExecContext(ctx, query, assignmentID)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error ending assignment with ID %d: %v", assignmentID, err)
 		return err
@@ -3,6 +3,7 @@ package data_test
 import (
 	"database/sql"
 	"errors"
+	"math"
 	"regexp"
 	"testing"
 	"time"
@@ -42,22 +43,22 @@ func TestSQLAssignmentStore_Create(t *testing.T) {
 	)
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO child_teacher_assignments (child_id, teacher_id, start_date, end_date) VALUES (?, ?, ?, ?)`)).
-			WithArgs(assignment.ChildID, assignment.TeacherID, assignment.StartDate, assignment.EndDate).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO child_teacher_assignments (child_id, teacher_id, assignment_type, start_date, end_date) VALUES (?, ?, ?, ?, ?) RETURNING assignment_id`)).
+			WithArgs(assignment.ChildID, assignment.TeacherID, assignment.AssignmentType, assignment.StartDate, assignment.EndDate).
+			WillReturnRows(sqlmock.NewRows([]string{"assignment_id"}).AddRow(1))
 
-		id, err := store.Create(assignment)
+		id, err := store.Create(t.Context(), assignment)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, id)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO child_teacher_assignments (child_id, teacher_id, start_date, end_date) VALUES (?, ?, ?, ?)`)).
-			WithArgs(assignment.ChildID, assignment.TeacherID, assignment.StartDate, assignment.EndDate).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO child_teacher_assignments (child_id, teacher_id, assignment_type, start_date, end_date) VALUES (?, ?, ?, ?, ?) RETURNING assignment_id`)).
+			WithArgs(assignment.ChildID, assignment.TeacherID, assignment.AssignmentType, assignment.StartDate, assignment.EndDate).
 			WillReturnError(errors.New("db error"))
 
-		id, err := store.Create(assignment)
+		id, err := store.Create(t.Context(), assignment)
 		assert.Error(t, err)
 		assert.Equal(t, 0, id)
 		assert.Contains(t, err.Error(), "db error")
@@ -86,14 +87,14 @@ func TestSQLAssignmentStore_GetByID(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"assignment_id", "child_id", "teacher_id", "start_date", "end_date", "created_at", "updated_at"}).
-			AddRow(expectedAssignment.ID, expectedAssignment.ChildID, expectedAssignment.TeacherID, expectedAssignment.StartDate, expectedAssignment.EndDate, expectedAssignment.CreatedAt, expectedAssignment.UpdatedAt)
+		rows := sqlmock.NewRows([]string{"assignment_id", "child_id", "teacher_id", "assignment_type", "start_date", "end_date", "created_at", "updated_at"}).
+			AddRow(expectedAssignment.ID, expectedAssignment.ChildID, expectedAssignment.TeacherID, expectedAssignment.AssignmentType, expectedAssignment.StartDate, expectedAssignment.EndDate, expectedAssignment.CreatedAt, expectedAssignment.UpdatedAt)
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE assignment_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE assignment_id = ?`)).
 			WithArgs(assignmentID).
 			WillReturnRows(rows)
 
-		assignment, err := store.GetByID(assignmentID)
+		assignment, err := store.GetByID(t.Context(), assignmentID)
 		assert.NoError(t, err)
 		assert.NotNil(t, assignment)
 		assert.Equal(t, expectedAssignment.ID, assignment.ID)
@@ -107,11 +108,11 @@ func TestSQLAssignmentStore_GetByID(t *testing.T) {
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE assignment_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE assignment_id = ?`)).
 			WithArgs(assignmentID).
 			WillReturnError(sql.ErrNoRows)
 
-		assignment, err := store.GetByID(assignmentID)
+		assignment, err := store.GetByID(t.Context(), assignmentID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.Nil(t, assignment)
@@ -119,11 +120,11 @@ func TestSQLAssignmentStore_GetByID(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE assignment_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE assignment_id = ?`)).
 			WithArgs(assignmentID).
 			WillReturnError(errors.New("db error"))
 
-		assignment, err := store.GetByID(assignmentID)
+		assignment, err := store.GetByID(t.Context(), assignmentID)
 		assert.Error(t, err)
 		assert.Nil(t, assignment)
 		assert.Contains(t, err.Error(), "db error")
@@ -150,32 +151,32 @@ func TestSQLAssignmentStore_Update(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE child_teacher_assignments SET child_id = ?, teacher_id = ?, start_date = ?, end_date = ?, updated_at = ? WHERE assignment_id = ?`)).
-			WithArgs(assignment.ChildID, assignment.TeacherID, assignment.StartDate, assignment.EndDate, assignment.UpdatedAt, assignment.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE child_teacher_assignments SET child_id = ?, teacher_id = ?, assignment_type = ?, start_date = ?, end_date = ?, updated_at = ? WHERE assignment_id = ?`)).
+			WithArgs(assignment.ChildID, assignment.TeacherID, assignment.AssignmentType, assignment.StartDate, assignment.EndDate, assignment.UpdatedAt, assignment.ID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Update(assignment)
+		err := store.Update(t.Context(), assignment)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE child_teacher_assignments SET child_id = ?, teacher_id = ?, start_date = ?, end_date = ?, updated_at = ? WHERE assignment_id = ?`)).
-			WithArgs(assignment.ChildID, assignment.TeacherID, assignment.StartDate, assignment.EndDate, assignment.UpdatedAt, assignment.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE child_teacher_assignments SET child_id = ?, teacher_id = ?, assignment_type = ?, start_date = ?, end_date = ?, updated_at = ? WHERE assignment_id = ?`)).
+			WithArgs(assignment.ChildID, assignment.TeacherID, assignment.AssignmentType, assignment.StartDate, assignment.EndDate, assignment.UpdatedAt, assignment.ID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := store.Update(assignment)
+		err := store.Update(t.Context(), assignment)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE child_teacher_assignments SET child_id = ?, teacher_id = ?, start_date = ?, end_date = ?, updated_at = ? WHERE assignment_id = ?`)).
-			WithArgs(assignment.ChildID, assignment.TeacherID, assignment.StartDate, assignment.EndDate, assignment.UpdatedAt, assignment.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE child_teacher_assignments SET child_id = ?, teacher_id = ?, assignment_type = ?, start_date = ?, end_date = ?, updated_at = ? WHERE assignment_id = ?`)).
+			WithArgs(assignment.ChildID, assignment.TeacherID, assignment.AssignmentType, assignment.StartDate, assignment.EndDate, assignment.UpdatedAt, assignment.ID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Update(assignment)
+		err := store.Update(t.Context(), assignment)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -198,7 +199,7 @@ func TestSQLAssignmentStore_Delete(t *testing.T) {
 			WithArgs(assignmentID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Delete(assignmentID)
+		err := store.Delete(t.Context(), assignmentID)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -208,7 +209,7 @@ func TestSQLAssignmentStore_Delete(t *testing.T) {
 			WithArgs(assignmentID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := store.Delete(assignmentID)
+		err := store.Delete(t.Context(), assignmentID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -219,7 +220,7 @@ func TestSQLAssignmentStore_Delete(t *testing.T) {
 			WithArgs(assignmentID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Delete(assignmentID)
+		err := store.Delete(t.Context(), assignmentID)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -243,15 +244,16 @@ func TestSQLAssignmentStore_GetAssignmentHistoryForChild(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "child_id", "teacher_id", "start_date", "end_date", "created_at", "updated_at"}).
-			AddRow(assignments[0].ID, assignments[0].ChildID, assignments[0].TeacherID, assignments[0].StartDate, assignments[0].EndDate, assignments[0].CreatedAt, assignments[0].UpdatedAt).
-			AddRow(assignments[1].ID, assignments[1].ChildID, assignments[1].TeacherID, assignments[1].StartDate, assignments[1].EndDate, assignments[1].CreatedAt, assignments[1].UpdatedAt)
+		rows := sqlmock.NewRows([]string{"id", "child_id", "teacher_id", "assignment_type", "start_date", "end_date", "created_at", "updated_at"}).
+			AddRow(assignments[0].ID, assignments[0].ChildID, assignments[0].TeacherID, assignments[0].AssignmentType, assignments[0].StartDate, assignments[0].EndDate, assignments[0].CreatedAt, assignments[0].UpdatedAt).
+			AddRow(assignments[1].ID, assignments[1].ChildID, assignments[1].TeacherID, assignments[1].AssignmentType, assignments[1].StartDate, assignments[1].EndDate, assignments[1].CreatedAt, assignments[1].UpdatedAt)
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE child_id = ? ORDER BY start_date DESC`)).
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE child_id = ? ORDER BY start_date DESC, assignment_id`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE child_id = ? ORDER BY start_date DESC, assignment_id`)).
 			WithArgs(childID).
 			WillReturnRows(rows)
 
-		fetchedAssignments, err := store.GetAssignmentHistoryForChild(childID)
+		fetchedAssignments, err := store.GetAssignmentHistoryForChild(t.Context(), childID)
 		assert.NoError(t, err)
 		assert.NotNil(t, fetchedAssignments)
 		assert.Len(t, fetchedAssignments, 2)
@@ -261,11 +263,11 @@ func TestSQLAssignmentStore_GetAssignmentHistoryForChild(t *testing.T) {
 	})
 
 	t.Run("no assignments found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE child_id = ? ORDER BY start_date DESC`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE child_id = ? ORDER BY start_date DESC, assignment_id`)).
 			WithArgs(childID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "child_id", "teacher_id", "start_date", "end_date", "created_at", "updated_at"}))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "child_id", "teacher_id", "assignment_type", "start_date", "end_date", "created_at", "updated_at"}))
 
-		fetchedAssignments, err := store.GetAssignmentHistoryForChild(childID)
+		fetchedAssignments, err := store.GetAssignmentHistoryForChild(t.Context(), childID)
 		assert.NoError(t, err)
 		assert.Nil(t, fetchedAssignments)
 		assert.Len(t, fetchedAssignments, 0)
@@ -273,11 +275,11 @@ func TestSQLAssignmentStore_GetAssignmentHistoryForChild(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE child_id = ? ORDER BY start_date DESC`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE child_id = ? ORDER BY start_date DESC, assignment_id`)).
 			WithArgs(childID).
 			WillReturnError(errors.New("db error"))
 
-		fetchedAssignments, err := store.GetAssignmentHistoryForChild(childID)
+		fetchedAssignments, err := store.GetAssignmentHistoryForChild(t.Context(), childID)
 		assert.Error(t, err)
 		assert.Nil(t, fetchedAssignments)
 		assert.Contains(t, err.Error(), "db error")
@@ -285,14 +287,14 @@ func TestSQLAssignmentStore_GetAssignmentHistoryForChild(t *testing.T) {
 	})
 
 	t.Run("scan error", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"assignment_id", "child_id", "teacher_id", "start_date", "end_date", "created_at", "updated_at"}).
-			AddRow(assignments[0].ID, assignments[0].ChildID, "not-an-int", assignments[0].StartDate, assignments[0].EndDate, assignments[0].CreatedAt, assignments[0].UpdatedAt) // Malformed row
+		rows := sqlmock.NewRows([]string{"assignment_id", "child_id", "teacher_id", "assignment_type", "start_date", "end_date", "created_at", "updated_at"}).
+			AddRow(assignments[0].ID, assignments[0].ChildID, "not-an-int", assignments[0].AssignmentType, assignments[0].StartDate, assignments[0].EndDate, assignments[0].CreatedAt, assignments[0].UpdatedAt) // Malformed row
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE child_id = ? ORDER BY start_date DESC`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE child_id = ? ORDER BY start_date DESC, assignment_id`)).
 			WithArgs(childID).
 			WillReturnRows(rows)
 
-		fetchedAssignments, err := store.GetAssignmentHistoryForChild(childID)
+		fetchedAssignments, err := store.GetAssignmentHistoryForChild(t.Context(), childID)
 		assert.Error(t, err)
 		assert.Nil(t, fetchedAssignments)
 		assert.Contains(t, err.Error(), "converting driver.Value type string")
@@ -316,7 +318,7 @@ func TestSQLAssignmentStore_EndAssignment(t *testing.T) {
 			WithArgs(assignmentID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.EndAssignment(assignmentID)
+		err := store.EndAssignment(t.Context(), assignmentID)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -326,7 +328,7 @@ func TestSQLAssignmentStore_EndAssignment(t *testing.T) {
 			WithArgs(assignmentID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := store.EndAssignment(assignmentID)
+		err := store.EndAssignment(t.Context(), assignmentID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -337,7 +339,7 @@ func TestSQLAssignmentStore_EndAssignment(t *testing.T) {
 			WithArgs(assignmentID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.EndAssignment(assignmentID)
+		err := store.EndAssignment(t.Context(), assignmentID)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -360,14 +362,15 @@ func TestSQLAssignmentStore_GetAllAssignments(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"assignment_id", "child_id", "teacher_id", "start_date", "end_date", "created_at", "updated_at"}).
-			AddRow(assignments[0].ID, assignments[0].ChildID, assignments[0].TeacherID, assignments[0].StartDate, assignments[0].EndDate, assignments[0].CreatedAt, assignments[0].UpdatedAt).
-			AddRow(assignments[1].ID, assignments[1].ChildID, assignments[1].TeacherID, assignments[1].StartDate, assignments[1].EndDate, assignments[1].CreatedAt, assignments[1].UpdatedAt)
+		rows := sqlmock.NewRows([]string{"assignment_id", "child_id", "teacher_id", "assignment_type", "start_date", "end_date", "created_at", "updated_at"}).
+			AddRow(assignments[0].ID, assignments[0].ChildID, assignments[0].TeacherID, assignments[0].AssignmentType, assignments[0].StartDate, assignments[0].EndDate, assignments[0].CreatedAt, assignments[0].UpdatedAt).
+			AddRow(assignments[1].ID, assignments[1].ChildID, assignments[1].TeacherID, assignments[1].AssignmentType, assignments[1].StartDate, assignments[1].EndDate, assignments[1].CreatedAt, assignments[1].UpdatedAt)
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments ORDER BY start_date DESC`)).
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments ORDER BY start_date DESC, assignment_id`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments ORDER BY start_date DESC, assignment_id`)).
 			WillReturnRows(rows)
 
-		fetchedAssignments, err := store.GetAllAssignments()
+		fetchedAssignments, err := store.GetAllAssignments(t.Context())
 		assert.NoError(t, err)
 		assert.NotNil(t, fetchedAssignments)
 		assert.Len(t, fetchedAssignments, 2)
@@ -377,13 +380,65 @@ func TestSQLAssignmentStore_GetAllAssignments(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, start_date, end_date, created_at, updated_at FROM child_teacher_assignments ORDER BY start_date DESC`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments ORDER BY start_date DESC, assignment_id`)).
 			WillReturnError(errors.New("db error"))
 
-		fetchedAssignments, err := store.GetAllAssignments()
+		fetchedAssignments, err := store.GetAllAssignments(t.Context())
 		assert.Error(t, err)
 		assert.Nil(t, fetchedAssignments)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestSQLAssignmentStore_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLAssignmentStore(db)
+	columns := []string{"assignment_id", "child_id", "teacher_id", "assignment_type", "start_date", "end_date", "created_at", "updated_at"}
+
+	t.Run("filters, sorting and pagination", func(t *testing.T) {
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE teacher_id = ? AND start_date >= ? AND end_date IS NULL ORDER BY end_date DESC, assignment_id LIMIT ? OFFSET ?`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments WHERE teacher_id = ? AND start_date >= ? AND end_date IS NULL ORDER BY end_date DESC, assignment_id LIMIT ? OFFSET ?`)).
+			WithArgs(3, from, 10, 20).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		query := models.ListQuery{Limit: 10, Offset: 20}.
+			Where("teacher_id", models.OperatorEqual, 3).
+			Where("start_date", models.OperatorGreaterOrEqual, from).
+			Where("end_date", models.OperatorIsNull, nil).
+			OrderBy("end_date", true)
+		fetchedAssignments, err := store.List(t.Context(), query)
+		assert.NoError(t, err)
+		assert.Empty(t, fetchedAssignments)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("offset without limit", func(t *testing.T) {
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments ORDER BY assignment_id LIMIT ? OFFSET ?`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT assignment_id, child_id, teacher_id, assignment_type, start_date, end_date, created_at, updated_at FROM child_teacher_assignments ORDER BY assignment_id LIMIT ? OFFSET ?`)).
+			WithArgs(int64(math.MaxInt64), 5).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := store.List(t.Context(), models.ListQuery{Offset: 5}.OrderBy("id", false))
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := store.List(t.Context(), models.ListQuery{}.Where("1=1; DROP TABLE child_teacher_assignments; --", models.OperatorEqual, 1))
+		assert.ErrorIs(t, err, data.ErrInvalidInput)
+
+		_, err = store.List(t.Context(), models.ListQuery{}.OrderBy("observation_date", false))
+		assert.ErrorIs(t, err, data.ErrInvalidInput)
+
+		_, err = store.List(t.Context(), models.ListQuery{}.Where("teacher_id", "like", "%"))
+		assert.ErrorIs(t, err, data.ErrInvalidInput)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,151 @@
+package data
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// Gallery photos and child documents store their content in attachment_blobs, once per distinct file: a photo of
+// the whole group tagged for 20 children, or the same vaccination record uploaded twice, is stored once. The rows
+// reference a blob by its content hash and the triggers of migration 000049 count the references, a blob is
+// deleted with its last reference.
+
+// attachmentHash returns the content hash a blob is stored under. It is keyed with the encryption key like
+// LookupHash, so that the hash does not tell whether a known file is stored.
+func attachmentHash(content []byte, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content) //nolint:errcheck
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// putAttachmentBlob stores the content as a blob unless it is stored already and returns its content hash. The
+// reference is counted when a row with the hash is inserted.
+func putAttachmentBlob(ctx context.Context, tx *sql.Tx, content []byte, key []byte) (string, error) {
+	hash := attachmentHash(content, key)
+	var exists int
+	err := tx.QueryRowContext(ctx, `SELECT 1 FROM attachment_blobs WHERE content_hash = ?`, hash).Scan(&exists)
+	if err == nil {
+		return hash, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	encrypted, err := Encrypt(string(content), key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt attachment: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO attachment_blobs (content_hash, content, size_bytes) VALUES (?, ?, ?)`, hash, encrypted, len(content)); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// attachmentContent returns the decrypted content of a row of a table with attachments. Rows written before the
+// blobs were introduced keep their content until the data migration moves it.
+func attachmentContent(ctx context.Context, db *sql.DB, table string, idColumn string, id int, key []byte) ([]byte, error) {
+	query := fmt.Sprintf(`SELECT COALESCE(b.content, t.content) FROM %s t
+		LEFT JOIN attachment_blobs b ON b.content_hash = t.content_hash WHERE t.%s = ?`, table, idColumn)
+	var content string
+	if err := db.QueryRowContext(ctx, query, id).Scan(&content); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	decrypted, err := Decrypt(content, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s %d: %w", table, id, err)
+	}
+	return []byte(decrypted), nil
+}
+
+// deduplicateAttachments moves the content of the gallery photos and child documents written before the blobs
+// were introduced into blobs.
+func deduplicateAttachments(tx *sql.Tx, encryptionKey []byte) (int, error) {
+	changed := 0
+	for _, table := range []struct{ name, idColumn string }{
+		{"gallery_photos", "photo_id"},
+		{"child_documents", "document_id"},
+	} {
+		tableChanged, err := deduplicateTableAttachments(tx, table.name, table.idColumn, encryptionKey)
+		changed += tableChanged
+		if err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+func deduplicateTableAttachments(tx *sql.Tx, table string, idColumn string, encryptionKey []byte) (int, error) {
+	rows, err := tx.Query(fmt.Sprintf(`SELECT %s, content FROM %s WHERE content_hash IS NULL ORDER BY %s`, idColumn, table, idColumn))
+	if err != nil {
+		return 0, err
+	}
+	type legacyRow struct {
+		id      int
+		content string
+	}
+	legacy := []legacyRow{}
+	for rows.Next() {
+		var row legacyRow
+		if err := rows.Scan(&row.id, &row.content); err != nil {
+			rows.Close() //nolint:errcheck
+			return 0, err
+		}
+		legacy = append(legacy, row)
+	}
+	rows.Close() //nolint:errcheck
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for i, row := range legacy {
+		content, err := Decrypt(row.content, encryptionKey)
+		if err != nil {
+			return i, fmt.Errorf("failed to decrypt %s %d: %w", table, row.id, err)
+		}
+		hash, err := putAttachmentBlob(context.Background(), tx, []byte(content), encryptionKey)
+		if err != nil {
+			return i, err
+		}
+		// The update trigger counts the reference.
+		query := fmt.Sprintf(`UPDATE %s SET content_hash = ?, content = '' WHERE %s = ?`, table, idColumn)
+		if _, err := tx.Exec(query, hash, row.id); err != nil {
+			return i, err
+		}
+	}
+	return len(legacy), nil
+}
+
+// encodeEnvelope returns the JSON an envelope is stored as, nil for plain attachments.
+func encodeEnvelope(envelope *models.AttachmentEnvelope) (*string, error) {
+	if envelope == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode attachment envelope: %w", err)
+	}
+	value := string(encoded)
+	return &value, nil
+}
+
+// decodeEnvelope decodes a stored envelope, nil for plain attachments.
+func decodeEnvelope(value sql.NullString) (*models.AttachmentEnvelope, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	envelope := &models.AttachmentEnvelope{}
+	if err := json.Unmarshal([]byte(value.String), envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode attachment envelope: %w", err)
+	}
+	return envelope, nil
+}
@@ -0,0 +1,120 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentBlobs(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, key)
+
+	groupID, err := dal.Groups.Create(t.Context(), &models.Group{Name: "Sonnengruppe", Capacity: 20})
+	require.NoError(t, err)
+	anna, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	require.NoError(t, err)
+
+	blobs := func() map[int]int {
+		rows, err := db.Query(`SELECT size_bytes, ref_count FROM attachment_blobs`)
+		require.NoError(t, err)
+		defer rows.Close() //nolint:errcheck
+		refCounts := map[int]int{}
+		for rows.Next() {
+			var size, refCount int
+			require.NoError(t, rows.Scan(&size, &refCount))
+			refCounts[size] = refCount
+		}
+		require.NoError(t, rows.Err())
+		return refCounts
+	}
+
+	t.Run("identical uploads are stored once", func(t *testing.T) {
+		photo := []byte("\x89PNG\r\n\x1a\ngroup photo")
+		var photoIDs []int
+		for i := 0; i < 3; i++ {
+			id, err := dal.Gallery.Create(t.Context(), &models.GalleryPhoto{GroupID: groupID, ContentType: "image/png", Content: photo})
+			require.NoError(t, err)
+			photoIDs = append(photoIDs, id)
+		}
+		document := []byte("%PDF-1.4 Impfpass")
+		documentID, err := dal.ChildDocuments.Create(t.Context(), &models.ChildDocument{ChildID: anna, DocumentType: models.ChildDocumentTypeOther,
+			FileName: "Impfpass.pdf", Content: document})
+		require.NoError(t, err)
+		assert.Equal(t, map[int]int{len(photo): 3, len(document): 1}, blobs())
+
+		var storedContent string
+		require.NoError(t, db.QueryRow(`SELECT content FROM attachment_blobs WHERE size_bytes = ?`, len(photo)).Scan(&storedContent))
+		assert.NotContains(t, storedContent, "group photo", "the content must be stored encrypted")
+
+		stored, err := dal.Gallery.GetWithContent(t.Context(), photoIDs[2])
+		require.NoError(t, err)
+		assert.Equal(t, photo, stored.Content)
+
+		require.NoError(t, dal.Gallery.Delete(t.Context(), photoIDs[0]))
+		require.NoError(t, dal.Gallery.Delete(t.Context(), photoIDs[1]))
+		assert.Equal(t, map[int]int{len(photo): 1, len(document): 1}, blobs())
+		stored, err = dal.Gallery.GetWithContent(t.Context(), photoIDs[2])
+		require.NoError(t, err)
+		assert.Equal(t, photo, stored.Content)
+
+		require.NoError(t, dal.Gallery.Delete(t.Context(), photoIDs[2]))
+		require.NoError(t, dal.ChildDocuments.Delete(t.Context(), documentID))
+		assert.Empty(t, blobs(), "a blob is deleted with its last reference")
+	})
+
+	t.Run("cascading deletes release blobs", func(t *testing.T) {
+		child, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Ben", LastName: "Schulz", Birthdate: models.NewDate(2021, time.June, 2)})
+		require.NoError(t, err)
+		_, err = dal.ChildDocuments.Create(t.Context(), &models.ChildDocument{ChildID: child, DocumentType: models.ChildDocumentTypeOther,
+			FileName: "Attest.pdf", Content: []byte("%PDF-1.4 Attest")})
+		require.NoError(t, err)
+		require.Len(t, blobs(), 1)
+
+		_, err = db.Exec(`DELETE FROM children WHERE child_id = ?`, child)
+		require.NoError(t, err)
+		assert.Empty(t, blobs())
+	})
+
+	t.Run("data migration moves legacy content into blobs", func(t *testing.T) {
+		legacy := []byte("\x89PNG\r\n\x1a\nlegacy photo")
+		encrypted, err := data.Encrypt(string(legacy), key)
+		require.NoError(t, err)
+		var photoIDs []int
+		for i := 0; i < 2; i++ {
+			result, err := db.Exec(`INSERT INTO gallery_photos (group_id, content, content_type) VALUES (?, ?, 'image/png')`, groupID, encrypted)
+			require.NoError(t, err)
+			id, err := result.LastInsertId()
+			require.NoError(t, err)
+			photoIDs = append(photoIDs, int(id))
+		}
+		stored, err := dal.Gallery.GetWithContent(t.Context(), photoIDs[0])
+		require.NoError(t, err)
+		assert.Equal(t, legacy, stored.Content, "legacy content is read until it is migrated")
+
+		results, err := data.RunDataMigrations(db, data.DataMigrations, key)
+		require.NoError(t, err)
+		require.NotEmpty(t, results)
+		assert.Equal(t, "0003_deduplicate_attachments", results[len(results)-1].Name)
+		assert.Equal(t, 2, results[len(results)-1].RowsChanged)
+		assert.Equal(t, map[int]int{len(legacy): 2}, blobs())
+
+		var content string
+		require.NoError(t, db.QueryRow(`SELECT content FROM gallery_photos WHERE photo_id = ?`, photoIDs[1]).Scan(&content))
+		assert.Empty(t, content)
+		stored, err = dal.Gallery.GetWithContent(t.Context(), photoIDs[1])
+		require.NoError(t, err)
+		assert.Equal(t, legacy, stored.Content)
+	})
+}
@@ -0,0 +1,198 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// AttendanceStore defines the interface for AttendanceRecord and check-in code data operations.
+type AttendanceStore interface {
+	Create(ctx context.Context, record *models.AttendanceRecord) (int, error)
+	GetByID(ctx context.Context, id int) (*models.AttendanceRecord, error)
+	Delete(ctx context.Context, id int) error
+	// GetForChild fetches the records of a child between two days, both included, in the order they were recorded.
+	GetForChild(ctx context.Context, childID int, from models.Date, to models.Date) ([]models.AttendanceRecord, error)
+	// GetForDay fetches the records of all children on a day in the order they were recorded.
+	GetForDay(ctx context.Context, day models.Date) ([]models.AttendanceRecord, error)
+	// GetLatestForChild fetches the last record of a child on a day.
+	GetLatestForChild(ctx context.Context, childID int, day models.Date) (*models.AttendanceRecord, error)
+	// SetCheckInCode creates or replaces the check-in code of a child.
+	SetCheckInCode(ctx context.Context, childID int, codeHash string, code string) error
+	GetCheckInCode(ctx context.Context, childID int) (string, error)
+	// GetChildIDByCheckInCode fetches the child a check-in code with the given hash belongs to.
+	GetChildIDByCheckInCode(ctx context.Context, codeHash string) (int, error)
+	DeleteCheckInCode(ctx context.Context, childID int) error
+}
+
+// SQLAttendanceStore implements AttendanceStore using database/sql.
+type SQLAttendanceStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLAttendanceStore creates a new SQLAttendanceStore.
+func NewSQLAttendanceStore(db *sql.DB, encryptionKey []byte) *SQLAttendanceStore {
+	return &SQLAttendanceStore{db: db, encryptionKey: encryptionKey}
+}
+
+const attendanceRecordColumns = `record_id, child_id, event_type, recorded_at, attendance_date, source, device_name, recorded_by_user_id, created_at`
+
+// Create inserts a new attendance record into the database. Times are stored in UTC, so that they sort.
+func (s *SQLAttendanceStore) Create(ctx context.Context, record *models.AttendanceRecord) (int, error) {
+	query := `INSERT INTO attendance_records (child_id, event_type, recorded_at, attendance_date, source, device_name, recorded_by_user_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING record_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, record.ChildID, record.EventType, record.RecordedAt.UTC(), record.AttendanceDate, record.Source,
+		record.DeviceName, record.RecordedByUserID).Scan(&id); err != nil {
+		return 0, attendanceConstraintError(err)
+	}
+	return id, nil
+}
+
+// GetByID fetches an attendance record by ID from the database.
+func (s *SQLAttendanceStore) GetByID(ctx context.Context, id int) (*models.AttendanceRecord, error) {
+	return s.queryRecord(ctx, `SELECT `+attendanceRecordColumns+` FROM attendance_records WHERE record_id = ?`, id)
+}
+
+// Delete deletes an attendance record by ID from the database.
+func (s *SQLAttendanceStore) Delete(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM attendance_records WHERE record_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetForChild fetches the attendance records of a child between two days from the database.
+func (s *SQLAttendanceStore) GetForChild(ctx context.Context, childID int, from models.Date, to models.Date) ([]models.AttendanceRecord, error) {
+	query := `SELECT ` + attendanceRecordColumns + ` FROM attendance_records
+		WHERE child_id = ? AND attendance_date BETWEEN ? AND ? ORDER BY recorded_at, record_id`
+	return s.queryRecords(ctx, query, childID, from, to)
+}
+
+// GetForDay fetches the attendance records of a day from the database.
+func (s *SQLAttendanceStore) GetForDay(ctx context.Context, day models.Date) ([]models.AttendanceRecord, error) {
+	query := `SELECT ` + attendanceRecordColumns + ` FROM attendance_records WHERE attendance_date = ? ORDER BY recorded_at, record_id`
+	return s.queryRecords(ctx, query, day)
+}
+
+// GetLatestForChild fetches the last attendance record of a child on a day from the database.
+func (s *SQLAttendanceStore) GetLatestForChild(ctx context.Context, childID int, day models.Date) (*models.AttendanceRecord, error) {
+	query := `SELECT ` + attendanceRecordColumns + ` FROM attendance_records
+		WHERE child_id = ? AND attendance_date = ? ORDER BY recorded_at DESC, record_id DESC LIMIT 1`
+	return s.queryRecord(ctx, query, childID, day)
+}
+
+// SetCheckInCode inserts the check-in code of a child into the database, replacing an existing code.
+func (s *SQLAttendanceStore) SetCheckInCode(ctx context.Context, childID int, codeHash string, code string) error {
+	encrypted, err := Encrypt(code, s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt check-in code: %w", err)
+	}
+	query := `INSERT INTO child_checkin_codes (child_id, code_hash, code) VALUES (?, ?, ?)
+		ON CONFLICT (child_id) DO UPDATE SET code_hash = excluded.code_hash, code = excluded.code, created_at = CURRENT_TIMESTAMP`
+	if _, err := s.db.ExecContext(ctx, query, childID, codeHash, encrypted); err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrConflict
+		}
+		return attendanceConstraintError(err)
+	}
+	return nil
+}
+
+// GetCheckInCode fetches the check-in code of a child from the database.
+func (s *SQLAttendanceStore) GetCheckInCode(ctx context.Context, childID int) (string, error) {
+	var encrypted string
+	if err := s.db.QueryRowContext(ctx, `SELECT code FROM child_checkin_codes WHERE child_id = ?`, childID).Scan(&encrypted); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	code, err := Decrypt(encrypted, s.encryptionKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt check-in code: %w", err)
+	}
+	return code, nil
+}
+
+// GetChildIDByCheckInCode fetches the child of a check-in code from the database.
+func (s *SQLAttendanceStore) GetChildIDByCheckInCode(ctx context.Context, codeHash string) (int, error) {
+	var childID int
+	if err := s.db.QueryRowContext(ctx, `SELECT child_id FROM child_checkin_codes WHERE code_hash = ?`, codeHash).Scan(&childID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	return childID, nil
+}
+
+// DeleteCheckInCode deletes the check-in code of a child from the database.
+func (s *SQLAttendanceStore) DeleteCheckInCode(ctx context.Context, childID int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM child_checkin_codes WHERE child_id = ?`, childID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLAttendanceStore) queryRecord(ctx context.Context, query string, args ...any) (*models.AttendanceRecord, error) {
+	records, err := s.queryRecords(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrNotFound
+	}
+	return &records[0], nil
+}
+
+func (s *SQLAttendanceStore) queryRecords(ctx context.Context, query string, args ...any) ([]models.AttendanceRecord, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	records := []models.AttendanceRecord{}
+	for rows.Next() {
+		var record models.AttendanceRecord
+		if err := rows.Scan(&record.ID, &record.ChildID, &record.EventType, &record.RecordedAt, &record.AttendanceDate, &record.Source,
+			&record.DeviceName, &record.RecordedByUserID, &record.CreatedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func attendanceConstraintError(err error) error {
+	if isForeignKeyError(err) {
+		return ErrForeignKeyConstraint
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLAttendanceStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	require.NoError(t, err)
+
+	store := dal.Attendance
+	day := models.NewDate(2024, time.October, 15)
+	berlin := time.FixedZone("", 2*60*60)
+	device := "Tablet Eingang"
+	record := func(eventType string, at time.Time) *models.AttendanceRecord {
+		return &models.AttendanceRecord{ChildID: childID, EventType: eventType, RecordedAt: at, AttendanceDate: models.DateOf(at),
+			Source: models.AttendanceSourceQRScan, DeviceName: &device}
+	}
+	arrivalID, err := store.Create(t.Context(), record(models.AttendanceArrival, time.Date(2024, time.October, 15, 8, 5, 0, 0, berlin)))
+	require.NoError(t, err)
+	departureID, err := store.Create(t.Context(), record(models.AttendanceDeparture, time.Date(2024, time.October, 15, 15, 30, 0, 0, berlin)))
+	require.NoError(t, err)
+	_, err = store.Create(t.Context(), record(models.AttendanceArrival, time.Date(2024, time.October, 16, 7, 55, 0, 0, berlin)))
+	require.NoError(t, err)
+	_, err = store.Create(t.Context(), &models.AttendanceRecord{ChildID: 999, EventType: models.AttendanceArrival, RecordedAt: time.Now(), Source: models.AttendanceSourceManual})
+	assert.ErrorIs(t, err, data.ErrForeignKeyConstraint)
+
+	arrival, err := store.GetByID(t.Context(), arrivalID)
+	require.NoError(t, err)
+	assert.True(t, arrival.RecordedAt.Equal(time.Date(2024, time.October, 15, 8, 5, 0, 0, berlin)))
+	assert.Equal(t, day, arrival.AttendanceDate)
+	assert.Equal(t, device, *arrival.DeviceName)
+
+	records, err := store.GetForDay(t.Context(), day)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []int{arrivalID, departureID}, []int{records[0].ID, records[1].ID})
+	records, err = store.GetForChild(t.Context(), childID, day, day.AddDays(1))
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+	latest, err := store.GetLatestForChild(t.Context(), childID, day)
+	require.NoError(t, err)
+	assert.Equal(t, departureID, latest.ID)
+	_, err = store.GetLatestForChild(t.Context(), childID, day.AddDays(2))
+	assert.ErrorIs(t, err, data.ErrNotFound)
+
+	require.NoError(t, store.Delete(t.Context(), departureID))
+	assert.ErrorIs(t, store.Delete(t.Context(), departureID), data.ErrNotFound)
+
+	// Check-in codes
+	require.NoError(t, store.SetCheckInCode(t.Context(), childID, "hash-1", "code-1"))
+	require.NoError(t, store.SetCheckInCode(t.Context(), childID, "hash-2", "code-2"))
+	assert.ErrorIs(t, store.SetCheckInCode(t.Context(), 999, "hash-3", "code-3"), data.ErrForeignKeyConstraint)
+	code, err := store.GetCheckInCode(t.Context(), childID)
+	require.NoError(t, err)
+	assert.Equal(t, "code-2", code)
+	var storedCode string
+	require.NoError(t, db.QueryRow(`SELECT code FROM child_checkin_codes WHERE child_id = ?`, childID).Scan(&storedCode))
+	assert.NotEqual(t, "code-2", storedCode, "the code must be stored encrypted")
+	_, err = store.GetChildIDByCheckInCode(t.Context(), "hash-1")
+	assert.ErrorIs(t, err, data.ErrNotFound, "the replaced code is no longer valid")
+	found, err := store.GetChildIDByCheckInCode(t.Context(), "hash-2")
+	require.NoError(t, err)
+	assert.Equal(t, childID, found)
+	require.NoError(t, store.DeleteCheckInCode(t.Context(), childID))
+	assert.ErrorIs(t, store.DeleteCheckInCode(t.Context(), childID), data.ErrNotFound)
+}
@@ -0,0 +1,91 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// AuditLogStore defines the interface for AuditLogEntry data operations.
+type AuditLogStore interface {
+	Create(ctx context.Context, entry *models.AuditLogEntry) (int, error)
+	GetForEntity(ctx context.Context, entityType string, entityID int) ([]models.AuditLogEntry, error)
+	GetForActor(ctx context.Context, actorUserID int) ([]models.AuditLogEntry, error)
+	GetForAction(ctx context.Context, action string, since time.Time) ([]models.AuditLogEntry, error)
+	GetAll(ctx context.Context) ([]models.AuditLogEntry, error)
+}
+
+// SQLAuditLogStore implements AuditLogStore using database/sql.
+type SQLAuditLogStore struct {
+	db *sql.DB
+}
+
+// NewSQLAuditLogStore creates a new SQLAuditLogStore.
+func NewSQLAuditLogStore(db *sql.DB) *SQLAuditLogStore {
+	return &SQLAuditLogStore{db: db}
+}
+
+// Create appends an entry to the audit log.
+func (s *SQLAuditLogStore) Create(ctx context.Context, entry *models.AuditLogEntry) (int, error) {
+	query := `INSERT INTO audit_log (action, entity_type, entity_id, actor_user_id, on_behalf_of_user_id, delegation_id, details) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING audit_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, entry.Action, entry.EntityType, entry.EntityID, entry.ActorUserID, entry.OnBehalfOfUserID, entry.DelegationID, entry.Details).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+const (
+	auditTrailQuery     = `SELECT audit_id, action, entity_type, entity_id, actor_user_id, on_behalf_of_user_id, delegation_id, details, created_at FROM audit_log WHERE entity_type = ? AND entity_id = ? ORDER BY created_at ASC, audit_id ASC`
+	actorAuditLogQuery  = `SELECT audit_id, action, entity_type, entity_id, actor_user_id, on_behalf_of_user_id, delegation_id, details, created_at FROM audit_log WHERE actor_user_id = ? ORDER BY created_at DESC, audit_id DESC`
+	actionAuditLogQuery = `SELECT audit_id, action, entity_type, entity_id, actor_user_id, on_behalf_of_user_id, delegation_id, details, created_at FROM audit_log WHERE action = ? AND created_at >= ? ORDER BY created_at ASC, audit_id ASC`
+)
+
+// GetForEntity fetches the audit trail of a single entity, oldest first.
+func (s *SQLAuditLogStore) GetForEntity(ctx context.Context, entityType string, entityID int) ([]models.AuditLogEntry, error) {
+	return s.queryEntries(ctx, auditTrailQuery, entityType, entityID)
+}
+
+// GetForActor fetches what a user did, newest first.
+func (s *SQLAuditLogStore) GetForActor(ctx context.Context, actorUserID int) ([]models.AuditLogEntry, error) {
+	return s.queryEntries(ctx, actorAuditLogQuery, actorUserID)
+}
+
+// GetForAction fetches the entries of an action since the given time, oldest first.
+func (s *SQLAuditLogStore) GetForAction(ctx context.Context, action string, since time.Time) ([]models.AuditLogEntry, error) {
+	return s.queryEntries(ctx, actionAuditLogQuery, action, since.UTC())
+}
+
+// GetAll fetches the complete audit log, newest first.
+func (s *SQLAuditLogStore) GetAll(ctx context.Context) ([]models.AuditLogEntry, error) {
+	query := `SELECT audit_id, action, entity_type, entity_id, actor_user_id, on_behalf_of_user_id, delegation_id, details, created_at FROM audit_log ORDER BY created_at DESC, audit_id DESC`
+	return s.queryEntries(ctx, query)
+}
+
+func (s *SQLAuditLogStore) queryEntries(ctx context.Context, query string, args ...any) ([]models.AuditLogEntry, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var entries []models.AuditLogEntry
+	for rows.Next() {
+		entry := models.AuditLogEntry{}
+		var details sql.NullString
+		err := rows.Scan(&entry.ID, &entry.Action, &entry.EntityType, &entry.EntityID, &entry.ActorUserID, &entry.OnBehalfOfUserID, &entry.DelegationID, &details, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entry.Details = details.String
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,137 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// AuthSessionStore defines the interface for AuthSession data operations.
+type AuthSessionStore interface {
+	Create(ctx context.Context, session *models.AuthSession) (int, error)
+	GetByID(ctx context.Context, id int) (*models.AuthSession, error)
+	GetByRefreshTokenHash(ctx context.Context, refreshTokenHash string) (*models.AuthSession, error)
+	// Rotate replaces the refresh token of an active session. It returns ErrConflict if the session was revoked or
+	// its token was replaced in the meantime, e.g. by a concurrent refresh with the same token.
+	Rotate(ctx context.Context, id int, oldRefreshTokenHash, newRefreshTokenHash string, refreshedAt time.Time) error
+	// Revoke marks a session as revoked, it returns ErrNotFound if the session does not exist or is revoked already.
+	Revoke(ctx context.Context, id int, revokedAt time.Time) error
+	// RevokeAllForUser revokes the active sessions of a user and returns how many were revoked.
+	RevokeAllForUser(ctx context.Context, userID int, revokedAt time.Time) (int, error)
+	// DeleteExpired deletes the sessions that expired before now.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// SQLAuthSessionStore implements AuthSessionStore using database/sql.
+type SQLAuthSessionStore struct {
+	db *sql.DB
+}
+
+// NewSQLAuthSessionStore creates a new SQLAuthSessionStore.
+func NewSQLAuthSessionStore(db *sql.DB) *SQLAuthSessionStore {
+	return &SQLAuthSessionStore{db: db}
+}
+
+const authSessionColumns = `session_id, user_id, refresh_token_hash, created_at, refreshed_at, expires_at, revoked_at`
+
+// Create inserts a new session into the database.
+func (s *SQLAuthSessionStore) Create(ctx context.Context, session *models.AuthSession) (int, error) {
+	query := `INSERT INTO auth_sessions (user_id, refresh_token_hash, created_at, expires_at) VALUES (?, ?, ?, ?) RETURNING session_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, session.UserID, session.RefreshTokenHash, session.CreatedAt, session.ExpiresAt).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a session by ID from the database.
+func (s *SQLAuthSessionStore) GetByID(ctx context.Context, id int) (*models.AuthSession, error) {
+	return s.queryAuthSession(ctx, `SELECT `+authSessionColumns+` FROM auth_sessions WHERE session_id = ?`, id)
+}
+
+// GetByRefreshTokenHash fetches the session with the hash of a refresh token from the database.
+func (s *SQLAuthSessionStore) GetByRefreshTokenHash(ctx context.Context, refreshTokenHash string) (*models.AuthSession, error) {
+	return s.queryAuthSession(ctx, `SELECT `+authSessionColumns+` FROM auth_sessions WHERE refresh_token_hash = ?`, refreshTokenHash)
+}
+
+// Rotate replaces the refresh token of a session.
+func (s *SQLAuthSessionStore) Rotate(ctx context.Context, id int, oldRefreshTokenHash, newRefreshTokenHash string, refreshedAt time.Time) error {
+	query := `UPDATE auth_sessions SET refresh_token_hash = ?, refreshed_at = ? WHERE session_id = ? AND refresh_token_hash = ? AND revoked_at IS NULL`
+	result, err := s.db.ExecContext(ctx, query, newRefreshTokenHash, refreshedAt, id, oldRefreshTokenHash)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+// Revoke marks a session as revoked.
+func (s *SQLAuthSessionStore) Revoke(ctx context.Context, id int, revokedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE auth_sessions SET revoked_at = ? WHERE session_id = ? AND revoked_at IS NULL`, revokedAt, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RevokeAllForUser marks the active sessions of a user as revoked.
+func (s *SQLAuthSessionStore) RevokeAllForUser(ctx context.Context, userID int, revokedAt time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `UPDATE auth_sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`, revokedAt, userID)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rowsAffected), nil
+}
+
+// DeleteExpired deletes the expired sessions from the database.
+func (s *SQLAuthSessionStore) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM auth_sessions WHERE expires_at < ?`, now)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rowsAffected), nil
+}
+
+func (s *SQLAuthSessionStore) queryAuthSession(ctx context.Context, query string, args ...any) (*models.AuthSession, error) {
+	session := &models.AuthSession{}
+	var refreshedAt, revokedAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&session.ID, &session.UserID, &session.RefreshTokenHash, &session.CreatedAt,
+		&refreshedAt, &session.ExpiresAt, &revokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if refreshedAt.Valid {
+		session.RefreshedAt = &refreshedAt.Time
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	return session, nil
+}
@@ -0,0 +1,87 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLAuthSessionStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	userID, err := dal.Users.Create(t.Context(), &models.User{Username: "erzieherin", PasswordHash: "hash", Role: string(data.RoleTeacher)})
+	require.NoError(t, err)
+
+	store := data.NewSQLAuthSessionStore(db)
+	now := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+	create := func(t *testing.T, refreshTokenHash string, expiresAt time.Time) int {
+		id, err := store.Create(t.Context(), &models.AuthSession{UserID: userID, RefreshTokenHash: refreshTokenHash, CreatedAt: now, ExpiresAt: expiresAt})
+		require.NoError(t, err)
+		return id
+	}
+
+	t.Run("refresh tokens are rotated once", func(t *testing.T) {
+		id := create(t, "first-hash", now.Add(7*24*time.Hour))
+		require.NoError(t, store.Rotate(t.Context(), id, "first-hash", "second-hash", now.Add(time.Hour)))
+		assert.ErrorIs(t, store.Rotate(t.Context(), id, "first-hash", "third-hash", now.Add(time.Hour)), data.ErrConflict)
+
+		_, err := store.GetByRefreshTokenHash(t.Context(), "first-hash")
+		assert.ErrorIs(t, err, data.ErrNotFound)
+		session, err := store.GetByRefreshTokenHash(t.Context(), "second-hash")
+		require.NoError(t, err)
+		assert.Equal(t, id, session.ID)
+		require.NotNil(t, session.RefreshedAt)
+		assert.True(t, session.RefreshedAt.Equal(now.Add(time.Hour)))
+		assert.True(t, session.IsActive(now.Add(time.Hour)))
+	})
+
+	t.Run("revoked sessions cannot be rotated", func(t *testing.T) {
+		id := create(t, "revoked-hash", now.Add(7*24*time.Hour))
+		require.NoError(t, store.Revoke(t.Context(), id, now))
+		assert.ErrorIs(t, store.Revoke(t.Context(), id, now), data.ErrNotFound)
+		assert.ErrorIs(t, store.Rotate(t.Context(), id, "revoked-hash", "next-hash", now), data.ErrConflict)
+
+		session, err := store.GetByID(t.Context(), id)
+		require.NoError(t, err)
+		require.NotNil(t, session.RevokedAt)
+		assert.False(t, session.IsActive(now))
+	})
+
+	t.Run("all sessions of a user are revoked", func(t *testing.T) {
+		id := create(t, "other-hash", now.Add(7*24*time.Hour))
+		revoked, err := store.RevokeAllForUser(t.Context(), userID, now)
+		require.NoError(t, err)
+		assert.Equal(t, 2, revoked, "the sessions revoked before are left alone")
+
+		session, err := store.GetByID(t.Context(), id)
+		require.NoError(t, err)
+		assert.NotNil(t, session.RevokedAt)
+	})
+
+	t.Run("expired sessions are deleted", func(t *testing.T) {
+		id := create(t, "expired-hash", now.Add(-time.Minute))
+		deleted, err := store.DeleteExpired(t.Context(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+		_, err = store.GetByID(t.Context(), id)
+		assert.ErrorIs(t, err, data.ErrNotFound)
+	})
+
+	t.Run("sessions are deleted with their user", func(t *testing.T) {
+		id := create(t, "deleted-user-hash", now.Add(time.Hour))
+		require.NoError(t, dal.Users.Delete(t.Context(), userID))
+		_, err := store.GetByID(t.Context(), id)
+		assert.ErrorIs(t, err, data.ErrNotFound)
+	})
+}
@@ -0,0 +1,66 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+
+	"kitadoc-backend/models"
+)
+
+// BenchmarkStore defines the interface for the aggregates other facilities shared for the benchmarks.
+type BenchmarkStore interface {
+	// Upsert stores the aggregate of a facility in a month, replacing an earlier import of the same month.
+	Upsert(ctx context.Context, aggregate *models.BenchmarkAggregate) error
+	// GetForMonth fetches the aggregates of a month, ordered by facility.
+	GetForMonth(ctx context.Context, month string) ([]models.BenchmarkAggregate, error)
+}
+
+// SQLBenchmarkStore implements BenchmarkStore using database/sql.
+type SQLBenchmarkStore struct {
+	db *sql.DB
+}
+
+// NewSQLBenchmarkStore creates a new SQLBenchmarkStore.
+func NewSQLBenchmarkStore(db *sql.DB) *SQLBenchmarkStore {
+	return &SQLBenchmarkStore{db: db}
+}
+
+// Upsert inserts the aggregate into the database, replacing the aggregate of the same facility and month.
+func (s *SQLBenchmarkStore) Upsert(ctx context.Context, aggregate *models.BenchmarkAggregate) error {
+	query := `INSERT INTO benchmark_aggregates (facility_pseudonym, month, children_band, coverage, median_approval_hours, imported_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (facility_pseudonym, month) DO UPDATE SET children_band = excluded.children_band,
+			coverage = excluded.coverage, median_approval_hours = excluded.median_approval_hours, imported_at = excluded.imported_at`
+	_, err := s.db.ExecContext(ctx, query, aggregate.Facility, aggregate.Month, aggregate.ChildrenBand, aggregate.Coverage,
+		aggregate.MedianApprovalHours, aggregate.ImportedAt.UTC())
+	return err
+}
+
+// GetForMonth fetches the aggregates of a month.
+func (s *SQLBenchmarkStore) GetForMonth(ctx context.Context, month string) ([]models.BenchmarkAggregate, error) {
+	query := `SELECT facility_pseudonym, month, children_band, coverage, median_approval_hours, imported_at
+		FROM benchmark_aggregates WHERE month = ? ORDER BY facility_pseudonym`
+	rows, err := s.db.QueryContext(ctx, query, month)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	aggregates := []models.BenchmarkAggregate{}
+	for rows.Next() {
+		var aggregate models.BenchmarkAggregate
+		var coverage, approvalHours sql.NullFloat64
+		if err := rows.Scan(&aggregate.Facility, &aggregate.Month, &aggregate.ChildrenBand, &coverage, &approvalHours,
+			&aggregate.ImportedAt); err != nil {
+			return nil, err
+		}
+		if coverage.Valid {
+			aggregate.Coverage = &coverage.Float64
+		}
+		if approvalHours.Valid {
+			aggregate.MedianApprovalHours = &approvalHours.Float64
+		}
+		aggregates = append(aggregates, aggregate)
+	}
+	return aggregates, rows.Err()
+}
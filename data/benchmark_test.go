@@ -0,0 +1,50 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLBenchmarkStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	store := data.NewSQLBenchmarkStore(db)
+
+	coverage, hours := 82.5, 30.0
+	importedAt := time.Date(2024, time.August, 2, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Upsert(t.Context(), &models.BenchmarkAggregate{Facility: "E-B", Month: "2024-07", ChildrenBand: models.ChildrenBandMedium,
+		Coverage: &coverage, ImportedAt: importedAt}))
+	require.NoError(t, store.Upsert(t.Context(), &models.BenchmarkAggregate{Facility: "E-A", Month: "2024-07", ChildrenBand: models.ChildrenBandSmall,
+		ImportedAt: importedAt}))
+	require.NoError(t, store.Upsert(t.Context(), &models.BenchmarkAggregate{Facility: "E-A", Month: "2024-06", ChildrenBand: models.ChildrenBandSmall,
+		ImportedAt: importedAt}))
+
+	// A facility sending its aggregate again replaces the earlier import
+	require.NoError(t, store.Upsert(t.Context(), &models.BenchmarkAggregate{Facility: "E-B", Month: "2024-07", ChildrenBand: models.ChildrenBandLarge,
+		Coverage: &coverage, MedianApprovalHours: &hours, ImportedAt: importedAt.Add(time.Hour)}))
+
+	aggregates, err := store.GetForMonth(t.Context(), "2024-07")
+	require.NoError(t, err)
+	require.Len(t, aggregates, 2)
+	assert.Equal(t, "E-A", aggregates[0].Facility)
+	assert.Nil(t, aggregates[0].Coverage)
+	assert.Nil(t, aggregates[0].MedianApprovalHours)
+	assert.Equal(t, models.ChildrenBandLarge, aggregates[1].ChildrenBand)
+	assert.Equal(t, coverage, *aggregates[1].Coverage)
+	assert.Equal(t, hours, *aggregates[1].MedianApprovalHours)
+	assert.True(t, aggregates[1].ImportedAt.Equal(importedAt.Add(time.Hour)))
+
+	aggregates, err = store.GetForMonth(t.Context(), "2024-05")
+	require.NoError(t, err)
+	assert.Empty(t, aggregates)
+}
@@ -0,0 +1,102 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// BootstrapStore defines the interface for installing the default data of a new instance.
+type BootstrapStore interface {
+	Run(ctx context.Context, categories []models.Category, groups []models.Group, userID *int, now time.Time) (*models.BootstrapResult, error)
+}
+
+// SQLBootstrapStore implements BootstrapStore using database/sql.
+type SQLBootstrapStore struct {
+	db *sql.DB
+}
+
+// NewSQLBootstrapStore creates a new SQLBootstrapStore.
+func NewSQLBootstrapStore(db *sql.DB) *SQLBootstrapStore {
+	return &SQLBootstrapStore{db: db}
+}
+
+// Run installs the categories and groups that do not exist yet and marks the instance as bootstrapped, all in one
+// transaction, recording now as the completion time. It returns ErrConflict if the instance has already been bootstrapped.
+func (s *SQLBootstrapStore) Run(ctx context.Context, categories []models.Category, groups []models.Group, userID *int, now time.Time) (*models.BootstrapResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	result := &models.BootstrapResult{
+		CompletedAt:       now,
+		CreatedCategories: []models.Category{},
+		SkippedCategories: []string{},
+		CreatedGroups:     []models.Group{},
+		SkippedGroups:     []string{},
+	}
+	marker, err := tx.ExecContext(ctx, `INSERT INTO bootstrap_runs (bootstrap_id, completed_by_user_id, completed_at) VALUES (1, ?, ?) ON CONFLICT DO NOTHING`, userID, result.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	rowsAffected, err := marker.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, ErrConflict
+	}
+
+	for _, category := range categories {
+		var existingID int
+		err := tx.QueryRowContext(ctx, `SELECT category_id FROM categories WHERE category_name = ?`, category.Name).Scan(&existingID)
+		if err == nil {
+			result.SkippedCategories = append(result.SkippedCategories, category.Name)
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		formSchema, err := encodeFormSchema(category.FormSchema)
+		if err != nil {
+			return nil, err
+		}
+		var id int
+		if err := tx.QueryRowContext(ctx, `INSERT INTO categories (category_name, description, form_schema) VALUES (?, ?, ?) RETURNING category_id`, category.Name, category.Description, formSchema).Scan(&id); err != nil {
+			return nil, err
+		}
+		category.ID = id
+		result.CreatedCategories = append(result.CreatedCategories, category)
+	}
+
+	for _, group := range groups {
+		var existingID int
+		err := tx.QueryRowContext(ctx, `SELECT group_id FROM kita_groups WHERE group_name = ?`, group.Name).Scan(&existingID)
+		if err == nil {
+			result.SkippedGroups = append(result.SkippedGroups, group.Name)
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		var id int
+		query := `INSERT INTO kita_groups (group_name, capacity, min_age_months, max_age_months) VALUES (?, ?, ?, ?) RETURNING group_id`
+		if err := tx.QueryRowContext(ctx, query, group.Name, group.Capacity, group.MinAgeMonths, group.MaxAgeMonths).Scan(&id); err != nil {
+			return nil, err
+		}
+		group.ID = id
+		group.AssistantTeacherIDs = []int{}
+		group.ChildIDs = []int{}
+		result.CreatedGroups = append(result.CreatedGroups, group)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package data_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLBootstrapStore_Run(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLBootstrapStore(db)
+	userID := 1
+	now := time.Date(2025, time.March, 5, 9, 0, 0, 0, time.UTC)
+	categories := []models.Category{{Name: "Bewegung"}, {Name: "Medien"}}
+	groups := []models.Group{
+		{Name: "Gruppenform I", Capacity: 20, MinAgeMonths: models.IntPtr(24), MaxAgeMonths: models.IntPtr(72)},
+		{Name: "Gruppenform II", Capacity: 10, MinAgeMonths: models.IntPtr(0), MaxAgeMonths: models.IntPtr(36)},
+	}
+	markerQuery := regexp.QuoteMeta(`INSERT INTO bootstrap_runs (bootstrap_id, completed_by_user_id, completed_at) VALUES (1, ?, ?) ON CONFLICT DO NOTHING`)
+	lookupQuery := regexp.QuoteMeta(`SELECT category_id FROM categories WHERE category_name = ?`)
+	groupLookupQuery := regexp.QuoteMeta(`SELECT group_id FROM kita_groups WHERE group_name = ?`)
+
+	t.Run("installs missing categories and groups", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(markerQuery).WithArgs(&userID, now).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(lookupQuery).WithArgs("Bewegung").WillReturnRows(sqlmock.NewRows([]string{"category_id"}).AddRow(3))
+		mock.ExpectQuery(lookupQuery).WithArgs("Medien").WillReturnRows(sqlmock.NewRows([]string{"category_id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO categories (category_name, description, form_schema) VALUES (?, ?, ?) RETURNING category_id`)).
+			WithArgs("Medien", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"category_id"}).AddRow(4))
+		mock.ExpectQuery(groupLookupQuery).WithArgs("Gruppenform I").WillReturnRows(sqlmock.NewRows([]string{"group_id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO kita_groups (group_name, capacity, min_age_months, max_age_months) VALUES (?, ?, ?, ?) RETURNING group_id`)).
+			WithArgs("Gruppenform I", 20, 24, 72).
+			WillReturnRows(sqlmock.NewRows([]string{"group_id"}).AddRow(7))
+		mock.ExpectQuery(groupLookupQuery).WithArgs("Gruppenform II").WillReturnRows(sqlmock.NewRows([]string{"group_id"}).AddRow(2))
+		mock.ExpectCommit()
+
+		result, err := store.Run(t.Context(), categories, groups, &userID, now)
+		assert.NoError(t, err)
+		assert.Equal(t, now, result.CompletedAt)
+		assert.Equal(t, []string{"Bewegung"}, result.SkippedCategories)
+		if assert.Len(t, result.CreatedCategories, 1) {
+			assert.Equal(t, 4, result.CreatedCategories[0].ID)
+		}
+		assert.Equal(t, []string{"Gruppenform II"}, result.SkippedGroups)
+		if assert.Len(t, result.CreatedGroups, 1) {
+			assert.Equal(t, 7, result.CreatedGroups[0].ID)
+			assert.Equal(t, "Gruppenform I", result.CreatedGroups[0].Name)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already bootstrapped", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(markerQuery).WithArgs(&userID, now).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		result, err := store.Run(t.Context(), categories, groups, &userID, now)
+		assert.Nil(t, result)
+		assert.Equal(t, data.ErrConflict, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
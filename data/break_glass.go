@@ -0,0 +1,195 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// BreakGlassStore defines the interface for BreakGlassCredential data operations.
+type BreakGlassStore interface {
+	// Seal stores a new credential and revokes the credential sealed before, at most one is sealed at a time.
+	Seal(ctx context.Context, credential *models.BreakGlassCredential) (int, error)
+	GetByID(ctx context.Context, id int) (*models.BreakGlassCredential, error)
+	// GetSealed fetches the credential that can be activated, it returns ErrNotFound if there is none.
+	GetSealed(ctx context.Context) (*models.BreakGlassCredential, error)
+	// GetAll fetches all credentials, latest first, revoked and activated ones included.
+	GetAll(ctx context.Context) ([]models.BreakGlassCredential, error)
+	// Revoke marks a sealed or activated credential as revoked, it returns ErrNotFound if the credential does not
+	// exist or is revoked already.
+	Revoke(ctx context.Context, id int, revokedAt time.Time) error
+	// Activate records the activation of a sealed credential and enqueues the alerts in the same transaction.
+	// It returns ErrConflict if the credential is not sealed anymore, e.g. because it was activated concurrently.
+	Activate(ctx context.Context, credential *models.BreakGlassCredential, alerts []models.OutboxMessage) error
+}
+
+// SQLBreakGlassStore implements BreakGlassStore using database/sql.
+type SQLBreakGlassStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLBreakGlassStore creates a new SQLBreakGlassStore.
+func NewSQLBreakGlassStore(db *sql.DB, encryptionKey []byte) *SQLBreakGlassStore {
+	return &SQLBreakGlassStore{db: db, encryptionKey: encryptionKey}
+}
+
+const breakGlassColumns = `credential_id, code_hash, sealed_by_user_id, sealed_at, revoked_at, activated_at, activation_reason, activated_user_id, expires_at`
+
+// Seal inserts a new credential into the database.
+func (s *SQLBreakGlassStore) Seal(ctx context.Context, credential *models.BreakGlassCredential) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, `UPDATE break_glass_credentials SET revoked_at = ? WHERE revoked_at IS NULL AND activated_at IS NULL`, credential.SealedAt); err != nil {
+		return 0, err
+	}
+	query := `INSERT INTO break_glass_credentials (code_hash, sealed_by_user_id, sealed_at) VALUES (?, ?, ?) RETURNING credential_id`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, credential.CodeHash, credential.SealedByUserID, credential.SealedAt).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a credential by ID from the database.
+func (s *SQLBreakGlassStore) GetByID(ctx context.Context, id int) (*models.BreakGlassCredential, error) {
+	return s.queryCredential(ctx, `SELECT `+breakGlassColumns+` FROM break_glass_credentials WHERE credential_id = ?`, id)
+}
+
+// GetSealed fetches the sealed credential from the database.
+func (s *SQLBreakGlassStore) GetSealed(ctx context.Context) (*models.BreakGlassCredential, error) {
+	query := `SELECT ` + breakGlassColumns + ` FROM break_glass_credentials WHERE revoked_at IS NULL AND activated_at IS NULL ORDER BY sealed_at DESC, credential_id DESC LIMIT 1`
+	return s.queryCredential(ctx, query)
+}
+
+// GetAll fetches all credentials from the database.
+func (s *SQLBreakGlassStore) GetAll(ctx context.Context) ([]models.BreakGlassCredential, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+breakGlassColumns+` FROM break_glass_credentials ORDER BY sealed_at DESC, credential_id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	credentials := []models.BreakGlassCredential{}
+	for rows.Next() {
+		credential, err := s.scanCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, *credential)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+// Revoke marks a credential as revoked.
+func (s *SQLBreakGlassStore) Revoke(ctx context.Context, id int, revokedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE break_glass_credentials SET revoked_at = ? WHERE credential_id = ? AND revoked_at IS NULL`, revokedAt, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Activate marks a sealed credential as activated.
+func (s *SQLBreakGlassStore) Activate(ctx context.Context, credential *models.BreakGlassCredential, alerts []models.OutboxMessage) error {
+	reason, err := Encrypt(credential.ActivationReason, s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt break-glass activation reason: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `UPDATE break_glass_credentials SET activated_at = ?, activation_reason = ?, activated_user_id = ?, expires_at = ?
+		WHERE credential_id = ? AND revoked_at IS NULL AND activated_at IS NULL`
+	result, err := tx.ExecContext(ctx, query, credential.ActivatedAt, reason, credential.ActivatedUserID, credential.ExpiresAt, credential.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrConflict
+	}
+	if err := enqueueOutboxMessages(ctx, tx, s.encryptionKey, alerts); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLBreakGlassStore) queryCredential(ctx context.Context, query string, args ...any) (*models.BreakGlassCredential, error) {
+	credential, err := s.scanCredential(s.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return credential, nil
+}
+
+func (s *SQLBreakGlassStore) scanCredential(row rowScanner) (*models.BreakGlassCredential, error) {
+	credential := &models.BreakGlassCredential{}
+	var sealedByUserID, activatedUserID sql.NullInt64
+	var revokedAt, activatedAt, expiresAt sql.NullTime
+	var reason sql.NullString
+	err := row.Scan(&credential.ID, &credential.CodeHash, &sealedByUserID, &credential.SealedAt, &revokedAt, &activatedAt,
+		&reason, &activatedUserID, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if reason.Valid {
+		credential.ActivationReason, err = Decrypt(reason.String, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt break-glass activation reason: %w", err)
+		}
+	}
+	if sealedByUserID.Valid {
+		id := int(sealedByUserID.Int64)
+		credential.SealedByUserID = &id
+	}
+	if activatedUserID.Valid {
+		id := int(activatedUserID.Int64)
+		credential.ActivatedUserID = &id
+	}
+	if revokedAt.Valid {
+		credential.RevokedAt = &revokedAt.Time
+	}
+	if activatedAt.Valid {
+		credential.ActivatedAt = &activatedAt.Time
+	}
+	if expiresAt.Valid {
+		credential.ExpiresAt = &expiresAt.Time
+	}
+	return credential, nil
+}
@@ -0,0 +1,104 @@
+package data_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLBreakGlassStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	adminID, err := dal.Users.Create(t.Context(), &models.User{Username: "leitung", PasswordHash: "hash", Role: string(data.RoleAdmin)})
+	require.NoError(t, err)
+	emergencyID, err := dal.Users.Create(t.Context(), &models.User{Username: models.BreakGlassUsername, PasswordHash: "hash", Role: string(data.RoleAdmin)})
+	require.NoError(t, err)
+
+	store := data.NewSQLBreakGlassStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	now := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+
+	_, err = store.GetSealed(t.Context())
+	assert.ErrorIs(t, err, data.ErrNotFound)
+
+	firstID, err := store.Seal(t.Context(), &models.BreakGlassCredential{CodeHash: "first-hash", SealedByUserID: &adminID, SealedAt: now})
+	require.NoError(t, err)
+	secondID, err := store.Seal(t.Context(), &models.BreakGlassCredential{CodeHash: "second-hash", SealedByUserID: &adminID, SealedAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+
+	t.Run("sealing revokes the credential sealed before", func(t *testing.T) {
+		sealed, err := store.GetSealed(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, secondID, sealed.ID)
+		assert.Equal(t, "second-hash", sealed.CodeHash)
+
+		first, err := store.GetByID(t.Context(), firstID)
+		require.NoError(t, err)
+		require.NotNil(t, first.RevokedAt)
+		assert.True(t, first.RevokedAt.Equal(now.Add(time.Hour)))
+		assert.ErrorIs(t, store.Revoke(t.Context(), firstID, now), data.ErrNotFound)
+	})
+
+	t.Run("activation is recorded once with the alerts", func(t *testing.T) {
+		activatedAt := now.Add(2 * time.Hour)
+		expiresAt := activatedAt.Add(2 * time.Hour)
+		alert := models.OutboxMessage{Channel: models.OutboxChannelEmail, Payload: json.RawMessage(`{"to":"traeger@kita.example"}`)}
+		activation := &models.BreakGlassCredential{
+			ID:               secondID,
+			ActivatedAt:      &activatedAt,
+			ActivationReason: "Leitung hat Passwort vergessen, Zugang für die Abrechnung nötig",
+			ActivatedUserID:  &emergencyID,
+			ExpiresAt:        &expiresAt,
+		}
+		require.NoError(t, store.Activate(t.Context(), activation, []models.OutboxMessage{alert}))
+		assert.ErrorIs(t, store.Activate(t.Context(), activation, nil), data.ErrConflict)
+
+		var storedReason string
+		require.NoError(t, db.QueryRow(`SELECT activation_reason FROM break_glass_credentials WHERE credential_id = ?`, secondID).Scan(&storedReason))
+		assert.NotContains(t, storedReason, "Passwort", "the reason must be stored encrypted")
+
+		credential, err := store.GetByID(t.Context(), secondID)
+		require.NoError(t, err)
+		assert.False(t, credential.IsSealed())
+		assert.Equal(t, activation.ActivationReason, credential.ActivationReason)
+		assert.Equal(t, emergencyID, *credential.ActivatedUserID)
+		assert.True(t, credential.ExpiresAt.Equal(expiresAt))
+		_, err = store.GetSealed(t.Context())
+		assert.ErrorIs(t, err, data.ErrNotFound)
+
+		pending, err := dal.Outbox.GetByStatus(t.Context(), models.OutboxStatusPending)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.JSONEq(t, string(alert.Payload), string(pending[0].Payload))
+	})
+
+	t.Run("all credentials are listed latest first", func(t *testing.T) {
+		credentials, err := store.GetAll(t.Context())
+		require.NoError(t, err)
+		require.Len(t, credentials, 2)
+		assert.Equal(t, secondID, credentials[0].ID)
+		assert.Equal(t, firstID, credentials[1].ID)
+		assert.Empty(t, credentials[1].ActivationReason)
+	})
+
+	t.Run("activated credentials can be revoked once", func(t *testing.T) {
+		require.NoError(t, store.Revoke(t.Context(), secondID, now.Add(2*time.Hour)))
+		assert.ErrorIs(t, store.Revoke(t.Context(), secondID, now.Add(2*time.Hour)), data.ErrNotFound)
+
+		credential, err := store.GetByID(t.Context(), secondID)
+		require.NoError(t, err)
+		require.NotNil(t, credential.RevokedAt)
+		assert.NotNil(t, credential.ActivatedAt)
+	})
+}
@@ -0,0 +1,130 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+
+	"kitadoc-backend/models"
+)
+
+// CareContractStore defines the interface for CareContract data operations.
+type CareContractStore interface {
+	Create(ctx context.Context, contract *models.CareContract) (int, error)
+	GetByID(ctx context.Context, id int) (*models.CareContract, error)
+	Update(ctx context.Context, contract *models.CareContract) error
+	Delete(ctx context.Context, id int) error
+	// GetForChild fetches the contracts of a child ordered by the start of their period.
+	GetForChild(ctx context.Context, childID int) ([]models.CareContract, error)
+	// GetValidBetween fetches the contracts of all children that cover at least one day between from and to, both
+	// included, ordered by child and start of their period.
+	GetValidBetween(ctx context.Context, from models.Date, to models.Date) ([]models.CareContract, error)
+}
+
+// SQLCareContractStore implements CareContractStore using database/sql.
+type SQLCareContractStore struct {
+	db *sql.DB
+}
+
+// NewSQLCareContractStore creates a new SQLCareContractStore.
+func NewSQLCareContractStore(db *sql.DB) *SQLCareContractStore {
+	return &SQLCareContractStore{db: db}
+}
+
+const careContractColumns = `contract_id, child_id, weekly_hours, valid_from, valid_until, created_at, updated_at`
+
+// Create inserts a new care contract into the database.
+func (s *SQLCareContractStore) Create(ctx context.Context, contract *models.CareContract) (int, error) {
+	query := `INSERT INTO care_contracts (child_id, weekly_hours, valid_from, valid_until) VALUES (?, ?, ?, ?) RETURNING contract_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, contract.ChildID, contract.WeeklyHours, contract.ValidFrom, contract.ValidUntil).Scan(&id); err != nil {
+		return 0, careContractConstraintError(err)
+	}
+	return id, nil
+}
+
+// GetByID fetches a care contract by ID from the database.
+func (s *SQLCareContractStore) GetByID(ctx context.Context, id int) (*models.CareContract, error) {
+	contracts, err := s.query(ctx, `SELECT `+careContractColumns+` FROM care_contracts WHERE contract_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(contracts) == 0 {
+		return nil, ErrNotFound
+	}
+	return &contracts[0], nil
+}
+
+// Update updates the hours and period of an existing care contract in the database.
+func (s *SQLCareContractStore) Update(ctx context.Context, contract *models.CareContract) error {
+	query := `UPDATE care_contracts SET weekly_hours = ?, valid_from = ?, valid_until = ?, updated_at = CURRENT_TIMESTAMP WHERE contract_id = ?`
+	result, err := s.db.ExecContext(ctx, query, contract.WeeklyHours, contract.ValidFrom, contract.ValidUntil, contract.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete deletes a care contract by ID from the database.
+func (s *SQLCareContractStore) Delete(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM care_contracts WHERE contract_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetForChild fetches the care contracts of a child from the database.
+func (s *SQLCareContractStore) GetForChild(ctx context.Context, childID int) ([]models.CareContract, error) {
+	return s.query(ctx, `SELECT `+careContractColumns+` FROM care_contracts WHERE child_id = ? ORDER BY valid_from, contract_id`, childID)
+}
+
+// GetValidBetween fetches the care contracts covering a day of a period from the database.
+func (s *SQLCareContractStore) GetValidBetween(ctx context.Context, from models.Date, to models.Date) ([]models.CareContract, error) {
+	query := `SELECT ` + careContractColumns + ` FROM care_contracts
+		WHERE valid_from <= ? AND (valid_until IS NULL OR valid_until >= ?) ORDER BY child_id, valid_from, contract_id`
+	return s.query(ctx, query, to, from)
+}
+
+func (s *SQLCareContractStore) query(ctx context.Context, query string, args ...any) ([]models.CareContract, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	contracts := []models.CareContract{}
+	for rows.Next() {
+		var contract models.CareContract
+		if err := rows.Scan(&contract.ID, &contract.ChildID, &contract.WeeklyHours, &contract.ValidFrom, &contract.ValidUntil,
+			&contract.CreatedAt, &contract.UpdatedAt); err != nil {
+			return nil, err
+		}
+		contracts = append(contracts, contract)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
+
+func careContractConstraintError(err error) error {
+	if isForeignKeyError(err) {
+		return ErrForeignKeyConstraint
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLCareContractStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	require.NoError(t, err)
+
+	store := dal.CareContracts
+	until := models.NewDate(2024, time.December, 31)
+	firstID, err := store.Create(t.Context(), &models.CareContract{ChildID: childID, WeeklyHours: models.CareHours25, ValidFrom: models.NewDate(2024, time.August, 1), ValidUntil: &until})
+	require.NoError(t, err)
+	secondID, err := store.Create(t.Context(), &models.CareContract{ChildID: childID, WeeklyHours: models.CareHours35, ValidFrom: models.NewDate(2025, time.January, 1)})
+	require.NoError(t, err)
+	_, err = store.Create(t.Context(), &models.CareContract{ChildID: 999, WeeklyHours: models.CareHours45, ValidFrom: models.NewDate(2025, time.January, 1)})
+	assert.ErrorIs(t, err, data.ErrForeignKeyConstraint)
+
+	first, err := store.GetByID(t.Context(), firstID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CareHours25, first.WeeklyHours)
+	assert.Equal(t, until, *first.ValidUntil)
+	second, err := store.GetByID(t.Context(), secondID)
+	require.NoError(t, err)
+	assert.Nil(t, second.ValidUntil)
+
+	contracts, err := store.GetForChild(t.Context(), childID)
+	require.NoError(t, err)
+	require.Len(t, contracts, 2)
+	assert.Equal(t, firstID, contracts[0].ID)
+
+	valid, err := store.GetValidBetween(t.Context(), models.NewDate(2024, time.December, 30), models.NewDate(2025, time.January, 5))
+	require.NoError(t, err)
+	assert.Len(t, valid, 2)
+	valid, err = store.GetValidBetween(t.Context(), models.NewDate(2030, time.January, 1), models.NewDate(2030, time.January, 1))
+	require.NoError(t, err)
+	require.Len(t, valid, 1, "open-ended contracts stay valid")
+	assert.Equal(t, secondID, valid[0].ID)
+	valid, err = store.GetValidBetween(t.Context(), models.NewDate(2024, time.July, 1), models.NewDate(2024, time.July, 31))
+	require.NoError(t, err)
+	assert.Empty(t, valid)
+
+	second.WeeklyHours = models.CareHours45
+	require.NoError(t, store.Update(t.Context(), second))
+	second, err = store.GetByID(t.Context(), secondID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CareHours45, second.WeeklyHours)
+
+	require.NoError(t, store.Delete(t.Context(), firstID))
+	assert.ErrorIs(t, store.Delete(t.Context(), firstID), data.ErrNotFound)
+	_, err = store.GetByID(t.Context(), firstID)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+}
@@ -1,22 +1,36 @@
 package data
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"kitadoc-backend/models"
-
-	"modernc.org/sqlite"
 )
 
 // CategoryStore defines the interface for Category data operations.
 type CategoryStore interface {
-	Create(category *models.Category) (int, error)
-	GetByID(id int) (*models.Category, error)
-	Update(category *models.Category) error
-	Delete(id int) error
-	GetByName(name string) (*models.Category, error)
-	GetAll() ([]models.Category, error)
+	Create(ctx context.Context, category *models.Category) (int, error)
+	GetByID(ctx context.Context, id int) (*models.Category, error)
+	Update(ctx context.Context, category *models.Category) error
+	Delete(ctx context.Context, id int) error
+	GetByName(ctx context.Context, name string) (*models.Category, error)
+	// GetAll fetches all categories, including archived ones.
+	GetAll(ctx context.Context) ([]models.Category, error)
+	// SetArchived archives a category at the given time, nil restores it.
+	SetArchived(ctx context.Context, id int, archivedAt *time.Time) error
+	// ReassignEntries moves the unlocked documentation entries of a category to another one and returns how many were moved.
+	ReassignEntries(ctx context.Context, fromCategoryID int, toCategoryID int) (int, error)
+	// CountUsage counts the entries of every used category, keyed by category ID.
+	CountUsage(ctx context.Context) (map[int]models.UsageCounts, error)
+	// GetFavorites fetches the unarchived categories pinned by a user or used in the entries of a teacher,
+	// the pinned ones first, then the most and most recently used ones.
+	GetFavorites(ctx context.Context, userID int, teacherID int) ([]models.FavoriteCategory, error)
+	// SetPinned pins a category for a user or removes the pin. Both are idempotent.
+	SetPinned(ctx context.Context, userID int, categoryID int, pinned bool) error
 }
 
 // SQLCategoryStore implements CategoryStore using database/sql.
@@ -29,26 +43,55 @@ func NewSQLCategoryStore(db *sql.DB) *SQLCategoryStore {
 	return &SQLCategoryStore{db: db}
 }
 
+const categoryColumns = `category_id, category_name, description, form_schema, min_age_months, max_age_months, archived_at`
+
+// scanCategory scans the categoryColumns of a row, followed by the extra columns of the query into extra.
+func scanCategory(row rowScanner, extra ...any) (*models.Category, error) {
+	category := &models.Category{}
+	var formSchema sql.NullString
+	dest := []any{&category.ID, &category.Name, &category.Description, &formSchema, &category.MinAgeMonths, &category.MaxAgeMonths, &category.ArchivedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if formSchema.Valid {
+		category.FormSchema = &models.FormSchema{}
+		if err := json.Unmarshal([]byte(formSchema.String), category.FormSchema); err != nil {
+			return nil, fmt.Errorf("failed to decode form schema: %w", err)
+		}
+	}
+	return category, nil
+}
+
+func encodeFormSchema(schema *models.FormSchema) (*string, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode form schema: %w", err)
+	}
+	formSchema := string(encoded)
+	return &formSchema, nil
+}
+
 // Create inserts a new category into the database.
-func (s *SQLCategoryStore) Create(category *models.Category) (int, error) {
-	query := `INSERT INTO categories (category_name, description) VALUES (?, ?)`
-	result, err := s.db.Exec(query, category.Name, category.Description)
+func (s *SQLCategoryStore) Create(ctx context.Context, category *models.Category) (int, error) {
+	formSchema, err := encodeFormSchema(category.FormSchema)
 	if err != nil {
 		return 0, err
 	}
-	id, err := result.LastInsertId()
-	if err != nil {
+	query := `INSERT INTO categories (category_name, description, form_schema, min_age_months, max_age_months) VALUES (?, ?, ?, ?, ?) RETURNING category_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, category.Name, category.Description, formSchema, category.MinAgeMonths, category.MaxAgeMonths).Scan(&id); err != nil {
 		return 0, err
 	}
-	return int(id), nil
+	return id, nil
 }
 
 // GetByID fetches a category by ID from the database.
-func (s *SQLCategoryStore) GetByID(id int) (*models.Category, error) {
-	query := `SELECT category_id, category_name, description FROM categories WHERE category_id = ?`
-	row := s.db.QueryRow(query, id)
-	category := &models.Category{}
-	err := row.Scan(&category.ID, &category.Name, &category.Description)
+func (s *SQLCategoryStore) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE category_id = ?`
+	category, err := scanCategory(s.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -59,9 +102,13 @@ func (s *SQLCategoryStore) GetByID(id int) (*models.Category, error) {
 }
 
 // Update updates an existing category in the database.
-func (s *SQLCategoryStore) Update(category *models.Category) error {
-	query := `UPDATE categories SET category_name = ?, description = ? WHERE category_id = ?`
-	result, err := s.db.Exec(query, category.Name, category.Description, category.ID)
+func (s *SQLCategoryStore) Update(ctx context.Context, category *models.Category) error {
+	formSchema, err := encodeFormSchema(category.FormSchema)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE categories SET category_name = ?, description = ?, form_schema = ?, min_age_months = ?, max_age_months = ? WHERE category_id = ?`
+	result, err := s.db.ExecContext(ctx, query, category.Name, category.Description, formSchema, category.MinAgeMonths, category.MaxAgeMonths, category.ID)
 	if err != nil {
 		return err
 	}
@@ -76,16 +123,13 @@ func (s *SQLCategoryStore) Update(category *models.Category) error {
 }
 
 // Delete deletes a category by ID from the database.
-func (s *SQLCategoryStore) Delete(id int) error {
+func (s *SQLCategoryStore) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM categories WHERE category_id = ?`
-	result, err := s.db.Exec(query, id)
+	result, err := s.db.ExecContext(ctx, query, id)
 	if err != nil {
 		// Check for foreign key constraint violation
-		if liteErr, ok := err.(*sqlite.Error); ok {
-			code := liteErr.Code()
-			if code == 1811 || code == 787 {
-				return ErrForeignKeyConstraint
-			}
+		if isForeignKeyError(err) {
+			return ErrForeignKeyConstraint
 		}
 		return err
 	}
@@ -100,11 +144,9 @@ func (s *SQLCategoryStore) Delete(id int) error {
 }
 
 // GetByName fetches a category by name from the database.
-func (s *SQLCategoryStore) GetByName(name string) (*models.Category, error) {
-	query := `SELECT category_id, category_name, description FROM categories WHERE category_name = ?`
-	row := s.db.QueryRow(query, name)
-	category := &models.Category{}
-	err := row.Scan(&category.ID, &category.Name, &category.Description)
+func (s *SQLCategoryStore) GetByName(ctx context.Context, name string) (*models.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE category_name = ?`
+	category, err := scanCategory(s.db.QueryRowContext(ctx, query, name))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -114,10 +156,10 @@ func (s *SQLCategoryStore) GetByName(name string) (*models.Category, error) {
 	return category, nil
 }
 
-// GetAll fetches all categories from the database.
-func (s *SQLCategoryStore) GetAll() ([]models.Category, error) {
-	query := `SELECT category_id, category_name, description FROM categories`
-	rows, err := s.db.Query(query)
+// GetAll fetches all categories from the database, including archived ones.
+func (s *SQLCategoryStore) GetAll(ctx context.Context) ([]models.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories ORDER BY category_id`
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -125,8 +167,7 @@ func (s *SQLCategoryStore) GetAll() ([]models.Category, error) {
 
 	var categories []models.Category
 	for rows.Next() {
-		category := &models.Category{}
-		err := rows.Scan(&category.ID, &category.Name, &category.Description)
+		category, err := scanCategory(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -139,3 +180,87 @@ func (s *SQLCategoryStore) GetAll() ([]models.Category, error) {
 
 	return categories, nil
 }
+
+// SetArchived archives a category at the given time, nil restores it.
+func (s *SQLCategoryStore) SetArchived(ctx context.Context, id int, archivedAt *time.Time) error {
+	query := `UPDATE categories SET archived_at = ? WHERE category_id = ?`
+	result, err := s.db.ExecContext(ctx, query, archivedAt, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ReassignEntries moves the documentation entries of a category to another one.
+// Entries locked by a generated report stay, so that the report can be traced back to its sections.
+func (s *SQLCategoryStore) ReassignEntries(ctx context.Context, fromCategoryID int, toCategoryID int) (int, error) {
+	query := `UPDATE documentation_entries SET category_id = ?, updated_at = CURRENT_TIMESTAMP WHERE category_id = ? AND locked_at IS NULL`
+	result, err := s.db.ExecContext(ctx, query, toCategoryID, fromCategoryID)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rowsAffected), nil
+}
+
+// CountUsage counts the documentation entries and documented children of every used category, keyed by category ID.
+func (s *SQLCategoryStore) CountUsage(ctx context.Context) (map[int]models.UsageCounts, error) {
+	query := `SELECT category_id, COUNT(*), COUNT(DISTINCT child_id) FROM documentation_entries GROUP BY category_id`
+	return queryUsageCounts(ctx, s.db, query, func(usage *models.UsageCounts) []any {
+		return []any{&usage.Entries, &usage.Children}
+	})
+}
+
+// GetFavorites fetches the unarchived categories pinned by a user or used in the entries of a teacher. Pinned
+// categories come first in the order they were pinned, then the others by use count and their latest entry.
+func (s *SQLCategoryStore) GetFavorites(ctx context.Context, userID int, teacherID int) ([]models.FavoriteCategory, error) {
+	query := `SELECT c.category_id, c.category_name, c.description, c.form_schema, c.min_age_months, c.max_age_months, c.archived_at,
+			pin.pinned_at IS NOT NULL, COALESCE(used.use_count, 0), latest.created_at
+		FROM categories c
+		LEFT JOIN category_pins pin ON pin.category_id = c.category_id AND pin.user_id = ?
+		LEFT JOIN (SELECT category_id, COUNT(*) AS use_count, MAX(entry_id) AS latest_entry_id FROM documentation_entries
+			WHERE documenting_teacher_id = ? GROUP BY category_id) used ON used.category_id = c.category_id
+		LEFT JOIN documentation_entries latest ON latest.entry_id = used.latest_entry_id
+		WHERE c.archived_at IS NULL AND (pin.user_id IS NOT NULL OR used.use_count IS NOT NULL)
+		ORDER BY pin.user_id IS NULL, pin.pinned_at, used.use_count DESC, used.latest_entry_id DESC, c.category_id`
+	rows, err := s.db.QueryContext(ctx, query, userID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	favorites := []models.FavoriteCategory{}
+	for rows.Next() {
+		var favorite models.FavoriteCategory
+		category, err := scanCategory(rows, &favorite.Pinned, &favorite.UseCount, &favorite.LastUsedAt)
+		if err != nil {
+			return nil, err
+		}
+		favorite.Category = *category
+		favorites = append(favorites, favorite)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+// SetPinned pins a category for a user or removes the pin.
+func (s *SQLCategoryStore) SetPinned(ctx context.Context, userID int, categoryID int, pinned bool) error {
+	query := `DELETE FROM category_pins WHERE user_id = ? AND category_id = ?`
+	if pinned {
+		query = `INSERT INTO category_pins (user_id, category_id) VALUES (?, ?) ON CONFLICT DO NOTHING`
+	}
+	_, err := s.db.ExecContext(ctx, query, userID, categoryID)
+	return err
+}
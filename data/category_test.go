@@ -5,6 +5,7 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"kitadoc-backend/data"
 	"kitadoc-backend/internal/testutils"
@@ -29,22 +30,22 @@ func TestSQLCategoryStore_Create(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO categories (category_name, description) VALUES (?, ?)`)).
-			WithArgs(category.Name, category.Description).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO categories (category_name, description, form_schema, min_age_months, max_age_months) VALUES (?, ?, ?, ?, ?) RETURNING category_id`)).
+			WithArgs(category.Name, category.Description, nil, nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"category_id"}).AddRow(1))
 
-		id, err := store.Create(category)
+		id, err := store.Create(t.Context(), category)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, id)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO categories (category_name, description) VALUES (?, ?)`)).
-			WithArgs(category.Name, category.Description).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO categories (category_name, description, form_schema, min_age_months, max_age_months) VALUES (?, ?, ?, ?, ?) RETURNING category_id`)).
+			WithArgs(category.Name, category.Description, nil, nil, nil).
 			WillReturnError(errors.New("db error"))
 
-		id, err := store.Create(category)
+		id, err := store.Create(t.Context(), category)
 		assert.Error(t, err)
 		assert.Equal(t, 0, id)
 		assert.Contains(t, err.Error(), "db error")
@@ -69,28 +70,32 @@ func TestSQLCategoryStore_GetByID(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"category_id", "category_name", "description"}).
-			AddRow(expectedCategory.ID, expectedCategory.Name, expectedCategory.Description)
+		rows := sqlmock.NewRows([]string{"category_id", "category_name", "description", "form_schema", "min_age_months", "max_age_months", "archived_at"}).
+			AddRow(expectedCategory.ID, expectedCategory.Name, expectedCategory.Description, nil, 36, nil, nil)
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description FROM categories WHERE category_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description, form_schema, min_age_months, max_age_months, archived_at FROM categories WHERE category_id = ?`)).
 			WithArgs(categoryID).
 			WillReturnRows(rows)
 
-		category, err := store.GetByID(categoryID)
+		category, err := store.GetByID(t.Context(), categoryID)
 		assert.NoError(t, err)
 		assert.NotNil(t, category)
 		assert.Equal(t, expectedCategory.ID, category.ID)
 		assert.Equal(t, expectedCategory.Name, category.Name)
 		assert.Equal(t, expectedCategory.Description, category.Description)
+		if assert.NotNil(t, category.MinAgeMonths) {
+			assert.Equal(t, 36, *category.MinAgeMonths)
+		}
+		assert.Nil(t, category.MaxAgeMonths)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description FROM categories WHERE category_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description, form_schema, min_age_months, max_age_months, archived_at FROM categories WHERE category_id = ?`)).
 			WithArgs(categoryID).
 			WillReturnError(sql.ErrNoRows)
 
-		category, err := store.GetByID(categoryID)
+		category, err := store.GetByID(t.Context(), categoryID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.Nil(t, category)
@@ -98,11 +103,11 @@ func TestSQLCategoryStore_GetByID(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description FROM categories WHERE category_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description, form_schema, min_age_months, max_age_months, archived_at FROM categories WHERE category_id = ?`)).
 			WithArgs(categoryID).
 			WillReturnError(errors.New("db error"))
 
-		category, err := store.GetByID(categoryID)
+		category, err := store.GetByID(t.Context(), categoryID)
 		assert.Error(t, err)
 		assert.Nil(t, category)
 		assert.Contains(t, err.Error(), "db error")
@@ -126,32 +131,32 @@ func TestSQLCategoryStore_Update(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE categories SET category_name = ?, description = ? WHERE category_id = ?`)).
-			WithArgs(category.Name, category.Description, category.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE categories SET category_name = ?, description = ?, form_schema = ?, min_age_months = ?, max_age_months = ? WHERE category_id = ?`)).
+			WithArgs(category.Name, category.Description, nil, nil, nil, category.ID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Update(category)
+		err := store.Update(t.Context(), category)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE categories SET category_name = ?, description = ? WHERE category_id = ?`)).
-			WithArgs(category.Name, category.Description, category.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE categories SET category_name = ?, description = ?, form_schema = ?, min_age_months = ?, max_age_months = ? WHERE category_id = ?`)).
+			WithArgs(category.Name, category.Description, nil, nil, nil, category.ID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := store.Update(category)
+		err := store.Update(t.Context(), category)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE categories SET category_name = ?, description = ? WHERE category_id = ?`)).
-			WithArgs(category.Name, category.Description, category.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE categories SET category_name = ?, description = ?, form_schema = ?, min_age_months = ?, max_age_months = ? WHERE category_id = ?`)).
+			WithArgs(category.Name, category.Description, nil, nil, nil, category.ID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Update(category)
+		err := store.Update(t.Context(), category)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -174,7 +179,7 @@ func TestSQLCategoryStore_Delete(t *testing.T) {
 			WithArgs(categoryID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Delete(categoryID)
+		err := store.Delete(t.Context(), categoryID)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -184,7 +189,7 @@ func TestSQLCategoryStore_Delete(t *testing.T) {
 			WithArgs(categoryID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := store.Delete(categoryID)
+		err := store.Delete(t.Context(), categoryID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -195,7 +200,7 @@ func TestSQLCategoryStore_Delete(t *testing.T) {
 			WithArgs(categoryID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Delete(categoryID)
+		err := store.Delete(t.Context(), categoryID)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -219,14 +224,14 @@ func TestSQLCategoryStore_GetByName(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"category_id", "category_name", "description"}).
-			AddRow(expectedCategory.ID, expectedCategory.Name, expectedCategory.Description)
+		rows := sqlmock.NewRows([]string{"category_id", "category_name", "description", "form_schema", "min_age_months", "max_age_months", "archived_at"}).
+			AddRow(expectedCategory.ID, expectedCategory.Name, expectedCategory.Description, nil, nil, nil, nil)
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description FROM categories WHERE category_name = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description, form_schema, min_age_months, max_age_months, archived_at FROM categories WHERE category_name = ?`)).
 			WithArgs(categoryName).
 			WillReturnRows(rows)
 
-		category, err := store.GetByName(categoryName)
+		category, err := store.GetByName(t.Context(), categoryName)
 		assert.NoError(t, err)
 		assert.NotNil(t, category)
 		assert.Equal(t, expectedCategory.ID, category.ID)
@@ -236,11 +241,11 @@ func TestSQLCategoryStore_GetByName(t *testing.T) {
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description FROM categories WHERE category_name = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description, form_schema, min_age_months, max_age_months, archived_at FROM categories WHERE category_name = ?`)).
 			WithArgs(categoryName).
 			WillReturnError(sql.ErrNoRows)
 
-		category, err := store.GetByName(categoryName)
+		category, err := store.GetByName(t.Context(), categoryName)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.Nil(t, category)
@@ -248,11 +253,11 @@ func TestSQLCategoryStore_GetByName(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description FROM categories WHERE category_name = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description, form_schema, min_age_months, max_age_months, archived_at FROM categories WHERE category_name = ?`)).
 			WithArgs(categoryName).
 			WillReturnError(errors.New("db error"))
 
-		category, err := store.GetByName(categoryName)
+		category, err := store.GetByName(t.Context(), categoryName)
 		assert.Error(t, err)
 		assert.Nil(t, category)
 		assert.Contains(t, err.Error(), "db error")
@@ -275,14 +280,14 @@ func TestSQLCategoryStore_GetAll(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"category_id", "category_name", "description"}).
-			AddRow(categories[0].ID, categories[0].Name, categories[0].Description).
-			AddRow(categories[1].ID, categories[1].Name, categories[1].Description)
+		rows := sqlmock.NewRows([]string{"category_id", "category_name", "description", "form_schema", "min_age_months", "max_age_months", "archived_at"}).
+			AddRow(categories[0].ID, categories[0].Name, categories[0].Description, nil, nil, nil, nil).
+			AddRow(categories[1].ID, categories[1].Name, categories[1].Description, nil, nil, nil, nil)
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description FROM categories`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description, form_schema, min_age_months, max_age_months, archived_at FROM categories ORDER BY category_id`)).
 			WillReturnRows(rows)
 
-		fetchedCategories, err := store.GetAll()
+		fetchedCategories, err := store.GetAll(t.Context())
 		assert.NoError(t, err)
 		assert.NotNil(t, fetchedCategories)
 		assert.Len(t, fetchedCategories, 2)
@@ -292,10 +297,10 @@ func TestSQLCategoryStore_GetAll(t *testing.T) {
 	})
 
 	t.Run("no categories found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description FROM categories`)).
-			WillReturnRows(sqlmock.NewRows([]string{"category_id", "category_name", "description"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description, form_schema, min_age_months, max_age_months, archived_at FROM categories ORDER BY category_id`)).
+			WillReturnRows(sqlmock.NewRows([]string{"category_id", "category_name", "description", "form_schema", "min_age_months", "max_age_months", "archived_at"}))
 
-		fetchedCategories, err := store.GetAll()
+		fetchedCategories, err := store.GetAll(t.Context())
 		assert.NoError(t, err)
 		assert.Nil(t, fetchedCategories)
 		assert.Len(t, fetchedCategories, 0)
@@ -303,13 +308,135 @@ func TestSQLCategoryStore_GetAll(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description FROM categories`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, category_name, description, form_schema, min_age_months, max_age_months, archived_at FROM categories ORDER BY category_id`)).
 			WillReturnError(errors.New("db error"))
 
-		fetchedCategories, err := store.GetAll()
+		fetchedCategories, err := store.GetAll(t.Context())
 		assert.Error(t, err)
 		assert.Nil(t, fetchedCategories)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestSQLCategoryStore_SetArchived(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLCategoryStore(db)
+	archivedAt := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE categories SET archived_at = ? WHERE category_id = ?`)).
+			WithArgs(&archivedAt, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := store.SetArchived(t.Context(), 1, &archivedAt)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE categories SET archived_at = ? WHERE category_id = ?`)).
+			WithArgs(nil, 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := store.SetArchived(t.Context(), 1, nil)
+		assert.Equal(t, data.ErrNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLCategoryStore_ReassignEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLCategoryStore(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET category_id = ?, updated_at = CURRENT_TIMESTAMP WHERE category_id = ? AND locked_at IS NULL`)).
+			WithArgs(2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+
+		moved, err := store.ReassignEntries(t.Context(), 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, moved)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET category_id = ?, updated_at = CURRENT_TIMESTAMP WHERE category_id = ? AND locked_at IS NULL`)).
+			WithArgs(2, 1).
+			WillReturnError(errors.New("db error"))
+
+		moved, err := store.ReassignEntries(t.Context(), 1, 2)
+		assert.Error(t, err)
+		assert.Equal(t, 0, moved)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLCategoryStore_CountUsage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLCategoryStore(db)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT category_id, COUNT(*), COUNT(DISTINCT child_id) FROM documentation_entries GROUP BY category_id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"category_id", "entries", "children"}).AddRow(1, 5, 3).AddRow(4, 1, 1))
+
+	usage, err := store.CountUsage(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]models.UsageCounts{1: {Entries: 5, Children: 3}, 4: {Entries: 1, Children: 1}}, usage)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLCategoryStore_GetFavorites(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLCategoryStore(db)
+	lastUsedAt := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT c.category_id, .* FROM categories c LEFT JOIN category_pins pin .* WHERE c.archived_at IS NULL`).
+		WithArgs(7, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"category_id", "category_name", "description", "form_schema", "min_age_months", "max_age_months", "archived_at", "pinned", "use_count", "last_used_at"}).
+			AddRow(2, "Motorik", nil, nil, nil, nil, nil, true, 0, nil).
+			AddRow(1, "Sprache", nil, nil, nil, nil, nil, false, 4, lastUsedAt))
+
+	favorites, err := store.GetFavorites(t.Context(), 7, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.FavoriteCategory{
+		{Category: models.Category{ID: 2, Name: "Motorik"}, Pinned: true},
+		{Category: models.Category{ID: 1, Name: "Sprache"}, UseCount: 4, LastUsedAt: &lastUsedAt},
+	}, favorites)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLCategoryStore_SetPinned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLCategoryStore(db)
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO category_pins (user_id, category_id) VALUES (?, ?) ON CONFLICT DO NOTHING`)).
+		WithArgs(7, 2).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM category_pins WHERE user_id = ? AND category_id = ?`)).
+		WithArgs(7, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, store.SetPinned(t.Context(), 7, 2, true))
+	assert.NoError(t, store.SetPinned(t.Context(), 7, 2, false))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,34 +1,41 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
-	"time"
 
 	"kitadoc-backend/models"
-
-	"modernc.org/sqlite"
 )
 
 // ChildStore defines the interface for Child data operations.
 type ChildStore interface {
-	Create(child *models.Child) (int, error)
-	GetByID(id int) (*models.Child, error)
-	Update(child *models.Child) error
-	Delete(id int) error
-	GetAll() ([]models.Child, error)
+	Create(ctx context.Context, child *models.Child) (int, error)
+	// CreateMany inserts the children in one transaction and returns their IDs in the same order.
+	CreateMany(ctx context.Context, children []models.Child) ([]int, error)
+	GetByID(ctx context.Context, id int) (*models.Child, error)
+	Update(ctx context.Context, child *models.Child) error
+	Delete(ctx context.Context, id int) error
+	GetAll(ctx context.Context) ([]models.Child, error)
+	GetAllIncludingArchived(ctx context.Context) ([]models.Child, error)
+	// List fetches the children matching the query, by default ordered by ID. Archived children are included
+	// unless the query filters on archived_at.
+	List(ctx context.Context, query models.ListQuery) ([]models.Child, error)
+	// Count counts the children matching the conditions of the query.
+	Count(ctx context.Context, query models.ListQuery) (int, error)
 }
 
 // SQLChildStore implements ChildStore using database/sql.
 type SQLChildStore struct {
 	db            *sql.DB
+	statements    *statementCache
 	encryptionKey []byte
 }
 
 // NewSQLChildStore creates a new SQLChildStore.
 func NewSQLChildStore(db *sql.DB, encryptionKey []byte) *SQLChildStore {
-	return &SQLChildStore{db: db, encryptionKey: encryptionKey}
+	return &SQLChildStore{db: db, statements: newStatementCache(db, maxCachedStatements), encryptionKey: encryptionKey}
 }
 
 // toChildDB converts a models.Child to a models.ChildDB and encrypts PII fields.
@@ -43,18 +50,19 @@ func toChildDB(child *models.Child, key []byte) (*models.ChildDB, error) {
 		return nil, fmt.Errorf("failed to encrypt LastName: %w", err)
 	}
 
-	encryptedBirthdate, err := Encrypt(child.Birthdate.Format(time.RFC3339Nano), key)
+	encryptedBirthdate, err := Encrypt(child.Birthdate.String(), key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt Birthdate: %w", err)
 	}
 
 	dbChild := &models.ChildDB{
-		ID:        child.ID,
-		FirstName: encryptedFirstName,
-		LastName:  encryptedLastName,
-		Birthdate: encryptedBirthdate,
-		CreatedAt: child.CreatedAt,
-		UpdatedAt: child.UpdatedAt,
+		ID:            child.ID,
+		FirstName:     encryptedFirstName,
+		LastName:      encryptedLastName,
+		Birthdate:     encryptedBirthdate,
+		IsPreschooler: child.IsPreschooler,
+		CreatedAt:     child.CreatedAt,
+		UpdatedAt:     child.UpdatedAt,
 	}
 
 	if child.AdmissionDate != nil {
@@ -89,18 +97,20 @@ func fromChildDB(dbChild *models.ChildDB, key []byte) (*models.Child, error) {
 		return nil, fmt.Errorf("failed to decrypt Birthdate: %w", err)
 	}
 
-	parsedBirthdate, err := time.Parse(time.RFC3339Nano, decryptedBirthdate)
+	// Birthdates were stored as timestamps before, models.ParseDate reads both
+	parsedBirthdate, err := models.ParseDate(decryptedBirthdate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse Birthdate: %w", err)
 	}
 
 	child := &models.Child{
-		ID:        dbChild.ID,
-		FirstName: decryptedFirstName,
-		LastName:  decryptedLastName,
-		Birthdate: parsedBirthdate,
-		CreatedAt: dbChild.CreatedAt,
-		UpdatedAt: dbChild.UpdatedAt,
+		ID:            dbChild.ID,
+		FirstName:     decryptedFirstName,
+		LastName:      decryptedLastName,
+		Birthdate:     parsedBirthdate,
+		IsPreschooler: dbChild.IsPreschooler,
+		CreatedAt:     dbChild.CreatedAt,
+		UpdatedAt:     dbChild.UpdatedAt,
 	}
 
 	if dbChild.AdmissionDate.Valid {
@@ -111,34 +121,61 @@ func fromChildDB(dbChild *models.ChildDB, key []byte) (*models.Child, error) {
 		child.ExpectedSchoolEnrollment = &dbChild.ExpectedSchoolEnrollment.Time
 	}
 
+	if dbChild.ArchivedAt.Valid {
+		child.ArchivedAt = &dbChild.ArchivedAt.Time
+	}
+
 	return child, nil
 }
 
 // Create inserts a new child into the database.
-func (s *SQLChildStore) Create(child *models.Child) (int, error) {
-	dbChild, err := toChildDB(child, s.encryptionKey)
+func (s *SQLChildStore) Create(ctx context.Context, child *models.Child) (int, error) {
+	return insertChild(ctx, s.db, s.encryptionKey, child)
+}
+
+// CreateMany inserts the children in one transaction and returns their IDs in the same order.
+// Either all children are inserted or none.
+func (s *SQLChildStore) CreateMany(ctx context.Context, children []models.Child) ([]int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
+	defer tx.Rollback() //nolint:errcheck
 
-	query := `INSERT INTO children (first_name, last_name, birthdate, admission_date, expected_school_enrollment) VALUES (?, ?, ?, ?, ?)`
-	result, err := s.db.Exec(query, dbChild.FirstName, dbChild.LastName, dbChild.Birthdate, dbChild.AdmissionDate, dbChild.ExpectedSchoolEnrollment)
+	ids := make([]int, len(children))
+	for i := range children {
+		ids[i], err = insertChild(ctx, tx, s.encryptionKey, &children[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// insertChild inserts a child, also within the transaction of other stores.
+func insertChild(ctx context.Context, db execer, key []byte, child *models.Child) (int, error) {
+	dbChild, err := toChildDB(child, key)
 	if err != nil {
 		return 0, err
 	}
-	id, err := result.LastInsertId()
-	if err != nil {
+
+	query := `INSERT INTO children (first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler) VALUES (?, ?, ?, ?, ?, ?) RETURNING child_id`
+	var id int
+	if err := db.QueryRowContext(ctx, query, dbChild.FirstName, dbChild.LastName, dbChild.Birthdate, dbChild.AdmissionDate, dbChild.ExpectedSchoolEnrollment, dbChild.IsPreschooler).Scan(&id); err != nil {
 		return 0, err
 	}
-	return int(id), nil
+	return id, nil
 }
 
 // GetByID fetches a child by ID from the database.
-func (s *SQLChildStore) GetByID(id int) (*models.Child, error) {
-	query := `SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, created_at, updated_at FROM children WHERE child_id = ?`
-	row := s.db.QueryRow(query, id)
+func (s *SQLChildStore) GetByID(ctx context.Context, id int) (*models.Child, error) {
+	query := `SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children WHERE child_id = ?`
+	row := s.db.QueryRowContext(ctx, query, id)
 	dbChild := &models.ChildDB{}
-	err := row.Scan(&dbChild.ID, &dbChild.FirstName, &dbChild.LastName, &dbChild.Birthdate, &dbChild.AdmissionDate, &dbChild.ExpectedSchoolEnrollment, &dbChild.CreatedAt, &dbChild.UpdatedAt)
+	err := row.Scan(&dbChild.ID, &dbChild.FirstName, &dbChild.LastName, &dbChild.Birthdate, &dbChild.AdmissionDate, &dbChild.ExpectedSchoolEnrollment, &dbChild.IsPreschooler, &dbChild.ArchivedAt, &dbChild.CreatedAt, &dbChild.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -150,14 +187,14 @@ func (s *SQLChildStore) GetByID(id int) (*models.Child, error) {
 }
 
 // Update updates an existing child in the database.
-func (s *SQLChildStore) Update(child *models.Child) error {
+func (s *SQLChildStore) Update(ctx context.Context, child *models.Child) error {
 	dbChild, err := toChildDB(child, s.encryptionKey)
 	if err != nil {
 		return err
 	}
 
-	query := `UPDATE children SET first_name = ?, last_name = ?, birthdate = ?, admission_date = ?, expected_school_enrollment = ? WHERE child_id = ?`
-	result, err := s.db.Exec(query, dbChild.FirstName, dbChild.LastName, dbChild.Birthdate, dbChild.AdmissionDate, dbChild.ExpectedSchoolEnrollment, dbChild.ID)
+	query := `UPDATE children SET first_name = ?, last_name = ?, birthdate = ?, admission_date = ?, expected_school_enrollment = ?, is_preschooler = ? WHERE child_id = ?`
+	result, err := s.db.ExecContext(ctx, query, dbChild.FirstName, dbChild.LastName, dbChild.Birthdate, dbChild.AdmissionDate, dbChild.ExpectedSchoolEnrollment, dbChild.IsPreschooler, dbChild.ID)
 	if err != nil {
 		return err
 	}
@@ -172,16 +209,13 @@ func (s *SQLChildStore) Update(child *models.Child) error {
 }
 
 // Delete deletes a child by ID from the database.
-func (s *SQLChildStore) Delete(id int) error {
+func (s *SQLChildStore) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM children WHERE child_id = ?`
-	result, err := s.db.Exec(query, id)
+	result, err := s.db.ExecContext(ctx, query, id)
 	if err != nil {
 		// Check for foreign key constraint violation
-		if liteErr, ok := err.(*sqlite.Error); ok {
-			code := liteErr.Code()
-			if code == 1811 || code == 787 {
-				return ErrForeignKeyConstraint
-			}
+		if isForeignKeyError(err) {
+			return ErrForeignKeyConstraint
 		}
 		return err
 	}
@@ -195,11 +229,52 @@ func (s *SQLChildStore) Delete(id int) error {
 	return nil
 }
 
-// GetAll fetches all children with pagination and filtering options.
-func (s *SQLChildStore) GetAll() ([]models.Child, error) {
-	query := `SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, created_at, updated_at FROM children`
+const childSelect = `SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children`
+
+// childListColumns are the fields children can be filtered and sorted by. Names and birthdates are encrypted.
+var childListColumns = listColumns{
+	"id":                         "child_id",
+	"group_id":                   "(SELECT group_id FROM group_children WHERE group_children.child_id = children.child_id)",
+	"admission_date":             "admission_date",
+	"expected_school_enrollment": "expected_school_enrollment",
+	"is_preschooler":             "is_preschooler",
+	"archived_at":                "archived_at",
+	"created_at":                 "created_at",
+	"updated_at":                 "updated_at",
+}
+
+// GetAll fetches all children that have not been archived.
+func (s *SQLChildStore) GetAll(ctx context.Context) ([]models.Child, error) {
+	return s.List(ctx, models.ListQuery{}.Where("archived_at", models.OperatorIsNull, nil))
+}
+
+// GetAllIncludingArchived fetches all children, including archived ones.
+func (s *SQLChildStore) GetAllIncludingArchived(ctx context.Context) ([]models.Child, error) {
+	return s.List(ctx, models.ListQuery{})
+}
+
+// List fetches the children matching the query, by default ordered by ID.
+func (s *SQLChildStore) List(ctx context.Context, query models.ListQuery) ([]models.Child, error) {
+	statement, args, err := buildListQuery(childSelect, childListColumns, query, models.SortField{Field: "id"})
+	if err != nil {
+		return nil, err
+	}
+	return s.queryChildren(ctx, statement, args...)
+}
+
+// Count counts the children matching the conditions of the query.
+func (s *SQLChildStore) Count(ctx context.Context, query models.ListQuery) (int, error) {
+	statement, args, err := buildCountQuery(`SELECT COUNT(*) FROM children`, childListColumns, query)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	err = s.statements.queryRow(ctx, statement, args...).Scan(&count)
+	return count, err
+}
 
-	rows, err := s.db.Query(query)
+func (s *SQLChildStore) queryChildren(ctx context.Context, query string, args ...any) ([]models.Child, error) {
+	rows, err := s.statements.query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -208,7 +283,7 @@ func (s *SQLChildStore) GetAll() ([]models.Child, error) {
 	var children []models.Child
 	for rows.Next() {
 		dbChild := &models.ChildDB{}
-		err := rows.Scan(&dbChild.ID, &dbChild.FirstName, &dbChild.LastName, &dbChild.Birthdate, &dbChild.AdmissionDate, &dbChild.ExpectedSchoolEnrollment, &dbChild.CreatedAt, &dbChild.UpdatedAt)
+		err := rows.Scan(&dbChild.ID, &dbChild.FirstName, &dbChild.LastName, &dbChild.Birthdate, &dbChild.AdmissionDate, &dbChild.ExpectedSchoolEnrollment, &dbChild.IsPreschooler, &dbChild.ArchivedAt, &dbChild.CreatedAt, &dbChild.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,182 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// ChildDocumentStore defines the interface for ChildDocument data operations.
+type ChildDocumentStore interface {
+	// Create inserts a document with its content.
+	Create(ctx context.Context, document *models.ChildDocument) (int, error)
+	GetByID(ctx context.Context, id int) (*models.ChildDocument, error)
+	// GetWithContent fetches a document with its content.
+	GetWithContent(ctx context.Context, id int) (*models.ChildDocument, error)
+	// Update changes the type, the file name and the expiry date of a document, the content is kept.
+	Update(ctx context.Context, document *models.ChildDocument) error
+	Delete(ctx context.Context, id int) error
+	// GetForChild fetches the documents of a child ordered by type, the latest first.
+	GetForChild(ctx context.Context, childID int) ([]models.ChildDocument, error)
+	// GetAll fetches the documents of all children without their content.
+	GetAll(ctx context.Context) ([]models.ChildDocument, error)
+}
+
+// SQLChildDocumentStore implements ChildDocumentStore using database/sql.
+type SQLChildDocumentStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLChildDocumentStore creates a new SQLChildDocumentStore.
+func NewSQLChildDocumentStore(db *sql.DB, encryptionKey []byte) *SQLChildDocumentStore {
+	return &SQLChildDocumentStore{db: db, encryptionKey: encryptionKey}
+}
+
+const childDocumentColumns = `document_id, child_id, document_type, file_name, content_type, size_bytes, expires_on, uploaded_by_user_id, envelope, created_at, updated_at`
+
+// Create inserts a new document into the database.
+func (s *SQLChildDocumentStore) Create(ctx context.Context, document *models.ChildDocument) (int, error) {
+	fileName, err := Encrypt(document.FileName, s.encryptionKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt document file name: %w", err)
+	}
+	envelope, err := encodeEnvelope(document.Envelope)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	contentHash, err := putAttachmentBlob(ctx, tx, document.Content, s.encryptionKey)
+	if err != nil {
+		return 0, err
+	}
+	query := `INSERT INTO child_documents (child_id, document_type, file_name, content_type, content, content_hash, size_bytes, expires_on, uploaded_by_user_id, envelope)
+		VALUES (?, ?, ?, ?, '', ?, ?, ?, ?, ?)
+		RETURNING document_id`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, document.ChildID, document.DocumentType, fileName, document.ContentType, contentHash, len(document.Content), document.ExpiresOn,
+		document.UploadedByUserID, envelope).Scan(&id); err != nil {
+		if isForeignKeyError(err) {
+			return 0, ErrForeignKeyConstraint
+		}
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a document by ID from the database.
+func (s *SQLChildDocumentStore) GetByID(ctx context.Context, id int) (*models.ChildDocument, error) {
+	documents, err := s.queryDocuments(ctx, `SELECT `+childDocumentColumns+` FROM child_documents WHERE document_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(documents) == 0 {
+		return nil, ErrNotFound
+	}
+	return &documents[0], nil
+}
+
+// GetWithContent fetches a document with its content from the database.
+func (s *SQLChildDocumentStore) GetWithContent(ctx context.Context, id int) (*models.ChildDocument, error) {
+	document, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	content, err := attachmentContent(ctx, s.db, "child_documents", "document_id", id, s.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	document.Content = content
+	return document, nil
+}
+
+// Update updates the type, the file name and the expiry date of a document in the database.
+func (s *SQLChildDocumentStore) Update(ctx context.Context, document *models.ChildDocument) error {
+	fileName, err := Encrypt(document.FileName, s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt document file name: %w", err)
+	}
+	query := `UPDATE child_documents SET document_type = ?, file_name = ?, expires_on = ?, updated_at = CURRENT_TIMESTAMP WHERE document_id = ?`
+	result, err := s.db.ExecContext(ctx, query, document.DocumentType, fileName, document.ExpiresOn, document.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete deletes a document by ID from the database.
+func (s *SQLChildDocumentStore) Delete(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM child_documents WHERE document_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetForChild fetches the documents of a child from the database.
+func (s *SQLChildDocumentStore) GetForChild(ctx context.Context, childID int) ([]models.ChildDocument, error) {
+	query := `SELECT ` + childDocumentColumns + ` FROM child_documents WHERE child_id = ? ORDER BY document_type, created_at DESC, document_id DESC`
+	return s.queryDocuments(ctx, query, childID)
+}
+
+// GetAll fetches the documents of all children from the database.
+func (s *SQLChildDocumentStore) GetAll(ctx context.Context) ([]models.ChildDocument, error) {
+	return s.queryDocuments(ctx, `SELECT `+childDocumentColumns+` FROM child_documents ORDER BY child_id, document_type, document_id`)
+}
+
+func (s *SQLChildDocumentStore) queryDocuments(ctx context.Context, query string, args ...any) ([]models.ChildDocument, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	documents := []models.ChildDocument{}
+	for rows.Next() {
+		var document models.ChildDocument
+		var fileName string
+		var envelope sql.NullString
+		if err := rows.Scan(&document.ID, &document.ChildID, &document.DocumentType, &fileName, &document.ContentType, &document.SizeBytes, &document.ExpiresOn,
+			&document.UploadedByUserID, &envelope, &document.CreatedAt, &document.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if document.Envelope, err = decodeEnvelope(envelope); err != nil {
+			return nil, err
+		}
+		document.FileName, err = Decrypt(fileName, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt document file name: %w", err)
+		}
+		documents = append(documents, document)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
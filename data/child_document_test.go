@@ -0,0 +1,78 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLChildDocumentStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	require.NoError(t, err)
+
+	store := dal.ChildDocuments
+	content := []byte("%PDF-1.4 Betreuungsvertrag")
+	contractID, err := store.Create(t.Context(), &models.ChildDocument{ChildID: childID, DocumentType: models.ChildDocumentTypeCareContract,
+		FileName: "Vertrag Anna Müller.pdf", ContentType: "application/pdf", Content: content})
+	require.NoError(t, err)
+	expiresOn := models.NewDate(2025, time.January, 31)
+	attestID, err := store.Create(t.Context(), &models.ChildDocument{ChildID: childID, DocumentType: models.ChildDocumentTypeMedicalCertificate,
+		FileName: "Attest.jpg", ContentType: "image/jpeg", ExpiresOn: &expiresOn, Content: content})
+	require.NoError(t, err)
+	_, err = store.Create(t.Context(), &models.ChildDocument{ChildID: 999, DocumentType: models.ChildDocumentTypeOther, FileName: "x.pdf", Content: content})
+	assert.ErrorIs(t, err, data.ErrForeignKeyConstraint)
+
+	var storedFileName string
+	require.NoError(t, db.QueryRow(`SELECT file_name FROM child_documents WHERE document_id = ?`, contractID).Scan(&storedFileName))
+	assert.NotContains(t, storedFileName, "Anna", "the file name must be stored encrypted")
+
+	document, err := store.GetByID(t.Context(), contractID)
+	require.NoError(t, err)
+	assert.Equal(t, "Vertrag Anna Müller.pdf", document.FileName)
+	assert.Equal(t, "application/pdf", document.ContentType)
+	assert.Equal(t, len(content), document.SizeBytes)
+	assert.Nil(t, document.ExpiresOn)
+	assert.Nil(t, document.Content, "the PDF is only loaded on request")
+	document, err = store.GetWithContent(t.Context(), contractID)
+	require.NoError(t, err)
+	assert.Equal(t, content, document.Content)
+
+	documents, err := store.GetForChild(t.Context(), childID)
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+	assert.Equal(t, contractID, documents[0].ID)
+	assert.Equal(t, expiresOn, *documents[1].ExpiresOn)
+	assert.Equal(t, "image/jpeg", documents[1].ContentType)
+
+	document.DocumentType = models.ChildDocumentTypeOther
+	document.ExpiresOn = &expiresOn
+	require.NoError(t, store.Update(t.Context(), document))
+	document, err = store.GetWithContent(t.Context(), contractID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ChildDocumentTypeOther, document.DocumentType)
+	assert.Equal(t, expiresOn, *document.ExpiresOn)
+	assert.Equal(t, content, document.Content, "the PDF is kept")
+	assert.ErrorIs(t, store.Update(t.Context(), &models.ChildDocument{ID: 999}), data.ErrNotFound)
+
+	require.NoError(t, store.Delete(t.Context(), attestID))
+	assert.ErrorIs(t, store.Delete(t.Context(), attestID), data.ErrNotFound)
+
+	// Deleting the child deletes its documents
+	require.NoError(t, dal.Children.Delete(t.Context(), childID))
+	documents, err = store.GetAll(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, documents)
+}
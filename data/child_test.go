@@ -30,28 +30,28 @@ func TestSQLChildStore_Create(t *testing.T) {
 	child := &models.Child{
 		FirstName:                "John",
 		LastName:                 "Doe",
-		Birthdate:                time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+		Birthdate:                models.NewDate(2015, 1, 1),
 		AdmissionDate:            timePtr(time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)),
 		ExpectedSchoolEnrollment: timePtr(time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)),
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO children (first_name, last_name, birthdate, admission_date, expected_school_enrollment) VALUES (?, ?, ?, ?, ?)`)).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), *child.AdmissionDate, *child.ExpectedSchoolEnrollment).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO children (first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler) VALUES (?, ?, ?, ?, ?, ?) RETURNING child_id`)).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), *child.AdmissionDate, *child.ExpectedSchoolEnrollment, child.IsPreschooler).
+			WillReturnRows(sqlmock.NewRows([]string{"child_id"}).AddRow(1))
 
-		id, err := store.Create(child)
+		id, err := store.Create(t.Context(), child)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, id)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO children (first_name, last_name, birthdate, admission_date, expected_school_enrollment) VALUES (?, ?, ?, ?, ?)`)).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), *child.AdmissionDate, *child.ExpectedSchoolEnrollment).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO children (first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler) VALUES (?, ?, ?, ?, ?, ?) RETURNING child_id`)).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), *child.AdmissionDate, *child.ExpectedSchoolEnrollment, child.IsPreschooler).
 			WillReturnError(errors.New("db error"))
 
-		id, err := store.Create(child)
+		id, err := store.Create(t.Context(), child)
 		assert.Error(t, err)
 		assert.Equal(t, 0, id)
 		assert.Contains(t, err.Error(), "db error")
@@ -74,7 +74,7 @@ func TestSQLChildStore_GetByID(t *testing.T) {
 		ID:                       childID,
 		FirstName:                "John",
 		LastName:                 "Doe",
-		Birthdate:                time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+		Birthdate:                models.NewDate(2015, 1, 1),
 		AdmissionDate:            timePtr(time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)),
 		ExpectedSchoolEnrollment: timePtr(time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)),
 		CreatedAt:                time.Now().Truncate(time.Second),
@@ -84,22 +84,22 @@ func TestSQLChildStore_GetByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		encryptedFirstName, _ := data.Encrypt(expectedChild.FirstName, key)
 		encryptedLastName, _ := data.Encrypt(expectedChild.LastName, key)
-		encryptedBirthdate, _ := data.Encrypt(expectedChild.Birthdate.Format(time.RFC3339Nano), key)
+		encryptedBirthdate, _ := data.Encrypt(expectedChild.Birthdate.String(), key)
 
-		rows := sqlmock.NewRows([]string{"child_id", "first_name", "last_name", "birthdate", "admission_date", "expected_school_enrollment", "created_at", "updated_at"}).
-			AddRow(expectedChild.ID, encryptedFirstName, encryptedLastName, encryptedBirthdate, *expectedChild.AdmissionDate, *expectedChild.ExpectedSchoolEnrollment, expectedChild.CreatedAt, expectedChild.UpdatedAt)
+		rows := sqlmock.NewRows([]string{"child_id", "first_name", "last_name", "birthdate", "admission_date", "expected_school_enrollment", "is_preschooler", "archived_at", "created_at", "updated_at"}).
+			AddRow(expectedChild.ID, encryptedFirstName, encryptedLastName, encryptedBirthdate, *expectedChild.AdmissionDate, *expectedChild.ExpectedSchoolEnrollment, expectedChild.IsPreschooler, nil, expectedChild.CreatedAt, expectedChild.UpdatedAt)
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, created_at, updated_at FROM children WHERE child_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children WHERE child_id = ?`)).
 			WithArgs(childID).
 			WillReturnRows(rows)
 
-		child, err := store.GetByID(childID)
+		child, err := store.GetByID(t.Context(), childID)
 		assert.NoError(t, err)
 		assert.NotNil(t, child)
 		assert.Equal(t, expectedChild.ID, child.ID)
 		assert.Equal(t, expectedChild.FirstName, child.FirstName)
 		assert.Equal(t, expectedChild.LastName, child.LastName)
-		assert.WithinDuration(t, expectedChild.Birthdate, child.Birthdate, time.Second)
+		assert.Equal(t, expectedChild.Birthdate, child.Birthdate)
 		assert.WithinDuration(t, *expectedChild.AdmissionDate, *child.AdmissionDate, time.Second)
 		assert.WithinDuration(t, *expectedChild.ExpectedSchoolEnrollment, *child.ExpectedSchoolEnrollment, time.Second)
 		assert.WithinDuration(t, expectedChild.CreatedAt, child.CreatedAt, time.Second)
@@ -107,12 +107,30 @@ func TestSQLChildStore_GetByID(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	t.Run("birthdate stored as a timestamp", func(t *testing.T) {
+		encryptedFirstName, _ := data.Encrypt(expectedChild.FirstName, key)
+		encryptedLastName, _ := data.Encrypt(expectedChild.LastName, key)
+		// Older versions stored the local midnight of the birthdate, which is the day before in UTC.
+		encryptedBirthdate, _ := data.Encrypt("2015-01-01T00:00:00+01:00", key)
+
+		rows := sqlmock.NewRows([]string{"child_id", "first_name", "last_name", "birthdate", "admission_date", "expected_school_enrollment", "is_preschooler", "archived_at", "created_at", "updated_at"}).
+			AddRow(expectedChild.ID, encryptedFirstName, encryptedLastName, encryptedBirthdate, nil, nil, false, nil, expectedChild.CreatedAt, expectedChild.UpdatedAt)
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children WHERE child_id = ?`)).
+			WithArgs(childID).
+			WillReturnRows(rows)
+
+		child, err := store.GetByID(t.Context(), childID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.NewDate(2015, 1, 1), child.Birthdate)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, created_at, updated_at FROM children WHERE child_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children WHERE child_id = ?`)).
 			WithArgs(childID).
 			WillReturnError(sql.ErrNoRows)
 
-		child, err := store.GetByID(childID)
+		child, err := store.GetByID(t.Context(), childID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.Nil(t, child)
@@ -120,11 +138,11 @@ func TestSQLChildStore_GetByID(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, created_at, updated_at FROM children WHERE child_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children WHERE child_id = ?`)).
 			WithArgs(childID).
 			WillReturnError(errors.New("db error"))
 
-		child, err := store.GetByID(childID)
+		child, err := store.GetByID(t.Context(), childID)
 		assert.Error(t, err)
 		assert.Nil(t, child)
 		assert.Contains(t, err.Error(), "db error")
@@ -145,38 +163,38 @@ func TestSQLChildStore_Update(t *testing.T) {
 		ID:                       1,
 		FirstName:                "Updated John",
 		LastName:                 "Doe",
-		Birthdate:                time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+		Birthdate:                models.NewDate(2015, 1, 1),
 		AdmissionDate:            timePtr(time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)),
 		ExpectedSchoolEnrollment: timePtr(time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)),
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE children SET first_name = ?, last_name = ?, birthdate = ?, admission_date = ?, expected_school_enrollment = ? WHERE child_id = ?`)).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), *child.AdmissionDate, *child.ExpectedSchoolEnrollment, child.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE children SET first_name = ?, last_name = ?, birthdate = ?, admission_date = ?, expected_school_enrollment = ?, is_preschooler = ? WHERE child_id = ?`)).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), *child.AdmissionDate, *child.ExpectedSchoolEnrollment, child.IsPreschooler, child.ID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Update(child)
+		err := store.Update(t.Context(), child)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE children SET first_name = ?, last_name = ?, birthdate = ?, admission_date = ?, expected_school_enrollment = ? WHERE child_id = ?`)).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), *child.AdmissionDate, *child.ExpectedSchoolEnrollment, child.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE children SET first_name = ?, last_name = ?, birthdate = ?, admission_date = ?, expected_school_enrollment = ?, is_preschooler = ? WHERE child_id = ?`)).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), *child.AdmissionDate, *child.ExpectedSchoolEnrollment, child.IsPreschooler, child.ID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := store.Update(child)
+		err := store.Update(t.Context(), child)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE children SET first_name = ?, last_name = ?, birthdate = ?, admission_date = ?, expected_school_enrollment = ? WHERE child_id = ?`)).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), *child.AdmissionDate, *child.ExpectedSchoolEnrollment, child.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE children SET first_name = ?, last_name = ?, birthdate = ?, admission_date = ?, expected_school_enrollment = ?, is_preschooler = ? WHERE child_id = ?`)).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), *child.AdmissionDate, *child.ExpectedSchoolEnrollment, child.IsPreschooler, child.ID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Update(child)
+		err := store.Update(t.Context(), child)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -199,7 +217,7 @@ func TestSQLChildStore_Delete(t *testing.T) {
 			WithArgs(childID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Delete(childID)
+		err := store.Delete(t.Context(), childID)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -209,7 +227,7 @@ func TestSQLChildStore_Delete(t *testing.T) {
 			WithArgs(childID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := store.Delete(childID)
+		err := store.Delete(t.Context(), childID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -220,7 +238,7 @@ func TestSQLChildStore_Delete(t *testing.T) {
 			WithArgs(childID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Delete(childID)
+		err := store.Delete(t.Context(), childID)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -243,7 +261,7 @@ func TestSQLChildStore_GetAll(t *testing.T) {
 			ID:                       1,
 			FirstName:                "Child A",
 			LastName:                 "Last A",
-			Birthdate:                now.AddDate(-5, 0, 0),
+			Birthdate:                models.DateOf(now.AddDate(-5, 0, 0)),
 			AdmissionDate:            timePtr(now.AddDate(-2, 0, 0)),
 			ExpectedSchoolEnrollment: timePtr(now.AddDate(1, 0, 0)),
 			CreatedAt:                now.AddDate(-3, 0, 0),
@@ -253,7 +271,7 @@ func TestSQLChildStore_GetAll(t *testing.T) {
 			ID:                       2,
 			FirstName:                "Child B",
 			LastName:                 "Last B",
-			Birthdate:                now.AddDate(-6, 0, 0),
+			Birthdate:                models.DateOf(now.AddDate(-6, 0, 0)),
 			AdmissionDate:            timePtr(now.AddDate(-3, 0, 0)),
 			ExpectedSchoolEnrollment: timePtr(now.AddDate(0, 0, 0)),
 			CreatedAt:                now.AddDate(-4, 0, 0),
@@ -262,18 +280,19 @@ func TestSQLChildStore_GetAll(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"child_id", "first_name", "last_name", "birthdate", "admission_date", "expected_school_enrollment", "created_at", "updated_at"})
+		rows := sqlmock.NewRows([]string{"child_id", "first_name", "last_name", "birthdate", "admission_date", "expected_school_enrollment", "is_preschooler", "archived_at", "created_at", "updated_at"})
 		for _, child := range children {
 			encryptedFirstName, _ := data.Encrypt(child.FirstName, key)
 			encryptedLastName, _ := data.Encrypt(child.LastName, key)
-			encryptedBirthdate, _ := data.Encrypt(child.Birthdate.Format(time.RFC3339Nano), key)
-			rows.AddRow(child.ID, encryptedFirstName, encryptedLastName, encryptedBirthdate, *child.AdmissionDate, *child.ExpectedSchoolEnrollment, child.CreatedAt, child.UpdatedAt)
+			encryptedBirthdate, _ := data.Encrypt(child.Birthdate.String(), key)
+			rows.AddRow(child.ID, encryptedFirstName, encryptedLastName, encryptedBirthdate, *child.AdmissionDate, *child.ExpectedSchoolEnrollment, child.IsPreschooler, nil, child.CreatedAt, child.UpdatedAt)
 		}
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, created_at, updated_at FROM children`)).
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children WHERE archived_at IS NULL ORDER BY child_id`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children WHERE archived_at IS NULL ORDER BY child_id`)).
 			WillReturnRows(rows)
 
-		fetchedChildren, err := store.GetAll()
+		fetchedChildren, err := store.GetAll(t.Context())
 		assert.NoError(t, err)
 		assert.NotNil(t, fetchedChildren)
 		assert.Len(t, fetchedChildren, 2)
@@ -283,23 +302,95 @@ func TestSQLChildStore_GetAll(t *testing.T) {
 	})
 
 	t.Run("no children found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, created_at, updated_at FROM children`)).
-			WillReturnRows(sqlmock.NewRows([]string{"child_id", "first_name", "last_name", "birthdate", "admission_date", "expected_school_enrollment", "created_at", "updated_at"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children WHERE archived_at IS NULL ORDER BY child_id`)).
+			WillReturnRows(sqlmock.NewRows([]string{"child_id", "first_name", "last_name", "birthdate", "admission_date", "expected_school_enrollment", "is_preschooler", "archived_at", "created_at", "updated_at"}))
 
-		fetchedChildren, err := store.GetAll()
+		fetchedChildren, err := store.GetAll(t.Context())
 		assert.NoError(t, err)
 		assert.Len(t, fetchedChildren, 0)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, created_at, updated_at FROM children`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children WHERE archived_at IS NULL ORDER BY child_id`)).
 			WillReturnError(errors.New("db error"))
 
-		fetchedChildren, err := store.GetAll()
+		fetchedChildren, err := store.GetAll(t.Context())
 		assert.Error(t, err)
 		assert.Nil(t, fetchedChildren)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestSQLChildStore_GetAllIncludingArchived(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	store := data.NewSQLChildStore(db, key)
+
+	now := time.Now().Truncate(time.Second)
+	archivedAt := now.AddDate(0, -1, 0)
+
+	t.Run("success", func(t *testing.T) {
+		encryptedFirstName, _ := data.Encrypt("Child A", key)
+		encryptedLastName, _ := data.Encrypt("Last A", key)
+		encryptedBirthdate, _ := data.Encrypt(now.AddDate(-7, 0, 0).Format(time.RFC3339Nano), key)
+		rows := sqlmock.NewRows([]string{"child_id", "first_name", "last_name", "birthdate", "admission_date", "expected_school_enrollment", "is_preschooler", "archived_at", "created_at", "updated_at"}).
+			AddRow(1, encryptedFirstName, encryptedLastName, encryptedBirthdate, nil, nil, false, archivedAt, now, now)
+
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children ORDER BY child_id`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children ORDER BY child_id`)).
+			WillReturnRows(rows)
+
+		fetchedChildren, err := store.GetAllIncludingArchived(t.Context())
+		assert.NoError(t, err)
+		if assert.Len(t, fetchedChildren, 1) {
+			assert.Equal(t, "Child A", fetchedChildren[0].FirstName)
+			assert.NotNil(t, fetchedChildren[0].ArchivedAt)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT child_id, first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler, archived_at, created_at, updated_at FROM children ORDER BY child_id`)).
+			WillReturnError(errors.New("db error"))
+
+		fetchedChildren, err := store.GetAllIncludingArchived(t.Context())
+		assert.Error(t, err)
+		assert.Nil(t, fetchedChildren)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLChildStore_Count(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLChildStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	query := models.ListQuery{}.Where("group_id", models.OperatorEqual, 3).Where("archived_at", models.OperatorIsNull, nil)
+	statement := `SELECT COUNT(*) FROM children WHERE (SELECT group_id FROM group_children WHERE group_children.child_id = children.child_id) = ? AND archived_at IS NULL`
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectPrepare(regexp.QuoteMeta(statement))
+		mock.ExpectQuery(regexp.QuoteMeta(statement)).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(12))
+
+		count, err := store.Count(t.Context(), query)
+		assert.NoError(t, err)
+		assert.Equal(t, 12, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := store.Count(t.Context(), models.ListQuery{}.Where("first_name", models.OperatorEqual, "Anna"))
+		assert.ErrorIs(t, err, data.ErrInvalidInput)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
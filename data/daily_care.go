@@ -0,0 +1,160 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// DailyCareStore defines the interface for DailyCareLog data operations.
+type DailyCareStore interface {
+	// Upsert creates or replaces the logs of the children on their days, all or none of them are saved.
+	Upsert(ctx context.Context, logs []models.DailyCareLog) error
+	Get(ctx context.Context, childID int, day models.Date) (*models.DailyCareLog, error)
+	// GetForChild fetches the logs of a child between two days, both included, ordered by day.
+	GetForChild(ctx context.Context, childID int, from models.Date, to models.Date) ([]models.DailyCareLog, error)
+	// GetForDay fetches the logs of all children on a day.
+	GetForDay(ctx context.Context, day models.Date) ([]models.DailyCareLog, error)
+	Delete(ctx context.Context, childID int, day models.Date) error
+}
+
+// SQLDailyCareStore implements DailyCareStore using database/sql.
+type SQLDailyCareStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLDailyCareStore creates a new SQLDailyCareStore.
+func NewSQLDailyCareStore(db *sql.DB, encryptionKey []byte) *SQLDailyCareStore {
+	return &SQLDailyCareStore{db: db, encryptionKey: encryptionKey}
+}
+
+const dailyCareColumns = `care_log_id, child_id, care_date, breakfast, lunch, snack, nap_minutes, diaper_changes, toilet_visits, note, hidden_from_parents, recorded_by_user_id, created_at, updated_at`
+
+// Upsert inserts the logs into the database, replacing the logs of the same children and days.
+func (s *SQLDailyCareStore) Upsert(ctx context.Context, logs []models.DailyCareLog) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `INSERT INTO daily_care_logs (child_id, care_date, breakfast, lunch, snack, nap_minutes, diaper_changes, toilet_visits, note, hidden_from_parents, recorded_by_user_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (child_id, care_date) DO UPDATE SET
+			breakfast = excluded.breakfast,
+			lunch = excluded.lunch,
+			snack = excluded.snack,
+			nap_minutes = excluded.nap_minutes,
+			diaper_changes = excluded.diaper_changes,
+			toilet_visits = excluded.toilet_visits,
+			note = excluded.note,
+			hidden_from_parents = excluded.hidden_from_parents,
+			recorded_by_user_id = excluded.recorded_by_user_id,
+			updated_at = CURRENT_TIMESTAMP`
+	for _, log := range logs {
+		var note *string
+		if log.Note != nil {
+			encrypted, err := Encrypt(*log.Note, s.encryptionKey)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt daily care note: %w", err)
+			}
+			note = &encrypted
+		}
+		_, err := tx.ExecContext(ctx, query, log.ChildID, log.CareDate, log.Breakfast, log.Lunch, log.Snack, log.NapMinutes, log.DiaperChanges,
+			log.ToiletVisits, note, log.HiddenFromParents, log.RecordedByUserID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Get fetches the log of a child on a day from the database.
+func (s *SQLDailyCareStore) Get(ctx context.Context, childID int, day models.Date) (*models.DailyCareLog, error) {
+	query := `SELECT ` + dailyCareColumns + ` FROM daily_care_logs WHERE child_id = ? AND care_date = ?`
+	log, err := s.scanDailyCareLog(s.db.QueryRowContext(ctx, query, childID, day))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return log, nil
+}
+
+// GetForChild fetches the logs of a child between two days from the database.
+func (s *SQLDailyCareStore) GetForChild(ctx context.Context, childID int, from models.Date, to models.Date) ([]models.DailyCareLog, error) {
+	query := `SELECT ` + dailyCareColumns + ` FROM daily_care_logs WHERE child_id = ? AND care_date >= ? AND care_date <= ? ORDER BY care_date`
+	return s.queryDailyCareLogs(ctx, query, childID, from, to)
+}
+
+// GetForDay fetches the logs of all children on a day from the database.
+func (s *SQLDailyCareStore) GetForDay(ctx context.Context, day models.Date) ([]models.DailyCareLog, error) {
+	query := `SELECT ` + dailyCareColumns + ` FROM daily_care_logs WHERE care_date = ? ORDER BY child_id`
+	return s.queryDailyCareLogs(ctx, query, day)
+}
+
+// Delete deletes the log of a child on a day from the database.
+func (s *SQLDailyCareStore) Delete(ctx context.Context, childID int, day models.Date) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM daily_care_logs WHERE child_id = ? AND care_date = ?`, childID, day)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLDailyCareStore) queryDailyCareLogs(ctx context.Context, query string, args ...any) ([]models.DailyCareLog, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	logs := []models.DailyCareLog{}
+	for rows.Next() {
+		log, err := s.scanDailyCareLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, *log)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (s *SQLDailyCareStore) scanDailyCareLog(row rowScanner) (*models.DailyCareLog, error) {
+	log := &models.DailyCareLog{}
+	var note sql.NullString
+	var recordedByUserID sql.NullInt64
+	err := row.Scan(&log.ID, &log.ChildID, &log.CareDate, &log.Breakfast, &log.Lunch, &log.Snack, &log.NapMinutes, &log.DiaperChanges,
+		&log.ToiletVisits, &note, &log.HiddenFromParents, &recordedByUserID, &log.CreatedAt, &log.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if note.Valid {
+		decrypted, err := Decrypt(note.String, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt daily care note: %w", err)
+		}
+		log.Note = &decrypted
+	}
+	if recordedByUserID.Valid {
+		id := int(recordedByUserID.Int64)
+		log.RecordedByUserID = &id
+	}
+	return log, nil
+}
@@ -0,0 +1,75 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLDailyCareStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	annaID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2024, time.March, 15)})
+	require.NoError(t, err)
+	benID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Ben", LastName: "Springer", Birthdate: models.NewDate(2024, time.May, 2)})
+	require.NoError(t, err)
+
+	store := dal.DailyCare
+	monday := models.NewDate(2025, time.March, 3)
+	all, half := models.MealPortionAll, models.MealPortionHalf
+	nap, diapers := 90, 3
+	note := "Hat heute zum ersten Mal allein gegessen"
+	require.NoError(t, store.Upsert(t.Context(), []models.DailyCareLog{
+		{ChildID: annaID, CareDate: monday, Lunch: &all, NapMinutes: &nap, DiaperChanges: &diapers, Note: &note},
+		{ChildID: benID, CareDate: monday, Lunch: &half},
+	}))
+
+	var storedNote string
+	require.NoError(t, db.QueryRow(`SELECT note FROM daily_care_logs WHERE child_id = ?`, annaID).Scan(&storedNote))
+	assert.NotContains(t, storedNote, "allein", "the note must be stored encrypted")
+
+	log, err := store.Get(t.Context(), annaID, monday)
+	require.NoError(t, err)
+	assert.Equal(t, monday, log.CareDate)
+	assert.Equal(t, note, *log.Note)
+	assert.Equal(t, 90, *log.NapMinutes)
+	assert.Nil(t, log.Breakfast)
+
+	// Recording the day again replaces the log
+	require.NoError(t, store.Upsert(t.Context(), []models.DailyCareLog{{ChildID: annaID, CareDate: monday, Lunch: &half, HiddenFromParents: true}}))
+	replaced, err := store.Get(t.Context(), annaID, monday)
+	require.NoError(t, err)
+	assert.Equal(t, log.ID, replaced.ID)
+	assert.Equal(t, models.MealPortionHalf, *replaced.Lunch)
+	assert.Nil(t, replaced.Note)
+	assert.True(t, replaced.HiddenFromParents)
+
+	invalid := "plenty"
+	assert.Error(t, store.Upsert(t.Context(), []models.DailyCareLog{
+		{ChildID: benID, CareDate: monday.AddDays(1), Lunch: &all},
+		{ChildID: benID, CareDate: monday.AddDays(2), Lunch: &invalid},
+	}))
+	_, err = store.Get(t.Context(), benID, monday.AddDays(1))
+	assert.ErrorIs(t, err, data.ErrNotFound, "a failed upsert saves none of the logs")
+
+	day, err := store.GetForDay(t.Context(), monday)
+	require.NoError(t, err)
+	assert.Len(t, day, 2)
+	week, err := store.GetForChild(t.Context(), benID, monday, monday.AddDays(4))
+	require.NoError(t, err)
+	assert.Len(t, week, 1)
+
+	require.NoError(t, store.Delete(t.Context(), benID, monday))
+	assert.ErrorIs(t, store.Delete(t.Context(), benID, monday), data.ErrNotFound)
+}
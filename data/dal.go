@@ -7,27 +7,113 @@ import (
 
 // DAL represents the Data Access Layer.
 type DAL struct {
-	Users                UserStore
-	Children             ChildStore
-	Teachers             TeacherStore
-	Categories           CategoryStore
-	Assignments          AssignmentStore
-	DocumentationEntries DocumentationEntryStore
-	KitaMasterdata       KitaMasterdataStore
-	Processes            ProcessStore
+	Users                   UserStore
+	AuthSessions            AuthSessionStore
+	Children                ChildStore
+	Teachers                TeacherStore
+	Categories              CategoryStore
+	Assignments             AssignmentStore
+	DocumentationEntries    DocumentationEntryStore
+	DocumentationEvents     DocumentationEventStore
+	KitaMasterdata          KitaMasterdataStore
+	Processes               ProcessStore
+	Devices                 DeviceStore
+	NotificationPreferences NotificationPreferenceStore
+	Announcements           AnnouncementStore
+	SchoolYears             SchoolYearStore
+	RedactionProfiles       RedactionProfileStore
+	ApprovalDelegations     ApprovalDelegationStore
+	AuditLog                AuditLogStore
+	ValidationRules         ValidationRulesStore
+	DemoSnapshots           DemoSnapshotStore
+	Outbox                  OutboxStore
+	Bootstrap               BootstrapStore
+	Groups                  GroupStore
+	Schools                 SchoolStore
+	ImportJobs              ImportJobStore
+	Invitations             InvitationStore
+	QueryPlans              QueryPlanStore
+	Digests                 DigestStore
+	QualityReports          QualityReportStore
+	Terms                   TermsStore
+	StatusBanner            StatusBannerStore
+	EmailTemplates          EmailTemplateStore
+	Uptime                  UptimeStore
+	EditLocks               EditLockStore
+	ReportShareLinks        ReportShareLinkStore
+	BreakGlass              BreakGlassStore
+	SupportProviders        SupportProviderStore
+	DailyCare               DailyCareStore
+	Incidents               IncidentStore
+	Medications             MedicationStore
+	Gallery                 GalleryStore
+	ChildDocuments          ChildDocumentStore
+	Attendance              AttendanceStore
+	CareContracts           CareContractStore
+	Projects                ProjectStore
+	TeamMeetings            TeamMeetingStore
+	GroupHandovers          GroupHandoverStore
+	Benchmarks              BenchmarkStore
+	QuarantinedUploads      QuarantinedUploadStore
+	ResumableUploads        ResumableUploadStore
+	Fixtures                FixtureStore // Only used by the fixture server, it deletes all data
+	Driver                  Driver       // The engine of the database, the stores that depend on it were created for it
 }
 
-// NewDAL creates a new DAL instance.
-func NewDAL(db *sql.DB, encryptionKey []byte) *DAL {
+// NewDAL creates a new DAL instance for a database opened with the driver, see Open.
+func NewDAL(db *sql.DB, driver Driver, encryptionKey []byte) *DAL {
 	return &DAL{
-		Users:                NewSQLUserStore(db, encryptionKey),
-		Children:             NewSQLChildStore(db, encryptionKey),
-		Teachers:             NewSQLTeacherStore(db, encryptionKey),
-		Categories:           NewSQLCategoryStore(db),
-		Assignments:          NewSQLAssignmentStore(db),
-		DocumentationEntries: NewSQLDocumentationEntryStore(db, encryptionKey),
-		KitaMasterdata:       NewSQLKitaMasterdataStore(db),
-		Processes:            NewSQLProcessStore(db),
+		Users:                   NewSQLUserStore(db, encryptionKey),
+		AuthSessions:            NewSQLAuthSessionStore(db),
+		Children:                NewSQLChildStore(db, encryptionKey),
+		Teachers:                NewSQLTeacherStore(db, encryptionKey),
+		Categories:              NewSQLCategoryStore(db),
+		Assignments:             NewSQLAssignmentStore(db),
+		DocumentationEntries:    NewSQLDocumentationEntryStore(db, encryptionKey),
+		DocumentationEvents:     NewSQLDocumentationEventStore(db, encryptionKey),
+		KitaMasterdata:          NewSQLKitaMasterdataStore(db),
+		Processes:               NewSQLProcessStore(db),
+		Devices:                 NewSQLDeviceStore(db),
+		NotificationPreferences: NewSQLNotificationPreferenceStore(db, encryptionKey),
+		Announcements:           NewSQLAnnouncementStore(db),
+		SchoolYears:             NewSQLSchoolYearStore(db),
+		RedactionProfiles:       NewSQLRedactionProfileStore(db),
+		ApprovalDelegations:     NewSQLApprovalDelegationStore(db),
+		AuditLog:                NewSQLAuditLogStore(db),
+		ValidationRules:         NewSQLValidationRulesStore(db),
+		DemoSnapshots:           NewSQLDemoSnapshotStore(db, driver, encryptionKey),
+		Outbox:                  NewSQLOutboxStore(db, encryptionKey),
+		Bootstrap:               NewSQLBootstrapStore(db),
+		Groups:                  NewSQLGroupStore(db),
+		Schools:                 NewSQLSchoolStore(db),
+		ImportJobs:              NewSQLImportJobStore(db, encryptionKey),
+		Invitations:             NewSQLInvitationStore(db),
+		QueryPlans:              NewSQLQueryPlanStore(db, driver),
+		Digests:                 NewSQLDigestStore(db, encryptionKey),
+		QualityReports:          NewSQLQualityReportStore(db, encryptionKey),
+		Terms:                   NewSQLTermsStore(db),
+		StatusBanner:            NewSQLStatusBannerStore(db),
+		EmailTemplates:          NewSQLEmailTemplateStore(db),
+		Uptime:                  NewSQLUptimeStore(db),
+		EditLocks:               NewSQLEditLockStore(db),
+		ReportShareLinks:        NewSQLReportShareLinkStore(db, encryptionKey),
+		BreakGlass:              NewSQLBreakGlassStore(db, encryptionKey),
+		SupportProviders:        NewSQLSupportProviderStore(db, encryptionKey),
+		DailyCare:               NewSQLDailyCareStore(db, encryptionKey),
+		Incidents:               NewSQLIncidentStore(db, encryptionKey),
+		Medications:             NewSQLMedicationStore(db, encryptionKey),
+		Gallery:                 NewSQLGalleryStore(db, encryptionKey),
+		ChildDocuments:          NewSQLChildDocumentStore(db, encryptionKey),
+		Attendance:              NewSQLAttendanceStore(db, encryptionKey),
+		CareContracts:           NewSQLCareContractStore(db),
+		Projects:                NewSQLProjectStore(db),
+		TeamMeetings:            NewSQLTeamMeetingStore(db, encryptionKey),
+		GroupHandovers:          NewSQLGroupHandoverStore(db, encryptionKey),
+		Benchmarks:              NewSQLBenchmarkStore(db),
+		QuarantinedUploads:      NewSQLQuarantinedUploadStore(db, encryptionKey),
+		ResumableUploads:        NewSQLResumableUploadStore(db, encryptionKey),
+		Fixtures:                NewSQLFixtureStore(db, driver),
+		Driver:                  driver,
 	}
 }
 
@@ -0,0 +1,263 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DataMigration changes existing rows instead of the schema, e.g. to repair values written by older versions.
+// Each data migration runs once in its own transaction after the schema migrations.
+type DataMigration struct {
+	Name string
+	// Run migrates the rows and returns the number of changed values. The encryption key is needed by data
+	// migrations that read encrypted columns.
+	Run func(tx *sql.Tx, encryptionKey []byte) (int, error)
+	// SQLiteOnly marks data migrations that repair values only older SQLite databases hold. They are recorded
+	// as applied without running on PostgreSQL: its databases start from the baseline schema of version 51 with
+	// typed date and time columns and never held those values. New data migrations must run on both engines.
+	SQLiteOnly bool
+}
+
+// DataMigrationResult is a data migration applied by RunDataMigrations.
+type DataMigrationResult struct {
+	Name        string
+	RowsChanged int
+}
+
+// DataMigrations are the data migrations of the application in the order they are applied.
+// Names must never change, they record which migrations have been applied.
+var DataMigrations = []DataMigration{
+	{Name: "0001_normalize_date_formats", Run: normalizeDateFormats, SQLiteOnly: true},
+	// Observation dates were written as timestamps until they became models.Date.
+	{Name: "0002_normalize_date_only_values", Run: normalizeDateFormats, SQLiteOnly: true},
+	{Name: "0003_deduplicate_attachments", Run: deduplicateAttachments},
+}
+
+// RunDataMigrations applies the data migrations that have not been applied yet.
+func RunDataMigrations(db *sql.DB, migrations []DataMigration, encryptionKey []byte) ([]DataMigrationResult, error) {
+	results := []DataMigrationResult{}
+	for _, migration := range migrations {
+		applied, err := runDataMigration(db, migration, encryptionKey)
+		if err != nil {
+			return results, fmt.Errorf("data migration %s failed: %w", migration.Name, err)
+		}
+		if applied != nil {
+			results = append(results, *applied)
+		}
+	}
+	return results, nil
+}
+
+func runDataMigration(db *sql.DB, migration DataMigration, encryptionKey []byte) (*DataMigrationResult, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var exists int
+	err = tx.QueryRow("SELECT 1 FROM data_migrations WHERE name = ?", migration.Name).Scan(&exists)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	rowsChanged := 0
+	if !migration.SQLiteOnly || DriverOf(db) == DriverSQLite {
+		rowsChanged, err = migration.Run(tx, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tx.Exec("INSERT INTO data_migrations (name, rows_changed) VALUES (?, ?)", migration.Name, rowsChanged); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &DataMigrationResult{Name: migration.Name, RowsChanged: rowsChanged}, nil
+}
+
+const (
+	// storedDateFormat is the format of DATE columns.
+	storedDateFormat = "2006-01-02"
+	// storedTimestampFormat is the format of TIMESTAMP columns, it is the format of CURRENT_TIMESTAMP with optional
+	// fractional seconds. Timestamps are stored in UTC, so that they compare correctly as text.
+	storedTimestampFormat = "2006-01-02 15:04:05.999999999"
+)
+
+// legacyTimeFormats are the formats older versions stored dates and times in.
+var legacyTimeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999 -0700 MST", // time.Time.String, written by the driver for time.Time parameters
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02.01.2006",
+}
+
+// parseLegacyTime parses a stored date or time in any of the legacy formats.
+func parseLegacyTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	// Drop the monotonic clock reading of time.Time.String, e.g. " m=+0.000000001".
+	if i := strings.Index(value, " m="); i > 0 {
+		value = value[:i]
+	}
+	for _, format := range legacyTimeFormats {
+		if parsed, err := time.Parse(format, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// normalizeStoredTime returns the value in the canonical format of the declared column type.
+// Dates keep the calendar day they were written with, even if they carry a time zone.
+func normalizeStoredTime(value string, columnType string) (string, bool) {
+	parsed, ok := parseLegacyTime(value)
+	if !ok {
+		return "", false
+	}
+	if columnType == "DATE" {
+		return parsed.Format(storedDateFormat), true
+	}
+	return parsed.UTC().Format(storedTimestampFormat), true
+}
+
+// normalizeDateFormats rewrites all DATE, DATETIME and TIMESTAMP columns into a single format per type.
+// Older versions stored RFC3339 and YYYY-MM-DD values next to each other, which breaks comparisons and
+// scans of computed columns. Values that cannot be parsed, e.g. encrypted ones, are left unchanged.
+func normalizeDateFormats(tx *sql.Tx, _ []byte) (int, error) {
+	tables, err := queryStrings(context.Background(), tx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'schema_migrations' ORDER BY name")
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, table := range tables {
+		columns, err := timeColumns(tx, table)
+		if err != nil {
+			return changed, err
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		tableChanged, err := normalizeTableDates(tx, table, columns)
+		changed += tableChanged
+		if err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+// timeColumns returns the date and time columns of a table with their normalized declared type.
+func timeColumns(tx *sql.Tx, table string) (map[string]string, error) {
+	rows, err := tx.Query(fmt.Sprintf("SELECT name, type FROM pragma_table_info('%s')", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	columns := map[string]string{}
+	for rows.Next() {
+		var name, columnType string
+		if err := rows.Scan(&name, &columnType); err != nil {
+			return nil, err
+		}
+		switch strings.ToUpper(columnType) {
+		case "DATE":
+			columns[name] = "DATE"
+		case "DATETIME", "TIMESTAMP":
+			columns[name] = "TIMESTAMP"
+		}
+	}
+	return columns, rows.Err()
+}
+
+// normalizeTableDates rewrites the time columns of a table. The updated_at triggers of the table are dropped
+// while the rows are rewritten, a format change must not count as a modification.
+func normalizeTableDates(tx *sql.Tx, table string, columns map[string]string) (int, error) {
+	triggerNames, err := queryStrings(context.Background(), tx, "SELECT name FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ? ORDER BY name", table)
+	if err != nil {
+		return 0, err
+	}
+	triggers, err := queryStrings(context.Background(), tx, "SELECT sql FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ? ORDER BY name", table)
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range triggerNames {
+		if _, err := tx.Exec(fmt.Sprintf(`DROP TRIGGER "%s"`, name)); err != nil {
+			return 0, err
+		}
+	}
+
+	changed := 0
+	for column, columnType := range columns {
+		type update struct {
+			rowID int64
+			value string
+		}
+		updates := []update{}
+		// The cast keeps the driver from parsing the value of the declared column type into a time.Time.
+		rows, err := tx.Query(fmt.Sprintf(`SELECT rowid, CAST("%s" AS TEXT) FROM "%s" WHERE typeof("%s") = 'text'`, column, table, column))
+		if err != nil {
+			return changed, err
+		}
+		for rows.Next() {
+			var rowID int64
+			var value string
+			if err := rows.Scan(&rowID, &value); err != nil {
+				rows.Close() //nolint:errcheck
+				return changed, err
+			}
+			if normalized, ok := normalizeStoredTime(value, columnType); ok && normalized != value {
+				updates = append(updates, update{rowID: rowID, value: normalized})
+			}
+		}
+		rows.Close() //nolint:errcheck
+		if err := rows.Err(); err != nil {
+			return changed, err
+		}
+
+		for _, u := range updates {
+			if _, err := tx.Exec(fmt.Sprintf(`UPDATE "%s" SET "%s" = ? WHERE rowid = ?`, table, column), u.value, u.rowID); err != nil {
+				return changed, err
+			}
+		}
+		changed += len(updates)
+	}
+
+	for _, trigger := range triggers {
+		if _, err := tx.Exec(trigger); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+func queryStrings(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,60 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestRunDataMigrations_NormalizeDateFormats(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+
+	_, err = db.Exec(`INSERT INTO teachers (teacher_id, first_name, last_name, username, updated_at) VALUES (1, 'Maria', 'Müller', 'mmueller', '2023-01-02 03:04:05')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO children (child_id, first_name, last_name, birthdate, admission_date) VALUES (1, 'x', 'y', 'encrypted', '2021-08-01T00:00:00Z')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO categories (category_id, category_name) VALUES (1, 'Sprache')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO documentation_entries (entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, created_at, updated_at)
+		VALUES (1, 1, 1, 1, '2023-05-04 00:00:00 +0200 CEST', 'text', 0, '2023-05-04T10:00:00+02:00', '2023-05-04 08:00:00'),
+		       (2, 1, 1, 1, '2023-05-05', 'text', 0, '2023-05-05 08:00:00', '2023-05-05 08:00:00')`)
+	require.NoError(t, err)
+
+	results, err := data.RunDataMigrations(db, data.DataMigrations, []byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, "0001_normalize_date_formats", results[0].Name)
+	assert.Equal(t, 3, results[0].RowsChanged)
+	assert.Equal(t, "0002_normalize_date_only_values", results[1].Name)
+	assert.Equal(t, 0, results[1].RowsChanged)
+
+	text := func(query string) string {
+		var value string
+		require.NoError(t, db.QueryRow(query).Scan(&value))
+		return value
+	}
+	assert.Equal(t, "2023-05-04", text("SELECT CAST(observation_date AS TEXT) FROM documentation_entries WHERE entry_id = 1"))
+	assert.Equal(t, "2023-05-04 08:00:00", text("SELECT CAST(created_at AS TEXT) FROM documentation_entries WHERE entry_id = 1"))
+	assert.Equal(t, "2023-05-04 08:00:00", text("SELECT CAST(updated_at AS TEXT) FROM documentation_entries WHERE entry_id = 1"), "the updated_at trigger must not fire")
+	assert.Equal(t, "2021-08-01", text("SELECT CAST(admission_date AS TEXT) FROM children WHERE child_id = 1"))
+	assert.Equal(t, "encrypted", text("SELECT birthdate FROM children WHERE child_id = 1"))
+
+	// The trigger is restored.
+	_, err = db.Exec("UPDATE documentation_entries SET observation_description = 'changed' WHERE entry_id = 2")
+	require.NoError(t, err)
+	assert.NotEqual(t, "2023-05-05 08:00:00", text("SELECT CAST(updated_at AS TEXT) FROM documentation_entries WHERE entry_id = 2"))
+
+	// Applied migrations are skipped.
+	results, err = data.RunDataMigrations(db, data.DataMigrations, []byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,99 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DemoSnapshot is a private copy of the database used by the demo mode.
+type DemoSnapshot struct {
+	DAL       *DAL
+	CreatedAt time.Time
+	db        *sql.DB
+	path      string
+}
+
+// Close closes the snapshot database and removes its file.
+func (snapshot *DemoSnapshot) Close() error {
+	if snapshot.db == nil {
+		return nil
+	}
+	closeErr := snapshot.db.Close()
+	removeErr := os.Remove(snapshot.path)
+	if errors.Is(removeErr, os.ErrNotExist) {
+		removeErr = nil
+	}
+	return errors.Join(closeErr, removeErr)
+}
+
+// DemoSnapshotStore defines the interface for creating demo mode snapshots.
+type DemoSnapshotStore interface {
+	Create(ctx context.Context, now time.Time) (*DemoSnapshot, error)
+}
+
+// SQLDemoSnapshotStore implements DemoSnapshotStore by copying the SQLite database into a temporary file.
+type SQLDemoSnapshotStore struct {
+	db            *sql.DB
+	driver        Driver
+	encryptionKey []byte
+}
+
+// NewSQLDemoSnapshotStore creates a new SQLDemoSnapshotStore.
+func NewSQLDemoSnapshotStore(db *sql.DB, driver Driver, encryptionKey []byte) *SQLDemoSnapshotStore {
+	return &SQLDemoSnapshotStore{db: db, driver: driver, encryptionKey: encryptionKey}
+}
+
+// demoSnapshotCleanup removes data that must not be reachable from the demo mode:
+// archived children, device tokens (so that demo actions never push to real devices), the audit trail,
+// the documentation events, which carry the original observations, and the outbox with pending notifications.
+var demoSnapshotCleanup = []string{
+	`DELETE FROM children WHERE archived_at IS NOT NULL`,
+	`DELETE FROM devices`,
+	`DELETE FROM audit_log`,
+	`DELETE FROM documentation_events`,
+	`DELETE FROM outbox`,
+}
+
+// Create copies the database into a temporary file and opens it, recording now as its creation time. Snapshots are copies of SQLite databases, they
+// are not available on PostgreSQL and ErrUnsupportedDriver is returned there.
+func (s *SQLDemoSnapshotStore) Create(ctx context.Context, now time.Time) (*DemoSnapshot, error) {
+	if s.driver != DriverSQLite {
+		return nil, fmt.Errorf("demo snapshots require the %s driver: %w", DriverSQLite, ErrUnsupportedDriver)
+	}
+	file, err := os.CreateTemp("", "kitadoc_demo_*.db")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create demo snapshot file: %w", err)
+	}
+	path := file.Name()
+	// VACUUM INTO refuses to overwrite existing files, the temporary file only reserves the name.
+	if err := errors.Join(file.Close(), os.Remove(path)); err != nil {
+		return nil, fmt.Errorf("failed to prepare demo snapshot file: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, `VACUUM INTO ?`, path); err != nil {
+		return nil, fmt.Errorf("failed to copy database for demo snapshot: %w", err)
+	}
+
+	snapshotDB, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)")
+	if err != nil {
+		os.Remove(path) //nolint:errcheck
+		return nil, fmt.Errorf("failed to open demo snapshot: %w", err)
+	}
+	snapshot := &DemoSnapshot{
+		DAL:       NewDAL(snapshotDB, DriverSQLite, s.encryptionKey),
+		CreatedAt: now,
+		db:        snapshotDB,
+		path:      path,
+	}
+	for _, statement := range demoSnapshotCleanup {
+		if _, err := snapshotDB.ExecContext(ctx, statement); err != nil {
+			snapshot.Close() //nolint:errcheck
+			return nil, fmt.Errorf("failed to clean up demo snapshot: %w", err)
+		}
+	}
+	return snapshot, nil
+}
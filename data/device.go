@@ -0,0 +1,111 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"kitadoc-backend/models"
+)
+
+// DeviceStore defines the interface for Device data operations.
+type DeviceStore interface {
+	Upsert(ctx context.Context, device *models.Device) (int, error)
+	GetByID(ctx context.Context, id int) (*models.Device, error)
+	GetAllForUser(ctx context.Context, userID int) ([]models.Device, error)
+	Delete(ctx context.Context, id int) error
+	DeleteByToken(ctx context.Context, token string) error
+}
+
+// SQLDeviceStore implements DeviceStore using database/sql.
+type SQLDeviceStore struct {
+	db *sql.DB
+}
+
+// NewSQLDeviceStore creates a new SQLDeviceStore.
+func NewSQLDeviceStore(db *sql.DB) *SQLDeviceStore {
+	return &SQLDeviceStore{db: db}
+}
+
+// Upsert registers a device token. Registering a token of the same user again updates it, a token
+// that is registered to another user returns ErrConflict instead of being moved, so that nobody can
+// take over the notifications of someone else. On a shared tablet the app unregisters the token on logout.
+func (s *SQLDeviceStore) Upsert(ctx context.Context, device *models.Device) (int, error) {
+	query := `INSERT INTO devices (user_id, platform, token) VALUES (?, ?, ?)
+		ON CONFLICT(token) DO UPDATE SET platform = excluded.platform WHERE devices.user_id = excluded.user_id
+		RETURNING device_id`
+	var id int
+	err := s.db.QueryRowContext(ctx, query, device.UserID, device.Platform, device.Token).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrConflict
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a device by ID from the database.
+func (s *SQLDeviceStore) GetByID(ctx context.Context, id int) (*models.Device, error) {
+	query := `SELECT device_id, user_id, platform, token, created_at, updated_at FROM devices WHERE device_id = ?`
+	row := s.db.QueryRowContext(ctx, query, id)
+	device := &models.Device{}
+	err := row.Scan(&device.ID, &device.UserID, &device.Platform, &device.Token, &device.CreatedAt, &device.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return device, nil
+}
+
+// GetAllForUser fetches all devices registered by a specific user.
+func (s *SQLDeviceStore) GetAllForUser(ctx context.Context, userID int) ([]models.Device, error) {
+	query := `SELECT device_id, user_id, platform, token, created_at, updated_at FROM devices WHERE user_id = ? ORDER BY device_id`
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var devices []models.Device
+	for rows.Next() {
+		device := models.Device{}
+		err := rows.Scan(&device.ID, &device.UserID, &device.Platform, &device.Token, &device.CreatedAt, &device.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// Delete deletes a device by ID from the database.
+func (s *SQLDeviceStore) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM devices WHERE device_id = ?`
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteByToken deletes a device by its push token. Used to prune tokens the push service reports as gone.
+func (s *SQLDeviceStore) DeleteByToken(ctx context.Context, token string) error {
+	query := `DELETE FROM devices WHERE token = ?`
+	_, err := s.db.ExecContext(ctx, query, token)
+	return err
+}
@@ -0,0 +1,120 @@
+package data_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLDeviceStore_Upsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLDeviceStore(db)
+	device := &models.Device{UserID: 1, Platform: models.DevicePlatformFCM, Token: "token"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO devices (user_id, platform, token) VALUES (?, ?, ?)`)).
+		WithArgs(device.UserID, device.Platform, device.Token).
+		WillReturnRows(sqlmock.NewRows([]string{"device_id"}).AddRow(7))
+
+	id, err := store.Upsert(t.Context(), device)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLDeviceStore_UpsertTokenOfAnotherUser(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	owner, err := dal.Users.Create(t.Context(), &models.User{Username: "erzieherin", PasswordHash: "hash", Role: string(data.RoleTeacher)})
+	require.NoError(t, err)
+	other, err := dal.Users.Create(t.Context(), &models.User{Username: "praktikant", PasswordHash: "hash", Role: string(data.RoleTeacher)})
+	require.NoError(t, err)
+
+	store := data.NewSQLDeviceStore(db)
+	id, err := store.Upsert(t.Context(), &models.Device{UserID: owner, Platform: models.DevicePlatformFCM, Token: "shared-token"})
+	require.NoError(t, err)
+
+	again, err := store.Upsert(t.Context(), &models.Device{UserID: owner, Platform: models.DevicePlatformFCM, Token: "shared-token"})
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+
+	_, err = store.Upsert(t.Context(), &models.Device{UserID: other, Platform: models.DevicePlatformFCM, Token: "shared-token"})
+	assert.ErrorIs(t, err, data.ErrConflict)
+
+	device, err := store.GetByID(t.Context(), id)
+	require.NoError(t, err)
+	assert.Equal(t, owner, device.UserID)
+}
+
+func TestSQLDeviceStore_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLDeviceStore(db)
+	query := regexp.QuoteMeta(`SELECT device_id, user_id, platform, token, created_at, updated_at FROM devices WHERE device_id = ?`)
+
+	t.Run("success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(query).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"device_id", "user_id", "platform", "token", "created_at", "updated_at"}).
+				AddRow(1, 2, models.DevicePlatformWebPush, "https://push.example.com/abc", now, now))
+
+		device, err := store.GetByID(t.Context(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, device.UserID)
+		assert.Equal(t, models.DevicePlatformWebPush, device.Platform)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(2).WillReturnError(sql.ErrNoRows)
+
+		device, err := store.GetByID(t.Context(), 2)
+		assert.ErrorIs(t, err, data.ErrNotFound)
+		assert.Nil(t, device)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLDeviceStore_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLDeviceStore(db)
+	query := regexp.QuoteMeta(`DELETE FROM devices WHERE device_id = ?`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		assert.NoError(t, store.Delete(t.Context(), 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+		assert.ErrorIs(t, store.Delete(t.Context(), 2), data.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,73 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+
+	"kitadoc-backend/models"
+)
+
+// DigestStore defines the interface for recording the weekly documentation digests sent to users.
+type DigestStore interface {
+	// GetSent fetches the IDs of the users the digest of a week has been sent to.
+	GetSent(ctx context.Context, weekStart models.Date) (map[int]bool, error)
+	// RecordSent records the digest of a week for a user and enqueues its email, unless it has been recorded
+	// before. It reports whether the digest was recorded.
+	RecordSent(ctx context.Context, userID int, weekStart models.Date, message models.OutboxMessage) (bool, error)
+}
+
+// SQLDigestStore implements DigestStore using database/sql.
+type SQLDigestStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLDigestStore creates a new SQLDigestStore.
+func NewSQLDigestStore(db *sql.DB, encryptionKey []byte) *SQLDigestStore {
+	return &SQLDigestStore{db: db, encryptionKey: encryptionKey}
+}
+
+// GetSent fetches the IDs of the users the digest of a week has been sent to.
+func (s *SQLDigestStore) GetSent(ctx context.Context, weekStart models.Date) (map[int]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT user_id FROM documentation_digests WHERE week_start = ?`, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	sent := make(map[int]bool)
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		sent[userID] = true
+	}
+	return sent, rows.Err()
+}
+
+// RecordSent records the digest of a week for a user together with its email in one transaction, so that
+// concurrent senders enqueue it once.
+func (s *SQLDigestStore) RecordSent(ctx context.Context, userID int, weekStart models.Date, message models.OutboxMessage) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO documentation_digests (user_id, week_start) VALUES (?, ?) ON CONFLICT DO NOTHING`, userID, weekStart)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+	if err := enqueueOutboxMessages(ctx, tx, s.encryptionKey, []models.OutboxMessage{message}); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
@@ -0,0 +1,81 @@
+package data_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLDigestStore_GetSent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLDigestStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	weekStart := models.NewDate(2025, time.March, 10)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT user_id FROM documentation_digests WHERE week_start = ?`)).
+		WithArgs("2025-03-10").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1).AddRow(3))
+
+	sent, err := store.GetSent(t.Context(), weekStart)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]bool{1: true, 3: true}, sent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLDigestStore_RecordSent(t *testing.T) {
+	weekStart := models.NewDate(2025, time.March, 10)
+	message := models.OutboxMessage{Channel: models.OutboxChannelEmail, Payload: []byte(`{"to":"m.mueller@kita.example"}`)}
+
+	t.Run("records and enqueues", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close() //nolint:errcheck
+		store := data.NewSQLDigestStore(db, []byte("0123456789abcdef0123456789abcdef"))
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO documentation_digests (user_id, week_start) VALUES (?, ?) ON CONFLICT DO NOTHING`)).
+			WithArgs(1, "2025-03-10").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO outbox (channel, payload, status, next_attempt_at) VALUES (?, ?, ?, ?) RETURNING message_id`)).
+			WithArgs(models.OutboxChannelEmail, sqlmock.AnyArg(), models.OutboxStatusPending, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow(7))
+		mock.ExpectCommit()
+
+		recorded, err := store.RecordSent(t.Context(), 1, weekStart, message)
+		assert.NoError(t, err)
+		assert.True(t, recorded)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already recorded", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close() //nolint:errcheck
+		store := data.NewSQLDigestStore(db, []byte("0123456789abcdef0123456789abcdef"))
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO documentation_digests (user_id, week_start) VALUES (?, ?) ON CONFLICT DO NOTHING`)).
+			WithArgs(1, "2025-03-10").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		recorded, err := store.RecordSent(t.Context(), 1, weekStart, message)
+		assert.NoError(t, err)
+		assert.False(t, recorded)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,33 +1,49 @@
 package data
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"kitadoc-backend/models"
 )
 
 // DocumentationEntryStore defines the interface for DocumentationEntry data operations.
 type DocumentationEntryStore interface {
-	Create(entry *models.DocumentationEntry) (int, error)
-	GetByID(id int) (*models.DocumentationEntry, error)
-	Update(entry *models.DocumentationEntry) error
-	Delete(id int) error
-	GetAllForChild(childID int) ([]models.DocumentationEntry, error)
-	ApproveEntry(entryID int, approvedByTeacherID int) error
+	Create(ctx context.Context, entry *models.DocumentationEntry) (int, error)
+	// CreateMany inserts the entries in one transaction and returns their IDs in the same order.
+	CreateMany(ctx context.Context, entries []models.DocumentationEntry) ([]int, error)
+	GetByID(ctx context.Context, id int) (*models.DocumentationEntry, error)
+	Update(ctx context.Context, entry *models.DocumentationEntry) error
+	Delete(ctx context.Context, id int) error
+	GetAllForChild(ctx context.Context, childID int) ([]models.DocumentationEntry, error)
+	// List fetches the documentation entries matching the query, by default the latest observations first.
+	List(ctx context.Context, query models.ListQuery) ([]models.DocumentationEntry, error)
+	// Count counts the documentation entries matching the conditions of the query.
+	Count(ctx context.Context, query models.ListQuery) (int, error)
+	ApproveEntry(ctx context.Context, entryID int, approvedByTeacherID int, outbox []models.OutboxMessage) error
+	RecordReport(ctx context.Context, report *models.GeneratedReport) error
+	GetReportsForChild(ctx context.Context, childID int) ([]models.GeneratedReport, error)
+	GetReportByID(ctx context.Context, reportID int) (*models.GeneratedReport, error)
+	GetArchivedReportIDsSince(ctx context.Context, since time.Time) ([]int, error)
+	GetLastObservationDates(ctx context.Context) (map[int]models.Date, error)
+	Unlock(ctx context.Context, entryID int) error
 }
 
 // SQLDocumentationEntryStore implements DocumentationEntryStore using database/sql.
 type SQLDocumentationEntryStore struct {
 	db            *sql.DB
+	statements    *statementCache
 	encryptionKey []byte
 }
 
 // NewSQLDocumentationEntryStore creates a new SQLDocumentationEntryStore.
 func NewSQLDocumentationEntryStore(db *sql.DB, encryptionKey []byte) *SQLDocumentationEntryStore {
-	return &SQLDocumentationEntryStore{db: db, encryptionKey: encryptionKey}
+	return &SQLDocumentationEntryStore{db: db, statements: newStatementCache(db, maxCachedStatements), encryptionKey: encryptionKey}
 }
 
 // toDocumentationEntryDB converts a models.DocumentationEntry to a models.DocumentationEntryDB and encrypts PII fields.
@@ -58,6 +74,19 @@ func toDocumentationEntryDB(entry *models.DocumentationEntry, key []byte) (*mode
 			}
 		}
 	}
+
+	// Structured form data can hold as much about the child as the free text, so it is encrypted as a whole.
+	if entry.StructuredData != nil {
+		encoded, err := json.Marshal(entry.StructuredData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode structured data: %w", err)
+		}
+		encrypted, err := Encrypt(string(encoded), key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt field StructuredData: %w", err)
+		}
+		dbEntry.StructuredData = &encrypted
+	}
 	return dbEntry, nil
 }
 
@@ -95,34 +124,78 @@ func fromDocumentationEntryDB(dbEntry *models.DocumentationEntryDB, key []byte)
 			}
 		}
 	}
+
+	if dbEntry.StructuredData != nil {
+		decrypted, err := Decrypt(*dbEntry.StructuredData, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt field StructuredData: %w", err)
+		}
+		if err := json.Unmarshal([]byte(decrypted), &entry.StructuredData); err != nil {
+			return nil, fmt.Errorf("failed to decode structured data: %w", err)
+		}
+	}
 	return entry, nil
 }
 
+const insertDocumentationEntryQuery = `INSERT INTO documentation_entries (child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, revision_of_entry_id, project_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING entry_id`
+
+func insertDocumentationEntryArgs(dbEntry *models.DocumentationEntryDB) []any {
+	return []any{dbEntry.ChildID, dbEntry.TeacherID, dbEntry.CategoryID, dbEntry.ObservationDate, dbEntry.ObservationDescription, dbEntry.StructuredData, dbEntry.IsApproved, dbEntry.ApprovedByUserID, dbEntry.RevisionOfEntryID, dbEntry.ProjectID, dbEntry.CreatedAt, dbEntry.UpdatedAt}
+}
+
 // Create inserts a new documentation entry into the database.
-func (s *SQLDocumentationEntryStore) Create(entry *models.DocumentationEntry) (int, error) {
+func (s *SQLDocumentationEntryStore) Create(ctx context.Context, entry *models.DocumentationEntry) (int, error) {
 	dbEntry, err := toDocumentationEntryDB(entry, s.encryptionKey)
 	if err != nil {
 		return 0, err
 	}
 
-	query := `INSERT INTO documentation_entries (child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	result, err := s.db.Exec(query, dbEntry.ChildID, dbEntry.TeacherID, dbEntry.CategoryID, dbEntry.ObservationDate, dbEntry.ObservationDescription, dbEntry.IsApproved, dbEntry.ApprovedByUserID, dbEntry.CreatedAt, dbEntry.UpdatedAt)
-	if err != nil {
+	var id int
+	if err := s.statements.queryRow(ctx, insertDocumentationEntryQuery, insertDocumentationEntryArgs(dbEntry)...).Scan(&id); err != nil {
 		return 0, err
 	}
-	id, err := result.LastInsertId()
+	return id, nil
+}
+
+// CreateMany inserts the entries in one transaction and returns their IDs in the same order.
+// Either all entries are inserted or none.
+func (s *SQLDocumentationEntryStore) CreateMany(ctx context.Context, entries []models.DocumentationEntry) ([]int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return int(id), nil
+	defer tx.Rollback() //nolint:errcheck
+
+	stmt, err := tx.PrepareContext(ctx, insertDocumentationEntryQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close() //nolint:errcheck
+
+	ids := make([]int, len(entries))
+	for i := range entries {
+		dbEntry, err := toDocumentationEntryDB(&entries[i], s.encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		var id int
+		if err := stmt.QueryRowContext(ctx, insertDocumentationEntryArgs(dbEntry)...).Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
 }
 
 // GetByID fetches a documentation entry by ID from the database.
-func (s *SQLDocumentationEntryStore) GetByID(id int) (*models.DocumentationEntry, error) {
-	query := `SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at FROM documentation_entries WHERE entry_id = ?`
-	row := s.db.QueryRow(query, id)
+func (s *SQLDocumentationEntryStore) GetByID(ctx context.Context, id int) (*models.DocumentationEntry, error) {
+	query := `SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries WHERE entry_id = ?`
+	row := s.statements.queryRow(ctx, query, id)
 	dbEntry := &models.DocumentationEntryDB{}
-	err := row.Scan(&dbEntry.ID, &dbEntry.ChildID, &dbEntry.TeacherID, &dbEntry.CategoryID, &dbEntry.ObservationDate, &dbEntry.ObservationDescription, &dbEntry.IsApproved, &dbEntry.ApprovedByUserID, &dbEntry.CreatedAt, &dbEntry.UpdatedAt)
+	err := row.Scan(&dbEntry.ID, &dbEntry.ChildID, &dbEntry.TeacherID, &dbEntry.CategoryID, &dbEntry.ObservationDate, &dbEntry.ObservationDescription, &dbEntry.StructuredData, &dbEntry.IsApproved, &dbEntry.ApprovedByUserID, &dbEntry.LockedAt, &dbEntry.RevisionOfEntryID, &dbEntry.ProjectID, &dbEntry.CreatedAt, &dbEntry.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -133,15 +206,15 @@ func (s *SQLDocumentationEntryStore) GetByID(id int) (*models.DocumentationEntry
 	return fromDocumentationEntryDB(dbEntry, s.encryptionKey)
 }
 
-// Update updates an existing documentation entry in the database.
-func (s *SQLDocumentationEntryStore) Update(entry *models.DocumentationEntry) error {
+// Update updates an existing documentation entry in the database. Locked entries are not changed, ErrLocked is returned instead.
+func (s *SQLDocumentationEntryStore) Update(ctx context.Context, entry *models.DocumentationEntry) error {
 	dbEntry, err := toDocumentationEntryDB(entry, s.encryptionKey)
 	if err != nil {
 		return err
 	}
 
-	query := `UPDATE documentation_entries SET child_id = ?, documenting_teacher_id = ?, category_id = ?, observation_date = ?, observation_description = ?, approved = ?, approved_by_teacher_id = ?, updated_at = ? WHERE entry_id = ?`
-	result, err := s.db.Exec(query, dbEntry.ChildID, dbEntry.TeacherID, dbEntry.CategoryID, dbEntry.ObservationDate, dbEntry.ObservationDescription, dbEntry.IsApproved, dbEntry.ApprovedByUserID, dbEntry.UpdatedAt, dbEntry.ID)
+	query := `UPDATE documentation_entries SET child_id = ?, documenting_teacher_id = ?, category_id = ?, observation_date = ?, observation_description = ?, structured_data = ?, approved = ?, approved_by_teacher_id = ?, project_id = ?, updated_at = ? WHERE entry_id = ? AND locked_at IS NULL`
+	result, err := s.statements.exec(ctx, query, dbEntry.ChildID, dbEntry.TeacherID, dbEntry.CategoryID, dbEntry.ObservationDate, dbEntry.ObservationDescription, dbEntry.StructuredData, dbEntry.IsApproved, dbEntry.ApprovedByUserID, dbEntry.ProjectID, dbEntry.UpdatedAt, dbEntry.ID)
 	if err != nil {
 		return err
 	}
@@ -150,15 +223,15 @@ func (s *SQLDocumentationEntryStore) Update(entry *models.DocumentationEntry) er
 		return err
 	}
 	if rowsAffected == 0 {
-		return ErrNotFound
+		return s.lockedOrNotFound(ctx, dbEntry.ID)
 	}
 	return nil
 }
 
-// Delete deletes a documentation entry by ID from the database.
-func (s *SQLDocumentationEntryStore) Delete(id int) error {
-	query := `DELETE FROM documentation_entries WHERE entry_id = ?`
-	result, err := s.db.Exec(query, id)
+// Delete deletes a documentation entry by ID from the database. Locked entries are not deleted, ErrLocked is returned instead.
+func (s *SQLDocumentationEntryStore) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM documentation_entries WHERE entry_id = ? AND locked_at IS NULL`
+	result, err := s.statements.exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -167,15 +240,54 @@ func (s *SQLDocumentationEntryStore) Delete(id int) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return ErrNotFound
+		return s.lockedOrNotFound(ctx, id)
 	}
 	return nil
 }
 
+// lockedOrNotFound tells why an entry was not changed: ErrLocked if it exists, ErrNotFound otherwise.
+func (s *SQLDocumentationEntryStore) lockedOrNotFound(ctx context.Context, id int) error {
+	var exists bool
+	err := s.statements.queryRow(ctx, `SELECT EXISTS (SELECT 1 FROM documentation_entries WHERE entry_id = ?)`, id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrLocked
+	}
+	return ErrNotFound
+}
+
+const documentationEntrySelect = `SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries`
+
+// documentationEntryListColumns are the fields documentation entries can be filtered and sorted by.
+// Descriptions and structured data are encrypted.
+var documentationEntryListColumns = listColumns{
+	"id":                     "entry_id",
+	"child_id":               "child_id",
+	"teacher_id":             "documenting_teacher_id",
+	"category_id":            "category_id",
+	"observation_date":       "observation_date",
+	"is_approved":            "approved",
+	"approved_by_teacher_id": "approved_by_teacher_id",
+	"locked_at":              "locked_at",
+	"project_id":             "project_id",
+	"created_at":             "created_at",
+	"updated_at":             "updated_at",
+}
+
 // GetAllForChild fetches all documentation entries for a specific child.
-func (s *SQLDocumentationEntryStore) GetAllForChild(childID int) ([]models.DocumentationEntry, error) {
-	query := `SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at FROM documentation_entries WHERE child_id = ? ORDER BY observation_date DESC`
-	rows, err := s.db.Query(query, childID)
+func (s *SQLDocumentationEntryStore) GetAllForChild(ctx context.Context, childID int) ([]models.DocumentationEntry, error) {
+	return s.List(ctx, models.ListQuery{}.Where("child_id", models.OperatorEqual, childID))
+}
+
+// List fetches the documentation entries matching the query, by default the latest observations first.
+func (s *SQLDocumentationEntryStore) List(ctx context.Context, query models.ListQuery) ([]models.DocumentationEntry, error) {
+	statement, args, err := buildListQuery(documentationEntrySelect, documentationEntryListColumns, query, models.SortField{Field: "observation_date", Descending: true})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.statements.query(ctx, statement, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -184,7 +296,7 @@ func (s *SQLDocumentationEntryStore) GetAllForChild(childID int) ([]models.Docum
 	var entries []models.DocumentationEntry
 	for rows.Next() {
 		dbEntry := &models.DocumentationEntryDB{}
-		err := rows.Scan(&dbEntry.ID, &dbEntry.ChildID, &dbEntry.TeacherID, &dbEntry.CategoryID, &dbEntry.ObservationDate, &dbEntry.ObservationDescription, &dbEntry.IsApproved, &dbEntry.ApprovedByUserID, &dbEntry.CreatedAt, &dbEntry.UpdatedAt)
+		err := rows.Scan(&dbEntry.ID, &dbEntry.ChildID, &dbEntry.TeacherID, &dbEntry.CategoryID, &dbEntry.ObservationDate, &dbEntry.ObservationDescription, &dbEntry.StructuredData, &dbEntry.IsApproved, &dbEntry.ApprovedByUserID, &dbEntry.LockedAt, &dbEntry.RevisionOfEntryID, &dbEntry.ProjectID, &dbEntry.CreatedAt, &dbEntry.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -203,10 +315,231 @@ func (s *SQLDocumentationEntryStore) GetAllForChild(childID int) ([]models.Docum
 	return entries, nil
 }
 
+// Count counts the documentation entries matching the conditions of the query.
+func (s *SQLDocumentationEntryStore) Count(ctx context.Context, query models.ListQuery) (int, error) {
+	statement, args, err := buildCountQuery(`SELECT COUNT(*) FROM documentation_entries`, documentationEntryListColumns, query)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	err = s.statements.queryRow(ctx, statement, args...).Scan(&count)
+	return count, err
+}
+
+// lastObservationDatesQuery uses a correlated subquery to keep observation_date a plain column, so that it is
+// scanned like in the other queries.
+const lastObservationDatesQuery = `SELECT entry.child_id, entry.observation_date FROM documentation_entries entry
+		WHERE entry.observation_date = (SELECT MAX(latest.observation_date) FROM documentation_entries latest WHERE latest.child_id = entry.child_id)`
+
+// GetLastObservationDates fetches the date of the latest observation of every child that has one, by child ID.
+func (s *SQLDocumentationEntryStore) GetLastObservationDates(ctx context.Context) (map[int]models.Date, error) {
+	rows, err := s.db.QueryContext(ctx, lastObservationDatesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	dates := map[int]models.Date{}
+	for rows.Next() {
+		var childID int
+		var observationDate models.Date
+		if err := rows.Scan(&childID, &observationDate); err != nil {
+			return nil, err
+		}
+		dates[childID] = observationDate
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
 // ApproveEntry sets the approved_by_teacher_id for a documentation entry.
-func (s *SQLDocumentationEntryStore) ApproveEntry(entryID int, approvedByTeacherID int) error {
-	query := `UPDATE documentation_entries SET approved_by_teacher_id = ?, approved = 1, updated_at = CURRENT_TIMESTAMP WHERE entry_id = ?`
-	result, err := s.db.Exec(query, approvedByTeacherID, entryID)
+// The outbox messages are enqueued in the same transaction, so they are sent if and only if the approval is stored.
+func (s *SQLDocumentationEntryStore) ApproveEntry(ctx context.Context, entryID int, approvedByTeacherID int, outbox []models.OutboxMessage) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `UPDATE documentation_entries SET approved_by_teacher_id = ?, approved = TRUE, updated_at = CURRENT_TIMESTAMP WHERE entry_id = ?`
+	result, err := tx.ExecContext(ctx, query, approvedByTeacherID, entryID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	if err := enqueueOutboxMessages(ctx, tx, s.encryptionKey, outbox); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RecordReport archives a generated report with its entries and locks the entries in a single transaction.
+// Entries that are already locked keep their original lock time.
+func (s *SQLDocumentationEntryStore) RecordReport(ctx context.Context, report *models.GeneratedReport) error {
+	parameters, err := json.Marshal(report.Parameters)
+	if err != nil {
+		return fmt.Errorf("failed to encode report parameters: %w", err)
+	}
+	// The report holds everything its entries do, so it is encrypted as a whole.
+	content, err := Encrypt(string(report.Content), s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt report content: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `INSERT INTO generated_reports (child_id, document_id, generated_by_user_id, generated_at, file_name, parameters, content, content_size) VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING report_id`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, report.ChildID, report.DocumentID, report.GeneratedByUserID, report.GeneratedAt, report.FileName, string(parameters), content, len(report.Content)).Scan(&id); err != nil {
+		return err
+	}
+	for _, entryID := range report.EntryIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO generated_report_entries (report_id, entry_id) VALUES (?, ?)`, id, entryID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE documentation_entries SET locked_at = ? WHERE entry_id = ? AND locked_at IS NULL`, report.GeneratedAt, entryID); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	report.ID = id
+	report.SizeBytes = len(report.Content)
+	report.Archived = true
+	return nil
+}
+
+// generatedReportColumns are the report columns read by scanGeneratedReport, without the content.
+const generatedReportColumns = `r.report_id, r.child_id, COALESCE(r.document_id, ''), r.generated_by_user_id, r.generated_at,
+	COALESCE(r.file_name, ''), r.parameters, r.content_size, r.content IS NOT NULL`
+
+// scanGeneratedReport scans the generatedReportColumns followed by the given destinations.
+func scanGeneratedReport(row rowScanner, report *models.GeneratedReport, dest ...any) error {
+	var parameters sql.NullString
+	columns := []any{&report.ID, &report.ChildID, &report.DocumentID, &report.GeneratedByUserID, &report.GeneratedAt,
+		&report.FileName, &parameters, &report.SizeBytes, &report.Archived}
+	if err := row.Scan(append(columns, dest...)...); err != nil {
+		return err
+	}
+	if parameters.Valid {
+		if err := json.Unmarshal([]byte(parameters.String), &report.Parameters); err != nil {
+			return fmt.Errorf("failed to decode report parameters: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetReportsForChild fetches the generated reports of a child with their entries, newest first.
+// The content of the reports is not loaded.
+func (s *SQLDocumentationEntryStore) GetReportsForChild(ctx context.Context, childID int) ([]models.GeneratedReport, error) {
+	query := `SELECT ` + generatedReportColumns + `, e.entry_id
+		FROM generated_reports r LEFT JOIN generated_report_entries e ON e.report_id = r.report_id
+		WHERE r.child_id = ? ORDER BY r.generated_at DESC, r.report_id DESC, e.entry_id`
+	rows, err := s.db.QueryContext(ctx, query, childID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	reports := []models.GeneratedReport{}
+	for rows.Next() {
+		var report models.GeneratedReport
+		var entryID sql.NullInt64
+		if err := scanGeneratedReport(rows, &report, &entryID); err != nil {
+			return nil, err
+		}
+		if len(reports) == 0 || reports[len(reports)-1].ID != report.ID {
+			report.EntryIDs = []int{}
+			reports = append(reports, report)
+		}
+		if entryID.Valid {
+			last := &reports[len(reports)-1]
+			last.EntryIDs = append(last.EntryIDs, int(entryID.Int64))
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// GetReportByID fetches a generated report with its entries and its decrypted content.
+func (s *SQLDocumentationEntryStore) GetReportByID(ctx context.Context, reportID int) (*models.GeneratedReport, error) {
+	query := `SELECT ` + generatedReportColumns + `, r.content FROM generated_reports r WHERE r.report_id = ?`
+	report := &models.GeneratedReport{}
+	var content sql.NullString
+	if err := scanGeneratedReport(s.db.QueryRowContext(ctx, query, reportID), report, &content); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if content.Valid {
+		decrypted, err := Decrypt(content.String, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt report content: %w", err)
+		}
+		report.Content = []byte(decrypted)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT entry_id FROM generated_report_entries WHERE report_id = ? ORDER BY entry_id`, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+	report.EntryIDs = []int{}
+	for rows.Next() {
+		var entryID int
+		if err := rows.Scan(&entryID); err != nil {
+			return nil, err
+		}
+		report.EntryIDs = append(report.EntryIDs, entryID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetArchivedReportIDsSince fetches the IDs of the archived reports generated at or after since, oldest first.
+func (s *SQLDocumentationEntryStore) GetArchivedReportIDsSince(ctx context.Context, since time.Time) ([]int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT report_id FROM generated_reports WHERE content IS NOT NULL AND generated_at >= ? ORDER BY generated_at, report_id`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	reportIDs := []int{}
+	for rows.Next() {
+		var reportID int
+		if err := rows.Scan(&reportID); err != nil {
+			return nil, err
+		}
+		reportIDs = append(reportIDs, reportID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return reportIDs, nil
+}
+
+// Unlock unlocks a documentation entry, so it can be edited again. The reports it was included in are kept.
+func (s *SQLDocumentationEntryStore) Unlock(ctx context.Context, entryID int) error {
+	query := `UPDATE documentation_entries SET locked_at = NULL WHERE entry_id = ?`
+	result, err := s.db.ExecContext(ctx, query, entryID)
 	if err != nil {
 		return err
 	}
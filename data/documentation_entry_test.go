@@ -2,16 +2,19 @@ package data_test
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"regexp"
 	"testing"
 	"time"
 
 	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
 	"kitadoc-backend/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSQLDocumentationEntryStore_Create(t *testing.T) {
@@ -27,7 +30,7 @@ func TestSQLDocumentationEntryStore_Create(t *testing.T) {
 		ChildID:                1,
 		TeacherID:              2,
 		CategoryID:             3,
-		ObservationDate:        time.Now(),
+		ObservationDate:        models.Today(time.Now()),
 		ObservationDescription: "Test observation",
 		ApprovedByUserID:       nil,
 		CreatedAt:              time.Now(),
@@ -35,22 +38,23 @@ func TestSQLDocumentationEntryStore_Create(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO documentation_entries (child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)).
-			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), entry.IsApproved, entry.ApprovedByUserID, entry.CreatedAt, entry.UpdatedAt).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO documentation_entries (child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, revision_of_entry_id, project_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO documentation_entries (child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, revision_of_entry_id, project_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING entry_id`)).
+			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), nil, entry.IsApproved, entry.ApprovedByUserID, entry.RevisionOfEntryID, entry.ProjectID, entry.CreatedAt, entry.UpdatedAt).
+			WillReturnRows(sqlmock.NewRows([]string{"entry_id"}).AddRow(1))
 
-		id, err := store.Create(entry)
+		id, err := store.Create(t.Context(), entry)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, id)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO documentation_entries (child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)).
-			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), entry.IsApproved, entry.ApprovedByUserID, entry.CreatedAt, entry.UpdatedAt).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO documentation_entries (child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, revision_of_entry_id, project_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING entry_id`)).
+			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), nil, entry.IsApproved, entry.ApprovedByUserID, entry.RevisionOfEntryID, entry.ProjectID, entry.CreatedAt, entry.UpdatedAt).
 			WillReturnError(errors.New("db error"))
 
-		id, err := store.Create(entry)
+		id, err := store.Create(t.Context(), entry)
 		assert.Error(t, err)
 		assert.Equal(t, 0, id)
 		assert.Contains(t, err.Error(), "db error")
@@ -75,7 +79,7 @@ func TestSQLDocumentationEntryStore_GetByID(t *testing.T) {
 		ChildID:                1,
 		TeacherID:              2,
 		CategoryID:             3,
-		ObservationDate:        time.Now().Truncate(time.Second),
+		ObservationDate:        models.DateOf(time.Now().Truncate(time.Second)),
 		ObservationDescription: "Test observation",
 		ApprovedByUserID:       &approvedByUserID,
 		CreatedAt:              time.Now().Truncate(time.Second),
@@ -85,21 +89,22 @@ func TestSQLDocumentationEntryStore_GetByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		encryptedObservation, _ := data.Encrypt(expectedEntry.ObservationDescription, key)
 
-		rows := sqlmock.NewRows([]string{"entry_id", "child_id", "documenting_teacher_id", "category_id", "observation_date", "observation_description", "approved", "approved_by_teacher_id", "created_at", "updated_at"}).
-			AddRow(expectedEntry.ID, expectedEntry.ChildID, expectedEntry.TeacherID, expectedEntry.CategoryID, expectedEntry.ObservationDate, encryptedObservation, expectedEntry.IsApproved, expectedEntry.ApprovedByUserID, expectedEntry.CreatedAt, expectedEntry.UpdatedAt)
+		rows := sqlmock.NewRows([]string{"entry_id", "child_id", "documenting_teacher_id", "category_id", "observation_date", "observation_description", "structured_data", "approved", "approved_by_teacher_id", "locked_at", "revision_of_entry_id", "project_id", "created_at", "updated_at"}).
+			AddRow(expectedEntry.ID, expectedEntry.ChildID, expectedEntry.TeacherID, expectedEntry.CategoryID, expectedEntry.ObservationDate, encryptedObservation, nil, expectedEntry.IsApproved, expectedEntry.ApprovedByUserID, nil, nil, nil, expectedEntry.CreatedAt, expectedEntry.UpdatedAt)
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at FROM documentation_entries WHERE entry_id = ?`)).
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries WHERE entry_id = ?`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries WHERE entry_id = ?`)).
 			WithArgs(entryID).
 			WillReturnRows(rows)
 
-		entry, err := store.GetByID(entryID)
+		entry, err := store.GetByID(t.Context(), entryID)
 		assert.NoError(t, err)
 		assert.NotNil(t, entry)
 		assert.Equal(t, expectedEntry.ID, entry.ID)
 		assert.Equal(t, expectedEntry.ChildID, entry.ChildID)
 		assert.Equal(t, expectedEntry.TeacherID, entry.TeacherID)
 		assert.Equal(t, expectedEntry.CategoryID, entry.CategoryID)
-		assert.WithinDuration(t, expectedEntry.ObservationDate, entry.ObservationDate, time.Second)
+		assert.Equal(t, expectedEntry.ObservationDate, entry.ObservationDate)
 		assert.Equal(t, expectedEntry.ObservationDescription, entry.ObservationDescription)
 		assert.Equal(t, expectedEntry.ApprovedByUserID, entry.ApprovedByUserID)
 		assert.WithinDuration(t, expectedEntry.CreatedAt, entry.CreatedAt, time.Second)
@@ -108,11 +113,11 @@ func TestSQLDocumentationEntryStore_GetByID(t *testing.T) {
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at FROM documentation_entries WHERE entry_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries WHERE entry_id = ?`)).
 			WithArgs(entryID).
 			WillReturnError(sql.ErrNoRows)
 
-		entry, err := store.GetByID(entryID)
+		entry, err := store.GetByID(t.Context(), entryID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.Nil(t, entry)
@@ -120,11 +125,11 @@ func TestSQLDocumentationEntryStore_GetByID(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at FROM documentation_entries WHERE entry_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries WHERE entry_id = ?`)).
 			WithArgs(entryID).
 			WillReturnError(errors.New("db error"))
 
-		entry, err := store.GetByID(entryID)
+		entry, err := store.GetByID(t.Context(), entryID)
 		assert.Error(t, err)
 		assert.Nil(t, entry)
 		assert.Contains(t, err.Error(), "db error")
@@ -147,39 +152,57 @@ func TestSQLDocumentationEntryStore_Update(t *testing.T) {
 		ChildID:                1,
 		TeacherID:              2,
 		CategoryID:             3,
-		ObservationDate:        time.Now().Add(-time.Hour),
+		ObservationDate:        models.DateOf(time.Now().Add(-time.Hour)),
 		ObservationDescription: "Updated observation",
 		ApprovedByUserID:       &approvedByUserID,
 		UpdatedAt:              time.Now(),
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET child_id = ?, documenting_teacher_id = ?, category_id = ?, observation_date = ?, observation_description = ?, approved = ?, approved_by_teacher_id = ?, updated_at = ? WHERE entry_id = ?`)).
-			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), entry.IsApproved, entry.ApprovedByUserID, entry.UpdatedAt, entry.ID).
+		mock.ExpectPrepare(regexp.QuoteMeta(`UPDATE documentation_entries SET child_id = ?, documenting_teacher_id = ?, category_id = ?, observation_date = ?, observation_description = ?, structured_data = ?, approved = ?, approved_by_teacher_id = ?, project_id = ?, updated_at = ? WHERE entry_id = ?`))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET child_id = ?, documenting_teacher_id = ?, category_id = ?, observation_date = ?, observation_description = ?, structured_data = ?, approved = ?, approved_by_teacher_id = ?, project_id = ?, updated_at = ? WHERE entry_id = ?`)).
+			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), nil, entry.IsApproved, entry.ApprovedByUserID, entry.ProjectID, entry.UpdatedAt, entry.ID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Update(entry)
+		err := store.Update(t.Context(), entry)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET child_id = ?, documenting_teacher_id = ?, category_id = ?, observation_date = ?, observation_description = ?, approved = ?, approved_by_teacher_id = ?, updated_at = ? WHERE entry_id = ?`)).
-			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), entry.IsApproved, entry.ApprovedByUserID, entry.UpdatedAt, entry.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET child_id = ?, documenting_teacher_id = ?, category_id = ?, observation_date = ?, observation_description = ?, structured_data = ?, approved = ?, approved_by_teacher_id = ?, project_id = ?, updated_at = ? WHERE entry_id = ?`)).
+			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), nil, entry.IsApproved, entry.ApprovedByUserID, entry.ProjectID, entry.UpdatedAt, entry.ID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM documentation_entries WHERE entry_id = ?)`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM documentation_entries WHERE entry_id = ?)`)).
+			WithArgs(entry.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-		err := store.Update(entry)
+		err := store.Update(t.Context(), entry)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	t.Run("locked", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET child_id = ?, documenting_teacher_id = ?, category_id = ?, observation_date = ?, observation_description = ?, structured_data = ?, approved = ?, approved_by_teacher_id = ?, project_id = ?, updated_at = ? WHERE entry_id = ? AND locked_at IS NULL`)).
+			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), nil, entry.IsApproved, entry.ApprovedByUserID, entry.ProjectID, entry.UpdatedAt, entry.ID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM documentation_entries WHERE entry_id = ?)`)).
+			WithArgs(entry.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		err := store.Update(t.Context(), entry)
+		assert.Equal(t, data.ErrLocked, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET child_id = ?, documenting_teacher_id = ?, category_id = ?, observation_date = ?, observation_description = ?, approved = ?, approved_by_teacher_id = ?, updated_at = ? WHERE entry_id = ?`)).
-			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), entry.IsApproved, entry.ApprovedByUserID, entry.UpdatedAt, entry.ID).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET child_id = ?, documenting_teacher_id = ?, category_id = ?, observation_date = ?, observation_description = ?, structured_data = ?, approved = ?, approved_by_teacher_id = ?, project_id = ?, updated_at = ? WHERE entry_id = ?`)).
+			WithArgs(entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, sqlmock.AnyArg(), nil, entry.IsApproved, entry.ApprovedByUserID, entry.ProjectID, entry.UpdatedAt, entry.ID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Update(entry)
+		err := store.Update(t.Context(), entry)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -198,11 +221,12 @@ func TestSQLDocumentationEntryStore_Delete(t *testing.T) {
 	entryID := 1
 
 	t.Run("success", func(t *testing.T) {
+		mock.ExpectPrepare(regexp.QuoteMeta(`DELETE FROM documentation_entries WHERE entry_id = ?`))
 		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM documentation_entries WHERE entry_id = ?`)).
 			WithArgs(entryID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Delete(entryID)
+		err := store.Delete(t.Context(), entryID)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -211,19 +235,36 @@ func TestSQLDocumentationEntryStore_Delete(t *testing.T) {
 		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM documentation_entries WHERE entry_id = ?`)).
 			WithArgs(entryID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM documentation_entries WHERE entry_id = ?)`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM documentation_entries WHERE entry_id = ?)`)).
+			WithArgs(entryID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-		err := store.Delete(entryID)
+		err := store.Delete(t.Context(), entryID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	t.Run("locked", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM documentation_entries WHERE entry_id = ? AND locked_at IS NULL`)).
+			WithArgs(entryID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM documentation_entries WHERE entry_id = ?)`)).
+			WithArgs(entryID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		err := store.Delete(t.Context(), entryID)
+		assert.Equal(t, data.ErrLocked, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("db error", func(t *testing.T) {
 		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM documentation_entries WHERE entry_id = ?`)).
 			WithArgs(entryID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Delete(entryID)
+		err := store.Delete(t.Context(), entryID)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -249,8 +290,9 @@ func TestSQLDocumentationEntryStore_GetAllForChild(t *testing.T) {
 			ChildID:                childID,
 			TeacherID:              1,
 			CategoryID:             1,
-			ObservationDate:        now.Add(-time.Hour * 24),
+			ObservationDate:        models.DateOf(now.Add(-time.Hour * 24)),
 			ObservationDescription: "Entry 1",
+			StructuredData:         map[string]any{"gross_motor": true},
 			ApprovedByUserID:       &approvedByUserID,
 			CreatedAt:              now.Add(-time.Hour * 25),
 			UpdatedAt:              now.Add(-time.Hour * 25),
@@ -260,7 +302,7 @@ func TestSQLDocumentationEntryStore_GetAllForChild(t *testing.T) {
 			ChildID:                childID,
 			TeacherID:              2,
 			CategoryID:             2,
-			ObservationDate:        now.Add(-time.Hour * 48),
+			ObservationDate:        models.DateOf(now.Add(-time.Hour * 48)),
 			ObservationDescription: "Entry 2",
 			ApprovedByUserID:       nil,
 			CreatedAt:              now.Add(-time.Hour * 49),
@@ -269,42 +311,51 @@ func TestSQLDocumentationEntryStore_GetAllForChild(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"entry_id", "child_id", "documenting_teacher_id", "category_id", "observation_date", "observation_description", "approved", "approved_by_teacher_id", "created_at", "updated_at"})
+		rows := sqlmock.NewRows([]string{"entry_id", "child_id", "documenting_teacher_id", "category_id", "observation_date", "observation_description", "structured_data", "approved", "approved_by_teacher_id", "locked_at", "revision_of_entry_id", "project_id", "created_at", "updated_at"})
 		for _, entry := range entries {
 			encryptedObservation, _ := data.Encrypt(entry.ObservationDescription, key)
-			rows.AddRow(entry.ID, entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, encryptedObservation, entry.IsApproved, entry.ApprovedByUserID, entry.CreatedAt, entry.UpdatedAt)
+			var encryptedStructuredData *string
+			if entry.StructuredData != nil {
+				encoded, _ := json.Marshal(entry.StructuredData)
+				encrypted, _ := data.Encrypt(string(encoded), key)
+				encryptedStructuredData = &encrypted
+			}
+			rows.AddRow(entry.ID, entry.ChildID, entry.TeacherID, entry.CategoryID, entry.ObservationDate, encryptedObservation, encryptedStructuredData, entry.IsApproved, entry.ApprovedByUserID, nil, nil, nil, entry.CreatedAt, entry.UpdatedAt)
 		}
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at FROM documentation_entries WHERE child_id = ? ORDER BY observation_date DESC`)).
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries WHERE child_id = ? ORDER BY observation_date DESC, entry_id`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries WHERE child_id = ? ORDER BY observation_date DESC, entry_id`)).
 			WithArgs(childID).
 			WillReturnRows(rows)
 
-		fetchedEntries, err := store.GetAllForChild(childID)
+		fetchedEntries, err := store.GetAllForChild(t.Context(), childID)
 		assert.NoError(t, err)
 		assert.NotNil(t, fetchedEntries)
 		assert.Len(t, fetchedEntries, 2)
 		assert.Equal(t, entries[0].ID, fetchedEntries[0].ID)
 		assert.Equal(t, entries[1].ID, fetchedEntries[1].ID)
+		assert.Equal(t, map[string]any{"gross_motor": true}, fetchedEntries[0].StructuredData)
+		assert.Nil(t, fetchedEntries[1].StructuredData)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("no entries found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at FROM documentation_entries WHERE child_id = ? ORDER BY observation_date DESC`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries WHERE child_id = ? ORDER BY observation_date DESC, entry_id`)).
 			WithArgs(childID).
-			WillReturnRows(sqlmock.NewRows([]string{"entry_id", "child_id", "documenting_teacher_id", "category_id", "observation_date", "observation_description", "approved", "approved_by_teacher_id", "created_at", "updated_at"}))
+			WillReturnRows(sqlmock.NewRows([]string{"entry_id", "child_id", "documenting_teacher_id", "category_id", "observation_date", "observation_description", "structured_data", "approved", "approved_by_teacher_id", "locked_at", "revision_of_entry_id", "project_id", "created_at", "updated_at"}))
 
-		fetchedEntries, err := store.GetAllForChild(childID)
+		fetchedEntries, err := store.GetAllForChild(t.Context(), childID)
 		assert.NoError(t, err)
 		assert.Nil(t, fetchedEntries)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at FROM documentation_entries WHERE child_id = ? ORDER BY observation_date DESC`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries WHERE child_id = ? ORDER BY observation_date DESC, entry_id`)).
 			WithArgs(childID).
 			WillReturnError(errors.New("db error"))
 
-		fetchedEntries, err := store.GetAllForChild(childID)
+		fetchedEntries, err := store.GetAllForChild(t.Context(), childID)
 		assert.Error(t, err)
 		assert.Nil(t, fetchedEntries)
 		assert.Contains(t, err.Error(), "db error")
@@ -312,14 +363,14 @@ func TestSQLDocumentationEntryStore_GetAllForChild(t *testing.T) {
 	})
 
 	t.Run("scan error", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"entry_id", "child_id", "documenting_teacher_id", "category_id", "observation_date", "observation_description", "approved", "approved_by_teacher_id", "created_at", "updated_at"}).
-			AddRow(entries[0].ID, entries[0].ChildID, "not-an-int", entries[0].CategoryID, entries[0].ObservationDate, entries[0].ObservationDescription, entries[0].IsApproved, entries[0].ApprovedByUserID, entries[0].CreatedAt, entries[0].UpdatedAt) // Malformed row
+		rows := sqlmock.NewRows([]string{"entry_id", "child_id", "documenting_teacher_id", "category_id", "observation_date", "observation_description", "structured_data", "approved", "approved_by_teacher_id", "locked_at", "revision_of_entry_id", "project_id", "created_at", "updated_at"}).
+			AddRow(entries[0].ID, entries[0].ChildID, "not-an-int", entries[0].CategoryID, entries[0].ObservationDate, entries[0].ObservationDescription, nil, entries[0].IsApproved, entries[0].ApprovedByUserID, nil, nil, nil, entries[0].CreatedAt, entries[0].UpdatedAt) // Malformed row
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, approved, approved_by_teacher_id, created_at, updated_at FROM documentation_entries WHERE child_id = ? ORDER BY observation_date DESC`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id, child_id, documenting_teacher_id, category_id, observation_date, observation_description, structured_data, approved, approved_by_teacher_id, locked_at, revision_of_entry_id, project_id, created_at, updated_at FROM documentation_entries WHERE child_id = ? ORDER BY observation_date DESC, entry_id`)).
 			WithArgs(childID).
 			WillReturnRows(rows)
 
-		fetchedEntries, err := store.GetAllForChild(childID)
+		fetchedEntries, err := store.GetAllForChild(t.Context(), childID)
 		assert.Error(t, err)
 		assert.Nil(t, fetchedEntries)
 		assert.Contains(t, err.Error(), "converting driver.Value type string")
@@ -340,34 +391,165 @@ func TestSQLDocumentationEntryStore_ApproveEntry(t *testing.T) {
 	approvedByUserID := 10
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET approved_by_teacher_id = ?, approved = 1, updated_at = CURRENT_TIMESTAMP WHERE entry_id = ?`)).
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET approved_by_teacher_id = ?, approved = TRUE, updated_at = CURRENT_TIMESTAMP WHERE entry_id = ?`)).
 			WithArgs(approvedByUserID, entryID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO outbox (channel, payload, status, next_attempt_at) VALUES (?, ?, ?, ?) RETURNING message_id`)).
+			WithArgs(models.OutboxChannelPush, sqlmock.AnyArg(), models.OutboxStatusPending, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow(5))
+		mock.ExpectCommit()
 
-		err := store.ApproveEntry(entryID, approvedByUserID)
+		outbox := []models.OutboxMessage{{Channel: models.OutboxChannelPush, Payload: []byte(`{"teacher_id":3}`)}}
+		err := store.ApproveEntry(t.Context(), entryID, approvedByUserID, outbox)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET approved_by_teacher_id = ?, approved = 1, updated_at = CURRENT_TIMESTAMP WHERE entry_id = ?`)).
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET approved_by_teacher_id = ?, approved = TRUE, updated_at = CURRENT_TIMESTAMP WHERE entry_id = ?`)).
 			WithArgs(approvedByUserID, entryID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
 
-		err := store.ApproveEntry(entryID, approvedByUserID)
+		err := store.ApproveEntry(t.Context(), entryID, approvedByUserID, nil)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET approved_by_teacher_id = ?, approved = 1, updated_at = CURRENT_TIMESTAMP WHERE entry_id = ?`)).
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET approved_by_teacher_id = ?, approved = TRUE, updated_at = CURRENT_TIMESTAMP WHERE entry_id = ?`)).
 			WithArgs(approvedByUserID, entryID).
 			WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
 
-		err := store.ApproveEntry(entryID, approvedByUserID)
+		err := store.ApproveEntry(t.Context(), entryID, approvedByUserID, nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestSQLDocumentationEntryStore_RecordReport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLDocumentationEntryStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	userID := 4
+	profileID := 2
+	report := &models.GeneratedReport{
+		ChildID:           1,
+		DocumentID:        "0b8f3c8e-5d6a-4d3b-9a57-2f6c1f0e7d21",
+		GeneratedByUserID: &userID,
+		GeneratedAt:       time.Now(),
+		EntryIDs:          []int{2, 3},
+		FileName:          "Bildungsdokumentation_Report_1.docx",
+		Parameters:        models.GeneratedReportParameters{RedactionProfileID: &profileID, RedactionProfileName: "Schule"},
+		Content:           []byte("docx"),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO generated_reports (child_id, document_id, generated_by_user_id, generated_at, file_name, parameters, content, content_size) VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING report_id`)).
+		WithArgs(1, "0b8f3c8e-5d6a-4d3b-9a57-2f6c1f0e7d21", &userID, report.GeneratedAt, "Bildungsdokumentation_Report_1.docx",
+			`{"redaction_profile_id":2,"redaction_profile_name":"Schule","include_completeness":false}`, sqlmock.AnyArg(), 4).
+		WillReturnRows(sqlmock.NewRows([]string{"report_id"}).AddRow(9))
+	for _, entryID := range report.EntryIDs {
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO generated_report_entries (report_id, entry_id) VALUES (?, ?)`)).
+			WithArgs(int64(9), entryID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET locked_at = ? WHERE entry_id = ? AND locked_at IS NULL`)).
+			WithArgs(report.GeneratedAt, entryID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	err = store.RecordReport(t.Context(), report)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, report.ID)
+	assert.True(t, report.Archived)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLDocumentationEntryStore_GetReportByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	store := data.NewSQLDocumentationEntryStore(db, key)
+	query := regexp.QuoteMeta(`FROM generated_reports r WHERE r.report_id = ?`)
+	columns := []string{"report_id", "child_id", "document_id", "generated_by_user_id", "generated_at", "file_name", "parameters", "content_size", "archived", "content"}
+
+	t.Run("archived report", func(t *testing.T) {
+		encrypted, err := data.Encrypt("docx", key)
+		assert.NoError(t, err)
+		generatedAt := time.Now()
+		mock.ExpectQuery(query).WithArgs(9).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(9, 1, "0b8f3c8e-5d6a-4d3b-9a57-2f6c1f0e7d21", 4, generatedAt, "report.docx", `{"redaction_profile_id":null,"include_completeness":true}`, 4, true, encrypted))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT entry_id FROM generated_report_entries WHERE report_id = ?`)).WithArgs(9).
+			WillReturnRows(sqlmock.NewRows([]string{"entry_id"}).AddRow(2).AddRow(3))
+
+		report, err := store.GetReportByID(t.Context(), 9)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("docx"), report.Content)
+		assert.Equal(t, []int{2, 3}, report.EntryIDs)
+		assert.True(t, report.Parameters.IncludeCompleteness)
+		assert.Nil(t, report.Parameters.RedactionProfileID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(10).WillReturnError(sql.ErrNoRows)
+
+		_, err := store.GetReportByID(t.Context(), 10)
+		assert.Equal(t, data.ErrNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLDocumentationEntryStore_List_StablePages(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	_, err = db.Exec(`INSERT INTO teachers (teacher_id, first_name, last_name, username) VALUES (1, 'Maria', 'Müller', 'mmueller')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO children (child_id, first_name, last_name, birthdate, admission_date) VALUES (1, 'x', 'y', 'encrypted', '2021-08-01T00:00:00Z')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO categories (category_id, category_name) VALUES (1, 'Sprache')`)
+	require.NoError(t, err)
+
+	store := data.NewSQLDocumentationEntryStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	// All entries share the observation date the list is sorted by, only the ID tells them apart.
+	observationDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := make([]models.DocumentationEntry, 7)
+	for i := range entries {
+		entries[i] = models.DocumentationEntry{ChildID: 1, TeacherID: 1, CategoryID: 1, ObservationDate: models.DateOf(observationDate), ObservationDescription: "Spielt im Sandkasten."}
+	}
+	ids, err := store.CreateMany(t.Context(), entries)
+	require.NoError(t, err)
+
+	var paged []int
+	for offset := 0; offset < len(entries); offset += 3 {
+		page, err := store.List(t.Context(), models.ListQuery{Limit: 3, Offset: offset}.Where("child_id", models.OperatorEqual, 1))
+		require.NoError(t, err)
+		for _, entry := range page {
+			paged = append(paged, entry.ID)
+		}
+	}
+	assert.Equal(t, ids, paged)
+
+	all, err := store.GetAllForChild(t.Context(), 1)
+	require.NoError(t, err)
+	for i, entry := range all {
+		assert.Equal(t, ids[i], entry.ID)
+	}
+}
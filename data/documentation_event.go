@@ -0,0 +1,113 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// DocumentationEventStore defines the interface for the append-only DocumentationEvent stream.
+type DocumentationEventStore interface {
+	Append(ctx context.Context, event *models.DocumentationEvent, outbox []models.OutboxMessage) error
+	GetForChild(ctx context.Context, childID int) ([]models.DocumentationEvent, error)
+	GetForEntry(ctx context.Context, entryID int) ([]models.DocumentationEvent, error)
+	GetAll(ctx context.Context) ([]models.DocumentationEvent, error)
+}
+
+// SQLDocumentationEventStore implements DocumentationEventStore using database/sql.
+type SQLDocumentationEventStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLDocumentationEventStore creates a new SQLDocumentationEventStore.
+func NewSQLDocumentationEventStore(db *sql.DB, encryptionKey []byte) *SQLDocumentationEventStore {
+	return &SQLDocumentationEventStore{db: db, encryptionKey: encryptionKey}
+}
+
+// Append adds an event to the end of the child's stream and sets its ID and sequence number.
+// The next sequence number is taken in the same statement, the unique constraint rejects concurrent duplicates.
+// The outbox messages are enqueued in the same transaction as the event.
+func (s *SQLDocumentationEventStore) Append(ctx context.Context, event *models.DocumentationEvent, outbox []models.OutboxMessage) error {
+	encoded, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode event payload: %w", err)
+	}
+	payload, err := Encrypt(string(encoded), s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt event payload: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `INSERT INTO documentation_events (child_id, sequence, entry_id, event_type, actor_user_id, on_behalf_of_user_id, delegation_id, payload, created_at)
+		SELECT ?, COALESCE(MAX(sequence), 0) + 1, ?, ?, ?, ?, ?, ?, ? FROM documentation_events WHERE child_id = ?
+		RETURNING event_id, sequence`
+	row := tx.QueryRowContext(ctx, query, event.ChildID, event.EntryID, event.Type, event.ActorUserID, event.OnBehalfOfUserID, event.DelegationID, payload, event.CreatedAt, event.ChildID)
+	if err := row.Scan(&event.ID, &event.Sequence); err != nil {
+		return err
+	}
+	if err := enqueueOutboxMessages(ctx, tx, s.encryptionKey, outbox); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetForChild fetches the stream of a child in order.
+func (s *SQLDocumentationEventStore) GetForChild(ctx context.Context, childID int) ([]models.DocumentationEvent, error) {
+	query := `SELECT event_id, child_id, sequence, entry_id, event_type, actor_user_id, on_behalf_of_user_id, delegation_id, payload, created_at FROM documentation_events WHERE child_id = ? ORDER BY sequence ASC`
+	return s.queryEvents(ctx, query, childID)
+}
+
+// GetForEntry fetches the events of a single entry in order.
+func (s *SQLDocumentationEventStore) GetForEntry(ctx context.Context, entryID int) ([]models.DocumentationEvent, error) {
+	query := `SELECT event_id, child_id, sequence, entry_id, event_type, actor_user_id, on_behalf_of_user_id, delegation_id, payload, created_at FROM documentation_events WHERE entry_id = ? ORDER BY sequence ASC`
+	return s.queryEvents(ctx, query, entryID)
+}
+
+// GetAll fetches the streams of all children, ordered by child and sequence.
+func (s *SQLDocumentationEventStore) GetAll(ctx context.Context) ([]models.DocumentationEvent, error) {
+	query := `SELECT event_id, child_id, sequence, entry_id, event_type, actor_user_id, on_behalf_of_user_id, delegation_id, payload, created_at FROM documentation_events ORDER BY child_id ASC, sequence ASC`
+	return s.queryEvents(ctx, query)
+}
+
+func (s *SQLDocumentationEventStore) queryEvents(ctx context.Context, query string, args ...any) ([]models.DocumentationEvent, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var events []models.DocumentationEvent
+	for rows.Next() {
+		event := models.DocumentationEvent{}
+		var payload sql.NullString
+		err := rows.Scan(&event.ID, &event.ChildID, &event.Sequence, &event.EntryID, &event.Type, &event.ActorUserID, &event.OnBehalfOfUserID, &event.DelegationID, &payload, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if payload.Valid {
+			decrypted, err := Decrypt(payload.String, s.encryptionKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt payload of event %d: %w", event.ID, err)
+			}
+			if err := json.Unmarshal([]byte(decrypted), &event.Payload); err != nil {
+				return nil, fmt.Errorf("failed to decode payload of event %d: %w", event.ID, err)
+			}
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
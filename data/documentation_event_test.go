@@ -0,0 +1,90 @@
+package data_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLDocumentationEventStore_Append(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLDocumentationEventStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	actorUserID := 3
+	event := &models.DocumentationEvent{
+		ChildID:     1,
+		EntryID:     2,
+		Type:        models.DocumentationEventRejected,
+		ActorUserID: &actorUserID,
+		Payload:     models.DocumentationEventData{Reason: "Bitte konkreter"},
+		CreatedAt:   time.Now(),
+	}
+	query := regexp.QuoteMeta(`INSERT INTO documentation_events`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).
+			WithArgs(event.ChildID, event.EntryID, event.Type, event.ActorUserID, nil, nil, sqlmock.AnyArg(), event.CreatedAt, event.ChildID).
+			WillReturnRows(sqlmock.NewRows([]string{"event_id", "sequence"}).AddRow(10, 4))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO outbox`)).WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow(5))
+		mock.ExpectCommit()
+
+		outbox := []models.OutboxMessage{{Channel: models.OutboxChannelPush, Payload: []byte(`{"teacher_id":3}`)}}
+		err := store.Append(t.Context(), event, outbox)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, event.ID)
+		assert.Equal(t, 4, event.Sequence)
+		assert.Equal(t, 5, outbox[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		err := store.Append(t.Context(), event, nil)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLDocumentationEventStore_GetForChild(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	store := data.NewSQLDocumentationEventStore(db, key)
+	payload, err := data.Encrypt(`{"reason":"Bitte konkreter"}`, key)
+	assert.NoError(t, err)
+	createdAt := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT event_id, child_id, sequence, entry_id, event_type, actor_user_id, on_behalf_of_user_id, delegation_id, payload, created_at FROM documentation_events WHERE child_id = ? ORDER BY sequence ASC`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "child_id", "sequence", "entry_id", "event_type", "actor_user_id", "on_behalf_of_user_id", "delegation_id", "payload", "created_at"}).
+			AddRow(10, 1, 1, 2, "created", 3, nil, nil, nil, createdAt).
+			AddRow(11, 1, 2, 2, "rejected", 3, nil, nil, payload, createdAt))
+
+	events, err := store.GetForChild(t.Context(), 1)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, models.DocumentationEventCreated, events[0].Type)
+		assert.Equal(t, 2, events[1].Sequence)
+		assert.Equal(t, "Bitte konkreter", events[1].Payload.Reason)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
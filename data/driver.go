@@ -0,0 +1,162 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Driver is a database engine the stores run against. The stores write portable SQL with ? placeholders, the
+// PostgreSQL driver rewrites them to the numbered placeholders of PostgreSQL.
+type Driver string
+
+const (
+	DriverSQLite   Driver = "sqlite"
+	DriverPostgres Driver = "postgres"
+)
+
+// postgresDriverName is the name the PostgreSQL driver is registered with at database/sql.
+const postgresDriverName = "kitadoc-postgres"
+
+func init() {
+	sql.Register(postgresDriverName, postgresDriver{})
+}
+
+// Open opens the database of the driver, dsn is passed on to it.
+func Open(driver Driver, dsn string) (*sql.DB, error) {
+	switch driver {
+	case DriverSQLite:
+		return sql.Open("sqlite", dsn)
+	case DriverPostgres:
+		return sql.Open(postgresDriverName, dsn)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", driver)
+	}
+}
+
+// DriverOf returns the driver a database was opened with.
+func DriverOf(db *sql.DB) Driver {
+	if _, ok := db.Driver().(postgresDriver); ok {
+		return DriverPostgres
+	}
+	return DriverSQLite
+}
+
+// postgresDriver is the pq driver with the ? placeholders of the stores.
+type postgresDriver struct{}
+
+// postgresConn is what the pq driver implements of a connection.
+type postgresConn interface {
+	driver.Conn
+	driver.ConnPrepareContext
+	driver.ConnBeginTx
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+func (postgresDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := pq.Driver{}.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	pqConn, ok := conn.(postgresConn)
+	if !ok {
+		conn.Close() //nolint:errcheck
+		return nil, fmt.Errorf("unsupported connection %T of the PostgreSQL driver", conn)
+	}
+	return placeholderConn{pqConn}, nil
+}
+
+// placeholderConn rewrites the placeholders of the statements it runs.
+type placeholderConn struct {
+	postgresConn
+}
+
+func (conn placeholderConn) Prepare(query string) (driver.Stmt, error) {
+	return conn.postgresConn.Prepare(numberPlaceholders(query))
+}
+
+func (conn placeholderConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return conn.postgresConn.PrepareContext(ctx, numberPlaceholders(query))
+}
+
+func (conn placeholderConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return conn.postgresConn.ExecContext(ctx, numberPlaceholders(query), args)
+}
+
+func (conn placeholderConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return conn.postgresConn.QueryContext(ctx, numberPlaceholders(query), args)
+}
+
+// numberPlaceholders replaces the ? placeholders of a query with $1, $2 and so on. Question marks in string
+// literals, quoted identifiers, dollar-quoted bodies of functions and comments are left alone.
+func numberPlaceholders(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+	var numbered strings.Builder
+	placeholders := 0
+	for i := 0; i < len(query); i++ {
+		end := i + 1
+		switch c := query[i]; {
+		case c == '?':
+			placeholders++
+			numbered.WriteString("$" + strconv.Itoa(placeholders))
+			continue
+		case c == '\'' || c == '"':
+			// A doubled quote escapes the quote, it is read as two literals next to each other.
+			if closing := strings.IndexByte(query[end:], c); closing >= 0 {
+				end += closing + 1
+			} else {
+				end = len(query)
+			}
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			if newline := strings.IndexByte(query[i:], '\n'); newline >= 0 {
+				end = i + newline + 1
+			} else {
+				end = len(query)
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			if closing := strings.Index(query[i+2:], "*/"); closing >= 0 {
+				end = i + 2 + closing + 2
+			} else {
+				end = len(query)
+			}
+		case c == '$':
+			if tag := dollarQuoteTag(query[i:]); tag != "" {
+				if closing := strings.Index(query[i+len(tag):], tag); closing >= 0 {
+					end = i + len(tag) + closing + len(tag)
+				} else {
+					end = len(query)
+				}
+			}
+		}
+		numbered.WriteString(query[i:end])
+		i = end - 1
+	}
+	return numbered.String()
+}
+
+// dollarQuoteTag returns the opening tag of a dollar-quoted string at the start of s, e.g. $$ or $body$, and
+// "" if s does not start with one.
+func dollarQuoteTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9':
+		default:
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberPlaceholders(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "no placeholders",
+			query: `SELECT 1`,
+			want:  `SELECT 1`,
+		},
+		{
+			name:  "placeholders are numbered in order",
+			query: `UPDATE children SET first_name = ?, last_name = ? WHERE child_id = ?`,
+			want:  `UPDATE children SET first_name = $1, last_name = $2 WHERE child_id = $3`,
+		},
+		{
+			name:  "string literals and quoted identifiers",
+			query: `SELECT '?', 'it''s ?', "a?b" FROM t WHERE a = ?`,
+			want:  `SELECT '?', 'it''s ?', "a?b" FROM t WHERE a = $1`,
+		},
+		{
+			name:  "comments",
+			query: "SELECT a -- why?\nFROM t /* or ? */ WHERE a = ?",
+			want:  "SELECT a -- why?\nFROM t /* or ? */ WHERE a = $1",
+		},
+		{
+			name:  "dollar-quoted bodies",
+			query: `SELECT $$?$$, $body$ ? $body$, ?`,
+			want:  `SELECT $$?$$, $body$ ? $body$, $1`,
+		},
+		{
+			name:  "unterminated literal",
+			query: `SELECT ? WHERE a = '?`,
+			want:  `SELECT $1 WHERE a = '?`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, numberPlaceholders(test.query))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// EditLockStore defines the interface for the advisory locks of documentation entries being edited.
+type EditLockStore interface {
+	// Get fetches the lock of an entry, also when it has expired. It returns ErrNotFound if the entry has none.
+	Get(ctx context.Context, entryID int) (*models.EditLock, error)
+	// Acquire takes the lock for its user, or renews it if the user already holds it. The lock of another user
+	// is only taken over once it has expired at now. It returns the lock held afterwards, which belongs to
+	// another user if it could not be taken.
+	Acquire(ctx context.Context, lock *models.EditLock, now time.Time) (*models.EditLock, error)
+	// Release removes the lock of an entry held by the user. It returns ErrNotFound if the user holds none.
+	Release(ctx context.Context, entryID int, userID int) error
+}
+
+// SQLEditLockStore implements EditLockStore using database/sql.
+type SQLEditLockStore struct {
+	db *sql.DB
+}
+
+// NewSQLEditLockStore creates a new SQLEditLockStore.
+func NewSQLEditLockStore(db *sql.DB) *SQLEditLockStore {
+	return &SQLEditLockStore{db: db}
+}
+
+// Get fetches the lock of an entry.
+func (s *SQLEditLockStore) Get(ctx context.Context, entryID int) (*models.EditLock, error) {
+	query := `SELECT entry_id, user_id, acquired_at, expires_at FROM entry_edit_locks WHERE entry_id = ?`
+	lock := &models.EditLock{}
+	err := s.db.QueryRowContext(ctx, query, entryID).Scan(&lock.EntryID, &lock.UserID, &lock.AcquiredAt, &lock.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
+// Acquire takes or renews the lock in a single statement, so that two teachers cannot both take an expired lock.
+// A renewal keeps the time the lock was first acquired.
+func (s *SQLEditLockStore) Acquire(ctx context.Context, lock *models.EditLock, now time.Time) (*models.EditLock, error) {
+	query := `INSERT INTO entry_edit_locks (entry_id, user_id, acquired_at, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(entry_id) DO UPDATE SET
+			acquired_at = CASE WHEN entry_edit_locks.user_id = excluded.user_id THEN entry_edit_locks.acquired_at ELSE excluded.acquired_at END,
+			user_id = excluded.user_id,
+			expires_at = excluded.expires_at
+		WHERE entry_edit_locks.user_id = excluded.user_id OR entry_edit_locks.expires_at <= ?`
+	if _, err := s.db.ExecContext(ctx, query, lock.EntryID, lock.UserID, lock.AcquiredAt.UTC(), lock.ExpiresAt.UTC(), now.UTC()); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, lock.EntryID)
+}
+
+// Release removes the lock of an entry held by the user.
+func (s *SQLEditLockStore) Release(ctx context.Context, entryID int, userID int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM entry_edit_locks WHERE entry_id = ? AND user_id = ?`, entryID, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
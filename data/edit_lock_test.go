@@ -0,0 +1,69 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLEditLockStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	maria, err := dal.Users.Create(t.Context(), &models.User{Username: "maria", PasswordHash: "hash", Role: "teacher"})
+	require.NoError(t, err)
+	thomas, err := dal.Users.Create(t.Context(), &models.User{Username: "thomas", PasswordHash: "hash", Role: "teacher"})
+	require.NoError(t, err)
+	childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2020, time.March, 15)})
+	require.NoError(t, err)
+	teacherID, err := dal.Teachers.Create(t.Context(), &models.Teacher{FirstName: "Maria", LastName: "Schmidt", Username: "maria"})
+	require.NoError(t, err)
+	categoryID, err := dal.Categories.Create(t.Context(), &models.Category{Name: "Motorik"})
+	require.NoError(t, err)
+	entryID, err := dal.DocumentationEntries.Create(t.Context(), &models.DocumentationEntry{ChildID: childID, TeacherID: teacherID, CategoryID: categoryID, ObservationDate: models.NewDate(2025, time.March, 4), ObservationDescription: "Balanciert über den Baumstamm."})
+	require.NoError(t, err)
+
+	store := data.NewSQLEditLockStore(db)
+	_, err = store.Get(t.Context(), entryID)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+
+	now := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+	lockOf := func(userID int, at time.Time) *models.EditLock {
+		return &models.EditLock{EntryID: entryID, UserID: userID, AcquiredAt: at, ExpiresAt: at.Add(time.Minute)}
+	}
+
+	lock, err := store.Acquire(t.Context(), lockOf(maria, now), now)
+	require.NoError(t, err)
+	assert.Equal(t, maria, lock.UserID)
+	assert.True(t, lock.ExpiresAt.Equal(now.Add(time.Minute)))
+
+	// Renewing keeps the time the lock was acquired
+	renewed, err := store.Acquire(t.Context(), lockOf(maria, now.Add(30*time.Second)), now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.True(t, renewed.AcquiredAt.Equal(now))
+	assert.True(t, renewed.ExpiresAt.Equal(now.Add(90*time.Second)))
+
+	// Another user gets the current lock back until it expired
+	held, err := store.Acquire(t.Context(), lockOf(thomas, now.Add(time.Minute)), now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, maria, held.UserID)
+	taken, err := store.Acquire(t.Context(), lockOf(thomas, now.Add(2*time.Minute)), now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, thomas, taken.UserID)
+	assert.True(t, taken.AcquiredAt.Equal(now.Add(2*time.Minute)))
+
+	assert.ErrorIs(t, store.Release(t.Context(), entryID, maria), data.ErrNotFound)
+	require.NoError(t, store.Release(t.Context(), entryID, thomas))
+	_, err = store.Get(t.Context(), entryID)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+}
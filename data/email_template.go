@@ -0,0 +1,78 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"kitadoc-backend/models"
+)
+
+// EmailTemplateStore defines the interface for the published versions of the email templates.
+type EmailTemplateStore interface {
+	Publish(ctx context.Context, template *models.EmailTemplate) (int, error)
+	GetCurrent(ctx context.Context, key string) (*models.EmailTemplate, error)
+	GetVersions(ctx context.Context, key string) ([]models.EmailTemplate, error)
+}
+
+// SQLEmailTemplateStore implements EmailTemplateStore using database/sql.
+type SQLEmailTemplateStore struct {
+	db *sql.DB
+}
+
+// NewSQLEmailTemplateStore creates a new SQLEmailTemplateStore.
+func NewSQLEmailTemplateStore(db *sql.DB) *SQLEmailTemplateStore {
+	return &SQLEmailTemplateStore{db: db}
+}
+
+// Publish inserts the next version of a template, which becomes the current one, and returns its version number.
+func (s *SQLEmailTemplateStore) Publish(ctx context.Context, template *models.EmailTemplate) (int, error) {
+	query := `INSERT INTO email_templates (template_key, version, subject, body, change_summary, published_by_user_id)
+		SELECT ?, COALESCE(MAX(version), 0) + 1, ?, ?, ?, ? FROM email_templates WHERE template_key = ?
+		RETURNING version`
+	var version int
+	err := s.db.QueryRowContext(ctx, query, template.Key, template.Subject, template.Body, template.ChangeSummary, template.PublishedByUserID, template.Key).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// GetCurrent fetches the latest version of a template. It returns ErrNotFound if none has been published.
+func (s *SQLEmailTemplateStore) GetCurrent(ctx context.Context, key string) (*models.EmailTemplate, error) {
+	query := `SELECT template_key, version, subject, body, change_summary, published_by_user_id, published_at FROM email_templates WHERE template_key = ? ORDER BY version DESC LIMIT 1`
+	template := &models.EmailTemplate{}
+	err := s.db.QueryRowContext(ctx, query, key).Scan(&template.Key, &template.Version, &template.Subject, &template.Body, &template.ChangeSummary, &template.PublishedByUserID, &template.PublishedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+// GetVersions fetches all published versions of a template, newest first.
+func (s *SQLEmailTemplateStore) GetVersions(ctx context.Context, key string) ([]models.EmailTemplate, error) {
+	query := `SELECT template_key, version, subject, body, change_summary, published_by_user_id, published_at FROM email_templates WHERE template_key = ? ORDER BY version DESC`
+	rows, err := s.db.QueryContext(ctx, query, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var templates []models.EmailTemplate
+	for rows.Next() {
+		template := models.EmailTemplate{}
+		if err := rows.Scan(&template.Key, &template.Version, &template.Subject, &template.Body, &template.ChangeSummary, &template.PublishedByUserID, &template.PublishedAt); err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
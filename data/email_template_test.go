@@ -0,0 +1,72 @@
+package data_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLEmailTemplateStore_PublishAndGetCurrent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLEmailTemplateStore(db)
+	adminID := 1
+	publishedAt := time.Date(2025, time.May, 2, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`INSERT INTO email_templates .* SELECT \?, COALESCE\(MAX\(version\), 0\) \+ 1, .* WHERE template_key = \? RETURNING version`).
+		WithArgs(models.EmailTemplateReminder, "Erinnerung", "{{message}}", "Kürzer", &adminID, models.EmailTemplateReminder).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	version, err := store.Publish(t.Context(), &models.EmailTemplate{Key: models.EmailTemplateReminder, Subject: "Erinnerung", Body: "{{message}}", ChangeSummary: "Kürzer", PublishedByUserID: &adminID})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	columns := []string{"template_key", "version", "subject", "body", "change_summary", "published_by_user_id", "published_at"}
+	query := regexp.QuoteMeta(`SELECT template_key, version, subject, body, change_summary, published_by_user_id, published_at FROM email_templates WHERE template_key = ? ORDER BY version DESC LIMIT 1`)
+	mock.ExpectQuery(query).
+		WithArgs(models.EmailTemplateReminder).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(models.EmailTemplateReminder, 2, "Erinnerung", "{{message}}", "Kürzer", adminID, publishedAt))
+	current, err := store.GetCurrent(t.Context(), models.EmailTemplateReminder)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, current.Version)
+	assert.Equal(t, publishedAt, *current.PublishedAt)
+
+	mock.ExpectQuery(query).
+		WithArgs(models.EmailTemplateRejection).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = store.GetCurrent(t.Context(), models.EmailTemplateRejection)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLEmailTemplateStore_GetVersions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLEmailTemplateStore(db)
+	columns := []string{"template_key", "version", "subject", "body", "change_summary", "published_by_user_id", "published_at"}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT template_key, version, subject, body, change_summary, published_by_user_id, published_at FROM email_templates WHERE template_key = ? ORDER BY version DESC`)).
+		WithArgs(models.EmailTemplateRejection).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(models.EmailTemplateRejection, 2, "Neu", "{{reason}}", "", nil, time.Now()).
+			AddRow(models.EmailTemplateRejection, 1, "Alt", "{{reason}}", "", nil, time.Now()))
+
+	versions, err := store.GetVersions(t.Context(), models.EmailTemplateRejection)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "Neu", versions[0].Subject)
+	assert.Nil(t, versions[0].PublishedByUserID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
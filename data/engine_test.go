@@ -0,0 +1,154 @@
+package data_test
+
+import (
+	"database/sql"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postgresTestDSN names the environment variable with the DSN of a PostgreSQL database for the tests. The database
+// is emptied by the tests, it must not hold any data worth keeping.
+const postgresTestDSN = "KINDERGARTEN_TEST_POSTGRES_DSN"
+
+// openEngine opens an empty, migrated database of the driver. PostgreSQL is skipped without a test database.
+func openEngine(t *testing.T, driver data.Driver) *sql.DB {
+	t.Helper()
+	dsn := "file:" + t.TempDir() + "/test.db?_pragma=foreign_keys(1)"
+	if driver == data.DriverPostgres {
+		dsn = os.Getenv(postgresTestDSN)
+		if dsn == "" {
+			t.Skipf("%s is not set", postgresTestDSN)
+		}
+	}
+	db, err := data.Open(driver, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	if driver == data.DriverPostgres {
+		_, err := db.Exec(`DROP SCHEMA public CASCADE; CREATE SCHEMA public`)
+		require.NoError(t, err)
+	}
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	_, err = data.RunDataMigrations(db, data.DataMigrations, []byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	return db
+}
+
+func TestDataLayerOnBothEngines(t *testing.T) {
+	for _, driver := range []data.Driver{data.DriverSQLite, data.DriverPostgres} {
+		t.Run(string(driver), func(t *testing.T) {
+			db := openEngine(t, driver)
+			assert.Equal(t, driver, data.DriverOf(db))
+			dal := data.NewDAL(db, driver, []byte("0123456789abcdef0123456789abcdef"))
+
+			userID, err := dal.Users.Create(t.Context(), &models.User{Username: "maria.schmidt", PasswordHash: "hash", Role: string(data.RoleTeacher)})
+			require.NoError(t, err)
+			teacherID, err := dal.Teachers.Create(t.Context(), &models.Teacher{FirstName: "Maria", LastName: "Schmidt", Username: "maria.schmidt"})
+			require.NoError(t, err)
+			childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+			require.NoError(t, err)
+			categoryID, err := dal.Categories.Create(t.Context(), &models.Category{Name: "Sprache"})
+			require.NoError(t, err)
+
+			child, err := dal.Children.GetByID(t.Context(), childID)
+			require.NoError(t, err)
+			assert.Equal(t, "Anna", child.FirstName)
+			assert.Equal(t, models.NewDate(2021, time.March, 15), child.Birthdate)
+
+			t.Run("entries are created, paged and approved", func(t *testing.T) {
+				entries := make([]models.DocumentationEntry, 5)
+				for i := range entries {
+					entries[i] = models.DocumentationEntry{ChildID: childID, TeacherID: teacherID, CategoryID: categoryID,
+						ObservationDate: models.NewDate(2024, time.March, 1+i), ObservationDescription: "Spielt im Sandkasten."}
+				}
+				ids, err := dal.DocumentationEntries.CreateMany(t.Context(), entries)
+				require.NoError(t, err)
+				require.Len(t, ids, 5)
+
+				page, err := dal.DocumentationEntries.List(t.Context(), models.ListQuery{Offset: 3}.OrderBy("id", false))
+				require.NoError(t, err)
+				if assert.Len(t, page, 2) {
+					assert.Equal(t, ids[3], page[0].ID)
+				}
+
+				require.NoError(t, dal.DocumentationEntries.ApproveEntry(t.Context(), ids[0], teacherID, nil))
+				entry, err := dal.DocumentationEntries.GetByID(t.Context(), ids[0])
+				require.NoError(t, err)
+				assert.True(t, entry.IsApproved)
+				assert.Equal(t, models.NewDate(2024, time.March, 1), entry.ObservationDate)
+				assert.Equal(t, "Spielt im Sandkasten.", entry.ObservationDescription)
+			})
+
+			t.Run("constraint violations", func(t *testing.T) {
+				_, err := dal.Assignments.Create(t.Context(), &models.Assignment{ChildID: childID, TeacherID: teacherID, AssignmentType: models.AssignmentTypePrimary, StartDate: time.Now()})
+				require.NoError(t, err)
+				_, err = dal.Assignments.Create(t.Context(), &models.Assignment{ChildID: childID, TeacherID: teacherID, AssignmentType: models.AssignmentTypePrimary, StartDate: time.Now()})
+				assert.ErrorIs(t, err, data.ErrConflict)
+				assert.ErrorIs(t, dal.Teachers.Delete(t.Context(), teacherID), data.ErrForeignKeyConstraint)
+			})
+
+			t.Run("triggers", func(t *testing.T) {
+				id, err := dal.Medications.Create(t.Context(), &models.MedicationAdministration{ChildID: childID, Medication: "Ibuprofen Saft", Dose: "2,5 ml",
+					InstructionReference: "Vollmacht", AdministeredAt: time.Now().UTC(), AdministeredByTeacherID: teacherID, RecordedByUserID: &userID})
+				require.NoError(t, err)
+				_, err = db.Exec(`UPDATE medication_administrations SET dose = 'x' WHERE administration_id = ?`, id)
+				assert.ErrorContains(t, err, "medication administrations are read-only")
+
+				before, err := dal.Teachers.GetByID(t.Context(), teacherID)
+				require.NoError(t, err)
+				_, err = db.Exec(`UPDATE teachers SET deactivated_at = CURRENT_TIMESTAMP, updated_at = ? WHERE teacher_id = ?`, before.UpdatedAt.Add(-time.Hour), teacherID)
+				require.NoError(t, err)
+				after, err := dal.Teachers.GetByID(t.Context(), teacherID)
+				require.NoError(t, err)
+				assert.False(t, after.UpdatedAt.Before(before.UpdatedAt), "updated_at is kept current")
+			})
+
+			t.Run("engine-specific features", func(t *testing.T) {
+				var applied int
+				require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM data_migrations`).Scan(&applied))
+				assert.Equal(t, len(data.DataMigrations), applied, "all data migrations are recorded, also those that only run on SQLite")
+				if driver != data.DriverPostgres {
+					return
+				}
+				_, err := dal.DemoSnapshots.Create(t.Context(), time.Now())
+				assert.ErrorIs(t, err, data.ErrUnsupportedDriver)
+				assert.ErrorIs(t, dal.Fixtures.Clear(t.Context()), data.ErrUnsupportedDriver)
+			})
+		})
+	}
+}
+
+// TestMigrationParity makes sure that new schema migrations are written for both engines.
+func TestMigrationParity(t *testing.T) {
+	versions := func(dir string) map[string]bool {
+		files, err := fs.Glob(migrations.Files, dir+"/*.up.sql")
+		require.NoError(t, err)
+		found := map[string]bool{}
+		for _, file := range files {
+			found[strings.SplitN(path.Base(file), "_", 2)[0]] = true
+		}
+		return found
+	}
+	sqlite := versions("migrations")
+	postgres := versions("postgres")
+
+	// The PostgreSQL schema starts with a baseline of the SQLite migrations up to version 51.
+	for version := range sqlite {
+		if version > "000051" {
+			assert.True(t, postgres[version], "migration %s is missing for PostgreSQL", version)
+		}
+	}
+	for version := range postgres {
+		assert.True(t, sqlite[version], "migration %s is missing for SQLite", version)
+	}
+}
@@ -1,10 +1,38 @@
 package data
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+)
 
 var (
 	ErrNotFound             = errors.New("record not found")
 	ErrConflict             = errors.New("record conflict")
 	ErrInvalidInput         = errors.New("invalid input")
 	ErrForeignKeyConstraint = errors.New("foreign key constraint violation")
+	ErrLocked               = errors.New("record is locked")
+	// ErrUnsupportedDriver is returned by the stores that only work on some engines, e.g. the demo snapshots.
+	ErrUnsupportedDriver = errors.New("not supported by the database driver")
 )
+
+// isUniqueConstraintError reports whether err is a UNIQUE or PRIMARY KEY constraint violation.
+func isUniqueConstraintError(err error) bool {
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		return liteErr.Code() == 2067 || liteErr.Code() == 1555
+	}
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isForeignKeyError reports whether err is a FOREIGN KEY constraint violation.
+func isForeignKeyError(err error) bool {
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		return liteErr.Code() == 787 || liteErr.Code() == 1811
+	}
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
@@ -0,0 +1,137 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// fixtureKeptTables survive a reset of the fixture database. Next to the state of the schema, the sessions are
+// kept, so that the logins of the frontend tests stay valid, the seeded accounts keep their IDs.
+var fixtureKeptTables = []string{"schema_migrations", "data_migrations", "auth_sessions"}
+
+// fixtureTimestampColumns are set by the stores and the database when rows are written.
+var fixtureTimestampColumns = []string{"created_at", "updated_at"}
+
+// FixtureStore defines the interface for resetting the database of the fixture server the frontend tests run against.
+type FixtureStore interface {
+	Clear(ctx context.Context) error
+	FreezeTimestamps(ctx context.Context, at time.Time) error
+}
+
+// SQLFixtureStore implements FixtureStore using database/sql. It reads the schema from the SQLite catalog, the
+// fixture profile refuses other drivers and the store returns ErrUnsupportedDriver for them.
+type SQLFixtureStore struct {
+	db     *sql.DB
+	driver Driver
+}
+
+// NewSQLFixtureStore creates a new SQLFixtureStore.
+func NewSQLFixtureStore(db *sql.DB, driver Driver) *SQLFixtureStore {
+	return &SQLFixtureStore{db: db, driver: driver}
+}
+
+// requireSQLite fails fast on other engines, before any statement runs.
+func (s *SQLFixtureStore) requireSQLite() error {
+	if s.driver != DriverSQLite {
+		return fmt.Errorf("fixtures require the %s driver: %w", DriverSQLite, ErrUnsupportedDriver)
+	}
+	return nil
+}
+
+// Clear deletes all rows and restarts the IDs at 1, so that data seeded afterwards always gets the same IDs.
+func (s *SQLFixtureStore) Clear(ctx context.Context) error {
+	if err := s.requireSQLite(); err != nil {
+		return err
+	}
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close() //nolint:errcheck
+
+	// Foreign keys cannot be switched off inside a transaction, the tables are cleared in any order.
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON") //nolint:errcheck
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	tables, err := queryStrings(ctx, tx, fmt.Sprintf("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%%' AND name NOT IN ('%s') ORDER BY name", strings.Join(fixtureKeptTables, "', '")))
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM "%s"`, table)); err != nil {
+			return err
+		}
+	}
+	sequences, err := queryStrings(ctx, tx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name = 'sqlite_sequence'")
+	if err != nil {
+		return err
+	}
+	if len(sequences) > 0 {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM sqlite_sequence"); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// FreezeTimestamps sets the creation and modification times of all rows to at. The updated_at triggers
+// are dropped while the rows are rewritten, they would set the current time instead.
+func (s *SQLFixtureStore) FreezeTimestamps(ctx context.Context, at time.Time) error {
+	if err := s.requireSQLite(); err != nil {
+		return err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	triggerNames, err := queryStrings(ctx, tx, "SELECT name FROM sqlite_master WHERE type = 'trigger' ORDER BY name")
+	if err != nil {
+		return err
+	}
+	triggers, err := queryStrings(ctx, tx, "SELECT sql FROM sqlite_master WHERE type = 'trigger' ORDER BY name")
+	if err != nil {
+		return err
+	}
+	for _, name := range triggerNames {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DROP TRIGGER "%s"`, name)); err != nil {
+			return err
+		}
+	}
+
+	tables, err := queryStrings(ctx, tx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return err
+	}
+	value := at.UTC().Format(storedTimestampFormat)
+	for _, table := range tables {
+		columns, err := queryStrings(ctx, tx, fmt.Sprintf("SELECT name FROM pragma_table_info('%s') WHERE name IN ('%s') ORDER BY cid", table, strings.Join(fixtureTimestampColumns, "', '")))
+		if err != nil {
+			return err
+		}
+		for _, column := range columns {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE "%s" SET "%s" = ?`, table, column), value); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, trigger := range triggers {
+		if _, err := tx.ExecContext(ctx, trigger); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,51 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLFixtureStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	store := data.NewSQLFixtureStore(db, data.DriverSQLite)
+	teachers := data.NewSQLTeacherStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	frozen := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+
+	_, err = teachers.Create(t.Context(), &models.Teacher{FirstName: "Maria", LastName: "Schmidt", Username: "maria"})
+	require.NoError(t, err)
+	require.NoError(t, store.Clear(t.Context()))
+	id, err := teachers.Create(t.Context(), &models.Teacher{FirstName: "Thomas", LastName: "Weber", Username: "thomas"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, id, "IDs restart after clearing")
+
+	require.NoError(t, store.FreezeTimestamps(t.Context(), frozen))
+	teacher, err := teachers.GetByID(t.Context(), id)
+	require.NoError(t, err)
+	assert.True(t, teacher.CreatedAt.Equal(frozen))
+	assert.True(t, teacher.UpdatedAt.Equal(frozen))
+
+	var triggers int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = 'trg_teachers_updated_at'`).Scan(&triggers))
+	assert.Equal(t, 1, triggers, "triggers are restored")
+	var migrationsLeft int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&migrationsLeft))
+	assert.Equal(t, 1, migrationsLeft, "the schema version is kept")
+}
+
+func TestSQLFixtureStoreRequiresSQLite(t *testing.T) {
+	store := data.NewSQLFixtureStore(nil, data.DriverPostgres)
+
+	assert.ErrorIs(t, store.Clear(t.Context()), data.ErrUnsupportedDriver)
+	assert.ErrorIs(t, store.FreezeTimestamps(t.Context(), time.Now()), data.ErrUnsupportedDriver)
+}
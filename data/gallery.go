@@ -0,0 +1,334 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// GalleryStore defines the interface for GalleryPhoto and PhotoConsent data operations.
+type GalleryStore interface {
+	// Create inserts a photo with its image and tagged children.
+	Create(ctx context.Context, photo *models.GalleryPhoto) (int, error)
+	GetByID(ctx context.Context, id int) (*models.GalleryPhoto, error)
+	// GetWithContent fetches a photo with its image.
+	GetWithContent(ctx context.Context, id int) (*models.GalleryPhoto, error)
+	// Update changes the caption and replaces the tagged children of a photo.
+	Update(ctx context.Context, photo *models.GalleryPhoto) error
+	Delete(ctx context.Context, id int) error
+	// GetForGroup fetches the photos of the gallery of a group, the latest first.
+	GetForGroup(ctx context.Context, groupID int) ([]models.GalleryPhoto, error)
+	// GetForChild fetches the photos a child is tagged on, the latest first.
+	GetForChild(ctx context.Context, childID int) ([]models.GalleryPhoto, error)
+	// SetConsent creates or replaces the photo consent of a child.
+	SetConsent(ctx context.Context, consent *models.PhotoConsent) error
+	GetConsent(ctx context.Context, childID int) (*models.PhotoConsent, error)
+	DeleteConsent(ctx context.Context, childID int) error
+	// GetConsentedChildIDs fetches the IDs of all children with photo consent.
+	GetConsentedChildIDs(ctx context.Context) (map[int]bool, error)
+}
+
+// SQLGalleryStore implements GalleryStore using database/sql.
+type SQLGalleryStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLGalleryStore creates a new SQLGalleryStore.
+func NewSQLGalleryStore(db *sql.DB, encryptionKey []byte) *SQLGalleryStore {
+	return &SQLGalleryStore{db: db, encryptionKey: encryptionKey}
+}
+
+const galleryPhotoColumns = `photo_id, group_id, caption, content_type, uploaded_by_user_id, envelope, created_at, updated_at`
+
+// Create inserts a new photo with its tagged children into the database.
+func (s *SQLGalleryStore) Create(ctx context.Context, photo *models.GalleryPhoto) (int, error) {
+	caption, err := s.encryptCaption(photo.Caption)
+	if err != nil {
+		return 0, err
+	}
+	envelope, err := encodeEnvelope(photo.Envelope)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	contentHash, err := putAttachmentBlob(ctx, tx, photo.Content, s.encryptionKey)
+	if err != nil {
+		return 0, err
+	}
+	query := `INSERT INTO gallery_photos (group_id, caption, content, content_hash, content_type, uploaded_by_user_id, envelope)
+		VALUES (?, ?, '', ?, ?, ?, ?)
+		RETURNING photo_id`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, photo.GroupID, caption, contentHash, photo.ContentType, photo.UploadedByUserID, envelope).Scan(&id); err != nil {
+		return 0, galleryConstraintError(err)
+	}
+	if err := insertPhotoChildren(ctx, tx, id, photo.ChildIDs); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a photo with its tagged children by ID from the database.
+func (s *SQLGalleryStore) GetByID(ctx context.Context, id int) (*models.GalleryPhoto, error) {
+	query := `SELECT ` + galleryPhotoColumns + ` FROM gallery_photos WHERE photo_id = ?`
+	photos, err := s.queryPhotos(ctx, query, `photo_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(photos) == 0 {
+		return nil, ErrNotFound
+	}
+	return &photos[0], nil
+}
+
+// GetWithContent fetches a photo with its tagged children and its image from the database.
+func (s *SQLGalleryStore) GetWithContent(ctx context.Context, id int) (*models.GalleryPhoto, error) {
+	photo, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	content, err := attachmentContent(ctx, s.db, "gallery_photos", "photo_id", id, s.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	photo.Content = content
+	return photo, nil
+}
+
+// Update updates the caption of a photo and replaces its tagged children.
+func (s *SQLGalleryStore) Update(ctx context.Context, photo *models.GalleryPhoto) error {
+	caption, err := s.encryptCaption(photo.Caption)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	result, err := tx.ExecContext(ctx, `UPDATE gallery_photos SET caption = ?, updated_at = CURRENT_TIMESTAMP WHERE photo_id = ?`, caption, photo.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM gallery_photo_children WHERE photo_id = ?`, photo.ID); err != nil {
+		return err
+	}
+	if err := insertPhotoChildren(ctx, tx, photo.ID, photo.ChildIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Delete deletes a photo by ID from the database.
+func (s *SQLGalleryStore) Delete(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM gallery_photos WHERE photo_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetForGroup fetches the photos of a group from the database.
+func (s *SQLGalleryStore) GetForGroup(ctx context.Context, groupID int) ([]models.GalleryPhoto, error) {
+	query := `SELECT ` + galleryPhotoColumns + ` FROM gallery_photos WHERE group_id = ? ORDER BY created_at DESC, photo_id DESC`
+	return s.queryPhotos(ctx, query, `photo_id IN (SELECT photo_id FROM gallery_photos WHERE group_id = ?)`, groupID)
+}
+
+// GetForChild fetches the photos a child is tagged on from the database.
+func (s *SQLGalleryStore) GetForChild(ctx context.Context, childID int) ([]models.GalleryPhoto, error) {
+	query := `SELECT ` + galleryPhotoColumns + ` FROM gallery_photos
+		WHERE photo_id IN (SELECT photo_id FROM gallery_photo_children WHERE child_id = ?) ORDER BY created_at DESC, photo_id DESC`
+	return s.queryPhotos(ctx, query, `photo_id IN (SELECT photo_id FROM gallery_photo_children WHERE child_id = ?)`, childID)
+}
+
+// SetConsent inserts the photo consent of a child into the database, replacing an existing consent.
+func (s *SQLGalleryStore) SetConsent(ctx context.Context, consent *models.PhotoConsent) error {
+	reference, err := Encrypt(consent.ConsentReference, s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt photo consent reference: %w", err)
+	}
+	query := `INSERT INTO photo_consents (child_id, consent_reference, recorded_by_user_id) VALUES (?, ?, ?)
+		ON CONFLICT (child_id) DO UPDATE SET
+			consent_reference = excluded.consent_reference,
+			recorded_by_user_id = excluded.recorded_by_user_id,
+			updated_at = CURRENT_TIMESTAMP`
+	if _, err := s.db.ExecContext(ctx, query, consent.ChildID, reference, consent.RecordedByUserID); err != nil {
+		return galleryConstraintError(err)
+	}
+	return nil
+}
+
+// GetConsent fetches the photo consent of a child from the database.
+func (s *SQLGalleryStore) GetConsent(ctx context.Context, childID int) (*models.PhotoConsent, error) {
+	query := `SELECT child_id, consent_reference, recorded_by_user_id, created_at, updated_at FROM photo_consents WHERE child_id = ?`
+	consent := &models.PhotoConsent{}
+	var reference string
+	err := s.db.QueryRowContext(ctx, query, childID).Scan(&consent.ChildID, &reference, &consent.RecordedByUserID, &consent.CreatedAt, &consent.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	consent.ConsentReference, err = Decrypt(reference, s.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt photo consent reference: %w", err)
+	}
+	return consent, nil
+}
+
+// DeleteConsent deletes the photo consent of a child from the database.
+func (s *SQLGalleryStore) DeleteConsent(ctx context.Context, childID int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM photo_consents WHERE child_id = ?`, childID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetConsentedChildIDs fetches the IDs of the children with photo consent from the database.
+func (s *SQLGalleryStore) GetConsentedChildIDs(ctx context.Context) (map[int]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT child_id FROM photo_consents`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	consented := make(map[int]bool)
+	for rows.Next() {
+		var childID int
+		if err := rows.Scan(&childID); err != nil {
+			return nil, err
+		}
+		consented[childID] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return consented, nil
+}
+
+func (s *SQLGalleryStore) encryptCaption(caption *string) (*string, error) {
+	if caption == nil {
+		return nil, nil
+	}
+	encrypted, err := Encrypt(*caption, s.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt photo caption: %w", err)
+	}
+	return &encrypted, nil
+}
+
+// queryPhotos fetches the photos of the query and fills in their tagged children, which are selected by the given
+// condition on gallery_photo_children with the same arguments.
+func (s *SQLGalleryStore) queryPhotos(ctx context.Context, query string, childCondition string, args ...any) ([]models.GalleryPhoto, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	photos := []models.GalleryPhoto{}
+	for rows.Next() {
+		photo := models.GalleryPhoto{ChildIDs: []int{}}
+		var caption, envelope sql.NullString
+		if err := rows.Scan(&photo.ID, &photo.GroupID, &caption, &photo.ContentType, &photo.UploadedByUserID, &envelope, &photo.CreatedAt,
+			&photo.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if photo.Envelope, err = decodeEnvelope(envelope); err != nil {
+			return nil, err
+		}
+		if caption.Valid {
+			decrypted, err := Decrypt(caption.String, s.encryptionKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt photo caption: %w", err)
+			}
+			photo.Caption = &decrypted
+		}
+		photos = append(photos, photo)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(photos) == 0 {
+		return photos, nil
+	}
+
+	byID := make(map[int]*models.GalleryPhoto, len(photos))
+	for i := range photos {
+		byID[photos[i].ID] = &photos[i]
+	}
+	childRows, err := s.db.QueryContext(ctx, `SELECT photo_id, child_id FROM gallery_photo_children WHERE `+childCondition+` ORDER BY child_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer childRows.Close() //nolint:errcheck
+	for childRows.Next() {
+		var photoID, childID int
+		if err := childRows.Scan(&photoID, &childID); err != nil {
+			return nil, err
+		}
+		if photo, ok := byID[photoID]; ok {
+			photo.ChildIDs = append(photo.ChildIDs, childID)
+		}
+	}
+	if err = childRows.Err(); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+func insertPhotoChildren(ctx context.Context, tx *sql.Tx, photoID int, childIDs []int) error {
+	for _, childID := range childIDs {
+		// Children tagged twice are stored once.
+		if _, err := tx.ExecContext(ctx, `INSERT INTO gallery_photo_children (photo_id, child_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, photoID, childID); err != nil {
+			return galleryConstraintError(err)
+		}
+	}
+	return nil
+}
+
+func galleryConstraintError(err error) error {
+	if isForeignKeyError(err) {
+		return ErrForeignKeyConstraint
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLGalleryStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	groupID, err := dal.Groups.Create(t.Context(), &models.Group{Name: "Sonnengruppe", Capacity: 20})
+	require.NoError(t, err)
+	anna, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	require.NoError(t, err)
+	ben, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Ben", LastName: "Schulz", Birthdate: models.NewDate(2021, time.June, 2)})
+	require.NoError(t, err)
+
+	store := dal.Gallery
+	caption := "Laternenumzug im Garten"
+	content := []byte("\x89PNG\r\n\x1a\nimage")
+	id, err := store.Create(t.Context(), &models.GalleryPhoto{GroupID: groupID, Caption: &caption, ContentType: "image/png", ChildIDs: []int{ben, anna, anna}, Content: content})
+	require.NoError(t, err)
+	otherID, err := store.Create(t.Context(), &models.GalleryPhoto{GroupID: groupID, ContentType: "image/jpeg", ChildIDs: []int{ben}, Content: content})
+	require.NoError(t, err)
+
+	var storedCaption string
+	require.NoError(t, db.QueryRow(`SELECT caption FROM gallery_photos WHERE photo_id = ?`, id).Scan(&storedCaption))
+	assert.NotContains(t, storedCaption, "Laternenumzug", "the caption must be stored encrypted")
+
+	photo, err := store.GetByID(t.Context(), id)
+	require.NoError(t, err)
+	assert.Equal(t, caption, *photo.Caption)
+	assert.Equal(t, []int{anna, ben}, photo.ChildIDs)
+	assert.Nil(t, photo.Content, "the image is only loaded on request")
+	photo, err = store.GetWithContent(t.Context(), id)
+	require.NoError(t, err)
+	assert.Equal(t, content, photo.Content)
+
+	photos, err := store.GetForGroup(t.Context(), groupID)
+	require.NoError(t, err)
+	require.Len(t, photos, 2)
+	assert.Equal(t, otherID, photos[0].ID)
+	assert.Equal(t, []int{ben}, photos[0].ChildIDs)
+	photos, err = store.GetForChild(t.Context(), anna)
+	require.NoError(t, err)
+	require.Len(t, photos, 1)
+	assert.Equal(t, []int{anna, ben}, photos[0].ChildIDs, "all tagged children are loaded")
+
+	photo.Caption = nil
+	photo.ChildIDs = []int{ben}
+	require.NoError(t, store.Update(t.Context(), photo))
+	photo, err = store.GetByID(t.Context(), id)
+	require.NoError(t, err)
+	assert.Nil(t, photo.Caption)
+	assert.Equal(t, []int{ben}, photo.ChildIDs)
+	photo.ChildIDs = []int{999}
+	assert.ErrorIs(t, store.Update(t.Context(), photo), data.ErrForeignKeyConstraint)
+	assert.ErrorIs(t, store.Update(t.Context(), &models.GalleryPhoto{ID: 999}), data.ErrNotFound)
+
+	// Photo consent
+	require.NoError(t, store.SetConsent(t.Context(), &models.PhotoConsent{ChildID: anna, ConsentReference: "Einwilligung vom 01.08.2024"}))
+	require.NoError(t, store.SetConsent(t.Context(), &models.PhotoConsent{ChildID: anna, ConsentReference: "Einwilligung vom 15.08.2024"}))
+	assert.ErrorIs(t, store.SetConsent(t.Context(), &models.PhotoConsent{ChildID: 999, ConsentReference: "x"}), data.ErrForeignKeyConstraint)
+	consent, err := store.GetConsent(t.Context(), anna)
+	require.NoError(t, err)
+	assert.Equal(t, "Einwilligung vom 15.08.2024", consent.ConsentReference)
+	_, err = store.GetConsent(t.Context(), ben)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+	consented, err := store.GetConsentedChildIDs(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, map[int]bool{anna: true}, consented)
+	require.NoError(t, store.DeleteConsent(t.Context(), anna))
+	assert.ErrorIs(t, store.DeleteConsent(t.Context(), anna), data.ErrNotFound)
+
+	// Deleting the group deletes its gallery
+	require.NoError(t, store.Delete(t.Context(), otherID))
+	assert.ErrorIs(t, store.Delete(t.Context(), otherID), data.ErrNotFound)
+	require.NoError(t, dal.Groups.Delete(t.Context(), groupID))
+	_, err = store.GetByID(t.Context(), id)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+}
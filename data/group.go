@@ -0,0 +1,255 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"kitadoc-backend/models"
+)
+
+// GroupStore defines the interface for Group data operations.
+type GroupStore interface {
+	Create(ctx context.Context, group *models.Group) (int, error)
+	GetByID(ctx context.Context, id int) (*models.Group, error)
+	Update(ctx context.Context, group *models.Group) error
+	Delete(ctx context.Context, id int) error
+	GetAll(ctx context.Context) ([]models.Group, error)
+	AddChild(ctx context.Context, groupID int, childID int) error
+	RemoveChild(ctx context.Context, groupID int, childID int) error
+	// CountUsage counts the children, entries and open assignments of every group, keyed by group ID.
+	CountUsage(ctx context.Context) (map[int]models.UsageCounts, error)
+}
+
+// SQLGroupStore implements GroupStore using database/sql.
+type SQLGroupStore struct {
+	db *sql.DB
+}
+
+// NewSQLGroupStore creates a new SQLGroupStore.
+func NewSQLGroupStore(db *sql.DB) *SQLGroupStore {
+	return &SQLGroupStore{db: db}
+}
+
+const groupColumns = `group_id, group_name, capacity, min_age_months, max_age_months, room, lead_teacher_id, created_at, updated_at`
+
+func scanGroup(row rowScanner) (*models.Group, error) {
+	group := &models.Group{}
+	err := row.Scan(&group.ID, &group.Name, &group.Capacity, &group.MinAgeMonths, &group.MaxAgeMonths, &group.Room, &group.LeadTeacherID, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	group.AssistantTeacherIDs = []int{}
+	group.ChildIDs = []int{}
+	return group, nil
+}
+
+// Create inserts a new group with its assistant teachers into the database.
+func (s *SQLGroupStore) Create(ctx context.Context, group *models.Group) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `INSERT INTO kita_groups (group_name, capacity, min_age_months, max_age_months, room, lead_teacher_id) VALUES (?, ?, ?, ?, ?, ?) RETURNING group_id`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, group.Name, group.Capacity, group.MinAgeMonths, group.MaxAgeMonths, group.Room, group.LeadTeacherID).Scan(&id); err != nil {
+		if isUniqueConstraintError(err) {
+			return 0, ErrConflict
+		}
+		return 0, err
+	}
+	if err := insertGroupAssistants(ctx, tx, id, group.AssistantTeacherIDs); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a group with its assistant teachers and children by ID from the database.
+func (s *SQLGroupStore) GetByID(ctx context.Context, id int) (*models.Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM kita_groups WHERE group_id = ?`
+	group, err := scanGroup(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	groups := []models.Group{*group}
+	if err := s.loadMembers(ctx, groups); err != nil {
+		return nil, err
+	}
+	return &groups[0], nil
+}
+
+// Update updates an existing group and replaces its assistant teachers.
+func (s *SQLGroupStore) Update(ctx context.Context, group *models.Group) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `UPDATE kita_groups SET group_name = ?, capacity = ?, min_age_months = ?, max_age_months = ?, room = ?, lead_teacher_id = ?, updated_at = CURRENT_TIMESTAMP WHERE group_id = ?`
+	result, err := tx.ExecContext(ctx, query, group.Name, group.Capacity, group.MinAgeMonths, group.MaxAgeMonths, group.Room, group.LeadTeacherID, group.ID)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM group_assistants WHERE group_id = ?`, group.ID); err != nil {
+		return err
+	}
+	if err := insertGroupAssistants(ctx, tx, group.ID, group.AssistantTeacherIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Delete deletes a group by ID from the database. Its children are left without a group.
+func (s *SQLGroupStore) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM kita_groups WHERE group_id = ?`
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetAll fetches all groups with their assistant teachers and children ordered by name.
+func (s *SQLGroupStore) GetAll(ctx context.Context) ([]models.Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM kita_groups ORDER BY group_name, group_id`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var groups []models.Group
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadMembers(ctx, groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// AddChild adds a child to a group, moving it out of its previous group.
+func (s *SQLGroupStore) AddChild(ctx context.Context, groupID int, childID int) error {
+	query := `INSERT INTO group_children (child_id, group_id) VALUES (?, ?) ON CONFLICT (child_id) DO UPDATE SET group_id = excluded.group_id`
+	_, err := s.db.ExecContext(ctx, query, childID, groupID)
+	return err
+}
+
+// RemoveChild removes a child from a group.
+func (s *SQLGroupStore) RemoveChild(ctx context.Context, groupID int, childID int) error {
+	query := `DELETE FROM group_children WHERE group_id = ? AND child_id = ?`
+	result, err := s.db.ExecContext(ctx, query, groupID, childID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func insertGroupAssistants(ctx context.Context, tx *sql.Tx, groupID int, teacherIDs []int) error {
+	for _, teacherID := range teacherIDs {
+		// Teachers listed twice are stored once.
+		if _, err := tx.ExecContext(ctx, `INSERT INTO group_assistants (group_id, teacher_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, groupID, teacherID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadMembers fills in the assistant teachers and children of the given groups.
+func (s *SQLGroupStore) loadMembers(ctx context.Context, groups []models.Group) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	byID := make(map[int]*models.Group, len(groups))
+	for i := range groups {
+		byID[groups[i].ID] = &groups[i]
+	}
+
+	members := []struct {
+		query string
+		add   func(group *models.Group, id int)
+	}{
+		{`SELECT group_id, teacher_id FROM group_assistants ORDER BY teacher_id`, func(group *models.Group, id int) {
+			group.AssistantTeacherIDs = append(group.AssistantTeacherIDs, id)
+		}},
+		{`SELECT group_id, child_id FROM group_children ORDER BY child_id`, func(group *models.Group, id int) {
+			group.ChildIDs = append(group.ChildIDs, id)
+		}},
+	}
+	for _, member := range members {
+		rows, err := s.db.QueryContext(ctx, member.query)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var groupID, id int
+			if err := rows.Scan(&groupID, &id); err != nil {
+				rows.Close() //nolint:errcheck
+				return err
+			}
+			if group, ok := byID[groupID]; ok {
+				member.add(group, id)
+			}
+		}
+		err = rows.Err()
+		rows.Close() //nolint:errcheck
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountUsage counts the children of every group and the entries and open assignments of these children, keyed by group ID.
+func (s *SQLGroupStore) CountUsage(ctx context.Context) (map[int]models.UsageCounts, error) {
+	query := `SELECT gc.group_id, COALESCE(SUM(e.entries), 0), COALESCE(SUM(a.open_assignments), 0), COUNT(*)
+		FROM group_children gc
+		LEFT JOIN (SELECT child_id, COUNT(*) AS entries FROM documentation_entries GROUP BY child_id) e ON e.child_id = gc.child_id
+		LEFT JOIN (SELECT child_id, COUNT(*) AS open_assignments FROM child_teacher_assignments WHERE end_date IS NULL GROUP BY child_id) a ON a.child_id = gc.child_id
+		GROUP BY gc.group_id`
+	return queryUsageCounts(ctx, s.db, query, func(usage *models.UsageCounts) []any {
+		return []any{&usage.Entries, &usage.OpenAssignments, &usage.Children}
+	})
+}
@@ -0,0 +1,159 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// GroupHandoverStore defines the interface for GroupHandover data operations.
+type GroupHandoverStore interface {
+	// Create records a handover and moves the child into the receiving group, both or neither are saved.
+	Create(ctx context.Context, handover *models.GroupHandover) (int, error)
+	GetByID(ctx context.Context, id int) (*models.GroupHandover, error)
+	// GetForChild fetches the handovers of a child, the latest first.
+	GetForChild(ctx context.Context, childID int) ([]models.GroupHandover, error)
+	// GetUnconfirmed fetches the handovers no receiving teacher confirmed yet, the oldest first.
+	GetUnconfirmed(ctx context.Context) ([]models.GroupHandover, error)
+	// Confirm records the confirmation of a handover. It returns ErrConflict if the handover is already confirmed.
+	Confirm(ctx context.Context, id int, confirmation *models.HandoverConfirmation) error
+}
+
+// SQLGroupHandoverStore implements GroupHandoverStore using database/sql.
+type SQLGroupHandoverStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLGroupHandoverStore creates a new SQLGroupHandoverStore.
+func NewSQLGroupHandoverStore(db *sql.DB, encryptionKey []byte) *SQLGroupHandoverStore {
+	return &SQLGroupHandoverStore{db: db, encryptionKey: encryptionKey}
+}
+
+const groupHandoverColumns = `handover_id, child_id, from_group_id, to_group_id, handover_date, created_by_user_id, confirmed_by_teacher_id,
+	confirmed_at, observations_reviewed, care_specifics_reviewed, support_goals_reviewed, parents_informed, confirmation_notes, created_at`
+
+// Create inserts a new handover into the database and moves the child.
+func (s *SQLGroupHandoverStore) Create(ctx context.Context, handover *models.GroupHandover) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `INSERT INTO group_handovers (child_id, from_group_id, to_group_id, handover_date, created_by_user_id) VALUES (?, ?, ?, ?, ?) RETURNING handover_id`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, handover.ChildID, handover.FromGroupID, handover.ToGroupID, handover.HandoverDate, handover.CreatedByUserID).Scan(&id); err != nil {
+		return 0, groupHandoverConstraintError(err)
+	}
+	query = `INSERT INTO group_children (child_id, group_id) VALUES (?, ?) ON CONFLICT (child_id) DO UPDATE SET group_id = excluded.group_id`
+	if _, err := tx.ExecContext(ctx, query, handover.ChildID, handover.ToGroupID); err != nil {
+		return 0, groupHandoverConstraintError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a handover by ID from the database.
+func (s *SQLGroupHandoverStore) GetByID(ctx context.Context, id int) (*models.GroupHandover, error) {
+	handovers, err := s.queryHandovers(ctx, `SELECT `+groupHandoverColumns+` FROM group_handovers WHERE handover_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(handovers) == 0 {
+		return nil, ErrNotFound
+	}
+	return &handovers[0], nil
+}
+
+// GetForChild fetches the handovers of a child from the database.
+func (s *SQLGroupHandoverStore) GetForChild(ctx context.Context, childID int) ([]models.GroupHandover, error) {
+	query := `SELECT ` + groupHandoverColumns + ` FROM group_handovers WHERE child_id = ? ORDER BY handover_date DESC, handover_id DESC`
+	return s.queryHandovers(ctx, query, childID)
+}
+
+// GetUnconfirmed fetches the unconfirmed handovers from the database.
+func (s *SQLGroupHandoverStore) GetUnconfirmed(ctx context.Context) ([]models.GroupHandover, error) {
+	query := `SELECT ` + groupHandoverColumns + ` FROM group_handovers WHERE confirmed_at IS NULL ORDER BY handover_date, handover_id`
+	return s.queryHandovers(ctx, query)
+}
+
+// Confirm stores the confirmation of a handover in the database.
+func (s *SQLGroupHandoverStore) Confirm(ctx context.Context, id int, confirmation *models.HandoverConfirmation) error {
+	var notes *string
+	if confirmation.Notes != nil {
+		encrypted, err := Encrypt(*confirmation.Notes, s.encryptionKey)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt confirmation notes: %w", err)
+		}
+		notes = &encrypted
+	}
+	query := `UPDATE group_handovers SET confirmed_by_teacher_id = ?, confirmed_at = ?, observations_reviewed = ?, care_specifics_reviewed = ?,
+		support_goals_reviewed = ?, parents_informed = ?, confirmation_notes = ? WHERE handover_id = ? AND confirmed_at IS NULL`
+	result, err := s.db.ExecContext(ctx, query, confirmation.ConfirmedByTeacherID, confirmation.ConfirmedAt.UTC(), confirmation.ObservationsReviewed,
+		confirmation.CareSpecificsReviewed, confirmation.SupportGoalsReviewed, confirmation.ParentsInformed, notes, id)
+	if err != nil {
+		return groupHandoverConstraintError(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		if _, err := s.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return ErrConflict
+	}
+	return nil
+}
+
+func (s *SQLGroupHandoverStore) queryHandovers(ctx context.Context, query string, args ...any) ([]models.GroupHandover, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	handovers := []models.GroupHandover{}
+	for rows.Next() {
+		var handover models.GroupHandover
+		var confirmation models.HandoverConfirmation
+		var confirmedAt sql.NullTime
+		var notes sql.NullString
+		if err := rows.Scan(&handover.ID, &handover.ChildID, &handover.FromGroupID, &handover.ToGroupID, &handover.HandoverDate,
+			&handover.CreatedByUserID, &confirmation.ConfirmedByTeacherID, &confirmedAt, &confirmation.ObservationsReviewed,
+			&confirmation.CareSpecificsReviewed, &confirmation.SupportGoalsReviewed, &confirmation.ParentsInformed, &notes,
+			&handover.CreatedAt); err != nil {
+			return nil, err
+		}
+		if confirmedAt.Valid {
+			confirmation.ConfirmedAt = confirmedAt.Time
+			if notes.Valid {
+				decrypted, err := Decrypt(notes.String, s.encryptionKey)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decrypt confirmation notes: %w", err)
+				}
+				confirmation.Notes = &decrypted
+			}
+			handover.Confirmation = &confirmation
+		}
+		handovers = append(handovers, handover)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return handovers, nil
+}
+
+func groupHandoverConstraintError(err error) error {
+	if isForeignKeyError(err) {
+		return ErrForeignKeyConstraint
+	}
+	return err
+}
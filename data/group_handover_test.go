@@ -0,0 +1,75 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLGroupHandoverStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	require.NoError(t, err)
+	teacherID, err := dal.Teachers.Create(t.Context(), &models.Teacher{FirstName: "Maria", LastName: "Schmidt", Username: "maria"})
+	require.NoError(t, err)
+	krippeID, err := dal.Groups.Create(t.Context(), &models.Group{Name: "Krippe", Capacity: 10})
+	require.NoError(t, err)
+	sonneID, err := dal.Groups.Create(t.Context(), &models.Group{Name: "Sonnengruppe", Capacity: 20})
+	require.NoError(t, err)
+	require.NoError(t, dal.Groups.AddChild(t.Context(), krippeID, childID))
+
+	store := dal.GroupHandovers
+	handoverID, err := store.Create(t.Context(), &models.GroupHandover{ChildID: childID, FromGroupID: &krippeID, ToGroupID: &sonneID,
+		HandoverDate: models.NewDate(2024, time.August, 1)})
+	require.NoError(t, err)
+
+	sonne, err := dal.Groups.GetByID(t.Context(), sonneID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{childID}, sonne.ChildIDs, "the child is moved into the receiving group")
+	krippe, err := dal.Groups.GetByID(t.Context(), krippeID)
+	require.NoError(t, err)
+	assert.Empty(t, krippe.ChildIDs)
+
+	unknown := 999
+	_, err = store.Create(t.Context(), &models.GroupHandover{ChildID: childID, ToGroupID: &unknown, HandoverDate: models.NewDate(2024, time.August, 1)})
+	assert.ErrorIs(t, err, data.ErrForeignKeyConstraint)
+
+	unconfirmed, err := store.GetUnconfirmed(t.Context())
+	require.NoError(t, err)
+	require.Len(t, unconfirmed, 1)
+	assert.Nil(t, unconfirmed[0].Confirmation)
+
+	notes := "Eingewöhnung mit der Mutter am ersten Tag"
+	confirmation := &models.HandoverConfirmation{ObservationsReviewed: true, CareSpecificsReviewed: true, SupportGoalsReviewed: true,
+		Notes: &notes, ConfirmedByTeacherID: &teacherID, ConfirmedAt: time.Date(2024, time.August, 2, 8, 0, 0, 0, time.UTC)}
+	require.NoError(t, store.Confirm(t.Context(), handoverID, confirmation))
+	assert.ErrorIs(t, store.Confirm(t.Context(), handoverID, confirmation), data.ErrConflict)
+	assert.ErrorIs(t, store.Confirm(t.Context(), 999, confirmation), data.ErrNotFound)
+
+	var stored string
+	require.NoError(t, db.QueryRow("SELECT confirmation_notes FROM group_handovers WHERE handover_id = ?", handoverID).Scan(&stored))
+	assert.NotContains(t, stored, "Eingewöhnung", "confirmation notes must be stored encrypted")
+
+	handovers, err := store.GetForChild(t.Context(), childID)
+	require.NoError(t, err)
+	require.Len(t, handovers, 1)
+	require.NotNil(t, handovers[0].Confirmation)
+	assert.Equal(t, notes, *handovers[0].Confirmation.Notes)
+	assert.Equal(t, teacherID, *handovers[0].Confirmation.ConfirmedByTeacherID)
+	assert.Equal(t, krippeID, *handovers[0].FromGroupID)
+	unconfirmed, err = store.GetUnconfirmed(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, unconfirmed)
+}
@@ -0,0 +1,96 @@
+package data_test
+
+import (
+	"regexp"
+	"testing"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLGroupStore_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLGroupStore(db)
+	lead := 1
+	group := &models.Group{Name: "Igel", Capacity: 20, LeadTeacherID: &lead, AssistantTeacherIDs: []int{2, 3}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO kita_groups (group_name, capacity, min_age_months, max_age_months, room, lead_teacher_id) VALUES (?, ?, ?, ?, ?, ?) RETURNING group_id`)).
+		WithArgs("Igel", 20, nil, nil, nil, &lead).
+		WillReturnRows(sqlmock.NewRows([]string{"group_id"}).AddRow(4))
+	insertAssistant := regexp.QuoteMeta(`INSERT INTO group_assistants (group_id, teacher_id) VALUES (?, ?) ON CONFLICT DO NOTHING`)
+	mock.ExpectExec(insertAssistant).WithArgs(4, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertAssistant).WithArgs(4, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := store.Create(t.Context(), group)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLGroupStore_AddChild(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLGroupStore(db)
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO group_children (child_id, group_id) VALUES (?, ?) ON CONFLICT (child_id) DO UPDATE SET group_id = excluded.group_id`)).
+		WithArgs(7, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, store.AddChild(t.Context(), 4, 7))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLGroupStore_RemoveChild(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLGroupStore(db)
+	query := regexp.QuoteMeta(`DELETE FROM group_children WHERE group_id = ? AND child_id = ?`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(4, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, store.RemoveChild(t.Context(), 4, 7))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not in group", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(4, 8).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Equal(t, data.ErrNotFound, store.RemoveChild(t.Context(), 4, 8))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLGroupStore_CountUsage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLGroupStore(db)
+	mock.ExpectQuery(`SELECT gc.group_id, .* FROM group_children gc .* GROUP BY gc.group_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"group_id", "entries", "open_assignments", "children"}).AddRow(2, 7, 3, 2))
+
+	usage, err := store.CountUsage(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]models.UsageCounts{2: {Entries: 7, OpenAssignments: 3, Children: 2}}, usage)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,287 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// ImportJobStore defines the interface for persisting bulk imports and the status of their rows.
+type ImportJobStore interface {
+	Create(ctx context.Context, job *models.ImportJob) (int, error)
+	GetByID(ctx context.Context, id int) (*models.ImportJob, error)
+	GetAll(ctx context.Context) ([]models.ImportJob, error)
+	UpdateStatus(ctx context.Context, id int, status string) error
+	ImportRow(ctx context.Context, jobID int, row *models.ImportJobRow) (int, error)
+	ImportRows(ctx context.Context, jobID int, rows []models.ImportJobRow) (int, error)
+	RollbackRow(ctx context.Context, jobID int, rowNumber int) error
+}
+
+// SQLImportJobStore implements ImportJobStore using database/sql.
+type SQLImportJobStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLImportJobStore creates a new SQLImportJobStore.
+func NewSQLImportJobStore(db *sql.DB, encryptionKey []byte) *SQLImportJobStore {
+	return &SQLImportJobStore{db: db, encryptionKey: encryptionKey}
+}
+
+// importRowData is the encrypted content of a row.
+type importRowData struct {
+	ChildName string        `json:"child_name"`
+	Child     *models.Child `json:"child"`
+}
+
+// Create inserts an import job with all of its rows in one transaction.
+func (s *SQLImportJobStore) Create(ctx context.Context, job *models.ImportJob) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var id int
+	if err := tx.QueryRowContext(ctx, `INSERT INTO import_jobs (file_name, status, created_by_user_id) VALUES (?, ?, ?) RETURNING import_job_id`, job.FileName, job.Status, job.CreatedByUserID).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	for _, row := range job.Rows {
+		content, err := json.Marshal(importRowData{ChildName: row.ChildName, Child: row.Child})
+		if err != nil {
+			return 0, err
+		}
+		encrypted, err := Encrypt(string(content), s.encryptionKey)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt import row: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO import_job_rows (import_job_id, row_number, data, status, error) VALUES (?, ?, ?, ?, ?)`,
+			id, row.RowNumber, encrypted, row.Status, row.Error)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches an import job with its rows ordered by row number.
+func (s *SQLImportJobStore) GetByID(ctx context.Context, id int) (*models.ImportJob, error) {
+	job := &models.ImportJob{RowCounts: map[string]int{}, Rows: []models.ImportJobRow{}}
+	err := s.db.QueryRowContext(ctx, `SELECT import_job_id, file_name, status, created_by_user_id, created_at, updated_at FROM import_jobs WHERE import_job_id = ?`, id).
+		Scan(&job.ID, &job.FileName, &job.Status, &job.CreatedByUserID, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT row_number, data, status, error, child_id FROM import_job_rows WHERE import_job_id = ? ORDER BY row_number`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		row := models.ImportJobRow{}
+		var encrypted string
+		if err := rows.Scan(&row.RowNumber, &encrypted, &row.Status, &row.Error, &row.ChildID); err != nil {
+			return nil, err
+		}
+		content, err := Decrypt(encrypted, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt import row: %w", err)
+		}
+		var rowData importRowData
+		if err := json.Unmarshal([]byte(content), &rowData); err != nil {
+			return nil, err
+		}
+		row.ChildName = rowData.ChildName
+		row.Child = rowData.Child
+		job.Rows = append(job.Rows, row)
+		job.RowCounts[row.Status]++
+	}
+	return job, rows.Err()
+}
+
+// GetAll fetches all import jobs with their row counts, newest first. The rows themselves are not loaded.
+func (s *SQLImportJobStore) GetAll(ctx context.Context) ([]models.ImportJob, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT import_job_id, file_name, status, created_by_user_id, created_at, updated_at FROM import_jobs ORDER BY created_at DESC, import_job_id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	jobs := []models.ImportJob{}
+	jobIndex := make(map[int]int)
+	for rows.Next() {
+		job := models.ImportJob{RowCounts: map[string]int{}}
+		if err := rows.Scan(&job.ID, &job.FileName, &job.Status, &job.CreatedByUserID, &job.CreatedAt, &job.UpdatedAt); err != nil {
+			return nil, err
+		}
+		jobIndex[job.ID] = len(jobs)
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.db.QueryContext(ctx, `SELECT import_job_id, status, COUNT(*) FROM import_job_rows GROUP BY import_job_id, status`)
+	if err != nil {
+		return nil, err
+	}
+	defer counts.Close() //nolint:errcheck
+
+	for counts.Next() {
+		var jobID, count int
+		var status string
+		if err := counts.Scan(&jobID, &status, &count); err != nil {
+			return nil, err
+		}
+		if i, ok := jobIndex[jobID]; ok {
+			jobs[i].RowCounts[status] = count
+		}
+	}
+	return jobs, counts.Err()
+}
+
+// UpdateStatus updates the status of an import job.
+func (s *SQLImportJobStore) UpdateStatus(ctx context.Context, id int, status string) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE import_jobs SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE import_job_id = ?`, status, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ImportRow creates the child of a pending row and marks the row as imported in one transaction,
+// so that a resumed import never creates a child twice. It returns ErrConflict if the row is not pending.
+func (s *SQLImportJobStore) ImportRow(ctx context.Context, jobID int, row *models.ImportJobRow) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	childID, err := insertChild(ctx, tx, s.encryptionKey, row.Child)
+	if err != nil {
+		return 0, err
+	}
+	result, err := tx.ExecContext(ctx, `UPDATE import_job_rows SET status = ?, error = NULL, child_id = ? WHERE import_job_id = ? AND row_number = ? AND status = ?`,
+		models.ImportRowStatusImported, childID, jobID, row.RowNumber, models.ImportRowStatusPending)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if rowsAffected == 0 {
+		return 0, ErrConflict
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return childID, nil
+}
+
+// ImportRows imports a batch of pending rows in one transaction, which is much faster than a transaction per row.
+// Rows that are not pending anymore are skipped. It returns the number of imported rows; if one row fails,
+// none of the batch is imported.
+func (s *SQLImportJobStore) ImportRows(ctx context.Context, jobID int, rows []models.ImportJobRow) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	imported := 0
+	for i := range rows {
+		// Claiming the row first keeps a concurrent run from creating the same child.
+		result, err := tx.ExecContext(ctx, `UPDATE import_job_rows SET status = ?, error = NULL WHERE import_job_id = ? AND row_number = ? AND status = ?`,
+			models.ImportRowStatusImported, jobID, rows[i].RowNumber, models.ImportRowStatusPending)
+		if err != nil {
+			return 0, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if rowsAffected == 0 {
+			continue
+		}
+		childID, err := insertChild(ctx, tx, s.encryptionKey, rows[i].Child)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE import_job_rows SET child_id = ? WHERE import_job_id = ? AND row_number = ?`, childID, jobID, rows[i].RowNumber); err != nil {
+			return 0, err
+		}
+		imported++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return imported, nil
+}
+
+// RollbackRow deletes the child created by an imported row and marks the row as rolled back in one transaction.
+// It returns ErrConflict if the row is not imported, and ErrForeignKeyConstraint if the child has been documented
+// since, because deleting it would delete the documentation as well.
+func (s *SQLImportJobStore) RollbackRow(ctx context.Context, jobID int, rowNumber int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var status string
+	var childID sql.NullInt64
+	err = tx.QueryRowContext(ctx, `SELECT status, child_id FROM import_job_rows WHERE import_job_id = ? AND row_number = ?`, jobID, rowNumber).Scan(&status, &childID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if status != models.ImportRowStatusImported {
+		return ErrConflict
+	}
+
+	if childID.Valid {
+		var entries int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM documentation_entries WHERE child_id = ?`, childID.Int64).Scan(&entries); err != nil {
+			return err
+		}
+		if entries > 0 {
+			return ErrForeignKeyConstraint
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM children WHERE child_id = ?`, childID.Int64); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE import_job_rows SET status = ?, child_id = NULL WHERE import_job_id = ? AND row_number = ?`,
+		models.ImportRowStatusRolledBack, jobID, rowNumber)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,147 @@
+package data_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLImportJobStore_ImportRow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLImportJobStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	row := &models.ImportJobRow{
+		RowNumber: 3,
+		ChildName: "Anna Musterkind",
+		Child:     &models.Child{FirstName: "Anna", LastName: "Musterkind", Birthdate: models.NewDate(2022, 11, 18)},
+	}
+	insertChild := regexp.QuoteMeta(`INSERT INTO children (first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler) VALUES (?, ?, ?, ?, ?, ?) RETURNING child_id`)
+	markImported := regexp.QuoteMeta(`UPDATE import_job_rows SET status = ?, error = NULL, child_id = ? WHERE import_job_id = ? AND row_number = ? AND status = ?`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertChild).WillReturnRows(sqlmock.NewRows([]string{"child_id"}).AddRow(12))
+		mock.ExpectExec(markImported).
+			WithArgs(models.ImportRowStatusImported, 12, 5, 3, models.ImportRowStatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		childID, err := store.ImportRow(t.Context(), 5, row)
+		assert.NoError(t, err)
+		assert.Equal(t, 12, childID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("row no longer pending", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertChild).WillReturnRows(sqlmock.NewRows([]string{"child_id"}).AddRow(13))
+		mock.ExpectExec(markImported).
+			WithArgs(models.ImportRowStatusImported, 13, 5, 3, models.ImportRowStatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		_, err := store.ImportRow(t.Context(), 5, row)
+		assert.Equal(t, data.ErrConflict, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLImportJobStore_ImportRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLImportJobStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	rows := []models.ImportJobRow{
+		{RowNumber: 1, Child: &models.Child{FirstName: "Anna", LastName: "Musterkind"}},
+		{RowNumber: 2, Child: &models.Child{FirstName: "Ben", LastName: "Musterkind"}},
+	}
+	claim := regexp.QuoteMeta(`UPDATE import_job_rows SET status = ?, error = NULL WHERE import_job_id = ? AND row_number = ? AND status = ?`)
+	insertChild := regexp.QuoteMeta(`INSERT INTO children (first_name, last_name, birthdate, admission_date, expected_school_enrollment, is_preschooler) VALUES (?, ?, ?, ?, ?, ?) RETURNING child_id`)
+	setChild := regexp.QuoteMeta(`UPDATE import_job_rows SET child_id = ? WHERE import_job_id = ? AND row_number = ?`)
+
+	t.Run("skips rows that are not pending", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(claim).WithArgs(models.ImportRowStatusImported, 5, 1, models.ImportRowStatusPending).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertChild).WillReturnRows(sqlmock.NewRows([]string{"child_id"}).AddRow(12))
+		mock.ExpectExec(setChild).WithArgs(12, 5, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(claim).WithArgs(models.ImportRowStatusImported, 5, 2, models.ImportRowStatusPending).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		imported, err := store.ImportRows(t.Context(), 5, rows)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, imported)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("one failing row rolls back the batch", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(claim).WithArgs(models.ImportRowStatusImported, 5, 1, models.ImportRowStatusPending).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertChild).WillReturnError(errors.New("disk I/O error"))
+		mock.ExpectRollback()
+
+		_, err := store.ImportRows(t.Context(), 5, rows)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLImportJobStore_RollbackRow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLImportJobStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	selectRow := regexp.QuoteMeta(`SELECT status, child_id FROM import_job_rows WHERE import_job_id = ? AND row_number = ?`)
+	countEntries := regexp.QuoteMeta(`SELECT COUNT(*) FROM documentation_entries WHERE child_id = ?`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectRow).WithArgs(5, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "child_id"}).AddRow(models.ImportRowStatusImported, 12))
+		mock.ExpectQuery(countEntries).WithArgs(int64(12)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM children WHERE child_id = ?`)).WithArgs(int64(12)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE import_job_rows SET status = ?, child_id = NULL WHERE import_job_id = ? AND row_number = ?`)).
+			WithArgs(models.ImportRowStatusRolledBack, 5, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, store.RollbackRow(t.Context(), 5, 3))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("child documented since", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectRow).WithArgs(5, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "child_id"}).AddRow(models.ImportRowStatusImported, 12))
+		mock.ExpectQuery(countEntries).WithArgs(int64(12)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectRollback()
+
+		assert.Equal(t, data.ErrForeignKeyConstraint, store.RollbackRow(t.Context(), 5, 3))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("row not imported", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectRow).WithArgs(5, 4).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "child_id"}).AddRow(models.ImportRowStatusInvalid, nil))
+		mock.ExpectRollback()
+
+		assert.Equal(t, data.ErrConflict, store.RollbackRow(t.Context(), 5, 4))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
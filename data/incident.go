@@ -0,0 +1,187 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// IncidentStore defines the interface for Incident data operations.
+type IncidentStore interface {
+	Create(ctx context.Context, incident *models.Incident) (int, error)
+	GetByID(ctx context.Context, id int) (*models.Incident, error)
+	// Update updates an incident, its child and reporter cannot be changed.
+	Update(ctx context.Context, incident *models.Incident) error
+	Delete(ctx context.Context, id int) error
+	// GetForChild fetches the incidents of a child, the latest first.
+	GetForChild(ctx context.Context, childID int) ([]models.Incident, error)
+}
+
+// SQLIncidentStore implements IncidentStore using database/sql.
+type SQLIncidentStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLIncidentStore creates a new SQLIncidentStore.
+func NewSQLIncidentStore(db *sql.DB, encryptionKey []byte) *SQLIncidentStore {
+	return &SQLIncidentStore{db: db, encryptionKey: encryptionKey}
+}
+
+const incidentColumns = `incident_id, child_id, occurred_at, location, description, injuries, first_aid, witnesses, medical_treatment, physician,
+	parent_informed_at, reported_by_user_id, created_at, updated_at`
+
+// Create inserts a new incident into the database.
+func (s *SQLIncidentStore) Create(ctx context.Context, incident *models.Incident) (int, error) {
+	encrypted, err := s.encryptIncident(incident)
+	if err != nil {
+		return 0, err
+	}
+	query := `INSERT INTO incidents (child_id, occurred_at, location, description, injuries, first_aid, witnesses, medical_treatment, physician,
+		parent_informed_at, reported_by_user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING incident_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, incident.ChildID, incident.OccurredAt, incident.Location, encrypted[0], encrypted[1], encrypted[2], encrypted[3],
+		incident.MedicalTreatment, encrypted[4], incident.ParentInformedAt, incident.ReportedByUserID).Scan(&id); err != nil {
+		if isForeignKeyError(err) {
+			return 0, ErrForeignKeyConstraint
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches an incident by ID from the database.
+func (s *SQLIncidentStore) GetByID(ctx context.Context, id int) (*models.Incident, error) {
+	query := `SELECT ` + incidentColumns + ` FROM incidents WHERE incident_id = ?`
+	incident, err := s.scanIncident(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return incident, nil
+}
+
+// Update updates an existing incident in the database.
+func (s *SQLIncidentStore) Update(ctx context.Context, incident *models.Incident) error {
+	encrypted, err := s.encryptIncident(incident)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE incidents SET occurred_at = ?, location = ?, description = ?, injuries = ?, first_aid = ?, witnesses = ?, medical_treatment = ?,
+		physician = ?, parent_informed_at = ?, updated_at = CURRENT_TIMESTAMP WHERE incident_id = ?`
+	result, err := s.db.ExecContext(ctx, query, incident.OccurredAt, incident.Location, encrypted[0], encrypted[1], encrypted[2], encrypted[3],
+		incident.MedicalTreatment, encrypted[4], incident.ParentInformedAt, incident.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete deletes an incident by ID from the database.
+func (s *SQLIncidentStore) Delete(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM incidents WHERE incident_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetForChild fetches the incidents of a child from the database.
+func (s *SQLIncidentStore) GetForChild(ctx context.Context, childID int) ([]models.Incident, error) {
+	query := `SELECT ` + incidentColumns + ` FROM incidents WHERE child_id = ? ORDER BY occurred_at DESC, incident_id DESC`
+	rows, err := s.db.QueryContext(ctx, query, childID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	incidents := []models.Incident{}
+	for rows.Next() {
+		incident, err := s.scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, *incident)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+// encryptIncident encrypts the description, injuries, first aid, witnesses and physician of an incident in this
+// order, nil values stay NULL.
+func (s *SQLIncidentStore) encryptIncident(incident *models.Incident) ([5]*string, error) {
+	var encrypted [5]*string
+	for i, value := range []*string{incident.Description, incident.Injuries, incident.FirstAid, incident.Witnesses, incident.Physician} {
+		if value == nil {
+			continue
+		}
+		ciphertext, err := Encrypt(*value, s.encryptionKey)
+		if err != nil {
+			return encrypted, fmt.Errorf("failed to encrypt incident: %w", err)
+		}
+		encrypted[i] = &ciphertext
+	}
+	return encrypted, nil
+}
+
+func (s *SQLIncidentStore) scanIncident(row rowScanner) (*models.Incident, error) {
+	incident := &models.Incident{}
+	var description, injuries, firstAid, witnesses, physician sql.NullString
+	var parentInformedAt sql.NullTime
+	var reportedByUserID sql.NullInt64
+	err := row.Scan(&incident.ID, &incident.ChildID, &incident.OccurredAt, &incident.Location, &description, &injuries, &firstAid, &witnesses,
+		&incident.MedicalTreatment, &physician, &parentInformedAt, &reportedByUserID, &incident.CreatedAt, &incident.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range []struct {
+		value  sql.NullString
+		target **string
+	}{
+		{description, &incident.Description},
+		{injuries, &incident.Injuries},
+		{firstAid, &incident.FirstAid},
+		{witnesses, &incident.Witnesses},
+		{physician, &incident.Physician},
+	} {
+		if !field.value.Valid {
+			continue
+		}
+		decrypted, err := Decrypt(field.value.String, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt incident: %w", err)
+		}
+		*field.target = &decrypted
+	}
+	if parentInformedAt.Valid {
+		incident.ParentInformedAt = &parentInformedAt.Time
+	}
+	if reportedByUserID.Valid {
+		id := int(reportedByUserID.Int64)
+		incident.ReportedByUserID = &id
+	}
+	return incident, nil
+}
@@ -0,0 +1,68 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLIncidentStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	require.NoError(t, err)
+
+	store := dal.Incidents
+	occurredAt := time.Date(2025, time.March, 3, 10, 15, 0, 0, time.UTC)
+	location := "Garten, Klettergerüst"
+	description := "Beim Klettern abgerutscht und auf das Knie gefallen"
+	id, err := store.Create(t.Context(), &models.Incident{ChildID: childID, OccurredAt: occurredAt, Location: &location, Description: &description})
+	require.NoError(t, err)
+
+	var storedDescription string
+	require.NoError(t, db.QueryRow(`SELECT description FROM incidents WHERE incident_id = ?`, id).Scan(&storedDescription))
+	assert.NotContains(t, storedDescription, "Klettern", "the description must be stored encrypted")
+
+	incident, err := store.GetByID(t.Context(), id)
+	require.NoError(t, err)
+	assert.True(t, occurredAt.Equal(incident.OccurredAt))
+	assert.Equal(t, location, *incident.Location)
+	assert.Equal(t, description, *incident.Description)
+	assert.Nil(t, incident.Injuries)
+	assert.Nil(t, incident.ParentInformedAt)
+
+	injuries := "Schürfwunde am linken Knie"
+	informedAt := occurredAt.Add(30 * time.Minute)
+	incident.Injuries = &injuries
+	incident.ParentInformedAt = &informedAt
+	require.NoError(t, store.Update(t.Context(), incident))
+	updated, err := store.GetByID(t.Context(), id)
+	require.NoError(t, err)
+	assert.Equal(t, injuries, *updated.Injuries)
+	assert.True(t, informedAt.Equal(*updated.ParentInformedAt))
+
+	laterID, err := store.Create(t.Context(), &models.Incident{ChildID: childID, OccurredAt: occurredAt.AddDate(0, 0, 1)})
+	require.NoError(t, err)
+	incidents, err := store.GetForChild(t.Context(), childID)
+	require.NoError(t, err)
+	require.Len(t, incidents, 2)
+	assert.Equal(t, laterID, incidents[0].ID)
+
+	_, err = store.Create(t.Context(), &models.Incident{ChildID: 999, OccurredAt: occurredAt})
+	assert.ErrorIs(t, err, data.ErrForeignKeyConstraint)
+	require.NoError(t, store.Delete(t.Context(), id))
+	assert.ErrorIs(t, store.Delete(t.Context(), id), data.ErrNotFound)
+	_, err = store.GetByID(t.Context(), id)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+}
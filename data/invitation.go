@@ -0,0 +1,159 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// InvitationStore defines the interface for Invitation data operations.
+type InvitationStore interface {
+	Create(ctx context.Context, invitation *models.Invitation) (int, error)
+	GetByID(ctx context.Context, id int) (*models.Invitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.Invitation, error)
+	GetAll(ctx context.Context) ([]models.Invitation, error)
+	// Claim marks an unused invitation as used, it returns ErrConflict if the invitation was used already.
+	Claim(ctx context.Context, id int, usedAt time.Time) error
+	// Release undoes a claim whose account could not be created.
+	Release(ctx context.Context, id int) error
+	SetUsedBy(ctx context.Context, id int, userID int) error
+	Delete(ctx context.Context, id int) error
+}
+
+// SQLInvitationStore implements InvitationStore using database/sql.
+type SQLInvitationStore struct {
+	db *sql.DB
+}
+
+// NewSQLInvitationStore creates a new SQLInvitationStore.
+func NewSQLInvitationStore(db *sql.DB) *SQLInvitationStore {
+	return &SQLInvitationStore{db: db}
+}
+
+const invitationColumns = `invitation_id, token_hash, role, created_by_user_id, created_at, expires_at, used_at, used_by_user_id`
+
+// Create inserts a new invitation into the database.
+func (s *SQLInvitationStore) Create(ctx context.Context, invitation *models.Invitation) (int, error) {
+	query := `INSERT INTO invitations (token_hash, role, created_by_user_id, expires_at) VALUES (?, ?, ?, ?) RETURNING invitation_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, invitation.TokenHash, invitation.Role, invitation.CreatedByUserID, invitation.ExpiresAt).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches an invitation by ID from the database.
+func (s *SQLInvitationStore) GetByID(ctx context.Context, id int) (*models.Invitation, error) {
+	return s.queryInvitation(ctx, `SELECT `+invitationColumns+` FROM invitations WHERE invitation_id = ?`, id)
+}
+
+// GetByTokenHash fetches the invitation with the hash of a token from the database.
+func (s *SQLInvitationStore) GetByTokenHash(ctx context.Context, tokenHash string) (*models.Invitation, error) {
+	return s.queryInvitation(ctx, `SELECT `+invitationColumns+` FROM invitations WHERE token_hash = ?`, tokenHash)
+}
+
+// GetAll fetches all invitations, latest first.
+func (s *SQLInvitationStore) GetAll(ctx context.Context) ([]models.Invitation, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+invitationColumns+` FROM invitations ORDER BY created_at DESC, invitation_id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	invitations := []models.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *invitation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// Claim marks an unused invitation as used.
+func (s *SQLInvitationStore) Claim(ctx context.Context, id int, usedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE invitations SET used_at = ? WHERE invitation_id = ? AND used_at IS NULL`, usedAt, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+// Release marks a claimed invitation as unused again.
+func (s *SQLInvitationStore) Release(ctx context.Context, id int) error {
+	return s.updateInvitation(ctx, `UPDATE invitations SET used_at = NULL, used_by_user_id = NULL WHERE invitation_id = ?`, id)
+}
+
+// SetUsedBy records the user account created from an invitation.
+func (s *SQLInvitationStore) SetUsedBy(ctx context.Context, id int, userID int) error {
+	return s.updateInvitation(ctx, `UPDATE invitations SET used_by_user_id = ? WHERE invitation_id = ?`, userID, id)
+}
+
+// Delete deletes an invitation by ID from the database.
+func (s *SQLInvitationStore) Delete(ctx context.Context, id int) error {
+	return s.updateInvitation(ctx, `DELETE FROM invitations WHERE invitation_id = ?`, id)
+}
+
+func (s *SQLInvitationStore) updateInvitation(ctx context.Context, query string, args ...any) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLInvitationStore) queryInvitation(ctx context.Context, query string, args ...any) (*models.Invitation, error) {
+	invitation, err := scanInvitation(s.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func scanInvitation(row rowScanner) (*models.Invitation, error) {
+	invitation := &models.Invitation{}
+	var createdByUserID, usedByUserID sql.NullInt64
+	var usedAt sql.NullTime
+	err := row.Scan(&invitation.ID, &invitation.TokenHash, &invitation.Role, &createdByUserID, &invitation.CreatedAt, &invitation.ExpiresAt, &usedAt, &usedByUserID)
+	if err != nil {
+		return nil, err
+	}
+	if createdByUserID.Valid {
+		id := int(createdByUserID.Int64)
+		invitation.CreatedByUserID = &id
+	}
+	if usedAt.Valid {
+		invitation.UsedAt = &usedAt.Time
+	}
+	if usedByUserID.Valid {
+		id := int(usedByUserID.Int64)
+		invitation.UsedByUserID = &id
+	}
+	return invitation, nil
+}
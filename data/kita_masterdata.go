@@ -1,16 +1,23 @@
 package data
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"kitadoc-backend/models"
 )
 
 // KitaMasterdataStore defines the interface for KitaMasterdata data operations.
 type KitaMasterdataStore interface {
-	Get() (*models.KitaMasterdata, error)
-	Update(data *models.KitaMasterdata) error
+	Get(ctx context.Context) (*models.KitaMasterdata, error)
+	Update(ctx context.Context, data *models.KitaMasterdata) error
+	UpdateTheme(ctx context.Context, theme *models.DocumentTheme) error
+	UpdateTerminology(ctx context.Context, terminology models.Terminology) error
+	GetLogo(ctx context.Context) (*models.DocumentLogo, error)
+	UpdateLogo(ctx context.Context, logo *models.DocumentLogo) error
 }
 
 // SQLKitaMasterdataStore implements KitaMasterdataStore using database/sql.
@@ -24,11 +31,13 @@ func NewSQLKitaMasterdataStore(db *sql.DB) *SQLKitaMasterdataStore {
 }
 
 // Get fetches the master data from the database.
-func (s *SQLKitaMasterdataStore) Get() (*models.KitaMasterdata, error) {
-	query := `SELECT name, street, house_number, postal_code, city, phone_number, email, created_at, updated_at FROM kita_masterdata LIMIT 1`
-	row := s.db.QueryRow(query)
+func (s *SQLKitaMasterdataStore) Get(ctx context.Context) (*models.KitaMasterdata, error) {
+	query := `SELECT name, street, house_number, postal_code, city, phone_number, email, created_at, updated_at,
+		document_font, document_accent_color, logo IS NOT NULL, terminology FROM kita_masterdata LIMIT 1`
+	row := s.db.QueryRowContext(ctx, query)
 
 	masterdata := &models.KitaMasterdata{}
+	var terminology sql.NullString
 	err := row.Scan(
 		&masterdata.Name,
 		&masterdata.Street,
@@ -39,6 +48,10 @@ func (s *SQLKitaMasterdataStore) Get() (*models.KitaMasterdata, error) {
 		&masterdata.Email,
 		&masterdata.CreatedAt,
 		&masterdata.UpdatedAt,
+		&masterdata.Theme.FontFamily,
+		&masterdata.Theme.AccentColor,
+		&masterdata.Theme.HasLogo,
+		&terminology,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -46,14 +59,20 @@ func (s *SQLKitaMasterdataStore) Get() (*models.KitaMasterdata, error) {
 		}
 		return nil, err
 	}
+	masterdata.Terminology = models.Terminology{}
+	if terminology.Valid {
+		if err := json.Unmarshal([]byte(terminology.String), &masterdata.Terminology); err != nil {
+			return nil, fmt.Errorf("failed to decode terminology: %w", err)
+		}
+	}
 	return masterdata, nil
 }
 
 // Update updates the master data. If no record exists, it creates one.
-func (s *SQLKitaMasterdataStore) Update(data *models.KitaMasterdata) error {
+func (s *SQLKitaMasterdataStore) Update(ctx context.Context, data *models.KitaMasterdata) error {
 	// First, try to update
 	queryUpdate := `UPDATE kita_masterdata SET name = ?, street = ?, house_number = ?, postal_code = ?, city = ?, phone_number = ?, email = ?`
-	result, err := s.db.Exec(queryUpdate, data.Name, data.Street, data.HouseNumber, data.PostalCode, data.City, data.PhoneNumber, data.Email)
+	result, err := s.db.ExecContext(ctx, queryUpdate, data.Name, data.Street, data.HouseNumber, data.PostalCode, data.City, data.PhoneNumber, data.Email)
 	if err != nil {
 		return err
 	}
@@ -66,10 +85,81 @@ func (s *SQLKitaMasterdataStore) Update(data *models.KitaMasterdata) error {
 	if rowsAffected == 0 {
 		// If no rows affected, insert
 		queryInsert := `INSERT INTO kita_masterdata (name, street, house_number, postal_code, city, phone_number, email) VALUES (?, ?, ?, ?, ?, ?, ?)`
-		_, err := s.db.Exec(queryInsert, data.Name, data.Street, data.HouseNumber, data.PostalCode, data.City, data.PhoneNumber, data.Email)
+		_, err := s.db.ExecContext(ctx, queryInsert, data.Name, data.Street, data.HouseNumber, data.PostalCode, data.City, data.PhoneNumber, data.Email)
 		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// UpdateTheme updates the document theme. The master data has to exist, otherwise ErrNotFound is returned.
+func (s *SQLKitaMasterdataStore) UpdateTheme(ctx context.Context, theme *models.DocumentTheme) error {
+	query := `UPDATE kita_masterdata SET document_font = ?, document_accent_color = ?`
+	result, err := s.db.ExecContext(ctx, query, theme.FontFamily, theme.AccentColor)
+	if err != nil {
+		return err
+	}
+	return requireMasterdataRow(result)
+}
+
+// UpdateTerminology replaces the terms of the facility, terms left out return to their default. The master data has
+// to exist, otherwise ErrNotFound is returned.
+func (s *SQLKitaMasterdataStore) UpdateTerminology(ctx context.Context, terminology models.Terminology) error {
+	var encoded *string
+	if len(terminology) > 0 {
+		content, err := json.Marshal(terminology)
+		if err != nil {
+			return fmt.Errorf("failed to encode terminology: %w", err)
+		}
+		value := string(content)
+		encoded = &value
+	}
+	result, err := s.db.ExecContext(ctx, `UPDATE kita_masterdata SET terminology = ?`, encoded)
+	if err != nil {
+		return err
+	}
+	return requireMasterdataRow(result)
+}
+
+// GetLogo fetches the document logo. ErrNotFound is returned if no logo has been uploaded.
+func (s *SQLKitaMasterdataStore) GetLogo(ctx context.Context) (*models.DocumentLogo, error) {
+	query := `SELECT logo, logo_content_type FROM kita_masterdata WHERE logo IS NOT NULL LIMIT 1`
+	logo := &models.DocumentLogo{}
+	err := s.db.QueryRowContext(ctx, query).Scan(&logo.Content, &logo.ContentType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return logo, nil
+}
+
+// UpdateLogo replaces the document logo, nil removes it. The master data has to exist, otherwise ErrNotFound is returned.
+func (s *SQLKitaMasterdataStore) UpdateLogo(ctx context.Context, logo *models.DocumentLogo) error {
+	var content []byte
+	var contentType *string
+	if logo != nil {
+		content = logo.Content
+		contentType = &logo.ContentType
+	}
+	query := `UPDATE kita_masterdata SET logo = ?, logo_content_type = ?`
+	result, err := s.db.ExecContext(ctx, query, content, contentType)
+	if err != nil {
+		return err
+	}
+	return requireMasterdataRow(result)
+}
+
+// requireMasterdataRow returns ErrNotFound if an update did not find the master data row.
+func requireMasterdataRow(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
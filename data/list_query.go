@@ -0,0 +1,116 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"kitadoc-backend/models"
+)
+
+// listColumns maps the fields of a models.ListQuery to the columns of a table. Only mapped fields can be
+// filtered and sorted by, so that no name given by a caller ends up in the SQL. The "id" field is required,
+// it breaks ties in every order.
+type listColumns map[string]string
+
+// buildListQuery appends the conditions, sorting and pagination of query to the SELECT statement base and
+// returns the statement with its arguments. defaultSort is used if the query does not sort.
+func buildListQuery(base string, columns listColumns, query models.ListQuery, defaultSort ...models.SortField) (string, []any, error) {
+	var statement strings.Builder
+	statement.WriteString(base)
+	args, err := writeConditions(&statement, columns, query.Conditions)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sort := query.Sort
+	if len(sort) == 0 {
+		sort = defaultSort
+	}
+	sortedByID := false
+	for i, field := range sort {
+		column, ok := columns[field.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidInput, field.Field)
+		}
+		sortedByID = sortedByID || field.Field == "id"
+		if i == 0 {
+			statement.WriteString(" ORDER BY ")
+		} else {
+			statement.WriteString(", ")
+		}
+		statement.WriteString(column)
+		if field.Descending {
+			statement.WriteString(" DESC")
+		}
+	}
+	if !sortedByID {
+		// Ties are broken by ID, so that equal values come in the same order on every call and no record
+		// shows up on two pages.
+		if len(sort) == 0 {
+			statement.WriteString(" ORDER BY ")
+		} else {
+			statement.WriteString(", ")
+		}
+		statement.WriteString(columns["id"])
+	}
+
+	switch {
+	case query.Limit > 0:
+		statement.WriteString(" LIMIT ? OFFSET ?")
+		args = append(args, query.Limit, query.Offset)
+	case query.Offset > 0:
+		// SQLite only knows OFFSET together with LIMIT and PostgreSQL rejects a negative limit, the largest limit
+		// means no limit on both.
+		statement.WriteString(" LIMIT ? OFFSET ?")
+		args = append(args, int64(math.MaxInt64), query.Offset)
+	}
+	return statement.String(), args, nil
+}
+
+// buildCountQuery appends the conditions of query to the SELECT COUNT statement base and returns the statement
+// with its arguments. The sorting and pagination of query are ignored, a count covers all matching records.
+func buildCountQuery(base string, columns listColumns, query models.ListQuery) (string, []any, error) {
+	var statement strings.Builder
+	statement.WriteString(base)
+	args, err := writeConditions(&statement, columns, query.Conditions)
+	if err != nil {
+		return "", nil, err
+	}
+	return statement.String(), args, nil
+}
+
+// writeConditions appends the WHERE clause of conditions to statement and returns its arguments.
+func writeConditions(statement *strings.Builder, columns listColumns, conditions []models.Condition) ([]any, error) {
+	var args []any
+	for i, condition := range conditions {
+		column, ok := columns[condition.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot filter by %q", ErrInvalidInput, condition.Field)
+		}
+		if i == 0 {
+			statement.WriteString(" WHERE ")
+		} else {
+			statement.WriteString(" AND ")
+		}
+		statement.WriteString(column)
+		switch condition.Operator {
+		case models.OperatorEqual:
+			statement.WriteString(" = ?")
+			args = append(args, condition.Value)
+		case models.OperatorGreaterOrEqual:
+			statement.WriteString(" >= ?")
+			args = append(args, condition.Value)
+		case models.OperatorLessOrEqual:
+			statement.WriteString(" <= ?")
+			args = append(args, condition.Value)
+		case models.OperatorIsNull:
+			statement.WriteString(" IS NULL")
+		case models.OperatorIsNotNull:
+			statement.WriteString(" IS NOT NULL")
+		default:
+			return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidInput, condition.Operator)
+		}
+	}
+	return args, nil
+}
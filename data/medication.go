@@ -0,0 +1,173 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// MedicationStore defines the interface for MedicationAdministration data operations. Administrations cannot be
+// changed or deleted, only confirmed once.
+type MedicationStore interface {
+	Create(ctx context.Context, administration *models.MedicationAdministration) (int, error)
+	GetByID(ctx context.Context, id int) (*models.MedicationAdministration, error)
+	// Confirm records the confirmation by a second user. It returns ErrConflict if the administration is
+	// already confirmed.
+	Confirm(ctx context.Context, id int, userID int, at time.Time) error
+	// GetForChild fetches the administrations of a child, the latest first.
+	GetForChild(ctx context.Context, childID int) ([]models.MedicationAdministration, error)
+}
+
+// SQLMedicationStore implements MedicationStore using database/sql.
+type SQLMedicationStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLMedicationStore creates a new SQLMedicationStore.
+func NewSQLMedicationStore(db *sql.DB, encryptionKey []byte) *SQLMedicationStore {
+	return &SQLMedicationStore{db: db, encryptionKey: encryptionKey}
+}
+
+const medicationColumns = `administration_id, child_id, medication, dose, instruction_reference, administered_at, administered_by_teacher_id, note,
+	recorded_by_user_id, confirmed_by_user_id, confirmed_at, created_at`
+
+// Create inserts a new administration into the database.
+func (s *SQLMedicationStore) Create(ctx context.Context, administration *models.MedicationAdministration) (int, error) {
+	var encrypted [3]string
+	for i, value := range []string{administration.Medication, administration.Dose, administration.InstructionReference} {
+		ciphertext, err := Encrypt(value, s.encryptionKey)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt medication administration: %w", err)
+		}
+		encrypted[i] = ciphertext
+	}
+	var note *string
+	if administration.Note != nil {
+		ciphertext, err := Encrypt(*administration.Note, s.encryptionKey)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt medication note: %w", err)
+		}
+		note = &ciphertext
+	}
+
+	query := `INSERT INTO medication_administrations (child_id, medication, dose, instruction_reference, administered_at, administered_by_teacher_id,
+		note, recorded_by_user_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING administration_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, administration.ChildID, encrypted[0], encrypted[1], encrypted[2], administration.AdministeredAt,
+		administration.AdministeredByTeacherID, note, administration.RecordedByUserID).Scan(&id); err != nil {
+		if isForeignKeyError(err) {
+			return 0, ErrForeignKeyConstraint
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches an administration by ID from the database.
+func (s *SQLMedicationStore) GetByID(ctx context.Context, id int) (*models.MedicationAdministration, error) {
+	query := `SELECT ` + medicationColumns + ` FROM medication_administrations WHERE administration_id = ?`
+	administration, err := s.scanAdministration(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return administration, nil
+}
+
+// Confirm sets the confirming user of an unconfirmed administration.
+func (s *SQLMedicationStore) Confirm(ctx context.Context, id int, userID int, at time.Time) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE medication_administrations SET confirmed_by_user_id = ?, confirmed_at = ?
+		WHERE administration_id = ? AND confirmed_at IS NULL`, userID, at, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		if _, err := s.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return ErrConflict
+	}
+	return nil
+}
+
+// GetForChild fetches the administrations of a child from the database.
+func (s *SQLMedicationStore) GetForChild(ctx context.Context, childID int) ([]models.MedicationAdministration, error) {
+	query := `SELECT ` + medicationColumns + ` FROM medication_administrations WHERE child_id = ? ORDER BY administered_at DESC, administration_id DESC`
+	rows, err := s.db.QueryContext(ctx, query, childID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	administrations := []models.MedicationAdministration{}
+	for rows.Next() {
+		administration, err := s.scanAdministration(rows)
+		if err != nil {
+			return nil, err
+		}
+		administrations = append(administrations, *administration)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return administrations, nil
+}
+
+func (s *SQLMedicationStore) scanAdministration(row rowScanner) (*models.MedicationAdministration, error) {
+	administration := &models.MedicationAdministration{}
+	var medication, dose, instructionReference string
+	var note sql.NullString
+	var recordedByUserID, confirmedByUserID sql.NullInt64
+	var confirmedAt sql.NullTime
+	err := row.Scan(&administration.ID, &administration.ChildID, &medication, &dose, &instructionReference, &administration.AdministeredAt,
+		&administration.AdministeredByTeacherID, &note, &recordedByUserID, &confirmedByUserID, &confirmedAt, &administration.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range []struct {
+		ciphertext string
+		target     *string
+	}{
+		{medication, &administration.Medication},
+		{dose, &administration.Dose},
+		{instructionReference, &administration.InstructionReference},
+	} {
+		decrypted, err := Decrypt(field.ciphertext, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt medication administration: %w", err)
+		}
+		*field.target = decrypted
+	}
+	if note.Valid {
+		decrypted, err := Decrypt(note.String, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt medication note: %w", err)
+		}
+		administration.Note = &decrypted
+	}
+	if recordedByUserID.Valid {
+		id := int(recordedByUserID.Int64)
+		administration.RecordedByUserID = &id
+	}
+	if confirmedByUserID.Valid {
+		id := int(confirmedByUserID.Int64)
+		administration.ConfirmedByUserID = &id
+	}
+	if confirmedAt.Valid {
+		administration.ConfirmedAt = &confirmedAt.Time
+	}
+	return administration, nil
+}
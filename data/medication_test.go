@@ -0,0 +1,85 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLMedicationStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	require.NoError(t, err)
+	teacherID, err := dal.Teachers.Create(t.Context(), &models.Teacher{FirstName: "Maria", LastName: "Schmidt", Username: "maria.schmidt"})
+	require.NoError(t, err)
+	recorderID, err := dal.Users.Create(t.Context(), &models.User{Username: "maria.schmidt", PasswordHash: "hash", Role: string(data.RoleTeacher)})
+	require.NoError(t, err)
+	confirmerID, err := dal.Users.Create(t.Context(), &models.User{Username: "thomas.weber", PasswordHash: "hash", Role: string(data.RoleTeacher)})
+	require.NoError(t, err)
+
+	store := dal.Medications
+	administeredAt := time.Date(2025, time.March, 3, 12, 30, 0, 0, time.UTC)
+	id, err := store.Create(t.Context(), &models.MedicationAdministration{
+		ChildID:                 childID,
+		Medication:              "Ibuprofen Saft",
+		Dose:                    "2,5 ml",
+		InstructionReference:    "Schriftliche Vollmacht vom 01.03.2025",
+		AdministeredAt:          administeredAt,
+		AdministeredByTeacherID: teacherID,
+		RecordedByUserID:        &recorderID,
+	})
+	require.NoError(t, err)
+
+	var storedMedication string
+	require.NoError(t, db.QueryRow(`SELECT medication FROM medication_administrations WHERE administration_id = ?`, id).Scan(&storedMedication))
+	assert.NotContains(t, storedMedication, "Ibuprofen", "the medication must be stored encrypted")
+
+	administration, err := store.GetByID(t.Context(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "Ibuprofen Saft", administration.Medication)
+	assert.Equal(t, "2,5 ml", administration.Dose)
+	assert.False(t, administration.IsConfirmed())
+
+	confirmedAt := administeredAt.Add(5 * time.Minute)
+	require.NoError(t, store.Confirm(t.Context(), id, confirmerID, confirmedAt))
+	assert.ErrorIs(t, store.Confirm(t.Context(), id, confirmerID, confirmedAt), data.ErrConflict)
+	assert.ErrorIs(t, store.Confirm(t.Context(), 999, confirmerID, confirmedAt), data.ErrNotFound)
+	administration, err = store.GetByID(t.Context(), id)
+	require.NoError(t, err)
+	assert.True(t, administration.IsConfirmed())
+	assert.Equal(t, confirmerID, *administration.ConfirmedByUserID)
+
+	// The history is read-only, also for direct changes of the database
+	_, err = db.Exec(`UPDATE medication_administrations SET dose = 'x' WHERE administration_id = ?`, id)
+	assert.Error(t, err)
+	_, err = db.Exec(`UPDATE medication_administrations SET confirmed_at = CURRENT_TIMESTAMP WHERE administration_id = ?`, id)
+	assert.Error(t, err)
+
+	// Deleting users clears the references, the teacher cannot be deleted
+	require.NoError(t, dal.Users.Delete(t.Context(), confirmerID))
+	administration, err = store.GetByID(t.Context(), id)
+	require.NoError(t, err)
+	assert.Nil(t, administration.ConfirmedByUserID)
+	assert.NotNil(t, administration.ConfirmedAt)
+	assert.ErrorIs(t, dal.Teachers.Delete(t.Context(), teacherID), data.ErrForeignKeyConstraint)
+
+	_, err = store.Create(t.Context(), &models.MedicationAdministration{ChildID: childID, Medication: "m", Dose: "d", InstructionReference: "r",
+		AdministeredAt: administeredAt.Add(time.Hour), AdministeredByTeacherID: 999})
+	assert.ErrorIs(t, err, data.ErrForeignKeyConstraint)
+	administrations, err := store.GetForChild(t.Context(), childID)
+	require.NoError(t, err)
+	require.Len(t, administrations, 1)
+	assert.Equal(t, id, administrations[0].ID)
+}
@@ -6,16 +6,32 @@ import (
 	"fmt"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
+// migrationDirs are the directories of migrationFS holding the migrations of each driver.
+var migrationDirs = map[Driver]string{
+	DriverSQLite:   "migrations",
+	DriverPostgres: "postgres",
+}
+
 func MigrateDB(db *sql.DB, migrationFS embed.FS) error {
-	db_driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	driver := DriverOf(db)
+	var db_driver database.Driver
+	var err error
+	switch driver {
+	case DriverPostgres:
+		db_driver, err = postgres.WithInstance(db, &postgres.Config{})
+	default:
+		db_driver, err = sqlite3.WithInstance(db, &sqlite3.Config{})
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create migration driver: %w", err)
 	}
-	fs_driver, err := iofs.New(migrationFS, "migrations")
+	fs_driver, err := iofs.New(migrationFS, migrationDirs[driver])
 	if err != nil {
 		return fmt.Errorf("failed to create migration source driver: %w", err)
 	}
@@ -23,7 +39,7 @@ func MigrateDB(db *sql.DB, migrationFS embed.FS) error {
 	migrations, err := migrate.NewWithInstance(
 		"iofs",
 		fs_driver,
-		"sqlite3",
+		string(driver),
 		db_driver,
 	)
 	if err != nil {
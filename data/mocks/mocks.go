@@ -1,6 +1,10 @@
 package mocks
 
 import (
+	"context"
+	"time"
+
+	"kitadoc-backend/data"
 	"kitadoc-backend/models"
 
 	"github.com/stretchr/testify/mock"
@@ -11,20 +15,20 @@ type MockUserStore struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: user
-func (_m *MockUserStore) Create(user *models.User) (int, error) {
-	ret := _m.Called(user)
+// Create provides a mock function with given fields: ctx, user
+func (_m *MockUserStore) Create(ctx context.Context, user *models.User) (int, error) {
+	ret := _m.Called(ctx, user)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(*models.User) int); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) int); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*models.User) error); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User) error); ok {
+		r1 = rf(ctx, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -32,13 +36,13 @@ func (_m *MockUserStore) Create(user *models.User) (int, error) {
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: id
-func (_m *MockUserStore) GetByID(id int) (*models.User, error) {
-	ret := _m.Called(id)
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *MockUserStore) GetByID(ctx context.Context, id int) (*models.User, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *models.User
-	if rf, ok := ret.Get(0).(func(int) *models.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
@@ -46,8 +50,8 @@ func (_m *MockUserStore) GetByID(id int) (*models.User, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -55,13 +59,13 @@ func (_m *MockUserStore) GetByID(id int) (*models.User, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: user
-func (_m *MockUserStore) Update(user *models.User) error {
-	ret := _m.Called(user)
+// Update provides a mock function with given fields: ctx, user
+func (_m *MockUserStore) Update(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*models.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -69,13 +73,13 @@ func (_m *MockUserStore) Update(user *models.User) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *MockUserStore) Delete(id int) error {
-	ret := _m.Called(id)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockUserStore) Delete(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -83,13 +87,13 @@ func (_m *MockUserStore) Delete(id int) error {
 	return r0
 }
 
-// GetUserByUsername provides a mock function with given fields: username
-func (_m *MockUserStore) GetUserByUsername(username string) (*models.User, error) {
-	ret := _m.Called(username)
+// GetUserByUsername provides a mock function with given fields: ctx, username
+func (_m *MockUserStore) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	ret := _m.Called(ctx, username)
 
 	var r0 *models.User
-	if rf, ok := ret.Get(0).(func(string) *models.User); ok {
-		r0 = rf(username)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, username)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
@@ -97,8 +101,8 @@ func (_m *MockUserStore) GetUserByUsername(username string) (*models.User, error
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(username)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -107,12 +111,12 @@ func (_m *MockUserStore) GetUserByUsername(username string) (*models.User, error
 }
 
 // GetAll provides a mock function with given fields:
-func (_m *MockUserStore) GetAll() ([]*models.User, error) {
-	ret := _m.Called()
+func (_m *MockUserStore) GetAll(ctx context.Context) ([]*models.User, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []*models.User
-	if rf, ok := ret.Get(0).(func() []*models.User); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.User); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*models.User)
@@ -120,8 +124,8 @@ func (_m *MockUserStore) GetAll() ([]*models.User, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -129,13 +133,13 @@ func (_m *MockUserStore) GetAll() ([]*models.User, error) {
 	return r0, r1
 }
 
-// UpdatePassword provides a mock function with given fields: id, passwordHash
-func (_m *MockUserStore) UpdatePassword(id int, passwordHash string) error {
-	ret := _m.Called(id, passwordHash)
+// UpdatePassword provides a mock function with given fields: ctx, id, passwordHash
+func (_m *MockUserStore) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	ret := _m.Called(ctx, id, passwordHash)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, string) error); ok {
-		r0 = rf(id, passwordHash)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, passwordHash)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -143,49 +147,103 @@ func (_m *MockUserStore) UpdatePassword(id int, passwordHash string) error {
 	return r0
 }
 
+// MockAuthSessionStore is a mock implementation of data.AuthSessionStore
+type MockAuthSessionStore struct {
+	mock.Mock
+}
+
+func (m *MockAuthSessionStore) Create(ctx context.Context, session *models.AuthSession) (int, error) {
+	args := m.Called(ctx, session)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuthSessionStore) GetByID(ctx context.Context, id int) (*models.AuthSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthSession), args.Error(1)
+}
+
+func (m *MockAuthSessionStore) GetByRefreshTokenHash(ctx context.Context, refreshTokenHash string) (*models.AuthSession, error) {
+	args := m.Called(ctx, refreshTokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthSession), args.Error(1)
+}
+
+func (m *MockAuthSessionStore) Rotate(ctx context.Context, id int, oldRefreshTokenHash, newRefreshTokenHash string, refreshedAt time.Time) error {
+	args := m.Called(ctx, id, oldRefreshTokenHash, newRefreshTokenHash, refreshedAt)
+	return args.Error(0)
+}
+
+func (m *MockAuthSessionStore) Revoke(ctx context.Context, id int, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockAuthSessionStore) RevokeAllForUser(ctx context.Context, userID int, revokedAt time.Time) (int, error) {
+	args := m.Called(ctx, userID, revokedAt)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuthSessionStore) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
 // MockAssignmentStore is a mock implementation of data.AssignmentStore
 type MockAssignmentStore struct {
 	mock.Mock
 }
 
-func (m *MockAssignmentStore) Create(assignment *models.Assignment) (int, error) {
-	args := m.Called(assignment)
+func (m *MockAssignmentStore) Create(ctx context.Context, assignment *models.Assignment) (int, error) {
+	args := m.Called(ctx, assignment)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockAssignmentStore) GetByID(id int) (*models.Assignment, error) {
-	args := m.Called(id)
+func (m *MockAssignmentStore) GetByID(ctx context.Context, id int) (*models.Assignment, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Assignment), args.Error(1)
 }
 
-func (m *MockAssignmentStore) Update(assignment *models.Assignment) error {
-	args := m.Called(assignment)
+func (m *MockAssignmentStore) Update(ctx context.Context, assignment *models.Assignment) error {
+	args := m.Called(ctx, assignment)
 	return args.Error(0)
 }
 
-func (m *MockAssignmentStore) Delete(id int) error {
-	args := m.Called(id)
+func (m *MockAssignmentStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockAssignmentStore) GetAssignmentHistoryForChild(childID int) ([]models.Assignment, error) {
-	args := m.Called(childID)
+func (m *MockAssignmentStore) GetAssignmentHistoryForChild(ctx context.Context, childID int) ([]models.Assignment, error) {
+	args := m.Called(ctx, childID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Assignment), args.Error(1)
 }
 
-func (m *MockAssignmentStore) EndAssignment(assignmentID int) error {
-	args := m.Called(assignmentID)
+func (m *MockAssignmentStore) EndAssignment(ctx context.Context, assignmentID int) error {
+	args := m.Called(ctx, assignmentID)
 	return args.Error(0)
 }
 
-func (m *MockAssignmentStore) GetAllAssignments() ([]models.Assignment, error) {
-	args := m.Called()
+func (m *MockAssignmentStore) GetAllAssignments(ctx context.Context) ([]models.Assignment, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Assignment), args.Error(1)
+}
+
+func (m *MockAssignmentStore) List(ctx context.Context, query models.ListQuery) ([]models.Assignment, error) {
+	args := m.Called(ctx, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -197,98 +255,153 @@ type MockChildStore struct {
 	mock.Mock
 }
 
-func (m *MockChildStore) Create(child *models.Child) (int, error) {
-	args := m.Called(child)
+func (m *MockChildStore) Create(ctx context.Context, child *models.Child) (int, error) {
+	args := m.Called(ctx, child)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockChildStore) GetByID(id int) (*models.Child, error) {
-	args := m.Called(id)
+func (m *MockChildStore) CreateMany(ctx context.Context, children []models.Child) ([]int, error) {
+	args := m.Called(ctx, children)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockChildStore) GetByID(ctx context.Context, id int) (*models.Child, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Child), args.Error(1)
 }
 
-func (m *MockChildStore) Update(child *models.Child) error {
-	args := m.Called(child)
+func (m *MockChildStore) Update(ctx context.Context, child *models.Child) error {
+	args := m.Called(ctx, child)
 	return args.Error(0)
 }
 
-func (m *MockChildStore) Delete(id int) error {
-	args := m.Called(id)
+func (m *MockChildStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockChildStore) GetAll() ([]models.Child, error) {
-	args := m.Called()
+func (m *MockChildStore) GetAll(ctx context.Context) ([]models.Child, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Child), args.Error(1)
+}
+
+func (m *MockChildStore) GetAllIncludingArchived(ctx context.Context) ([]models.Child, error) {
+	args := m.Called(ctx)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Child), args.Error(1)
 }
 
+func (m *MockChildStore) List(ctx context.Context, query models.ListQuery) ([]models.Child, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Child), args.Error(1)
+}
+
+func (m *MockChildStore) Count(ctx context.Context, query models.ListQuery) (int, error) {
+	args := m.Called(ctx, query)
+	return args.Int(0), args.Error(1)
+}
+
 // MockTeacherStore is a mock implementation of data.TeacherStore
 type MockTeacherStore struct {
 	mock.Mock
 }
 
-func (m *MockTeacherStore) Create(teacher *models.Teacher) (int, error) {
-	args := m.Called(teacher)
+func (m *MockTeacherStore) Create(ctx context.Context, teacher *models.Teacher) (int, error) {
+	args := m.Called(ctx, teacher)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockTeacherStore) GetByID(id int) (*models.Teacher, error) {
-	args := m.Called(id)
+func (m *MockTeacherStore) GetByID(ctx context.Context, id int) (*models.Teacher, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Teacher), args.Error(1)
 }
 
-func (m *MockTeacherStore) Update(teacher *models.Teacher) error {
-	args := m.Called(teacher)
+func (m *MockTeacherStore) Update(ctx context.Context, teacher *models.Teacher) error {
+	args := m.Called(ctx, teacher)
 	return args.Error(0)
 }
 
-func (m *MockTeacherStore) Delete(id int) error {
-	args := m.Called(id)
+func (m *MockTeacherStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockTeacherStore) GetAll() ([]models.Teacher, error) {
-	args := m.Called()
+func (m *MockTeacherStore) GetAll(ctx context.Context) ([]models.Teacher, error) {
+	args := m.Called(ctx)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Teacher), args.Error(1)
 }
 
+func (m *MockTeacherStore) CountUsage(ctx context.Context) (map[int]models.UsageCounts, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]models.UsageCounts), args.Error(1)
+}
+
+func (m *MockTeacherStore) SetDeactivated(ctx context.Context, id int, deactivatedAt *time.Time) error {
+	args := m.Called(ctx, id, deactivatedAt)
+	return args.Error(0)
+}
+
+func (m *MockTeacherStore) Merge(ctx context.Context, duplicateID int, teacherID int) error {
+	args := m.Called(ctx, duplicateID, teacherID)
+	return args.Error(0)
+}
+
 // MockDocumentationEntryStore is a mock implementation of data.DocumentationEntryStore
 type MockDocumentationEntryStore struct {
 	mock.Mock
 }
 
-func (m *MockDocumentationEntryStore) Create(entry *models.DocumentationEntry) (int, error) {
-	args := m.Called(entry)
+func (m *MockDocumentationEntryStore) Create(ctx context.Context, entry *models.DocumentationEntry) (int, error) {
+	args := m.Called(ctx, entry)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockDocumentationEntryStore) GetByID(id int) (*models.DocumentationEntry, error) {
-	args := m.Called(id)
+func (m *MockDocumentationEntryStore) CreateMany(ctx context.Context, entries []models.DocumentationEntry) ([]int, error) {
+	args := m.Called(ctx, entries)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockDocumentationEntryStore) GetByID(ctx context.Context, id int) (*models.DocumentationEntry, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.DocumentationEntry), args.Error(1)
 }
 
-func (m *MockDocumentationEntryStore) Update(entry *models.DocumentationEntry) error {
-	args := m.Called(entry)
+func (m *MockDocumentationEntryStore) Update(ctx context.Context, entry *models.DocumentationEntry) error {
+	args := m.Called(ctx, entry)
 	return args.Error(0)
 }
 
-func (m *MockDocumentationEntryStore) Delete(id int) error {
-	args := m.Called(id)
+func (m *MockDocumentationEntryStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
@@ -300,16 +413,71 @@ func (m *MockDocumentationEntryStore) GetAll() ([]models.DocumentationEntry, err
 	return args.Get(0).([]models.DocumentationEntry), args.Error(1)
 }
 
-func (m *MockDocumentationEntryStore) GetAllForChild(childID int) ([]models.DocumentationEntry, error) {
-	args := m.Called(childID)
+func (m *MockDocumentationEntryStore) GetAllForChild(ctx context.Context, childID int) ([]models.DocumentationEntry, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DocumentationEntry), args.Error(1)
+}
+
+func (m *MockDocumentationEntryStore) List(ctx context.Context, query models.ListQuery) ([]models.DocumentationEntry, error) {
+	args := m.Called(ctx, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.DocumentationEntry), args.Error(1)
 }
 
-func (m *MockDocumentationEntryStore) ApproveEntry(entryID, approvedByUserID int) error {
-	args := m.Called(entryID, approvedByUserID)
+func (m *MockDocumentationEntryStore) Count(ctx context.Context, query models.ListQuery) (int, error) {
+	args := m.Called(ctx, query)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDocumentationEntryStore) ApproveEntry(ctx context.Context, entryID, approvedByUserID int, outbox []models.OutboxMessage) error {
+	args := m.Called(ctx, entryID, approvedByUserID, outbox)
+	return args.Error(0)
+}
+
+func (m *MockDocumentationEntryStore) RecordReport(ctx context.Context, report *models.GeneratedReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+func (m *MockDocumentationEntryStore) GetReportsForChild(ctx context.Context, childID int) ([]models.GeneratedReport, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.GeneratedReport), args.Error(1)
+}
+
+func (m *MockDocumentationEntryStore) GetArchivedReportIDsSince(ctx context.Context, since time.Time) ([]int, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockDocumentationEntryStore) GetLastObservationDates(ctx context.Context) (map[int]models.Date, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]models.Date), args.Error(1)
+}
+
+func (m *MockDocumentationEntryStore) GetReportByID(ctx context.Context, reportID int) (*models.GeneratedReport, error) {
+	args := m.Called(ctx, reportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GeneratedReport), args.Error(1)
+}
+
+func (m *MockDocumentationEntryStore) Unlock(ctx context.Context, entryID int) error {
+	args := m.Called(ctx, entryID)
 	return args.Error(0)
 }
 
@@ -318,42 +486,73 @@ type MockCategoryStore struct {
 	mock.Mock
 }
 
-func (m *MockCategoryStore) Create(category *models.Category) (int, error) {
-	args := m.Called(category)
+func (m *MockCategoryStore) Create(ctx context.Context, category *models.Category) (int, error) {
+	args := m.Called(ctx, category)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockCategoryStore) GetByID(id int) (*models.Category, error) {
-	args := m.Called(id)
+func (m *MockCategoryStore) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Category), args.Error(1)
 }
 
-func (m *MockCategoryStore) GetByName(name string) (*models.Category, error) {
-	args := m.Called(name)
+func (m *MockCategoryStore) GetByName(ctx context.Context, name string) (*models.Category, error) {
+	args := m.Called(ctx, name)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Category), args.Error(1)
 }
 
-func (m *MockCategoryStore) GetAll() ([]models.Category, error) {
-	args := m.Called()
+func (m *MockCategoryStore) GetAll(ctx context.Context) ([]models.Category, error) {
+	args := m.Called(ctx)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Category), args.Error(1)
 }
 
-func (m *MockCategoryStore) Update(category *models.Category) error {
-	args := m.Called(category)
+func (m *MockCategoryStore) CountUsage(ctx context.Context) (map[int]models.UsageCounts, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]models.UsageCounts), args.Error(1)
+}
+
+func (m *MockCategoryStore) GetFavorites(ctx context.Context, userID int, teacherID int) ([]models.FavoriteCategory, error) {
+	args := m.Called(ctx, userID, teacherID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FavoriteCategory), args.Error(1)
+}
+
+func (m *MockCategoryStore) SetPinned(ctx context.Context, userID int, categoryID int, pinned bool) error {
+	args := m.Called(ctx, userID, categoryID, pinned)
+	return args.Error(0)
+}
+
+func (m *MockCategoryStore) SetArchived(ctx context.Context, id int, archivedAt *time.Time) error {
+	args := m.Called(ctx, id, archivedAt)
+	return args.Error(0)
+}
+
+func (m *MockCategoryStore) ReassignEntries(ctx context.Context, fromCategoryID int, toCategoryID int) (int, error) {
+	args := m.Called(ctx, fromCategoryID, toCategoryID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCategoryStore) Update(ctx context.Context, category *models.Category) error {
+	args := m.Called(ctx, category)
 	return args.Error(0)
 }
 
-func (m *MockCategoryStore) Delete(id int) error {
-	args := m.Called(id)
+func (m *MockCategoryStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
@@ -362,16 +561,39 @@ type MockKitaMasterdataStore struct {
 	mock.Mock
 }
 
-func (m *MockKitaMasterdataStore) Get() (*models.KitaMasterdata, error) {
-	args := m.Called()
+func (m *MockKitaMasterdataStore) Get(ctx context.Context) (*models.KitaMasterdata, error) {
+	args := m.Called(ctx)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.KitaMasterdata), args.Error(1)
 }
 
-func (m *MockKitaMasterdataStore) Update(data *models.KitaMasterdata) error {
-	args := m.Called(data)
+func (m *MockKitaMasterdataStore) Update(ctx context.Context, data *models.KitaMasterdata) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockKitaMasterdataStore) UpdateTheme(ctx context.Context, theme *models.DocumentTheme) error {
+	args := m.Called(ctx, theme)
+	return args.Error(0)
+}
+
+func (m *MockKitaMasterdataStore) UpdateTerminology(ctx context.Context, terminology models.Terminology) error {
+	args := m.Called(ctx, terminology)
+	return args.Error(0)
+}
+
+func (m *MockKitaMasterdataStore) GetLogo(ctx context.Context) (*models.DocumentLogo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DocumentLogo), args.Error(1)
+}
+
+func (m *MockKitaMasterdataStore) UpdateLogo(ctx context.Context, logo *models.DocumentLogo) error {
+	args := m.Called(ctx, logo)
 	return args.Error(0)
 }
 
@@ -380,29 +602,29 @@ type MockProcessStore struct {
 	mock.Mock
 }
 
-func (m *MockProcessStore) Create(process *models.Process) (*models.Process, error) {
-	args := m.Called(process)
+func (m *MockProcessStore) Create(ctx context.Context, process *models.Process) (*models.Process, error) {
+	args := m.Called(ctx, process)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Process), args.Error(1)
 }
 
-func (m *MockProcessStore) GetByID(id int) (*models.Process, error) {
-	args := m.Called(id)
+func (m *MockProcessStore) GetByID(ctx context.Context, id int) (*models.Process, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Process), args.Error(1)
 }
 
-func (m *MockProcessStore) Update(process *models.Process) error {
-	args := m.Called(process)
+func (m *MockProcessStore) Update(ctx context.Context, process *models.Process) error {
+	args := m.Called(ctx, process)
 	return args.Error(0)
 }
 
-func (m *MockProcessStore) Delete(id int) error {
-	args := m.Called(id)
+func (m *MockProcessStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
@@ -413,3 +635,1518 @@ func (m *MockProcessStore) GetAll() ([]models.Process, error) {
 	}
 	return args.Get(0).([]models.Process), args.Error(1)
 }
+
+// MockDeviceStore is a mock implementation of data.DeviceStore
+type MockDeviceStore struct {
+	mock.Mock
+}
+
+func (m *MockDeviceStore) Upsert(ctx context.Context, device *models.Device) (int, error) {
+	args := m.Called(ctx, device)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDeviceStore) GetByID(ctx context.Context, id int) (*models.Device, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Device), args.Error(1)
+}
+
+func (m *MockDeviceStore) GetAllForUser(ctx context.Context, userID int) ([]models.Device, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Device), args.Error(1)
+}
+
+func (m *MockDeviceStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDeviceStore) DeleteByToken(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+// MockNotificationPreferenceStore is a mock implementation of data.NotificationPreferenceStore
+type MockNotificationPreferenceStore struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceStore) Get(ctx context.Context, userID int) (*models.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceStore) Upsert(ctx context.Context, preferences *models.NotificationPreferences) error {
+	args := m.Called(ctx, preferences)
+	return args.Error(0)
+}
+
+// MockAnnouncementStore is a mock implementation of data.AnnouncementStore
+type MockAnnouncementStore struct {
+	mock.Mock
+}
+
+func (m *MockAnnouncementStore) Create(ctx context.Context, announcement *models.Announcement) (int, error) {
+	args := m.Called(ctx, announcement)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAnnouncementStore) GetByID(ctx context.Context, id int) (*models.Announcement, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementStore) GetAll(ctx context.Context) ([]models.Announcement, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAnnouncementStore) GetUnreadForUser(ctx context.Context, userID int) ([]models.Announcement, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementStore) Acknowledge(ctx context.Context, announcementID int, userID int) error {
+	args := m.Called(ctx, announcementID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAnnouncementStore) GetAcknowledgements(ctx context.Context, announcementID int) ([]models.AnnouncementAcknowledgement, error) {
+	args := m.Called(ctx, announcementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AnnouncementAcknowledgement), args.Error(1)
+}
+
+// MockSchoolYearStore is a mock implementation of data.SchoolYearStore
+type MockSchoolYearStore struct {
+	mock.Mock
+}
+
+func (m *MockSchoolYearStore) Rollover(ctx context.Context, request *models.RolloverRequest) (*models.RolloverSummary, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RolloverSummary), args.Error(1)
+}
+
+// MockApprovalDelegationStore is a mock implementation of data.ApprovalDelegationStore
+type MockApprovalDelegationStore struct {
+	mock.Mock
+}
+
+func (m *MockApprovalDelegationStore) Create(ctx context.Context, delegation *models.ApprovalDelegation) (int, error) {
+	args := m.Called(ctx, delegation)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockApprovalDelegationStore) GetByID(ctx context.Context, id int) (*models.ApprovalDelegation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ApprovalDelegation), args.Error(1)
+}
+
+func (m *MockApprovalDelegationStore) GetAllForUser(ctx context.Context, userID int) ([]models.ApprovalDelegation, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ApprovalDelegation), args.Error(1)
+}
+
+func (m *MockApprovalDelegationStore) GetBetween(ctx context.Context, delegatorUserID int, delegateUserID int) ([]models.ApprovalDelegation, error) {
+	args := m.Called(ctx, delegatorUserID, delegateUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ApprovalDelegation), args.Error(1)
+}
+
+func (m *MockApprovalDelegationStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockAuditLogStore is a mock implementation of data.AuditLogStore
+type MockAuditLogStore struct {
+	mock.Mock
+}
+
+func (m *MockAuditLogStore) Create(ctx context.Context, entry *models.AuditLogEntry) (int, error) {
+	args := m.Called(ctx, entry)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuditLogStore) GetForEntity(ctx context.Context, entityType string, entityID int) ([]models.AuditLogEntry, error) {
+	args := m.Called(ctx, entityType, entityID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuditLogEntry), args.Error(1)
+}
+
+func (m *MockAuditLogStore) GetForActor(ctx context.Context, actorUserID int) ([]models.AuditLogEntry, error) {
+	args := m.Called(ctx, actorUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuditLogEntry), args.Error(1)
+}
+
+func (m *MockAuditLogStore) GetForAction(ctx context.Context, action string, since time.Time) ([]models.AuditLogEntry, error) {
+	args := m.Called(ctx, action, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuditLogEntry), args.Error(1)
+}
+
+func (m *MockAuditLogStore) GetAll(ctx context.Context) ([]models.AuditLogEntry, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuditLogEntry), args.Error(1)
+}
+
+// MockDocumentationEventStore is a mock implementation of data.DocumentationEventStore
+type MockDocumentationEventStore struct {
+	mock.Mock
+}
+
+func (m *MockDocumentationEventStore) Append(ctx context.Context, event *models.DocumentationEvent, outbox []models.OutboxMessage) error {
+	args := m.Called(ctx, event, outbox)
+	return args.Error(0)
+}
+
+func (m *MockDocumentationEventStore) GetForChild(ctx context.Context, childID int) ([]models.DocumentationEvent, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DocumentationEvent), args.Error(1)
+}
+
+func (m *MockDocumentationEventStore) GetForEntry(ctx context.Context, entryID int) ([]models.DocumentationEvent, error) {
+	args := m.Called(ctx, entryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DocumentationEvent), args.Error(1)
+}
+
+func (m *MockDocumentationEventStore) GetAll(ctx context.Context) ([]models.DocumentationEvent, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DocumentationEvent), args.Error(1)
+}
+
+// MockValidationRulesStore is a mock implementation of data.ValidationRulesStore
+type MockValidationRulesStore struct {
+	mock.Mock
+}
+
+func (m *MockValidationRulesStore) Get(ctx context.Context) (*models.ValidationRules, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ValidationRules), args.Error(1)
+}
+
+func (m *MockValidationRulesStore) Upsert(ctx context.Context, rules *models.ValidationRules) error {
+	args := m.Called(ctx, rules)
+	return args.Error(0)
+}
+
+// MockDemoSnapshotStore is a mock implementation of data.DemoSnapshotStore
+type MockDemoSnapshotStore struct {
+	mock.Mock
+}
+
+func (m *MockDemoSnapshotStore) Create(ctx context.Context, now time.Time) (*data.DemoSnapshot, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*data.DemoSnapshot), args.Error(1)
+}
+
+// MockOutboxStore is a mock implementation of data.OutboxStore
+type MockOutboxStore struct {
+	mock.Mock
+}
+
+func (m *MockOutboxStore) Enqueue(ctx context.Context, message *models.OutboxMessage) error {
+	args := m.Called(ctx, message)
+	return args.Error(0)
+}
+
+func (m *MockOutboxStore) GetDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OutboxMessage), args.Error(1)
+}
+
+func (m *MockOutboxStore) GetByStatus(ctx context.Context, status string) ([]models.OutboxMessage, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OutboxMessage), args.Error(1)
+}
+
+func (m *MockOutboxStore) MarkDelivered(ctx context.Context, id int, deliveredAt time.Time) error {
+	args := m.Called(ctx, id, deliveredAt)
+	return args.Error(0)
+}
+
+func (m *MockOutboxStore) MarkRetry(ctx context.Context, id int, lastError string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, id, lastError, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockOutboxStore) MarkFailed(ctx context.Context, id int, lastError string) error {
+	args := m.Called(ctx, id, lastError)
+	return args.Error(0)
+}
+
+// MockBootstrapStore is a mock implementation of data.BootstrapStore
+type MockBootstrapStore struct {
+	mock.Mock
+}
+
+func (m *MockBootstrapStore) Run(ctx context.Context, categories []models.Category, groups []models.Group, userID *int, now time.Time) (*models.BootstrapResult, error) {
+	args := m.Called(ctx, categories, groups, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BootstrapResult), args.Error(1)
+}
+
+// MockGroupStore is a mock implementation of data.GroupStore
+type MockGroupStore struct {
+	mock.Mock
+}
+
+func (m *MockGroupStore) Create(ctx context.Context, group *models.Group) (int, error) {
+	args := m.Called(ctx, group)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockGroupStore) GetByID(ctx context.Context, id int) (*models.Group, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (m *MockGroupStore) Update(ctx context.Context, group *models.Group) error {
+	args := m.Called(ctx, group)
+	return args.Error(0)
+}
+
+func (m *MockGroupStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockGroupStore) GetAll(ctx context.Context) ([]models.Group, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Group), args.Error(1)
+}
+
+func (m *MockGroupStore) CountUsage(ctx context.Context) (map[int]models.UsageCounts, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]models.UsageCounts), args.Error(1)
+}
+
+func (m *MockGroupStore) AddChild(ctx context.Context, groupID int, childID int) error {
+	args := m.Called(ctx, groupID, childID)
+	return args.Error(0)
+}
+
+func (m *MockGroupStore) RemoveChild(ctx context.Context, groupID int, childID int) error {
+	args := m.Called(ctx, groupID, childID)
+	return args.Error(0)
+}
+
+// MockSchoolStore is a mock implementation of data.SchoolStore
+type MockSchoolStore struct {
+	mock.Mock
+}
+
+func (m *MockSchoolStore) Create(ctx context.Context, school *models.School) (int, error) {
+	args := m.Called(ctx, school)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSchoolStore) GetByID(ctx context.Context, id int) (*models.School, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.School), args.Error(1)
+}
+
+func (m *MockSchoolStore) Update(ctx context.Context, school *models.School) error {
+	args := m.Called(ctx, school)
+	return args.Error(0)
+}
+
+func (m *MockSchoolStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSchoolStore) GetAll(ctx context.Context) ([]models.School, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.School), args.Error(1)
+}
+
+func (m *MockSchoolStore) AddChild(ctx context.Context, schoolID int, childID int) error {
+	args := m.Called(ctx, schoolID, childID)
+	return args.Error(0)
+}
+
+func (m *MockSchoolStore) RemoveChild(ctx context.Context, schoolID int, childID int) error {
+	args := m.Called(ctx, schoolID, childID)
+	return args.Error(0)
+}
+
+// MockImportJobStore is a mock implementation of data.ImportJobStore
+type MockImportJobStore struct {
+	mock.Mock
+}
+
+func (m *MockImportJobStore) Create(ctx context.Context, job *models.ImportJob) (int, error) {
+	args := m.Called(ctx, job)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockImportJobStore) GetByID(ctx context.Context, id int) (*models.ImportJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportJob), args.Error(1)
+}
+
+func (m *MockImportJobStore) GetAll(ctx context.Context) ([]models.ImportJob, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ImportJob), args.Error(1)
+}
+
+func (m *MockImportJobStore) UpdateStatus(ctx context.Context, id int, status string) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *MockImportJobStore) ImportRow(ctx context.Context, jobID int, row *models.ImportJobRow) (int, error) {
+	args := m.Called(ctx, jobID, row)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockImportJobStore) ImportRows(ctx context.Context, jobID int, rows []models.ImportJobRow) (int, error) {
+	args := m.Called(ctx, jobID, rows)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockImportJobStore) RollbackRow(ctx context.Context, jobID int, rowNumber int) error {
+	args := m.Called(ctx, jobID, rowNumber)
+	return args.Error(0)
+}
+
+// MockInvitationStore is a mock implementation of data.InvitationStore
+type MockInvitationStore struct {
+	mock.Mock
+}
+
+func (m *MockInvitationStore) Create(ctx context.Context, invitation *models.Invitation) (int, error) {
+	args := m.Called(ctx, invitation)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockInvitationStore) GetByID(ctx context.Context, id int) (*models.Invitation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationStore) GetByTokenHash(ctx context.Context, tokenHash string) (*models.Invitation, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationStore) GetAll(ctx context.Context) ([]models.Invitation, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationStore) Claim(ctx context.Context, id int, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func (m *MockInvitationStore) Release(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockInvitationStore) SetUsedBy(ctx context.Context, id int, userID int) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockInvitationStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockDigestStore is a mock implementation of data.DigestStore
+type MockDigestStore struct {
+	mock.Mock
+}
+
+func (m *MockDigestStore) GetSent(ctx context.Context, weekStart models.Date) (map[int]bool, error) {
+	args := m.Called(ctx, weekStart)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]bool), args.Error(1)
+}
+
+func (m *MockDigestStore) RecordSent(ctx context.Context, userID int, weekStart models.Date, message models.OutboxMessage) (bool, error) {
+	args := m.Called(ctx, userID, weekStart, message)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockQualityReportStore is a mock implementation of data.QualityReportStore
+type MockQualityReportStore struct {
+	mock.Mock
+}
+
+func (m *MockQualityReportStore) Create(ctx context.Context, report *models.ArchivedQualityReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+func (m *MockQualityReportStore) Get(ctx context.Context, month string) (*models.ArchivedQualityReport, error) {
+	args := m.Called(ctx, month)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ArchivedQualityReport), args.Error(1)
+}
+
+func (m *MockQualityReportStore) GetAll(ctx context.Context) ([]models.ArchivedQualityReport, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ArchivedQualityReport), args.Error(1)
+}
+
+// MockTermsStore is a mock implementation of data.TermsStore
+type MockTermsStore struct {
+	mock.Mock
+}
+
+func (m *MockTermsStore) Publish(ctx context.Context, version *models.TermsVersion) (int, error) {
+	args := m.Called(ctx, version)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTermsStore) GetCurrent(ctx context.Context) (*models.TermsVersion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TermsVersion), args.Error(1)
+}
+
+func (m *MockTermsStore) GetAll(ctx context.Context) ([]models.TermsVersion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TermsVersion), args.Error(1)
+}
+
+func (m *MockTermsStore) GetPendingForUser(ctx context.Context, userID int) (*models.TermsVersion, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TermsVersion), args.Error(1)
+}
+
+func (m *MockTermsStore) Accept(ctx context.Context, userID int, version int) (bool, error) {
+	args := m.Called(ctx, userID, version)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTermsStore) GetLastAcceptance(ctx context.Context, userID int) (*models.TermsAcceptance, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TermsAcceptance), args.Error(1)
+}
+
+// MockStatusBannerStore is a mock implementation of data.StatusBannerStore
+type MockStatusBannerStore struct {
+	mock.Mock
+}
+
+func (m *MockStatusBannerStore) Get(ctx context.Context) (*models.StatusBanner, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StatusBanner), args.Error(1)
+}
+
+func (m *MockStatusBannerStore) Set(ctx context.Context, banner *models.StatusBanner) error {
+	args := m.Called(ctx, banner)
+	return args.Error(0)
+}
+
+func (m *MockStatusBannerStore) Delete(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// MockEmailTemplateStore is a mock implementation of data.EmailTemplateStore
+type MockEmailTemplateStore struct {
+	mock.Mock
+}
+
+func (m *MockEmailTemplateStore) Publish(ctx context.Context, template *models.EmailTemplate) (int, error) {
+	args := m.Called(ctx, template)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockEmailTemplateStore) GetCurrent(ctx context.Context, key string) (*models.EmailTemplate, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailTemplate), args.Error(1)
+}
+
+func (m *MockEmailTemplateStore) GetVersions(ctx context.Context, key string) ([]models.EmailTemplate, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.EmailTemplate), args.Error(1)
+}
+
+// MockUptimeStore is a mock implementation of data.UptimeStore
+type MockUptimeStore struct {
+	mock.Mock
+}
+
+func (m *MockUptimeStore) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockUptimeStore) RecordCheck(ctx context.Context, check *models.HealthCheck) error {
+	args := m.Called(ctx, check)
+	return args.Error(0)
+}
+
+func (m *MockUptimeStore) GetChecks(ctx context.Context, from time.Time, to time.Time) ([]models.HealthCheck, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HealthCheck), args.Error(1)
+}
+
+func (m *MockUptimeStore) GetFirstCheck(ctx context.Context) (*models.HealthCheck, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HealthCheck), args.Error(1)
+}
+
+func (m *MockUptimeStore) GetLastCheck(ctx context.Context) (*models.HealthCheck, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HealthCheck), args.Error(1)
+}
+
+func (m *MockUptimeStore) DeleteChecksBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUptimeStore) CreateIncident(ctx context.Context, incident *models.UptimeIncident) (int, error) {
+	args := m.Called(ctx, incident)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUptimeStore) GetIncidentByID(ctx context.Context, id int) (*models.UptimeIncident, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UptimeIncident), args.Error(1)
+}
+
+func (m *MockUptimeStore) GetIncidents(ctx context.Context, from time.Time, to time.Time) ([]models.UptimeIncident, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.UptimeIncident), args.Error(1)
+}
+
+func (m *MockUptimeStore) DeleteIncident(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockEditLockStore is a mock implementation of data.EditLockStore
+type MockEditLockStore struct {
+	mock.Mock
+}
+
+func (m *MockEditLockStore) Get(ctx context.Context, entryID int) (*models.EditLock, error) {
+	args := m.Called(ctx, entryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EditLock), args.Error(1)
+}
+
+func (m *MockEditLockStore) Acquire(ctx context.Context, lock *models.EditLock, now time.Time) (*models.EditLock, error) {
+	args := m.Called(ctx, lock, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EditLock), args.Error(1)
+}
+
+func (m *MockEditLockStore) Release(ctx context.Context, entryID int, userID int) error {
+	args := m.Called(ctx, entryID, userID)
+	return args.Error(0)
+}
+
+// MockReportShareLinkStore is a mock implementation of data.ReportShareLinkStore
+type MockReportShareLinkStore struct {
+	mock.Mock
+}
+
+func (m *MockReportShareLinkStore) Create(ctx context.Context, link *models.ReportShareLink) (int, error) {
+	args := m.Called(ctx, link)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReportShareLinkStore) GetByID(ctx context.Context, id int) (*models.ReportShareLink, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReportShareLink), args.Error(1)
+}
+
+func (m *MockReportShareLinkStore) GetByTokenHash(ctx context.Context, tokenHash string) (*models.ReportShareLink, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReportShareLink), args.Error(1)
+}
+
+func (m *MockReportShareLinkStore) GetForReport(ctx context.Context, reportID int) ([]models.ReportShareLink, error) {
+	args := m.Called(ctx, reportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReportShareLink), args.Error(1)
+}
+
+func (m *MockReportShareLinkStore) Revoke(ctx context.Context, id int, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockReportShareLinkStore) RecordAccess(ctx context.Context, access *models.ReportShareLinkAccess) error {
+	args := m.Called(ctx, access)
+	return args.Error(0)
+}
+
+func (m *MockReportShareLinkStore) GetAccesses(ctx context.Context, shareLinkID int) ([]models.ReportShareLinkAccess, error) {
+	args := m.Called(ctx, shareLinkID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReportShareLinkAccess), args.Error(1)
+}
+
+func (m *MockReportShareLinkStore) CountFailedAccesses(ctx context.Context, shareLinkID int) (int, error) {
+	args := m.Called(ctx, shareLinkID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockBreakGlassStore is a mock implementation of data.BreakGlassStore
+type MockBreakGlassStore struct {
+	mock.Mock
+}
+
+func (m *MockBreakGlassStore) Seal(ctx context.Context, credential *models.BreakGlassCredential) (int, error) {
+	args := m.Called(ctx, credential)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockBreakGlassStore) GetByID(ctx context.Context, id int) (*models.BreakGlassCredential, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BreakGlassCredential), args.Error(1)
+}
+
+func (m *MockBreakGlassStore) GetSealed(ctx context.Context) (*models.BreakGlassCredential, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BreakGlassCredential), args.Error(1)
+}
+
+func (m *MockBreakGlassStore) GetAll(ctx context.Context) ([]models.BreakGlassCredential, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BreakGlassCredential), args.Error(1)
+}
+
+func (m *MockBreakGlassStore) Revoke(ctx context.Context, id int, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockBreakGlassStore) Activate(ctx context.Context, credential *models.BreakGlassCredential, alerts []models.OutboxMessage) error {
+	args := m.Called(ctx, credential, alerts)
+	return args.Error(0)
+}
+
+// MockSupportProviderStore is a mock implementation of data.SupportProviderStore
+type MockSupportProviderStore struct {
+	mock.Mock
+}
+
+func (m *MockSupportProviderStore) Create(ctx context.Context, provider *models.SupportProvider) (int, error) {
+	args := m.Called(ctx, provider)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSupportProviderStore) GetByID(ctx context.Context, id int) (*models.SupportProvider, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupportProvider), args.Error(1)
+}
+
+func (m *MockSupportProviderStore) Update(ctx context.Context, provider *models.SupportProvider) error {
+	args := m.Called(ctx, provider)
+	return args.Error(0)
+}
+
+func (m *MockSupportProviderStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSupportProviderStore) GetAll(ctx context.Context) ([]models.SupportProvider, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SupportProvider), args.Error(1)
+}
+
+func (m *MockSupportProviderStore) CreateSupport(ctx context.Context, support *models.ChildSupport) (int, error) {
+	args := m.Called(ctx, support)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSupportProviderStore) GetSupportByID(ctx context.Context, id int) (*models.ChildSupport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChildSupport), args.Error(1)
+}
+
+func (m *MockSupportProviderStore) UpdateSupport(ctx context.Context, support *models.ChildSupport) error {
+	args := m.Called(ctx, support)
+	return args.Error(0)
+}
+
+func (m *MockSupportProviderStore) DeleteSupport(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSupportProviderStore) GetSupportsForChild(ctx context.Context, childID int) ([]models.ChildSupport, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ChildSupport), args.Error(1)
+}
+
+// MockDailyCareStore is a mock implementation of data.DailyCareStore
+type MockDailyCareStore struct {
+	mock.Mock
+}
+
+func (m *MockDailyCareStore) Upsert(ctx context.Context, logs []models.DailyCareLog) error {
+	args := m.Called(ctx, logs)
+	return args.Error(0)
+}
+
+func (m *MockDailyCareStore) Get(ctx context.Context, childID int, day models.Date) (*models.DailyCareLog, error) {
+	args := m.Called(ctx, childID, day)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DailyCareLog), args.Error(1)
+}
+
+func (m *MockDailyCareStore) GetForChild(ctx context.Context, childID int, from models.Date, to models.Date) ([]models.DailyCareLog, error) {
+	args := m.Called(ctx, childID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailyCareLog), args.Error(1)
+}
+
+func (m *MockDailyCareStore) GetForDay(ctx context.Context, day models.Date) ([]models.DailyCareLog, error) {
+	args := m.Called(ctx, day)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailyCareLog), args.Error(1)
+}
+
+func (m *MockDailyCareStore) Delete(ctx context.Context, childID int, day models.Date) error {
+	args := m.Called(ctx, childID, day)
+	return args.Error(0)
+}
+
+// MockIncidentStore is a mock implementation of data.IncidentStore
+type MockIncidentStore struct {
+	mock.Mock
+}
+
+func (m *MockIncidentStore) Create(ctx context.Context, incident *models.Incident) (int, error) {
+	args := m.Called(ctx, incident)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockIncidentStore) GetByID(ctx context.Context, id int) (*models.Incident, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Incident), args.Error(1)
+}
+
+func (m *MockIncidentStore) Update(ctx context.Context, incident *models.Incident) error {
+	args := m.Called(ctx, incident)
+	return args.Error(0)
+}
+
+func (m *MockIncidentStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockIncidentStore) GetForChild(ctx context.Context, childID int) ([]models.Incident, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Incident), args.Error(1)
+}
+
+// MockMedicationStore is a mock implementation of data.MedicationStore
+type MockMedicationStore struct {
+	mock.Mock
+}
+
+func (m *MockMedicationStore) Create(ctx context.Context, administration *models.MedicationAdministration) (int, error) {
+	args := m.Called(ctx, administration)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMedicationStore) GetByID(ctx context.Context, id int) (*models.MedicationAdministration, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MedicationAdministration), args.Error(1)
+}
+
+func (m *MockMedicationStore) Confirm(ctx context.Context, id int, userID int, at time.Time) error {
+	args := m.Called(ctx, id, userID, at)
+	return args.Error(0)
+}
+
+func (m *MockMedicationStore) GetForChild(ctx context.Context, childID int) ([]models.MedicationAdministration, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MedicationAdministration), args.Error(1)
+}
+
+// MockGalleryStore is a mock implementation of data.GalleryStore
+type MockGalleryStore struct {
+	mock.Mock
+}
+
+func (m *MockGalleryStore) Create(ctx context.Context, photo *models.GalleryPhoto) (int, error) {
+	args := m.Called(ctx, photo)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockGalleryStore) GetByID(ctx context.Context, id int) (*models.GalleryPhoto, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GalleryPhoto), args.Error(1)
+}
+
+func (m *MockGalleryStore) GetWithContent(ctx context.Context, id int) (*models.GalleryPhoto, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GalleryPhoto), args.Error(1)
+}
+
+func (m *MockGalleryStore) Update(ctx context.Context, photo *models.GalleryPhoto) error {
+	args := m.Called(ctx, photo)
+	return args.Error(0)
+}
+
+func (m *MockGalleryStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockGalleryStore) GetForGroup(ctx context.Context, groupID int) ([]models.GalleryPhoto, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.GalleryPhoto), args.Error(1)
+}
+
+func (m *MockGalleryStore) GetForChild(ctx context.Context, childID int) ([]models.GalleryPhoto, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.GalleryPhoto), args.Error(1)
+}
+
+func (m *MockGalleryStore) SetConsent(ctx context.Context, consent *models.PhotoConsent) error {
+	args := m.Called(ctx, consent)
+	return args.Error(0)
+}
+
+func (m *MockGalleryStore) GetConsent(ctx context.Context, childID int) (*models.PhotoConsent, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PhotoConsent), args.Error(1)
+}
+
+func (m *MockGalleryStore) DeleteConsent(ctx context.Context, childID int) error {
+	args := m.Called(ctx, childID)
+	return args.Error(0)
+}
+
+func (m *MockGalleryStore) GetConsentedChildIDs(ctx context.Context) (map[int]bool, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]bool), args.Error(1)
+}
+
+// MockChildDocumentStore is a mock implementation of data.ChildDocumentStore
+type MockChildDocumentStore struct {
+	mock.Mock
+}
+
+func (m *MockChildDocumentStore) Create(ctx context.Context, document *models.ChildDocument) (int, error) {
+	args := m.Called(ctx, document)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockChildDocumentStore) GetByID(ctx context.Context, id int) (*models.ChildDocument, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChildDocument), args.Error(1)
+}
+
+func (m *MockChildDocumentStore) GetWithContent(ctx context.Context, id int) (*models.ChildDocument, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChildDocument), args.Error(1)
+}
+
+func (m *MockChildDocumentStore) Update(ctx context.Context, document *models.ChildDocument) error {
+	args := m.Called(ctx, document)
+	return args.Error(0)
+}
+
+func (m *MockChildDocumentStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockChildDocumentStore) GetForChild(ctx context.Context, childID int) ([]models.ChildDocument, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ChildDocument), args.Error(1)
+}
+
+func (m *MockChildDocumentStore) GetAll(ctx context.Context) ([]models.ChildDocument, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ChildDocument), args.Error(1)
+}
+
+// MockAttendanceStore is a mock implementation of data.AttendanceStore
+type MockAttendanceStore struct {
+	mock.Mock
+}
+
+func (m *MockAttendanceStore) Create(ctx context.Context, record *models.AttendanceRecord) (int, error) {
+	args := m.Called(ctx, record)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAttendanceStore) GetByID(ctx context.Context, id int) (*models.AttendanceRecord, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AttendanceRecord), args.Error(1)
+}
+
+func (m *MockAttendanceStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAttendanceStore) GetForChild(ctx context.Context, childID int, from models.Date, to models.Date) ([]models.AttendanceRecord, error) {
+	args := m.Called(ctx, childID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AttendanceRecord), args.Error(1)
+}
+
+func (m *MockAttendanceStore) GetForDay(ctx context.Context, day models.Date) ([]models.AttendanceRecord, error) {
+	args := m.Called(ctx, day)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AttendanceRecord), args.Error(1)
+}
+
+func (m *MockAttendanceStore) GetLatestForChild(ctx context.Context, childID int, day models.Date) (*models.AttendanceRecord, error) {
+	args := m.Called(ctx, childID, day)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AttendanceRecord), args.Error(1)
+}
+
+func (m *MockAttendanceStore) SetCheckInCode(ctx context.Context, childID int, codeHash string, code string) error {
+	args := m.Called(ctx, childID, codeHash, code)
+	return args.Error(0)
+}
+
+func (m *MockAttendanceStore) GetCheckInCode(ctx context.Context, childID int) (string, error) {
+	args := m.Called(ctx, childID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAttendanceStore) GetChildIDByCheckInCode(ctx context.Context, codeHash string) (int, error) {
+	args := m.Called(ctx, codeHash)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAttendanceStore) DeleteCheckInCode(ctx context.Context, childID int) error {
+	args := m.Called(ctx, childID)
+	return args.Error(0)
+}
+
+// MockCareContractStore is a mock implementation of data.CareContractStore
+type MockCareContractStore struct {
+	mock.Mock
+}
+
+func (m *MockCareContractStore) Create(ctx context.Context, contract *models.CareContract) (int, error) {
+	args := m.Called(ctx, contract)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCareContractStore) GetByID(ctx context.Context, id int) (*models.CareContract, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CareContract), args.Error(1)
+}
+
+func (m *MockCareContractStore) Update(ctx context.Context, contract *models.CareContract) error {
+	args := m.Called(ctx, contract)
+	return args.Error(0)
+}
+
+func (m *MockCareContractStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCareContractStore) GetForChild(ctx context.Context, childID int) ([]models.CareContract, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CareContract), args.Error(1)
+}
+
+func (m *MockCareContractStore) GetValidBetween(ctx context.Context, from models.Date, to models.Date) ([]models.CareContract, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CareContract), args.Error(1)
+}
+
+// MockProjectStore is a mock implementation of data.ProjectStore
+type MockProjectStore struct {
+	mock.Mock
+}
+
+func (m *MockProjectStore) Create(ctx context.Context, project *models.Project) (int, error) {
+	args := m.Called(ctx, project)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProjectStore) GetByID(ctx context.Context, id int) (*models.Project, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Project), args.Error(1)
+}
+
+func (m *MockProjectStore) Update(ctx context.Context, project *models.Project) error {
+	args := m.Called(ctx, project)
+	return args.Error(0)
+}
+
+func (m *MockProjectStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockProjectStore) GetAll(ctx context.Context) ([]models.Project, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Project), args.Error(1)
+}
+
+// MockTeamMeetingStore is a mock implementation of data.TeamMeetingStore
+type MockTeamMeetingStore struct {
+	mock.Mock
+}
+
+func (m *MockTeamMeetingStore) Create(ctx context.Context, meeting *models.TeamMeeting) (int, error) {
+	args := m.Called(ctx, meeting)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTeamMeetingStore) GetByID(ctx context.Context, id int) (*models.TeamMeeting, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TeamMeeting), args.Error(1)
+}
+
+func (m *MockTeamMeetingStore) Update(ctx context.Context, meeting *models.TeamMeeting) error {
+	args := m.Called(ctx, meeting)
+	return args.Error(0)
+}
+
+func (m *MockTeamMeetingStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTeamMeetingStore) GetAll(ctx context.Context) ([]models.TeamMeeting, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TeamMeeting), args.Error(1)
+}
+
+func (m *MockTeamMeetingStore) GetForChild(ctx context.Context, childID int) ([]models.TeamMeeting, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TeamMeeting), args.Error(1)
+}
+
+func (m *MockTeamMeetingStore) CreateActionItem(ctx context.Context, item *models.ActionItem) (int, error) {
+	args := m.Called(ctx, item)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTeamMeetingStore) GetActionItemByID(ctx context.Context, id int) (*models.ActionItem, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ActionItem), args.Error(1)
+}
+
+func (m *MockTeamMeetingStore) UpdateActionItem(ctx context.Context, item *models.ActionItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockTeamMeetingStore) SetActionItemCompleted(ctx context.Context, id int, completedAt *time.Time) error {
+	args := m.Called(ctx, id, completedAt)
+	return args.Error(0)
+}
+
+func (m *MockTeamMeetingStore) DeleteActionItem(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTeamMeetingStore) GetOpenActionItems(ctx context.Context) ([]models.ActionItem, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ActionItem), args.Error(1)
+}
+
+// MockGroupHandoverStore is a mock implementation of data.GroupHandoverStore
+type MockGroupHandoverStore struct {
+	mock.Mock
+}
+
+func (m *MockGroupHandoverStore) Create(ctx context.Context, handover *models.GroupHandover) (int, error) {
+	args := m.Called(ctx, handover)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockGroupHandoverStore) GetByID(ctx context.Context, id int) (*models.GroupHandover, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupHandover), args.Error(1)
+}
+
+func (m *MockGroupHandoverStore) GetForChild(ctx context.Context, childID int) ([]models.GroupHandover, error) {
+	args := m.Called(ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.GroupHandover), args.Error(1)
+}
+
+func (m *MockGroupHandoverStore) GetUnconfirmed(ctx context.Context) ([]models.GroupHandover, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.GroupHandover), args.Error(1)
+}
+
+func (m *MockGroupHandoverStore) Confirm(ctx context.Context, id int, confirmation *models.HandoverConfirmation) error {
+	args := m.Called(ctx, id, confirmation)
+	return args.Error(0)
+}
+
+// MockBenchmarkStore is a mock implementation of data.BenchmarkStore
+type MockBenchmarkStore struct {
+	mock.Mock
+}
+
+func (m *MockBenchmarkStore) Upsert(ctx context.Context, aggregate *models.BenchmarkAggregate) error {
+	args := m.Called(ctx, aggregate)
+	return args.Error(0)
+}
+
+func (m *MockBenchmarkStore) GetForMonth(ctx context.Context, month string) ([]models.BenchmarkAggregate, error) {
+	args := m.Called(ctx, month)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BenchmarkAggregate), args.Error(1)
+}
+
+// MockQuarantinedUploadStore is a mock implementation of data.QuarantinedUploadStore
+type MockQuarantinedUploadStore struct {
+	mock.Mock
+}
+
+func (m *MockQuarantinedUploadStore) Create(ctx context.Context, upload *models.QuarantinedUpload) (int, error) {
+	args := m.Called(ctx, upload)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockQuarantinedUploadStore) GetAll(ctx context.Context) ([]models.QuarantinedUpload, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.QuarantinedUpload), args.Error(1)
+}
+
+func (m *MockQuarantinedUploadStore) GetWithContent(ctx context.Context, id int) (*models.QuarantinedUpload, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.QuarantinedUpload), args.Error(1)
+}
+
+func (m *MockQuarantinedUploadStore) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockResumableUploadStore is a mock implementation of data.ResumableUploadStore
+type MockResumableUploadStore struct {
+	mock.Mock
+}
+
+func (m *MockResumableUploadStore) Create(ctx context.Context, upload *models.ResumableUpload) error {
+	args := m.Called(ctx, upload)
+	return args.Error(0)
+}
+
+func (m *MockResumableUploadStore) Get(ctx context.Context, id string) (*models.ResumableUpload, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ResumableUpload), args.Error(1)
+}
+
+func (m *MockResumableUploadStore) AppendChunk(ctx context.Context, id string, offset int, chunk []byte) error {
+	args := m.Called(ctx, id, offset, chunk)
+	return args.Error(0)
+}
+
+func (m *MockResumableUploadStore) GetContent(ctx context.Context, id string) ([]byte, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockResumableUploadStore) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockResumableUploadStore) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// NotificationPreferenceStore defines the interface for NotificationPreferences data operations.
+type NotificationPreferenceStore interface {
+	Get(ctx context.Context, userID int) (*models.NotificationPreferences, error)
+	Upsert(ctx context.Context, preferences *models.NotificationPreferences) error
+}
+
+// SQLNotificationPreferenceStore implements NotificationPreferenceStore using database/sql.
+type SQLNotificationPreferenceStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLNotificationPreferenceStore creates a new SQLNotificationPreferenceStore.
+func NewSQLNotificationPreferenceStore(db *sql.DB, encryptionKey []byte) *SQLNotificationPreferenceStore {
+	return &SQLNotificationPreferenceStore{db: db, encryptionKey: encryptionKey}
+}
+
+// Get fetches the notification preferences of a user.
+func (s *SQLNotificationPreferenceStore) Get(ctx context.Context, userID int) (*models.NotificationPreferences, error) {
+	query := `SELECT user_id, push_enabled, notify_approvals, notify_rejections, notify_reminders, notify_digest, digest_email, updated_at FROM notification_preferences WHERE user_id = ?`
+	row := s.db.QueryRowContext(ctx, query, userID)
+	preferences := &models.NotificationPreferences{}
+	var digestEmail sql.NullString
+	err := row.Scan(&preferences.UserID, &preferences.PushEnabled, &preferences.NotifyApprovals, &preferences.NotifyRejections, &preferences.NotifyReminders, &preferences.NotifyDigest, &digestEmail, &preferences.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if digestEmail.Valid {
+		decrypted, err := Decrypt(digestEmail.String, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt digest email: %w", err)
+		}
+		preferences.DigestEmail = &decrypted
+	}
+	return preferences, nil
+}
+
+// Upsert creates or replaces the notification preferences of a user.
+func (s *SQLNotificationPreferenceStore) Upsert(ctx context.Context, preferences *models.NotificationPreferences) error {
+	var digestEmail *string
+	if preferences.DigestEmail != nil {
+		encrypted, err := Encrypt(*preferences.DigestEmail, s.encryptionKey)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt digest email: %w", err)
+		}
+		digestEmail = &encrypted
+	}
+	query := `INSERT INTO notification_preferences (user_id, push_enabled, notify_approvals, notify_rejections, notify_reminders, notify_digest, digest_email, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(user_id) DO UPDATE SET
+			push_enabled = excluded.push_enabled,
+			notify_approvals = excluded.notify_approvals,
+			notify_rejections = excluded.notify_rejections,
+			notify_reminders = excluded.notify_reminders,
+			notify_digest = excluded.notify_digest,
+			digest_email = excluded.digest_email,
+			updated_at = CURRENT_TIMESTAMP`
+	_, err := s.db.ExecContext(ctx, query, preferences.UserID, preferences.PushEnabled, preferences.NotifyApprovals, preferences.NotifyRejections, preferences.NotifyReminders, preferences.NotifyDigest, digestEmail)
+	return err
+}
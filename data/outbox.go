@@ -0,0 +1,145 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// OutboxStore defines the interface for OutboxMessage data operations.
+type OutboxStore interface {
+	Enqueue(ctx context.Context, message *models.OutboxMessage) error
+	GetDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error)
+	GetByStatus(ctx context.Context, status string) ([]models.OutboxMessage, error)
+	MarkDelivered(ctx context.Context, id int, deliveredAt time.Time) error
+	MarkRetry(ctx context.Context, id int, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id int, lastError string) error
+}
+
+// SQLOutboxStore implements OutboxStore using database/sql.
+type SQLOutboxStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLOutboxStore creates a new SQLOutboxStore.
+func NewSQLOutboxStore(db *sql.DB, encryptionKey []byte) *SQLOutboxStore {
+	return &SQLOutboxStore{db: db, encryptionKey: encryptionKey}
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// enqueueOutboxMessages writes messages to the outbox. Stores call it within the transaction of the change
+// that caused the messages, so that they are written if and only if the change is committed. The services set
+// NextAttemptAt from their clock, messages are sent from then on.
+func enqueueOutboxMessages(ctx context.Context, db execer, key []byte, messages []models.OutboxMessage) error {
+	for i := range messages {
+		message := &messages[i]
+		payload, err := Encrypt(string(message.Payload), key)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt outbox payload: %w", err)
+		}
+		message.Status = models.OutboxStatusPending
+		query := `INSERT INTO outbox (channel, payload, status, next_attempt_at) VALUES (?, ?, ?, ?) RETURNING message_id`
+		var id int
+		if err := db.QueryRowContext(ctx, query, message.Channel, payload, message.Status, message.NextAttemptAt).Scan(&id); err != nil {
+			return err
+		}
+		message.ID = id
+	}
+	return nil
+}
+
+// Enqueue adds a message that is not tied to another change to the outbox.
+func (s *SQLOutboxStore) Enqueue(ctx context.Context, message *models.OutboxMessage) error {
+	messages := []models.OutboxMessage{*message}
+	if err := enqueueOutboxMessages(ctx, s.db, s.encryptionKey, messages); err != nil {
+		return err
+	}
+	*message = messages[0]
+	return nil
+}
+
+// GetDue fetches pending messages whose next attempt is due, oldest first.
+func (s *SQLOutboxStore) GetDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error) {
+	query := `SELECT message_id, channel, payload, status, attempts, last_error, next_attempt_at, created_at, delivered_at FROM outbox WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at ASC, message_id ASC LIMIT ?`
+	return s.queryMessages(ctx, query, models.OutboxStatusPending, now, limit)
+}
+
+// GetByStatus fetches all messages in a state, newest first.
+func (s *SQLOutboxStore) GetByStatus(ctx context.Context, status string) ([]models.OutboxMessage, error) {
+	query := `SELECT message_id, channel, payload, status, attempts, last_error, next_attempt_at, created_at, delivered_at FROM outbox WHERE status = ? ORDER BY message_id DESC`
+	return s.queryMessages(ctx, query, status)
+}
+
+// MarkDelivered marks a message as delivered.
+func (s *SQLOutboxStore) MarkDelivered(ctx context.Context, id int, deliveredAt time.Time) error {
+	query := `UPDATE outbox SET status = ?, attempts = attempts + 1, last_error = NULL, delivered_at = ? WHERE message_id = ?`
+	return s.update(ctx, query, models.OutboxStatusDelivered, deliveredAt, id)
+}
+
+// MarkRetry records a failed attempt and schedules the next one.
+func (s *SQLOutboxStore) MarkRetry(ctx context.Context, id int, lastError string, nextAttemptAt time.Time) error {
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE message_id = ?`
+	return s.update(ctx, query, lastError, nextAttemptAt, id)
+}
+
+// MarkFailed records a failed attempt and gives up on the message.
+func (s *SQLOutboxStore) MarkFailed(ctx context.Context, id int, lastError string) error {
+	query := `UPDATE outbox SET status = ?, attempts = attempts + 1, last_error = ? WHERE message_id = ?`
+	return s.update(ctx, query, models.OutboxStatusFailed, lastError, id)
+}
+
+func (s *SQLOutboxStore) update(ctx context.Context, query string, args ...any) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLOutboxStore) queryMessages(ctx context.Context, query string, args ...any) ([]models.OutboxMessage, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var messages []models.OutboxMessage
+	for rows.Next() {
+		message := models.OutboxMessage{}
+		var payload string
+		var lastError sql.NullString
+		err := rows.Scan(&message.ID, &message.Channel, &payload, &message.Status, &message.Attempts, &lastError, &message.NextAttemptAt, &message.CreatedAt, &message.DeliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		decrypted, err := Decrypt(payload, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt payload of outbox message %d: %w", message.ID, err)
+		}
+		message.Payload = []byte(decrypted)
+		message.LastError = lastError.String
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,89 @@
+package data_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLOutboxStore_Enqueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLOutboxStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	now := time.Date(2025, time.March, 5, 9, 0, 0, 0, time.UTC)
+	message := &models.OutboxMessage{Channel: models.OutboxChannelPush, Payload: []byte(`{"user_id":1}`), NextAttemptAt: now}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO outbox (channel, payload, status, next_attempt_at) VALUES (?, ?, ?, ?) RETURNING message_id`)).
+		WithArgs(models.OutboxChannelPush, sqlmock.AnyArg(), models.OutboxStatusPending, now).
+		WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow(7))
+
+	err = store.Enqueue(t.Context(), message)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, message.ID)
+	assert.Equal(t, models.OutboxStatusPending, message.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLOutboxStore_GetDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	store := data.NewSQLOutboxStore(db, key)
+	payload, err := data.Encrypt(`{"user_id":1}`, key)
+	assert.NoError(t, err)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT message_id, channel, payload, status, attempts, last_error, next_attempt_at, created_at, delivered_at FROM outbox WHERE status = ? AND next_attempt_at <= ?`)).
+		WithArgs(models.OutboxStatusPending, now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"message_id", "channel", "payload", "status", "attempts", "last_error", "next_attempt_at", "created_at", "delivered_at"}).
+			AddRow(7, "push", payload, "pending", 1, "unavailable", now, now, nil))
+
+	messages, err := store.GetDue(t.Context(), now, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 1) {
+		assert.JSONEq(t, `{"user_id":1}`, string(messages[0].Payload))
+		assert.Equal(t, "unavailable", messages[0].LastError)
+		assert.Nil(t, messages[0].DeliveredAt)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLOutboxStore_MarkDelivered(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLOutboxStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	query := regexp.QuoteMeta(`UPDATE outbox SET status = ?, attempts = attempts + 1, last_error = NULL, delivered_at = ? WHERE message_id = ?`)
+	deliveredAt := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(models.OutboxStatusDelivered, deliveredAt, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, store.MarkDelivered(t.Context(), 7, deliveredAt))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(models.OutboxStatusDelivered, deliveredAt, 8).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Equal(t, data.ErrNotFound, store.MarkDelivered(t.Context(), 8, deliveredAt))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
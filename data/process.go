@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 
@@ -10,10 +11,10 @@ import (
 
 // ProcessStore defines the interface for Process data operations.
 type ProcessStore interface {
-	Create(process *models.Process) (*models.Process, error)
-	GetByID(id int) (*models.Process, error)
-	Update(process *models.Process) error
-	Delete(id int) error
+	Create(ctx context.Context, process *models.Process) (*models.Process, error)
+	GetByID(ctx context.Context, id int) (*models.Process, error)
+	Update(ctx context.Context, process *models.Process) error
+	Delete(ctx context.Context, id int) error
 }
 
 // SQLProcessStore implements ProcessStore using database/sql.
@@ -28,26 +29,21 @@ func NewSQLProcessStore(db *sql.DB) *SQLProcessStore {
 
 // Creates a new process. Returns the new newly created process.
 // Warning: Does not return the correct created_at timestamp
-func (s *SQLProcessStore) Create(process *models.Process) (*models.Process, error) {
-	query := `INSERT INTO processes (status) VALUES (?)`
-	result, err := s.db.Exec(query, process.Status)
-	if err != nil {
+func (s *SQLProcessStore) Create(ctx context.Context, process *models.Process) (*models.Process, error) {
+	query := `INSERT INTO processes (status) VALUES (?) RETURNING process_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, process.Status).Scan(&id); err != nil {
 		logger.GetGlobalLogger().Errorf("Error creating process: %v", err)
 		return nil, err
 	}
-	id, err := result.LastInsertId()
-	if err != nil {
-		logger.GetGlobalLogger().Errorf("Error getting last insert ID: %v", err)
-		return nil, err
-	}
-	process.ProcessId = int(id)
+	process.ProcessId = id
 	return process, nil
 }
 
 // GetByID fetches a process by ID from the database.
-func (s *SQLProcessStore) GetByID(id int) (*models.Process, error) {
+func (s *SQLProcessStore) GetByID(ctx context.Context, id int) (*models.Process, error) {
 	query := `SELECT process_id, status, created_at FROM processes WHERE process_id = ?`
-	row := s.db.QueryRow(query, id)
+	row := s.db.QueryRowContext(ctx, query, id)
 	process := &models.Process{}
 	err := row.Scan(&process.ProcessId, &process.Status, &process.CreatedAt)
 	if err != nil {
@@ -63,9 +59,9 @@ func (s *SQLProcessStore) GetByID(id int) (*models.Process, error) {
 }
 
 // Update updates an existing process in the database.
-func (s *SQLProcessStore) Update(process *models.Process) error {
+func (s *SQLProcessStore) Update(ctx context.Context, process *models.Process) error {
 	query := `UPDATE processes SET status = ? WHERE process_id = ?`
-	result, err := s.db.Exec(query, process.Status, process.ProcessId)
+	result, err := s.db.ExecContext(ctx, query, process.Status, process.ProcessId)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error updating process: %v", err)
 		return err
@@ -83,9 +79,9 @@ func (s *SQLProcessStore) Update(process *models.Process) error {
 }
 
 // Delete deletes a process by ID from the database.
-func (s *SQLProcessStore) Delete(id int) error {
+func (s *SQLProcessStore) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM processes WHERE process_id = ?`
-	result, err := s.db.Exec(query, id)
+	result, err := s.db.ExecContext(ctx, query, id)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error deleting process: %v", err)
 		return err
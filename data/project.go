@@ -0,0 +1,226 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+
+	"kitadoc-backend/models"
+)
+
+// ProjectStore defines the interface for Project data operations.
+type ProjectStore interface {
+	Create(ctx context.Context, project *models.Project) (int, error)
+	GetByID(ctx context.Context, id int) (*models.Project, error)
+	// Update updates a project and replaces its groups and goals.
+	Update(ctx context.Context, project *models.Project) error
+	// Delete deletes a project, the documentation entries referring to it are kept without a project.
+	Delete(ctx context.Context, id int) error
+	// GetAll fetches all projects with their groups and goals, the latest first.
+	GetAll(ctx context.Context) ([]models.Project, error)
+}
+
+// SQLProjectStore implements ProjectStore using database/sql.
+type SQLProjectStore struct {
+	db *sql.DB
+}
+
+// NewSQLProjectStore creates a new SQLProjectStore.
+func NewSQLProjectStore(db *sql.DB) *SQLProjectStore {
+	return &SQLProjectStore{db: db}
+}
+
+const projectColumns = `project_id, title, description, start_date, end_date, created_at, updated_at`
+
+// Create inserts a new project with its groups and goals into the database.
+func (s *SQLProjectStore) Create(ctx context.Context, project *models.Project) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `INSERT INTO projects (title, description, start_date, end_date) VALUES (?, ?, ?, ?) RETURNING project_id`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, project.Title, project.Description, project.StartDate, project.EndDate).Scan(&id); err != nil {
+		return 0, err
+	}
+	if err := insertProjectMembers(ctx, tx, id, project); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a project with its groups and goals by ID from the database.
+func (s *SQLProjectStore) GetByID(ctx context.Context, id int) (*models.Project, error) {
+	projects, err := s.query(ctx, `SELECT `+projectColumns+` FROM projects WHERE project_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return nil, ErrNotFound
+	}
+	return &projects[0], nil
+}
+
+// Update updates an existing project in the database. Its goals get new IDs.
+func (s *SQLProjectStore) Update(ctx context.Context, project *models.Project) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `UPDATE projects SET title = ?, description = ?, start_date = ?, end_date = ?, updated_at = CURRENT_TIMESTAMP WHERE project_id = ?`
+	result, err := tx.ExecContext(ctx, query, project.Title, project.Description, project.StartDate, project.EndDate, project.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM project_groups WHERE project_id = ?`, project.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM project_goals WHERE project_id = ?`, project.ID); err != nil {
+		return err
+	}
+	if err := insertProjectMembers(ctx, tx, project.ID, project); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Delete deletes a project by ID from the database and unlinks its documentation entries, which have no foreign
+// key on the project.
+func (s *SQLProjectStore) Delete(ctx context.Context, id int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM projects WHERE project_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE documentation_entries SET project_id = NULL WHERE project_id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetAll fetches all projects from the database ordered by the start of their period, the latest first.
+func (s *SQLProjectStore) GetAll(ctx context.Context) ([]models.Project, error) {
+	return s.query(ctx, `SELECT `+projectColumns+` FROM projects ORDER BY start_date DESC, project_id DESC`)
+}
+
+func insertProjectMembers(ctx context.Context, tx *sql.Tx, projectID int, project *models.Project) error {
+	for _, groupID := range project.GroupIDs {
+		// Groups listed twice are stored once.
+		if _, err := tx.ExecContext(ctx, `INSERT INTO project_groups (project_id, group_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, projectID, groupID); err != nil {
+			return projectConstraintError(err)
+		}
+	}
+	for _, goal := range project.Goals {
+		query := `INSERT INTO project_goals (project_id, category_id, description) VALUES (?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, query, projectID, goal.CategoryID, goal.Description); err != nil {
+			return projectConstraintError(err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLProjectStore) query(ctx context.Context, query string, args ...any) ([]models.Project, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	projects := []models.Project{}
+	for rows.Next() {
+		project := models.Project{GroupIDs: []int{}, Goals: []models.ProjectGoal{}}
+		if err := rows.Scan(&project.ID, &project.Title, &project.Description, &project.StartDate, &project.EndDate,
+			&project.CreatedAt, &project.UpdatedAt); err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadMembers(ctx, projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// loadMembers fills in the groups and goals of the given projects.
+func (s *SQLProjectStore) loadMembers(ctx context.Context, projects []models.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+	byID := make(map[int]*models.Project, len(projects))
+	for i := range projects {
+		byID[projects[i].ID] = &projects[i]
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT project_id, group_id FROM project_groups ORDER BY group_id`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var projectID, groupID int
+		if err := rows.Scan(&projectID, &groupID); err != nil {
+			rows.Close() //nolint:errcheck
+			return err
+		}
+		if project, ok := byID[projectID]; ok {
+			project.GroupIDs = append(project.GroupIDs, groupID)
+		}
+	}
+	err = rows.Err()
+	rows.Close() //nolint:errcheck
+	if err != nil {
+		return err
+	}
+
+	rows, err = s.db.QueryContext(ctx, `SELECT project_id, goal_id, category_id, description FROM project_goals ORDER BY goal_id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close() //nolint:errcheck
+	for rows.Next() {
+		var projectID int
+		var goal models.ProjectGoal
+		if err := rows.Scan(&projectID, &goal.ID, &goal.CategoryID, &goal.Description); err != nil {
+			return err
+		}
+		if project, ok := byID[projectID]; ok {
+			project.Goals = append(project.Goals, goal)
+		}
+	}
+	return rows.Err()
+}
+
+func projectConstraintError(err error) error {
+	if isForeignKeyError(err) {
+		return ErrForeignKeyConstraint
+	}
+	return err
+}
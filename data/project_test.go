@@ -0,0 +1,94 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLProjectStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	require.NoError(t, err)
+	teacherID, err := dal.Teachers.Create(t.Context(), &models.Teacher{FirstName: "Maria", LastName: "Schmidt", Username: "maria"})
+	require.NoError(t, err)
+	motorikID, err := dal.Categories.Create(t.Context(), &models.Category{Name: "Motorik"})
+	require.NoError(t, err)
+	spracheID, err := dal.Categories.Create(t.Context(), &models.Category{Name: "Sprache"})
+	require.NoError(t, err)
+	sonneID, err := dal.Groups.Create(t.Context(), &models.Group{Name: "Sonnengruppe", Capacity: 20})
+	require.NoError(t, err)
+	mondID, err := dal.Groups.Create(t.Context(), &models.Group{Name: "Mondgruppe", Capacity: 20})
+	require.NoError(t, err)
+
+	store := dal.Projects
+	project := &models.Project{
+		Title:     "Herbstwoche",
+		StartDate: models.NewDate(2024, time.October, 7),
+		EndDate:   models.NewDate(2024, time.October, 11),
+		GroupIDs:  []int{sonneID, sonneID},
+		Goals:     []models.ProjectGoal{{CategoryID: motorikID, Description: "Sammelt Blätter beim Waldspaziergang"}},
+	}
+	projectID, err := store.Create(t.Context(), project)
+	require.NoError(t, err)
+	_, err = store.Create(t.Context(), &models.Project{Title: "Ohne Gruppe", StartDate: project.StartDate, EndDate: project.EndDate, GroupIDs: []int{999}})
+	assert.ErrorIs(t, err, data.ErrForeignKeyConstraint)
+
+	got, err := store.GetByID(t.Context(), projectID)
+	require.NoError(t, err)
+	assert.Equal(t, "Herbstwoche", got.Title)
+	assert.Nil(t, got.Description)
+	assert.Equal(t, []int{sonneID}, got.GroupIDs, "groups listed twice are stored once")
+	require.Len(t, got.Goals, 1)
+	assert.Equal(t, motorikID, got.Goals[0].CategoryID)
+
+	description := "Wir entdecken den Herbst."
+	got.Description = &description
+	got.GroupIDs = []int{mondID, sonneID}
+	got.Goals = []models.ProjectGoal{{CategoryID: spracheID, Description: "Benennt die Farben der Blätter"}}
+	require.NoError(t, store.Update(t.Context(), got))
+	updated, err := store.GetByID(t.Context(), projectID)
+	require.NoError(t, err)
+	assert.Equal(t, description, *updated.Description)
+	assert.ElementsMatch(t, []int{sonneID, mondID}, updated.GroupIDs)
+	require.Len(t, updated.Goals, 1)
+	assert.Equal(t, spracheID, updated.Goals[0].CategoryID)
+	assert.ErrorIs(t, store.Update(t.Context(), &models.Project{ID: 999, Title: "x", StartDate: project.StartDate, EndDate: project.EndDate}), data.ErrNotFound)
+
+	laterID, err := store.Create(t.Context(), &models.Project{Title: "Weihnachten", StartDate: models.NewDate(2024, time.December, 2), EndDate: models.NewDate(2024, time.December, 20)})
+	require.NoError(t, err)
+	projects, err := store.GetAll(t.Context())
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+	assert.Equal(t, laterID, projects[0].ID, "the latest project comes first")
+	assert.Empty(t, projects[0].Goals)
+
+	entryID, err := dal.DocumentationEntries.Create(t.Context(), &models.DocumentationEntry{ChildID: childID, TeacherID: teacherID, CategoryID: spracheID,
+		ObservationDate: models.NewDate(2024, time.October, 8), ObservationDescription: "Zählt die Farben der Blätter auf.", ProjectID: &projectID})
+	require.NoError(t, err)
+	entries, err := dal.DocumentationEntries.List(t.Context(), models.ListQuery{}.Where("project_id", models.OperatorEqual, projectID))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, projectID, *entries[0].ProjectID)
+
+	// Deleting the project keeps its entries
+	require.NoError(t, store.Delete(t.Context(), projectID))
+	assert.ErrorIs(t, store.Delete(t.Context(), projectID), data.ErrNotFound)
+	_, err = store.GetByID(t.Context(), projectID)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+	entry, err := dal.DocumentationEntries.GetByID(t.Context(), entryID)
+	require.NoError(t, err)
+	assert.Nil(t, entry.ProjectID)
+}
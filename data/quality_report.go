@@ -0,0 +1,87 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// QualityReportStore defines the interface for the archive of monthly quality reports.
+type QualityReportStore interface {
+	// Create archives the report of a month, ErrConflict if the month has been archived already.
+	Create(ctx context.Context, report *models.ArchivedQualityReport) error
+	// Get fetches the archived report of a month with its content.
+	Get(ctx context.Context, month string) (*models.ArchivedQualityReport, error)
+	// GetAll fetches the archived reports without their content, newest month first.
+	GetAll(ctx context.Context) ([]models.ArchivedQualityReport, error)
+}
+
+// SQLQualityReportStore implements QualityReportStore using database/sql.
+type SQLQualityReportStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLQualityReportStore creates a new SQLQualityReportStore.
+func NewSQLQualityReportStore(db *sql.DB, encryptionKey []byte) *SQLQualityReportStore {
+	return &SQLQualityReportStore{db: db, encryptionKey: encryptionKey}
+}
+
+// Create archives the report of a month.
+func (s *SQLQualityReportStore) Create(ctx context.Context, report *models.ArchivedQualityReport) error {
+	content, err := Encrypt(string(report.Content), s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt quality report content: %w", err)
+	}
+	query := `INSERT INTO quality_reports (month, document_id, generated_at, content, content_size) VALUES (?, ?, ?, ?, ?)`
+	if _, err := s.db.ExecContext(ctx, query, report.Month, report.DocumentID, report.GeneratedAt, content, len(report.Content)); err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	report.SizeBytes = len(report.Content)
+	return nil
+}
+
+// Get fetches the archived report of a month with its content.
+func (s *SQLQualityReportStore) Get(ctx context.Context, month string) (*models.ArchivedQualityReport, error) {
+	query := `SELECT month, document_id, generated_at, content_size, content FROM quality_reports WHERE month = ?`
+	report := &models.ArchivedQualityReport{}
+	var content string
+	err := s.db.QueryRowContext(ctx, query, month).Scan(&report.Month, &report.DocumentID, &report.GeneratedAt, &report.SizeBytes, &content)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	decrypted, err := Decrypt(content, s.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt quality report content: %w", err)
+	}
+	report.Content = []byte(decrypted)
+	return report, nil
+}
+
+// GetAll fetches the archived reports without their content, newest month first.
+func (s *SQLQualityReportStore) GetAll(ctx context.Context) ([]models.ArchivedQualityReport, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT month, document_id, generated_at, content_size FROM quality_reports ORDER BY month DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	reports := []models.ArchivedQualityReport{}
+	for rows.Next() {
+		var report models.ArchivedQualityReport
+		if err := rows.Scan(&report.Month, &report.DocumentID, &report.GeneratedAt, &report.SizeBytes); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
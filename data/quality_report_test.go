@@ -0,0 +1,75 @@
+package data_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLQualityReportStore_CreateAndGet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	store := data.NewSQLQualityReportStore(db, key)
+	generatedAt := time.Date(2025, time.April, 10, 6, 0, 0, 0, time.UTC)
+	report := &models.ArchivedQualityReport{Month: "2025-03", DocumentID: "doc", GeneratedAt: generatedAt, Content: []byte("%PDF-1.3")}
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO quality_reports (month, document_id, generated_at, content, content_size) VALUES (?, ?, ?, ?, ?)`)).
+		WithArgs("2025-03", "doc", generatedAt, sqlmock.AnyArg(), 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = store.Create(t.Context(), report)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, report.SizeBytes)
+
+	content, err := data.Encrypt("%PDF-1.3", key)
+	assert.NoError(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT month, document_id, generated_at, content_size, content FROM quality_reports WHERE month = ?`)).
+		WithArgs("2025-03").
+		WillReturnRows(sqlmock.NewRows([]string{"month", "document_id", "generated_at", "content_size", "content"}).
+			AddRow("2025-03", "doc", generatedAt, 8, content))
+	archived, err := store.Get(t.Context(), "2025-03")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("%PDF-1.3"), archived.Content)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT month, document_id, generated_at, content_size, content FROM quality_reports WHERE month = ?`)).
+		WithArgs("2025-02").
+		WillReturnRows(sqlmock.NewRows([]string{"month", "document_id", "generated_at", "content_size", "content"}))
+	_, err = store.Get(t.Context(), "2025-02")
+	assert.ErrorIs(t, err, data.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLQualityReportStore_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLQualityReportStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	generatedAt := time.Date(2025, time.April, 10, 6, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT month, document_id, generated_at, content_size FROM quality_reports ORDER BY month DESC`)).
+		WillReturnRows(sqlmock.NewRows([]string{"month", "document_id", "generated_at", "content_size"}).
+			AddRow("2025-03", "doc-3", generatedAt, 1200).
+			AddRow("2025-02", "doc-2", generatedAt.AddDate(0, -1, 0), 1100))
+
+	reports, err := store.GetAll(t.Context())
+	assert.NoError(t, err)
+	if assert.Len(t, reports, 2) {
+		assert.Equal(t, "2025-03", reports[0].Month)
+		assert.Equal(t, 1100, reports[1].SizeBytes)
+		assert.Nil(t, reports[0].Content)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,133 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// QuarantinedUploadStore defines the interface for the uploads the virus scanner rejected.
+type QuarantinedUploadStore interface {
+	// Create inserts a rejected upload with its content.
+	Create(ctx context.Context, upload *models.QuarantinedUpload) (int, error)
+	// GetAll fetches the rejected uploads without their content, the latest first.
+	GetAll(ctx context.Context) ([]models.QuarantinedUpload, error)
+	// GetWithContent fetches a rejected upload with its content.
+	GetWithContent(ctx context.Context, id int) (*models.QuarantinedUpload, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// SQLQuarantinedUploadStore implements QuarantinedUploadStore using database/sql.
+type SQLQuarantinedUploadStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLQuarantinedUploadStore creates a new SQLQuarantinedUploadStore.
+func NewSQLQuarantinedUploadStore(db *sql.DB, encryptionKey []byte) *SQLQuarantinedUploadStore {
+	return &SQLQuarantinedUploadStore{db: db, encryptionKey: encryptionKey}
+}
+
+const quarantinedUploadColumns = `quarantine_id, kind, file_name, signature, size_bytes, uploaded_by_user_id, quarantined_at`
+
+// Create inserts a new rejected upload into the database. The file name and the content are encrypted.
+func (s *SQLQuarantinedUploadStore) Create(ctx context.Context, upload *models.QuarantinedUpload) (int, error) {
+	fileName, err := Encrypt(upload.FileName, s.encryptionKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt quarantined file name: %w", err)
+	}
+	content, err := Encrypt(string(upload.Content), s.encryptionKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt quarantined file: %w", err)
+	}
+
+	query := `INSERT INTO quarantined_uploads (kind, file_name, signature, content, size_bytes, uploaded_by_user_id, quarantined_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING quarantine_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, upload.Kind, fileName, upload.Signature, content, len(upload.Content), upload.UploadedByUserID,
+		upload.QuarantinedAt.UTC()).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetAll fetches all rejected uploads from the database.
+func (s *SQLQuarantinedUploadStore) GetAll(ctx context.Context) ([]models.QuarantinedUpload, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+quarantinedUploadColumns+` FROM quarantined_uploads ORDER BY quarantined_at DESC, quarantine_id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	uploads := []models.QuarantinedUpload{}
+	for rows.Next() {
+		upload, err := s.scanUpload(rows)
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, *upload)
+	}
+	return uploads, rows.Err()
+}
+
+// GetWithContent fetches a rejected upload by ID with its decrypted content.
+func (s *SQLQuarantinedUploadStore) GetWithContent(ctx context.Context, id int) (*models.QuarantinedUpload, error) {
+	var content string
+	row := s.db.QueryRowContext(ctx, `SELECT `+quarantinedUploadColumns+`, content FROM quarantined_uploads WHERE quarantine_id = ?`, id)
+	upload, err := s.scanUpload(row, &content)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	decrypted, err := Decrypt(content, s.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt quarantined file: %w", err)
+	}
+	upload.Content = []byte(decrypted)
+	return upload, nil
+}
+
+// Delete deletes a rejected upload by ID from the database.
+func (s *SQLQuarantinedUploadStore) Delete(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM quarantined_uploads WHERE quarantine_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// scanUpload scans the quarantinedUploadColumns of a row followed by the extra destinations and decrypts the
+// file name.
+func (s *SQLQuarantinedUploadStore) scanUpload(row interface{ Scan(...any) error }, extra ...any) (*models.QuarantinedUpload, error) {
+	var upload models.QuarantinedUpload
+	var fileName string
+	var uploadedBy sql.NullInt64
+	destinations := append([]any{&upload.ID, &upload.Kind, &fileName, &upload.Signature, &upload.SizeBytes, &uploadedBy,
+		&upload.QuarantinedAt}, extra...)
+	if err := row.Scan(destinations...); err != nil {
+		return nil, err
+	}
+	decrypted, err := Decrypt(fileName, s.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt quarantined file name: %w", err)
+	}
+	upload.FileName = decrypted
+	if uploadedBy.Valid {
+		userID := int(uploadedBy.Int64)
+		upload.UploadedByUserID = &userID
+	}
+	return &upload, nil
+}
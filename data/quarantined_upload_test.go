@@ -0,0 +1,55 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLQuarantinedUploadStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	store := data.NewSQLQuarantinedUploadStore(db, []byte("0123456789abcdef0123456789abcdef"))
+
+	quarantinedAt := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	firstID, err := store.Create(t.Context(), &models.QuarantinedUpload{Kind: models.UploadKindPhoto, FileName: "photo.jpg", Signature: "Eicar-Signature",
+		Content: []byte("malware"), QuarantinedAt: quarantinedAt})
+	require.NoError(t, err)
+	secondID, err := store.Create(t.Context(), &models.QuarantinedUpload{Kind: models.UploadKindDocumentLogo, FileName: "logo.png", Signature: "Win.Trojan",
+		Content: []byte("trojan"), QuarantinedAt: quarantinedAt.Add(time.Hour)})
+	require.NoError(t, err)
+
+	// The file name and the content are encrypted at rest
+	var fileName, content string
+	require.NoError(t, db.QueryRow(`SELECT file_name, content FROM quarantined_uploads WHERE quarantine_id = ?`, firstID).Scan(&fileName, &content))
+	assert.NotEqual(t, "photo.jpg", fileName)
+	assert.NotContains(t, content, "malware")
+
+	uploads, err := store.GetAll(t.Context())
+	require.NoError(t, err)
+	require.Len(t, uploads, 2)
+	assert.Equal(t, secondID, uploads[0].ID, "the latest upload comes first")
+	assert.Equal(t, "photo.jpg", uploads[1].FileName)
+	assert.Equal(t, 7, uploads[1].SizeBytes)
+	assert.Nil(t, uploads[1].Content)
+	assert.Nil(t, uploads[1].UploadedByUserID)
+
+	upload, err := store.GetWithContent(t.Context(), firstID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("malware"), upload.Content)
+	assert.Equal(t, "Eicar-Signature", upload.Signature)
+
+	require.NoError(t, store.Delete(t.Context(), firstID))
+	assert.ErrorIs(t, store.Delete(t.Context(), firstID), data.ErrNotFound)
+	_, err = store.GetWithContent(t.Context(), firstID)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+}
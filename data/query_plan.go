@@ -0,0 +1,152 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// QueryPlanStore reports how the database executes the hot queries of the application.
+type QueryPlanStore interface {
+	ExplainHotQueries(ctx context.Context) ([]models.QueryPlan, error)
+}
+
+// SQLQueryPlanStore implements QueryPlanStore using database/sql.
+type SQLQueryPlanStore struct {
+	db     *sql.DB
+	driver Driver
+}
+
+// NewSQLQueryPlanStore creates a new SQLQueryPlanStore, the plans are explained in the syntax of the driver.
+func NewSQLQueryPlanStore(db *sql.DB, driver Driver) *SQLQueryPlanStore {
+	return &SQLQueryPlanStore{db: db, driver: driver}
+}
+
+// hotQuery is a statement the stores run often, with arguments to plan it with.
+type hotQuery struct {
+	name  string
+	query string
+	args  []any
+}
+
+// hotQueries builds the statements exactly like the stores do, so that the plans match what runs in production.
+func hotQueries() ([]hotQuery, error) {
+	queries := []hotQuery{
+		{name: "last_observation_dates", query: lastObservationDatesQuery},
+		{name: "audit_trail_of_entity", query: auditTrailQuery, args: []any{"documentation_entry", 0}},
+		{name: "audit_log_of_actor", query: actorAuditLogQuery, args: []any{0}},
+		{name: "audit_log_of_action", query: actionAuditLogQuery, args: []any{models.AuditActionSendReminder, time.Time{}}},
+		{name: "pending_terms_of_user", query: pendingTermsQuery, args: []any{0}},
+		{name: "health_checks_in_range", query: healthChecksInRangeQuery, args: []any{time.Time{}, time.Time{}}},
+	}
+	for _, list := range []struct {
+		name        string
+		base        string
+		columns     listColumns
+		query       models.ListQuery
+		defaultSort models.SortField
+	}{
+		{
+			name:        "documentation_of_child",
+			base:        documentationEntrySelect,
+			columns:     documentationEntryListColumns,
+			query:       models.ListQuery{}.Where("child_id", models.OperatorEqual, 0),
+			defaultSort: models.SortField{Field: "observation_date", Descending: true},
+		},
+		{
+			name:        "assignment_history_of_child",
+			base:        assignmentSelect,
+			columns:     assignmentListColumns,
+			query:       models.ListQuery{}.Where("child_id", models.OperatorEqual, 0),
+			defaultSort: models.SortField{Field: "start_date", Descending: true},
+		},
+		{
+			name:        "open_assignments_of_child",
+			base:        assignmentSelect,
+			columns:     assignmentListColumns,
+			query:       models.ListQuery{}.Where("child_id", models.OperatorEqual, 0).Where("end_date", models.OperatorIsNull, nil),
+			defaultSort: models.SortField{Field: "start_date", Descending: true},
+		},
+	} {
+		query, args, err := buildListQuery(list.base, list.columns, list.query, list.defaultSort)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, hotQuery{name: list.name, query: query, args: args})
+	}
+	return queries, nil
+}
+
+// ExplainHotQueries fetches the query plans of the hot queries.
+func (s *SQLQueryPlanStore) ExplainHotQueries(ctx context.Context) ([]models.QueryPlan, error) {
+	queries, err := hotQueries()
+	if err != nil {
+		return nil, err
+	}
+	plans := make([]models.QueryPlan, 0, len(queries))
+	for _, hot := range queries {
+		plan, err := s.explain(ctx, hot)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, *plan)
+	}
+	return plans, nil
+}
+
+func (s *SQLQueryPlanStore) explain(ctx context.Context, hot hotQuery) (*models.QueryPlan, error) {
+	if s.driver == DriverPostgres {
+		return s.explainPostgres(ctx, hot)
+	}
+	rows, err := s.db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+hot.query, hot.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	plan := &models.QueryPlan{Name: hot.name, Query: strings.Join(strings.Fields(hot.query), " "), Steps: []string{}}
+	depths := map[int]int{}
+	for rows.Next() {
+		var id, parent, unused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+			return nil, err
+		}
+		depth := 0
+		if parentDepth, ok := depths[parent]; ok {
+			depth = parentDepth + 1
+		}
+		depths[id] = depth
+		plan.Steps = append(plan.Steps, strings.Repeat("  ", depth)+detail)
+		// Scans through an index read the rows in index order, only plain scans read the whole table.
+		if strings.HasPrefix(detail, "SCAN ") && !strings.Contains(detail, " INDEX ") {
+			plan.FullScan = true
+		}
+	}
+	return plan, rows.Err()
+}
+
+// explainPostgres fetches a plan from PostgreSQL, whose EXPLAIN indents the steps itself.
+func (s *SQLQueryPlanStore) explainPostgres(ctx context.Context, hot hotQuery) (*models.QueryPlan, error) {
+	rows, err := s.db.QueryContext(ctx, "EXPLAIN "+hot.query, hot.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	plan := &models.QueryPlan{Name: hot.name, Query: strings.Join(strings.Fields(hot.query), " "), Steps: []string{}}
+	for rows.Next() {
+		var step string
+		if err := rows.Scan(&step); err != nil {
+			return nil, err
+		}
+		plan.Steps = append(plan.Steps, step)
+		if strings.Contains(step, "Seq Scan on ") {
+			plan.FullScan = true
+		}
+	}
+	return plan, rows.Err()
+}
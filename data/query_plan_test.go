@@ -0,0 +1,35 @@
+package data_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLQueryPlanStore_ExplainHotQueries(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+
+	plans, err := data.NewSQLQueryPlanStore(db, data.DriverSQLite).ExplainHotQueries(t.Context())
+	require.NoError(t, err)
+
+	indexes := map[string]string{}
+	for _, plan := range plans {
+		assert.False(t, plan.FullScan, "%s scans a whole table:\n%s", plan.Name, strings.Join(plan.Steps, "\n"))
+		indexes[plan.Name] = strings.Join(plan.Steps, "\n")
+	}
+	assert.Contains(t, indexes["documentation_of_child"], "idx_documentation_child_date")
+	assert.Contains(t, indexes["open_assignments_of_child"], "idx_assignments_child_end")
+	assert.Contains(t, indexes["audit_log_of_actor"], "idx_audit_log_actor")
+	assert.Contains(t, indexes["audit_log_of_action"], "idx_audit_log_action")
+	assert.Contains(t, indexes["pending_terms_of_user"], "sqlite_autoindex_terms_acceptances_1")
+	assert.Contains(t, indexes["health_checks_in_range"], "idx_health_checks_checked_at")
+}
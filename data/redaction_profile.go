@@ -0,0 +1,163 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// RedactionProfileStore defines the interface for RedactionProfile data operations.
+type RedactionProfileStore interface {
+	Create(ctx context.Context, profile *models.RedactionProfile) (int, error)
+	GetByID(ctx context.Context, id int) (*models.RedactionProfile, error)
+	Update(ctx context.Context, profile *models.RedactionProfile) error
+	Delete(ctx context.Context, id int) error
+	GetAll(ctx context.Context) ([]models.RedactionProfile, error)
+}
+
+// SQLRedactionProfileStore implements RedactionProfileStore using database/sql.
+type SQLRedactionProfileStore struct {
+	db *sql.DB
+}
+
+// NewSQLRedactionProfileStore creates a new SQLRedactionProfileStore.
+func NewSQLRedactionProfileStore(db *sql.DB) *SQLRedactionProfileStore {
+	return &SQLRedactionProfileStore{db: db}
+}
+
+const redactionProfileColumns = `redaction_profile_id, profile_name, description, hide_child_last_name, hide_birthdate, hide_admission_date, hide_school_enrollment, hide_teacher_names, hide_kita_contact, hide_observation_dates, hide_support_providers, excluded_category_ids, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRedactionProfile(row rowScanner) (*models.RedactionProfile, error) {
+	profile := &models.RedactionProfile{}
+	var excludedCategoryIDs string
+	err := row.Scan(&profile.ID, &profile.Name, &profile.Description, &profile.HideChildLastName, &profile.HideBirthdate, &profile.HideAdmissionDate,
+		&profile.HideSchoolEnrollment, &profile.HideTeacherNames, &profile.HideKitaContact, &profile.HideObservationDates, &profile.HideSupportProviders, &excludedCategoryIDs,
+		&profile.CreatedAt, &profile.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(excludedCategoryIDs), &profile.ExcludedCategoryIDs); err != nil {
+		return nil, fmt.Errorf("failed to decode excluded category IDs: %w", err)
+	}
+	return profile, nil
+}
+
+func encodeCategoryIDs(categoryIDs []int) (string, error) {
+	if categoryIDs == nil {
+		categoryIDs = []int{}
+	}
+	encoded, err := json.Marshal(categoryIDs)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode excluded category IDs: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// Create inserts a new redaction profile into the database.
+func (s *SQLRedactionProfileStore) Create(ctx context.Context, profile *models.RedactionProfile) (int, error) {
+	excludedCategoryIDs, err := encodeCategoryIDs(profile.ExcludedCategoryIDs)
+	if err != nil {
+		return 0, err
+	}
+	query := `INSERT INTO redaction_profiles (profile_name, description, hide_child_last_name, hide_birthdate, hide_admission_date, hide_school_enrollment, hide_teacher_names, hide_kita_contact, hide_observation_dates, hide_support_providers, excluded_category_ids)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING redaction_profile_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, profile.Name, profile.Description, profile.HideChildLastName, profile.HideBirthdate, profile.HideAdmissionDate,
+		profile.HideSchoolEnrollment, profile.HideTeacherNames, profile.HideKitaContact, profile.HideObservationDates, profile.HideSupportProviders, excludedCategoryIDs).Scan(&id); err != nil {
+		if isUniqueConstraintError(err) {
+			return 0, ErrConflict
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a redaction profile by ID from the database.
+func (s *SQLRedactionProfileStore) GetByID(ctx context.Context, id int) (*models.RedactionProfile, error) {
+	query := `SELECT ` + redactionProfileColumns + ` FROM redaction_profiles WHERE redaction_profile_id = ?`
+	profile, err := scanRedactionProfile(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Update updates an existing redaction profile in the database.
+func (s *SQLRedactionProfileStore) Update(ctx context.Context, profile *models.RedactionProfile) error {
+	excludedCategoryIDs, err := encodeCategoryIDs(profile.ExcludedCategoryIDs)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE redaction_profiles SET profile_name = ?, description = ?, hide_child_last_name = ?, hide_birthdate = ?, hide_admission_date = ?, hide_school_enrollment = ?,
+		hide_teacher_names = ?, hide_kita_contact = ?, hide_observation_dates = ?, hide_support_providers = ?, excluded_category_ids = ? WHERE redaction_profile_id = ?`
+	result, err := s.db.ExecContext(ctx, query, profile.Name, profile.Description, profile.HideChildLastName, profile.HideBirthdate, profile.HideAdmissionDate,
+		profile.HideSchoolEnrollment, profile.HideTeacherNames, profile.HideKitaContact, profile.HideObservationDates, profile.HideSupportProviders, excludedCategoryIDs, profile.ID)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete deletes a redaction profile by ID from the database.
+func (s *SQLRedactionProfileStore) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM redaction_profiles WHERE redaction_profile_id = ?`
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetAll fetches all redaction profiles ordered by name.
+func (s *SQLRedactionProfileStore) GetAll(ctx context.Context) ([]models.RedactionProfile, error) {
+	query := `SELECT ` + redactionProfileColumns + ` FROM redaction_profiles ORDER BY profile_name, redaction_profile_id`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var profiles []models.RedactionProfile
+	for rows.Next() {
+		profile, err := scanRedactionProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, *profile)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,205 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// ReportShareLinkStore defines the interface for ReportShareLink data operations.
+type ReportShareLinkStore interface {
+	Create(ctx context.Context, link *models.ReportShareLink) (int, error)
+	GetByID(ctx context.Context, id int) (*models.ReportShareLink, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.ReportShareLink, error)
+	// GetForReport fetches the links of a report, latest first, revoked and expired ones included.
+	GetForReport(ctx context.Context, reportID int) ([]models.ReportShareLink, error)
+	// Revoke marks a link as revoked, it returns ErrNotFound if the link does not exist or is revoked already.
+	Revoke(ctx context.Context, id int, revokedAt time.Time) error
+	// RecordAccess logs an attempt to open a link, granted accesses are also counted on the link.
+	RecordAccess(ctx context.Context, access *models.ReportShareLinkAccess) error
+	// GetAccesses fetches the access log of a link, latest first.
+	GetAccesses(ctx context.Context, shareLinkID int) ([]models.ReportShareLinkAccess, error)
+	// CountFailedAccesses counts the attempts to open a link with a wrong access code.
+	CountFailedAccesses(ctx context.Context, shareLinkID int) (int, error)
+}
+
+// SQLReportShareLinkStore implements ReportShareLinkStore using database/sql.
+type SQLReportShareLinkStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLReportShareLinkStore creates a new SQLReportShareLinkStore.
+func NewSQLReportShareLinkStore(db *sql.DB, encryptionKey []byte) *SQLReportShareLinkStore {
+	return &SQLReportShareLinkStore{db: db, encryptionKey: encryptionKey}
+}
+
+const reportShareLinkColumns = `share_link_id, report_id, token_hash, access_code_hash, recipient, created_by_user_id, created_at, expires_at, revoked_at, accesses, last_accessed_at`
+
+// Create inserts a new share link into the database.
+func (s *SQLReportShareLinkStore) Create(ctx context.Context, link *models.ReportShareLink) (int, error) {
+	recipient, err := Encrypt(link.Recipient, s.encryptionKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt share link recipient: %w", err)
+	}
+	query := `INSERT INTO report_share_links (report_id, token_hash, access_code_hash, recipient, created_by_user_id, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING share_link_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, link.ReportID, link.TokenHash, link.AccessCodeHash, recipient, link.CreatedByUserID, link.CreatedAt, link.ExpiresAt).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a share link by ID from the database.
+func (s *SQLReportShareLinkStore) GetByID(ctx context.Context, id int) (*models.ReportShareLink, error) {
+	return s.queryReportShareLink(ctx, `SELECT `+reportShareLinkColumns+` FROM report_share_links WHERE share_link_id = ?`, id)
+}
+
+// GetByTokenHash fetches the share link with the hash of a token from the database.
+func (s *SQLReportShareLinkStore) GetByTokenHash(ctx context.Context, tokenHash string) (*models.ReportShareLink, error) {
+	return s.queryReportShareLink(ctx, `SELECT `+reportShareLinkColumns+` FROM report_share_links WHERE token_hash = ?`, tokenHash)
+}
+
+// GetForReport fetches the share links of a report.
+func (s *SQLReportShareLinkStore) GetForReport(ctx context.Context, reportID int) ([]models.ReportShareLink, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+reportShareLinkColumns+` FROM report_share_links WHERE report_id = ? ORDER BY created_at DESC, share_link_id DESC`, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	links := []models.ReportShareLink{}
+	for rows.Next() {
+		link, err := s.scanReportShareLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// Revoke marks a share link as revoked.
+func (s *SQLReportShareLinkStore) Revoke(ctx context.Context, id int, revokedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE report_share_links SET revoked_at = ? WHERE share_link_id = ? AND revoked_at IS NULL`, revokedAt, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordAccess inserts an access into the access log of a share link.
+func (s *SQLReportShareLinkStore) RecordAccess(ctx context.Context, access *models.ReportShareLinkAccess) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var id int
+	if err := tx.QueryRowContext(ctx, `INSERT INTO report_share_link_accesses (share_link_id, accessed_at, outcome) VALUES (?, ?, ?) RETURNING access_id`,
+		access.ShareLinkID, access.AccessedAt, access.Outcome).Scan(&id); err != nil {
+		return err
+	}
+	if access.Outcome == models.ShareLinkAccessGranted {
+		_, err := tx.ExecContext(ctx, `UPDATE report_share_links SET accesses = accesses + 1, last_accessed_at = ? WHERE share_link_id = ?`,
+			access.AccessedAt, access.ShareLinkID)
+		if err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	access.ID = id
+	return nil
+}
+
+// GetAccesses fetches the access log of a share link.
+func (s *SQLReportShareLinkStore) GetAccesses(ctx context.Context, shareLinkID int) ([]models.ReportShareLinkAccess, error) {
+	query := `SELECT access_id, share_link_id, accessed_at, outcome FROM report_share_link_accesses WHERE share_link_id = ? ORDER BY accessed_at DESC, access_id DESC`
+	rows, err := s.db.QueryContext(ctx, query, shareLinkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	accesses := []models.ReportShareLinkAccess{}
+	for rows.Next() {
+		var access models.ReportShareLinkAccess
+		if err := rows.Scan(&access.ID, &access.ShareLinkID, &access.AccessedAt, &access.Outcome); err != nil {
+			return nil, err
+		}
+		accesses = append(accesses, access)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accesses, nil
+}
+
+// CountFailedAccesses counts the attempts with a wrong access code.
+func (s *SQLReportShareLinkStore) CountFailedAccesses(ctx context.Context, shareLinkID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM report_share_link_accesses WHERE share_link_id = ? AND outcome = ?`
+	if err := s.db.QueryRowContext(ctx, query, shareLinkID, models.ShareLinkAccessWrongCode).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *SQLReportShareLinkStore) queryReportShareLink(ctx context.Context, query string, args ...any) (*models.ReportShareLink, error) {
+	link, err := s.scanReportShareLink(s.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *SQLReportShareLinkStore) scanReportShareLink(row rowScanner) (*models.ReportShareLink, error) {
+	link := &models.ReportShareLink{}
+	var createdByUserID sql.NullInt64
+	var revokedAt, lastAccessedAt sql.NullTime
+	var recipient string
+	err := row.Scan(&link.ID, &link.ReportID, &link.TokenHash, &link.AccessCodeHash, &recipient, &createdByUserID,
+		&link.CreatedAt, &link.ExpiresAt, &revokedAt, &link.Accesses, &lastAccessedAt)
+	if err != nil {
+		return nil, err
+	}
+	link.Recipient, err = Decrypt(recipient, s.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt share link recipient: %w", err)
+	}
+	if createdByUserID.Valid {
+		id := int(createdByUserID.Int64)
+		link.CreatedByUserID = &id
+	}
+	if revokedAt.Valid {
+		link.RevokedAt = &revokedAt.Time
+	}
+	if lastAccessedAt.Valid {
+		link.LastAccessedAt = &lastAccessedAt.Time
+	}
+	return link, nil
+}
@@ -0,0 +1,83 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLReportShareLinkStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2020, time.March, 15)})
+	require.NoError(t, err)
+	report := &models.GeneratedReport{ChildID: childID, DocumentID: "DOC-1", GeneratedAt: time.Now(), FileName: "Anna_Müller.docx", Content: []byte("report")}
+	require.NoError(t, dal.DocumentationEntries.RecordReport(t.Context(), report))
+
+	store := data.NewSQLReportShareLinkStore(db, []byte("0123456789abcdef0123456789abcdef"))
+	now := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+	id, err := store.Create(t.Context(), &models.ReportShareLink{
+		ReportID:       report.ID,
+		Recipient:      "Frühförderstelle Musterstadt",
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(14 * 24 * time.Hour),
+		TokenHash:      "token-hash",
+		AccessCodeHash: "code-hash",
+	})
+	require.NoError(t, err)
+
+	var storedRecipient string
+	require.NoError(t, db.QueryRow(`SELECT recipient FROM report_share_links WHERE share_link_id = ?`, id).Scan(&storedRecipient))
+	assert.NotContains(t, storedRecipient, "Frühförderstelle", "the recipient must be stored encrypted")
+
+	link, err := store.GetByTokenHash(t.Context(), "token-hash")
+	require.NoError(t, err)
+	assert.Equal(t, id, link.ID)
+	assert.Equal(t, "Frühförderstelle Musterstadt", link.Recipient)
+	assert.Equal(t, "code-hash", link.AccessCodeHash)
+	assert.Nil(t, link.LastAccessedAt)
+	_, err = store.GetByTokenHash(t.Context(), "unknown")
+	assert.ErrorIs(t, err, data.ErrNotFound)
+
+	require.NoError(t, store.RecordAccess(t.Context(), &models.ReportShareLinkAccess{ShareLinkID: id, AccessedAt: now.Add(time.Hour), Outcome: models.ShareLinkAccessWrongCode}))
+	require.NoError(t, store.RecordAccess(t.Context(), &models.ReportShareLinkAccess{ShareLinkID: id, AccessedAt: now.Add(2 * time.Hour), Outcome: models.ShareLinkAccessGranted}))
+	failed, err := store.CountFailedAccesses(t.Context(), id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+	accesses, err := store.GetAccesses(t.Context(), id)
+	require.NoError(t, err)
+	require.Len(t, accesses, 2)
+	assert.Equal(t, models.ShareLinkAccessGranted, accesses[0].Outcome)
+
+	links, err := store.GetForReport(t.Context(), report.ID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, 1, links[0].Accesses)
+	require.NotNil(t, links[0].LastAccessedAt)
+	assert.True(t, links[0].LastAccessedAt.Equal(now.Add(2*time.Hour)))
+
+	require.NoError(t, store.Revoke(t.Context(), id, now.Add(3*time.Hour)))
+	assert.ErrorIs(t, store.Revoke(t.Context(), id, now.Add(4*time.Hour)), data.ErrNotFound)
+	revoked, err := store.GetByID(t.Context(), id)
+	require.NoError(t, err)
+	require.NotNil(t, revoked.RevokedAt)
+	assert.True(t, revoked.RevokedAt.Equal(now.Add(3*time.Hour)))
+	assert.False(t, revoked.IsActive(now))
+
+	// The links and their accesses go with the report
+	_, err = db.Exec(`DELETE FROM generated_reports WHERE report_id = ?`, report.ID)
+	require.NoError(t, err)
+	_, err = store.GetByID(t.Context(), id)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+}
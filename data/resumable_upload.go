@@ -0,0 +1,162 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// ResumableUploadStore defines the interface for the files uploaded in chunks.
+type ResumableUploadStore interface {
+	Create(ctx context.Context, upload *models.ResumableUpload) error
+	// Get fetches an upload with the number of bytes received so far.
+	Get(ctx context.Context, id string) (*models.ResumableUpload, error)
+	// AppendChunk stores the next chunk of an upload. It returns ErrConflict if offset is not the number of bytes
+	// received so far, e.g. because the chunk has already been stored before the connection dropped.
+	AppendChunk(ctx context.Context, id string, offset int, chunk []byte) error
+	// GetContent returns the chunks of an upload joined in their order.
+	GetContent(ctx context.Context, id string) ([]byte, error)
+	Delete(ctx context.Context, id string) error
+	// DeleteExpired deletes the uploads that expired before now with their chunks.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// SQLResumableUploadStore implements ResumableUploadStore using database/sql.
+type SQLResumableUploadStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLResumableUploadStore creates a new SQLResumableUploadStore.
+func NewSQLResumableUploadStore(db *sql.DB, encryptionKey []byte) *SQLResumableUploadStore {
+	return &SQLResumableUploadStore{db: db, encryptionKey: encryptionKey}
+}
+
+// Create inserts a new upload into the database. The file name is encrypted.
+func (s *SQLResumableUploadStore) Create(ctx context.Context, upload *models.ResumableUpload) error {
+	fileName, err := Encrypt(upload.FileName, s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt upload file name: %w", err)
+	}
+	query := `INSERT INTO resumable_uploads (upload_id, kind, file_name, content_type, size_bytes, sha256, created_by_user_id, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := s.db.ExecContext(ctx, query, upload.ID, upload.Kind, fileName, upload.ContentType, upload.SizeBytes, upload.SHA256,
+		upload.CreatedByUserID, upload.CreatedAt.UTC(), upload.ExpiresAt.UTC()); err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// Get fetches an upload by ID from the database.
+func (s *SQLResumableUploadStore) Get(ctx context.Context, id string) (*models.ResumableUpload, error) {
+	query := `SELECT u.upload_id, u.kind, u.file_name, u.content_type, u.size_bytes, u.sha256, u.created_by_user_id, u.created_at,
+			u.expires_at, (SELECT COALESCE(SUM(c.size_bytes), 0) FROM resumable_upload_chunks c WHERE c.upload_id = u.upload_id)
+		FROM resumable_uploads u WHERE u.upload_id = ?`
+	var upload models.ResumableUpload
+	var fileName string
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&upload.ID, &upload.Kind, &fileName, &upload.ContentType, &upload.SizeBytes, &upload.SHA256,
+		&upload.CreatedByUserID, &upload.CreatedAt, &upload.ExpiresAt, &upload.Offset)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	decrypted, err := Decrypt(fileName, s.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt upload file name: %w", err)
+	}
+	upload.FileName = decrypted
+	return &upload, nil
+}
+
+// AppendChunk stores an encrypted chunk at the offset. The offset is checked in the same transaction, so that a
+// chunk sent twice by a retrying client is not stored twice.
+func (s *SQLResumableUploadStore) AppendChunk(ctx context.Context, id string, offset int, chunk []byte) error {
+	content, err := Encrypt(string(chunk), s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt upload chunk: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var received int
+	query := `SELECT COALESCE(SUM(size_bytes), 0) FROM resumable_upload_chunks WHERE upload_id = ?`
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&received); err != nil {
+		return err
+	}
+	if received != offset {
+		return ErrConflict
+	}
+	query = `INSERT INTO resumable_upload_chunks (upload_id, chunk_offset, size_bytes, content) VALUES (?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, id, offset, len(chunk), content); err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetContent fetches and decrypts the chunks of an upload.
+func (s *SQLResumableUploadStore) GetContent(ctx context.Context, id string) ([]byte, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT content FROM resumable_upload_chunks WHERE upload_id = ? ORDER BY chunk_offset`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	content := []byte{}
+	for rows.Next() {
+		var chunk string
+		if err := rows.Scan(&chunk); err != nil {
+			return nil, err
+		}
+		decrypted, err := Decrypt(chunk, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt upload chunk: %w", err)
+		}
+		content = append(content, decrypted...)
+	}
+	return content, rows.Err()
+}
+
+// Delete deletes an upload by ID with its chunks from the database.
+func (s *SQLResumableUploadStore) Delete(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM resumable_uploads WHERE upload_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteExpired deletes the expired uploads from the database.
+func (s *SQLResumableUploadStore) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM resumable_uploads WHERE expires_at <= ?`, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rowsAffected), nil
+}
@@ -0,0 +1,63 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLResumableUploadStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	userID, err := dal.Users.Create(t.Context(), &models.User{Username: "mschmidt", PasswordHash: "hash", Role: "teacher"})
+	require.NoError(t, err)
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	store := dal.ResumableUploads
+
+	upload := &models.ResumableUpload{ID: "abc", Kind: models.UploadKindAudio, FileName: "Morgenkreis Anna.mp3", ContentType: "audio/mpeg",
+		SizeBytes: 10, SHA256: "00", CreatedByUserID: userID, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, store.Create(t.Context(), upload))
+	assert.ErrorIs(t, store.Create(t.Context(), upload), data.ErrConflict)
+
+	var storedFileName string
+	require.NoError(t, db.QueryRow(`SELECT file_name FROM resumable_uploads WHERE upload_id = 'abc'`).Scan(&storedFileName))
+	assert.NotContains(t, storedFileName, "Anna", "the file name must be stored encrypted")
+
+	require.NoError(t, store.AppendChunk(t.Context(), "abc", 0, []byte("0123")))
+	assert.ErrorIs(t, store.AppendChunk(t.Context(), "abc", 0, []byte("0123")), data.ErrConflict, "a chunk is only stored once")
+	assert.ErrorIs(t, store.AppendChunk(t.Context(), "abc", 8, []byte("89")), data.ErrConflict, "chunks must not leave gaps")
+	require.NoError(t, store.AppendChunk(t.Context(), "abc", 4, []byte("456789")))
+
+	stored, err := store.Get(t.Context(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "Morgenkreis Anna.mp3", stored.FileName)
+	assert.Equal(t, 10, stored.Offset)
+	assert.True(t, stored.Complete())
+	content, err := store.GetContent(t.Context(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("0123456789"), content)
+
+	deleted, err := store.DeleteExpired(t.Context(), now.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+	deleted, err = store.DeleteExpired(t.Context(), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	_, err = store.Get(t.Context(), "abc")
+	assert.ErrorIs(t, err, data.ErrNotFound)
+	var chunks int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM resumable_upload_chunks`).Scan(&chunks))
+	assert.Zero(t, chunks, "the chunks are deleted with their upload")
+	assert.ErrorIs(t, store.Delete(t.Context(), "abc"), data.ErrNotFound)
+}
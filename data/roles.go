@@ -7,3 +7,6 @@ const (
 	RoleAdmin   Role = "admin"
 	RoleTeacher Role = "teacher"
 )
+
+// Roles lists all roles known to the system.
+var Roles = []Role{RoleAdmin, RoleTeacher}
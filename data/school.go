@@ -0,0 +1,192 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"kitadoc-backend/models"
+)
+
+// SchoolStore defines the interface for School data operations.
+type SchoolStore interface {
+	Create(ctx context.Context, school *models.School) (int, error)
+	GetByID(ctx context.Context, id int) (*models.School, error)
+	Update(ctx context.Context, school *models.School) error
+	Delete(ctx context.Context, id int) error
+	GetAll(ctx context.Context) ([]models.School, error)
+	AddChild(ctx context.Context, schoolID int, childID int) error
+	RemoveChild(ctx context.Context, schoolID int, childID int) error
+}
+
+// SQLSchoolStore implements SchoolStore using database/sql.
+type SQLSchoolStore struct {
+	db *sql.DB
+}
+
+// NewSQLSchoolStore creates a new SQLSchoolStore.
+func NewSQLSchoolStore(db *sql.DB) *SQLSchoolStore {
+	return &SQLSchoolStore{db: db}
+}
+
+const schoolColumns = `school_id, school_name, street, house_number, postal_code, city, contact_person, email, phone_number, created_at, updated_at`
+
+func scanSchool(row rowScanner) (*models.School, error) {
+	school := &models.School{}
+	err := row.Scan(&school.ID, &school.Name, &school.Street, &school.HouseNumber, &school.PostalCode, &school.City,
+		&school.ContactPerson, &school.Email, &school.PhoneNumber, &school.CreatedAt, &school.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	school.ChildIDs = []int{}
+	return school, nil
+}
+
+// Create inserts a new school into the database.
+func (s *SQLSchoolStore) Create(ctx context.Context, school *models.School) (int, error) {
+	query := `INSERT INTO schools (school_name, street, house_number, postal_code, city, contact_person, email, phone_number) VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING school_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, school.Name, school.Street, school.HouseNumber, school.PostalCode, school.City,
+		school.ContactPerson, school.Email, school.PhoneNumber).Scan(&id); err != nil {
+		if isUniqueConstraintError(err) {
+			return 0, ErrConflict
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a school with its children by ID from the database.
+func (s *SQLSchoolStore) GetByID(ctx context.Context, id int) (*models.School, error) {
+	query := `SELECT ` + schoolColumns + ` FROM schools WHERE school_id = ?`
+	school, err := scanSchool(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	schools := []models.School{*school}
+	if err := s.loadChildren(ctx, schools); err != nil {
+		return nil, err
+	}
+	return &schools[0], nil
+}
+
+// Update updates an existing school in the database.
+func (s *SQLSchoolStore) Update(ctx context.Context, school *models.School) error {
+	query := `UPDATE schools SET school_name = ?, street = ?, house_number = ?, postal_code = ?, city = ?, contact_person = ?, email = ?, phone_number = ?,
+		updated_at = CURRENT_TIMESTAMP WHERE school_id = ?`
+	result, err := s.db.ExecContext(ctx, query, school.Name, school.Street, school.HouseNumber, school.PostalCode, school.City,
+		school.ContactPerson, school.Email, school.PhoneNumber, school.ID)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete deletes a school by ID from the database. Its children are left without an expected school.
+func (s *SQLSchoolStore) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM schools WHERE school_id = ?`
+	result, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetAll fetches all schools with their children ordered by name.
+func (s *SQLSchoolStore) GetAll(ctx context.Context) ([]models.School, error) {
+	query := `SELECT ` + schoolColumns + ` FROM schools ORDER BY school_name, school_id`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var schools []models.School
+	for rows.Next() {
+		school, err := scanSchool(rows)
+		if err != nil {
+			return nil, err
+		}
+		schools = append(schools, *school)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadChildren(ctx, schools); err != nil {
+		return nil, err
+	}
+	return schools, nil
+}
+
+// AddChild links a child to the school it is expected to enroll at, replacing its previous school.
+func (s *SQLSchoolStore) AddChild(ctx context.Context, schoolID int, childID int) error {
+	query := `INSERT INTO school_children (child_id, school_id) VALUES (?, ?) ON CONFLICT (child_id) DO UPDATE SET school_id = excluded.school_id`
+	_, err := s.db.ExecContext(ctx, query, childID, schoolID)
+	return err
+}
+
+// RemoveChild removes the link between a child and a school.
+func (s *SQLSchoolStore) RemoveChild(ctx context.Context, schoolID int, childID int) error {
+	query := `DELETE FROM school_children WHERE school_id = ? AND child_id = ?`
+	result, err := s.db.ExecContext(ctx, query, schoolID, childID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// loadChildren fills in the children of the given schools.
+func (s *SQLSchoolStore) loadChildren(ctx context.Context, schools []models.School) error {
+	if len(schools) == 0 {
+		return nil
+	}
+	byID := make(map[int]*models.School, len(schools))
+	for i := range schools {
+		byID[schools[i].ID] = &schools[i]
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT school_id, child_id FROM school_children ORDER BY child_id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close() //nolint:errcheck
+	for rows.Next() {
+		var schoolID, childID int
+		if err := rows.Scan(&schoolID, &childID); err != nil {
+			return err
+		}
+		if school, ok := byID[schoolID]; ok {
+			school.ChildIDs = append(school.ChildIDs, childID)
+		}
+	}
+	return rows.Err()
+}
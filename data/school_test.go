@@ -0,0 +1,88 @@
+package data_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLSchoolStore_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLSchoolStore(db)
+	email := "sekretariat@grundschule.example"
+	school := &models.School{Name: "Grundschule am Park", Email: &email}
+	query := regexp.QuoteMeta(`INSERT INTO schools (school_name, street, house_number, postal_code, city, contact_person, email, phone_number) VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING school_id`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("Grundschule am Park", nil, nil, nil, nil, nil, &email, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"school_id"}).AddRow(3))
+
+		id, err := store.Create(t.Context(), school)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLSchoolStore_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLSchoolStore(db)
+	query := regexp.QuoteMeta(`FROM schools WHERE school_id = ?`)
+	columns := []string{"school_id", "school_name", "street", "house_number", "postal_code", "city", "contact_person", "email", "phone_number", "created_at", "updated_at"}
+
+	t.Run("with children", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(query).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(3, "Grundschule am Park", "Parkweg", "1", "12345", "Musterstadt", "Frau Schulz", nil, nil, now, now))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT school_id, child_id FROM school_children ORDER BY child_id`)).
+			WillReturnRows(sqlmock.NewRows([]string{"school_id", "child_id"}).AddRow(3, 5).AddRow(4, 6).AddRow(3, 7))
+
+		school, err := store.GetByID(t.Context(), 3)
+		assert.NoError(t, err)
+		assert.Equal(t, "Frau Schulz", *school.ContactPerson)
+		assert.Nil(t, school.Email)
+		assert.Equal(t, []int{5, 7}, school.ChildIDs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(4).WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := store.GetByID(t.Context(), 4)
+		assert.Equal(t, data.ErrNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSQLSchoolStore_AddChild(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLSchoolStore(db)
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO school_children (child_id, school_id) VALUES (?, ?) ON CONFLICT (child_id) DO UPDATE SET school_id = excluded.school_id`)).
+		WithArgs(7, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, store.AddChild(t.Context(), 3, 7))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
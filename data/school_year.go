@@ -0,0 +1,180 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+
+	"kitadoc-backend/models"
+)
+
+// SchoolYearStore defines the interface for school year transition operations.
+type SchoolYearStore interface {
+	Rollover(ctx context.Context, request *models.RolloverRequest) (*models.RolloverSummary, error)
+}
+
+// SQLSchoolYearStore implements SchoolYearStore using database/sql.
+type SQLSchoolYearStore struct {
+	db *sql.DB
+}
+
+// NewSQLSchoolYearStore creates a new SQLSchoolYearStore.
+func NewSQLSchoolYearStore(db *sql.DB) *SQLSchoolYearStore {
+	return &SQLSchoolYearStore{db: db}
+}
+
+// Rollover archives departing children, moves open assignments according to the teacher mapping and
+// resets the pre-school cohort in a single transaction. A dry run rolls the transaction back.
+// ErrNotFound is returned if a departing child does not exist or is already archived.
+func (s *SQLSchoolYearStore) Rollover(ctx context.Context, request *models.RolloverRequest) (*models.RolloverSummary, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	summary := &models.RolloverSummary{
+		RolloverDate:        request.RolloverDate,
+		DryRun:              request.DryRun,
+		ArchivedChildIDs:    []int{},
+		EndedAssignments:    []models.Assignment{},
+		CreatedAssignments:  []models.Assignment{},
+		ResetPreschoolerIDs: []int{},
+	}
+
+	departingChildIDs := request.DepartingChildIDs
+	if departingChildIDs == nil {
+		departingChildIDs, err = childrenEnrollingBy(ctx, tx, request)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, childID := range departingChildIDs {
+		result, err := tx.ExecContext(ctx, `UPDATE children SET archived_at = ?, is_preschooler = FALSE WHERE child_id = ? AND archived_at IS NULL`, request.RolloverDate, childID)
+		if err != nil {
+			return nil, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if rowsAffected == 0 {
+			return nil, fmt.Errorf("child %d: %w", childID, ErrNotFound)
+		}
+		summary.ArchivedChildIDs = append(summary.ArchivedChildIDs, childID)
+
+		ended, err := endOpenAssignments(ctx, tx, `child_id = ?`, childID, request)
+		if err != nil {
+			return nil, err
+		}
+		summary.EndedAssignments = append(summary.EndedAssignments, ended...)
+	}
+
+	for _, fromTeacherID := range slices.Sorted(maps.Keys(request.TeacherMapping)) {
+		toTeacherID := request.TeacherMapping[fromTeacherID]
+		if fromTeacherID == toTeacherID {
+			continue
+		}
+		ended, err := endOpenAssignments(ctx, tx, `teacher_id = ?`, fromTeacherID, request)
+		if err != nil {
+			return nil, err
+		}
+		summary.EndedAssignments = append(summary.EndedAssignments, ended...)
+
+		for _, previous := range ended {
+			assignment := models.Assignment{
+				ChildID:        previous.ChildID,
+				TeacherID:      toTeacherID,
+				AssignmentType: previous.AssignmentType,
+				StartDate:      request.RolloverDate,
+			}
+			var id int
+			if err := tx.QueryRowContext(ctx, `INSERT INTO child_teacher_assignments (child_id, teacher_id, assignment_type, start_date) VALUES (?, ?, ?, ?) RETURNING assignment_id`, assignment.ChildID, assignment.TeacherID, assignment.AssignmentType, assignment.StartDate).Scan(&id); err != nil {
+				return nil, err
+			}
+			assignment.ID = id
+			summary.CreatedAssignments = append(summary.CreatedAssignments, assignment)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `UPDATE children SET is_preschooler = FALSE WHERE is_preschooler = TRUE AND archived_at IS NULL RETURNING child_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+	for rows.Next() {
+		var childID int
+		if err := rows.Scan(&childID); err != nil {
+			return nil, err
+		}
+		summary.ResetPreschoolerIDs = append(summary.ResetPreschoolerIDs, childID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close() //nolint:errcheck
+
+	if request.DryRun {
+		return summary, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// childrenEnrollingBy returns the active children whose expected school enrollment is on or before the rollover date.
+func childrenEnrollingBy(ctx context.Context, tx *sql.Tx, request *models.RolloverRequest) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT child_id, expected_school_enrollment FROM children WHERE archived_at IS NULL AND expected_school_enrollment IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var childIDs []int
+	for rows.Next() {
+		var childID int
+		var enrollment sql.NullTime
+		if err := rows.Scan(&childID, &enrollment); err != nil {
+			return nil, err
+		}
+		if enrollment.Valid && !enrollment.Time.After(request.RolloverDate) {
+			childIDs = append(childIDs, childID)
+		}
+	}
+	return childIDs, rows.Err()
+}
+
+// endOpenAssignments ends all open assignments matching the condition at the rollover date and returns them.
+func endOpenAssignments(ctx context.Context, tx *sql.Tx, condition string, arg int, request *models.RolloverRequest) ([]models.Assignment, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT assignment_id, child_id, teacher_id, assignment_type, start_date FROM child_teacher_assignments WHERE end_date IS NULL AND `+condition, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var assignments []models.Assignment
+	for rows.Next() {
+		assignment := models.Assignment{}
+		if err := rows.Scan(&assignment.ID, &assignment.ChildID, &assignment.TeacherID, &assignment.AssignmentType, &assignment.StartDate); err != nil {
+			return nil, err
+		}
+		endDate := request.RolloverDate
+		assignment.EndDate = &endDate
+		assignments = append(assignments, assignment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close() //nolint:errcheck
+
+	for _, assignment := range assignments {
+		if _, err := tx.ExecContext(ctx, `UPDATE child_teacher_assignments SET end_date = ?, updated_at = CURRENT_TIMESTAMP WHERE assignment_id = ?`, request.RolloverDate, assignment.ID); err != nil {
+			return nil, err
+		}
+	}
+	return assignments, nil
+}
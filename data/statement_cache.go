@@ -0,0 +1,81 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// maxCachedStatements bounds the statements a store keeps prepared. List queries differ by their filters and
+// sorting, so the number of statements depends on what callers ask for.
+const maxCachedStatements = 64
+
+// statementCache prepares statements on first use and reuses them afterwards, so that hot paths do not parse
+// the same SQL on every call. Statements beyond the limit are run unprepared.
+type statementCache struct {
+	db         *sql.DB
+	limit      int
+	mu         sync.RWMutex
+	statements map[string]*sql.Stmt
+}
+
+func newStatementCache(db *sql.DB, limit int) *statementCache {
+	return &statementCache{db: db, limit: limit, statements: map[string]*sql.Stmt{}}
+}
+
+// prepared returns the prepared statement for query. It returns nil if the cache is full, and the error
+// of the database if the statement cannot be prepared; nothing is cached in both cases.
+func (c *statementCache) prepared(ctx context.Context, query string) (*sql.Stmt, error) {
+	c.mu.RLock()
+	stmt, ok := c.statements[query]
+	c.mu.RUnlock()
+	if ok {
+		return stmt, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stmt, ok := c.statements[query]; ok {
+		return stmt, nil
+	}
+	if len(c.statements) >= c.limit {
+		return nil, nil
+	}
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.statements[query] = stmt
+	return stmt, nil
+}
+
+func (c *statementCache) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	stmt, err := c.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return c.db.ExecContext(ctx, query, args...)
+	}
+	return stmt.ExecContext(ctx, args...)
+}
+
+func (c *statementCache) query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := c.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return c.db.QueryContext(ctx, query, args...)
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// queryRow runs query unprepared if it cannot be prepared, a sql.Row cannot carry the error otherwise.
+func (c *statementCache) queryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	stmt, err := c.prepared(ctx, query)
+	if err != nil || stmt == nil {
+		return c.db.QueryRowContext(ctx, query, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
@@ -0,0 +1,131 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestStatementCache(t *testing.T) {
+	t.Run("prepares a statement once", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close() //nolint:errcheck
+
+		cache := newStatementCache(db, maxCachedStatements)
+		mock.ExpectPrepare(regexp.QuoteMeta(`DELETE FROM groups WHERE group_id = ?`))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM groups WHERE group_id = ?`)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM groups WHERE group_id = ?`)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err = cache.exec(t.Context(), `DELETE FROM groups WHERE group_id = ?`, 1)
+		assert.NoError(t, err)
+		_, err = cache.exec(t.Context(), `DELETE FROM groups WHERE group_id = ?`, 2)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("runs statements beyond the limit unprepared", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close() //nolint:errcheck
+
+		cache := newStatementCache(db, 1)
+		mock.ExpectPrepare(regexp.QuoteMeta(`SELECT group_id FROM groups`))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT group_id FROM groups`)).WillReturnRows(sqlmock.NewRows([]string{"group_id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id FROM teachers`)).WillReturnRows(sqlmock.NewRows([]string{"teacher_id"}))
+
+		rows, err := cache.query(t.Context(), `SELECT group_id FROM groups`)
+		require.NoError(t, err)
+		assert.NoError(t, rows.Close())
+		rows, err = cache.query(t.Context(), `SELECT teacher_id FROM teachers`)
+		require.NoError(t, err)
+		assert.NoError(t, rows.Close())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// openBenchmarkDB opens a migrated SQLite database with a child, a teacher and a category to document.
+func openBenchmarkDB(b *testing.B) *sql.DB {
+	db, err := sql.Open("sqlite", "file:"+b.TempDir()+"/bench.db?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)")
+	require.NoError(b, err)
+	b.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(b, MigrateDB(db, migrations.Files))
+
+	_, err = db.Exec(`INSERT INTO teachers (teacher_id, first_name, last_name, username) VALUES (1, 'Maria', 'Müller', 'mmueller')`)
+	require.NoError(b, err)
+	_, err = db.Exec(`INSERT INTO children (child_id, first_name, last_name, birthdate, admission_date) VALUES (1, 'x', 'y', 'encrypted', '2021-08-01T00:00:00Z')`)
+	require.NoError(b, err)
+	_, err = db.Exec(`INSERT INTO categories (category_id, category_name) VALUES (1, 'Sprache')`)
+	require.NoError(b, err)
+	return db
+}
+
+// benchmarkDocumentationEntryStores runs fn against a store that prepares its statements and one that does not.
+func benchmarkDocumentationEntryStores(b *testing.B, fn func(b *testing.B, store *SQLDocumentationEntryStore)) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	for _, variant := range []struct {
+		name  string
+		limit int
+	}{{"prepared", maxCachedStatements}, {"unprepared", 0}} {
+		b.Run(variant.name, func(b *testing.B) {
+			db := openBenchmarkDB(b)
+			store := &SQLDocumentationEntryStore{db: db, statements: newStatementCache(db, variant.limit), encryptionKey: key}
+			fn(b, store)
+		})
+	}
+}
+
+func benchmarkEntry(day int) *models.DocumentationEntry {
+	now := time.Now()
+	return &models.DocumentationEntry{
+		ChildID:                1,
+		TeacherID:              1,
+		CategoryID:             1,
+		ObservationDate:        models.NewDate(2025, 1, 1).AddDays(day),
+		ObservationDescription: "Baut einen hohen Turm aus Bauklötzen.",
+		CreatedAt:              now,
+		UpdatedAt:              now,
+	}
+}
+
+func BenchmarkSQLDocumentationEntryStore_Create(b *testing.B) {
+	benchmarkDocumentationEntryStores(b, func(b *testing.B, store *SQLDocumentationEntryStore) {
+		b.ReportAllocs()
+		for i := 0; b.Loop(); i++ {
+			if _, err := store.Create(context.Background(), benchmarkEntry(i%365)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkSQLDocumentationEntryStore_List(b *testing.B) {
+	benchmarkDocumentationEntryStores(b, func(b *testing.B, store *SQLDocumentationEntryStore) {
+		for i := range 50 {
+			_, err := store.Create(context.Background(), benchmarkEntry(i))
+			require.NoError(b, err)
+		}
+		query := models.ListQuery{Limit: 20}.Where("child_id", models.OperatorEqual, 1).Where("is_approved", models.OperatorEqual, false)
+
+		b.ReportAllocs()
+		for b.Loop() {
+			entries, err := store.List(context.Background(), query)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(entries) != 20 {
+				b.Fatalf("expected 20 entries, got %d", len(entries))
+			}
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"kitadoc-backend/models"
+)
+
+// StatusBannerStore defines the interface for the banner shown to all users.
+type StatusBannerStore interface {
+	Get(ctx context.Context) (*models.StatusBanner, error)
+	Set(ctx context.Context, banner *models.StatusBanner) error
+	Delete(ctx context.Context) error
+}
+
+// SQLStatusBannerStore implements StatusBannerStore using database/sql.
+type SQLStatusBannerStore struct {
+	db *sql.DB
+}
+
+// NewSQLStatusBannerStore creates a new SQLStatusBannerStore.
+func NewSQLStatusBannerStore(db *sql.DB) *SQLStatusBannerStore {
+	return &SQLStatusBannerStore{db: db}
+}
+
+// Get fetches the banner. It returns ErrNotFound if no banner is configured.
+func (s *SQLStatusBannerStore) Get(ctx context.Context) (*models.StatusBanner, error) {
+	query := `SELECT message, level, starts_at, ends_at, updated_by_user_id, updated_at FROM status_banner WHERE banner_id = 1`
+	banner := &models.StatusBanner{}
+	err := s.db.QueryRowContext(ctx, query).Scan(&banner.Message, &banner.Level, &banner.StartsAt, &banner.EndsAt, &banner.UpdatedByUserID, &banner.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return banner, nil
+}
+
+// Set replaces the banner.
+func (s *SQLStatusBannerStore) Set(ctx context.Context, banner *models.StatusBanner) error {
+	query := `INSERT INTO status_banner (banner_id, message, level, starts_at, ends_at, updated_by_user_id) VALUES (1, ?, ?, ?, ?, ?)
+		ON CONFLICT(banner_id) DO UPDATE SET message = excluded.message, level = excluded.level, starts_at = excluded.starts_at,
+		ends_at = excluded.ends_at, updated_by_user_id = excluded.updated_by_user_id, updated_at = CURRENT_TIMESTAMP`
+	_, err := s.db.ExecContext(ctx, query, banner.Message, banner.Level, banner.StartsAt, banner.EndsAt, banner.UpdatedByUserID)
+	return err
+}
+
+// Delete removes the banner. It returns ErrNotFound if no banner is configured.
+func (s *SQLStatusBannerStore) Delete(ctx context.Context) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM status_banner WHERE banner_id = 1`)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package data_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLStatusBannerStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLStatusBannerStore(db)
+	adminID := 1
+	startsAt := time.Date(2025, time.June, 6, 18, 0, 0, 0, time.UTC)
+	columns := []string{"message", "level", "starts_at", "ends_at", "updated_by_user_id", "updated_at"}
+	selectQuery := regexp.QuoteMeta(`SELECT message, level, starts_at, ends_at, updated_by_user_id, updated_at FROM status_banner WHERE banner_id = 1`)
+
+	mock.ExpectQuery(selectQuery).WillReturnRows(sqlmock.NewRows(columns))
+	_, err = store.Get(t.Context())
+	assert.ErrorIs(t, err, data.ErrNotFound)
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO status_banner (banner_id, message, level, starts_at, ends_at, updated_by_user_id) VALUES (1, ?, ?, ?, ?, ?)`)).
+		WithArgs("Wartungsarbeiten am Freitagabend", models.BannerLevelMaintenance, &startsAt, nil, &adminID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = store.Set(t.Context(), &models.StatusBanner{Message: "Wartungsarbeiten am Freitagabend", Level: models.BannerLevelMaintenance, StartsAt: &startsAt, UpdatedByUserID: &adminID})
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(selectQuery).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("Wartungsarbeiten am Freitagabend", models.BannerLevelMaintenance, startsAt, nil, adminID, startsAt))
+	banner, err := store.Get(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, startsAt, *banner.StartsAt)
+	assert.Nil(t, banner.EndsAt)
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM status_banner WHERE banner_id = 1`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, store.Delete(t.Context()))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM status_banner WHERE banner_id = 1`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, store.Delete(t.Context()), data.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
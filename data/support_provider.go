@@ -0,0 +1,283 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/models"
+)
+
+// SupportProviderStore defines the interface for SupportProvider and ChildSupport data operations.
+type SupportProviderStore interface {
+	Create(ctx context.Context, provider *models.SupportProvider) (int, error)
+	GetByID(ctx context.Context, id int) (*models.SupportProvider, error)
+	Update(ctx context.Context, provider *models.SupportProvider) error
+	// Delete returns ErrForeignKeyConstraint if children are supported by the provider.
+	Delete(ctx context.Context, id int) error
+	GetAll(ctx context.Context) ([]models.SupportProvider, error)
+	CreateSupport(ctx context.Context, support *models.ChildSupport) (int, error)
+	GetSupportByID(ctx context.Context, id int) (*models.ChildSupport, error)
+	UpdateSupport(ctx context.Context, support *models.ChildSupport) error
+	DeleteSupport(ctx context.Context, id int) error
+	// GetSupportsForChild fetches the supports of a child ordered by start date, ended ones included.
+	GetSupportsForChild(ctx context.Context, childID int) ([]models.ChildSupport, error)
+}
+
+// SQLSupportProviderStore implements SupportProviderStore using database/sql.
+type SQLSupportProviderStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLSupportProviderStore creates a new SQLSupportProviderStore.
+func NewSQLSupportProviderStore(db *sql.DB, encryptionKey []byte) *SQLSupportProviderStore {
+	return &SQLSupportProviderStore{db: db, encryptionKey: encryptionKey}
+}
+
+const supportProviderColumns = `provider_id, provider_name, provider_type, contact_person, email, phone_number, address, created_at, updated_at`
+
+const childSupportColumns = `s.support_id, s.child_id, s.provider_id, p.provider_name, p.provider_type, s.consent_reference, s.start_date, s.end_date, s.appointment_notes, s.created_at, s.updated_at`
+
+func scanSupportProvider(row rowScanner) (*models.SupportProvider, error) {
+	provider := &models.SupportProvider{}
+	err := row.Scan(&provider.ID, &provider.Name, &provider.ProviderType, &provider.ContactPerson, &provider.Email, &provider.PhoneNumber,
+		&provider.Address, &provider.CreatedAt, &provider.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// Create inserts a new support provider into the database.
+func (s *SQLSupportProviderStore) Create(ctx context.Context, provider *models.SupportProvider) (int, error) {
+	query := `INSERT INTO support_providers (provider_name, provider_type, contact_person, email, phone_number, address) VALUES (?, ?, ?, ?, ?, ?) RETURNING provider_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, provider.Name, provider.ProviderType, provider.ContactPerson, provider.Email, provider.PhoneNumber, provider.Address).Scan(&id); err != nil {
+		if isUniqueConstraintError(err) {
+			return 0, ErrConflict
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a support provider by ID from the database.
+func (s *SQLSupportProviderStore) GetByID(ctx context.Context, id int) (*models.SupportProvider, error) {
+	query := `SELECT ` + supportProviderColumns + ` FROM support_providers WHERE provider_id = ?`
+	provider, err := scanSupportProvider(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return provider, nil
+}
+
+// Update updates an existing support provider in the database.
+func (s *SQLSupportProviderStore) Update(ctx context.Context, provider *models.SupportProvider) error {
+	query := `UPDATE support_providers SET provider_name = ?, provider_type = ?, contact_person = ?, email = ?, phone_number = ?, address = ?,
+		updated_at = CURRENT_TIMESTAMP WHERE provider_id = ?`
+	result, err := s.db.ExecContext(ctx, query, provider.Name, provider.ProviderType, provider.ContactPerson, provider.Email, provider.PhoneNumber,
+		provider.Address, provider.ID)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete deletes a support provider by ID from the database.
+func (s *SQLSupportProviderStore) Delete(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM support_providers WHERE provider_id = ?`, id)
+	if err != nil {
+		// Check for foreign key constraint violation
+		if isForeignKeyError(err) {
+			return ErrForeignKeyConstraint
+		}
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetAll fetches all support providers ordered by name.
+func (s *SQLSupportProviderStore) GetAll(ctx context.Context) ([]models.SupportProvider, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+supportProviderColumns+` FROM support_providers ORDER BY provider_name, provider_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	providers := []models.SupportProvider{}
+	for rows.Next() {
+		provider, err := scanSupportProvider(rows)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, *provider)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// CreateSupport inserts a new support of a child into the database.
+func (s *SQLSupportProviderStore) CreateSupport(ctx context.Context, support *models.ChildSupport) (int, error) {
+	consentReference, appointmentNotes, err := s.encryptSupport(support)
+	if err != nil {
+		return 0, err
+	}
+	query := `INSERT INTO child_supports (child_id, provider_id, consent_reference, start_date, end_date, appointment_notes) VALUES (?, ?, ?, ?, ?, ?) RETURNING support_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, support.ChildID, support.ProviderID, consentReference, support.StartDate, support.EndDate, appointmentNotes).Scan(&id); err != nil {
+		if isForeignKeyError(err) {
+			return 0, ErrForeignKeyConstraint
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetSupportByID fetches a support of a child with its provider by ID from the database.
+func (s *SQLSupportProviderStore) GetSupportByID(ctx context.Context, id int) (*models.ChildSupport, error) {
+	query := `SELECT ` + childSupportColumns + ` FROM child_supports s JOIN support_providers p ON p.provider_id = s.provider_id WHERE s.support_id = ?`
+	support, err := s.scanChildSupport(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return support, nil
+}
+
+// UpdateSupport updates an existing support of a child in the database, the child cannot be changed.
+func (s *SQLSupportProviderStore) UpdateSupport(ctx context.Context, support *models.ChildSupport) error {
+	consentReference, appointmentNotes, err := s.encryptSupport(support)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE child_supports SET provider_id = ?, consent_reference = ?, start_date = ?, end_date = ?, appointment_notes = ?,
+		updated_at = CURRENT_TIMESTAMP WHERE support_id = ?`
+	result, err := s.db.ExecContext(ctx, query, support.ProviderID, consentReference, support.StartDate, support.EndDate, appointmentNotes, support.ID)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return ErrForeignKeyConstraint
+		}
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteSupport deletes a support of a child by ID from the database.
+func (s *SQLSupportProviderStore) DeleteSupport(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM child_supports WHERE support_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetSupportsForChild fetches the supports of a child with their providers.
+func (s *SQLSupportProviderStore) GetSupportsForChild(ctx context.Context, childID int) ([]models.ChildSupport, error) {
+	query := `SELECT ` + childSupportColumns + ` FROM child_supports s JOIN support_providers p ON p.provider_id = s.provider_id
+		WHERE s.child_id = ? ORDER BY s.start_date, s.support_id`
+	rows, err := s.db.QueryContext(ctx, query, childID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	supports := []models.ChildSupport{}
+	for rows.Next() {
+		support, err := s.scanChildSupport(rows)
+		if err != nil {
+			return nil, err
+		}
+		supports = append(supports, *support)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return supports, nil
+}
+
+// encryptSupport encrypts the consent reference and the appointment notes of a support, nil values stay NULL.
+func (s *SQLSupportProviderStore) encryptSupport(support *models.ChildSupport) (*string, *string, error) {
+	var consentReference, appointmentNotes *string
+	if support.ConsentReference != nil {
+		encrypted, err := Encrypt(*support.ConsentReference, s.encryptionKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encrypt consent reference: %w", err)
+		}
+		consentReference = &encrypted
+	}
+	if support.AppointmentNotes != nil {
+		encrypted, err := Encrypt(*support.AppointmentNotes, s.encryptionKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encrypt appointment notes: %w", err)
+		}
+		appointmentNotes = &encrypted
+	}
+	return consentReference, appointmentNotes, nil
+}
+
+func (s *SQLSupportProviderStore) scanChildSupport(row rowScanner) (*models.ChildSupport, error) {
+	support := &models.ChildSupport{}
+	var consentReference, appointmentNotes sql.NullString
+	err := row.Scan(&support.ID, &support.ChildID, &support.ProviderID, &support.ProviderName, &support.ProviderType, &consentReference,
+		&support.StartDate, &support.EndDate, &appointmentNotes, &support.CreatedAt, &support.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if consentReference.Valid {
+		decrypted, err := Decrypt(consentReference.String, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt consent reference: %w", err)
+		}
+		support.ConsentReference = &decrypted
+	}
+	if appointmentNotes.Valid {
+		decrypted, err := Decrypt(appointmentNotes.String, s.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt appointment notes: %w", err)
+		}
+		support.AppointmentNotes = &decrypted
+	}
+	return support, nil
+}
@@ -0,0 +1,76 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLSupportProviderStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	childID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2020, time.March, 15)})
+	require.NoError(t, err)
+
+	store := dal.SupportProviders
+	providerID, err := store.Create(t.Context(), &models.SupportProvider{Name: "Logopädie am Markt", ProviderType: models.SupportProviderTypeSpeechTherapy})
+	require.NoError(t, err)
+	_, err = store.Create(t.Context(), &models.SupportProvider{Name: "Logopädie am Markt", ProviderType: models.SupportProviderTypeOther})
+	assert.ErrorIs(t, err, data.ErrConflict)
+
+	consent := "Einwilligung vom 03.02.2025"
+	notes := "Termine dienstags 9 Uhr"
+	supportID, err := store.CreateSupport(t.Context(), &models.ChildSupport{
+		ChildID:          childID,
+		ProviderID:       providerID,
+		ConsentReference: &consent,
+		StartDate:        time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+		AppointmentNotes: &notes,
+	})
+	require.NoError(t, err)
+	_, err = store.CreateSupport(t.Context(), &models.ChildSupport{ChildID: childID, ProviderID: 999, StartDate: time.Now()})
+	assert.ErrorIs(t, err, data.ErrForeignKeyConstraint)
+
+	var storedConsent, storedNotes string
+	require.NoError(t, db.QueryRow(`SELECT consent_reference, appointment_notes FROM child_supports WHERE support_id = ?`, supportID).Scan(&storedConsent, &storedNotes))
+	assert.NotContains(t, storedConsent, "Einwilligung", "the consent reference must be stored encrypted")
+	assert.NotContains(t, storedNotes, "dienstags", "the appointment notes must be stored encrypted")
+
+	supports, err := store.GetSupportsForChild(t.Context(), childID)
+	require.NoError(t, err)
+	require.Len(t, supports, 1)
+	assert.Equal(t, "Logopädie am Markt", supports[0].ProviderName)
+	assert.Equal(t, models.SupportProviderTypeSpeechTherapy, supports[0].ProviderType)
+	assert.Equal(t, consent, *supports[0].ConsentReference)
+	assert.Equal(t, notes, *supports[0].AppointmentNotes)
+	assert.Nil(t, supports[0].EndDate)
+
+	support := supports[0]
+	end := time.Date(2025, time.July, 31, 0, 0, 0, 0, time.UTC)
+	support.EndDate = &end
+	support.AppointmentNotes = nil
+	require.NoError(t, store.UpdateSupport(t.Context(), &support))
+	updated, err := store.GetSupportByID(t.Context(), supportID)
+	require.NoError(t, err)
+	require.NotNil(t, updated.EndDate)
+	assert.True(t, end.Equal(*updated.EndDate))
+	assert.Nil(t, updated.AppointmentNotes)
+
+	assert.ErrorIs(t, store.Delete(t.Context(), providerID), data.ErrForeignKeyConstraint, "providers of supported children cannot be deleted")
+	require.NoError(t, store.DeleteSupport(t.Context(), supportID))
+	assert.ErrorIs(t, store.DeleteSupport(t.Context(), supportID), data.ErrNotFound)
+	require.NoError(t, store.Delete(t.Context(), providerID))
+	_, err = store.GetByID(t.Context(), providerID)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+}
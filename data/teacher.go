@@ -1,22 +1,30 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"kitadoc-backend/models"
-	"modernc.org/sqlite"
 )
 
 // TeacherStore defines the interface for Teacher data operations.
 type TeacherStore interface {
-	Create(teacher *models.Teacher) (int, error)
-	GetByID(id int) (*models.Teacher, error)
-	Update(teacher *models.Teacher) error
-	Delete(id int) error
-	GetAll() ([]models.Teacher, error)
+	Create(ctx context.Context, teacher *models.Teacher) (int, error)
+	GetByID(ctx context.Context, id int) (*models.Teacher, error)
+	Update(ctx context.Context, teacher *models.Teacher) error
+	Delete(ctx context.Context, id int) error
+	// GetAll fetches all teachers, including deactivated ones.
+	GetAll(ctx context.Context) ([]models.Teacher, error)
+	// SetDeactivated deactivates a teacher at the given time, nil reactivates it.
+	SetDeactivated(ctx context.Context, id int, deactivatedAt *time.Time) error
+	// Merge moves the assignments, documentation and groups of the duplicate to the teacher and deletes the duplicate.
+	Merge(ctx context.Context, duplicateID int, teacherID int) error
+	// CountUsage counts the entries, open assignments and assigned children of every used teacher, keyed by teacher ID.
+	CountUsage(ctx context.Context) (map[int]models.UsageCounts, error)
 }
 
 // SQLTeacherStore implements TeacherStore using database/sql.
@@ -99,30 +107,26 @@ func fromTeacherDB(dbTeacher *models.TeacherDB, key []byte) (*models.Teacher, er
 }
 
 // Create inserts a new teacher into the database.
-func (s *SQLTeacherStore) Create(teacher *models.Teacher) (int, error) {
+func (s *SQLTeacherStore) Create(ctx context.Context, teacher *models.Teacher) (int, error) {
 	dbTeacher, err := toTeacherDB(teacher, s.encryptionKey)
 	if err != nil {
 		return 0, err
 	}
 
-	query := `INSERT INTO teachers (first_name, last_name, username, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
-	result, err := s.db.Exec(query, dbTeacher.FirstName, dbTeacher.LastName, dbTeacher.Username, teacher.CreatedAt, teacher.UpdatedAt)
-	if err != nil {
+	query := `INSERT INTO teachers (first_name, last_name, username, created_at, updated_at) VALUES (?, ?, ?, ?, ?) RETURNING teacher_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, dbTeacher.FirstName, dbTeacher.LastName, dbTeacher.Username, teacher.CreatedAt, teacher.UpdatedAt).Scan(&id); err != nil {
 		return 0, err
 	}
-	id, err := result.LastInsertId()
-	if err != nil {
-		return 0, err
-	}
-	return int(id), nil
+	return id, nil
 }
 
 // GetByID fetches a teacher by ID from the database.
-func (s *SQLTeacherStore) GetByID(id int) (*models.Teacher, error) {
-	query := `SELECT teacher_id, first_name, last_name, username, created_at, updated_at FROM teachers WHERE teacher_id = ?`
-	row := s.db.QueryRow(query, id)
+func (s *SQLTeacherStore) GetByID(ctx context.Context, id int) (*models.Teacher, error) {
+	query := `SELECT teacher_id, first_name, last_name, username, deactivated_at, created_at, updated_at FROM teachers WHERE teacher_id = ?`
+	row := s.db.QueryRowContext(ctx, query, id)
 	dbTeacher := &models.TeacherDB{}
-	err := row.Scan(&dbTeacher.ID, &dbTeacher.FirstName, &dbTeacher.LastName, &dbTeacher.Username, &dbTeacher.CreatedAt, &dbTeacher.UpdatedAt)
+	err := row.Scan(&dbTeacher.ID, &dbTeacher.FirstName, &dbTeacher.LastName, &dbTeacher.Username, &dbTeacher.DeactivatedAt, &dbTeacher.CreatedAt, &dbTeacher.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -134,14 +138,14 @@ func (s *SQLTeacherStore) GetByID(id int) (*models.Teacher, error) {
 }
 
 // Update updates an existing teacher in the database.
-func (s *SQLTeacherStore) Update(teacher *models.Teacher) error {
+func (s *SQLTeacherStore) Update(ctx context.Context, teacher *models.Teacher) error {
 	dbTeacher, err := toTeacherDB(teacher, s.encryptionKey)
 	if err != nil {
 		return err
 	}
 
 	query := `UPDATE teachers SET first_name = ?, last_name = ?, username = ?, updated_at = ? WHERE teacher_id = ?`
-	result, err := s.db.Exec(query, dbTeacher.FirstName, dbTeacher.LastName, dbTeacher.Username, teacher.UpdatedAt, dbTeacher.ID)
+	result, err := s.db.ExecContext(ctx, query, dbTeacher.FirstName, dbTeacher.LastName, dbTeacher.Username, teacher.UpdatedAt, dbTeacher.ID)
 	if err != nil {
 		return err
 	}
@@ -156,16 +160,13 @@ func (s *SQLTeacherStore) Update(teacher *models.Teacher) error {
 }
 
 // Delete deletes a teacher by ID from the database.
-func (s *SQLTeacherStore) Delete(id int) error {
+func (s *SQLTeacherStore) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM teachers WHERE teacher_id = ?`
-	result, err := s.db.Exec(query, id)
+	result, err := s.db.ExecContext(ctx, query, id)
 	if err != nil {
 		// Check for foreign key constraint violation
-		if liteErr, ok := err.(*sqlite.Error); ok {
-			code := liteErr.Code()
-			if code == 1811 || code == 787 {
-				return ErrForeignKeyConstraint
-			}
+		if isForeignKeyError(err) {
+			return ErrForeignKeyConstraint
 		}
 		return err
 	}
@@ -179,10 +180,10 @@ func (s *SQLTeacherStore) Delete(id int) error {
 	return nil
 }
 
-// GetAll fetches all teachers from the database.
-func (s *SQLTeacherStore) GetAll() ([]models.Teacher, error) {
-	query := `SELECT teacher_id, first_name, last_name, username, created_at, updated_at FROM teachers`
-	rows, err := s.db.Query(query)
+// GetAll fetches all teachers from the database, including deactivated ones.
+func (s *SQLTeacherStore) GetAll(ctx context.Context) ([]models.Teacher, error) {
+	query := `SELECT teacher_id, first_name, last_name, username, deactivated_at, created_at, updated_at FROM teachers ORDER BY teacher_id`
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +192,7 @@ func (s *SQLTeacherStore) GetAll() ([]models.Teacher, error) {
 	var teachers []models.Teacher
 	for rows.Next() {
 		dbTeacher := &models.TeacherDB{}
-		err := rows.Scan(&dbTeacher.ID, &dbTeacher.FirstName, &dbTeacher.LastName, &dbTeacher.Username, &dbTeacher.CreatedAt, &dbTeacher.UpdatedAt)
+		err := rows.Scan(&dbTeacher.ID, &dbTeacher.FirstName, &dbTeacher.LastName, &dbTeacher.Username, &dbTeacher.DeactivatedAt, &dbTeacher.CreatedAt, &dbTeacher.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -209,3 +210,69 @@ func (s *SQLTeacherStore) GetAll() ([]models.Teacher, error) {
 
 	return teachers, nil
 }
+
+// SetDeactivated deactivates a teacher at the given time, nil reactivates it.
+func (s *SQLTeacherStore) SetDeactivated(ctx context.Context, id int, deactivatedAt *time.Time) error {
+	query := `UPDATE teachers SET deactivated_at = ? WHERE teacher_id = ?`
+	result, err := s.db.ExecContext(ctx, query, deactivatedAt, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Merge moves everything referring to the duplicate teacher to the teacher and deletes the duplicate in one transaction.
+// Locked documentation entries are moved as well, both records describe the same person.
+func (s *SQLTeacherStore) Merge(ctx context.Context, duplicateID int, teacherID int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	statements := []string{
+		`UPDATE child_teacher_assignments SET teacher_id = ? WHERE teacher_id = ?`,
+		`UPDATE documentation_entries SET documenting_teacher_id = ? WHERE documenting_teacher_id = ?`,
+		`UPDATE documentation_entries SET approved_by_teacher_id = ? WHERE approved_by_teacher_id = ?`,
+		`UPDATE kita_groups SET lead_teacher_id = ? WHERE lead_teacher_id = ?`,
+		// The duplicate's assistant rows are removed by the cascade when it is deleted.
+		`INSERT INTO group_assistants (group_id, teacher_id) SELECT group_id, ? FROM group_assistants WHERE teacher_id = ? ON CONFLICT DO NOTHING`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, teacherID, duplicateID); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM teachers WHERE teacher_id = ?`, duplicateID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return tx.Commit()
+}
+
+// CountUsage counts the documentation entries, open assignments and assigned children of every used teacher, keyed by teacher ID.
+func (s *SQLTeacherStore) CountUsage(ctx context.Context) (map[int]models.UsageCounts, error) {
+	query := `SELECT t.teacher_id, COALESCE(e.entries, 0), COALESCE(a.open_assignments, 0), COALESCE(a.children, 0)
+		FROM teachers t
+		LEFT JOIN (SELECT documenting_teacher_id, COUNT(*) AS entries FROM documentation_entries GROUP BY documenting_teacher_id) e ON e.documenting_teacher_id = t.teacher_id
+		LEFT JOIN (SELECT teacher_id, COUNT(*) AS open_assignments, COUNT(DISTINCT child_id) AS children FROM child_teacher_assignments WHERE end_date IS NULL GROUP BY teacher_id) a ON a.teacher_id = t.teacher_id
+		WHERE e.entries IS NOT NULL OR a.open_assignments IS NOT NULL`
+	return queryUsageCounts(ctx, s.db, query, func(usage *models.UsageCounts) []any {
+		return []any{&usage.Entries, &usage.OpenAssignments, &usage.Children}
+	})
+}
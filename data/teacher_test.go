@@ -32,22 +32,22 @@ func TestSQLTeacherStore_Create(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO teachers (first_name, last_name, username, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO teachers (first_name, last_name, username, created_at, updated_at) VALUES (?, ?, ?, ?, ?) RETURNING teacher_id`)).
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), teacher.CreatedAt, teacher.UpdatedAt).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+			WillReturnRows(sqlmock.NewRows([]string{"teacher_id"}).AddRow(1))
 
-		id, err := store.Create(teacher)
+		id, err := store.Create(t.Context(), teacher)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, id)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO teachers (first_name, last_name, username, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO teachers (first_name, last_name, username, created_at, updated_at) VALUES (?, ?, ?, ?, ?) RETURNING teacher_id`)).
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), teacher.CreatedAt, teacher.UpdatedAt).
 			WillReturnError(errors.New("db error"))
 
-		id, err := store.Create(teacher)
+		id, err := store.Create(t.Context(), teacher)
 		assert.Error(t, err)
 		assert.Equal(t, 0, id)
 		assert.Contains(t, err.Error(), "db error")
@@ -80,14 +80,14 @@ func TestSQLTeacherStore_GetByID(t *testing.T) {
 		encryptedLastName, _ := data.Encrypt(expectedTeacher.LastName, key)
 		encryptedUsername, _ := data.Encrypt(expectedTeacher.Username, key)
 
-		rows := sqlmock.NewRows([]string{"teacher_id", "first_name", "last_name", "username", "created_at", "updated_at"}).
-			AddRow(expectedTeacher.ID, encryptedFirstName, encryptedLastName, encryptedUsername, expectedTeacher.CreatedAt, expectedTeacher.UpdatedAt)
+		rows := sqlmock.NewRows([]string{"teacher_id", "first_name", "last_name", "username", "deactivated_at", "created_at", "updated_at"}).
+			AddRow(expectedTeacher.ID, encryptedFirstName, encryptedLastName, encryptedUsername, nil, expectedTeacher.CreatedAt, expectedTeacher.UpdatedAt)
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, created_at, updated_at FROM teachers WHERE teacher_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, deactivated_at, created_at, updated_at FROM teachers WHERE teacher_id = ?`)).
 			WithArgs(teacherID).
 			WillReturnRows(rows)
 
-		teacher, err := store.GetByID(teacherID)
+		teacher, err := store.GetByID(t.Context(), teacherID)
 		assert.NoError(t, err)
 		assert.NotNil(t, teacher)
 		assert.Equal(t, expectedTeacher.ID, teacher.ID)
@@ -100,11 +100,11 @@ func TestSQLTeacherStore_GetByID(t *testing.T) {
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, created_at, updated_at FROM teachers WHERE teacher_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, deactivated_at, created_at, updated_at FROM teachers WHERE teacher_id = ?`)).
 			WithArgs(teacherID).
 			WillReturnError(sql.ErrNoRows)
 
-		teacher, err := store.GetByID(teacherID)
+		teacher, err := store.GetByID(t.Context(), teacherID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.Nil(t, teacher)
@@ -112,11 +112,11 @@ func TestSQLTeacherStore_GetByID(t *testing.T) {
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, created_at, updated_at FROM teachers WHERE teacher_id = ?`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, deactivated_at, created_at, updated_at FROM teachers WHERE teacher_id = ?`)).
 			WithArgs(teacherID).
 			WillReturnError(errors.New("db error"))
 
-		teacher, err := store.GetByID(teacherID)
+		teacher, err := store.GetByID(t.Context(), teacherID)
 		assert.Error(t, err)
 		assert.Nil(t, teacher)
 		assert.Contains(t, err.Error(), "db error")
@@ -146,7 +146,7 @@ func TestSQLTeacherStore_Update(t *testing.T) {
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), teacher.UpdatedAt, teacher.ID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Update(teacher)
+		err := store.Update(t.Context(), teacher)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -156,7 +156,7 @@ func TestSQLTeacherStore_Update(t *testing.T) {
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), teacher.UpdatedAt, teacher.ID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := store.Update(teacher)
+		err := store.Update(t.Context(), teacher)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -167,7 +167,7 @@ func TestSQLTeacherStore_Update(t *testing.T) {
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), teacher.UpdatedAt, teacher.ID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Update(teacher)
+		err := store.Update(t.Context(), teacher)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -191,18 +191,18 @@ func TestSQLTeacherStore_GetAll(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"teacher_id", "first_name", "last_name", "username", "created_at", "updated_at"})
+		rows := sqlmock.NewRows([]string{"teacher_id", "first_name", "last_name", "username", "deactivated_at", "created_at", "updated_at"})
 		for _, teacher := range teachers {
 			encryptedFirstName, _ := data.Encrypt(teacher.FirstName, key)
 			encryptedLastName, _ := data.Encrypt(teacher.LastName, key)
 			encryptedUsername, _ := data.Encrypt(teacher.Username, key)
-			rows.AddRow(teacher.ID, encryptedFirstName, encryptedLastName, encryptedUsername, teacher.CreatedAt, teacher.UpdatedAt)
+			rows.AddRow(teacher.ID, encryptedFirstName, encryptedLastName, encryptedUsername, teacher.DeactivatedAt, teacher.CreatedAt, teacher.UpdatedAt)
 		}
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, created_at, updated_at FROM teachers`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, deactivated_at, created_at, updated_at FROM teachers ORDER BY teacher_id`)).
 			WillReturnRows(rows)
 
-		fetchedTeachers, err := store.GetAll()
+		fetchedTeachers, err := store.GetAll(t.Context())
 		assert.NoError(t, err)
 		assert.NotNil(t, fetchedTeachers)
 		assert.Len(t, fetchedTeachers, 2)
@@ -212,23 +212,66 @@ func TestSQLTeacherStore_GetAll(t *testing.T) {
 	})
 
 	t.Run("no teachers found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, created_at, updated_at FROM teachers`)).
-			WillReturnRows(sqlmock.NewRows([]string{"teacher_id", "first_name", "last_name", "username", "created_at", "updated_at"}))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, deactivated_at, created_at, updated_at FROM teachers ORDER BY teacher_id`)).
+			WillReturnRows(sqlmock.NewRows([]string{"teacher_id", "first_name", "last_name", "username", "deactivated_at", "created_at", "updated_at"}))
 
-		fetchedTeachers, err := store.GetAll()
+		fetchedTeachers, err := store.GetAll(t.Context())
 		assert.NoError(t, err)
 		assert.Nil(t, fetchedTeachers)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, created_at, updated_at FROM teachers`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT teacher_id, first_name, last_name, username, deactivated_at, created_at, updated_at FROM teachers ORDER BY teacher_id`)).
 			WillReturnError(errors.New("db error"))
 
-		fetchedTeachers, err := store.GetAll()
+		fetchedTeachers, err := store.GetAll(t.Context())
 		assert.Error(t, err)
 		assert.Nil(t, fetchedTeachers)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestSQLTeacherStore_Merge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLTeacherStore(db, []byte("0123456789abcdef0123456789abcdef"))
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE child_teacher_assignments SET teacher_id = ? WHERE teacher_id = ?`)).
+			WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET documenting_teacher_id = ? WHERE documenting_teacher_id = ?`)).
+			WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE documentation_entries SET approved_by_teacher_id = ? WHERE approved_by_teacher_id = ?`)).
+			WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE kita_groups SET lead_teacher_id = ? WHERE lead_teacher_id = ?`)).
+			WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO group_assistants (group_id, teacher_id) SELECT group_id, ? FROM group_assistants WHERE teacher_id = ? ON CONFLICT DO NOTHING`)).
+			WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM teachers WHERE teacher_id = ?`)).
+			WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, store.Merge(t.Context(), 2, 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("duplicate not found rolls back", func(t *testing.T) {
+		mock.ExpectBegin()
+		for range 5 {
+			mock.ExpectExec(".*").WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM teachers WHERE teacher_id = ?`)).
+			WithArgs(99).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, store.Merge(t.Context(), 99, 1), data.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
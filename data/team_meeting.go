@@ -0,0 +1,380 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// TeamMeetingStore defines the interface for TeamMeeting and ActionItem data operations.
+type TeamMeetingStore interface {
+	Create(ctx context.Context, meeting *models.TeamMeeting) (int, error)
+	// GetByID fetches a meeting with its attendees, children and action items.
+	GetByID(ctx context.Context, id int) (*models.TeamMeeting, error)
+	// Update updates a meeting and replaces its attendees and children, its action items are kept.
+	Update(ctx context.Context, meeting *models.TeamMeeting) error
+	Delete(ctx context.Context, id int) error
+	// GetAll fetches all meetings, the latest first.
+	GetAll(ctx context.Context) ([]models.TeamMeeting, error)
+	// GetForChild fetches the meetings the child was discussed in or got an action item in, the latest first.
+	GetForChild(ctx context.Context, childID int) ([]models.TeamMeeting, error)
+	CreateActionItem(ctx context.Context, item *models.ActionItem) (int, error)
+	GetActionItemByID(ctx context.Context, id int) (*models.ActionItem, error)
+	UpdateActionItem(ctx context.Context, item *models.ActionItem) error
+	// SetActionItemCompleted completes an action item at the given time, nil reopens it.
+	SetActionItemCompleted(ctx context.Context, id int, completedAt *time.Time) error
+	DeleteActionItem(ctx context.Context, id int) error
+	// GetOpenActionItems fetches the action items that have not been completed, the earliest due first.
+	GetOpenActionItems(ctx context.Context) ([]models.ActionItem, error)
+}
+
+// SQLTeamMeetingStore implements TeamMeetingStore using database/sql.
+type SQLTeamMeetingStore struct {
+	db            *sql.DB
+	encryptionKey []byte
+}
+
+// NewSQLTeamMeetingStore creates a new SQLTeamMeetingStore.
+func NewSQLTeamMeetingStore(db *sql.DB, encryptionKey []byte) *SQLTeamMeetingStore {
+	return &SQLTeamMeetingStore{db: db, encryptionKey: encryptionKey}
+}
+
+const teamMeetingColumns = `meeting_id, meeting_date, title, minutes, recorded_by_user_id, created_at, updated_at`
+
+const actionItemColumns = `action_item_id, meeting_id, description, teacher_id, child_id, due_date, completed_at, created_at, updated_at`
+
+// Create inserts a new meeting with its attendees and children into the database.
+func (s *SQLTeamMeetingStore) Create(ctx context.Context, meeting *models.TeamMeeting) (int, error) {
+	minutes, err := Encrypt(meeting.Minutes, s.encryptionKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt minutes: %w", err)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `INSERT INTO team_meetings (meeting_date, title, minutes, recorded_by_user_id) VALUES (?, ?, ?, ?) RETURNING meeting_id`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, meeting.MeetingDate, meeting.Title, minutes, meeting.RecordedByUserID).Scan(&id); err != nil {
+		return 0, teamMeetingConstraintError(err)
+	}
+	if err := insertTeamMeetingMembers(ctx, tx, id, meeting); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID fetches a meeting by ID from the database.
+func (s *SQLTeamMeetingStore) GetByID(ctx context.Context, id int) (*models.TeamMeeting, error) {
+	meetings, err := s.queryMeetings(ctx, `SELECT `+teamMeetingColumns+` FROM team_meetings WHERE meeting_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(meetings) == 0 {
+		return nil, ErrNotFound
+	}
+	return &meetings[0], nil
+}
+
+// Update updates an existing meeting in the database.
+func (s *SQLTeamMeetingStore) Update(ctx context.Context, meeting *models.TeamMeeting) error {
+	minutes, err := Encrypt(meeting.Minutes, s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt minutes: %w", err)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `UPDATE team_meetings SET meeting_date = ?, title = ?, minutes = ?, updated_at = CURRENT_TIMESTAMP WHERE meeting_id = ?`
+	result, err := tx.ExecContext(ctx, query, meeting.MeetingDate, meeting.Title, minutes, meeting.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM team_meeting_attendees WHERE meeting_id = ?`, meeting.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM team_meeting_children WHERE meeting_id = ?`, meeting.ID); err != nil {
+		return err
+	}
+	if err := insertTeamMeetingMembers(ctx, tx, meeting.ID, meeting); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Delete deletes a meeting with its action items by ID from the database.
+func (s *SQLTeamMeetingStore) Delete(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM team_meetings WHERE meeting_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetAll fetches all meetings from the database.
+func (s *SQLTeamMeetingStore) GetAll(ctx context.Context) ([]models.TeamMeeting, error) {
+	return s.queryMeetings(ctx, `SELECT `+teamMeetingColumns+` FROM team_meetings ORDER BY meeting_date DESC, meeting_id DESC`)
+}
+
+// GetForChild fetches the meetings concerning a child from the database.
+func (s *SQLTeamMeetingStore) GetForChild(ctx context.Context, childID int) ([]models.TeamMeeting, error) {
+	query := `SELECT ` + teamMeetingColumns + ` FROM team_meetings
+		WHERE meeting_id IN (SELECT meeting_id FROM team_meeting_children WHERE child_id = ?)
+			OR meeting_id IN (SELECT meeting_id FROM meeting_action_items WHERE child_id = ?)
+		ORDER BY meeting_date DESC, meeting_id DESC`
+	return s.queryMeetings(ctx, query, childID, childID)
+}
+
+// CreateActionItem inserts a new action item into the database.
+func (s *SQLTeamMeetingStore) CreateActionItem(ctx context.Context, item *models.ActionItem) (int, error) {
+	description, err := Encrypt(item.Description, s.encryptionKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt action item: %w", err)
+	}
+	query := `INSERT INTO meeting_action_items (meeting_id, description, teacher_id, child_id, due_date) VALUES (?, ?, ?, ?, ?) RETURNING action_item_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, item.MeetingID, description, item.TeacherID, item.ChildID, item.DueDate).Scan(&id); err != nil {
+		return 0, teamMeetingConstraintError(err)
+	}
+	return id, nil
+}
+
+// GetActionItemByID fetches an action item by ID from the database.
+func (s *SQLTeamMeetingStore) GetActionItemByID(ctx context.Context, id int) (*models.ActionItem, error) {
+	items, err := s.queryActionItems(ctx, `SELECT `+actionItemColumns+` FROM meeting_action_items WHERE action_item_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrNotFound
+	}
+	return &items[0], nil
+}
+
+// UpdateActionItem updates the description, assignee, child and due date of an action item in the database.
+func (s *SQLTeamMeetingStore) UpdateActionItem(ctx context.Context, item *models.ActionItem) error {
+	description, err := Encrypt(item.Description, s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt action item: %w", err)
+	}
+	query := `UPDATE meeting_action_items SET description = ?, teacher_id = ?, child_id = ?, due_date = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE action_item_id = ?`
+	result, err := s.db.ExecContext(ctx, query, description, item.TeacherID, item.ChildID, item.DueDate, item.ID)
+	if err != nil {
+		return teamMeetingConstraintError(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SetActionItemCompleted sets the completion time of an action item in the database.
+func (s *SQLTeamMeetingStore) SetActionItemCompleted(ctx context.Context, id int, completedAt *time.Time) error {
+	if completedAt != nil {
+		utc := completedAt.UTC()
+		completedAt = &utc
+	}
+	query := `UPDATE meeting_action_items SET completed_at = ?, updated_at = CURRENT_TIMESTAMP WHERE action_item_id = ?`
+	result, err := s.db.ExecContext(ctx, query, completedAt, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteActionItem deletes an action item by ID from the database.
+func (s *SQLTeamMeetingStore) DeleteActionItem(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM meeting_action_items WHERE action_item_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetOpenActionItems fetches the open action items from the database.
+func (s *SQLTeamMeetingStore) GetOpenActionItems(ctx context.Context) ([]models.ActionItem, error) {
+	query := `SELECT ` + actionItemColumns + ` FROM meeting_action_items WHERE completed_at IS NULL ORDER BY due_date, action_item_id`
+	return s.queryActionItems(ctx, query)
+}
+
+func insertTeamMeetingMembers(ctx context.Context, tx *sql.Tx, meetingID int, meeting *models.TeamMeeting) error {
+	for _, teacherID := range meeting.AttendeeTeacherIDs {
+		// Teachers and children listed twice are stored once.
+		if _, err := tx.ExecContext(ctx, `INSERT INTO team_meeting_attendees (meeting_id, teacher_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, meetingID, teacherID); err != nil {
+			return teamMeetingConstraintError(err)
+		}
+	}
+	for _, childID := range meeting.ChildIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO team_meeting_children (meeting_id, child_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, meetingID, childID); err != nil {
+			return teamMeetingConstraintError(err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLTeamMeetingStore) queryMeetings(ctx context.Context, query string, args ...any) ([]models.TeamMeeting, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	meetings := []models.TeamMeeting{}
+	for rows.Next() {
+		meeting := models.TeamMeeting{AttendeeTeacherIDs: []int{}, ChildIDs: []int{}, ActionItems: []models.ActionItem{}}
+		var minutes string
+		if err := rows.Scan(&meeting.ID, &meeting.MeetingDate, &meeting.Title, &minutes, &meeting.RecordedByUserID,
+			&meeting.CreatedAt, &meeting.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if meeting.Minutes, err = Decrypt(minutes, s.encryptionKey); err != nil {
+			return nil, fmt.Errorf("failed to decrypt minutes: %w", err)
+		}
+		meetings = append(meetings, meeting)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadMembers(ctx, meetings); err != nil {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+// loadMembers fills in the attendees, children and action items of the given meetings.
+func (s *SQLTeamMeetingStore) loadMembers(ctx context.Context, meetings []models.TeamMeeting) error {
+	if len(meetings) == 0 {
+		return nil
+	}
+	byID := make(map[int]*models.TeamMeeting, len(meetings))
+	ids := make([]any, len(meetings))
+	for i := range meetings {
+		byID[meetings[i].ID] = &meetings[i]
+		ids[i] = meetings[i].ID
+	}
+	in := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+	members := []struct {
+		query string
+		add   func(meeting *models.TeamMeeting, id int)
+	}{
+		{`SELECT meeting_id, teacher_id FROM team_meeting_attendees WHERE meeting_id IN (` + in + `) ORDER BY teacher_id`, func(meeting *models.TeamMeeting, id int) {
+			meeting.AttendeeTeacherIDs = append(meeting.AttendeeTeacherIDs, id)
+		}},
+		{`SELECT meeting_id, child_id FROM team_meeting_children WHERE meeting_id IN (` + in + `) ORDER BY child_id`, func(meeting *models.TeamMeeting, id int) {
+			meeting.ChildIDs = append(meeting.ChildIDs, id)
+		}},
+	}
+	for _, member := range members {
+		rows, err := s.db.QueryContext(ctx, member.query, ids...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var meetingID, id int
+			if err := rows.Scan(&meetingID, &id); err != nil {
+				rows.Close() //nolint:errcheck
+				return err
+			}
+			if meeting, ok := byID[meetingID]; ok {
+				member.add(meeting, id)
+			}
+		}
+		err = rows.Err()
+		rows.Close() //nolint:errcheck
+		if err != nil {
+			return err
+		}
+	}
+
+	items, err := s.queryActionItems(ctx, `SELECT `+actionItemColumns+` FROM meeting_action_items WHERE meeting_id IN (`+in+`) ORDER BY due_date, action_item_id`, ids...)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if meeting, ok := byID[item.MeetingID]; ok {
+			meeting.ActionItems = append(meeting.ActionItems, item)
+		}
+	}
+	return nil
+}
+
+func (s *SQLTeamMeetingStore) queryActionItems(ctx context.Context, query string, args ...any) ([]models.ActionItem, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	items := []models.ActionItem{}
+	for rows.Next() {
+		var item models.ActionItem
+		var description string
+		if err := rows.Scan(&item.ID, &item.MeetingID, &description, &item.TeacherID, &item.ChildID, &item.DueDate, &item.CompletedAt,
+			&item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if item.Description, err = Decrypt(description, s.encryptionKey); err != nil {
+			return nil, fmt.Errorf("failed to decrypt action item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func teamMeetingConstraintError(err error) error {
+	if isForeignKeyError(err) {
+		return ErrForeignKeyConstraint
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package data_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLTeamMeetingStore(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte("0123456789abcdef0123456789abcdef"))
+
+	annaID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	require.NoError(t, err)
+	benID, err := dal.Children.Create(t.Context(), &models.Child{FirstName: "Ben", LastName: "Schulz", Birthdate: models.NewDate(2020, time.June, 2)})
+	require.NoError(t, err)
+	mariaID, err := dal.Teachers.Create(t.Context(), &models.Teacher{FirstName: "Maria", LastName: "Schmidt", Username: "maria"})
+	require.NoError(t, err)
+	jonasID, err := dal.Teachers.Create(t.Context(), &models.Teacher{FirstName: "Jonas", LastName: "Weber", Username: "jonas"})
+	require.NoError(t, err)
+
+	store := dal.TeamMeetings
+	meeting := &models.TeamMeeting{
+		MeetingDate:        models.NewDate(2024, time.October, 14),
+		Title:              "Fallbesprechung",
+		Minutes:            "Anna zieht sich in der Gruppe zurück, Gespräch mit den Eltern vereinbart.",
+		AttendeeTeacherIDs: []int{mariaID, jonasID},
+		ChildIDs:           []int{annaID},
+	}
+	meetingID, err := store.Create(t.Context(), meeting)
+	require.NoError(t, err)
+
+	var minutes string
+	require.NoError(t, db.QueryRow("SELECT minutes FROM team_meetings WHERE meeting_id = ?", meetingID).Scan(&minutes))
+	assert.NotContains(t, minutes, "Anna", "minutes must be stored encrypted")
+
+	talkID, err := store.CreateActionItem(t.Context(), &models.ActionItem{MeetingID: meetingID, Description: "Elterngespräch führen", TeacherID: mariaID,
+		ChildID: &annaID, DueDate: models.NewDate(2024, time.October, 21)})
+	require.NoError(t, err)
+	_, err = store.CreateActionItem(t.Context(), &models.ActionItem{MeetingID: meetingID, Description: "Beobachtungsbogen ausfüllen", TeacherID: jonasID,
+		ChildID: &benID, DueDate: models.NewDate(2024, time.October, 18)})
+	require.NoError(t, err)
+	_, err = store.CreateActionItem(t.Context(), &models.ActionItem{MeetingID: meetingID, Description: "Unbekannt", TeacherID: 999,
+		DueDate: models.NewDate(2024, time.October, 18)})
+	assert.ErrorIs(t, err, data.ErrForeignKeyConstraint)
+
+	t.Run("Read With Members And Action Items", func(t *testing.T) {
+		got, err := store.GetByID(t.Context(), meetingID)
+		require.NoError(t, err)
+		assert.Equal(t, meeting.Minutes, got.Minutes)
+		assert.ElementsMatch(t, []int{mariaID, jonasID}, got.AttendeeTeacherIDs)
+		assert.Equal(t, []int{annaID}, got.ChildIDs)
+		require.Len(t, got.ActionItems, 2)
+
+		forBen, err := store.GetForChild(t.Context(), benID)
+		require.NoError(t, err)
+		require.Len(t, forBen, 1, "a child with an action item is concerned by the meeting")
+		assert.Equal(t, meetingID, forBen[0].ID)
+	})
+
+	t.Run("Open Action Items", func(t *testing.T) {
+		open, err := store.GetOpenActionItems(t.Context())
+		require.NoError(t, err)
+		require.Len(t, open, 2)
+		assert.Equal(t, "Beobachtungsbogen ausfüllen", open[0].Description, "the earliest due comes first")
+
+		completedAt := time.Date(2024, time.October, 20, 9, 0, 0, 0, time.UTC)
+		require.NoError(t, store.SetActionItemCompleted(t.Context(), talkID, &completedAt))
+		open, err = store.GetOpenActionItems(t.Context())
+		require.NoError(t, err)
+		assert.Len(t, open, 1)
+		item, err := store.GetActionItemByID(t.Context(), talkID)
+		require.NoError(t, err)
+		require.NotNil(t, item.CompletedAt)
+		assert.True(t, completedAt.Equal(*item.CompletedAt))
+
+		assert.ErrorIs(t, store.SetActionItemCompleted(t.Context(), 999, nil), data.ErrNotFound)
+	})
+
+	t.Run("Update Keeps Action Items", func(t *testing.T) {
+		meeting.ID = meetingID
+		meeting.AttendeeTeacherIDs = []int{mariaID}
+		meeting.ChildIDs = []int{annaID, benID}
+		require.NoError(t, store.Update(t.Context(), meeting))
+		got, err := store.GetByID(t.Context(), meetingID)
+		require.NoError(t, err)
+		assert.Equal(t, []int{mariaID}, got.AttendeeTeacherIDs)
+		assert.ElementsMatch(t, []int{annaID, benID}, got.ChildIDs)
+		assert.Len(t, got.ActionItems, 2)
+	})
+
+	t.Run("Delete Removes Action Items", func(t *testing.T) {
+		require.NoError(t, store.Delete(t.Context(), meetingID))
+		_, err := store.GetByID(t.Context(), meetingID)
+		assert.ErrorIs(t, err, data.ErrNotFound)
+		_, err = store.GetActionItemByID(t.Context(), talkID)
+		assert.ErrorIs(t, err, data.ErrNotFound)
+		assert.ErrorIs(t, store.Delete(t.Context(), meetingID), data.ErrNotFound)
+	})
+}
@@ -0,0 +1,125 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"kitadoc-backend/models"
+)
+
+// pendingTermsQuery selects the current terms version unless the user has accepted it. It runs on every request.
+const pendingTermsQuery = `SELECT v.version, v.terms_of_use, v.privacy_policy, v.change_summary, v.published_by_user_id, v.published_at
+	FROM terms_versions v
+	WHERE v.version = (SELECT MAX(version) FROM terms_versions)
+	AND NOT EXISTS (SELECT 1 FROM terms_acceptances a WHERE a.user_id = ? AND a.version = v.version)`
+
+// TermsStore defines the interface for the versions of the terms of use and privacy policy and their acceptances.
+type TermsStore interface {
+	Publish(ctx context.Context, version *models.TermsVersion) (int, error)
+	GetCurrent(ctx context.Context) (*models.TermsVersion, error)
+	GetAll(ctx context.Context) ([]models.TermsVersion, error)
+	GetPendingForUser(ctx context.Context, userID int) (*models.TermsVersion, error)
+	Accept(ctx context.Context, userID int, version int) (bool, error)
+	GetLastAcceptance(ctx context.Context, userID int) (*models.TermsAcceptance, error)
+}
+
+// SQLTermsStore implements TermsStore using database/sql.
+type SQLTermsStore struct {
+	db *sql.DB
+}
+
+// NewSQLTermsStore creates a new SQLTermsStore.
+func NewSQLTermsStore(db *sql.DB) *SQLTermsStore {
+	return &SQLTermsStore{db: db}
+}
+
+// Publish inserts a new terms version, which becomes the current one, and returns its version number.
+func (s *SQLTermsStore) Publish(ctx context.Context, version *models.TermsVersion) (int, error) {
+	query := `INSERT INTO terms_versions (terms_of_use, privacy_policy, change_summary, published_by_user_id) VALUES (?, ?, ?, ?) RETURNING version`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, version.TermsOfUse, version.PrivacyPolicy, version.ChangeSummary, version.PublishedByUserID).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetCurrent fetches the latest terms version. It returns ErrNotFound if no terms have been published.
+func (s *SQLTermsStore) GetCurrent(ctx context.Context) (*models.TermsVersion, error) {
+	query := `SELECT version, terms_of_use, privacy_policy, change_summary, published_by_user_id, published_at FROM terms_versions ORDER BY version DESC LIMIT 1`
+	return s.queryTermsVersion(ctx, query)
+}
+
+// GetPendingForUser fetches the current terms version if the user has not accepted it yet.
+// It returns ErrNotFound if nothing is pending, also when no terms have been published.
+func (s *SQLTermsStore) GetPendingForUser(ctx context.Context, userID int) (*models.TermsVersion, error) {
+	return s.queryTermsVersion(ctx, pendingTermsQuery, userID)
+}
+
+func (s *SQLTermsStore) queryTermsVersion(ctx context.Context, query string, args ...any) (*models.TermsVersion, error) {
+	version := &models.TermsVersion{}
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&version.Version, &version.TermsOfUse, &version.PrivacyPolicy, &version.ChangeSummary, &version.PublishedByUserID, &version.PublishedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return version, nil
+}
+
+// GetAll fetches all terms versions, newest first.
+func (s *SQLTermsStore) GetAll(ctx context.Context) ([]models.TermsVersion, error) {
+	query := `SELECT version, terms_of_use, privacy_policy, change_summary, published_by_user_id, published_at FROM terms_versions ORDER BY version DESC`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var versions []models.TermsVersion
+	for rows.Next() {
+		version := models.TermsVersion{}
+		if err := rows.Scan(&version.Version, &version.TermsOfUse, &version.PrivacyPolicy, &version.ChangeSummary, &version.PublishedByUserID, &version.PublishedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// Accept records that a user has accepted a terms version. It reports whether the acceptance is new,
+// accepting twice keeps the first timestamp.
+func (s *SQLTermsStore) Accept(ctx context.Context, userID int, version int) (bool, error) {
+	query := `INSERT INTO terms_acceptances (user_id, version) VALUES (?, ?)
+		ON CONFLICT(user_id, version) DO NOTHING`
+	result, err := s.db.ExecContext(ctx, query, userID, version)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetLastAcceptance fetches the acceptance of the highest version a user has accepted.
+// It returns ErrNotFound if the user has never accepted any terms.
+func (s *SQLTermsStore) GetLastAcceptance(ctx context.Context, userID int) (*models.TermsAcceptance, error) {
+	query := `SELECT user_id, version, accepted_at FROM terms_acceptances WHERE user_id = ? ORDER BY version DESC LIMIT 1`
+	acceptance := &models.TermsAcceptance{}
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(&acceptance.UserID, &acceptance.Version, &acceptance.AcceptedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return acceptance, nil
+}
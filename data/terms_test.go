@@ -0,0 +1,102 @@
+package data_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLTermsStore_PublishAndGetCurrent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLTermsStore(db)
+	adminID := 1
+	publishedAt := time.Date(2025, time.May, 2, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO terms_versions (terms_of_use, privacy_policy, change_summary, published_by_user_id) VALUES (?, ?, ?, ?) RETURNING version`)).
+		WithArgs("Nutzung", "Datenschutz", "Neue Aufbewahrungsfristen", &adminID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	version, err := store.Publish(t.Context(), &models.TermsVersion{TermsOfUse: "Nutzung", PrivacyPolicy: "Datenschutz", ChangeSummary: "Neue Aufbewahrungsfristen", PublishedByUserID: &adminID})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	columns := []string{"version", "terms_of_use", "privacy_policy", "change_summary", "published_by_user_id", "published_at"}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, terms_of_use, privacy_policy, change_summary, published_by_user_id, published_at FROM terms_versions ORDER BY version DESC LIMIT 1`)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Nutzung", "Datenschutz", "Neue Aufbewahrungsfristen", adminID, publishedAt))
+	current, err := store.GetCurrent(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, current.Version)
+	assert.Equal(t, "Datenschutz", current.PrivacyPolicy)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, terms_of_use, privacy_policy, change_summary, published_by_user_id, published_at FROM terms_versions ORDER BY version DESC LIMIT 1`)).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = store.GetCurrent(t.Context())
+	assert.ErrorIs(t, err, data.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLTermsStore_GetPendingForUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLTermsStore(db)
+	columns := []string{"version", "terms_of_use", "privacy_policy", "change_summary", "published_by_user_id", "published_at"}
+
+	mock.ExpectQuery(`SELECT v.version, .* FROM terms_versions v WHERE .* NOT EXISTS`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Nutzung", "Datenschutz", "", nil, time.Now()))
+	pending, err := store.GetPendingForUser(t.Context(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pending.Version)
+	assert.Nil(t, pending.PublishedByUserID)
+
+	mock.ExpectQuery(`SELECT v.version, .* FROM terms_versions v WHERE .* NOT EXISTS`).
+		WithArgs(8).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = store.GetPendingForUser(t.Context(), 8)
+	assert.ErrorIs(t, err, data.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLTermsStore_Accept(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLTermsStore(db)
+	query := regexp.QuoteMeta(`INSERT INTO terms_acceptances (user_id, version) VALUES (?, ?)`)
+
+	mock.ExpectExec(query).WithArgs(7, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	accepted, err := store.Accept(t.Context(), 7, 2)
+	assert.NoError(t, err)
+	assert.True(t, accepted)
+
+	mock.ExpectExec(query).WithArgs(7, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+	accepted, err = store.Accept(t.Context(), 7, 2)
+	assert.NoError(t, err)
+	assert.False(t, accepted, "accepting twice keeps the first acceptance")
+
+	acceptedAt := time.Date(2025, time.May, 3, 7, 30, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT user_id, version, accepted_at FROM terms_acceptances WHERE user_id = ? ORDER BY version DESC LIMIT 1`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "version", "accepted_at"}).AddRow(7, 2, acceptedAt))
+	acceptance, err := store.GetLastAcceptance(t.Context(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.TermsAcceptance{UserID: 7, Version: 2, AcceptedAt: acceptedAt}, acceptance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
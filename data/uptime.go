@@ -0,0 +1,180 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// healthChecksInRangeQuery selects the checks of the days shown in the uptime history.
+const healthChecksInRangeQuery = `SELECT check_id, checked_at, healthy, latency_ms, error FROM health_checks WHERE checked_at >= ? AND checked_at < ? ORDER BY checked_at ASC`
+
+// UptimeStore defines the interface for the recorded health checks and the incident annotations.
+type UptimeStore interface {
+	Ping(ctx context.Context) error
+	RecordCheck(ctx context.Context, check *models.HealthCheck) error
+	GetChecks(ctx context.Context, from time.Time, to time.Time) ([]models.HealthCheck, error)
+	GetFirstCheck(ctx context.Context) (*models.HealthCheck, error)
+	GetLastCheck(ctx context.Context) (*models.HealthCheck, error)
+	DeleteChecksBefore(ctx context.Context, before time.Time) (int64, error)
+	CreateIncident(ctx context.Context, incident *models.UptimeIncident) (int, error)
+	GetIncidentByID(ctx context.Context, id int) (*models.UptimeIncident, error)
+	GetIncidents(ctx context.Context, from time.Time, to time.Time) ([]models.UptimeIncident, error)
+	DeleteIncident(ctx context.Context, id int) error
+}
+
+// SQLUptimeStore implements UptimeStore using database/sql.
+type SQLUptimeStore struct {
+	db *sql.DB
+}
+
+// NewSQLUptimeStore creates a new SQLUptimeStore.
+func NewSQLUptimeStore(db *sql.DB) *SQLUptimeStore {
+	return &SQLUptimeStore{db: db}
+}
+
+// Ping runs a trivial query to check that the database answers.
+func (s *SQLUptimeStore) Ping(ctx context.Context) error {
+	var one int
+	return s.db.QueryRowContext(ctx, `SELECT 1`).Scan(&one)
+}
+
+// RecordCheck inserts the result of a health check.
+func (s *SQLUptimeStore) RecordCheck(ctx context.Context, check *models.HealthCheck) error {
+	query := `INSERT INTO health_checks (checked_at, healthy, latency_ms, error) VALUES (?, ?, ?, ?) RETURNING check_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, check.CheckedAt.UTC(), check.Healthy, check.LatencyMS, check.Error).Scan(&id); err != nil {
+		return err
+	}
+	check.ID = id
+	return nil
+}
+
+// GetChecks fetches the checks from from until before to, oldest first.
+func (s *SQLUptimeStore) GetChecks(ctx context.Context, from time.Time, to time.Time) ([]models.HealthCheck, error) {
+	rows, err := s.db.QueryContext(ctx, healthChecksInRangeQuery, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var checks []models.HealthCheck
+	for rows.Next() {
+		check := models.HealthCheck{}
+		if err := rows.Scan(&check.ID, &check.CheckedAt, &check.Healthy, &check.LatencyMS, &check.Error); err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return checks, nil
+}
+
+// GetFirstCheck fetches the oldest recorded check. It returns ErrNotFound if no check has been recorded.
+func (s *SQLUptimeStore) GetFirstCheck(ctx context.Context) (*models.HealthCheck, error) {
+	return s.queryCheck(ctx, `SELECT check_id, checked_at, healthy, latency_ms, error FROM health_checks ORDER BY checked_at ASC LIMIT 1`)
+}
+
+// GetLastCheck fetches the latest recorded check. It returns ErrNotFound if no check has been recorded.
+func (s *SQLUptimeStore) GetLastCheck(ctx context.Context) (*models.HealthCheck, error) {
+	return s.queryCheck(ctx, `SELECT check_id, checked_at, healthy, latency_ms, error FROM health_checks ORDER BY checked_at DESC LIMIT 1`)
+}
+
+func (s *SQLUptimeStore) queryCheck(ctx context.Context, query string) (*models.HealthCheck, error) {
+	check := &models.HealthCheck{}
+	err := s.db.QueryRowContext(ctx, query).Scan(&check.ID, &check.CheckedAt, &check.Healthy, &check.LatencyMS, &check.Error)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return check, nil
+}
+
+// DeleteChecksBefore deletes the checks older than before and returns how many were deleted.
+func (s *SQLUptimeStore) DeleteChecksBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM health_checks WHERE checked_at < ?`, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CreateIncident inserts an incident annotation and returns its ID.
+func (s *SQLUptimeStore) CreateIncident(ctx context.Context, incident *models.UptimeIncident) (int, error) {
+	query := `INSERT INTO uptime_incidents (started_at, ended_at, note, created_by_user_id) VALUES (?, ?, ?, ?) RETURNING incident_id`
+	var endedAt *time.Time
+	if incident.EndedAt != nil {
+		utc := incident.EndedAt.UTC()
+		endedAt = &utc
+	}
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, incident.StartedAt.UTC(), endedAt, incident.Note, incident.CreatedByUserID).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetIncidentByID fetches an incident annotation. It returns ErrNotFound if it does not exist.
+func (s *SQLUptimeStore) GetIncidentByID(ctx context.Context, id int) (*models.UptimeIncident, error) {
+	query := `SELECT incident_id, started_at, ended_at, note, created_by_user_id, created_at FROM uptime_incidents WHERE incident_id = ?`
+	incident := &models.UptimeIncident{}
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&incident.ID, &incident.StartedAt, &incident.EndedAt, &incident.Note, &incident.CreatedByUserID, &incident.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return incident, nil
+}
+
+// GetIncidents fetches the incidents overlapping the time from from until before to, oldest first.
+func (s *SQLUptimeStore) GetIncidents(ctx context.Context, from time.Time, to time.Time) ([]models.UptimeIncident, error) {
+	query := `SELECT incident_id, started_at, ended_at, note, created_by_user_id, created_at FROM uptime_incidents
+		WHERE started_at < ? AND (ended_at IS NULL OR ended_at >= ?) ORDER BY started_at ASC, incident_id ASC`
+	rows, err := s.db.QueryContext(ctx, query, to.UTC(), from.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var incidents []models.UptimeIncident
+	for rows.Next() {
+		incident := models.UptimeIncident{}
+		if err := rows.Scan(&incident.ID, &incident.StartedAt, &incident.EndedAt, &incident.Note, &incident.CreatedByUserID, &incident.CreatedAt); err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, incident)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return incidents, nil
+}
+
+// DeleteIncident deletes an incident annotation. It returns ErrNotFound if it does not exist.
+func (s *SQLUptimeStore) DeleteIncident(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM uptime_incidents WHERE incident_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package data_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUptimeStore(t *testing.T) *data.SQLUptimeStore {
+	db, err := sql.Open("sqlite", "file:"+t.TempDir()+"/test.db?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	require.NoError(t, data.MigrateDB(db, migrations.Files))
+	return data.NewSQLUptimeStore(db)
+}
+
+func TestSQLUptimeStore_Checks(t *testing.T) {
+	store := newUptimeStore(t)
+	require.NoError(t, store.Ping(context.Background()))
+	_, err := store.GetLastCheck(t.Context())
+	assert.ErrorIs(t, err, data.ErrNotFound)
+
+	start := time.Date(2025, time.May, 2, 8, 0, 0, 0, time.UTC)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	for i, healthy := range []bool{true, false, true} {
+		check := &models.HealthCheck{CheckedAt: start.Add(time.Duration(i) * time.Minute).In(berlin), Healthy: healthy, LatencyMS: 2}
+		require.NoError(t, store.RecordCheck(t.Context(), check))
+		assert.NotZero(t, check.ID)
+	}
+
+	checks, err := store.GetChecks(t.Context(), start.Add(time.Minute), start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+	assert.False(t, checks[0].Healthy)
+	assert.True(t, checks[0].CheckedAt.Equal(start.Add(time.Minute)))
+
+	first, err := store.GetFirstCheck(t.Context())
+	require.NoError(t, err)
+	assert.True(t, first.CheckedAt.Equal(start))
+	last, err := store.GetLastCheck(t.Context())
+	require.NoError(t, err)
+	assert.True(t, last.CheckedAt.Equal(start.Add(2*time.Minute)))
+
+	deleted, err := store.DeleteChecksBefore(t.Context(), start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestSQLUptimeStore_Incidents(t *testing.T) {
+	store := newUptimeStore(t)
+	start := time.Date(2025, time.May, 2, 8, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	id, err := store.CreateIncident(t.Context(), &models.UptimeIncident{StartedAt: start, EndedAt: &end, Note: "Stromausfall im Rechenzentrum"})
+	require.NoError(t, err)
+	ongoing, err := store.CreateIncident(t.Context(), &models.UptimeIncident{StartedAt: start.AddDate(0, 0, 3), Note: "Langsame Antworten"})
+	require.NoError(t, err)
+
+	incident, err := store.GetIncidentByID(t.Context(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "Stromausfall im Rechenzentrum", incident.Note)
+	require.NotNil(t, incident.EndedAt)
+	assert.True(t, incident.EndedAt.Equal(end))
+
+	incidents, err := store.GetIncidents(t.Context(), start.Add(time.Hour), start.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, incidents, 1)
+	assert.Equal(t, id, incidents[0].ID)
+	incidents, err = store.GetIncidents(t.Context(), start.AddDate(0, 0, 5), start.AddDate(0, 0, 6))
+	require.NoError(t, err)
+	require.Len(t, incidents, 1)
+	assert.Equal(t, ongoing, incidents[0].ID)
+
+	require.NoError(t, store.DeleteIncident(t.Context(), id))
+	assert.ErrorIs(t, store.DeleteIncident(t.Context(), id), data.ErrNotFound)
+}
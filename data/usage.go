@@ -0,0 +1,32 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+
+	"kitadoc-backend/models"
+)
+
+// queryUsageCounts runs an aggregate query whose rows start with the ID of the counted reference data,
+// followed by the counts that fields returns the destinations for.
+func queryUsageCounts(ctx context.Context, db *sql.DB, query string, fields func(usage *models.UsageCounts) []any) (map[int]models.UsageCounts, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	counts := make(map[int]models.UsageCounts)
+	for rows.Next() {
+		var id int
+		var usage models.UsageCounts
+		if err := rows.Scan(append([]any{&id}, fields(&usage)...)...); err != nil {
+			return nil, err
+		}
+		counts[id] = usage
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -11,13 +12,13 @@ import (
 
 // UserStore defines the interface for User data operations.
 type UserStore interface {
-	Create(user *models.User) (int, error)
-	GetByID(id int) (*models.User, error)
-	Update(user *models.User) error
-	Delete(id int) error
-	GetUserByUsername(username string) (*models.User, error)
-	GetAll() ([]*models.User, error)
-	UpdatePassword(id int, passwordHash string) error
+	Create(ctx context.Context, user *models.User) (int, error)
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, id int) error
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	GetAll(ctx context.Context) ([]*models.User, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
 }
 
 // SQLUserStore implements UserStore using database/sql.
@@ -106,30 +107,25 @@ func fromUserDB(dbUser *models.UserDB, key []byte) (*models.User, error) {
 }
 
 // Create inserts a new user into the database.
-func (s *SQLUserStore) Create(user *models.User) (int, error) {
+func (s *SQLUserStore) Create(ctx context.Context, user *models.User) (int, error) {
 	dbUser, err := toUserDB(user, s.encryptionKey)
 	if err != nil {
 		return 0, err
 	}
 
-	query := `INSERT INTO users (username, username_hmac, password_hash, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`
-	result, err := s.db.Exec(query, dbUser.Username, dbUser.UsernameHMAC, dbUser.PasswordHash, dbUser.Role, user.CreatedAt, user.UpdatedAt)
-	if err != nil {
+	query := `INSERT INTO users (username, username_hmac, password_hash, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING user_id`
+	var id int
+	if err := s.db.QueryRowContext(ctx, query, dbUser.Username, dbUser.UsernameHMAC, dbUser.PasswordHash, dbUser.Role, user.CreatedAt, user.UpdatedAt).Scan(&id); err != nil {
 		logger.GetGlobalLogger().Errorf("Error inserting user: %v", err)
 		return -1, err
 	}
-	id, err := result.LastInsertId()
-	if err != nil {
-		logger.GetGlobalLogger().Errorf("Error getting last insert ID: %v", err)
-		return -1, err
-	}
-	return int(id), nil
+	return id, nil
 }
 
 // GetByID fetches a user by ID from the database.
-func (s *SQLUserStore) GetByID(id int) (*models.User, error) {
+func (s *SQLUserStore) GetByID(ctx context.Context, id int) (*models.User, error) {
 	query := `SELECT user_id, username, password_hash, role, created_at, updated_at FROM users WHERE user_id = ?`
-	row := s.db.QueryRow(query, id)
+	row := s.db.QueryRowContext(ctx, query, id)
 	dbUser := &models.UserDB{}
 	err := row.Scan(&dbUser.ID, &dbUser.Username, &dbUser.PasswordHash, &dbUser.Role, &dbUser.CreatedAt, &dbUser.UpdatedAt)
 	if err != nil {
@@ -144,14 +140,14 @@ func (s *SQLUserStore) GetByID(id int) (*models.User, error) {
 }
 
 // Update updates an existing user in the database.
-func (s *SQLUserStore) Update(user *models.User) error {
+func (s *SQLUserStore) Update(ctx context.Context, user *models.User) error {
 	dbUser, err := toUserDB(user, s.encryptionKey)
 	if err != nil {
 		return err
 	}
 
 	query := `UPDATE users SET username = ?, username_hmac = ?, password_hash = ?, role = ?, updated_at = ? WHERE user_id = ?`
-	result, err := s.db.Exec(query, dbUser.Username, dbUser.UsernameHMAC, dbUser.PasswordHash, dbUser.Role, user.UpdatedAt, dbUser.ID)
+	result, err := s.db.ExecContext(ctx, query, dbUser.Username, dbUser.UsernameHMAC, dbUser.PasswordHash, dbUser.Role, user.UpdatedAt, dbUser.ID)
 	if err != nil {
 		return err
 	}
@@ -166,9 +162,9 @@ func (s *SQLUserStore) Update(user *models.User) error {
 }
 
 // Delete deletes a user by ID from the database.
-func (s *SQLUserStore) Delete(id int) error {
+func (s *SQLUserStore) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM users WHERE user_id = ?`
-	result, err := s.db.Exec(query, id)
+	result, err := s.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -183,14 +179,14 @@ func (s *SQLUserStore) Delete(id int) error {
 }
 
 // GetUserByUsername fetches a user by username from the database.
-func (s *SQLUserStore) GetUserByUsername(username string) (*models.User, error) {
+func (s *SQLUserStore) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
 	usernameHMAC, err := LookupHash(username, s.encryptionKey)
 	if err != nil {
 		return nil, err
 	}
 
 	query := `SELECT user_id, username, password_hash, role, created_at, updated_at FROM users WHERE username_hmac = ?`
-	row := s.db.QueryRow(query, usernameHMAC)
+	row := s.db.QueryRowContext(ctx, query, usernameHMAC)
 	dbUser := &models.UserDB{}
 	err = row.Scan(&dbUser.ID, &dbUser.Username, &dbUser.PasswordHash, &dbUser.Role, &dbUser.CreatedAt, &dbUser.UpdatedAt)
 	if err != nil {
@@ -204,9 +200,9 @@ func (s *SQLUserStore) GetUserByUsername(username string) (*models.User, error)
 }
 
 // GetAll fetches all users from the database.
-func (s *SQLUserStore) GetAll() ([]*models.User, error) {
-	query := `SELECT user_id, username, password_hash, role, created_at, updated_at FROM users`
-	rows, err := s.db.Query(query)
+func (s *SQLUserStore) GetAll(ctx context.Context) ([]*models.User, error) {
+	query := `SELECT user_id, username, password_hash, role, created_at, updated_at FROM users ORDER BY user_id`
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -235,10 +231,10 @@ func (s *SQLUserStore) GetAll() ([]*models.User, error) {
 }
 
 // UpdatePassword updates a user's password in the database.
-func (s *SQLUserStore) UpdatePassword(id int, passwordHash string) error {
+func (s *SQLUserStore) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
 	query := `UPDATE users SET password_hash = ? WHERE user_id = ?`
 	logger.GetGlobalLogger().Infof("Updating password for user ID %d", id)
-	result, err := s.db.Exec(query, passwordHash, id)
+	result, err := s.db.ExecContext(ctx, query, passwordHash, id)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error updating password for user ID %d: %v", id, err)
 		return err
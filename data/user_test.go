@@ -45,11 +45,11 @@ func TestSQLUserStore_Create(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		usernameHMAC, _ := data.LookupHash(user.Username, key) // nolint:errcheck
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO users (username, username_hmac, password_hash, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO users (username, username_hmac, password_hash, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING user_id`)).
 			WithArgs(sqlmock.AnyArg(), usernameHMAC, user.PasswordHash, user.Role, user.CreatedAt, user.UpdatedAt).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
 
-		id, err := store.Create(user)
+		id, err := store.Create(t.Context(), user)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, id)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -57,11 +57,11 @@ func TestSQLUserStore_Create(t *testing.T) {
 
 	t.Run("db error", func(t *testing.T) {
 		usernameHMAC, _ := data.LookupHash(user.Username, key) // nolint:errcheck
-		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO users (username, username_hmac, password_hash, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO users (username, username_hmac, password_hash, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?) RETURNING user_id`)).
 			WithArgs(sqlmock.AnyArg(), usernameHMAC, user.PasswordHash, user.Role, user.CreatedAt, user.UpdatedAt).
 			WillReturnError(errors.New("db error"))
 
-		id, err := store.Create(user)
+		id, err := store.Create(t.Context(), user)
 		assert.Error(t, err)
 		assert.Equal(t, -1, id)
 		assert.Contains(t, err.Error(), "db error")
@@ -99,7 +99,7 @@ func TestSQLUserStore_GetByID(t *testing.T) {
 			WithArgs(userID).
 			WillReturnRows(rows)
 
-		user, err := store.GetByID(userID)
+		user, err := store.GetByID(t.Context(), userID)
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
 		assert.Equal(t, expectedUser.ID, user.ID)
@@ -116,7 +116,7 @@ func TestSQLUserStore_GetByID(t *testing.T) {
 			WithArgs(userID).
 			WillReturnError(sql.ErrNoRows)
 
-		user, err := store.GetByID(userID)
+		user, err := store.GetByID(t.Context(), userID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.Nil(t, user)
@@ -128,7 +128,7 @@ func TestSQLUserStore_GetByID(t *testing.T) {
 			WithArgs(userID).
 			WillReturnError(errors.New("db error"))
 
-		user, err := store.GetByID(userID)
+		user, err := store.GetByID(t.Context(), userID)
 		assert.Error(t, err)
 		assert.Nil(t, user)
 		assert.Contains(t, err.Error(), "db error")
@@ -160,7 +160,7 @@ func TestSQLUserStore_Update(t *testing.T) {
 			WithArgs(sqlmock.AnyArg(), usernameHMAC, user.PasswordHash, user.Role, user.UpdatedAt, user.ID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Update(user)
+		err := store.Update(t.Context(), user)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -170,7 +170,7 @@ func TestSQLUserStore_Update(t *testing.T) {
 			WithArgs(sqlmock.AnyArg(), usernameHMAC, user.PasswordHash, user.Role, user.UpdatedAt, user.ID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := store.Update(user)
+		err := store.Update(t.Context(), user)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -181,7 +181,7 @@ func TestSQLUserStore_Update(t *testing.T) {
 			WithArgs(sqlmock.AnyArg(), usernameHMAC, user.PasswordHash, user.Role, user.UpdatedAt, user.ID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Update(user)
+		err := store.Update(t.Context(), user)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -204,7 +204,7 @@ func TestSQLUserStore_Delete(t *testing.T) {
 			WithArgs(userID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := store.Delete(userID)
+		err := store.Delete(t.Context(), userID)
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
@@ -214,7 +214,7 @@ func TestSQLUserStore_Delete(t *testing.T) {
 			WithArgs(userID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := store.Delete(userID)
+		err := store.Delete(t.Context(), userID)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -225,7 +225,7 @@ func TestSQLUserStore_Delete(t *testing.T) {
 			WithArgs(userID).
 			WillReturnError(errors.New("db error"))
 
-		err := store.Delete(userID)
+		err := store.Delete(t.Context(), userID)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "db error")
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -263,7 +263,7 @@ func TestSQLUserStore_GetUserByUsername(t *testing.T) {
 			WithArgs(usernameHMAC).
 			WillReturnRows(rows)
 
-		user, err := store.GetUserByUsername(username)
+		user, err := store.GetUserByUsername(t.Context(), username)
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
 		assert.Equal(t, expectedUser.ID, user.ID)
@@ -280,7 +280,7 @@ func TestSQLUserStore_GetUserByUsername(t *testing.T) {
 			WithArgs(usernameHMAC).
 			WillReturnError(sql.ErrNoRows)
 
-		user, err := store.GetUserByUsername(username)
+		user, err := store.GetUserByUsername(t.Context(), username)
 		assert.Error(t, err)
 		assert.Equal(t, data.ErrNotFound, err)
 		assert.Nil(t, user)
@@ -292,7 +292,7 @@ func TestSQLUserStore_GetUserByUsername(t *testing.T) {
 			WithArgs(usernameHMAC).
 			WillReturnError(errors.New("db error"))
 
-		user, err := store.GetUserByUsername(username)
+		user, err := store.GetUserByUsername(t.Context(), username)
 		assert.Error(t, err)
 		assert.Nil(t, user)
 		assert.Contains(t, err.Error(), "db error")
@@ -0,0 +1,61 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// ValidationRulesStore defines the interface for ValidationRules data operations.
+type ValidationRulesStore interface {
+	Get(ctx context.Context) (*models.ValidationRules, error)
+	Upsert(ctx context.Context, rules *models.ValidationRules) error
+}
+
+// SQLValidationRulesStore implements ValidationRulesStore using database/sql.
+type SQLValidationRulesStore struct {
+	db *sql.DB
+}
+
+// NewSQLValidationRulesStore creates a new SQLValidationRulesStore.
+func NewSQLValidationRulesStore(db *sql.DB) *SQLValidationRulesStore {
+	return &SQLValidationRulesStore{db: db}
+}
+
+// Get fetches the configured validation rules. It returns ErrNotFound if none have been stored yet.
+func (s *SQLValidationRulesStore) Get(ctx context.Context) (*models.ValidationRules, error) {
+	query := `SELECT rules, updated_at FROM validation_rules WHERE id = 1`
+	row := s.db.QueryRowContext(ctx, query)
+
+	var rulesJSON string
+	var updatedAt time.Time
+	err := row.Scan(&rulesJSON, &updatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	rules := &models.ValidationRules{}
+	if err := json.Unmarshal([]byte(rulesJSON), rules); err != nil {
+		return nil, err
+	}
+	rules.UpdatedAt = updatedAt
+	return rules, nil
+}
+
+// Upsert stores the validation rules, replacing any previous configuration.
+func (s *SQLValidationRulesStore) Upsert(ctx context.Context, rules *models.ValidationRules) error {
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO validation_rules (id, rules, updated_at) VALUES (1, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET rules = excluded.rules, updated_at = CURRENT_TIMESTAMP`
+	_, err = s.db.ExecContext(ctx, query, string(rulesJSON))
+	return err
+}
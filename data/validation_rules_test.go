@@ -0,0 +1,47 @@
+package data_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLValidationRulesStore_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close() //nolint:errcheck
+
+	store := data.NewSQLValidationRulesStore(db)
+	query := regexp.QuoteMeta(`SELECT rules, updated_at FROM validation_rules WHERE id = 1`)
+
+	t.Run("success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"rules", "updated_at"}).
+				AddRow(`{"max_observation_age_days":30,"required_tags_by_category":{"2":["#sprache"]}}`, now))
+
+		rules, err := store.Get(t.Context())
+		assert.NoError(t, err)
+		assert.Equal(t, 30, rules.MaxObservationAgeDays)
+		assert.Equal(t, []string{"#sprache"}, rules.RequiredTagsByCategory[2])
+		assert.Equal(t, now, rules.UpdatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(sql.ErrNoRows)
+
+		rules, err := store.Get(t.Context())
+		assert.ErrorIs(t, err, data.ErrNotFound)
+		assert.Nil(t, rules)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
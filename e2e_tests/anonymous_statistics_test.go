@@ -0,0 +1,57 @@
+package e2e_test
+
+import (
+	"net/http"
+	"testing"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestAnonymousStatisticsEndpoint(t *testing.T) {
+	h := testsupport.New(t)
+	admin := h.MustCreateUser("admin")
+	token := h.MustLogin(admin.Username)
+	h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+
+	export := func(reauthenticationToken string) *http.Response {
+		request, err := http.NewRequest(http.MethodGet, h.Server.URL+"/api/v1/statistics/anonymous", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+		if reauthenticationToken != "" {
+			request.Header.Set(middleware.ReauthenticationHeader, reauthenticationToken)
+		}
+		resp, err := h.Server.Client().Do(request)
+		if err != nil {
+			t.Fatalf("Failed to export anonymous statistics: %v", err)
+		}
+		return resp
+	}
+
+	resp := export("")
+	readResponseBody(t, resp)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected the export to require a password confirmation, got %d", resp.StatusCode)
+	}
+
+	var reauthentication struct {
+		Token string `json:"reauthentication_token"`
+	}
+	h.MustDo(http.MethodPost, "/api/v1/auth/reauthenticate", token, map[string]string{"password": testsupport.Password}, http.StatusOK, &reauthentication)
+	resp = export(reauthentication.Token)
+	if body := readResponseBody(t, resp); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the export, got %d: %s", resp.StatusCode, body)
+	}
+
+	var entries []models.AuditLogEntry
+	h.MustDo(http.MethodGet, "/api/v1/audit-log", token, nil, http.StatusOK, &entries)
+	for _, entry := range entries {
+		if entry.Action == models.AuditActionExportAnonymousStatistics && entry.ActorUserID != nil && *entry.ActorUserID == admin.ID {
+			return
+		}
+	}
+	t.Errorf("Expected the export in the audit log, got %+v", entries)
+}
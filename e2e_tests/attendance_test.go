@@ -0,0 +1,101 @@
+package e2e_test
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestAttendanceEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	token := h.MustLogin(teacher.Username)
+	child := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+	qrURL := fmt.Sprintf("/api/v1/children/%d/checkin-qr", child.ID)
+	// Morning in the facility, so that all scans are on the same day
+	year, month, day := h.Now().In(models.FacilityLocation()).Date()
+	h.SetClock(time.Date(year, month, day, 8, 0, 0, 0, models.FacilityLocation()))
+
+	fetchCode := func() string {
+		resp := h.Do(http.MethodGet, qrURL, token, nil)
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/png" {
+			t.Fatalf("Expected the QR code, got %d: %s", resp.StatusCode, body)
+		}
+		if _, err := png.Decode(bytes.NewReader(body)); err != nil {
+			t.Fatalf("Expected a PNG: %v", err)
+		}
+		code, err := h.DAL.Attendance.GetCheckInCode(t.Context(), child.ID)
+		if err != nil {
+			t.Fatalf("Failed to fetch check-in code: %v", err)
+		}
+		return models.CheckInCodePrefix + code
+	}
+	code := fetchCode()
+	if again := fetchCode(); again != code {
+		t.Fatalf("Expected the QR code to be stable, got %s and %s", code, again)
+	}
+
+	scan := func(code string, wantStatus int) models.AttendanceRecord {
+		var record models.AttendanceRecord
+		h.MustDo(http.MethodPost, "/api/v1/attendance/check-in", token,
+			map[string]any{"code": code, "device_name": "Tablet Eingang"}, wantStatus, &record)
+		return record
+	}
+
+	t.Run("Scans Toggle Arrival And Departure", func(t *testing.T) {
+		arrival := scan(code, http.StatusCreated)
+		if arrival.EventType != models.AttendanceArrival || arrival.Source != models.AttendanceSourceQRScan ||
+			arrival.ChildFirstName != "Anna" || arrival.DeviceName == nil || *arrival.DeviceName != "Tablet Eingang" {
+			t.Fatalf("Expected an arrival scanned at the tablet, got %+v", arrival)
+		}
+		if repeated := scan(code, http.StatusOK); repeated.ID != arrival.ID {
+			t.Errorf("Expected the repeated scan to return the arrival, got %+v", repeated)
+		}
+
+		h.SetClock(h.Now().Add(5 * time.Minute))
+		departure := scan(code, http.StatusCreated)
+		if departure.EventType != models.AttendanceDeparture {
+			t.Errorf("Expected a departure, got %+v", departure)
+		}
+
+		var records []models.AttendanceRecord
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/children/%d/attendance", child.ID), token, nil, http.StatusOK, &records)
+		if len(records) != 2 || records[0].ID != arrival.ID || records[1].ID != departure.ID {
+			t.Errorf("Expected the arrival and the departure, got %+v", records)
+		}
+		h.MustDo(http.MethodGet, "/api/v1/attendance?date="+models.Today(h.Now()).String(), token, nil, http.StatusOK, &records)
+		if len(records) != 2 {
+			t.Errorf("Expected the records of the day, got %+v", records)
+		}
+	})
+
+	t.Run("Manual Records", func(t *testing.T) {
+		var record models.AttendanceRecord
+		url := fmt.Sprintf("/api/v1/children/%d/attendance", child.ID)
+		h.MustDo(http.MethodPost, url, token, map[string]any{"event_type": "arrival", "recorded_at": h.Now().Add(-time.Minute)}, http.StatusCreated, &record)
+		if record.Source != models.AttendanceSourceManual || record.DeviceName != nil {
+			t.Errorf("Expected a manual record, got %+v", record)
+		}
+		h.MustDo(http.MethodPost, url, token, map[string]any{"event_type": "arrival", "recorded_at": h.Now().Add(time.Hour)}, http.StatusBadRequest, nil)
+		h.MustDo(http.MethodPost, url, token, map[string]any{"event_type": "nap", "recorded_at": h.Now()}, http.StatusBadRequest, nil)
+
+		h.MustDo(http.MethodDelete, fmt.Sprintf("/api/v1/attendance/%d", record.ID), token, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/attendance/%d", record.ID), token, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("Revoked Codes Are Rejected", func(t *testing.T) {
+		scan("kitadoc-checkin:unknown", http.StatusNotFound)
+		h.MustDo(http.MethodDelete, qrURL, token, nil, http.StatusNoContent, nil)
+		scan(code, http.StatusNotFound)
+		if renewed := fetchCode(); renewed == code {
+			t.Errorf("Expected a new code after revoking, got %s", renewed)
+		}
+	})
+}
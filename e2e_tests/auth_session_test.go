@@ -0,0 +1,119 @@
+package e2e_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestAuthSessionEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	login := func(t *testing.T, username string) models.AuthTokens {
+		t.Helper()
+		var tokens models.AuthTokens
+		h.MustDo(http.MethodPost, "/api/v1/auth/login", "", map[string]string{"username": username, "password": testsupport.Password}, http.StatusOK, &tokens)
+		return tokens
+	}
+	refresh := func(refreshToken string, wantStatus int, out any) {
+		h.MustDo(http.MethodPost, "/api/v1/auth/refresh", "", map[string]string{"refresh_token": refreshToken}, wantStatus, out)
+	}
+
+	t.Run("Login Returns A Short-Lived Access Token And A Refresh Token", func(t *testing.T) {
+		user := h.MustCreateUser("teacher")
+		tokens := login(t, user.Username)
+		if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+			t.Fatalf("Expected an access and a refresh token, got %+v", tokens)
+		}
+		if tokens.ExpiresAt.After(time.Now().Add(16*time.Minute)) || !tokens.RefreshTokenExpiresAt.After(tokens.ExpiresAt) {
+			t.Errorf("Expected an access token for 15 minutes and a longer session, got %+v", tokens)
+		}
+		h.MustDo(http.MethodGet, "/api/v1/auth/me", tokens.AccessToken, nil, http.StatusOK, nil)
+	})
+
+	t.Run("Refresh Tokens Can Be Used Once", func(t *testing.T) {
+		user := h.MustCreateUser("teacher")
+		tokens := login(t, user.Username)
+
+		var refreshed models.AuthTokens
+		refresh(tokens.RefreshToken, http.StatusOK, &refreshed)
+		if refreshed.RefreshToken == tokens.RefreshToken || !refreshed.RefreshTokenExpiresAt.Equal(tokens.RefreshTokenExpiresAt) {
+			t.Errorf("Expected a new refresh token for the same session, got %+v", refreshed)
+		}
+		h.MustDo(http.MethodGet, "/api/v1/auth/me", refreshed.AccessToken, nil, http.StatusOK, nil)
+		refresh(tokens.RefreshToken, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("Logout Revokes The Session", func(t *testing.T) {
+		user := h.MustCreateUser("teacher")
+		tokens := login(t, user.Username)
+		other := login(t, user.Username)
+
+		h.MustDo(http.MethodPost, "/api/v1/auth/logout", tokens.AccessToken, nil, http.StatusOK, nil)
+		h.MustDo(http.MethodGet, "/api/v1/auth/me", tokens.AccessToken, nil, http.StatusUnauthorized, nil)
+		refresh(tokens.RefreshToken, http.StatusUnauthorized, nil)
+		h.MustDo(http.MethodGet, "/api/v1/auth/me", other.AccessToken, nil, http.StatusOK, nil)
+	})
+
+	t.Run("Demo Mode Survives A Refresh", func(t *testing.T) {
+		admin := h.MustCreateUser("admin")
+		tokens := login(t, admin.Username)
+		h.MustDo(http.MethodPost, "/api/v1/demo-mode", tokens.AccessToken, nil, http.StatusOK, nil)
+
+		var refreshed models.AuthTokens
+		refresh(tokens.RefreshToken, http.StatusOK, &refreshed)
+		t.Cleanup(func() {
+			h.MustDo(http.MethodDelete, "/api/v1/demo-mode", refreshed.AccessToken, nil, http.StatusOK, nil)
+		})
+
+		var status models.DemoModeStatus
+		h.MustDo(http.MethodGet, "/api/v1/demo-mode", refreshed.AccessToken, nil, http.StatusOK, &status)
+		if !status.Enabled {
+			t.Errorf("Expected demo mode to stay enabled after the refresh, got %+v", status)
+		}
+		resp := h.Do(http.MethodGet, "/api/v1/children", refreshed.AccessToken, nil)
+		resp.Body.Close() //nolint:errcheck
+		if resp.Header.Get("X-Demo-Mode") != "true" {
+			t.Errorf("Expected requests with the refreshed token to be served from the demo dataset")
+		}
+	})
+
+	t.Run("Tokens Without A Session Are Refused", func(t *testing.T) {
+		user := h.MustCreateUser("teacher")
+		tokens := login(t, user.Username)
+		legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{
+			UserID:           user.ID,
+			Role:             data.RoleTeacher,
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour))},
+		}).SignedString([]byte(h.Config.Server.JWTSecret))
+		if err != nil {
+			t.Fatalf("Failed to sign a token without a session: %v", err)
+		}
+
+		h.MustDo(http.MethodPost, "/api/v1/auth/logout", tokens.AccessToken, nil, http.StatusOK, nil)
+		h.MustDo(http.MethodGet, "/api/v1/auth/me", legacy, nil, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("Password Changes Revoke All Sessions", func(t *testing.T) {
+		user := h.MustCreateUser("teacher")
+		first := login(t, user.Username)
+		second := login(t, user.Username)
+
+		h.MustDo(http.MethodPut, "/api/v1/auth/change-password", first.AccessToken, map[string]any{
+			"user_id":      user.ID,
+			"old_password": testsupport.Password,
+			"new_password": "ein-neues-passwort",
+		}, http.StatusOK, nil)
+		for _, tokens := range []models.AuthTokens{first, second} {
+			h.MustDo(http.MethodGet, "/api/v1/auth/me", tokens.AccessToken, nil, http.StatusUnauthorized, nil)
+			refresh(tokens.RefreshToken, http.StatusUnauthorized, nil)
+		}
+		h.MustDo(http.MethodPost, "/api/v1/auth/login", "", map[string]string{"username": user.Username, "password": "ein-neues-passwort"}, http.StatusOK, nil)
+	})
+}
@@ -0,0 +1,53 @@
+package e2e_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestBenchmarkEndpoints(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		h := testsupport.New(t)
+		adminToken := h.MustLogin(h.MustCreateUser(string(data.RoleAdmin)).Username)
+		h.MustDo(http.MethodGet, "/api/v1/benchmarks?month=2026-07", adminToken, nil, http.StatusForbidden, nil)
+		h.MustDo(http.MethodGet, "/api/v1/benchmarks/aggregate?month=2026-07", adminToken, nil, http.StatusForbidden, nil)
+	})
+
+	h := testsupport.New(t, func(cfg *config.Config) {
+		cfg.Benchmarking.Enabled = true
+		cfg.Benchmarking.MinPeers = 3
+	})
+	h.SetClock(time.Date(2026, time.August, 3, 9, 0, 0, 0, models.FacilityLocation()))
+	adminToken := h.MustLogin(h.MustCreateUser(string(data.RoleAdmin)).Username)
+	teacherToken := h.MustLogin(h.MustCreateUser(string(data.RoleTeacher)).Username)
+
+	var own models.BenchmarkAggregate
+	h.MustDo(http.MethodGet, "/api/v1/benchmarks/aggregate", adminToken, nil, http.StatusOK, &own)
+	if own.Month != "2026-07" || own.Facility == "" || own.ChildrenBand != models.ChildrenBandSmall || own.Coverage != nil {
+		t.Fatalf("Expected the anonymized aggregate of the past month, got %+v", own)
+	}
+	h.MustDo(http.MethodGet, "/api/v1/benchmarks/aggregate?month=2026-09", adminToken, nil, http.StatusBadRequest, nil)
+	h.MustDo(http.MethodGet, "/api/v1/benchmarks/aggregate", teacherToken, nil, http.StatusForbidden, nil)
+
+	h.MustDo(http.MethodPost, "/api/v1/benchmarks/aggregates", adminToken, own, http.StatusBadRequest, nil)
+	h.MustDo(http.MethodPost, "/api/v1/benchmarks/aggregates", adminToken, map[string]any{"facility": "E-1", "month": "2026-07"}, http.StatusBadRequest, nil)
+	for facility, coverage := range map[string]float64{"E-1": 60, "E-2": 75, "E-3": 90} {
+		aggregate := map[string]any{"facility": facility, "month": "2026-07", "children_band": models.ChildrenBandMedium, "coverage": coverage}
+		h.MustDo(http.MethodPost, "/api/v1/benchmarks/aggregates", adminToken, aggregate, http.StatusNoContent, nil)
+	}
+
+	var comparison models.BenchmarkComparison
+	h.MustDo(http.MethodGet, "/api/v1/benchmarks?month=2026-07", adminToken, nil, http.StatusOK, &comparison)
+	if comparison.Peers != 3 || comparison.Coverage == nil || comparison.Coverage.Median != 75 || comparison.Coverage.Rank != nil {
+		t.Errorf("Expected the coverage of three facilities, got %+v", comparison)
+	}
+	if comparison.ApprovalHours != nil {
+		t.Errorf("Expected no approval times without reports, got %+v", comparison.ApprovalHours)
+	}
+}
@@ -0,0 +1,162 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+	"kitadoc-backend/testsupport"
+)
+
+func TestBreakGlassEndpoints(t *testing.T) {
+	// The attempts are throttled per client, the subtests make more of them than allowed by default.
+	h := testsupport.New(t, func(cfg *config.Config) { cfg.BreakGlass.MaxAttempts = 0 })
+	admin := h.MustCreateUser("admin")
+	token := h.MustLogin(admin.Username)
+	const reason = "Die Leitung hat Passwort und Handy verloren, Zugang für die Abrechnung nötig"
+
+	decode := func(t *testing.T, response *http.Response, wantStatus int, out any) {
+		t.Helper()
+		body := readResponseBody(t, response)
+		if response.StatusCode != wantStatus {
+			t.Fatalf("Expected status %d, got %d: %s", wantStatus, response.StatusCode, body)
+		}
+		if err := json.Unmarshal(body, out); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+	seal := func(t *testing.T) models.BreakGlassSealed {
+		t.Helper()
+		var reauthentication struct {
+			Token string `json:"reauthentication_token"`
+		}
+		h.MustDo(http.MethodPost, "/api/v1/auth/reauthenticate", token, map[string]string{"password": testsupport.Password}, http.StatusOK, &reauthentication)
+		request, err := http.NewRequest(http.MethodPost, h.Server.URL+"/api/v1/admin/break-glass", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+		request.Header.Set(middleware.ReauthenticationHeader, reauthentication.Token)
+		response, err := h.Server.Client().Do(request)
+		if err != nil {
+			t.Fatalf("Failed to seal break-glass credential: %v", err)
+		}
+		var sealed models.BreakGlassSealed
+		decode(t, response, http.StatusCreated, &sealed)
+		return sealed
+	}
+	activate := func(code, reason string) *http.Response {
+		return h.Do(http.MethodPost, "/api/v1/auth/break-glass", "", map[string]string{"code": code, "reason": reason})
+	}
+	expectCode := func(t *testing.T, response *http.Response, wantStatus int, wantCode string) {
+		t.Helper()
+		body := readResponseBody(t, response)
+		if response.StatusCode != wantStatus || !bytes.Contains(body, []byte(wantCode)) {
+			t.Errorf("Expected status %d with code %s, got %d: %s", wantStatus, wantCode, response.StatusCode, body)
+		}
+	}
+
+	t.Run("Sealing Requires A Password Confirmation", func(t *testing.T) {
+		h.MustDo(http.MethodPost, "/api/v1/admin/break-glass", token, nil, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("The Sealed Code Grants Expiring Admin Access Once", func(t *testing.T) {
+		sealed := seal(t)
+		expectCode(t, activate(sealed.Code, "vergessen"), http.StatusBadRequest, services.CodeValidationFailed)
+		expectCode(t, activate("AAAA-BBBB-CCCC-DDDD-EEEE-FFFF", reason), http.StatusForbidden, services.CodeInvalidBreakGlassCode)
+
+		var session models.BreakGlassSession
+		decode(t, activate(sealed.Code, reason), http.StatusOK, &session)
+		if session.Username != models.BreakGlassUsername || session.ExpiresAt.Before(time.Now().Add(2*time.Hour-time.Minute)) || session.ExpiresAt.After(time.Now().Add(2*time.Hour)) {
+			t.Errorf("Expected access of the break-glass account for 2 hours, got %+v", session)
+		}
+		var users []models.User
+		h.MustDo(http.MethodGet, "/api/v1/users", session.Token, nil, http.StatusOK, &users)
+		expectCode(t, activate(sealed.Code, reason), http.StatusForbidden, services.CodeInvalidBreakGlassCode)
+
+		var credentials []models.BreakGlassCredential
+		h.MustDo(http.MethodGet, "/api/v1/admin/break-glass", token, nil, http.StatusOK, &credentials)
+		if len(credentials) != 1 || credentials[0].ActivatedAt == nil || credentials[0].ActivationReason != reason {
+			t.Errorf("Expected the activation with its reason, got %+v", credentials)
+		}
+		var actions []string
+		var entries []models.AuditLogEntry
+		h.MustDo(http.MethodGet, "/api/v1/audit-log", token, nil, http.StatusOK, &entries)
+		for _, entry := range entries {
+			actions = append(actions, entry.Action)
+		}
+		if fmt.Sprint(actions) != fmt.Sprint([]string{models.AuditActionFailBreakGlass, models.AuditActionActivateBreakGlass, models.AuditActionFailBreakGlass, models.AuditActionSealBreakGlass}) {
+			t.Errorf("Expected the seal, the activation and both failed attempts in the audit log, got %v", actions)
+		}
+	})
+
+	t.Run("The Break-Glass Account Cannot Log In With A Password", func(t *testing.T) {
+		h.MustDo(http.MethodPost, "/api/v1/auth/login", "", map[string]string{"username": models.BreakGlassUsername, "password": ""}, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("Revoked Credentials Cannot Be Activated", func(t *testing.T) {
+		sealed := seal(t)
+		h.MustDo(http.MethodDelete, fmt.Sprintf("/api/v1/admin/break-glass/%d", sealed.Credential.ID), token, nil, http.StatusNoContent, nil)
+		expectCode(t, h.Do(http.MethodDelete, fmt.Sprintf("/api/v1/admin/break-glass/%d", sealed.Credential.ID), token, nil), http.StatusConflict, services.CodeBreakGlassRevoked)
+		expectCode(t, activate(sealed.Code, reason), http.StatusForbidden, services.CodeInvalidBreakGlassCode)
+	})
+
+	t.Run("Logout Ends The Emergency Access", func(t *testing.T) {
+		sealed := seal(t)
+		var session models.BreakGlassSession
+		decode(t, activate(sealed.Code, reason), http.StatusOK, &session)
+		h.MustDo(http.MethodPost, "/api/v1/auth/logout", session.Token, nil, http.StatusOK, nil)
+		h.MustDo(http.MethodGet, "/api/v1/users", session.Token, nil, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("Revoking An Activated Credential Ends The Emergency Access", func(t *testing.T) {
+		sealed := seal(t)
+		var session models.BreakGlassSession
+		decode(t, activate(sealed.Code, reason), http.StatusOK, &session)
+		h.MustDo(http.MethodGet, "/api/v1/users", session.Token, nil, http.StatusOK, nil)
+
+		h.MustDo(http.MethodDelete, fmt.Sprintf("/api/v1/admin/break-glass/%d", sealed.Credential.ID), token, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, "/api/v1/users", session.Token, nil, http.StatusUnauthorized, nil)
+	})
+
+	t.Run("The Emergency Access Does Not Depend On The Application Clock", func(t *testing.T) {
+		now := h.Now()
+		h.SetClock(now.AddDate(0, 0, -1))
+		t.Cleanup(func() { h.SetClock(now) })
+
+		sealed := seal(t)
+		var session models.BreakGlassSession
+		decode(t, activate(sealed.Code, reason), http.StatusOK, &session)
+		h.MustDo(http.MethodGet, "/api/v1/users", session.Token, nil, http.StatusOK, nil)
+	})
+}
+
+func TestBreakGlassActivationThrottle(t *testing.T) {
+	h := testsupport.New(t)
+	activate := func(code string) *http.Response {
+		return h.Do(http.MethodPost, "/api/v1/auth/break-glass", "", map[string]string{
+			"code":   code,
+			"reason": "Die Leitung hat Passwort und Handy verloren, Zugang für die Abrechnung nötig",
+		})
+	}
+
+	for range h.Config.BreakGlass.MaxAttempts {
+		response := activate("AAAA-BBBB-CCCC-DDDD-EEEE-FFFF")
+		response.Body.Close() //nolint:errcheck
+		if response.StatusCode != http.StatusForbidden {
+			t.Fatalf("Expected wrong codes to be refused with 403, got %d", response.StatusCode)
+		}
+	}
+	response := activate("AAAA-BBBB-CCCC-DDDD-EEEE-FFFF")
+	response.Body.Close() //nolint:errcheck
+	if response.StatusCode != http.StatusTooManyRequests || response.Header.Get("Retry-After") == "" {
+		t.Errorf("Expected further attempts to be throttled, got %d", response.StatusCode)
+	}
+}
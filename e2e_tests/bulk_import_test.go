@@ -3,12 +3,15 @@ package e2e_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"kitadoc-backend/models"
 )
 
 func TestBulkImportChildrenFromXLSX(t *testing.T) {
@@ -73,6 +76,41 @@ func TestBulkImportChildrenFromXLSX(t *testing.T) {
 		t.Errorf("Expected bulk import success message, got %s", responseBody)
 	}
 
+	var importResult struct {
+		ImportJobID int `json:"import_job_id"`
+	}
+	if err := json.Unmarshal(responseBody, &importResult); err != nil {
+		t.Fatalf("Failed to unmarshal import response: %v", err)
+	}
+
+	// Verify that the import is recorded in the import job history
+	t.Run("Import Job History", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/bulk/import-jobs", adminAuthToken, nil, "application/json")
+		defer resp.Body.Close() // nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Failed to get import jobs: %s", readResponseBody(t, resp))
+		}
+		if !bytes.Contains(readResponseBody(t, resp), []byte(`"file_name":"Kindliste.xlsx"`)) {
+			t.Errorf("Expected the import in the import job history")
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/bulk/import-jobs/%d", importResult.ImportJobID), adminAuthToken, nil, "application/json")
+		defer resp.Body.Close() // nolint:errcheck
+		var job models.ImportJob
+		if err := json.Unmarshal(readResponseBody(t, resp), &job); err != nil {
+			t.Fatalf("Failed to unmarshal import job: %v", err)
+		}
+		if job.Status != models.ImportJobStatusCompleted || job.RowCounts[models.ImportRowStatusImported] != len(job.Rows) {
+			t.Errorf("Expected a completed import job with all rows imported, got %+v", job)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/bulk/import-jobs/%d/resume", importResult.ImportJobID), adminAuthToken, nil, "application/json")
+		defer resp.Body.Close() // nolint:errcheck
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("Expected status %d when resuming a completed import, got %d", http.StatusConflict, resp.StatusCode)
+		}
+	})
+
 	// Verify that the children were actually created
 	t.Run("Verify Children Creation", func(t *testing.T) {
 		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/children", authToken, nil, "application/json")
@@ -0,0 +1,93 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestCareContractEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	admin := h.MustCreateUser("admin")
+	adminToken := h.MustLogin(admin.Username)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	teacherToken := h.MustLogin(teacher.Username)
+	anna := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+	ben := h.MustCreateChild(models.Child{FirstName: "Ben", LastName: "Schulz"})
+	// Wednesday noon in the facility, the four weeks before this one are checked
+	h.SetClock(time.Date(2024, time.October, 16, 12, 0, 0, 0, models.FacilityLocation()))
+	today := models.Today(h.Now())
+
+	createContract := func(childID int, body map[string]any, wantStatus int) models.CareContract {
+		var contract models.CareContract
+		h.MustDo(http.MethodPost, fmt.Sprintf("/api/v1/children/%d/care-contracts", childID), adminToken, body, wantStatus, &contract)
+		return contract
+	}
+
+	var annaContract models.CareContract
+	t.Run("Create And Update", func(t *testing.T) {
+		annaContract = createContract(anna.ID, map[string]any{"weekly_hours": 25, "valid_from": "2024-08-01"}, http.StatusCreated)
+		if annaContract.WeeklyHours != 25 || annaContract.ValidUntil != nil {
+			t.Fatalf("Expected an open-ended 25 h contract, got %+v", annaContract)
+		}
+		createContract(anna.ID, map[string]any{"weekly_hours": 35, "valid_from": "2024-09-01"}, http.StatusConflict)
+		createContract(anna.ID, map[string]any{"weekly_hours": 30, "valid_from": "2024-09-01"}, http.StatusBadRequest)
+		createContract(anna.ID, map[string]any{"weekly_hours": 35, "valid_from": "2024-09-01", "valid_until": "2024-08-31"}, http.StatusBadRequest)
+		createContract(ben.ID, map[string]any{"weekly_hours": 45, "valid_from": "2024-08-01"}, http.StatusCreated)
+
+		resp := h.Do(http.MethodPost, fmt.Sprintf("/api/v1/children/%d/care-contracts", anna.ID), teacherToken,
+			map[string]any{"weekly_hours": 45, "valid_from": "2025-01-01"})
+		readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected teachers not to book hours, got %d", resp.StatusCode)
+		}
+
+		// Ending the contract makes room for the next one
+		contractURL := fmt.Sprintf("/api/v1/care-contracts/%d", annaContract.ID)
+		h.MustDo(http.MethodPut, contractURL, adminToken, map[string]any{"weekly_hours": 25, "valid_from": "2024-08-01", "valid_until": "2024-12-31"}, http.StatusOK, nil)
+		next := createContract(anna.ID, map[string]any{"weekly_hours": 35, "valid_from": "2025-01-01"}, http.StatusCreated)
+
+		var contracts []models.CareContract
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/children/%d/care-contracts", anna.ID), teacherToken, nil, http.StatusOK, &contracts)
+		if len(contracts) != 2 || contracts[0].ID != annaContract.ID || contracts[1].ID != next.ID {
+			t.Fatalf("Expected both contracts of Anna, got %+v", contracts)
+		}
+		h.MustDo(http.MethodDelete, fmt.Sprintf("/api/v1/care-contracts/%d", next.ID), adminToken, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/care-contracts/%d", next.ID), teacherToken, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("Warnings For Consistently Exceeded Hours", func(t *testing.T) {
+		// Both children attend from 8:00 to 14:00 on every weekday of the past four weeks, 30 hours a week
+		for day := today.AddDays(-30); day.Before(today.Time); day = day.AddDays(1) {
+			if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+				continue
+			}
+			for _, childID := range []int{anna.ID, ben.ID} {
+				for _, record := range []struct {
+					eventType string
+					hour      int
+				}{{models.AttendanceArrival, 8}, {models.AttendanceDeparture, 14}} {
+					at := time.Date(day.Year(), day.Month(), day.Day(), record.hour, 0, 0, 0, models.FacilityLocation())
+					if _, err := h.DAL.Attendance.Create(t.Context(), &models.AttendanceRecord{ChildID: childID, EventType: record.eventType, RecordedAt: at,
+						AttendanceDate: day, Source: models.AttendanceSourceManual}); err != nil {
+						t.Fatalf("Failed to record attendance: %v", err)
+					}
+				}
+			}
+		}
+
+		var warnings []models.CareHoursWarning
+		h.MustDo(http.MethodGet, "/api/v1/care-contracts/warnings", adminToken, nil, http.StatusOK, &warnings)
+		if len(warnings) != 1 || warnings[0].ChildID != anna.ID || len(warnings[0].Weeks) != 4 || warnings[0].AverageExcessHours != 5 {
+			t.Fatalf("Expected a warning for Anna only, got %+v", warnings)
+		}
+		if week := warnings[0].Weeks[0]; week.WeekStart != models.NewDate(2024, time.September, 16) || week.BookedHours != 25 || week.AttendedHours != 30 {
+			t.Errorf("Expected the first week to compare 30 attended with 25 booked hours, got %+v", week)
+		}
+		h.MustDo(http.MethodGet, "/api/v1/care-contracts/warnings?weeks=13", adminToken, nil, http.StatusBadRequest, nil)
+	})
+}
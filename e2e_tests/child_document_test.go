@@ -0,0 +1,163 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestChildDocumentEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	token := h.MustLogin(teacher.Username)
+	child := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+	today := models.Today(time.Now())
+
+	upload := func(documentType string, fileName string, content []byte, expiresOn string) *http.Response {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("document", fileName)
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write(content)                              //nolint:errcheck
+		writer.WriteField("document_type", documentType) //nolint:errcheck
+		if expiresOn != "" {
+			writer.WriteField("expires_on", expiresOn) //nolint:errcheck
+		}
+		writer.Close() //nolint:errcheck
+
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/children/%d/documents", h.Server.URL, child.ID), body)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := h.Server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to upload document: %v", err)
+		}
+		return resp
+	}
+	pdf := []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n")
+	getReminders := func() []models.DocumentReminder {
+		var reminders []models.DocumentReminder
+		h.MustDo(http.MethodGet, "/api/v1/child-documents/reminders", token, nil, http.StatusOK, &reminders)
+		var ofChild []models.DocumentReminder
+		for _, reminder := range reminders {
+			if reminder.ChildID == child.ID {
+				ofChild = append(ofChild, reminder)
+			}
+		}
+		return ofChild
+	}
+
+	t.Run("Missing Documents Are Reminded", func(t *testing.T) {
+		reminders := getReminders()
+		if len(reminders) != 2 || reminders[0].Reason != models.DocumentReminderMissing || reminders[1].Reason != models.DocumentReminderMissing {
+			t.Fatalf("Expected the contract and the Masernschutznachweis to be missing, got %+v", reminders)
+		}
+		if reminders[0].ChildFirstName != "Anna" {
+			t.Errorf("Expected the name of the child, got %+v", reminders[0])
+		}
+	})
+
+	var contract models.ChildDocument
+	t.Run("Upload And Download", func(t *testing.T) {
+		resp := upload(models.ChildDocumentTypeCareContract, "Betreuungsvertrag Müller.pdf", pdf, "")
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", resp.StatusCode, body)
+		}
+		if err := json.Unmarshal(body, &contract); err != nil {
+			t.Fatalf("Failed to unmarshal document: %v", err)
+		}
+		if contract.FileName != "Betreuungsvertrag Müller.pdf" || contract.SizeBytes != len(pdf) {
+			t.Errorf("Unexpected document %+v", contract)
+		}
+
+		resp = h.Do(http.MethodGet, fmt.Sprintf("/api/v1/child-documents/%d/content", contract.ID), token, nil)
+		body = readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/pdf" || !bytes.Equal(body, pdf) {
+			t.Errorf("Expected the PDF, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		if disposition := resp.Header.Get("Content-Disposition"); disposition != "attachment; filename*=utf-8''Betreuungsvertrag%20M%C3%BCller.pdf" {
+			t.Errorf("Expected the original file name, got %s", disposition)
+		}
+
+		var documents []models.ChildDocument
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/children/%d/documents", child.ID), token, nil, http.StatusOK, &documents)
+		if len(documents) != 1 || documents[0].ID != contract.ID {
+			t.Errorf("Expected the contract, got %+v", documents)
+		}
+	})
+
+	t.Run("Invalid Uploads", func(t *testing.T) {
+		for name, resp := range map[string]*http.Response{
+			"no PDF":       upload(models.ChildDocumentTypeOther, "foto.png", []byte("\x89PNG\r\n\x1a\n"), ""),
+			"unknown type": upload("passport", "pass.pdf", pdf, ""),
+			"invalid date": upload(models.ChildDocumentTypeOther, "x.pdf", pdf, "31.12.2025"),
+		} {
+			readResponseBody(t, resp)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("%s: expected status 400, got %d", name, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("Expired And Expiring Documents Are Reminded", func(t *testing.T) {
+		resp := upload(models.ChildDocumentTypeMeaslesProtection, "Impfpass.pdf", pdf, "")
+		readResponseBody(t, resp)
+		resp = upload(models.ChildDocumentTypeMedicalCertificate, "Attest.pdf", pdf, today.AddDays(-1).String())
+		readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+		}
+
+		reminders := getReminders()
+		if len(reminders) != 1 || reminders[0].Reason != models.DocumentReminderExpired || reminders[0].DocumentType != models.ChildDocumentTypeMedicalCertificate {
+			t.Fatalf("Expected the expired Attest, got %+v", reminders)
+		}
+
+		resp = upload(models.ChildDocumentTypeMedicalCertificate, "Attest neu.pdf", pdf, today.AddDays(10).String())
+		readResponseBody(t, resp)
+		reminders = getReminders()
+		if len(reminders) != 1 || reminders[0].Reason != models.DocumentReminderExpiring {
+			t.Fatalf("Expected the new Attest to expire soon, got %+v", reminders)
+		}
+		var none []models.DocumentReminder
+		h.MustDo(http.MethodGet, "/api/v1/child-documents/reminders?within_days=5", token, nil, http.StatusOK, &none)
+		for _, reminder := range none {
+			if reminder.ChildID == child.ID {
+				t.Errorf("Expected no reminder within 5 days, got %+v", reminder)
+			}
+		}
+		h.MustDo(http.MethodGet, "/api/v1/child-documents/reminders?within_days=-1", token, nil, http.StatusBadRequest, nil)
+	})
+
+	t.Run("Update And Delete", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/child-documents/%d", contract.ID)
+		expired := today.AddDays(-3).String()
+		h.MustDo(http.MethodPut, url, token, map[string]any{"document_type": "care_contract", "file_name": "Vertrag.pdf", "expires_on": expired}, http.StatusOK, nil)
+		var document models.ChildDocument
+		h.MustDo(http.MethodGet, url, token, nil, http.StatusOK, &document)
+		if document.FileName != "Vertrag.pdf" || document.ExpiresOn == nil || document.ExpiresOn.String() != expired {
+			t.Errorf("Expected the updated document, got %+v", document)
+		}
+		h.MustDo(http.MethodPut, url, token, map[string]any{"document_type": "passport", "file_name": "Vertrag.pdf"}, http.StatusBadRequest, nil)
+
+		h.MustDo(http.MethodDelete, url, token, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, url, token, nil, http.StatusNotFound, nil)
+		reminders := getReminders()
+		if len(reminders) != 2 || reminders[0].DocumentType != models.ChildDocumentTypeCareContract || reminders[0].Reason != models.DocumentReminderMissing {
+			t.Errorf("Expected the contract to be missing again, got %+v", reminders)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+	"kitadoc-backend/testsupport"
+)
+
+func TestClientEncryptionEndpoints(t *testing.T) {
+	h := testsupport.New(t, func(cfg *config.Config) {
+		cfg.ClientEncryption.Mode = config.ClientEncryptionRequired
+	})
+	adminToken := h.MustLogin(h.MustCreateUser(string(data.RoleAdmin)).Username)
+	token := h.MustLogin(h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"}).Username)
+	anna := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+
+	var group models.Group
+	h.MustDo(http.MethodPost, "/api/v1/groups", adminToken, map[string]any{"name": "Sonnengruppe", "capacity": 20}, http.StatusCreated, &group)
+	h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/groups/%d/children/%d", group.ID, anna.ID), token, nil, http.StatusOK, nil)
+	h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/children/%d/photo-consent", anna.ID), token,
+		map[string]any{"consent_reference": "Einwilligung vom 01.08.2024"}, http.StatusOK, nil)
+
+	ciphertext := []byte("\x00\x9f\x13ciphertext the server cannot read")
+	envelope := `{"algorithm":"AES-256-GCM","key_id":"traeger-2024","nonce":"bm9uY2UxMjM0NTY=","wrapped_key":"a2V5"}`
+	upload := func(path, field string, fields map[string]string) (int, []byte) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile(field, "anhang.bin")
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write(ciphertext) //nolint:errcheck
+		for name, value := range fields {
+			writer.WriteField(name, value) //nolint:errcheck
+		}
+		writer.Close() //nolint:errcheck
+
+		req, err := http.NewRequest(http.MethodPost, h.Server.URL+path, body)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := h.Server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to upload: %v", err)
+		}
+		return resp.StatusCode, readResponseBody(t, resp)
+	}
+
+	t.Run("Encrypted Photos", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/groups/%d/photos", group.ID)
+		status, body := upload(path, "photo", map[string]string{"child_ids": fmt.Sprint(anna.ID)})
+		if status != http.StatusBadRequest || !strings.Contains(string(body), services.CodeClientEncryptionRequired) {
+			t.Fatalf("Expected plain photos to be rejected, got %d: %s", status, body)
+		}
+		status, body = upload(path, "photo", map[string]string{"child_ids": fmt.Sprint(anna.ID), "envelope": "{"})
+		if status != http.StatusBadRequest {
+			t.Fatalf("Expected an invalid envelope to be rejected, got %d: %s", status, body)
+		}
+
+		status, body = upload(path, "photo", map[string]string{"child_ids": fmt.Sprint(anna.ID), "envelope": envelope})
+		if status != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", status, body)
+		}
+		var photo models.GalleryPhoto
+		if err := json.Unmarshal(body, &photo); err != nil {
+			t.Fatalf("Failed to unmarshal photo: %v", err)
+		}
+		if photo.Envelope == nil || photo.Envelope.KeyID != "traeger-2024" {
+			t.Fatalf("Expected the envelope to be returned, got %+v", photo.Envelope)
+		}
+
+		resp := h.Do(http.MethodGet, fmt.Sprintf("/api/v1/photos/%d/content", photo.ID), token, nil)
+		if content := readResponseBody(t, resp); !bytes.Equal(content, ciphertext) || resp.Header.Get("Content-Type") != "application/octet-stream" {
+			t.Errorf("Expected the ciphertext to be served as it is, got %s: %q", resp.Header.Get("Content-Type"), content)
+		}
+	})
+
+	t.Run("Encrypted Documents", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/children/%d/documents", anna.ID)
+		status, body := upload(path, "document", map[string]string{"document_type": models.ChildDocumentTypeCareContract, "envelope": envelope})
+		if status != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", status, body)
+		}
+		var document models.ChildDocument
+		if err := json.Unmarshal(body, &document); err != nil {
+			t.Fatalf("Failed to unmarshal document: %v", err)
+		}
+		if document.Envelope == nil || document.Envelope.WrappedKey != "a2V5" {
+			t.Fatalf("Expected the envelope to be returned, got %+v", document.Envelope)
+		}
+
+		var documents []models.ChildDocument
+		h.MustDo(http.MethodGet, path, token, nil, http.StatusOK, &documents)
+		if len(documents) != 1 || documents[0].Envelope == nil {
+			t.Fatalf("Expected the listed document with its envelope, got %+v", documents)
+		}
+		resp := h.Do(http.MethodGet, fmt.Sprintf("/api/v1/child-documents/%d/content", document.ID), token, nil)
+		if content := readResponseBody(t, resp); !bytes.Equal(content, ciphertext) || resp.Header.Get("Content-Type") != "application/octet-stream" {
+			t.Errorf("Expected the ciphertext to be served as it is, got %s: %q", resp.Header.Get("Content-Type"), content)
+		}
+	})
+}
+
+func TestClientEncryptionDisabled(t *testing.T) {
+	h := testsupport.New(t)
+	token := h.MustLogin(h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"}).Username)
+	anna := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("document", "anhang.bin")
+	if err != nil {
+		t.Fatalf("Failed to create form file: %v", err)
+	}
+	part.Write([]byte("ciphertext"))                                                                        //nolint:errcheck
+	writer.WriteField("document_type", models.ChildDocumentTypeCareContract)                                //nolint:errcheck
+	writer.WriteField("envelope", `{"algorithm":"AES-256-GCM","key_id":"traeger-2024","nonce":"bm9uY2U="}`) //nolint:errcheck
+	writer.Close()                                                                                          //nolint:errcheck
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/children/%d/documents", h.Server.URL, anna.ID), body)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := h.Server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Failed to upload: %v", err)
+	}
+	if respBody := readResponseBody(t, resp); resp.StatusCode != http.StatusForbidden || !strings.Contains(string(respBody), services.CodeClientEncryptionDisabled) {
+		t.Fatalf("Expected encrypted uploads to be refused, got %d: %s", resp.StatusCode, respBody)
+	}
+}
@@ -0,0 +1,92 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestDailyCareEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	admin := h.MustCreateUser(string(data.RoleAdmin))
+	adminToken := h.MustLogin(admin.Username)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	token := h.MustLogin(teacher.Username)
+	today := models.Today(h.Now())
+	emma := h.MustCreateChild(models.Child{FirstName: "Emma", LastName: "Müller", Birthdate: today.AddDays(-500)})
+	ben := h.MustCreateChild(models.Child{FirstName: "Ben", LastName: "Schulz", Birthdate: today.AddDays(-700)})
+	lukas := h.MustCreateChild(models.Child{FirstName: "Lukas", LastName: "Weber"})
+
+	var group models.Group
+	h.MustDo(http.MethodPost, "/api/v1/groups", adminToken, map[string]any{"name": "Krippe", "capacity": 12}, http.StatusCreated, &group)
+	for _, child := range []*models.Child{emma, ben, lukas} {
+		h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/groups/%d/children/%d", group.ID, child.ID), token, nil, http.StatusOK, nil)
+	}
+	groupURL := fmt.Sprintf("/api/v1/groups/%d/daily-care/%s", group.ID, today)
+	childURL := fmt.Sprintf("/api/v1/children/%d/daily-care", emma.ID)
+
+	t.Run("Bulk Entry For A Group", func(t *testing.T) {
+		var care []models.GroupDailyCare
+		h.MustDo(http.MethodGet, groupURL, token, nil, http.StatusOK, &care)
+		if len(care) != 2 || care[0].ChildID != ben.ID || care[1].ChildID != emma.ID || care[0].Log != nil {
+			t.Fatalf("Expected the U3 children of the group without logs, got %+v", care)
+		}
+
+		h.MustDo(http.MethodPut, groupURL, token, []map[string]any{
+			{"child_id": emma.ID, "breakfast": "all", "lunch": "half", "nap_minutes": 75, "diaper_changes": 3},
+			{"child_id": ben.ID, "lunch": "little", "toilet_visits": 2},
+		}, http.StatusOK, &care)
+		if len(care) != 2 || care[1].Log == nil || *care[1].Log.NapMinutes != 75 || care[0].Log == nil || *care[0].Log.ToiletVisits != 2 {
+			t.Fatalf("Unexpected group daily care %+v", care)
+		}
+		if care[1].Log.RecordedByUserID == nil {
+			t.Error("Expected the recording user to be set")
+		}
+	})
+
+	t.Run("Bulk Entry Saves Nothing On An Invalid Log", func(t *testing.T) {
+		resp := h.Do(http.MethodPut, groupURL, token, []map[string]any{
+			{"child_id": emma.ID, "lunch": "none"},
+			{"child_id": lukas.ID, "lunch": "all"},
+		})
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("Expected status 400 for a child older than three, got %d: %s", resp.StatusCode, body)
+		}
+		var care []models.GroupDailyCare
+		h.MustDo(http.MethodGet, groupURL, token, nil, http.StatusOK, &care)
+		if len(care) != 2 || care[1].Log == nil || *care[1].Log.Lunch != models.MealPortionHalf {
+			t.Errorf("Expected the logs of the failed bulk entry not to be saved, got %+v", care)
+		}
+	})
+
+	t.Run("Single Entries And Parents View", func(t *testing.T) {
+		yesterday := today.AddDays(-1)
+		h.MustDo(http.MethodPut, fmt.Sprintf("%s/%s", childURL, yesterday), token, map[string]any{
+			"snack": "most", "note": "Hatte leichtes Fieber", "hidden_from_parents": true,
+		}, http.StatusOK, nil)
+		h.MustDo(http.MethodPut, fmt.Sprintf("%s/%s", childURL, today.AddDays(1)), token, map[string]any{"snack": "all"}, http.StatusBadRequest, nil)
+		h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/children/%d/daily-care/%s", lukas.ID, today), token, map[string]any{"snack": "all"}, http.StatusBadRequest, nil)
+		h.MustDo(http.MethodPut, fmt.Sprintf("%s/%s", childURL, "gestern"), token, map[string]any{"snack": "all"}, http.StatusBadRequest, nil)
+
+		var logs []models.DailyCareLog
+		h.MustDo(http.MethodGet, childURL, token, nil, http.StatusOK, &logs)
+		if len(logs) != 2 || logs[0].CareDate != yesterday || *logs[0].Note != "Hatte leichtes Fieber" {
+			t.Fatalf("Unexpected daily care %+v", logs)
+		}
+		h.MustDo(http.MethodGet, childURL+"?parents_view=true", token, nil, http.StatusOK, &logs)
+		if len(logs) != 1 || logs[0].CareDate != today {
+			t.Fatalf("Expected the hidden log to be left out of the parents view, got %+v", logs)
+		}
+		h.MustDo(http.MethodGet, fmt.Sprintf("%s?from=%s&to=%s", childURL, today, yesterday), token, nil, http.StatusBadRequest, nil)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		h.MustDo(http.MethodDelete, fmt.Sprintf("%s/%s", childURL, today), token, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodDelete, fmt.Sprintf("%s/%s", childURL, today), token, nil, http.StatusNotFound, nil)
+	})
+}
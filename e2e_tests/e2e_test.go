@@ -8,11 +8,17 @@ import (
 	"mime/multipart"
 	"net/http"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
+	"kitadoc-backend/services"
 )
 
 var (
@@ -118,6 +124,25 @@ func TestPublicRoutes(t *testing.T) {
 		if !bytes.Contains(body, []byte("status")) || !bytes.Contains(body, []byte("ok")) {
 			t.Errorf("Expected status ok in response, got %s", body)
 		}
+		if !bytes.Contains(body, []byte(`"version"`)) {
+			t.Errorf("Expected the version in response, got %s", body)
+		}
+	})
+
+	// Test GET /version
+	t.Run("Version", func(t *testing.T) {
+		resp := makeUnauthenticatedRequest(t, http.MethodGet, "/version", nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var build models.BuildInfo
+		if err := json.Unmarshal(readResponseBody(t, resp), &build); err != nil {
+			t.Fatalf("Failed to unmarshal build info: %v", err)
+		}
+		if build.Version == "" || build.GoVersion == "" {
+			t.Errorf("Expected version and Go version, got %+v", build)
+		}
 	})
 }
 
@@ -216,6 +241,33 @@ func TestChildrenManagementEndpoints(t *testing.T) {
 		}
 	})
 
+	// Test GET and HEAD /api/v1/children/count
+	t.Run("Count Children", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/children/count", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var count struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal(readResponseBody(t, resp), &count); err != nil {
+			t.Fatalf("Failed to unmarshal count: %v", err)
+		}
+		if count.Count < 1 {
+			t.Errorf("Expected at least 1 child, got %d", count.Count)
+		}
+
+		headResp := makeAuthenticatedRequest(t, http.MethodHead, "/api/v1/children/count", authToken, nil, "application/json")
+		defer headResp.Body.Close() //nolint:errcheck
+		if headResp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, headResp.StatusCode)
+		}
+		if total := headResp.Header.Get("X-Total-Count"); total != strconv.Itoa(count.Count) {
+			t.Errorf("Expected X-Total-Count %d, got %q", count.Count, total)
+		}
+	})
+
 	// Test GET /api/v1/children/{child_id}
 	t.Run("Get Child By ID", func(t *testing.T) {
 		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/children/%d", childID), authToken, nil, "application/json")
@@ -277,7 +329,7 @@ func TestChildrenManagementEndpoints(t *testing.T) {
 func TestTeachersManagementEndpoints(t *testing.T) {
 	setupTest(t)
 
-	var teacherID int
+	var teacherID, childID int
 	// Test POST /api/v1/teachers
 	t.Run("Create Teacher", func(t *testing.T) {
 		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", authToken, map[string]string{
@@ -373,7 +425,7 @@ func TestTeachersManagementEndpoints(t *testing.T) {
 		if err := json.Unmarshal(bodyChild, &childResp); err != nil {
 			t.Fatalf("Failed to unmarshal child creation response: %v", err)
 		}
-		childID := childResp.ID
+		childID = childResp.ID
 		if childID == 0 {
 			t.Fatalf("Expected child ID, got 0")
 		}
@@ -418,6 +470,96 @@ func TestTeachersManagementEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Deactivate Teacher", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/teachers/%d/deactivate", teacherID), adminAuthToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+		}
+
+		respList := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/teachers", authToken, nil, "application/json")
+		defer respList.Body.Close() //nolint:errcheck
+		if body := readResponseBody(t, respList); bytes.Contains(body, []byte("Alicia")) {
+			t.Errorf("Expected deactivated teacher to be left out, got %s", body)
+		}
+		respAll := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/teachers?include_deactivated=true", authToken, nil, "application/json")
+		defer respAll.Body.Close() //nolint:errcheck
+		if body := readResponseBody(t, respAll); !bytes.Contains(body, []byte("deactivated_at")) {
+			t.Errorf("Expected deactivated teacher with include_deactivated, got %s", body)
+		}
+
+		respAssign := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/assignments", adminAuthToken, map[string]interface{}{
+			"teacher_id": teacherID,
+			"child_id":   childID,
+			"start_date": time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}, "application/json")
+		defer respAssign.Body.Close() //nolint:errcheck
+		if body := readResponseBody(t, respAssign); respAssign.StatusCode != http.StatusConflict || !bytes.Contains(body, []byte("TEACHER_DEACTIVATED")) {
+			t.Errorf("Expected deactivated teacher to get no assignment, got %d: %s", respAssign.StatusCode, body)
+		}
+
+		respReactivate := makeAuthenticatedRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/teachers/%d/reactivate", teacherID), adminAuthToken, nil, "application/json")
+		defer respReactivate.Body.Close() //nolint:errcheck
+		if respReactivate.StatusCode != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, respReactivate.StatusCode)
+		}
+	})
+
+	t.Run("Merge Duplicate Teacher", func(t *testing.T) {
+		respDuplicate := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", authToken, map[string]string{
+			"first_name": "Alicia",
+			"last_name":  "Smith",
+			"username":   "asmith",
+		}, "application/json")
+		defer respDuplicate.Body.Close() //nolint:errcheck
+		var duplicate struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(readResponseBody(t, respDuplicate), &duplicate); err != nil || duplicate.ID == 0 {
+			t.Fatalf("Failed to create duplicate teacher: %v", err)
+		}
+		respAssign := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/assignments", adminAuthToken, map[string]interface{}{
+			"teacher_id": duplicate.ID,
+			"child_id":   childID,
+			"start_date": time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}, "application/json")
+		defer respAssign.Body.Close() //nolint:errcheck
+		var assignment struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(readResponseBody(t, respAssign), &assignment); err != nil || assignment.ID == 0 {
+			t.Fatalf("Failed to create assignment of duplicate teacher: %v", err)
+		}
+
+		resp := makeAuthenticatedRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/teachers/%d/merge", teacherID), adminAuthToken, map[string]int{
+			"duplicate_teacher_id": duplicate.ID,
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+		}
+
+		respMoved := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/assignments/%d", assignment.ID), authToken, nil, "application/json")
+		defer respMoved.Body.Close() //nolint:errcheck
+		var moved struct {
+			TeacherID int `json:"teacher_id"`
+		}
+		if err := json.Unmarshal(readResponseBody(t, respMoved), &moved); err != nil || moved.TeacherID != teacherID {
+			t.Errorf("Expected the assignment to be moved to teacher %d, got %d (%v)", teacherID, moved.TeacherID, err)
+		}
+		respGone := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/teachers/%d", duplicate.ID), authToken, nil, "application/json")
+		defer respGone.Body.Close() //nolint:errcheck
+		if respGone.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected the duplicate to be deleted, got status %d", respGone.StatusCode)
+		}
+
+		respCleanup := makeAuthenticatedRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/assignments/%d", assignment.ID), adminAuthToken, nil, "application/json")
+		defer respCleanup.Body.Close() //nolint:errcheck
+		if respCleanup.StatusCode != http.StatusOK {
+			t.Fatalf("Failed to clean up assignment after merge test: %s", readResponseBody(t, respCleanup))
+		}
+	})
+
 	// Test DELETE /api/v1/teachers/{teacher_id}
 	t.Run("Delete Teacher", func(t *testing.T) {
 		resp := makeAuthenticatedRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/teachers/%d", teacherID), adminAuthToken, nil, "application/json")
@@ -488,6 +630,47 @@ func TestCategoriesManagementEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Pin Favorite Category", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/categories/%d/pin", categoryID), authToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("Expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/categories?view=favorites", authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var favorites []models.FavoriteCategory
+		if err := json.Unmarshal(readResponseBody(t, resp), &favorites); err != nil {
+			t.Fatalf("Failed to unmarshal favorite categories: %v", err)
+		}
+		if len(favorites) == 0 || favorites[0].ID != categoryID || !favorites[0].Pinned {
+			t.Errorf("Expected the pinned category first, got %+v", favorites)
+		}
+	})
+
+	t.Run("Unpin Favorite Category", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/categories/%d/pin", categoryID), authToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("Expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/categories?view=favorites", authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		var favorites []models.FavoriteCategory
+		if err := json.Unmarshal(readResponseBody(t, resp), &favorites); err != nil {
+			t.Fatalf("Failed to unmarshal favorite categories: %v", err)
+		}
+		for _, favorite := range favorites {
+			if favorite.ID == categoryID && favorite.Pinned {
+				t.Errorf("Expected the category to be unpinned, got %+v", favorite)
+			}
+		}
+	})
+
 	// Test DELETE /api/v1/categories/{category_id}
 	t.Run("Delete Category", func(t *testing.T) {
 		resp := makeAuthenticatedRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/categories/%d", categoryID), adminAuthToken, nil, "application/json")
@@ -598,6 +781,39 @@ func TestChildTeacherAssignmentsEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Second Open Primary Assignment Is Rejected", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/assignments", authToken, map[string]interface{}{
+			"child_id":        childID,
+			"teacher_id":      teacherID,
+			"assignment_type": models.AssignmentTypePrimary,
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("Expected status %d, got %d", http.StatusConflict, resp.StatusCode)
+		}
+	})
+
+	t.Run("Filter Assignments by Type", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/assignments/child/%d?type=primary&open=true", childID), authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var assignments []models.Assignment
+		if err := json.Unmarshal(readResponseBody(t, resp), &assignments); err != nil {
+			t.Fatalf("failed to unmarshal assignments response: %v", err)
+		}
+		if len(assignments) != 1 || assignments[0].ID != assignmentID {
+			t.Errorf("Expected only the primary assignment %d, got %+v", assignmentID, assignments)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/assignments?type=trainee", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d for an unknown type, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
 	// Test PUT /api/v1/assignments/{assignment_id}
 	t.Run("Update Assignment", func(t *testing.T) {
 		// Create another teacher to reassign
@@ -814,6 +1030,7 @@ func TestAudioRecordingsEndpoints(t *testing.T) {
 
 func TestDocumentGenerationEndpoints(t *testing.T) {
 	setupTest(t)
+	reportPath := filepath.Join(t.TempDir(), "child_report.docx")
 
 	// First, create a child for document generation
 	var childID int
@@ -856,11 +1073,17 @@ func TestDocumentGenerationEndpoints(t *testing.T) {
 	})
 
 	// Create a category for document generation
+	var categoryID int
 	t.Run("Setup Category for Document Generation", func(t *testing.T) {
 		respCategory := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/categories", adminAuthToken, map[string]string{
 			"name": "ReportCategory",
 		}, "application/json")
 		defer respCategory.Body.Close() //nolint:errcheck
+		var categoryResp struct {
+			ID int `json:"id"`
+		}
+		json.Unmarshal(readResponseBody(t, respCategory), &categoryResp) //nolint:errcheck
+		categoryID = categoryResp.ID
 	})
 
 	// Create a documentation entry for the child
@@ -869,7 +1092,7 @@ func TestDocumentGenerationEndpoints(t *testing.T) {
 		respEntry := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/documentation", authToken, map[string]interface{}{
 			"child_id":    childID,
 			"teacher_id":  teacherID,
-			"category_id": 1,
+			"category_id": categoryID,
 
 			"observation_description": "Child showed excellent progress in language skills.",
 			"observation_date":        time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC),
@@ -902,11 +1125,94 @@ func TestDocumentGenerationEndpoints(t *testing.T) {
 		if len(body) == 0 {
 			t.Error("Expected non-empty report content, got empty")
 		}
-		// save the report to a file for manual inspection if needed
-		if err := os.WriteFile("child_report.docx", body, 0644); err != nil {
+		// Kept to compare the archived report with
+		if err := os.WriteFile(reportPath, body, 0644); err != nil {
 			t.Fatalf("Failed to write report to file: %v", err)
 		}
 	})
+
+	// The entry is part of the generated report and can only be changed through a revision.
+	t.Run("Locked Entry Cannot Be Updated", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPut, fmt.Sprintf("/api/v1/documentation/%d", entryID), authToken, map[string]interface{}{
+			"child_id":                childID,
+			"teacher_id":              teacherID,
+			"category_id":             categoryID,
+			"observation_description": "Changed after the report was handed out.",
+			"observation_date":        time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC),
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("Expected status %d, got %d", http.StatusConflict, resp.StatusCode)
+		}
+	})
+
+	t.Run("Create Revision Of Locked Entry", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/documentation/%d/revisions", entryID), authToken, map[string]interface{}{
+			"child_id":                childID,
+			"teacher_id":              teacherID,
+			"category_id":             categoryID,
+			"observation_description": "Child showed excellent progress in language and counting.",
+			"observation_date":        time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC),
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+		}
+		var revision models.DocumentationEntry
+		json.Unmarshal(readResponseBody(t, resp), &revision) //nolint:errcheck
+		if revision.RevisionOfEntryID == nil || *revision.RevisionOfEntryID != entryID {
+			t.Errorf("Expected revision of entry %d, got %v", entryID, revision.RevisionOfEntryID)
+		}
+	})
+
+	var reportID int
+	t.Run("List Archived Reports", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/children/%d/reports", childID), authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var reports []models.GeneratedReport
+		json.Unmarshal(readResponseBody(t, resp), &reports) //nolint:errcheck
+		if len(reports) != 1 || len(reports[0].EntryIDs) != 1 || reports[0].EntryIDs[0] != entryID {
+			t.Fatalf("Expected one report including entry %d, got %+v", entryID, reports)
+		}
+		if !reports[0].Archived || reports[0].SizeBytes == 0 {
+			t.Errorf("Expected an archived report with content, got %+v", reports[0])
+		}
+		reportID = reports[0].ID
+	})
+
+	t.Run("Download Archived Report", func(t *testing.T) {
+		original, err := os.ReadFile(reportPath)
+		if err != nil {
+			t.Fatalf("Failed to read generated report: %v", err)
+		}
+		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/children/%d/reports/%d", childID, reportID), authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		if !bytes.Equal(readResponseBody(t, resp), original) {
+			t.Error("Expected the downloaded report to match the generated one")
+		}
+	})
+
+	t.Run("Download Report Of Another Child", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/children/%d/reports/%d", childID+1, reportID), authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+	})
+
+	t.Run("Unlock Entry As Admin", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPut, fmt.Sprintf("/api/v1/documentation/%d/unlock", entryID), adminAuthToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+	})
 }
 
 func TestDocumentationEntriesEndpoints(t *testing.T) {
@@ -1026,6 +1332,41 @@ func TestDocumentationEntriesEndpoints(t *testing.T) {
 		}
 	})
 
+	// Test GET /api/v1/documentation/pending/poll
+	t.Run("Pending Poll Waits While Unchanged", func(t *testing.T) {
+		start := time.Now()
+		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/documentation/pending/poll?child_id=%d&count=1&timeout=1", childID), authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		if body := bytes.TrimSpace(readResponseBody(t, resp)); string(body) != `{"count":1}` {
+			t.Errorf("Expected the count 1, got %s", body)
+		}
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("Expected the poll to wait for the timeout, returned after %s", elapsed)
+		}
+	})
+
+	// The approval below ends the long poll.
+	polled := make(chan string, 1)
+	go func() {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/documentation/pending/poll?child_id=%d&count=1&timeout=20", ts.URL, childID), nil)
+		if err != nil {
+			polled <- err.Error()
+			return
+		}
+		req.Header.Set("Authorization", "Bearer "+authToken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			polled <- err.Error()
+			return
+		}
+		defer resp.Body.Close() //nolint:errcheck
+		body, _ := io.ReadAll(resp.Body)
+		polled <- fmt.Sprintf("%d %s", resp.StatusCode, bytes.TrimSpace(body))
+	}()
+
 	// Test PUT /api/v1/documentation/{entry_id}/approve
 	t.Run("Approve Documentation Entry", func(t *testing.T) {
 		reqBody := map[string]interface{}{
@@ -1042,6 +1383,17 @@ func TestDocumentationEntriesEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Pending Poll Returns On Approval", func(t *testing.T) {
+		select {
+		case result := <-polled:
+			if result != `200 {"count":0}` {
+				t.Errorf("Expected the poll to return the count 0, got %s", result)
+			}
+		case <-time.After(10 * time.Second):
+			t.Error("Expected the poll to return after the approval")
+		}
+	})
+
 	// Test DELETE /api/v1/documentation/{entry_id}
 	t.Run("Delete Documentation Entry", func(t *testing.T) {
 		resp := makeAuthenticatedRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/documentation/%d", entryID), adminAuthToken, nil, "application/json")
@@ -1104,4 +1456,1522 @@ func TestKitaMasterdataEndpoints(t *testing.T) {
 			t.Errorf("Expected updated Kita name in response, got %s", body)
 		}
 	})
+
+	// Test PUT /api/v1/kita-masterdata/theme
+	t.Run("Update Document Theme", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPut, "/api/v1/kita-masterdata/theme", adminAuthToken, map[string]string{
+			"font_family":  "Open Sans",
+			"accent_color": "#1a5276",
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status %d, got %d. Body: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodPut, "/api/v1/kita-masterdata/theme", adminAuthToken, map[string]string{
+			"accent_color": "blue",
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d for an invalid color, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/kita-masterdata", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var masterdata models.KitaMasterdata
+		if err := json.Unmarshal(readResponseBody(t, resp), &masterdata); err != nil {
+			t.Fatalf("Failed to decode master data: %v", err)
+		}
+		if masterdata.Theme.FontFamily == nil || *masterdata.Theme.FontFamily != "Open Sans" || masterdata.Theme.HasLogo {
+			t.Errorf("Expected the updated theme without logo, got %+v", masterdata.Theme)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/kita-masterdata/logo", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status %d without logo, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+	})
+
+	// Test PUT /api/v1/kita-masterdata/terminology
+	t.Run("Update Terminology", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPut, "/api/v1/kita-masterdata/terminology", adminAuthToken, map[string]string{
+			"teacher":  "pädagogische Fachkraft",
+			"teachers": "pädagogische Fachkräfte",
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status %d, got %d. Body: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodPut, "/api/v1/kita-masterdata/terminology", adminAuthToken, map[string]string{
+			"lehrer": "Lehrkraft",
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d for an unknown term, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/kita-masterdata/terminology", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var terms map[string]string
+		if err := json.Unmarshal(readResponseBody(t, resp), &terms); err != nil {
+			t.Fatalf("Failed to decode terminology: %v", err)
+		}
+		if terms["teachers"] != "pädagogische Fachkräfte" || terms["category"] != "Bildungsbereich" {
+			t.Errorf("Expected the replaced and the default terms, got %+v", terms)
+		}
+	})
+}
+
+func TestAnnouncementsEndpoints(t *testing.T) {
+	setupTest(t)
+
+	var announcementID int
+
+	t.Run("Create Announcement", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/announcements", adminAuthToken, map[string]string{
+			"title": "Neuer Hygieneplan",
+			"body":  "Bitte den aktualisierten Hygieneplan lesen.",
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+		}
+		var announcement models.Announcement
+		if err := json.Unmarshal(readResponseBody(t, resp), &announcement); err != nil {
+			t.Fatalf("failed to unmarshal announcement: %v", err)
+		}
+		announcementID = announcement.ID
+	})
+
+	t.Run("Teacher Cannot Create Announcement", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/announcements", authToken, map[string]string{
+			"title": "Test",
+			"body":  "Test",
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	t.Run("Acknowledge Announcement", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/announcements/unread", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var unread []models.Announcement
+		if err := json.Unmarshal(readResponseBody(t, resp), &unread); err != nil {
+			t.Fatalf("failed to unmarshal unread announcements: %v", err)
+		}
+		if len(unread) != 1 || unread[0].ID != announcementID {
+			t.Fatalf("Expected the new announcement to be unread, got %+v", unread)
+		}
+
+		for range 2 {
+			resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/announcements/"+strconv.Itoa(announcementID)+"/acknowledge", authToken, nil, "application/json")
+			defer resp.Body.Close() //nolint:errcheck
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected status %d, got %d. Body: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+			}
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/announcements/unread", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if body := readResponseBody(t, resp); !bytes.Equal(bytes.TrimSpace(body), []byte("[]")) {
+			t.Errorf("Expected no unread announcements, got %s", body)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/announcements/"+strconv.Itoa(announcementID)+"/acknowledgements", adminAuthToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var acknowledgements []models.AnnouncementAcknowledgement
+		if err := json.Unmarshal(readResponseBody(t, resp), &acknowledgements); err != nil {
+			t.Fatalf("failed to unmarshal acknowledgements: %v", err)
+		}
+		if len(acknowledgements) != 1 {
+			t.Errorf("Expected 1 acknowledgement, got %d", len(acknowledgements))
+		}
+	})
+
+	t.Run("Acknowledge Unknown Announcement", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/announcements/9999/acknowledge", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+	})
+
+	t.Run("Delete Announcement", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodDelete, "/api/v1/announcements/"+strconv.Itoa(announcementID), adminAuthToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("Expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+		}
+	})
+}
+
+func TestSchoolYearRolloverEndpoint(t *testing.T) {
+	setupTest(t)
+
+	createChild := func(t *testing.T, firstName string, isPreschooler bool) int {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/children", authToken, map[string]interface{}{
+			"first_name":     firstName,
+			"last_name":      "Rollover",
+			"birthdate":      time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC),
+			"admission_date": time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+			"is_preschooler": isPreschooler,
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var child models.Child
+		if err := json.Unmarshal(readResponseBody(t, resp), &child); err != nil || child.ID == 0 {
+			t.Fatalf("failed to create child: %v", err)
+		}
+		return child.ID
+	}
+	createTeacher := func(t *testing.T, username string) int {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", adminAuthToken, map[string]string{
+			"first_name": username,
+			"last_name":  "Rollover",
+			"username":   username,
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var teacher models.Teacher
+		if err := json.Unmarshal(readResponseBody(t, resp), &teacher); err != nil || teacher.ID == 0 {
+			t.Fatalf("failed to create teacher: %v", err)
+		}
+		return teacher.ID
+	}
+
+	stayingChildID := createChild(t, "Staying", true)
+	departingChildID := createChild(t, "Departing", true)
+	fromTeacherID := createTeacher(t, "rolloverfrom")
+	toTeacherID := createTeacher(t, "rolloverto")
+	for _, childID := range []int{stayingChildID, departingChildID} {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/assignments", authToken, map[string]interface{}{
+			"child_id":   childID,
+			"teacher_id": fromTeacherID,
+			"start_date": time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+		}, "application/json")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("failed to create assignment, status %d", resp.StatusCode)
+		}
+	}
+
+	rollover := func(t *testing.T, token string, dryRun bool) (*http.Response, models.RolloverSummary) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/school-year/rollover", token, map[string]interface{}{
+			"rollover_date":       time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC),
+			"departing_child_ids": []int{departingChildID},
+			"teacher_mapping":     map[string]int{strconv.Itoa(fromTeacherID): toTeacherID},
+			"dry_run":             dryRun,
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var summary models.RolloverSummary
+		json.Unmarshal(readResponseBody(t, resp), &summary) //nolint:errcheck
+		return resp, summary
+	}
+
+	t.Run("Teacher Cannot Roll Over", func(t *testing.T) {
+		resp, _ := rollover(t, authToken, true)
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		resp, summary := rollover(t, adminAuthToken, true)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		if !summary.DryRun || len(summary.ArchivedChildIDs) != 1 || len(summary.EndedAssignments) != 2 || len(summary.CreatedAssignments) != 1 {
+			t.Errorf("Unexpected dry run summary: %+v", summary)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/children/"+strconv.Itoa(departingChildID), authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var child models.Child
+		json.Unmarshal(readResponseBody(t, resp), &child) //nolint:errcheck
+		if child.ArchivedAt != nil {
+			t.Error("Expected dry run not to archive the child")
+		}
+	})
+
+	t.Run("Rollover", func(t *testing.T) {
+		resp, summary := rollover(t, adminAuthToken, false)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		if len(summary.CreatedAssignments) != 1 || summary.CreatedAssignments[0].ChildID != stayingChildID || summary.CreatedAssignments[0].TeacherID != toTeacherID {
+			t.Errorf("Unexpected created assignments: %+v", summary.CreatedAssignments)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/children", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var children []models.Child
+		json.Unmarshal(readResponseBody(t, resp), &children) //nolint:errcheck
+		for _, child := range children {
+			if child.ID == departingChildID {
+				t.Error("Expected archived child to be hidden from the children list")
+			}
+			if child.ID == stayingChildID && child.IsPreschooler {
+				t.Error("Expected pre-school flag to be reset")
+			}
+		}
+
+		resp, _ = rollover(t, adminAuthToken, false)
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected repeated rollover of archived child to fail with %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+	})
+}
+
+func TestApprovalDelegationEndpoints(t *testing.T) {
+	setupTest(t)
+
+	currentUserID := func(t *testing.T, token string) int {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/auth/me", token, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var user models.User
+		if err := json.Unmarshal(readResponseBody(t, resp), &user); err != nil || user.ID == 0 {
+			t.Fatalf("failed to fetch current user: %v", err)
+		}
+		return user.ID
+	}
+	adminUserID := currentUserID(t, adminAuthToken)
+	teacherUserID := currentUserID(t, authToken)
+
+	resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/children", authToken, map[string]interface{}{
+		"first_name":     "Delegation",
+		"last_name":      "Child",
+		"birthdate":      time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC),
+		"admission_date": time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}, "application/json")
+	var child models.Child
+	json.Unmarshal(readResponseBody(t, resp), &child) //nolint:errcheck
+	resp.Body.Close()                                 //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", adminAuthToken, map[string]string{
+		"first_name": "Delegation",
+		"last_name":  "Teacher",
+		"username":   "delegationteacher",
+	}, "application/json")
+	var teacher models.Teacher
+	json.Unmarshal(readResponseBody(t, resp), &teacher) //nolint:errcheck
+	resp.Body.Close()                                   //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/categories", adminAuthToken, map[string]string{
+		"name": "DelegationCategory",
+	}, "application/json")
+	var category models.Category
+	json.Unmarshal(readResponseBody(t, resp), &category) //nolint:errcheck
+	resp.Body.Close()                                    //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/documentation", authToken, map[string]interface{}{
+		"child_id":                child.ID,
+		"teacher_id":              teacher.ID,
+		"category_id":             category.ID,
+		"observation_description": "Delegated approval test.",
+		"observation_date":        time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC),
+	}, "application/json")
+	var entry models.DocumentationEntry
+	json.Unmarshal(readResponseBody(t, resp), &entry) //nolint:errcheck
+	resp.Body.Close()                                 //nolint:errcheck
+	if entry.ID == 0 {
+		t.Fatal("failed to create documentation entry")
+	}
+
+	approveOnBehalf := func(t *testing.T) *http.Response {
+		return makeAuthenticatedRequest(t, http.MethodPut, fmt.Sprintf("/api/v1/documentation/%d/approve", entry.ID), authToken, map[string]interface{}{
+			"approvedByTeacherId": teacher.ID,
+			"onBehalfOfUserId":    adminUserID,
+		}, "application/json")
+	}
+
+	t.Run("Approval On Behalf Without Delegation Is Forbidden", func(t *testing.T) {
+		resp := approveOnBehalf(t)
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	t.Run("Teacher Cannot Create Delegation", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/approval-delegations", authToken, map[string]interface{}{
+			"delegate_user_id": adminUserID,
+			"start_date":       time.Now().AddDate(0, 0, -1),
+			"end_date":         time.Now().AddDate(0, 0, 14),
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	var delegation models.ApprovalDelegation
+	t.Run("Create Delegation", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/approval-delegations", adminAuthToken, map[string]interface{}{
+			"delegate_user_id": teacherUserID,
+			"start_date":       time.Now().AddDate(0, 0, -1),
+			"end_date":         time.Now().AddDate(0, 0, 14),
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+		}
+		if err := json.Unmarshal(readResponseBody(t, resp), &delegation); err != nil {
+			t.Fatalf("failed to unmarshal delegation: %v", err)
+		}
+		if delegation.DelegatorUserID != adminUserID || delegation.DelegateUserID != teacherUserID {
+			t.Errorf("Unexpected delegation: %+v", delegation)
+		}
+	})
+
+	t.Run("Delegate Sees Delegation", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/approval-delegations", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var delegations []models.ApprovalDelegation
+		json.Unmarshal(readResponseBody(t, resp), &delegations) //nolint:errcheck
+		found := false
+		for _, d := range delegations {
+			if d.ID == delegation.ID {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected delegation %d in %+v", delegation.ID, delegations)
+		}
+	})
+
+	t.Run("Approval On Behalf With Delegation", func(t *testing.T) {
+		resp := approveOnBehalf(t)
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+		}
+	})
+
+	t.Run("Audit Trail Records Delegation", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/audit-log?entity_type=documentation_entry&entity_id=%d", entry.ID), adminAuthToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var auditEntries []models.AuditLogEntry
+		if err := json.Unmarshal(readResponseBody(t, resp), &auditEntries); err != nil {
+			t.Fatalf("failed to unmarshal audit log: %v", err)
+		}
+		if len(auditEntries) != 1 {
+			t.Fatalf("Expected 1 audit entry, got %d", len(auditEntries))
+		}
+		auditEntry := auditEntries[0]
+		if auditEntry.Action != models.AuditActionApproveDocumentationEntry ||
+			auditEntry.ActorUserID == nil || *auditEntry.ActorUserID != teacherUserID ||
+			auditEntry.OnBehalfOfUserID == nil || *auditEntry.OnBehalfOfUserID != adminUserID ||
+			auditEntry.DelegationID == nil || *auditEntry.DelegationID != delegation.ID {
+			t.Errorf("Unexpected audit entry: %+v", auditEntry)
+		}
+	})
+
+	t.Run("Audit Log Of Actor", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/audit-log?actor_user_id=%d", teacherUserID), adminAuthToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var auditEntries []models.AuditLogEntry
+		if err := json.Unmarshal(readResponseBody(t, resp), &auditEntries); err != nil {
+			t.Fatalf("failed to unmarshal audit log: %v", err)
+		}
+		if len(auditEntries) == 0 {
+			t.Fatalf("Expected audit entries of user %d", teacherUserID)
+		}
+		for _, auditEntry := range auditEntries {
+			if auditEntry.ActorUserID == nil || *auditEntry.ActorUserID != teacherUserID {
+				t.Errorf("Unexpected audit entry: %+v", auditEntry)
+			}
+		}
+	})
+
+	t.Run("Delete Delegation", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/approval-delegations/%d", delegation.ID), adminAuthToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("Expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+		}
+	})
+}
+
+func TestValidationRulesEndpoints(t *testing.T) {
+	setupTest(t)
+
+	putRules := func(t *testing.T, token string, rules map[string]interface{}) *http.Response {
+		return makeAuthenticatedRequest(t, http.MethodPut, "/api/v1/validation-rules", token, rules, "application/json")
+	}
+	t.Cleanup(func() {
+		resp := putRules(t, adminAuthToken, map[string]interface{}{})
+		resp.Body.Close() //nolint:errcheck
+	})
+
+	resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/children", authToken, map[string]interface{}{
+		"first_name":     "Rules",
+		"last_name":      "Child",
+		"birthdate":      time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC),
+		"admission_date": time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}, "application/json")
+	var child models.Child
+	json.Unmarshal(readResponseBody(t, resp), &child) //nolint:errcheck
+	resp.Body.Close()                                 //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", adminAuthToken, map[string]string{
+		"first_name": "Rules",
+		"last_name":  "Teacher",
+		"username":   "rulesteacher",
+	}, "application/json")
+	var teacher models.Teacher
+	json.Unmarshal(readResponseBody(t, resp), &teacher) //nolint:errcheck
+	resp.Body.Close()                                   //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/categories", adminAuthToken, map[string]string{
+		"name": "RulesCategory",
+	}, "application/json")
+	var category models.Category
+	json.Unmarshal(readResponseBody(t, resp), &category) //nolint:errcheck
+	resp.Body.Close()                                    //nolint:errcheck
+
+	createEntry := func(t *testing.T, description string) *http.Response {
+		return makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/documentation", authToken, map[string]interface{}{
+			"child_id":                child.ID,
+			"teacher_id":              teacher.ID,
+			"category_id":             category.ID,
+			"observation_description": description,
+			"observation_date":        time.Now().AddDate(0, 0, -1),
+		}, "application/json")
+	}
+
+	t.Run("Teacher Cannot Update Rules", func(t *testing.T) {
+		resp := putRules(t, authToken, map[string]interface{}{"min_observation_length": 50})
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	t.Run("Update Rules", func(t *testing.T) {
+		resp := putRules(t, adminAuthToken, map[string]interface{}{
+			"min_observation_length":    20,
+			"min_word_count":            3,
+			"duplicate_window_days":     7,
+			"required_tags_by_category": map[string][]string{strconv.Itoa(category.ID): {"sprache"}},
+		})
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+		}
+	})
+
+	t.Run("Get Rules", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/validation-rules", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		var rules models.ValidationRules
+		if err := json.Unmarshal(readResponseBody(t, resp), &rules); err != nil {
+			t.Fatalf("failed to unmarshal rules: %v", err)
+		}
+		if rules.MinObservationLength != 20 || len(rules.RequiredTagsByCategory[category.ID]) != 1 || rules.RequiredTagsByCategory[category.ID][0] != "#sprache" {
+			t.Errorf("Unexpected rules: %+v", rules)
+		}
+	})
+
+	violatedRules := func(t *testing.T, resp *http.Response) []string {
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+		var validationResp struct {
+			Violations []struct {
+				Rule  string `json:"rule"`
+				Field string `json:"field"`
+			} `json:"violations"`
+		}
+		if err := json.Unmarshal(readResponseBody(t, resp), &validationResp); err != nil {
+			t.Fatalf("failed to unmarshal validation error: %v", err)
+		}
+		var rules []string
+		for _, violation := range validationResp.Violations {
+			rules = append(rules, violation.Rule)
+		}
+		return rules
+	}
+
+	t.Run("Entry Missing Required Tag Is Rejected", func(t *testing.T) {
+		resp := createEntry(t, "Erzählt ausführlich vom Wochenende.")
+		defer resp.Body.Close() //nolint:errcheck
+		rules := violatedRules(t, resp)
+		if len(rules) != 1 || rules[0] != "required_category_tags" {
+			t.Errorf("Unexpected violations: %v", rules)
+		}
+	})
+
+	t.Run("Entry With Too Few Words Is Rejected", func(t *testing.T) {
+		resp := createEntry(t, "Wochenendeerzählung ausführlichst #sprache")
+		defer resp.Body.Close() //nolint:errcheck
+		rules := violatedRules(t, resp)
+		if len(rules) != 1 || rules[0] != "min_word_count" {
+			t.Errorf("Unexpected violations: %v", rules)
+		}
+	})
+
+	t.Run("Entry Satisfying Rules Is Created", func(t *testing.T) {
+		resp := createEntry(t, "Erzählt ausführlich vom Wochenende. #Sprache")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("Expected status %d, got %d. Body: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+		}
+	})
+
+	t.Run("Copy-Pasted Entry Is Rejected", func(t *testing.T) {
+		resp := createEntry(t, "Erzählt ausführlich vom  Wochenende. #Sprache")
+		defer resp.Body.Close() //nolint:errcheck
+		rules := violatedRules(t, resp)
+		if len(rules) != 1 || rules[0] != "duplicate_observation" {
+			t.Errorf("Unexpected violations: %v", rules)
+		}
+	})
+}
+
+func TestCompletenessEndpoints(t *testing.T) {
+	setupTest(t)
+
+	resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/children", authToken, map[string]interface{}{
+		"first_name":     "Complete",
+		"last_name":      "Child",
+		"birthdate":      time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC),
+		"admission_date": time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}, "application/json")
+	var child models.Child
+	json.Unmarshal(readResponseBody(t, resp), &child) //nolint:errcheck
+	resp.Body.Close()                                 //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", adminAuthToken, map[string]string{
+		"first_name": "Complete",
+		"last_name":  "Teacher",
+		"username":   "completeteacher",
+	}, "application/json")
+	var teacher models.Teacher
+	json.Unmarshal(readResponseBody(t, resp), &teacher) //nolint:errcheck
+	resp.Body.Close()                                   //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/categories", adminAuthToken, map[string]string{
+		"name": "CompletenessCategory",
+	}, "application/json")
+	var category models.Category
+	json.Unmarshal(readResponseBody(t, resp), &category) //nolint:errcheck
+	resp.Body.Close()                                    //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/documentation", authToken, map[string]interface{}{
+		"child_id":                child.ID,
+		"teacher_id":              teacher.ID,
+		"category_id":             category.ID,
+		"observation_description": "Spielt konzentriert mit Bausteinen",
+		"observation_date":        time.Now().AddDate(0, 0, -3),
+	}, "application/json")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+	}
+	resp.Body.Close() //nolint:errcheck
+
+	t.Run("Get Child Completeness", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/children/%d/completeness", child.ID), authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var completeness models.ChildCompleteness
+		if err := json.Unmarshal(readResponseBody(t, resp), &completeness); err != nil {
+			t.Fatalf("Failed to unmarshal completeness: %v", err)
+		}
+		if completeness.CategoriesCovered != 1 || completeness.EntryCount != 1 {
+			t.Errorf("Expected 1 covered category with 1 entry, got %d categories and %d entries", completeness.CategoriesCovered, completeness.EntryCount)
+		}
+		if completeness.LastEntryAgeDays == nil || *completeness.LastEntryAgeDays != 3 {
+			t.Errorf("Expected last entry age of 3 days, got %v", completeness.LastEntryAgeDays)
+		}
+		for _, coverage := range completeness.Categories {
+			if coverage.CategoryID == category.ID && coverage.EntryCount != 1 {
+				t.Errorf("Expected 1 entry for category %d, got %d", category.ID, coverage.EntryCount)
+			}
+		}
+	})
+
+	t.Run("Category Suggestions", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/categories", adminAuthToken, map[string]interface{}{
+			"name":           "Abschlussprojekt",
+			"min_age_months": 120,
+		}, "application/json")
+		var banded models.Category
+		json.Unmarshal(readResponseBody(t, resp), &banded) //nolint:errcheck
+		resp.Body.Close()                                  //nolint:errcheck
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/children/%d/category-suggestions", child.ID), authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var suggestions []models.CategorySuggestion
+		if err := json.Unmarshal(readResponseBody(t, resp), &suggestions); err != nil {
+			t.Fatalf("Failed to unmarshal category suggestions: %v", err)
+		}
+		if len(suggestions) < 2 {
+			t.Fatalf("Expected at least 2 suggestions, got %v", suggestions)
+		}
+		last := suggestions[len(suggestions)-1]
+		if last.CategoryID != banded.ID || !slices.Contains(last.Reasons, models.SuggestionReasonOutsideAgeBand) {
+			t.Errorf("Expected the category for older children last, got %+v", last)
+		}
+	})
+
+	t.Run("Get Child Completeness Not Found", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/children/99999/completeness", authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+	})
+
+	t.Run("Get All Completeness", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/completeness", authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var scores []models.ChildCompleteness
+		if err := json.Unmarshal(readResponseBody(t, resp), &scores); err != nil {
+			t.Fatalf("Failed to unmarshal completeness scores: %v", err)
+		}
+		found := false
+		for _, score := range scores {
+			if score.ChildID == child.ID {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected child %d in completeness scores", child.ID)
+		}
+	})
+
+	t.Run("Report With Completeness Appendix", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/documents/child-report/%d?include_completeness=true", child.ID), authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+		}
+	})
+
+	t.Run("Report With Invalid Completeness Flag", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/documents/child-report/%d?include_completeness=maybe", child.ID), authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+}
+
+func TestCategoryFormEndpoints(t *testing.T) {
+	setupTest(t)
+
+	resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/categories", adminAuthToken, map[string]interface{}{
+		"name": "FormCategory",
+		"form_schema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"gross_motor": map[string]string{"type": "boolean", "title": "Grobmotorik"},
+				"fine_motor":  map[string]string{"type": "boolean", "title": "Feinmotorik"},
+			},
+			"required": []string{"gross_motor"},
+		},
+	}, "application/json")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+	}
+	var category models.Category
+	json.Unmarshal(readResponseBody(t, resp), &category) //nolint:errcheck
+	resp.Body.Close()                                    //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/children", authToken, map[string]interface{}{
+		"first_name":     "Form",
+		"last_name":      "Child",
+		"birthdate":      time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC),
+		"admission_date": time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}, "application/json")
+	var child models.Child
+	json.Unmarshal(readResponseBody(t, resp), &child) //nolint:errcheck
+	resp.Body.Close()                                 //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", adminAuthToken, map[string]string{
+		"first_name": "Form",
+		"last_name":  "Teacher",
+		"username":   "formteacher",
+	}, "application/json")
+	var teacher models.Teacher
+	json.Unmarshal(readResponseBody(t, resp), &teacher) //nolint:errcheck
+	resp.Body.Close()                                   //nolint:errcheck
+
+	createEntry := func(t *testing.T, structuredData map[string]interface{}) *http.Response {
+		return makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/documentation", authToken, map[string]interface{}{
+			"child_id":                child.ID,
+			"teacher_id":              teacher.ID,
+			"category_id":             category.ID,
+			"observation_description": "Klettert sicher auf das Gerüst",
+			"observation_date":        time.Now().AddDate(0, 0, -1),
+			"structured_data":         structuredData,
+		}, "application/json")
+	}
+
+	t.Run("Invalid Form Schema", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/categories", adminAuthToken, map[string]interface{}{
+			"name":        "BrokenFormCategory",
+			"form_schema": map[string]interface{}{"type": "array"},
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
+	t.Run("Create Entry With Invalid Structured Data", func(t *testing.T) {
+		resp := createEntry(t, map[string]interface{}{"fine_motor": "yes"})
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+		var body struct {
+			Violations []struct {
+				Rule  string `json:"rule"`
+				Field string `json:"field"`
+			} `json:"violations"`
+		}
+		if err := json.Unmarshal(readResponseBody(t, resp), &body); err != nil {
+			t.Fatalf("Failed to unmarshal validation error: %v", err)
+		}
+		if len(body.Violations) != 2 {
+			t.Errorf("Expected 2 violations, got %+v", body.Violations)
+		}
+	})
+
+	t.Run("Create Entry With Structured Data", func(t *testing.T) {
+		resp := createEntry(t, map[string]interface{}{"gross_motor": true, "fine_motor": false})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+		}
+		resp.Body.Close() //nolint:errcheck
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/documentation/child/%d", child.ID), authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		var entries []models.DocumentationEntry
+		if err := json.Unmarshal(readResponseBody(t, resp), &entries); err != nil {
+			t.Fatalf("Failed to unmarshal entries: %v", err)
+		}
+		if len(entries) != 1 || entries[0].StructuredData["gross_motor"] != true || entries[0].StructuredData["fine_motor"] != false {
+			t.Errorf("Expected the stored structured data, got %+v", entries)
+		}
+	})
+}
+
+func TestDemoModeEndpoints(t *testing.T) {
+	setupTest(t)
+
+	resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/children", authToken, map[string]interface{}{
+		"first_name":     "Realname",
+		"last_name":      "Productionchild",
+		"birthdate":      time.Date(2021, time.June, 14, 0, 0, 0, 0, time.UTC),
+		"admission_date": time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}, "application/json")
+	var child models.Child
+	json.Unmarshal(readResponseBody(t, resp), &child) //nolint:errcheck
+	resp.Body.Close()                                 //nolint:errcheck
+
+	getChildren := func(t *testing.T, token string) ([]models.Child, *http.Response) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/children", token, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		var children []models.Child
+		if err := json.Unmarshal(readResponseBody(t, resp), &children); err != nil {
+			t.Fatalf("Failed to unmarshal children: %v", err)
+		}
+		return children, resp
+	}
+
+	t.Run("Teacher Cannot Enable Demo Mode", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/demo-mode", authToken, nil, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	t.Run("Enable Demo Mode", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/demo-mode", adminAuthToken, map[string]bool{"refresh": true}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+		}
+		var status models.DemoModeStatus
+		json.Unmarshal(readResponseBody(t, resp), &status) //nolint:errcheck
+		if !status.Enabled || status.SnapshotCreatedAt == nil {
+			t.Errorf("Expected demo mode to be enabled, got %+v", status)
+		}
+	})
+	t.Cleanup(func() {
+		resp := makeAuthenticatedRequest(t, http.MethodDelete, "/api/v1/demo-mode", adminAuthToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+	})
+
+	t.Run("Demo Session Sees Anonymized Data", func(t *testing.T) {
+		children, resp := getChildren(t, adminAuthToken)
+		if resp.Header.Get("X-Demo-Mode") != "true" {
+			t.Errorf("Expected X-Demo-Mode header on demo responses")
+		}
+		found := false
+		for _, demoChild := range children {
+			if demoChild.FirstName == "Realname" || demoChild.LastName != "Beispiel" {
+				t.Errorf("Expected anonymized child, got %s %s", demoChild.FirstName, demoChild.LastName)
+			}
+			if demoChild.ID == child.ID {
+				found = true
+				if demoChild.Birthdate.Day() != 1 {
+					t.Errorf("Expected birthdate to be moved to the first of the month, got %v", demoChild.Birthdate)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Expected child %d in the demo dataset", child.ID)
+		}
+	})
+
+	t.Run("Demo Changes Do Not Touch Production", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/children", adminAuthToken, map[string]interface{}{
+			"first_name": "Democreated",
+			"last_name":  "Child",
+			"birthdate":  time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+		}, "application/json")
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+		}
+		resp.Body.Close() //nolint:errcheck
+
+		children, resp := getChildren(t, authToken)
+		if resp.Header.Get("X-Demo-Mode") != "" {
+			t.Errorf("Expected no X-Demo-Mode header for production sessions")
+		}
+		foundReal := false
+		for _, productionChild := range children {
+			if productionChild.FirstName == "Democreated" {
+				t.Errorf("Child created in demo mode leaked into production")
+			}
+			if productionChild.ID == child.ID && productionChild.FirstName == "Realname" {
+				foundReal = true
+			}
+		}
+		if !foundReal {
+			t.Errorf("Expected the real child in production data")
+		}
+	})
+
+	t.Run("Disable Demo Mode", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodDelete, "/api/v1/demo-mode", adminAuthToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+
+		children, resp := getChildren(t, adminAuthToken)
+		if resp.Header.Get("X-Demo-Mode") != "" {
+			t.Errorf("Expected production data after disabling demo mode")
+		}
+		for _, productionChild := range children {
+			if productionChild.ID == child.ID && productionChild.FirstName != "Realname" {
+				t.Errorf("Expected real name after disabling demo mode, got %s", productionChild.FirstName)
+			}
+		}
+	})
+}
+
+func TestRoutePolicies(t *testing.T) {
+	setupTest(t)
+
+	t.Run("Teacher Cannot Dump Policies", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/route-policies", authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/route-policies", adminAuthToken, nil, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	var policies []models.RoutePolicy
+	if err := json.Unmarshal(readResponseBody(t, resp), &policies); err != nil {
+		t.Fatalf("Failed to unmarshal route policies: %v", err)
+	}
+	resp.Body.Close() //nolint:errcheck
+
+	t.Run("Dump Contains Enforced Policies", func(t *testing.T) {
+		expected := map[string]models.RouteAccess{
+			"GET /health":                        models.RouteAccessPublic,
+			"GET /api/v1/auth/me":                models.RouteAccessAuthenticated,
+			"GET /api/v1/users":                  models.RouteAccessRole,
+			"GET /api/v1/route-policies":         models.RouteAccessRole,
+			"DELETE /api/v1/children/{child_id}": models.RouteAccessRole,
+		}
+		found := make(map[string]models.RoutePolicy)
+		for _, policy := range policies {
+			found[policy.Method+" "+policy.Path] = policy
+		}
+		for route, access := range expected {
+			policy, ok := found[route]
+			if !ok {
+				t.Errorf("Expected policy for %s", route)
+				continue
+			}
+			if policy.Access != access {
+				t.Errorf("Expected access %s for %s, got %s", access, route, policy.Access)
+			}
+		}
+		if users := found["GET /api/v1/users"]; len(users.AllowedRoles) != 1 || users.AllowedRoles[0] != "admin" {
+			t.Errorf("Expected only admins on GET /api/v1/users, got %v", users.AllowedRoles)
+		}
+	})
+
+	// Every route is probed with the callers its policy rejects; rejected requests never reach a handler.
+	t.Run("Every Route Enforces Its Policy", func(t *testing.T) {
+		placeholder := regexp.MustCompile(`\{[^}]+\}`)
+		for _, policy := range policies {
+			if policy.Access == models.RouteAccessPublic {
+				continue
+			}
+			url := placeholder.ReplaceAllString(policy.Path, "999999")
+
+			resp := makeAuthenticatedRequest(t, policy.Method, url, "", nil, "application/json")
+			resp.Body.Close() //nolint:errcheck
+			if resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("%s %s: expected status %d without token, got %d", policy.Method, policy.Path, http.StatusUnauthorized, resp.StatusCode)
+			}
+
+			teacherAllowed := slices.Contains(policy.AllowedRoles, "teacher")
+			if teacherAllowed && policy.Method != http.MethodGet {
+				continue
+			}
+			resp = makeAuthenticatedRequest(t, policy.Method, url, authToken, nil, "application/json")
+			resp.Body.Close() //nolint:errcheck
+			if !teacherAllowed && resp.StatusCode != http.StatusForbidden {
+				t.Errorf("%s %s: expected status %d for teacher, got %d", policy.Method, policy.Path, http.StatusForbidden, resp.StatusCode)
+			}
+			if teacherAllowed && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+				t.Errorf("%s %s: expected teacher to pass the policy, got %d", policy.Method, policy.Path, resp.StatusCode)
+			}
+		}
+	})
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	setupTest(t)
+
+	getDocument := func(t *testing.T, url string, token string) models.OpenAPIDocument {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, url, token, nil, "")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var document models.OpenAPIDocument
+		if err := json.Unmarshal(readResponseBody(t, resp), &document); err != nil {
+			t.Fatalf("Failed to unmarshal OpenAPI document: %v", err)
+		}
+		resp.Body.Close() //nolint:errcheck
+		return document
+	}
+
+	t.Run("Teacher Does Not See Admin Routes", func(t *testing.T) {
+		document := getDocument(t, "/api/v1/openapi.json", authToken)
+		if _, ok := document.Paths["/api/v1/users"]; ok {
+			t.Errorf("Expected admin only route /api/v1/users to be hidden from teachers")
+		}
+		if _, ok := document.Paths["/api/v1/children/{child_id}"]["delete"]; ok {
+			t.Errorf("Expected admin only DELETE /api/v1/children/{child_id} to be hidden from teachers")
+		}
+		child, ok := document.Paths["/api/v1/children/{child_id}"]["get"]
+		if !ok {
+			t.Fatalf("Expected GET /api/v1/children/{child_id} for teachers")
+		}
+		if child.OperationID != "getApiV1ChildrenByChildId" || len(child.Parameters) != 1 || child.Parameters[0].Name != "child_id" {
+			t.Errorf("Unexpected operation %+v", child)
+		}
+		if health, ok := document.Paths["/health"]["get"]; !ok || len(health.Security) != 0 {
+			t.Errorf("Expected public GET /health without security requirement, got %+v", health)
+		}
+	})
+
+	t.Run("Teacher Cannot Select Another Role", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/openapi.json?role=admin", authToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	t.Run("Admin Sees Every Route", func(t *testing.T) {
+		document := getDocument(t, "/api/v1/openapi.json", adminAuthToken)
+		if _, ok := document.Paths["/api/v1/users"]["get"]; !ok {
+			t.Errorf("Expected GET /api/v1/users for admins")
+		}
+	})
+
+	t.Run("Admin Selects Teacher Role", func(t *testing.T) {
+		document := getDocument(t, "/api/v1/openapi.json?role=teacher", adminAuthToken)
+		if _, ok := document.Paths["/api/v1/users"]; ok {
+			t.Errorf("Expected /api/v1/users to be hidden in the teacher document")
+		}
+	})
+
+	t.Run("Error Responses Document Their Codes", func(t *testing.T) {
+		document := getDocument(t, "/api/v1/openapi.json", authToken)
+		envelope := document.Components.Schemas["Error"]
+		if envelope == nil || envelope.Properties["code"] == nil || envelope.Properties["violations"] == nil {
+			t.Fatalf("Expected the error envelope with code and violations, got %+v", envelope)
+		}
+		codes := map[string]string{}
+		for _, code := range document.Components.Schemas["ErrorCode"].OneOf {
+			codes[code.Const] = code.Description
+		}
+		for code, status := range map[string]string{
+			services.CodeChildNotFound:             "404",
+			services.CodeEntryAlreadyApproved:      "409",
+			services.CodeValidationFailed:          "400",
+			middleware.CodeTermsAcceptanceRequired: "403",
+		} {
+			if !strings.HasPrefix(codes[code], status+": ") {
+				t.Errorf("Expected code %s with status %s, got %q", code, status, codes[code])
+			}
+		}
+		if len(codes) != len(services.DomainErrors)+5 {
+			t.Errorf("Expected every domain error and the codes of the middleware, got %d codes", len(codes))
+		}
+		child := document.Paths["/api/v1/children/{child_id}"]["get"]
+		if schema := child.Responses["4XX"].Content["application/json"].Schema; schema == nil || schema.Ref != "#/components/schemas/Error" {
+			t.Errorf("Expected the client errors to reference the error envelope, got %+v", child.Responses["4XX"])
+		}
+	})
+}
+
+func TestDocumentationDigestEndpoints(t *testing.T) {
+	setupTest(t)
+
+	t.Run("Update Digest Preferences", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPut, "/api/v1/notifications/preferences", authToken, map[string]interface{}{
+			"push_enabled":  true,
+			"notify_digest": true,
+			"digest_email":  "no-email",
+		}, "application/json")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d for an invalid address, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodPut, "/api/v1/notifications/preferences", authToken, map[string]interface{}{
+			"push_enabled":  true,
+			"notify_digest": true,
+			"digest_email":  "testuser@kita.example",
+		}, "application/json")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/notifications/preferences", authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		var preferences models.NotificationPreferences
+		if err := json.Unmarshal(readResponseBody(t, resp), &preferences); err != nil {
+			t.Fatalf("Failed to unmarshal notification preferences: %v", err)
+		}
+		if !preferences.WantsDigest() || *preferences.DigestEmail != "testuser@kita.example" {
+			t.Errorf("Expected the digest to be enabled for testuser@kita.example, got %+v", preferences)
+		}
+	})
+
+	t.Run("Get Digest Without Teacher", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/notifications/digest", adminAuthToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+	})
+
+	t.Run("Get Digest", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", adminAuthToken, map[string]string{
+			"first_name": "Digest",
+			"last_name":  "Teacher",
+			"username":   "testuser",
+		}, "application/json")
+		var teacher models.Teacher
+		json.Unmarshal(readResponseBody(t, resp), &teacher) //nolint:errcheck
+		resp.Body.Close()                                   //nolint:errcheck
+
+		resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/children", authToken, map[string]interface{}{
+			"first_name":     "Digest",
+			"last_name":      "Child",
+			"birthdate":      "2022-03-01",
+			"gender":         "other",
+			"admission_date": time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+		}, "application/json")
+		var child models.Child
+		json.Unmarshal(readResponseBody(t, resp), &child) //nolint:errcheck
+		resp.Body.Close()                                 //nolint:errcheck
+
+		resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/assignments", authToken, map[string]interface{}{
+			"child_id":   child.ID,
+			"teacher_id": teacher.ID,
+			"start_date": time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+		}, "application/json")
+		resp.Body.Close() //nolint:errcheck
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/notifications/digest", authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+		}
+		var digest models.DocumentationDigest
+		if err := json.Unmarshal(readResponseBody(t, resp), &digest); err != nil {
+			t.Fatalf("Failed to unmarshal digest: %v", err)
+		}
+		if digest.TeacherID != teacher.ID || digest.WeekStart.Weekday() != time.Monday {
+			t.Errorf("Expected the current week of teacher %d, got %+v", teacher.ID, digest)
+		}
+		if len(digest.UndocumentedChildren) != 1 || digest.UndocumentedChildren[0].ChildID != child.ID {
+			t.Errorf("Expected the assigned child without observations, got %+v", digest.UndocumentedChildren)
+		}
+	})
+}
+
+func TestQualityReportEndpoints(t *testing.T) {
+	setupTest(t)
+	month := models.Today(time.Now()).Format("2006-01")
+
+	resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", adminAuthToken, map[string]string{
+		"first_name": "Quality",
+		"last_name":  "Teacher",
+		"username":   "qualityteacher",
+	}, "application/json")
+	var teacher models.Teacher
+	json.Unmarshal(readResponseBody(t, resp), &teacher) //nolint:errcheck
+	resp.Body.Close()                                   //nolint:errcheck
+
+	t.Run("Reminders Are Counted", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/notifications/reminders/%d", teacher.ID), adminAuthToken, map[string]string{
+			"message": "Bitte die Beobachtungen der Woche dokumentieren",
+		}, "application/json")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("Expected status %d, got %d", http.StatusAccepted, resp.StatusCode)
+		}
+
+		resp = makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/quality-reports/"+month, adminAuthToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, readResponseBody(t, resp))
+		}
+		var report models.QualityReport
+		if err := json.Unmarshal(readResponseBody(t, resp), &report); err != nil {
+			t.Fatalf("Failed to unmarshal quality report: %v", err)
+		}
+		if report.Month != month || report.Facility.RemindersSent != 1 || report.Facility.RemindersIgnored != 0 {
+			t.Errorf("Expected one pending reminder in %s, got %+v", month, report)
+		}
+	})
+
+	t.Run("Download Quality Report", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/quality-reports/"+month+"/pdf", adminAuthToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		if contentType := resp.Header.Get("Content-Type"); contentType != "application/pdf" {
+			t.Errorf("Expected a PDF, got %s", contentType)
+		}
+		if body := readResponseBody(t, resp); !bytes.HasPrefix(body, []byte("%PDF-")) {
+			t.Errorf("Expected the body to be a PDF document")
+		}
+	})
+
+	t.Run("Invalid Month", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/quality-reports/2025-13", adminAuthToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
+	t.Run("Teachers Cannot Access Quality Reports", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/quality-reports", authToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+}
+
+func TestTermsAcceptance(t *testing.T) {
+	setupTest(t)
+
+	resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/terms/versions", adminAuthToken, map[string]string{
+		"terms_of_use":   "Die Dokumentation dient ausschließlich der pädagogischen Arbeit.",
+		"privacy_policy": "Beobachtungen werden verschlüsselt gespeichert.",
+		"change_summary": "Erste Fassung",
+	}, "application/json")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+	}
+	var published models.TermsVersion
+	json.Unmarshal(readResponseBody(t, resp), &published) //nolint:errcheck
+	resp.Body.Close()                                     //nolint:errcheck
+
+	t.Run("API Is Blocked Until Accepted", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/children", authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+		if body := string(readResponseBody(t, resp)); !strings.Contains(body, "TERMS_ACCEPTANCE_REQUIRED") {
+			t.Errorf("Expected the terms acceptance to be required, got %s", body)
+		}
+	})
+
+	t.Run("Terms Status", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/terms", authToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var status models.TermsStatus
+		json.Unmarshal(readResponseBody(t, resp), &status) //nolint:errcheck
+		if !status.AcceptanceRequired || status.Current == nil || status.Current.Version != published.Version {
+			t.Errorf("Expected version %d to be pending, got %+v", published.Version, status)
+		}
+	})
+
+	t.Run("Outdated Version Is Rejected", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/terms/accept", authToken, map[string]int{"version": published.Version - 1}, "application/json")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
+	t.Run("Accept Current Version", func(t *testing.T) {
+		for _, token := range []string{authToken, adminAuthToken} {
+			resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/terms/accept", token, map[string]int{"version": published.Version}, "application/json")
+			resp.Body.Close() //nolint:errcheck
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+			}
+		}
+
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/children", authToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+	})
+
+	t.Run("Acceptance Is Audited", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/audit-log", adminAuthToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var entries []models.AuditLogEntry
+		json.Unmarshal(readResponseBody(t, resp), &entries) //nolint:errcheck
+		accepted := 0
+		for _, entry := range entries {
+			if entry.Action == models.AuditActionAcceptTerms && entry.EntityID != nil && *entry.EntityID == published.Version {
+				accepted++
+			}
+		}
+		if accepted != 2 {
+			t.Errorf("Expected 2 audited acceptances, got %d", accepted)
+		}
+	})
+}
+
+func TestStatusEndpoint(t *testing.T) {
+	setupTest(t)
+
+	getStatus := func(t *testing.T) models.SystemStatus {
+		resp := makeUnauthenticatedRequest(t, http.MethodGet, "/api/v1/status", nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var status models.SystemStatus
+		if err := json.Unmarshal(readResponseBody(t, resp), &status); err != nil {
+			t.Fatalf("Failed to unmarshal status: %v", err)
+		}
+		return status
+	}
+
+	t.Run("Status Without Login", func(t *testing.T) {
+		status := getStatus(t)
+		if status.Version == "" || status.Features == nil || status.Banner != nil {
+			t.Errorf("Expected a version, features and no banner, got %+v", status)
+		}
+	})
+
+	t.Run("Admin Sets Banner", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPut, "/api/v1/status/banner", adminAuthToken, map[string]string{
+			"message": "Am Freitag ab 18 Uhr ist Kitadoc wegen Wartungsarbeiten nicht erreichbar.",
+			"level":   models.BannerLevelMaintenance,
+		}, "application/json")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+
+		status := getStatus(t)
+		if status.Banner == nil || status.Banner.Level != models.BannerLevelMaintenance {
+			t.Errorf("Expected the maintenance banner, got %+v", status.Banner)
+		}
+	})
+
+	t.Run("Teachers Cannot Set Banner", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPut, "/api/v1/status/banner", authToken, map[string]string{
+			"message": "Hallo",
+			"level":   models.BannerLevelInfo,
+		}, "application/json")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	t.Run("Admin Removes Banner", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodDelete, "/api/v1/status/banner", adminAuthToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("Expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+		}
+		if status := getStatus(t); status.Banner != nil {
+			t.Errorf("Expected no banner, got %+v", status.Banner)
+		}
+	})
+}
+
+func TestEmailTemplateEndpoints(t *testing.T) {
+	setupTest(t)
+
+	t.Run("Publish New Version", func(t *testing.T) {
+		for _, subject := range []string{"Rückmeldung zu {{child_name}}", "Bitte überarbeiten: {{child_name}}"} {
+			resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/email-templates/rejection/versions", adminAuthToken, map[string]string{
+				"subject": subject,
+				"body":    "Hallo {{teacher_name}},\n\n{{reason}}",
+			}, "application/json")
+			resp.Body.Close() //nolint:errcheck
+			if resp.StatusCode != http.StatusCreated {
+				t.Fatalf("Expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+			}
+		}
+
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/email-templates/rejection/versions", adminAuthToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		var versions []models.EmailTemplate
+		json.Unmarshal(readResponseBody(t, resp), &versions) //nolint:errcheck
+		if len(versions) != 3 || versions[0].Version != 2 || versions[2].Version != 0 {
+			t.Errorf("Expected versions 2, 1 and the default, got %+v", versions)
+		}
+	})
+
+	t.Run("Preview Current Version", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/email-templates/rejection/preview", adminAuthToken, map[string]any{
+			"variables": map[string]string{"child_name": "Lena"},
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var rendered models.RenderedEmail
+		json.Unmarshal(readResponseBody(t, resp), &rendered) //nolint:errcheck
+		if rendered.Subject != "Bitte überarbeiten: Lena" {
+			t.Errorf("Expected the subject of the current version, got %q", rendered.Subject)
+		}
+	})
+
+	t.Run("Unknown Placeholder Is Rejected", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/email-templates/reminder/versions", adminAuthToken, map[string]string{
+			"subject": "Erinnerung",
+			"body":    "Hallo {{child_name}}",
+		}, "application/json")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
+	t.Run("Teachers Cannot Edit Templates", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/email-templates", authToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+}
+
+func TestUptimeEndpoints(t *testing.T) {
+	setupTest(t)
+
+	var incident models.UptimeIncident
+	t.Run("Annotate Incident", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/admin/uptime/incidents", adminAuthToken, map[string]any{
+			"started_at": time.Now().Add(-time.Hour),
+			"ended_at":   time.Now().Add(-30 * time.Minute),
+			"note":       "Neustart nach Sicherheitsupdate",
+		}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+		}
+		json.Unmarshal(readResponseBody(t, resp), &incident) //nolint:errcheck
+	})
+
+	t.Run("Uptime History", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/admin/uptime?days=7", adminAuthToken, nil, "")
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var report models.UptimeReport
+		if err := json.Unmarshal(readResponseBody(t, resp), &report); err != nil {
+			t.Fatalf("Failed to unmarshal uptime report: %v", err)
+		}
+		if len(report.Days) != 7 || len(report.Incidents) != 1 || report.Incidents[0].ID != incident.ID {
+			t.Errorf("Expected 7 days and the annotated incident, got %+v", report)
+		}
+	})
+
+	t.Run("Invalid Days", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/admin/uptime?days=1000", adminAuthToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+
+	t.Run("Teachers Cannot Access Uptime", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodGet, "/api/v1/admin/uptime", authToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, resp.StatusCode)
+		}
+	})
+
+	t.Run("Delete Incident", func(t *testing.T) {
+		resp := makeAuthenticatedRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/admin/uptime/incidents/%d", incident.ID), adminAuthToken, nil, "")
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("Expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+		}
+	})
 }
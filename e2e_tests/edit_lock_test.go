@@ -0,0 +1,76 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+	"kitadoc-backend/testsupport"
+)
+
+func TestEditLockEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	maria := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	thomas := h.MustCreateTeacher(models.Teacher{FirstName: "Thomas", LastName: "Weber"})
+	mariaToken, thomasToken := h.MustLogin(maria.Username), h.MustLogin(thomas.Username)
+	adminToken := h.MustLogin(h.MustCreateUser(string(data.RoleAdmin)).Username)
+	entry := h.MustCreateEntry(models.DocumentationEntry{TeacherID: maria.ID})
+	lockURL := fmt.Sprintf("/api/v1/documentation/entry/%d/edit-lock", entry.ID)
+
+	t.Run("Nobody Edits A New Entry", func(t *testing.T) {
+		h.MustDo(http.MethodGet, lockURL, thomasToken, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, "/api/v1/documentation/entry/999999/edit-lock", thomasToken, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("Other Teachers See Who Edits", func(t *testing.T) {
+		var lock models.EditLock
+		h.MustDo(http.MethodPut, lockURL, mariaToken, nil, http.StatusOK, &lock)
+		if lock.EditorName != "Maria Schmidt" || !lock.ExpiresAt.Equal(h.Now().Add(services.EditLockTTL)) {
+			t.Errorf("Unexpected lock %+v", lock)
+		}
+
+		var conflict struct {
+			Error string          `json:"error"`
+			Code  string          `json:"code"`
+			Lock  models.EditLock `json:"lock"`
+		}
+		h.MustDo(http.MethodPut, lockURL, thomasToken, nil, http.StatusConflict, &conflict)
+		if conflict.Code != services.CodeEntryBeingEdited || conflict.Error != "documentation entry is currently edited by Maria Schmidt" {
+			t.Errorf("Unexpected conflict %+v", conflict)
+		}
+		h.MustDo(http.MethodDelete, lockURL, thomasToken, nil, http.StatusForbidden, nil)
+	})
+
+	t.Run("Heartbeats Keep The Lock", func(t *testing.T) {
+		h.SetClock(h.Now().Add(services.EditLockTTL - time.Second))
+		var lock models.EditLock
+		h.MustDo(http.MethodPut, lockURL, mariaToken, nil, http.StatusOK, &lock)
+		if !lock.ExpiresAt.Equal(h.Now().Add(services.EditLockTTL)) {
+			t.Errorf("Expected the renewed lock to expire at %s, got %s", h.Now().Add(services.EditLockTTL), lock.ExpiresAt)
+		}
+		h.SetClock(h.Now().Add(services.EditLockTTL - time.Second))
+		h.MustDo(http.MethodPut, lockURL, thomasToken, nil, http.StatusConflict, nil)
+	})
+
+	t.Run("Expired Locks Are Taken Over", func(t *testing.T) {
+		h.SetClock(h.Now().Add(services.EditLockTTL))
+		h.MustDo(http.MethodGet, lockURL, thomasToken, nil, http.StatusNoContent, nil)
+		var lock models.EditLock
+		h.MustDo(http.MethodPut, lockURL, thomasToken, nil, http.StatusOK, &lock)
+		if lock.EditorName != "Thomas Weber" {
+			t.Errorf("Expected Thomas to edit the entry, got %+v", lock)
+		}
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		h.MustDo(http.MethodDelete, lockURL, thomasToken, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodDelete, lockURL, thomasToken, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodPut, lockURL, mariaToken, nil, http.StatusOK, nil)
+		h.MustDo(http.MethodDelete, lockURL, adminToken, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, lockURL, mariaToken, nil, http.StatusNoContent, nil)
+	})
+}
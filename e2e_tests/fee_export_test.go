@@ -0,0 +1,95 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestFeeExportEndpoint(t *testing.T) {
+	h := testsupport.New(t)
+	admin := h.MustCreateUser("admin")
+	token := h.MustLogin(admin.Username)
+	child := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)})
+	h.SetClock(time.Date(2024, time.November, 4, 12, 0, 0, 0, models.FacilityLocation()))
+
+	h.MustDo(http.MethodPost, fmt.Sprintf("/api/v1/children/%d/care-contracts", child.ID), token,
+		map[string]any{"weekly_hours": 35, "valid_from": "2024-08-01"}, http.StatusCreated, nil)
+	for _, record := range []struct {
+		eventType string
+		at        time.Time
+	}{
+		{models.AttendanceArrival, time.Date(2024, time.October, 7, 7, 30, 0, 0, models.FacilityLocation())},
+		{models.AttendanceDeparture, time.Date(2024, time.October, 7, 15, 0, 0, 0, models.FacilityLocation())},
+	} {
+		h.MustDo(http.MethodPost, fmt.Sprintf("/api/v1/children/%d/attendance", child.ID), token,
+			map[string]any{"event_type": record.eventType, "recorded_at": record.at}, http.StatusCreated, nil)
+	}
+
+	export := func(query, reauthenticationToken string) (*http.Response, string) {
+		request, err := http.NewRequest(http.MethodGet, h.Server.URL+"/api/v1/exports/fees"+query, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+		if reauthenticationToken != "" {
+			request.Header.Set(middleware.ReauthenticationHeader, reauthenticationToken)
+		}
+		resp, err := h.Server.Client().Do(request)
+		if err != nil {
+			t.Fatalf("Failed to export fees: %v", err)
+		}
+		return resp, string(readResponseBody(t, resp))
+	}
+
+	if resp, _ := export("", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected the export to require a password confirmation, got %d", resp.StatusCode)
+	}
+	var reauthentication struct {
+		Token string `json:"reauthentication_token"`
+	}
+	h.MustDo(http.MethodPost, "/api/v1/auth/reauthenticate", token, map[string]string{"password": testsupport.Password}, http.StatusOK, &reauthentication)
+
+	// The past month by default
+	resp, body := export("", reauthentication.Token)
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/csv") {
+		t.Fatalf("Expected the CSV export, got %d: %s", resp.StatusCode, body)
+	}
+	if disposition := resp.Header.Get("Content-Disposition"); !strings.Contains(disposition, "beitraege-2024-10.csv") {
+		t.Errorf("Expected the export to be named after October, got %q", disposition)
+	}
+	want := "child_id;first_name;last_name;birthdate;month;booked_weekly_hours;attendance_days;attended_hours\n" +
+		strconv.Itoa(child.ID) + ";Anna;Müller;15.03.2021;2024-10;35;1;7,5\n"
+	if body != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, body)
+	}
+
+	if resp, body := export("?month=2024-07", reauthentication.Token); resp.StatusCode != http.StatusOK || strings.Count(body, "\n") != 1 {
+		t.Errorf("Expected only the header before the contract started, got %d: %s", resp.StatusCode, body)
+	}
+	if resp, _ := export("?month=2024-12", reauthentication.Token); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a future month to be rejected, got %d", resp.StatusCode)
+	}
+	if resp, _ := export("?month=October", reauthentication.Token); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected an invalid month to be rejected, got %d", resp.StatusCode)
+	}
+
+	var entries []models.AuditLogEntry
+	h.MustDo(http.MethodGet, "/api/v1/audit-log", token, nil, http.StatusOK, &entries)
+	exports := 0
+	for _, entry := range entries {
+		if entry.Action == models.AuditActionExportFees && entry.ActorUserID != nil && *entry.ActorUserID == admin.ID {
+			exports++
+		}
+	}
+	if exports != 2 {
+		t.Errorf("Expected both exports in the audit log, got %d of %+v", exports, entries)
+	}
+}
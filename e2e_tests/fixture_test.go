@@ -0,0 +1,153 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"kitadoc-backend/app"
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// startFixtureServer boots the server like -fixture does, next to the server of the other tests.
+func startFixtureServer(t *testing.T) *httptest.Server {
+	t.Setenv("KINDERGARTEN_FACILITY_TIMEZONE", "UTC")
+	cfg, err := config.LoadConfig(config.ProfileFixture)
+	if err != nil {
+		t.Fatalf("Failed to load fixture configuration: %v", err)
+	}
+	fixtureDB, err := sql.Open("sqlite", cfg.Database.DSN)
+	if err != nil {
+		t.Fatalf("Failed to open fixture database: %v", err)
+	}
+	t.Cleanup(func() { fixtureDB.Close() }) //nolint:errcheck
+	if err := data.MigrateDB(fixtureDB, migrations.Files); err != nil {
+		t.Fatalf("Failed to migrate fixture database: %v", err)
+	}
+
+	fixtureApp := app.NewApplication(*cfg, data.NewDAL(fixtureDB, data.DriverSQLite, []byte(cfg.Database.EncryptionKey)))
+	if _, err := fixtureApp.Fixtures.Reset(logrus.NewEntry(logrus.New()), context.Background()); err != nil {
+		t.Fatalf("Failed to seed fixture data: %v", err)
+	}
+	server := httptest.NewServer(fixtureApp.Routes())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func fixtureRequest(t *testing.T, server *httptest.Server, method, url, token string, body interface{}) *http.Response {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("failed to marshal request body: %v", err)
+	}
+	req, err := http.NewRequest(method, server.URL+url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	return resp
+}
+
+func TestFixtureServer(t *testing.T) {
+	server := startFixtureServer(t)
+
+	resp := fixtureRequest(t, server, http.MethodGet, "/api/v1/fixture", "", nil)
+	var state models.FixtureState
+	if err := json.Unmarshal(readResponseBody(t, resp), &state); err != nil {
+		t.Fatalf("Failed to unmarshal fixture state: %v", err)
+	}
+	resp.Body.Close() //nolint:errcheck
+	if !state.Now.Equal(services.FixtureTime) || len(state.Users) != 3 {
+		t.Fatalf("Unexpected fixture state %+v", state)
+	}
+
+	resp = fixtureRequest(t, server, http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"username": state.Users[0].Username,
+		"password": state.Users[0].Password,
+	})
+	var login struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(readResponseBody(t, resp), &login); err != nil || login.Token == "" {
+		t.Fatalf("Failed to log in as the seeded admin: %v", err)
+	}
+	resp.Body.Close() //nolint:errcheck
+
+	getChildren := func(t *testing.T) []models.Child {
+		resp := fixtureRequest(t, server, http.MethodGet, "/api/v1/children", login.Token, nil)
+		defer resp.Body.Close() //nolint:errcheck
+		var children []models.Child
+		if err := json.Unmarshal(readResponseBody(t, resp), &children); err != nil {
+			t.Fatalf("Failed to unmarshal children: %v", err)
+		}
+		return children
+	}
+
+	t.Run("Seeded Data Is Deterministic", func(t *testing.T) {
+		children := getChildren(t)
+		if len(children) != 4 {
+			t.Fatalf("Expected 4 seeded children, got %d", len(children))
+		}
+		if children[0].ID != 1 || children[0].FirstName != "Anna" || !children[0].CreatedAt.Equal(services.FixtureTime) {
+			t.Errorf("Unexpected first child %+v", children[0])
+		}
+	})
+
+	t.Run("Clock Can Be Moved", func(t *testing.T) {
+		tomorrow := services.FixtureTime.AddDate(0, 0, 1)
+		resp := fixtureRequest(t, server, http.MethodPut, "/api/v1/fixture/clock", "", map[string]time.Time{"now": tomorrow})
+		defer resp.Body.Close() //nolint:errcheck
+		var moved models.FixtureState
+		if err := json.Unmarshal(readResponseBody(t, resp), &moved); err != nil {
+			t.Fatalf("Failed to unmarshal fixture state: %v", err)
+		}
+		if !moved.Now.Equal(tomorrow) {
+			t.Errorf("Expected clock at %s, got %s", tomorrow, moved.Now)
+		}
+	})
+
+	t.Run("Reset Restores The Seeded State", func(t *testing.T) {
+		resp := fixtureRequest(t, server, http.MethodPost, "/api/v1/children", login.Token, map[string]interface{}{
+			"first_name": "Extra",
+			"last_name":  "Kind",
+			"birthdate":  "2021-01-01",
+		})
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status %d creating a child, got %d", http.StatusCreated, resp.StatusCode)
+		}
+
+		resp = fixtureRequest(t, server, http.MethodPost, "/api/v1/fixture/reset", "", nil)
+		var reset models.FixtureState
+		if err := json.Unmarshal(readResponseBody(t, resp), &reset); err != nil {
+			t.Fatalf("Failed to unmarshal fixture state: %v", err)
+		}
+		resp.Body.Close() //nolint:errcheck
+		if !reset.Now.Equal(services.FixtureTime) {
+			t.Errorf("Expected the clock to be reset to %s, got %s", services.FixtureTime, reset.Now)
+		}
+
+		// Tokens stay valid, the seeded accounts keep their IDs
+		children := getChildren(t)
+		if len(children) != 4 || children[3].ID != 4 {
+			t.Errorf("Expected the 4 seeded children after the reset, got %+v", children)
+		}
+	})
+}
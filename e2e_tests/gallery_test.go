@@ -0,0 +1,158 @@
+package e2e_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestGalleryEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	admin := h.MustCreateUser(string(data.RoleAdmin))
+	adminToken := h.MustLogin(admin.Username)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	token := h.MustLogin(teacher.Username)
+	anna := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+	ben := h.MustCreateChild(models.Child{FirstName: "Ben", LastName: "Schulz"})
+
+	var group models.Group
+	h.MustDo(http.MethodPost, "/api/v1/groups", adminToken, map[string]any{"name": "Sonnengruppe", "capacity": 20}, http.StatusCreated, &group)
+	for _, child := range []*models.Child{anna, ben} {
+		h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/groups/%d/children/%d", group.ID, child.ID), token, nil, http.StatusOK, nil)
+	}
+	h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/children/%d/photo-consent", anna.ID), token,
+		map[string]any{"consent_reference": "Einwilligung vom 01.08.2024"}, http.StatusOK, nil)
+
+	upload := func(caption string, childIDs ...int) *http.Response {
+		var content bytes.Buffer
+		if err := png.Encode(&content, image.NewRGBA(image.Rect(0, 0, 30, 20))); err != nil {
+			t.Fatalf("Failed to encode photo: %v", err)
+		}
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("photo", "foto.png")
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write(content.Bytes()) //nolint:errcheck
+		ids := make([]string, len(childIDs))
+		for i, id := range childIDs {
+			ids[i] = fmt.Sprint(id)
+		}
+		writer.WriteField("caption", caption)                  //nolint:errcheck
+		writer.WriteField("child_ids", strings.Join(ids, ",")) //nolint:errcheck
+		writer.Close()                                         //nolint:errcheck
+
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/groups/%d/photos", h.Server.URL, group.ID), body)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := h.Server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to upload photo: %v", err)
+		}
+		return resp
+	}
+	uploadPhoto := func(caption string, childIDs ...int) models.GalleryPhoto {
+		resp := upload(caption, childIDs...)
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", resp.StatusCode, body)
+		}
+		var photo models.GalleryPhoto
+		if err := json.Unmarshal(body, &photo); err != nil {
+			t.Fatalf("Failed to unmarshal photo: %v", err)
+		}
+		return photo
+	}
+
+	annaPhoto := uploadPhoto("Anna im Sandkasten", anna.ID)
+	groupPhoto := uploadPhoto("Laternenumzug", anna.ID, ben.ID)
+
+	t.Run("Listing Redacts Children Without Consent", func(t *testing.T) {
+		var photos []models.GalleryPhoto
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/groups/%d/photos", group.ID), token, nil, http.StatusOK, &photos)
+		if len(photos) != 2 || photos[0].ID != groupPhoto.ID {
+			t.Fatalf("Expected both photos, the latest first, got %+v", photos)
+		}
+		if len(photos[0].RedactedChildIDs) != 1 || photos[0].RedactedChildIDs[0] != ben.ID || photos[1].IsRedacted() {
+			t.Errorf("Expected only Ben to be redacted, got %+v", photos)
+		}
+
+		resp := h.Do(http.MethodGet, fmt.Sprintf("/api/v1/photos/%d/content", groupPhoto.ID), token, nil)
+		readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected the redacted image to be withheld, got %d", resp.StatusCode)
+		}
+		resp = h.Do(http.MethodGet, fmt.Sprintf("/api/v1/photos/%d/content", annaPhoto.ID), token, nil)
+		readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/png" {
+			t.Errorf("Expected the image, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+	})
+
+	t.Run("Tagged Children Must Be In The Group", func(t *testing.T) {
+		other := h.MustCreateChild(models.Child{FirstName: "Clara", LastName: "Weber"})
+		resp := upload("Ausflug", other.ID)
+		readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", resp.StatusCode)
+		}
+		h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/photos/%d", annaPhoto.ID), token,
+			map[string]any{"caption": "Ausflug", "child_ids": []int{anna.ID, other.ID}}, http.StatusBadRequest, nil)
+	})
+
+	t.Run("Reports Embed Picked Photos", func(t *testing.T) {
+		reportURL := fmt.Sprintf("/api/v1/documents/child-report/%d?photo_ids=%d", anna.ID, annaPhoto.ID)
+		resp := h.Do(http.MethodGet, reportURL, token, nil)
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, body)
+		}
+		reader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			t.Fatalf("Failed to open report: %v", err)
+		}
+		if _, err := reader.Open("word/media/image1.png"); err != nil {
+			t.Errorf("Expected the photo in the report: %v", err)
+		}
+
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/documents/child-report/%d?photo_ids=%d", anna.ID, groupPhoto.ID), token, nil, http.StatusForbidden, nil)
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/documents/child-report/%d?photo_ids=%d", ben.ID, annaPhoto.ID), token, nil, http.StatusBadRequest, nil)
+	})
+
+	t.Run("Consent Changes Redaction", func(t *testing.T) {
+		consentURL := fmt.Sprintf("/api/v1/children/%d/photo-consent", ben.ID)
+		h.MustDo(http.MethodPut, consentURL, token, map[string]any{"consent_reference": "Einwilligung vom 05.09.2024"}, http.StatusOK, nil)
+		var photo models.GalleryPhoto
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/photos/%d", groupPhoto.ID), token, nil, http.StatusOK, &photo)
+		if photo.IsRedacted() {
+			t.Errorf("Expected no redaction after consent, got %+v", photo)
+		}
+
+		h.MustDo(http.MethodDelete, consentURL, token, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, consentURL, token, nil, http.StatusNotFound, nil)
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/photos/%d", groupPhoto.ID), token, nil, http.StatusOK, &photo)
+		if !photo.IsRedacted() {
+			t.Errorf("Expected redaction after the consent was revoked, got %+v", photo)
+		}
+	})
+
+	t.Run("Delete Photo", func(t *testing.T) {
+		h.MustDo(http.MethodDelete, fmt.Sprintf("/api/v1/photos/%d", groupPhoto.ID), token, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/photos/%d", groupPhoto.ID), token, nil, http.StatusNotFound, nil)
+	})
+}
@@ -0,0 +1,162 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+func TestGraphQLEndpoint(t *testing.T) {
+	setupTest(t)
+
+	resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/children", authToken, map[string]interface{}{
+		"first_name":     "Graph",
+		"last_name":      "Child",
+		"birthdate":      time.Date(2021, time.May, 5, 0, 0, 0, 0, time.UTC),
+		"admission_date": time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}, "application/json")
+	var child models.Child
+	json.Unmarshal(readResponseBody(t, resp), &child) //nolint:errcheck
+	resp.Body.Close()                                 //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", adminAuthToken, map[string]string{
+		"first_name": "Graph",
+		"last_name":  "Teacher",
+		"username":   "graphteacher",
+	}, "application/json")
+	var teacher models.Teacher
+	json.Unmarshal(readResponseBody(t, resp), &teacher) //nolint:errcheck
+	resp.Body.Close()                                   //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/categories", adminAuthToken, map[string]string{
+		"name": "GraphCategory",
+	}, "application/json")
+	var category models.Category
+	json.Unmarshal(readResponseBody(t, resp), &category) //nolint:errcheck
+	resp.Body.Close()                                    //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/assignments", authToken, map[string]interface{}{
+		"child_id":   child.ID,
+		"teacher_id": teacher.ID,
+		"start_date": time.Now().AddDate(0, -1, 0),
+	}, "application/json")
+	resp.Body.Close() //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/documentation", authToken, map[string]interface{}{
+		"child_id":                child.ID,
+		"teacher_id":              teacher.ID,
+		"category_id":             category.ID,
+		"observation_description": "Malt ein großes Bild mit Wasserfarben",
+		"observation_date":        time.Now().AddDate(0, 0, -2),
+	}, "application/json")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+	}
+	resp.Body.Close() //nolint:errcheck
+
+	query := func(t *testing.T, token string, query string) (*http.Response, []byte) {
+		resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/graphql", token, map[string]string{"query": query}, "application/json")
+		defer resp.Body.Close() //nolint:errcheck
+		return resp, readResponseBody(t, resp)
+	}
+
+	t.Run("Requires Authentication", func(t *testing.T) {
+		resp, _ := query(t, "", `{ children { id } }`)
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, resp.StatusCode)
+		}
+	})
+
+	t.Run("Child With Nested Entries, Assignments And Stats", func(t *testing.T) {
+		resp, body := query(t, authToken, fmt.Sprintf(`{
+			child(id: %d) {
+				first_name
+				birthdate
+				entries { category_id observation_description observation_date }
+				assignments { teacher_id end_date }
+				completeness { entry_count categories_covered }
+			}
+			categories { id name }
+		}`, child.ID))
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+		}
+		var result struct {
+			Data struct {
+				Child struct {
+					FirstName string `json:"first_name"`
+					Birthdate string `json:"birthdate"`
+					Entries   []struct {
+						CategoryID      int    `json:"category_id"`
+						ObservationDate string `json:"observation_date"`
+					} `json:"entries"`
+					Assignments []struct {
+						TeacherID int `json:"teacher_id"`
+					} `json:"assignments"`
+					Completeness struct {
+						EntryCount int `json:"entry_count"`
+					} `json:"completeness"`
+				} `json:"child"`
+			} `json:"data"`
+			Errors []any `json:"errors"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			t.Fatalf("Failed to unmarshal GraphQL result: %v", err)
+		}
+		if len(result.Errors) > 0 {
+			t.Fatalf("Expected no errors, got %v", result.Errors)
+		}
+		if result.Data.Child.FirstName != "Graph" {
+			t.Errorf("Expected child Graph, got %s", result.Data.Child.FirstName)
+		}
+		if result.Data.Child.Birthdate != "2021-05-05" {
+			t.Errorf("Expected birthdate 2021-05-05, got %s", result.Data.Child.Birthdate)
+		}
+		if len(result.Data.Child.Entries) != 1 || result.Data.Child.Entries[0].CategoryID != category.ID {
+			t.Errorf("Expected one entry in category %d, got %v", category.ID, result.Data.Child.Entries)
+		} else if observationDate := time.Now().AddDate(0, 0, -2).Format(time.DateOnly); result.Data.Child.Entries[0].ObservationDate != observationDate {
+			t.Errorf("Expected observation date %s, got %s", observationDate, result.Data.Child.Entries[0].ObservationDate)
+		}
+		if len(result.Data.Child.Assignments) != 1 || result.Data.Child.Assignments[0].TeacherID != teacher.ID {
+			t.Errorf("Expected one assignment to teacher %d, got %v", teacher.ID, result.Data.Child.Assignments)
+		}
+		if result.Data.Child.Completeness.EntryCount != 1 {
+			t.Errorf("Expected 1 entry in completeness, got %d", result.Data.Child.Completeness.EntryCount)
+		}
+	})
+
+	t.Run("Is Read Only", func(t *testing.T) {
+		resp, body := query(t, authToken, `mutation { deleteChild(id: 1) }`)
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "errors") {
+			t.Errorf("Expected mutation to be rejected, got %d: %s", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("Rejects Deep Queries", func(t *testing.T) {
+		maxDepth := application.GraphQLHandler.MaxDepth
+		application.GraphQLHandler.MaxDepth = 3
+		defer func() { application.GraphQLHandler.MaxDepth = maxDepth }()
+
+		resp, body := query(t, authToken, `{ children { completeness { score } } }`)
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+		}
+		resp, body = query(t, authToken, `{ children { ...Coverage } } fragment Coverage on Child { completeness { categories { category_name } } }`)
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), "depth") {
+			t.Errorf("Expected depth limit error, got %d: %s", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("Rejects Expensive Queries", func(t *testing.T) {
+		fields := strings.Repeat("entries { id child_id teacher_id category_id observation_description } ", 20)
+		resp, body := query(t, authToken, `{ children { `+fields+` } }`)
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), "complexity") {
+			t.Errorf("Expected complexity limit error, got %d: %s", resp.StatusCode, body)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestGroupHandoverEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	h.SetClock(time.Date(2026, time.August, 3, 9, 0, 0, 0, models.FacilityLocation()))
+	maria := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	mariaToken := h.MustLogin(maria.Username)
+	jonas := h.MustCreateTeacher(models.Teacher{FirstName: "Jonas", LastName: "Weber"})
+	jonasToken := h.MustLogin(jonas.Username)
+	anna := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2023, time.June, 1)})
+	motorik := h.MustCreateCategory(models.Category{Name: "Motorik"})
+	sprache := h.MustCreateCategory(models.Category{Name: "Sprache"})
+
+	krippeID, err := h.DAL.Groups.Create(t.Context(), &models.Group{Name: "Krippe", Capacity: 10, LeadTeacherID: &jonas.ID})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	sonneID, err := h.DAL.Groups.Create(t.Context(), &models.Group{Name: "Sonnengruppe", Capacity: 20, LeadTeacherID: &maria.ID})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	if err := h.DAL.Groups.AddChild(t.Context(), krippeID, anna.ID); err != nil {
+		t.Fatalf("Failed to add child to group: %v", err)
+	}
+
+	h.MustCreateEntry(models.DocumentationEntry{ChildID: anna.ID, TeacherID: jonas.ID, CategoryID: motorik.ID,
+		ObservationDate: models.NewDate(2026, time.June, 10), ObservationDescription: "Klettert die Sprossenwand hinauf."})
+	latest := h.MustCreateEntry(models.DocumentationEntry{ChildID: anna.ID, TeacherID: jonas.ID, CategoryID: motorik.ID,
+		ObservationDate: models.NewDate(2026, time.July, 20), ObservationDescription: "Balanciert sicher über den Baumstamm."})
+	h.MustCreateEntry(models.DocumentationEntry{ChildID: anna.ID, TeacherID: jonas.ID, CategoryID: sprache.ID,
+		ObservationDate: models.NewDate(2026, time.July, 1), ObservationDescription: "Spricht in Drei-Wort-Sätzen."})
+	nap := 90
+	if err := h.DAL.DailyCare.Upsert(t.Context(), []models.DailyCareLog{{ChildID: anna.ID, CareDate: models.NewDate(2026, time.July, 31), NapMinutes: &nap}}); err != nil {
+		t.Fatalf("Failed to log daily care: %v", err)
+	}
+	providerID, err := h.DAL.SupportProviders.Create(t.Context(), &models.SupportProvider{Name: "Praxis Sprachwerk", ProviderType: models.SupportProviderTypeSpeechTherapy})
+	if err != nil {
+		t.Fatalf("Failed to create support provider: %v", err)
+	}
+	if _, err := h.DAL.SupportProviders.CreateSupport(t.Context(), &models.ChildSupport{ChildID: anna.ID, ProviderID: providerID,
+		StartDate: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatalf("Failed to create child support: %v", err)
+	}
+	meetingID, err := h.DAL.TeamMeetings.Create(t.Context(), &models.TeamMeeting{MeetingDate: models.NewDate(2026, time.July, 15), Title: "Fallbesprechung", Minutes: "Wechsel in die Sonnengruppe vorbereiten."})
+	if err != nil {
+		t.Fatalf("Failed to create team meeting: %v", err)
+	}
+	if _, err := h.DAL.TeamMeetings.CreateActionItem(t.Context(), &models.ActionItem{MeetingID: meetingID, Description: "Sprachförderung fortführen",
+		TeacherID: maria.ID, ChildID: &anna.ID, DueDate: models.NewDate(2026, time.September, 30)}); err != nil {
+		t.Fatalf("Failed to create action item: %v", err)
+	}
+
+	handoversURL := fmt.Sprintf("/api/v1/children/%d/handovers", anna.ID)
+	h.MustDo(http.MethodPost, handoversURL, jonasToken, map[string]any{"to_group_id": krippeID}, http.StatusBadRequest, nil)
+	h.MustDo(http.MethodPost, handoversURL, jonasToken, map[string]any{"to_group_id": 999}, http.StatusNotFound, nil)
+
+	var packet models.HandoverPacket
+	h.MustDo(http.MethodPost, handoversURL, jonasToken, map[string]any{"to_group_id": sonneID}, http.StatusCreated, &packet)
+	handover := packet.Handover
+
+	t.Run("Packet", func(t *testing.T) {
+		if handover.FromGroupID == nil || *handover.FromGroupID != krippeID || handover.HandoverDate != models.NewDate(2026, time.August, 3) {
+			t.Fatalf("Expected a handover from the Krippe today, got %+v", handover)
+		}
+		if packet.ChildName != "Anna Müller" || packet.AgeMonths != 38 || *packet.FromGroupName != "Krippe" || *packet.ToGroupName != "Sonnengruppe" {
+			t.Errorf("Expected Anna with her groups, got %+v", packet)
+		}
+		if len(packet.LatestObservations) != 2 || packet.LatestObservations[0].Entry.ID != latest.ID || packet.LatestObservations[0].CategoryName != "Motorik" {
+			t.Errorf("Expected the latest observation per category, got %+v", packet.LatestObservations)
+		}
+		if len(packet.Care.DailyCareLogs) != 1 || packet.Care.AverageNapMinutes == nil || *packet.Care.AverageNapMinutes != 90 {
+			t.Errorf("Expected the daily care of the last weeks, got %+v", packet.Care)
+		}
+		if len(packet.ActiveSupports) != 1 || len(packet.OpenActionItems) != 1 {
+			t.Errorf("Expected the running support and the open action item, got %+v and %+v", packet.ActiveSupports, packet.OpenActionItems)
+		}
+
+		var group models.Group
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/groups/%d", sonneID), mariaToken, nil, http.StatusOK, &group)
+		if len(group.ChildIDs) != 1 || group.ChildIDs[0] != anna.ID {
+			t.Errorf("Expected Anna to be in the Sonnengruppe, got %+v", group.ChildIDs)
+		}
+		var fetched models.HandoverPacket
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/handovers/%d", handover.ID), mariaToken, nil, http.StatusOK, &fetched)
+		if fetched.Handover.ID != handover.ID || len(fetched.LatestObservations) != 2 {
+			t.Errorf("Expected the same packet, got %+v", fetched)
+		}
+	})
+
+	t.Run("Confirmation", func(t *testing.T) {
+		confirmURL := fmt.Sprintf("/api/v1/handovers/%d/confirm", handover.ID)
+		confirmation := map[string]any{
+			"observations_reviewed": true, "care_specifics_reviewed": true, "support_goals_reviewed": true,
+			"parents_informed": true, "notes": "Eingewöhnung in der ersten Woche mit Bezugsperson.",
+		}
+		var unconfirmed []models.GroupHandover
+		h.MustDo(http.MethodGet, "/api/v1/handovers/unconfirmed", mariaToken, nil, http.StatusOK, &unconfirmed)
+		if len(unconfirmed) != 1 {
+			t.Fatalf("Expected one unconfirmed handover, got %+v", unconfirmed)
+		}
+
+		h.MustDo(http.MethodPost, confirmURL, jonasToken, confirmation, http.StatusForbidden, nil)
+		h.MustDo(http.MethodPost, confirmURL, mariaToken, map[string]any{"observations_reviewed": true}, http.StatusBadRequest, nil)
+		var confirmed models.GroupHandover
+		h.MustDo(http.MethodPost, confirmURL, mariaToken, confirmation, http.StatusOK, &confirmed)
+		if confirmed.Confirmation == nil || *confirmed.Confirmation.ConfirmedByTeacherID != maria.ID || !confirmed.Confirmation.ParentsInformed {
+			t.Fatalf("Expected the confirmation of Maria, got %+v", confirmed)
+		}
+		h.MustDo(http.MethodPost, confirmURL, mariaToken, confirmation, http.StatusConflict, nil)
+
+		h.MustDo(http.MethodGet, "/api/v1/handovers/unconfirmed", mariaToken, nil, http.StatusOK, &unconfirmed)
+		if len(unconfirmed) != 0 {
+			t.Errorf("Expected no unconfirmed handovers, got %+v", unconfirmed)
+		}
+		var handovers []models.GroupHandover
+		h.MustDo(http.MethodGet, handoversURL, mariaToken, nil, http.StatusOK, &handovers)
+		if len(handovers) != 1 || handovers[0].Confirmation == nil {
+			t.Errorf("Expected the confirmed handover of Anna, got %+v", handovers)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"kitadoc-backend/models"
+	kitadocv1 "kitadoc-backend/proto/kitadoc/v1"
+)
+
+func TestGRPCReportingAPI(t *testing.T) {
+	setupTest(t)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := application.GRPCServer()
+	go server.Serve(listener) //nolint:errcheck
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create gRPC client: %v", err)
+	}
+	defer conn.Close() //nolint:errcheck
+	client := kitadocv1.NewReportingServiceClient(conn)
+
+	authContext := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+
+	resp := makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/children", authToken, map[string]interface{}{
+		"first_name":     "Grpc",
+		"last_name":      "Child",
+		"birthdate":      time.Date(2021, time.March, 3, 0, 0, 0, 0, time.UTC),
+		"admission_date": time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}, "application/json")
+	var child models.Child
+	json.Unmarshal(readResponseBody(t, resp), &child) //nolint:errcheck
+	resp.Body.Close()                                 //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/teachers", adminAuthToken, map[string]string{
+		"first_name": "Grpc",
+		"last_name":  "Teacher",
+		"username":   "grpcteacher",
+	}, "application/json")
+	var teacher models.Teacher
+	json.Unmarshal(readResponseBody(t, resp), &teacher) //nolint:errcheck
+	resp.Body.Close()                                   //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/categories", adminAuthToken, map[string]string{
+		"name": "GrpcCategory",
+	}, "application/json")
+	var category models.Category
+	json.Unmarshal(readResponseBody(t, resp), &category) //nolint:errcheck
+	resp.Body.Close()                                    //nolint:errcheck
+
+	resp = makeAuthenticatedRequest(t, http.MethodPost, "/api/v1/documentation", authToken, map[string]interface{}{
+		"child_id":                child.ID,
+		"teacher_id":              teacher.ID,
+		"category_id":             category.ID,
+		"observation_description": "Baut mit anderen Kindern eine Murmelbahn",
+		"observation_date":        time.Now().AddDate(0, 0, -1),
+	}, "application/json")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, resp.StatusCode, readResponseBody(t, resp))
+	}
+	resp.Body.Close() //nolint:errcheck
+
+	t.Run("Rejects Calls Without Token", func(t *testing.T) {
+		_, err := client.ListChildren(context.Background(), &kitadocv1.ListChildrenRequest{})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Expected code %s, got %s", codes.Unauthenticated, status.Code(err))
+		}
+	})
+
+	t.Run("List Children", func(t *testing.T) {
+		response, err := client.ListChildren(authContext(authToken), &kitadocv1.ListChildrenRequest{})
+		if err != nil {
+			t.Fatalf("ListChildren failed: %v", err)
+		}
+		found := false
+		for _, listed := range response.GetChildren() {
+			if listed.GetId() == int64(child.ID) {
+				found = listed.GetFirstName() == "Grpc" && listed.GetBirthdate().AsTime().Equal(child.Birthdate.Time)
+			}
+		}
+		if !found {
+			t.Errorf("Expected child %d in ListChildren response", child.ID)
+		}
+	})
+
+	t.Run("Get Child", func(t *testing.T) {
+		response, err := client.GetChild(authContext(authToken), &kitadocv1.GetChildRequest{ChildId: int64(child.ID)})
+		if err != nil {
+			t.Fatalf("GetChild failed: %v", err)
+		}
+		if response.GetLastName() != "Child" {
+			t.Errorf("Expected last name Child, got %s", response.GetLastName())
+		}
+
+		_, err = client.GetChild(authContext(authToken), &kitadocv1.GetChildRequest{ChildId: 999999})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected code %s, got %s", codes.NotFound, status.Code(err))
+		}
+	})
+
+	t.Run("List Documentation Entries", func(t *testing.T) {
+		response, err := client.ListDocumentationEntries(authContext(authToken), &kitadocv1.ListDocumentationEntriesRequest{ChildId: int64(child.ID)})
+		if err != nil {
+			t.Fatalf("ListDocumentationEntries failed: %v", err)
+		}
+		if len(response.GetEntries()) != 1 || response.GetEntries()[0].GetCategoryId() != int64(category.ID) {
+			t.Errorf("Expected one entry in category %d, got %v", category.ID, response.GetEntries())
+		}
+	})
+
+	t.Run("Get Documentation Stats", func(t *testing.T) {
+		response, err := client.GetDocumentationStats(authContext(adminAuthToken), &kitadocv1.GetDocumentationStatsRequest{})
+		if err != nil {
+			t.Fatalf("GetDocumentationStats failed: %v", err)
+		}
+		for _, stats := range response.GetChildren() {
+			if stats.GetChildId() != int64(child.ID) {
+				continue
+			}
+			if stats.GetEntryCount() != 1 || stats.GetLastEntryAgeDays() != 1 {
+				t.Errorf("Expected 1 entry of age 1 day, got %d entries of age %d", stats.GetEntryCount(), stats.GetLastEntryAgeDays())
+			}
+			return
+		}
+		t.Errorf("Expected stats for child %d", child.ID)
+	})
+}
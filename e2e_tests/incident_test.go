@@ -0,0 +1,96 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestIncidentEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	admin := h.MustCreateUser(string(data.RoleAdmin))
+	adminToken := h.MustLogin(admin.Username)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	token := h.MustLogin(teacher.Username)
+	child := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+	occurredAt := h.Now().Add(-2 * time.Hour)
+
+	var incident models.Incident
+	h.MustDo(http.MethodPost, fmt.Sprintf("/api/v1/children/%d/incidents", child.ID), token, map[string]any{
+		"occurred_at": occurredAt,
+		"location":    "Garten, Klettergerüst",
+		"description": "Beim Klettern abgerutscht und auf das Knie gefallen",
+	}, http.StatusCreated, &incident)
+	if incident.ChildID != child.ID || incident.ReportedByUserID == nil {
+		t.Fatalf("Unexpected incident %+v", incident)
+	}
+	h.MustDo(http.MethodPost, fmt.Sprintf("/api/v1/children/%d/incidents", child.ID), token, map[string]any{
+		"occurred_at": h.Now().Add(time.Hour),
+	}, http.StatusBadRequest, nil)
+	reportURL := fmt.Sprintf("/api/v1/incidents/%d/unfallanzeige", incident.ID)
+
+	t.Run("Incomplete Incidents Are Not Exported", func(t *testing.T) {
+		resp := h.Do(http.MethodGet, reportURL, token, nil)
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d: %s", resp.StatusCode, body)
+		}
+		var validation struct {
+			Violations []struct {
+				Field string `json:"field"`
+			} `json:"violations"`
+		}
+		if err := json.Unmarshal(body, &validation); err != nil {
+			t.Fatalf("Failed to unmarshal violations: %v", err)
+		}
+		missing := map[string]bool{}
+		for _, violation := range validation.Violations {
+			missing[violation.Field] = true
+		}
+		for _, field := range []string{"injuries", "first_aid", "witnesses", "parent_informed_at"} {
+			if !missing[field] {
+				t.Errorf("Expected %s to be reported missing, got %s", field, body)
+			}
+		}
+		if missing["location"] || missing["description"] {
+			t.Errorf("Expected given fields not to be reported missing, got %s", body)
+		}
+	})
+
+	t.Run("Complete Incidents Are Exported", func(t *testing.T) {
+		injuries, firstAid, witnesses := "Schürfwunde am linken Knie", "Wunde gereinigt und verbunden durch Frau Schmidt", "Frau Schmidt"
+		informedAt := occurredAt.Add(30 * time.Minute)
+		incident.Injuries, incident.FirstAid, incident.Witnesses = &injuries, &firstAid, &witnesses
+		incident.ParentInformedAt = &informedAt
+		h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/incidents/%d", incident.ID), token, incident, http.StatusOK, nil)
+
+		resp := h.Do(http.MethodGet, reportURL, token, nil)
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, body)
+		}
+		if resp.Header.Get("Content-Type") != "application/pdf" || !bytes.HasPrefix(body, []byte("%PDF-")) {
+			t.Errorf("Expected a PDF, got %s", resp.Header.Get("Content-Type"))
+		}
+
+		var incidents []models.Incident
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/children/%d/incidents", child.ID), token, nil, http.StatusOK, &incidents)
+		if len(incidents) != 1 || incidents[0].Witnesses == nil || *incidents[0].Witnesses != witnesses {
+			t.Fatalf("Unexpected incidents %+v", incidents)
+		}
+	})
+
+	t.Run("Only Admins Delete Incidents", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/incidents/%d", incident.ID)
+		h.MustDo(http.MethodDelete, url, token, nil, http.StatusForbidden, nil)
+		h.MustDo(http.MethodDelete, url, adminToken, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, url, token, nil, http.StatusNotFound, nil)
+	})
+}
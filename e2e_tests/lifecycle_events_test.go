@@ -0,0 +1,174 @@
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/testutils"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestDocumentationLifecycleEvents(t *testing.T) {
+	// Runs on its own database, the outbox and the timeline only hold the events of this test.
+	h := testsupport.New(t)
+	adminAuthToken := h.MustLogin(h.MustCreateUser(string(data.RoleAdmin)).Username)
+	authToken := h.MustLogin(h.MustCreateUser(string(data.RoleTeacher)).Username)
+	child := h.MustCreateChild(models.Child{AdmissionDate: testutils.TimePtr(time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC))})
+	teacher := h.MustCreateTeacher(models.Teacher{})
+	category := h.MustCreateCategory(models.Category{})
+
+	entryBody := map[string]interface{}{
+		"child_id":                child.ID,
+		"teacher_id":              teacher.ID,
+		"category_id":             category.ID,
+		"observation_description": "Hilft beim Aufräumen der Bauecke",
+		"observation_date":        models.Today(h.Now()).AddDays(-3),
+	}
+	var entry models.DocumentationEntry
+	h.MustDo(http.MethodPost, "/api/v1/documentation", authToken, entryBody, http.StatusCreated, &entry)
+
+	request := func(t *testing.T, method string, url string, token string, body interface{}, expectedStatus int) []byte {
+		resp := h.Do(method, url, token, body)
+		defer resp.Body.Close() //nolint:errcheck
+		responseBody := readResponseBody(t, resp)
+		if resp.StatusCode != expectedStatus {
+			t.Fatalf("%s %s: expected status %d, got %d: %s", method, url, expectedStatus, resp.StatusCode, responseBody)
+		}
+		return responseBody
+	}
+
+	t.Run("Review Workflow", func(t *testing.T) {
+		entryURL := fmt.Sprintf("/api/v1/documentation/%d", entry.ID)
+		request(t, http.MethodPut, entryURL+"/reject", adminAuthToken, map[string]string{"reason": "Noch nicht eingereicht"}, http.StatusConflict)
+		request(t, http.MethodPut, entryURL+"/submit", authToken, nil, http.StatusOK)
+		request(t, http.MethodPut, entryURL+"/submit", authToken, nil, http.StatusConflict)
+		request(t, http.MethodPut, entryURL+"/reject", adminAuthToken, map[string]string{"reason": ""}, http.StatusBadRequest)
+		request(t, http.MethodPut, entryURL+"/reject", adminAuthToken, map[string]string{"reason": "Bitte die Situation genauer beschreiben"}, http.StatusOK)
+
+		entryBody["observation_description"] = "Hilft nach dem Freispiel ohne Aufforderung beim Aufräumen der Bauecke"
+		request(t, http.MethodPut, entryURL, authToken, entryBody, http.StatusOK)
+		request(t, http.MethodPut, entryURL+"/submit", authToken, nil, http.StatusOK)
+		request(t, http.MethodPut, entryURL+"/approve", adminAuthToken, map[string]int{"approvedByTeacherId": teacher.ID}, http.StatusOK)
+		request(t, http.MethodGet, fmt.Sprintf("/api/v1/documents/child-report/%d", child.ID), authToken, nil, http.StatusOK)
+	})
+
+	t.Run("Entry History", func(t *testing.T) {
+		body := request(t, http.MethodGet, fmt.Sprintf("/api/v1/documentation/entry/%d/history", entry.ID), authToken, nil, http.StatusOK)
+		var history models.DocumentationEntryHistory
+		if err := json.Unmarshal(body, &history); err != nil {
+			t.Fatalf("Failed to unmarshal history: %v", err)
+		}
+		if history.Status != models.DocumentationStatusApproved || history.Revision != 1 || history.RejectionCount != 1 || history.ReportCount != 1 {
+			t.Errorf("Unexpected history: status %s, revision %d, rejections %d, reports %d", history.Status, history.Revision, history.RejectionCount, history.ReportCount)
+		}
+		if history.ApprovedByTeacherID == nil || *history.ApprovedByTeacherID != teacher.ID {
+			t.Errorf("Expected approval by teacher %d, got %v", teacher.ID, history.ApprovedByTeacherID)
+		}
+
+		var types []models.DocumentationEventType
+		for _, event := range history.Events {
+			types = append(types, event.Type)
+		}
+		expected := []models.DocumentationEventType{
+			models.DocumentationEventCreated,
+			models.DocumentationEventSubmitted,
+			models.DocumentationEventRejected,
+			models.DocumentationEventEdited,
+			models.DocumentationEventSubmitted,
+			models.DocumentationEventApproved,
+			models.DocumentationEventIncludedInReport,
+		}
+		if fmt.Sprint(types) != fmt.Sprint(expected) {
+			t.Errorf("Expected events %v, got %v", expected, types)
+		}
+		if history.Events[0].Payload.Entry == nil || history.Events[0].Payload.Entry.ObservationDescription != "Hilft beim Aufräumen der Bauecke" {
+			t.Errorf("Expected the created event to hold the original observation")
+		}
+		if history.Events[2].Payload.Reason != "Bitte die Situation genauer beschreiben" || history.Events[2].ActorUserID == nil {
+			t.Errorf("Expected the rejection to hold its reason and actor, got %+v", history.Events[2])
+		}
+	})
+
+	t.Run("Child Timeline", func(t *testing.T) {
+		body := request(t, http.MethodGet, fmt.Sprintf("/api/v1/children/%d/timeline", child.ID), authToken, nil, http.StatusOK)
+		var events []models.DocumentationEvent
+		if err := json.Unmarshal(body, &events); err != nil {
+			t.Fatalf("Failed to unmarshal timeline: %v", err)
+		}
+		for i, event := range events {
+			if event.Sequence != i+1 || event.ChildID != child.ID {
+				t.Errorf("Expected event %d of child %d, got sequence %d of child %d", i+1, child.ID, event.Sequence, event.ChildID)
+			}
+		}
+		if len(events) != 7 {
+			t.Errorf("Expected 7 events, got %d", len(events))
+		}
+
+		request(t, http.MethodGet, "/api/v1/children/999999/timeline", authToken, nil, http.StatusNotFound)
+	})
+
+	t.Run("Lifecycle Stats", func(t *testing.T) {
+		body := request(t, http.MethodGet, fmt.Sprintf("/api/v1/documentation/lifecycle-stats?child_id=%d", child.ID), authToken, nil, http.StatusOK)
+		var stats models.DocumentationLifecycleStats
+		if err := json.Unmarshal(body, &stats); err != nil {
+			t.Fatalf("Failed to unmarshal stats: %v", err)
+		}
+		if stats.StatusCounts[models.DocumentationStatusApproved] != 1 || stats.EventCounts["submitted"] != 2 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+		if stats.RejectionRate == nil || *stats.RejectionRate != 0.5 {
+			t.Errorf("Expected rejection rate 0.5, got %v", stats.RejectionRate)
+		}
+	})
+
+	t.Run("Outbox", func(t *testing.T) {
+		getMessages := func(status string) []models.OutboxMessage {
+			body := request(t, http.MethodGet, "/api/v1/outbox?status="+status, adminAuthToken, nil, http.StatusOK)
+			var messages []models.OutboxMessage
+			if err := json.Unmarshal(body, &messages); err != nil {
+				t.Fatalf("Failed to unmarshal outbox messages: %v", err)
+			}
+			return messages
+		}
+		request(t, http.MethodGet, "/api/v1/outbox", authToken, nil, http.StatusForbidden)
+		request(t, http.MethodGet, "/api/v1/outbox?status=unknown", adminAuthToken, nil, http.StatusBadRequest)
+
+		// The rejection and the approval each enqueued a notification of the author.
+		var pending []models.OutboxMessage
+		for _, message := range getMessages(models.OutboxStatusPending) {
+			var payload models.PushOutboxPayload
+			if err := json.Unmarshal(message.Payload, &payload); err != nil {
+				t.Fatalf("Failed to unmarshal outbox payload: %v", err)
+			}
+			if payload.TeacherID != nil && *payload.TeacherID == teacher.ID {
+				pending = append(pending, message)
+			}
+		}
+		if len(pending) != 2 {
+			t.Fatalf("Expected 2 pending notifications for teacher %d, got %d", teacher.ID, len(pending))
+		}
+
+		h.App.OutboxDispatcher.DispatchDue(logrus.NewEntry(logrus.New()), context.Background())
+
+		if remaining := getMessages(models.OutboxStatusPending); len(remaining) != 0 {
+			t.Errorf("Expected no pending messages after dispatch, got %d", len(remaining))
+		}
+		delivered := map[int]bool{}
+		for _, message := range getMessages(models.OutboxStatusDelivered) {
+			delivered[message.ID] = message.DeliveredAt != nil
+		}
+		for _, message := range pending {
+			if !delivered[message.ID] {
+				t.Errorf("Expected message %d to be delivered", message.ID)
+			}
+		}
+	})
+}
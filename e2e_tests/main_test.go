@@ -1,6 +1,7 @@
 package e2e_test
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -99,14 +100,22 @@ func TestMain(m *testing.M) {
 			WriteTimeout time.Duration `mapstructure:"write_timeout"`
 			IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
 			JWTSecret    string        `mapstructure:"jwt_secret"`
+			// RequestTimeout bounds the handling of a request, answered with 504 when exceeded. 0 disables it.
+			RequestTimeout time.Duration `mapstructure:"request_timeout"`
+			// LongRequestTimeout applies instead to report generation, imports and uploads.
+			LongRequestTimeout time.Duration `mapstructure:"long_request_timeout"`
 		}{
-			Port:      8080,
-			JWTSecret: "test_jwt_secret_very_long_and_secure_key_for_testing_purposes",
+			Port:               8080,
+			JWTSecret:          "test_jwt_secret_very_long_and_secure_key_for_testing_purposes",
+			RequestTimeout:     10 * time.Second,
+			LongRequestTimeout: 60 * time.Second,
 		},
 		Database: struct {
+			Driver        string `mapstructure:"driver"`
 			DSN           string `mapstructure:"dsn"`
 			EncryptionKey string `mapstructure:"encryption_key"`
 		}{
+			Driver:        config.DatabaseSQLite,
 			DSN:           "file:" + tmpDBFile.Name() + "?_pragma=foreign_keys(1)", // Use file-backed DB in tmp
 			EncryptionKey: "0123456789abcdef0123456789abcdef",
 		},
@@ -120,6 +129,8 @@ func TestMain(m *testing.M) {
 		TranscriptionServiceURL: mockTranscription.URL,
 		LLMAnalysisServiceURL:   mockLLMAnalysis.URL,
 	}
+	cfg.Session.AccessTokenValidity = time.Hour
+	cfg.Session.RefreshTokenValidity = 24 * time.Hour
 
 	logLevel, _ := logrus.ParseLevel("debug")
 	logger.InitGlobalLogger(logLevel, &logrus.TextFormatter{FullTimestamp: true})
@@ -137,20 +148,23 @@ func TestMain(m *testing.M) {
 	if err := data.MigrateDB(db, migrations.Files); err != nil {
 		panic(fmt.Sprintf("failed to migrate database: %v", err))
 	}
+	if _, err := data.RunDataMigrations(db, data.DataMigrations, []byte(cfg.Database.EncryptionKey)); err != nil {
+		panic(fmt.Sprintf("failed to run data migrations: %v", err))
+	}
 
 	// Initialize DAL
-	dal := data.NewDAL(db, []byte(cfg.Database.EncryptionKey))
+	dal := data.NewDAL(db, data.DriverSQLite, []byte(cfg.Database.EncryptionKey))
 
 	// Pre-create users for testing
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
 	adminUser := &models.User{Username: "admin", PasswordHash: string(hashedPassword), Role: "admin"}
-	_, err = dal.Users.Create(adminUser)
+	_, err = dal.Users.Create(context.Background(), adminUser)
 	if err != nil {
 		panic(fmt.Sprintf("failed to create admin user: %v", err))
 	}
 
 	normalUser := &models.User{Username: "testuser", PasswordHash: string(hashedPassword), Role: "teacher"}
-	_, err = dal.Users.Create(normalUser)
+	_, err = dal.Users.Create(context.Background(), normalUser)
 	if err != nil {
 		panic(fmt.Sprintf("failed to create normal user: %v", err))
 	}
@@ -165,7 +179,7 @@ func TestMain(m *testing.M) {
 		PhoneNumber: "123456",
 		Email:       "test@example.com",
 	}
-	if err := dal.KitaMasterdata.Update(defaultMasterdata); err != nil {
+	if err := dal.KitaMasterdata.Update(context.Background(), defaultMasterdata); err != nil {
 		panic(fmt.Sprintf("failed to create default kita masterdata: %v", err))
 	}
 
@@ -0,0 +1,71 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestMedicationEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	maria := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	mariaToken := h.MustLogin(maria.Username)
+	thomas := h.MustCreateTeacher(models.Teacher{FirstName: "Thomas", LastName: "Weber"})
+	thomasToken := h.MustLogin(thomas.Username)
+	child := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+	logURL := fmt.Sprintf("/api/v1/children/%d/medication-administrations", child.ID)
+
+	var administration models.MedicationAdministration
+	h.MustDo(http.MethodPost, logURL, mariaToken, map[string]any{
+		"medication":                 "Ibuprofen Saft",
+		"dose":                       "2,5 ml",
+		"instruction_reference":      "Schriftliche Vollmacht der Eltern vom 01.03.2025",
+		"administered_at":            h.Now().Add(-10 * time.Minute),
+		"administered_by_teacher_id": maria.ID,
+	}, http.StatusCreated, &administration)
+	if administration.ChildID != child.ID || administration.RecordedByUserID == nil || administration.IsConfirmed() {
+		t.Fatalf("Unexpected administration %+v", administration)
+	}
+	h.MustDo(http.MethodPost, logURL, mariaToken, map[string]any{
+		"medication":                 "Ibuprofen Saft",
+		"administered_at":            h.Now().Add(-10 * time.Minute),
+		"administered_by_teacher_id": maria.ID,
+	}, http.StatusBadRequest, nil)
+	confirmURL := fmt.Sprintf("/api/v1/medication-administrations/%d/confirm", administration.ID)
+
+	t.Run("Confirmation Requires A Second User", func(t *testing.T) {
+		h.MustDo(http.MethodPost, confirmURL, mariaToken, nil, http.StatusForbidden, nil)
+
+		var confirmed models.MedicationAdministration
+		h.MustDo(http.MethodPost, confirmURL, thomasToken, nil, http.StatusOK, &confirmed)
+		if !confirmed.IsConfirmed() || confirmed.ConfirmedByUserID == nil {
+			t.Errorf("Expected a confirmed administration, got %+v", confirmed)
+		}
+		h.MustDo(http.MethodPost, confirmURL, thomasToken, nil, http.StatusConflict, nil)
+	})
+
+	t.Run("History Is Read-Only", func(t *testing.T) {
+		itemURL := fmt.Sprintf("/api/v1/medication-administrations/%d", administration.ID)
+		resp := h.Do(http.MethodPut, itemURL, mariaToken, map[string]any{"dose": "5 ml"})
+		readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("Expected status 405 for changes, got %d", resp.StatusCode)
+		}
+		resp = h.Do(http.MethodDelete, itemURL, mariaToken, nil)
+		readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("Expected status 405 for deletes, got %d", resp.StatusCode)
+		}
+
+		var history []models.MedicationAdministration
+		h.MustDo(http.MethodGet, logURL, thomasToken, nil, http.StatusOK, &history)
+		if len(history) != 1 || history[0].Dose != "2,5 ml" || !history[0].IsConfirmed() {
+			t.Errorf("Unexpected history %+v", history)
+		}
+		h.MustDo(http.MethodGet, "/api/v1/children/999999/medication-administrations", mariaToken, nil, http.StatusNotFound, nil)
+	})
+}
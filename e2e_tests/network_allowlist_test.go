@@ -0,0 +1,62 @@
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestNetworkAllowlist(t *testing.T) {
+	// The test client connects from 127.0.0.1, outside of the facility network.
+	h := testsupport.New(t, func(cfg *config.Config) {
+		cfg.NetworkAllowlist.AdminNetworks = []netip.Prefix{netip.MustParsePrefix("192.168.10.0/24")}
+		cfg.NetworkAllowlist.BulkExportNetworks = []netip.Prefix{netip.MustParsePrefix("192.168.10.0/24")}
+	})
+	admin := h.MustCreateUser(string(data.RoleAdmin))
+	adminToken := h.MustLogin(admin.Username)
+
+	// Admin-only routes are restricted wherever they live, not only under /api/v1/admin/.
+	for _, path := range []string{"/api/v1/admin/uptime", "/api/v1/users", "/api/v1/reports/export", "/api/v1/exports/fees", "/api/v1/statistics/anonymous"} {
+		resp := h.Do(http.MethodGet, path, adminToken, nil)
+		body := readResponseBody(t, resp)
+		var apiError map[string]string
+		if resp.StatusCode != http.StatusForbidden || json.Unmarshal(body, &apiError) != nil || apiError["code"] != "NETWORK_NOT_ALLOWED" {
+			t.Errorf("Expected %s to be rejected outside of the allowlist, got %d: %s", path, resp.StatusCode, body)
+		}
+	}
+
+	resp := h.Do(http.MethodGet, "/api/v1/admin/uptime", "", nil)
+	readResponseBody(t, resp)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected unauthenticated requests to be rejected before the allowlist, got %d", resp.StatusCode)
+	}
+
+	teacher := h.MustCreateUser(string(data.RoleTeacher))
+	h.MustDo(http.MethodGet, "/api/v1/children", h.MustLogin(teacher.Username), nil, http.StatusOK, nil)
+
+	// The audit log is admin-only too, so it is read from the service.
+	entries, err := h.App.AuditLogHandler.AuditLogService.GetAuditLog(logrus.NewEntry(logrus.New()), context.Background())
+	if err != nil {
+		t.Fatalf("Failed to read the audit log: %v", err)
+	}
+	denied := 0
+	for _, entry := range entries {
+		if entry.Action == models.AuditActionDenyNetwork && entry.ActorUserID != nil && *entry.ActorUserID == admin.ID &&
+			strings.Contains(entry.Details, "client=127.0.0.1") {
+			denied++
+		}
+	}
+	if denied != 5 {
+		t.Errorf("Expected the five denied requests to be audited, got %+v", entries)
+	}
+}
@@ -0,0 +1,134 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestProjectEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	admin := h.MustCreateUser("admin")
+	adminToken := h.MustLogin(admin.Username)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	teacherToken := h.MustLogin(teacher.Username)
+	anna := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+	ben := h.MustCreateChild(models.Child{FirstName: "Ben", LastName: "Schulz"})
+	motorik := h.MustCreateCategory(models.Category{Name: "Motorik"})
+	sprache := h.MustCreateCategory(models.Category{Name: "Sprache"})
+	h.SetClock(time.Date(2024, time.October, 16, 12, 0, 0, 0, models.FacilityLocation()))
+
+	groupID, err := h.DAL.Groups.Create(t.Context(), &models.Group{Name: "Sonnengruppe", Capacity: 20})
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	for _, childID := range []int{anna.ID, ben.ID} {
+		if err := h.DAL.Groups.AddChild(t.Context(), groupID, childID); err != nil {
+			t.Fatalf("Failed to add child to group: %v", err)
+		}
+	}
+
+	var project models.Project
+	h.MustDo(http.MethodPost, "/api/v1/projects", teacherToken, map[string]any{
+		"title":      "Herbstwoche",
+		"start_date": "2024-10-07",
+		"end_date":   "2024-10-11",
+		"group_ids":  []int{groupID},
+		"goals": []map[string]any{
+			{"category_id": motorik.ID, "description": "Sammelt Blätter beim Waldspaziergang"},
+			{"category_id": sprache.ID, "description": "Benennt die Farben der Blätter"},
+		},
+	}, http.StatusCreated, &project)
+	if len(project.GroupIDs) != 1 || len(project.Goals) != 2 {
+		t.Fatalf("Expected the project with its group and goals, got %+v", project)
+	}
+	h.MustDo(http.MethodPost, "/api/v1/projects", teacherToken, map[string]any{
+		"title": "Ohne Ende", "start_date": "2024-10-07", "end_date": "2024-10-01",
+	}, http.StatusBadRequest, nil)
+	h.MustDo(http.MethodPost, "/api/v1/projects", teacherToken, map[string]any{
+		"title": "Unbekannte Gruppe", "start_date": "2024-10-07", "end_date": "2024-10-11", "group_ids": []int{999},
+	}, http.StatusNotFound, nil)
+
+	createEntry := func(childID int, categoryID int, day string, projectID any, wantStatus int) {
+		h.MustDo(http.MethodPost, "/api/v1/documentation", teacherToken, map[string]any{
+			"child_id":                childID,
+			"teacher_id":              teacher.ID,
+			"category_id":             categoryID,
+			"observation_date":        day,
+			"observation_description": "Sammelt bunte Blätter und sortiert sie nach Farben.",
+			"project_id":              projectID,
+		}, wantStatus, nil)
+	}
+
+	t.Run("Entries Refer To The Project", func(t *testing.T) {
+		createEntry(anna.ID, motorik.ID, "2024-10-08", project.ID, http.StatusCreated)
+		createEntry(anna.ID, sprache.ID, "2024-10-09", project.ID, http.StatusCreated)
+		createEntry(ben.ID, motorik.ID, "2024-10-10", nil, http.StatusCreated)
+		createEntry(ben.ID, motorik.ID, "2024-10-14", project.ID, http.StatusBadRequest)
+		createEntry(ben.ID, motorik.ID, "2024-10-10", 999, http.StatusNotFound)
+
+		var entries []models.DocumentationEntry
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/projects/%d/documentation?category_id=%d", project.ID, motorik.ID), teacherToken, nil, http.StatusOK, &entries)
+		if len(entries) != 1 || entries[0].ChildID != anna.ID || *entries[0].ProjectID != project.ID {
+			t.Fatalf("Expected the motor skills entry of Anna in the project, got %+v", entries)
+		}
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/documentation/child/%d?project_id=%d", ben.ID, project.ID), teacherToken, nil, http.StatusOK, &entries)
+		if len(entries) != 0 {
+			t.Errorf("Expected no project entries of Ben, got %+v", entries)
+		}
+	})
+
+	t.Run("Summary", func(t *testing.T) {
+		var summary models.ProjectSummary
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/projects/%d/summary", project.ID), teacherToken, nil, http.StatusOK, &summary)
+		if summary.EntryCount != 2 || summary.ChildCount != 1 || len(summary.Goals) != 2 {
+			t.Fatalf("Expected two entries of one child for two goals, got %+v", summary)
+		}
+		for _, goal := range summary.Goals {
+			if goal.EntryCount != 1 || goal.ChildCount != 1 {
+				t.Errorf("Expected one entry for goal %q, got %+v", goal.Description, goal)
+			}
+		}
+		if len(summary.Groups) != 1 || summary.Groups[0].ChildCount != 2 || summary.Groups[0].DocumentedChildren != 1 {
+			t.Errorf("Expected one of two children of the group documented, got %+v", summary.Groups)
+		}
+	})
+
+	t.Run("Update And Delete", func(t *testing.T) {
+		projectURL := fmt.Sprintf("/api/v1/projects/%d", project.ID)
+		h.MustDo(http.MethodPut, projectURL, teacherToken, map[string]any{
+			"title": "Herbstwochen", "start_date": "2024-10-07", "end_date": "2024-10-18",
+			"goals": []map[string]any{{"category_id": sprache.ID, "description": "Benennt die Farben der Blätter"}},
+		}, http.StatusOK, nil)
+		var updated models.Project
+		h.MustDo(http.MethodGet, projectURL, teacherToken, nil, http.StatusOK, &updated)
+		if updated.Title != "Herbstwochen" || len(updated.GroupIDs) != 0 || len(updated.Goals) != 1 {
+			t.Fatalf("Expected the updated project, got %+v", updated)
+		}
+		createEntry(ben.ID, motorik.ID, "2024-10-14", project.ID, http.StatusCreated)
+
+		var projects []models.Project
+		h.MustDo(http.MethodGet, "/api/v1/projects", teacherToken, nil, http.StatusOK, &projects)
+		if len(projects) != 1 {
+			t.Errorf("Expected one project, got %+v", projects)
+		}
+
+		resp := h.Do(http.MethodDelete, projectURL, teacherToken, nil)
+		readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected teachers not to delete projects, got %d", resp.StatusCode)
+		}
+		h.MustDo(http.MethodDelete, projectURL, adminToken, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, projectURL+"/summary", teacherToken, nil, http.StatusNotFound, nil)
+
+		var entries []models.DocumentationEntry
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/documentation/child/%d", anna.ID), teacherToken, nil, http.StatusOK, &entries)
+		if len(entries) != 2 || entries[0].ProjectID != nil || entries[1].ProjectID != nil {
+			t.Errorf("Expected the entries of Anna to be kept without the project, got %+v", entries)
+		}
+	})
+}
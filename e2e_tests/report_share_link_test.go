@@ -0,0 +1,100 @@
+package e2e_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+	"kitadoc-backend/testsupport"
+)
+
+func TestReportShareLinkEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	token := h.MustLogin(teacher.Username)
+	child := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+	h.MustCreateAssignment(child.ID, teacher.ID)
+	h.MustCreateEntry(models.DocumentationEntry{ChildID: child.ID, TeacherID: teacher.ID})
+
+	h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/documents/child-report/%d", child.ID), token, nil, http.StatusOK, nil)
+	var reports []models.GeneratedReport
+	h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/children/%d/reports", child.ID), token, nil, http.StatusOK, &reports)
+	if len(reports) != 1 {
+		t.Fatalf("Expected 1 generated report, got %d", len(reports))
+	}
+	shareLinksURL := fmt.Sprintf("/api/v1/children/%d/reports/%d/share-links", child.ID, reports[0].ID)
+	archived := readResponseBody(t, h.Do(http.MethodGet, fmt.Sprintf("/api/v1/children/%d/reports/%d", child.ID, reports[0].ID), token, nil))
+
+	share := func(t *testing.T) models.ReportShareLinkCreated {
+		var created models.ReportShareLinkCreated
+		h.MustDo(http.MethodPost, shareLinksURL, token, map[string]any{"recipient": "Frühförderstelle Musterstadt", "valid_days": 7}, http.StatusCreated, &created)
+		return created
+	}
+	open := func(token, accessCode string) *http.Response {
+		return h.Do(http.MethodPost, "/api/v1/shared-reports", "", map[string]string{"token": token, "access_code": accessCode})
+	}
+	expectCode := func(t *testing.T, response *http.Response, wantStatus int, wantCode string) {
+		t.Helper()
+		body := readResponseBody(t, response)
+		if response.StatusCode != wantStatus || !bytes.Contains(body, []byte(wantCode)) {
+			t.Errorf("Expected status %d with code %s, got %d: %s", wantStatus, wantCode, response.StatusCode, body)
+		}
+	}
+
+	t.Run("Recipients Open The Report With The Access Code", func(t *testing.T) {
+		created := share(t)
+		if !created.ShareLink.ExpiresAt.Equal(h.Now().Add(7 * 24 * time.Hour)) {
+			t.Errorf("Expected the link to expire in 7 days, got %s", created.ShareLink.ExpiresAt)
+		}
+
+		response := open(created.Token, created.AccessCode)
+		body := readResponseBody(t, response)
+		if response.StatusCode != http.StatusOK || !bytes.Equal(body, archived) {
+			t.Fatalf("Expected the archived report, got status %d", response.StatusCode)
+		}
+		expectCode(t, open(created.Token, ""), http.StatusForbidden, services.CodeInvalidAccessCode)
+		expectCode(t, open("unknown", created.AccessCode), http.StatusNotFound, services.CodeShareLinkNotFound)
+
+		var links []models.ReportShareLink
+		h.MustDo(http.MethodGet, shareLinksURL, token, nil, http.StatusOK, &links)
+		if len(links) != 1 || links[0].Recipient != "Frühförderstelle Musterstadt" || links[0].Accesses != 1 {
+			t.Errorf("Unexpected share links %+v", links)
+		}
+		var accesses []models.ReportShareLinkAccess
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/share-links/%d/accesses", created.ShareLink.ID), token, nil, http.StatusOK, &accesses)
+		if len(accesses) != 2 || accesses[0].Outcome != models.ShareLinkAccessWrongCode || accesses[1].Outcome != models.ShareLinkAccessGranted {
+			t.Errorf("Unexpected access log %+v", accesses)
+		}
+	})
+
+	t.Run("Links Expire", func(t *testing.T) {
+		created := share(t)
+		h.SetClock(h.Now().Add(7 * 24 * time.Hour))
+		expectCode(t, open(created.Token, created.AccessCode), http.StatusConflict, services.CodeShareLinkExpired)
+	})
+
+	t.Run("Revoked Links Cannot Be Opened", func(t *testing.T) {
+		created := share(t)
+		h.MustDo(http.MethodDelete, fmt.Sprintf("/api/v1/share-links/%d", created.ShareLink.ID), token, nil, http.StatusNoContent, nil)
+		expectCode(t, open(created.Token, created.AccessCode), http.StatusConflict, services.CodeShareLinkRevoked)
+	})
+
+	t.Run("Guessing The Access Code Revokes The Link", func(t *testing.T) {
+		created := share(t)
+		for range h.Config.Sharing.MaxFailedAttempts {
+			expectCode(t, open(created.Token, "WRONGCODE"), http.StatusForbidden, services.CodeInvalidAccessCode)
+		}
+		expectCode(t, open(created.Token, created.AccessCode), http.StatusConflict, services.CodeShareLinkRevoked)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		h.MustDo(http.MethodPost, shareLinksURL, token, map[string]any{"recipient": ""}, http.StatusBadRequest, nil)
+		h.MustDo(http.MethodPost, shareLinksURL, token, map[string]any{"recipient": "Logopädie", "valid_days": 365}, http.StatusBadRequest, nil)
+		h.MustDo(http.MethodPost, fmt.Sprintf("/api/v1/children/%d/reports/999999/share-links", child.ID), token, map[string]any{"recipient": "Logopädie"}, http.StatusNotFound, nil)
+		h.MustDo(http.MethodPost, shareLinksURL, "", map[string]any{"recipient": "Logopädie"}, http.StatusUnauthorized, nil)
+	})
+}
@@ -0,0 +1,196 @@
+package e2e_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+	"kitadoc-backend/testsupport"
+)
+
+func TestResumableUploadEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	token := h.MustLogin(teacher.Username)
+	otherToken := h.MustLogin(h.MustCreateTeacher(models.Teacher{FirstName: "Jonas", LastName: "Weber"}).Username)
+	child := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+
+	content := []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n" + strings.Repeat("scan ", 200))
+	sum := sha256.Sum256(content)
+
+	sendChunk := func(uploadID string, offset int, chunk []byte, checksum bool) *http.Response {
+		req, err := http.NewRequest(http.MethodPatch, h.Server.URL+"/api/v1/uploads/"+uploadID, bytes.NewReader(chunk))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/offset+octet-stream")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Upload-Offset", strconv.Itoa(offset))
+		if checksum {
+			chunkSum := sha256.Sum256(chunk)
+			req.Header.Set("Upload-Checksum", "sha256 "+base64.StdEncoding.EncodeToString(chunkSum[:]))
+		}
+		resp, err := h.Server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send chunk: %v", err)
+		}
+		return resp
+	}
+	createUpload := func(sha string) models.ResumableUpload {
+		var upload models.ResumableUpload
+		h.MustDo(http.MethodPost, "/api/v1/uploads", token, map[string]any{
+			"kind":         models.UploadKindChildDocument,
+			"file_name":    "Impfpass.pdf",
+			"content_type": "application/pdf",
+			"size_bytes":   len(content),
+			"sha256":       sha,
+		}, http.StatusCreated, &upload)
+		if upload.ID == "" || upload.Offset != 0 {
+			t.Fatalf("Expected a new upload, got %+v", upload)
+		}
+		return upload
+	}
+
+	t.Run("Validation", func(t *testing.T) {
+		h.MustDo(http.MethodPost, "/api/v1/uploads", token, map[string]any{
+			"kind": models.UploadKindChildDocument, "file_name": "x.pdf", "content_type": "application/pdf",
+			"size_bytes": services.MaxChildDocumentSize + 1, "sha256": hex.EncodeToString(sum[:]),
+		}, http.StatusBadRequest, nil)
+		h.MustDo(http.MethodPost, "/api/v1/uploads", token, map[string]any{
+			"kind": "spreadsheet", "file_name": "x.xlsx", "content_type": "application/pdf", "size_bytes": 10, "sha256": hex.EncodeToString(sum[:]),
+		}, http.StatusBadRequest, nil)
+	})
+
+	t.Run("Resume And Use Upload", func(t *testing.T) {
+		upload := createUpload(hex.EncodeToString(sum[:]))
+		split := len(content) / 2
+
+		resp := sendChunk(upload.ID, 0, content[:split], true)
+		if body := readResponseBody(t, resp); resp.StatusCode != http.StatusOK || resp.Header.Get("Upload-Offset") != strconv.Itoa(split) {
+			t.Fatalf("Expected the first chunk to be stored, got %d %s: %s", resp.StatusCode, resp.Header.Get("Upload-Offset"), body)
+		}
+		// The client lost the response and sends the chunk again.
+		resp = sendChunk(upload.ID, 0, content[:split], true)
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusConflict || resp.Header.Get("Upload-Offset") != strconv.Itoa(split) ||
+			!strings.Contains(string(body), services.CodeUploadOffsetMismatch) {
+			t.Fatalf("Expected the repeated chunk to be rejected with the offset, got %d %s: %s", resp.StatusCode, resp.Header.Get("Upload-Offset"), body)
+		}
+
+		var state models.ResumableUpload
+		h.MustDo(http.MethodGet, "/api/v1/uploads/"+upload.ID, token, nil, http.StatusOK, &state)
+		if state.Offset != split {
+			t.Fatalf("Expected offset %d, got %+v", split, state)
+		}
+		h.MustDo(http.MethodGet, "/api/v1/uploads/"+upload.ID, otherToken, nil, http.StatusNotFound, nil)
+
+		h.MustDo(http.MethodDelete, "/api/v1/uploads/"+upload.ID, otherToken, nil, http.StatusNotFound, nil)
+		h.MustDo(http.MethodDelete, "/api/v1/uploads/"+upload.ID, token, nil, http.StatusNoContent, nil)
+
+		// Chunks without a checksum are accepted, the whole file is checked anyway.
+		upload = createUpload(hex.EncodeToString(sum[:]))
+		for offset := 0; offset < len(content); offset += 256 {
+			resp := sendChunk(upload.ID, offset, content[offset:min(offset+256, len(content))], offset%512 == 0)
+			if body := readResponseBody(t, resp); resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected chunk at %d to be stored, got %d: %s", offset, resp.StatusCode, body)
+			}
+		}
+
+		form := &bytes.Buffer{}
+		writer := multipart.NewWriter(form)
+		writer.WriteField("upload_id", upload.ID)                                //nolint:errcheck
+		writer.WriteField("document_type", models.ChildDocumentTypeCareContract) //nolint:errcheck
+		writer.Close()                                                           //nolint:errcheck
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/children/%d/documents", h.Server.URL, child.ID), form)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err = h.Server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to upload document: %v", err)
+		}
+		if body := readResponseBody(t, resp); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", resp.StatusCode, body)
+		}
+
+		var documents []models.ChildDocument
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/children/%d/documents", child.ID), token, nil, http.StatusOK, &documents)
+		if len(documents) != 1 || documents[0].FileName != "Impfpass.pdf" {
+			t.Fatalf("Expected the resumed document, got %+v", documents)
+		}
+		resp = h.Do(http.MethodGet, fmt.Sprintf("/api/v1/child-documents/%d/content", documents[0].ID), token, nil)
+		if body := readResponseBody(t, resp); !bytes.Equal(body, content) {
+			t.Errorf("Expected the joined chunks to be stored, got %d bytes", len(body))
+		}
+		h.MustDo(http.MethodGet, "/api/v1/uploads/"+upload.ID, token, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("Corrupted Uploads Are Rejected", func(t *testing.T) {
+		other := sha256.Sum256([]byte("other"))
+		upload := createUpload(hex.EncodeToString(other[:]))
+		resp := sendChunk(upload.ID, 0, content, true)
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), services.CodeUploadChecksumMismatch) {
+			t.Fatalf("Expected the file to fail its checksum, got %d: %s", resp.StatusCode, body)
+		}
+		h.MustDo(http.MethodGet, "/api/v1/uploads/"+upload.ID, token, nil, http.StatusNotFound, nil)
+
+		upload = createUpload(hex.EncodeToString(sum[:]))
+		req, err := http.NewRequest(http.MethodPatch, h.Server.URL+"/api/v1/uploads/"+upload.ID, bytes.NewReader(content))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Upload-Offset", "0")
+		req.Header.Set("Upload-Checksum", "sha256 "+base64.StdEncoding.EncodeToString(other[:]))
+		resp, err = h.Server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send chunk: %v", err)
+		}
+		if body := readResponseBody(t, resp); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("Expected the chunk to fail its checksum, got %d: %s", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("Browsers May Send Chunks Cross-Origin", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodOptions, h.Server.URL+"/api/v1/uploads/abc", nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Origin", "https://app.kita.example")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+		req.Header.Set("Access-Control-Request-Headers", "authorization, content-type, upload-offset, upload-checksum")
+		resp, err := h.Server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send preflight request: %v", err)
+		}
+		resp.Body.Close() //nolint:errcheck
+
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("Expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+		}
+		if methods := resp.Header.Get("Access-Control-Allow-Methods"); !strings.Contains(methods, http.MethodPatch) {
+			t.Errorf("Expected PATCH to be allowed, got %q", methods)
+		}
+		allowedHeaders := resp.Header.Get("Access-Control-Allow-Headers")
+		for _, header := range []string{"Upload-Offset", "Upload-Checksum"} {
+			if !strings.Contains(allowedHeaders, header) {
+				t.Errorf("Expected %s to be allowed, got %q", header, allowedHeaders)
+			}
+		}
+		if exposed := resp.Header.Get("Access-Control-Expose-Headers"); !strings.Contains(exposed, "Upload-Offset") {
+			t.Errorf("Expected Upload-Offset to be exposed, got %q", exposed)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package e2e_test
+
+import (
+	"net/http"
+	"testing"
+
+	"kitadoc-backend/testsupport"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	h := testsupport.New(t)
+
+	for _, path := range []string{"/health", "/api/v1/children"} {
+		resp := h.Do(http.MethodGet, path, "", nil)
+		readResponseBody(t, resp)
+		if resp.Header.Get("X-Content-Type-Options") != "nosniff" || resp.Header.Get("Referrer-Policy") != "strict-origin-when-cross-origin" ||
+			resp.Header.Get("Content-Security-Policy") == "" {
+			t.Errorf("Expected the security headers on %s, got %v", path, resp.Header)
+		}
+		if resp.Header.Get("Strict-Transport-Security") != "" {
+			t.Errorf("Expected no HSTS in the fixture profile on %s, got %q", path, resp.Header.Get("Strict-Transport-Security"))
+		}
+	}
+}
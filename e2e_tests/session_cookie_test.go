@@ -0,0 +1,90 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"testing"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestCookieSessionEndpoints(t *testing.T) {
+	h := testsupport.New(t, func(cfg *config.Config) {
+		cfg.Session.Cookies = true
+	})
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("Failed to create cookie jar: %v", err)
+	}
+	client := &http.Client{Jar: jar}
+	send := func(method, path, csrfToken string, body any) *http.Response {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("Failed to marshal request body: %v", err)
+		}
+		req, err := http.NewRequest(method, h.Server.URL+path, bytes.NewReader(encoded))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if csrfToken != "" {
+			req.Header.Set(middleware.CSRFHeader, csrfToken)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send %s %s: %v", method, path, err)
+		}
+		return resp
+	}
+
+	resp := send(http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"username": teacher.Username, "password": testsupport.Password, "session": "cookie",
+	})
+	body := readResponseBody(t, resp)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, body)
+	}
+	var login map[string]string
+	if err := json.Unmarshal(body, &login); err != nil {
+		t.Fatalf("Failed to unmarshal login response: %v", err)
+	}
+	if login["token"] != "" || login["csrf_token"] == "" {
+		t.Fatalf("Expected only the CSRF token in the response, got %v", login)
+	}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == middleware.SessionCookieName && (!cookie.HttpOnly || cookie.SameSite != http.SameSiteStrictMode) {
+			t.Errorf("Expected an HttpOnly SameSite=Strict session cookie, got %+v", cookie)
+		}
+	}
+
+	if resp := send(http.MethodGet, "/api/v1/auth/me", "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the cookie to authenticate, got %d: %s", resp.StatusCode, readResponseBody(t, resp))
+	}
+	if resp := send(http.MethodPost, "/api/v1/auth/logout", "", nil); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected a forged request without CSRF token to be rejected, got %d: %s", resp.StatusCode, readResponseBody(t, resp))
+	}
+	if resp := send(http.MethodPost, "/api/v1/auth/logout", login["csrf_token"], nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the logout to pass with the CSRF token, got %d: %s", resp.StatusCode, readResponseBody(t, resp))
+	}
+	if resp := send(http.MethodGet, "/api/v1/auth/me", "", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected the logout to clear the session cookie, got %d", resp.StatusCode)
+	}
+
+	// Bearer tokens keep working without a CSRF token.
+	h.MustDo(http.MethodPost, "/api/v1/auth/logout", h.MustLogin(teacher.Username), nil, http.StatusOK, nil)
+}
+
+func TestCookieSessionsDisabled(t *testing.T) {
+	h := testsupport.New(t)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	h.MustDo(http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"username": teacher.Username, "password": testsupport.Password, "session": "cookie",
+	}, http.StatusBadRequest, nil)
+}
@@ -0,0 +1,106 @@
+package e2e_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestSupportProviderEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	admin := h.MustCreateUser(string(data.RoleAdmin))
+	adminToken := h.MustLogin(admin.Username)
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	token := h.MustLogin(teacher.Username)
+	child := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+	h.MustCreateAssignment(child.ID, teacher.ID)
+	supportsURL := fmt.Sprintf("/api/v1/children/%d/supports", child.ID)
+
+	provider := map[string]any{"name": "Logopädie am Markt", "provider_type": models.SupportProviderTypeSpeechTherapy}
+	h.MustDo(http.MethodPost, "/api/v1/support-providers", token, provider, http.StatusForbidden, nil)
+	var created models.SupportProvider
+	h.MustDo(http.MethodPost, "/api/v1/support-providers", adminToken, provider, http.StatusCreated, &created)
+	h.MustDo(http.MethodPost, "/api/v1/support-providers", adminToken, provider, http.StatusConflict, nil)
+	var providers []models.SupportProvider
+	h.MustDo(http.MethodGet, "/api/v1/support-providers", token, nil, http.StatusOK, &providers)
+	if len(providers) != 1 || providers[0].ID != created.ID {
+		t.Fatalf("Unexpected support providers %+v", providers)
+	}
+
+	reportText := func(t *testing.T, query string) string {
+		t.Helper()
+		body := readResponseBody(t, h.Do(http.MethodGet, fmt.Sprintf("/api/v1/documents/child-report/%d%s", child.ID, query), token, nil))
+		reader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			t.Fatalf("Failed to open report: %v", err)
+		}
+		documentFile, err := reader.Open("word/document.xml")
+		if err != nil {
+			t.Fatalf("Failed to open report document: %v", err)
+		}
+		documentXML, err := io.ReadAll(documentFile)
+		if err != nil {
+			t.Fatalf("Failed to read report document: %v", err)
+		}
+		return string(documentXML)
+	}
+
+	var support models.ChildSupport
+	t.Run("Supports Are Only Reported With Consent", func(t *testing.T) {
+		h.MustDo(http.MethodPost, supportsURL, token, map[string]any{
+			"provider_id":       created.ID,
+			"start_date":        "2025-02-01T00:00:00Z",
+			"appointment_notes": "Termine dienstags um 9 Uhr",
+		}, http.StatusCreated, &support)
+		if support.ProviderName != "Logopädie am Markt" || support.HasConsent() {
+			t.Fatalf("Unexpected support %+v", support)
+		}
+		if strings.Contains(reportText(t, ""), "Inklusion/Förderung") {
+			t.Error("Expected supports without consent to be left out of the report")
+		}
+
+		consent := "Einwilligung vom 03.02.2025"
+		support.ConsentReference = &consent
+		h.MustDo(http.MethodPut, fmt.Sprintf("%s/%d", supportsURL, support.ID), token, support, http.StatusOK, nil)
+		report := reportText(t, "")
+		if !strings.Contains(report, "Logopädie: Logopädie am Markt (seit 01.02.2025)") {
+			t.Error("Expected the consented support in the report")
+		}
+		if strings.Contains(report, "dienstags") {
+			t.Error("Expected appointment notes to be left out of the report")
+		}
+	})
+
+	t.Run("Redaction Profiles Hide Supports", func(t *testing.T) {
+		var profile models.RedactionProfile
+		h.MustDo(http.MethodPost, "/api/v1/redaction-profiles", adminToken, map[string]any{"name": "Schule", "hide_support_providers": true}, http.StatusCreated, &profile)
+		if !profile.HideSupportProviders {
+			t.Fatalf("Expected the profile to hide supports, got %+v", profile)
+		}
+		if strings.Contains(reportText(t, fmt.Sprintf("?redaction_profile_id=%d", profile.ID)), "Logopädie am Markt") {
+			t.Error("Expected the redaction profile to hide the supports")
+		}
+	})
+
+	t.Run("Providers Of Supported Children Cannot Be Deleted", func(t *testing.T) {
+		providerURL := fmt.Sprintf("/api/v1/support-providers/%d", created.ID)
+		h.MustDo(http.MethodDelete, providerURL, adminToken, nil, http.StatusConflict, nil)
+		h.MustDo(http.MethodDelete, fmt.Sprintf("/api/v1/children/%d/supports/%d", child.ID+1, support.ID), token, nil, http.StatusNotFound, nil)
+		h.MustDo(http.MethodDelete, fmt.Sprintf("%s/%d", supportsURL, support.ID), token, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodDelete, providerURL, adminToken, nil, http.StatusNoContent, nil)
+
+		var supports []models.ChildSupport
+		h.MustDo(http.MethodGet, supportsURL, token, nil, http.StatusOK, &supports)
+		if len(supports) != 0 {
+			t.Errorf("Expected no supports, got %+v", supports)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestTeamMeetingEndpoints(t *testing.T) {
+	h := testsupport.New(t)
+	admin := h.MustCreateUser("admin")
+	adminToken := h.MustLogin(admin.Username)
+	maria := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	mariaToken := h.MustLogin(maria.Username)
+	jonas := h.MustCreateTeacher(models.Teacher{FirstName: "Jonas", LastName: "Weber"})
+	anna := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+	ben := h.MustCreateChild(models.Child{FirstName: "Ben", LastName: "Schulz"})
+	h.SetClock(time.Date(2024, time.October, 16, 12, 0, 0, 0, models.FacilityLocation()))
+
+	var meeting models.TeamMeeting
+	h.MustDo(http.MethodPost, "/api/v1/meetings", mariaToken, map[string]any{
+		"meeting_date":         "2024-10-14",
+		"title":                "Fallbesprechung",
+		"minutes":              "Anna zieht sich in der Gruppe zurück, ein Elterngespräch wird vereinbart.",
+		"attendee_teacher_ids": []int{maria.ID, jonas.ID},
+		"child_ids":            []int{anna.ID},
+	}, http.StatusCreated, &meeting)
+	if meeting.RecordedByUserID == nil || len(meeting.AttendeeTeacherIDs) != 2 {
+		t.Fatalf("Expected the meeting with its author and attendees, got %+v", meeting)
+	}
+	h.MustDo(http.MethodPost, "/api/v1/meetings", mariaToken, map[string]any{
+		"meeting_date": "2024-10-14", "title": "Ohne Protokoll",
+	}, http.StatusBadRequest, nil)
+	h.MustDo(http.MethodPost, "/api/v1/meetings", mariaToken, map[string]any{
+		"meeting_date": "2024-10-14", "title": "Unbekanntes Kind", "minutes": "Notizen", "child_ids": []int{999},
+	}, http.StatusNotFound, nil)
+
+	actionItemsURL := fmt.Sprintf("/api/v1/meetings/%d/action-items", meeting.ID)
+	var talk, sheet models.ActionItem
+	h.MustDo(http.MethodPost, actionItemsURL, mariaToken, map[string]any{
+		"description": "Elterngespräch führen", "teacher_id": maria.ID, "child_id": anna.ID, "due_date": "2024-10-21",
+	}, http.StatusCreated, &talk)
+	h.MustDo(http.MethodPost, actionItemsURL, mariaToken, map[string]any{
+		"description": "Beobachtungsbogen ausfüllen", "teacher_id": jonas.ID, "child_id": ben.ID, "due_date": "2024-10-15",
+	}, http.StatusCreated, &sheet)
+
+	t.Run("Open Action Items", func(t *testing.T) {
+		var items []models.ActionItem
+		h.MustDo(http.MethodGet, "/api/v1/action-items/open", mariaToken, nil, http.StatusOK, &items)
+		if len(items) != 2 || items[0].ID != sheet.ID || !items[0].Overdue || items[1].Overdue {
+			t.Fatalf("Expected the overdue sheet before the talk, got %+v", items)
+		}
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/action-items/open?teacher_id=%d", maria.ID), mariaToken, nil, http.StatusOK, &items)
+		if len(items) != 1 || items[0].ID != talk.ID {
+			t.Errorf("Expected only the talk of Maria, got %+v", items)
+		}
+
+		var completed models.ActionItem
+		h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/action-items/%d/complete", sheet.ID), mariaToken, nil, http.StatusOK, &completed)
+		if completed.CompletedAt == nil || completed.Overdue {
+			t.Errorf("Expected the sheet to be completed, got %+v", completed)
+		}
+		h.MustDo(http.MethodGet, "/api/v1/action-items/open", mariaToken, nil, http.StatusOK, &items)
+		if len(items) != 1 {
+			t.Errorf("Expected one open item, got %+v", items)
+		}
+		h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/action-items/%d/reopen", sheet.ID), mariaToken, nil, http.StatusOK, &completed)
+		if completed.CompletedAt != nil {
+			t.Errorf("Expected the sheet to be reopened, got %+v", completed)
+		}
+	})
+
+	t.Run("Dashboard", func(t *testing.T) {
+		resp := h.Do(http.MethodPost, "/api/v1/graphql", mariaToken, map[string]string{
+			"query": fmt.Sprintf(`{ open_action_items(teacher_id: %d) { id description due_date overdue } }`, jonas.ID),
+		})
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+		}
+		var result struct {
+			Data struct {
+				OpenActionItems []struct {
+					ID      int    `json:"id"`
+					DueDate string `json:"due_date"`
+					Overdue bool   `json:"overdue"`
+				} `json:"open_action_items"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			t.Fatalf("Failed to unmarshal GraphQL result: %v", err)
+		}
+		items := result.Data.OpenActionItems
+		if len(items) != 1 || items[0].ID != sheet.ID || items[0].DueDate != "2024-10-15" || !items[0].Overdue {
+			t.Errorf("Expected the overdue sheet of Jonas, got %s", body)
+		}
+	})
+
+	t.Run("Meetings Of A Child", func(t *testing.T) {
+		var meetings []models.TeamMeeting
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/children/%d/meetings", ben.ID), mariaToken, nil, http.StatusOK, &meetings)
+		if len(meetings) != 1 || meetings[0].ID != meeting.ID || len(meetings[0].ActionItems) != 2 {
+			t.Fatalf("Expected the meeting Ben got an action item in, got %+v", meetings)
+		}
+		if meetings[0].Minutes != "Anna zieht sich in der Gruppe zurück, ein Elterngespräch wird vereinbart." {
+			t.Errorf("Expected the decrypted minutes, got %q", meetings[0].Minutes)
+		}
+		h.MustDo(http.MethodGet, "/api/v1/children/999/meetings", mariaToken, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("Deactivated Teachers Get No New Items", func(t *testing.T) {
+		h.MustDo(http.MethodPost, fmt.Sprintf("/api/v1/teachers/%d/deactivate", jonas.ID), adminToken, nil, http.StatusOK, nil)
+		h.MustDo(http.MethodPost, actionItemsURL, mariaToken, map[string]any{
+			"description": "Entwicklungsbericht schreiben", "teacher_id": jonas.ID, "due_date": "2024-10-30",
+		}, http.StatusConflict, nil)
+		h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/action-items/%d", sheet.ID), mariaToken, map[string]any{
+			"description": "Beobachtungsbogen übergeben", "teacher_id": maria.ID, "child_id": ben.ID, "due_date": "2024-10-18",
+		}, http.StatusOK, nil)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		meetingURL := fmt.Sprintf("/api/v1/meetings/%d", meeting.ID)
+		h.MustDo(http.MethodDelete, fmt.Sprintf("/api/v1/action-items/%d", talk.ID), mariaToken, nil, http.StatusNoContent, nil)
+		resp := h.Do(http.MethodDelete, meetingURL, mariaToken, nil)
+		readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected teachers not to delete meetings, got %d", resp.StatusCode)
+		}
+		h.MustDo(http.MethodDelete, meetingURL, adminToken, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodGet, meetingURL, mariaToken, nil, http.StatusNotFound, nil)
+		h.MustDo(http.MethodPut, fmt.Sprintf("/api/v1/action-items/%d/complete", sheet.ID), mariaToken, nil, http.StatusNotFound, nil)
+	})
+}
@@ -0,0 +1,159 @@
+package e2e_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+	"kitadoc-backend/testsupport"
+)
+
+// startFakeClamd serves the clamd INSTREAM command, reporting files that contain "EICAR" as infected.
+func startFakeClamd(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() }) //nolint:errcheck
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close() //nolint:errcheck
+				reader := bufio.NewReader(conn)
+				if command, err := reader.ReadString('\x00'); err != nil || command != "zINSTREAM\x00" {
+					return
+				}
+				var content bytes.Buffer
+				for {
+					var length uint32
+					if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+						return
+					}
+					if length == 0 {
+						break
+					}
+					if _, err := io.CopyN(&content, reader, int64(length)); err != nil {
+						return
+					}
+				}
+				if strings.Contains(content.String(), "EICAR") {
+					conn.Write([]byte("stream: Eicar-Signature FOUND\x00")) //nolint:errcheck
+					return
+				}
+				conn.Write([]byte("stream: OK\x00")) //nolint:errcheck
+			}()
+		}
+	}()
+	return listener
+}
+
+func TestUploadScanEndpoints(t *testing.T) {
+	clamd := startFakeClamd(t)
+	h := testsupport.New(t, func(cfg *config.Config) {
+		cfg.VirusScan.Provider = config.VirusScanClamAV
+		cfg.VirusScan.Address = "tcp://" + clamd.Addr().String()
+		cfg.VirusScan.Timeout = 5 * time.Second
+	})
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria", LastName: "Schmidt"})
+	token := h.MustLogin(teacher.Username)
+	adminToken := h.MustLogin(h.MustCreateUser(string(data.RoleAdmin)).Username)
+	child := h.MustCreateChild(models.Child{FirstName: "Anna", LastName: "Müller"})
+
+	upload := func(content []byte) *http.Response {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("document", "Betreuungsvertrag.pdf")
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write(content)                                                      //nolint:errcheck
+		writer.WriteField("document_type", models.ChildDocumentTypeCareContract) //nolint:errcheck
+		writer.Close()                                                           //nolint:errcheck
+
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/children/%d/documents", h.Server.URL, child.ID), body)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := h.Server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to upload document: %v", err)
+		}
+		return resp
+	}
+
+	t.Run("Clean Files Are Stored", func(t *testing.T) {
+		resp := upload([]byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n"))
+		if body := readResponseBody(t, resp); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", resp.StatusCode, body)
+		}
+	})
+
+	var quarantined []models.QuarantinedUpload
+	t.Run("Infected Files Are Quarantined", func(t *testing.T) {
+		resp := upload([]byte("%PDF-1.4\nEICAR\n"))
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), services.CodeUploadInfected) {
+			t.Fatalf("Expected the infected upload to be rejected, got %d: %s", resp.StatusCode, body)
+		}
+
+		var documents []models.ChildDocument
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/children/%d/documents", child.ID), token, nil, http.StatusOK, &documents)
+		if len(documents) != 1 {
+			t.Errorf("Expected only the clean document to be stored, got %+v", documents)
+		}
+
+		h.MustDo(http.MethodGet, "/api/v1/quarantine", token, nil, http.StatusForbidden, nil)
+		h.MustDo(http.MethodGet, "/api/v1/quarantine", adminToken, nil, http.StatusOK, &quarantined)
+		if len(quarantined) != 1 || quarantined[0].Kind != models.UploadKindChildDocument || quarantined[0].Signature != "Eicar-Signature" ||
+			quarantined[0].FileName != "Betreuungsvertrag.pdf" || quarantined[0].UploadedByUserID == nil {
+			t.Fatalf("Expected the quarantined document, got %+v", quarantined)
+		}
+
+		var entries []models.AuditLogEntry
+		h.MustDo(http.MethodGet, "/api/v1/audit-log", adminToken, nil, http.StatusOK, &entries)
+		found := false
+		for _, entry := range entries {
+			found = found || (entry.Action == models.AuditActionQuarantineUpload && entry.EntityID != nil && *entry.EntityID == quarantined[0].ID)
+		}
+		if !found {
+			t.Errorf("Expected the quarantine to be audited, got %+v", entries)
+		}
+	})
+
+	t.Run("Download And Delete", func(t *testing.T) {
+		resp := h.Do(http.MethodGet, fmt.Sprintf("/api/v1/quarantine/%d/content", quarantined[0].ID), adminToken, nil)
+		body := readResponseBody(t, resp)
+		if resp.StatusCode != http.StatusOK || string(body) != "%PDF-1.4\nEICAR\n" || resp.Header.Get("Content-Type") != "application/octet-stream" {
+			t.Fatalf("Expected the quarantined file as an opaque attachment, got %d %s: %s", resp.StatusCode, resp.Header.Get("Content-Type"), body)
+		}
+
+		h.MustDo(http.MethodDelete, fmt.Sprintf("/api/v1/quarantine/%d", quarantined[0].ID), adminToken, nil, http.StatusNoContent, nil)
+		h.MustDo(http.MethodDelete, fmt.Sprintf("/api/v1/quarantine/%d", quarantined[0].ID), adminToken, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("Uploads Fail Closed Without The Scanner", func(t *testing.T) {
+		clamd.Close() //nolint:errcheck
+		resp := upload([]byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n"))
+		if body := readResponseBody(t, resp); resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status 503, got %d: %s", resp.StatusCode, body)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package graphqlapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/sirupsen/logrus"
+
+	"kitadoc-backend/middleware"
+)
+
+const (
+	// DefaultMaxDepth allows e.g. children → completeness → categories with room for one more level.
+	DefaultMaxDepth = 6
+	// DefaultMaxComplexity allows the dashboard query over all children with their entries, assignments and stats.
+	DefaultMaxComplexity = 5000
+	// maxRequestSize limits the size of the request body.
+	maxRequestSize = 1 << 20
+)
+
+// Request is the body of a GraphQL request.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Handler serves the read-only GraphQL endpoint.
+type Handler struct {
+	Schema        graphql.Schema
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// NewHandler creates a new Handler with the default limits.
+// The schema is static, so failing to build it is a programming error.
+func NewHandler(resolver *Resolver) *Handler {
+	schema, err := NewSchema(resolver)
+	if err != nil {
+		panic("invalid GraphQL schema: " + err.Error())
+	}
+	return &Handler{Schema: schema, MaxDepth: DefaultMaxDepth, MaxComplexity: DefaultMaxComplexity}
+}
+
+// Query handles a GraphQL query. Malformed requests and queries exceeding the depth or complexity limit
+// are answered with 400, errors while resolving are reported in the errors of the result.
+func (handler *Handler) Query(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	var graphqlRequest Request
+	if err := json.NewDecoder(http.MaxBytesReader(writer, request.Body, maxRequestSize)).Decode(&graphqlRequest); err != nil || graphqlRequest.Query == "" {
+		logger.WithError(err).Warn("Invalid request body for GraphQL query")
+		http.Error(writer, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(graphqlRequest.Query), Name: "GraphQL request"})})
+	if err != nil {
+		writeResult(writer, logger, http.StatusBadRequest, &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)}})
+		return
+	}
+	if err := checkLimits(handler.Schema, document, graphqlRequest.OperationName, handler.MaxDepth, handler.MaxComplexity); err != nil {
+		logger.WithError(err).Warn("GraphQL query rejected")
+		writeResult(writer, logger, http.StatusBadRequest, &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)}})
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         handler.Schema,
+		RequestString:  graphqlRequest.Query,
+		VariableValues: graphqlRequest.Variables,
+		OperationName:  graphqlRequest.OperationName,
+		Context:        request.Context(),
+	})
+	writeResult(writer, logger, http.StatusOK, result)
+}
+
+func writeResult(writer http.ResponseWriter, logger *logrus.Entry, statusCode int, result *graphql.Result) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+	if err := json.NewEncoder(writer).Encode(result); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GraphQL query")
+	}
+}
@@ -0,0 +1,131 @@
+package graphqlapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listCostFactor is the assumed number of elements of a list field, its selections are resolved once per element.
+const listCostFactor = 10
+
+// limitChecker rejects queries that are nested too deeply or too expensive to resolve.
+// Every field costs one point, fields below a list field count listCostFactor times.
+// Introspection fields are not counted, they only read the static schema.
+type limitChecker struct {
+	schema        graphql.Schema
+	fragments     map[string]*ast.FragmentDefinition
+	maxDepth      int
+	maxComplexity int
+}
+
+// checkLimits validates the operation of the document that will be executed.
+func checkLimits(schema graphql.Schema, document *ast.Document, operationName string, maxDepth int, maxComplexity int) error {
+	checker := &limitChecker{
+		schema:        schema,
+		fragments:     make(map[string]*ast.FragmentDefinition),
+		maxDepth:      maxDepth,
+		maxComplexity: maxComplexity,
+	}
+	var operation *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			checker.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil {
+		// Unknown operations are reported by the executor.
+		return nil
+	}
+
+	complexity, err := checker.selectionCost(operation.SelectionSet, schema.QueryType(), 1, make(map[string]bool))
+	if err != nil {
+		return err
+	}
+	if complexity > maxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, maxComplexity)
+	}
+	return nil
+}
+
+func (checker *limitChecker) selectionCost(selectionSet *ast.SelectionSet, parent graphql.Type, depth int, visiting map[string]bool) (int, error) {
+	if selectionSet == nil {
+		return 0, nil
+	}
+	total := 0
+	for _, selection := range selectionSet.Selections {
+		var cost int
+		var err error
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			if depth > checker.maxDepth {
+				return 0, fmt.Errorf("query depth exceeds the limit of %d", checker.maxDepth)
+			}
+			fieldType, isList := checker.fieldType(parent, selection.Name.Value)
+			cost, err = checker.selectionCost(selection.SelectionSet, fieldType, depth+1, visiting)
+			if isList {
+				cost *= listCostFactor
+			}
+			cost++
+		case *ast.InlineFragment:
+			fragmentType := parent
+			if selection.TypeCondition != nil {
+				fragmentType = checker.schema.Type(selection.TypeCondition.Name.Value)
+			}
+			cost, err = checker.selectionCost(selection.SelectionSet, fragmentType, depth, visiting)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := checker.fragments[name]
+			if !ok || visiting[name] {
+				// Unknown and cyclic fragments are reported by the validation.
+				continue
+			}
+			visiting[name] = true
+			cost, err = checker.selectionCost(fragment.SelectionSet, checker.schema.Type(fragment.TypeCondition.Name.Value), depth, visiting)
+			delete(visiting, name)
+		}
+		if err != nil {
+			return 0, err
+		}
+		total += cost
+		if total > checker.maxComplexity {
+			return 0, fmt.Errorf("query complexity exceeds the limit of %d", checker.maxComplexity)
+		}
+	}
+	return total, nil
+}
+
+// fieldType returns the named type of a field and whether the field is a list.
+func (checker *limitChecker) fieldType(parent graphql.Type, name string) (graphql.Type, bool) {
+	object, ok := parent.(*graphql.Object)
+	if !ok {
+		return nil, false
+	}
+	field, ok := object.Fields()[name]
+	if !ok {
+		return nil, false
+	}
+	fieldType := field.Type
+	isList := false
+	for {
+		switch wrapped := fieldType.(type) {
+		case *graphql.NonNull:
+			fieldType = wrapped.OfType
+		case *graphql.List:
+			isList = true
+			fieldType = wrapped.OfType
+		default:
+			return fieldType, isList
+		}
+	}
+}
@@ -0,0 +1,316 @@
+// Package graphqlapi provides a read-only GraphQL endpoint for the dashboard over the existing services.
+// Field names follow the JSON names of the REST API.
+package graphqlapi
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// errInternal is returned to clients instead of service errors, which are logged.
+var errInternal = errors.New("internal server error")
+
+// Resolver holds the services the schema reads from.
+type Resolver struct {
+	ChildService              services.ChildService
+	DocumentationEntryService services.DocumentationEntryService
+	AssignmentService         services.AssignmentService
+	CompletenessService       services.CompletenessService
+	CategoryService           services.CategoryService
+	TeacherService            services.TeacherService
+	TeamMeetingService        services.TeamMeetingService
+}
+
+// jsonScalar passes arbitrary JSON values through, used for the values of observation forms.
+var jsonScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "JSON",
+	Description:  "An arbitrary JSON value.",
+	Serialize:    func(value any) any { return value },
+	ParseValue:   func(value any) any { return value },
+	ParseLiteral: func(valueAST ast.Value) any { return nil },
+})
+
+// dateScalar serializes models.Date as YYYY-MM-DD, dates have no time of day to put into a DateTime.
+var dateScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Date",
+	Description: "A calendar day as YYYY-MM-DD.",
+	Serialize: func(value any) any {
+		switch value := value.(type) {
+		case models.Date:
+			return value.String()
+		case *models.Date:
+			if value == nil {
+				return nil
+			}
+			return value.String()
+		default:
+			return nil
+		}
+	},
+	ParseValue:   func(value any) any { return value },
+	ParseLiteral: func(valueAST ast.Value) any { return nil },
+})
+
+// NewSchema builds the read-only schema.
+func NewSchema(resolver *Resolver) (graphql.Schema, error) {
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.Field{Type: graphql.String},
+		},
+	})
+	teacherType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Teacher",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"first_name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"last_name":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"username":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	assignmentType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Assignment",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"child_id":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"teacher_id": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"start_date": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"end_date":   &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+	entryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "DocumentationEntry",
+		Fields: graphql.Fields{
+			"id":                      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"child_id":                &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"teacher_id":              &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"category_id":             &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"observation_date":        &graphql.Field{Type: graphql.NewNonNull(dateScalar)},
+			"observation_description": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"structured_data":         &graphql.Field{Type: jsonScalar},
+			"is_approved":             &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"approved_by_teacher_id":  &graphql.Field{Type: graphql.Int},
+			"created_at":              &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+	actionItemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ActionItem",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"meeting_id":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"description": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"teacher_id":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"child_id":    &graphql.Field{Type: graphql.Int},
+			"due_date":    &graphql.Field{Type: graphql.NewNonNull(dateScalar)},
+			"overdue":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		},
+	})
+	coverageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CategoryCoverage",
+		Fields: graphql.Fields{
+			"category_id":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"category_name":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"entry_count":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"last_entry_date": &graphql.Field{Type: dateScalar},
+		},
+	})
+	completenessType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Completeness",
+		Fields: graphql.Fields{
+			"child_id":            &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"child_name":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"score":               &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"categories_total":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"categories_covered":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"entry_count":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"last_entry_date":     &graphql.Field{Type: dateScalar},
+			"last_entry_age_days": &graphql.Field{Type: graphql.Int},
+			"period_start":        &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"period_end":          &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"categories":          &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(coverageType))},
+		},
+	})
+	childType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Child",
+		Fields: graphql.Fields{
+			"id":                         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"first_name":                 &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"last_name":                  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"birthdate":                  &graphql.Field{Type: graphql.NewNonNull(dateScalar)},
+			"admission_date":             &graphql.Field{Type: graphql.DateTime},
+			"expected_school_enrollment": &graphql.Field{Type: graphql.DateTime},
+			"is_preschooler":             &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"entries": &graphql.Field{
+				Type:    graphql.NewList(graphql.NewNonNull(entryType)),
+				Resolve: resolver.childEntries,
+			},
+			"assignments": &graphql.Field{
+				Type:    graphql.NewList(graphql.NewNonNull(assignmentType)),
+				Resolve: resolver.childAssignments,
+			},
+			"completeness": &graphql.Field{
+				Type:    completenessType,
+				Resolve: resolver.childCompleteness,
+			},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"children": &graphql.Field{
+				Type:        graphql.NewList(graphql.NewNonNull(childType)),
+				Description: "All children that have not been archived.",
+				Resolve:     resolver.children,
+			},
+			"child": &graphql.Field{
+				Type:    childType,
+				Args:    graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}},
+				Resolve: resolver.child,
+			},
+			"assignments": &graphql.Field{
+				Type:    graphql.NewList(graphql.NewNonNull(assignmentType)),
+				Resolve: resolver.assignments,
+			},
+			"categories": &graphql.Field{
+				Type:    graphql.NewList(graphql.NewNonNull(categoryType)),
+				Resolve: resolver.categories,
+			},
+			"teachers": &graphql.Field{
+				Type:    graphql.NewList(graphql.NewNonNull(teacherType)),
+				Resolve: resolver.teachers,
+			},
+			"completeness": &graphql.Field{
+				Type:        graphql.NewList(graphql.NewNonNull(completenessType)),
+				Description: "Documentation completeness of all children.",
+				Resolve:     resolver.completeness,
+			},
+			"open_action_items": &graphql.Field{
+				Type:        graphql.NewList(graphql.NewNonNull(actionItemType)),
+				Description: "Open action items from team meetings, the earliest due first, optionally of a single teacher.",
+				Args:        graphql.FieldConfigArgument{"teacher_id": &graphql.ArgumentConfig{Type: graphql.Int}},
+				Resolve:     resolver.openActionItems,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func (resolver *Resolver) children(params graphql.ResolveParams) (any, error) {
+	children, err := resolver.ChildService.GetAllChildren(params.Context, models.ListQuery{})
+	if err != nil {
+		middleware.GetLoggerWithReqID(params.Context).WithError(err).Error("Error fetching children for GraphQL")
+		return nil, errInternal
+	}
+	return children, nil
+}
+
+func (resolver *Resolver) child(params graphql.ResolveParams) (any, error) {
+	id, _ := params.Args["id"].(int)
+	child, err := resolver.ChildService.GetChildByID(params.Context, id)
+	if errors.Is(err, services.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		middleware.GetLoggerWithReqID(params.Context).WithError(err).WithField("child_id", id).Error("Error fetching child for GraphQL")
+		return nil, errInternal
+	}
+	return child, nil
+}
+
+func (resolver *Resolver) assignments(params graphql.ResolveParams) (any, error) {
+	assignments, err := resolver.AssignmentService.GetAllAssignments(params.Context, models.ListQuery{})
+	if err != nil {
+		middleware.GetLoggerWithReqID(params.Context).WithError(err).Error("Error fetching assignments for GraphQL")
+		return nil, errInternal
+	}
+	return assignments, nil
+}
+
+func (resolver *Resolver) categories(params graphql.ResolveParams) (any, error) {
+	categories, err := resolver.CategoryService.GetAllCategories(params.Context, true)
+	if err != nil {
+		middleware.GetLoggerWithReqID(params.Context).WithError(err).Error("Error fetching categories for GraphQL")
+		return nil, errInternal
+	}
+	return categories, nil
+}
+
+func (resolver *Resolver) teachers(params graphql.ResolveParams) (any, error) {
+	teachers, err := resolver.TeacherService.GetAllTeachers(params.Context, true)
+	if err != nil {
+		middleware.GetLoggerWithReqID(params.Context).WithError(err).Error("Error fetching teachers for GraphQL")
+		return nil, errInternal
+	}
+	return teachers, nil
+}
+
+func (resolver *Resolver) completeness(params graphql.ResolveParams) (any, error) {
+	logger := middleware.GetLoggerWithReqID(params.Context)
+	completeness, err := resolver.CompletenessService.GetAllCompleteness(logger, params.Context)
+	if err != nil {
+		return nil, errInternal
+	}
+	return completeness, nil
+}
+
+func (resolver *Resolver) openActionItems(params graphql.ResolveParams) (any, error) {
+	logger := middleware.GetLoggerWithReqID(params.Context)
+	var teacherID *int
+	if id, ok := params.Args["teacher_id"].(int); ok {
+		teacherID = &id
+	}
+	items, err := resolver.TeamMeetingService.GetOpenActionItems(logger, params.Context, teacherID)
+	if err != nil {
+		return nil, errInternal
+	}
+	return items, nil
+}
+
+func (resolver *Resolver) childEntries(params graphql.ResolveParams) (any, error) {
+	logger := middleware.GetLoggerWithReqID(params.Context)
+	entries, err := resolver.DocumentationEntryService.GetAllDocumentationForChild(logger, params.Context, sourceChildID(params.Source), models.ListQuery{})
+	if err != nil {
+		return nil, errInternal
+	}
+	return entries, nil
+}
+
+func (resolver *Resolver) childAssignments(params graphql.ResolveParams) (any, error) {
+	childID := sourceChildID(params.Source)
+	assignments, err := resolver.AssignmentService.GetAssignmentHistoryForChild(params.Context, childID, models.ListQuery{})
+	if err != nil {
+		middleware.GetLoggerWithReqID(params.Context).WithError(err).WithField("child_id", childID).Error("Error fetching assignments of child for GraphQL")
+		return nil, errInternal
+	}
+	return assignments, nil
+}
+
+func (resolver *Resolver) childCompleteness(params graphql.ResolveParams) (any, error) {
+	logger := middleware.GetLoggerWithReqID(params.Context)
+	completeness, err := resolver.CompletenessService.GetChildCompleteness(logger, params.Context, sourceChildID(params.Source))
+	if err != nil {
+		return nil, errInternal
+	}
+	return completeness, nil
+}
+
+// sourceChildID returns the ID of the child a nested field is resolved on.
+func sourceChildID(source any) int {
+	switch child := source.(type) {
+	case models.Child:
+		return child.ID
+	case *models.Child:
+		return child.ID
+	}
+	return 0
+}
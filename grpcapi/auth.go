@@ -0,0 +1,60 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/logger"
+	"kitadoc-backend/middleware"
+)
+
+// UnaryAuthInterceptor authenticates calls with the bearer token of the HTTP API, sent as "authorization" metadata.
+// The read APIs require the teacher role, like their HTTP counterparts.
+func UnaryAuthInterceptor(userAuthenticator middleware.UserAuthenticator, jwtSecret string) grpc.UnaryServerInterceptor {
+	policy := middleware.RoleAccess(data.RoleTeacher)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		log := logger.GetGlobalLogger().GetLogrusEntry().WithField("grpc_method", info.FullMethod)
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			log.Warn("Unauthenticated: Missing authorization metadata")
+			return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+		}
+		tokenString := strings.TrimPrefix(values[0], "Bearer ")
+		if tokenString == values[0] {
+			log.Warn("Unauthenticated: Invalid authorization metadata format")
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization metadata format")
+		}
+
+		claims, err := middleware.ParseToken(tokenString, jwtSecret)
+		if err != nil {
+			log.WithError(err).Warn("Invalid or expired token")
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+		}
+		user, err := userAuthenticator.GetUserByID(log, ctx, claims.UserID)
+		if err != nil {
+			log.WithError(err).WithField("user_id", claims.UserID).Warn("User not found or inactive during authentication")
+			return nil, status.Error(codes.Unauthenticated, "user not found or inactive")
+		}
+		if claims.SessionID == 0 {
+			log.WithField("user_id", claims.UserID).Warn("Unauthenticated: Token without a session")
+			return nil, status.Error(codes.Unauthenticated, "session has ended")
+		}
+		if err := userAuthenticator.ValidateSession(log, ctx, claims.UserID, claims.SessionID); err != nil {
+			log.WithError(err).WithField("user_id", claims.UserID).Warn("Unauthenticated: Session has ended")
+			return nil, status.Error(codes.Unauthenticated, "session has ended")
+		}
+		if !policy.Allows(user.Role) {
+			return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+		}
+
+		return handler(context.WithValue(ctx, middleware.ContextKeyUser, user), req)
+	}
+}
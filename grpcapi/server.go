@@ -0,0 +1,208 @@
+// Package grpcapi exposes the core read APIs over gRPC for the internal reporting pipeline.
+// It shares the service layer with the HTTP handlers.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"kitadoc-backend/internal/logger"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	kitadocv1 "kitadoc-backend/proto/kitadoc/v1"
+	"kitadoc-backend/services"
+)
+
+// ReportingServer implements kitadocv1.ReportingServiceServer.
+type ReportingServer struct {
+	kitadocv1.UnimplementedReportingServiceServer
+	ChildService              services.ChildService
+	DocumentationEntryService services.DocumentationEntryService
+	CompletenessService       services.CompletenessService
+}
+
+// NewReportingServer creates a new ReportingServer.
+func NewReportingServer(childService services.ChildService, documentationEntryService services.DocumentationEntryService, completenessService services.CompletenessService) *ReportingServer {
+	return &ReportingServer{
+		ChildService:              childService,
+		DocumentationEntryService: documentationEntryService,
+		CompletenessService:       completenessService,
+	}
+}
+
+// NewServer creates a gRPC server that serves the reporting service behind the authentication interceptor.
+func NewServer(reportingServer *ReportingServer, userAuthenticator middleware.UserAuthenticator, jwtSecret string) *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(UnaryAuthInterceptor(userAuthenticator, jwtSecret)))
+	kitadocv1.RegisterReportingServiceServer(server, reportingServer)
+	return server
+}
+
+// ListChildren returns all children that have not been archived.
+func (server *ReportingServer) ListChildren(ctx context.Context, request *kitadocv1.ListChildrenRequest) (*kitadocv1.ListChildrenResponse, error) {
+	children, err := server.ChildService.GetAllChildren(ctx, models.ListQuery{})
+	if err != nil {
+		return nil, toStatusError(getLogger("ListChildren"), err)
+	}
+
+	response := &kitadocv1.ListChildrenResponse{Children: make([]*kitadocv1.Child, 0, len(children))}
+	for i := range children {
+		response.Children = append(response.Children, toChildMessage(&children[i]))
+	}
+	return response, nil
+}
+
+// GetChild returns a single child.
+func (server *ReportingServer) GetChild(ctx context.Context, request *kitadocv1.GetChildRequest) (*kitadocv1.Child, error) {
+	child, err := server.ChildService.GetChildByID(ctx, int(request.GetChildId()))
+	if err != nil {
+		return nil, toStatusError(getLogger("GetChild").WithField("child_id", request.GetChildId()), err)
+	}
+	return toChildMessage(child), nil
+}
+
+// ListDocumentationEntries returns all documentation entries of a child.
+func (server *ReportingServer) ListDocumentationEntries(ctx context.Context, request *kitadocv1.ListDocumentationEntriesRequest) (*kitadocv1.ListDocumentationEntriesResponse, error) {
+	log := getLogger("ListDocumentationEntries").WithField("child_id", request.GetChildId())
+	childID := int(request.GetChildId())
+	if _, err := server.ChildService.GetChildByID(ctx, childID); err != nil {
+		return nil, toStatusError(log, err)
+	}
+	entries, err := server.DocumentationEntryService.GetAllDocumentationForChild(log, ctx, childID, models.ListQuery{})
+	if err != nil {
+		return nil, toStatusError(log, err)
+	}
+
+	response := &kitadocv1.ListDocumentationEntriesResponse{Entries: make([]*kitadocv1.DocumentationEntry, 0, len(entries))}
+	for i := range entries {
+		entry, err := toDocumentationEntryMessage(&entries[i])
+		if err != nil {
+			log.WithError(err).WithField("entry_id", entries[i].ID).Error("Failed to convert structured data of documentation entry")
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		response.Entries = append(response.Entries, entry)
+	}
+	return response, nil
+}
+
+// GetDocumentationStats returns the documentation completeness of all children.
+func (server *ReportingServer) GetDocumentationStats(ctx context.Context, request *kitadocv1.GetDocumentationStatsRequest) (*kitadocv1.GetDocumentationStatsResponse, error) {
+	log := getLogger("GetDocumentationStats")
+	completeness, err := server.CompletenessService.GetAllCompleteness(log, ctx)
+	if err != nil {
+		return nil, toStatusError(log, err)
+	}
+
+	response := &kitadocv1.GetDocumentationStatsResponse{Children: make([]*kitadocv1.ChildStats, 0, len(completeness))}
+	for i := range completeness {
+		response.Children = append(response.Children, toChildStatsMessage(&completeness[i]))
+	}
+	return response, nil
+}
+
+func getLogger(method string) *logrus.Entry {
+	return logger.GetGlobalLogger().GetLogrusEntry().WithField("grpc_method", method)
+}
+
+// toStatusError maps service errors to gRPC status codes.
+func toStatusError(log *logrus.Entry, err error) error {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		return status.Error(codes.NotFound, "not found")
+	case errors.Is(err, services.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, "invalid input")
+	case errors.Is(err, services.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "permission denied")
+	}
+	log.WithError(err).Error("Internal server error in gRPC call")
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func toChildMessage(child *models.Child) *kitadocv1.Child {
+	return &kitadocv1.Child{
+		Id:                       int64(child.ID),
+		FirstName:                child.FirstName,
+		LastName:                 child.LastName,
+		Birthdate:                timestamppb.New(child.Birthdate.Time),
+		AdmissionDate:            toTimestamp(child.AdmissionDate),
+		ExpectedSchoolEnrollment: toTimestamp(child.ExpectedSchoolEnrollment),
+		IsPreschooler:            child.IsPreschooler,
+		CreatedAt:                timestamppb.New(child.CreatedAt),
+		UpdatedAt:                timestamppb.New(child.UpdatedAt),
+	}
+}
+
+func toDocumentationEntryMessage(entry *models.DocumentationEntry) (*kitadocv1.DocumentationEntry, error) {
+	message := &kitadocv1.DocumentationEntry{
+		Id:                     int64(entry.ID),
+		ChildId:                int64(entry.ChildID),
+		TeacherId:              int64(entry.TeacherID),
+		CategoryId:             int64(entry.CategoryID),
+		ObservationDate:        timestamppb.New(entry.ObservationDate.Time),
+		ObservationDescription: entry.ObservationDescription,
+		IsApproved:             entry.IsApproved,
+		CreatedAt:              timestamppb.New(entry.CreatedAt),
+		UpdatedAt:              timestamppb.New(entry.UpdatedAt),
+	}
+	if entry.ApprovedByUserID != nil {
+		approvedBy := int64(*entry.ApprovedByUserID)
+		message.ApprovedByUserId = &approvedBy
+	}
+	if entry.StructuredData != nil {
+		structuredData, err := structpb.NewStruct(entry.StructuredData)
+		if err != nil {
+			return nil, err
+		}
+		message.StructuredData = structuredData
+	}
+	return message, nil
+}
+
+func toChildStatsMessage(completeness *models.ChildCompleteness) *kitadocv1.ChildStats {
+	message := &kitadocv1.ChildStats{
+		ChildId:           int64(completeness.ChildID),
+		ChildName:         completeness.ChildName,
+		Score:             int32(completeness.Score),
+		CategoriesTotal:   int32(completeness.CategoriesTotal),
+		CategoriesCovered: int32(completeness.CategoriesCovered),
+		EntryCount:        int32(completeness.EntryCount),
+		LastEntryDate:     dateToTimestamp(completeness.LastEntryDate),
+		PeriodStart:       timestamppb.New(completeness.PeriodStart),
+		PeriodEnd:         timestamppb.New(completeness.PeriodEnd),
+		Categories:        make([]*kitadocv1.CategoryCoverage, 0, len(completeness.Categories)),
+	}
+	if completeness.LastEntryAgeDays != nil {
+		ageDays := int32(*completeness.LastEntryAgeDays)
+		message.LastEntryAgeDays = &ageDays
+	}
+	for _, category := range completeness.Categories {
+		message.Categories = append(message.Categories, &kitadocv1.CategoryCoverage{
+			CategoryId:    int64(category.CategoryID),
+			CategoryName:  category.CategoryName,
+			EntryCount:    int32(category.EntryCount),
+			LastEntryDate: dateToTimestamp(category.LastEntryDate),
+		})
+	}
+	return message
+}
+
+func toTimestamp(value *time.Time) *timestamppb.Timestamp {
+	if value == nil {
+		return nil
+	}
+	return timestamppb.New(*value)
+}
+
+func dateToTimestamp(value *models.Date) *timestamppb.Timestamp {
+	if value == nil {
+		return nil
+	}
+	return timestamppb.New(value.Time)
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// AnnouncementHandler handles announcement-related HTTP requests.
+type AnnouncementHandler struct {
+	AnnouncementService services.AnnouncementService
+}
+
+// NewAnnouncementHandler creates a new AnnouncementHandler.
+func NewAnnouncementHandler(announcementService services.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{AnnouncementService: announcementService}
+}
+
+// CreateAnnouncement handles posting a new announcement.
+func (handler *AnnouncementHandler) CreateAnnouncement(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for CreateAnnouncement handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var announcement models.Announcement
+	if err := json.NewDecoder(request.Body).Decode(&announcement); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateAnnouncement")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	announcement.CreatedByUserID = &user.ID
+
+	createdAnnouncement, err := handler.AnnouncementService.CreateAnnouncement(logger, request.Context(), &announcement)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		if err == services.ErrInvalidInput {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error during announcement creation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/announcements", createdAnnouncement.ID)
+	if err := json.NewEncoder(writer).Encode(createdAnnouncement); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateAnnouncement")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAllAnnouncements handles fetching all announcements.
+func (handler *AnnouncementHandler) GetAllAnnouncements(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	announcements, err := handler.AnnouncementService.GetAllAnnouncements(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching announcements")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if announcements == nil {
+		announcements = []models.Announcement{}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(announcements); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAllAnnouncements")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetUnreadAnnouncements handles fetching the announcements the current user has not acknowledged.
+func (handler *AnnouncementHandler) GetUnreadAnnouncements(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for GetUnreadAnnouncements handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	announcements, err := handler.AnnouncementService.GetUnreadAnnouncements(logger, request.Context(), user.ID)
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching unread announcements")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if announcements == nil {
+		announcements = []models.Announcement{}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(announcements); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetUnreadAnnouncements")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// AcknowledgeAnnouncement handles recording that the current user has read an announcement.
+func (handler *AnnouncementHandler) AcknowledgeAnnouncement(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for AcknowledgeAnnouncement handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	announcementIDStr := request.PathValue("announcement_id")
+	announcementID, err := strconv.Atoi(announcementIDStr)
+	if err != nil {
+		logger.WithField("announcement_id_str", announcementIDStr).WithError(err).Warn("Invalid announcement ID format for AcknowledgeAnnouncement")
+		http.Error(writer, "Invalid announcement ID", http.StatusBadRequest)
+		return
+	}
+
+	err = handler.AnnouncementService.AcknowledgeAnnouncement(logger, request.Context(), announcementID, user.ID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Announcement not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("announcement_id", announcementID).Error("Internal server error during announcement acknowledgement")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Announcement acknowledged successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for AcknowledgeAnnouncement")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAcknowledgements handles fetching the read receipts of an announcement.
+func (handler *AnnouncementHandler) GetAcknowledgements(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	announcementIDStr := request.PathValue("announcement_id")
+	announcementID, err := strconv.Atoi(announcementIDStr)
+	if err != nil {
+		logger.WithField("announcement_id_str", announcementIDStr).WithError(err).Warn("Invalid announcement ID format for GetAcknowledgements")
+		http.Error(writer, "Invalid announcement ID", http.StatusBadRequest)
+		return
+	}
+
+	acknowledgements, err := handler.AnnouncementService.GetAcknowledgements(logger, request.Context(), announcementID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Announcement not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("announcement_id", announcementID).Error("Internal server error fetching acknowledgements")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if acknowledgements == nil {
+		acknowledgements = []models.AnnouncementAcknowledgement{}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(acknowledgements); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAcknowledgements")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteAnnouncement handles deleting an announcement.
+func (handler *AnnouncementHandler) DeleteAnnouncement(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	announcementIDStr := request.PathValue("announcement_id")
+	announcementID, err := strconv.Atoi(announcementIDStr)
+	if err != nil {
+		logger.WithField("announcement_id_str", announcementIDStr).WithError(err).Warn("Invalid announcement ID format for DeleteAnnouncement")
+		http.Error(writer, "Invalid announcement ID", http.StatusBadRequest)
+		return
+	}
+
+	err = handler.AnnouncementService.DeleteAnnouncement(logger, request.Context(), announcementID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Announcement not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("announcement_id", announcementID).Error("Internal server error during announcement deletion")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
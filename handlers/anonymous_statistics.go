@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// AnonymousStatisticsHandler handles the export of documentation statistics without personal data.
+type AnonymousStatisticsHandler struct {
+	AnonymousStatisticsService services.AnonymousStatisticsService
+}
+
+// NewAnonymousStatisticsHandler creates a new AnonymousStatisticsHandler.
+func NewAnonymousStatisticsHandler(anonymousStatisticsService services.AnonymousStatisticsService) *AnonymousStatisticsHandler {
+	return &AnonymousStatisticsHandler{AnonymousStatisticsService: anonymousStatisticsService}
+}
+
+// ExportAnonymousStatistics handles exporting the anonymous statistics as a JSON download.
+func (handler *AnonymousStatisticsHandler) ExportAnonymousStatistics(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for ExportAnonymousStatistics handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	statistics, err := handler.AnonymousStatisticsService.GetAnonymousStatistics(logger, request.Context(), user.ID)
+	if err != nil {
+		logger.WithError(err).Error("Internal server error exporting anonymous statistics")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=statistik-%s.json", statistics.GeneratedAt.Format("2006-01-02")))
+	if err := json.NewEncoder(writer).Encode(statistics); err != nil {
+		logger.WithError(err).Error("Failed to encode response for ExportAnonymousStatistics")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// ApprovalDelegationHandler handles approval delegation HTTP requests.
+type ApprovalDelegationHandler struct {
+	ApprovalDelegationService services.ApprovalDelegationService
+}
+
+// NewApprovalDelegationHandler creates a new ApprovalDelegationHandler.
+func NewApprovalDelegationHandler(approvalDelegationService services.ApprovalDelegationService) *ApprovalDelegationHandler {
+	return &ApprovalDelegationHandler{ApprovalDelegationService: approvalDelegationService}
+}
+
+// CreateDelegation handles delegating the current user's approval rights to another user.
+func (handler *ApprovalDelegationHandler) CreateDelegation(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for CreateDelegation handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var delegation models.ApprovalDelegation
+	if err := json.NewDecoder(request.Body).Decode(&delegation); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateDelegation")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	delegation.DelegatorUserID = user.ID
+
+	createdDelegation, err := handler.ApprovalDelegationService.CreateDelegation(logger, request.Context(), &delegation)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		if err == services.ErrInvalidInput {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error during approval delegation creation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/approval-delegations", createdDelegation.ID)
+	if err := json.NewEncoder(writer).Encode(createdDelegation); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateDelegation")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetDelegations handles fetching the delegations the current user has granted or received.
+func (handler *ApprovalDelegationHandler) GetDelegations(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for GetDelegations handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	delegations, err := handler.ApprovalDelegationService.GetDelegationsForUser(logger, request.Context(), user.ID)
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching approval delegations")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if delegations == nil {
+		delegations = []models.ApprovalDelegation{}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(delegations); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetDelegations")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteDelegation handles revoking an approval delegation.
+func (handler *ApprovalDelegationHandler) DeleteDelegation(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	delegationIDStr := request.PathValue("delegation_id")
+	delegationID, err := strconv.Atoi(delegationIDStr)
+	if err != nil {
+		logger.WithField("delegation_id_str", delegationIDStr).WithError(err).Warn("Invalid delegation ID format for DeleteDelegation")
+		http.Error(writer, "Invalid delegation ID", http.StatusBadRequest)
+		return
+	}
+
+	err = handler.ApprovalDelegationService.DeleteDelegation(logger, request.Context(), delegationID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Approval delegation not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("delegation_id", delegationID).Error("Internal server error during approval delegation deletion")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
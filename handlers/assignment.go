@@ -4,8 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
-	"time"
 
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 )
@@ -13,11 +13,12 @@ import (
 // AssignmentHandler handles assignment-related HTTP requests.
 type AssignmentHandler struct {
 	AssignmentService services.AssignmentService
+	Clock             clock.Clock
 }
 
 // NewAssignmentHandler creates a new AssignmentHandler.
-func NewAssignmentHandler(assignmentService services.AssignmentService) *AssignmentHandler {
-	return &AssignmentHandler{AssignmentService: assignmentService}
+func NewAssignmentHandler(assignmentService services.AssignmentService, clock clock.Clock) *AssignmentHandler {
+	return &AssignmentHandler{AssignmentService: assignmentService, Clock: clock}
 }
 
 // CreateAssignment handles creating a new assignment.
@@ -28,28 +29,62 @@ func (assignmentHandler *AssignmentHandler) CreateAssignment(writer http.Respons
 		return
 	}
 
-	assignment.StartDate = time.Now()
-	assignment.CreatedAt = time.Now()
-	assignment.UpdatedAt = time.Now()
+	assignment.StartDate = assignmentHandler.Clock.Now()
 
-	createdAssignment, err := assignmentHandler.AssignmentService.CreateAssignment(&assignment)
+	createdAssignment, err := assignmentHandler.AssignmentService.CreateAssignment(request.Context(), &assignment)
 	if err != nil {
 		if err == services.ErrInvalidInput {
 			http.Error(writer, "Invalid assignment data provided", http.StatusBadRequest)
 			return
 		}
+		if err == services.ErrAlreadyExists {
+			http.Error(writer, "Child already has an open primary assignment", http.StatusConflict)
+			return
+		}
+		if writeValidationError(writer, err) {
+			return
+		}
+		if writeDomainError(writer, err) {
+			return
+		}
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	writer.WriteHeader(http.StatusCreated)
+	writeCreatedHeader(writer, "/api/v1/assignments", createdAssignment.ID)
 	if err := json.NewEncoder(writer).Encode(createdAssignment); err != nil {
 		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
+// GetAssignmentByID handles fetching an assignment by ID.
+func (assignmentHandler *AssignmentHandler) GetAssignmentByID(writer http.ResponseWriter, request *http.Request) {
+	assignmentIDStr := request.PathValue("assignment_id")
+	assignmentID, err := strconv.Atoi(assignmentIDStr)
+	if err != nil {
+		http.Error(writer, "Invalid assignment ID", http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := assignmentHandler.AssignmentService.GetAssignmentByID(request.Context(), assignmentID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Assignment not found", http.StatusNotFound)
+			return
+		}
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(assignment); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
 // GetAssignmentsByChildID handles fetching assignments by child ID.
+// The query parameters narrow down, sort and paginate the result, see parseAssignmentQuery.
 func (assignmentHandler *AssignmentHandler) GetAssignmentsByChildID(writer http.ResponseWriter, request *http.Request) {
 	childIDStr := request.PathValue("child_id")
 	childID, err := strconv.Atoi(childIDStr)
@@ -57,12 +92,22 @@ func (assignmentHandler *AssignmentHandler) GetAssignmentsByChildID(writer http.
 		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
 		return
 	}
+	query, ok := parseAssignmentQuery(writer, request)
+	if !ok {
+		return
+	}
 
-	assignments, err := assignmentHandler.AssignmentService.GetAssignmentHistoryForChild(childID)
+	assignments, err := assignmentHandler.AssignmentService.GetAssignmentHistoryForChild(request.Context(), childID, query)
 	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	if assignments == nil {
+		assignments = []models.Assignment{}
+	}
 
 	if err := json.NewEncoder(writer).Encode(assignments); err != nil {
 		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
@@ -71,12 +116,21 @@ func (assignmentHandler *AssignmentHandler) GetAssignmentsByChildID(writer http.
 }
 
 // GetAllAssignments handles fetching all assignments.
+// The query parameters narrow down, sort and paginate the result, see parseAssignmentQuery.
 func (assignmentHandler *AssignmentHandler) GetAllAssignments(writer http.ResponseWriter, request *http.Request) {
-	assignments, err := assignmentHandler.AssignmentService.GetAllAssignments()
+	query, ok := parseAssignmentQuery(writer, request, idFilter("child_id", "child_id"))
+	if !ok {
+		return
+	}
+
+	assignments, err := assignmentHandler.AssignmentService.GetAllAssignments(request.Context(), query)
 	if err != nil {
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	if assignments == nil {
+		assignments = []models.Assignment{}
+	}
 
 	if err := json.NewEncoder(writer).Encode(assignments); err != nil {
 		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
@@ -100,10 +154,12 @@ func (assignmentHandler *AssignmentHandler) UpdateAssignment(writer http.Respons
 	}
 
 	assignment.ID = assignmentID
-	assignment.UpdatedAt = time.Now()
 
-	err = assignmentHandler.AssignmentService.UpdateAssignment(&assignment)
+	err = assignmentHandler.AssignmentService.UpdateAssignment(request.Context(), &assignment)
 	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
 		if err == services.ErrNotFound {
 			http.Error(writer, "Assignment not found", http.StatusNotFound)
 			return
@@ -112,6 +168,10 @@ func (assignmentHandler *AssignmentHandler) UpdateAssignment(writer http.Respons
 			http.Error(writer, "Invalid assignment data provided", http.StatusBadRequest)
 			return
 		}
+		if err == services.ErrAlreadyExists {
+			http.Error(writer, "Child already has an open primary assignment", http.StatusConflict)
+			return
+		}
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -136,7 +196,7 @@ func (assignmentHandler *AssignmentHandler) DeleteAssignment(writer http.Respons
 		return
 	}
 
-	err = assignmentHandler.AssignmentService.DeleteAssignment(assignmentID)
+	err = assignmentHandler.AssignmentService.DeleteAssignment(request.Context(), assignmentID)
 	if err != nil {
 		if err == services.ErrNotFound {
 			http.Error(writer, "Assignment not found", http.StatusNotFound)
@@ -152,3 +212,32 @@ func (assignmentHandler *AssignmentHandler) DeleteAssignment(writer http.Respons
 		return
 	}
 }
+
+// parseAssignmentQuery reads the query parameters type (primary, secondary or intern), open (true for assignments
+// without an end date) and teacher_id, the given filters and sort, limit and offset, see parseListQuery.
+// It answers with 400 if they are invalid.
+func parseAssignmentQuery(writer http.ResponseWriter, request *http.Request, filters ...listFilter) (models.ListQuery, bool) {
+	query, ok := parseListQuery(writer, request, []string{"id", "start_date", "end_date"}, append(filters, idFilter("teacher_id", "teacher_id"))...)
+	if !ok {
+		return query, false
+	}
+	switch assignmentType := request.URL.Query().Get("type"); assignmentType {
+	case "":
+	case models.AssignmentTypePrimary, models.AssignmentTypeSecondary, models.AssignmentTypeIntern:
+		query = query.Where("assignment_type", models.OperatorEqual, assignmentType)
+	default:
+		http.Error(writer, "type must be primary, secondary or intern", http.StatusBadRequest)
+		return query, false
+	}
+	if openStr := request.URL.Query().Get("open"); openStr != "" {
+		open, err := strconv.ParseBool(openStr)
+		if err != nil {
+			http.Error(writer, "Invalid open value", http.StatusBadRequest)
+			return query, false
+		}
+		if open {
+			query = query.Where("end_date", models.OperatorIsNull, nil)
+		}
+	}
+	return query, true
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"kitadoc-backend/handlers/mocks"
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 
@@ -21,14 +22,15 @@ import (
 func TestCreateAssignment(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignment := models.Assignment{
+			ID:        4,
 			ChildID:   1,
 			TeacherID: 1,
 			StartDate: time.Now(),
 		}
-		mockService.On("CreateAssignment", mock.AnythingOfType("*models.Assignment")).Return(&assignment, nil).Once()
+		mockService.On("CreateAssignment", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(&assignment, nil).Once()
 
 		body, _ := json.Marshal(assignment)
 		req := httptest.NewRequest(http.MethodPost, "/assignments", bytes.NewBuffer(body))
@@ -37,6 +39,7 @@ func TestCreateAssignment(t *testing.T) {
 		handler.CreateAssignment(rr, req)
 
 		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "/api/v1/assignments/4", rr.Header().Get("Location"))
 		var createdAssignment models.Assignment
 		json.NewDecoder(rr.Body).Decode(&createdAssignment) //nolint:errcheck
 		assert.Equal(t, assignment.ChildID, createdAssignment.ChildID)
@@ -45,7 +48,7 @@ func TestCreateAssignment(t *testing.T) {
 
 	t.Run("invalid request payload", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		req := httptest.NewRequest(http.MethodPost, "/assignments", bytes.NewBuffer([]byte("invalid json")))
 		rr := httptest.NewRecorder()
@@ -54,19 +57,19 @@ func TestCreateAssignment(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 		assert.Contains(t, rr.Body.String(), "Invalid request payload")
-		mockService.AssertNotCalled(t, "CreateAssignment", mock.Anything)
+		mockService.AssertNotCalled(t, "CreateAssignment", mock.Anything, mock.Anything)
 	})
 
 	t.Run("service returns invalid input error", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignment := models.Assignment{
 			ChildID:   1,
 			TeacherID: 1,
 			StartDate: time.Now(),
 		}
-		mockService.On("CreateAssignment", mock.AnythingOfType("*models.Assignment")).Return(nil, services.ErrInvalidInput).Once()
+		mockService.On("CreateAssignment", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(nil, services.ErrInvalidInput).Once()
 
 		body, _ := json.Marshal(assignment)
 		req := httptest.NewRequest(http.MethodPost, "/assignments", bytes.NewBuffer(body))
@@ -81,14 +84,14 @@ func TestCreateAssignment(t *testing.T) {
 
 	t.Run("service returns internal server error", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignment := models.Assignment{
 			ChildID:   1,
 			TeacherID: 1,
 			StartDate: time.Now(),
 		}
-		mockService.On("CreateAssignment", mock.AnythingOfType("*models.Assignment")).Return(nil, errors.New("db error")).Once()
+		mockService.On("CreateAssignment", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(nil, errors.New("db error")).Once()
 
 		body, _ := json.Marshal(assignment)
 		req := httptest.NewRequest(http.MethodPost, "/assignments", bytes.NewBuffer(body))
@@ -105,14 +108,14 @@ func TestCreateAssignment(t *testing.T) {
 func TestGetAssignmentsByChildID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		childID := 1
 		assignments := []models.Assignment{
 			{ID: 1, ChildID: childID, StartDate: time.Now()},
 			{ID: 2, ChildID: childID, StartDate: time.Now()},
 		}
-		mockService.On("GetAssignmentHistoryForChild", childID).Return(assignments, nil).Once()
+		mockService.On("GetAssignmentHistoryForChild", mock.Anything, childID, models.ListQuery{}).Return(assignments, nil).Once()
 
 		router := http.NewServeMux()
 		router.HandleFunc("GET /assignments/child/{child_id}", handler.GetAssignmentsByChildID)
@@ -132,7 +135,7 @@ func TestGetAssignmentsByChildID(t *testing.T) {
 
 	t.Run("invalid child ID", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		router := http.NewServeMux()
 		router.HandleFunc("GET /assignments/child/{child_id}", handler.GetAssignmentsByChildID)
@@ -144,15 +147,15 @@ func TestGetAssignmentsByChildID(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 		assert.Contains(t, rr.Body.String(), "Invalid child ID")
-		mockService.AssertNotCalled(t, "GetAssignmentHistoryForChild", mock.Anything)
+		mockService.AssertNotCalled(t, "GetAssignmentHistoryForChild", mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("service returns error", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		childID := 1
-		mockService.On("GetAssignmentHistoryForChild", childID).Return(nil, errors.New("db error")).Once()
+		mockService.On("GetAssignmentHistoryForChild", mock.Anything, childID, models.ListQuery{}).Return(nil, errors.New("db error")).Once()
 
 		router := http.NewServeMux()
 		router.HandleFunc("GET /assignments/child/{child_id}", handler.GetAssignmentsByChildID)
@@ -171,7 +174,7 @@ func TestGetAssignmentsByChildID(t *testing.T) {
 func TestUpdateAssignment(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignmentID := 1
 		assignment := models.Assignment{
@@ -180,7 +183,7 @@ func TestUpdateAssignment(t *testing.T) {
 			TeacherID: 1,
 			StartDate: time.Now(),
 		}
-		mockService.On("UpdateAssignment", mock.AnythingOfType("*models.Assignment")).Return(nil).Once()
+		mockService.On("UpdateAssignment", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(nil).Once()
 
 		body, _ := json.Marshal(assignment)
 		router := http.NewServeMux()
@@ -198,7 +201,7 @@ func TestUpdateAssignment(t *testing.T) {
 
 	t.Run("invalid assignment ID", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		router := http.NewServeMux()
 		router.HandleFunc("PUT /assignments/{assignment_id}", handler.UpdateAssignment)
@@ -210,12 +213,12 @@ func TestUpdateAssignment(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 		assert.Contains(t, rr.Body.String(), "Invalid assignment ID")
-		mockService.AssertNotCalled(t, "UpdateAssignment", mock.Anything)
+		mockService.AssertNotCalled(t, "UpdateAssignment", mock.Anything, mock.Anything)
 	})
 
 	t.Run("invalid request payload", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignmentID := 1
 		router := http.NewServeMux()
@@ -228,12 +231,12 @@ func TestUpdateAssignment(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 		assert.Contains(t, rr.Body.String(), "Invalid request payload")
-		mockService.AssertNotCalled(t, "UpdateAssignment", mock.Anything)
+		mockService.AssertNotCalled(t, "UpdateAssignment", mock.Anything, mock.Anything)
 	})
 
 	t.Run("assignment not found", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignmentID := 1
 		assignment := models.Assignment{
@@ -242,7 +245,7 @@ func TestUpdateAssignment(t *testing.T) {
 			TeacherID: 1,
 			StartDate: time.Now(),
 		}
-		mockService.On("UpdateAssignment", mock.AnythingOfType("*models.Assignment")).Return(services.ErrNotFound).Once()
+		mockService.On("UpdateAssignment", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(services.ErrNotFound).Once()
 
 		body, _ := json.Marshal(assignment)
 		router := http.NewServeMux()
@@ -260,7 +263,7 @@ func TestUpdateAssignment(t *testing.T) {
 
 	t.Run("invalid assignment data provided", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignmentID := 1
 		assignment := models.Assignment{
@@ -269,7 +272,7 @@ func TestUpdateAssignment(t *testing.T) {
 			TeacherID: 1,
 			StartDate: time.Now(),
 		}
-		mockService.On("UpdateAssignment", mock.AnythingOfType("*models.Assignment")).Return(services.ErrInvalidInput).Once()
+		mockService.On("UpdateAssignment", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(services.ErrInvalidInput).Once()
 
 		body, _ := json.Marshal(assignment)
 		router := http.NewServeMux()
@@ -287,7 +290,7 @@ func TestUpdateAssignment(t *testing.T) {
 
 	t.Run("internal server error", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignmentID := 1
 		assignment := models.Assignment{
@@ -296,7 +299,7 @@ func TestUpdateAssignment(t *testing.T) {
 			TeacherID: 1,
 			StartDate: time.Now(),
 		}
-		mockService.On("UpdateAssignment", mock.AnythingOfType("*models.Assignment")).Return(errors.New("db error")).Once()
+		mockService.On("UpdateAssignment", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(errors.New("db error")).Once()
 
 		body, _ := json.Marshal(assignment)
 		router := http.NewServeMux()
@@ -316,10 +319,10 @@ func TestUpdateAssignment(t *testing.T) {
 func TestDeleteAssignment(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignmentID := 1
-		mockService.On("DeleteAssignment", assignmentID).Return(nil).Once()
+		mockService.On("DeleteAssignment", mock.Anything, assignmentID).Return(nil).Once()
 
 		router := http.NewServeMux()
 		router.HandleFunc("DELETE /assignments/{assignment_id}", handler.DeleteAssignment)
@@ -336,7 +339,7 @@ func TestDeleteAssignment(t *testing.T) {
 
 	t.Run("invalid assignment ID", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		router := http.NewServeMux()
 		router.HandleFunc("DELETE /assignments/{assignment_id}", handler.DeleteAssignment)
@@ -348,15 +351,15 @@ func TestDeleteAssignment(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 		assert.Contains(t, rr.Body.String(), "Invalid assignment ID")
-		mockService.AssertNotCalled(t, "DeleteAssignment", mock.Anything)
+		mockService.AssertNotCalled(t, "DeleteAssignment", mock.Anything, mock.Anything)
 	})
 
 	t.Run("assignment not found", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignmentID := 1
-		mockService.On("DeleteAssignment", assignmentID).Return(services.ErrNotFound).Once()
+		mockService.On("DeleteAssignment", mock.Anything, assignmentID).Return(services.ErrNotFound).Once()
 
 		router := http.NewServeMux()
 		router.HandleFunc("DELETE /assignments/{assignment_id}", handler.DeleteAssignment)
@@ -373,10 +376,10 @@ func TestDeleteAssignment(t *testing.T) {
 
 	t.Run("internal server error", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignmentID := 1
-		mockService.On("DeleteAssignment", assignmentID).Return(errors.New("db error")).Once()
+		mockService.On("DeleteAssignment", mock.Anything, assignmentID).Return(errors.New("db error")).Once()
 
 		router := http.NewServeMux()
 		router.HandleFunc("DELETE /assignments/{assignment_id}", handler.DeleteAssignment)
@@ -395,13 +398,13 @@ func TestDeleteAssignment(t *testing.T) {
 func TestGetAllAssignments(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
 		assignments := []models.Assignment{
 			{ID: 1, ChildID: 1, StartDate: time.Now()},
 			{ID: 2, ChildID: 2, StartDate: time.Now()},
 		}
-		mockService.On("GetAllAssignments").Return(assignments, nil).Once()
+		mockService.On("GetAllAssignments", mock.Anything, models.ListQuery{}).Return(assignments, nil).Once()
 
 		router := http.NewServeMux()
 		router.HandleFunc("GET /assignments", handler.GetAllAssignments)
@@ -421,9 +424,9 @@ func TestGetAllAssignments(t *testing.T) {
 
 	t.Run("service returns error", func(t *testing.T) {
 		mockService := new(mocks.AssignmentService)
-		handler := NewAssignmentHandler(mockService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
 
-		mockService.On("GetAllAssignments").Return(nil, errors.New("db error")).Once()
+		mockService.On("GetAllAssignments", mock.Anything, models.ListQuery{}).Return(nil, errors.New("db error")).Once()
 
 		router := http.NewServeMux()
 		router.HandleFunc("GET /assignments", handler.GetAllAssignments)
@@ -437,4 +440,46 @@ func TestGetAllAssignments(t *testing.T) {
 		assert.Contains(t, rr.Body.String(), "Internal server error")
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("filters, sorting and pagination", func(t *testing.T) {
+		mockService := new(mocks.AssignmentService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
+
+		expectedQuery := models.ListQuery{
+			Conditions: []models.Condition{
+				{Field: "child_id", Operator: models.OperatorEqual, Value: 4},
+				{Field: "teacher_id", Operator: models.OperatorEqual, Value: 3},
+				{Field: "assignment_type", Operator: models.OperatorEqual, Value: models.AssignmentTypePrimary},
+				{Field: "end_date", Operator: models.OperatorIsNull},
+			},
+			Sort:   []models.SortField{{Field: "start_date", Descending: true}, {Field: "id"}},
+			Limit:  10,
+			Offset: 20,
+		}
+		mockService.On("GetAllAssignments", mock.Anything, expectedQuery).Return([]models.Assignment{}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/assignments?child_id=4&teacher_id=3&type=primary&open=true&sort=-start_date,id&limit=10&offset=20", nil)
+		rr := httptest.NewRecorder()
+
+		handler.GetAllAssignments(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "[]\n", rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid list parameters", func(t *testing.T) {
+		mockService := new(mocks.AssignmentService)
+		handler := NewAssignmentHandler(mockService, clock.System{})
+
+		for _, params := range []string{"sort=observation_date", "limit=0", "limit=501", "offset=-1", "teacher_id=abc"} {
+			req := httptest.NewRequest(http.MethodGet, "/assignments?"+params, nil)
+			rr := httptest.NewRecorder()
+
+			handler.GetAllAssignments(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, params)
+		}
+		mockService.AssertNotCalled(t, "GetAllAssignments", mock.Anything, mock.Anything)
+	})
 }
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+)
+
+// attachmentEnvelope returns the envelope sent as JSON in the "envelope" form field of an upload encrypted by the
+// client, nil for plain uploads. It writes the error response and returns false if the field is no valid JSON.
+func attachmentEnvelope(writer http.ResponseWriter, request *http.Request) (*models.AttachmentEnvelope, bool) {
+	value := request.FormValue("envelope")
+	if value == "" {
+		return nil, true
+	}
+	var envelope models.AttachmentEnvelope
+	if err := json.Unmarshal([]byte(value), &envelope); err != nil {
+		middleware.GetLoggerWithReqID(request.Context()).WithError(err).Warn("Invalid attachment envelope")
+		http.Error(writer, "Invalid envelope, must be a JSON object", http.StatusBadRequest)
+		return nil, false
+	}
+	return &envelope, true
+}
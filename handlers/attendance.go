@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// AttendanceHandler handles the HTTP requests for the arrivals and departures of children and their check-in QR codes.
+type AttendanceHandler struct {
+	AttendanceService services.AttendanceService
+	Clock             clock.Clock
+}
+
+// NewAttendanceHandler creates a new AttendanceHandler.
+func NewAttendanceHandler(attendanceService services.AttendanceService, clock clock.Clock) *AttendanceHandler {
+	return &AttendanceHandler{AttendanceService: attendanceService, Clock: clock}
+}
+
+// CheckIn handles a scan of a check-in QR code at the door tablet. It answers 201 with the new record, or 200 with
+// the previous record if the scan repeated it.
+func (handler *AttendanceHandler) CheckIn(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for CheckIn handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var checkIn models.CheckIn
+	if err := json.NewDecoder(request.Body).Decode(&checkIn); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CheckIn")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	record, created, err := handler.AttendanceService.CheckIn(logger, request.Context(), &checkIn, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error during check-in")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if created {
+		writeCreatedHeader(writer, "/api/v1/attendance", record.ID)
+	} else {
+		writer.WriteHeader(http.StatusOK)
+	}
+	if err := json.NewEncoder(writer).Encode(record); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CheckIn")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RecordAttendance handles recording an arrival or departure of a child by a teacher.
+func (handler *AttendanceHandler) RecordAttendance(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for RecordAttendance handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	childID, ok := parsePathID(writer, request, "child_id", "RecordAttendance")
+	if !ok {
+		return
+	}
+
+	var record models.AttendanceRecord
+	if err := json.NewDecoder(request.Body).Decode(&record); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for RecordAttendance")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	record.ChildID = childID
+
+	created, err := handler.AttendanceService.RecordAttendance(logger, request.Context(), &record, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error recording attendance")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/attendance", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for RecordAttendance")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetChildAttendance handles listing the attendance of a child between the from and to query parameters, by
+// default today.
+func (handler *AttendanceHandler) GetChildAttendance(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetChildAttendance")
+	if !ok {
+		return
+	}
+
+	params := request.URL.Query()
+	to := models.Today(handler.Clock.Now())
+	if toStr := params.Get("to"); toStr != "" {
+		parsed, err := time.Parse(time.DateOnly, toStr)
+		if err != nil {
+			http.Error(writer, "Invalid to date, must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = models.DateOf(parsed)
+	}
+	from := to
+	if fromStr := params.Get("from"); fromStr != "" {
+		parsed, err := time.Parse(time.DateOnly, fromStr)
+		if err != nil {
+			http.Error(writer, "Invalid from date, must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		from = models.DateOf(parsed)
+	}
+
+	records, err := handler.AttendanceService.GetChildAttendance(logger, request.Context(), childID, from, to)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "from must not be after to", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching attendance of child")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(records); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetChildAttendance")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetDayAttendance handles listing the attendance of all children on the day of the date query parameter, by
+// default today.
+func (handler *AttendanceHandler) GetDayAttendance(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	day := models.Today(handler.Clock.Now())
+	if dateStr := request.URL.Query().Get("date"); dateStr != "" {
+		parsed, err := time.Parse(time.DateOnly, dateStr)
+		if err != nil {
+			http.Error(writer, "Invalid date, must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		day = models.DateOf(parsed)
+	}
+
+	records, err := handler.AttendanceService.GetDayAttendance(logger, request.Context(), day)
+	if err != nil {
+		logger.WithError(err).WithField("day", day).Error("Internal server error fetching attendance of day")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(records); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetDayAttendance")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAttendanceByID handles fetching an attendance record by ID.
+func (handler *AttendanceHandler) GetAttendanceByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	recordID, ok := parsePathID(writer, request, "record_id", "GetAttendanceByID")
+	if !ok {
+		return
+	}
+
+	record, err := handler.AttendanceService.GetAttendanceByID(logger, request.Context(), recordID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("record_id", recordID).Error("Internal server error fetching attendance record")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(record); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAttendanceByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteAttendance handles deleting a wrong attendance record.
+func (handler *AttendanceHandler) DeleteAttendance(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	recordID, ok := parsePathID(writer, request, "record_id", "DeleteAttendance")
+	if !ok {
+		return
+	}
+
+	if err := handler.AttendanceService.DeleteAttendance(logger, request.Context(), recordID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("record_id", recordID).Error("Internal server error deleting attendance record")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetCheckInQRCode handles downloading the check-in QR code of a child as PNG, e.g. to print it on a card.
+func (handler *AttendanceHandler) GetCheckInQRCode(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetCheckInQRCode")
+	if !ok {
+		return
+	}
+
+	png, err := handler.AttendanceService.GetCheckInQRCode(logger, request.Context(), childID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching check-in QR code")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "image/png")
+	writer.Header().Set("Content-Length", strconv.Itoa(len(png)))
+	if _, err := writer.Write(png); err != nil {
+		logger.WithError(err).Error("Failed to write response for GetCheckInQRCode")
+		return
+	}
+}
+
+// RevokeCheckInCode handles invalidating the check-in code of a child, e.g. after the card was lost.
+func (handler *AttendanceHandler) RevokeCheckInCode(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "RevokeCheckInCode")
+	if !ok {
+		return
+	}
+
+	if err := handler.AttendanceService.RevokeCheckInCode(logger, request.Context(), childID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Check-in code not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error revoking check-in code")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -23,6 +24,8 @@ type AudioRecordingHandler struct {
 	AudioAnalysisService      services.AudioAnalysisService
 	DocumentationEntryService services.DocumentationEntryService
 	ProcessService            services.ProcessService
+	UploadScanService         services.UploadScanService
+	ResumableUploadService    services.ResumableUploadService
 	Config                    *config.Config
 }
 
@@ -31,12 +34,16 @@ func NewAudioRecordingHandler(
 	audioAnalysisService services.AudioAnalysisService,
 	documentationEntryService services.DocumentationEntryService,
 	processService services.ProcessService,
+	uploadScanService services.UploadScanService,
+	resumableUploadService services.ResumableUploadService,
 	cfg *config.Config,
 ) *AudioRecordingHandler {
 	return &AudioRecordingHandler{
 		AudioAnalysisService:      audioAnalysisService,
 		DocumentationEntryService: documentationEntryService,
 		ProcessService:            processService,
+		UploadScanService:         uploadScanService,
+		ResumableUploadService:    resumableUploadService,
 		Config:                    cfg,
 	}
 }
@@ -75,20 +82,34 @@ func (handler *AudioRecordingHandler) UploadAudio(writer http.ResponseWriter, re
 	}
 	logger.Info("Successfully parsed multipart form")
 
-	// 2. Get the file, teacher_id, and timestamp from the form
+	// 2. Get the file, teacher_id, and timestamp from the form. Long recordings are sent as resumable uploads,
+	// the form then carries the upload_id instead of the file.
 	logger.Info("Retrieving file and form values")
-	file, fileHeader, err := request.FormFile("audio")
-	if err != nil {
-		logger.WithError(err).Error("Error retrieving audio file from form")
-		handler.writeBadRequestError(writer, "Error retrieving audio file: "+err.Error())
+	resumed, ok := resumedUpload(writer, request, handler.ResumableUploadService, models.UploadKindAudio)
+	if !ok {
 		return
 	}
-	defer func() {
-		err := file.Close()
+	var fileName, contentType string
+	var file io.Reader
+	if resumed != nil {
+		fileName, contentType = resumed.FileName, resumed.ContentType
+		file = bytes.NewReader(resumed.Content)
+	} else {
+		formFile, fileHeader, err := request.FormFile("audio")
 		if err != nil {
-			logger.WithError(err).Error("Failed to close uploaded audio file")
+			logger.WithError(err).Error("Error retrieving audio file from form")
+			handler.writeBadRequestError(writer, "Error retrieving audio file: "+err.Error())
+			return
 		}
-	}()
+		defer func() {
+			err := formFile.Close()
+			if err != nil {
+				logger.WithError(err).Error("Failed to close uploaded audio file")
+			}
+		}()
+		fileName, contentType = fileHeader.Filename, fileHeader.Header.Get("Content-Type")
+		file = formFile
+	}
 
 	teacherID := request.FormValue("teacher_id")
 	if teacherID == "" {
@@ -110,10 +131,9 @@ func (handler *AudioRecordingHandler) UploadAudio(writer http.ResponseWriter, re
 		handler.writeBadRequestError(writer, "Invalid timestamp format. Use RFC3339 (e.g., 2006-01-02T15:04:05Z07:00)")
 		return
 	}
-	logger.Infof("Received file: %s, teacher_id: %s, timestamp: %s", fileHeader.Filename, teacherID, timestampStr)
+	logger.Infof("Received file: %s, teacher_id: %s, timestamp: %s", fileName, teacherID, timestampStr)
 
 	// 3. Validate file type
-	contentType := fileHeader.Header.Get("Content-Type")
 	logger.Infof("Validating file type: %s", contentType)
 	if !handler.isAllowedFileType(contentType) {
 		logger.WithField("content_type", contentType).Warn("Disallowed file type uploaded")
@@ -137,9 +157,14 @@ func (handler *AudioRecordingHandler) UploadAudio(writer http.ResponseWriter, re
 		return
 	}
 	logger.Infof("Successfully read %d bytes from file", len(fileContent))
+	if !scanUpload(writer, request, handler.UploadScanService, models.UploadKindAudio, fileName, fileContent) {
+		return
+	}
+	// The recording is kept in memory for the analysis, the chunks are no longer needed.
+	releaseResumedUpload(request, handler.ResumableUploadService, resumed)
 
 	// Create a new process entry in the database that the client can poll
-	process, err := handler.ProcessService.Create("starting")
+	process, err := handler.ProcessService.Create(request.Context(), "starting")
 	var processId int
 	if err != nil {
 		logger.WithError(err).Error("Failed to create process entry in database for polling")
@@ -166,31 +191,31 @@ func (handler *AudioRecordingHandler) UploadAudio(writer http.ResponseWriter, re
 		analysisResult, err := handler.AudioAnalysisService.ProcessAudio(ctx, logger, processId, fileContent)
 		if err != nil {
 			logger.WithError(err).Error("Failed to analyze audio")
-			handler.UpdateProcessStatus(logger, processId, "failed")
+			handler.UpdateProcessStatus(logger, ctx, processId, "failed")
 			return
 		}
 		logger.WithField("analysis_result", analysisResult).Debug("Audio analysis result")
 
 		// 6. Persist the analysis result as a documentation entry
-		handler.UpdateProcessStatus(logger, processId, "creating documentation entry")
+		handler.UpdateProcessStatus(logger, ctx, processId, "creating documentation entry")
 		logger.Info("Persisting analysis result")
 		teacherIDInt, err := strconv.Atoi(teacherID)
 		if err != nil {
 			logger.WithError(err).Error("Invalid teacher ID")
-			handler.UpdateProcessStatus(logger, processId, "failed")
+			handler.UpdateProcessStatus(logger, ctx, processId, "failed")
 			return
 		}
 
 		if len(analysisResult) == 0 {
 			logger.Warn("No analysis results found")
-			handler.UpdateProcessStatus(logger, processId, "failed")
+			handler.UpdateProcessStatus(logger, ctx, processId, "failed")
 			return
 		}
 
 		for _, childAnalysis := range analysisResult {
 			docEntry := models.DocumentationEntry{
 				TeacherID:              teacherIDInt,
-				ObservationDate:        timestamp,
+				ObservationDate:        models.DateOf(timestamp),
 				ObservationDescription: childAnalysis.TranscriptionSummary,
 				CategoryID:             childAnalysis.Category.AnalysisCategoryID,
 				ChildID:                childAnalysis.ChildID,
@@ -200,21 +225,21 @@ func (handler *AudioRecordingHandler) UploadAudio(writer http.ResponseWriter, re
 			_, err := handler.DocumentationEntryService.CreateDocumentationEntry(logger, ctx, &docEntry)
 			if err != nil {
 				logger.WithError(err).Error("Failed to create documentation entry from audio analysis")
-				handler.UpdateProcessStatus(logger, processId, "failed")
+				handler.UpdateProcessStatus(logger, ctx, processId, "failed")
 				return
 			}
 		}
 		logger.Info("Finished asynchronous audio processing")
-		handler.UpdateProcessStatus(logger, processId, "completed")
+		handler.UpdateProcessStatus(logger, ctx, processId, "completed")
 	}(processId)
 
 	logger.Info("Finished audio upload process (handler)")
 }
 
 // Checks if a process entry in the database was created and updates its status.
-func (handler *AudioRecordingHandler) UpdateProcessStatus(logger *logrus.Entry, processId int, status string) {
+func (handler *AudioRecordingHandler) UpdateProcessStatus(logger *logrus.Entry, ctx context.Context, processId int, status string) {
 	if processId != -1 {
-		if err := handler.ProcessService.Update(&models.Process{ProcessId: processId, Status: status}); err != nil {
+		if err := handler.ProcessService.Update(ctx, &models.Process{ProcessId: processId, Status: status}); err != nil {
 			logger.WithError(err).Error("Failed to update process status")
 		}
 	}
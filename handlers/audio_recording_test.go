@@ -15,12 +15,16 @@ import (
 	"kitadoc-backend/handlers"
 	"kitadoc-backend/handlers/mocks"
 	"kitadoc-backend/models"
+	"kitadoc-backend/services"
 	services_mocks "kitadoc-backend/services/mocks"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// noUploadScan accepts every upload without quarantining it.
+var noUploadScan = services.NewUploadScanService(services.NoopVirusScanner{}, nil, nil, nil)
+
 func TestAudioRecordingHandler_UploadAudio(t *testing.T) {
 	ctx := context.Background()
 
@@ -28,7 +32,7 @@ func TestAudioRecordingHandler_UploadAudio(t *testing.T) {
 		mockAudioAnalysisService := &services_mocks.MockAudioAnalysisService{}
 		mockDocEntryService := &mocks.MockDocumentationEntryService{}
 		mockProcessService := &mocks.MockProcessService{}
-		h := handlers.NewAudioRecordingHandler(mockAudioAnalysisService, mockDocEntryService, mockProcessService, &config.Config{
+		h := handlers.NewAudioRecordingHandler(mockAudioAnalysisService, mockDocEntryService, mockProcessService, noUploadScan, nil, &config.Config{
 			FileStorage: struct {
 				MaxSizeMB    int      `mapstructure:"max_size_mb"`
 				AllowedTypes []string `mapstructure:"allowed_types"`
@@ -74,11 +78,11 @@ func TestAudioRecordingHandler_UploadAudio(t *testing.T) {
 		done := make(chan bool, 1)
 
 		processID := 42
-		mockProcessService.On("Create", "starting").Return(&models.Process{ProcessId: processID, Status: "starting"}, nil).Once()
+		mockProcessService.On("Create", mock.Anything, "starting").Return(&models.Process{ProcessId: processID, Status: "starting"}, nil).Once()
 
 		mockAudioAnalysisService.On("ProcessAudio", mock.Anything, mock.AnythingOfType("*logrus.Entry"), processID, []byte("dummy audio data")).Return(mockResponse, nil).Once()
 
-		mockProcessService.On("Update", mock.MatchedBy(func(p *models.Process) bool {
+		mockProcessService.On("Update", mock.Anything, mock.MatchedBy(func(p *models.Process) bool {
 			return p.ProcessId == processID && p.Status == "creating documentation entry"
 		})).Return(nil).Once()
 
@@ -86,7 +90,7 @@ func TestAudioRecordingHandler_UploadAudio(t *testing.T) {
 			done <- true
 		}).Once()
 
-		mockProcessService.On("Update", mock.MatchedBy(func(p *models.Process) bool {
+		mockProcessService.On("Update", mock.Anything, mock.MatchedBy(func(p *models.Process) bool {
 			return p.ProcessId == processID && p.Status == "completed"
 		})).Return(nil).Once()
 
@@ -115,7 +119,7 @@ func TestAudioRecordingHandler_UploadAudio(t *testing.T) {
 		mockAudioAnalysisService := &services_mocks.MockAudioAnalysisService{}
 		mockDocEntryService := &mocks.MockDocumentationEntryService{}
 		mockProcessService := &mocks.MockProcessService{}
-		h := handlers.NewAudioRecordingHandler(mockAudioAnalysisService, mockDocEntryService, mockProcessService, &config.Config{
+		h := handlers.NewAudioRecordingHandler(mockAudioAnalysisService, mockDocEntryService, mockProcessService, noUploadScan, nil, &config.Config{
 			FileStorage: struct {
 				MaxSizeMB    int      `mapstructure:"max_size_mb"`
 				AllowedTypes []string `mapstructure:"allowed_types"`
@@ -155,7 +159,7 @@ func TestAudioRecordingHandler_UploadAudio(t *testing.T) {
 		mockAudioAnalysisService := &services_mocks.MockAudioAnalysisService{}
 		mockDocEntryService := &mocks.MockDocumentationEntryService{}
 		mockProcessService := &mocks.MockProcessService{}
-		h := handlers.NewAudioRecordingHandler(mockAudioAnalysisService, mockDocEntryService, mockProcessService, &config.Config{
+		h := handlers.NewAudioRecordingHandler(mockAudioAnalysisService, mockDocEntryService, mockProcessService, noUploadScan, nil, &config.Config{
 			FileStorage: struct {
 				MaxSizeMB    int      `mapstructure:"max_size_mb"`
 				AllowedTypes []string `mapstructure:"allowed_types"`
@@ -185,11 +189,11 @@ func TestAudioRecordingHandler_UploadAudio(t *testing.T) {
 
 		done := make(chan bool, 1)
 		processID := 124
-		mockProcessService.On("Create", "starting").Return(&models.Process{ProcessId: processID, Status: "starting"}, nil).Once()
+		mockProcessService.On("Create", mock.Anything, "starting").Return(&models.Process{ProcessId: processID, Status: "starting"}, nil).Once()
 
 		mockAudioAnalysisService.On("ProcessAudio", mock.Anything, mock.AnythingOfType("*logrus.Entry"), processID, []byte("dummy audio data")).Return([]models.ChildAnalysisObject{}, assert.AnError).Once()
 
-		mockProcessService.On("Update", mock.MatchedBy(func(p *models.Process) bool {
+		mockProcessService.On("Update", mock.Anything, mock.MatchedBy(func(p *models.Process) bool {
 			return p.ProcessId == processID && p.Status == "failed"
 		})).Return(nil).Run(func(args mock.Arguments) {
 			done <- true
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// AuditLogHandler handles audit log HTTP requests.
+type AuditLogHandler struct {
+	AuditLogService services.AuditLogService
+}
+
+// NewAuditLogHandler creates a new AuditLogHandler.
+func NewAuditLogHandler(auditLogService services.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{AuditLogService: auditLogService}
+}
+
+// GetAuditLog handles fetching the audit log.
+// The optional query parameters entity_type and entity_id narrow it down to the trail of a single entity,
+// actor_user_id narrows it down to what a single user did.
+func (handler *AuditLogHandler) GetAuditLog(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	var entries []models.AuditLogEntry
+	var err error
+	entityType := request.URL.Query().Get("entity_type")
+	entityIDStr := request.URL.Query().Get("entity_id")
+	actorUserIDStr := request.URL.Query().Get("actor_user_id")
+	switch {
+	case actorUserIDStr != "":
+		actorUserID, convErr := strconv.Atoi(actorUserIDStr)
+		if convErr != nil || entityType != "" || entityIDStr != "" {
+			logger.WithField("actor_user_id_str", actorUserIDStr).Warn("Invalid actor filter for GetAuditLog")
+			http.Error(writer, "actor_user_id must be numeric and cannot be combined with an entity filter", http.StatusBadRequest)
+			return
+		}
+		entries, err = handler.AuditLogService.GetActorAuditLog(logger, request.Context(), actorUserID)
+	case entityType != "" || entityIDStr != "":
+		entityID, convErr := strconv.Atoi(entityIDStr)
+		if entityType == "" || convErr != nil {
+			logger.WithField("entity_type", entityType).WithField("entity_id_str", entityIDStr).Warn("Invalid entity filter for GetAuditLog")
+			http.Error(writer, "entity_type and a numeric entity_id are required together", http.StatusBadRequest)
+			return
+		}
+		entries, err = handler.AuditLogService.GetAuditTrail(logger, request.Context(), entityType, entityID)
+	default:
+		entries, err = handler.AuditLogService.GetAuditLog(logger, request.Context())
+	}
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching audit log")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if entries == nil {
+		entries = []models.AuditLogEntry{}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(entries); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAuditLog")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
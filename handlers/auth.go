@@ -3,27 +3,41 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
-	"time"
 
+	"kitadoc-backend/config"
 	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
 )
 
 // AuthHandler handles authentication-related HTTP requests.
 type AuthHandler struct {
 	UserService services.UserService
+	Config      *config.Config
 }
 
 // NewAuthHandler creates a new AuthHandler.
-func NewAuthHandler(userService services.UserService) *AuthHandler {
-	return &AuthHandler{UserService: userService}
+func NewAuthHandler(userService services.UserService, cfg *config.Config) *AuthHandler {
+	return &AuthHandler{UserService: userService, Config: cfg}
 }
 
+// sessionCookie is the session of a login that asks for a cookie session instead of a bearer token.
+const sessionCookie = "cookie"
+
 // LoginRequest represents the request body for user login.
 type LoginRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// Session is "cookie" for a cookie session, empty for a bearer token.
+	Session string `json:"session,omitempty"`
+}
+
+// RefreshRequest represents the request body for exchanging a refresh token. Cookie sessions send the refresh
+// token in the refresh cookie instead.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 type RegisterUserRequest struct {
@@ -32,13 +46,20 @@ type RegisterUserRequest struct {
 	Role     string `json:"role"` // e.g., "teacher" or "admin"
 }
 
+// ReauthenticateRequest represents the request body for confirming the password before a bulk export.
+type ReauthenticateRequest struct {
+	Password string `json:"password"`
+}
+
 type ChangePasswordRequest struct {
 	UserID      int    `json:"user_id"`
 	OldPassword string `json:"old_password"`
 	NewPassword string `json:"new_password"`
 }
 
-// Login handles user login.
+// Login handles user login. The response carries a short-lived access token and the refresh token of the session.
+// A login asking for a cookie session gets the tokens as HttpOnly cookies and only the CSRF token in the response,
+// which unsafe requests send in the X-CSRF-Token header.
 func (authHandler *AuthHandler) Login(writer http.ResponseWriter, request *http.Request) {
 	logger := middleware.GetLoggerWithReqID(request.Context())
 	var req LoginRequest
@@ -47,8 +68,13 @@ func (authHandler *AuthHandler) Login(writer http.ResponseWriter, request *http.
 		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
+	if req.Session != "" && (req.Session != sessionCookie || !authHandler.Config.Session.Cookies) {
+		logger.WithField("session", req.Session).Warn("Unsupported session requested for Login")
+		http.Error(writer, "Unsupported session, cookie sessions must be enabled", http.StatusBadRequest)
+		return
+	}
 
-	token, err := authHandler.UserService.LoginUser(logger, req.Username, req.Password)
+	tokens, err := authHandler.UserService.LoginUser(logger, request.Context(), req.Username, req.Password)
 	if err != nil {
 		if err == services.ErrInvalidCredentials {
 			logger.WithField("username", req.Username).Warn("Invalid credentials during login attempt")
@@ -60,19 +86,77 @@ func (authHandler *AuthHandler) Login(writer http.ResponseWriter, request *http.
 		return
 	}
 
-	if err := json.NewEncoder(writer).Encode(map[string]string{"token": token}); err != nil {
-		logger.WithError(err).Error("Failed to encode login response")
+	authHandler.writeTokens(logger, writer, tokens, req.Session == sessionCookie)
+}
+
+// Refresh handles exchanging the refresh token of a session for new tokens, before or after the access token
+// expired. The refresh token is replaced and cannot be used again. Cookie sessions get the new tokens as cookies.
+func (authHandler *AuthHandler) Refresh(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var req RefreshRequest
+	fromCookie := false
+	if cookie, err := request.Cookie(middleware.RefreshCookieName); authHandler.Config.Session.Cookies && err == nil && cookie.Value != "" {
+		req.RefreshToken, fromCookie = cookie.Value, true
+	} else if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for Refresh")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if req.RefreshToken == "" {
+		http.Error(writer, "A refresh token is required", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := authHandler.UserService.RefreshSession(logger, request.Context(), req.RefreshToken)
+	if err != nil {
+		if err == services.ErrAuthenticationFailed {
+			if fromCookie {
+				middleware.ClearSessionCookies(writer, authHandler.Config)
+			}
+			http.Error(writer, "Invalid or expired refresh token", http.StatusUnauthorized)
+			return
+		}
+		logger.WithError(err).Error("Internal server error during refresh")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	authHandler.writeTokens(logger, writer, tokens, fromCookie)
+}
+
+// writeTokens responds with the tokens of a login or a refresh, a cookie session gets them as cookies.
+func (authHandler *AuthHandler) writeTokens(logger *logrus.Entry, writer http.ResponseWriter, tokens *models.AuthTokens, cookies bool) {
+	var response any = tokens
+	if cookies {
+		response = map[string]any{
+			"csrf_token":               middleware.SetSessionCookies(writer, authHandler.Config, tokens.AccessToken, tokens.RefreshToken),
+			"expires_at":               tokens.ExpiresAt,
+			"refresh_token_expires_at": tokens.RefreshTokenExpiresAt,
+		}
+	}
+	writer.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(writer).Encode(response); err != nil {
+		logger.WithError(err).Error("Failed to encode token response")
 		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
-// Logout handles user logout (token invalidation is typically client-side).
+// Logout handles user logout. The session of the access token is revoked, so that neither the access token nor
+// the refresh token are accepted anymore. The cookies of a cookie session are cleared.
 func (authHandler *AuthHandler) Logout(writer http.ResponseWriter, request *http.Request) {
 	logger := middleware.GetLoggerWithReqID(request.Context())
-	// For JWT, logout is typically handled client-side by discarding the token.
-	// If server-side invalidation is needed, a token blacklist mechanism would be implemented.
-	logger.Info("User logged out (client-side token discard)")
+	if sessionID, ok := request.Context().Value(middleware.ContextKeySessionID).(int); ok {
+		if err := authHandler.UserService.EndSession(logger, request.Context(), sessionID); err != nil {
+			logger.WithError(err).WithField("session_id", sessionID).Error("Internal server error during logout")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+	if authHandler.Config.Session.Cookies {
+		middleware.ClearSessionCookies(writer, authHandler.Config)
+	}
+	logger.Info("User logged out")
 	writer.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Logged out successfully"}); err != nil {
 		logger.WithError(err).Error("Failed to encode logout response")
@@ -109,8 +193,14 @@ func (authHandler *AuthHandler) RegisterUser(writer http.ResponseWriter, request
 		return
 	}
 
-	createdUser, err := authHandler.UserService.RegisterUser(logger, user.Username, user.Password, user.Role)
+	createdUser, err := authHandler.UserService.RegisterUser(logger, request.Context(), user.Username, user.Password, user.Role)
 	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		if writeDomainError(writer, err) {
+			return
+		}
 		if err == services.ErrAlreadyExists {
 			logger.WithField("username", user.Username).Warn("Registration attempt for existing username")
 			http.Error(writer, "User with this username already exists", http.StatusConflict)
@@ -159,10 +249,12 @@ func (authHandler *AuthHandler) UpdateUser(writer http.ResponseWriter, request *
 	// or if an admin is performing the update.
 	// For now, we'll assume the user is updating their own profile.
 	updatedUser.ID = userFromContext.ID
-	updatedUser.UpdatedAt = time.Now()
 
-	err := authHandler.UserService.UpdateUser(logger, &updatedUser)
+	err := authHandler.UserService.UpdateUser(logger, request.Context(), &updatedUser)
 	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
 		if err == services.ErrNotFound {
 			logger.WithField("user_id", updatedUser.ID).Warn("User not found for update")
 			http.Error(writer, "User not found", http.StatusNotFound)
@@ -201,7 +293,7 @@ func (authHandler *AuthHandler) DeleteUser(writer http.ResponseWriter, request *
 		return
 	}
 
-	err := authHandler.UserService.DeleteUser(logger, userFromContext.ID)
+	err := authHandler.UserService.DeleteUser(logger, request.Context(), userFromContext.ID)
 	if err != nil {
 		if err == services.ErrNotFound {
 			logger.WithField("user_id", userFromContext.ID).Warn("User not found for deletion")
@@ -226,7 +318,7 @@ func (authHandler *AuthHandler) GetAllUsers(writer http.ResponseWriter, request
 	logger := middleware.GetLoggerWithReqID(request.Context())
 	logger.Info("Fetching all users")
 
-	users, err := authHandler.UserService.GetAllUsers(logger)
+	users, err := authHandler.UserService.GetAllUsers(logger, request.Context())
 	if err != nil {
 		logger.WithError(err).Error("Internal server error during getting all users")
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
@@ -256,7 +348,7 @@ func (authHandler *AuthHandler) ChangePassword(writer http.ResponseWriter, reque
 		return
 	}
 
-	err := authHandler.UserService.ChangePassword(logger, user, req.UserID, req.OldPassword, req.NewPassword)
+	err := authHandler.UserService.ChangePassword(logger, request.Context(), user, req.UserID, req.OldPassword, req.NewPassword)
 	if err != nil {
 		if err == services.ErrInvalidCredentials {
 			logger.WithField("user_id", req.UserID).Warn("Invalid credentials provided for password change")
@@ -280,3 +372,39 @@ func (authHandler *AuthHandler) ChangePassword(writer http.ResponseWriter, reque
 		return
 	}
 }
+
+// Reauthenticate handles confirming the password of the current user, the returned token is sent in the
+// X-Reauthentication-Token header of bulk exports.
+func (authHandler *AuthHandler) Reauthenticate(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for Reauthenticate handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req ReauthenticateRequest
+	if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for Reauthenticate")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	token, expiresAt, err := authHandler.UserService.Reauthenticate(logger, request.Context(), user.ID, req.Password)
+	if err != nil {
+		if err == services.ErrInvalidCredentials {
+			http.Error(writer, "Invalid password", http.StatusUnauthorized)
+			return
+		}
+		logger.WithError(err).Error("Internal server error during re-authentication")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(map[string]any{"reauthentication_token": token, "expires_at": expiresAt}); err != nil {
+		logger.WithError(err).Error("Failed to encode re-authentication response")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
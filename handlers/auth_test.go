@@ -9,9 +9,11 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"kitadoc-backend/config"
 	"kitadoc-backend/handlers/mocks"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/middleware"
@@ -31,10 +33,10 @@ func TestMain(m *testing.M) {
 func TestLogin(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		reqBody := LoginRequest{Username: "testuser", Password: "password123"}
-		mockService.On("LoginUser", mock.Anything, reqBody.Username, reqBody.Password).Return("mock_token", nil).Once()
+		mockService.On("LoginUser", mock.Anything, mock.Anything, reqBody.Username, reqBody.Password).Return(&models.AuthTokens{AccessToken: "mock_token", RefreshToken: "mock_refresh_token"}, nil).Once()
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
@@ -46,12 +48,14 @@ func TestLogin(t *testing.T) {
 		var response map[string]string
 		json.NewDecoder(rr.Body).Decode(&response) //nolint:errcheck
 		assert.Equal(t, "mock_token", response["token"])
+		assert.Equal(t, "mock_refresh_token", response["refresh_token"])
+		assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
 		mockService.AssertExpectations(t)
 	})
 
 	t.Run("invalid request payload", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBuffer([]byte("invalid json")))
 		rr := httptest.NewRecorder()
@@ -60,15 +64,15 @@ func TestLogin(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 		assert.Contains(t, rr.Body.String(), "Invalid request payload")
-		mockService.AssertNotCalled(t, "LoginUser", mock.Anything, mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "LoginUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("invalid credentials", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		reqBody := LoginRequest{Username: "testuser", Password: "wrongpassword"}
-		mockService.On("LoginUser", mock.Anything, reqBody.Username, reqBody.Password).Return("", services.ErrInvalidCredentials).Once()
+		mockService.On("LoginUser", mock.Anything, mock.Anything, reqBody.Username, reqBody.Password).Return(nil, services.ErrInvalidCredentials).Once()
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
@@ -83,10 +87,10 @@ func TestLogin(t *testing.T) {
 
 	t.Run("internal server error", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		reqBody := LoginRequest{Username: "testuser", Password: "password123"}
-		mockService.On("LoginUser", mock.Anything, reqBody.Username, reqBody.Password).Return("", errors.New("db error")).Once()
+		mockService.On("LoginUser", mock.Anything, mock.Anything, reqBody.Username, reqBody.Password).Return(nil, errors.New("db error")).Once()
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
@@ -100,26 +104,117 @@ func TestLogin(t *testing.T) {
 	})
 }
 
+func TestRefresh(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(mocks.UserService)
+		handler := NewAuthHandler(mockService, &config.Config{})
+		mockService.On("RefreshSession", mock.Anything, mock.Anything, "old_refresh_token").
+			Return(&models.AuthTokens{AccessToken: "new_token", RefreshToken: "new_refresh_token"}, nil).Once()
+
+		body, _ := json.Marshal(RefreshRequest{RefreshToken: "old_refresh_token"})
+		req := httptest.NewRequest(http.MethodPost, "/refresh", bytes.NewBuffer(body))
+		rr := httptest.NewRecorder()
+
+		handler.Refresh(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response map[string]string
+		json.NewDecoder(rr.Body).Decode(&response) //nolint:errcheck
+		assert.Equal(t, "new_token", response["token"])
+		assert.Equal(t, "new_refresh_token", response["refresh_token"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("cookie session", func(t *testing.T) {
+		mockService := new(mocks.UserService)
+		cfg := &config.Config{}
+		cfg.Session.Cookies = true
+		handler := NewAuthHandler(mockService, cfg)
+		mockService.On("RefreshSession", mock.Anything, mock.Anything, "old_refresh_token").
+			Return(&models.AuthTokens{AccessToken: "new_token", RefreshToken: "new_refresh_token"}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/refresh", nil)
+		req.AddCookie(&http.Cookie{Name: middleware.RefreshCookieName, Value: "old_refresh_token"})
+		rr := httptest.NewRecorder()
+
+		handler.Refresh(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response map[string]any
+		json.NewDecoder(rr.Body).Decode(&response) //nolint:errcheck
+		assert.NotEmpty(t, response["csrf_token"])
+		assert.NotContains(t, response, "token", "cookie sessions get the tokens only as cookies")
+		assert.Len(t, rr.Result().Cookies(), 3)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("missing refresh token", func(t *testing.T) {
+		mockService := new(mocks.UserService)
+		handler := NewAuthHandler(mockService, &config.Config{})
+
+		req := httptest.NewRequest(http.MethodPost, "/refresh", bytes.NewBufferString(`{}`))
+		rr := httptest.NewRecorder()
+
+		handler.Refresh(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockService.AssertNotCalled(t, "RefreshSession", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid refresh token", func(t *testing.T) {
+		mockService := new(mocks.UserService)
+		handler := NewAuthHandler(mockService, &config.Config{})
+		mockService.On("RefreshSession", mock.Anything, mock.Anything, "used_refresh_token").Return(nil, services.ErrAuthenticationFailed).Once()
+
+		body, _ := json.Marshal(RefreshRequest{RefreshToken: "used_refresh_token"})
+		req := httptest.NewRequest(http.MethodPost, "/refresh", bytes.NewBuffer(body))
+		rr := httptest.NewRecorder()
+
+		handler.Refresh(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Invalid or expired refresh token")
+		mockService.AssertExpectations(t)
+	})
+}
+
 func TestLogout(t *testing.T) {
-	mockService := new(mocks.UserService)
-	handler := NewAuthHandler(mockService)
+	t.Run("ends the session", func(t *testing.T) {
+		mockService := new(mocks.UserService)
+		handler := NewAuthHandler(mockService, &config.Config{})
+		mockService.On("EndSession", mock.Anything, mock.Anything, 5).Return(nil).Once()
 
-	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
-	rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeySessionID, 5))
+		rr := httptest.NewRecorder()
 
-	handler.Logout(rr, req)
+		handler.Logout(rr, req)
 
-	assert.Equal(t, http.StatusOK, rr.Code)
-	var response map[string]string
-	json.NewDecoder(rr.Body).Decode(&response) //nolint:errcheck
-	assert.Equal(t, "Logged out successfully", response["message"])
-	mockService.AssertNotCalled(t, "LoginUser", mock.Anything, mock.Anything, mock.Anything) // Ensure no service calls
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var response map[string]string
+		json.NewDecoder(rr.Body).Decode(&response) //nolint:errcheck
+		assert.Equal(t, "Logged out successfully", response["message"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("tokens without a session", func(t *testing.T) {
+		mockService := new(mocks.UserService)
+		handler := NewAuthHandler(mockService, &config.Config{})
+
+		req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+		rr := httptest.NewRecorder()
+
+		handler.Logout(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockService.AssertNotCalled(t, "EndSession", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestGetMe(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		user := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, user)
@@ -138,7 +233,7 @@ func TestGetMe(t *testing.T) {
 
 	t.Run("user not found in context", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		req := httptest.NewRequest(http.MethodGet, "/me", nil) // No user in context
 		rr := httptest.NewRecorder()
@@ -153,7 +248,7 @@ func TestGetMe(t *testing.T) {
 func TestRegisterUser(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userRequest := RegisterUserRequest{Username: "newuser", Password: "password123", Role: "teacher"}
 		expectedUser := models.User{
@@ -161,7 +256,7 @@ func TestRegisterUser(t *testing.T) {
 			PasswordHash: "hashedpassword", // This would be the hashed password in a real scenario
 			Role:         userRequest.Role,
 		}
-		mockService.On("RegisterUser", mock.Anything, userRequest.Username, userRequest.Password, userRequest.Role).Return(&expectedUser, nil).Once()
+		mockService.On("RegisterUser", mock.Anything, mock.Anything, userRequest.Username, userRequest.Password, userRequest.Role).Return(&expectedUser, nil).Once()
 
 		body, _ := json.Marshal(userRequest)
 		req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
@@ -178,7 +273,7 @@ func TestRegisterUser(t *testing.T) {
 
 	t.Run("invalid request payload", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer([]byte("invalid json")))
 		rr := httptest.NewRecorder()
@@ -187,15 +282,15 @@ func TestRegisterUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 		assert.Contains(t, rr.Body.String(), "Invalid request payload")
-		mockService.AssertNotCalled(t, "RegisterUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "RegisterUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("user already exists", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userRequest := RegisterUserRequest{Username: "existinguser", Password: "password123", Role: "teacher"}
-		mockService.On("RegisterUser", mock.Anything, userRequest.Username, userRequest.Password, userRequest.Role).Return(nil, services.ErrAlreadyExists).Once()
+		mockService.On("RegisterUser", mock.Anything, mock.Anything, userRequest.Username, userRequest.Password, userRequest.Role).Return(nil, services.ErrAlreadyExists).Once()
 
 		body, _ := json.Marshal(userRequest)
 		req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
@@ -210,10 +305,10 @@ func TestRegisterUser(t *testing.T) {
 
 	t.Run("invalid user data provided", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userRequest := RegisterUserRequest{Username: "invalid", Password: "", Role: "teacher"}
-		mockService.On("RegisterUser", mock.Anything, userRequest.Username, userRequest.Password, userRequest.Role).Return(nil, services.ErrInvalidInput).Once()
+		mockService.On("RegisterUser", mock.Anything, mock.Anything, userRequest.Username, userRequest.Password, userRequest.Role).Return(nil, services.ErrInvalidInput).Once()
 
 		body, _ := json.Marshal(userRequest)
 		req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
@@ -228,10 +323,10 @@ func TestRegisterUser(t *testing.T) {
 
 	t.Run("internal server error", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userRequest := RegisterUserRequest{Username: "invalid", Password: "", Role: "teacher"}
-		mockService.On("RegisterUser", mock.Anything, userRequest.Username, userRequest.Password, userRequest.Role).Return(nil, errors.New("db error")).Once()
+		mockService.On("RegisterUser", mock.Anything, mock.Anything, userRequest.Username, userRequest.Password, userRequest.Role).Return(nil, errors.New("db error")).Once()
 
 		body, _ := json.Marshal(userRequest)
 		req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
@@ -243,16 +338,34 @@ func TestRegisterUser(t *testing.T) {
 		assert.Contains(t, rr.Body.String(), "Internal server error")
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("open registration disabled", func(t *testing.T) {
+		mockService := new(mocks.UserService)
+		handler := NewAuthHandler(mockService, &config.Config{})
+
+		userRequest := RegisterUserRequest{Username: "newuser", Password: "password123", Role: "admin"}
+		mockService.On("RegisterUser", mock.Anything, mock.Anything, userRequest.Username, userRequest.Password, userRequest.Role).Return(nil, services.ErrRegistrationClosed).Once()
+
+		body, _ := json.Marshal(userRequest)
+		req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
+		rr := httptest.NewRecorder()
+
+		handler.RegisterUser(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"code":"REGISTRATION_CLOSED"`)
+		mockService.AssertExpectations(t)
+	})
 }
 
 func TestUpdateUser(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userInContext := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
 		updatedUser := models.User{ID: 1, Username: "updateduser", Role: "teacher"}
-		mockService.On("UpdateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil).Once()
+		mockService.On("UpdateUser", mock.Anything, mock.Anything, mock.AnythingOfType("*models.User")).Return(nil).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, userInContext)
 		body, _ := json.Marshal(updatedUser)
@@ -268,7 +381,7 @@ func TestUpdateUser(t *testing.T) {
 
 	t.Run("user not found in context", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		updatedUser := models.User{ID: 1, Username: "updateduser", Role: "teacher"}
 		body, _ := json.Marshal(updatedUser)
@@ -279,12 +392,12 @@ func TestUpdateUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rr.Code)
 		assert.Contains(t, rr.Body.String(), "User not found in context")
-		mockService.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("invalid request payload", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userInContext := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, userInContext)
@@ -295,16 +408,16 @@ func TestUpdateUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 		assert.Contains(t, rr.Body.String(), "Invalid request payload")
-		mockService.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("user not found in service", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userInContext := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
 		updatedUser := models.User{ID: 1, Username: "updateduser", Role: "teacher"}
-		mockService.On("UpdateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(services.ErrNotFound).Once()
+		mockService.On("UpdateUser", mock.Anything, mock.Anything, mock.AnythingOfType("*models.User")).Return(services.ErrNotFound).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, userInContext)
 		body, _ := json.Marshal(updatedUser)
@@ -320,11 +433,11 @@ func TestUpdateUser(t *testing.T) {
 
 	t.Run("invalid user data provided", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userInContext := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
 		updatedUser := models.User{ID: 1, Username: "invalid", Role: "invalid_role"} // Invalid role
-		mockService.On("UpdateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(services.ErrInvalidInput).Once()
+		mockService.On("UpdateUser", mock.Anything, mock.Anything, mock.AnythingOfType("*models.User")).Return(services.ErrInvalidInput).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, userInContext)
 		body, _ := json.Marshal(updatedUser)
@@ -340,11 +453,11 @@ func TestUpdateUser(t *testing.T) {
 
 	t.Run("internal server error", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userInContext := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
 		updatedUser := models.User{ID: 1, Username: "updateduser", Role: "teacher"}
-		mockService.On("UpdateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(errors.New("db error")).Once()
+		mockService.On("UpdateUser", mock.Anything, mock.Anything, mock.AnythingOfType("*models.User")).Return(errors.New("db error")).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, userInContext)
 		body, _ := json.Marshal(updatedUser)
@@ -362,10 +475,10 @@ func TestUpdateUser(t *testing.T) {
 func TestDeleteUser(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userInContext := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
-		mockService.On("DeleteUser", mock.Anything, userInContext.ID).Return(nil).Once()
+		mockService.On("DeleteUser", mock.Anything, mock.Anything, userInContext.ID).Return(nil).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, userInContext)
 		req := httptest.NewRequest(http.MethodDelete, "/users/1", nil).WithContext(ctx)
@@ -380,7 +493,7 @@ func TestDeleteUser(t *testing.T) {
 
 	t.Run("user not found in context", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		req := httptest.NewRequest(http.MethodDelete, "/users/1", nil) // No user in context
 		rr := httptest.NewRecorder()
@@ -389,15 +502,15 @@ func TestDeleteUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rr.Code)
 		assert.Contains(t, rr.Body.String(), "User not found in context")
-		mockService.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("user not found in service", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userInContext := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
-		mockService.On("DeleteUser", mock.Anything, userInContext.ID).Return(services.ErrNotFound).Once()
+		mockService.On("DeleteUser", mock.Anything, mock.Anything, userInContext.ID).Return(services.ErrNotFound).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, userInContext)
 		req := httptest.NewRequest(http.MethodDelete, "/users/1", nil).WithContext(ctx)
@@ -412,10 +525,10 @@ func TestDeleteUser(t *testing.T) {
 
 	t.Run("internal server error", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		userInContext := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
-		mockService.On("DeleteUser", mock.Anything, userInContext.ID).Return(errors.New("db error")).Once()
+		mockService.On("DeleteUser", mock.Anything, mock.Anything, userInContext.ID).Return(errors.New("db error")).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, userInContext)
 		req := httptest.NewRequest(http.MethodDelete, "/users/1", nil).WithContext(ctx)
@@ -432,13 +545,13 @@ func TestDeleteUser(t *testing.T) {
 func TestGetAllUsers(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		expectedUsers := []*models.User{
 			{ID: 1, Username: "user1", Role: "teacher"},
 			{ID: 2, Username: "user2", Role: "admin"},
 		}
-		mockService.On("GetAllUsers", mock.Anything).Return(expectedUsers, nil).Once()
+		mockService.On("GetAllUsers", mock.Anything, mock.Anything).Return(expectedUsers, nil).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/users", nil)
 		rr := httptest.NewRecorder()
@@ -454,9 +567,9 @@ func TestGetAllUsers(t *testing.T) {
 
 	t.Run("internal server error", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
-		mockService.On("GetAllUsers", mock.Anything).Return(nil, errors.New("db error")).Once()
+		mockService.On("GetAllUsers", mock.Anything, mock.Anything).Return(nil, errors.New("db error")).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/users", nil)
 		rr := httptest.NewRecorder()
@@ -472,11 +585,11 @@ func TestGetAllUsers(t *testing.T) {
 func TestChangePassword(t *testing.T) {
 	t.Run("success - admin changes password", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		adminUser := &models.User{ID: 1, Username: "admin", Role: "admin"}
 		reqBody := ChangePasswordRequest{UserID: 2, NewPassword: "newpassword"}
-		mockService.On("ChangePassword", mock.Anything, adminUser, reqBody.UserID, reqBody.OldPassword, reqBody.NewPassword).Return(nil).Once()
+		mockService.On("ChangePassword", mock.Anything, mock.Anything, adminUser, reqBody.UserID, reqBody.OldPassword, reqBody.NewPassword).Return(nil).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, adminUser)
 		body, _ := json.Marshal(reqBody)
@@ -492,11 +605,11 @@ func TestChangePassword(t *testing.T) {
 
 	t.Run("success - user changes own password", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		user := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
 		reqBody := ChangePasswordRequest{UserID: 1, OldPassword: "oldpassword", NewPassword: "newpassword"}
-		mockService.On("ChangePassword", mock.Anything, user, reqBody.UserID, reqBody.OldPassword, reqBody.NewPassword).Return(nil).Once()
+		mockService.On("ChangePassword", mock.Anything, mock.Anything, user, reqBody.UserID, reqBody.OldPassword, reqBody.NewPassword).Return(nil).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, user)
 		body, _ := json.Marshal(reqBody)
@@ -512,11 +625,11 @@ func TestChangePassword(t *testing.T) {
 
 	t.Run("permission denied", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		user := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
 		reqBody := ChangePasswordRequest{UserID: 2, OldPassword: "oldpassword", NewPassword: "newpassword"}
-		mockService.On("ChangePassword", mock.Anything, user, reqBody.UserID, reqBody.OldPassword, reqBody.NewPassword).Return(services.ErrPermissionDenied).Once()
+		mockService.On("ChangePassword", mock.Anything, mock.Anything, user, reqBody.UserID, reqBody.OldPassword, reqBody.NewPassword).Return(services.ErrPermissionDenied).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, user)
 		body, _ := json.Marshal(reqBody)
@@ -532,11 +645,11 @@ func TestChangePassword(t *testing.T) {
 
 	t.Run("invalid credentials", func(t *testing.T) {
 		mockService := new(mocks.UserService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &config.Config{})
 
 		user := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
 		reqBody := ChangePasswordRequest{UserID: 1, OldPassword: "wrongpassword", NewPassword: "newpassword"}
-		mockService.On("ChangePassword", mock.Anything, user, reqBody.UserID, reqBody.OldPassword, reqBody.NewPassword).Return(services.ErrInvalidCredentials).Once()
+		mockService.On("ChangePassword", mock.Anything, mock.Anything, user, reqBody.UserID, reqBody.OldPassword, reqBody.NewPassword).Return(services.ErrInvalidCredentials).Once()
 
 		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, user)
 		body, _ := json.Marshal(reqBody)
@@ -550,3 +663,39 @@ func TestChangePassword(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestReauthenticate(t *testing.T) {
+	user := &models.User{ID: 1, Username: "admin", Role: "admin"}
+	newRequest := func(password string) *http.Request {
+		body, _ := json.Marshal(ReauthenticateRequest{Password: password})
+		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, user)
+		return httptest.NewRequest(http.MethodPost, "/api/v1/auth/reauthenticate", bytes.NewBuffer(body)).WithContext(ctx)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(mocks.UserService)
+		handler := NewAuthHandler(mockService, &config.Config{})
+		expiresAt := time.Date(2025, 3, 1, 12, 5, 0, 0, time.UTC)
+		mockService.On("Reauthenticate", mock.Anything, mock.Anything, 1, "password").Return("reauth-token", expiresAt, nil).Once()
+		rr := httptest.NewRecorder()
+
+		handler.Reauthenticate(rr, newRequest("password"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"reauthentication_token":"reauth-token","expires_at":"2025-03-01T12:05:00Z"}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		mockService := new(mocks.UserService)
+		handler := NewAuthHandler(mockService, &config.Config{})
+		mockService.On("Reauthenticate", mock.Anything, mock.Anything, 1, "wrong").Return("", time.Time{}, services.ErrInvalidCredentials).Once()
+		rr := httptest.NewRecorder()
+
+		handler.Reauthenticate(rr, newRequest("wrong"))
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Invalid password")
+		mockService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// BenchmarkHandler handles the requests for the cross-facility benchmarks.
+type BenchmarkHandler struct {
+	BenchmarkService services.BenchmarkService
+	Clock            clock.Clock
+}
+
+// NewBenchmarkHandler creates a new BenchmarkHandler.
+func NewBenchmarkHandler(benchmarkService services.BenchmarkService, clock clock.Clock) *BenchmarkHandler {
+	return &BenchmarkHandler{BenchmarkService: benchmarkService, Clock: clock}
+}
+
+// benchmarkMonth returns the "month" query parameter, by default the past month.
+func (handler *BenchmarkHandler) benchmarkMonth(request *http.Request) string {
+	if month := request.URL.Query().Get("month"); month != "" {
+		return month
+	}
+	today := models.Today(handler.Clock.Now())
+	return models.NewDate(today.Year(), today.Month()-1, 1).Format("2006-01")
+}
+
+// ExportAggregate handles downloading the anonymized aggregate of the facility in a month, to be imported
+// by other facilities.
+func (handler *BenchmarkHandler) ExportAggregate(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	month := handler.benchmarkMonth(request)
+
+	aggregate, err := handler.BenchmarkService.GetOwnAggregate(logger, request.Context(), month)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "Invalid month, must be YYYY-MM and not in the future", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("month", month).Error("Internal server error exporting benchmark aggregate")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=benchmark-%s.json", aggregate.Month))
+	if err := json.NewEncoder(writer).Encode(aggregate); err != nil {
+		logger.WithError(err).Error("Failed to encode response for ExportAggregate")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ImportAggregate handles importing the aggregate another facility shared.
+func (handler *BenchmarkHandler) ImportAggregate(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	var aggregate models.BenchmarkAggregate
+	if err := json.NewDecoder(request.Body).Decode(&aggregate); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for ImportAggregate")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.BenchmarkService.ImportAggregate(logger, request.Context(), &aggregate); err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error importing benchmark aggregate")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetComparison handles comparing the facility to the other facilities in a month.
+func (handler *BenchmarkHandler) GetComparison(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	month := handler.benchmarkMonth(request)
+
+	comparison, err := handler.BenchmarkService.GetComparison(logger, request.Context(), month)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "Invalid month, must be YYYY-MM and not in the future", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("month", month).Error("Internal server error comparing benchmarks")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(comparison); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetComparison")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
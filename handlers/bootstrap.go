@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// BootstrapHandler handles instance bootstrap requests.
+type BootstrapHandler struct {
+	BootstrapService services.BootstrapService
+}
+
+// NewBootstrapHandler creates a new BootstrapHandler.
+func NewBootstrapHandler(bootstrapService services.BootstrapService) *BootstrapHandler {
+	return &BootstrapHandler{BootstrapService: bootstrapService}
+}
+
+// Bootstrap handles installing the default data of a new instance.
+func (handler *BootstrapHandler) Bootstrap(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for Bootstrap handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	result, err := handler.BootstrapService.Bootstrap(logger, request.Context(), user.ID)
+	if err != nil {
+		switch err {
+		case services.ErrAlreadyExists:
+			http.Error(writer, "Instance has already been bootstrapped", http.StatusConflict)
+		default:
+			logger.WithError(err).Error("Internal server error during bootstrap")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(writer).Encode(result); err != nil {
+		logger.WithError(err).Error("Failed to encode response for Bootstrap")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// BreakGlassHandler handles the HTTP requests for the emergency access of a facility whose only admin is locked out.
+type BreakGlassHandler struct {
+	BreakGlassService services.BreakGlassService
+}
+
+// NewBreakGlassHandler creates a new BreakGlassHandler.
+func NewBreakGlassHandler(breakGlassService services.BreakGlassService) *BreakGlassHandler {
+	return &BreakGlassHandler{BreakGlassService: breakGlassService}
+}
+
+// SealBreakGlass handles sealing a new break-glass credential. The code is only in this response, it is meant to
+// be printed and locked away.
+func (handler *BreakGlassHandler) SealBreakGlass(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for SealBreakGlass handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	sealed, err := handler.BreakGlassService.Seal(logger, request.Context(), user.ID)
+	if err != nil {
+		logger.WithError(err).Error("Internal server error sealing break-glass credential")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Cache-Control", "no-store")
+	writeCreatedHeader(writer, "/api/v1/admin/break-glass", sealed.Credential.ID)
+	if err := json.NewEncoder(writer).Encode(sealed); err != nil {
+		logger.WithError(err).Error("Failed to encode response for SealBreakGlass")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetBreakGlassCredentials handles listing the break-glass credentials with their activations.
+func (handler *BreakGlassHandler) GetBreakGlassCredentials(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	credentials, err := handler.BreakGlassService.GetCredentials(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching break-glass credentials")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(credentials); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetBreakGlassCredentials")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RevokeBreakGlass handles revoking a break-glass credential, e.g. because the envelope was opened. Revoking an
+// activated credential ends the emergency access.
+func (handler *BreakGlassHandler) RevokeBreakGlass(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for RevokeBreakGlass handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	credentialIDStr := request.PathValue("credential_id")
+	credentialID, err := strconv.Atoi(credentialIDStr)
+	if err != nil {
+		logger.WithField("credential_id_str", credentialIDStr).WithError(err).Warn("Invalid credential ID format for RevokeBreakGlass")
+		http.Error(writer, "Invalid credential ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.BreakGlassService.Revoke(logger, request.Context(), credentialID, user.ID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("credential_id", credentialID).Error("Internal server error revoking break-glass credential")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// ActivateBreakGlass handles activating the sealed break-glass credential with its code and a reason. The response
+// carries a token of the break-glass account that expires with the emergency access.
+func (handler *BreakGlassHandler) ActivateBreakGlass(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var req models.BreakGlassActivation
+	if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for ActivateBreakGlass")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	session, err := handler.BreakGlassService.Activate(logger, request.Context(), req)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		if writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "A code and a reason are required", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error activating break-glass credential")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(writer).Encode(session); err != nil {
+		logger.WithError(err).Error("Failed to encode response for ActivateBreakGlass")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
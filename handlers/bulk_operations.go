@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"kitadoc-backend/internal/logger"
+	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 
@@ -16,15 +21,26 @@ import (
 
 // BulkOperationsHandler handles bulk operations HTTP requests.
 type BulkOperationsHandler struct {
-	ChildService services.ChildService
+	ImportJobService           services.ImportJobService
+	DocumentationImportService services.DocumentationImportService
+	UploadScanService          services.UploadScanService
 }
 
 // NewBulkOperationsHandler creates a new BulkOperationsHandler.
-func NewBulkOperationsHandler(childService services.ChildService) *BulkOperationsHandler {
-	return &BulkOperationsHandler{ChildService: childService}
+func NewBulkOperationsHandler(
+	importJobService services.ImportJobService,
+	documentationImportService services.DocumentationImportService,
+	uploadScanService services.UploadScanService,
+) *BulkOperationsHandler {
+	return &BulkOperationsHandler{
+		ImportJobService:           importJobService,
+		DocumentationImportService: documentationImportService,
+		UploadScanService:          uploadScanService,
+	}
 }
 
 // ImportChildren handles bulk import of children from an XLSX file.
+// The rows are persisted as an import job, so that an import that stops on an error can be resumed.
 func (bulkOperationsHandler *BulkOperationsHandler) ImportChildren(writer http.ResponseWriter, request *http.Request) {
 	log := logger.GetLoggerFromContext(request.Context())
 
@@ -37,7 +53,7 @@ func (bulkOperationsHandler *BulkOperationsHandler) ImportChildren(writer http.R
 	}
 
 	// Get the file from the form
-	file, _, err := request.FormFile("file")
+	file, fileHeader, err := request.FormFile("file")
 	if err != nil {
 		log.Errorf("Failed to get file from form: %v", err)
 		http.Error(writer, "Failed to get file from form: "+err.Error(), http.StatusBadRequest)
@@ -49,151 +65,87 @@ func (bulkOperationsHandler *BulkOperationsHandler) ImportChildren(writer http.R
 		}
 	}()
 
-	// Open the XLSX file
-	f, err := excelize.OpenReader(file)
+	content, err := io.ReadAll(file)
 	if err != nil {
-		log.Errorf("Failed to open XLSX file: %v", err)
-		http.Error(writer, "Failed to open XLSX file: "+err.Error(), http.StatusInternalServerError)
+		log.Errorf("Failed to read XLSX file: %v", err)
+		http.Error(writer, "Failed to read XLSX file", http.StatusBadRequest)
 		return
 	}
-
-	// Get all the rows from the first sheet
-	sheetName := f.GetSheetName(0)
-	if sheetName == "" {
-		log.Error("No sheet found in the XLSX file")
-		http.Error(writer, "No sheet found in the XLSX file", http.StatusBadRequest)
+	if !scanUpload(writer, request, bulkOperationsHandler.UploadScanService, models.UploadKindChildrenImport, fileHeader.Filename, content) {
 		return
 	}
 
-	rows, err := f.GetRows(sheetName)
+	// Open the XLSX file
+	f, err := excelize.OpenReader(bytes.NewReader(content))
 	if err != nil {
-		log.Errorf("Failed to get rows from sheet %s: %v", sheetName, err)
-		http.Error(writer, "Failed to get rows from sheet: "+err.Error(), http.StatusInternalServerError)
+		log.Errorf("Failed to open XLSX file: %v", err)
+		http.Error(writer, "Failed to open XLSX file: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	headerRow := rows[0]
-	dataRows := rows[1:]
-
-	// Define the mapping from German headers to Child struct fields
-	headerMapping := map[string]string{
-		"Vorname":          "FirstName",
-		"Nachname":         "LastName",
-		"Geburtsdatum":     "Birthdate",
-		"Aufnahmedatum":    "AdmissionDate",
-		"Entlassungsdatum": "ExpectedSchoolEnrollment",
-	}
-
-	// Build a map from column index to Child struct field name
-	colIndexToField := make(map[int]string)
-	for i, header := range headerRow {
-		trimmedHeader := strings.TrimSpace(header)
-		if fieldName, ok := headerMapping[trimmedHeader]; ok {
-			colIndexToField[i] = fieldName
+	// Find the sheet and row holding the children list
+	sheetName, colIndexToField, dataRows, err := detectChildImportTable(f)
+	if err != nil {
+		if err == errNoChildImportTable {
+			log.Warn("No sheet with a children list found in the XLSX file")
+			http.Error(writer, "Keine Tabelle mit den Spalten Vorname, Nachname und Geburtsdatum gefunden", http.StatusBadRequest)
+			return
 		}
+		log.Errorf("Failed to read rows from XLSX file: %v", err)
+		http.Error(writer, "Failed to get rows from sheet: "+err.Error(), http.StatusInternalServerError)
+		return
 	}
+	log.Infof("Importing children from sheet %s", sheetName)
 
-	var importedChildren []*models.Child
-	var importErrors []map[string]string
-
+	importRows := make([]models.ImportJobRow, 0, len(dataRows))
 	for i, row := range dataRows {
-		var createdChild *models.Child
-		var err error
-		child := &models.Child{}
-		childName := "" // To store child's name for error reporting
-
-		// Populate child struct from row data
-		for colIndex, cellValue := range row {
-			fieldName, ok := colIndexToField[colIndex]
-			if !ok {
-				continue // Skip columns not in our mapping
-			}
-
-			trimmedCellValue := strings.TrimSpace(cellValue)
-
-			switch fieldName {
-			case "FirstName":
-				child.FirstName = trimmedCellValue
-				childName = trimmedCellValue // Use first name as part of childName
-			case "LastName":
-				child.LastName = trimmedCellValue
-				if childName != "" {
-					childName = fmt.Sprintf("%s %s", childName, trimmedCellValue)
-				} else {
-					childName = trimmedCellValue
-				}
-			case "Birthdate":
-				// Assuming date format DD.MM.YYYY
-				birthdate, err := time.Parse("02.01.2006", trimmedCellValue)
-				if err != nil {
-					importErrors = append(importErrors, map[string]string{
-						"child_name": childName,
-						"error":      fmt.Sprintf("Reihe %d: Ungültiges Format für Geburtsdatum '%s'. Ein Datum im Format 02.01.2006 wird erwartet.", i+1, trimmedCellValue),
-					})
-					log.Warnf("Row %d: Invalid Birthdate format for child %s: %v", i+1, childName, err)
-					goto nextRow // Skip to the next row
-				}
-				child.Birthdate = birthdate
-			case "AdmissionDate":
-				// Assuming date format DD.MM.YYYY
-				admissionDate, err := time.Parse("02.01.2006", trimmedCellValue)
-				if err != nil {
-					importErrors = append(importErrors, map[string]string{
-						"child_name": childName,
-						"error":      fmt.Sprintf("Reihe %d: Ungültiges Format für Aufnahmedatum '%s'. Ein Datum im Format 02.01.2006 wird erwartet.", i+1, trimmedCellValue),
-					})
-					log.Warnf("Row %d: Invalid AdmissionDate format for child %s: %v", i+1, childName, err)
-					goto nextRow // Skip to the next row
-				}
-				child.AdmissionDate = &admissionDate
-			case "ExpectedSchoolEnrollment":
-				// Assuming date format DD.MM.YYYY
-				enrollmentDate, err := time.Parse("02.01.2006", trimmedCellValue)
-				if err != nil {
-					importErrors = append(importErrors, map[string]string{
-						"child_name": childName,
-						"error":      fmt.Sprintf("Reihe %d: Ungültiges Format für Entlassungsdatum '%s'. Ein Datum im Format 02.01.2006 wird erwartet.", i+1, trimmedCellValue),
-					})
-					log.Warnf("Row %d: Invalid ExpectedSchoolEnrollment format for child %s: %v", i+1, childName, err)
-					goto nextRow // Skip to the next row
-				}
-				child.ExpectedSchoolEnrollment = &enrollmentDate
-			}
-		}
-
-		// Validate the child struct before creation
-		if err = models.ValidateChild(*child); err != nil {
-			importErrors = append(importErrors, map[string]string{
-				"child_name": childName,
-				"error":      fmt.Sprintf("Reihe %d: Kind %s konnte nicht erfolgreich importiert werden: %v", i+1, childName, err),
-			})
-			log.Warnf("Row %d: Child validation failed for child %s: %v", i+1, childName, err)
-			goto nextRow // Skip to the next row
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue // Skip empty rows, spreadsheet exports often end with some
 		}
+		importRows = append(importRows, parseChildImportRow(log, row, colIndexToField, i+1))
+	}
 
-		// Set CreatedAt and UpdatedAt
-		child.CreatedAt = time.Now()
-		child.UpdatedAt = time.Now()
+	job, err := bulkOperationsHandler.ImportJobService.ImportChildren(middleware.GetLoggerWithReqID(request.Context()), request.Context(), fileHeader.Filename, importRows)
+	if err != nil {
+		log.Errorf("Failed to run import job: %v", err)
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
 
-		createdChild, err = bulkOperationsHandler.ChildService.CreateChild(child)
-		if err != nil {
+	importedChildren := []models.Child{}
+	importErrors := []map[string]string{}
+	for _, row := range job.Rows {
+		switch row.Status {
+		case models.ImportRowStatusImported:
+			child := *row.Child
+			child.ID = *row.ChildID
+			importedChildren = append(importedChildren, child)
+		case models.ImportRowStatusInvalid:
 			importErrors = append(importErrors, map[string]string{
-				"child_name": childName,
-				"error":      fmt.Sprintf("Reihe %d: Kind %s konnte nicht erstellt werden: %v", i+1, childName, err),
+				"child_name": row.ChildName,
+				"error":      *row.Error,
 			})
-			log.Errorf("Row %d: Failed to create child %s: %v", i+1, childName, err)
-			goto nextRow // Skip to the next row
 		}
-		importedChildren = append(importedChildren, createdChild)
+	}
 
-	nextRow:
-		continue
+	if job.Status == models.ImportJobStatusFailed {
+		writer.WriteHeader(http.StatusInternalServerError)
+		if err := json.NewEncoder(writer).Encode(map[string]interface{}{
+			"message":        "Massenimport abgebrochen. Der Import kann fortgesetzt werden.",
+			"import_job_id":  job.ID,
+			"imported_count": len(importedChildren),
+			"errors":         importErrors,
+		}); err != nil {
+			log.Errorf("Failed to encode response for failed import: %v", err)
+		}
+		return
 	}
 
 	if len(importErrors) > 0 {
 		writer.WriteHeader(http.StatusPartialContent)
 		if err := json.NewEncoder(writer).Encode(map[string]interface{}{
 			"message":        "Massenimport mit Fehlern abgeschlossen.",
+			"import_job_id":  job.ID,
 			"imported_count": len(importedChildren),
 			"errors":         importErrors,
 		}); err != nil {
@@ -206,6 +158,7 @@ func (bulkOperationsHandler *BulkOperationsHandler) ImportChildren(writer http.R
 	writer.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(writer).Encode(map[string]interface{}{
 		"message":        "Massenimport erfolgreich abgeschlossen",
+		"import_job_id":  job.ID,
 		"imported_count": len(importedChildren),
 		"children":       importedChildren,
 	}); err != nil {
@@ -214,3 +167,267 @@ func (bulkOperationsHandler *BulkOperationsHandler) ImportChildren(writer http.R
 		return
 	}
 }
+
+// childImportHeaders maps the lower case column headers of a children list to Child struct fields.
+// Besides the headers of the import template it accepts the usual names of spreadsheet exports.
+var childImportHeaders = map[string]string{
+	"vorname":                      "FirstName",
+	"nachname":                     "LastName",
+	"familienname":                 "LastName",
+	"geburtsdatum":                 "Birthdate",
+	"aufnahmedatum":                "AdmissionDate",
+	"aufnahme":                     "AdmissionDate",
+	"entlassungsdatum":             "ExpectedSchoolEnrollment",
+	"einschulung":                  "ExpectedSchoolEnrollment",
+	"voraussichtliche einschulung": "ExpectedSchoolEnrollment",
+}
+
+// maxHeaderSearchRows is how far down a sheet the header row is looked for, below titles and notes of an export.
+const maxHeaderSearchRows = 10
+
+var errNoChildImportTable = errors.New("no children list found")
+
+// detectChildImportTable finds the first sheet with a header row naming at least the first name, last name and
+// birthdate columns. It returns the sheet name, the mapping from column index to Child struct field and the rows
+// below the header.
+func detectChildImportTable(f *excelize.File) (string, map[int]string, [][]string, error) {
+	for _, sheetName := range f.GetSheetList() {
+		rows, err := f.GetRows(sheetName)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		for rowIndex, row := range rows[:min(len(rows), maxHeaderSearchRows)] {
+			colIndexToField := make(map[int]string)
+			fields := make(map[string]bool)
+			for colIndex, header := range row {
+				if fieldName, ok := childImportHeaders[strings.ToLower(strings.TrimSpace(header))]; ok && !fields[fieldName] {
+					colIndexToField[colIndex] = fieldName
+					fields[fieldName] = true
+				}
+			}
+			if fields["FirstName"] && fields["LastName"] && fields["Birthdate"] {
+				return sheetName, colIndexToField, rows[rowIndex+1:], nil
+			}
+		}
+	}
+	return "", nil, nil, errNoChildImportTable
+}
+
+// parseChildImportRow parses a data row of the import file. Rows that cannot be parsed or validated
+// are returned without a child and with the error shown to the user.
+func parseChildImportRow(log logger.Logger, row []string, colIndexToField map[int]string, rowNumber int) models.ImportJobRow {
+	child := &models.Child{}
+	childName := "" // To store child's name for error reporting
+	invalid := func(message string) models.ImportJobRow {
+		return models.ImportJobRow{RowNumber: rowNumber, ChildName: childName, Error: &message}
+	}
+
+	// Populate child struct from row data
+	for colIndex, cellValue := range row {
+		fieldName, ok := colIndexToField[colIndex]
+		if !ok {
+			continue // Skip columns not in our mapping
+		}
+
+		trimmedCellValue := strings.TrimSpace(cellValue)
+
+		switch fieldName {
+		case "FirstName":
+			child.FirstName = trimmedCellValue
+			childName = trimmedCellValue // Use first name as part of childName
+		case "LastName":
+			child.LastName = trimmedCellValue
+			if childName != "" {
+				childName = fmt.Sprintf("%s %s", childName, trimmedCellValue)
+			} else {
+				childName = trimmedCellValue
+			}
+		case "Birthdate":
+			// Assuming date format DD.MM.YYYY
+			birthdate, err := time.Parse("02.01.2006", trimmedCellValue)
+			if err != nil {
+				log.Warnf("Row %d: Invalid Birthdate format for child %s: %v", rowNumber, childName, err)
+				return invalid(fmt.Sprintf("Reihe %d: Ungültiges Format für Geburtsdatum '%s'. Ein Datum im Format 02.01.2006 wird erwartet.", rowNumber, trimmedCellValue))
+			}
+			child.Birthdate = models.DateOf(birthdate)
+		case "AdmissionDate":
+			// Assuming date format DD.MM.YYYY
+			admissionDate, err := time.Parse("02.01.2006", trimmedCellValue)
+			if err != nil {
+				log.Warnf("Row %d: Invalid AdmissionDate format for child %s: %v", rowNumber, childName, err)
+				return invalid(fmt.Sprintf("Reihe %d: Ungültiges Format für Aufnahmedatum '%s'. Ein Datum im Format 02.01.2006 wird erwartet.", rowNumber, trimmedCellValue))
+			}
+			child.AdmissionDate = &admissionDate
+		case "ExpectedSchoolEnrollment":
+			// Assuming date format DD.MM.YYYY
+			enrollmentDate, err := time.Parse("02.01.2006", trimmedCellValue)
+			if err != nil {
+				log.Warnf("Row %d: Invalid ExpectedSchoolEnrollment format for child %s: %v", rowNumber, childName, err)
+				return invalid(fmt.Sprintf("Reihe %d: Ungültiges Format für Entlassungsdatum '%s'. Ein Datum im Format 02.01.2006 wird erwartet.", rowNumber, trimmedCellValue))
+			}
+			child.ExpectedSchoolEnrollment = &enrollmentDate
+		}
+	}
+
+	// Validate the child struct before creation
+	if err := models.ValidateChild(*child); err != nil {
+		log.Warnf("Row %d: Child validation failed for child %s: %v", rowNumber, childName, err)
+		return invalid(fmt.Sprintf("Reihe %d: Kind %s konnte nicht erfolgreich importiert werden: %v", rowNumber, childName, err))
+	}
+
+	return models.ImportJobRow{RowNumber: rowNumber, ChildName: childName, Child: child}
+}
+
+// GetImportJobs handles fetching the history of bulk imports.
+func (bulkOperationsHandler *BulkOperationsHandler) GetImportJobs(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	jobs, err := bulkOperationsHandler.ImportJobService.GetImportJobs(logger, request.Context())
+	if err != nil {
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(jobs); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetImportJobs")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetImportJob handles fetching a bulk import with the status of its rows.
+func (bulkOperationsHandler *BulkOperationsHandler) GetImportJob(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	jobID, ok := parseImportJobID(writer, request, "GetImportJob")
+	if !ok {
+		return
+	}
+
+	job, err := bulkOperationsHandler.ImportJobService.GetImportJobByID(logger, request.Context(), jobID)
+	if err != nil {
+		writeImportJobError(writer, err)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(job); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetImportJob")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ResumeImportJob handles importing the pending rows of a failed bulk import.
+func (bulkOperationsHandler *BulkOperationsHandler) ResumeImportJob(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	jobID, ok := parseImportJobID(writer, request, "ResumeImportJob")
+	if !ok {
+		return
+	}
+
+	job, err := bulkOperationsHandler.ImportJobService.ResumeImportJob(logger, request.Context(), jobID)
+	if err != nil {
+		writeImportJobError(writer, err)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(job); err != nil {
+		logger.WithError(err).Error("Failed to encode response for ResumeImportJob")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RollbackImportJob handles deleting the children created by a bulk import.
+// The optional body {"row_numbers": [...]} limits the rollback to these rows.
+func (bulkOperationsHandler *BulkOperationsHandler) RollbackImportJob(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	jobID, ok := parseImportJobID(writer, request, "RollbackImportJob")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		RowNumbers []int `json:"row_numbers"`
+	}
+	if err := json.NewDecoder(request.Body).Decode(&body); err != nil && err != io.EOF {
+		logger.WithError(err).Warn("Invalid request payload for RollbackImportJob")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	result, err := bulkOperationsHandler.ImportJobService.RollbackImportJob(logger, request.Context(), jobID, body.RowNumbers)
+	if err != nil {
+		writeImportJobError(writer, err)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(result); err != nil {
+		logger.WithError(err).Error("Failed to encode response for RollbackImportJob")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ImportDocumentation handles importing historical observations from the previous paper system.
+// The response is a reconciliation report; with "dry_run" set nothing is imported.
+func (bulkOperationsHandler *BulkOperationsHandler) ImportDocumentation(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	var importRequest models.DocumentationImportRequest
+	if err := json.NewDecoder(request.Body).Decode(&importRequest); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for ImportDocumentation")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	report, err := bulkOperationsHandler.DocumentationImportService.ImportDocumentation(logger, request.Context(), &importRequest)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, "Invalid documentation import", http.StatusBadRequest)
+		case services.ErrForeignKeyConstraint:
+			http.Error(writer, "Referenced child, teacher or category no longer exists", http.StatusConflict)
+		default:
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(report); err != nil {
+		logger.WithError(err).Error("Failed to encode response for ImportDocumentation")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseImportJobID reads the job_id path value, writing a bad request response if it is invalid.
+func parseImportJobID(writer http.ResponseWriter, request *http.Request, operation string) (int, bool) {
+	jobID, err := strconv.Atoi(request.PathValue("job_id"))
+	if err != nil {
+		middleware.GetLoggerWithReqID(request.Context()).WithError(err).Warnf("Invalid import job ID for %s", operation)
+		http.Error(writer, "Invalid import job ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return jobID, true
+}
+
+// writeImportJobError maps the errors of the import job service to responses.
+func writeImportJobError(writer http.ResponseWriter, err error) {
+	switch err {
+	case services.ErrNotFound:
+		http.Error(writer, "Import job not found", http.StatusNotFound)
+	case services.ErrInvalidInput:
+		http.Error(writer, "Only imported rows can be rolled back", http.StatusBadRequest)
+	case services.ErrInvalidStateTransition:
+		http.Error(writer, "Import job cannot be changed in its current status", http.StatusConflict)
+	default:
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestDetectChildImportTable(t *testing.T) {
+	t.Run("header below title on second sheet", func(t *testing.T) {
+		f := excelize.NewFile()
+		defer f.Close() //nolint:errcheck
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Hinweise zum Export"}))
+		_, err := f.NewSheet("Kinder")
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Kinder", "A1", &[]string{"Kinderliste Kita Sonnenschein"}))
+		assert.NoError(t, f.SetSheetRow("Kinder", "A3", &[]string{"Gruppe", " geburtsdatum ", "Familienname", "VORNAME", "Voraussichtliche Einschulung"}))
+		assert.NoError(t, f.SetSheetRow("Kinder", "A4", &[]string{"Bären", "18.11.2022", "Musterkind", "Anna", "31.07.2029"}))
+
+		sheetName, colIndexToField, dataRows, err := detectChildImportTable(f)
+		assert.NoError(t, err)
+		assert.Equal(t, "Kinder", sheetName)
+		assert.Equal(t, map[int]string{1: "Birthdate", 2: "LastName", 3: "FirstName", 4: "ExpectedSchoolEnrollment"}, colIndexToField)
+		assert.Equal(t, [][]string{{"Bären", "18.11.2022", "Musterkind", "Anna", "31.07.2029"}}, dataRows)
+	})
+
+	t.Run("no children list", func(t *testing.T) {
+		f := excelize.NewFile()
+		defer f.Close() //nolint:errcheck
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Vorname", "Nachname"}))
+
+		_, _, _, err := detectChildImportTable(f)
+		assert.Equal(t, errNoChildImportTable, err)
+	})
+}
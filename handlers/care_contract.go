@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// CareContractHandler handles the HTTP requests for the booked care hours of children.
+type CareContractHandler struct {
+	CareContractService services.CareContractService
+}
+
+// NewCareContractHandler creates a new CareContractHandler.
+func NewCareContractHandler(careContractService services.CareContractService) *CareContractHandler {
+	return &CareContractHandler{CareContractService: careContractService}
+}
+
+// CreateContract handles adding a care contract to the child of the path.
+func (handler *CareContractHandler) CreateContract(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "CreateContract")
+	if !ok {
+		return
+	}
+
+	var contract models.CareContract
+	if err := json.NewDecoder(request.Body).Decode(&contract); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateContract")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	contract.ChildID = childID
+
+	created, err := handler.CareContractService.CreateContract(logger, request.Context(), &contract)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error during care contract creation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/care-contracts", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateContract")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetContractsForChild handles listing the care contracts of a child.
+func (handler *CareContractHandler) GetContractsForChild(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetContractsForChild")
+	if !ok {
+		return
+	}
+
+	contracts, err := handler.CareContractService.GetContractsForChild(logger, request.Context(), childID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching care contracts of child")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(contracts); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetContractsForChild")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetContractByID handles fetching a care contract by ID.
+func (handler *CareContractHandler) GetContractByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	contractID, ok := parsePathID(writer, request, "contract_id", "GetContractByID")
+	if !ok {
+		return
+	}
+
+	contract, err := handler.CareContractService.GetContractByID(logger, request.Context(), contractID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("contract_id", contractID).Error("Internal server error fetching care contract")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(contract); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetContractByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateContract handles changing the hours or the period of a care contract, e.g. to end it when the hours change.
+func (handler *CareContractHandler) UpdateContract(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	contractID, ok := parsePathID(writer, request, "contract_id", "UpdateContract")
+	if !ok {
+		return
+	}
+
+	var contract models.CareContract
+	if err := json.NewDecoder(request.Body).Decode(&contract); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateContract")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	contract.ID = contractID
+
+	if err := handler.CareContractService.UpdateContract(logger, request.Context(), &contract); err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("contract_id", contractID).Error("Internal server error during care contract update")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Care contract updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateContract")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteContract handles deleting a care contract.
+func (handler *CareContractHandler) DeleteContract(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	contractID, ok := parsePathID(writer, request, "contract_id", "DeleteContract")
+	if !ok {
+		return
+	}
+
+	if err := handler.CareContractService.DeleteContract(logger, request.Context(), contractID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("contract_id", contractID).Error("Internal server error deleting care contract")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetCareHoursWarnings handles listing the children who attended longer than booked in each of the past weeks. The
+// optional "weeks" query parameter is how many weeks are checked, 4 by default.
+func (handler *CareContractHandler) GetCareHoursWarnings(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	weeks := services.DefaultCareHoursWeeks
+	if weeksStr := request.URL.Query().Get("weeks"); weeksStr != "" {
+		var err error
+		weeks, err = strconv.Atoi(weeksStr)
+		if err != nil || weeks < 1 || weeks > services.MaxCareHoursWeeks {
+			logger.WithField("weeks_str", weeksStr).Warn("Invalid weeks for GetCareHoursWarnings")
+			http.Error(writer, fmt.Sprintf("Invalid weeks, must be between 1 and %d", services.MaxCareHoursWeeks), http.StatusBadRequest)
+			return
+		}
+	}
+
+	warnings, err := handler.CareContractService.GetCareHoursWarnings(logger, request.Context(), weeks)
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching care hours warnings")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(warnings); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetCareHoursWarnings")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 )
@@ -27,8 +28,11 @@ func (handler *CategoryHandler) CreateCategory(writer http.ResponseWriter, reque
 		return
 	}
 
-	createdCategory, err := handler.CategoryService.CreateCategory(&category)
+	createdCategory, err := handler.CategoryService.CreateCategory(request.Context(), &category)
 	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
 		if err == services.ErrInvalidInput {
 			http.Error(writer, "Invalid category data provided", http.StatusBadRequest)
 			return
@@ -37,16 +41,37 @@ func (handler *CategoryHandler) CreateCategory(writer http.ResponseWriter, reque
 		return
 	}
 
-	writer.WriteHeader(http.StatusCreated)
+	writeCreatedHeader(writer, "/api/v1/categories", createdCategory.ID)
 	if err := json.NewEncoder(writer).Encode(createdCategory); err != nil {
 		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
-// GetAllCategories handles fetching all categories.
+// GetAllCategories handles fetching all categories. Archived categories are only listed with include_archived=true.
+// With view=favorites, only the categories pinned or used most by the current user are listed.
 func (handler *CategoryHandler) GetAllCategories(writer http.ResponseWriter, request *http.Request) {
-	categories, err := handler.CategoryService.GetAllCategories()
+	switch request.URL.Query().Get("view") {
+	case "":
+	case "favorites":
+		handler.getFavoriteCategories(writer, request)
+		return
+	default:
+		http.Error(writer, "Invalid view value", http.StatusBadRequest)
+		return
+	}
+
+	includeArchived := false
+	if includeStr := request.URL.Query().Get("include_archived"); includeStr != "" {
+		include, err := strconv.ParseBool(includeStr)
+		if err != nil {
+			http.Error(writer, "Invalid include_archived value", http.StatusBadRequest)
+			return
+		}
+		includeArchived = include
+	}
+
+	categories, err := handler.CategoryService.GetAllCategories(request.Context(), includeArchived)
 	if err != nil {
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
@@ -58,6 +83,52 @@ func (handler *CategoryHandler) GetAllCategories(writer http.ResponseWriter, req
 	}
 }
 
+func (handler *CategoryHandler) getFavoriteCategories(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for GetAllCategories handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	favorites, err := handler.CategoryService.GetFavoriteCategories(request.Context(), user)
+	if err != nil {
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(favorites); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetCategoryByID handles fetching a category by ID.
+func (handler *CategoryHandler) GetCategoryByID(writer http.ResponseWriter, request *http.Request) {
+	idStr := request.PathValue("category_id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(writer, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	category, err := handler.CategoryService.GetCategoryByID(request.Context(), id)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Category not found", http.StatusNotFound)
+			return
+		}
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(category); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
 // UpdateCategory handles updating an existing category.
 func (handler *CategoryHandler) UpdateCategory(writer http.ResponseWriter, request *http.Request) {
 	idStr := request.PathValue("category_id")
@@ -75,8 +146,11 @@ func (handler *CategoryHandler) UpdateCategory(writer http.ResponseWriter, reque
 
 	category.ID = id
 
-	err = handler.CategoryService.UpdateCategory(&category)
+	err = handler.CategoryService.UpdateCategory(request.Context(), &category)
 	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
 		if err == services.ErrNotFound {
 			http.Error(writer, "Category not found", http.StatusNotFound)
 			return
@@ -105,14 +179,14 @@ func (handler *CategoryHandler) DeleteCategory(writer http.ResponseWriter, reque
 		return
 	}
 
-	err = handler.CategoryService.DeleteCategory(id)
+	err = handler.CategoryService.DeleteCategory(request.Context(), id)
 	if err != nil {
 		switch err {
 		case services.ErrNotFound:
 			http.Error(writer, "Category not found", http.StatusNotFound)
 			return
 		case services.ErrForeignKeyConstraint:
-			http.Error(writer, "Cannot delete category: foreign key constraint violation", http.StatusConflict)
+			http.Error(writer, "Cannot delete category: foreign key constraint violation, archive the category instead", http.StatusConflict)
 			return
 		}
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
@@ -125,3 +199,123 @@ func (handler *CategoryHandler) DeleteCategory(writer http.ResponseWriter, reque
 		return
 	}
 }
+
+// ArchiveCategory handles archiving a category that is no longer used, instead of deleting it.
+func (handler *CategoryHandler) ArchiveCategory(writer http.ResponseWriter, request *http.Request) {
+	handler.setArchived(writer, request, true)
+}
+
+// RestoreCategory handles reverting the archiving of a category.
+func (handler *CategoryHandler) RestoreCategory(writer http.ResponseWriter, request *http.Request) {
+	handler.setArchived(writer, request, false)
+}
+
+func (handler *CategoryHandler) setArchived(writer http.ResponseWriter, request *http.Request, archive bool) {
+	idStr := request.PathValue("category_id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(writer, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	message := "Category restored successfully"
+	if archive {
+		err = handler.CategoryService.ArchiveCategory(request.Context(), id)
+		message = "Category archived successfully"
+	} else {
+		err = handler.CategoryService.RestoreCategory(request.Context(), id)
+	}
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Category not found", http.StatusNotFound)
+			return
+		}
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": message}); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// PinCategory handles pinning a category to the favorites of the current user.
+func (handler *CategoryHandler) PinCategory(writer http.ResponseWriter, request *http.Request) {
+	handler.setPinned(writer, request, true)
+}
+
+// UnpinCategory handles removing a category from the favorites of the current user.
+func (handler *CategoryHandler) UnpinCategory(writer http.ResponseWriter, request *http.Request) {
+	handler.setPinned(writer, request, false)
+}
+
+func (handler *CategoryHandler) setPinned(writer http.ResponseWriter, request *http.Request, pin bool) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for category pin handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	id, err := strconv.Atoi(request.PathValue("category_id"))
+	if err != nil {
+		http.Error(writer, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	if pin {
+		err = handler.CategoryService.PinCategory(request.Context(), user, id)
+	} else {
+		err = handler.CategoryService.UnpinCategory(request.Context(), user, id)
+	}
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// ReassignEntries handles moving the documentation entries of the category of the path to another category.
+func (handler *CategoryHandler) ReassignEntries(writer http.ResponseWriter, request *http.Request) {
+	idStr := request.PathValue("category_id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(writer, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody struct {
+		TargetCategoryID int `json:"target_category_id"`
+	}
+	if err := json.NewDecoder(request.Body).Decode(&requestBody); err != nil || requestBody.TargetCategoryID == 0 {
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	moved, err := handler.CategoryService.ReassignEntries(request.Context(), id, requestBody.TargetCategoryID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrNotFound:
+			http.Error(writer, "Category not found", http.StatusNotFound)
+		case services.ErrInvalidInput:
+			http.Error(writer, "Entries cannot be reassigned to their own category", http.StatusBadRequest)
+		default:
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]int{"reassigned_entries": moved}); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
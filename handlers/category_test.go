@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -22,37 +23,70 @@ type MockCategoryService struct {
 	mock.Mock
 }
 
-func (m *MockCategoryService) CreateCategory(category *models.Category) (*models.Category, error) {
-	args := m.Called(category)
+func (m *MockCategoryService) CreateCategory(ctx context.Context, category *models.Category) (*models.Category, error) {
+	args := m.Called(ctx, category)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) GetCategoryByID(id int) (*models.Category, error) {
-	args := m.Called(id)
+func (m *MockCategoryService) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) UpdateCategory(category *models.Category) error {
-	args := m.Called(category)
+func (m *MockCategoryService) UpdateCategory(ctx context.Context, category *models.Category) error {
+	args := m.Called(ctx, category)
 	return args.Error(0)
 }
 
-func (m *MockCategoryService) DeleteCategory(id int) error {
-	args := m.Called(id)
+func (m *MockCategoryService) DeleteCategory(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockCategoryService) GetAllCategories() ([]models.Category, error) {
-	args := m.Called()
+func (m *MockCategoryService) GetAllCategories(ctx context.Context, includeArchived bool) ([]models.Category, error) {
+	args := m.Called(ctx, includeArchived)
 	return args.Get(0).([]models.Category), args.Error(1)
 }
 
+func (m *MockCategoryService) ArchiveCategory(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryService) RestoreCategory(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryService) GetFavoriteCategories(ctx context.Context, user *models.User) ([]models.FavoriteCategory, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FavoriteCategory), args.Error(1)
+}
+
+func (m *MockCategoryService) PinCategory(ctx context.Context, user *models.User, id int) error {
+	args := m.Called(ctx, user, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryService) UnpinCategory(ctx context.Context, user *models.User, id int) error {
+	args := m.Called(ctx, user, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryService) ReassignEntries(ctx context.Context, fromCategoryID int, toCategoryID int) (int, error) {
+	args := m.Called(ctx, fromCategoryID, toCategoryID)
+	return args.Int(0), args.Error(1)
+}
+
 func TestCreateCategory(t *testing.T) {
 	mockCategoryService := new(MockCategoryService)
 	handler := NewCategoryHandler(mockCategoryService)
@@ -71,7 +105,7 @@ func TestCreateCategory(t *testing.T) {
 				Description: models.StringPtr("A category for testing"),
 			},
 			setupMocks: func() {
-				mockCategoryService.On("CreateCategory", mock.AnythingOfType("*models.Category")).Return(&models.Category{
+				mockCategoryService.On("CreateCategory", mock.Anything, mock.AnythingOfType("*models.Category")).Return(&models.Category{
 					ID:          1,
 					Name:        "Test Category",
 					Description: models.StringPtr("A category for testing"),
@@ -79,11 +113,13 @@ func TestCreateCategory(t *testing.T) {
 			},
 			expectedStatus: http.StatusCreated,
 			expectedBody: map[string]interface{}{
-				"created_at":  "0001-01-01T00:00:00Z",
-				"id":          float64(1),
-				"name":        "Test Category",
-				"description": "A category for testing",
-				"updated_at":  "0001-01-01T00:00:00Z",
+				"created_at":     "0001-01-01T00:00:00Z",
+				"id":             float64(1),
+				"name":           "Test Category",
+				"description":    "A category for testing",
+				"min_age_months": nil,
+				"max_age_months": nil,
+				"updated_at":     "0001-01-01T00:00:00Z",
 			},
 		},
 	}
@@ -119,7 +155,7 @@ func TestCreateCategory(t *testing.T) {
 			Description: models.StringPtr("Description"),
 		}
 
-		mockCategoryService.On("CreateCategory", mock.AnythingOfType("*models.Category")).Return(nil, errors.New("database error")).Once()
+		mockCategoryService.On("CreateCategory", mock.Anything, mock.AnythingOfType("*models.Category")).Return(nil, errors.New("database error")).Once()
 
 		body, _ := json.Marshal(category)
 		req := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewBuffer(body))
@@ -141,7 +177,7 @@ func TestCreateCategory(t *testing.T) {
 			Description: models.StringPtr("Description"),
 		}
 
-		mockCategoryService.On("CreateCategory", mock.AnythingOfType("*models.Category")).Return(nil, services.ErrInvalidInput).Once()
+		mockCategoryService.On("CreateCategory", mock.Anything, mock.AnythingOfType("*models.Category")).Return(nil, services.ErrInvalidInput).Once()
 
 		body, _ := json.Marshal(category)
 		req := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewBuffer(body))
@@ -182,7 +218,7 @@ func TestGetAllCategories(t *testing.T) {
 		{
 			name: "Successful Retrieval",
 			setupMocks: func() {
-				mockCategoryService.On("GetAllCategories").Return([]models.Category{
+				mockCategoryService.On("GetAllCategories", mock.Anything, false).Return([]models.Category{
 					{ID: 1, Name: "Category A", Description: models.StringPtr("Desc A")},
 					{ID: 2, Name: "Category B", Description: models.StringPtr("Desc B")},
 				}, nil).Once()
@@ -196,7 +232,7 @@ func TestGetAllCategories(t *testing.T) {
 		{
 			name: "Internal Server Error",
 			setupMocks: func() {
-				mockCategoryService.On("GetAllCategories").Return([]models.Category{}, errors.New("database error")).Once()
+				mockCategoryService.On("GetAllCategories", mock.Anything, false).Return([]models.Category{}, errors.New("database error")).Once()
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   nil, // Body will be an error message string
@@ -229,6 +265,69 @@ func TestGetAllCategories(t *testing.T) {
 	}
 }
 
+func TestGetCategoryByID(t *testing.T) {
+	tests := []struct {
+		name           string
+		categoryID     string
+		setupMocks     func(mockCategoryService *MockCategoryService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "Successful Retrieval",
+			categoryID: "1",
+			setupMocks: func(mockCategoryService *MockCategoryService) {
+				mockCategoryService.On("GetCategoryByID", mock.Anything, 1).Return(&models.Category{ID: 1, Name: "Category A"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"name":"Category A"`,
+		},
+		{
+			name:           "Invalid ID",
+			categoryID:     "abc",
+			setupMocks:     func(mockCategoryService *MockCategoryService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid category ID",
+		},
+		{
+			name:       "Not Found",
+			categoryID: "99",
+			setupMocks: func(mockCategoryService *MockCategoryService) {
+				mockCategoryService.On("GetCategoryByID", mock.Anything, 99).Return(nil, services.ErrNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Category not found",
+		},
+		{
+			name:       "Internal Server Error",
+			categoryID: "1",
+			setupMocks: func(mockCategoryService *MockCategoryService) {
+				mockCategoryService.On("GetCategoryByID", mock.Anything, 1).Return(nil, errors.New("database error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCategoryService := new(MockCategoryService)
+			handler := NewCategoryHandler(mockCategoryService)
+			tt.setupMocks(mockCategoryService)
+
+			req := httptest.NewRequest(http.MethodGet, "/categories/"+tt.categoryID, nil)
+			req.SetPathValue("category_id", tt.categoryID)
+			rr := httptest.NewRecorder()
+
+			handler.GetCategoryByID(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			mockCategoryService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUpdateCategory(t *testing.T) {
 	t.Run("Successful Update", func(t *testing.T) {
 		mockCategoryService := new(MockCategoryService)
@@ -240,7 +339,7 @@ func TestUpdateCategory(t *testing.T) {
 			Description: models.StringPtr("Updated description"),
 		}
 
-		mockCategoryService.On("UpdateCategory", mock.AnythingOfType("*models.Category")).Return(nil).Once()
+		mockCategoryService.On("UpdateCategory", mock.Anything, mock.AnythingOfType("*models.Category")).Return(nil).Once()
 
 		body, _ := json.Marshal(inputCategory)
 		req := httptest.NewRequest(http.MethodPut, "/categories/"+strconv.Itoa(categoryID), bytes.NewBuffer(body))
@@ -269,7 +368,7 @@ func TestUpdateCategory(t *testing.T) {
 			Name: "Non Existent",
 		}
 
-		mockCategoryService.On("UpdateCategory", mock.AnythingOfType("*models.Category")).Return(services.ErrNotFound).Once()
+		mockCategoryService.On("UpdateCategory", mock.Anything, mock.AnythingOfType("*models.Category")).Return(services.ErrNotFound).Once()
 
 		body, _ := json.Marshal(inputCategory)
 		req := httptest.NewRequest(http.MethodPut, "/categories/"+strconv.Itoa(categoryID), bytes.NewBuffer(body))
@@ -295,7 +394,7 @@ func TestUpdateCategory(t *testing.T) {
 			Name: "",
 		}
 
-		mockCategoryService.On("UpdateCategory", mock.AnythingOfType("*models.Category")).Return(services.ErrInvalidInput).Once()
+		mockCategoryService.On("UpdateCategory", mock.Anything, mock.AnythingOfType("*models.Category")).Return(services.ErrInvalidInput).Once()
 
 		body, _ := json.Marshal(inputCategory)
 		req := httptest.NewRequest(http.MethodPut, "/categories/"+strconv.Itoa(categoryID), bytes.NewBuffer(body))
@@ -321,7 +420,7 @@ func TestUpdateCategory(t *testing.T) {
 			Name: "Error Category",
 		}
 
-		mockCategoryService.On("UpdateCategory", mock.AnythingOfType("*models.Category")).Return(errors.New("database error")).Once()
+		mockCategoryService.On("UpdateCategory", mock.Anything, mock.AnythingOfType("*models.Category")).Return(errors.New("database error")).Once()
 
 		body, _ := json.Marshal(inputCategory)
 		req := httptest.NewRequest(http.MethodPut, "/categories/"+strconv.Itoa(categoryID), bytes.NewBuffer(body))
@@ -377,7 +476,7 @@ func TestDeleteCategory(t *testing.T) {
 		handler := NewCategoryHandler(mockCategoryService)
 
 		categoryID := 1
-		mockCategoryService.On("DeleteCategory", categoryID).Return(nil).Once()
+		mockCategoryService.On("DeleteCategory", mock.Anything, categoryID).Return(nil).Once()
 
 		req := httptest.NewRequest(http.MethodDelete, "/categories/"+strconv.Itoa(categoryID), nil)
 		req = req.WithContext(req.Context())
@@ -400,7 +499,7 @@ func TestDeleteCategory(t *testing.T) {
 		handler := NewCategoryHandler(mockCategoryService)
 
 		categoryID := 99
-		mockCategoryService.On("DeleteCategory", categoryID).Return(services.ErrNotFound).Once()
+		mockCategoryService.On("DeleteCategory", mock.Anything, categoryID).Return(services.ErrNotFound).Once()
 
 		req := httptest.NewRequest(http.MethodDelete, "/categories/"+strconv.Itoa(categoryID), nil)
 		req = req.WithContext(req.Context())
@@ -420,7 +519,7 @@ func TestDeleteCategory(t *testing.T) {
 		handler := NewCategoryHandler(mockCategoryService)
 
 		categoryID := 1
-		mockCategoryService.On("DeleteCategory", categoryID).Return(errors.New("database error")).Once()
+		mockCategoryService.On("DeleteCategory", mock.Anything, categoryID).Return(errors.New("database error")).Once()
 
 		req := httptest.NewRequest(http.MethodDelete, "/categories/"+strconv.Itoa(categoryID), nil)
 		req = req.WithContext(req.Context())
@@ -451,3 +550,104 @@ func TestDeleteCategory(t *testing.T) {
 		mockCategoryService.AssertExpectations(t)
 	})
 }
+
+func TestGetAllCategories_IncludeArchived(t *testing.T) {
+	mockCategoryService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockCategoryService)
+	mockCategoryService.On("GetAllCategories", mock.Anything, true).Return([]models.Category{{ID: 1, Name: "Category A"}}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/categories?include_archived=true", nil)
+	rr := httptest.NewRecorder()
+
+	handler.GetAllCategories(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockCategoryService.AssertExpectations(t)
+
+	req = httptest.NewRequest(http.MethodGet, "/categories?include_archived=maybe", nil)
+	rr = httptest.NewRecorder()
+
+	handler.GetAllCategories(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestArchiveCategory(t *testing.T) {
+	t.Run("Successful Archiving", func(t *testing.T) {
+		mockCategoryService := new(MockCategoryService)
+		handler := NewCategoryHandler(mockCategoryService)
+		mockCategoryService.On("ArchiveCategory", mock.Anything, 1).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/categories/1/archive", nil)
+		req.SetPathValue("category_id", "1")
+		rr := httptest.NewRecorder()
+
+		handler.ArchiveCategory(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Category archived successfully")
+		mockCategoryService.AssertExpectations(t)
+	})
+
+	t.Run("Category Not Found", func(t *testing.T) {
+		mockCategoryService := new(MockCategoryService)
+		handler := NewCategoryHandler(mockCategoryService)
+		mockCategoryService.On("RestoreCategory", mock.Anything, 99).Return(services.ErrNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/categories/99/restore", nil)
+		req.SetPathValue("category_id", "99")
+		rr := httptest.NewRecorder()
+
+		handler.RestoreCategory(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockCategoryService.AssertExpectations(t)
+	})
+}
+
+func TestReassignEntries(t *testing.T) {
+	t.Run("Successful Reassignment", func(t *testing.T) {
+		mockCategoryService := new(MockCategoryService)
+		handler := NewCategoryHandler(mockCategoryService)
+		mockCategoryService.On("ReassignEntries", mock.Anything, 1, 2).Return(3, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/categories/1/reassign-entries", strings.NewReader(`{"target_category_id": 2}`))
+		req.SetPathValue("category_id", "1")
+		rr := httptest.NewRecorder()
+
+		handler.ReassignEntries(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"reassigned_entries": 3}`, rr.Body.String())
+		mockCategoryService.AssertExpectations(t)
+	})
+
+	t.Run("Archived Target", func(t *testing.T) {
+		mockCategoryService := new(MockCategoryService)
+		handler := NewCategoryHandler(mockCategoryService)
+		mockCategoryService.On("ReassignEntries", mock.Anything, 1, 2).Return(0, services.ErrCategoryArchived).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/categories/1/reassign-entries", strings.NewReader(`{"target_category_id": 2}`))
+		req.SetPathValue("category_id", "1")
+		rr := httptest.NewRecorder()
+
+		handler.ReassignEntries(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Contains(t, rr.Body.String(), services.CodeCategoryArchived)
+	})
+
+	t.Run("Missing Target", func(t *testing.T) {
+		mockCategoryService := new(MockCategoryService)
+		handler := NewCategoryHandler(mockCategoryService)
+
+		req := httptest.NewRequest(http.MethodPost, "/categories/1/reassign-entries", strings.NewReader(`{}`))
+		req.SetPathValue("category_id", "1")
+		rr := httptest.NewRecorder()
+
+		handler.ReassignEntries(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockCategoryService.AssertNotCalled(t, "ReassignEntries", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
@@ -30,8 +33,11 @@ func (childHandler *ChildHandler) CreateChild(writer http.ResponseWriter, reques
 		return
 	}
 
-	createdChild, err := childHandler.ChildService.CreateChild(&child)
+	createdChild, err := childHandler.ChildService.CreateChild(request.Context(), &child)
 	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
 		if err == services.ErrInvalidInput {
 			logger.Errorf("Invalid child data provided: %v", err)
 			http.Error(writer, "Invalid child data provided", http.StatusBadRequest)
@@ -42,7 +48,7 @@ func (childHandler *ChildHandler) CreateChild(writer http.ResponseWriter, reques
 		return
 	}
 
-	writer.WriteHeader(http.StatusCreated)
+	writeCreatedHeader(writer, "/api/v1/children", createdChild.ID)
 	if err := json.NewEncoder(writer).Encode(createdChild); err != nil {
 		logger.Errorf("Failed to encode response: %v", err)
 		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
@@ -50,10 +56,44 @@ func (childHandler *ChildHandler) CreateChild(writer http.ResponseWriter, reques
 	}
 }
 
-// GetAllChildren handles fetching all children.
+// childFilters are the query parameters that narrow down the children of a list or count.
+var childFilters = []listFilter{
+	boolFilter("is_preschooler", "is_preschooler"),
+	idFilter("group_id", "group_id"),
+}
+
+// GetAllChildren handles fetching all children. The list view columns are added with
+// ?include=group,current_teacher,last_observation, see models.ChildIncludes.
+// is_preschooler, group_id, sort, limit and offset narrow down, sort and paginate the list, see parseListQuery.
 func (childHandler *ChildHandler) GetAllChildren(writer http.ResponseWriter, request *http.Request) {
 	logger := middleware.GetLoggerWithReqID(request.Context())
-	children, err := childHandler.ChildService.GetAllChildren()
+
+	var includes []string
+	if includeParam := request.URL.Query().Get("include"); includeParam != "" {
+		for include := range strings.SplitSeq(includeParam, ",") {
+			include = strings.TrimSpace(include)
+			if !slices.Contains(models.ChildIncludes, include) {
+				logger.Warnf("Unknown include for GetAllChildren: %q", include)
+				http.Error(writer, fmt.Sprintf("Unknown include %q, must be one of %s", include, strings.Join(models.ChildIncludes, ", ")), http.StatusBadRequest)
+				return
+			}
+			includes = append(includes, include)
+		}
+	}
+
+	query, ok := parseListQuery(writer, request, []string{"id", "admission_date", "expected_school_enrollment", "created_at"}, childFilters...)
+	if !ok {
+		return
+	}
+
+	var children any
+	var err error
+	if len(includes) == 0 {
+		children, err = childHandler.ChildService.GetAllChildren(request.Context(), query)
+	} else {
+		// Every include is a single batched lookup in the service, not one call per child.
+		children, err = childHandler.ChildService.GetAllChildrenWithIncludes(request.Context(), includes, query)
+	}
 	if err != nil {
 		logger.Errorf("Failed to get all children: %v", err)
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
@@ -67,6 +107,29 @@ func (childHandler *ChildHandler) GetAllChildren(writer http.ResponseWriter, req
 	}
 }
 
+// CountChildren handles counting the children, narrowed down by is_preschooler and group_id like the list.
+func (childHandler *ChildHandler) CountChildren(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	query, ok := parseCountQuery(writer, request, childFilters...)
+	if !ok {
+		return
+	}
+
+	count, err := childHandler.ChildService.CountChildren(request.Context(), query)
+	if err != nil {
+		logger.Errorf("Failed to count children: %v", err)
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeCount(writer, count); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
 // GetChildByID handles fetching a child by ID.
 func (childHandler *ChildHandler) GetChildByID(writer http.ResponseWriter, request *http.Request) {
 	logger := middleware.GetLoggerWithReqID(request.Context())
@@ -78,7 +141,7 @@ func (childHandler *ChildHandler) GetChildByID(writer http.ResponseWriter, reque
 		return
 	}
 
-	child, err := childHandler.ChildService.GetChildByID(id)
+	child, err := childHandler.ChildService.GetChildByID(request.Context(), id)
 	if err != nil {
 		if err == services.ErrNotFound {
 			logger.Errorf("Child not found: %d", id)
@@ -117,8 +180,11 @@ func (childHandler *ChildHandler) UpdateChild(writer http.ResponseWriter, reques
 
 	child.ID = id
 
-	err = childHandler.ChildService.UpdateChild(&child)
+	err = childHandler.ChildService.UpdateChild(request.Context(), &child)
 	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
 		if err == services.ErrNotFound {
 			logger.Errorf("Child not found: %d", child.ID)
 			http.Error(writer, "Child not found", http.StatusNotFound)
@@ -153,7 +219,7 @@ func (childHandler *ChildHandler) DeleteChild(writer http.ResponseWriter, reques
 		return
 	}
 
-	err = childHandler.ChildService.DeleteChild(id)
+	err = childHandler.ChildService.DeleteChild(request.Context(), id)
 	if err != nil {
 		switch err {
 		case services.ErrNotFound:
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// ChildDocumentHandler handles the HTTP requests for the scanned paperwork of children.
+type ChildDocumentHandler struct {
+	ChildDocumentService   services.ChildDocumentService
+	UploadScanService      services.UploadScanService
+	ResumableUploadService services.ResumableUploadService
+}
+
+// NewChildDocumentHandler creates a new ChildDocumentHandler.
+func NewChildDocumentHandler(
+	childDocumentService services.ChildDocumentService,
+	uploadScanService services.UploadScanService,
+	resumableUploadService services.ResumableUploadService,
+) *ChildDocumentHandler {
+	return &ChildDocumentHandler{
+		ChildDocumentService:   childDocumentService,
+		UploadScanService:      uploadScanService,
+		ResumableUploadService: resumableUploadService,
+	}
+}
+
+// UploadDocument handles uploading a scanned PDF of a child as the "document" field of a multipart form.
+// The "document_type" field is required, "expires_on" is an optional date given as YYYY-MM-DD. Instead of the
+// document, the "upload_id" of a completed resumable upload can be sent. A document encrypted by the client is
+// sent with its "envelope" as JSON.
+func (handler *ChildDocumentHandler) UploadDocument(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for UploadDocument handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	childID, ok := parsePathID(writer, request, "child_id", "UploadDocument")
+	if !ok {
+		return
+	}
+
+	// The form overhead is allowed on top of the document itself.
+	request.Body = http.MaxBytesReader(writer, request.Body, services.MaxChildDocumentSize+(64<<10))
+	resumed, ok := resumedUpload(writer, request, handler.ResumableUploadService, models.UploadKindChildDocument)
+	if !ok {
+		return
+	}
+	var fileName string
+	var content []byte
+	if resumed != nil {
+		fileName, content = resumed.FileName, resumed.Content
+	} else {
+		file, header, err := request.FormFile("document")
+		if err != nil {
+			http.Error(writer, "Error retrieving document file: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close() //nolint:errcheck
+
+		fileName = header.Filename
+		content, err = io.ReadAll(file)
+		if err != nil {
+			http.Error(writer, "Failed to read document file", http.StatusBadRequest)
+			return
+		}
+	}
+	if !scanUpload(writer, request, handler.UploadScanService, models.UploadKindChildDocument, fileName, content) {
+		return
+	}
+	envelope, ok := attachmentEnvelope(writer, request)
+	if !ok {
+		return
+	}
+	document := models.ChildDocument{
+		ChildID:      childID,
+		DocumentType: request.FormValue("document_type"),
+		FileName:     filepath.Base(fileName),
+		Content:      content,
+		Envelope:     envelope,
+	}
+	if expiresOnStr := request.FormValue("expires_on"); expiresOnStr != "" {
+		expiresOn, err := models.ParseDate(expiresOnStr)
+		if err != nil {
+			logger.WithField("expires_on_str", expiresOnStr).WithError(err).Warn("Invalid expiry date for UploadDocument")
+			http.Error(writer, "Invalid expiry date, must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		document.ExpiresOn = &expiresOn
+	}
+
+	created, err := handler.ChildDocumentService.UploadDocument(logger, request.Context(), &document, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "Invalid document, a PDF of at most 10 MB is required", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error uploading child document")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	releaseResumedUpload(request, handler.ResumableUploadService, resumed)
+
+	writeCreatedHeader(writer, "/api/v1/child-documents", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UploadDocument")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetChildDocuments handles listing the documents of a child.
+func (handler *ChildDocumentHandler) GetChildDocuments(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetChildDocuments")
+	if !ok {
+		return
+	}
+
+	documents, err := handler.ChildDocumentService.GetChildDocuments(logger, request.Context(), childID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching child documents")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(documents); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetChildDocuments")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetDocumentByID handles fetching a document by ID without its PDF.
+func (handler *ChildDocumentHandler) GetDocumentByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	documentID, ok := parsePathID(writer, request, "document_id", "GetDocumentByID")
+	if !ok {
+		return
+	}
+
+	document, err := handler.ChildDocumentService.GetDocumentByID(logger, request.Context(), documentID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("document_id", documentID).Error("Internal server error fetching child document")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(document); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetDocumentByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetDocumentContent handles downloading the PDF of a document under its original file name. Documents encrypted
+// by the client are served as the uploaded ciphertext.
+func (handler *ChildDocumentHandler) GetDocumentContent(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	documentID, ok := parsePathID(writer, request, "document_id", "GetDocumentContent")
+	if !ok {
+		return
+	}
+
+	document, err := handler.ChildDocumentService.GetDocumentContent(logger, request.Context(), documentID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("document_id", documentID).Error("Internal server error fetching child document content")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", document.ContentType)
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+	writer.Header().Set("Content-Length", strconv.Itoa(len(document.Content)))
+	if _, err := writer.Write(document.Content); err != nil {
+		logger.WithError(err).Error("Failed to write response for GetDocumentContent")
+		return
+	}
+}
+
+// UpdateDocument handles changing the type, the file name and the expiry date of a document.
+func (handler *ChildDocumentHandler) UpdateDocument(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	documentID, ok := parsePathID(writer, request, "document_id", "UpdateDocument")
+	if !ok {
+		return
+	}
+
+	var document models.ChildDocument
+	if err := json.NewDecoder(request.Body).Decode(&document); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateDocument")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	document.ID = documentID
+
+	if err := handler.ChildDocumentService.UpdateDocument(logger, request.Context(), &document); err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("document_id", documentID).Error("Internal server error updating child document")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Document updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateDocument")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteDocument handles deleting a document.
+func (handler *ChildDocumentHandler) DeleteDocument(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	documentID, ok := parsePathID(writer, request, "document_id", "DeleteDocument")
+	if !ok {
+		return
+	}
+
+	if err := handler.ChildDocumentService.DeleteDocument(logger, request.Context(), documentID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("document_id", documentID).Error("Internal server error deleting child document")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetReminders handles listing the missing, expired and expiring documents of the children in care. The optional
+// "within_days" query parameter is how many days ahead expiring documents are listed, 30 by default.
+func (handler *ChildDocumentHandler) GetReminders(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	withinDays := services.DefaultDocumentReminderDays
+	if withinDaysStr := request.URL.Query().Get("within_days"); withinDaysStr != "" {
+		var err error
+		withinDays, err = strconv.Atoi(withinDaysStr)
+		if err != nil || withinDays < 0 {
+			logger.WithField("within_days_str", withinDaysStr).Warn("Invalid within_days for GetReminders")
+			http.Error(writer, "Invalid within_days, must be a non-negative number of days", http.StatusBadRequest)
+			return
+		}
+	}
+
+	reminders, err := handler.ChildDocumentService.GetReminders(logger, request.Context(), withinDays)
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching document reminders")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(reminders); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetReminders")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
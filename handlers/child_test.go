@@ -31,7 +31,7 @@ func TestCreateChild(t *testing.T) {
 		inputChild := models.Child{
 			FirstName:                "Test",
 			LastName:                 "Child",
-			Birthdate:                time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate:                models.NewDate(2020, 1, 1),
 			AdmissionDate:            timePtr(time.Now()),
 			ExpectedSchoolEnrollment: timePtr(time.Now().AddDate(1, 0, 0)),
 		}
@@ -40,14 +40,14 @@ func TestCreateChild(t *testing.T) {
 			ID:                       1,
 			FirstName:                "Test",
 			LastName:                 "Child",
-			Birthdate:                time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate:                models.NewDate(2020, 1, 1),
 			AdmissionDate:            inputChild.AdmissionDate,
 			ExpectedSchoolEnrollment: inputChild.ExpectedSchoolEnrollment,
 			CreatedAt:                time.Now(),
 			UpdatedAt:                time.Now(),
 		}
 
-		mockChildService.On("CreateChild", mock.AnythingOfType("*models.Child")).Return(&returnedChild, nil).Once()
+		mockChildService.On("CreateChild", mock.Anything, mock.AnythingOfType("*models.Child")).Return(&returnedChild, nil).Once()
 
 		body, _ := json.Marshal(inputChild)
 		req := httptest.NewRequest(http.MethodPost, "/children", bytes.NewBuffer(body))
@@ -56,6 +56,7 @@ func TestCreateChild(t *testing.T) {
 		handler.CreateChild(rr, req)
 
 		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "/api/v1/children/1", rr.Header().Get("Location"))
 
 		var responseBody models.Child
 		json.Unmarshal(rr.Body.Bytes(), &responseBody) //nolint:errcheck
@@ -64,8 +65,7 @@ func TestCreateChild(t *testing.T) {
 		assert.Equal(t, "Test", responseBody.FirstName)
 		assert.Equal(t, "Child", responseBody.LastName)
 
-		expectedTime, _ := time.Parse(time.RFC3339, "2020-01-01T00:00:00Z")
-		assert.True(t, expectedTime.Equal(responseBody.Birthdate), "Birthdate mismatch")
+		assert.Equal(t, models.NewDate(2020, 1, 1), responseBody.Birthdate)
 
 		assert.NotNil(t, responseBody.CreatedAt)
 		assert.NotNil(t, responseBody.UpdatedAt)
@@ -80,7 +80,7 @@ func TestCreateChild(t *testing.T) {
 		inputChild := models.Child{
 			FirstName: "",
 		}
-		mockChildService.On("CreateChild", mock.AnythingOfType("*models.Child")).Return(nil, services.ErrInvalidInput).Once()
+		mockChildService.On("CreateChild", mock.Anything, mock.AnythingOfType("*models.Child")).Return(nil, services.ErrInvalidInput).Once()
 
 		body, _ := json.Marshal(inputChild)
 		req := httptest.NewRequest(http.MethodPost, "/children", bytes.NewBuffer(body))
@@ -94,15 +94,38 @@ func TestCreateChild(t *testing.T) {
 		mockChildService.AssertExpectations(t)
 	})
 
+	t.Run("Invalid Fields", func(t *testing.T) {
+		mockChildService := new(mocks.MockChildService)
+		handler := NewChildHandler(mockChildService)
+
+		validationError := &services.ValidationError{Violations: []services.RuleViolation{
+			{Rule: "required", Field: "first_name", Message: "first_name is required", Text: "Dieses Feld ist erforderlich."},
+		}}
+		mockChildService.On("CreateChild", mock.Anything, mock.AnythingOfType("*models.Child")).Return(nil, validationError).Once()
+
+		body, _ := json.Marshal(models.Child{})
+		req := httptest.NewRequest(http.MethodPost, "/children", bytes.NewBuffer(body))
+		rr := httptest.NewRecorder()
+
+		handler.CreateChild(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"error": "validation failed", "code": "VALIDATION_FAILED", "violations": [
+			{"rule": "required", "field": "first_name", "message": "first_name is required", "text": "Dieses Feld ist erforderlich."}
+		]}`, rr.Body.String())
+
+		mockChildService.AssertExpectations(t)
+	})
+
 	t.Run("Internal Server Error", func(t *testing.T) {
 		mockChildService := new(mocks.MockChildService)
 		handler := NewChildHandler(mockChildService)
 
 		inputChild := models.Child{
 			FirstName: "Error",
-			Birthdate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate: models.NewDate(2020, 1, 1),
 		}
-		mockChildService.On("CreateChild", mock.AnythingOfType("*models.Child")).Return(nil, errors.New("database error")).Once()
+		mockChildService.On("CreateChild", mock.Anything, mock.AnythingOfType("*models.Child")).Return(nil, errors.New("database error")).Once()
 
 		body, _ := json.Marshal(inputChild)
 		req := httptest.NewRequest(http.MethodPost, "/children", bytes.NewBuffer(body))
@@ -136,9 +159,9 @@ func TestGetAllChildren(t *testing.T) {
 		mockChildService := new(mocks.MockChildService)
 		handler := NewChildHandler(mockChildService)
 
-		mockChildService.On("GetAllChildren").Return([]models.Child{
-			{ID: 1, FirstName: "Child A", Birthdate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
-			{ID: 2, FirstName: "Child B", Birthdate: time.Date(2022, 2, 2, 0, 0, 0, 0, time.UTC)},
+		mockChildService.On("GetAllChildren", mock.Anything, models.ListQuery{}).Return([]models.Child{
+			{ID: 1, FirstName: "Child A", Birthdate: models.NewDate(2021, 1, 1)},
+			{ID: 2, FirstName: "Child B", Birthdate: models.NewDate(2022, 2, 2)},
 		}, nil).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/children", nil)
@@ -151,8 +174,8 @@ func TestGetAllChildren(t *testing.T) {
 		var responseBody []models.Child
 		json.Unmarshal(rr.Body.Bytes(), &responseBody) //nolint:errcheck
 		assert.Equal(t, []models.Child{
-			{ID: 1, FirstName: "Child A", Birthdate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
-			{ID: 2, FirstName: "Child B", Birthdate: time.Date(2022, 2, 2, 0, 0, 0, 0, time.UTC)},
+			{ID: 1, FirstName: "Child A", Birthdate: models.NewDate(2021, 1, 1)},
+			{ID: 2, FirstName: "Child B", Birthdate: models.NewDate(2022, 2, 2)},
 		}, responseBody)
 
 		mockChildService.AssertExpectations(t)
@@ -162,7 +185,7 @@ func TestGetAllChildren(t *testing.T) {
 		mockChildService := new(mocks.MockChildService)
 		handler := NewChildHandler(mockChildService)
 
-		mockChildService.On("GetAllChildren").Return([]models.Child{}, errors.New("database error")).Once()
+		mockChildService.On("GetAllChildren", mock.Anything, models.ListQuery{}).Return([]models.Child{}, errors.New("database error")).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/children", nil)
 		rr := httptest.NewRecorder()
@@ -174,6 +197,91 @@ func TestGetAllChildren(t *testing.T) {
 
 		mockChildService.AssertExpectations(t)
 	})
+
+	t.Run("With Includes", func(t *testing.T) {
+		mockChildService := new(mocks.MockChildService)
+		handler := NewChildHandler(mockChildService)
+
+		lastObservation := models.NewDate(2025, 3, 1)
+		mockChildService.On("GetAllChildrenWithIncludes", mock.Anything, []string{"group", "last_observation"}, models.ListQuery{}).Return([]models.ChildListItem{
+			{Child: models.Child{ID: 1, FirstName: "Child A"}, Group: &models.ChildGroupSummary{ID: 2, Name: "Igel"}, LastObservationDate: &lastObservation},
+			{Child: models.Child{ID: 2, FirstName: "Child B"}},
+		}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/children?include=group,%20last_observation", nil)
+		rr := httptest.NewRecorder()
+
+		handler.GetAllChildren(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var responseBody []map[string]any
+		json.Unmarshal(rr.Body.Bytes(), &responseBody) //nolint:errcheck
+		assert.Equal(t, map[string]any{"id": float64(2), "name": "Igel"}, responseBody[0]["group"])
+		assert.Equal(t, "2025-03-01", responseBody[0]["last_observation_date"])
+		assert.NotContains(t, responseBody[1], "group")
+		mockChildService.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Include", func(t *testing.T) {
+		mockChildService := new(mocks.MockChildService)
+		handler := NewChildHandler(mockChildService)
+
+		req := httptest.NewRequest(http.MethodGet, "/children?include=parents", nil)
+		rr := httptest.NewRecorder()
+
+		handler.GetAllChildren(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), `Unknown include "parents"`)
+		mockChildService.AssertNotCalled(t, "GetAllChildren", mock.Anything)
+	})
+}
+
+func TestCountChildren(t *testing.T) {
+	t.Run("Successful Count", func(t *testing.T) {
+		mockChildService := new(mocks.MockChildService)
+		handler := NewChildHandler(mockChildService)
+
+		mockChildService.On("CountChildren", mock.Anything, models.ListQuery{}.Where("group_id", models.OperatorEqual, 3)).Return(12, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/children/count?group_id=3", nil)
+		rr := httptest.NewRecorder()
+
+		handler.CountChildren(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "12", rr.Header().Get("X-Total-Count"))
+		assert.JSONEq(t, `{"count": 12}`, rr.Body.String())
+		mockChildService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Filter", func(t *testing.T) {
+		mockChildService := new(mocks.MockChildService)
+		handler := NewChildHandler(mockChildService)
+
+		req := httptest.NewRequest(http.MethodGet, "/children/count?group_id=abc", nil)
+		rr := httptest.NewRecorder()
+
+		handler.CountChildren(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockChildService.AssertNotCalled(t, "CountChildren", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Internal Server Error", func(t *testing.T) {
+		mockChildService := new(mocks.MockChildService)
+		handler := NewChildHandler(mockChildService)
+
+		mockChildService.On("CountChildren", mock.Anything, models.ListQuery{}).Return(0, errors.New("database error")).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/children/count", nil)
+		rr := httptest.NewRecorder()
+
+		handler.CountChildren(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		mockChildService.AssertExpectations(t)
+	})
 }
 
 func TestGetChildByID(t *testing.T) {
@@ -181,10 +289,10 @@ func TestGetChildByID(t *testing.T) {
 		mockChildService := new(mocks.MockChildService)
 		handler := NewChildHandler(mockChildService)
 
-		mockChildService.On("GetChildByID", 1).Return(&models.Child{
+		mockChildService.On("GetChildByID", mock.Anything, 1).Return(&models.Child{
 			ID:        1,
 			FirstName: "Test Child",
-			Birthdate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate: models.NewDate(2020, 1, 1),
 		}, nil).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/children/1", nil)
@@ -202,8 +310,7 @@ func TestGetChildByID(t *testing.T) {
 		assert.Equal(t, 1, responseBody.ID)
 		assert.Equal(t, "Test Child", responseBody.FirstName)
 
-		expectedTime, _ := time.Parse(time.RFC3339, "2020-01-01T00:00:00Z")
-		assert.True(t, expectedTime.Equal(responseBody.Birthdate), "Birthdate mismatch")
+		assert.Equal(t, models.NewDate(2020, 1, 1), responseBody.Birthdate)
 
 		mockChildService.AssertExpectations(t)
 	})
@@ -212,7 +319,7 @@ func TestGetChildByID(t *testing.T) {
 		mockChildService := new(mocks.MockChildService)
 		handler := NewChildHandler(mockChildService)
 
-		mockChildService.On("GetChildByID", 99).Return(nil, services.ErrNotFound).Once()
+		mockChildService.On("GetChildByID", mock.Anything, 99).Return(nil, services.ErrNotFound).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/children/99", nil)
 		req = req.WithContext(req.Context())
@@ -231,7 +338,7 @@ func TestGetChildByID(t *testing.T) {
 		mockChildService := new(mocks.MockChildService)
 		handler := NewChildHandler(mockChildService)
 
-		mockChildService.On("GetChildByID", 1).Return(nil, errors.New("database error")).Once()
+		mockChildService.On("GetChildByID", mock.Anything, 1).Return(nil, errors.New("database error")).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/children/1", nil)
 		req = req.WithContext(req.Context())
@@ -272,9 +379,9 @@ func TestUpdateChild(t *testing.T) {
 		inputChild := models.Child{
 			FirstName: "Updated",
 			LastName:  "Child",
-			Birthdate: time.Date(2019, 5, 10, 0, 0, 0, 0, time.UTC),
+			Birthdate: models.NewDate(2019, 5, 10),
 		}
-		mockChildService.On("UpdateChild", mock.AnythingOfType("*models.Child")).Return(nil).Once()
+		mockChildService.On("UpdateChild", mock.Anything, mock.AnythingOfType("*models.Child")).Return(nil).Once()
 
 		body, _ := json.Marshal(inputChild)
 		req := httptest.NewRequest(http.MethodPut, "/children/"+strconv.Itoa(childID), bytes.NewBuffer(body))
@@ -302,7 +409,7 @@ func TestUpdateChild(t *testing.T) {
 		inputChild := models.Child{
 			FirstName: "Non Existent",
 		}
-		mockChildService.On("UpdateChild", mock.AnythingOfType("*models.Child")).Return(services.ErrNotFound).Once()
+		mockChildService.On("UpdateChild", mock.Anything, mock.AnythingOfType("*models.Child")).Return(services.ErrNotFound).Once()
 
 		body, _ := json.Marshal(inputChild)
 		req := httptest.NewRequest(http.MethodPut, "/children/"+strconv.Itoa(childID), bytes.NewBuffer(body))
@@ -327,7 +434,7 @@ func TestUpdateChild(t *testing.T) {
 		inputChild := models.Child{
 			FirstName: "",
 		}
-		mockChildService.On("UpdateChild", mock.AnythingOfType("*models.Child")).Return(services.ErrInvalidInput).Once()
+		mockChildService.On("UpdateChild", mock.Anything, mock.AnythingOfType("*models.Child")).Return(services.ErrInvalidInput).Once()
 
 		body, _ := json.Marshal(inputChild)
 		req := httptest.NewRequest(http.MethodPut, "/children/"+strconv.Itoa(childID), bytes.NewBuffer(body))
@@ -352,7 +459,7 @@ func TestUpdateChild(t *testing.T) {
 		inputChild := models.Child{
 			FirstName: "Error Child",
 		}
-		mockChildService.On("UpdateChild", mock.AnythingOfType("*models.Child")).Return(errors.New("database error")).Once()
+		mockChildService.On("UpdateChild", mock.Anything, mock.AnythingOfType("*models.Child")).Return(errors.New("database error")).Once()
 
 		body, _ := json.Marshal(inputChild)
 		req := httptest.NewRequest(http.MethodPut, "/children/"+strconv.Itoa(childID), bytes.NewBuffer(body))
@@ -408,7 +515,7 @@ func TestDeleteChild(t *testing.T) {
 		handler := NewChildHandler(mockChildService)
 
 		childID := 1
-		mockChildService.On("DeleteChild", childID).Return(nil).Once()
+		mockChildService.On("DeleteChild", mock.Anything, childID).Return(nil).Once()
 
 		req := httptest.NewRequest(http.MethodDelete, "/children/"+strconv.Itoa(childID), nil)
 		req = req.WithContext(req.Context())
@@ -431,7 +538,7 @@ func TestDeleteChild(t *testing.T) {
 		handler := NewChildHandler(mockChildService)
 
 		childID := 99
-		mockChildService.On("DeleteChild", childID).Return(services.ErrNotFound).Once()
+		mockChildService.On("DeleteChild", mock.Anything, childID).Return(services.ErrNotFound).Once()
 
 		req := httptest.NewRequest(http.MethodDelete, "/children/"+strconv.Itoa(childID), nil)
 		req = req.WithContext(req.Context())
@@ -451,7 +558,7 @@ func TestDeleteChild(t *testing.T) {
 		handler := NewChildHandler(mockChildService)
 
 		childID := 1
-		mockChildService.On("DeleteChild", childID).Return(errors.New("database error")).Once()
+		mockChildService.On("DeleteChild", mock.Anything, childID).Return(errors.New("database error")).Once()
 
 		req := httptest.NewRequest(http.MethodDelete, "/children/"+strconv.Itoa(childID), nil)
 		req = req.WithContext(req.Context())
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/services"
+)
+
+// CompletenessHandler handles documentation completeness HTTP requests.
+type CompletenessHandler struct {
+	CompletenessService services.CompletenessService
+}
+
+// NewCompletenessHandler creates a new CompletenessHandler.
+func NewCompletenessHandler(completenessService services.CompletenessService) *CompletenessHandler {
+	return &CompletenessHandler{CompletenessService: completenessService}
+}
+
+// GetChildCompleteness handles fetching the documentation completeness score of a child.
+func (handler *CompletenessHandler) GetChildCompleteness(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	childIDStr := request.PathValue("child_id")
+	childID, err := strconv.Atoi(childIDStr)
+	if err != nil {
+		logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for GetChildCompleteness")
+		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+		return
+	}
+
+	completeness, err := handler.CompletenessService.GetChildCompleteness(logger, request.Context(), childID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Child not found", http.StatusNotFound)
+			return
+		}
+		logger.WithField("child_id", childID).WithError(err).Error("Internal server error calculating completeness score")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(completeness); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetChildCompleteness")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAllCompleteness handles fetching the completeness scores of all active children for the dashboard.
+func (handler *CompletenessHandler) GetAllCompleteness(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	scores, err := handler.CompletenessService.GetAllCompleteness(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error calculating completeness scores")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(scores); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAllCompleteness")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetCategorySuggestions handles fetching the categories suggested for the next observation of a child.
+func (handler *CompletenessHandler) GetCategorySuggestions(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	childIDStr := request.PathValue("child_id")
+	childID, err := strconv.Atoi(childIDStr)
+	if err != nil {
+		logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for GetCategorySuggestions")
+		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+		return
+	}
+
+	suggestions, err := handler.CompletenessService.SuggestCategories(logger, request.Context(), childID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Child not found", http.StatusNotFound)
+			return
+		}
+		logger.WithField("child_id", childID).WithError(err).Error("Internal server error suggesting categories")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(suggestions); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetCategorySuggestions")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
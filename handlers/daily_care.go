@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// dailyCareDefaultDays is the number of days listed for a child when no range is given, today included.
+const dailyCareDefaultDays = 7
+
+// DailyCareHandler handles the HTTP requests for the daily care logs of U3 children.
+type DailyCareHandler struct {
+	DailyCareService services.DailyCareService
+	Clock            clock.Clock
+}
+
+// NewDailyCareHandler creates a new DailyCareHandler.
+func NewDailyCareHandler(dailyCareService services.DailyCareService, clock clock.Clock) *DailyCareHandler {
+	return &DailyCareHandler{DailyCareService: dailyCareService, Clock: clock}
+}
+
+// GetChildDailyCare handles listing the logs of a child between the from and to query parameters, by default the
+// last seven days. parents_view=true leaves out the logs hidden from parents.
+func (handler *DailyCareHandler) GetChildDailyCare(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetChildDailyCare")
+	if !ok {
+		return
+	}
+
+	params := request.URL.Query()
+	to := models.Today(handler.Clock.Now())
+	if toStr := params.Get("to"); toStr != "" {
+		parsed, err := time.Parse(time.DateOnly, toStr)
+		if err != nil {
+			http.Error(writer, "Invalid to date, must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = models.DateOf(parsed)
+	}
+	from := to.AddDays(-(dailyCareDefaultDays - 1))
+	if fromStr := params.Get("from"); fromStr != "" {
+		parsed, err := time.Parse(time.DateOnly, fromStr)
+		if err != nil {
+			http.Error(writer, "Invalid from date, must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		from = models.DateOf(parsed)
+	}
+	parentsView := params.Get("parents_view") == "true"
+
+	logs, err := handler.DailyCareService.GetChildDailyCare(logger, request.Context(), childID, from, to, parentsView)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, "from must not be after to", http.StatusBadRequest)
+		default:
+			logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching daily care")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(logs); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetChildDailyCare")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RecordDailyCare handles creating or replacing the log of a child on the day of the path.
+func (handler *DailyCareHandler) RecordDailyCare(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for RecordDailyCare handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	childID, ok := parsePathID(writer, request, "child_id", "RecordDailyCare")
+	if !ok {
+		return
+	}
+	day, ok := parsePathDate(writer, request)
+	if !ok {
+		return
+	}
+
+	var log models.DailyCareLog
+	if err := json.NewDecoder(request.Body).Decode(&log); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for RecordDailyCare")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	log.ChildID = childID
+	log.CareDate = day
+
+	recorded, err := handler.DailyCareService.RecordDailyCare(logger, request.Context(), &log, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		default:
+			logger.WithError(err).WithField("child_id", childID).Error("Internal server error recording daily care")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(recorded); err != nil {
+		logger.WithError(err).Error("Failed to encode response for RecordDailyCare")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteDailyCare handles deleting the log of a child on the day of the path.
+func (handler *DailyCareHandler) DeleteDailyCare(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "DeleteDailyCare")
+	if !ok {
+		return
+	}
+	day, ok := parsePathDate(writer, request)
+	if !ok {
+		return
+	}
+
+	err := handler.DailyCareService.DeleteDailyCare(logger, request.Context(), childID, day)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Daily care not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error during daily care deletion")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetGroupDailyCare handles listing the U3 children of a group with their logs of the day of the path.
+func (handler *DailyCareHandler) GetGroupDailyCare(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	groupID, ok := parsePathID(writer, request, "group_id", "GetGroupDailyCare")
+	if !ok {
+		return
+	}
+	day, ok := parsePathDate(writer, request)
+	if !ok {
+		return
+	}
+
+	care, err := handler.DailyCareService.GetGroupDailyCare(logger, request.Context(), groupID, day)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		if err == services.ErrNotFound {
+			http.Error(writer, "Group not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("group_id", groupID).Error("Internal server error fetching group daily care")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(care); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetGroupDailyCare")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RecordGroupDailyCare handles the bulk entry of the logs of children of a group on the day of the path. The body
+// is a list of logs with child IDs, all or none of them are saved.
+func (handler *DailyCareHandler) RecordGroupDailyCare(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for RecordGroupDailyCare handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	groupID, ok := parsePathID(writer, request, "group_id", "RecordGroupDailyCare")
+	if !ok {
+		return
+	}
+	day, ok := parsePathDate(writer, request)
+	if !ok {
+		return
+	}
+
+	var logs []models.DailyCareLog
+	if err := json.NewDecoder(request.Body).Decode(&logs); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for RecordGroupDailyCare")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	care, err := handler.DailyCareService.RecordGroupDailyCare(logger, request.Context(), groupID, day, logs, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Group not found", http.StatusNotFound)
+		default:
+			logger.WithError(err).WithField("group_id", groupID).Error("Internal server error recording group daily care")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(care); err != nil {
+		logger.WithError(err).Error("Failed to encode response for RecordGroupDailyCare")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// parsePathDate reads the date path value and answers with 400 if it is not YYYY-MM-DD.
+func parsePathDate(writer http.ResponseWriter, request *http.Request) (models.Date, bool) {
+	parsed, err := time.Parse(time.DateOnly, request.PathValue("date"))
+	if err != nil {
+		http.Error(writer, "Invalid date, must be YYYY-MM-DD", http.StatusBadRequest)
+		return models.Date{}, false
+	}
+	return models.DateOf(parsed), true
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/services"
+)
+
+// DemoModeHandler handles HTTP requests for switching a session into the demo mode.
+type DemoModeHandler struct {
+	DemoModeService services.DemoModeService
+}
+
+// NewDemoModeHandler creates a new DemoModeHandler.
+func NewDemoModeHandler(demoModeService services.DemoModeService) *DemoModeHandler {
+	return &DemoModeHandler{DemoModeService: demoModeService}
+}
+
+// GetStatus handles fetching the demo mode status of the current session.
+func (handler *DemoModeHandler) GetStatus(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	status := handler.DemoModeService.GetStatus(middleware.SessionID(request))
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(status); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetStatus")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Enable handles switching the current session to the anonymized demo dataset.
+// The optional body {"refresh": true} recreates the dataset from the current data.
+func (handler *DemoModeHandler) Enable(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var body struct {
+		Refresh bool `json:"refresh"`
+	}
+	if err := json.NewDecoder(request.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		logger.WithError(err).Warn("Invalid request payload for Enable")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	sessionID := middleware.SessionID(request)
+	if sessionID == "" {
+		logger.Warn("Demo mode requested for a token without a login session")
+		http.Error(writer, "Demo mode requires a login session", http.StatusBadRequest)
+		return
+	}
+
+	status, err := handler.DemoModeService.Enable(logger, request.Context(), sessionID, body.Refresh)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error enabling demo mode")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(status); err != nil {
+		logger.WithError(err).Error("Failed to encode response for Enable")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Disable handles switching the current session back to the production data.
+func (handler *DemoModeHandler) Disable(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	status := handler.DemoModeService.Disable(logger, request.Context(), middleware.SessionID(request))
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(status); err != nil {
+		logger.WithError(err).Error("Failed to encode response for Disable")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
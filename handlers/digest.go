@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// DigestHandler handles requests for the weekly documentation digest.
+type DigestHandler struct {
+	DigestService services.DigestService
+	Clock         clock.Clock
+}
+
+// NewDigestHandler creates a new DigestHandler.
+func NewDigestHandler(digestService services.DigestService, clock clock.Clock) *DigestHandler {
+	return &DigestHandler{DigestService: digestService, Clock: clock}
+}
+
+// GetDigest handles previewing the digest of the current user for the week of the date query parameter,
+// by default the current week.
+func (handler *DigestHandler) GetDigest(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for GetDigest handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	day := models.Today(handler.Clock.Now())
+	if dateStr := request.URL.Query().Get("date"); dateStr != "" {
+		parsed, err := time.Parse(time.DateOnly, dateStr)
+		if err != nil {
+			http.Error(writer, "Invalid date, must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		day = models.DateOf(parsed)
+	}
+
+	digest, err := handler.DigestService.GetDigest(logger, request.Context(), user, day)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error building documentation digest")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(digest); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetDigest")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
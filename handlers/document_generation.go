@@ -1,13 +1,18 @@
 package handlers
 
 import (
-	"context"
+	"archive/zip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 )
 
@@ -15,20 +20,41 @@ import (
 type DocumentGenerationHandler struct {
 	DocumentationEntryService services.DocumentationEntryService
 	AssignmentService         services.AssignmentService
+	RedactionProfileService   services.RedactionProfileService
+	CompletenessService       services.CompletenessService
+	SupportProviderService    services.SupportProviderService
+	GalleryService            services.GalleryService
+	Clock                     clock.Clock
 }
 
 // NewDocumentGenerationHandler creates a new DocumentGenerationHandler.
 func NewDocumentGenerationHandler(
 	documentationEntryService services.DocumentationEntryService,
 	assignmentService services.AssignmentService,
+	redactionProfileService services.RedactionProfileService,
+	completenessService services.CompletenessService,
+	supportProviderService services.SupportProviderService,
+	galleryService services.GalleryService,
+	clock clock.Clock,
 ) *DocumentGenerationHandler {
 	return &DocumentGenerationHandler{
 		DocumentationEntryService: documentationEntryService,
 		AssignmentService:         assignmentService,
+		RedactionProfileService:   redactionProfileService,
+		CompletenessService:       completenessService,
+		SupportProviderService:    supportProviderService,
+		GalleryService:            galleryService,
+		Clock:                     clock,
 	}
 }
 
+// busyRetryAfter is the Retry-After in seconds when too many reports are being generated.
+const busyRetryAfter = "10"
+
 // GenerateChildReport handles generating a child report.
+// The optional query parameter redaction_profile_id selects a redaction profile to apply,
+// include_completeness=true appends the completeness score as an internal appendix.
+// photo_ids, a comma-separated list, embeds gallery photos of the child with photo consent.
 func (handler *DocumentGenerationHandler) GenerateChildReport(writer http.ResponseWriter, request *http.Request) {
 	logger := middleware.GetLoggerWithReqID(request.Context())
 
@@ -42,27 +68,115 @@ func (handler *DocumentGenerationHandler) GenerateChildReport(writer http.Respon
 
 	logger.WithField("child_id", childID).Info("Generating child report")
 
-	// Use context for graceful shutdown and cancellation
-	ctx, cancel := context.WithCancel(request.Context())
-	defer cancel()
+	// The request context is cancelled when the client disconnects or the route timeout expires
+	ctx := request.Context()
+
+	var redaction *models.RedactionProfile
+	if profileIDStr := request.URL.Query().Get("redaction_profile_id"); profileIDStr != "" {
+		profileID, err := strconv.Atoi(profileIDStr)
+		if err != nil {
+			logger.WithField("redaction_profile_id_str", profileIDStr).WithError(err).Warn("Invalid redaction profile ID format for report generation")
+			http.Error(writer, "Invalid redaction profile ID", http.StatusBadRequest)
+			return
+		}
+		redaction, err = handler.RedactionProfileService.GetRedactionProfileByID(logger, ctx, profileID)
+		if err != nil {
+			if errors.Is(err, services.ErrNotFound) {
+				http.Error(writer, "Redaction profile not found", http.StatusNotFound)
+				return
+			}
+			logger.WithField("redaction_profile_id", profileID).WithError(err).Error("Internal server error during redaction profile retrieval")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
 
-	assignments, err := handler.AssignmentService.GetAssignmentHistoryForChild(childID)
+	var completeness *models.ChildCompleteness
+	if includeStr := request.URL.Query().Get("include_completeness"); includeStr != "" {
+		include, err := strconv.ParseBool(includeStr)
+		if err != nil {
+			logger.WithField("include_completeness_str", includeStr).WithError(err).Warn("Invalid include_completeness value for report generation")
+			http.Error(writer, "Invalid include_completeness value", http.StatusBadRequest)
+			return
+		}
+		if include {
+			completeness, err = handler.CompletenessService.GetChildCompleteness(logger, ctx, childID)
+			if err != nil {
+				if errors.Is(err, services.ErrNotFound) {
+					http.Error(writer, "Child not found", http.StatusNotFound)
+					return
+				}
+				logger.WithField("child_id", childID).WithError(err).Error("Internal server error during completeness calculation")
+				http.Error(writer, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	assignments, err := handler.AssignmentService.GetAssignmentHistoryForChild(ctx, childID, models.ListQuery{})
 	if err != nil {
-		if errors.Is(err, services.ErrNotFound) {
-			logger.WithField("child_id", childID).WithError(err).Warn("No assignments found for child")
+		if writeDomainError(writer, err) {
+			logger.WithField("child_id", childID).WithError(err).Warn("Child not found for report generation")
+			return
 		}
 		logger.WithField("child_id", childID).WithError(err).Error("Internal server error during assignment retrieval")
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	reportBytes, err := handler.DocumentationEntryService.GenerateChildReport(logger, ctx, childID, assignments)
+	var supports []models.ChildSupport
+	if redaction == nil || !redaction.HideSupportProviders {
+		supports, err = handler.SupportProviderService.GetChildSupports(logger, ctx, childID)
+		if err != nil {
+			if errors.Is(err, services.ErrNotFound) {
+				http.Error(writer, "Child not found", http.StatusNotFound)
+				return
+			}
+			logger.WithField("child_id", childID).WithError(err).Error("Internal server error during child support retrieval")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var photos []models.GalleryPhoto
+	if photoIDsStr := request.URL.Query().Get("photo_ids"); photoIDsStr != "" {
+		var photoIDs []int
+		for _, photoIDStr := range strings.Split(photoIDsStr, ",") {
+			photoID, err := strconv.Atoi(strings.TrimSpace(photoIDStr))
+			if err != nil {
+				logger.WithField("photo_ids_str", photoIDsStr).WithError(err).Warn("Invalid photo IDs for report generation")
+				http.Error(writer, "Invalid photo IDs", http.StatusBadRequest)
+				return
+			}
+			photoIDs = append(photoIDs, photoID)
+		}
+		photos, err = handler.GalleryService.GetReportPhotos(logger, ctx, childID, photoIDs)
+		if err != nil {
+			if writeDomainError(writer, err) {
+				return
+			}
+			logger.WithField("child_id", childID).WithError(err).Error("Internal server error during photo retrieval")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	reportBytes, err := handler.DocumentationEntryService.GenerateChildReport(logger, ctx, childID, assignments, redaction, completeness, supports, photos)
 	if err != nil {
 		if errors.Is(err, services.ErrNotFound) {
 			logger.WithField("child_id", childID).WithError(err).Warn("Child not found for report generation")
 			http.Error(writer, "Child not found", http.StatusNotFound)
 			return
 		}
+		if err == services.ErrCanceled {
+			// The timeout middleware answers timed out requests, disconnected clients get no response.
+			return
+		}
+		if errors.Is(err, services.ErrBusy) {
+			writer.Header().Set("Retry-After", busyRetryAfter)
+			http.Error(writer, "Too many reports are being generated, please try again shortly", http.StatusServiceUnavailable)
+			return
+		}
 		if err == services.ErrChildReportGenerationFailed {
 			logger.WithField("child_id", childID).WithError(err).Error("Failed to generate child report in service")
 			http.Error(writer, "Failed to generate child report", http.StatusInternalServerError)
@@ -74,7 +188,7 @@ func (handler *DocumentGenerationHandler) GenerateChildReport(writer http.Respon
 	}
 
 	logger.WithField("child_id", childID).Info("Child report generated successfully, sending for download")
-	documentName, err := handler.DocumentationEntryService.GetDocumentName(ctx, childID)
+	documentName, err := handler.DocumentationEntryService.GetDocumentName(ctx, childID, redaction)
 	if err != nil {
 		logger.WithField("child_id", childID).WithError(err).Error("Failed to retrieve child details for report")
 		http.Error(writer, "Failed to retrieve child details", http.StatusInternalServerError)
@@ -89,3 +203,132 @@ func (handler *DocumentGenerationHandler) GenerateChildReport(writer http.Respon
 		return
 	}
 }
+
+// GetGeneratedReports handles listing the archived reports of a child with the entries and options they were generated with.
+func (handler *DocumentGenerationHandler) GetGeneratedReports(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	childIDStr := request.PathValue("child_id")
+	childID, err := strconv.Atoi(childIDStr)
+	if err != nil {
+		logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for GetGeneratedReports")
+		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+		return
+	}
+
+	reports, err := handler.DocumentationEntryService.GetGeneratedReportsForChild(logger, request.Context(), childID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Child not found", http.StatusNotFound)
+			return
+		}
+		logger.WithField("child_id", childID).WithError(err).Error("Internal server error fetching generated reports")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(reports); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetGeneratedReports")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DownloadGeneratedReport handles downloading an archived report of a child again.
+func (handler *DocumentGenerationHandler) DownloadGeneratedReport(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	childIDStr := request.PathValue("child_id")
+	childID, err := strconv.Atoi(childIDStr)
+	if err != nil {
+		logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for DownloadGeneratedReport")
+		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+		return
+	}
+	reportIDStr := request.PathValue("report_id")
+	reportID, err := strconv.Atoi(reportIDStr)
+	if err != nil {
+		logger.WithField("report_id_str", reportIDStr).WithError(err).Warn("Invalid report ID format for DownloadGeneratedReport")
+		http.Error(writer, "Invalid report ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for DownloadGeneratedReport handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	report, err := handler.DocumentationEntryService.DownloadGeneratedReport(logger, request.Context(), childID, reportID, user.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Report not found", http.StatusNotFound)
+			return
+		}
+		logger.WithField("report_id", reportID).WithError(err).Error("Internal server error fetching generated report")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.wordprocessingml.document")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", report.FileName))
+	if _, err := writer.Write(report.Content); err != nil {
+		logger.WithField("report_id", reportID).WithError(err).Error("Failed to write report bytes to response")
+		http.Error(writer, "Failed to write report", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ExportGeneratedReports handles downloading all archived reports as a ZIP file.
+// The optional query parameter since (YYYY-MM-DD) limits the export to reports generated from that day on.
+func (handler *DocumentGenerationHandler) ExportGeneratedReports(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	var since time.Time
+	if sinceStr := request.URL.Query().Get("since"); sinceStr != "" {
+		var err error
+		since, err = time.Parse("2006-01-02", sinceStr)
+		if err != nil {
+			logger.WithField("since_str", sinceStr).WithError(err).Warn("Invalid since date for ExportGeneratedReports")
+			http.Error(writer, "Invalid since date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for ExportGeneratedReports handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	reports, err := handler.DocumentationEntryService.ExportGeneratedReports(logger, request.Context(), since, user.ID)
+	if err != nil {
+		if err == services.ErrCanceled {
+			return
+		}
+		logger.WithError(err).Error("Internal server error during generated report export")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/zip")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"berichte-%s.zip\"", handler.Clock.Now().Format("2006-01-02")))
+	archive := zip.NewWriter(writer)
+	for _, report := range reports {
+		// File names repeat across reports of the same child, the report ID keeps them apart.
+		file, err := archive.Create(fmt.Sprintf("%d_%s", report.ID, report.FileName))
+		if err == nil {
+			_, err = file.Write(report.Content)
+		}
+		if err != nil {
+			logger.WithField("report_id", report.ID).WithError(err).Error("Failed to write report to export archive")
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		logger.WithError(err).Error("Failed to finish export archive")
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"kitadoc-backend/handlers/mocks"
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/internal/testutils"
+	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 
@@ -21,7 +23,7 @@ import (
 func TestNewDocumentGenerationHandler(t *testing.T) {
 	mockDocEntryService := new(mocks.MockDocumentationEntryService)
 	mockAssignmentService := new(mocks.AssignmentService)
-	handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService)
+	handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService, nil, nil, nil, nil, clock.System{})
 	assert.NotNil(t, handler)
 	assert.Equal(t, mockDocEntryService, handler.DocumentationEntryService)
 	assert.Equal(t, mockAssignmentService, handler.AssignmentService)
@@ -36,11 +38,14 @@ func TestGenerateChildReport(t *testing.T) {
 		assignments := []models.Assignment{
 			{ID: 1, ChildID: 123, TeacherID: 1, StartDate: time.Now()},
 		}
-		mockDocEntryService.On("GenerateChildReport", mock.Anything, mock.Anything, 123, assignments).Return([]byte("test report content"), nil)
-		mockDocEntryService.On("GetDocumentName", mock.Anything, 123).Return("child_report.docx", nil).Once()
-		mockAssignmentService.On("GetAssignmentHistoryForChild", 123).Return(assignments, nil).Once()
+		supports := []models.ChildSupport{{ID: 4, ChildID: 123, ProviderID: 2, ProviderName: "Logopädie am Markt"}}
+		mockSupportProviderService := new(mocks.MockSupportProviderService)
+		mockSupportProviderService.On("GetChildSupports", mock.Anything, mock.Anything, 123).Return(supports, nil).Once()
+		mockDocEntryService.On("GenerateChildReport", mock.Anything, mock.Anything, 123, assignments, (*models.RedactionProfile)(nil), (*models.ChildCompleteness)(nil), supports, []models.GalleryPhoto(nil)).Return([]byte("test report content"), nil)
+		mockDocEntryService.On("GetDocumentName", mock.Anything, 123, (*models.RedactionProfile)(nil)).Return("child_report.docx", nil).Once()
+		mockAssignmentService.On("GetAssignmentHistoryForChild", mock.Anything, 123, models.ListQuery{}).Return(assignments, nil).Once()
 
-		handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService)
+		handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService, nil, nil, mockSupportProviderService, nil, clock.System{})
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/documents/child-report/123", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -57,12 +62,13 @@ func TestGenerateChildReport(t *testing.T) {
 
 		mockDocEntryService.AssertExpectations(t)
 		mockAssignmentService.AssertExpectations(t)
+		mockSupportProviderService.AssertExpectations(t)
 	})
 
 	t.Run("Invalid Child ID", func(t *testing.T) {
 		mockDocEntryService := new(mocks.MockDocumentationEntryService)
 		mockAssignmentService := new(mocks.AssignmentService)
-		handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService)
+		handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService, nil, nil, nil, nil, clock.System{})
 
 		req := httptest.NewRequest(http.MethodGet, "/reports/abc", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -80,10 +86,12 @@ func TestGenerateChildReport(t *testing.T) {
 	t.Run("Service Returns ErrChildReportGenerationFailed", func(t *testing.T) {
 		mockDocEntryService := new(mocks.MockDocumentationEntryService)
 		mockAssignmentService := new(mocks.AssignmentService)
-		mockDocEntryService.On("GenerateChildReport", mock.Anything, mock.Anything, 123, mock.Anything).Return(nil, services.ErrChildReportGenerationFailed)
-		mockAssignmentService.On("GetAssignmentHistoryForChild", 123).Return([]models.Assignment{}, nil).Once()
+		mockDocEntryService.On("GenerateChildReport", mock.Anything, mock.Anything, 123, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrChildReportGenerationFailed)
+		mockAssignmentService.On("GetAssignmentHistoryForChild", mock.Anything, 123, models.ListQuery{}).Return([]models.Assignment{}, nil).Once()
+		mockSupportProviderService := new(mocks.MockSupportProviderService)
+		mockSupportProviderService.On("GetChildSupports", mock.Anything, mock.Anything, 123).Return([]models.ChildSupport{}, nil).Once()
 
-		handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService)
+		handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService, nil, nil, mockSupportProviderService, nil, clock.System{})
 
 		req := httptest.NewRequest(http.MethodGet, "/reports/123", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -101,10 +109,12 @@ func TestGenerateChildReport(t *testing.T) {
 	t.Run("Service Returns Other Error", func(t *testing.T) {
 		mockDocEntryService := new(mocks.MockDocumentationEntryService)
 		mockAssignmentService := new(mocks.AssignmentService)
-		mockDocEntryService.On("GenerateChildReport", mock.Anything, mock.Anything, 123, mock.Anything).Return(nil, errors.New("some other service error"))
-		mockAssignmentService.On("GetAssignmentHistoryForChild", 123).Return([]models.Assignment{}, nil).Once()
+		mockDocEntryService.On("GenerateChildReport", mock.Anything, mock.Anything, 123, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("some other service error"))
+		mockAssignmentService.On("GetAssignmentHistoryForChild", mock.Anything, 123, models.ListQuery{}).Return([]models.Assignment{}, nil).Once()
+		mockSupportProviderService := new(mocks.MockSupportProviderService)
+		mockSupportProviderService.On("GetChildSupports", mock.Anything, mock.Anything, 123).Return([]models.ChildSupport{}, nil).Once()
 
-		handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService)
+		handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService, nil, nil, mockSupportProviderService, nil, clock.System{})
 
 		req := httptest.NewRequest(http.MethodGet, "/reports/123", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -122,10 +132,12 @@ func TestGenerateChildReport(t *testing.T) {
 	t.Run("Context Cancellation", func(t *testing.T) {
 		mockDocEntryService := new(mocks.MockDocumentationEntryService)
 		mockAssignmentService := new(mocks.AssignmentService)
-		mockDocEntryService.On("GenerateChildReport", mock.Anything, mock.Anything, 123, mock.Anything).Return(nil, context.Canceled)
-		mockAssignmentService.On("GetAssignmentHistoryForChild", 123).Return([]models.Assignment{}, nil).Once()
+		mockDocEntryService.On("GenerateChildReport", mock.Anything, mock.Anything, 123, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, context.Canceled)
+		mockAssignmentService.On("GetAssignmentHistoryForChild", mock.Anything, 123, models.ListQuery{}).Return([]models.Assignment{}, nil).Once()
+		mockSupportProviderService := new(mocks.MockSupportProviderService)
+		mockSupportProviderService.On("GetChildSupports", mock.Anything, mock.Anything, 123).Return([]models.ChildSupport{}, nil).Once()
 
-		handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService)
+		handler := NewDocumentGenerationHandler(mockDocEntryService, mockAssignmentService, nil, nil, mockSupportProviderService, nil, clock.System{})
 
 		req := httptest.NewRequest(http.MethodGet, "/reports/123", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -140,3 +152,51 @@ func TestGenerateChildReport(t *testing.T) {
 		mockDocEntryService.AssertExpectations(t)
 	})
 }
+
+func TestDownloadGeneratedReport(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	newRequest := func(childID, reportID string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/children/"+childID+"/reports/"+reportID, nil)
+		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
+		ctx = context.WithValue(ctx, middleware.ContextKeyUser, &models.User{ID: 5})
+		req.SetPathValue("child_id", childID)
+		req.SetPathValue("report_id", reportID)
+		return req.WithContext(ctx)
+	}
+
+	t.Run("Successful Download", func(t *testing.T) {
+		mockDocEntryService := new(mocks.MockDocumentationEntryService)
+		mockDocEntryService.On("DownloadGeneratedReport", mock.Anything, mock.Anything, 123, 9, 5).
+			Return(&models.GeneratedReport{ID: 9, ChildID: 123, FileName: "child_report.docx", Archived: true, Content: []byte("archived report")}, nil).Once()
+		handler := NewDocumentGenerationHandler(mockDocEntryService, nil, nil, nil, nil, nil, clock.System{})
+
+		recorder := httptest.NewRecorder()
+		handler.DownloadGeneratedReport(recorder, newRequest("123", "9"))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "archived report", recorder.Body.String())
+		assert.Equal(t, "attachment; filename=\"child_report.docx\"", recorder.Header().Get("Content-Disposition"))
+		mockDocEntryService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Report ID", func(t *testing.T) {
+		handler := NewDocumentGenerationHandler(new(mocks.MockDocumentationEntryService), nil, nil, nil, nil, nil, clock.System{})
+
+		recorder := httptest.NewRecorder()
+		handler.DownloadGeneratedReport(recorder, newRequest("123", "abc"))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, "Invalid report ID\n", recorder.Body.String())
+	})
+
+	t.Run("Report Not Found", func(t *testing.T) {
+		mockDocEntryService := new(mocks.MockDocumentationEntryService)
+		mockDocEntryService.On("DownloadGeneratedReport", mock.Anything, mock.Anything, 123, 9, 5).Return(nil, services.ErrNotFound).Once()
+		handler := NewDocumentGenerationHandler(mockDocEntryService, nil, nil, nil, nil, nil, clock.System{})
+
+		recorder := httptest.NewRecorder()
+		handler.DownloadGeneratedReport(recorder, newRequest("123", "9"))
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -21,6 +22,9 @@ func NewDocumentationEntryHandler(documentationEntryService services.Documentati
 	return &DocumentationEntryHandler{DocumentationEntryService: documentationEntryService}
 }
 
+// lockedEntryMessage answers edits of entries locked by a generated report.
+const lockedEntryMessage = "Documentation entry is locked because it was included in a generated report, create a revision instead"
+
 // CreateDocumentationEntry handles creating a new documentation entry.
 func (handler *DocumentationEntryHandler) CreateDocumentationEntry(writer http.ResponseWriter, request *http.Request) {
 	logger := middleware.GetLoggerWithReqID(request.Context())
@@ -31,9 +35,6 @@ func (handler *DocumentationEntryHandler) CreateDocumentationEntry(writer http.R
 		return
 	}
 
-	entry.CreatedAt = time.Now()
-	entry.UpdatedAt = time.Now()
-
 	createdEntry, err := handler.DocumentationEntryService.CreateDocumentationEntry(logger, request.Context(), &entry)
 	if err != nil {
 		if err == services.ErrInvalidInput {
@@ -41,12 +42,19 @@ func (handler *DocumentationEntryHandler) CreateDocumentationEntry(writer http.R
 			http.Error(writer, "Invalid documentation entry data provided", http.StatusBadRequest)
 			return
 		}
+		if writeValidationError(writer, err) {
+			return
+		}
+		if writeDomainError(writer, err) {
+			logger.WithError(err).Warn("Documentation entry references an unknown child, teacher or category")
+			return
+		}
 		logger.WithError(err).Error("Internal server error during documentation entry creation")
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	writer.WriteHeader(http.StatusCreated)
+	writeCreatedHeader(writer, "/api/v1/documentation", createdEntry.ID)
 	if err := json.NewEncoder(writer).Encode(createdEntry); err != nil {
 		logger.WithError(err).Error("Failed to encode response for CreateDocumentationEntry")
 		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
@@ -54,7 +62,9 @@ func (handler *DocumentationEntryHandler) CreateDocumentationEntry(writer http.R
 	}
 }
 
-// GetDocumentationEntriesByChildID handles fetching documentation entries by child ID.
+// GetDocumentationEntriesByChildID handles fetching documentation entries by child ID. The query parameters
+// category_id, teacher_id, project_id, is_approved, observation_date_from and observation_date_to narrow down the
+// entries, sort, limit and offset sort and paginate them, see parseListQuery.
 func (handler *DocumentationEntryHandler) GetDocumentationEntriesByChildID(writer http.ResponseWriter, request *http.Request) {
 	logger := middleware.GetLoggerWithReqID(request.Context())
 	childIDStr := request.PathValue("child_id")
@@ -65,8 +75,22 @@ func (handler *DocumentationEntryHandler) GetDocumentationEntriesByChildID(write
 		return
 	}
 
-	entries, err := handler.DocumentationEntryService.GetAllDocumentationForChild(logger, request.Context(), childID)
+	filters := append([]listFilter{
+		idFilter("category_id", "category_id"),
+		idFilter("teacher_id", "teacher_id"),
+		idFilter("project_id", "project_id"),
+		boolFilter("is_approved", "is_approved"),
+	}, dateRangeFilters("observation_date_from", "observation_date_to", "observation_date")...)
+	query, ok := parseListQuery(writer, request, []string{"id", "observation_date", "created_at", "updated_at"}, filters...)
+	if !ok {
+		return
+	}
+
+	entries, err := handler.DocumentationEntryService.GetAllDocumentationForChild(logger, request.Context(), childID, query)
 	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
 		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching documentation entries for child")
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
@@ -79,6 +103,134 @@ func (handler *DocumentationEntryHandler) GetDocumentationEntriesByChildID(write
 	}
 }
 
+// pendingDocumentationFilters are the query parameters that narrow down the entries awaiting approval.
+var pendingDocumentationFilters = []listFilter{
+	idFilter("child_id", "child_id"),
+	idFilter("category_id", "category_id"),
+	idFilter("teacher_id", "teacher_id"),
+	idFilter("project_id", "project_id"),
+}
+
+// CountPendingDocumentation handles counting the documentation entries awaiting approval. The query parameters
+// child_id, category_id, teacher_id and project_id narrow down the entries.
+func (handler *DocumentationEntryHandler) CountPendingDocumentation(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	query, ok := parseCountQuery(writer, request, pendingDocumentationFilters...)
+	if !ok {
+		return
+	}
+
+	count, err := handler.DocumentationEntryService.CountPendingDocumentation(logger, request.Context(), query)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error counting pending documentation entries")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeCount(writer, count); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CountPendingDocumentation")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Long polls of the pending count wait defaultPendingPollTimeout unless the client asks for another timeout.
+const (
+	defaultPendingPollTimeout = 25 * time.Second
+	maxPendingPollTimeout     = 50 * time.Second
+	// pendingPollMargin ends a long poll before the request timeout would answer it with 504.
+	pendingPollMargin = time.Second
+)
+
+// PollPendingDocumentation handles the long poll of the approval badge, for clients that cannot hold a stream
+// open. count is the pending count the client shows, the response is sent as soon as the count differs and at
+// the latest after timeout seconds. Without count, the current count is returned at once. The filters are the
+// ones of CountPendingDocumentation.
+func (handler *DocumentationEntryHandler) PollPendingDocumentation(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	query, ok := parseCountQuery(writer, request, pendingDocumentationFilters...)
+	if !ok {
+		return
+	}
+	params := request.URL.Query()
+	known := -1
+	if countStr := params.Get("count"); countStr != "" {
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count < 0 {
+			http.Error(writer, "Invalid count value", http.StatusBadRequest)
+			return
+		}
+		known = count
+	}
+	timeout := defaultPendingPollTimeout
+	if timeoutStr := params.Get("timeout"); timeoutStr != "" {
+		seconds, err := strconv.Atoi(timeoutStr)
+		if err != nil || seconds < 1 || time.Duration(seconds)*time.Second > maxPendingPollTimeout {
+			http.Error(writer, fmt.Sprintf("timeout must be between 1 and %d seconds", int(maxPendingPollTimeout/time.Second)), http.StatusBadRequest)
+			return
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if deadline, ok := request.Context().Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline)-pendingPollMargin)
+	}
+
+	count, err := handler.DocumentationEntryService.WaitForPendingDocumentationChange(logger, request.Context(), query, known, timeout)
+	if err != nil {
+		if err == services.ErrCanceled {
+			// The timeout middleware answers timed out requests, disconnected clients get no response.
+			return
+		}
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error polling pending documentation entries")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeCount(writer, count); err != nil {
+		logger.WithError(err).Error("Failed to encode response for PollPendingDocumentation")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetDocumentationEntryByID handles fetching a documentation entry by ID.
+func (handler *DocumentationEntryHandler) GetDocumentationEntryByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	entryIDStr := request.PathValue("entry_id")
+	entryID, err := strconv.Atoi(entryIDStr)
+	if err != nil {
+		logger.WithField("entry_id_str", entryIDStr).WithError(err).Warn("Invalid entry ID format for GetDocumentationEntryByID")
+		http.Error(writer, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := handler.DocumentationEntryService.GetDocumentationEntryByID(logger, request.Context(), entryID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Documentation entry not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error fetching documentation entry")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(entry); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetDocumentationEntryByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
 // UpdateDocumentationEntry handles updating an existing documentation entry.
 func (handler *DocumentationEntryHandler) UpdateDocumentationEntry(writer http.ResponseWriter, request *http.Request) {
 	logger := middleware.GetLoggerWithReqID(request.Context())
@@ -98,7 +250,6 @@ func (handler *DocumentationEntryHandler) UpdateDocumentationEntry(writer http.R
 	}
 
 	entry.ID = entryID
-	entry.UpdatedAt = time.Now()
 
 	err = handler.DocumentationEntryService.UpdateDocumentationEntry(logger, request.Context(), &entry)
 	if err != nil {
@@ -107,18 +258,33 @@ func (handler *DocumentationEntryHandler) UpdateDocumentationEntry(writer http.R
 			http.Error(writer, "Documentation entry not found", http.StatusNotFound)
 			return
 		}
+		if err == services.ErrLocked {
+			http.Error(writer, lockedEntryMessage, http.StatusConflict)
+			return
+		}
 		if err == services.ErrInvalidInput {
 			logger.WithError(err).Warn("Invalid documentation entry data provided for update")
 			http.Error(writer, "Invalid documentation entry data provided", http.StatusBadRequest)
 			return
 		}
+		if writeValidationError(writer, err) {
+			return
+		}
+		if writeDomainError(writer, err) {
+			logger.WithError(err).WithField("entry_id", entryID).Warn("Documentation entry update rejected")
+			return
+		}
 		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error during documentation entry update")
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
+	response := map[string]any{"message": "Documentation entry updated successfully"}
+	if len(entry.Warnings) > 0 {
+		response["warnings"] = entry.Warnings
+	}
 	writer.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Documentation entry updated successfully"}); err != nil {
+	if err := json.NewEncoder(writer).Encode(response); err != nil {
 		logger.WithError(err).Error("Failed to encode response for UpdateDocumentationEntry")
 		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
 		return
@@ -143,6 +309,10 @@ func (handler *DocumentationEntryHandler) DeleteDocumentationEntry(writer http.R
 			http.Error(writer, "Documentation entry not found", http.StatusNotFound)
 			return
 		}
+		if err == services.ErrLocked {
+			http.Error(writer, lockedEntryMessage, http.StatusConflict)
+			return
+		}
 		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error during documentation entry deletion")
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
@@ -167,9 +337,17 @@ func (handler *DocumentationEntryHandler) ApproveDocumentationEntry(writer http.
 		return
 	}
 
-	// Parse approvedByTeacherId from the request body
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for ApproveDocumentationEntry handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	// Parse approvedByTeacherId and the optional onBehalfOfUserId from the request body
 	var requestBody struct {
-		ApprovedByTeacherId int `json:"approvedByTeacherId"`
+		ApprovedByTeacherId int  `json:"approvedByTeacherId"`
+		OnBehalfOfUserId    *int `json:"onBehalfOfUserId"`
 	}
 	if err := json.NewDecoder(request.Body).Decode(&requestBody); err != nil {
 		logger.WithError(err).Error("Invalid request body for ApproveDocumentationEntry")
@@ -177,13 +355,22 @@ func (handler *DocumentationEntryHandler) ApproveDocumentationEntry(writer http.
 		return
 	}
 	approvedByUserID := requestBody.ApprovedByTeacherId
-	err = handler.DocumentationEntryService.ApproveDocumentationEntry(logger, request.Context(), entryID, approvedByUserID)
+	err = handler.DocumentationEntryService.ApproveDocumentationEntry(logger, request.Context(), entryID, approvedByUserID, user.ID, requestBody.OnBehalfOfUserId)
 	if err != nil {
 		if err == services.ErrNotFound {
 			logger.WithField("entry_id", entryID).Warn("Documentation entry not found for approval")
 			http.Error(writer, "Documentation entry not found", http.StatusNotFound)
 			return
 		}
+		if err == services.ErrPermissionDenied {
+			logger.WithField("entry_id", entryID).WithField("user_id", user.ID).Warn("No active delegation for approval on behalf of another user")
+			http.Error(writer, "No active approval delegation", http.StatusForbidden)
+			return
+		}
+		if writeDomainError(writer, err) {
+			logger.WithError(err).WithField("entry_id", entryID).Warn("Documentation entry approval rejected")
+			return
+		}
 		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error during documentation entry approval")
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
 		return
@@ -196,3 +383,179 @@ func (handler *DocumentationEntryHandler) ApproveDocumentationEntry(writer http.
 		return
 	}
 }
+
+// SubmitDocumentationEntry handles submitting a documentation entry for review.
+func (handler *DocumentationEntryHandler) SubmitDocumentationEntry(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	entryIDStr := request.PathValue("entry_id")
+	entryID, err := strconv.Atoi(entryIDStr)
+	if err != nil {
+		logger.WithField("entry_id_str", entryIDStr).WithError(err).Warn("Invalid entry ID format for SubmitDocumentationEntry")
+		http.Error(writer, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for SubmitDocumentationEntry handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	err = handler.DocumentationEntryService.SubmitDocumentationEntry(logger, request.Context(), entryID, user.ID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Documentation entry not found", http.StatusNotFound)
+			return
+		}
+		if err == services.ErrInvalidStateTransition {
+			http.Error(writer, "Only drafts and rejected documentation entries can be submitted", http.StatusConflict)
+			return
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error during documentation entry submission")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Documentation entry submitted successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for SubmitDocumentationEntry")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RejectDocumentationEntry handles rejecting a submitted documentation entry.
+func (handler *DocumentationEntryHandler) RejectDocumentationEntry(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	entryIDStr := request.PathValue("entry_id")
+	entryID, err := strconv.Atoi(entryIDStr)
+	if err != nil {
+		logger.WithField("entry_id_str", entryIDStr).WithError(err).Warn("Invalid entry ID format for RejectDocumentationEntry")
+		http.Error(writer, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for RejectDocumentationEntry handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var requestBody struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(request.Body).Decode(&requestBody); err != nil {
+		logger.WithError(err).Warn("Invalid request body for RejectDocumentationEntry")
+		http.Error(writer, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = handler.DocumentationEntryService.RejectDocumentationEntry(logger, request.Context(), entryID, user.ID, requestBody.Reason)
+	if err != nil {
+		if err == services.ErrInvalidInput {
+			http.Error(writer, "A reason of at most 1000 characters is required", http.StatusBadRequest)
+			return
+		}
+		if err == services.ErrNotFound {
+			http.Error(writer, "Documentation entry not found", http.StatusNotFound)
+			return
+		}
+		if err == services.ErrInvalidStateTransition {
+			http.Error(writer, "Only submitted documentation entries can be rejected", http.StatusConflict)
+			return
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error during documentation entry rejection")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Documentation entry rejected successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for RejectDocumentationEntry")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// CreateDocumentationEntryRevision handles creating a revision of a documentation entry, e.g. of one locked by a generated report.
+func (handler *DocumentationEntryHandler) CreateDocumentationEntryRevision(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	entryIDStr := request.PathValue("entry_id")
+	entryID, err := strconv.Atoi(entryIDStr)
+	if err != nil {
+		logger.WithField("entry_id_str", entryIDStr).WithError(err).Warn("Invalid entry ID format for CreateDocumentationEntryRevision")
+		http.Error(writer, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	var revision models.DocumentationEntry
+	if err := json.NewDecoder(request.Body).Decode(&revision); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateDocumentationEntryRevision")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	createdRevision, err := handler.DocumentationEntryService.CreateDocumentationEntryRevision(logger, request.Context(), entryID, &revision)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Documentation entry not found", http.StatusNotFound)
+			return
+		}
+		if err == services.ErrInvalidInput {
+			http.Error(writer, "Invalid documentation entry data provided", http.StatusBadRequest)
+			return
+		}
+		if writeValidationError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error during documentation entry revision")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/documentation", createdRevision.ID)
+	if err := json.NewEncoder(writer).Encode(createdRevision); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateDocumentationEntryRevision")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UnlockDocumentationEntry handles unlocking a documentation entry locked by a generated report.
+func (handler *DocumentationEntryHandler) UnlockDocumentationEntry(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	entryIDStr := request.PathValue("entry_id")
+	entryID, err := strconv.Atoi(entryIDStr)
+	if err != nil {
+		logger.WithField("entry_id_str", entryIDStr).WithError(err).Warn("Invalid entry ID format for UnlockDocumentationEntry")
+		http.Error(writer, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for UnlockDocumentationEntry handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	err = handler.DocumentationEntryService.UnlockDocumentationEntry(logger, request.Context(), entryID, user.ID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Documentation entry not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error during documentation entry unlock")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Documentation entry unlocked successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UnlockDocumentationEntry")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"kitadoc-backend/handlers/mocks"
 	"kitadoc-backend/internal/testutils"
+	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 
@@ -43,7 +44,7 @@ func TestCreateDocumentationEntry(t *testing.T) {
 				ChildID:                1,
 				TeacherID:              1,
 				CategoryID:             1,
-				ObservationDate:        time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC),
+				ObservationDate:        models.NewDate(2023, time.January, 15),
 				ObservationDescription: "Test observation",
 			},
 			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
@@ -52,7 +53,7 @@ func TestCreateDocumentationEntry(t *testing.T) {
 					ChildID:                1,
 					TeacherID:              1,
 					CategoryID:             1,
-					ObservationDate:        time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC),
+					ObservationDate:        models.NewDate(2023, time.January, 15),
 					ObservationDescription: "Test observation",
 					CreatedAt:              time.Now(),
 					UpdatedAt:              time.Now(),
@@ -113,6 +114,7 @@ func TestCreateDocumentationEntry(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatusCode, recorder.Code)
 			if tt.expectedStatusCode == http.StatusCreated {
+				assert.Equal(t, "/api/v1/documentation/1", recorder.Header().Get("Location"))
 				var actualEntry models.DocumentationEntry
 				err := json.Unmarshal(recorder.Body.Bytes(), &actualEntry)
 				assert.NoError(t, err)
@@ -120,7 +122,7 @@ func TestCreateDocumentationEntry(t *testing.T) {
 				assert.Equal(t, 1, actualEntry.ChildID)
 				assert.Equal(t, 1, actualEntry.TeacherID)
 				assert.Equal(t, 1, actualEntry.CategoryID)
-				assert.Equal(t, time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC), actualEntry.ObservationDate)
+				assert.Equal(t, models.NewDate(2023, time.January, 15), actualEntry.ObservationDate)
 				assert.Equal(t, "Test observation", actualEntry.ObservationDescription)
 				assert.False(t, actualEntry.IsApproved)
 				assert.Nil(t, actualEntry.ApprovedByUserID)
@@ -149,13 +151,13 @@ func TestGetDocumentationEntriesByChildID(t *testing.T) {
 			name:         "Successful Fetch",
 			childIDParam: "1",
 			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
-				m.On("GetAllDocumentationForChild", mock.Anything, mock.Anything, 1).Return([]models.DocumentationEntry{
+				m.On("GetAllDocumentationForChild", mock.Anything, mock.Anything, 1, models.ListQuery{}).Return([]models.DocumentationEntry{
 					{ID: 1, ChildID: 1, ObservationDescription: "Entry 1"},
 					{ID: 2, ChildID: 1, ObservationDescription: "Entry 2"},
 				}, nil).Once()
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedBody:       `[{"id":1,"child_id":1,"teacher_id":0,"category_id":0,"observation_date":"0001-01-01T00:00:00Z","observation_description":"Entry 1","is_approved":false,"approved_by_teacher_id":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},{"id":2,"child_id":1,"teacher_id":0,"category_id":0,"observation_date":"0001-01-01T00:00:00Z","observation_description":"Entry 2","is_approved":false,"approved_by_teacher_id":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}]` + "\n",
+			expectedBody:       `[{"id":1,"child_id":1,"teacher_id":0,"category_id":0,"observation_date":"0001-01-01","observation_description":"Entry 1","is_approved":false,"approved_by_teacher_id":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},{"id":2,"child_id":1,"teacher_id":0,"category_id":0,"observation_date":"0001-01-01","observation_description":"Entry 2","is_approved":false,"approved_by_teacher_id":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}]` + "\n",
 		},
 		{
 			name:         "Invalid Child ID",
@@ -170,7 +172,7 @@ func TestGetDocumentationEntriesByChildID(t *testing.T) {
 			name:         "Service Returns Error",
 			childIDParam: "1",
 			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
-				m.On("GetAllDocumentationForChild", mock.Anything, mock.Anything, 1).Return(nil, errors.New("service error")).Once()
+				m.On("GetAllDocumentationForChild", mock.Anything, mock.Anything, 1, models.ListQuery{}).Return(nil, errors.New("service error")).Once()
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedBody:       "Internal server error\n",
@@ -200,6 +202,71 @@ func TestGetDocumentationEntriesByChildID(t *testing.T) {
 	}
 }
 
+func TestPollPendingDocumentation(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	tests := []struct {
+		name               string
+		query              string
+		mockServiceSetup   func(*mocks.MockDocumentationEntryService)
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:  "Count Changed",
+			query: "?count=3&timeout=10&child_id=1",
+			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
+				m.On("WaitForPendingDocumentationChange", mock.Anything, mock.Anything, models.ListQuery{}.Where("child_id", models.OperatorEqual, 1), 3, 10*time.Second).Return(4, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"count":4}` + "\n",
+		},
+		{
+			name:  "Without Count",
+			query: "",
+			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
+				m.On("WaitForPendingDocumentationChange", mock.Anything, mock.Anything, models.ListQuery{}, -1, defaultPendingPollTimeout).Return(3, nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"count":3}` + "\n",
+		},
+		{
+			name:               "Invalid Count",
+			query:              "?count=-1",
+			mockServiceSetup:   func(m *mocks.MockDocumentationEntryService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "Invalid count value\n",
+		},
+		{
+			name:               "Timeout Too Long",
+			query:              "?count=3&timeout=600",
+			mockServiceSetup:   func(m *mocks.MockDocumentationEntryService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "timeout must be between 1 and 50 seconds\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockDocumentationEntryService)
+			tt.mockServiceSetup(mockService)
+
+			handler := NewDocumentationEntryHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/documentation/pending/poll"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), testutils.ContextKeyLogger, logger))
+
+			recorder := httptest.NewRecorder()
+			handler.PollPendingDocumentation(recorder, req)
+
+			assert.Equal(t, tt.expectedStatusCode, recorder.Code)
+			assert.Equal(t, tt.expectedBody, recorder.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUpdateDocumentationEntry(t *testing.T) {
 	logger := logrus.NewEntry(logrus.New())
 
@@ -219,7 +286,7 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 				ChildID:                1,
 				TeacherID:              1,
 				CategoryID:             1,
-				ObservationDate:        time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC),
+				ObservationDate:        models.NewDate(2023, time.February, 1),
 				ObservationDescription: "Updated observation",
 			},
 			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
@@ -228,6 +295,26 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			expectedStatusCode: http.StatusOK,
 			expectedBody:       `{"message":"Documentation entry updated successfully"}` + "\n",
 		},
+		{
+			name:         "Successful Update With Warnings",
+			entryIDParam: "1",
+			inputPayload: models.DocumentationEntry{
+				ID:                     1,
+				ChildID:                1,
+				TeacherID:              1,
+				CategoryID:             1,
+				ObservationDate:        models.NewDate(2023, time.February, 5),
+				ObservationDescription: "Updated observation",
+			},
+			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
+				m.On("UpdateDocumentationEntry", mock.Anything, mock.Anything, mock.AnythingOfType("*models.DocumentationEntry")).
+					Run(func(args mock.Arguments) {
+						args.Get(2).(*models.DocumentationEntry).Warnings = []string{"observation date is a Sunday, the facility is closed on that day"}
+					}).Return(nil).Once()
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"message":"Documentation entry updated successfully","warnings":["observation date is a Sunday, the facility is closed on that day"]}` + "\n",
+		},
 		{
 			name:               "Invalid Entry ID",
 			entryIDParam:       "abc",
@@ -248,7 +335,7 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			name:         "Service Returns ErrNotFound",
 			entryIDParam: "99",
 			inputPayload: models.DocumentationEntry{
-				ID: 99, ChildID: 1, TeacherID: 1, CategoryID: 1, ObservationDate: time.Now(), ObservationDescription: "Test",
+				ID: 99, ChildID: 1, TeacherID: 1, CategoryID: 1, ObservationDate: models.Today(time.Now()), ObservationDescription: "Test",
 			},
 			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
 				m.On("UpdateDocumentationEntry", mock.Anything, mock.Anything, mock.AnythingOfType("*models.DocumentationEntry")).Return(services.ErrNotFound).Once()
@@ -260,7 +347,7 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			name:         "Service Returns ErrInvalidInput",
 			entryIDParam: "1",
 			inputPayload: models.DocumentationEntry{
-				ID: 1, ChildID: 1, TeacherID: 1, CategoryID: 1, ObservationDate: time.Now(), ObservationDescription: "Test",
+				ID: 1, ChildID: 1, TeacherID: 1, CategoryID: 1, ObservationDate: models.Today(time.Now()), ObservationDescription: "Test",
 			},
 			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
 				m.On("UpdateDocumentationEntry", mock.Anything, mock.Anything, mock.AnythingOfType("*models.DocumentationEntry")).Return(services.ErrInvalidInput).Once()
@@ -272,7 +359,7 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			name:         "Service Returns Other Error",
 			entryIDParam: "1",
 			inputPayload: models.DocumentationEntry{
-				ID: 1, ChildID: 1, TeacherID: 1, CategoryID: 1, ObservationDate: time.Now(), ObservationDescription: "Test",
+				ID: 1, ChildID: 1, TeacherID: 1, CategoryID: 1, ObservationDate: models.Today(time.Now()), ObservationDescription: "Test",
 			},
 			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
 				m.On("UpdateDocumentationEntry", mock.Anything, mock.Anything, mock.AnythingOfType("*models.DocumentationEntry")).Return(errors.New("database error")).Once()
@@ -400,7 +487,7 @@ func TestApproveDocumentationEntry(t *testing.T) {
 				ApprovedByTeacherId int `json:"approvedByTeacherId"`
 			}{ApprovedByTeacherId: 1},
 			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
-				m.On("ApproveDocumentationEntry", mock.Anything, mock.Anything, 1, 1).Return(nil).Once()
+				m.On("ApproveDocumentationEntry", mock.Anything, mock.Anything, 1, 1, 5, (*int)(nil)).Return(nil).Once()
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedBody:       `{"message":"Documentation entry approved successfully"}` + "\n",
@@ -424,7 +511,7 @@ func TestApproveDocumentationEntry(t *testing.T) {
 				ApprovedByTeacherId int `json:"approvedByTeacherId"`
 			}{ApprovedByTeacherId: 1},
 			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
-				m.On("ApproveDocumentationEntry", mock.Anything, mock.Anything, 99, 1).Return(services.ErrNotFound).Once()
+				m.On("ApproveDocumentationEntry", mock.Anything, mock.Anything, 99, 1, 5, (*int)(nil)).Return(services.ErrNotFound).Once()
 			},
 			expectedStatusCode: http.StatusNotFound,
 			expectedBody:       "Documentation entry not found\n",
@@ -436,11 +523,45 @@ func TestApproveDocumentationEntry(t *testing.T) {
 				ApprovedByTeacherId int `json:"approvedByTeacherId"`
 			}{ApprovedByTeacherId: 1},
 			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
-				m.On("ApproveDocumentationEntry", mock.Anything, mock.Anything, 1, 1).Return(errors.New("service error")).Once()
+				m.On("ApproveDocumentationEntry", mock.Anything, mock.Anything, 1, 1, 5, (*int)(nil)).Return(errors.New("service error")).Once()
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedBody:       "Internal server error\n",
 		},
+		{
+			name:         "On Behalf Without Active Delegation",
+			entryIDParam: "1",
+			inputPayload: map[string]int{"approvedByTeacherId": 1, "onBehalfOfUserId": 2},
+			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
+				m.On("ApproveDocumentationEntry", mock.Anything, mock.Anything, 1, 1, 5, mock.MatchedBy(func(id *int) bool { return id != nil && *id == 2 })).Return(services.ErrPermissionDenied).Once()
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       "No active approval delegation\n",
+		},
+		{
+			name:         "Entry Already Approved",
+			entryIDParam: "1",
+			inputPayload: struct {
+				ApprovedByTeacherId int `json:"approvedByTeacherId"`
+			}{ApprovedByTeacherId: 1},
+			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
+				m.On("ApproveDocumentationEntry", mock.Anything, mock.Anything, 1, 1, 5, (*int)(nil)).Return(services.ErrEntryAlreadyApproved).Once()
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       `{"code":"ENTRY_ALREADY_APPROVED","error":"documentation entry is already approved"}` + "\n",
+		},
+		{
+			name:         "Approving Teacher Not Found",
+			entryIDParam: "1",
+			inputPayload: struct {
+				ApprovedByTeacherId int `json:"approvedByTeacherId"`
+			}{ApprovedByTeacherId: 1},
+			mockServiceSetup: func(m *mocks.MockDocumentationEntryService) {
+				m.On("ApproveDocumentationEntry", mock.Anything, mock.Anything, 1, 1, 5, (*int)(nil)).Return(services.ErrTeacherNotFound).Once()
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"code":"TEACHER_NOT_FOUND","error":"teacher not found"}` + "\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -455,6 +576,7 @@ func TestApproveDocumentationEntry(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodPost, "/entries/"+tt.entryIDParam+"/approve", &reqBody)
 			ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
+			ctx = context.WithValue(ctx, middleware.ContextKeyUser, &models.User{ID: 5, Role: "teacher"})
 			req.SetPathValue("entry_id", tt.entryIDParam)
 			req = req.WithContext(ctx)
 
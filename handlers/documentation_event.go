@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/services"
+)
+
+// DocumentationEventHandler handles HTTP requests on the lifecycle event stream of documentation entries.
+type DocumentationEventHandler struct {
+	DocumentationEventService services.DocumentationEventService
+}
+
+// NewDocumentationEventHandler creates a new DocumentationEventHandler.
+func NewDocumentationEventHandler(documentationEventService services.DocumentationEventService) *DocumentationEventHandler {
+	return &DocumentationEventHandler{DocumentationEventService: documentationEventService}
+}
+
+// GetChildTimeline handles fetching the documentation timeline of a child.
+func (handler *DocumentationEventHandler) GetChildTimeline(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	childIDStr := request.PathValue("child_id")
+	childID, err := strconv.Atoi(childIDStr)
+	if err != nil {
+		logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for GetChildTimeline")
+		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+		return
+	}
+
+	events, err := handler.DocumentationEventService.GetChildTimeline(logger, request.Context(), childID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Child not found", http.StatusNotFound)
+			return
+		}
+		logger.WithField("child_id", childID).WithError(err).Error("Internal server error fetching documentation timeline")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(events); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetChildTimeline")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetEntryHistory handles fetching the review history of a documentation entry.
+func (handler *DocumentationEventHandler) GetEntryHistory(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	entryIDStr := request.PathValue("entry_id")
+	entryID, err := strconv.Atoi(entryIDStr)
+	if err != nil {
+		logger.WithField("entry_id_str", entryIDStr).WithError(err).Warn("Invalid entry ID format for GetEntryHistory")
+		http.Error(writer, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	history, err := handler.DocumentationEventService.GetEntryHistory(logger, request.Context(), entryID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "No history recorded for documentation entry", http.StatusNotFound)
+			return
+		}
+		logger.WithField("entry_id", entryID).WithError(err).Error("Internal server error fetching documentation entry history")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(history); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetEntryHistory")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetLifecycleStats handles fetching the documentation lifecycle statistics.
+// The optional query parameter child_id narrows them down to a single child.
+func (handler *DocumentationEventHandler) GetLifecycleStats(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	var childID *int
+	if childIDStr := request.URL.Query().Get("child_id"); childIDStr != "" {
+		id, err := strconv.Atoi(childIDStr)
+		if err != nil {
+			logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for GetLifecycleStats")
+			http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+			return
+		}
+		childID = &id
+	}
+
+	stats, err := handler.DocumentationEventService.GetLifecycleStats(logger, request.Context(), childID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Child not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).Error("Internal server error computing documentation lifecycle statistics")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(stats); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetLifecycleStats")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
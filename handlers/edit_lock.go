@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// EditLockHandler handles the advisory locks of documentation entries being edited.
+type EditLockHandler struct {
+	EditLockService services.EditLockService
+}
+
+// NewEditLockHandler creates a new EditLockHandler.
+func NewEditLockHandler(editLockService services.EditLockService) *EditLockHandler {
+	return &EditLockHandler{EditLockService: editLockService}
+}
+
+// editLockConflict is the response when another user edits the entry, the frontend shows who.
+type editLockConflict struct {
+	Error string           `json:"error"`
+	Code  string           `json:"code"`
+	Lock  *models.EditLock `json:"lock"`
+}
+
+// GetEditLock handles fetching who edits an entry. It answers 204 No Content if nobody does.
+func (handler *EditLockHandler) GetEditLock(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	entryID, err := strconv.Atoi(request.PathValue("entry_id"))
+	if err != nil {
+		logger.WithError(err).Warn("Invalid entry ID format for GetEditLock")
+		http.Error(writer, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	lock, err := handler.EditLockService.GetEditLock(logger, request.Context(), entryID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Documentation entry not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error fetching edit lock")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if lock == nil {
+		writer.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(lock); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetEditLock")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// AcquireEditLock handles taking the lock when an entry is opened for editing, and renewing it while the
+// editor stays open. If another user edits the entry, it answers 409 Conflict with their lock.
+func (handler *EditLockHandler) AcquireEditLock(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		http.Error(writer, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	entryID, err := strconv.Atoi(request.PathValue("entry_id"))
+	if err != nil {
+		logger.WithError(err).Warn("Invalid entry ID format for AcquireEditLock")
+		http.Error(writer, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	lock, err := handler.EditLockService.AcquireEditLock(logger, request.Context(), entryID, user)
+	if err != nil {
+		if errors.Is(err, services.ErrEntryBeingEdited) {
+			writer.Header().Set("Content-Type", "application/json")
+			writer.WriteHeader(http.StatusConflict)
+			json.NewEncoder(writer).Encode(editLockConflict{ //nolint:errcheck
+				Error: "documentation entry is currently edited by " + lock.EditorName,
+				Code:  services.CodeEntryBeingEdited,
+				Lock:  lock,
+			})
+			return
+		}
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Documentation entry not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error acquiring edit lock")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(lock); err != nil {
+		logger.WithError(err).Error("Failed to encode response for AcquireEditLock")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ReleaseEditLock handles releasing the lock when the editor is closed. Admins can release abandoned locks.
+func (handler *EditLockHandler) ReleaseEditLock(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		http.Error(writer, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	entryID, err := strconv.Atoi(request.PathValue("entry_id"))
+	if err != nil {
+		logger.WithError(err).Warn("Invalid entry ID format for ReleaseEditLock")
+		http.Error(writer, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.EditLockService.ReleaseEditLock(logger, request.Context(), entryID, user); err != nil {
+		if errors.Is(err, services.ErrPermissionDenied) {
+			http.Error(writer, "The entry is edited by another user", http.StatusForbidden)
+			return
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Internal server error releasing edit lock")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
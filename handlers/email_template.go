@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// EmailTemplateHandler handles requests for the email templates the admins edit.
+type EmailTemplateHandler struct {
+	EmailTemplateService services.EmailTemplateService
+}
+
+// NewEmailTemplateHandler creates a new EmailTemplateHandler.
+func NewEmailTemplateHandler(emailTemplateService services.EmailTemplateService) *EmailTemplateHandler {
+	return &EmailTemplateHandler{EmailTemplateService: emailTemplateService}
+}
+
+// GetEmailTemplates handles listing the current version of every email template.
+func (handler *EmailTemplateHandler) GetEmailTemplates(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	templates, err := handler.EmailTemplateService.GetEmailTemplates(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching email templates")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(map[string]any{
+		"templates": templates,
+		"variables": models.EmailTemplateVariables,
+	}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetEmailTemplates")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetEmailTemplateVersions handles listing all versions of an email template.
+func (handler *EmailTemplateHandler) GetEmailTemplateVersions(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	versions, err := handler.EmailTemplateService.GetEmailTemplateVersions(logger, request.Context(), request.PathValue("template_key"))
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Email template not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).Error("Internal server error fetching email template versions")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(versions); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetEmailTemplateVersions")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// PublishEmailTemplate handles publishing a new version of an email template.
+func (handler *EmailTemplateHandler) PublishEmailTemplate(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for PublishEmailTemplate handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var template models.EmailTemplate
+	if err := json.NewDecoder(request.Body).Decode(&template); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for PublishEmailTemplate")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	template.Key = request.PathValue("template_key")
+
+	published, err := handler.EmailTemplateService.PublishEmailTemplate(logger, request.Context(), user.ID, &template)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			http.Error(writer, "Email template not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrInvalidInput):
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		default:
+			logger.WithError(err).Error("Internal server error publishing email template")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(writer).Encode(published); err != nil {
+		logger.WithError(err).Error("Failed to encode response for PublishEmailTemplate")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// PreviewEmailTemplate handles rendering an email template with example or given values without publishing it.
+// Without a subject and body, the current version is rendered.
+func (handler *EmailTemplateHandler) PreviewEmailTemplate(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var previewRequest struct {
+		Subject   string            `json:"subject"`
+		Body      string            `json:"body"`
+		Variables map[string]string `json:"variables"`
+	}
+	if err := json.NewDecoder(request.Body).Decode(&previewRequest); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for PreviewEmailTemplate")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	template := models.EmailTemplate{
+		Key:     request.PathValue("template_key"),
+		Subject: previewRequest.Subject,
+		Body:    previewRequest.Body,
+	}
+
+	rendered, err := handler.EmailTemplateService.PreviewEmailTemplate(logger, request.Context(), &template, previewRequest.Variables)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			http.Error(writer, "Email template not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrInvalidInput):
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		default:
+			logger.WithError(err).Error("Internal server error previewing email template")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(rendered); err != nil {
+		logger.WithError(err).Error("Failed to encode response for PreviewEmailTemplate")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
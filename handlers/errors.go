@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// writeDomainError responds with the code and message of a *services.DomainError, using the status of the
+// generic error it is a case of. It reports whether a response was written.
+func writeDomainError(writer http.ResponseWriter, err error) bool {
+	var domainError *services.DomainError
+	if !errors.As(err, &domainError) {
+		return false
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(domainErrorStatus(domainError))
+	json.NewEncoder(writer).Encode(map[string]string{ //nolint:errcheck
+		"error": domainError.Message,
+		"code":  domainError.Code,
+	})
+	return true
+}
+
+// domainErrorStatus maps a domain error to the status the handlers use for the generic error.
+func domainErrorStatus(domainError *services.DomainError) int {
+	switch {
+	case errors.Is(domainError, services.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(domainError, services.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(domainError, services.ErrInvalidStateTransition),
+		errors.Is(domainError, services.ErrAlreadyExists),
+		errors.Is(domainError, services.ErrLocked):
+		return http.StatusConflict
+	case errors.Is(domainError, services.ErrPermissionDenied):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// openAPIErrorCodes are the codes of the error responses, those of the middleware and the domain errors.
+func openAPIErrorCodes() []models.OpenAPIErrorCode {
+	codes := []models.OpenAPIErrorCode{
+		{Code: services.CodeValidationFailed, Status: http.StatusBadRequest, Description: "validation failed, see the violations"},
+		{Code: middleware.CodeReauthenticationRequired, Status: http.StatusUnauthorized, Description: "the password has to be confirmed again"},
+		{Code: middleware.CodeTermsAcceptanceRequired, Status: http.StatusForbidden, Description: "the current terms of use have not been accepted"},
+		{Code: middleware.CodeNetworkNotAllowed, Status: http.StatusForbidden, Description: "the route is only reachable from the facility network"},
+		{Code: middleware.CodeInternalError, Status: http.StatusInternalServerError, Description: "internal server error"},
+	}
+	for _, domainError := range services.DomainErrors {
+		codes = append(codes, models.OpenAPIErrorCode{Code: domainError.Code, Status: domainErrorStatus(domainError), Description: domainError.Message})
+	}
+	return codes
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// FeeExportHandler handles the export of the care hours and attendance for the fee accounting of the municipality.
+type FeeExportHandler struct {
+	FeeExportService services.FeeExportService
+	Clock            clock.Clock
+}
+
+// NewFeeExportHandler creates a new FeeExportHandler.
+func NewFeeExportHandler(feeExportService services.FeeExportService, clock clock.Clock) *FeeExportHandler {
+	return &FeeExportHandler{FeeExportService: feeExportService, Clock: clock}
+}
+
+// ExportFees handles exporting a month as CSV download. The optional "month" query parameter is the month as
+// YYYY-MM, by default the past month.
+func (handler *FeeExportHandler) ExportFees(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for ExportFees handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	today := models.Today(handler.Clock.Now())
+	month := models.NewDate(today.Year(), today.Month()-1, 1)
+	if monthStr := request.URL.Query().Get("month"); monthStr != "" {
+		parsed, err := time.Parse("2006-01", monthStr)
+		if err != nil {
+			logger.WithField("month_str", monthStr).Warn("Invalid month for ExportFees")
+			http.Error(writer, "Invalid month, must be YYYY-MM", http.StatusBadRequest)
+			return
+		}
+		month = models.DateOf(parsed)
+	}
+
+	content, err := handler.FeeExportService.ExportFees(logger, request.Context(), month, user.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "The month has not started yet", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("month", month).Error("Internal server error exporting fees")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=beitraege-%s.csv", month.Format("2006-01")))
+	writer.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	if _, err := writer.Write(content); err != nil {
+		logger.WithError(err).Error("Failed to write response for ExportFees")
+		return
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// FixtureHandler handles the requests of the frontend tests to the fixture server.
+type FixtureHandler struct {
+	FixtureService services.FixtureService
+}
+
+// NewFixtureHandler creates a new FixtureHandler.
+func NewFixtureHandler(fixtureService services.FixtureService) *FixtureHandler {
+	return &FixtureHandler{FixtureService: fixtureService}
+}
+
+// GetState handles fetching the seeded accounts and the time of the frozen clock.
+func (handler *FixtureHandler) GetState(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	if err := json.NewEncoder(writer).Encode(handler.FixtureService.GetState()); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetState")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Reset handles restoring the seeded data and the clock, called between test runs.
+func (handler *FixtureHandler) Reset(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	state, err := handler.FixtureService.Reset(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error resetting fixture data")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(state); err != nil {
+		logger.WithError(err).Error("Failed to encode response for Reset")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetClock handles moving the frozen clock.
+func (handler *FixtureHandler) SetClock(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var clockRequest models.FixtureClockRequest
+	if err := json.NewDecoder(request.Body).Decode(&clockRequest); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for SetClock")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if clockRequest.Now.IsZero() {
+		http.Error(writer, "now is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(handler.FixtureService.SetClock(logger, clockRequest.Now)); err != nil {
+		logger.WithError(err).Error("Failed to encode response for SetClock")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
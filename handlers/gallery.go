@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// GalleryHandler handles the HTTP requests for the photo galleries of groups and the photo consent of children.
+type GalleryHandler struct {
+	GalleryService         services.GalleryService
+	UploadScanService      services.UploadScanService
+	ResumableUploadService services.ResumableUploadService
+}
+
+// NewGalleryHandler creates a new GalleryHandler.
+func NewGalleryHandler(
+	galleryService services.GalleryService,
+	uploadScanService services.UploadScanService,
+	resumableUploadService services.ResumableUploadService,
+) *GalleryHandler {
+	return &GalleryHandler{GalleryService: galleryService, UploadScanService: uploadScanService, ResumableUploadService: resumableUploadService}
+}
+
+// UploadPhoto handles uploading a photo to the gallery of a group as the "photo" field of a multipart form.
+// The optional fields are "caption" and "child_ids", a comma-separated list of the tagged children. Instead of the
+// photo, the "upload_id" of a completed resumable upload can be sent. A photo encrypted by the client is sent with
+// its "envelope" as JSON.
+func (handler *GalleryHandler) UploadPhoto(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for UploadPhoto handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	groupID, ok := parsePathID(writer, request, "group_id", "UploadPhoto")
+	if !ok {
+		return
+	}
+
+	// The form overhead is allowed on top of the photo itself.
+	request.Body = http.MaxBytesReader(writer, request.Body, services.MaxGalleryPhotoSize+(64<<10))
+	resumed, ok := resumedUpload(writer, request, handler.ResumableUploadService, models.UploadKindPhoto)
+	if !ok {
+		return
+	}
+	var fileName string
+	var content []byte
+	if resumed != nil {
+		fileName, content = resumed.FileName, resumed.Content
+	} else {
+		file, header, err := request.FormFile("photo")
+		if err != nil {
+			http.Error(writer, "Error retrieving photo file: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close() //nolint:errcheck
+
+		fileName = header.Filename
+		content, err = io.ReadAll(file)
+		if err != nil {
+			http.Error(writer, "Failed to read photo file", http.StatusBadRequest)
+			return
+		}
+	}
+	if !scanUpload(writer, request, handler.UploadScanService, models.UploadKindPhoto, fileName, content) {
+		return
+	}
+	envelope, ok := attachmentEnvelope(writer, request)
+	if !ok {
+		return
+	}
+	photo := models.GalleryPhoto{GroupID: groupID, ChildIDs: []int{}, Content: content, Envelope: envelope}
+	if caption := request.FormValue("caption"); caption != "" {
+		photo.Caption = &caption
+	}
+	if childIDsStr := request.FormValue("child_ids"); childIDsStr != "" {
+		for _, childIDStr := range strings.Split(childIDsStr, ",") {
+			childID, err := strconv.Atoi(strings.TrimSpace(childIDStr))
+			if err != nil {
+				logger.WithField("child_ids_str", childIDsStr).WithError(err).Warn("Invalid child IDs for UploadPhoto")
+				http.Error(writer, "Invalid child IDs", http.StatusBadRequest)
+				return
+			}
+			photo.ChildIDs = append(photo.ChildIDs, childID)
+		}
+	}
+
+	created, err := handler.GalleryService.UploadPhoto(logger, request.Context(), &photo, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			http.Error(writer, "Group not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrInvalidInput):
+			http.Error(writer, "Invalid photo, a PNG or JPEG image of at most 5 MB is required", http.StatusBadRequest)
+		default:
+			logger.WithError(err).WithField("group_id", groupID).Error("Internal server error uploading gallery photo")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+	releaseResumedUpload(request, handler.ResumableUploadService, resumed)
+
+	writeCreatedHeader(writer, "/api/v1/photos", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UploadPhoto")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetGroupPhotos handles listing the gallery of a group. Photos of children without photo consent are redacted.
+func (handler *GalleryHandler) GetGroupPhotos(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	groupID, ok := parsePathID(writer, request, "group_id", "GetGroupPhotos")
+	if !ok {
+		return
+	}
+
+	photos, err := handler.GalleryService.GetGroupPhotos(logger, request.Context(), groupID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Group not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("group_id", groupID).Error("Internal server error fetching group gallery")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(photos); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetGroupPhotos")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetChildPhotos handles listing the photos a child is tagged on, e.g. to pick photos for a report.
+func (handler *GalleryHandler) GetChildPhotos(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetChildPhotos")
+	if !ok {
+		return
+	}
+
+	photos, err := handler.GalleryService.GetChildPhotos(logger, request.Context(), childID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching photos of child")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(photos); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetChildPhotos")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetPhotoByID handles fetching a photo by ID without its image.
+func (handler *GalleryHandler) GetPhotoByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	photoID, ok := parsePathID(writer, request, "photo_id", "GetPhotoByID")
+	if !ok {
+		return
+	}
+
+	photo, err := handler.GalleryService.GetPhotoByID(logger, request.Context(), photoID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("photo_id", photoID).Error("Internal server error fetching gallery photo")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(photo); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetPhotoByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetPhotoContent handles downloading the image of a photo. Redacted photos are withheld. Photos encrypted by the
+// client are served as the uploaded ciphertext.
+func (handler *GalleryHandler) GetPhotoContent(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	photoID, ok := parsePathID(writer, request, "photo_id", "GetPhotoContent")
+	if !ok {
+		return
+	}
+
+	photo, err := handler.GalleryService.GetPhotoContent(logger, request.Context(), photoID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("photo_id", photoID).Error("Internal server error fetching gallery photo content")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", photo.ContentType)
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.Header().Set("Content-Length", strconv.Itoa(len(photo.Content)))
+	if _, err := writer.Write(photo.Content); err != nil {
+		logger.WithError(err).Error("Failed to write response for GetPhotoContent")
+		return
+	}
+}
+
+// UpdatePhoto handles changing the caption and the tagged children of a photo.
+func (handler *GalleryHandler) UpdatePhoto(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	photoID, ok := parsePathID(writer, request, "photo_id", "UpdatePhoto")
+	if !ok {
+		return
+	}
+
+	var photo models.GalleryPhoto
+	if err := json.NewDecoder(request.Body).Decode(&photo); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdatePhoto")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	photo.ID = photoID
+
+	if err := handler.GalleryService.UpdatePhoto(logger, request.Context(), &photo); err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Group not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("photo_id", photoID).Error("Internal server error updating gallery photo")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Photo updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdatePhoto")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeletePhoto handles deleting a photo from its gallery.
+func (handler *GalleryHandler) DeletePhoto(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	photoID, ok := parsePathID(writer, request, "photo_id", "DeletePhoto")
+	if !ok {
+		return
+	}
+
+	if err := handler.GalleryService.DeletePhoto(logger, request.Context(), photoID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("photo_id", photoID).Error("Internal server error deleting gallery photo")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetPhotoConsent handles fetching the photo consent of a child.
+func (handler *GalleryHandler) GetPhotoConsent(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetPhotoConsent")
+	if !ok {
+		return
+	}
+
+	consent, err := handler.GalleryService.GetPhotoConsent(logger, request.Context(), childID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Photo consent not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching photo consent")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(consent); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetPhotoConsent")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetPhotoConsent handles recording or replacing the photo consent of a child.
+func (handler *GalleryHandler) SetPhotoConsent(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for SetPhotoConsent handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	childID, ok := parsePathID(writer, request, "child_id", "SetPhotoConsent")
+	if !ok {
+		return
+	}
+
+	var consent models.PhotoConsent
+	if err := json.NewDecoder(request.Body).Decode(&consent); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for SetPhotoConsent")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	consent.ChildID = childID
+
+	recorded, err := handler.GalleryService.SetPhotoConsent(logger, request.Context(), &consent, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error recording photo consent")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(recorded); err != nil {
+		logger.WithError(err).Error("Failed to encode response for SetPhotoConsent")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RevokePhotoConsent handles removing the photo consent of a child.
+func (handler *GalleryHandler) RevokePhotoConsent(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "RevokePhotoConsent")
+	if !ok {
+		return
+	}
+
+	if err := handler.GalleryService.RevokePhotoConsent(logger, request.Context(), childID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Photo consent not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error revoking photo consent")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// GroupHandler handles group HTTP requests.
+type GroupHandler struct {
+	GroupService services.GroupService
+}
+
+// NewGroupHandler creates a new GroupHandler.
+func NewGroupHandler(groupService services.GroupService) *GroupHandler {
+	return &GroupHandler{GroupService: groupService}
+}
+
+// CreateGroup handles creating a new group.
+func (handler *GroupHandler) CreateGroup(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var group models.Group
+	if err := json.NewDecoder(request.Body).Decode(&group); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateGroup")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	createdGroup, err := handler.GroupService.CreateGroup(logger, request.Context(), &group)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		case services.ErrAlreadyExists:
+			http.Error(writer, "Group with this name already exists", http.StatusConflict)
+		default:
+			logger.WithError(err).Error("Internal server error during group creation")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/groups", createdGroup.ID)
+	if err := json.NewEncoder(writer).Encode(createdGroup); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateGroup")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAllGroups handles fetching all groups.
+func (handler *GroupHandler) GetAllGroups(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	groups, err := handler.GroupService.GetAllGroups(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching groups")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if groups == nil {
+		groups = []models.Group{}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(groups); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAllGroups")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetGroupByID handles fetching a group by ID.
+func (handler *GroupHandler) GetGroupByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	groupID, ok := parseGroupID(writer, request, "GetGroupByID")
+	if !ok {
+		return
+	}
+
+	group, err := handler.GroupService.GetGroupByID(logger, request.Context(), groupID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Group not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("group_id", groupID).Error("Internal server error fetching group")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(group); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetGroupByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateGroup handles updating an existing group.
+func (handler *GroupHandler) UpdateGroup(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	groupID, ok := parseGroupID(writer, request, "UpdateGroup")
+	if !ok {
+		return
+	}
+
+	var group models.Group
+	if err := json.NewDecoder(request.Body).Decode(&group); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateGroup")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	group.ID = groupID
+
+	err := handler.GroupService.UpdateGroup(logger, request.Context(), &group)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Group not found", http.StatusNotFound)
+		case services.ErrAlreadyExists:
+			http.Error(writer, "Group with this name already exists", http.StatusConflict)
+		default:
+			logger.WithError(err).WithField("group_id", groupID).Error("Internal server error during group update")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Group updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateGroup")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteGroup handles deleting a group.
+func (handler *GroupHandler) DeleteGroup(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	groupID, ok := parseGroupID(writer, request, "DeleteGroup")
+	if !ok {
+		return
+	}
+
+	err := handler.GroupService.DeleteGroup(logger, request.Context(), groupID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Group not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("group_id", groupID).Error("Internal server error during group deletion")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// AssignChild handles moving a child into a group. Capacity and age band violations are returned as warnings.
+func (handler *GroupHandler) AssignChild(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	groupID, ok := parseGroupID(writer, request, "AssignChild")
+	if !ok {
+		return
+	}
+	childIDStr := request.PathValue("child_id")
+	childID, err := strconv.Atoi(childIDStr)
+	if err != nil {
+		logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for AssignChild")
+		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := handler.GroupService.AssignChild(logger, request.Context(), groupID, childID)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, "Archived children cannot be assigned to a group", http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Group or child not found", http.StatusNotFound)
+		default:
+			logger.WithError(err).WithField("group_id", groupID).Error("Internal server error during group assignment")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(result); err != nil {
+		logger.WithError(err).Error("Failed to encode response for AssignChild")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RemoveChild handles removing a child from a group.
+func (handler *GroupHandler) RemoveChild(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	groupID, ok := parseGroupID(writer, request, "RemoveChild")
+	if !ok {
+		return
+	}
+	childIDStr := request.PathValue("child_id")
+	childID, err := strconv.Atoi(childIDStr)
+	if err != nil {
+		logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for RemoveChild")
+		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+		return
+	}
+
+	err = handler.GroupService.RemoveChild(logger, request.Context(), groupID, childID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Child is not in this group", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("group_id", groupID).Error("Internal server error removing child from group")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetGroupStatistics handles fetching the occupancy of all groups.
+func (handler *GroupHandler) GetGroupStatistics(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	statistics, err := handler.GroupService.GetGroupStatistics(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching group statistics")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(statistics); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetGroupStatistics")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseGroupID reads the group ID path value and answers with 400 if it is invalid.
+func parseGroupID(writer http.ResponseWriter, request *http.Request, handlerName string) (int, bool) {
+	groupIDStr := request.PathValue("group_id")
+	groupID, err := strconv.Atoi(groupIDStr)
+	if err != nil {
+		middleware.GetLoggerWithReqID(request.Context()).WithField("group_id_str", groupIDStr).WithError(err).Warn("Invalid group ID format for " + handlerName)
+		http.Error(writer, "Invalid group ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return groupID, true
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// GroupHandoverHandler handles the HTTP requests for handing children over to another group.
+type GroupHandoverHandler struct {
+	GroupHandoverService services.GroupHandoverService
+}
+
+// NewGroupHandoverHandler creates a new GroupHandoverHandler.
+func NewGroupHandoverHandler(groupHandoverService services.GroupHandoverService) *GroupHandoverHandler {
+	return &GroupHandoverHandler{GroupHandoverService: groupHandoverService}
+}
+
+// CreateHandover handles moving a child into another group, it answers with the handover packet.
+func (handler *GroupHandoverHandler) CreateHandover(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for CreateHandover handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	childID, ok := parsePathID(writer, request, "child_id", "CreateHandover")
+	if !ok {
+		return
+	}
+
+	var handover models.GroupHandover
+	if err := json.NewDecoder(request.Body).Decode(&handover); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateHandover")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	handover.ChildID = childID
+
+	packet, err := handler.GroupHandoverService.CreateHandover(logger, request.Context(), &handover, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		if err == services.ErrInvalidInput {
+			http.Error(writer, "Archived children cannot be handed over to a group", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error during group handover")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/handovers", packet.Handover.ID)
+	if err := json.NewEncoder(writer).Encode(packet); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateHandover")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetHandoversForChild handles listing the handovers of a child, the latest first.
+func (handler *GroupHandoverHandler) GetHandoversForChild(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetHandoversForChild")
+	if !ok {
+		return
+	}
+
+	handovers, err := handler.GroupHandoverService.GetHandoversForChild(logger, request.Context(), childID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching handovers of child")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(handovers); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetHandoversForChild")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetUnconfirmedHandovers handles listing the handovers waiting for the confirmation of a receiving teacher.
+func (handler *GroupHandoverHandler) GetUnconfirmedHandovers(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	handovers, err := handler.GroupHandoverService.GetUnconfirmedHandovers(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching unconfirmed handovers")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(handovers); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetUnconfirmedHandovers")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetHandoverPacket handles fetching the handover packet of a handover.
+func (handler *GroupHandoverHandler) GetHandoverPacket(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	handoverID, ok := parsePathID(writer, request, "handover_id", "GetHandoverPacket")
+	if !ok {
+		return
+	}
+
+	packet, err := handler.GroupHandoverService.GetHandoverPacket(logger, request.Context(), handoverID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("handover_id", handoverID).Error("Internal server error fetching handover packet")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(packet); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetHandoverPacket")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ConfirmHandover handles the structured confirmation of a handover by a teacher of the receiving group.
+func (handler *GroupHandoverHandler) ConfirmHandover(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for ConfirmHandover handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	handoverID, ok := parsePathID(writer, request, "handover_id", "ConfirmHandover")
+	if !ok {
+		return
+	}
+
+	var confirmation models.HandoverConfirmation
+	if err := json.NewDecoder(request.Body).Decode(&confirmation); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for ConfirmHandover")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	handover, err := handler.GroupHandoverService.ConfirmHandover(logger, request.Context(), handoverID, &confirmation, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("handover_id", handoverID).Error("Internal server error confirming handover")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(handover); err != nil {
+		logger.WithError(err).Error("Failed to encode response for ConfirmHandover")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// IncidentHandler handles the HTTP requests for incidents and accidents of children.
+type IncidentHandler struct {
+	IncidentService services.IncidentService
+}
+
+// NewIncidentHandler creates a new IncidentHandler.
+func NewIncidentHandler(incidentService services.IncidentService) *IncidentHandler {
+	return &IncidentHandler{IncidentService: incidentService}
+}
+
+// CreateIncident handles recording an incident of the child of the path.
+func (handler *IncidentHandler) CreateIncident(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for CreateIncident handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	childID, ok := parsePathID(writer, request, "child_id", "CreateIncident")
+	if !ok {
+		return
+	}
+
+	var incident models.Incident
+	if err := json.NewDecoder(request.Body).Decode(&incident); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateIncident")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	incident.ChildID = childID
+
+	created, err := handler.IncidentService.CreateIncident(logger, request.Context(), &incident, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error during incident creation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/incidents", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateIncident")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetIncidentsForChild handles fetching the incidents of a child.
+func (handler *IncidentHandler) GetIncidentsForChild(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetIncidentsForChild")
+	if !ok {
+		return
+	}
+
+	incidents, err := handler.IncidentService.GetIncidentsForChild(logger, request.Context(), childID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching incidents")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(incidents); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetIncidentsForChild")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetIncidentByID handles fetching an incident by ID.
+func (handler *IncidentHandler) GetIncidentByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	incidentID, ok := parsePathID(writer, request, "incident_id", "GetIncidentByID")
+	if !ok {
+		return
+	}
+
+	incident, err := handler.IncidentService.GetIncidentByID(logger, request.Context(), incidentID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Incident not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("incident_id", incidentID).Error("Internal server error fetching incident")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(incident); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetIncidentByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateIncident handles updating the details of an incident.
+func (handler *IncidentHandler) UpdateIncident(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	incidentID, ok := parsePathID(writer, request, "incident_id", "UpdateIncident")
+	if !ok {
+		return
+	}
+
+	var incident models.Incident
+	if err := json.NewDecoder(request.Body).Decode(&incident); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateIncident")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	incident.ID = incidentID
+
+	err := handler.IncidentService.UpdateIncident(logger, request.Context(), &incident)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			http.Error(writer, "Incident not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrInvalidInput):
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		default:
+			logger.WithError(err).WithField("incident_id", incidentID).Error("Internal server error during incident update")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Incident updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateIncident")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteIncident handles deleting an incident.
+func (handler *IncidentHandler) DeleteIncident(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	incidentID, ok := parsePathID(writer, request, "incident_id", "DeleteIncident")
+	if !ok {
+		return
+	}
+
+	err := handler.IncidentService.DeleteIncident(logger, request.Context(), incidentID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Incident not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("incident_id", incidentID).Error("Internal server error during incident deletion")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// DownloadIncidentReport handles downloading the Unfallanzeige of an incident as PDF. Incomplete incidents are
+// answered with the violations listing the missing fields.
+func (handler *IncidentHandler) DownloadIncidentReport(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	incidentID, ok := parsePathID(writer, request, "incident_id", "DownloadIncidentReport")
+	if !ok {
+		return
+	}
+
+	content, err := handler.IncidentService.GetIncidentReportPDF(logger, request.Context(), incidentID)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		if err == services.ErrNotFound {
+			http.Error(writer, "Incident not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("incident_id", incidentID).Error("Internal server error generating Unfallanzeige")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/pdf")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"unfallanzeige_%d.pdf\"", incidentID))
+	if _, err := writer.Write(content); err != nil {
+		logger.WithError(err).Error("Failed to write Unfallanzeige")
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// InvitationHandler handles invitation HTTP requests.
+type InvitationHandler struct {
+	InvitationService services.InvitationService
+}
+
+// NewInvitationHandler creates a new InvitationHandler.
+func NewInvitationHandler(invitationService services.InvitationService) *InvitationHandler {
+	return &InvitationHandler{InvitationService: invitationService}
+}
+
+// CreateInvitationRequest represents the request body for creating an invitation.
+type CreateInvitationRequest struct {
+	Role string `json:"role"` // Role of the account created from the invitation, "teacher" or "admin"
+}
+
+// AcceptInvitationRequest represents the request body for registering with an invitation.
+type AcceptInvitationRequest struct {
+	Token    string `json:"token"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CreateInvitation handles creating a single-use signup link.
+func (handler *InvitationHandler) CreateInvitation(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for CreateInvitation handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req CreateInvitationRequest
+	if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateInvitation")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	link, err := handler.InvitationService.CreateInvitation(logger, request.Context(), req.Role, user.ID)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error during invitation creation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/invitations", link.Invitation.ID)
+	if err := json.NewEncoder(writer).Encode(link); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateInvitation")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetInvitations handles fetching all invitations.
+func (handler *InvitationHandler) GetInvitations(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	invitations, err := handler.InvitationService.GetAllInvitations(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching invitations")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(invitations); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetInvitations")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteInvitation handles revoking an invitation.
+func (handler *InvitationHandler) DeleteInvitation(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	invitationIDStr := request.PathValue("invitation_id")
+	invitationID, err := strconv.Atoi(invitationIDStr)
+	if err != nil {
+		logger.WithField("invitation_id_str", invitationIDStr).WithError(err).Warn("Invalid invitation ID format for DeleteInvitation")
+		http.Error(writer, "Invalid invitation ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.InvitationService.DeleteInvitation(logger, request.Context(), invitationID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("invitation_id", invitationID).Error("Internal server error during invitation deletion")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// AcceptInvitation handles registering an account with an invitation token.
+func (handler *InvitationHandler) AcceptInvitation(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var req AcceptInvitationRequest
+	if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for AcceptInvitation")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	createdUser, err := handler.InvitationService.AcceptInvitation(logger, request.Context(), req.Token, req.Username, req.Password)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		if writeDomainError(writer, err) {
+			return
+		}
+		if err == services.ErrAlreadyExists {
+			http.Error(writer, "User with this username already exists", http.StatusConflict)
+			return
+		}
+		if err == services.ErrInvalidInput {
+			http.Error(writer, "Invalid user data provided", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error during registration with invitation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(writer).Encode(createdUser); err != nil {
+		logger.WithError(err).Error("Failed to encode response for AcceptInvitation")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
 
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
@@ -11,16 +13,17 @@ import (
 // KitaMasterdataHandler handles Kita master data-related HTTP requests.
 type KitaMasterdataHandler struct {
 	KitaMasterdataService services.KitaMasterdataService
+	UploadScanService     services.UploadScanService
 }
 
 // NewKitaMasterdataHandler creates a new KitaMasterdataHandler.
-func NewKitaMasterdataHandler(kitaMasterdataService services.KitaMasterdataService) *KitaMasterdataHandler {
-	return &KitaMasterdataHandler{KitaMasterdataService: kitaMasterdataService}
+func NewKitaMasterdataHandler(kitaMasterdataService services.KitaMasterdataService, uploadScanService services.UploadScanService) *KitaMasterdataHandler {
+	return &KitaMasterdataHandler{KitaMasterdataService: kitaMasterdataService, UploadScanService: uploadScanService}
 }
 
 // GetKitaMasterdata handles fetching the Kita master data.
 func (handler *KitaMasterdataHandler) GetKitaMasterdata(writer http.ResponseWriter, request *http.Request) {
-	masterdata, err := handler.KitaMasterdataService.GetKitaMasterdata()
+	masterdata, err := handler.KitaMasterdataService.GetKitaMasterdata(request.Context())
 	if err != nil {
 		if err == services.ErrNotFound {
 			http.Error(writer, "Kita master data not found", http.StatusNotFound)
@@ -44,8 +47,11 @@ func (handler *KitaMasterdataHandler) UpdateKitaMasterdata(writer http.ResponseW
 		return
 	}
 
-	err := handler.KitaMasterdataService.UpdateKitaMasterdata(&masterdata)
+	err := handler.KitaMasterdataService.UpdateKitaMasterdata(request.Context(), &masterdata)
 	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
 		if err == services.ErrInvalidInput {
 			http.Error(writer, "Invalid Kita master data provided", http.StatusBadRequest)
 			return
@@ -60,3 +66,158 @@ func (handler *KitaMasterdataHandler) UpdateKitaMasterdata(writer http.ResponseW
 		return
 	}
 }
+
+// UpdateDocumentTheme handles updating the fonts and colors of generated documents.
+func (handler *KitaMasterdataHandler) UpdateDocumentTheme(writer http.ResponseWriter, request *http.Request) {
+	var theme models.DocumentTheme
+	if err := json.NewDecoder(request.Body).Decode(&theme); err != nil {
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	err := handler.KitaMasterdataService.UpdateDocumentTheme(request.Context(), &theme)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, "Invalid document theme provided", http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Kita master data not found", http.StatusNotFound)
+		default:
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Document theme updated successfully"}); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetTerminology handles fetching the wording of every term, replaced or default, for clients that show the same
+// wording as the generated documents.
+func (handler *KitaMasterdataHandler) GetTerminology(writer http.ResponseWriter, request *http.Request) {
+	terminology := models.Terminology{}
+	masterdata, err := handler.KitaMasterdataService.GetKitaMasterdata(request.Context())
+	if err == nil {
+		terminology = masterdata.Terminology
+	} else if err != services.ErrNotFound {
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(terminology.Effective()); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateTerminology handles replacing the terms of generated documents, terms left out return to their default.
+func (handler *KitaMasterdataHandler) UpdateTerminology(writer http.ResponseWriter, request *http.Request) {
+	var terminology models.Terminology
+	if err := json.NewDecoder(request.Body).Decode(&terminology); err != nil {
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	err := handler.KitaMasterdataService.UpdateTerminology(request.Context(), terminology)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, "Invalid terminology provided", http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Kita master data not found", http.StatusNotFound)
+		default:
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Terminology updated successfully"}); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetDocumentLogo handles downloading the logo of generated documents.
+func (handler *KitaMasterdataHandler) GetDocumentLogo(writer http.ResponseWriter, request *http.Request) {
+	logo, err := handler.KitaMasterdataService.GetDocumentLogo(request.Context())
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Document logo not found", http.StatusNotFound)
+			return
+		}
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", logo.ContentType)
+	writer.Header().Set("Content-Length", strconv.Itoa(len(logo.Content)))
+	if _, err := writer.Write(logo.Content); err != nil {
+		http.Error(writer, "Failed to write response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UploadDocumentLogo handles uploading the logo of generated documents as the "logo" field of a multipart form.
+func (handler *KitaMasterdataHandler) UploadDocumentLogo(writer http.ResponseWriter, request *http.Request) {
+	// The form overhead is allowed on top of the logo itself.
+	request.Body = http.MaxBytesReader(writer, request.Body, services.MaxDocumentLogoSize+(64<<10))
+	file, header, err := request.FormFile("logo")
+	if err != nil {
+		http.Error(writer, "Error retrieving logo file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close() //nolint:errcheck
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(writer, "Failed to read logo file", http.StatusBadRequest)
+		return
+	}
+	if !scanUpload(writer, request, handler.UploadScanService, models.UploadKindDocumentLogo, header.Filename, content) {
+		return
+	}
+
+	err = handler.KitaMasterdataService.UpdateDocumentLogo(request.Context(), content)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, "Invalid logo, a PNG or JPEG image of at most 1 MB is required", http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Kita master data not found", http.StatusNotFound)
+		default:
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Document logo uploaded successfully"}); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteDocumentLogo handles removing the logo of generated documents.
+func (handler *KitaMasterdataHandler) DeleteDocumentLogo(writer http.ResponseWriter, request *http.Request) {
+	err := handler.KitaMasterdataService.DeleteDocumentLogo(request.Context())
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Kita master data not found", http.StatusNotFound)
+			return
+		}
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
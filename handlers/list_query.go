@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"kitadoc-backend/models"
+)
+
+// maxListLimit caps the page size of list endpoints.
+const maxListLimit = 500
+
+// listFilter reads a query parameter of a list endpoint into a condition on a field.
+type listFilter struct {
+	param    string
+	field    string
+	operator string
+	parse    func(value string) (any, error)
+}
+
+// idFilter filters a field by the ID given in the query parameter.
+func idFilter(param string, field string) listFilter {
+	return listFilter{param: param, field: field, operator: models.OperatorEqual, parse: func(value string) (any, error) {
+		return strconv.Atoi(value)
+	}}
+}
+
+// boolFilter filters a field by the boolean given in the query parameter.
+func boolFilter(param string, field string) listFilter {
+	return listFilter{param: param, field: field, operator: models.OperatorEqual, parse: func(value string) (any, error) {
+		return strconv.ParseBool(value)
+	}}
+}
+
+// dateRangeFilters filters a date field by the from and to query parameters, both days are included.
+func dateRangeFilters(fromParam string, toParam string, field string) []listFilter {
+	return []listFilter{
+		{param: fromParam, field: field, operator: models.OperatorGreaterOrEqual, parse: parseDay},
+		{param: toParam, field: field, operator: models.OperatorLessOrEqual, parse: parseDay},
+	}
+}
+
+// parseDay parses a YYYY-MM-DD query parameter into a models.Date.
+func parseDay(value string) (any, error) {
+	day, err := time.Parse(time.DateOnly, value)
+	return models.DateOf(day), err
+}
+
+// parseListQuery reads the given filters and the sort, limit and offset query parameters of a list endpoint and
+// answers with 400 if one of them is invalid. sort is a comma separated list of the sortable fields, a leading "-"
+// sorts descending, e.g. sort=-observation_date,id.
+func parseListQuery(writer http.ResponseWriter, request *http.Request, sortable []string, filters ...listFilter) (models.ListQuery, bool) {
+	query, ok := parseCountQuery(writer, request, filters...)
+	if !ok {
+		return query, false
+	}
+	params := request.URL.Query()
+
+	if sortParam := params.Get("sort"); sortParam != "" {
+		for field := range strings.SplitSeq(sortParam, ",") {
+			field = strings.TrimSpace(field)
+			descending := strings.HasPrefix(field, "-")
+			field = strings.TrimPrefix(field, "-")
+			if !slices.Contains(sortable, field) {
+				http.Error(writer, fmt.Sprintf("Cannot sort by %q, must be one of %s", field, strings.Join(sortable, ", ")), http.StatusBadRequest)
+				return query, false
+			}
+			query = query.OrderBy(field, descending)
+		}
+	}
+
+	if limitStr := params.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			http.Error(writer, fmt.Sprintf("limit must be between 1 and %d", maxListLimit), http.StatusBadRequest)
+			return query, false
+		}
+		query.Limit = limit
+	}
+	if offsetStr := params.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			http.Error(writer, "Invalid offset value", http.StatusBadRequest)
+			return query, false
+		}
+		query.Offset = offset
+	}
+	return query, true
+}
+
+// parseCountQuery reads the given filters of a count endpoint and answers with 400 if one of them is invalid.
+func parseCountQuery(writer http.ResponseWriter, request *http.Request, filters ...listFilter) (models.ListQuery, bool) {
+	var query models.ListQuery
+	params := request.URL.Query()
+
+	for _, filter := range filters {
+		value := params.Get(filter.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := filter.parse(value)
+		if err != nil {
+			http.Error(writer, fmt.Sprintf("Invalid %s value", filter.param), http.StatusBadRequest)
+			return query, false
+		}
+		query = query.Where(filter.field, filter.operator, parsed)
+	}
+	return query, true
+}
+
+// writeCount answers a count endpoint. The count is also sent in the X-Total-Count header, so that a HEAD request
+// is enough for a badge.
+func writeCount(writer http.ResponseWriter, count int) error {
+	writer.Header().Set("X-Total-Count", strconv.Itoa(count))
+	writer.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(writer).Encode(map[string]int{"count": count})
+}
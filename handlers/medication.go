@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// MedicationHandler handles the HTTP requests for the medication administration log of children. The log is
+// read-only, administrations are only recorded and confirmed.
+type MedicationHandler struct {
+	MedicationService services.MedicationService
+}
+
+// NewMedicationHandler creates a new MedicationHandler.
+func NewMedicationHandler(medicationService services.MedicationService) *MedicationHandler {
+	return &MedicationHandler{MedicationService: medicationService}
+}
+
+// RecordAdministration handles recording a medication given to the child of the path.
+func (handler *MedicationHandler) RecordAdministration(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for RecordAdministration handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	childID, ok := parsePathID(writer, request, "child_id", "RecordAdministration")
+	if !ok {
+		return
+	}
+
+	var administration models.MedicationAdministration
+	if err := json.NewDecoder(request.Body).Decode(&administration); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for RecordAdministration")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	administration.ChildID = childID
+
+	created, err := handler.MedicationService.RecordAdministration(logger, request.Context(), &administration, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error recording medication administration")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/medication-administrations", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for RecordAdministration")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAdministrationsForChild handles fetching the medication log of a child.
+func (handler *MedicationHandler) GetAdministrationsForChild(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetAdministrationsForChild")
+	if !ok {
+		return
+	}
+
+	administrations, err := handler.MedicationService.GetAdministrationsForChild(logger, request.Context(), childID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching medication log")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(administrations); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAdministrationsForChild")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAdministrationByID handles fetching a medication administration by ID.
+func (handler *MedicationHandler) GetAdministrationByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	administrationID, ok := parsePathID(writer, request, "administration_id", "GetAdministrationByID")
+	if !ok {
+		return
+	}
+
+	administration, err := handler.MedicationService.GetAdministrationByID(logger, request.Context(), administrationID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Medication administration not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("administration_id", administrationID).Error("Internal server error fetching medication administration")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(administration); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAdministrationByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ConfirmAdministration handles the confirmation of a medication administration by a second user.
+func (handler *MedicationHandler) ConfirmAdministration(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for ConfirmAdministration handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	administrationID, ok := parsePathID(writer, request, "administration_id", "ConfirmAdministration")
+	if !ok {
+		return
+	}
+
+	administration, err := handler.MedicationService.ConfirmAdministration(logger, request.Context(), administrationID, user)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		if err == services.ErrNotFound {
+			http.Error(writer, "Medication administration not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("administration_id", administrationID).Error("Internal server error confirming medication administration")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(administration); err != nil {
+		logger.WithError(err).Error("Failed to encode response for ConfirmAdministration")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
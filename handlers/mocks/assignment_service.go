@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"github.com/stretchr/testify/mock"
 	"kitadoc-backend/models"
 )
@@ -10,13 +11,13 @@ type AssignmentService struct {
 	mock.Mock
 }
 
-// GetAssignmentByID provides a mock function with given fields: id
-func (_m *AssignmentService) GetAssignmentByID(id int) (*models.Assignment, error) {
-	ret := _m.Called(id)
+// GetAssignmentByID provides a mock function with given fields: ctx, id
+func (_m *AssignmentService) GetAssignmentByID(ctx context.Context, id int) (*models.Assignment, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *models.Assignment
-	if rf, ok := ret.Get(0).(func(int) *models.Assignment); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Assignment); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.Assignment)
@@ -24,8 +25,8 @@ func (_m *AssignmentService) GetAssignmentByID(id int) (*models.Assignment, erro
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -33,13 +34,13 @@ func (_m *AssignmentService) GetAssignmentByID(id int) (*models.Assignment, erro
 	return r0, r1
 }
 
-// CreateAssignment provides a mock function with given fields: assignment
-func (_m *AssignmentService) CreateAssignment(assignment *models.Assignment) (*models.Assignment, error) {
-	ret := _m.Called(assignment)
+// CreateAssignment provides a mock function with given fields: ctx, assignment
+func (_m *AssignmentService) CreateAssignment(ctx context.Context, assignment *models.Assignment) (*models.Assignment, error) {
+	ret := _m.Called(ctx, assignment)
 
 	var r0 *models.Assignment
-	if rf, ok := ret.Get(0).(func(*models.Assignment) *models.Assignment); ok {
-		r0 = rf(assignment)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Assignment) *models.Assignment); ok {
+		r0 = rf(ctx, assignment)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.Assignment)
@@ -47,8 +48,8 @@ func (_m *AssignmentService) CreateAssignment(assignment *models.Assignment) (*m
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*models.Assignment) error); ok {
-		r1 = rf(assignment)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Assignment) error); ok {
+		r1 = rf(ctx, assignment)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -56,13 +57,13 @@ func (_m *AssignmentService) CreateAssignment(assignment *models.Assignment) (*m
 	return r0, r1
 }
 
-// DeleteAssignment provides a mock function with given fields: id
-func (_m *AssignmentService) DeleteAssignment(id int) error {
-	ret := _m.Called(id)
+// DeleteAssignment provides a mock function with given fields: ctx, id
+func (_m *AssignmentService) DeleteAssignment(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -70,13 +71,13 @@ func (_m *AssignmentService) DeleteAssignment(id int) error {
 	return r0
 }
 
-// GetAssignmentHistoryForChild provides a mock function with given fields: childID
-func (_m *AssignmentService) GetAssignmentHistoryForChild(childID int) ([]models.Assignment, error) {
-	ret := _m.Called(childID)
+// GetAssignmentHistoryForChild provides a mock function with given fields: ctx, childID, query
+func (_m *AssignmentService) GetAssignmentHistoryForChild(ctx context.Context, childID int, query models.ListQuery) ([]models.Assignment, error) {
+	ret := _m.Called(ctx, childID, query)
 
 	var r0 []models.Assignment
-	if rf, ok := ret.Get(0).(func(int) []models.Assignment); ok {
-		r0 = rf(childID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.ListQuery) []models.Assignment); ok {
+		r0 = rf(ctx, childID, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Assignment)
@@ -84,8 +85,8 @@ func (_m *AssignmentService) GetAssignmentHistoryForChild(childID int) ([]models
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(childID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.ListQuery) error); ok {
+		r1 = rf(ctx, childID, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -93,13 +94,13 @@ func (_m *AssignmentService) GetAssignmentHistoryForChild(childID int) ([]models
 	return r0, r1
 }
 
-// UpdateAssignment provides a mock function with given fields: assignment
-func (_m *AssignmentService) UpdateAssignment(assignment *models.Assignment) error {
-	ret := _m.Called(assignment)
+// UpdateAssignment provides a mock function with given fields: ctx, assignment
+func (_m *AssignmentService) UpdateAssignment(ctx context.Context, assignment *models.Assignment) error {
+	ret := _m.Called(ctx, assignment)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*models.Assignment) error); ok {
-		r0 = rf(assignment)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Assignment) error); ok {
+		r0 = rf(ctx, assignment)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -107,13 +108,13 @@ func (_m *AssignmentService) UpdateAssignment(assignment *models.Assignment) err
 	return r0
 }
 
-// GetAllAssignments provides a mock function with given fields:
-func (_m *AssignmentService) GetAllAssignments() ([]models.Assignment, error) {
-	ret := _m.Called()
+// GetAllAssignments provides a mock function with given fields: ctx, query
+func (_m *AssignmentService) GetAllAssignments(ctx context.Context, query models.ListQuery) ([]models.Assignment, error) {
+	ret := _m.Called(ctx, query)
 
 	var r0 []models.Assignment
-	if rf, ok := ret.Get(0).(func() []models.Assignment); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, models.ListQuery) []models.Assignment); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Assignment)
@@ -121,8 +122,8 @@ func (_m *AssignmentService) GetAllAssignments() ([]models.Assignment, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, models.ListQuery) error); ok {
+		r1 = rf(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"kitadoc-backend/models"
 
 	"github.com/stretchr/testify/mock"
@@ -11,37 +12,50 @@ type MockChildService struct {
 	mock.Mock
 }
 
-func (m *MockChildService) CreateChild(child *models.Child) (*models.Child, error) {
-	args := m.Called(child)
+func (m *MockChildService) CreateChild(ctx context.Context, child *models.Child) (*models.Child, error) {
+	args := m.Called(ctx, child)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Child), args.Error(1)
 }
 
-func (m *MockChildService) GetChildByID(id int) (*models.Child, error) {
-	args := m.Called(id)
+func (m *MockChildService) GetChildByID(ctx context.Context, id int) (*models.Child, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Child), args.Error(1)
 }
 
-func (m *MockChildService) UpdateChild(child *models.Child) error {
-	args := m.Called(child)
+func (m *MockChildService) UpdateChild(ctx context.Context, child *models.Child) error {
+	args := m.Called(ctx, child)
 	return args.Error(0)
 }
 
-func (m *MockChildService) DeleteChild(id int) error {
-	args := m.Called(id)
+func (m *MockChildService) DeleteChild(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockChildService) GetAllChildren() ([]models.Child, error) {
-	args := m.Called()
+func (m *MockChildService) GetAllChildren(ctx context.Context, query models.ListQuery) ([]models.Child, error) {
+	args := m.Called(ctx, query)
 	return args.Get(0).([]models.Child), args.Error(1)
 }
 
+func (m *MockChildService) GetAllChildrenWithIncludes(ctx context.Context, includes []string, query models.ListQuery) ([]models.ChildListItem, error) {
+	args := m.Called(ctx, includes, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ChildListItem), args.Error(1)
+}
+
+func (m *MockChildService) CountChildren(ctx context.Context, query models.ListQuery) (int, error) {
+	args := m.Called(ctx, query)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockChildService) BulkImportChildren(fileContent []byte) error {
 	args := m.Called(fileContent)
 	return args.Error(0)
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"kitadoc-backend/models"
 
@@ -88,13 +89,13 @@ func (_m *MockDocumentationEntryService) DeleteDocumentationEntry(logger *logrus
 	return r0
 }
 
-// GetAllDocumentationForChild provides a mock function with given fields: logger, ctx, childID
-func (_m *MockDocumentationEntryService) GetAllDocumentationForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.DocumentationEntry, error) {
-	ret := _m.Called(logger, ctx, childID)
+// GetAllDocumentationForChild provides a mock function with given fields: logger, ctx, childID, query
+func (_m *MockDocumentationEntryService) GetAllDocumentationForChild(logger *logrus.Entry, ctx context.Context, childID int, query models.ListQuery) ([]models.DocumentationEntry, error) {
+	ret := _m.Called(logger, ctx, childID, query)
 
 	var r0 []models.DocumentationEntry
-	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int) []models.DocumentationEntry); ok {
-		r0 = rf(logger, ctx, childID)
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int, models.ListQuery) []models.DocumentationEntry); ok {
+		r0 = rf(logger, ctx, childID, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.DocumentationEntry)
@@ -102,8 +103,50 @@ func (_m *MockDocumentationEntryService) GetAllDocumentationForChild(logger *log
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, int) error); ok {
-		r1 = rf(logger, ctx, childID)
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, int, models.ListQuery) error); ok {
+		r1 = rf(logger, ctx, childID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountPendingDocumentation provides a mock function with given fields: logger, ctx, query
+func (_m *MockDocumentationEntryService) CountPendingDocumentation(logger *logrus.Entry, ctx context.Context, query models.ListQuery) (int, error) {
+	ret := _m.Called(logger, ctx, query)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, models.ListQuery) int); ok {
+		r0 = rf(logger, ctx, query)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, models.ListQuery) error); ok {
+		r1 = rf(logger, ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WaitForPendingDocumentationChange provides a mock function with given fields: logger, ctx, query, known, timeout
+func (_m *MockDocumentationEntryService) WaitForPendingDocumentationChange(logger *logrus.Entry, ctx context.Context, query models.ListQuery, known int, timeout time.Duration) (int, error) {
+	ret := _m.Called(logger, ctx, query, known, timeout)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, models.ListQuery, int, time.Duration) int); ok {
+		r0 = rf(logger, ctx, query, known, timeout)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, models.ListQuery, int, time.Duration) error); ok {
+		r1 = rf(logger, ctx, query, known, timeout)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -111,13 +154,27 @@ func (_m *MockDocumentationEntryService) GetAllDocumentationForChild(logger *log
 	return r0, r1
 }
 
-// ApproveDocumentationEntry provides a mock function with given fields: logger, ctx, entryID, approvedByUserID
-func (_m *MockDocumentationEntryService) ApproveDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, approvedByUserID int) error {
-	ret := _m.Called(logger, ctx, entryID, approvedByUserID)
+// ApproveDocumentationEntry provides a mock function with given fields: logger, ctx, entryID, approvedByUserID, actingUserID, onBehalfOfUserID
+func (_m *MockDocumentationEntryService) ApproveDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, approvedByUserID int, actingUserID int, onBehalfOfUserID *int) error {
+	ret := _m.Called(logger, ctx, entryID, approvedByUserID, actingUserID, onBehalfOfUserID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int, int, int, *int) error); ok {
+		r0 = rf(logger, ctx, entryID, approvedByUserID, actingUserID, onBehalfOfUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SubmitDocumentationEntry provides a mock function with given fields: logger, ctx, entryID, actingUserID
+func (_m *MockDocumentationEntryService) SubmitDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, actingUserID int) error {
+	ret := _m.Called(logger, ctx, entryID, actingUserID)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int, int) error); ok {
-		r0 = rf(logger, ctx, entryID, approvedByUserID)
+		r0 = rf(logger, ctx, entryID, actingUserID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -125,13 +182,27 @@ func (_m *MockDocumentationEntryService) ApproveDocumentationEntry(logger *logru
 	return r0
 }
 
-// GenerateChildReport provides a mock function with given fields: logger, ctx, childID, assignments
-func (_m *MockDocumentationEntryService) GenerateChildReport(logger *logrus.Entry, ctx context.Context, childID int, assignments []models.Assignment) ([]byte, error) {
-	ret := _m.Called(logger, ctx, childID, assignments)
+// RejectDocumentationEntry provides a mock function with given fields: logger, ctx, entryID, actingUserID, reason
+func (_m *MockDocumentationEntryService) RejectDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, actingUserID int, reason string) error {
+	ret := _m.Called(logger, ctx, entryID, actingUserID, reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int, int, string) error); ok {
+		r0 = rf(logger, ctx, entryID, actingUserID, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GenerateChildReport provides a mock function with given fields: logger, ctx, childID, assignments, redaction, completeness, supports, photos
+func (_m *MockDocumentationEntryService) GenerateChildReport(logger *logrus.Entry, ctx context.Context, childID int, assignments []models.Assignment, redaction *models.RedactionProfile, completeness *models.ChildCompleteness, supports []models.ChildSupport, photos []models.GalleryPhoto) ([]byte, error) {
+	ret := _m.Called(logger, ctx, childID, assignments, redaction, completeness, supports, photos)
 
 	var r0 []byte
-	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int, []models.Assignment) []byte); ok {
-		r0 = rf(logger, ctx, childID, assignments)
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int, []models.Assignment, *models.RedactionProfile, *models.ChildCompleteness, []models.ChildSupport, []models.GalleryPhoto) []byte); ok {
+		r0 = rf(logger, ctx, childID, assignments, redaction, completeness, supports, photos)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
@@ -139,8 +210,8 @@ func (_m *MockDocumentationEntryService) GenerateChildReport(logger *logrus.Entr
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, int, []models.Assignment) error); ok {
-		r1 = rf(logger, ctx, childID, assignments)
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, int, []models.Assignment, *models.RedactionProfile, *models.ChildCompleteness, []models.ChildSupport, []models.GalleryPhoto) error); ok {
+		r1 = rf(logger, ctx, childID, assignments, redaction, completeness, supports, photos)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -148,23 +219,117 @@ func (_m *MockDocumentationEntryService) GenerateChildReport(logger *logrus.Entr
 	return r0, r1
 }
 
-// GetDocumentName provides a mock function with given fields: ctx, childID
-func (_m *MockDocumentationEntryService) GetDocumentName(ctx context.Context, childID int) (string, error) {
-	ret := _m.Called(ctx, childID)
+// GetDocumentName provides a mock function with given fields: ctx, childID, redaction
+func (_m *MockDocumentationEntryService) GetDocumentName(ctx context.Context, childID int, redaction *models.RedactionProfile) (string, error) {
+	ret := _m.Called(ctx, childID, redaction)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(context.Context, int) string); ok {
-		r0 = rf(ctx, childID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.RedactionProfile) string); ok {
+		r0 = rf(ctx, childID, redaction)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, childID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.RedactionProfile) error); ok {
+		r1 = rf(ctx, childID, redaction)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateDocumentationEntryRevision provides a mock function with given fields: logger, ctx, entryID, revision
+func (_m *MockDocumentationEntryService) CreateDocumentationEntryRevision(logger *logrus.Entry, ctx context.Context, entryID int, revision *models.DocumentationEntry) (*models.DocumentationEntry, error) {
+	ret := _m.Called(logger, ctx, entryID, revision)
+
+	var r0 *models.DocumentationEntry
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int, *models.DocumentationEntry) *models.DocumentationEntry); ok {
+		r0 = rf(logger, ctx, entryID, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DocumentationEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, int, *models.DocumentationEntry) error); ok {
+		r1 = rf(logger, ctx, entryID, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnlockDocumentationEntry provides a mock function with given fields: logger, ctx, entryID, actingUserID
+func (_m *MockDocumentationEntryService) UnlockDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, actingUserID int) error {
+	ret := _m.Called(logger, ctx, entryID, actingUserID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int, int) error); ok {
+		r0 = rf(logger, ctx, entryID, actingUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetGeneratedReportsForChild provides a mock function with given fields: logger, ctx, childID
+func (_m *MockDocumentationEntryService) GetGeneratedReportsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.GeneratedReport, error) {
+	ret := _m.Called(logger, ctx, childID)
+
+	var r0 []models.GeneratedReport
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int) []models.GeneratedReport); ok {
+		r0 = rf(logger, ctx, childID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.GeneratedReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, int) error); ok {
+		r1 = rf(logger, ctx, childID)
 	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
+
+// DownloadGeneratedReport provides a mock function with given fields: logger, ctx, childID, reportID, actingUserID
+func (_m *MockDocumentationEntryService) DownloadGeneratedReport(logger *logrus.Entry, ctx context.Context, childID int, reportID int, actingUserID int) (*models.GeneratedReport, error) {
+	ret := _m.Called(logger, ctx, childID, reportID, actingUserID)
+
+	var r0 *models.GeneratedReport
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int, int, int) *models.GeneratedReport); ok {
+		r0 = rf(logger, ctx, childID, reportID, actingUserID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GeneratedReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, int, int, int) error); ok {
+		r1 = rf(logger, ctx, childID, reportID, actingUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportGeneratedReports provides a mock function with given fields: logger, ctx, since, actingUserID
+func (_m *MockDocumentationEntryService) ExportGeneratedReports(logger *logrus.Entry, ctx context.Context, since time.Time, actingUserID int) ([]models.GeneratedReport, error) {
+	ret := _m.Called(logger, ctx, since, actingUserID)
+
+	var r0 []models.GeneratedReport
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]models.GeneratedReport)
+	}
+	return r0, ret.Error(1)
+}
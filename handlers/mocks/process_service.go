@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"kitadoc-backend/models"
 
 	"github.com/stretchr/testify/mock"
@@ -11,21 +12,21 @@ type MockProcessService struct {
 	mock.Mock
 }
 
-func (m *MockProcessService) Create(status string) (*models.Process, error) {
-	args := m.Called(status)
+func (m *MockProcessService) Create(ctx context.Context, status string) (*models.Process, error) {
+	args := m.Called(ctx, status)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Process), args.Error(1)
 }
 
-func (m *MockProcessService) Update(process *models.Process) error {
-	args := m.Called(process)
+func (m *MockProcessService) Update(ctx context.Context, process *models.Process) error {
+	args := m.Called(ctx, process)
 	return args.Error(0)
 }
 
-func (m *MockProcessService) GetByID(id int) (*models.Process, error) {
-	args := m.Called(id)
+func (m *MockProcessService) GetByID(ctx context.Context, id int) (*models.Process, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -0,0 +1,75 @@
+package mocks
+
+import (
+	"context"
+
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSupportProviderService is a mock implementation of services.SupportProviderService
+type MockSupportProviderService struct {
+	mock.Mock
+}
+
+func (m *MockSupportProviderService) CreateSupportProvider(logger *logrus.Entry, ctx context.Context, provider *models.SupportProvider) (*models.SupportProvider, error) {
+	args := m.Called(logger, ctx, provider)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupportProvider), args.Error(1)
+}
+
+func (m *MockSupportProviderService) GetSupportProviderByID(logger *logrus.Entry, ctx context.Context, id int) (*models.SupportProvider, error) {
+	args := m.Called(logger, ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupportProvider), args.Error(1)
+}
+
+func (m *MockSupportProviderService) GetAllSupportProviders(logger *logrus.Entry, ctx context.Context) ([]models.SupportProvider, error) {
+	args := m.Called(logger, ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SupportProvider), args.Error(1)
+}
+
+func (m *MockSupportProviderService) UpdateSupportProvider(logger *logrus.Entry, ctx context.Context, provider *models.SupportProvider) error {
+	args := m.Called(logger, ctx, provider)
+	return args.Error(0)
+}
+
+func (m *MockSupportProviderService) DeleteSupportProvider(logger *logrus.Entry, ctx context.Context, id int) error {
+	args := m.Called(logger, ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSupportProviderService) CreateChildSupport(logger *logrus.Entry, ctx context.Context, support *models.ChildSupport) (*models.ChildSupport, error) {
+	args := m.Called(logger, ctx, support)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChildSupport), args.Error(1)
+}
+
+func (m *MockSupportProviderService) GetChildSupports(logger *logrus.Entry, ctx context.Context, childID int) ([]models.ChildSupport, error) {
+	args := m.Called(logger, ctx, childID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ChildSupport), args.Error(1)
+}
+
+func (m *MockSupportProviderService) UpdateChildSupport(logger *logrus.Entry, ctx context.Context, support *models.ChildSupport) error {
+	args := m.Called(logger, ctx, support)
+	return args.Error(0)
+}
+
+func (m *MockSupportProviderService) DeleteChildSupport(logger *logrus.Entry, ctx context.Context, childID int, supportID int) error {
+	args := m.Called(logger, ctx, childID, supportID)
+	return args.Error(0)
+}
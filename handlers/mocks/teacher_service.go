@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"kitadoc-backend/models"
 
 	"github.com/stretchr/testify/mock"
@@ -12,8 +13,8 @@ type MockTeacherService struct {
 }
 
 // CreateTeacher mocks the CreateTeacher method.
-func (m *MockTeacherService) CreateTeacher(teacher *models.Teacher) (*models.Teacher, error) {
-	args := m.Called(teacher)
+func (m *MockTeacherService) CreateTeacher(ctx context.Context, teacher *models.Teacher) (*models.Teacher, error) {
+	args := m.Called(ctx, teacher)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -21,8 +22,8 @@ func (m *MockTeacherService) CreateTeacher(teacher *models.Teacher) (*models.Tea
 }
 
 // GetTeacherByID mocks the GetTeacherByID method.
-func (m *MockTeacherService) GetTeacherByID(id int) (*models.Teacher, error) {
-	args := m.Called(id)
+func (m *MockTeacherService) GetTeacherByID(ctx context.Context, id int) (*models.Teacher, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -30,22 +31,40 @@ func (m *MockTeacherService) GetTeacherByID(id int) (*models.Teacher, error) {
 }
 
 // UpdateTeacher mocks the UpdateTeacher method.
-func (m *MockTeacherService) UpdateTeacher(teacher *models.Teacher) error {
-	args := m.Called(teacher)
+func (m *MockTeacherService) UpdateTeacher(ctx context.Context, teacher *models.Teacher) error {
+	args := m.Called(ctx, teacher)
 	return args.Error(0)
 }
 
 // DeleteTeacher mocks the DeleteTeacher method.
-func (m *MockTeacherService) DeleteTeacher(id int) error {
-	args := m.Called(id)
+func (m *MockTeacherService) DeleteTeacher(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
 // GetAllTeachers mocks the GetAllTeachers method.
-func (m *MockTeacherService) GetAllTeachers() ([]models.Teacher, error) {
-	args := m.Called()
+func (m *MockTeacherService) GetAllTeachers(ctx context.Context, includeDeactivated bool) ([]models.Teacher, error) {
+	args := m.Called(ctx, includeDeactivated)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Teacher), args.Error(1)
 }
+
+// DeactivateTeacher mocks the DeactivateTeacher method.
+func (m *MockTeacherService) DeactivateTeacher(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// ReactivateTeacher mocks the ReactivateTeacher method.
+func (m *MockTeacherService) ReactivateTeacher(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MergeTeachers mocks the MergeTeachers method.
+func (m *MockTeacherService) MergeTeachers(ctx context.Context, duplicateID int, teacherID int) error {
+	args := m.Called(ctx, duplicateID, teacherID)
+	return args.Error(0)
+}
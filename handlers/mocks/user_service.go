@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"context"
+	"time"
+
 	"kitadoc-backend/models"
 
 	"github.com/sirupsen/logrus"
@@ -13,13 +15,36 @@ type UserService struct {
 	mock.Mock
 }
 
-// RegisterUser provides a mock function with given fields: logger, username, password, role
-func (_m *UserService) RegisterUser(logger *logrus.Entry, username string, password string, role string) (*models.User, error) {
-	ret := _m.Called(logger, username, password, role)
+// RegisterUser provides a mock function with given fields: logger, ctx, username, password, role
+func (_m *UserService) RegisterUser(logger *logrus.Entry, ctx context.Context, username string, password string, role string) (*models.User, error) {
+	ret := _m.Called(logger, ctx, username, password, role)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, string, string, string) *models.User); ok {
+		r0 = rf(logger, ctx, username, password, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, string, string, string) error); ok {
+		r1 = rf(logger, ctx, username, password, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateUser provides a mock function with given fields: logger, ctx, username, password, role
+func (_m *UserService) CreateUser(logger *logrus.Entry, ctx context.Context, username string, password string, role string) (*models.User, error) {
+	ret := _m.Called(logger, ctx, username, password, role)
 
 	var r0 *models.User
-	if rf, ok := ret.Get(0).(func(*logrus.Entry, string, string, string) *models.User); ok {
-		r0 = rf(logger, username, password, role)
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, string, string, string) *models.User); ok {
+		r0 = rf(logger, ctx, username, password, role)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.User)
@@ -27,8 +52,8 @@ func (_m *UserService) RegisterUser(logger *logrus.Entry, username string, passw
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*logrus.Entry, string, string, string) error); ok {
-		r1 = rf(logger, username, password, role)
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, string, string, string) error); ok {
+		r1 = rf(logger, ctx, username, password, role)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -36,20 +61,22 @@ func (_m *UserService) RegisterUser(logger *logrus.Entry, username string, passw
 	return r0, r1
 }
 
-// LoginUser provides a mock function with given fields: logger, username, password
-func (_m *UserService) LoginUser(logger *logrus.Entry, username string, password string) (string, error) {
-	ret := _m.Called(logger, username, password)
+// LoginUser provides a mock function with given fields: logger, ctx, username, password
+func (_m *UserService) LoginUser(logger *logrus.Entry, ctx context.Context, username string, password string) (*models.AuthTokens, error) {
+	ret := _m.Called(logger, ctx, username, password)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(*logrus.Entry, string, string) string); ok {
-		r0 = rf(logger, username, password)
+	var r0 *models.AuthTokens
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, string, string) *models.AuthTokens); ok {
+		r0 = rf(logger, ctx, username, password)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AuthTokens)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*logrus.Entry, string, string) error); ok {
-		r1 = rf(logger, username, password)
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, string, string) error); ok {
+		r1 = rf(logger, ctx, username, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -57,6 +84,57 @@ func (_m *UserService) LoginUser(logger *logrus.Entry, username string, password
 	return r0, r1
 }
 
+// RefreshSession provides a mock function with given fields: logger, ctx, refreshToken
+func (_m *UserService) RefreshSession(logger *logrus.Entry, ctx context.Context, refreshToken string) (*models.AuthTokens, error) {
+	ret := _m.Called(logger, ctx, refreshToken)
+
+	var r0 *models.AuthTokens
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, string) *models.AuthTokens); ok {
+		r0 = rf(logger, ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AuthTokens)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context, string) error); ok {
+		r1 = rf(logger, ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EndSession provides a mock function with given fields: logger, ctx, sessionID
+func (_m *UserService) EndSession(logger *logrus.Entry, ctx context.Context, sessionID int) error {
+	ret := _m.Called(logger, ctx, sessionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int) error); ok {
+		r0 = rf(logger, ctx, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ValidateSession provides a mock function with given fields: logger, ctx, userID, sessionID
+func (_m *UserService) ValidateSession(logger *logrus.Entry, ctx context.Context, userID int, sessionID int) error {
+	ret := _m.Called(logger, ctx, userID, sessionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int, int) error); ok {
+		r0 = rf(logger, ctx, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetCurrentUser provides a mock function with given fields: logger, tokenString
 func (_m *UserService) GetCurrentUser(logger *logrus.Entry, tokenString string) (*models.User, error) {
 	ret := _m.Called(logger, tokenString)
@@ -103,13 +181,13 @@ func (_m *UserService) GetUserByID(logger *logrus.Entry, ctx context.Context, us
 	return r0, r1
 }
 
-// UpdateUser provides a mock function with given fields: logger, userModel
-func (_m *UserService) UpdateUser(logger *logrus.Entry, userModel *models.User) error {
-	ret := _m.Called(logger, userModel)
+// UpdateUser provides a mock function with given fields: logger, ctx, userModel
+func (_m *UserService) UpdateUser(logger *logrus.Entry, ctx context.Context, userModel *models.User) error {
+	ret := _m.Called(logger, ctx, userModel)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*logrus.Entry, *models.User) error); ok {
-		r0 = rf(logger, userModel)
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, *models.User) error); ok {
+		r0 = rf(logger, ctx, userModel)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -117,13 +195,13 @@ func (_m *UserService) UpdateUser(logger *logrus.Entry, userModel *models.User)
 	return r0
 }
 
-// DeleteUser provides a mock function with given fields: logger, userID
-func (_m *UserService) DeleteUser(logger *logrus.Entry, userID int) error {
-	ret := _m.Called(logger, userID)
+// DeleteUser provides a mock function with given fields: logger, ctx, userID
+func (_m *UserService) DeleteUser(logger *logrus.Entry, ctx context.Context, userID int) error {
+	ret := _m.Called(logger, ctx, userID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*logrus.Entry, int) error); ok {
-		r0 = rf(logger, userID)
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, int) error); ok {
+		r0 = rf(logger, ctx, userID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -131,13 +209,13 @@ func (_m *UserService) DeleteUser(logger *logrus.Entry, userID int) error {
 	return r0
 }
 
-// GetAllUsers provides a mock function with given fields: logger
-func (_m *UserService) GetAllUsers(logger *logrus.Entry) ([]*models.User, error) {
-	ret := _m.Called(logger)
+// GetAllUsers provides a mock function with given fields: logger, ctx
+func (_m *UserService) GetAllUsers(logger *logrus.Entry, ctx context.Context) ([]*models.User, error) {
+	ret := _m.Called(logger, ctx)
 
 	var r0 []*models.User
-	if rf, ok := ret.Get(0).(func(*logrus.Entry) []*models.User); ok {
-		r0 = rf(logger)
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context) []*models.User); ok {
+		r0 = rf(logger, ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*models.User)
@@ -145,8 +223,8 @@ func (_m *UserService) GetAllUsers(logger *logrus.Entry) ([]*models.User, error)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*logrus.Entry) error); ok {
-		r1 = rf(logger)
+	if rf, ok := ret.Get(1).(func(*logrus.Entry, context.Context) error); ok {
+		r1 = rf(logger, ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -154,16 +232,22 @@ func (_m *UserService) GetAllUsers(logger *logrus.Entry) ([]*models.User, error)
 	return r0, r1
 }
 
-// ChangePassword provides a mock function with given fields: logger, actor, userID, oldPassword, newPassword
-func (_m *UserService) ChangePassword(logger *logrus.Entry, actor *models.User, userID int, oldPassword string, newPassword string) error {
-	ret := _m.Called(logger, actor, userID, oldPassword, newPassword)
+// ChangePassword provides a mock function with given fields: logger, ctx, actor, userID, oldPassword, newPassword
+func (_m *UserService) ChangePassword(logger *logrus.Entry, ctx context.Context, actor *models.User, userID int, oldPassword string, newPassword string) error {
+	ret := _m.Called(logger, ctx, actor, userID, oldPassword, newPassword)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*logrus.Entry, *models.User, int, string, string) error); ok {
-		r0 = rf(logger, actor, userID, oldPassword, newPassword)
+	if rf, ok := ret.Get(0).(func(*logrus.Entry, context.Context, *models.User, int, string, string) error); ok {
+		r0 = rf(logger, ctx, actor, userID, oldPassword, newPassword)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
+
+// Reauthenticate provides a mock function with given fields: logger, ctx, userID, password
+func (_m *UserService) Reauthenticate(logger *logrus.Entry, ctx context.Context, userID int, password string) (string, time.Time, error) {
+	ret := _m.Called(logger, ctx, userID, password)
+	return ret.String(0), ret.Get(1).(time.Time), ret.Error(2)
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// NotificationHandler handles device registration and notification preference requests.
+type NotificationHandler struct {
+	NotificationService services.NotificationService
+	VAPIDPublicKey      string // Empty when Web Push is not configured
+}
+
+// NewNotificationHandler creates a new NotificationHandler.
+func NewNotificationHandler(notificationService services.NotificationService, vapidPublicKey string) *NotificationHandler {
+	return &NotificationHandler{NotificationService: notificationService, VAPIDPublicKey: vapidPublicKey}
+}
+
+// RegisterDevice handles registering a push token for the current user.
+func (handler *NotificationHandler) RegisterDevice(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for RegisterDevice handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var device models.Device
+	if err := json.NewDecoder(request.Body).Decode(&device); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for RegisterDevice")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	device.UserID = user.ID
+
+	registeredDevice, err := handler.NotificationService.RegisterDevice(logger, request.Context(), &device)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		if err == services.ErrInvalidInput {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error during device registration")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/devices", registeredDevice.ID)
+	if err := json.NewEncoder(writer).Encode(registeredDevice); err != nil {
+		logger.WithError(err).Error("Failed to encode response for RegisterDevice")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetDevices handles fetching the devices of the current user.
+func (handler *NotificationHandler) GetDevices(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for GetDevices handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	devices, err := handler.NotificationService.GetDevicesForUser(logger, request.Context(), user.ID)
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching devices")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if devices == nil {
+		devices = []models.Device{}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(devices); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetDevices")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UnregisterDevice handles removing a device of the current user.
+func (handler *NotificationHandler) UnregisterDevice(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for UnregisterDevice handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	deviceIDStr := request.PathValue("device_id")
+	deviceID, err := strconv.Atoi(deviceIDStr)
+	if err != nil {
+		logger.WithField("device_id_str", deviceIDStr).WithError(err).Warn("Invalid device ID format for UnregisterDevice")
+		http.Error(writer, "Invalid device ID", http.StatusBadRequest)
+		return
+	}
+
+	err = handler.NotificationService.UnregisterDevice(logger, request.Context(), user.ID, deviceID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Device not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("device_id", deviceID).Error("Internal server error during device unregistration")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetPreferences handles fetching the notification preferences of the current user.
+func (handler *NotificationHandler) GetPreferences(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for GetPreferences handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	preferences, err := handler.NotificationService.GetPreferences(logger, request.Context(), user.ID)
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching notification preferences")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(preferences); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetPreferences")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdatePreferences handles updating the notification preferences of the current user.
+func (handler *NotificationHandler) UpdatePreferences(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for UpdatePreferences handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var preferences models.NotificationPreferences
+	if err := json.NewDecoder(request.Body).Decode(&preferences); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdatePreferences")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	preferences.UserID = user.ID
+
+	if err := handler.NotificationService.UpdatePreferences(logger, request.Context(), &preferences); err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error updating notification preferences")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Notification preferences updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdatePreferences")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetVAPIDPublicKey returns the application server key browsers need to create a Web Push subscription.
+func (handler *NotificationHandler) GetVAPIDPublicKey(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	if handler.VAPIDPublicKey == "" {
+		http.Error(writer, "Web Push is not configured", http.StatusNotFound)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"public_key": handler.VAPIDPublicKey}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetVAPIDPublicKey")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SendReminder handles sending a reminder notification to a teacher.
+func (handler *NotificationHandler) SendReminder(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for SendReminder handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	teacherIDStr := request.PathValue("teacher_id")
+	teacherID, err := strconv.Atoi(teacherIDStr)
+	if err != nil {
+		logger.WithField("teacher_id_str", teacherIDStr).WithError(err).Warn("Invalid teacher ID format for SendReminder")
+		http.Error(writer, "Invalid teacher ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(request.Body).Decode(&requestBody); err != nil || requestBody.Message == "" {
+		logger.WithError(err).Warn("Invalid request payload for SendReminder")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	handler.NotificationService.SendReminder(logger, request.Context(), user.ID, teacherID, requestBody.Message)
+
+	writer.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Reminder queued"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for SendReminder")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
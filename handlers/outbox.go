@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// OutboxHandler handles outbox HTTP requests.
+type OutboxHandler struct {
+	OutboxService services.OutboxService
+}
+
+// NewOutboxHandler creates a new OutboxHandler.
+func NewOutboxHandler(outboxService services.OutboxService) *OutboxHandler {
+	return &OutboxHandler{OutboxService: outboxService}
+}
+
+// GetOutboxMessages handles fetching the outbox messages in a state.
+// The query parameter status defaults to failed, the messages that need attention.
+func (handler *OutboxHandler) GetOutboxMessages(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	status := request.URL.Query().Get("status")
+	if status == "" {
+		status = models.OutboxStatusFailed
+	}
+	messages, err := handler.OutboxService.GetMessages(logger, request.Context(), status)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "status must be pending, delivered or failed", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error fetching outbox messages")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if messages == nil {
+		messages = []models.OutboxMessage{}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(messages); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetOutboxMessages")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -28,7 +28,7 @@ func (handler *ProcessHandler) GetStatus(writer http.ResponseWriter, request *ht
 		http.Error(writer, "Invalid process ID", http.StatusBadRequest)
 		return
 	}
-	process, err := handler.processService.GetByID(id)
+	process, err := handler.processService.GetByID(request.Context(), id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			http.Error(writer, "Process not found", http.StatusNotFound)
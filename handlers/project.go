@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// ProjectHandler handles the HTTP requests for projects and the observations made in them.
+type ProjectHandler struct {
+	ProjectService services.ProjectService
+}
+
+// NewProjectHandler creates a new ProjectHandler.
+func NewProjectHandler(projectService services.ProjectService) *ProjectHandler {
+	return &ProjectHandler{ProjectService: projectService}
+}
+
+// CreateProject handles planning a new project.
+func (handler *ProjectHandler) CreateProject(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	var project models.Project
+	if err := json.NewDecoder(request.Body).Decode(&project); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateProject")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	created, err := handler.ProjectService.CreateProject(logger, request.Context(), &project)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error during project creation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/projects", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateProject")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAllProjects handles listing all projects.
+func (handler *ProjectHandler) GetAllProjects(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	projects, err := handler.ProjectService.GetAllProjects(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching projects")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(projects); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAllProjects")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetProjectByID handles fetching a project by ID.
+func (handler *ProjectHandler) GetProjectByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	projectID, ok := parsePathID(writer, request, "project_id", "GetProjectByID")
+	if !ok {
+		return
+	}
+
+	project, err := handler.ProjectService.GetProjectByID(logger, request.Context(), projectID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("project_id", projectID).Error("Internal server error fetching project")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(project); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetProjectByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateProject handles changing a project, its groups and goals are replaced by those in the request.
+func (handler *ProjectHandler) UpdateProject(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	projectID, ok := parsePathID(writer, request, "project_id", "UpdateProject")
+	if !ok {
+		return
+	}
+
+	var project models.Project
+	if err := json.NewDecoder(request.Body).Decode(&project); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateProject")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	project.ID = projectID
+
+	if err := handler.ProjectService.UpdateProject(logger, request.Context(), &project); err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("project_id", projectID).Error("Internal server error during project update")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Project updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateProject")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteProject handles deleting a project.
+func (handler *ProjectHandler) DeleteProject(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	projectID, ok := parsePathID(writer, request, "project_id", "DeleteProject")
+	if !ok {
+		return
+	}
+
+	if err := handler.ProjectService.DeleteProject(logger, request.Context(), projectID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("project_id", projectID).Error("Internal server error deleting project")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetProjectDocumentation handles listing the documentation entries of a project. The query parameters child_id,
+// category_id, teacher_id, is_approved, observation_date_from and observation_date_to narrow down the entries,
+// sort, limit and offset sort and paginate them, see parseListQuery.
+func (handler *ProjectHandler) GetProjectDocumentation(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	projectID, ok := parsePathID(writer, request, "project_id", "GetProjectDocumentation")
+	if !ok {
+		return
+	}
+
+	filters := append([]listFilter{
+		idFilter("child_id", "child_id"),
+		idFilter("category_id", "category_id"),
+		idFilter("teacher_id", "teacher_id"),
+		boolFilter("is_approved", "is_approved"),
+	}, dateRangeFilters("observation_date_from", "observation_date_to", "observation_date")...)
+	query, ok := parseListQuery(writer, request, []string{"id", "observation_date", "created_at", "updated_at"}, filters...)
+	if !ok {
+		return
+	}
+
+	entries, err := handler.ProjectService.GetProjectDocumentation(logger, request.Context(), projectID, query)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("project_id", projectID).Error("Internal server error fetching documentation entries of project")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(entries); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetProjectDocumentation")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetProjectSummary handles the summary report of a project.
+func (handler *ProjectHandler) GetProjectSummary(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	projectID, ok := parsePathID(writer, request, "project_id", "GetProjectSummary")
+	if !ok {
+		return
+	}
+
+	summary, err := handler.ProjectService.GetProjectSummary(logger, request.Context(), projectID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("project_id", projectID).Error("Internal server error fetching project summary")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(summary); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetProjectSummary")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/services"
+)
+
+// QualityReportHandler handles requests for the monthly documentation quality report.
+type QualityReportHandler struct {
+	QualityReportService services.QualityReportService
+}
+
+// NewQualityReportHandler creates a new QualityReportHandler.
+func NewQualityReportHandler(qualityReportService services.QualityReportService) *QualityReportHandler {
+	return &QualityReportHandler{QualityReportService: qualityReportService}
+}
+
+// GetQualityReports handles listing the archived quality reports.
+func (handler *QualityReportHandler) GetQualityReports(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	reports, err := handler.QualityReportService.GetArchivedQualityReports(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching archived quality reports")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(reports); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetQualityReports")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetQualityReport handles fetching the figures of the quality report of a month, given as YYYY-MM.
+func (handler *QualityReportHandler) GetQualityReport(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	month := request.PathValue("month")
+	report, err := handler.QualityReportService.GetQualityReport(logger, request.Context(), month)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "Invalid month, must be YYYY-MM and not in the future", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("month", month).Error("Internal server error building quality report")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(report); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetQualityReport")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DownloadQualityReport handles downloading the quality report of a month as PDF. Completed months are
+// served from the archive once archived, other months are generated on demand.
+func (handler *QualityReportHandler) DownloadQualityReport(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	month := request.PathValue("month")
+	report, err := handler.QualityReportService.GetQualityReportPDF(logger, request.Context(), month)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "Invalid month, must be YYYY-MM and not in the future", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("month", month).Error("Internal server error generating quality report")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/pdf")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"qualitaetsbericht_%s.pdf\"", report.Month))
+	if _, err := writer.Write(report.Content); err != nil {
+		logger.WithError(err).Error("Failed to write quality report")
+	}
+}
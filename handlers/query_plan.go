@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/services"
+)
+
+// QueryPlanHandler handles the query plan report used to review the database indexes.
+type QueryPlanHandler struct {
+	QueryPlanService services.QueryPlanService
+}
+
+// NewQueryPlanHandler creates a new QueryPlanHandler.
+func NewQueryPlanHandler(queryPlanService services.QueryPlanService) *QueryPlanHandler {
+	return &QueryPlanHandler{QueryPlanService: queryPlanService}
+}
+
+// GetQueryPlans handles reporting EXPLAIN QUERY PLAN for the hot queries of the application.
+func (handler *QueryPlanHandler) GetQueryPlans(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	plans, err := handler.QueryPlanService.GetQueryPlans(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching query plans")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(plans); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetQueryPlans")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
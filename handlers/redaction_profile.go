@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// RedactionProfileHandler handles report redaction profile HTTP requests.
+type RedactionProfileHandler struct {
+	RedactionProfileService services.RedactionProfileService
+}
+
+// NewRedactionProfileHandler creates a new RedactionProfileHandler.
+func NewRedactionProfileHandler(redactionProfileService services.RedactionProfileService) *RedactionProfileHandler {
+	return &RedactionProfileHandler{RedactionProfileService: redactionProfileService}
+}
+
+// CreateRedactionProfile handles creating a new redaction profile.
+func (handler *RedactionProfileHandler) CreateRedactionProfile(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var profile models.RedactionProfile
+	if err := json.NewDecoder(request.Body).Decode(&profile); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateRedactionProfile")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	createdProfile, err := handler.RedactionProfileService.CreateRedactionProfile(logger, request.Context(), &profile)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		case services.ErrAlreadyExists:
+			http.Error(writer, "Redaction profile with this name already exists", http.StatusConflict)
+		default:
+			logger.WithError(err).Error("Internal server error during redaction profile creation")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/redaction-profiles", createdProfile.ID)
+	if err := json.NewEncoder(writer).Encode(createdProfile); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateRedactionProfile")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAllRedactionProfiles handles fetching all redaction profiles.
+func (handler *RedactionProfileHandler) GetAllRedactionProfiles(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	profiles, err := handler.RedactionProfileService.GetAllRedactionProfiles(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching redaction profiles")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if profiles == nil {
+		profiles = []models.RedactionProfile{}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(profiles); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAllRedactionProfiles")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetRedactionProfileByID handles fetching a redaction profile by ID.
+func (handler *RedactionProfileHandler) GetRedactionProfileByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	profileIDStr := request.PathValue("redaction_profile_id")
+	profileID, err := strconv.Atoi(profileIDStr)
+	if err != nil {
+		logger.WithField("redaction_profile_id_str", profileIDStr).WithError(err).Warn("Invalid redaction profile ID format for GetRedactionProfileByID")
+		http.Error(writer, "Invalid redaction profile ID", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := handler.RedactionProfileService.GetRedactionProfileByID(logger, request.Context(), profileID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Redaction profile not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("redaction_profile_id", profileID).Error("Internal server error fetching redaction profile")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(profile); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetRedactionProfileByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateRedactionProfile handles updating an existing redaction profile.
+func (handler *RedactionProfileHandler) UpdateRedactionProfile(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	profileIDStr := request.PathValue("redaction_profile_id")
+	profileID, err := strconv.Atoi(profileIDStr)
+	if err != nil {
+		logger.WithField("redaction_profile_id_str", profileIDStr).WithError(err).Warn("Invalid redaction profile ID format for UpdateRedactionProfile")
+		http.Error(writer, "Invalid redaction profile ID", http.StatusBadRequest)
+		return
+	}
+
+	var profile models.RedactionProfile
+	if err := json.NewDecoder(request.Body).Decode(&profile); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateRedactionProfile")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	profile.ID = profileID
+
+	err = handler.RedactionProfileService.UpdateRedactionProfile(logger, request.Context(), &profile)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Redaction profile not found", http.StatusNotFound)
+		case services.ErrAlreadyExists:
+			http.Error(writer, "Redaction profile with this name already exists", http.StatusConflict)
+		default:
+			logger.WithError(err).WithField("redaction_profile_id", profileID).Error("Internal server error during redaction profile update")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Redaction profile updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateRedactionProfile")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteRedactionProfile handles deleting a redaction profile.
+func (handler *RedactionProfileHandler) DeleteRedactionProfile(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	profileIDStr := request.PathValue("redaction_profile_id")
+	profileID, err := strconv.Atoi(profileIDStr)
+	if err != nil {
+		logger.WithField("redaction_profile_id_str", profileIDStr).WithError(err).Warn("Invalid redaction profile ID format for DeleteRedactionProfile")
+		http.Error(writer, "Invalid redaction profile ID", http.StatusBadRequest)
+		return
+	}
+
+	err = handler.RedactionProfileService.DeleteRedactionProfile(logger, request.Context(), profileID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Redaction profile not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("redaction_profile_id", profileID).Error("Internal server error during redaction profile deletion")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
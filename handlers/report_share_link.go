@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// ReportShareLinkHandler handles the HTTP requests for sharing archived reports with external recipients.
+type ReportShareLinkHandler struct {
+	ReportShareLinkService services.ReportShareLinkService
+}
+
+// NewReportShareLinkHandler creates a new ReportShareLinkHandler.
+func NewReportShareLinkHandler(reportShareLinkService services.ReportShareLinkService) *ReportShareLinkHandler {
+	return &ReportShareLinkHandler{ReportShareLinkService: reportShareLinkService}
+}
+
+// CreateShareLinkRequest represents the request body for creating a share link.
+type CreateShareLinkRequest struct {
+	Recipient string `json:"recipient"`  // Who the link is handed to, e.g. "Frühförderstelle Musterstadt"
+	ValidDays int    `json:"valid_days"` // Optional, defaults to the configured maximum
+}
+
+// OpenSharedReportRequest represents the request body for opening a shared report. The token and the access code
+// are sent in the body, so that they do not end up in access logs.
+type OpenSharedReportRequest struct {
+	Token      string `json:"token"`
+	AccessCode string `json:"access_code"`
+}
+
+// CreateShareLink handles creating a share link to an archived report of a child.
+func (handler *ReportShareLinkHandler) CreateShareLink(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for CreateShareLink handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	childID, reportID, ok := reportPathValues(writer, request, "CreateShareLink")
+	if !ok {
+		return
+	}
+
+	var req CreateShareLinkRequest
+	if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateShareLink")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if req.ValidDays < 0 {
+		http.Error(writer, "Invalid valid_days, must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	validity := time.Duration(req.ValidDays) * 24 * time.Hour
+	created, err := handler.ReportShareLinkService.CreateShareLink(logger, request.Context(), childID, reportID, req.Recipient, validity, user.ID)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		if writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Report not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "Invalid share link data provided", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).WithField("report_id", reportID).Error("Internal server error during share link creation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, fmt.Sprintf("/api/v1/children/%d/reports/%d/share-links", childID, reportID), created.ShareLink.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateShareLink")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetShareLinks handles listing the share links of an archived report.
+func (handler *ReportShareLinkHandler) GetShareLinks(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, reportID, ok := reportPathValues(writer, request, "GetShareLinks")
+	if !ok {
+		return
+	}
+
+	links, err := handler.ReportShareLinkService.GetShareLinksForReport(logger, request.Context(), childID, reportID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Report not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("report_id", reportID).Error("Internal server error fetching share links")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(links); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetShareLinks")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetShareLinkAccesses handles fetching the access log of a share link.
+func (handler *ReportShareLinkHandler) GetShareLinkAccesses(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	shareLinkIDStr := request.PathValue("share_link_id")
+	shareLinkID, err := strconv.Atoi(shareLinkIDStr)
+	if err != nil {
+		logger.WithField("share_link_id_str", shareLinkIDStr).WithError(err).Warn("Invalid share link ID format for GetShareLinkAccesses")
+		http.Error(writer, "Invalid share link ID", http.StatusBadRequest)
+		return
+	}
+
+	accesses, err := handler.ReportShareLinkService.GetShareLinkAccesses(logger, request.Context(), shareLinkID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("share_link_id", shareLinkID).Error("Internal server error fetching share link accesses")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(accesses); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetShareLinkAccesses")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RevokeShareLink handles revoking a share link, it cannot be opened afterwards.
+func (handler *ReportShareLinkHandler) RevokeShareLink(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for RevokeShareLink handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	shareLinkIDStr := request.PathValue("share_link_id")
+	shareLinkID, err := strconv.Atoi(shareLinkIDStr)
+	if err != nil {
+		logger.WithField("share_link_id_str", shareLinkIDStr).WithError(err).Warn("Invalid share link ID format for RevokeShareLink")
+		http.Error(writer, "Invalid share link ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.ReportShareLinkService.RevokeShareLink(logger, request.Context(), shareLinkID, user.ID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("share_link_id", shareLinkID).Error("Internal server error during share link revocation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// OpenSharedReport handles downloading a shared report with the token of its link and the access code.
+func (handler *ReportShareLinkHandler) OpenSharedReport(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var req OpenSharedReportRequest
+	if err := json.NewDecoder(request.Body).Decode(&req); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for OpenSharedReport")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	report, err := handler.ReportShareLinkService.OpenSharedReport(logger, request.Context(), req.Token, req.AccessCode)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error opening shared report")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.wordprocessingml.document")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", report.FileName))
+	if _, err := writer.Write(report.Content); err != nil {
+		logger.WithField("report_id", report.ID).WithError(err).Error("Failed to write shared report to response")
+		return
+	}
+}
+
+// reportPathValues parses the child and report IDs of a report route, it answers 400 Bad Request if one is invalid.
+func reportPathValues(writer http.ResponseWriter, request *http.Request, handlerName string) (int, int, bool) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childIDStr := request.PathValue("child_id")
+	childID, err := strconv.Atoi(childIDStr)
+	if err != nil {
+		logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for " + handlerName)
+		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	reportIDStr := request.PathValue("report_id")
+	reportID, err := strconv.Atoi(reportIDStr)
+	if err != nil {
+		logger.WithField("report_id_str", reportIDStr).WithError(err).Warn("Invalid report ID format for " + handlerName)
+		http.Error(writer, "Invalid report ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return childID, reportID, true
+}
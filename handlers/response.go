@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// writeCreatedHeader answers 201 with the Location of the created resource, its ID below collection.
+// Create handlers respond with the complete persisted resource, as fetched again by the service.
+func writeCreatedHeader(writer http.ResponseWriter, collection string, id int) {
+	writer.Header().Set("Location", fmt.Sprintf("%s/%d", collection, id))
+	writer.WriteHeader(http.StatusCreated)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// Headers of the chunk requests, named like in the tus protocol.
+const (
+	uploadOffsetHeader   = "Upload-Offset"
+	uploadChecksumHeader = "Upload-Checksum"
+)
+
+// ResumableUploadHandler handles the requests for uploading files in chunks.
+type ResumableUploadHandler struct {
+	ResumableUploadService services.ResumableUploadService
+}
+
+// NewResumableUploadHandler creates a new ResumableUploadHandler.
+func NewResumableUploadHandler(resumableUploadService services.ResumableUploadService) *ResumableUploadHandler {
+	return &ResumableUploadHandler{ResumableUploadService: resumableUploadService}
+}
+
+// resumedUpload returns the completed resumable upload whose ID is sent in the "upload_id" form field instead of
+// the file, nil if the field is empty. It writes the error response and returns false if the upload cannot be used.
+func resumedUpload(writer http.ResponseWriter, request *http.Request, resumableUploadService services.ResumableUploadService, kind string) (*models.ResumableUpload, bool) {
+	uploadID := request.FormValue("upload_id")
+	if uploadID == "" {
+		return nil, true
+	}
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for resumed upload")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	upload, err := resumableUploadService.GetCompletedUpload(logger, request.Context(), uploadID, kind, user.ID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return nil, false
+		}
+		logger.WithError(err).Error("Internal server error fetching resumed upload")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return upload, true
+}
+
+// releaseResumedUpload deletes a resumable upload once its file has been stored. A failure is only logged, the
+// upload expires anyway.
+func releaseResumedUpload(request *http.Request, resumableUploadService services.ResumableUploadService, upload *models.ResumableUpload) {
+	if upload == nil {
+		return
+	}
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	if err := resumableUploadService.DeleteUpload(logger, request.Context(), upload.ID, upload.CreatedByUserID); err != nil {
+		logger.WithError(err).Warn("Failed to delete used resumable upload")
+	}
+}
+
+// CreateUpload handles starting a resumable upload. The client announces the kind, the name, the type, the size
+// and the SHA-256 checksum (hex) of the file and sends its chunks to the returned upload.
+func (handler *ResumableUploadHandler) CreateUpload(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for CreateUpload handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var upload models.ResumableUpload
+	if err := json.NewDecoder(request.Body).Decode(&upload); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateUpload")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	created, err := handler.ResumableUploadService.CreateUpload(logger, request.Context(), &upload, user.ID)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error creating resumable upload")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Location", "/api/v1/uploads/"+created.ID)
+	writer.Header().Set(uploadOffsetHeader, strconv.Itoa(created.Offset))
+	writer.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateUpload")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetUpload handles fetching the state of an upload, e.g. to learn the offset to resume at after a lost
+// connection.
+func (handler *ResumableUploadHandler) GetUpload(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for GetUpload handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	upload, err := handler.ResumableUploadService.GetUpload(logger, request.Context(), request.PathValue("upload_id"), user.ID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error fetching resumable upload")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set(uploadOffsetHeader, strconv.Itoa(upload.Offset))
+	if err := json.NewEncoder(writer).Encode(upload); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetUpload")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// AppendChunk handles a chunk of an upload. The body is the raw chunk, the Upload-Offset header the offset it
+// starts at and the optional Upload-Checksum header its checksum as "sha256 <base64>". A chunk at another offset
+// than the received one is rejected with the received offset in the Upload-Offset header.
+func (handler *ResumableUploadHandler) AppendChunk(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for AppendChunk handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	uploadID := request.PathValue("upload_id")
+
+	offset, err := strconv.Atoi(request.Header.Get(uploadOffsetHeader))
+	if err != nil || offset < 0 {
+		http.Error(writer, "Invalid or missing Upload-Offset header", http.StatusBadRequest)
+		return
+	}
+	var checksum []byte
+	if value := request.Header.Get(uploadChecksumHeader); value != "" {
+		algorithm, encoded, _ := strings.Cut(value, " ")
+		checksum, err = base64.StdEncoding.DecodeString(encoded)
+		if algorithm != "sha256" || err != nil {
+			http.Error(writer, "Invalid Upload-Checksum header, must be \"sha256 <base64>\"", http.StatusBadRequest)
+			return
+		}
+	}
+	request.Body = http.MaxBytesReader(writer, request.Body, services.MaxUploadChunkSize)
+	chunk, err := io.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, "Failed to read chunk, chunks are at most 4 MB", http.StatusBadRequest)
+		return
+	}
+
+	upload, err := handler.ResumableUploadService.AppendChunk(logger, request.Context(), uploadID, offset, chunk, checksum, user.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrUploadOffsetMismatch) {
+			writer.Header().Set(uploadOffsetHeader, strconv.Itoa(upload.Offset))
+		}
+		if writeDomainError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "Invalid chunk, it must not be empty or extend beyond the announced size", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error storing chunk of resumable upload")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set(uploadOffsetHeader, strconv.Itoa(upload.Offset))
+	if err := json.NewEncoder(writer).Encode(upload); err != nil {
+		logger.WithError(err).Error("Failed to encode response for AppendChunk")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteUpload handles abandoning an upload.
+func (handler *ResumableUploadHandler) DeleteUpload(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for DeleteUpload handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := handler.ResumableUploadService.DeleteUpload(logger, request.Context(), request.PathValue("upload_id"), user.ID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error deleting resumable upload")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/buildinfo"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+)
+
+// RoutePolicySource provides the effective route policies.
+type RoutePolicySource interface {
+	Policies() []models.RoutePolicy
+}
+
+// RoutePolicyHandler handles the route policy dump used for security reviews.
+type RoutePolicyHandler struct {
+	PolicySource RoutePolicySource
+}
+
+// NewRoutePolicyHandler creates a new RoutePolicyHandler.
+func NewRoutePolicyHandler(policySource RoutePolicySource) *RoutePolicyHandler {
+	return &RoutePolicyHandler{PolicySource: policySource}
+}
+
+// GetRoutePolicies handles dumping the route to role matrix that is enforced by the router.
+func (handler *RoutePolicyHandler) GetRoutePolicies(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(handler.PolicySource.Policies()); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetRoutePolicies")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetOpenAPIDocument handles serving the OpenAPI document of the routes the caller's role may use.
+// Admins can select another role with the optional query parameter role, e.g. to generate the teacher client.
+func (handler *RoutePolicyHandler) GetOpenAPIDocument(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for GetOpenAPIDocument handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	role := user.Role
+	if requested := request.URL.Query().Get("role"); requested != "" && requested != role {
+		if role != string(data.RoleAdmin) {
+			http.Error(writer, "Forbidden: Only admins can select another role", http.StatusForbidden)
+			return
+		}
+		if !slices.Contains(data.Roles, data.Role(requested)) {
+			http.Error(writer, "Invalid role", http.StatusBadRequest)
+			return
+		}
+		role = requested
+	}
+
+	document := models.NewOpenAPIDocument(buildinfo.Get().Version, handler.PolicySource.Policies(), role, openAPIErrorCodes())
+	if err := json.NewEncoder(writer).Encode(document); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetOpenAPIDocument")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
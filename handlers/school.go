@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// SchoolHandler handles school directory HTTP requests.
+type SchoolHandler struct {
+	SchoolService services.SchoolService
+}
+
+// NewSchoolHandler creates a new SchoolHandler.
+func NewSchoolHandler(schoolService services.SchoolService) *SchoolHandler {
+	return &SchoolHandler{SchoolService: schoolService}
+}
+
+// CreateSchool handles creating a new school.
+func (handler *SchoolHandler) CreateSchool(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var school models.School
+	if err := json.NewDecoder(request.Body).Decode(&school); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateSchool")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	createdSchool, err := handler.SchoolService.CreateSchool(logger, request.Context(), &school)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		case services.ErrAlreadyExists:
+			http.Error(writer, "School with this name already exists", http.StatusConflict)
+		default:
+			logger.WithError(err).Error("Internal server error during school creation")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/schools", createdSchool.ID)
+	if err := json.NewEncoder(writer).Encode(createdSchool); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateSchool")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAllSchools handles fetching all schools.
+func (handler *SchoolHandler) GetAllSchools(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	schools, err := handler.SchoolService.GetAllSchools(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching schools")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if schools == nil {
+		schools = []models.School{}
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(schools); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAllSchools")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetSchoolByID handles fetching a school by ID.
+func (handler *SchoolHandler) GetSchoolByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	schoolID, ok := parseSchoolID(writer, request, "GetSchoolByID")
+	if !ok {
+		return
+	}
+
+	school, err := handler.SchoolService.GetSchoolByID(logger, request.Context(), schoolID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "School not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("school_id", schoolID).Error("Internal server error fetching school")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(school); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetSchoolByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateSchool handles updating an existing school.
+func (handler *SchoolHandler) UpdateSchool(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	schoolID, ok := parseSchoolID(writer, request, "UpdateSchool")
+	if !ok {
+		return
+	}
+
+	var school models.School
+	if err := json.NewDecoder(request.Body).Decode(&school); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateSchool")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	school.ID = schoolID
+
+	err := handler.SchoolService.UpdateSchool(logger, request.Context(), &school)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "School not found", http.StatusNotFound)
+		case services.ErrAlreadyExists:
+			http.Error(writer, "School with this name already exists", http.StatusConflict)
+		default:
+			logger.WithError(err).WithField("school_id", schoolID).Error("Internal server error during school update")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "School updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateSchool")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteSchool handles deleting a school.
+func (handler *SchoolHandler) DeleteSchool(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	schoolID, ok := parseSchoolID(writer, request, "DeleteSchool")
+	if !ok {
+		return
+	}
+
+	err := handler.SchoolService.DeleteSchool(logger, request.Context(), schoolID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "School not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("school_id", schoolID).Error("Internal server error during school deletion")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// LinkChild handles recording the school a child is expected to enroll at.
+func (handler *SchoolHandler) LinkChild(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	schoolID, ok := parseSchoolID(writer, request, "LinkChild")
+	if !ok {
+		return
+	}
+	childIDStr := request.PathValue("child_id")
+	childID, err := strconv.Atoi(childIDStr)
+	if err != nil {
+		logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for LinkChild")
+		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+		return
+	}
+
+	school, err := handler.SchoolService.LinkChild(logger, request.Context(), schoolID, childID)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, "Only children with an expected school enrollment that are not archived can be linked to a school", http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "School or child not found", http.StatusNotFound)
+		default:
+			logger.WithError(err).WithField("school_id", schoolID).Error("Internal server error linking child to school")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(school); err != nil {
+		logger.WithError(err).Error("Failed to encode response for LinkChild")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UnlinkChild handles removing the link between a child and a school.
+func (handler *SchoolHandler) UnlinkChild(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	schoolID, ok := parseSchoolID(writer, request, "UnlinkChild")
+	if !ok {
+		return
+	}
+	childIDStr := request.PathValue("child_id")
+	childID, err := strconv.Atoi(childIDStr)
+	if err != nil {
+		logger.WithField("child_id_str", childIDStr).WithError(err).Warn("Invalid child ID format for UnlinkChild")
+		http.Error(writer, "Invalid child ID", http.StatusBadRequest)
+		return
+	}
+
+	err = handler.SchoolService.UnlinkChild(logger, request.Context(), schoolID, childID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Child is not linked to this school", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("school_id", schoolID).Error("Internal server error unlinking child from school")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetSchoolChildren handles fetching the children expected to enroll at a school.
+// The optional query parameter preschoolers=true restricts the result to the Vorschul cohort.
+func (handler *SchoolHandler) GetSchoolChildren(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	schoolID, ok := parseSchoolID(writer, request, "GetSchoolChildren")
+	if !ok {
+		return
+	}
+	preschoolersOnly := false
+	if preschoolersStr := request.URL.Query().Get("preschoolers"); preschoolersStr != "" {
+		var err error
+		preschoolersOnly, err = strconv.ParseBool(preschoolersStr)
+		if err != nil {
+			logger.WithField("preschoolers_str", preschoolersStr).WithError(err).Warn("Invalid preschoolers value for GetSchoolChildren")
+			http.Error(writer, "Invalid preschoolers value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	children, err := handler.SchoolService.GetSchoolChildren(logger, request.Context(), schoolID, preschoolersOnly)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "School not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("school_id", schoolID).Error("Internal server error fetching children of school")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(children); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetSchoolChildren")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseSchoolID reads the school ID path value and answers with 400 if it is invalid.
+func parseSchoolID(writer http.ResponseWriter, request *http.Request, handlerName string) (int, bool) {
+	schoolIDStr := request.PathValue("school_id")
+	schoolID, err := strconv.Atoi(schoolIDStr)
+	if err != nil {
+		middleware.GetLoggerWithReqID(request.Context()).WithField("school_id_str", schoolIDStr).WithError(err).Warn("Invalid school ID format for " + handlerName)
+		http.Error(writer, "Invalid school ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return schoolID, true
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// SchoolYearHandler handles school year transition requests.
+type SchoolYearHandler struct {
+	SchoolYearService services.SchoolYearService
+}
+
+// NewSchoolYearHandler creates a new SchoolYearHandler.
+func NewSchoolYearHandler(schoolYearService services.SchoolYearService) *SchoolYearHandler {
+	return &SchoolYearHandler{SchoolYearService: schoolYearService}
+}
+
+// Rollover handles the end-of-year rollover. Use "dry_run": true to review the changes first.
+func (handler *SchoolYearHandler) Rollover(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var rolloverRequest models.RolloverRequest
+	if err := json.NewDecoder(request.Body).Decode(&rolloverRequest); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for Rollover")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := handler.SchoolYearService.Rollover(logger, request.Context(), &rolloverRequest)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, "Invalid rollover request", http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Departing child not found or already archived", http.StatusNotFound)
+		default:
+			logger.WithError(err).Error("Internal server error during school year rollover")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(summary); err != nil {
+		logger.WithError(err).Error("Failed to encode response for Rollover")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
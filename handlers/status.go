@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// StatusHandler handles requests for the public server status and its banner.
+type StatusHandler struct {
+	StatusService services.StatusService
+}
+
+// NewStatusHandler creates a new StatusHandler.
+func NewStatusHandler(statusService services.StatusService) *StatusHandler {
+	return &StatusHandler{StatusService: statusService}
+}
+
+// GetStatus handles fetching the version, the enabled features and the active banner. It needs no login,
+// the frontend shows the banner on the login page.
+func (handler *StatusHandler) GetStatus(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	status := handler.StatusService.GetStatus(logger, request.Context())
+
+	writer.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(writer).Encode(status); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetStatus")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetBanner handles fetching the configured banner, also when it is not active.
+func (handler *StatusHandler) GetBanner(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	banner, err := handler.StatusService.GetBanner(logger, request.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "No banner configured", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).Error("Internal server error fetching status banner")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(banner); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetBanner")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetBanner handles replacing the banner.
+func (handler *StatusHandler) SetBanner(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for SetBanner handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var banner models.StatusBanner
+	if err := json.NewDecoder(request.Body).Decode(&banner); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for SetBanner")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := handler.StatusService.SetBanner(logger, request.Context(), user.ID, &banner)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, "Invalid banner, it must end after it starts", http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error setting status banner")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(updated); err != nil {
+		logger.WithError(err).Error("Failed to encode response for SetBanner")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteBanner handles removing the banner.
+func (handler *StatusHandler) DeleteBanner(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	if err := handler.StatusService.DeleteBanner(logger, request.Context()); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "No banner configured", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).Error("Internal server error deleting status banner")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// SupportProviderHandler handles the HTTP requests for external therapy and support providers and the supports of children.
+type SupportProviderHandler struct {
+	SupportProviderService services.SupportProviderService
+}
+
+// NewSupportProviderHandler creates a new SupportProviderHandler.
+func NewSupportProviderHandler(supportProviderService services.SupportProviderService) *SupportProviderHandler {
+	return &SupportProviderHandler{SupportProviderService: supportProviderService}
+}
+
+// CreateSupportProvider handles creating a new support provider.
+func (handler *SupportProviderHandler) CreateSupportProvider(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var provider models.SupportProvider
+	if err := json.NewDecoder(request.Body).Decode(&provider); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateSupportProvider")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	created, err := handler.SupportProviderService.CreateSupportProvider(logger, request.Context(), &provider)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		case services.ErrAlreadyExists:
+			http.Error(writer, "Support provider with this name already exists", http.StatusConflict)
+		default:
+			logger.WithError(err).Error("Internal server error during support provider creation")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/support-providers", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateSupportProvider")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAllSupportProviders handles fetching all support providers.
+func (handler *SupportProviderHandler) GetAllSupportProviders(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	providers, err := handler.SupportProviderService.GetAllSupportProviders(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching support providers")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(providers); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAllSupportProviders")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetSupportProviderByID handles fetching a support provider by ID.
+func (handler *SupportProviderHandler) GetSupportProviderByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	providerID, ok := parsePathID(writer, request, "provider_id", "GetSupportProviderByID")
+	if !ok {
+		return
+	}
+
+	provider, err := handler.SupportProviderService.GetSupportProviderByID(logger, request.Context(), providerID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Support provider not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("provider_id", providerID).Error("Internal server error fetching support provider")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(provider); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetSupportProviderByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateSupportProvider handles updating an existing support provider.
+func (handler *SupportProviderHandler) UpdateSupportProvider(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	providerID, ok := parsePathID(writer, request, "provider_id", "UpdateSupportProvider")
+	if !ok {
+		return
+	}
+
+	var provider models.SupportProvider
+	if err := json.NewDecoder(request.Body).Decode(&provider); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateSupportProvider")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	provider.ID = providerID
+
+	err := handler.SupportProviderService.UpdateSupportProvider(logger, request.Context(), &provider)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Support provider not found", http.StatusNotFound)
+		case services.ErrAlreadyExists:
+			http.Error(writer, "Support provider with this name already exists", http.StatusConflict)
+		default:
+			logger.WithError(err).WithField("provider_id", providerID).Error("Internal server error during support provider update")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Support provider updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateSupportProvider")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteSupportProvider handles deleting a support provider.
+func (handler *SupportProviderHandler) DeleteSupportProvider(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	providerID, ok := parsePathID(writer, request, "provider_id", "DeleteSupportProvider")
+	if !ok {
+		return
+	}
+
+	err := handler.SupportProviderService.DeleteSupportProvider(logger, request.Context(), providerID)
+	if err != nil {
+		switch err {
+		case services.ErrNotFound:
+			http.Error(writer, "Support provider not found", http.StatusNotFound)
+		case services.ErrForeignKeyConstraint:
+			http.Error(writer, "Cannot delete support provider: children are supported by it, remove their supports first", http.StatusConflict)
+		default:
+			logger.WithError(err).WithField("provider_id", providerID).Error("Internal server error during support provider deletion")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// CreateChildSupport handles recording that a child is supported by a provider.
+func (handler *SupportProviderHandler) CreateChildSupport(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "CreateChildSupport")
+	if !ok {
+		return
+	}
+
+	var support models.ChildSupport
+	if err := json.NewDecoder(request.Body).Decode(&support); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateChildSupport")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	support.ChildID = childID
+
+	created, err := handler.SupportProviderService.CreateChildSupport(logger, request.Context(), &support)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, "Support provider not found", http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Child not found", http.StatusNotFound)
+		default:
+			logger.WithError(err).WithField("child_id", childID).Error("Internal server error during child support creation")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeCreatedHeader(writer, fmt.Sprintf("/api/v1/children/%d/supports", childID), created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateChildSupport")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetChildSupports handles fetching the supports of a child.
+func (handler *SupportProviderHandler) GetChildSupports(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetChildSupports")
+	if !ok {
+		return
+	}
+
+	supports, err := handler.SupportProviderService.GetChildSupports(logger, request.Context(), childID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Child not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching child supports")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(supports); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetChildSupports")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateChildSupport handles updating a support of a child.
+func (handler *SupportProviderHandler) UpdateChildSupport(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "UpdateChildSupport")
+	if !ok {
+		return
+	}
+	supportID, ok := parsePathID(writer, request, "support_id", "UpdateChildSupport")
+	if !ok {
+		return
+	}
+
+	var support models.ChildSupport
+	if err := json.NewDecoder(request.Body).Decode(&support); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateChildSupport")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	support.ID = supportID
+	support.ChildID = childID
+
+	err := handler.SupportProviderService.UpdateChildSupport(logger, request.Context(), &support)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		switch err {
+		case services.ErrInvalidInput:
+			http.Error(writer, "Support provider not found", http.StatusBadRequest)
+		case services.ErrNotFound:
+			http.Error(writer, "Child support not found", http.StatusNotFound)
+		default:
+			logger.WithError(err).WithField("support_id", supportID).Error("Internal server error during child support update")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Child support updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateChildSupport")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteChildSupport handles deleting a support of a child.
+func (handler *SupportProviderHandler) DeleteChildSupport(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "DeleteChildSupport")
+	if !ok {
+		return
+	}
+	supportID, ok := parsePathID(writer, request, "support_id", "DeleteChildSupport")
+	if !ok {
+		return
+	}
+
+	err := handler.SupportProviderService.DeleteChildSupport(logger, request.Context(), childID, supportID)
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Child support not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("support_id", supportID).Error("Internal server error during child support deletion")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// parsePathID reads an ID path value and answers with 400 if it is invalid.
+func parsePathID(writer http.ResponseWriter, request *http.Request, name string, handlerName string) (int, bool) {
+	idStr := request.PathValue(name)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		middleware.GetLoggerWithReqID(request.Context()).WithField(name+"_str", idStr).WithError(err).Warn("Invalid " + name + " format for " + handlerName)
+		http.Error(writer, "Invalid "+strings.ReplaceAll(strings.TrimSuffix(name, "_id"), "_", " ")+" ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
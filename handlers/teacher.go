@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
-	"time"
 
 	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
@@ -31,11 +30,11 @@ func (teacherHandler *TeacherHandler) CreateTeacher(writer http.ResponseWriter,
 		return
 	}
 
-	teacher.CreatedAt = time.Now()
-	teacher.UpdatedAt = time.Now()
-
-	createdTeacher, err := teacherHandler.TeacherService.CreateTeacher(&teacher)
+	createdTeacher, err := teacherHandler.TeacherService.CreateTeacher(request.Context(), &teacher)
 	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
 		if err == services.ErrInvalidInput {
 			http.Error(writer, "Invalid teacher data provided", http.StatusBadRequest)
 			return
@@ -44,17 +43,27 @@ func (teacherHandler *TeacherHandler) CreateTeacher(writer http.ResponseWriter,
 		return
 	}
 
-	writer.WriteHeader(http.StatusCreated)
+	writeCreatedHeader(writer, "/api/v1/teachers", createdTeacher.ID)
 	if err := json.NewEncoder(writer).Encode(createdTeacher); err != nil {
 		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
-// GetAllTeachers handles fetching all teachers.
+// GetAllTeachers handles fetching all teachers. Deactivated teachers are only listed with include_deactivated=true.
 func (teacherHandler *TeacherHandler) GetAllTeachers(writer http.ResponseWriter, request *http.Request) {
 	logger := middleware.GetLoggerWithReqID(request.Context())
-	teachers, err := teacherHandler.TeacherService.GetAllTeachers()
+	includeDeactivated := false
+	if includeStr := request.URL.Query().Get("include_deactivated"); includeStr != "" {
+		include, err := strconv.ParseBool(includeStr)
+		if err != nil {
+			http.Error(writer, "Invalid include_deactivated value", http.StatusBadRequest)
+			return
+		}
+		includeDeactivated = include
+	}
+
+	teachers, err := teacherHandler.TeacherService.GetAllTeachers(request.Context(), includeDeactivated)
 	if err != nil {
 		logger.Errorf("Error fetching all teachers: %v", err)
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
@@ -76,7 +85,7 @@ func (teacherHandler *TeacherHandler) GetTeacherByID(writer http.ResponseWriter,
 		return
 	}
 
-	teacher, err := teacherHandler.TeacherService.GetTeacherByID(id)
+	teacher, err := teacherHandler.TeacherService.GetTeacherByID(request.Context(), id)
 	if err != nil {
 		if err == services.ErrNotFound {
 			http.Error(writer, "Teacher not found", http.StatusNotFound)
@@ -108,10 +117,12 @@ func (teacherHandler *TeacherHandler) UpdateTeacher(writer http.ResponseWriter,
 	}
 
 	teacher.ID = id
-	teacher.UpdatedAt = time.Now()
 
-	err = teacherHandler.TeacherService.UpdateTeacher(&teacher)
+	err = teacherHandler.TeacherService.UpdateTeacher(request.Context(), &teacher)
 	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
 		if err == services.ErrNotFound {
 			http.Error(writer, "Teacher not found", http.StatusNotFound)
 			return
@@ -140,14 +151,14 @@ func (teacherHandler *TeacherHandler) DeleteTeacher(writer http.ResponseWriter,
 		return
 	}
 
-	err = teacherHandler.TeacherService.DeleteTeacher(id)
+	err = teacherHandler.TeacherService.DeleteTeacher(request.Context(), id)
 	if err != nil {
 		switch err {
 		case services.ErrNotFound:
 			http.Error(writer, "Teacher not found", http.StatusNotFound)
 			return
 		case services.ErrForeignKeyConstraint:
-			http.Error(writer, "Cannot delete teacher: foreign key constraint violation", http.StatusConflict)
+			http.Error(writer, "Cannot delete teacher: foreign key constraint violation, deactivate the teacher instead", http.StatusConflict)
 			return
 		}
 		http.Error(writer, "Internal server error", http.StatusInternalServerError)
@@ -160,3 +171,81 @@ func (teacherHandler *TeacherHandler) DeleteTeacher(writer http.ResponseWriter,
 		return
 	}
 }
+
+// DeactivateTeacher handles deactivating a teacher who left, instead of deleting the teacher.
+func (teacherHandler *TeacherHandler) DeactivateTeacher(writer http.ResponseWriter, request *http.Request) {
+	teacherHandler.setDeactivated(writer, request, true)
+}
+
+// ReactivateTeacher handles reverting the deactivation of a teacher.
+func (teacherHandler *TeacherHandler) ReactivateTeacher(writer http.ResponseWriter, request *http.Request) {
+	teacherHandler.setDeactivated(writer, request, false)
+}
+
+func (teacherHandler *TeacherHandler) setDeactivated(writer http.ResponseWriter, request *http.Request, deactivate bool) {
+	idStr := request.PathValue("teacher_id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(writer, "Invalid teacher ID", http.StatusBadRequest)
+		return
+	}
+
+	message := "Teacher reactivated successfully"
+	if deactivate {
+		err = teacherHandler.TeacherService.DeactivateTeacher(request.Context(), id)
+		message = "Teacher deactivated successfully"
+	} else {
+		err = teacherHandler.TeacherService.ReactivateTeacher(request.Context(), id)
+	}
+	if err != nil {
+		if err == services.ErrNotFound {
+			http.Error(writer, "Teacher not found", http.StatusNotFound)
+			return
+		}
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": message}); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// MergeTeacher handles merging an accidentally duplicated teacher record into the teacher of the path.
+func (teacherHandler *TeacherHandler) MergeTeacher(writer http.ResponseWriter, request *http.Request) {
+	idStr := request.PathValue("teacher_id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(writer, "Invalid teacher ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody struct {
+		DuplicateTeacherID int `json:"duplicate_teacher_id"`
+	}
+	if err := json.NewDecoder(request.Body).Decode(&requestBody); err != nil || requestBody.DuplicateTeacherID == 0 {
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	err = teacherHandler.TeacherService.MergeTeachers(request.Context(), requestBody.DuplicateTeacherID, id)
+	if err != nil {
+		switch err {
+		case services.ErrNotFound:
+			http.Error(writer, "Teacher not found", http.StatusNotFound)
+		case services.ErrInvalidInput:
+			http.Error(writer, "A teacher cannot be merged into itself", http.StatusBadRequest)
+		default:
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Teachers merged successfully"}); err != nil {
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
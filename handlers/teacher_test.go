@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -41,7 +42,7 @@ func TestCreateTeacher(t *testing.T) {
 		}
 
 		createdTime := time.Now()
-		mockService.On("CreateTeacher", mock.AnythingOfType("*models.Teacher")).Return(&models.Teacher{
+		mockService.On("CreateTeacher", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(&models.Teacher{
 			ID:        1,
 			FirstName: "John",
 			LastName:  "Doe",
@@ -101,7 +102,7 @@ func TestCreateTeacher(t *testing.T) {
 			Username:  "johndoe",
 		}
 
-		mockService.On("CreateTeacher", mock.AnythingOfType("*models.Teacher")).Return(nil, services.ErrInvalidInput).Once()
+		mockService.On("CreateTeacher", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(nil, services.ErrInvalidInput).Once()
 
 		var reqBody bytes.Buffer
 		json.NewEncoder(&reqBody).Encode(inputTeacher) //nolint:errcheck
@@ -129,7 +130,7 @@ func TestCreateTeacher(t *testing.T) {
 			Username:  "johndoe",
 		}
 
-		mockService.On("CreateTeacher", mock.AnythingOfType("*models.Teacher")).Return(nil, errors.New("database error")).Once()
+		mockService.On("CreateTeacher", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(nil, errors.New("database error")).Once()
 
 		var reqBody bytes.Buffer
 		json.NewEncoder(&reqBody).Encode(inputTeacher) //nolint:errcheck
@@ -155,7 +156,7 @@ func TestGetAllTeachers(t *testing.T) {
 		mockService := new(mocks.MockTeacherService)
 		handler := NewTeacherHandler(mockService)
 
-		mockService.On("GetAllTeachers").Return([]models.Teacher{
+		mockService.On("GetAllTeachers", mock.Anything, false).Return([]models.Teacher{
 			{ID: 1, FirstName: "Jane", LastName: "Smith", Username: "janesmith"},
 			{ID: 2, FirstName: "Peter", LastName: "Jones", Username: "peterjones"},
 		}, nil).Once()
@@ -176,7 +177,7 @@ func TestGetAllTeachers(t *testing.T) {
 		mockService := new(mocks.MockTeacherService)
 		handler := NewTeacherHandler(mockService)
 
-		mockService.On("GetAllTeachers").Return(nil, errors.New("database error")).Once()
+		mockService.On("GetAllTeachers", mock.Anything, false).Return(nil, errors.New("database error")).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/teachers", nil)
 		req = req.WithContext(context.WithValue(req.Context(), testutils.ContextKeyLogger, logger))
@@ -198,7 +199,7 @@ func TestGetTeacherByID(t *testing.T) {
 		mockService := new(mocks.MockTeacherService)
 		handler := NewTeacherHandler(mockService)
 
-		mockService.On("GetTeacherByID", 1).Return(&models.Teacher{ID: 1, FirstName: "John", Username: "johndoe"}, nil).Once()
+		mockService.On("GetTeacherByID", mock.Anything, 1).Return(&models.Teacher{ID: 1, FirstName: "John", Username: "johndoe"}, nil).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/teachers/1", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -236,7 +237,7 @@ func TestGetTeacherByID(t *testing.T) {
 		mockService := new(mocks.MockTeacherService)
 		handler := NewTeacherHandler(mockService)
 
-		mockService.On("GetTeacherByID", 99).Return(nil, services.ErrNotFound).Once()
+		mockService.On("GetTeacherByID", mock.Anything, 99).Return(nil, services.ErrNotFound).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/teachers/99", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -256,7 +257,7 @@ func TestGetTeacherByID(t *testing.T) {
 		mockService := new(mocks.MockTeacherService)
 		handler := NewTeacherHandler(mockService)
 
-		mockService.On("GetTeacherByID", 1).Return(nil, errors.New("database error")).Once()
+		mockService.On("GetTeacherByID", mock.Anything, 1).Return(nil, errors.New("database error")).Once()
 
 		req := httptest.NewRequest(http.MethodGet, "/teachers/1", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -286,7 +287,7 @@ func TestUpdateTeacher(t *testing.T) {
 			LastName:  "Teacher",
 		}
 
-		mockService.On("UpdateTeacher", mock.AnythingOfType("*models.Teacher")).Return(nil).Once()
+		mockService.On("UpdateTeacher", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(nil).Once()
 
 		var reqBody bytes.Buffer
 		json.NewEncoder(&reqBody).Encode(inputTeacher) //nolint:errcheck
@@ -352,7 +353,7 @@ func TestUpdateTeacher(t *testing.T) {
 			ID: 99, FirstName: "NonExistent", LastName: "Teacher",
 		}
 
-		mockService.On("UpdateTeacher", mock.AnythingOfType("*models.Teacher")).Return(services.ErrNotFound).Once()
+		mockService.On("UpdateTeacher", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(services.ErrNotFound).Once()
 
 		var reqBody bytes.Buffer
 		json.NewEncoder(&reqBody).Encode(inputTeacher) //nolint:errcheck
@@ -380,7 +381,7 @@ func TestUpdateTeacher(t *testing.T) {
 			ID: 1, FirstName: "", LastName: "Teacher",
 		}
 
-		mockService.On("UpdateTeacher", mock.AnythingOfType("*models.Teacher")).Return(services.ErrInvalidInput).Once()
+		mockService.On("UpdateTeacher", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(services.ErrInvalidInput).Once()
 
 		var reqBody bytes.Buffer
 		json.NewEncoder(&reqBody).Encode(inputTeacher) //nolint:errcheck
@@ -408,7 +409,7 @@ func TestUpdateTeacher(t *testing.T) {
 			ID: 1, FirstName: "Test", LastName: "Teacher",
 		}
 
-		mockService.On("UpdateTeacher", mock.AnythingOfType("*models.Teacher")).Return(errors.New("database error")).Once()
+		mockService.On("UpdateTeacher", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(errors.New("database error")).Once()
 
 		var reqBody bytes.Buffer
 		json.NewEncoder(&reqBody).Encode(inputTeacher) //nolint:errcheck
@@ -436,7 +437,7 @@ func TestDeleteTeacher(t *testing.T) {
 		mockService := new(mocks.MockTeacherService)
 		handler := NewTeacherHandler(mockService)
 
-		mockService.On("DeleteTeacher", 1).Return(nil).Once()
+		mockService.On("DeleteTeacher", mock.Anything, 1).Return(nil).Once()
 
 		req := httptest.NewRequest(http.MethodDelete, "/teachers/1", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -474,7 +475,7 @@ func TestDeleteTeacher(t *testing.T) {
 		mockService := new(mocks.MockTeacherService)
 		handler := NewTeacherHandler(mockService)
 
-		mockService.On("DeleteTeacher", 99).Return(services.ErrNotFound).Once()
+		mockService.On("DeleteTeacher", mock.Anything, 99).Return(services.ErrNotFound).Once()
 
 		req := httptest.NewRequest(http.MethodDelete, "/teachers/99", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -494,7 +495,7 @@ func TestDeleteTeacher(t *testing.T) {
 		mockService := new(mocks.MockTeacherService)
 		handler := NewTeacherHandler(mockService)
 
-		mockService.On("DeleteTeacher", 1).Return(errors.New("database error")).Once()
+		mockService.On("DeleteTeacher", mock.Anything, 1).Return(errors.New("database error")).Once()
 
 		req := httptest.NewRequest(http.MethodDelete, "/teachers/1", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -514,7 +515,7 @@ func TestDeleteTeacher(t *testing.T) {
 		mockService := new(mocks.MockTeacherService)
 		handler := NewTeacherHandler(mockService)
 
-		mockService.On("DeleteTeacher", 2).Return(services.ErrForeignKeyConstraint).Once()
+		mockService.On("DeleteTeacher", mock.Anything, 2).Return(services.ErrForeignKeyConstraint).Once()
 
 		req := httptest.NewRequest(http.MethodDelete, "/teachers/2", nil)
 		ctx := context.WithValue(req.Context(), testutils.ContextKeyLogger, logger)
@@ -525,8 +526,65 @@ func TestDeleteTeacher(t *testing.T) {
 		handler.DeleteTeacher(recorder, req)
 
 		assert.Equal(t, http.StatusConflict, recorder.Code)
-		assert.Equal(t, "Cannot delete teacher: foreign key constraint violation\n", recorder.Body.String())
+		assert.Equal(t, "Cannot delete teacher: foreign key constraint violation, deactivate the teacher instead\n", recorder.Body.String())
 
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestMergeTeacher(t *testing.T) {
+	tests := []struct {
+		name           string
+		teacherID      string
+		body           string
+		setupMock      func(mockService *mocks.MockTeacherService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:      "Successful Merge",
+			teacherID: "1",
+			body:      `{"duplicate_teacher_id": 2}`,
+			setupMock: func(mockService *mocks.MockTeacherService) {
+				mockService.On("MergeTeachers", mock.Anything, 2, 1).Return(nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"message":"Teachers merged successfully"}` + "\n",
+		},
+		{
+			name:           "Missing Duplicate",
+			teacherID:      "1",
+			body:           `{}`,
+			setupMock:      func(mockService *mocks.MockTeacherService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request payload\n",
+		},
+		{
+			name:      "Teacher Not Found",
+			teacherID: "1",
+			body:      `{"duplicate_teacher_id": 99}`,
+			setupMock: func(mockService *mocks.MockTeacherService) {
+				mockService.On("MergeTeachers", mock.Anything, 99, 1).Return(services.ErrNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Teacher not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockTeacherService)
+			handler := NewTeacherHandler(mockService)
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/teachers/"+tt.teacherID+"/merge", strings.NewReader(tt.body))
+			req.SetPathValue("teacher_id", tt.teacherID)
+			recorder := httptest.NewRecorder()
+			handler.MergeTeacher(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.Equal(t, tt.expectedBody, recorder.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
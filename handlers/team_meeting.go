@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// TeamMeetingHandler handles the HTTP requests for the minutes of team meetings and their action items.
+type TeamMeetingHandler struct {
+	TeamMeetingService services.TeamMeetingService
+}
+
+// NewTeamMeetingHandler creates a new TeamMeetingHandler.
+func NewTeamMeetingHandler(teamMeetingService services.TeamMeetingService) *TeamMeetingHandler {
+	return &TeamMeetingHandler{TeamMeetingService: teamMeetingService}
+}
+
+// CreateMeeting handles recording the minutes of a team meeting.
+func (handler *TeamMeetingHandler) CreateMeeting(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for CreateMeeting handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var meeting models.TeamMeeting
+	if err := json.NewDecoder(request.Body).Decode(&meeting); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateMeeting")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	created, err := handler.TeamMeetingService.CreateMeeting(logger, request.Context(), &meeting, user)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).Error("Internal server error during team meeting creation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/meetings", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateMeeting")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetAllMeetings handles listing all team meetings, the latest first.
+func (handler *TeamMeetingHandler) GetAllMeetings(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	meetings, err := handler.TeamMeetingService.GetAllMeetings(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching team meetings")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(meetings); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetAllMeetings")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetMeetingByID handles fetching a team meeting with its action items by ID.
+func (handler *TeamMeetingHandler) GetMeetingByID(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	meetingID, ok := parsePathID(writer, request, "meeting_id", "GetMeetingByID")
+	if !ok {
+		return
+	}
+
+	meeting, err := handler.TeamMeetingService.GetMeetingByID(logger, request.Context(), meetingID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("meeting_id", meetingID).Error("Internal server error fetching team meeting")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(meeting); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetMeetingByID")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetMeetingsForChild handles listing the team meetings a child was discussed in or got an action item in.
+func (handler *TeamMeetingHandler) GetMeetingsForChild(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	childID, ok := parsePathID(writer, request, "child_id", "GetMeetingsForChild")
+	if !ok {
+		return
+	}
+
+	meetings, err := handler.TeamMeetingService.GetMeetingsForChild(logger, request.Context(), childID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Internal server error fetching team meetings of child")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(meetings); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetMeetingsForChild")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateMeeting handles changing the minutes of a team meeting, its attendees and children are replaced by those in
+// the request. Action items are kept.
+func (handler *TeamMeetingHandler) UpdateMeeting(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	meetingID, ok := parsePathID(writer, request, "meeting_id", "UpdateMeeting")
+	if !ok {
+		return
+	}
+
+	var meeting models.TeamMeeting
+	if err := json.NewDecoder(request.Body).Decode(&meeting); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateMeeting")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	meeting.ID = meetingID
+
+	if err := handler.TeamMeetingService.UpdateMeeting(logger, request.Context(), &meeting); err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("meeting_id", meetingID).Error("Internal server error during team meeting update")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Team meeting updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateMeeting")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteMeeting handles deleting a team meeting with its action items.
+func (handler *TeamMeetingHandler) DeleteMeeting(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	meetingID, ok := parsePathID(writer, request, "meeting_id", "DeleteMeeting")
+	if !ok {
+		return
+	}
+
+	if err := handler.TeamMeetingService.DeleteMeeting(logger, request.Context(), meetingID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("meeting_id", meetingID).Error("Internal server error deleting team meeting")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// CreateActionItem handles adding an action item to a team meeting.
+func (handler *TeamMeetingHandler) CreateActionItem(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	meetingID, ok := parsePathID(writer, request, "meeting_id", "CreateActionItem")
+	if !ok {
+		return
+	}
+
+	var item models.ActionItem
+	if err := json.NewDecoder(request.Body).Decode(&item); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateActionItem")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	item.MeetingID = meetingID
+
+	created, err := handler.TeamMeetingService.CreateActionItem(logger, request.Context(), &item)
+	if err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("meeting_id", meetingID).Error("Internal server error during action item creation")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/action-items", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateActionItem")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetOpenActionItems handles listing the open action items, the earliest due first. The query parameter teacher_id
+// narrows them down to the items assigned to a teacher.
+func (handler *TeamMeetingHandler) GetOpenActionItems(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	var teacherID *int
+	if teacherIDStr := request.URL.Query().Get("teacher_id"); teacherIDStr != "" {
+		id, err := strconv.Atoi(teacherIDStr)
+		if err != nil {
+			logger.WithError(err).WithField("teacher_id_str", teacherIDStr).Warn("Invalid teacher ID for GetOpenActionItems")
+			http.Error(writer, "Invalid teacher ID", http.StatusBadRequest)
+			return
+		}
+		teacherID = &id
+	}
+
+	items, err := handler.TeamMeetingService.GetOpenActionItems(logger, request.Context(), teacherID)
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching open action items")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(items); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetOpenActionItems")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateActionItem handles changing an action item.
+func (handler *TeamMeetingHandler) UpdateActionItem(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	itemID, ok := parsePathID(writer, request, "action_item_id", "UpdateActionItem")
+	if !ok {
+		return
+	}
+
+	var item models.ActionItem
+	if err := json.NewDecoder(request.Body).Decode(&item); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateActionItem")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	item.ID = itemID
+
+	if err := handler.TeamMeetingService.UpdateActionItem(logger, request.Context(), &item); err != nil {
+		if writeValidationError(writer, err) || writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("action_item_id", itemID).Error("Internal server error during action item update")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(map[string]string{"message": "Action item updated successfully"}); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateActionItem")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// CompleteActionItem handles marking an action item as done.
+func (handler *TeamMeetingHandler) CompleteActionItem(writer http.ResponseWriter, request *http.Request) {
+	handler.setActionItemCompleted(writer, request, true, "CompleteActionItem")
+}
+
+// ReopenActionItem handles reopening a completed action item.
+func (handler *TeamMeetingHandler) ReopenActionItem(writer http.ResponseWriter, request *http.Request) {
+	handler.setActionItemCompleted(writer, request, false, "ReopenActionItem")
+}
+
+func (handler *TeamMeetingHandler) setActionItemCompleted(writer http.ResponseWriter, request *http.Request, completed bool, handlerName string) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	itemID, ok := parsePathID(writer, request, "action_item_id", handlerName)
+	if !ok {
+		return
+	}
+
+	if err := handler.TeamMeetingService.SetActionItemCompleted(logger, request.Context(), itemID, completed); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("action_item_id", itemID).Error("Internal server error changing completion of action item")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	item, err := handler.TeamMeetingService.GetActionItemByID(logger, request.Context(), itemID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("action_item_id", itemID).Error("Internal server error fetching action item")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(item); err != nil {
+		logger.WithError(err).Error("Failed to encode response for " + handlerName)
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteActionItem handles deleting an action item.
+func (handler *TeamMeetingHandler) DeleteActionItem(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	itemID, ok := parsePathID(writer, request, "action_item_id", "DeleteActionItem")
+	if !ok {
+		return
+	}
+
+	if err := handler.TeamMeetingService.DeleteActionItem(logger, request.Context(), itemID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("action_item_id", itemID).Error("Internal server error deleting action item")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// TermsHandler handles requests for the versioned terms of use and privacy policy.
+type TermsHandler struct {
+	TermsService services.TermsService
+}
+
+// NewTermsHandler creates a new TermsHandler.
+func NewTermsHandler(termsService services.TermsService) *TermsHandler {
+	return &TermsHandler{TermsService: termsService}
+}
+
+// PublishTerms handles publishing a new version of the terms, which every user then has to accept.
+func (handler *TermsHandler) PublishTerms(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for PublishTerms handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var version models.TermsVersion
+	if err := json.NewDecoder(request.Body).Decode(&version); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for PublishTerms")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	published, err := handler.TermsService.PublishTerms(logger, request.Context(), user.ID, &version)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error publishing terms")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(writer).Encode(published); err != nil {
+		logger.WithError(err).Error("Failed to encode response for PublishTerms")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetTermsVersions handles listing all published versions of the terms.
+func (handler *TermsHandler) GetTermsVersions(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	versions, err := handler.TermsService.GetTermsVersions(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching terms versions")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if versions == nil {
+		versions = []models.TermsVersion{}
+	}
+
+	if err := json.NewEncoder(writer).Encode(versions); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetTermsVersions")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetTermsStatus handles fetching the current terms and whether the current user still has to accept them.
+func (handler *TermsHandler) GetTermsStatus(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for GetTermsStatus handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	status, err := handler.TermsService.GetTermsStatus(logger, request.Context(), user.ID)
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching terms status")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(status); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetTermsStatus")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// AcceptTerms handles recording that the current user accepts the current version of the terms.
+func (handler *TermsHandler) AcceptTerms(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for AcceptTerms handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var acceptRequest struct {
+		Version int `json:"version"`
+	}
+	if err := json.NewDecoder(request.Body).Decode(&acceptRequest); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for AcceptTerms")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	status, err := handler.TermsService.AcceptTerms(logger, request.Context(), user.ID, acceptRequest.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			http.Error(writer, "No terms have been published", http.StatusNotFound)
+		case errors.Is(err, services.ErrInvalidInput):
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		default:
+			logger.WithError(err).Error("Internal server error accepting terms")
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(status); err != nil {
+		logger.WithError(err).Error("Failed to encode response for AcceptTerms")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// UploadScanHandler handles the requests for the uploads the virus scanner rejected.
+type UploadScanHandler struct {
+	UploadScanService services.UploadScanService
+}
+
+// NewUploadScanHandler creates a new UploadScanHandler.
+func NewUploadScanHandler(uploadScanService services.UploadScanService) *UploadScanHandler {
+	return &UploadScanHandler{UploadScanService: uploadScanService}
+}
+
+// scanUpload scans an uploaded file before it is handed to the service storing it. It writes the error response
+// and returns false if the file was rejected or could not be scanned.
+func scanUpload(writer http.ResponseWriter, request *http.Request, uploadScanService services.UploadScanService, kind string, fileName string, content []byte) bool {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, _ := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+
+	err := uploadScanService.ScanUpload(logger, request.Context(), kind, fileName, content, user)
+	if err == nil {
+		return true
+	}
+	if writeDomainError(writer, err) {
+		return false
+	}
+	if errors.Is(err, services.ErrScanUnavailable) {
+		http.Error(writer, "The file could not be scanned for malware, please try again later", http.StatusServiceUnavailable)
+		return false
+	}
+	http.Error(writer, "Internal server error", http.StatusInternalServerError)
+	return false
+}
+
+// GetQuarantinedUploads handles listing the uploads the virus scanner rejected.
+func (handler *UploadScanHandler) GetQuarantinedUploads(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+
+	uploads, err := handler.UploadScanService.GetQuarantinedUploads(logger, request.Context())
+	if err != nil {
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(uploads); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetQuarantinedUploads")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DownloadQuarantinedUpload handles downloading a rejected upload, e.g. to report a false positive to the
+// vendor of the scanner. The file is always sent as an attachment of an opaque type, so that browsers do not
+// open it.
+func (handler *UploadScanHandler) DownloadQuarantinedUpload(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for DownloadQuarantinedUpload handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	quarantineID, ok := parsePathID(writer, request, "quarantine_id", "DownloadQuarantinedUpload")
+	if !ok {
+		return
+	}
+
+	upload, err := handler.UploadScanService.GetQuarantinedUpload(logger, request.Context(), quarantineID, user.ID)
+	if err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("quarantine_id", quarantineID).Error("Internal server error downloading quarantined upload")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/octet-stream")
+	writer.Header().Set("Content-Disposition", "attachment; filename=quarantine-"+strconv.Itoa(upload.ID)+".bin")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.Header().Set("Content-Length", strconv.Itoa(len(upload.Content)))
+	if _, err := writer.Write(upload.Content); err != nil {
+		logger.WithError(err).Error("Failed to write response for DownloadQuarantinedUpload")
+	}
+}
+
+// DeleteQuarantinedUpload handles deleting a rejected upload for good.
+func (handler *UploadScanHandler) DeleteQuarantinedUpload(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for DeleteQuarantinedUpload handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	quarantineID, ok := parsePathID(writer, request, "quarantine_id", "DeleteQuarantinedUpload")
+	if !ok {
+		return
+	}
+
+	if err := handler.UploadScanService.DeleteQuarantinedUpload(logger, request.Context(), quarantineID, user.ID); err != nil {
+		if writeDomainError(writer, err) {
+			return
+		}
+		logger.WithError(err).WithField("quarantine_id", quarantineID).Error("Internal server error deleting quarantined upload")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// defaultUptimeDays is the history shown without the days query parameter.
+const defaultUptimeDays = 30
+
+// UptimeHandler handles requests for the uptime history of the server.
+type UptimeHandler struct {
+	UptimeService services.UptimeService
+	Clock         clock.Clock
+}
+
+// NewUptimeHandler creates a new UptimeHandler.
+func NewUptimeHandler(uptimeService services.UptimeService, clock clock.Clock) *UptimeHandler {
+	return &UptimeHandler{UptimeService: uptimeService, Clock: clock}
+}
+
+// GetUptime handles fetching the daily availability up to today and the incidents in that time.
+// The optional query parameter days selects the number of days, 30 by default.
+func (handler *UptimeHandler) GetUptime(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	days := defaultUptimeDays
+	if daysStr := request.URL.Query().Get("days"); daysStr != "" {
+		var err error
+		days, err = strconv.Atoi(daysStr)
+		if err != nil {
+			logger.WithField("days_str", daysStr).WithError(err).Warn("Invalid days format for GetUptime")
+			http.Error(writer, "Invalid days", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := handler.UptimeService.GetUptime(logger, request.Context(), handler.Clock.Now(), days)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, fmt.Sprintf("Invalid days, must be between 1 and %d", services.MaxUptimeDays), http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error building uptime report")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(writer).Encode(report); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetUptime")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// CreateIncident handles annotating the uptime history with an incident.
+func (handler *UptimeHandler) CreateIncident(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	user, ok := request.Context().Value(middleware.ContextKeyUser).(*models.User)
+	if !ok {
+		logger.Error("User not found in context for CreateIncident handler")
+		http.Error(writer, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var incident models.UptimeIncident
+	if err := json.NewDecoder(request.Body).Decode(&incident); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for CreateIncident")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	created, err := handler.UptimeService.CreateIncident(logger, request.Context(), user.ID, &incident)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error creating uptime incident")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCreatedHeader(writer, "/api/v1/admin/uptime/incidents", created.ID)
+	if err := json.NewEncoder(writer).Encode(created); err != nil {
+		logger.WithError(err).Error("Failed to encode response for CreateIncident")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteIncident handles removing an incident annotation.
+func (handler *UptimeHandler) DeleteIncident(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	incidentIDStr := request.PathValue("incident_id")
+	incidentID, err := strconv.Atoi(incidentIDStr)
+	if err != nil {
+		logger.WithField("incident_id_str", incidentIDStr).WithError(err).Warn("Invalid incident ID format for DeleteIncident")
+		http.Error(writer, "Invalid incident ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.UptimeService.DeleteIncident(logger, request.Context(), incidentID); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			http.Error(writer, "Incident not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).WithField("incident_id", incidentID).Error("Internal server error deleting uptime incident")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+)
+
+// ValidationRuleHandler handles HTTP requests for the configurable business rules.
+type ValidationRuleHandler struct {
+	ValidationRuleService services.ValidationRuleService
+}
+
+// NewValidationRuleHandler creates a new ValidationRuleHandler.
+func NewValidationRuleHandler(validationRuleService services.ValidationRuleService) *ValidationRuleHandler {
+	return &ValidationRuleHandler{ValidationRuleService: validationRuleService}
+}
+
+// GetRules handles fetching the business rules of the facility.
+func (handler *ValidationRuleHandler) GetRules(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	rules, err := handler.ValidationRuleService.GetRules(logger, request.Context())
+	if err != nil {
+		logger.WithError(err).Error("Internal server error fetching validation rules")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(rules); err != nil {
+		logger.WithError(err).Error("Failed to encode response for GetRules")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateRules handles replacing the business rules of the facility.
+func (handler *ValidationRuleHandler) UpdateRules(writer http.ResponseWriter, request *http.Request) {
+	logger := middleware.GetLoggerWithReqID(request.Context())
+	var rules models.ValidationRules
+	if err := json.NewDecoder(request.Body).Decode(&rules); err != nil {
+		logger.WithError(err).Warn("Invalid request payload for UpdateRules")
+		http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	updatedRules, err := handler.ValidationRuleService.UpdateRules(logger, request.Context(), &rules)
+	if err != nil {
+		if writeValidationError(writer, err) {
+			return
+		}
+		if err == services.ErrInvalidInput {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.WithError(err).Error("Internal server error during validation rules update")
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(updatedRules); err != nil {
+		logger.WithError(err).Error("Failed to encode response for UpdateRules")
+		http.Error(writer, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeValidationError responds with the structured rule violations if err is a *services.ValidationError,
+// so clients can explain to the user what is missing. It reports whether a response was written.
+func writeValidationError(writer http.ResponseWriter, err error) bool {
+	var validationError *services.ValidationError
+	if !errors.As(err, &validationError) {
+		return false
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(writer).Encode(map[string]any{ //nolint:errcheck
+		"error":      "validation failed",
+		"code":       services.CodeValidationFailed,
+		"violations": validationError.Violations,
+	})
+	return true
+}
@@ -0,0 +1,59 @@
+// Package buildinfo reports which build of the server is running.
+package buildinfo
+
+import (
+	"runtime/debug"
+	"sync"
+
+	"kitadoc-backend/models"
+)
+
+// Set at build time, e.g. -ldflags "-X kitadoc-backend/internal/buildinfo.Version=1.4.0", see the makefile.
+var (
+	Version   = "" // Semantic version, falls back to the module version and then to "dev"
+	Commit    = "" // Git commit, falls back to the revision Go records when building from a checkout
+	BuildTime = "" // RFC 3339, empty if not set
+)
+
+// Get returns the build of the running binary.
+func Get() models.BuildInfo {
+	return get()
+}
+
+var get = sync.OnceValue(func() models.BuildInfo {
+	info := models.BuildInfo{Version: Version, Commit: Commit, BuildTime: BuildTime}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = build.GoVersion
+		if info.Version == "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		if info.Commit == "" {
+			info.Commit = vcsRevision(build)
+		}
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	return info
+})
+
+// vcsRevision returns the shortened revision of the checkout the binary was built from, ending in -dirty if
+// the checkout had local changes.
+func vcsRevision(build *debug.BuildInfo) string {
+	revision, modified := "", false
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+			if len(revision) > 12 {
+				revision = revision[:12]
+			}
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" && modified {
+		revision += "-dirty"
+	}
+	return revision
+}
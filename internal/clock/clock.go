@@ -0,0 +1,56 @@
+// Package clock provides the current time, so that it can be frozen for the fixture server and in tests.
+//
+// Services and handlers take the time of business rules from a Clock: observation and assignment dates,
+// expiry of invitations, digests and archives. Durations, token lifetimes and latencies keep using
+// time.Now, a frozen clock would otherwise expire sessions or hide timeouts.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the clock of the operating system.
+type System struct{}
+
+// Now returns the current time.
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// Frozen is a clock that stands still until it is set to another time.
+type Frozen struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// NewFrozen creates a Frozen clock showing now.
+func NewFrozen(now time.Time) *Frozen {
+	return &Frozen{now: now}
+}
+
+// Now returns the time the clock was set to.
+func (clock *Frozen) Now() time.Time {
+	clock.mu.RLock()
+	defer clock.mu.RUnlock()
+	return clock.now
+}
+
+// Set moves the clock to now.
+func (clock *Frozen) Set(now time.Time) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = now
+}
+
+// Advance moves the clock forward by d.
+func (clock *Frozen) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+}
@@ -0,0 +1,26 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"kitadoc-backend/internal/clock"
+)
+
+func TestFrozen(t *testing.T) {
+	start := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+	frozen := clock.NewFrozen(start)
+	if !frozen.Now().Equal(start) {
+		t.Fatalf("Expected %s, got %s", start, frozen.Now())
+	}
+
+	frozen.Advance(24 * time.Hour)
+	if want := start.AddDate(0, 0, 1); !frozen.Now().Equal(want) {
+		t.Errorf("Expected %s after advancing, got %s", want, frozen.Now())
+	}
+
+	frozen.Set(start)
+	if !frozen.Now().Equal(start) {
+		t.Errorf("Expected %s after setting, got %s", start, frozen.Now())
+	}
+}
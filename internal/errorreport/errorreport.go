@@ -0,0 +1,83 @@
+// Package errorreport describes errors and recovered panics for an external error tracker like Sentry.
+package errorreport
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// maxFrames bounds the stack of an event, deeper frames are left out.
+const maxFrames = 64
+
+// Frame is a frame of a stack trace.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+func (frame Frame) String() string {
+	return fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+}
+
+// Event is an error or a recovered panic of a request.
+type Event struct {
+	Message   string // The error or the panic value
+	Panic     bool
+	Stack     []Frame // Innermost frame first
+	RequestID string
+	Method    string
+	Route     string // Pattern of the route, unlike the path it contains no IDs
+	UserID    *int
+	Timestamp time.Time
+}
+
+// Reporter sends events to an error tracker. It must neither block nor fail the request it is called from.
+type Reporter interface {
+	Report(ctx context.Context, event Event)
+}
+
+// Flusher is implemented by reporters sending in the background, Flush waits at most for the timeout
+// until the pending events are sent and reports whether they were.
+type Flusher interface {
+	Flush(timeout time.Duration) bool
+}
+
+// PanicStack returns the stack of the panic being recovered, it must be called from the deferred function.
+// The frames of the deferred function and the panic machinery of the runtime are left out.
+func PanicStack() []Frame {
+	frames := callers(3)
+	for i, frame := range frames {
+		if frame.Function != "runtime.gopanic" {
+			continue
+		}
+		rest := frames[i+1:]
+		for len(rest) > 1 && strings.HasPrefix(rest[0].Function, "runtime.") {
+			rest = rest[1:]
+		}
+		return rest
+	}
+	return frames
+}
+
+// callers returns the stack of the calling goroutine, skipping skip frames including runtime.Callers.
+func callers(skip int) []Frame {
+	pcs := make([]uintptr, maxFrames)
+	n := runtime.Callers(skip, pcs)
+	if n == 0 {
+		return nil
+	}
+	iterator := runtime.CallersFrames(pcs[:n])
+	frames := make([]Frame, 0, n)
+	for {
+		frame, more := iterator.Next()
+		frames = append(frames, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
@@ -0,0 +1,61 @@
+package errorreport
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogHook reports the errors logged at error level, above all the causes services log before they return
+// ErrInternal. Entries without an error are no failures worth tracking and are left out, so are recovered
+// panics, which the Recovery middleware reports itself.
+type LogHook struct {
+	reporter Reporter
+}
+
+// NewLogHook creates a hook reporting to the reporter, add it to the logger with AddHook.
+func NewLogHook(reporter Reporter) *LogHook {
+	return &LogHook{reporter: reporter}
+}
+
+// Levels returns the levels the hook fires on.
+func (hook *LogHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+}
+
+// Fire reports the entry if it has an error.
+func (hook *LogHook) Fire(entry *logrus.Entry) error {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok || err == nil {
+		return nil
+	}
+
+	event := Event{
+		Message:   entry.Message + ": " + err.Error(),
+		Stack:     callerStack(),
+		Timestamp: entry.Time,
+	}
+	if requestID, ok := entry.Data["request_id"].(string); ok {
+		event.RequestID = requestID
+	}
+	if userID, ok := entry.Data["user_id"].(int); ok {
+		event.UserID = &userID
+	}
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	hook.reporter.Report(ctx, event)
+	return nil
+}
+
+// callerStack returns the stack starting at the code that logged the entry, below logrus and its wrapper.
+func callerStack() []Frame {
+	frames := callers(4)
+	for len(frames) > 1 && (strings.HasPrefix(frames[0].Function, "github.com/sirupsen/logrus.") ||
+		strings.HasPrefix(frames[0].Function, "kitadoc-backend/internal/logger.")) {
+		frames = frames[1:]
+	}
+	return frames
+}
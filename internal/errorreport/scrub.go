@@ -0,0 +1,28 @@
+package errorreport
+
+import "regexp"
+
+// scrubRules replace personal data and secrets in the messages of events before they leave the server.
+// Errors quote user input like names with %q, so quoted values are removed altogether.
+var scrubRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`eyJ[\w-]*\.[\w-]+\.[\w-]*`), "[token]"},
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+\S+`), "$1 [token]"},
+	{regexp.MustCompile(`(?i)\b(password|passwort|secret|token|api_key|apikey)(\s*[=:]\s*)\S+`), "$1$2[redacted]"},
+	{regexp.MustCompile(`[\w.%+-]+@[\w-]+(\.[\w-]+)*\.[A-Za-z]{2,}`), "[email]"},
+	{regexp.MustCompile(`"(?:[^"\\]|\\.)*"`), `"[redacted]"`},
+	{regexp.MustCompile("`[^`]*`"), "`[redacted]`"},
+	{regexp.MustCompile(`'[^'\s][^']*'`), "'[redacted]'"},
+	{regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){3,7}(?: ?[A-Z0-9]{1,3})?\b`), "[iban]"},
+}
+
+// Scrub removes personal data from the message of an event. The other fields hold no personal data: the route
+// is the pattern instead of the path and the user is only identified by the ID.
+func Scrub(event Event) Event {
+	for _, rule := range scrubRules {
+		event.Message = rule.pattern.ReplaceAllString(event.Message, rule.replacement)
+	}
+	return event
+}
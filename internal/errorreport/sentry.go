@@ -0,0 +1,178 @@
+package errorreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"kitadoc-backend/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+// maxPendingEvents is the number of events sent at the same time, further events are dropped.
+const maxPendingEvents = 8
+
+// sendTimeout bounds sending an event, the request it happened in has usually finished by then.
+const sendTimeout = 10 * time.Second
+
+// SentryReporter sends events to Sentry or a tracker with a compatible API like GlitchTip. Events are scrubbed
+// and sent in the background, so a slow tracker does not slow down requests.
+type SentryReporter struct {
+	client      *http.Client
+	endpoint    string
+	auth        string
+	environment string
+	release     string
+	pending     chan struct{}
+	sending     sync.WaitGroup
+}
+
+// NewSentryReporter creates a reporter for the project of the DSN, e.g. https://<key>@sentry.example/42.
+// The events are tagged with the environment, e.g. the profile, and the release of the server.
+func NewSentryReporter(client *http.Client, dsn string, environment string, release string) (*SentryReporter, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" || parsed.User.Username() == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN, must be https://<key>@<host>/<project>")
+	}
+	path := strings.Trim(parsed.Path, "/")
+	prefix, project := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		prefix, project = path[:i+1], path[i+1:]
+	}
+	if project == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN, the project is missing")
+	}
+
+	return &SentryReporter{
+		client:      client,
+		endpoint:    fmt.Sprintf("%s://%s/%sapi/%s/envelope/", parsed.Scheme, parsed.Host, prefix, project),
+		auth:        fmt.Sprintf("Sentry sentry_version=7, sentry_client=kitadoc/%s, sentry_key=%s", release, parsed.User.Username()),
+		environment: environment,
+		release:     release,
+		pending:     make(chan struct{}, maxPendingEvents),
+	}, nil
+}
+
+// Report sends the event in the background. It is dropped if too many events are being sent already.
+func (reporter *SentryReporter) Report(ctx context.Context, event Event) {
+	// Failures are logged as warnings, errors would be reported again by the LogHook.
+	log := logger.GetGlobalLogger().GetLogrusEntry().WithField("request_id", event.RequestID)
+	select {
+	case reporter.pending <- struct{}{}:
+	default:
+		log.Warn("Too many errors being reported to Sentry, dropping the event")
+		return
+	}
+
+	reporter.sending.Add(1)
+	go func() {
+		defer reporter.sending.Done()
+		defer func() { <-reporter.pending }()
+		if err := reporter.send(Scrub(event)); err != nil {
+			log.WithError(err).Warn("Failed to report error to Sentry")
+		}
+	}()
+}
+
+// Flush waits until the events being sent are sent, at most for the timeout. It reports whether all were sent.
+func (reporter *SentryReporter) Flush(timeout time.Duration) bool {
+	sent := make(chan struct{})
+	go func() {
+		reporter.sending.Wait()
+		close(sent)
+	}()
+	select {
+	case <-sent:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (reporter *SentryReporter) send(event Event) error {
+	eventID := strings.ReplaceAll(uuid.NewString(), "-", "")
+	payload, err := json.Marshal(reporter.sentryEvent(eventID, event))
+	if err != nil {
+		return err
+	}
+	var envelope bytes.Buffer
+	fmt.Fprintf(&envelope, "{\"event_id\":%q,\"sent_at\":%q}\n", eventID, time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(&envelope, "{\"type\":\"event\",\"length\":%d}\n", len(payload))
+	envelope.Write(payload)
+	envelope.WriteByte('\n')
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, reporter.endpoint, &envelope)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-sentry-envelope")
+	request.Header.Set("X-Sentry-Auth", reporter.auth)
+	response, err := reporter.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close() //nolint:errcheck
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("sentry answered %s", response.Status)
+	}
+	return nil
+}
+
+// sentryEvent maps the event to the event payload of Sentry.
+func (reporter *SentryReporter) sentryEvent(eventID string, event Event) map[string]any {
+	// Sentry expects the outermost frame first.
+	frames := make([]map[string]any, len(event.Stack))
+	for i, frame := range event.Stack {
+		frames[len(frames)-1-i] = map[string]any{
+			"function": frame.Function,
+			"filename": frame.File,
+			"lineno":   frame.Line,
+			"in_app":   strings.HasPrefix(frame.Function, "kitadoc-backend/"),
+		}
+	}
+	exceptionType, mechanism := "error", "logrus"
+	if event.Panic {
+		exceptionType, mechanism = "panic", "recovery"
+	}
+	tags := map[string]string{}
+	if event.RequestID != "" {
+		tags["request_id"] = event.RequestID
+	}
+	if event.Method != "" {
+		tags["method"] = event.Method
+	}
+
+	payload := map[string]any{
+		"event_id":    eventID,
+		"timestamp":   event.Timestamp.UTC().Format(time.RFC3339Nano),
+		"platform":    "go",
+		"level":       "error",
+		"logger":      "kitadoc-backend",
+		"environment": reporter.environment,
+		"release":     reporter.release,
+		"tags":        tags,
+		"exception": map[string]any{"values": []map[string]any{{
+			"type":       exceptionType,
+			"value":      event.Message,
+			"mechanism":  map[string]any{"type": mechanism, "handled": !event.Panic},
+			"stacktrace": map[string]any{"frames": frames},
+		}}},
+	}
+	if event.Route != "" {
+		payload["transaction"] = event.Route
+	}
+	if event.UserID != nil {
+		payload["user"] = map[string]string{"id": strconv.Itoa(*event.UserID)}
+	}
+	return payload
+}
@@ -0,0 +1,84 @@
+package errorreport
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// reportedErrorEventType marks a log entry as an error for Google Cloud Error Reporting.
+const reportedErrorEventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// StackdriverReporter writes events as structured log entries, which Google Cloud Logging forwards to
+// Error Reporting when the server runs on Google Cloud, e.g. on Cloud Run or GKE. Events are scrubbed first.
+type StackdriverReporter struct {
+	mu          sync.Mutex
+	writer      io.Writer
+	environment string
+	release     string
+}
+
+// NewStackdriverReporter creates a reporter writing to the writer, usually standard error. The events are
+// tagged with the environment, e.g. the profile, and the release of the server.
+func NewStackdriverReporter(writer io.Writer, environment string, release string) *StackdriverReporter {
+	return &StackdriverReporter{writer: writer, environment: environment, release: release}
+}
+
+// Report writes the event as a log entry.
+func (reporter *StackdriverReporter) Report(ctx context.Context, event Event) {
+	event = Scrub(event)
+	labels := map[string]string{"environment": reporter.environment}
+	if event.RequestID != "" {
+		labels["request_id"] = event.RequestID
+	}
+	errorContext := map[string]any{}
+	if event.Method != "" || event.Route != "" {
+		errorContext["httpRequest"] = map[string]string{"method": event.Method, "url": event.Route}
+	}
+	if event.UserID != nil {
+		errorContext["user"] = strconv.Itoa(*event.UserID)
+	}
+	if len(event.Stack) > 0 {
+		errorContext["reportLocation"] = map[string]any{
+			"filePath":     event.Stack[0].File,
+			"lineNumber":   event.Stack[0].Line,
+			"functionName": event.Stack[0].Function,
+		}
+	}
+
+	line, err := json.Marshal(map[string]any{
+		"@type":                         reportedErrorEventType,
+		"severity":                      "ERROR",
+		"eventTime":                     event.Timestamp.UTC().Format(time.RFC3339Nano),
+		"message":                       stackdriverMessage(event),
+		"serviceContext":                map[string]string{"service": "kitadoc-backend", "version": reporter.release},
+		"context":                       errorContext,
+		"logging.googleapis.com/labels": labels,
+	})
+	if err != nil {
+		return
+	}
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	reporter.writer.Write(append(line, '\n')) //nolint:errcheck // Nowhere left to report a failing standard error
+}
+
+// stackdriverMessage appends the stack in the format of Go panics, which Error Reporting groups errors by.
+func stackdriverMessage(event Event) string {
+	var message strings.Builder
+	if event.Panic {
+		message.WriteString("panic: ")
+	}
+	message.WriteString(event.Message)
+	if len(event.Stack) > 0 {
+		message.WriteString("\n\ngoroutine 1 [running]:\n")
+		for _, frame := range event.Stack {
+			message.WriteString(frame.Function + "(...)\n\t" + frame.File + ":" + strconv.Itoa(frame.Line) + "\n")
+		}
+	}
+	return message.String()
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/sirupsen/logrus"
+
+	"kitadoc-backend/internal/buildinfo"
 )
 
 // Logger is the interface for structured logging.
@@ -98,12 +100,13 @@ func (l *logrusLogger) GetLogrusEntry() *logrus.Entry {
 
 var globalLogger Logger
 
-// InitGlobalLogger initializes the global logger instance.
+// InitGlobalLogger initializes the global logger instance. Every entry carries the version of the server,
+// so that log lines in support requests can be matched to a build.
 func InitGlobalLogger(level logrus.Level, format logrus.Formatter) {
 	logrusLogger := logrus.New()
 	logrusLogger.SetLevel(level)
 	logrusLogger.SetFormatter(format)
-	globalLogger = NewLogrusLogger(logrusLogger.WithFields(logrus.Fields{}))
+	globalLogger = NewLogrusLogger(logrusLogger.WithFields(logrus.Fields{"version": buildinfo.Get().Version}))
 }
 
 // GetGlobalLogger returns the global logger instance.
@@ -2,9 +2,10 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"errors"
+	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,17 +18,32 @@ import (
 	"kitadoc-backend/app"
 	"kitadoc-backend/config"
 	"kitadoc-backend/data"
+	"kitadoc-backend/internal/buildinfo"
+	"kitadoc-backend/internal/errorreport"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/migrations"
 	"kitadoc-backend/services"
 )
 
 func main() {
+	profile := flag.String("profile", "", "Configuration profile: development, staging or production (default $KINDERGARTEN_PROFILE or development)")
+	fixture := flag.Bool("fixture", false, "Serve seeded in-memory data with a frozen clock for the frontend tests, same as -profile fixture")
+	chaos := flag.Bool("chaos", false, "Inject the latency and errors of the configured chaos rules, not allowed in production")
+	flag.Parse()
+	if *fixture {
+		*profile = config.ProfileFixture
+	}
+
 	// Load configuration
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfig(*profile)
 	if err != nil {
 		logrus.Fatalf("Failed to load configuration: %v", err)
 	}
+	if *chaos {
+		if err := cfg.EnableChaos(); err != nil {
+			logrus.Fatalf("Failed to enable chaos injection: %v", err)
+		}
+	}
 
 	// Set up structured logging
 	logLevel, err := logrus.ParseLevel(cfg.Log.Level)
@@ -50,10 +66,12 @@ func main() {
 	logger.InitGlobalLogger(logLevel, logFormatter)
 
 	log := logger.GetGlobalLogger()
-	log.Infof("Application starting in %s environment...", cfg.Environment)
+	build := buildinfo.Get()
+	log.WithFields(logrus.Fields{"commit": build.Commit, "build_time": build.BuildTime, "go_version": build.GoVersion}).
+		Infof("Application starting with the %s profile...", cfg.Environment)
 
-	// Open SQLite database connection
-	db, err := sql.Open("sqlite", cfg.Database.DSN)
+	// Open the database connection
+	db, err := data.Open(data.Driver(cfg.Database.Driver), cfg.Database.DSN)
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
@@ -70,19 +88,21 @@ func main() {
 	}
 	log.Info("Successfully connected to the database!")
 
-	// Make sure journal mode is WAL
-	_, err = db.Exec("PRAGMA journal_mode=WAL;")
-	if err != nil {
-		log.Fatalf("Failed to set journal mode to WAL: %v", err)
-	}
-	// Set busy timeout and synchronous mode
-	_, err = db.Exec("PRAGMA busy_timeout = 5000;")
-	if err != nil {
-		log.Fatalf("Failed to set busy timeout: %v", err)
-	}
-	_, err = db.Exec("PRAGMA synchronous = NORMAL;")
-	if err != nil {
-		log.Fatalf("Failed to set synchronous mode: %v", err)
+	if cfg.Database.Driver == config.DatabaseSQLite {
+		// Make sure journal mode is WAL
+		_, err = db.Exec("PRAGMA journal_mode=WAL;")
+		if err != nil {
+			log.Fatalf("Failed to set journal mode to WAL: %v", err)
+		}
+		// Set busy timeout and synchronous mode
+		_, err = db.Exec("PRAGMA busy_timeout = 5000;")
+		if err != nil {
+			log.Fatalf("Failed to set busy timeout: %v", err)
+		}
+		_, err = db.Exec("PRAGMA synchronous = NORMAL;")
+		if err != nil {
+			log.Fatalf("Failed to set synchronous mode: %v", err)
+		}
 	}
 
 	// Check if the database schema is initialized
@@ -92,12 +112,39 @@ func main() {
 	}
 	log.Info("Database schema is up to date.")
 
+	dataMigrations, err := data.RunDataMigrations(db, data.DataMigrations, []byte(cfg.Database.EncryptionKey))
+	if err != nil {
+		log.Fatalf("Data migration failed: %v", err)
+	}
+	for _, migration := range dataMigrations {
+		log.Infof("Applied data migration %s, %d values changed.", migration.Name, migration.RowsChanged)
+	}
+
 	// Initialize DAL
-	dal := data.NewDAL(db, []byte(cfg.Database.EncryptionKey))
+	dal := data.NewDAL(db, data.Driver(cfg.Database.Driver), []byte(cfg.Database.EncryptionKey))
 
 	// Initialize App
 	application := app.NewApplication(*cfg, dal)
 
+	// Report the causes of internal errors, the handlers report panics themselves
+	if application.ErrorReporter != nil {
+		log.GetLogrusEntry().Logger.AddHook(errorreport.NewLogHook(application.ErrorReporter))
+		log.Infof("Reporting errors to %s in environment %s.", cfg.ErrorReporting.Provider, cfg.ErrorReporting.Environment)
+	}
+
+	if cfg.Chaos.Enabled {
+		log.Warnf("Chaos injection is enabled for %d route rules, requests are slowed down and failed on purpose.", len(cfg.Chaos.Rules))
+	}
+
+	// The fixture server seeds its own accounts
+	if application.Fixtures != nil {
+		state, err := application.Fixtures.Reset(log.GetLogrusEntry(), context.Background())
+		if err != nil {
+			log.Fatalf("Failed to seed fixture data: %v", err)
+		}
+		log.Infof("Fixture data seeded, the clock is frozen at %s.", state.Now.Format(time.RFC3339))
+	}
+
 	// Get UserService for pre-creating users
 	userService := application.AuthHandler.UserService
 
@@ -105,7 +152,7 @@ func main() {
 	adminUsername := cfg.AdminUser.Username
 	adminPassword := cfg.AdminUser.Password
 	if adminUsername != "" && adminPassword != "" {
-		_, err := userService.RegisterUser(log.GetLogrusEntry(), adminUsername, adminPassword, "admin")
+		_, err := userService.RegisterUser(log.GetLogrusEntry(), context.Background(), adminUsername, adminPassword, "admin")
 		if err != nil && !errors.Is(err, services.ErrAlreadyExists) {
 			log.Fatalf("Failed to pre-create admin user: %v", err)
 		} else if errors.Is(err, services.ErrAlreadyExists) {
@@ -119,7 +166,7 @@ func main() {
 	normalUsername := cfg.NormalUser.Username
 	normalPassword := cfg.NormalUser.Password
 	if normalUsername != "" && normalPassword != "" {
-		_, err := userService.RegisterUser(log.GetLogrusEntry(), normalUsername, normalPassword, "teacher")
+		_, err := userService.RegisterUser(log.GetLogrusEntry(), context.Background(), normalUsername, normalPassword, "teacher")
 		if err != nil && !errors.Is(err, services.ErrAlreadyExists) {
 			log.Fatalf("Failed to pre-create normal user: %v", err)
 		} else if errors.Is(err, services.ErrAlreadyExists) {
@@ -152,8 +199,64 @@ func main() {
 		}
 	}()
 
+	// Start gRPC server for the internal reporting pipeline
+	grpcServer := application.GRPCServer()
+	if cfg.GRPC.Port != 0 {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+		if err != nil {
+			log.Fatalf("Could not listen on :%d: %v", cfg.GRPC.Port, err)
+		}
+		go func() {
+			log.Infof("gRPC server starting on %s", listener.Addr())
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("gRPC server failed: %v", err)
+			}
+		}()
+	}
+
+	// Start the outbox dispatcher delivering notifications of committed changes
+	dispatcherCtx, stopDispatcher := context.WithCancel(context.Background())
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		application.OutboxDispatcher.Run(log.GetLogrusEntry(), dispatcherCtx, cfg.Outbox.PollInterval)
+	}()
+
+	// Enqueue the weekly documentation digests, which the dispatcher sends as emails
+	digestDone := make(chan struct{})
+	go func() {
+		defer close(digestDone)
+		if application.DigestSender != nil {
+			application.DigestSender.Run(log.GetLogrusEntry(), dispatcherCtx, cfg.Email.DigestPollInterval)
+		}
+	}()
+
+	// Archive the quality report of every completed month
+	qualityReportsDone := make(chan struct{})
+	go func() {
+		defer close(qualityReportsDone)
+		if cfg.Exports.QualityReportInterval > 0 {
+			application.QualityReports.Run(log.GetLogrusEntry(), dispatcherCtx, cfg.Exports.QualityReportInterval)
+		}
+	}()
+
+	// Record the health checks of the uptime history
+	uptimeDone := make(chan struct{})
+	go func() {
+		defer close(uptimeDone)
+		if cfg.Monitoring.HealthCheckInterval > 0 {
+			application.Uptime.Run(log.GetLogrusEntry(), dispatcherCtx)
+		}
+	}()
+
 	<-done
 	log.Info("Attempting graceful shutdown...")
+	grpcServer.GracefulStop()
+	stopDispatcher()
+	<-dispatcherDone
+	<-digestDone
+	<-qualityReportsDone
+	<-uptimeDone
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -161,5 +264,8 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("Server shutdown failed: %v", err)
 	}
+	if flusher, ok := application.ErrorReporter.(errorreport.Flusher); ok && !flusher.Flush(5*time.Second) {
+		log.Warn("Not all errors could be reported before shutting down.")
+	}
 	log.Info("Server gracefully shut down.")
 }
@@ -18,54 +18,66 @@ import (
 // UserAuthenticator defines the interface for user authentication operations needed by middleware.
 type UserAuthenticator interface {
 	GetUserByID(logger *logrus.Entry, ctx context.Context, id int) (*models.User, error)
+	// ValidateSession returns an error if the session of an access token has been revoked, e.g. by a logout.
+	ValidateSession(logger *logrus.Entry, ctx context.Context, userID, sessionID int) error
 }
 
 type contextKeyUser string
 
 const (
 	ContextKeyUser contextKeyUser = "user"
+	// ContextKeySessionID holds the ID of the session of the access token.
+	ContextKeySessionID contextKeyUser = "session_id"
 )
 
 // Claims defines the structure of our JWT claims.
 type Claims struct {
 	UserID int       `json:"user_id"`
 	Role   data.Role `json:"role"`
+	// SessionID names the session of an access token issued by a login, a refresh or a break-glass activation.
+	// Tokens without a session, issued before sessions were introduced, are rejected: they could not be revoked.
+	SessionID int `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // Authenticate middleware validates JWT tokens and injects user context.
+// The token is sent as a bearer token or, with cookie sessions enabled, in the session cookie. Requests
+// authenticated by the cookie must pass the CSRF check, browsers attach the cookie to forged requests too.
 func Authenticate(userAuthenticator UserAuthenticator, cfg *config.Config) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 			logger := GetLoggerWithReqID(request.Context())
 			authHeader := request.Header.Get("Authorization")
+			var tokenString string
+			fromCookie := false
 			if authHeader == "" {
-				logger.Warn("Unauthorized: Missing Authorization header")
-				http.Error(writer, "Unauthorized", http.StatusUnauthorized)
-				return
-			}
-
-			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-			if tokenString == authHeader {
-				logger.Warn("Unauthorized: Invalid Authorization header format")
-				http.Error(writer, "Invalid Authorization header format", http.StatusUnauthorized)
-				return
-			}
-
-			claims := &Claims{}
-			token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					logger.WithField("signing_method", token.Method).Warn("Unexpected signing method for JWT")
-					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+				cookie, err := request.Cookie(SessionCookieName)
+				if !cfg.Session.Cookies || err != nil || cookie.Value == "" {
+					logger.Warn("Unauthorized: Missing Authorization header")
+					http.Error(writer, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+				tokenString, fromCookie = cookie.Value, true
+			} else {
+				tokenString = strings.TrimPrefix(authHeader, "Bearer ")
+				if tokenString == authHeader {
+					logger.Warn("Unauthorized: Invalid Authorization header format")
+					http.Error(writer, "Invalid Authorization header format", http.StatusUnauthorized)
+					return
 				}
-				return []byte(cfg.Server.JWTSecret), nil
-			})
+			}
 
-			if err != nil || !token.Valid {
+			claims, err := ParseToken(tokenString, cfg.Server.JWTSecret)
+			if err != nil {
 				logger.WithError(err).Warn("Invalid or expired token")
 				http.Error(writer, "Invalid or expired token", http.StatusUnauthorized)
 				return
 			}
+			if fromCookie && !validCSRF(request, tokenString, cfg.Server.JWTSecret) {
+				logger.WithField("user_id", claims.UserID).Warn("Forbidden: Invalid or missing CSRF token")
+				http.Error(writer, "Invalid or missing CSRF token", http.StatusForbidden)
+				return
+			}
 
 			// Fetch user from database to ensure they still exist and are active
 			user, err := userAuthenticator.GetUserByID(logger, request.Context(), claims.UserID)
@@ -75,13 +87,43 @@ func Authenticate(userAuthenticator UserAuthenticator, cfg *config.Config) func(
 				return
 			}
 
+			if claims.SessionID == 0 {
+				logger.WithField("user_id", claims.UserID).Warn("Unauthorized: Token without a session")
+				http.Error(writer, "Session has ended", http.StatusUnauthorized)
+				return
+			}
+			if err := userAuthenticator.ValidateSession(logger, request.Context(), claims.UserID, claims.SessionID); err != nil {
+				logger.WithError(err).WithField("user_id", claims.UserID).Warn("Unauthorized: Session has ended")
+				http.Error(writer, "Session has ended", http.StatusUnauthorized)
+				return
+			}
+
 			// Inject user into context
 			ctx := context.WithValue(request.Context(), ContextKeyUser, user)
+			ctx = context.WithValue(ctx, ContextKeySessionID, claims.SessionID)
 			next.ServeHTTP(writer, request.WithContext(ctx))
 		})
 	}
 }
 
+// ParseToken validates a JWT signed with the given secret and returns its claims.
+func ParseToken(tokenString string, secret string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return claims, nil
+}
+
 // Authorize middleware checks if the authenticated user has the required role.
 func Authorize(requiredRole data.Role) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -93,7 +135,7 @@ func Authorize(requiredRole data.Role) func(next http.Handler) http.Handler {
 				return
 			}
 
-			if user.Role != string(requiredRole) && user.Role != string(data.RoleAdmin) { // Admin can do anything
+			if !RoleAccess(requiredRole).Allows(user.Role) {
 				http.Error(writer, "Forbidden: Insufficient permissions", http.StatusForbidden)
 				return
 			}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"kitadoc-backend/config"
+)
+
+// ChaosHeader tells the frontend which failure was injected into a response, "latency" or "error".
+const ChaosHeader = "X-Chaos-Injected"
+
+// ChaosInjector slows down and fails requests of the routes matched by its rules, so that the frontend can test
+// loading states and retries against a staging server. It must only be used when enabled by the -chaos flag.
+type ChaosInjector struct {
+	rules  []config.ChaosRule
+	random func() float64 // Returns a number in [0, 1)
+	sleep  func(request *http.Request, duration time.Duration) bool
+}
+
+// NewChaosInjector creates an injector for the rules, the first rule matching a route applies.
+func NewChaosInjector(rules []config.ChaosRule) *ChaosInjector {
+	return &ChaosInjector{rules: rules, random: rand.Float64, sleep: sleepUnlessCancelled}
+}
+
+// For returns the middleware injecting the failures of the rule matching pattern, the pattern a route is
+// registered with. Routes without a rule are passed through.
+func (injector *ChaosInjector) For(pattern string) func(http.Handler) http.Handler {
+	rule, ok := injector.rule(pattern)
+	if !ok {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	status := rule.ErrorStatus
+	if status == 0 {
+		status = http.StatusServiceUnavailable
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			logger := GetLoggerWithReqID(request.Context()).WithField("pattern", pattern)
+			latency := rule.Latency
+			if rule.Jitter > 0 {
+				latency += time.Duration(injector.random() * float64(rule.Jitter))
+			}
+			if latency > 0 {
+				writer.Header().Set(ChaosHeader, "latency")
+				logger.WithField("latency", latency).Debug("Injecting latency")
+				if !injector.sleep(request, latency) {
+					return // The client gave up or the request timed out
+				}
+			}
+			if rule.ErrorRate > 0 && injector.random() < rule.ErrorRate {
+				writer.Header().Set(ChaosHeader, "error")
+				logger.WithField("status", status).Info("Injecting an error")
+				http.Error(writer, "Injected failure", status)
+				return
+			}
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
+
+// rule returns the first rule matching pattern.
+func (injector *ChaosInjector) rule(pattern string) (config.ChaosRule, bool) {
+	method, path, hasMethod := strings.Cut(pattern, " ")
+	if !hasMethod {
+		method, path = "", pattern
+	}
+	for _, rule := range injector.rules {
+		if rule.Route == "*" {
+			return rule, true
+		}
+		ruleMethod, rulePath, ruleHasMethod := strings.Cut(rule.Route, " ")
+		if !ruleHasMethod {
+			ruleMethod, rulePath = "", rule.Route
+		}
+		if ruleMethod != "" && ruleMethod != method {
+			continue
+		}
+		if rulePath == path {
+			return rule, true
+		}
+		if prefix, isPrefix := strings.CutSuffix(rulePath, "*"); isPrefix && strings.HasPrefix(path, prefix) {
+			return rule, true
+		}
+	}
+	return config.ChaosRule{}, false
+}
+
+// sleepUnlessCancelled waits for duration and reports false if the request was cancelled before.
+func sleepUnlessCancelled(request *http.Request, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-request.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaosInjector(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{})
+
+	newInjector := func(random float64, rules ...config.ChaosRule) (*ChaosInjector, *time.Duration) {
+		slept := new(time.Duration)
+		injector := NewChaosInjector(rules)
+		injector.random = func() float64 { return random }
+		injector.sleep = func(request *http.Request, duration time.Duration) bool {
+			*slept += duration
+			return request.Context().Err() == nil
+		}
+		return injector, slept
+	}
+	serve := func(handler http.Handler, request *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+	ok := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("routes are matched by pattern, prefix and method", func(t *testing.T) {
+		injector, _ := newInjector(0,
+			config.ChaosRule{Route: "GET /api/v1/children", Latency: time.Second},
+			config.ChaosRule{Route: "/api/v1/documents/*", Latency: 2 * time.Second},
+		)
+		_, matched := injector.rule("GET /api/v1/children")
+		assert.True(t, matched)
+		_, matched = injector.rule("POST /api/v1/children")
+		assert.False(t, matched)
+		rule, matched := injector.rule("GET /api/v1/documents/child-report/{child_id}")
+		assert.True(t, matched)
+		assert.Equal(t, 2*time.Second, rule.Latency)
+		_, matched = injector.rule("GET /api/v1/teachers")
+		assert.False(t, matched)
+
+		all, _ := newInjector(0, config.ChaosRule{Route: "*"})
+		_, matched = all.rule("DELETE /api/v1/teachers/{teacher_id}")
+		assert.True(t, matched)
+	})
+
+	t.Run("latency with jitter", func(t *testing.T) {
+		injector, slept := newInjector(0.5, config.ChaosRule{Route: "*", Latency: time.Second, Jitter: 2 * time.Second})
+		recorder := serve(injector.For("GET /api/v1/children")(ok), httptest.NewRequest(http.MethodGet, "/api/v1/children", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, 2*time.Second, *slept)
+		assert.Equal(t, "latency", recorder.Header().Get(ChaosHeader))
+	})
+
+	t.Run("errors by rate", func(t *testing.T) {
+		failing, _ := newInjector(0.1, config.ChaosRule{Route: "*", ErrorRate: 0.2})
+		recorder := serve(failing.For("GET /api/v1/children")(ok), httptest.NewRequest(http.MethodGet, "/api/v1/children", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.Equal(t, "error", recorder.Header().Get(ChaosHeader))
+
+		passing, _ := newInjector(0.3, config.ChaosRule{Route: "*", ErrorRate: 0.2, ErrorStatus: http.StatusInternalServerError})
+		recorder = serve(passing.For("GET /api/v1/children")(ok), httptest.NewRequest(http.MethodGet, "/api/v1/children", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Header().Get(ChaosHeader))
+
+		custom, _ := newInjector(0, config.ChaosRule{Route: "*", ErrorRate: 1, ErrorStatus: http.StatusInternalServerError})
+		recorder = serve(custom.For("GET /api/v1/children")(ok), httptest.NewRequest(http.MethodGet, "/api/v1/children", nil))
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.Run("cancelled requests are not handled", func(t *testing.T) {
+		injector, _ := newInjector(0, config.ChaosRule{Route: "*", Latency: time.Minute})
+		called := false
+		handler := injector.For("GET /api/v1/children")(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			called = true
+		}))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		serve(handler, httptest.NewRequest(http.MethodGet, "/api/v1/children", nil).WithContext(ctx))
+		assert.False(t, called)
+	})
+
+	t.Run("routes without a rule are passed through", func(t *testing.T) {
+		injector, slept := newInjector(0, config.ChaosRule{Route: "GET /api/v1/teachers", Latency: time.Second, ErrorRate: 1})
+		recorder := serve(injector.For("GET /api/v1/children")(ok), httptest.NewRequest(http.MethodGet, "/api/v1/children", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Zero(t, *slept)
+	})
+}
@@ -5,16 +5,14 @@ import (
 )
 
 // CORS middleware adds Cross-Origin Resource Sharing headers to responses.
+// Preflight requests are answered by AllowedMethods, which must be wrapped.
 func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		writer.Header().Set("Access-Control-Allow-Origin", "*") // Allow all origins for now
-		writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Correlation-ID")
-
-		if request.Method == "OPTIONS" {
-			writer.WriteHeader(http.StatusOK)
-			return
-		}
+		writer.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS")
+		// Upload-Offset and Upload-Checksum carry the chunks of resumable uploads.
+		writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Correlation-ID, Upload-Offset, Upload-Checksum, "+ReauthenticationHeader+", "+CSRFHeader)
+		writer.Header().Set("Access-Control-Expose-Headers", "Retry-After, Upload-Offset, "+RateLimitLimitHeader+", "+RateLimitRemainingHeader+", "+RateLimitResetHeader)
 
 		next.ServeHTTP(writer, request)
 	})
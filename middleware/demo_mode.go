@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kitadoc-backend/config"
+)
+
+// SessionID identifies the login session of an authenticated request by the session of its access token.
+// The session survives token refreshes, the access token itself is replaced every few minutes.
+// It returns an empty string for requests without a session.
+func SessionID(request *http.Request) string {
+	sessionID, ok := request.Context().Value(ContextKeySessionID).(int)
+	if !ok {
+		return ""
+	}
+	return strconv.Itoa(sessionID)
+}
+
+// tokenSessionID returns the session of the bearer token or session cookie of a request that has not been
+// authenticated yet, or an empty string if the request carries no valid token with a session.
+func tokenSessionID(request *http.Request, cfg *config.Config) string {
+	authHeader := request.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		tokenString = ""
+		if cookie, err := request.Cookie(SessionCookieName); err == nil && authHeader == "" && cfg.Session.Cookies {
+			tokenString = cookie.Value
+		}
+	}
+	if tokenString == "" {
+		return ""
+	}
+	claims, err := ParseToken(tokenString, cfg.Server.JWTSecret)
+	if err != nil || claims.SessionID == 0 {
+		return ""
+	}
+	return strconv.Itoa(claims.SessionID)
+}
+
+// DemoMode routes requests of sessions in demo mode to the handler serving the anonymized demo dataset.
+// Responses served from the demo dataset carry the header X-Demo-Mode: true.
+func DemoMode(cfg *config.Config, isEnabled func(sessionID string) bool, demoHandler func() http.Handler) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			sessionID := tokenSessionID(request, cfg)
+			if sessionID == "" || !isEnabled(sessionID) {
+				next.ServeHTTP(writer, request)
+				return
+			}
+			handler := demoHandler()
+			if handler == nil {
+				GetLoggerWithReqID(request.Context()).Error("Demo mode enabled but no demo dataset available")
+				http.Error(writer, "Demo dataset not available", http.StatusServiceUnavailable)
+				return
+			}
+			writer.Header().Set("X-Demo-Mode", "true")
+			handler.ServeHTTP(writer, request)
+		})
+	}
+}
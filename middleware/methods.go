@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// routedMethods are the methods the router is asked about when listing the methods of a path.
+// HEAD is served by the GET routes, OPTIONS by AllowedMethods itself.
+var routedMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// AllowedMethods middleware answers OPTIONS with 204 and requests with a method the path has no route for
+// with 405, both with an Allow header listing the methods of the path. Everything else, including paths
+// without any route, is passed on.
+func AllowedMethods(router *http.ServeMux) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.Method != http.MethodOptions {
+				if _, pattern := router.Handler(request); pattern != "" {
+					next.ServeHTTP(writer, request)
+					return
+				}
+			}
+
+			allowed := allowedMethods(router, request)
+			if len(allowed) == 0 {
+				next.ServeHTTP(writer, request)
+				return
+			}
+			writer.Header().Set("Allow", strings.Join(allowed, ", "))
+			if request.Method == http.MethodOptions {
+				writer.WriteHeader(http.StatusNoContent)
+				return
+			}
+			http.Error(writer, "Method not allowed", http.StatusMethodNotAllowed)
+		})
+	}
+}
+
+// allowedMethods returns the methods the router has a route for at the path of the request, plus OPTIONS.
+func allowedMethods(router *http.ServeMux, request *http.Request) []string {
+	var allowed []string
+	for _, method := range routedMethods {
+		probe := &http.Request{Method: method, URL: request.URL, Host: request.Host, Header: http.Header{}}
+		if _, pattern := router.Handler(probe); pattern != "" {
+			allowed = append(allowed, method)
+		}
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	return append(allowed, http.MethodOptions)
+}
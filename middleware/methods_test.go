@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedMethods(t *testing.T) {
+	router := http.NewServeMux()
+	ok := func(writer http.ResponseWriter, request *http.Request) { writer.Write([]byte("ok")) } //nolint:errcheck
+	router.HandleFunc("GET /api/v1/children", ok)
+	router.HandleFunc("POST /api/v1/children", ok)
+	router.HandleFunc("GET /api/v1/children/{child_id}", ok)
+	router.HandleFunc("DELETE /api/v1/children/{child_id}", ok)
+	handler := AllowedMethods(router)(router)
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+		return recorder
+	}
+
+	t.Run("passes routed requests", func(t *testing.T) {
+		recorder := serve(http.MethodGet, "/api/v1/children/1")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("Allow"))
+	})
+
+	t.Run("HEAD is served by the GET route", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(http.MethodHead, "/api/v1/children").Code)
+	})
+
+	t.Run("OPTIONS lists the methods of the path", func(t *testing.T) {
+		recorder := serve(http.MethodOptions, "/api/v1/children/1")
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, "GET, HEAD, DELETE, OPTIONS", recorder.Header().Get("Allow"))
+	})
+
+	t.Run("405 with the methods of the path", func(t *testing.T) {
+		recorder := serve(http.MethodPut, "/api/v1/children")
+
+		assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+		assert.Equal(t, "GET, HEAD, POST, OPTIONS", recorder.Header().Get("Allow"))
+	})
+
+	t.Run("404 for unknown paths", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/v1/unknown").Code)
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodOptions, "/api/v1/unknown").Code)
+	})
+}
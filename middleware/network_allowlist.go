@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// CodeNetworkNotAllowed is reported for requests from networks outside of an allowlist.
+const CodeNetworkNotAllowed = "NETWORK_NOT_ALLOWED"
+
+// NetworkAllowlist only lets requests from its networks pass, for routes that must only be reachable from the
+// facility network or the VPN.
+type NetworkAllowlist struct {
+	networks       []netip.Prefix
+	trustedProxies []netip.Prefix
+	onDenied       func(request *http.Request, client netip.Addr) // Records a denied request, e.g. in the audit log
+}
+
+// NewNetworkAllowlist creates an allowlist of the networks. The X-Forwarded-For header is only followed on
+// connections from the trusted proxies. An empty list of networks allows every network.
+func NewNetworkAllowlist(networks, trustedProxies []netip.Prefix, onDenied func(request *http.Request, client netip.Addr)) *NetworkAllowlist {
+	return &NetworkAllowlist{networks: networks, trustedProxies: trustedProxies, onDenied: onDenied}
+}
+
+// Restrict returns the middleware rejecting requests from other networks with 403.
+func (allowlist *NetworkAllowlist) Restrict(next http.Handler) http.Handler {
+	if len(allowlist.networks) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		client, ok := allowlist.clientAddr(request)
+		if ok && containsAddr(allowlist.networks, client) {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		GetLoggerWithReqID(request.Context()).WithField("client", client.String()).Warn("Forbidden: Request from a network outside of the allowlist")
+		if allowlist.onDenied != nil {
+			allowlist.onDenied(request, client)
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(writer).Encode(map[string]string{ //nolint:errcheck
+			"error": "this route is only reachable from the facility network",
+			"code":  CodeNetworkNotAllowed,
+		})
+	})
+}
+
+// clientAddr returns the address of the client of a request. Behind trusted proxies the X-Forwarded-For header
+// is read from the right, each proxy appends the address it received the request from, so that the first
+// untrusted address is the client and addresses the client made up itself are ignored.
+func (allowlist *NetworkAllowlist) clientAddr(request *http.Request) (netip.Addr, bool) {
+	remote, err := netip.ParseAddrPort(request.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	client := remote.Addr().Unmap()
+	if !containsAddr(allowlist.trustedProxies, client) {
+		return client, true
+	}
+	forwarded := strings.Split(strings.Join(request.Header.Values("X-Forwarded-For"), ","), ",")
+	for _, value := range slices.Backward(forwarded) {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		addr, err := netip.ParseAddr(strings.TrimSpace(value))
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		client = addr.Unmap()
+		if !containsAddr(allowlist.trustedProxies, client) {
+			return client, true
+		}
+	}
+	return client, true
+}
+
+func containsAddr(networks []netip.Prefix, addr netip.Addr) bool {
+	for _, network := range networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
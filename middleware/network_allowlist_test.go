@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"kitadoc-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkAllowlist(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{})
+
+	facility := []netip.Prefix{netip.MustParsePrefix("192.168.10.0/24"), netip.MustParsePrefix("2001:db8::/32")}
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32"), netip.MustParsePrefix("10.0.0.2/32")}
+	var denied []netip.Addr
+	allowlist := NewNetworkAllowlist(facility, proxies, func(request *http.Request, client netip.Addr) {
+		denied = append(denied, client)
+	})
+	handler := allowlist.Restrict(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	serve := func(remoteAddr string, forwardedFor ...string) int {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/admin/uptime", nil)
+		request.RemoteAddr = remoteAddr
+		for _, value := range forwardedFor {
+			request.Header.Add("X-Forwarded-For", value)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	t.Run("passes requests from the networks", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("192.168.10.7:51234"))
+		assert.Equal(t, http.StatusOK, serve("[::ffff:192.168.10.7]:51234"), "IPv4-mapped addresses are compared as IPv4")
+		assert.Equal(t, http.StatusOK, serve("[2001:db8::5]:51234"))
+	})
+
+	t.Run("rejects and records requests from other networks", func(t *testing.T) {
+		denied = nil
+		assert.Equal(t, http.StatusForbidden, serve("203.0.113.9:51234"))
+		assert.Equal(t, []netip.Addr{netip.MustParseAddr("203.0.113.9")}, denied)
+	})
+
+	t.Run("follows X-Forwarded-For only behind trusted proxies", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("10.0.0.1:443", "192.168.10.7"))
+		assert.Equal(t, http.StatusOK, serve("10.0.0.1:443", "192.168.10.7, 10.0.0.2"), "chained proxies are skipped")
+		assert.Equal(t, http.StatusForbidden, serve("10.0.0.1:443", "192.168.10.7", "203.0.113.9"),
+			"an address made up by the client is not the client")
+		assert.Equal(t, http.StatusForbidden, serve("203.0.113.9:51234", "192.168.10.7"), "untrusted connections cannot forward")
+		assert.Equal(t, http.StatusForbidden, serve("10.0.0.1:443", "not-an-address"))
+	})
+
+	t.Run("an empty allowlist allows every network", func(t *testing.T) {
+		next := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {})
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/admin/uptime", nil)
+		recorder := httptest.NewRecorder()
+		NewNetworkAllowlist(nil, nil, nil).Restrict(next).ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+)
+
+// Policy is the access rule of a route.
+type Policy struct {
+	Access      models.RouteAccess
+	Role        data.Role
+	TermsExempt bool // Usable before the current terms of use are accepted, e.g. to accept them
+}
+
+var (
+	// PublicAccess admits every caller.
+	PublicAccess = Policy{Access: models.RouteAccessPublic}
+	// AuthenticatedAccess admits every logged-in user.
+	AuthenticatedAccess = Policy{Access: models.RouteAccessAuthenticated}
+)
+
+// RoleAccess admits users with the given role. Admins can do anything.
+func RoleAccess(role data.Role) Policy {
+	return Policy{Access: models.RouteAccessRole, Role: role}
+}
+
+// AdminOnly reports whether only admins pass the policy.
+func (policy Policy) AdminOnly() bool {
+	return policy.Access == models.RouteAccessRole && policy.Role == data.RoleAdmin
+}
+
+// BeforeTermsAcceptance returns the policy for a route that users can call before they have accepted
+// the current terms of use and privacy policy.
+func (policy Policy) BeforeTermsAcceptance() Policy {
+	policy.TermsExempt = true
+	return policy
+}
+
+// Allows reports whether a user with the given role passes the policy.
+func (policy Policy) Allows(role string) bool {
+	switch policy.Access {
+	case models.RouteAccessPublic, models.RouteAccessAuthenticated:
+		return true
+	case models.RouteAccessRole:
+		return role == string(policy.Role) || role == string(data.RoleAdmin)
+	}
+	return false
+}
+
+// PolicyEngine holds the access policy of every route pattern registered on a router.
+// Routes consult it on each request, so the dumped policies are the ones that are enforced.
+type PolicyEngine struct {
+	mu       sync.RWMutex
+	policies map[string]Policy // Route pattern to policy
+}
+
+// NewPolicyEngine creates an empty PolicyEngine.
+func NewPolicyEngine() *PolicyEngine {
+	return &PolicyEngine{policies: make(map[string]Policy)}
+}
+
+// Register records the policy of a route pattern.
+func (engine *PolicyEngine) Register(pattern string, policy Policy) {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	engine.policies[pattern] = policy
+}
+
+// Authorize enforces the policy registered for the pattern the request was routed by.
+// Requests on patterns without a policy are rejected.
+func (engine *PolicyEngine) Authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		engine.mu.RLock()
+		policy, ok := engine.policies[request.Pattern]
+		engine.mu.RUnlock()
+		if !ok {
+			GetLoggerWithReqID(request.Context()).WithField("pattern", request.Pattern).Error("Forbidden: No policy registered for route")
+			http.Error(writer, "Forbidden: Insufficient permissions", http.StatusForbidden)
+			return
+		}
+		if policy.Access == models.RouteAccessPublic {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		user, ok := request.Context().Value(ContextKeyUser).(*models.User)
+		if !ok {
+			GetLoggerWithReqID(request.Context()).Error("Forbidden: User context not found in policy engine")
+			http.Error(writer, "Forbidden: User context not found", http.StatusForbidden)
+			return
+		}
+		if !policy.Allows(user.Role) {
+			http.Error(writer, "Forbidden: Insufficient permissions", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// Policies returns the effective policy of every registered route, sorted by path and method.
+func (engine *PolicyEngine) Policies() []models.RoutePolicy {
+	engine.mu.RLock()
+	defer engine.mu.RUnlock()
+
+	routePolicies := make([]models.RoutePolicy, 0, len(engine.policies))
+	for pattern, policy := range engine.policies {
+		method, path, found := strings.Cut(pattern, " ")
+		if !found {
+			method, path = "", pattern
+		}
+		routePolicy := models.RoutePolicy{
+			Method:       method,
+			Path:         path,
+			Access:       policy.Access,
+			RequiredRole: string(policy.Role),
+			AllowedRoles: []string{},
+		}
+		if policy.Access != models.RouteAccessPublic {
+			routePolicy.TermsAcceptanceRequired = !policy.TermsExempt
+			for _, role := range data.Roles {
+				if policy.Allows(string(role)) {
+					routePolicy.AllowedRoles = append(routePolicy.AllowedRoles, string(role))
+				}
+			}
+		}
+		routePolicies = append(routePolicies, routePolicy)
+	}
+	sort.Slice(routePolicies, func(i, j int) bool {
+		if routePolicies[i].Path != routePolicies[j].Path {
+			return routePolicies[i].Path < routePolicies[j].Path
+		}
+		return routePolicies[i].Method < routePolicies[j].Method
+	})
+	return routePolicies
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"kitadoc-backend/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ReauthenticationHeader carries the token of a recent re-authentication, required for bulk exports.
+const ReauthenticationHeader = "X-Reauthentication-Token"
+
+// CodeReauthenticationRequired is reported for requests without a valid re-authentication token.
+const CodeReauthenticationRequired = "REAUTHENTICATION_REQUIRED"
+
+// reauthenticationSecret derives the signing secret of re-authentication tokens, so that they cannot be
+// used as login tokens and login tokens cannot be used as re-authentication tokens.
+func reauthenticationSecret(secret string) []byte {
+	return []byte(secret + ":reauthentication")
+}
+
+// IssueReauthenticationToken creates a token confirming that the user has just entered their password again.
+func IssueReauthenticationToken(userID int, secret string, validity time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(validity)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID:           userID,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(expiresAt)},
+	})
+	tokenString, err := token.SignedString(reauthenticationSecret(secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return tokenString, expiresAt, nil
+}
+
+// RequireReauthentication middleware only lets requests pass that carry a valid re-authentication token
+// of the authenticated user. It must run after Authenticate.
+func RequireReauthentication(secret string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			logger := GetLoggerWithReqID(request.Context())
+			user, ok := request.Context().Value(ContextKeyUser).(*models.User)
+			if !ok {
+				logger.Error("Forbidden: User context not found in RequireReauthentication middleware")
+				http.Error(writer, "Forbidden: User context not found", http.StatusForbidden)
+				return
+			}
+
+			tokenString := request.Header.Get(ReauthenticationHeader)
+			if tokenString != "" {
+				claims, err := ParseToken(tokenString, string(reauthenticationSecret(secret)))
+				if err == nil && claims.UserID == user.ID {
+					next.ServeHTTP(writer, request)
+					return
+				}
+				logger.WithError(err).WithField("user_id", user.ID).Warn("Invalid or expired re-authentication token")
+			}
+
+			writer.Header().Set("Content-Type", "application/json")
+			writer.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(writer).Encode(map[string]string{ //nolint:errcheck
+				"error": "re-authentication required, confirm your password at /api/v1/auth/reauthenticate",
+				"code":  CodeReauthenticationRequired,
+			})
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kitadoc-backend/internal/logger"
+	"kitadoc-backend/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireReauthentication(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{})
+
+	const secret = "test-secret"
+	handler := RequireReauthentication(secret)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	serve := func(token string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/reports/export", nil)
+		request = request.WithContext(context.WithValue(request.Context(), ContextKeyUser, &models.User{ID: 1}))
+		if token != "" {
+			request.Header.Set(ReauthenticationHeader, token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("passes a valid token of the user", func(t *testing.T) {
+		token, expiresAt, err := IssueReauthenticationToken(1, secret, 5*time.Minute)
+		require.NoError(t, err)
+
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), expiresAt, time.Second)
+		assert.Equal(t, http.StatusOK, serve(token).Code)
+	})
+
+	t.Run("rejects missing, foreign, expired and login tokens", func(t *testing.T) {
+		foreign, _, err := IssueReauthenticationToken(2, secret, 5*time.Minute)
+		require.NoError(t, err)
+		expired, _, err := IssueReauthenticationToken(1, secret, -time.Minute)
+		require.NoError(t, err)
+		login, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+			UserID:           1,
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		}).SignedString([]byte(secret))
+		require.NoError(t, err)
+
+		for _, token := range []string{"", foreign, expired, login} {
+			recorder := serve(token)
+
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), `"code":"REAUTHENTICATION_REQUIRED"`)
+		}
+	})
+}
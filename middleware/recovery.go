@@ -1,24 +1,77 @@
 package middleware
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
-	"runtime/debug"
+	"time"
+
+	"kitadoc-backend/internal/errorreport"
+	"kitadoc-backend/models"
 
 	"github.com/sirupsen/logrus"
 )
 
-// Recovery middleware recovers from panics and logs the stack trace.
-func Recovery(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-		defer func() {
-			if err := recover(); err != nil {
-				http.Error(writer, "Internal Server Error", http.StatusInternalServerError)
-				GetLoggerWithReqID(request.Context()).WithFields(logrus.Fields{
-					"panic": err,
-					"stack": string(debug.Stack()),
-				}).Error("Recovered from panic")
-			}
-		}()
-		next.ServeHTTP(writer, request)
-	})
+// RequestIDHeader carries the request ID of a failed request, users quote it in support requests.
+const RequestIDHeader = "X-Request-ID"
+
+// CodeInternalError is reported for requests that failed with a panic.
+const CodeInternalError = "INTERNAL_ERROR"
+
+// Recovery recovers from panics of the handler and answers 500 with the request ID. The panic is logged with
+// its stack as frames and passed on to the reporter, a nil reporter disables reporting.
+func Recovery(reporter errorreport.Reporter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler { // Aborting a response on purpose is no error
+					panic(recovered)
+				}
+
+				event := errorreport.Event{
+					Message:   fmt.Sprint(recovered),
+					Panic:     true,
+					Stack:     errorreport.PanicStack(),
+					RequestID: GetRequestID(request.Context()),
+					Method:    request.Method,
+					Route:     request.Pattern,
+					Timestamp: time.Now(),
+				}
+				if user, ok := request.Context().Value(ContextKeyUser).(*models.User); ok {
+					event.UserID = &user.ID
+				}
+				stack := make([]string, len(event.Stack))
+				for i, frame := range event.Stack {
+					stack[i] = frame.String()
+				}
+				logger := GetLoggerWithReqID(request.Context()).WithFields(logrus.Fields{
+					"panic":   event.Message,
+					"method":  event.Method,
+					"pattern": event.Route,
+					"stack":   stack,
+				})
+				if event.UserID != nil {
+					logger = logger.WithField("user_id", *event.UserID)
+				}
+				logger.Error("Recovered from panic")
+				if reporter != nil {
+					reporter.Report(request.Context(), event)
+				}
+
+				writer.Header().Set(RequestIDHeader, event.RequestID)
+				writer.Header().Set("Content-Type", "application/json")
+				writer.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(writer).Encode(map[string]string{ //nolint:errcheck
+					"error":      "internal server error, please quote the request ID when reporting the problem",
+					"code":       CodeInternalError,
+					"request_id": event.RequestID,
+				})
+			}()
+			next.ServeHTTP(writer, request)
+		})
+	}
 }
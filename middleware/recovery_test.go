@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kitadoc-backend/internal/errorreport"
+	"kitadoc-backend/internal/logger"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reporterFunc func(ctx context.Context, event errorreport.Event)
+
+func (report reporterFunc) Report(ctx context.Context, event errorreport.Event) {
+	report(ctx, event)
+}
+
+func TestRecovery(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{})
+
+	t.Run("answers 500 with the request ID and reports the panic", func(t *testing.T) {
+		var events []errorreport.Event
+		reporter := reporterFunc(func(ctx context.Context, event errorreport.Event) { events = append(events, event) })
+		handler := RequestIDMiddleware(Recovery(reporter)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			var entries map[int]string
+			entries[1] = "boom"
+		})))
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/children/1", nil)
+		request = request.WithContext(context.WithValue(request.Context(), ContextKeyUser, &models.User{ID: 7}))
+		request.Pattern = "GET /api/v1/children/{child_id}"
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&body))
+		assert.Equal(t, "INTERNAL_ERROR", body["code"])
+		assert.NotEmpty(t, body["request_id"])
+		assert.Equal(t, body["request_id"], recorder.Header().Get(RequestIDHeader))
+
+		require.Len(t, events, 1)
+		event := events[0]
+		assert.True(t, event.Panic)
+		assert.Contains(t, event.Message, "nil map")
+		assert.Equal(t, body["request_id"], event.RequestID)
+		assert.Equal(t, "GET /api/v1/children/{child_id}", event.Route)
+		assert.Equal(t, 7, *event.UserID)
+		require.NotEmpty(t, event.Stack)
+		assert.True(t, strings.HasPrefix(event.Stack[0].Function, "kitadoc-backend/middleware.TestRecovery"),
+			"the stack starts at the panic, got %s", event.Stack[0])
+	})
+
+	t.Run("passes responses without panic", func(t *testing.T) {
+		handler := Recovery(nil)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusNoContent)
+		}))
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/", nil))
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+
+	t.Run("recovers without reporter", func(t *testing.T) {
+		handler := Recovery(nil)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			panic("boom")
+		}))
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.Run("does not recover aborted responses", func(t *testing.T) {
+		handler := Recovery(nil)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	})
+}
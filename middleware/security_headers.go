@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"kitadoc-backend/config"
+)
+
+// SecurityHeaders middleware adds the security headers to every response: Strict-Transport-Security,
+// X-Content-Type-Options, Referrer-Policy and the Content-Security-Policy, which matters once the embedded SPA is
+// served. Headers that are not configured are left out.
+func SecurityHeaders(cfg *config.Config) func(next http.Handler) http.Handler {
+	settings := cfg.SecurityHeaders
+	hsts := ""
+	if settings.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(settings.HSTSMaxAge/time.Second), 10)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			header := writer.Header()
+			if hsts != "" {
+				header.Set("Strict-Transport-Security", hsts)
+			}
+			header.Set("X-Content-Type-Options", "nosniff")
+			if settings.ReferrerPolicy != "" {
+				header.Set("Referrer-Policy", settings.ReferrerPolicy)
+			}
+			if settings.ContentSecurityPolicy != "" {
+				header.Set("Content-Security-Policy", settings.ContentSecurityPolicy)
+			}
+
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	serve := func(cfg *config.Config) http.Header {
+		handler := SecurityHeaders(cfg)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/children", nil))
+		return recorder.Header()
+	}
+
+	t.Run("sets the configured headers", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.SecurityHeaders.HSTSMaxAge = 365 * 24 * time.Hour
+		cfg.SecurityHeaders.ReferrerPolicy = "no-referrer"
+		cfg.SecurityHeaders.ContentSecurityPolicy = "default-src 'self'"
+
+		header := serve(cfg)
+		assert.Equal(t, "max-age=31536000", header.Get("Strict-Transport-Security"))
+		assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+		assert.Equal(t, "no-referrer", header.Get("Referrer-Policy"))
+		assert.Equal(t, "default-src 'self'", header.Get("Content-Security-Policy"))
+	})
+
+	t.Run("leaves out headers that are not configured", func(t *testing.T) {
+		header := serve(&config.Config{})
+		assert.Empty(t, header.Values("Strict-Transport-Security"))
+		assert.Empty(t, header.Values("Referrer-Policy"))
+		assert.Empty(t, header.Values("Content-Security-Policy"))
+		assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+	})
+}
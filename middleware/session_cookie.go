@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+
+	"kitadoc-backend/config"
+)
+
+// Cookies and header of the cookie sessions.
+const (
+	// SessionCookieName is the HttpOnly cookie carrying the login token of a cookie session.
+	SessionCookieName = "kitadoc_session"
+	// CSRFCookieName is the cookie the frontend reads the CSRF token from, e.g. after a reload of the page.
+	CSRFCookieName = "kitadoc_csrf"
+	// RefreshCookieName is the HttpOnly cookie carrying the refresh token of a cookie session. It is only sent to
+	// RefreshCookiePath.
+	RefreshCookieName = "kitadoc_refresh"
+	RefreshCookiePath = "/api/v1/auth/refresh"
+	// CSRFHeader carries the CSRF token on unsafe requests of a cookie session.
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// csrfSecret derives the key CSRF tokens are signed with, so that a CSRF token cannot be mistaken for any other
+// token signed with the JWT secret.
+func csrfSecret(secret string) []byte {
+	return []byte(secret + ":csrf")
+}
+
+// CSRFToken returns the CSRF token of a cookie session. It is derived from the login token, so that it needs no
+// server state and is only valid for its own session.
+func CSRFToken(sessionToken string, secret string) string {
+	mac := hmac.New(sha256.New, csrfSecret(secret))
+	mac.Write([]byte(sessionToken))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SetSessionCookies starts a cookie session with the access and refresh token of a login and returns its CSRF
+// token. A refresh sets the cookies again with the new tokens. The cookies last as long as the browser session,
+// the tokens themselves expire like bearer tokens.
+func SetSessionCookies(writer http.ResponseWriter, cfg *config.Config, sessionToken, refreshToken string) string {
+	csrfToken := CSRFToken(sessionToken, cfg.Server.JWTSecret)
+	http.SetCookie(writer, sessionCookie(cfg, SessionCookieName, sessionToken, true))
+	http.SetCookie(writer, sessionCookie(cfg, CSRFCookieName, csrfToken, false))
+	http.SetCookie(writer, refreshCookie(cfg, refreshToken))
+	return csrfToken
+}
+
+// ClearSessionCookies ends a cookie session.
+func ClearSessionCookies(writer http.ResponseWriter, cfg *config.Config) {
+	for _, cookie := range []*http.Cookie{
+		sessionCookie(cfg, SessionCookieName, "", true),
+		sessionCookie(cfg, CSRFCookieName, "", false),
+		refreshCookie(cfg, ""),
+	} {
+		cookie.MaxAge = -1
+		http.SetCookie(writer, cookie)
+	}
+}
+
+func refreshCookie(cfg *config.Config, value string) *http.Cookie {
+	cookie := sessionCookie(cfg, RefreshCookieName, value, true)
+	cookie.Path = RefreshCookiePath
+	return cookie
+}
+
+func sessionCookie(cfg *config.Config, name, value string, httpOnly bool) *http.Cookie {
+	sameSite := http.SameSiteStrictMode
+	if cfg.Session.SameSite == config.SameSiteLax {
+		sameSite = http.SameSiteLaxMode
+	}
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: httpOnly,
+		Secure:   cfg.Session.SecureCookies,
+		SameSite: sameSite,
+	}
+}
+
+// validCSRF reports whether a request of a cookie session may pass. Safe methods do not change data and need no
+// CSRF token, all other requests must send the token of their session in the X-CSRF-Token header.
+func validCSRF(request *http.Request, sessionToken string, secret string) bool {
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	sent := request.Header.Get(CSRFHeader)
+	return sent != "" && hmac.Equal([]byte(sent), []byte(CSRFToken(sessionToken, secret)))
+}
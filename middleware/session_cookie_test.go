@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/internal/logger"
+	"kitadoc-backend/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAuthenticator struct{}
+
+func (stubAuthenticator) GetUserByID(logger *logrus.Entry, ctx context.Context, id int) (*models.User, error) {
+	return &models.User{ID: id}, nil
+}
+
+func (stubAuthenticator) ValidateSession(logger *logrus.Entry, ctx context.Context, userID, sessionID int) error {
+	return nil
+}
+
+func TestCookieSessions(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{})
+
+	cfg := &config.Config{}
+	cfg.Server.JWTSecret = "test-secret"
+	cfg.Session.Cookies = true
+	cfg.Session.SameSite = config.SameSiteLax
+	cfg.Session.SecureCookies = true
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID:           1,
+		SessionID:        1,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}).SignedString([]byte(cfg.Server.JWTSecret))
+	require.NoError(t, err)
+
+	handler := Authenticate(stubAuthenticator{}, cfg)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	serve := func(method string, cookie string, csrfToken string) int {
+		request := httptest.NewRequest(method, "/api/v1/children", nil)
+		if cookie != "" {
+			request.AddCookie(&http.Cookie{Name: SessionCookieName, Value: cookie})
+		}
+		if csrfToken != "" {
+			request.Header.Set(CSRFHeader, csrfToken)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	t.Run("login sets the cookies", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		csrfToken := SetSessionCookies(recorder, cfg, token, "refresh-token")
+
+		cookies := recorder.Result().Cookies()
+		require.Len(t, cookies, 3)
+		assert.Equal(t, SessionCookieName, cookies[0].Name)
+		assert.Equal(t, token, cookies[0].Value)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.True(t, cookies[0].Secure)
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+		assert.Equal(t, CSRFCookieName, cookies[1].Name)
+		assert.Equal(t, csrfToken, cookies[1].Value)
+		assert.False(t, cookies[1].HttpOnly, "the frontend reads the CSRF token")
+		assert.Equal(t, RefreshCookieName, cookies[2].Name)
+		assert.Equal(t, "refresh-token", cookies[2].Value)
+		assert.True(t, cookies[2].HttpOnly)
+		assert.Equal(t, RefreshCookiePath, cookies[2].Path, "the refresh token is only sent to the refresh endpoint")
+	})
+
+	t.Run("safe requests need no CSRF token", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(http.MethodGet, token, ""))
+		assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "", ""))
+		assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "forged", ""))
+	})
+
+	t.Run("unsafe requests need the CSRF token of the session", func(t *testing.T) {
+		otherToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: 2, SessionID: 2}).SignedString([]byte(cfg.Server.JWTSecret))
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, serve(http.MethodPost, token, CSRFToken(token, cfg.Server.JWTSecret)))
+		assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, token, ""))
+		assert.Equal(t, http.StatusForbidden, serve(http.MethodDelete, token, CSRFToken(otherToken, cfg.Server.JWTSecret)))
+	})
+
+	t.Run("bearer tokens need no CSRF token", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/children", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("cookies are ignored while cookie sessions are disabled", func(t *testing.T) {
+		disabled := *cfg
+		disabled.Session.Cookies = false
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/children", nil)
+		request.AddCookie(&http.Cookie{Name: SessionCookieName, Value: token})
+		recorder := httptest.NewRecorder()
+		Authenticate(stubAuthenticator{}, &disabled)(handler).ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"kitadoc-backend/models"
+)
+
+// CodeTermsAcceptanceRequired is reported for requests of users who have not accepted the current terms.
+const CodeTermsAcceptanceRequired = "TERMS_ACCEPTANCE_REQUIRED"
+
+// TermsChecker defines the interface for looking up the terms a user still has to accept.
+type TermsChecker interface {
+	GetPendingTermsVersion(logger *logrus.Entry, ctx context.Context, userID int) (*models.TermsVersion, error)
+}
+
+// RequireTermsAcceptance middleware only lets requests of users pass who have accepted the current version
+// of the terms of use and privacy policy. It must run after Authenticate.
+func RequireTermsAcceptance(termsChecker TermsChecker) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			logger := GetLoggerWithReqID(request.Context())
+			user, ok := request.Context().Value(ContextKeyUser).(*models.User)
+			if !ok {
+				logger.Error("Forbidden: User context not found in RequireTermsAcceptance middleware")
+				http.Error(writer, "Forbidden: User context not found", http.StatusForbidden)
+				return
+			}
+
+			pending, err := termsChecker.GetPendingTermsVersion(logger, request.Context(), user.ID)
+			if err != nil {
+				http.Error(writer, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			if pending == nil {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			writer.Header().Set("Content-Type", "application/json")
+			writer.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(writer).Encode(map[string]any{ //nolint:errcheck
+				"error":   "the terms of use and privacy policy have changed, accept them at /api/v1/terms/accept",
+				"code":    CodeTermsAcceptanceRequired,
+				"version": pending.Version,
+			})
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kitadoc-backend/internal/logger"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type termsCheckerFunc func(userID int) (*models.TermsVersion, error)
+
+func (check termsCheckerFunc) GetPendingTermsVersion(logger *logrus.Entry, ctx context.Context, userID int) (*models.TermsVersion, error) {
+	return check(userID)
+}
+
+func TestRequireTermsAcceptance(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{})
+
+	serve := func(checker TermsChecker) *httptest.ResponseRecorder {
+		handler := RequireTermsAcceptance(checker)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/children", nil)
+		request = request.WithContext(context.WithValue(request.Context(), ContextKeyUser, &models.User{ID: 1}))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("passes users who accepted the current terms", func(t *testing.T) {
+		recorder := serve(termsCheckerFunc(func(userID int) (*models.TermsVersion, error) { return nil, nil }))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("rejects users with pending terms", func(t *testing.T) {
+		recorder := serve(termsCheckerFunc(func(userID int) (*models.TermsVersion, error) {
+			return &models.TermsVersion{Version: 3}, nil
+		}))
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"code":"TERMS_ACCEPTANCE_REQUIRED"`)
+		assert.Contains(t, recorder.Body.String(), `"version":3`)
+	})
+
+	t.Run("fails closed", func(t *testing.T) {
+		recorder := serve(termsCheckerFunc(func(userID int) (*models.TermsVersion, error) { return nil, errors.New("database down") }))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Headers reporting the quota of a throttled route, so that clients can back off before they are throttled.
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	// RateLimitResetHeader is the Unix time in seconds at which the oldest counted request leaves the window.
+	RateLimitResetHeader = "X-RateLimit-Reset"
+)
+
+// UserThrottle limits how many requests a user can make to the wrapped routes within a sliding window,
+// e.g. report downloads. Requests count when they start, regardless of their outcome.
+type UserThrottle struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	requests map[string][]time.Time // Start times of the requests within the window, per user ID or client
+}
+
+// NewUserThrottle creates a throttle allowing limit requests per user and window. A limit of 0 disables it.
+func NewUserThrottle(limit int, window time.Duration) *UserThrottle {
+	return &UserThrottle{
+		limit:    limit,
+		window:   window,
+		now:      time.Now,
+		requests: map[string][]time.Time{},
+	}
+}
+
+// Limit answers requests of users over the limit with 429 and a Retry-After header.
+// All responses of throttled users carry the quota headers.
+// It must run after Authenticate, requests without a user are passed through.
+func (throttle *UserThrottle) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		user, ok := request.Context().Value(ContextKeyUser).(*models.User)
+		if !ok || throttle.limit <= 0 {
+			next.ServeHTTP(writer, request)
+			return
+		}
+		throttle.serve(writer, request, next, strconv.Itoa(user.ID), logrus.Fields{"user_id": user.ID})
+	})
+}
+
+// LimitClients limits the requests per client address instead of per user, for public routes without a user,
+// e.g. the break-glass activation. The client is taken from the request like by the allowlist, so that behind
+// a trusted proxy every client has its own quota. Requests without a client address share one quota.
+func (throttle *UserThrottle) LimitClients(allowlist *NetworkAllowlist, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if throttle.limit <= 0 {
+			next.ServeHTTP(writer, request)
+			return
+		}
+		client, _ := allowlist.clientAddr(request)
+		throttle.serve(writer, request, next, "client:"+client.String(), logrus.Fields{"client": client.String()})
+	})
+}
+
+// serve counts the request against the quota of the key and passes it on if it is within the limit.
+func (throttle *UserThrottle) serve(writer http.ResponseWriter, request *http.Request, next http.Handler, key string, fields logrus.Fields) {
+	quota := throttle.allow(key)
+	writer.Header().Set(RateLimitLimitHeader, strconv.Itoa(throttle.limit))
+	writer.Header().Set(RateLimitRemainingHeader, strconv.Itoa(quota.remaining))
+	// Rounded up, so that clients waiting until the reset are not throttled again
+	writer.Header().Set(RateLimitResetHeader, strconv.FormatInt(quota.reset.Add(time.Second-time.Nanosecond).Unix(), 10))
+	if !quota.allowed {
+		GetLoggerWithReqID(request.Context()).WithFields(fields).WithField("pattern", request.Pattern).
+			Warn("Request throttled, the limit of the route was exceeded")
+		retryAfter := quota.reset.Sub(throttle.now())
+		writer.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second).Seconds())))
+		http.Error(writer, "Too many requests, please try again later", http.StatusTooManyRequests)
+		return
+	}
+	next.ServeHTTP(writer, request)
+}
+
+// throttleQuota is the state of a user's quota after a request.
+type throttleQuota struct {
+	allowed   bool
+	remaining int
+	// reset is when the oldest counted request leaves the window and frees a request.
+	reset time.Time
+}
+
+// allow records a request of the user or client if it is within the limit and returns the remaining quota.
+func (throttle *UserThrottle) allow(key string) throttleQuota {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+
+	now := throttle.now()
+	windowStart := now.Add(-throttle.window)
+	recent := throttle.requests[key][:0]
+	for _, at := range throttle.requests[key] {
+		if at.After(windowStart) {
+			recent = append(recent, at)
+		}
+	}
+	allowed := len(recent) < throttle.limit
+	if allowed {
+		recent = append(recent, now)
+	}
+	throttle.requests[key] = recent
+	return throttleQuota{
+		allowed:   allowed,
+		remaining: throttle.limit - len(recent),
+		reset:     recent[0].Add(throttle.window),
+	}
+}
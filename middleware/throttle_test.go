@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"kitadoc-backend/internal/logger"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserThrottle(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{})
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	newThrottle := func(limit int) *UserThrottle {
+		throttle := NewUserThrottle(limit, time.Hour)
+		throttle.now = func() time.Time { return now }
+		return throttle
+	}
+	serve := func(handler http.Handler, userID int) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request = request.WithContext(context.WithValue(request.Context(), ContextKeyUser, &models.User{ID: userID}))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+	ok := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {})
+
+	t.Run("answers 429 once a user exceeds the limit", func(t *testing.T) {
+		handler := newThrottle(2).Limit(ok)
+
+		assert.Equal(t, http.StatusOK, serve(handler, 1).Code)
+		now = now.Add(10 * time.Minute)
+		assert.Equal(t, http.StatusOK, serve(handler, 1).Code)
+		recorder := serve(handler, 1)
+
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Equal(t, "3000", recorder.Header().Get("Retry-After"))
+		assert.Equal(t, "0", recorder.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, http.StatusOK, serve(handler, 2).Code, "other users are not affected")
+
+		now = now.Add(50 * time.Minute)
+		assert.Equal(t, http.StatusOK, serve(handler, 1).Code, "the first request left the window")
+	})
+
+	t.Run("reports the remaining quota", func(t *testing.T) {
+		handler := newThrottle(3).Limit(ok)
+		start := now
+
+		recorder := serve(handler, 1)
+		assert.Equal(t, "3", recorder.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, "2", recorder.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, strconv.FormatInt(start.Add(time.Hour).Unix(), 10), recorder.Header().Get(RateLimitResetHeader))
+
+		now = now.Add(30*time.Minute + 500*time.Millisecond)
+		recorder = serve(handler, 1)
+		assert.Equal(t, "1", recorder.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, strconv.FormatInt(start.Add(time.Hour).Unix(), 10), recorder.Header().Get(RateLimitResetHeader),
+			"the reset is when the oldest request leaves the window")
+
+		now = now.Add(30 * time.Minute)
+		recorder = serve(handler, 1)
+		assert.Equal(t, "1", recorder.Header().Get(RateLimitRemainingHeader), "the first request left the window")
+		assert.Equal(t, strconv.FormatInt(start.Add(90*time.Minute).Unix()+1, 10), recorder.Header().Get(RateLimitResetHeader),
+			"the reset is rounded up to the next second")
+	})
+
+	t.Run("a limit of 0 disables the throttle", func(t *testing.T) {
+		handler := newThrottle(0).Limit(ok)
+
+		for range 3 {
+			recorder := serve(handler, 1)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Empty(t, recorder.Header().Get(RateLimitLimitHeader))
+		}
+	})
+
+	t.Run("public routes are limited per client", func(t *testing.T) {
+		proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32")}
+		handler := newThrottle(1).LimitClients(NewNetworkAllowlist(nil, proxies, nil), ok)
+		serveFrom := func(remoteAddr, forwardedFor string) int {
+			request := httptest.NewRequest(http.MethodPost, "/", nil)
+			request.RemoteAddr = remoteAddr
+			if forwardedFor != "" {
+				request.Header.Set("X-Forwarded-For", forwardedFor)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder.Code
+		}
+
+		assert.Equal(t, http.StatusOK, serveFrom("192.0.2.1:1234", ""))
+		assert.Equal(t, http.StatusTooManyRequests, serveFrom("192.0.2.1:4321", ""))
+		assert.Equal(t, http.StatusOK, serveFrom("10.0.0.1:1234", "192.0.2.2"), "clients behind the proxy have their own quota")
+		assert.Equal(t, http.StatusTooManyRequests, serveFrom("10.0.0.1:1234", "192.0.2.2"))
+	})
+}
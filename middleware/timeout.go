@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Timeout cancels the request context after the timeout and answers 504 Gateway Timeout if the handler
+// has not finished by then. Services observe the cancelled context and stop, e.g. a report generation
+// does not archive a report nobody receives. If the client disconnects first, no response is written.
+// The response is buffered until the handler returns; a timeout of 0 disables the middleware.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			ctx, cancel := context.WithTimeout(request.Context(), timeout)
+			defer cancel()
+
+			buffered := &timeoutWriter{header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(buffered, request.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+				buffered.mu.Lock()
+				defer buffered.mu.Unlock()
+				for key, values := range buffered.header {
+					writer.Header()[key] = values
+				}
+				if buffered.status == 0 {
+					buffered.status = http.StatusOK
+				}
+				writer.WriteHeader(buffered.status)
+				writer.Write(buffered.body.Bytes()) //nolint:errcheck
+			case <-ctx.Done():
+				buffered.mu.Lock()
+				defer buffered.mu.Unlock()
+				buffered.expired = true
+				logger := GetLoggerWithReqID(request.Context()).WithField("pattern", request.Pattern)
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					logger.WithField("timeout", timeout).Warn("Request timed out")
+					http.Error(writer, "Request timed out", http.StatusGatewayTimeout)
+					return
+				}
+				logger.Info("Client disconnected before the request completed")
+			}
+		})
+	}
+}
+
+// timeoutWriter buffers the response of a handler running under Timeout.
+// Writes after the request expired are discarded.
+type timeoutWriter struct {
+	mu      sync.Mutex
+	header  http.Header
+	body    bytes.Buffer
+	status  int
+	expired bool
+}
+
+func (writer *timeoutWriter) Header() http.Header {
+	return writer.header
+}
+
+func (writer *timeoutWriter) WriteHeader(status int) {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	if writer.expired || writer.status != 0 {
+		return
+	}
+	writer.status = status
+}
+
+func (writer *timeoutWriter) Write(content []byte) (int, error) {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	if writer.expired {
+		return 0, http.ErrHandlerTimeout
+	}
+	if writer.status == 0 {
+		writer.status = http.StatusOK
+	}
+	return writer.body.Write(content)
+}
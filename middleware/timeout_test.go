@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kitadoc-backend/internal/logger"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{})
+
+	t.Run("passes the response of a fast handler", func(t *testing.T) {
+		handler := Timeout(time.Second)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", "application/json")
+			writer.WriteHeader(http.StatusCreated)
+			writer.Write([]byte(`{"id":1}`)) //nolint:errcheck
+		}))
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, `{"id":1}`, recorder.Body.String())
+	})
+
+	t.Run("answers 504 and cancels the handler", func(t *testing.T) {
+		canceled := make(chan error, 1)
+		handler := Timeout(10 * time.Millisecond)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			<-request.Context().Done()
+			canceled <- request.Context().Err()
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		}))
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+		assert.Equal(t, "Request timed out\n", recorder.Body.String())
+		assert.Equal(t, context.DeadlineExceeded, <-canceled)
+	})
+
+	t.Run("writes nothing when the client disconnects", func(t *testing.T) {
+		handler := Timeout(time.Second)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			<-request.Context().Done()
+		}))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+		assert.False(t, recorder.Flushed)
+		assert.Empty(t, recorder.Body.String())
+	})
+}
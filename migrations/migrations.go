@@ -2,5 +2,7 @@ package migrations
 
 import "embed"
 
-//go:embed migrations/*.sql
+// Files holds the migrations of both database engines, migrations/ for SQLite and postgres/ for PostgreSQL.
+//
+//go:embed migrations/*.sql postgres/*.sql
 var Files embed.FS
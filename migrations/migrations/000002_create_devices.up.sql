@@ -0,0 +1,32 @@
+-- Devices Table (push notification targets per user)
+CREATE TABLE IF NOT EXISTS devices (
+    device_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    platform VARCHAR(20) NOT NULL, -- e.g., 'fcm', 'webpush'
+    token TEXT UNIQUE NOT NULL, -- FCM registration token or Web Push endpoint URL
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT chk_device_platform_valid CHECK (platform IN ('fcm', 'webpush')),
+    CONSTRAINT chk_device_token_not_empty CHECK (LENGTH(TRIM(token)) > 0)
+);
+
+-- Notification Preferences Table (per-user opt-out)
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INTEGER PRIMARY KEY,
+    push_enabled BOOLEAN NOT NULL DEFAULT 1,
+    notify_approvals BOOLEAN NOT NULL DEFAULT 1,
+    notify_rejections BOOLEAN NOT NULL DEFAULT 1,
+    notify_reminders BOOLEAN NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_devices_user ON devices(user_id);
+
+CREATE TRIGGER IF NOT EXISTS trg_devices_updated_at
+    AFTER UPDATE ON devices
+    FOR EACH ROW
+BEGIN
+    UPDATE devices SET updated_at = CURRENT_TIMESTAMP WHERE device_id = NEW.device_id;
+END;
@@ -0,0 +1,30 @@
+-- Announcements Table (team-wide information posted by admins)
+CREATE TABLE IF NOT EXISTS announcements (
+    announcement_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_announcement_title_not_empty CHECK (LENGTH(TRIM(title)) > 0)
+);
+
+-- Announcement Acknowledgements Table (read receipts)
+CREATE TABLE IF NOT EXISTS announcement_acknowledgements (
+    announcement_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    acknowledged_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (announcement_id, user_id),
+    FOREIGN KEY (announcement_id) REFERENCES announcements(announcement_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcement_acknowledgements_user ON announcement_acknowledgements(user_id);
+
+CREATE TRIGGER IF NOT EXISTS trg_announcements_updated_at
+    AFTER UPDATE ON announcements
+    FOR EACH ROW
+BEGIN
+    UPDATE announcements SET updated_at = CURRENT_TIMESTAMP WHERE announcement_id = NEW.announcement_id;
+END;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_children_archived;
+ALTER TABLE children DROP COLUMN is_preschooler;
+ALTER TABLE children DROP COLUMN archived_at;
@@ -0,0 +1,8 @@
+-- Children leave the facility through the school year rollover; they are archived instead of deleted
+-- so that their documentation remains available.
+ALTER TABLE children ADD COLUMN archived_at TIMESTAMP;
+
+-- Flag for the pre-school cohort (Vorschulkinder) of the current school year
+ALTER TABLE children ADD COLUMN is_preschooler BOOLEAN NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_children_archived ON children(archived_at);
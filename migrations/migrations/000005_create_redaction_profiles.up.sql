@@ -0,0 +1,24 @@
+-- Redaction Profiles Table (which information is left out of generated reports, e.g. copies for schools)
+CREATE TABLE IF NOT EXISTS redaction_profiles (
+    redaction_profile_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    profile_name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    hide_child_last_name BOOLEAN NOT NULL DEFAULT 0,
+    hide_birthdate BOOLEAN NOT NULL DEFAULT 0,
+    hide_admission_date BOOLEAN NOT NULL DEFAULT 0,
+    hide_school_enrollment BOOLEAN NOT NULL DEFAULT 0,
+    hide_teacher_names BOOLEAN NOT NULL DEFAULT 0,
+    hide_kita_contact BOOLEAN NOT NULL DEFAULT 0,
+    hide_observation_dates BOOLEAN NOT NULL DEFAULT 0,
+    excluded_category_ids TEXT NOT NULL DEFAULT '[]', -- JSON array of category IDs
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_redaction_profile_name_not_empty CHECK (LENGTH(TRIM(profile_name)) > 0)
+);
+
+CREATE TRIGGER IF NOT EXISTS trg_redaction_profiles_updated_at
+    AFTER UPDATE ON redaction_profiles
+    FOR EACH ROW
+BEGIN
+    UPDATE redaction_profiles SET updated_at = CURRENT_TIMESTAMP WHERE redaction_profile_id = NEW.redaction_profile_id;
+END;
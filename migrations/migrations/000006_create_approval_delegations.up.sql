@@ -0,0 +1,32 @@
+-- Approval Delegations Table (user A lets user B approve on their behalf for a date range)
+CREATE TABLE IF NOT EXISTS approval_delegations (
+    delegation_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    delegator_user_id INTEGER NOT NULL,
+    delegate_user_id INTEGER NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (delegator_user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (delegate_user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT chk_delegation_distinct_users CHECK (delegator_user_id <> delegate_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_delegations_delegate ON approval_delegations(delegate_user_id);
+
+-- Audit Log Table (who did what, and on whose behalf)
+CREATE TABLE IF NOT EXISTS audit_log (
+    audit_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id INTEGER,
+    actor_user_id INTEGER,
+    on_behalf_of_user_id INTEGER,
+    delegation_id INTEGER,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (actor_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (on_behalf_of_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (delegation_id) REFERENCES approval_delegations(delegation_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id);
@@ -0,0 +1,6 @@
+-- Validation Rules Table (facility-wide business rule configuration, a single row stored as JSON)
+CREATE TABLE IF NOT EXISTS validation_rules (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    rules TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+ALTER TABLE documentation_entries DROP COLUMN structured_data;
+ALTER TABLE categories DROP COLUMN form_schema;
@@ -0,0 +1,5 @@
+-- Category-specific observation forms: a JSON schema per category describing the structured fields,
+-- and the (encrypted) values of those fields per documentation entry.
+ALTER TABLE categories ADD COLUMN form_schema TEXT;
+
+ALTER TABLE documentation_entries ADD COLUMN structured_data TEXT;
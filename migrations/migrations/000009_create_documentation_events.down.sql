@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trg_documentation_events_append_only;
+DROP TABLE IF EXISTS documentation_events;
@@ -0,0 +1,28 @@
+-- Documentation Events Table (append-only lifecycle stream of the documentation entries, numbered per child)
+CREATE TABLE IF NOT EXISTS documentation_events (
+    event_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    child_id INTEGER NOT NULL,
+    sequence INTEGER NOT NULL,
+    entry_id INTEGER NOT NULL, -- No foreign key, the stream outlives deleted entries
+    event_type VARCHAR(50) NOT NULL,
+    actor_user_id INTEGER,
+    on_behalf_of_user_id INTEGER,
+    delegation_id INTEGER,
+    payload TEXT, -- Encrypted JSON
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (actor_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (on_behalf_of_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (delegation_id) REFERENCES approval_delegations(delegation_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT uq_documentation_events_sequence UNIQUE (child_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_documentation_events_entry ON documentation_events(entry_id);
+
+-- Events are never changed once written. Deleting is still possible, together with the child,
+-- and the foreign keys may still clear the references to deleted users.
+CREATE TRIGGER IF NOT EXISTS trg_documentation_events_append_only
+BEFORE UPDATE OF sequence, entry_id, event_type, payload, created_at ON documentation_events
+BEGIN
+    SELECT RAISE(ABORT, 'documentation events are append-only');
+END;
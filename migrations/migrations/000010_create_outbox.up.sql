@@ -0,0 +1,15 @@
+-- Outbox Table (messages to other systems, written in the transaction of the change that caused them)
+CREATE TABLE IF NOT EXISTS outbox (
+    message_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    channel VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL, -- Encrypted JSON
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP,
+    CONSTRAINT chk_outbox_status CHECK (status IN ('pending', 'delivered', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(status, next_attempt_at);
@@ -0,0 +1,7 @@
+-- Bootstrap Runs Table (marks that the default data has been installed, at most one row)
+CREATE TABLE IF NOT EXISTS bootstrap_runs (
+    bootstrap_id INTEGER PRIMARY KEY CHECK (bootstrap_id = 1),
+    completed_by_user_id INTEGER,
+    completed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (completed_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS group_children;
+DROP TABLE IF EXISTS group_assistants;
+DROP TABLE IF EXISTS kita_groups;
@@ -0,0 +1,34 @@
+-- Groups Table ("groups" is a keyword in SQLite)
+CREATE TABLE IF NOT EXISTS kita_groups (
+    group_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_name VARCHAR(100) UNIQUE NOT NULL,
+    capacity INTEGER NOT NULL,
+    min_age_months INTEGER,
+    max_age_months INTEGER,
+    room VARCHAR(100),
+    lead_teacher_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (lead_teacher_id) REFERENCES teachers(teacher_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_group_capacity CHECK (capacity > 0),
+    CONSTRAINT chk_group_age_band CHECK (min_age_months IS NULL OR max_age_months IS NULL OR min_age_months <= max_age_months)
+);
+
+-- Assistant teachers of a group
+CREATE TABLE IF NOT EXISTS group_assistants (
+    group_id INTEGER NOT NULL,
+    teacher_id INTEGER NOT NULL,
+    PRIMARY KEY (group_id, teacher_id),
+    FOREIGN KEY (group_id) REFERENCES kita_groups(group_id) ON DELETE CASCADE,
+    FOREIGN KEY (teacher_id) REFERENCES teachers(teacher_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- Group membership of children, a child belongs to at most one group
+CREATE TABLE IF NOT EXISTS group_children (
+    child_id INTEGER PRIMARY KEY,
+    group_id INTEGER NOT NULL,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES kita_groups(group_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_children_group ON group_children(group_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_assignments_open_primary;
+ALTER TABLE child_teacher_assignments DROP COLUMN assignment_type;
@@ -0,0 +1,9 @@
+ALTER TABLE child_teacher_assignments ADD COLUMN assignment_type TEXT NOT NULL DEFAULT 'primary' CHECK (assignment_type IN ('primary', 'secondary', 'intern'));
+
+-- Existing children may have several open assignments. Keep the most recent one as primary.
+UPDATE child_teacher_assignments SET assignment_type = 'secondary'
+WHERE end_date IS NULL AND assignment_id NOT IN (
+    SELECT MAX(assignment_id) FROM child_teacher_assignments WHERE end_date IS NULL GROUP BY child_id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_assignments_open_primary ON child_teacher_assignments(child_id) WHERE assignment_type = 'primary' AND end_date IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS generated_report_entries;
+DROP TABLE IF EXISTS generated_reports;
+ALTER TABLE documentation_entries DROP COLUMN revision_of_entry_id;
+ALTER TABLE documentation_entries DROP COLUMN locked_at;
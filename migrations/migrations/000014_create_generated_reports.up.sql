@@ -0,0 +1,23 @@
+-- Entries included in a generated report are locked. Changes are made in a revision, a new entry referring to the locked one.
+-- revision_of_entry_id has no foreign key, since SQLite cannot drop such a column again in the down migration.
+ALTER TABLE documentation_entries ADD COLUMN locked_at TIMESTAMP;
+ALTER TABLE documentation_entries ADD COLUMN revision_of_entry_id INTEGER;
+
+CREATE TABLE IF NOT EXISTS generated_reports (
+    report_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    child_id INTEGER NOT NULL,
+    generated_by_user_id INTEGER,
+    generated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (generated_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS generated_report_entries (
+    report_id INTEGER NOT NULL,
+    entry_id INTEGER NOT NULL,
+    PRIMARY KEY (report_id, entry_id),
+    FOREIGN KEY (report_id) REFERENCES generated_reports(report_id) ON DELETE CASCADE,
+    FOREIGN KEY (entry_id) REFERENCES documentation_entries(entry_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_generated_reports_child ON generated_reports(child_id);
@@ -0,0 +1,4 @@
+ALTER TABLE generated_reports DROP COLUMN content_size;
+ALTER TABLE generated_reports DROP COLUMN content;
+ALTER TABLE generated_reports DROP COLUMN parameters;
+ALTER TABLE generated_reports DROP COLUMN file_name;
@@ -0,0 +1,7 @@
+-- Generated reports are archived with the options they were generated with, so past versions can be downloaded again.
+-- The content is encrypted like the documentation entries it was generated from.
+-- Reports generated before the archive keep NULL content.
+ALTER TABLE generated_reports ADD COLUMN file_name TEXT;
+ALTER TABLE generated_reports ADD COLUMN parameters TEXT;
+ALTER TABLE generated_reports ADD COLUMN content TEXT;
+ALTER TABLE generated_reports ADD COLUMN content_size INTEGER NOT NULL DEFAULT 0;
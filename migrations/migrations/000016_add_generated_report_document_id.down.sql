@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_generated_reports_document_id;
+ALTER TABLE generated_reports DROP COLUMN document_id;
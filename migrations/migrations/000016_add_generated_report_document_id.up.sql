@@ -0,0 +1,4 @@
+-- The document ID is printed in the footer and the properties of a report, so that copies can be traced back to the archive.
+ALTER TABLE generated_reports ADD COLUMN document_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_generated_reports_document_id ON generated_reports(document_id);
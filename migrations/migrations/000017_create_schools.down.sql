@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS school_children;
+DROP TABLE IF EXISTS schools;
@@ -0,0 +1,24 @@
+-- Schools the children are expected to enroll at, with the contact for handing over reports
+CREATE TABLE IF NOT EXISTS schools (
+    school_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    school_name VARCHAR(200) UNIQUE NOT NULL,
+    street VARCHAR(200),
+    house_number VARCHAR(20),
+    postal_code VARCHAR(20),
+    city VARCHAR(100),
+    contact_person VARCHAR(200),
+    email VARCHAR(200),
+    phone_number VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Expected school enrollment of children, a child is expected at most at one school
+CREATE TABLE IF NOT EXISTS school_children (
+    child_id INTEGER PRIMARY KEY,
+    school_id INTEGER NOT NULL,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (school_id) REFERENCES schools(school_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_school_children_school ON school_children(school_id);
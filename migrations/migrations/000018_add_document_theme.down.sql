@@ -0,0 +1,4 @@
+ALTER TABLE kita_masterdata DROP COLUMN logo_content_type;
+ALTER TABLE kita_masterdata DROP COLUMN logo;
+ALTER TABLE kita_masterdata DROP COLUMN document_accent_color;
+ALTER TABLE kita_masterdata DROP COLUMN document_font;
@@ -0,0 +1,5 @@
+-- The Träger branding used by the document builders is stored with the facility settings.
+ALTER TABLE kita_masterdata ADD COLUMN document_font TEXT;
+ALTER TABLE kita_masterdata ADD COLUMN document_accent_color TEXT;
+ALTER TABLE kita_masterdata ADD COLUMN logo BLOB;
+ALTER TABLE kita_masterdata ADD COLUMN logo_content_type TEXT;
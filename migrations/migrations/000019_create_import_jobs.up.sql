@@ -0,0 +1,23 @@
+-- Bulk imports are persisted with the status of every row, so a failed import can be resumed or rolled back.
+CREATE TABLE IF NOT EXISTS import_jobs (
+    import_job_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    file_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+-- The parsed row is stored encrypted, it contains the personal data of the child.
+CREATE TABLE IF NOT EXISTS import_job_rows (
+    import_job_id INTEGER NOT NULL,
+    row_number INTEGER NOT NULL,
+    data TEXT,
+    status TEXT NOT NULL,
+    error TEXT,
+    child_id INTEGER,
+    PRIMARY KEY (import_job_id, row_number),
+    FOREIGN KEY (import_job_id) REFERENCES import_jobs(import_job_id) ON DELETE CASCADE,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
@@ -0,0 +1,6 @@
+-- Data migrations normalize existing rows in Go code, each runs once after the schema migrations.
+CREATE TABLE IF NOT EXISTS data_migrations (
+    name TEXT PRIMARY KEY,
+    rows_changed INTEGER NOT NULL,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
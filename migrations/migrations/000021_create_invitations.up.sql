@@ -0,0 +1,15 @@
+-- Invitations are single-use signup links with a preset role, handed out by admins when open registration is disabled.
+-- Only the SHA-256 hash of the token is stored.
+CREATE TABLE IF NOT EXISTS invitations (
+    invitation_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    used_by_user_id INTEGER,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (used_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_invitation_role_valid CHECK (role IN ('teacher', 'admin'))
+);
@@ -0,0 +1,2 @@
+-- Teachers who leave the facility are deactivated instead of deleted, so that their documentation keeps its author.
+ALTER TABLE teachers ADD COLUMN deactivated_at TIMESTAMP;
@@ -0,0 +1,2 @@
+-- Categories that are no longer used are archived instead of deleted, so that existing entries and report sections keep them.
+ALTER TABLE categories ADD COLUMN archived_at TIMESTAMP;
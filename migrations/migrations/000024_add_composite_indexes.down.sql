@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_audit_log_actor;
+CREATE INDEX IF NOT EXISTS idx_assignments_child ON child_teacher_assignments(child_id);
+DROP INDEX IF EXISTS idx_assignments_child_end;
+CREATE INDEX IF NOT EXISTS idx_documentation_child ON documentation_entries(child_id);
+DROP INDEX IF EXISTS idx_documentation_child_date;
@@ -0,0 +1,7 @@
+-- Composite indexes for the hot queries: a child's observations by date, a child's open assignments and what a user did recently.
+-- They replace the single column indexes on child_id, which are prefixes of them.
+CREATE INDEX IF NOT EXISTS idx_documentation_child_date ON documentation_entries(child_id, observation_date);
+DROP INDEX IF EXISTS idx_documentation_child;
+CREATE INDEX IF NOT EXISTS idx_assignments_child_end ON child_teacher_assignments(child_id, end_date);
+DROP INDEX IF EXISTS idx_assignments_child;
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_user_id, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE categories DROP COLUMN max_age_months;
+ALTER TABLE categories DROP COLUMN min_age_months;
@@ -0,0 +1,3 @@
+-- Categories can be limited to an age band in months, e.g. school readiness from 60 months, to suggest them for children of that age.
+ALTER TABLE categories ADD COLUMN min_age_months INTEGER;
+ALTER TABLE categories ADD COLUMN max_age_months INTEGER;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_documentation_teacher_category;
+DROP TABLE IF EXISTS category_pins;
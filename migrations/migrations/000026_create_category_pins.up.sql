@@ -0,0 +1,12 @@
+-- Categories a user pinned to the top of the category picker for new entries.
+CREATE TABLE IF NOT EXISTS category_pins (
+    user_id INTEGER NOT NULL,
+    category_id INTEGER NOT NULL,
+    pinned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, category_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(category_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- The favorite categories of a teacher are counted from their entries.
+CREATE INDEX IF NOT EXISTS idx_documentation_teacher_category ON documentation_entries(documenting_teacher_id, category_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS documentation_digests;
+ALTER TABLE notification_preferences DROP COLUMN digest_email;
+ALTER TABLE notification_preferences DROP COLUMN notify_digest;
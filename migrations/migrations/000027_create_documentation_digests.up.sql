@@ -0,0 +1,12 @@
+-- The weekly documentation digest is emailed to the address of the preferences, which is encrypted.
+ALTER TABLE notification_preferences ADD COLUMN notify_digest BOOLEAN NOT NULL DEFAULT 1;
+ALTER TABLE notification_preferences ADD COLUMN digest_email TEXT;
+
+-- Weeks whose digest has been sent to a user, so that it is sent once even if the server restarts.
+CREATE TABLE IF NOT EXISTS documentation_digests (
+    user_id INTEGER NOT NULL,
+    week_start DATE NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, week_start),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_audit_log_action;
+DROP TABLE IF EXISTS quality_reports;
@@ -0,0 +1,12 @@
+-- The monthly quality report of every completed month is archived, so that it stays the same when it is
+-- downloaded again. The content is encrypted, it names teachers.
+CREATE TABLE IF NOT EXISTS quality_reports (
+    month TEXT PRIMARY KEY,
+    document_id TEXT NOT NULL,
+    generated_at TIMESTAMP NOT NULL,
+    content TEXT NOT NULL,
+    content_size INTEGER NOT NULL
+);
+
+-- Reminders are counted per teacher from the audit log.
+CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log(action, created_at);
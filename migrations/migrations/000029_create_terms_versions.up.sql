@@ -0,0 +1,22 @@
+-- Versions of the terms of use and privacy policy. The highest version is the current one.
+CREATE TABLE IF NOT EXISTS terms_versions (
+    version INTEGER PRIMARY KEY AUTOINCREMENT,
+    terms_of_use TEXT NOT NULL,
+    privacy_policy TEXT NOT NULL,
+    change_summary TEXT NOT NULL DEFAULT '',
+    published_by_user_id INTEGER,
+    published_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (published_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_terms_of_use_not_empty CHECK (LENGTH(TRIM(terms_of_use)) > 0),
+    CONSTRAINT chk_privacy_policy_not_empty CHECK (LENGTH(TRIM(privacy_policy)) > 0)
+);
+
+-- Which user accepted which version. The acceptance of the current version is checked on every request.
+CREATE TABLE IF NOT EXISTS terms_acceptances (
+    user_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    accepted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, version),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (version) REFERENCES terms_versions(version) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,11 @@
+-- The banner shown to all users, also before login. There is at most one.
+CREATE TABLE IF NOT EXISTS status_banner (
+    banner_id INTEGER PRIMARY KEY CHECK (banner_id = 1),
+    message TEXT NOT NULL,
+    level TEXT NOT NULL CHECK (level IN ('info', 'warning', 'maintenance')),
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    updated_by_user_id INTEGER,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (updated_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
@@ -0,0 +1,15 @@
+-- Versions of the editable email templates. The highest version of a key is the one sent, without any
+-- version the built-in default is used.
+CREATE TABLE IF NOT EXISTS email_templates (
+    template_key TEXT NOT NULL CHECK (template_key IN ('reminder', 'rejection', 'report_delivery')),
+    version INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    change_summary TEXT NOT NULL DEFAULT '',
+    published_by_user_id INTEGER,
+    published_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (template_key, version),
+    FOREIGN KEY (published_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_subject_not_empty CHECK (LENGTH(TRIM(subject)) > 0),
+    CONSTRAINT chk_body_not_empty CHECK (LENGTH(TRIM(body)) > 0)
+);
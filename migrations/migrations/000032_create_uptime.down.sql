@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS uptime_incidents;
+DROP INDEX IF EXISTS idx_health_checks_checked_at;
+DROP TABLE IF EXISTS health_checks;
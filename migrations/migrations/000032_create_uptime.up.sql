@@ -0,0 +1,21 @@
+-- Results of the periodic health checks, the uptime history is computed from them.
+CREATE TABLE IF NOT EXISTS health_checks (
+    check_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    checked_at TIMESTAMP NOT NULL,
+    healthy BOOLEAN NOT NULL,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_health_checks_checked_at ON health_checks(checked_at);
+
+-- Annotations of the admins explaining outages, shown alongside the uptime history.
+CREATE TABLE IF NOT EXISTS uptime_incidents (
+    incident_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    note TEXT NOT NULL,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_note_not_empty CHECK (LENGTH(TRIM(note)) > 0)
+);
@@ -0,0 +1,9 @@
+-- Advisory locks of documentation entries being edited, renewed by the editor until they expire.
+CREATE TABLE IF NOT EXISTS entry_edit_locks (
+    entry_id INTEGER PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    acquired_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (entry_id) REFERENCES documentation_entries(entry_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
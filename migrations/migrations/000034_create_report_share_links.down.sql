@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS report_share_link_accesses;
+DROP TABLE IF EXISTS report_share_links;
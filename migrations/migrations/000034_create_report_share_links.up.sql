@@ -0,0 +1,31 @@
+-- Share links hand out a single archived report read-only, e.g. to a Frühförderstelle, instead of an email attachment.
+-- Only the SHA-256 hash of the token and the bcrypt hash of the access code are stored, the recipient is encrypted.
+CREATE TABLE IF NOT EXISTS report_share_links (
+    share_link_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    report_id INTEGER NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    access_code_hash VARCHAR(255) NOT NULL,
+    recipient TEXT NOT NULL,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    accesses INTEGER NOT NULL DEFAULT 0,
+    last_accessed_at TIMESTAMP,
+    FOREIGN KEY (report_id) REFERENCES generated_reports(report_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_share_links_report ON report_share_links(report_id);
+
+-- Every attempt to open a share link, so that the facility can tell whether and when the report was read.
+CREATE TABLE IF NOT EXISTS report_share_link_accesses (
+    access_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    share_link_id INTEGER NOT NULL,
+    accessed_at TIMESTAMP NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    FOREIGN KEY (share_link_id) REFERENCES report_share_links(share_link_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT chk_share_link_access_outcome_valid CHECK (outcome IN ('granted', 'wrong_code', 'expired', 'revoked'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_share_link_accesses_link ON report_share_link_accesses(share_link_id);
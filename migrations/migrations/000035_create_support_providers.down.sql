@@ -0,0 +1,3 @@
+ALTER TABLE redaction_profiles DROP COLUMN hide_support_providers;
+DROP TABLE IF EXISTS child_supports;
+DROP TABLE IF EXISTS support_providers;
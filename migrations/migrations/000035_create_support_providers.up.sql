@@ -0,0 +1,34 @@
+-- External therapy and support providers, e.g. Logopädie or Frühförderung
+CREATE TABLE IF NOT EXISTS support_providers (
+    provider_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    provider_name VARCHAR(200) UNIQUE NOT NULL,
+    provider_type VARCHAR(30) NOT NULL CHECK (provider_type IN ('speech_therapy', 'occupational_therapy', 'physiotherapy', 'early_intervention', 'other')),
+    contact_person VARCHAR(200),
+    email VARCHAR(200),
+    phone_number VARCHAR(50),
+    address VARCHAR(300),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Support of a child by a provider. The consent reference and the appointment notes are encrypted.
+-- Providers with supports cannot be deleted, the supports are part of the documentation of the child.
+CREATE TABLE IF NOT EXISTS child_supports (
+    support_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    child_id INTEGER NOT NULL,
+    provider_id INTEGER NOT NULL,
+    consent_reference TEXT,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    appointment_notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (provider_id) REFERENCES support_providers(provider_id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_child_supports_child ON child_supports(child_id);
+CREATE INDEX IF NOT EXISTS idx_child_supports_provider ON child_supports(provider_id);
+
+-- Supports are listed in the Inklusion/Förderung section of reports unless the redaction profile hides them
+ALTER TABLE redaction_profiles ADD COLUMN hide_support_providers BOOLEAN NOT NULL DEFAULT 0;
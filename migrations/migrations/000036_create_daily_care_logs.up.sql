@@ -0,0 +1,23 @@
+-- Daily care of U3 children: meals, nap and diapers or toileting, at most one log per child and day.
+-- The log is separate from the Bildungsdokumentation and never part of reports.
+CREATE TABLE IF NOT EXISTS daily_care_logs (
+    care_log_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    child_id INTEGER NOT NULL,
+    care_date DATE NOT NULL,
+    breakfast VARCHAR(10) CHECK (breakfast IN ('none', 'little', 'half', 'most', 'all')),
+    lunch VARCHAR(10) CHECK (lunch IN ('none', 'little', 'half', 'most', 'all')),
+    snack VARCHAR(10) CHECK (snack IN ('none', 'little', 'half', 'most', 'all')),
+    nap_minutes INTEGER CHECK (nap_minutes >= 0),
+    diaper_changes INTEGER CHECK (diaper_changes >= 0),
+    toilet_visits INTEGER CHECK (toilet_visits >= 0),
+    note TEXT,
+    hidden_from_parents BOOLEAN NOT NULL DEFAULT 0,
+    recorded_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (child_id, care_date),
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (recorded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_daily_care_logs_date ON daily_care_logs(care_date);
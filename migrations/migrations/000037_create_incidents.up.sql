@@ -0,0 +1,22 @@
+-- Incidents and accidents of children. The description of what happened, the injuries, the first aid, the
+-- witnesses and the physician are encrypted. An incident is exported as Unfallanzeige once it is complete.
+CREATE TABLE IF NOT EXISTS incidents (
+    incident_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    child_id INTEGER NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    location VARCHAR(200),
+    description TEXT,
+    injuries TEXT,
+    first_aid TEXT,
+    witnesses TEXT,
+    medical_treatment BOOLEAN NOT NULL DEFAULT 0,
+    physician TEXT,
+    parent_informed_at TIMESTAMP,
+    reported_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (reported_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_incidents_child ON incidents(child_id);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_medication_administrations_confirmed_once;
+DROP TRIGGER IF EXISTS trg_medication_administrations_read_only;
+DROP TABLE IF EXISTS medication_administrations;
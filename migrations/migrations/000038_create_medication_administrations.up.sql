@@ -0,0 +1,39 @@
+-- Medication given to children, on written instruction of the parents. The medication, the dose, the
+-- instruction reference and the note are encrypted. Every administration is confirmed by a second user.
+-- Teachers who administered medication cannot be deleted, they can still be deactivated.
+CREATE TABLE IF NOT EXISTS medication_administrations (
+    administration_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    child_id INTEGER NOT NULL,
+    medication TEXT NOT NULL,
+    dose TEXT NOT NULL,
+    instruction_reference TEXT NOT NULL,
+    administered_at TIMESTAMP NOT NULL,
+    administered_by_teacher_id INTEGER NOT NULL,
+    note TEXT,
+    recorded_by_user_id INTEGER,
+    confirmed_by_user_id INTEGER,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (administered_by_teacher_id) REFERENCES teachers(teacher_id) ON DELETE RESTRICT,
+    FOREIGN KEY (recorded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (confirmed_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_medication_administrations_child ON medication_administrations(child_id);
+
+-- The history is read-only: administrations are never changed and confirmed only once. Deleting is still
+-- possible, together with the child, and the foreign keys may still clear the references to deleted users.
+CREATE TRIGGER IF NOT EXISTS trg_medication_administrations_read_only
+BEFORE UPDATE OF child_id, medication, dose, instruction_reference, administered_at, administered_by_teacher_id, note, created_at
+ON medication_administrations
+BEGIN
+    SELECT RAISE(ABORT, 'medication administrations are read-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_medication_administrations_confirmed_once
+BEFORE UPDATE OF confirmed_at ON medication_administrations
+WHEN OLD.confirmed_at IS NOT NULL
+BEGIN
+    SELECT RAISE(ABORT, 'medication administration is already confirmed');
+END;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS gallery_photo_children;
+DROP TABLE IF EXISTS gallery_photos;
+DROP TABLE IF EXISTS photo_consents;
@@ -0,0 +1,38 @@
+-- Photo consent of the parents of a child, the reference to the signed form is encrypted.
+-- Photos showing a child without consent are withheld from the gallery and from reports.
+CREATE TABLE IF NOT EXISTS photo_consents (
+    child_id INTEGER PRIMARY KEY,
+    consent_reference TEXT NOT NULL,
+    recorded_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (recorded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+-- Photos of the gallery of a group. The caption and the image are encrypted.
+CREATE TABLE IF NOT EXISTS gallery_photos (
+    photo_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_id INTEGER NOT NULL,
+    caption TEXT,
+    content TEXT NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    uploaded_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES kita_groups(group_id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_gallery_photos_group ON gallery_photos(group_id);
+
+-- Children tagged on a photo
+CREATE TABLE IF NOT EXISTS gallery_photo_children (
+    photo_id INTEGER NOT NULL,
+    child_id INTEGER NOT NULL,
+    PRIMARY KEY (photo_id, child_id),
+    FOREIGN KEY (photo_id) REFERENCES gallery_photos(photo_id) ON DELETE CASCADE,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_gallery_photo_children_child ON gallery_photo_children(child_id);
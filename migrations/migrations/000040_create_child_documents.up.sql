@@ -0,0 +1,18 @@
+-- Scanned paperwork of a child, e.g. the Betreuungsvertrag or an ärztliches Attest. The file name and the PDF are
+-- encrypted. Documents without an expiry date stay valid.
+CREATE TABLE IF NOT EXISTS child_documents (
+    document_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    child_id INTEGER NOT NULL,
+    document_type VARCHAR(50) NOT NULL,
+    file_name TEXT NOT NULL,
+    content TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    expires_on DATE,
+    uploaded_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (uploaded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_child_documents_child ON child_documents(child_id, document_type);
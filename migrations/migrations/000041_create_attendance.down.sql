@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS child_checkin_codes;
+DROP TABLE IF EXISTS attendance_records;
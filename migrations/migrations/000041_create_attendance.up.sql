@@ -0,0 +1,30 @@
+-- Arrivals and departures of children. attendance_date is the day of recorded_at in the time zone of the facility,
+-- device_name is the door tablet a check-in code was scanned at.
+CREATE TABLE IF NOT EXISTS attendance_records (
+    record_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    child_id INTEGER NOT NULL,
+    event_type VARCHAR(20) NOT NULL,
+    recorded_at TIMESTAMP NOT NULL,
+    attendance_date DATE NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    device_name TEXT,
+    recorded_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (recorded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL,
+    CONSTRAINT chk_attendance_event_type CHECK (event_type IN ('arrival', 'departure')),
+    CONSTRAINT chk_attendance_source CHECK (source IN ('manual', 'qr_scan'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_records_child_date ON attendance_records(child_id, attendance_date);
+CREATE INDEX IF NOT EXISTS idx_attendance_records_date ON attendance_records(attendance_date);
+
+-- Check-in code of a child printed as QR code. The code is looked up by its SHA-256 hash and kept encrypted,
+-- so that the QR code can be printed again.
+CREATE TABLE IF NOT EXISTS child_checkin_codes (
+    child_id INTEGER PRIMARY KEY,
+    code_hash VARCHAR(64) UNIQUE NOT NULL,
+    code TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,16 @@
+-- Booked weekly care hours of a child (Betreuungsumfang). valid_until is the last day of the contract, NULL if it
+-- is open-ended. The periods of the contracts of a child do not overlap, which the service checks.
+CREATE TABLE IF NOT EXISTS care_contracts (
+    contract_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    child_id INTEGER NOT NULL,
+    weekly_hours INTEGER NOT NULL,
+    valid_from DATE NOT NULL,
+    valid_until DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT chk_care_contract_weekly_hours CHECK (weekly_hours IN (25, 35, 45)),
+    CONSTRAINT chk_care_contract_period CHECK (valid_until IS NULL OR valid_until >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_care_contracts_child_id ON care_contracts(child_id, valid_from);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_documentation_entries_project;
+ALTER TABLE documentation_entries DROP COLUMN project_id;
+DROP TABLE IF EXISTS project_goals;
+DROP TABLE IF EXISTS project_groups;
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,37 @@
+-- Projects plan the activities of a period, e.g. a project week on autumn, for one or more groups. Their goals are
+-- mapped to the categories the observations of the project are documented in.
+CREATE TABLE IF NOT EXISTS projects (
+    project_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_project_period CHECK (end_date >= start_date)
+);
+
+CREATE TABLE IF NOT EXISTS project_groups (
+    project_id INTEGER NOT NULL,
+    group_id INTEGER NOT NULL,
+    PRIMARY KEY (project_id, group_id),
+    FOREIGN KEY (project_id) REFERENCES projects(project_id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES kita_groups(group_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS project_goals (
+    goal_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    category_id INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(project_id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(category_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_goals_project ON project_goals(project_id);
+
+-- project_id has no foreign key, since SQLite cannot drop such a column again in the down migration. Deleting a
+-- project unlinks its entries in the store.
+ALTER TABLE documentation_entries ADD COLUMN project_id INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_documentation_entries_project ON documentation_entries(project_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS meeting_action_items;
+DROP TABLE IF EXISTS team_meeting_children;
+DROP TABLE IF EXISTS team_meeting_attendees;
+DROP TABLE IF EXISTS team_meetings;
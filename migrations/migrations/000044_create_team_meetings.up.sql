@@ -0,0 +1,49 @@
+-- Minutes of team meetings (Teambesprechungen). The minutes are encrypted, as they are about children.
+CREATE TABLE IF NOT EXISTS team_meetings (
+    meeting_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    meeting_date DATE NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    minutes TEXT NOT NULL,
+    recorded_by_user_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (recorded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS team_meeting_attendees (
+    meeting_id INTEGER NOT NULL,
+    teacher_id INTEGER NOT NULL,
+    PRIMARY KEY (meeting_id, teacher_id),
+    FOREIGN KEY (meeting_id) REFERENCES team_meetings(meeting_id) ON DELETE CASCADE,
+    FOREIGN KEY (teacher_id) REFERENCES teachers(teacher_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- Children discussed in a meeting
+CREATE TABLE IF NOT EXISTS team_meeting_children (
+    meeting_id INTEGER NOT NULL,
+    child_id INTEGER NOT NULL,
+    PRIMARY KEY (meeting_id, child_id),
+    FOREIGN KEY (meeting_id) REFERENCES team_meetings(meeting_id) ON DELETE CASCADE,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_meeting_children_child ON team_meeting_children(child_id);
+
+-- Action items agreed on in a meeting, assigned to a teacher and optionally about a child. The description is encrypted.
+CREATE TABLE IF NOT EXISTS meeting_action_items (
+    action_item_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    meeting_id INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    teacher_id INTEGER NOT NULL,
+    child_id INTEGER,
+    due_date DATE NOT NULL,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (meeting_id) REFERENCES team_meetings(meeting_id) ON DELETE CASCADE,
+    FOREIGN KEY (teacher_id) REFERENCES teachers(teacher_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_action_items_meeting ON meeting_action_items(meeting_id);
+CREATE INDEX IF NOT EXISTS idx_meeting_action_items_open ON meeting_action_items(completed_at, due_date);
@@ -0,0 +1,26 @@
+-- Handovers of children moving to another group, e.g. from the U3 into the Ü3 group, with the structured
+-- confirmation by a teacher of the receiving group. The confirmation notes are encrypted.
+CREATE TABLE IF NOT EXISTS group_handovers (
+    handover_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    child_id INTEGER NOT NULL,
+    from_group_id INTEGER,
+    to_group_id INTEGER,
+    handover_date DATE NOT NULL,
+    created_by_user_id INTEGER,
+    confirmed_by_teacher_id INTEGER,
+    confirmed_at TIMESTAMP,
+    observations_reviewed BOOLEAN NOT NULL DEFAULT 0,
+    care_specifics_reviewed BOOLEAN NOT NULL DEFAULT 0,
+    support_goals_reviewed BOOLEAN NOT NULL DEFAULT 0,
+    parents_informed BOOLEAN NOT NULL DEFAULT 0,
+    confirmation_notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (from_group_id) REFERENCES kita_groups(group_id) ON DELETE SET NULL,
+    FOREIGN KEY (to_group_id) REFERENCES kita_groups(group_id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (confirmed_by_teacher_id) REFERENCES teachers(teacher_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_handovers_child ON group_handovers(child_id);
+CREATE INDEX IF NOT EXISTS idx_group_handovers_unconfirmed ON group_handovers(confirmed_at);
@@ -0,0 +1,11 @@
+-- Anonymized monthly aggregates other facilities shared for the cross-facility benchmarks. Facilities are only
+-- known by their pseudonym, the number of children only as a band.
+CREATE TABLE IF NOT EXISTS benchmark_aggregates (
+    facility_pseudonym TEXT NOT NULL,
+    month TEXT NOT NULL,
+    children_band TEXT NOT NULL,
+    coverage REAL,
+    median_approval_hours REAL,
+    imported_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (facility_pseudonym, month)
+);
@@ -0,0 +1 @@
+ALTER TABLE kita_masterdata DROP COLUMN terminology;
@@ -0,0 +1,2 @@
+-- Terms of generated documents the facility replaced, as a JSON object from term to wording.
+ALTER TABLE kita_masterdata ADD COLUMN terminology TEXT;
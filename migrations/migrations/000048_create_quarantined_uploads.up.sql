@@ -0,0 +1,12 @@
+-- Uploaded files the virus scanner rejected, kept encrypted apart from the stored files until an admin deletes them.
+CREATE TABLE IF NOT EXISTS quarantined_uploads (
+    quarantine_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    signature TEXT NOT NULL,
+    content TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    uploaded_by_user_id INTEGER,
+    quarantined_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (uploaded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
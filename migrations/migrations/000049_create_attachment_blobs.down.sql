@@ -0,0 +1,16 @@
+UPDATE gallery_photos SET content = (SELECT content FROM attachment_blobs WHERE attachment_blobs.content_hash = gallery_photos.content_hash)
+WHERE content_hash IS NOT NULL;
+UPDATE child_documents SET content = (SELECT content FROM attachment_blobs WHERE attachment_blobs.content_hash = child_documents.content_hash)
+WHERE content_hash IS NOT NULL;
+
+DROP TRIGGER IF EXISTS trg_gallery_photos_blob_insert;
+DROP TRIGGER IF EXISTS trg_gallery_photos_blob_update;
+DROP TRIGGER IF EXISTS trg_gallery_photos_blob_delete;
+DROP TRIGGER IF EXISTS trg_child_documents_blob_insert;
+DROP TRIGGER IF EXISTS trg_child_documents_blob_update;
+DROP TRIGGER IF EXISTS trg_child_documents_blob_delete;
+DROP INDEX IF EXISTS idx_gallery_photos_content_hash;
+DROP INDEX IF EXISTS idx_child_documents_content_hash;
+ALTER TABLE gallery_photos DROP COLUMN content_hash;
+ALTER TABLE child_documents DROP COLUMN content_hash;
+DROP TABLE IF EXISTS attachment_blobs;
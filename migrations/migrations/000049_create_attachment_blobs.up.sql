@@ -0,0 +1,66 @@
+-- Content of the gallery photos and child documents, stored once per distinct file. The hash is a keyed
+-- HMAC-SHA256 of the content, so that it does not tell whether a known file is stored; the content is encrypted.
+-- The references are counted by the triggers below, also when rows are deleted by a cascade.
+CREATE TABLE IF NOT EXISTS attachment_blobs (
+    content_hash TEXT PRIMARY KEY,
+    content TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The content column is emptied once the content has been moved to a blob by the data migration. The blobs are
+-- encrypted like the content column, so the down migration can copy them back.
+ALTER TABLE gallery_photos ADD COLUMN content_hash TEXT;
+ALTER TABLE child_documents ADD COLUMN content_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_gallery_photos_content_hash ON gallery_photos(content_hash);
+CREATE INDEX IF NOT EXISTS idx_child_documents_content_hash ON child_documents(content_hash);
+
+CREATE TRIGGER IF NOT EXISTS trg_gallery_photos_blob_insert
+AFTER INSERT ON gallery_photos
+WHEN NEW.content_hash IS NOT NULL
+BEGIN
+    UPDATE attachment_blobs SET ref_count = ref_count + 1 WHERE content_hash = NEW.content_hash;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_gallery_photos_blob_update
+AFTER UPDATE OF content_hash ON gallery_photos
+WHEN NEW.content_hash IS NOT OLD.content_hash
+BEGIN
+    UPDATE attachment_blobs SET ref_count = ref_count + 1 WHERE content_hash = NEW.content_hash;
+    UPDATE attachment_blobs SET ref_count = ref_count - 1 WHERE content_hash = OLD.content_hash;
+    DELETE FROM attachment_blobs WHERE content_hash = OLD.content_hash AND ref_count <= 0;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_gallery_photos_blob_delete
+AFTER DELETE ON gallery_photos
+WHEN OLD.content_hash IS NOT NULL
+BEGIN
+    UPDATE attachment_blobs SET ref_count = ref_count - 1 WHERE content_hash = OLD.content_hash;
+    DELETE FROM attachment_blobs WHERE content_hash = OLD.content_hash AND ref_count <= 0;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_child_documents_blob_insert
+AFTER INSERT ON child_documents
+WHEN NEW.content_hash IS NOT NULL
+BEGIN
+    UPDATE attachment_blobs SET ref_count = ref_count + 1 WHERE content_hash = NEW.content_hash;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_child_documents_blob_update
+AFTER UPDATE OF content_hash ON child_documents
+WHEN NEW.content_hash IS NOT OLD.content_hash
+BEGIN
+    UPDATE attachment_blobs SET ref_count = ref_count + 1 WHERE content_hash = NEW.content_hash;
+    UPDATE attachment_blobs SET ref_count = ref_count - 1 WHERE content_hash = OLD.content_hash;
+    DELETE FROM attachment_blobs WHERE content_hash = OLD.content_hash AND ref_count <= 0;
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_child_documents_blob_delete
+AFTER DELETE ON child_documents
+WHEN OLD.content_hash IS NOT NULL
+BEGIN
+    UPDATE attachment_blobs SET ref_count = ref_count - 1 WHERE content_hash = OLD.content_hash;
+    DELETE FROM attachment_blobs WHERE content_hash = OLD.content_hash AND ref_count <= 0;
+END;
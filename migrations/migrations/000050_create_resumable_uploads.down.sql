@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_resumable_uploads_expires_at;
+DROP TABLE IF EXISTS resumable_upload_chunks;
+DROP TABLE IF EXISTS resumable_uploads;
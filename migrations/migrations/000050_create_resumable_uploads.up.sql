@@ -0,0 +1,25 @@
+-- Files uploaded in chunks, e.g. long audio recordings over a weak WiFi. The chunks are kept encrypted until the
+-- file is passed to the upload endpoint of its kind, unfinished uploads are deleted once they expired.
+CREATE TABLE IF NOT EXISTS resumable_uploads (
+    upload_id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    sha256 TEXT NOT NULL,
+    created_by_user_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS resumable_upload_chunks (
+    upload_id TEXT NOT NULL,
+    chunk_offset INTEGER NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    PRIMARY KEY (upload_id, chunk_offset),
+    FOREIGN KEY (upload_id) REFERENCES resumable_uploads(upload_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_resumable_uploads_expires_at ON resumable_uploads(expires_at);
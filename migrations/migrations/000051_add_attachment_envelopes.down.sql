@@ -0,0 +1,2 @@
+ALTER TABLE gallery_photos DROP COLUMN envelope;
+ALTER TABLE child_documents DROP COLUMN envelope;
@@ -0,0 +1,3 @@
+-- The envelope of an attachment the client encrypted before the upload, as JSON. NULL for plain attachments.
+ALTER TABLE gallery_photos ADD COLUMN envelope TEXT;
+ALTER TABLE child_documents ADD COLUMN envelope TEXT;
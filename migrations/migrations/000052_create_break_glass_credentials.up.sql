@@ -0,0 +1,16 @@
+-- Break-glass credentials give emergency admin access when the only admin is locked out. A credential is sealed by
+-- an admin and kept offline, e.g. in an envelope in the safe. It can be activated once, with a reason, and the
+-- access expires after a while. Only the bcrypt hash of the code is stored, the reason is encrypted.
+CREATE TABLE IF NOT EXISTS break_glass_credentials (
+    credential_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code_hash VARCHAR(255) NOT NULL,
+    sealed_by_user_id INTEGER,
+    sealed_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    activated_at TIMESTAMP,
+    activation_reason TEXT,
+    activated_user_id INTEGER,
+    expires_at TIMESTAMP,
+    FOREIGN KEY (sealed_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (activated_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
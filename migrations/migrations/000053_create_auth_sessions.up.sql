@@ -0,0 +1,15 @@
+-- Auth sessions are the server side of a login. The refresh token of a session issues new short-lived access
+-- tokens, which name the session and are rejected once it is revoked by a logout or a password change. Only the
+-- SHA-256 hash of the refresh token is stored, it is replaced on every refresh.
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    session_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    refresh_token_hash VARCHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    refreshed_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user ON auth_sessions(user_id);
@@ -0,0 +1 @@
+ALTER TABLE child_documents DROP COLUMN content_type;
@@ -0,0 +1,4 @@
+-- Documents can be scanned as PDFs or photographed as PNG or JPEG images. Existing documents are PDFs, or the
+-- ciphertext of documents the client encrypted.
+ALTER TABLE child_documents ADD COLUMN content_type TEXT NOT NULL DEFAULT 'application/pdf';
+UPDATE child_documents SET content_type = 'application/octet-stream' WHERE envelope IS NOT NULL;
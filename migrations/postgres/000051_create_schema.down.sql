@@ -0,0 +1,70 @@
+DROP TABLE IF EXISTS resumable_upload_chunks;
+DROP TABLE IF EXISTS resumable_uploads;
+DROP TABLE IF EXISTS attachment_blobs;
+DROP TABLE IF EXISTS quarantined_uploads;
+DROP TABLE IF EXISTS benchmark_aggregates;
+DROP TABLE IF EXISTS group_handovers;
+DROP TABLE IF EXISTS meeting_action_items;
+DROP TABLE IF EXISTS team_meeting_children;
+DROP TABLE IF EXISTS team_meeting_attendees;
+DROP TABLE IF EXISTS team_meetings;
+DROP TABLE IF EXISTS project_goals;
+DROP TABLE IF EXISTS project_groups;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS care_contracts;
+DROP TABLE IF EXISTS child_checkin_codes;
+DROP TABLE IF EXISTS attendance_records;
+DROP TABLE IF EXISTS child_documents;
+DROP TABLE IF EXISTS gallery_photo_children;
+DROP TABLE IF EXISTS gallery_photos;
+DROP TABLE IF EXISTS photo_consents;
+DROP TABLE IF EXISTS medication_administrations;
+DROP TABLE IF EXISTS incidents;
+DROP TABLE IF EXISTS daily_care_logs;
+DROP TABLE IF EXISTS child_supports;
+DROP TABLE IF EXISTS support_providers;
+DROP TABLE IF EXISTS report_share_link_accesses;
+DROP TABLE IF EXISTS report_share_links;
+DROP TABLE IF EXISTS entry_edit_locks;
+DROP TABLE IF EXISTS uptime_incidents;
+DROP TABLE IF EXISTS health_checks;
+DROP TABLE IF EXISTS email_templates;
+DROP TABLE IF EXISTS status_banner;
+DROP TABLE IF EXISTS terms_acceptances;
+DROP TABLE IF EXISTS terms_versions;
+DROP TABLE IF EXISTS quality_reports;
+DROP TABLE IF EXISTS documentation_digests;
+DROP TABLE IF EXISTS category_pins;
+DROP TABLE IF EXISTS invitations;
+DROP TABLE IF EXISTS data_migrations;
+DROP TABLE IF EXISTS import_job_rows;
+DROP TABLE IF EXISTS import_jobs;
+DROP TABLE IF EXISTS school_children;
+DROP TABLE IF EXISTS schools;
+DROP TABLE IF EXISTS generated_report_entries;
+DROP TABLE IF EXISTS generated_reports;
+DROP TABLE IF EXISTS group_children;
+DROP TABLE IF EXISTS group_assistants;
+DROP TABLE IF EXISTS kita_groups;
+DROP TABLE IF EXISTS bootstrap_runs;
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS documentation_events;
+DROP TABLE IF EXISTS validation_rules;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS approval_delegations;
+DROP TABLE IF EXISTS redaction_profiles;
+DROP TABLE IF EXISTS announcement_acknowledgements;
+DROP TABLE IF EXISTS announcements;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS devices;
+DROP TABLE IF EXISTS kita_masterdata;
+DROP TABLE IF EXISTS processes;
+DROP TABLE IF EXISTS documentation_entries;
+DROP TABLE IF EXISTS child_teacher_assignments;
+DROP TABLE IF EXISTS children;
+DROP TABLE IF EXISTS teachers;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
+DROP FUNCTION IF EXISTS count_attachment_blob_refs();
+DROP FUNCTION IF EXISTS reject_change();
+DROP FUNCTION IF EXISTS set_updated_at();
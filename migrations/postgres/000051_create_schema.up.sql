@@ -0,0 +1,1018 @@
+-- Kindergarten Behavioral Documentation System - PostgreSQL Database
+-- Baseline of the schema as of version 51 of the SQLite migrations. Later migrations are written for both
+-- engines with the same version number, so that schema_migrations means the same on both.
+
+-- =============================================================================
+-- TRIGGER FUNCTIONS
+-- =============================================================================
+
+-- Keeps updated_at current, like the AFTER UPDATE triggers of the SQLite schema.
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Rejects a change with the message given to the trigger, like RAISE(ABORT, ...) in SQLite.
+CREATE OR REPLACE FUNCTION reject_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION '%', TG_ARGV[0];
+END;
+$$ LANGUAGE plpgsql;
+
+-- Counts the references to attachment_blobs, also when rows are deleted by a cascade, and deletes blobs
+-- without references.
+CREATE OR REPLACE FUNCTION count_attachment_blob_refs() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE attachment_blobs SET ref_count = ref_count + 1 WHERE content_hash = NEW.content_hash;
+    END IF;
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE attachment_blobs SET ref_count = ref_count - 1 WHERE content_hash = OLD.content_hash;
+        DELETE FROM attachment_blobs WHERE content_hash = OLD.content_hash AND ref_count <= 0;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- =============================================================================
+-- TABLES, INDEXES AND TRIGGERS
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS users (
+    user_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    username VARCHAR(100) UNIQUE NOT NULL,
+    username_hmac VARCHAR(100) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL, -- e.g., 'teacher', 'admin'
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_username_not_empty CHECK (LENGTH(TRIM(username)) > 0),
+    CONSTRAINT chk_password_hash_not_empty CHECK (LENGTH(TRIM(password_hash)) > 0),
+    CONSTRAINT chk_role_valid CHECK (role IN ('teacher', 'admin'))
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    category_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    category_name VARCHAR(200) UNIQUE NOT NULL,
+    description TEXT,
+    form_schema TEXT,
+    archived_at TIMESTAMPTZ,
+    min_age_months INTEGER,
+    max_age_months INTEGER,
+    CONSTRAINT chk_category_name_not_empty CHECK (LENGTH(TRIM(category_name)) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS teachers (
+    teacher_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    username VARCHAR(100) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deactivated_at TIMESTAMPTZ,
+    CONSTRAINT chk_teacher_first_name_not_empty CHECK (LENGTH(TRIM(first_name)) > 0),
+    CONSTRAINT chk_teacher_last_name_not_empty CHECK (LENGTH(TRIM(last_name)) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS children (
+    child_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    birthdate VARCHAR(100) NOT NULL,
+    admission_date DATE,
+    expected_school_enrollment DATE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    archived_at TIMESTAMPTZ,
+    is_preschooler BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT chk_child_first_name_not_empty CHECK (LENGTH(TRIM(first_name)) > 0),
+    CONSTRAINT chk_child_last_name_not_empty CHECK (LENGTH(TRIM(last_name)) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS child_teacher_assignments (
+    assignment_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    teacher_id INTEGER NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    assignment_type TEXT NOT NULL DEFAULT 'primary' CHECK (assignment_type IN ('primary', 'secondary', 'intern')),
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (teacher_id) REFERENCES teachers(teacher_id) ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS documentation_entries (
+    entry_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    documenting_teacher_id INTEGER NOT NULL,
+    category_id INTEGER NOT NULL,
+    observation_description TEXT NOT NULL,
+    observation_date DATE NOT NULL,
+    approved BOOLEAN NOT NULL DEFAULT FALSE,
+    approved_by_teacher_id INTEGER,
+    approved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    structured_data TEXT,
+    locked_at TIMESTAMPTZ,
+    revision_of_entry_id INTEGER,
+    project_id INTEGER,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (documenting_teacher_id) REFERENCES teachers(teacher_id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(category_id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (approved_by_teacher_id) REFERENCES teachers(teacher_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_observation_description_not_empty CHECK (LENGTH(TRIM(observation_description)) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS processes (
+    process_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    status VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS kita_masterdata (
+    name TEXT NOT NULL,
+    street TEXT NOT NULL,
+    house_number TEXT NOT NULL,
+    postal_code TEXT NOT NULL,
+    city TEXT NOT NULL,
+    phone_number TEXT NOT NULL,
+    email TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    document_font TEXT,
+    document_accent_color TEXT,
+    logo BYTEA,
+    logo_content_type TEXT,
+    terminology TEXT);
+
+CREATE INDEX IF NOT EXISTS idx_documentation_date ON documentation_entries(observation_date);
+
+CREATE INDEX IF NOT EXISTS idx_documentation_approved ON documentation_entries(approved);
+
+CREATE INDEX IF NOT EXISTS idx_users_username ON users(username_hmac);
+
+CREATE TRIGGER trg_teachers_updated_at
+    BEFORE UPDATE ON teachers
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER trg_children_updated_at
+    BEFORE UPDATE ON children
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER trg_documentation_updated_at
+    BEFORE UPDATE ON documentation_entries
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER trg_users_updated_at
+    BEFORE UPDATE ON users
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER trg_kita_masterdata_updated_at
+    BEFORE UPDATE ON kita_masterdata
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS devices (
+    device_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    platform VARCHAR(20) NOT NULL, -- e.g., 'fcm', 'webpush'
+    token TEXT UNIQUE NOT NULL, -- FCM registration token or Web Push endpoint URL
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT chk_device_platform_valid CHECK (platform IN ('fcm', 'webpush')),
+    CONSTRAINT chk_device_token_not_empty CHECK (LENGTH(TRIM(token)) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INTEGER PRIMARY KEY,
+    push_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    notify_approvals BOOLEAN NOT NULL DEFAULT TRUE,
+    notify_rejections BOOLEAN NOT NULL DEFAULT TRUE,
+    notify_reminders BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    notify_digest BOOLEAN NOT NULL DEFAULT TRUE,
+    digest_email TEXT,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_devices_user ON devices(user_id);
+
+CREATE TRIGGER trg_devices_updated_at
+    BEFORE UPDATE ON devices
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS announcements (
+    announcement_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_announcement_title_not_empty CHECK (LENGTH(TRIM(title)) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS announcement_acknowledgements (
+    announcement_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    acknowledged_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (announcement_id, user_id),
+    FOREIGN KEY (announcement_id) REFERENCES announcements(announcement_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcement_acknowledgements_user ON announcement_acknowledgements(user_id);
+
+CREATE TRIGGER trg_announcements_updated_at
+    BEFORE UPDATE ON announcements
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE INDEX IF NOT EXISTS idx_children_archived ON children(archived_at);
+
+CREATE TABLE IF NOT EXISTS redaction_profiles (
+    redaction_profile_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    profile_name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    hide_child_last_name BOOLEAN NOT NULL DEFAULT FALSE,
+    hide_birthdate BOOLEAN NOT NULL DEFAULT FALSE,
+    hide_admission_date BOOLEAN NOT NULL DEFAULT FALSE,
+    hide_school_enrollment BOOLEAN NOT NULL DEFAULT FALSE,
+    hide_teacher_names BOOLEAN NOT NULL DEFAULT FALSE,
+    hide_kita_contact BOOLEAN NOT NULL DEFAULT FALSE,
+    hide_observation_dates BOOLEAN NOT NULL DEFAULT FALSE,
+    excluded_category_ids TEXT NOT NULL DEFAULT '[]', -- JSON array of category IDs
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    hide_support_providers BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT chk_redaction_profile_name_not_empty CHECK (LENGTH(TRIM(profile_name)) > 0)
+);
+
+CREATE TRIGGER trg_redaction_profiles_updated_at
+    BEFORE UPDATE ON redaction_profiles
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS approval_delegations (
+    delegation_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    delegator_user_id INTEGER NOT NULL,
+    delegate_user_id INTEGER NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (delegator_user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (delegate_user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT chk_delegation_distinct_users CHECK (delegator_user_id <> delegate_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_delegations_delegate ON approval_delegations(delegate_user_id);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    audit_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    action VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id INTEGER,
+    actor_user_id INTEGER,
+    on_behalf_of_user_id INTEGER,
+    delegation_id INTEGER,
+    details TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (actor_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (on_behalf_of_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (delegation_id) REFERENCES approval_delegations(delegation_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id);
+
+CREATE TABLE IF NOT EXISTS validation_rules (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    rules TEXT NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS documentation_events (
+    event_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    sequence INTEGER NOT NULL,
+    entry_id INTEGER NOT NULL, -- No foreign key, the stream outlives deleted entries
+    event_type VARCHAR(50) NOT NULL,
+    actor_user_id INTEGER,
+    on_behalf_of_user_id INTEGER,
+    delegation_id INTEGER,
+    payload TEXT, -- Encrypted JSON
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (actor_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (on_behalf_of_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (delegation_id) REFERENCES approval_delegations(delegation_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT uq_documentation_events_sequence UNIQUE (child_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_documentation_events_entry ON documentation_events(entry_id);
+
+CREATE TRIGGER trg_documentation_events_append_only
+BEFORE UPDATE OF sequence, entry_id, event_type, payload, created_at ON documentation_events
+FOR EACH ROW
+EXECUTE FUNCTION reject_change('documentation events are append-only');
+
+CREATE TABLE IF NOT EXISTS outbox (
+    message_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    channel VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL, -- Encrypted JSON
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMPTZ,
+    CONSTRAINT chk_outbox_status CHECK (status IN ('pending', 'delivered', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(status, next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS bootstrap_runs (
+    bootstrap_id INTEGER PRIMARY KEY CHECK (bootstrap_id = 1),
+    completed_by_user_id INTEGER,
+    completed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (completed_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS kita_groups (
+    group_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    group_name VARCHAR(100) UNIQUE NOT NULL,
+    capacity INTEGER NOT NULL,
+    min_age_months INTEGER,
+    max_age_months INTEGER,
+    room VARCHAR(100),
+    lead_teacher_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (lead_teacher_id) REFERENCES teachers(teacher_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_group_capacity CHECK (capacity > 0),
+    CONSTRAINT chk_group_age_band CHECK (min_age_months IS NULL OR max_age_months IS NULL OR min_age_months <= max_age_months)
+);
+
+CREATE TABLE IF NOT EXISTS group_assistants (
+    group_id INTEGER NOT NULL,
+    teacher_id INTEGER NOT NULL,
+    PRIMARY KEY (group_id, teacher_id),
+    FOREIGN KEY (group_id) REFERENCES kita_groups(group_id) ON DELETE CASCADE,
+    FOREIGN KEY (teacher_id) REFERENCES teachers(teacher_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS group_children (
+    child_id INTEGER PRIMARY KEY,
+    group_id INTEGER NOT NULL,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES kita_groups(group_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_children_group ON group_children(group_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_assignments_open_primary ON child_teacher_assignments(child_id) WHERE assignment_type = 'primary' AND end_date IS NULL;
+
+CREATE TABLE IF NOT EXISTS generated_reports (
+    report_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    generated_by_user_id INTEGER,
+    generated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    file_name TEXT,
+    parameters TEXT,
+    content TEXT,
+    content_size INTEGER NOT NULL DEFAULT 0,
+    document_id TEXT,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (generated_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS generated_report_entries (
+    report_id INTEGER NOT NULL,
+    entry_id INTEGER NOT NULL,
+    PRIMARY KEY (report_id, entry_id),
+    FOREIGN KEY (report_id) REFERENCES generated_reports(report_id) ON DELETE CASCADE,
+    FOREIGN KEY (entry_id) REFERENCES documentation_entries(entry_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_generated_reports_child ON generated_reports(child_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_generated_reports_document_id ON generated_reports(document_id);
+
+CREATE TABLE IF NOT EXISTS schools (
+    school_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    school_name VARCHAR(200) UNIQUE NOT NULL,
+    street VARCHAR(200),
+    house_number VARCHAR(20),
+    postal_code VARCHAR(20),
+    city VARCHAR(100),
+    contact_person VARCHAR(200),
+    email VARCHAR(200),
+    phone_number VARCHAR(50),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS school_children (
+    child_id INTEGER PRIMARY KEY,
+    school_id INTEGER NOT NULL,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (school_id) REFERENCES schools(school_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_school_children_school ON school_children(school_id);
+
+CREATE TABLE IF NOT EXISTS import_jobs (
+    import_job_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    file_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS import_job_rows (
+    import_job_id INTEGER NOT NULL,
+    row_number INTEGER NOT NULL,
+    data TEXT,
+    status TEXT NOT NULL,
+    error TEXT,
+    child_id INTEGER,
+    PRIMARY KEY (import_job_id, row_number),
+    FOREIGN KEY (import_job_id) REFERENCES import_jobs(import_job_id) ON DELETE CASCADE,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS data_migrations (
+    name TEXT PRIMARY KEY,
+    rows_changed INTEGER NOT NULL,
+    applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS invitations (
+    invitation_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    used_by_user_id INTEGER,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (used_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_invitation_role_valid CHECK (role IN ('teacher', 'admin'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_documentation_child_date ON documentation_entries(child_id, observation_date);
+
+CREATE INDEX IF NOT EXISTS idx_assignments_child_end ON child_teacher_assignments(child_id, end_date);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS category_pins (
+    user_id INTEGER NOT NULL,
+    category_id INTEGER NOT NULL,
+    pinned_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, category_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(category_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_documentation_teacher_category ON documentation_entries(documenting_teacher_id, category_id);
+
+CREATE TABLE IF NOT EXISTS documentation_digests (
+    user_id INTEGER NOT NULL,
+    week_start DATE NOT NULL,
+    sent_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, week_start),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS quality_reports (
+    month TEXT PRIMARY KEY,
+    document_id TEXT NOT NULL,
+    generated_at TIMESTAMPTZ NOT NULL,
+    content TEXT NOT NULL,
+    content_size INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log(action, created_at);
+
+CREATE TABLE IF NOT EXISTS terms_versions (
+    version INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    terms_of_use TEXT NOT NULL,
+    privacy_policy TEXT NOT NULL,
+    change_summary TEXT NOT NULL DEFAULT '',
+    published_by_user_id INTEGER,
+    published_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (published_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_terms_of_use_not_empty CHECK (LENGTH(TRIM(terms_of_use)) > 0),
+    CONSTRAINT chk_privacy_policy_not_empty CHECK (LENGTH(TRIM(privacy_policy)) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS terms_acceptances (
+    user_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    accepted_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, version),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (version) REFERENCES terms_versions(version) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS status_banner (
+    banner_id INTEGER PRIMARY KEY CHECK (banner_id = 1),
+    message TEXT NOT NULL,
+    level TEXT NOT NULL CHECK (level IN ('info', 'warning', 'maintenance')),
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    updated_by_user_id INTEGER,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (updated_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS email_templates (
+    template_key TEXT NOT NULL CHECK (template_key IN ('reminder', 'rejection', 'report_delivery')),
+    version INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    change_summary TEXT NOT NULL DEFAULT '',
+    published_by_user_id INTEGER,
+    published_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (template_key, version),
+    FOREIGN KEY (published_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_subject_not_empty CHECK (LENGTH(TRIM(subject)) > 0),
+    CONSTRAINT chk_body_not_empty CHECK (LENGTH(TRIM(body)) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS health_checks (
+    check_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    checked_at TIMESTAMPTZ NOT NULL,
+    healthy BOOLEAN NOT NULL,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_health_checks_checked_at ON health_checks(checked_at);
+
+CREATE TABLE IF NOT EXISTS uptime_incidents (
+    incident_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    note TEXT NOT NULL,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    CONSTRAINT chk_note_not_empty CHECK (LENGTH(TRIM(note)) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS entry_edit_locks (
+    entry_id INTEGER PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    acquired_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (entry_id) REFERENCES documentation_entries(entry_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS report_share_links (
+    share_link_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    report_id INTEGER NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    access_code_hash VARCHAR(255) NOT NULL,
+    recipient TEXT NOT NULL,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    accesses INTEGER NOT NULL DEFAULT 0,
+    last_accessed_at TIMESTAMPTZ,
+    FOREIGN KEY (report_id) REFERENCES generated_reports(report_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_share_links_report ON report_share_links(report_id);
+
+CREATE TABLE IF NOT EXISTS report_share_link_accesses (
+    access_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    share_link_id INTEGER NOT NULL,
+    accessed_at TIMESTAMPTZ NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    FOREIGN KEY (share_link_id) REFERENCES report_share_links(share_link_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT chk_share_link_access_outcome_valid CHECK (outcome IN ('granted', 'wrong_code', 'expired', 'revoked'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_share_link_accesses_link ON report_share_link_accesses(share_link_id);
+
+CREATE TABLE IF NOT EXISTS support_providers (
+    provider_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    provider_name VARCHAR(200) UNIQUE NOT NULL,
+    provider_type VARCHAR(30) NOT NULL CHECK (provider_type IN ('speech_therapy', 'occupational_therapy', 'physiotherapy', 'early_intervention', 'other')),
+    contact_person VARCHAR(200),
+    email VARCHAR(200),
+    phone_number VARCHAR(50),
+    address VARCHAR(300),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS child_supports (
+    support_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    provider_id INTEGER NOT NULL,
+    consent_reference TEXT,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    appointment_notes TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (provider_id) REFERENCES support_providers(provider_id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_child_supports_child ON child_supports(child_id);
+
+CREATE INDEX IF NOT EXISTS idx_child_supports_provider ON child_supports(provider_id);
+
+CREATE TABLE IF NOT EXISTS daily_care_logs (
+    care_log_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    care_date DATE NOT NULL,
+    breakfast VARCHAR(10) CHECK (breakfast IN ('none', 'little', 'half', 'most', 'all')),
+    lunch VARCHAR(10) CHECK (lunch IN ('none', 'little', 'half', 'most', 'all')),
+    snack VARCHAR(10) CHECK (snack IN ('none', 'little', 'half', 'most', 'all')),
+    nap_minutes INTEGER CHECK (nap_minutes >= 0),
+    diaper_changes INTEGER CHECK (diaper_changes >= 0),
+    toilet_visits INTEGER CHECK (toilet_visits >= 0),
+    note TEXT,
+    hidden_from_parents BOOLEAN NOT NULL DEFAULT FALSE,
+    recorded_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (child_id, care_date),
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (recorded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_daily_care_logs_date ON daily_care_logs(care_date);
+
+CREATE TABLE IF NOT EXISTS incidents (
+    incident_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    location VARCHAR(200),
+    description TEXT,
+    injuries TEXT,
+    first_aid TEXT,
+    witnesses TEXT,
+    medical_treatment BOOLEAN NOT NULL DEFAULT FALSE,
+    physician TEXT,
+    parent_informed_at TIMESTAMPTZ,
+    reported_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (reported_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_incidents_child ON incidents(child_id);
+
+CREATE TABLE IF NOT EXISTS medication_administrations (
+    administration_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    medication TEXT NOT NULL,
+    dose TEXT NOT NULL,
+    instruction_reference TEXT NOT NULL,
+    administered_at TIMESTAMPTZ NOT NULL,
+    administered_by_teacher_id INTEGER NOT NULL,
+    note TEXT,
+    recorded_by_user_id INTEGER,
+    confirmed_by_user_id INTEGER,
+    confirmed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (administered_by_teacher_id) REFERENCES teachers(teacher_id) ON DELETE RESTRICT,
+    FOREIGN KEY (recorded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (confirmed_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_medication_administrations_child ON medication_administrations(child_id);
+
+CREATE TRIGGER trg_medication_administrations_read_only
+BEFORE UPDATE OF child_id, medication, dose, instruction_reference, administered_at, administered_by_teacher_id, note, created_at
+ON medication_administrations
+FOR EACH ROW
+EXECUTE FUNCTION reject_change('medication administrations are read-only');
+
+CREATE TRIGGER trg_medication_administrations_confirmed_once
+BEFORE UPDATE OF confirmed_at ON medication_administrations
+FOR EACH ROW
+WHEN (OLD.confirmed_at IS NOT NULL)
+EXECUTE FUNCTION reject_change('medication administration is already confirmed');
+
+CREATE TABLE IF NOT EXISTS photo_consents (
+    child_id INTEGER PRIMARY KEY,
+    consent_reference TEXT NOT NULL,
+    recorded_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (recorded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS gallery_photos (
+    photo_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    group_id INTEGER NOT NULL,
+    caption TEXT,
+    content TEXT NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    uploaded_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    content_hash TEXT,
+    envelope TEXT,
+    FOREIGN KEY (group_id) REFERENCES kita_groups(group_id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_gallery_photos_group ON gallery_photos(group_id);
+
+CREATE TABLE IF NOT EXISTS gallery_photo_children (
+    photo_id INTEGER NOT NULL,
+    child_id INTEGER NOT NULL,
+    PRIMARY KEY (photo_id, child_id),
+    FOREIGN KEY (photo_id) REFERENCES gallery_photos(photo_id) ON DELETE CASCADE,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_gallery_photo_children_child ON gallery_photo_children(child_id);
+
+CREATE TABLE IF NOT EXISTS child_documents (
+    document_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    document_type VARCHAR(50) NOT NULL,
+    file_name TEXT NOT NULL,
+    content TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    expires_on DATE,
+    uploaded_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    content_hash TEXT,
+    envelope TEXT,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (uploaded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_child_documents_child ON child_documents(child_id, document_type);
+
+CREATE TABLE IF NOT EXISTS attendance_records (
+    record_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    event_type VARCHAR(20) NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    attendance_date DATE NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    device_name TEXT,
+    recorded_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (recorded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL,
+    CONSTRAINT chk_attendance_event_type CHECK (event_type IN ('arrival', 'departure')),
+    CONSTRAINT chk_attendance_source CHECK (source IN ('manual', 'qr_scan'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_records_child_date ON attendance_records(child_id, attendance_date);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_records_date ON attendance_records(attendance_date);
+
+CREATE TABLE IF NOT EXISTS child_checkin_codes (
+    child_id INTEGER PRIMARY KEY,
+    code_hash VARCHAR(64) UNIQUE NOT NULL,
+    code TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS care_contracts (
+    contract_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    weekly_hours INTEGER NOT NULL,
+    valid_from DATE NOT NULL,
+    valid_until DATE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT chk_care_contract_weekly_hours CHECK (weekly_hours IN (25, 35, 45)),
+    CONSTRAINT chk_care_contract_period CHECK (valid_until IS NULL OR valid_until >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_care_contracts_child_id ON care_contracts(child_id, valid_from);
+
+CREATE TABLE IF NOT EXISTS projects (
+    project_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_project_period CHECK (end_date >= start_date)
+);
+
+CREATE TABLE IF NOT EXISTS project_groups (
+    project_id INTEGER NOT NULL,
+    group_id INTEGER NOT NULL,
+    PRIMARY KEY (project_id, group_id),
+    FOREIGN KEY (project_id) REFERENCES projects(project_id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES kita_groups(group_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS project_goals (
+    goal_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    project_id INTEGER NOT NULL,
+    category_id INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(project_id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(category_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_goals_project ON project_goals(project_id);
+
+CREATE INDEX IF NOT EXISTS idx_documentation_entries_project ON documentation_entries(project_id);
+
+CREATE TABLE IF NOT EXISTS team_meetings (
+    meeting_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    meeting_date DATE NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    minutes TEXT NOT NULL,
+    recorded_by_user_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (recorded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS team_meeting_attendees (
+    meeting_id INTEGER NOT NULL,
+    teacher_id INTEGER NOT NULL,
+    PRIMARY KEY (meeting_id, teacher_id),
+    FOREIGN KEY (meeting_id) REFERENCES team_meetings(meeting_id) ON DELETE CASCADE,
+    FOREIGN KEY (teacher_id) REFERENCES teachers(teacher_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS team_meeting_children (
+    meeting_id INTEGER NOT NULL,
+    child_id INTEGER NOT NULL,
+    PRIMARY KEY (meeting_id, child_id),
+    FOREIGN KEY (meeting_id) REFERENCES team_meetings(meeting_id) ON DELETE CASCADE,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_meeting_children_child ON team_meeting_children(child_id);
+
+CREATE TABLE IF NOT EXISTS meeting_action_items (
+    action_item_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    meeting_id INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    teacher_id INTEGER NOT NULL,
+    child_id INTEGER,
+    due_date DATE NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (meeting_id) REFERENCES team_meetings(meeting_id) ON DELETE CASCADE,
+    FOREIGN KEY (teacher_id) REFERENCES teachers(teacher_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_action_items_meeting ON meeting_action_items(meeting_id);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_action_items_open ON meeting_action_items(completed_at, due_date);
+
+CREATE TABLE IF NOT EXISTS group_handovers (
+    handover_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    child_id INTEGER NOT NULL,
+    from_group_id INTEGER,
+    to_group_id INTEGER,
+    handover_date DATE NOT NULL,
+    created_by_user_id INTEGER,
+    confirmed_by_teacher_id INTEGER,
+    confirmed_at TIMESTAMPTZ,
+    observations_reviewed BOOLEAN NOT NULL DEFAULT FALSE,
+    care_specifics_reviewed BOOLEAN NOT NULL DEFAULT FALSE,
+    support_goals_reviewed BOOLEAN NOT NULL DEFAULT FALSE,
+    parents_informed BOOLEAN NOT NULL DEFAULT FALSE,
+    confirmation_notes TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (child_id) REFERENCES children(child_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (from_group_id) REFERENCES kita_groups(group_id) ON DELETE SET NULL,
+    FOREIGN KEY (to_group_id) REFERENCES kita_groups(group_id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (confirmed_by_teacher_id) REFERENCES teachers(teacher_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_handovers_child ON group_handovers(child_id);
+
+CREATE INDEX IF NOT EXISTS idx_group_handovers_unconfirmed ON group_handovers(confirmed_at);
+
+CREATE TABLE IF NOT EXISTS benchmark_aggregates (
+    facility_pseudonym TEXT NOT NULL,
+    month TEXT NOT NULL,
+    children_band TEXT NOT NULL,
+    coverage DOUBLE PRECISION,
+    median_approval_hours DOUBLE PRECISION,
+    imported_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (facility_pseudonym, month)
+);
+
+CREATE TABLE IF NOT EXISTS quarantined_uploads (
+    quarantine_id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    kind TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    signature TEXT NOT NULL,
+    content TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    uploaded_by_user_id INTEGER,
+    quarantined_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (uploaded_by_user_id) REFERENCES users(user_id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS attachment_blobs (
+    content_hash TEXT PRIMARY KEY,
+    content TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_gallery_photos_content_hash ON gallery_photos(content_hash);
+
+CREATE INDEX IF NOT EXISTS idx_child_documents_content_hash ON child_documents(content_hash);
+
+CREATE TRIGGER trg_gallery_photos_blob_insert
+AFTER INSERT ON gallery_photos
+FOR EACH ROW
+WHEN (NEW.content_hash IS NOT NULL)
+EXECUTE FUNCTION count_attachment_blob_refs();
+
+CREATE TRIGGER trg_gallery_photos_blob_update
+AFTER UPDATE OF content_hash ON gallery_photos
+FOR EACH ROW
+WHEN (NEW.content_hash IS DISTINCT FROM OLD.content_hash)
+EXECUTE FUNCTION count_attachment_blob_refs();
+
+CREATE TRIGGER trg_gallery_photos_blob_delete
+AFTER DELETE ON gallery_photos
+FOR EACH ROW
+WHEN (OLD.content_hash IS NOT NULL)
+EXECUTE FUNCTION count_attachment_blob_refs();
+
+CREATE TRIGGER trg_child_documents_blob_insert
+AFTER INSERT ON child_documents
+FOR EACH ROW
+WHEN (NEW.content_hash IS NOT NULL)
+EXECUTE FUNCTION count_attachment_blob_refs();
+
+CREATE TRIGGER trg_child_documents_blob_update
+AFTER UPDATE OF content_hash ON child_documents
+FOR EACH ROW
+WHEN (NEW.content_hash IS DISTINCT FROM OLD.content_hash)
+EXECUTE FUNCTION count_attachment_blob_refs();
+
+CREATE TRIGGER trg_child_documents_blob_delete
+AFTER DELETE ON child_documents
+FOR EACH ROW
+WHEN (OLD.content_hash IS NOT NULL)
+EXECUTE FUNCTION count_attachment_blob_refs();
+
+CREATE TABLE IF NOT EXISTS resumable_uploads (
+    upload_id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    sha256 TEXT NOT NULL,
+    created_by_user_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS resumable_upload_chunks (
+    upload_id TEXT NOT NULL,
+    chunk_offset INTEGER NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    PRIMARY KEY (upload_id, chunk_offset),
+    FOREIGN KEY (upload_id) REFERENCES resumable_uploads(upload_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_resumable_uploads_expires_at ON resumable_uploads(expires_at);
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Announcement represents a piece of team-wide information that teachers have to acknowledge.
+type Announcement struct {
+	ID              int       `json:"id"`
+	Title           string    `json:"title" validate:"required,min=1,max=255"`
+	Body            string    `json:"body" validate:"required"`
+	CreatedByUserID *int      `json:"created_by_user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ValidateAnnouncement validates the Announcement struct.
+func ValidateAnnouncement(announcement Announcement) error {
+	validate := NewValidator()
+	return validate.Struct(announcement)
+}
+
+// AnnouncementAcknowledgement records that a user has read an announcement.
+type AnnouncementAcknowledgement struct {
+	AnnouncementID int       `json:"announcement_id"`
+	UserID         int       `json:"user_id"`
+	AcknowledgedAt time.Time `json:"acknowledged_at"`
+}
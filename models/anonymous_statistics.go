@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Age bands of the anonymous statistics, children are only reported by band instead of their birthdate.
+const (
+	AgeBandUnder3 = "0-2"
+	AgeBand3To4   = "3-4"
+	AgeBand5To6   = "5-6"
+	AgeBandOver6  = "7+"
+)
+
+// AnonymousStatistics are documentation statistics without personal data, for sharing with the Träger's
+// quality management. Children are identified by a pseudonym that stays the same across exports.
+type AnonymousStatistics struct {
+	GeneratedAt time.Time                  `json:"generated_at"`
+	PeriodStart time.Time                  `json:"period_start"`
+	PeriodEnd   time.Time                  `json:"period_end"`
+	Children    []AnonymousChildStatistics `json:"children"`
+}
+
+// AnonymousChildStatistics summarises the documentation of one child within the period.
+type AnonymousChildStatistics struct {
+	Pseudonym          string                   `json:"pseudonym"`
+	AgeBand            string                   `json:"age_band"`
+	EntryCount         int                      `json:"entry_count"`
+	ApprovedEntryCount int                      `json:"approved_entry_count"`
+	CategoriesCovered  int                      `json:"categories_covered"`
+	CompletenessScore  int                      `json:"completeness_score"`
+	Categories         []AnonymousCategoryCount `json:"categories"`
+}
+
+// AnonymousCategoryCount is the number of observations of a child in one category.
+type AnonymousCategoryCount struct {
+	CategoryName string `json:"category_name"`
+	EntryCount   int    `json:"entry_count"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// ApprovalDelegation lets the delegator's approval rights be exercised by the delegate
+// between StartDate and EndDate (both inclusive).
+type ApprovalDelegation struct {
+	ID              int       `json:"id"`
+	DelegatorUserID int       `json:"delegator_user_id" validate:"required,gt=0"`
+	DelegateUserID  int       `json:"delegate_user_id" validate:"required,gt=0,nefield=DelegatorUserID"`
+	StartDate       time.Time `json:"start_date" validate:"required"`
+	EndDate         time.Time `json:"end_date" validate:"required,gtefield=StartDate"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ValidateApprovalDelegation validates the ApprovalDelegation struct.
+func ValidateApprovalDelegation(delegation ApprovalDelegation) error {
+	validate := NewValidator()
+	return validate.Struct(delegation)
+}
+
+// IsActiveAt reports whether the delegation covers the calendar day of t.
+func (delegation ApprovalDelegation) IsActiveAt(t time.Time) bool {
+	day := t.Format("2006-01-02")
+	return day >= delegation.StartDate.Format("2006-01-02") && day <= delegation.EndDate.Format("2006-01-02")
+}
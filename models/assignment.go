@@ -1,24 +1,34 @@
 package models
 
-import (
-	"time"
+import "time"
 
-	"github.com/go-playground/validator/v10"
+// Assignment types. A child has exactly one open primary assignment (Bezugserzieher/-in),
+// further teachers are assigned as secondary (Zweitkraft) or intern (Praktikant/-in).
+const (
+	AssignmentTypePrimary   = "primary"
+	AssignmentTypeSecondary = "secondary"
+	AssignmentTypeIntern    = "intern"
 )
 
 // Assignment represents an assignment of a child to a teacher.
 type Assignment struct {
-	ID        int        `json:"id"`
-	ChildID   int        `json:"child_id" validate:"required"`
-	TeacherID int        `json:"teacher_id" validate:"required"`
-	StartDate time.Time  `json:"start_date" validate:"required"`
-	EndDate   *time.Time `json:"end_date" validate:"omitempty,gtfield=StartDate"` // Optional, but if present, must be after StartDate
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+	ID             int        `json:"id"`
+	ChildID        int        `json:"child_id" validate:"required"`
+	TeacherID      int        `json:"teacher_id" validate:"required"`
+	AssignmentType string     `json:"assignment_type" validate:"omitempty,oneof=primary secondary intern"` // Defaults to primary for the first open assignment of a child, secondary otherwise
+	StartDate      time.Time  `json:"start_date" validate:"required"`
+	EndDate        *time.Time `json:"end_date" validate:"omitempty,gtfield=StartDate"` // Optional, but if present, must be after StartDate
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// IsOpen reports whether the assignment has not ended.
+func (a *Assignment) IsOpen() bool {
+	return a.EndDate == nil
 }
 
 // ValidateAssignment validates the Assignment struct.
 func ValidateAssignment(assignment Assignment) error {
-	validate := validator.New()
+	validate := NewValidator()
 	return validate.Struct(assignment)
 }
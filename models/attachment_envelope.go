@@ -0,0 +1,24 @@
+package models
+
+// Algorithms clients encrypt attachments with.
+const (
+	EnvelopeAlgorithmAES256GCM         = "AES-256-GCM"
+	EnvelopeAlgorithmXChaCha20Poly1305 = "XChaCha20-Poly1305"
+)
+
+// AttachmentEnvelope describes a gallery photo or child document the client encrypted before the upload, for
+// Träger that require end-to-end encryption of media. The server stores and serves the ciphertext as it is; the
+// envelope tells other clients which key of the Träger's key management decrypts it.
+type AttachmentEnvelope struct {
+	Algorithm string `json:"algorithm" validate:"required,oneof=AES-256-GCM XChaCha20-Poly1305"`
+	KeyID     string `json:"key_id" validate:"required,max=200"` // Reference to the key in the key management of the Träger
+	Nonce     string `json:"nonce" validate:"required,base64,max=100"`
+	// WrappedKey is the content key encrypted with the key of KeyID, if the client uses a key per attachment.
+	WrappedKey string `json:"wrapped_key,omitempty" validate:"omitempty,base64,max=2000"`
+}
+
+// ValidateAttachmentEnvelope validates the AttachmentEnvelope struct.
+func ValidateAttachmentEnvelope(envelope AttachmentEnvelope) error {
+	validate := NewValidator()
+	return validate.Struct(envelope)
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Attendance event types.
+const (
+	AttendanceArrival   = "arrival"
+	AttendanceDeparture = "departure"
+)
+
+// Sources of attendance records.
+const (
+	AttendanceSourceManual = "manual"
+	AttendanceSourceQRScan = "qr_scan"
+)
+
+// CheckInCodePrefix is put in front of the check-in code in the QR code, so that the door tablet can tell it from
+// other QR codes. Scanned codes are accepted with and without the prefix.
+const CheckInCodePrefix = "kitadoc-checkin:"
+
+// AttendanceRecord is the arrival or the departure of a child, recorded by a teacher or by scanning the check-in
+// QR code of the child at the door tablet.
+type AttendanceRecord struct {
+	ID             int       `json:"id"`
+	ChildID        int       `json:"child_id"` // Set from the route or from the scanned code
+	EventType      string    `json:"event_type" validate:"required,oneof=arrival departure"`
+	RecordedAt     time.Time `json:"recorded_at" validate:"required"`
+	AttendanceDate Date      `json:"attendance_date"` // Read only, the day of RecordedAt in the facility
+	Source         string    `json:"source"`          // Read only
+	// DeviceName is the door tablet the code was scanned at, nil for manual records.
+	DeviceName       *string   `json:"device_name" validate:"omitempty,max=100"`
+	RecordedByUserID *int      `json:"recorded_by_user_id"` // Read only
+	CreatedAt        time.Time `json:"created_at"`
+	// ChildFirstName is only set in the response to a check-in, so that the tablet can greet the child.
+	ChildFirstName string `json:"child_first_name,omitempty"`
+}
+
+// CheckIn is a scan of a check-in QR code at a door tablet. Without an event type, a scan records an arrival, or
+// the departure if the child has arrived and not left yet on that day.
+type CheckIn struct {
+	Code       string  `json:"code" validate:"required,max=200"`
+	DeviceName string  `json:"device_name" validate:"required,max=100"`
+	EventType  *string `json:"event_type" validate:"omitempty,oneof=arrival departure"`
+	// ScannedAt is set by tablets that queue scans while offline, the time of the request otherwise.
+	ScannedAt *time.Time `json:"scanned_at"`
+}
+
+// ValidateAttendanceRecord validates the AttendanceRecord struct.
+func ValidateAttendanceRecord(record AttendanceRecord) error {
+	validate := NewValidator()
+	return validate.Struct(record)
+}
+
+// ValidateCheckIn validates the CheckIn struct.
+func ValidateCheckIn(checkIn CheckIn) error {
+	validate := NewValidator()
+	return validate.Struct(checkIn)
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Audit log actions.
+const (
+	AuditActionApproveDocumentationEntry = "documentation_entry.approve"
+	AuditActionUnlockDocumentationEntry  = "documentation_entry.unlock"
+	AuditActionDownloadGeneratedReport   = "generated_report.download"
+	AuditActionGenerateChildReport       = "generated_report.generate"
+	AuditActionExportGeneratedReports    = "generated_report.export"
+	AuditActionExportFees                = "fee_export.export"
+	AuditActionExportAnonymousStatistics = "anonymous_statistics.export"
+	AuditActionCreateShareLink           = "report_share_link.create"
+	AuditActionRevokeShareLink           = "report_share_link.revoke"
+	AuditActionSendReminder              = "teacher.remind"
+	AuditActionPublishTerms              = "terms.publish"
+	AuditActionAcceptTerms               = "terms.accept"
+	AuditActionPublishEmailTemplate      = "email_template.publish"
+	AuditActionQuarantineUpload          = "upload.quarantine"
+	AuditActionDownloadQuarantinedUpload = "upload.quarantine_download"
+	AuditActionDeleteQuarantinedUpload   = "upload.quarantine_delete"
+	AuditActionDenyNetwork               = "network.deny"
+	AuditActionSealBreakGlass            = "break_glass.seal"
+	AuditActionRevokeBreakGlass          = "break_glass.revoke"
+	AuditActionActivateBreakGlass        = "break_glass.activate"
+	AuditActionFailBreakGlass            = "break_glass.activation_failed"
+)
+
+// AuditLogEntry records an action taken by a user, optionally on behalf of another user.
+type AuditLogEntry struct {
+	ID               int       `json:"id"`
+	Action           string    `json:"action"`
+	EntityType       string    `json:"entity_type"`
+	EntityID         *int      `json:"entity_id"`
+	ActorUserID      *int      `json:"actor_user_id"`
+	OnBehalfOfUserID *int      `json:"on_behalf_of_user_id"`
+	DelegationID     *int      `json:"delegation_id"`
+	Details          string    `json:"details"`
+	CreatedAt        time.Time `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// AuthSession is the server side of a login. Its refresh token issues new short-lived access tokens until the
+// session expires or is revoked by a logout or a password change.
+type AuthSession struct {
+	ID     int `json:"id"`
+	UserID int `json:"user_id"`
+	// RefreshTokenHash is the SHA-256 hash of the current refresh token, the token itself is only returned to the
+	// client.
+	RefreshTokenHash string     `json:"-"`
+	CreatedAt        time.Time  `json:"created_at"`
+	RefreshedAt      *time.Time `json:"refreshed_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+}
+
+// IsActive reports whether the refresh token of the session can still be used.
+func (session *AuthSession) IsActive(now time.Time) bool {
+	return session.RevokedAt == nil && now.Before(session.ExpiresAt)
+}
+
+// AuthTokens are returned by a login and a refresh. The access token is sent as bearer token, the refresh token
+// is exchanged for new tokens at /api/v1/auth/refresh before the access token expires.
+type AuthTokens struct {
+	AccessToken           string    `json:"token"`
+	ExpiresAt             time.Time `json:"expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Bands of the number of children of a facility in a benchmark aggregate.
+const (
+	ChildrenBandSmall  = "0-24"
+	ChildrenBandMedium = "25-49"
+	ChildrenBandLarge  = "50-99"
+	ChildrenBandHuge   = "100+"
+)
+
+// BenchmarkAggregate are the anonymized documentation figures of a facility in a month, shared with other
+// facilities for the cross-facility benchmarks. The facility is only known by its pseudonym, the figures are
+// rounded and the number of children is a band.
+type BenchmarkAggregate struct {
+	Facility     string `json:"facility" validate:"required,max=64"` // Pseudonym of the facility
+	Month        string `json:"month" validate:"required,datetime=2006-01"`
+	ChildrenBand string `json:"children_band" validate:"required,oneof=0-24 25-49 50-99 100+"`
+	// Coverage is the share of documented children in percent, nil if the facility has too few children.
+	Coverage *float64 `json:"coverage" validate:"omitempty,min=0,max=100"`
+	// MedianApprovalHours is the median time from submitting an entry to its approval, nil without approvals.
+	MedianApprovalHours *float64  `json:"median_approval_hours" validate:"omitempty,min=0"`
+	ImportedAt          time.Time `json:"imported_at"` // Read only, zero for the own aggregate
+}
+
+// BenchmarkComparison compares the figures of the facility in a month to the aggregates of other facilities.
+type BenchmarkComparison struct {
+	Month string             `json:"month"`
+	Own   BenchmarkAggregate `json:"own"`
+	Peers int                `json:"peers"` // Facilities with an aggregate of the month
+	// Coverage and ApprovalHours are nil while fewer facilities than configured reported the figure, so that
+	// no single facility can be singled out.
+	Coverage      *BenchmarkDistribution `json:"coverage"`
+	ApprovalHours *BenchmarkDistribution `json:"approval_hours"`
+}
+
+// BenchmarkDistribution is the distribution of a figure over the other facilities.
+type BenchmarkDistribution struct {
+	Peers  int     `json:"peers"` // Facilities that reported the figure
+	P25    float64 `json:"p25"`
+	Median float64 `json:"median"`
+	P75    float64 `json:"p75"`
+	// Rank is the share of facilities with a lower figure in percent, nil if the own figure is missing.
+	Rank *float64 `json:"rank"`
+}
+
+// ValidateBenchmarkAggregate validates the BenchmarkAggregate struct.
+func ValidateBenchmarkAggregate(aggregate BenchmarkAggregate) error {
+	validate := NewValidator()
+	return validate.Struct(aggregate)
+}
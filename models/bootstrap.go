@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// DefaultCategories returns the educational areas (Bildungsbereiche) of the NRW Bildungsgrundsätze,
+// completed by the areas for settling in and inclusion. New instances are bootstrapped with them.
+func DefaultCategories() []Category {
+	return []Category{
+		{Name: "Bewegung", Description: StringPtr("Beobachtungen zur Bewegungsfreude, Koordination, Grundbewegungen (Robben, Klettern, Springen, Balancieren etc.) und Selbstständigkeit bei motorischen Aufgaben.")},
+		{Name: "Körper, Gesundheit, Ernährung", Description: StringPtr("Körperwahrnehmung, Körperschema, Spannungsverhalten, Essverhalten, Gesundheitsfragen, U-Untersuchungen und Impfstatus.")},
+		{Name: "Sprache und Kommunikation", Description: StringPtr("Sprachgebrauch, Lautbildung, Wortschatz, Erzählen, Hörverständnis, Zuhören, Grammatik und frühe Schriftsprache.")},
+		{Name: "Soziale und (inter-) kulturelle Bildung", Description: StringPtr("Sozialverhalten in Gruppen und gegenüber Erwachsenen, Trennung, Spielverhalten, Kooperation, Konfliktlösung, Empathie und interkulturelle Anpassung.")},
+		{Name: "Musisch- ästhetische Bildung", Description: StringPtr("Kreativität beim Gestalten, Umgang mit Farben und Materialien, Musizieren, Rhythmusgefühl und Gedächtnis für Lieder/Reime.")},
+		{Name: "Religion und Ethik", Description: StringPtr("Interesse an religiösen Ritualen und Festen, Kenntnis biblischer Geschichten, Gerechtigkeitssinn, Solidarität und philosophische Fragen zu Leben und Tod.")},
+		{Name: "Mathematische Bildung", Description: StringPtr("Zahlenverständnis, Mengenverständnis, Puzzeln, räumliches Vorstellungsvermögen, Vergleichen (mehr/weniger) und erste mathematische Zusammenhänge.")},
+		{Name: "Naturwissenschaftlich- technische Bildung", Description: StringPtr("Neugier für Natur und Technik, Experimentieren mit Materialien, Beobachtung von Prozessen und Teilen von Wissen.")},
+		{Name: "Ökologische Bildung", Description: StringPtr("Umweltbewusstsein, Kreisläufe der Natur, Trennen/Recycle von Rohstoffen und nachhaltiges Verhalten.")},
+		{Name: "Medien", Description: StringPtr("Umgang mit Bilderbüchern und digitalen Medien, Zuhören bei Geschichten, Wiedergeben/Weitererzählen und kreativer Medieneinsatz.")},
+		{Name: "Eingewöhnung", Description: StringPtr("Trennungs- und Bindungsfähigkeit, Erkundungsverhalten in der Kita, Nähe-Distanz-Regulation und Wohlbefinden.")},
+		{Name: "Inklusion", Description: StringPtr("Orientierung an Teil- und Förderplan, individuelle Förderung und inklusive Unterstützung.")},
+	}
+}
+
+// DefaultGroups returns a group for each group form (Gruppenform) of the KiBiz NRW with its usual capacity and
+// age band. New instances are bootstrapped with them, the facility renames or deletes them as needed.
+func DefaultGroups() []Group {
+	return []Group{
+		{Name: "Gruppenform I", Capacity: 20, MinAgeMonths: IntPtr(24), MaxAgeMonths: IntPtr(72)},
+		{Name: "Gruppenform II", Capacity: 10, MinAgeMonths: IntPtr(0), MaxAgeMonths: IntPtr(36)},
+		{Name: "Gruppenform III", Capacity: 25, MinAgeMonths: IntPtr(36), MaxAgeMonths: IntPtr(72)},
+	}
+}
+
+// BootstrapResult describes what a bootstrap installed.
+// Default categories and groups whose name is already taken are left untouched and listed as skipped.
+type BootstrapResult struct {
+	CompletedAt       time.Time  `json:"completed_at"`
+	CreatedCategories []Category `json:"created_categories"`
+	SkippedCategories []string   `json:"skipped_categories"`
+	CreatedGroups     []Group    `json:"created_groups"`
+	SkippedGroups     []string   `json:"skipped_groups"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// BreakGlassUsername is the account emergency access is granted to. It cannot log in with a password, only by
+// activating a break-glass credential.
+const BreakGlassUsername = "notfallzugang"
+
+// BreakGlassCredential is a sealed emergency credential for when the only admin cannot log in anymore. An admin
+// seals it and keeps the code offline, e.g. in an envelope in the safe of the facility. It can be activated once,
+// with a reason, and grants admin access until it expires.
+type BreakGlassCredential struct {
+	ID             int        `json:"id"`
+	SealedByUserID *int       `json:"sealed_by_user_id"`
+	SealedAt       time.Time  `json:"sealed_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	ActivatedAt    *time.Time `json:"activated_at"`
+	// ActivationReason explains why the credential was activated, it is required for the activation.
+	ActivationReason string     `json:"activation_reason,omitempty" pii:"true"`
+	ActivatedUserID  *int       `json:"activated_user_id"`
+	ExpiresAt        *time.Time `json:"expires_at"` // End of the emergency access, set on activation
+	// CodeHash is the bcrypt hash of the code, the code itself is only returned once when sealing.
+	CodeHash string `json:"-"`
+}
+
+// IsSealed reports whether the credential can still be activated.
+func (credential *BreakGlassCredential) IsSealed() bool {
+	return credential.RevokedAt == nil && credential.ActivatedAt == nil
+}
+
+// BreakGlassSealed is returned when a credential is sealed, it is the only time the code can be read.
+type BreakGlassSealed struct {
+	Credential *BreakGlassCredential `json:"credential"`
+	Code       string                `json:"code"`
+}
+
+// BreakGlassActivation is the request to activate the sealed credential.
+type BreakGlassActivation struct {
+	Code   string `json:"code" validate:"required"`
+	Reason string `json:"reason" validate:"required,min=20,max=1000" pii:"true"`
+}
+
+// BreakGlassSession is the emergency access granted by an activation. The token authenticates as the
+// break-glass account and expires with the access.
+type BreakGlassSession struct {
+	CredentialID int       `json:"credential_id"`
+	Username     string    `json:"username"`
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// ValidateBreakGlassActivation validates the BreakGlassActivation struct.
+func ValidateBreakGlassActivation(activation BreakGlassActivation) error {
+	validate := NewValidator()
+	return validate.Struct(activation)
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Booked weekly care hours of the care contracts.
+const (
+	CareHours25 = 25
+	CareHours35 = 35
+	CareHours45 = 45
+)
+
+// CareContract is the booked weekly care hours (Betreuungsumfang) of a child between ValidFrom and ValidUntil,
+// both included.
+type CareContract struct {
+	ID          int       `json:"id"`
+	ChildID     int       `json:"child_id"` // Set from the route
+	WeeklyHours int       `json:"weekly_hours" validate:"required,oneof=25 35 45"`
+	ValidFrom   Date      `json:"valid_from" validate:"required"`
+	ValidUntil  *Date     `json:"valid_until" validate:"omitempty,gtefield=ValidFrom"` // Nil if the contract is open-ended
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// IsValidOn reports whether the contract covers the given day.
+func (c *CareContract) IsValidOn(day Date) bool {
+	return !c.ValidFrom.After(day.Time) && (c.ValidUntil == nil || !c.ValidUntil.Before(day.Time))
+}
+
+// Overlaps reports whether the periods of the two contracts share a day.
+func (c *CareContract) Overlaps(other *CareContract) bool {
+	startsBeforeOtherEnds := other.ValidUntil == nil || !c.ValidFrom.After(other.ValidUntil.Time)
+	endsAfterOtherStarts := c.ValidUntil == nil || !c.ValidUntil.Before(other.ValidFrom.Time)
+	return startsBeforeOtherEnds && endsAfterOtherStarts
+}
+
+// CareHoursWarning is raised for a child who attended longer than booked in every week of the checked period.
+type CareHoursWarning struct {
+	ChildID        int             `json:"child_id"`
+	ChildFirstName string          `json:"child_first_name"`
+	ChildLastName  string          `json:"child_last_name"`
+	Weeks          []CareHoursWeek `json:"weeks"`
+	// AverageExcessHours is by how many hours a week the attendance exceeded the booked hours on average.
+	AverageExcessHours float64 `json:"average_excess_hours"`
+}
+
+// CareHoursWeek compares the attendance of a child in the week starting on Monday WeekStart with the booked hours.
+type CareHoursWeek struct {
+	WeekStart     Date    `json:"week_start"`
+	BookedHours   int     `json:"booked_hours"`
+	AttendedHours float64 `json:"attended_hours"`
+}
+
+// ValidateCareContract validates the CareContract struct.
+func ValidateCareContract(contract CareContract) error {
+	validate := NewValidator()
+	return validate.Struct(contract)
+}
@@ -1,27 +1,72 @@
 package models
 
 import (
+	"errors"
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 // Category represents a category for documentation entries.
+// The optional age band in months marks the ages the category is suggested for, e.g. 60 and up for school readiness.
 type Category struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name" validate:"required,min=2,max=100"` // Unique handled by DB, but required for feedback
-	Description *string   `json:"description"`                            // Pointer for nullable field
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           int          `json:"id"`
+	Name         string       `json:"name" validate:"required,min=2,max=100"` // Unique handled by DB, but required for feedback
+	Description  *string      `json:"description"`                            // Pointer for nullable field
+	FormSchema   *FormSchema  `json:"form_schema,omitempty"`                  // Structured fields of observations in this category, nil for free text only
+	MinAgeMonths *int         `json:"min_age_months" validate:"omitempty,gte=0"`
+	MaxAgeMonths *int         `json:"max_age_months" validate:"omitempty,gte=0"`
+	ArchivedAt   *time.Time   `json:"archived_at,omitempty"` // Read only, archived categories are hidden from pickers for new entries
+	Usage        *UsageCounts `json:"usage,omitempty"`       // Read only, only set when listing categories
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// IsArchived reports whether the category has been archived.
+func (category *Category) IsArchived() bool {
+	return category.ArchivedAt != nil
+}
+
+// HasAgeBand reports whether the category is limited to an age band.
+func (category *Category) HasAgeBand() bool {
+	return category.MinAgeMonths != nil || category.MaxAgeMonths != nil
+}
+
+// AcceptsAge reports whether a child of the given age in months fits the age band of the category.
+func (category *Category) AcceptsAge(ageMonths int) bool {
+	if category.MinAgeMonths != nil && ageMonths < *category.MinAgeMonths {
+		return false
+	}
+	return category.MaxAgeMonths == nil || ageMonths <= *category.MaxAgeMonths
+}
+
+// FavoriteCategory is a category a user pinned or documented in before, listed first in the picker for new entries.
+type FavoriteCategory struct {
+	Category
+	Pinned     bool       `json:"pinned"`
+	UseCount   int        `json:"use_count"`    // Entries the teacher of the user documented in the category
+	LastUsedAt *time.Time `json:"last_used_at"` // Creation of the latest of these entries
 }
 
 // ValidateCategory validates the Category struct.
 func ValidateCategory(category Category) error {
-	validate := validator.New()
-	return validate.Struct(category)
+	validate := NewValidator()
+	if err := validate.Struct(category); err != nil {
+		return err
+	}
+	if category.MinAgeMonths != nil && category.MaxAgeMonths != nil && *category.MinAgeMonths > *category.MaxAgeMonths {
+		return errors.New("min_age_months must not be greater than max_age_months")
+	}
+	if category.FormSchema != nil {
+		return ValidateFormSchema(*category.FormSchema)
+	}
+	return nil
 }
 
 // StringPtr returns a pointer to the string value.
 func StringPtr(s string) *string {
 	return &s
 }
+
+// IntPtr returns a pointer to the int value.
+func IntPtr(i int) *int {
+	return &i
+}
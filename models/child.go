@@ -12,9 +12,11 @@ type Child struct {
 	ID                       int        `json:"id"`
 	FirstName                string     `json:"first_name" validate:"required,min=1,max=100" pii:"true"`
 	LastName                 string     `json:"last_name" validate:"required,min=1,max=100" pii:"true"`
-	Birthdate                time.Time  `json:"birthdate" validate:"required,childbirthdate" pii:"true"`
+	Birthdate                Date       `json:"birthdate" validate:"required,childbirthdate" pii:"true"`
 	AdmissionDate            *time.Time `json:"admission_date"`
 	ExpectedSchoolEnrollment *time.Time `json:"expected_school_enrollment" validate:"omitempty,gtfield=Birthdate"`
+	IsPreschooler            bool       `json:"is_preschooler"`        // Member of the pre-school cohort of the current school year
+	ArchivedAt               *time.Time `json:"archived_at,omitempty"` // Set by the school year rollover for departed children
 	CreatedAt                time.Time  `json:"created_at"`
 	UpdatedAt                time.Time  `json:"updated_at"`
 }
@@ -28,13 +30,15 @@ type ChildDB struct {
 	Birthdate                string
 	AdmissionDate            sql.NullTime
 	ExpectedSchoolEnrollment sql.NullTime
+	IsPreschooler            bool
+	ArchivedAt               sql.NullTime
 	CreatedAt                time.Time
 	UpdatedAt                time.Time
 }
 
 // ValidateChild validates the Child struct.
 func ValidateChild(child Child) error {
-	validate := validator.New()
+	validate := NewValidator()
 	validate.RegisterValidation("childbirthdate", ValidateChildBirthdate) //nolint:errcheck
 	return validate.Struct(child)
 }
@@ -46,10 +50,41 @@ func ValidateChildBirthdate(fl validator.FieldLevel) bool {
 		return false
 	}
 
-	now := time.Now()
-	minBirthdate := now.AddDate(-8, 0, 0) // Max 8 years old
-	maxBirthdate := now                   // Must already be born
+	today := Today(time.Now())
+	minBirthdate := today.AddDate(-8, 0, 0) // Max 8 years old
 
-	// Birthdate must be after minBirthdate
-	return birthdate.After(minBirthdate) && birthdate.Before(maxBirthdate)
+	// Birthdate must be after minBirthdate and the child must already be born
+	return birthdate.After(minBirthdate) && !birthdate.After(today.Time)
+}
+
+// Expansions of the children list, requested with ?include=group,current_teacher,last_observation.
+const (
+	ChildIncludeGroup           = "group"
+	ChildIncludeCurrentTeacher  = "current_teacher"
+	ChildIncludeLastObservation = "last_observation"
+)
+
+// ChildIncludes lists the valid expansions of the children list.
+var ChildIncludes = []string{ChildIncludeGroup, ChildIncludeCurrentTeacher, ChildIncludeLastObservation}
+
+// ChildListItem is a child of the list view with the requested expansions.
+// An expansion is omitted if it was not requested or the child has none, e.g. no group.
+type ChildListItem struct {
+	Child
+	Group               *ChildGroupSummary   `json:"group,omitempty"`
+	CurrentTeacher      *ChildTeacherSummary `json:"current_teacher,omitempty"`
+	LastObservationDate *Date                `json:"last_observation_date,omitempty"`
+}
+
+// ChildGroupSummary is the group of a child in the list view.
+type ChildGroupSummary struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ChildTeacherSummary is the Bezugserzieher/-in of a child in the list view, the teacher of the open primary assignment.
+type ChildTeacherSummary struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
 }
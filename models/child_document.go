@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Child document types.
+const (
+	ChildDocumentTypeCareContract       = "care_contract"       // Betreuungsvertrag
+	ChildDocumentTypeMeaslesProtection  = "measles_protection"  // Masernschutznachweis
+	ChildDocumentTypeMedicalCertificate = "medical_certificate" // Ärztliches Attest
+	ChildDocumentTypeOther              = "other"
+)
+
+// RequiredChildDocumentTypes are the documents every child in care needs, a reminder is raised while one is missing.
+var RequiredChildDocumentTypes = []string{ChildDocumentTypeCareContract, ChildDocumentTypeMeaslesProtection}
+
+// Reasons of document reminders.
+const (
+	DocumentReminderMissing  = "missing"
+	DocumentReminderExpired  = "expired"
+	DocumentReminderExpiring = "expiring"
+)
+
+// ChildDocument is a scan of the paperwork of a child, e.g. the Betreuungsvertrag or an ärztliches Attest. Scans are
+// PDFs or photographed pages as PNG or JPEG images.
+type ChildDocument struct {
+	ID               int    `json:"id"`
+	ChildID          int    `json:"child_id"` // Set from the route
+	DocumentType     string `json:"document_type" validate:"required,oneof=care_contract measles_protection medical_certificate other"`
+	FileName         string `json:"file_name" validate:"required,max=255" pii:"true"`
+	ContentType      string `json:"content_type"`        // Read only, detected from the uploaded content
+	SizeBytes        int    `json:"size_bytes"`          // Read only
+	ExpiresOn        *Date  `json:"expires_on"`          // Last day the document is valid, nil if it does not expire
+	UploadedByUserID *int   `json:"uploaded_by_user_id"` // Read only
+	// Envelope is set for documents the client encrypted, their content is served as it was uploaded. Read only.
+	Envelope  *AttachmentEnvelope `json:"envelope,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	// Content is the PDF or image, or its ciphertext if the document has an envelope. It is only loaded for downloads.
+	Content []byte `json:"-"`
+}
+
+// IsValidOn reports whether the document has not expired on the given day.
+func (d *ChildDocument) IsValidOn(day Date) bool {
+	return d.ExpiresOn == nil || !d.ExpiresOn.Before(day.Time)
+}
+
+// DocumentReminder is raised for a child whose required document is missing, or whose document of a type has
+// expired or is about to expire without a newer one being uploaded.
+type DocumentReminder struct {
+	ChildID        int    `json:"child_id"`
+	ChildFirstName string `json:"child_first_name"`
+	ChildLastName  string `json:"child_last_name"`
+	DocumentType   string `json:"document_type"`
+	Reason         string `json:"reason"`
+	// DocumentID and ExpiresOn are those of the document of the type that is valid the longest, nil if it is missing.
+	DocumentID *int  `json:"document_id"`
+	ExpiresOn  *Date `json:"expires_on"`
+}
+
+// ValidateChildDocument validates the ChildDocument struct.
+func ValidateChildDocument(document ChildDocument) error {
+	validate := NewValidator()
+	return validate.Struct(document)
+}
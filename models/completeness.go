@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// CategoryCoverage summarises the documentation of a child in one category.
+type CategoryCoverage struct {
+	CategoryID    int    `json:"category_id"`
+	CategoryName  string `json:"category_name"`
+	EntryCount    int    `json:"entry_count"`
+	LastEntryDate *Date  `json:"last_entry_date"`
+}
+
+// ChildCompleteness describes how completely a child has been documented within a period.
+// Score is the share of categories with at least one observation in the period, in percent.
+type ChildCompleteness struct {
+	ChildID           int                `json:"child_id"`
+	ChildName         string             `json:"child_name"`
+	Score             int                `json:"score"`
+	CategoriesTotal   int                `json:"categories_total"`
+	CategoriesCovered int                `json:"categories_covered"`
+	EntryCount        int                `json:"entry_count"`
+	LastEntryDate     *Date              `json:"last_entry_date"`
+	LastEntryAgeDays  *int               `json:"last_entry_age_days"`
+	PeriodStart       time.Time          `json:"period_start"`
+	PeriodEnd         time.Time          `json:"period_end"`
+	Categories        []CategoryCoverage `json:"categories"`
+}
+
+// Reasons a category is suggested, or ranked down, for the next observation of a child.
+const (
+	SuggestionReasonNotDocumented    = "not_documented"    // No observation in the completeness period
+	SuggestionReasonDocumentationGap = "documentation_gap" // The last observation is a while ago
+	SuggestionReasonAgeBand          = "age_band"          // The child is in the age band of the category
+	SuggestionReasonOutsideAgeBand   = "outside_age_band"  // The child is too young or too old for the category
+)
+
+// CategorySuggestion is a category suggested for the next observation of a child, ordered by Score.
+type CategorySuggestion struct {
+	CategoryID    int      `json:"category_id"`
+	CategoryName  string   `json:"category_name"`
+	Score         int      `json:"score"`
+	Reasons       []string `json:"reasons"`
+	EntryCount    int      `json:"entry_count"`
+	LastEntryDate *Date    `json:"last_entry_date"`
+}
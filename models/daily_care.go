@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// U3AgeMonths is the age in months from which a child is no longer U3, i.e. three years. Daily care is only
+// logged for younger children.
+const U3AgeMonths = 36
+
+// Meal portions, how much of a meal a child ate.
+const (
+	MealPortionNone   = "none"
+	MealPortionLittle = "little"
+	MealPortionHalf   = "half"
+	MealPortionMost   = "most"
+	MealPortionAll    = "all"
+)
+
+// DailyCareLog is the care of a U3 child on one day: meals eaten, the nap and diaper changes or toilet visits.
+// It is separate from the documentation entries of the Bildungsdokumentation. Unset fields were not recorded.
+type DailyCareLog struct {
+	ID                int       `json:"id"`
+	ChildID           int       `json:"child_id" validate:"required"` // Set from the route for the log of a child
+	CareDate          Date      `json:"care_date"`                    // Set from the route
+	Breakfast         *string   `json:"breakfast" validate:"omitempty,oneof=none little half most all"`
+	Lunch             *string   `json:"lunch" validate:"omitempty,oneof=none little half most all"`
+	Snack             *string   `json:"snack" validate:"omitempty,oneof=none little half most all"`
+	NapMinutes        *int      `json:"nap_minutes" validate:"omitempty,gte=0,lte=360"`
+	DiaperChanges     *int      `json:"diaper_changes" validate:"omitempty,gte=0,lte=20"`
+	ToiletVisits      *int      `json:"toilet_visits" validate:"omitempty,gte=0,lte=20"`
+	Note              *string   `json:"note" validate:"omitempty,max=500" pii:"true"`
+	HiddenFromParents bool      `json:"hidden_from_parents"` // Hidden logs are left out of the parents view
+	RecordedByUserID  *int      `json:"recorded_by_user_id"` // Read only, the user who last recorded the log
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// GroupDailyCare is a U3 child of a group with its care log of a day, Log is nil if nothing was recorded yet.
+type GroupDailyCare struct {
+	ChildID   int           `json:"child_id"`
+	FirstName string        `json:"first_name"`
+	LastName  string        `json:"last_name"`
+	Log       *DailyCareLog `json:"log"`
+}
+
+// ValidateDailyCareLog validates the DailyCareLog struct.
+func ValidateDailyCareLog(log DailyCareLog) error {
+	validate := NewValidator()
+	return validate.Struct(log)
+}
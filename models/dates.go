@@ -0,0 +1,136 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Date is a calendar day without a time of day, e.g. a birthdate or an observation date. It is sent and stored as
+// YYYY-MM-DD, so that it does not move by a day when converted between time zones, e.g. around daylight saving
+// time. The embedded time is midnight UTC of the day.
+type Date struct {
+	time.Time
+}
+
+// dateLayouts are the formats a Date is parsed from. Older clients and versions sent and stored timestamps,
+// their day in the offset they were given in is kept.
+var dateLayouts = []string{
+	time.DateOnly,
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00", // Written by the database driver for time.Time parameters
+	"2006-01-02 15:04:05.999999999",
+}
+
+// NewDate returns the given day.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf returns the calendar day of t in its location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return NewDate(year, month, day)
+}
+
+// ParseDate parses a day given as YYYY-MM-DD or, for backwards compatibility, as a timestamp.
+func ParseDate(value string) (Date, error) {
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return DateOf(parsed), nil
+		}
+	}
+	return Date{}, fmt.Errorf("invalid date %q, must be YYYY-MM-DD", value)
+}
+
+// AddDays returns the day the given number of days later, or earlier if days is negative.
+func (d Date) AddDays(days int) Date {
+	return Date{d.AddDate(0, 0, days)}
+}
+
+// String returns the day as YYYY-MM-DD.
+func (d Date) String() string {
+	return d.Format(time.DateOnly)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. null leaves the date unchanged.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid date %s, must be a string", data)
+	}
+	return d.UnmarshalText([]byte(value))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(text []byte) error {
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, dates are stored as YYYY-MM-DD.
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner.
+func (d *Date) Scan(value any) error {
+	switch value := value.(type) {
+	case time.Time:
+		*d = DateOf(value)
+		return nil
+	case string:
+		return d.UnmarshalText([]byte(value))
+	case []byte:
+		return d.UnmarshalText(value)
+	default:
+		return fmt.Errorf("cannot scan %T into a date", value)
+	}
+}
+
+// Dates are compared to today in the time zone of the facility, not of the server, so that an entry made late in
+// the evening is not dated in the future.
+
+var facilityLocation atomic.Pointer[time.Location]
+
+// SetFacilityLocation sets the time zone of the facility, see config.Config.Facility.
+func SetFacilityLocation(location *time.Location) {
+	facilityLocation.Store(location)
+}
+
+// FacilityLocation returns the time zone of the facility, the local time zone of the server if it is not set.
+func FacilityLocation() *time.Location {
+	if location := facilityLocation.Load(); location != nil {
+		return location
+	}
+	return time.Local
+}
+
+// Today returns the calendar day of now in the time zone of the facility.
+func Today(now time.Time) Date {
+	return DateOf(now.In(FacilityLocation()))
+}
+
+// IsAfterToday reports whether the day is after today in the facility.
+func (d Date) IsAfterToday(now time.Time) bool {
+	return d.After(Today(now).Time)
+}
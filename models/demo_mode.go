@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// DemoModeStatus describes whether the current session is served from the anonymized demo dataset.
+type DemoModeStatus struct {
+	Enabled           bool       `json:"enabled"`
+	SnapshotCreatedAt *time.Time `json:"snapshot_created_at,omitempty"`
+}
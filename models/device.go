@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+const (
+	DevicePlatformFCM     = "fcm"
+	DevicePlatformWebPush = "webpush"
+)
+
+// Device represents a push notification target registered by a user.
+type Device struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Platform  string    `json:"platform" validate:"required,oneof=fcm webpush"`
+	Token     string    `json:"token" validate:"required,max=2048"` // FCM registration token or Web Push endpoint URL
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ValidateDevice validates the Device struct.
+func ValidateDevice(device Device) error {
+	validate := NewValidator()
+	return validate.Struct(device)
+}
+
+// NotificationPreferences holds a user's opt-outs for push notifications and the weekly digest email.
+type NotificationPreferences struct {
+	UserID           int       `json:"user_id"`
+	PushEnabled      bool      `json:"push_enabled"`
+	NotifyApprovals  bool      `json:"notify_approvals"`
+	NotifyRejections bool      `json:"notify_rejections"`
+	NotifyReminders  bool      `json:"notify_reminders"`
+	NotifyDigest     bool      `json:"notify_digest"`
+	DigestEmail      *string   `json:"digest_email" validate:"omitempty,email,max=254" pii:"true"` // Recipient of the weekly digest, nil for none
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ValidateNotificationPreferences validates the NotificationPreferences struct.
+func ValidateNotificationPreferences(preferences NotificationPreferences) error {
+	validate := NewValidator()
+	return validate.Struct(preferences)
+}
+
+// DefaultNotificationPreferences returns the preferences used for users that never changed them.
+func DefaultNotificationPreferences(userID int) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:           userID,
+		PushEnabled:      true,
+		NotifyApprovals:  true,
+		NotifyRejections: true,
+		NotifyReminders:  true,
+		NotifyDigest:     true,
+	}
+}
+
+// NotificationType identifies the kind of event a notification is about.
+type NotificationType string
+
+const (
+	NotificationTypeApproval  NotificationType = "approval"
+	NotificationTypeRejection NotificationType = "rejection"
+	NotificationTypeReminder  NotificationType = "reminder"
+)
+
+// Notification is the payload sent to a user's devices.
+type Notification struct {
+	Type  NotificationType  `json:"type"`
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	Data  map[string]string `json:"data,omitempty"`
+}
+
+// Allows reports whether the preferences permit delivering a notification of the given type.
+func (p *NotificationPreferences) Allows(notificationType NotificationType) bool {
+	if !p.PushEnabled {
+		return false
+	}
+	switch notificationType {
+	case NotificationTypeApproval:
+		return p.NotifyApprovals
+	case NotificationTypeRejection:
+		return p.NotifyRejections
+	case NotificationTypeReminder:
+		return p.NotifyReminders
+	}
+	return true
+}
+
+// WantsDigest reports whether the weekly documentation digest is to be emailed. It does not depend on push
+// notifications being enabled.
+func (p *NotificationPreferences) WantsDigest() bool {
+	return p.NotifyDigest && p.DigestEmail != nil && *p.DigestEmail != ""
+}
@@ -0,0 +1,21 @@
+package models
+
+// DocumentationDigest summarizes the documentation of a teacher in a week. It is emailed every week to the
+// users who opted in, see NotificationPreferences.
+type DocumentationDigest struct {
+	TeacherID            int                 `json:"teacher_id"`
+	WeekStart            Date                `json:"week_start"` // Monday of the summarized week
+	WeekEnd              Date                `json:"week_end"`   // Sunday of the summarized week
+	EntriesWritten       int                 `json:"entries_written"`
+	ApprovalsReceived    int                 `json:"approvals_received"`
+	RejectedEntryIDs     []int               `json:"rejected_entry_ids"` // Entries of the teacher that were rejected and not submitted again
+	UndocumentedChildren []UndocumentedChild `json:"undocumented_children"`
+}
+
+// UndocumentedChild is a child assigned to a teacher without a recent observation.
+type UndocumentedChild struct {
+	ChildID             int    `json:"child_id"`
+	FirstName           string `json:"first_name"`
+	LastName            string `json:"last_name"`
+	LastObservationDate *Date  `json:"last_observation_date"` // nil if the child has never been observed
+}
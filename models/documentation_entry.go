@@ -8,16 +8,21 @@ import (
 
 // DocumentationEntry represents a behavioral documentation entry.
 type DocumentationEntry struct {
-	ID                     int       `json:"id"`
-	ChildID                int       `json:"child_id" validate:"required"`
-	TeacherID              int       `json:"teacher_id" validate:"required"`
-	CategoryID             int       `json:"category_id" validate:"required"`
-	ObservationDate        time.Time `json:"observation_date" validate:"required,iso8601date"` // Assuming ISO8601 format for date
-	ObservationDescription string    `json:"observation_description" validate:"required,min=10" pii:"true"`
-	IsApproved             bool      `json:"is_approved"`
-	ApprovedByUserID       *int      `json:"approved_by_teacher_id"` // Pointer for nullable foreign key
-	CreatedAt              time.Time `json:"created_at"`
-	UpdatedAt              time.Time `json:"updated_at"`
+	ID                     int            `json:"id"`
+	ChildID                int            `json:"child_id" validate:"required"`
+	TeacherID              int            `json:"teacher_id" validate:"required"`
+	CategoryID             int            `json:"category_id" validate:"required"`
+	ObservationDate        Date           `json:"observation_date" validate:"required,iso8601date"`
+	ObservationDescription string         `json:"observation_description" validate:"required,min=10" pii:"true"`
+	StructuredData         map[string]any `json:"structured_data,omitempty"` // Values of the category's form, stored encrypted
+	IsApproved             bool           `json:"is_approved"`
+	ApprovedByUserID       *int           `json:"approved_by_teacher_id"`         // Pointer for nullable foreign key
+	LockedAt               *time.Time     `json:"locked_at,omitempty"`            // Read only, set when the entry is included in a generated report
+	RevisionOfEntryID      *int           `json:"revision_of_entry_id,omitempty"` // Read only, the locked entry this entry revises
+	ProjectID              *int           `json:"project_id,omitempty"`           // The project the observation was made in, if any
+	Warnings               []string       `json:"warnings,omitempty"`             // Read only, set when saving an implausible entry, e.g. observed on a closed day
+	CreatedAt              time.Time      `json:"created_at"`
+	UpdatedAt              time.Time      `json:"updated_at"`
 }
 
 // DocumentationEntryDB is a struct that matches the documentation_entries table in the database.
@@ -27,25 +32,27 @@ type DocumentationEntryDB struct {
 	ChildID                int
 	TeacherID              int
 	CategoryID             int
-	ObservationDate        time.Time
+	ObservationDate        Date
 	ObservationDescription string
+	StructuredData         *string // Encrypted JSON, nil if the entry has no structured data
 	IsApproved             bool
 	ApprovedByUserID       *int
+	LockedAt               *time.Time
+	RevisionOfEntryID      *int
+	ProjectID              *int
 	CreatedAt              time.Time
 	UpdatedAt              time.Time
 }
 
 // ValidateDocumentationEntry validates the DocumentationEntry struct.
 func ValidateDocumentationEntry(entry DocumentationEntry) error {
-	validate := validator.New()
+	validate := NewValidator()
 	validate.RegisterValidation("iso8601date", ValidateISO8601Date) //nolint:errcheck
 	return validate.Struct(entry)
 }
 
-// ValidateISO8601Date is a custom validator for ISO8601 date format.
-// This is a placeholder; actual ISO8601 validation might be more complex
-// depending on the exact format expected (e.g., "YYYY-MM-DD").
-// For simplicity, this just checks if it's a valid time.Time and not in the future.
+// ValidateISO8601Date is a custom validator for dates. The format itself is checked when the date is parsed,
+// see Date.UnmarshalJSON, this only checks that the field is a date.
 func ValidateISO8601Date(fl validator.FieldLevel) bool {
 	_, ok := fl.Field().Interface().(time.Time)
 	return ok
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// DocumentationEventType identifies a step in the lifecycle of a documentation entry.
+type DocumentationEventType string
+
+const (
+	DocumentationEventCreated          DocumentationEventType = "created"
+	DocumentationEventEdited           DocumentationEventType = "edited"
+	DocumentationEventSubmitted        DocumentationEventType = "submitted"
+	DocumentationEventApproved         DocumentationEventType = "approved"
+	DocumentationEventRejected         DocumentationEventType = "rejected"
+	DocumentationEventIncludedInReport DocumentationEventType = "included_in_report"
+	DocumentationEventDeleted          DocumentationEventType = "deleted"
+)
+
+// Lifecycle states of a documentation entry, derived from its events.
+const (
+	DocumentationStatusDraft     = "draft"
+	DocumentationStatusSubmitted = "submitted"
+	DocumentationStatusApproved  = "approved"
+	DocumentationStatusRejected  = "rejected"
+	DocumentationStatusDeleted   = "deleted"
+)
+
+// DocumentationEvent is an immutable record in the append-only event stream of a child.
+// Sequence numbers the events of a child without gaps, starting at 1.
+type DocumentationEvent struct {
+	ID               int                    `json:"id"`
+	ChildID          int                    `json:"child_id"`
+	Sequence         int                    `json:"sequence"`
+	EntryID          int                    `json:"entry_id"`
+	Type             DocumentationEventType `json:"type"`
+	ActorUserID      *int                   `json:"actor_user_id"`
+	OnBehalfOfUserID *int                   `json:"on_behalf_of_user_id"`
+	DelegationID     *int                   `json:"delegation_id"`
+	Payload          DocumentationEventData `json:"payload"`
+	CreatedAt        time.Time              `json:"created_at"`
+}
+
+// DocumentationEventData holds what changed with an event, it is stored encrypted.
+// Entry is the state of the entry after created and edited events.
+type DocumentationEventData struct {
+	Entry               *DocumentationEntry `json:"entry,omitempty"`
+	ApprovedByTeacherID *int                `json:"approved_by_teacher_id,omitempty"`
+	Reason              string              `json:"reason,omitempty"`
+}
+
+// DocumentationEntryHistory is the review history of a single entry, replayed from its events.
+type DocumentationEntryHistory struct {
+	EntryID             int                  `json:"entry_id"`
+	ChildID             int                  `json:"child_id"`
+	Status              string               `json:"status"`
+	Revision            int                  `json:"revision"` // Number of edits
+	CreatedAt           *time.Time           `json:"created_at"`
+	SubmittedAt         *time.Time           `json:"submitted_at"`
+	ApprovedAt          *time.Time           `json:"approved_at"`
+	ApprovedByTeacherID *int                 `json:"approved_by_teacher_id"`
+	RejectionCount      int                  `json:"rejection_count"`
+	RejectionReason     string               `json:"rejection_reason,omitempty"` // Reason of the latest rejection while the entry is rejected
+	ReportCount         int                  `json:"report_count"`
+	Events              []DocumentationEvent `json:"events"`
+}
+
+// DocumentationLifecycleStats summarises the event streams of one or all children.
+type DocumentationLifecycleStats struct {
+	ChildID            *int           `json:"child_id"`
+	EventCounts        map[string]int `json:"event_counts"`
+	StatusCounts       map[string]int `json:"status_counts"`
+	AverageReviewHours *float64       `json:"average_review_hours"` // From the last submission to the approval
+	RejectionRate      *float64       `json:"rejection_rate"`       // Rejections per review decision
+}
@@ -0,0 +1,79 @@
+package models
+
+// DocumentationImportRequest carries historical observations, e.g. transcribed from a previous paper system.
+// A dry run only reports how the observations would be matched.
+type DocumentationImportRequest struct {
+	DryRun       bool                    `json:"dry_run"`
+	Observations []HistoricalObservation `json:"observations" validate:"required,min=1,max=5000"`
+}
+
+// HistoricalObservation references the child, author and category by name. ChildID and TeacherID
+// resolve names that the reconciliation report lists as ambiguous or unmatched.
+type HistoricalObservation struct {
+	ChildName       string `json:"child_name" validate:"required_without=ChildID" pii:"true"`
+	ChildBirthdate  *Date  `json:"child_birthdate" pii:"true"`
+	ChildID         *int   `json:"child_id"`
+	ObservationDate Date   `json:"observation_date" validate:"required"`
+	CategoryName    string `json:"category_name" validate:"required"`
+	Text            string `json:"text" validate:"required,min=10" pii:"true"`
+	AuthorName      string `json:"author_name" validate:"required_without=TeacherID" pii:"true"`
+	TeacherID       *int   `json:"teacher_id"`
+}
+
+// Status of an observation in the reconciliation report.
+const (
+	ImportItemStatusImported = "imported"
+	// ImportItemStatusMatched is reported by dry runs for observations that would be imported.
+	ImportItemStatusMatched   = "matched"
+	ImportItemStatusDuplicate = "duplicate"
+	ImportItemStatusUnmatched = "unmatched"
+	ImportItemStatusAmbiguous = "ambiguous"
+	ImportItemStatusInvalid   = "invalid"
+)
+
+// How a name of an observation was matched.
+const (
+	MatchByID    = "id"
+	MatchExact   = "exact"
+	MatchFuzzy   = "fuzzy"
+	MatchNone    = "none"
+	MatchSeveral = "ambiguous"
+)
+
+// DocumentationImportReport reconciles the observations of an import with the existing children, teachers and categories.
+type DocumentationImportReport struct {
+	DryRun        bool                      `json:"dry_run"`
+	ImportedCount int                       `json:"imported_count"`
+	ReviewCount   int                       `json:"review_count"`
+	Items         []DocumentationImportItem `json:"items"`
+}
+
+// DocumentationImportItem is the result for one observation, Index is its position in the request.
+// Candidates list the IDs an ambiguous name could refer to.
+type DocumentationImportItem struct {
+	Index             int      `json:"index"`
+	Status            string   `json:"status"`
+	ChildID           *int     `json:"child_id"`
+	ChildMatch        string   `json:"child_match"`
+	ChildCandidates   []int    `json:"child_candidates,omitempty"`
+	TeacherID         *int     `json:"teacher_id"`
+	TeacherMatch      string   `json:"teacher_match"`
+	TeacherCandidates []int    `json:"teacher_candidates,omitempty"`
+	CategoryID        *int     `json:"category_id"`
+	CategoryMatch     string   `json:"category_match"`
+	EntryID           *int     `json:"entry_id,omitempty"`
+	Problems          []string `json:"problems,omitempty"`
+}
+
+// ValidateDocumentationImportRequest validates the DocumentationImportRequest struct.
+// The observations are validated one by one, so that an invalid observation is reported instead of failing the import.
+func ValidateDocumentationImportRequest(request DocumentationImportRequest) error {
+	validate := NewValidator()
+	return validate.Struct(request)
+}
+
+// ValidateHistoricalObservation validates the HistoricalObservation struct.
+func ValidateHistoricalObservation(observation HistoricalObservation) error {
+	validate := NewValidator()
+	return validate.Struct(observation)
+}
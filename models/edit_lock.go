@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// EditLock marks a documentation entry as being edited, so that other teachers are told who edits it instead of
+// overwriting each other's changes. The lock is advisory and expires unless the editor renews it.
+type EditLock struct {
+	EntryID    int       `json:"entry_id"`
+	UserID     int       `json:"user_id"`
+	EditorName string    `json:"editor_name"` // Name of the editing teacher, the username of other accounts
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// IsActive reports whether the lock has not expired at the given time.
+func (lock *EditLock) IsActive(now time.Time) bool {
+	return now.Before(lock.ExpiresAt)
+}
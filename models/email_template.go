@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Keys of the email templates the admins can edit.
+const (
+	EmailTemplateReminder       = "reminder"
+	EmailTemplateRejection      = "rejection"
+	EmailTemplateReportDelivery = "report_delivery"
+)
+
+// EmailTemplateVariables lists the placeholders each template may use, with an example value for previews.
+// A placeholder is written as {{name}} in the subject and body.
+var EmailTemplateVariables = map[string]map[string]string{
+	EmailTemplateReminder: {
+		"teacher_name":  "Anna Schmidt",
+		"message":       "Bitte denken Sie an die Beobachtungen dieser Woche.",
+		"facility_name": "Kita Sonnenschein",
+	},
+	EmailTemplateRejection: {
+		"teacher_name":     "Anna Schmidt",
+		"child_name":       "Max Mustermann",
+		"observation_date": "02.05.2025",
+		"reason":           "Bitte ergänzen Sie die Beschreibung.",
+		"facility_name":    "Kita Sonnenschein",
+	},
+	EmailTemplateReportDelivery: {
+		"recipient_name": "Anna Schmidt",
+		"child_name":     "Max Mustermann",
+		"report_date":    "02.05.2025",
+		"facility_name":  "Kita Sonnenschein",
+	},
+}
+
+// EmailTemplate is a published version of the subject and body of an email. The highest version of a key is
+// the one sent. Version 0 is the built-in default, used as long as no version has been published.
+type EmailTemplate struct {
+	Key               string     `json:"key"`
+	Version           int        `json:"version"`
+	Subject           string     `json:"subject" validate:"required,max=200"`
+	Body              string     `json:"body" validate:"required,max=10000"`
+	ChangeSummary     string     `json:"change_summary" validate:"max=1000"` // What changed compared to the previous version
+	PublishedByUserID *int       `json:"published_by_user_id"`
+	PublishedAt       *time.Time `json:"published_at"` // Nil for the built-in default
+}
+
+// ValidateEmailTemplate validates the EmailTemplate struct.
+func ValidateEmailTemplate(template EmailTemplate) error {
+	validate := NewValidator()
+	return validate.Struct(template)
+}
+
+// RenderedEmail is an email template with its placeholders replaced.
+type RenderedEmail struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
@@ -0,0 +1,39 @@
+package models
+
+// Fields of the monthly fee export, the columns of the export are mapped from them.
+const (
+	FeeExportFieldChildID           = "child_id"
+	FeeExportFieldFirstName         = "first_name"
+	FeeExportFieldLastName          = "last_name"
+	FeeExportFieldBirthdate         = "birthdate"
+	FeeExportFieldMonth             = "month"
+	FeeExportFieldBookedWeeklyHours = "booked_weekly_hours"
+	FeeExportFieldAttendanceDays    = "attendance_days"
+	FeeExportFieldAttendedHours     = "attended_hours"
+)
+
+// FeeExportFields lists the fields of the fee export in their default order.
+var FeeExportFields = []string{
+	FeeExportFieldChildID,
+	FeeExportFieldFirstName,
+	FeeExportFieldLastName,
+	FeeExportFieldBirthdate,
+	FeeExportFieldMonth,
+	FeeExportFieldBookedWeeklyHours,
+	FeeExportFieldAttendanceDays,
+	FeeExportFieldAttendedHours,
+}
+
+// FeeExportRow is the care of a child in one month, as exported for the fee accounting of the municipality.
+type FeeExportRow struct {
+	ChildID   int
+	FirstName string
+	LastName  string
+	Birthdate Date
+	Month     Date // First day of the month
+	// BookedWeeklyHours are those of the contract valid at the start of the month, or of the first contract starting
+	// in it. Nil if the child had no contract in the month.
+	BookedWeeklyHours *int
+	AttendanceDays    int // Days with an arrival
+	AttendedHours     float64
+}
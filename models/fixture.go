@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// FixtureUser is an account seeded by the fixture server, with the password to log in.
+type FixtureUser struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// FixtureState describes the seeded data and the frozen clock of the fixture server.
+type FixtureState struct {
+	Now      time.Time     `json:"now"`       // The time of the frozen clock
+	SeededAt time.Time     `json:"seeded_at"` // The time of the clock when the data was last reset
+	Users    []FixtureUser `json:"users"`
+}
+
+// FixtureClockRequest moves the frozen clock of the fixture server.
+type FixtureClockRequest struct {
+	Now time.Time `json:"now"`
+}
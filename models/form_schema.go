@@ -0,0 +1,181 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Property types supported in category form schemas.
+const (
+	FormPropertyBoolean = "boolean"
+	FormPropertyString  = "string"
+	FormPropertyInteger = "integer"
+	FormPropertyNumber  = "number"
+)
+
+// FormSchema defines the structured fields of a category-specific observation form.
+// It uses the subset of JSON Schema needed for flat forms: an object with typed properties.
+type FormSchema struct {
+	Type       string                        `json:"type"`
+	Properties map[string]FormSchemaProperty `json:"properties"`
+	Required   []string                      `json:"required,omitempty"`
+}
+
+// FormSchemaProperty defines a single field of a FormSchema.
+type FormSchemaProperty struct {
+	Type      string   `json:"type"`
+	Title     string   `json:"title,omitempty"`
+	Enum      []string `json:"enum,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+}
+
+// FormFieldError describes why a value of the structured data does not match the schema.
+type FormFieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidateFormSchema checks that a form schema only uses the supported subset of JSON Schema.
+func ValidateFormSchema(schema FormSchema) error {
+	if schema.Type != "object" {
+		return errors.New(`form schema type must be "object"`)
+	}
+	if len(schema.Properties) == 0 {
+		return errors.New("form schema must define at least one property")
+	}
+	for name, property := range schema.Properties {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("form schema property names must not be empty")
+		}
+		switch property.Type {
+		case FormPropertyBoolean, FormPropertyString, FormPropertyInteger, FormPropertyNumber:
+		default:
+			return fmt.Errorf("property %q has unsupported type %q", name, property.Type)
+		}
+		if len(property.Enum) > 0 && property.Type != FormPropertyString {
+			return fmt.Errorf("property %q: enum is only supported for strings", name)
+		}
+		if property.MaxLength != nil && property.Type != FormPropertyString {
+			return fmt.Errorf("property %q: maxLength is only supported for strings", name)
+		}
+		if (property.Minimum != nil || property.Maximum != nil) && property.Type != FormPropertyInteger && property.Type != FormPropertyNumber {
+			return fmt.Errorf("property %q: minimum and maximum are only supported for numbers", name)
+		}
+	}
+	for _, name := range schema.Required {
+		if _, ok := schema.Properties[name]; !ok {
+			return fmt.Errorf("required property %q is not defined", name)
+		}
+	}
+	return nil
+}
+
+// Validate checks structured observation data against the schema and returns every mismatch.
+func (schema *FormSchema) Validate(data map[string]any) []FormFieldError {
+	var fieldErrors []FormFieldError
+	for _, name := range schema.Required {
+		if value, ok := data[name]; !ok || value == nil {
+			fieldErrors = append(fieldErrors, FormFieldError{Field: name, Message: fmt.Sprintf("%s is required", name)})
+		}
+	}
+	for _, name := range sortedKeys(data) {
+		value := data[name]
+		property, ok := schema.Properties[name]
+		if !ok {
+			fieldErrors = append(fieldErrors, FormFieldError{Field: name, Message: fmt.Sprintf("%s is not part of the form", name)})
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if message := property.check(value); message != "" {
+			fieldErrors = append(fieldErrors, FormFieldError{Field: name, Message: fmt.Sprintf("%s %s", name, message)})
+		}
+	}
+	return fieldErrors
+}
+
+func (property FormSchemaProperty) check(value any) string {
+	switch property.Type {
+	case FormPropertyBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+	case FormPropertyString:
+		text, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if len(property.Enum) > 0 && !slices.Contains(property.Enum, text) {
+			return fmt.Sprintf("must be one of %s", strings.Join(property.Enum, ", "))
+		}
+		if property.MaxLength != nil && utf8.RuneCountInString(text) > *property.MaxLength {
+			return fmt.Sprintf("must be at most %d characters long", *property.MaxLength)
+		}
+	case FormPropertyInteger, FormPropertyNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return "must be a number"
+		}
+		if property.Type == FormPropertyInteger && number != math.Trunc(number) {
+			return "must be an integer"
+		}
+		if property.Minimum != nil && number < *property.Minimum {
+			return fmt.Sprintf("must be at least %s", formatNumber(*property.Minimum))
+		}
+		if property.Maximum != nil && number > *property.Maximum {
+			return fmt.Sprintf("must be at most %s", formatNumber(*property.Maximum))
+		}
+	}
+	return ""
+}
+
+// FormatFields renders the structured data as "Title: value" lines in the order of the property names.
+func (schema *FormSchema) FormatFields(data map[string]any) []string {
+	var lines []string
+	for _, name := range sortedKeys(data) {
+		value := data[name]
+		if value == nil {
+			continue
+		}
+		label := name
+		if property, ok := schema.Properties[name]; ok && property.Title != "" {
+			label = property.Title
+		}
+		var formatted string
+		switch typed := value.(type) {
+		case bool:
+			formatted = "nein"
+			if typed {
+				formatted = "ja"
+			}
+		case float64:
+			formatted = formatNumber(typed)
+		default:
+			formatted = fmt.Sprint(typed)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", label, formatted))
+	}
+	return lines
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+func sortedKeys(data map[string]any) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// GalleryPhoto is a photo of the gallery of a group with the children it shows.
+type GalleryPhoto struct {
+	ID          int     `json:"id"`
+	GroupID     int     `json:"group_id"` // Set from the route
+	Caption     *string `json:"caption" validate:"omitempty,max=500" pii:"true"`
+	ContentType string  `json:"content_type"` // Read only, detected from the uploaded image
+	ChildIDs    []int   `json:"child_ids" validate:"dive,gt=0"`
+	// RedactedChildIDs are the tagged children without photo consent. Read only, set when listing photos;
+	// the image of a photo with such children is withheld.
+	RedactedChildIDs []int `json:"redacted_child_ids"`
+	UploadedByUserID *int  `json:"uploaded_by_user_id"`
+	// Envelope is set for photos the client encrypted, their content is served as it was uploaded. Read only.
+	Envelope  *AttachmentEnvelope `json:"envelope,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	// Content is the image, or its ciphertext if the photo has an envelope. It is only loaded for downloads and
+	// reports.
+	Content []byte `json:"-"`
+}
+
+// IsRedacted reports whether the photo shows a child without photo consent.
+func (p *GalleryPhoto) IsRedacted() bool {
+	return len(p.RedactedChildIDs) > 0
+}
+
+// PhotoConsent records that the parents of a child consented to photos of the child, with a reference to the
+// signed form, e.g. its date or file number.
+type PhotoConsent struct {
+	ChildID          int       `json:"child_id"` // Set from the route
+	ConsentReference string    `json:"consent_reference" validate:"required,max=200" pii:"true"`
+	RecordedByUserID *int      `json:"recorded_by_user_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ValidateGalleryPhoto validates the GalleryPhoto struct.
+func ValidateGalleryPhoto(photo GalleryPhoto) error {
+	validate := NewValidator()
+	return validate.Struct(photo)
+}
+
+// ValidatePhotoConsent validates the PhotoConsent struct.
+func ValidatePhotoConsent(consent PhotoConsent) error {
+	validate := NewValidator()
+	return validate.Struct(consent)
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// GeneratedReport records a child report that has been handed out and the entries it included.
+// The included entries are locked until an admin unlocks them.
+type GeneratedReport struct {
+	ID      int `json:"id"`
+	ChildID int `json:"child_id"`
+	// DocumentID is printed on the report, so that copies can be traced back to it.
+	DocumentID        string                    `json:"document_id"`
+	GeneratedByUserID *int                      `json:"generated_by_user_id"`
+	GeneratedAt       time.Time                 `json:"generated_at"`
+	EntryIDs          []int                     `json:"entry_ids"`
+	FileName          string                    `json:"file_name"`
+	Parameters        GeneratedReportParameters `json:"parameters"`
+	SizeBytes         int                       `json:"size_bytes"`
+	// Archived is false for reports generated before reports were archived, they cannot be downloaded again.
+	Archived bool `json:"archived"`
+	// Content is the generated document. It is only loaded for downloads.
+	Content []byte `json:"-"`
+}
+
+// GeneratedReportParameters are the options a report was generated with.
+type GeneratedReportParameters struct {
+	RedactionProfileID   *int   `json:"redaction_profile_id"`
+	RedactionProfileName string `json:"redaction_profile_name,omitempty"`
+	IncludeCompleteness  bool   `json:"include_completeness"`
+	PhotoIDs             []int  `json:"photo_ids,omitempty"` // Gallery photos embedded in the report
+}
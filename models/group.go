@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Group represents a group of children with its room and staff.
+// The age band is given in months, e.g. 24 to 72 for a group of children aged two to six.
+type Group struct {
+	ID                  int          `json:"id"`
+	Name                string       `json:"name" validate:"required,min=1,max=100"`
+	Capacity            int          `json:"capacity" validate:"required,gt=0"`
+	MinAgeMonths        *int         `json:"min_age_months" validate:"omitempty,gte=0"`
+	MaxAgeMonths        *int         `json:"max_age_months" validate:"omitempty,gte=0"`
+	Room                *string      `json:"room" validate:"omitempty,max=100"`
+	LeadTeacherID       *int         `json:"lead_teacher_id"`
+	AssistantTeacherIDs []int        `json:"assistant_teacher_ids"`
+	ChildIDs            []int        `json:"child_ids"`       // Read only, changed by assigning children
+	Usage               *UsageCounts `json:"usage,omitempty"` // Read only, only set when listing groups
+	CreatedAt           time.Time    `json:"created_at"`
+	UpdatedAt           time.Time    `json:"updated_at"`
+}
+
+// ValidateGroup validates the Group struct.
+func ValidateGroup(group Group) error {
+	validate := NewValidator()
+	if err := validate.Struct(group); err != nil {
+		return err
+	}
+	if group.MinAgeMonths != nil && group.MaxAgeMonths != nil && *group.MinAgeMonths > *group.MaxAgeMonths {
+		return errors.New("min_age_months must not be greater than max_age_months")
+	}
+	if group.LeadTeacherID != nil && slices.Contains(group.AssistantTeacherIDs, *group.LeadTeacherID) {
+		return errors.New("the lead teacher cannot also be an assistant teacher")
+	}
+	return nil
+}
+
+// AcceptsAge reports whether a child of the given age in months fits the age band of the group.
+func (g *Group) AcceptsAge(ageMonths int) bool {
+	if g.MinAgeMonths != nil && ageMonths < *g.MinAgeMonths {
+		return false
+	}
+	return g.MaxAgeMonths == nil || ageMonths <= *g.MaxAgeMonths
+}
+
+// AgeInMonths returns the age in completed months on the given day.
+func AgeInMonths(birthdate Date, at Date) int {
+	months := (at.Year()-birthdate.Year())*12 + int(at.Month()-birthdate.Month())
+	if at.Day() < birthdate.Day() {
+		months--
+	}
+	return months
+}
+
+// GroupAssignmentResult is the result of assigning a child to a group.
+// Assignments beyond the capacity or outside the age band succeed, but come with warnings.
+type GroupAssignmentResult struct {
+	Group    *Group   `json:"group"`
+	Warnings []string `json:"warnings"`
+}
+
+// GroupStatistics summarizes the occupancy of a group.
+type GroupStatistics struct {
+	GroupID                int      `json:"group_id"`
+	Name                   string   `json:"name"`
+	Capacity               int      `json:"capacity"`
+	ChildCount             int      `json:"child_count"`
+	FreePlaces             int      `json:"free_places"` // Negative if the group is over capacity
+	Utilization            float64  `json:"utilization"` // Child count divided by capacity
+	StaffCount             int      `json:"staff_count"`
+	ChildrenPerStaff       *float64 `json:"children_per_staff"` // Nil for groups without staff
+	AverageAgeMonths       *float64 `json:"average_age_months"` // Nil for empty groups
+	ChildrenOutsideAgeBand int      `json:"children_outside_age_band"`
+	// BookedCareHours is the sum of the weekly hours of the care contracts valid today.
+	BookedCareHours             int `json:"booked_care_hours"`
+	ChildrenWithoutCareContract int `json:"children_without_care_contract"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// GroupHandover records the move of a child into another group, e.g. from the U3 into the Ü3 group (Gruppenwechsel),
+// and its confirmation by a teacher of the receiving group.
+type GroupHandover struct {
+	ID              int                   `json:"id"`
+	ChildID         int                   `json:"child_id"`      // Set from the route
+	FromGroupID     *int                  `json:"from_group_id"` // Read only, the group the child left; nil if it was in none or the group was deleted
+	ToGroupID       *int                  `json:"to_group_id" validate:"required"`
+	HandoverDate    Date                  `json:"handover_date"`      // Defaults to today
+	CreatedByUserID *int                  `json:"created_by_user_id"` // Read only
+	Confirmation    *HandoverConfirmation `json:"confirmation"`       // Read only, nil until a receiving teacher confirmed
+	CreatedAt       time.Time             `json:"created_at"`
+}
+
+// HandoverConfirmation is the structured confirmation of a handover by a teacher of the receiving group. The reviewed
+// parts of the handover packet must all be confirmed.
+type HandoverConfirmation struct {
+	ObservationsReviewed  bool      `json:"observations_reviewed" validate:"required"`
+	CareSpecificsReviewed bool      `json:"care_specifics_reviewed" validate:"required"`
+	SupportGoalsReviewed  bool      `json:"support_goals_reviewed" validate:"required"`
+	ParentsInformed       bool      `json:"parents_informed"`
+	Notes                 *string   `json:"notes" validate:"omitempty,max=2000" pii:"true"`
+	ConfirmedByTeacherID  *int      `json:"confirmed_by_teacher_id"` // Read only, nil if the teacher was deleted
+	ConfirmedAt           time.Time `json:"confirmed_at"`            // Read only
+}
+
+// IsConfirmed reports whether a teacher of the receiving group confirmed the handover.
+func (h *GroupHandover) IsConfirmed() bool {
+	return h.Confirmation != nil
+}
+
+// HandoverPacket is what the receiving group needs to know about a child, compiled as of the handover date.
+// The open Förderziele are the running external supports and the open action items agreed on in team meetings.
+type HandoverPacket struct {
+	Handover           GroupHandover         `json:"handover"`
+	ChildName          string                `json:"child_name" pii:"true"`
+	AgeMonths          int                   `json:"age_months"` // On the handover date
+	FromGroupName      *string               `json:"from_group_name"`
+	ToGroupName        *string               `json:"to_group_name"`
+	LatestObservations []HandoverObservation `json:"latest_observations"` // The latest observation of each category
+	Care               HandoverCare          `json:"care"`
+	ActiveSupports     []ChildSupport        `json:"active_supports"`
+	OpenActionItems    []ActionItem          `json:"open_action_items"`
+}
+
+// HandoverObservation is the latest observation of a child in a category.
+type HandoverObservation struct {
+	CategoryID   int                `json:"category_id"`
+	CategoryName string             `json:"category_name"`
+	Entry        DocumentationEntry `json:"entry"`
+}
+
+// HandoverCare are the care specifics of a child, the daily care logs of the last weeks and the recent medications.
+type HandoverCare struct {
+	DailyCareLogs     []DailyCareLog             `json:"daily_care_logs"`
+	AverageNapMinutes *int                       `json:"average_nap_minutes"` // Nil if no nap was logged
+	Medications       []MedicationAdministration `json:"medications"`
+}
+
+// ValidateGroupHandover validates the GroupHandover struct.
+func ValidateGroupHandover(handover GroupHandover) error {
+	validate := NewValidator()
+	return validate.Struct(handover)
+}
+
+// ValidateHandoverConfirmation validates the HandoverConfirmation struct.
+func ValidateHandoverConfirmation(confirmation HandoverConfirmation) error {
+	validate := NewValidator()
+	return validate.Struct(confirmation)
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Status of an import job.
+const (
+	ImportJobStatusRunning   = "running"
+	ImportJobStatusCompleted = "completed"
+	// ImportJobStatusFailed is set when the import stopped on an error. The pending rows can be resumed.
+	ImportJobStatusFailed     = "failed"
+	ImportJobStatusRolledBack = "rolled_back"
+)
+
+// Status of a row of an import job.
+const (
+	ImportRowStatusPending  = "pending"
+	ImportRowStatusImported = "imported"
+	// ImportRowStatusInvalid rows could not be parsed or validated, they are never imported.
+	ImportRowStatusInvalid    = "invalid"
+	ImportRowStatusRolledBack = "rolled_back"
+)
+
+// ImportJob is a bulk import of children together with the status of its rows.
+type ImportJob struct {
+	ID              int       `json:"id"`
+	FileName        string    `json:"file_name"`
+	Status          string    `json:"status"`
+	CreatedByUserID *int      `json:"created_by_user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	// RowCounts counts the rows by status.
+	RowCounts map[string]int `json:"row_counts"`
+	// Rows is only loaded for a single job.
+	Rows []ImportJobRow `json:"rows,omitempty"`
+}
+
+// ImportJobRow is a row of an import job. RowNumber counts the data rows of the file, starting at 1.
+type ImportJobRow struct {
+	RowNumber int     `json:"row_number"`
+	ChildName string  `json:"child_name"`
+	Status    string  `json:"status"`
+	Error     *string `json:"error"`
+	ChildID   *int    `json:"child_id"`
+	// Child is the parsed row, nil for invalid rows.
+	Child *Child `json:"-"`
+}
+
+// ImportRollbackResult lists the rows rolled back and the rows kept, because their child has been documented since.
+type ImportRollbackResult struct {
+	Job            *ImportJob `json:"job"`
+	RolledBackRows []int      `json:"rolled_back_rows"`
+	SkippedRows    []int      `json:"skipped_rows"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Incident is an incident or accident of a child in the facility. Incidents can be saved while details are still
+// missing, the Unfallanzeige for the accident insurer is only exported once ValidateIncidentComplete passes.
+type Incident struct {
+	ID               int        `json:"id"`
+	ChildID          int        `json:"child_id" validate:"required"` // Set from the route when creating
+	OccurredAt       time.Time  `json:"occurred_at" validate:"required"`
+	Location         *string    `json:"location" validate:"omitempty,max=200"`
+	Description      *string    `json:"description" validate:"omitempty,max=4000" pii:"true"` // What happened
+	Injuries         *string    `json:"injuries" validate:"omitempty,max=2000" pii:"true"`    // Injured body parts and kind of injury
+	FirstAid         *string    `json:"first_aid" validate:"omitempty,max=2000" pii:"true"`   // Measures taken and by whom
+	Witnesses        *string    `json:"witnesses" validate:"omitempty,max=500" pii:"true"`
+	MedicalTreatment bool       `json:"medical_treatment"`
+	Physician        *string    `json:"physician" validate:"omitempty,max=300" pii:"true"` // Name and address of the treating physician
+	ParentInformedAt *time.Time `json:"parent_informed_at" validate:"omitempty,gtefield=OccurredAt"`
+	ReportedByUserID *int       `json:"reported_by_user_id"` // Read only, the user who created the incident
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// incidentReportFields are the fields the Unfallanzeige asks for. If nobody saw the incident, the witnesses
+// are given as e.g. "keine".
+type incidentReportFields struct {
+	Location         string     `json:"location" validate:"required"`
+	Description      string     `json:"description" validate:"required"`
+	Injuries         string     `json:"injuries" validate:"required"`
+	FirstAid         string     `json:"first_aid" validate:"required"`
+	Witnesses        string     `json:"witnesses" validate:"required"`
+	MedicalTreatment bool       `json:"medical_treatment"`
+	Physician        string     `json:"physician" validate:"required_if=MedicalTreatment true"`
+	ParentInformedAt *time.Time `json:"parent_informed_at" validate:"required"`
+}
+
+// ValidateIncident validates the Incident struct.
+func ValidateIncident(incident Incident) error {
+	validate := NewValidator()
+	return validate.Struct(incident)
+}
+
+// ValidateIncidentComplete validates that an incident has everything the Unfallanzeige asks for.
+func ValidateIncidentComplete(incident Incident) error {
+	value := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	validate := NewValidator()
+	return validate.Struct(incidentReportFields{
+		Location:         value(incident.Location),
+		Description:      value(incident.Description),
+		Injuries:         value(incident.Injuries),
+		FirstAid:         value(incident.FirstAid),
+		Witnesses:        value(incident.Witnesses),
+		MedicalTreatment: incident.MedicalTreatment,
+		Physician:        value(incident.Physician),
+		ParentInformedAt: incident.ParentInformedAt,
+	})
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Invitation is a single-use signup link created by an admin. The account created from it gets the preset role.
+type Invitation struct {
+	ID              int        `json:"id"`
+	Role            string     `json:"role" validate:"required,oneof=teacher admin"`
+	CreatedByUserID *int       `json:"created_by_user_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	UsedAt          *time.Time `json:"used_at"`
+	UsedByUserID    *int       `json:"used_by_user_id"`
+	// TokenHash is the SHA-256 hash of the token, the token itself is only returned once on creation.
+	TokenHash string `json:"-"`
+}
+
+// InvitationLink is returned when an invitation is created, it is the only time the token can be read.
+type InvitationLink struct {
+	Invitation *Invitation `json:"invitation"`
+	Token      string      `json:"token"`
+	// SignupURL is the configured signup page with the token appended, empty if no signup page is configured.
+	SignupURL string `json:"signup_url,omitempty"`
+}
+
+// ValidateInvitation validates the Invitation struct.
+func ValidateInvitation(invitation Invitation) error {
+	validate := NewValidator()
+	return validate.Struct(invitation)
+}
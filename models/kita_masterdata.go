@@ -1,9 +1,9 @@
 package models
 
 import (
+	"slices"
+	"strings"
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 // KitaMasterdata represents the master data of the kindergarten.
@@ -17,10 +17,90 @@ type KitaMasterdata struct {
 	Email       string    `json:"email" validate:"required,email"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// Theme is read only here, it is changed through its own endpoint.
+	Theme DocumentTheme `json:"theme"`
+	// Terminology is read only here, it is changed through its own endpoint.
+	Terminology Terminology `json:"terminology"`
+}
+
+// DocumentTheme is the branding applied to all generated documents.
+type DocumentTheme struct {
+	// FontFamily replaces the default font of the documents, nil keeps it.
+	FontFamily *string `json:"font_family" validate:"omitempty,min=1,max=100"`
+	// AccentColor is used for titles and headings, as #RRGGBB. Nil keeps the default colors.
+	AccentColor *string `json:"accent_color" validate:"omitempty,len=7,hexcolor"`
+	// HasLogo is read only, the logo is uploaded separately.
+	HasLogo bool `json:"has_logo"`
+}
+
+// DocumentLogo is the logo printed at the top of generated documents.
+type DocumentLogo struct {
+	Content     []byte
+	ContentType string
+}
+
+// Terms of the terminology, the wording generated documents use for the staff and the structure of the documentation.
+const (
+	TermTeacher          = "teacher"
+	TermTeachers         = "teachers"
+	TermPrimaryTeacher   = "primary_teacher"
+	TermSecondaryTeacher = "secondary_teacher"
+	TermIntern           = "intern"
+	TermCategory         = "category"
+	TermDocumentation    = "documentation"
+)
+
+// DefaultTerms are the terms used unless the facility replaces them.
+var DefaultTerms = map[string]string{
+	TermTeacher:          "Fachkraft",
+	TermTeachers:         "Fachkräfte",
+	TermPrimaryTeacher:   "Bezugserzieher/-in",
+	TermSecondaryTeacher: "Zweitkraft",
+	TermIntern:           "Praktikant/-in",
+	TermCategory:         "Bildungsbereich",
+	TermDocumentation:    "Bildungsdokumentation",
+}
+
+// Terminology replaces the default terms of generated documents and exports, e.g. for a Träger that calls its staff
+// "pädagogische Fachkräfte". Terms that are not set keep their default.
+type Terminology map[string]string
+
+// Term returns the wording of a term, the default if the facility did not replace it.
+func (terminology Terminology) Term(term string) string {
+	if wording, ok := terminology[term]; ok {
+		return wording
+	}
+	return DefaultTerms[term]
+}
+
+// Effective returns the wording of every term.
+func (terminology Terminology) Effective() map[string]string {
+	effective := make(map[string]string, len(DefaultTerms))
+	for term := range DefaultTerms {
+		effective[term] = terminology.Term(term)
+	}
+	return effective
 }
 
 // ValidateKitaMasterdata validates the KitaMasterdata struct.
 func ValidateKitaMasterdata(data KitaMasterdata) error {
-	validate := validator.New()
+	validate := NewValidator()
 	return validate.Struct(data)
 }
+
+// ValidateDocumentTheme validates the DocumentTheme struct.
+func ValidateDocumentTheme(theme DocumentTheme) error {
+	validate := NewValidator()
+	return validate.Struct(theme)
+}
+
+// ValidateTerminology validates that only known terms are replaced, each by a non-empty wording.
+func ValidateTerminology(terminology Terminology) error {
+	terms := make([]string, 0, len(DefaultTerms))
+	for term := range DefaultTerms {
+		terms = append(terms, term)
+	}
+	slices.Sort(terms)
+	validate := NewValidator()
+	return validate.Var(terminology, "dive,keys,oneof="+strings.Join(terms, " ")+",endkeys,required,max=100")
+}
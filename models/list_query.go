@@ -0,0 +1,47 @@
+package models
+
+import "slices"
+
+// Operators of the conditions of a ListQuery.
+const (
+	OperatorEqual          = "eq"
+	OperatorGreaterOrEqual = "gte"
+	OperatorLessOrEqual    = "lte"
+	OperatorIsNull         = "null"     // Takes no value
+	OperatorIsNotNull      = "not_null" // Takes no value
+)
+
+// Condition narrows down a list to the records whose field compares to the value.
+type Condition struct {
+	Field    string
+	Operator string
+	Value    any
+}
+
+// SortField orders a list by a field.
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// ListQuery specifies the filters, sorting and pagination of a list. Fields are named like the JSON fields of
+// the listed model, the stores map them to columns and reject the fields they do not support.
+// The zero value lists all records in the default order of the store.
+type ListQuery struct {
+	Conditions []Condition
+	Sort       []SortField // The default order of the store if empty
+	Limit      int         // 0 for no limit
+	Offset     int
+}
+
+// Where returns a copy of the query with an additional condition.
+func (query ListQuery) Where(field string, operator string, value any) ListQuery {
+	query.Conditions = append(slices.Clip(query.Conditions), Condition{Field: field, Operator: operator, Value: value})
+	return query
+}
+
+// OrderBy returns a copy of the query sorted by the given field, after the fields it is already sorted by.
+func (query ListQuery) OrderBy(field string, descending bool) ListQuery {
+	query.Sort = append(slices.Clip(query.Sort), SortField{Field: field, Descending: descending})
+	return query
+}
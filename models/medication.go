@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// MedicationAdministration is a medication given to a child on written instruction of the parents. Administrations
+// are read-only once recorded and confirmed by a second user, as the Träger requires.
+type MedicationAdministration struct {
+	ID                      int        `json:"id"`
+	ChildID                 int        `json:"child_id" validate:"required"` // Set from the route
+	Medication              string     `json:"medication" validate:"required,max=200" pii:"true"`
+	Dose                    string     `json:"dose" validate:"required,max=100" pii:"true"`
+	InstructionReference    string     `json:"instruction_reference" validate:"required,max=200" pii:"true"` // The written instruction of the parents, e.g. its date
+	AdministeredAt          time.Time  `json:"administered_at" validate:"required"`
+	AdministeredByTeacherID int        `json:"administered_by_teacher_id" validate:"required"`
+	Note                    *string    `json:"note" validate:"omitempty,max=1000" pii:"true"`
+	RecordedByUserID        *int       `json:"recorded_by_user_id"`  // Read only
+	ConfirmedByUserID       *int       `json:"confirmed_by_user_id"` // Read only, set by the confirmation
+	ConfirmedAt             *time.Time `json:"confirmed_at"`         // Read only, nil until a second user confirmed
+	CreatedAt               time.Time  `json:"created_at"`
+}
+
+// IsConfirmed reports whether a second user confirmed the administration.
+func (administration MedicationAdministration) IsConfirmed() bool {
+	return administration.ConfirmedAt != nil
+}
+
+// ValidateMedicationAdministration validates the MedicationAdministration struct.
+func ValidateMedicationAdministration(administration MedicationAdministration) error {
+	validate := NewValidator()
+	return validate.Struct(administration)
+}
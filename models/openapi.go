@@ -0,0 +1,230 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification the API description follows.
+const OpenAPIVersion = "3.1.0"
+
+// bearerAuthScheme is the name of the security scheme of the JWT in the Authorization header.
+const bearerAuthScheme = "bearerAuth"
+
+// errorSchemaRef references the schema of the JSON body of error responses.
+const errorSchemaRef = "#/components/schemas/Error"
+
+// OpenAPIDocument describes the routes a caller may use as an OpenAPI document, for generating clients.
+type OpenAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       OpenAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]OpenAPIOperation `json:"paths"` // Path to lower case method to operation
+	Components OpenAPIComponents                      `json:"components"`
+}
+
+// OpenAPIInfo is the metadata of the API.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIOperation is a single route of the API.
+type OpenAPIOperation struct {
+	OperationID  string                     `json:"operationId"`
+	Parameters   []OpenAPIParameter         `json:"parameters,omitempty"`
+	Security     []map[string][]string      `json:"security"` // Empty for public routes
+	Responses    map[string]OpenAPIResponse `json:"responses"`
+	AllowedRoles []string                   `json:"x-allowed-roles,omitempty"`
+}
+
+// OpenAPIParameter is a parameter of an operation.
+type OpenAPIParameter struct {
+	Name     string            `json:"name"`
+	In       string            `json:"in"`
+	Required bool              `json:"required"`
+	Schema   map[string]string `json:"schema"`
+}
+
+// OpenAPIResponse is a possible response of an operation.
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"` // Media type to body, for documented bodies
+}
+
+// OpenAPIMediaType is the body of a response in one media type.
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the JSON schema of a body, only the keywords the document uses are supported.
+type OpenAPISchema struct {
+	Ref         string                    `json:"$ref,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Const       string                    `json:"const,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Properties  map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required    []string                  `json:"required,omitempty"`
+	Items       *OpenAPISchema            `json:"items,omitempty"`
+	OneOf       []*OpenAPISchema          `json:"oneOf,omitempty"`
+}
+
+// OpenAPIComponents holds the security schemes and schemas referenced by the operations.
+type OpenAPIComponents struct {
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes"`
+	Schemas         map[string]*OpenAPISchema        `json:"schemas"`
+}
+
+// OpenAPIErrorCode is a code reported in the "code" field of error responses, with the status it comes with.
+type OpenAPIErrorCode struct {
+	Code        string
+	Status      int
+	Description string
+}
+
+// OpenAPISecurityScheme describes how callers authenticate.
+type OpenAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// NewOpenAPIDocument builds the OpenAPI document of the routes a user with the given role passes,
+// so that generated clients do not contain routes the role is forbidden to call. The error codes are
+// documented in the schema of the error responses.
+func NewOpenAPIDocument(version string, policies []RoutePolicy, role string, errorCodes []OpenAPIErrorCode) *OpenAPIDocument {
+	document := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info: OpenAPIInfo{
+			Title:       "KitaDoc API",
+			Version:     version,
+			Description: "Routes available to the role " + role,
+		},
+		Paths: make(map[string]map[string]OpenAPIOperation),
+		Components: OpenAPIComponents{
+			SecuritySchemes: map[string]OpenAPISecurityScheme{
+				bearerAuthScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+			Schemas: openAPIErrorSchemas(errorCodes),
+		},
+	}
+
+	for _, policy := range policies {
+		if policy.Method == "" || (policy.Access != RouteAccessPublic && !slices.Contains(policy.AllowedRoles, role)) {
+			continue
+		}
+		path, parameters := openAPIPath(policy.Path)
+		operation := OpenAPIOperation{
+			OperationID: openAPIOperationID(policy.Method, policy.Path),
+			Parameters:  parameters,
+			Security:    []map[string][]string{},
+			Responses: map[string]OpenAPIResponse{
+				"default": {Description: "Response of the route"},
+				"4XX":     openAPIErrorResponse("Error of the request, the code tells which one"),
+				"5XX":     openAPIErrorResponse("Internal error, the request ID identifies it in the logs"),
+			},
+		}
+		if policy.Access != RouteAccessPublic {
+			operation.Security = []map[string][]string{{bearerAuthScheme: {}}}
+			operation.AllowedRoles = policy.AllowedRoles
+			operation.Responses["401"] = OpenAPIResponse{Description: "Missing or invalid token"}
+			if policy.TermsAcceptanceRequired {
+				operation.Responses["403"] = openAPIErrorResponse("The current terms of use have not been accepted")
+			}
+		}
+		if document.Paths[path] == nil {
+			document.Paths[path] = make(map[string]OpenAPIOperation)
+		}
+		document.Paths[path][strings.ToLower(policy.Method)] = operation
+	}
+	return document
+}
+
+// openAPIErrorResponse is an error response with a JSON body.
+func openAPIErrorResponse(description string) OpenAPIResponse {
+	return OpenAPIResponse{
+		Description: description,
+		Content:     map[string]OpenAPIMediaType{"application/json": {Schema: &OpenAPISchema{Ref: errorSchemaRef}}},
+	}
+}
+
+// openAPIErrorSchemas describes the body of error responses. Every code is listed with its status, sorted by
+// code so that the document does not change between requests.
+func openAPIErrorSchemas(errorCodes []OpenAPIErrorCode) map[string]*OpenAPISchema {
+	sorted := slices.SortedFunc(slices.Values(errorCodes), func(a, b OpenAPIErrorCode) int { return cmp.Compare(a.Code, b.Code) })
+	codes := make([]*OpenAPISchema, 0, len(sorted))
+	for _, errorCode := range sorted {
+		codes = append(codes, &OpenAPISchema{Const: errorCode.Code, Description: strconv.Itoa(errorCode.Status) + ": " + errorCode.Description})
+	}
+	return map[string]*OpenAPISchema{
+		"Error": {
+			Type:     "object",
+			Required: []string{"error"},
+			Properties: map[string]*OpenAPISchema{
+				"error":      {Type: "string", Description: "Description of the error, for developers"},
+				"code":       {Ref: "#/components/schemas/ErrorCode"},
+				"violations": {Type: "array", Items: &OpenAPISchema{Ref: "#/components/schemas/RuleViolation"}, Description: "The broken rules, with VALIDATION_FAILED"},
+				"request_id": {Type: "string", Description: "The ID to quote in support requests, with INTERNAL_ERROR"},
+				"version":    {Type: "integer", Description: "The version of the terms to accept, with TERMS_ACCEPTANCE_REQUIRED"},
+			},
+		},
+		"ErrorCode": {
+			Type:        "string",
+			Description: "Stable code of the error, clients match on it instead of the message",
+			OneOf:       codes,
+		},
+		"RuleViolation": {
+			Type:     "object",
+			Required: []string{"rule", "field", "message"},
+			Properties: map[string]*OpenAPISchema{
+				"rule":    {Type: "string"},
+				"field":   {Type: "string"},
+				"message": {Type: "string"},
+				"text":    {Type: "string", Description: "German text for users, set for field errors"},
+				"params":  {Type: "object"},
+			},
+		},
+	}
+}
+
+// openAPIPath converts a route pattern to an OpenAPI path and returns its path parameters.
+// Wildcards matching the remainder of the path, such as {name...}, become plain parameters.
+func openAPIPath(pattern string) (string, []OpenAPIParameter) {
+	var parameters []OpenAPIParameter
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.Trim(segment, "{}"), "...")
+		if name == "$" {
+			segments[i] = ""
+			continue
+		}
+		segments[i] = "{" + name + "}"
+		parameters = append(parameters, OpenAPIParameter{Name: name, In: "path", Required: true, Schema: map[string]string{"type": "string"}})
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+// openAPIOperationID derives a stable operation ID from the method and path, for example
+// getApiV1ChildrenByChildId for GET /api/v1/children/{child_id}.
+func openAPIOperationID(method string, pattern string) string {
+	var builder strings.Builder
+	builder.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(pattern, "/") {
+		if strings.HasPrefix(segment, "{") {
+			segment = strings.TrimSuffix(strings.Trim(segment, "{}"), "...")
+			if segment == "$" {
+				continue
+			}
+			builder.WriteString("By")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+			builder.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Outbox channels, each is delivered by its own deliverer.
+const (
+	OutboxChannelPush  = "push"
+	OutboxChannelEmail = "email"
+)
+
+// Outbox message states.
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusFailed    = "failed" // Given up after too many attempts or undeliverable
+)
+
+// OutboxMessage is a message to another system that is delivered by the outbox dispatcher after the change
+// that caused it has been committed. Delivery is at least once.
+type OutboxMessage struct {
+	ID            int             `json:"id"`
+	Channel       string          `json:"channel"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at"`
+}
+
+// PushOutboxPayload is the payload of a push notification message.
+// The recipient is resolved to devices on delivery, so that the preferences at that time apply.
+type PushOutboxPayload struct {
+	UserID       *int         `json:"user_id,omitempty"`
+	TeacherID    *int         `json:"teacher_id,omitempty"`
+	Notification Notification `json:"notification"`
+}
+
+// EmailOutboxPayload is the payload of an email message, a plain text mail to a single recipient.
+type EmailOutboxPayload struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
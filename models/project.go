@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Project plans the activities of a period for one or more groups, e.g. a project week on autumn. Its goals are
+// mapped to categories, documentation entries observed in the project refer to it.
+type Project struct {
+	ID          int           `json:"id"`
+	Title       string        `json:"title" validate:"required,max=200"`
+	Description *string       `json:"description" validate:"omitempty,max=2000"`
+	StartDate   Date          `json:"start_date" validate:"required"`
+	EndDate     Date          `json:"end_date" validate:"required,gtefield=StartDate"` // Last day of the project
+	GroupIDs    []int         `json:"group_ids"`                                       // The participating groups
+	Goals       []ProjectGoal `json:"goals" validate:"dive"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// ProjectGoal is a goal of a project, e.g. "names the colours of leaves", mapped to the category its
+// observations are documented in.
+type ProjectGoal struct {
+	ID          int    `json:"id"` // Read only, goals are replaced when the project is updated
+	CategoryID  int    `json:"category_id" validate:"required"`
+	Description string `json:"description" validate:"required,max=500"`
+}
+
+// Covers reports whether the day lies in the period of the project.
+func (p *Project) Covers(day Date) bool {
+	return !day.Before(p.StartDate.Time) && !day.After(p.EndDate.Time)
+}
+
+// ProjectSummary sums up the documentation of a project.
+type ProjectSummary struct {
+	ProjectID          int                  `json:"project_id"`
+	Title              string               `json:"title"`
+	StartDate          Date                 `json:"start_date"`
+	EndDate            Date                 `json:"end_date"`
+	EntryCount         int                  `json:"entry_count"`
+	ApprovedEntryCount int                  `json:"approved_entry_count"`
+	ChildCount         int                  `json:"child_count"` // Children with at least one entry
+	Goals              []ProjectGoalSummary `json:"goals"`
+	// EntriesWithoutGoal counts the entries in a category no goal of the project is mapped to.
+	EntriesWithoutGoal int                   `json:"entries_without_goal"`
+	Groups             []ProjectGroupSummary `json:"groups"`
+}
+
+// ProjectGoalSummary counts the entries documented for a goal of a project.
+type ProjectGoalSummary struct {
+	GoalID       int    `json:"goal_id"`
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Description  string `json:"description"`
+	EntryCount   int    `json:"entry_count"`
+	ChildCount   int    `json:"child_count"`
+}
+
+// ProjectGroupSummary tells how many of the current children of a participating group were documented in a project.
+type ProjectGroupSummary struct {
+	GroupID            int    `json:"group_id"`
+	GroupName          string `json:"group_name"`
+	ChildCount         int    `json:"child_count"`
+	DocumentedChildren int    `json:"documented_children"`
+}
+
+// ValidateProject validates the Project struct.
+func ValidateProject(project Project) error {
+	validate := NewValidator()
+	return validate.Struct(project)
+}
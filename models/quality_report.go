@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// QualityReport is the monthly documentation quality report for the management of the facility, for the whole
+// facility, per group and per teacher. It is generated as a PDF on demand and archived for every completed month.
+type QualityReport struct {
+	Month       string           `json:"month"` // YYYY-MM
+	PeriodStart Date             `json:"period_start"`
+	PeriodEnd   Date             `json:"period_end"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Facility    QualityFigures   `json:"facility"`
+	Groups      []GroupQuality   `json:"groups"`
+	Teachers    []TeacherQuality `json:"teachers"`
+}
+
+// QualityFigures are the documentation quality figures of a month.
+type QualityFigures struct {
+	ChildrenAssigned   int `json:"children_assigned"`
+	ChildrenDocumented int `json:"children_documented"` // Children with an observation in the month
+	// Coverage is the share of documented children in percent, nil without children.
+	Coverage        *float64 `json:"coverage"`
+	EntriesWritten  int      `json:"entries_written"`
+	EntriesApproved int      `json:"entries_approved"`
+	// MedianApprovalHours is the median time from submitting an entry to its approval, nil without approvals.
+	MedianApprovalHours *float64 `json:"median_approval_hours"`
+	RemindersSent       int      `json:"reminders_sent"`
+	// RemindersIgnored are the reminders not followed by a new entry of the teacher within a week.
+	RemindersIgnored int `json:"reminders_ignored"`
+}
+
+// GroupQuality are the quality figures of the children of a group, reminders count for its staff.
+type GroupQuality struct {
+	GroupID int    `json:"group_id"`
+	Name    string `json:"name"`
+	QualityFigures
+}
+
+// TeacherQuality are the quality figures of the children assigned to a teacher and the entries of the teacher.
+type TeacherQuality struct {
+	TeacherID int    `json:"teacher_id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	QualityFigures
+}
+
+// ArchivedQualityReport is the PDF of the quality report of a completed month.
+type ArchivedQualityReport struct {
+	Month       string    `json:"month"`
+	DocumentID  string    `json:"document_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	SizeBytes   int       `json:"size_bytes"`
+	Content     []byte    `json:"-"` // Only loaded for downloads
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Kinds of uploads that are scanned for malware.
+const (
+	UploadKindAudio          = "audio_recording"
+	UploadKindPhoto          = "gallery_photo"
+	UploadKindChildDocument  = "child_document"
+	UploadKindDocumentLogo   = "document_logo"
+	UploadKindChildrenImport = "children_import"
+)
+
+// QuarantinedUpload is an uploaded file the virus scanner rejected. It is kept apart from the stored files so
+// that an admin can have it analysed before deleting it.
+type QuarantinedUpload struct {
+	ID               int       `json:"id"`
+	Kind             string    `json:"kind"`
+	FileName         string    `json:"file_name" pii:"true"`
+	Signature        string    `json:"signature"` // Name of the malware the scanner found
+	SizeBytes        int       `json:"size_bytes"`
+	UploadedByUserID *int      `json:"uploaded_by_user_id"` // Nil if the user was deleted
+	Content          []byte    `json:"-"`
+	QuarantinedAt    time.Time `json:"quarantined_at"`
+}
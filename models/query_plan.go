@@ -0,0 +1,11 @@
+package models
+
+// QueryPlan is how SQLite executes one of the statements the application runs most often.
+type QueryPlan struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	// Steps are the lines of EXPLAIN QUERY PLAN (EXPLAIN on PostgreSQL), indented by their depth in the plan.
+	Steps []string `json:"steps"`
+	// FullScan tells whether a table is read completely instead of through an index.
+	FullScan bool `json:"full_scan"`
+}
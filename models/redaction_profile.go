@@ -0,0 +1,37 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// RedactionProfile describes which information is left out of a generated child report,
+// e.g. for copies handed to schools.
+type RedactionProfile struct {
+	ID                   int       `json:"id"`
+	Name                 string    `json:"name" validate:"required,min=1,max=100"`
+	Description          *string   `json:"description"`
+	HideChildLastName    bool      `json:"hide_child_last_name"`
+	HideBirthdate        bool      `json:"hide_birthdate"`
+	HideAdmissionDate    bool      `json:"hide_admission_date"`
+	HideSchoolEnrollment bool      `json:"hide_school_enrollment"`
+	HideTeacherNames     bool      `json:"hide_teacher_names"`
+	HideKitaContact      bool      `json:"hide_kita_contact"`
+	HideObservationDates bool      `json:"hide_observation_dates"`
+	HideSupportProviders bool      `json:"hide_support_providers"` // Leaves out the Inklusion/Förderung section
+	ExcludedCategoryIDs  []int     `json:"excluded_category_ids"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// ValidateRedactionProfile validates the RedactionProfile struct.
+func ValidateRedactionProfile(profile RedactionProfile) error {
+	validate := NewValidator()
+	return validate.Struct(profile)
+}
+
+// ExcludesCategory reports whether entries of the given category are left out of the report.
+// A nil profile excludes nothing.
+func (p *RedactionProfile) ExcludesCategory(categoryID int) bool {
+	return p != nil && slices.Contains(p.ExcludedCategoryIDs, categoryID)
+}
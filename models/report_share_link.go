@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Outcomes of an attempt to open a share link.
+const (
+	ShareLinkAccessGranted   = "granted"
+	ShareLinkAccessWrongCode = "wrong_code"
+	ShareLinkAccessExpired   = "expired"
+	ShareLinkAccessRevoked   = "revoked"
+)
+
+// ReportShareLink is an expiring read-only link to a single archived report, protected by an access code that is
+// passed on separately, e.g. by phone.
+type ReportShareLink struct {
+	ID       int `json:"id"`
+	ReportID int `json:"report_id"`
+	// Recipient describes who the link was handed to, e.g. "Frühförderstelle Musterstadt".
+	Recipient       string     `json:"recipient" validate:"required,min=1,max=200" pii:"true"`
+	CreatedByUserID *int       `json:"created_by_user_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+	// Accesses and LastAccessedAt are read only and count the granted accesses.
+	Accesses       int        `json:"accesses"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+	// TokenHash is the SHA-256 hash of the token, the token itself is only returned once on creation.
+	TokenHash string `json:"-"`
+	// AccessCodeHash is the bcrypt hash of the access code, the code itself is only returned once on creation.
+	AccessCodeHash string `json:"-"`
+}
+
+// IsActive reports whether the link can still be opened.
+func (link *ReportShareLink) IsActive(now time.Time) bool {
+	return link.RevokedAt == nil && now.Before(link.ExpiresAt)
+}
+
+// ReportShareLinkCreated is returned when a share link is created, it is the only time the token and the access
+// code can be read.
+type ReportShareLinkCreated struct {
+	ShareLink  *ReportShareLink `json:"share_link"`
+	Token      string           `json:"token"`
+	AccessCode string           `json:"access_code"`
+	// ShareURL is the configured share page with the token appended, empty if no share page is configured.
+	ShareURL string `json:"share_url,omitempty"`
+}
+
+// ReportShareLinkAccess records an attempt to open a share link.
+type ReportShareLinkAccess struct {
+	ID          int       `json:"id"`
+	ShareLinkID int       `json:"share_link_id"`
+	AccessedAt  time.Time `json:"accessed_at"`
+	Outcome     string    `json:"outcome"`
+}
+
+// ValidateReportShareLink validates the ReportShareLink struct.
+func ValidateReportShareLink(link ReportShareLink) error {
+	validate := NewValidator()
+	return validate.Struct(link)
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ResumableUpload is a file uploaded in chunks, so that an upload interrupted by a weak connection continues
+// where it stopped instead of starting over. Once all chunks have been received, the file is passed to the
+// upload endpoint of its kind by its ID.
+type ResumableUpload struct {
+	ID              string    `json:"upload_id"`
+	Kind            string    `json:"kind" validate:"required,oneof=audio_recording gallery_photo child_document"`
+	FileName        string    `json:"file_name" validate:"required,max=255" pii:"true"`
+	ContentType     string    `json:"content_type" validate:"required,max=100"`
+	SizeBytes       int       `json:"size_bytes" validate:"required,gt=0"`
+	SHA256          string    `json:"sha256" validate:"required,len=64,hexadecimal"` // Checksum of the whole file
+	Offset          int       `json:"offset"`                                        // Number of bytes received
+	CreatedByUserID int       `json:"created_by_user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	Content         []byte    `json:"-"` // Set once the upload is complete
+}
+
+// Complete reports whether all chunks of the upload have been received.
+func (upload *ResumableUpload) Complete() bool {
+	return upload.Offset == upload.SizeBytes
+}
+
+// ValidateResumableUpload validates the ResumableUpload struct.
+func ValidateResumableUpload(upload ResumableUpload) error {
+	validate := NewValidator()
+	return validate.Struct(upload)
+}
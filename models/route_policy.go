@@ -0,0 +1,20 @@
+package models
+
+// RouteAccess describes which callers a route admits.
+type RouteAccess string
+
+const (
+	RouteAccessPublic        RouteAccess = "public"        // No authentication required
+	RouteAccessAuthenticated RouteAccess = "authenticated" // Any logged-in user
+	RouteAccessRole          RouteAccess = "role"          // Users with the required role, admins always pass
+)
+
+// RoutePolicy is the effective authorization of a single route.
+type RoutePolicy struct {
+	Method                  string      `json:"method"`
+	Path                    string      `json:"path"`
+	Access                  RouteAccess `json:"access"`
+	RequiredRole            string      `json:"required_role,omitempty"`
+	AllowedRoles            []string    `json:"allowed_roles"`             // Roles that pass the policy, empty for public routes
+	TermsAcceptanceRequired bool        `json:"terms_acceptance_required"` // The current terms of use must have been accepted
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// School is an entry of the school directory: a school children are expected to enroll at,
+// with the contact reports are handed over to.
+type School struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name" validate:"required,min=1,max=200"`
+	Street        *string   `json:"street" validate:"omitempty,max=200"`
+	HouseNumber   *string   `json:"house_number" validate:"omitempty,max=20"`
+	PostalCode    *string   `json:"postal_code" validate:"omitempty,max=20"`
+	City          *string   `json:"city" validate:"omitempty,max=100"`
+	ContactPerson *string   `json:"contact_person" validate:"omitempty,max=200"`
+	Email         *string   `json:"email" validate:"omitempty,email,max=200"`
+	PhoneNumber   *string   `json:"phone_number" validate:"omitempty,max=50"`
+	ChildIDs      []int     `json:"child_ids"` // Read only, changed by linking children
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ValidateSchool validates the School struct.
+func ValidateSchool(school School) error {
+	validate := NewValidator()
+	return validate.Struct(school)
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// RolloverRequest describes the changes applied when moving on to a new school year.
+type RolloverRequest struct {
+	RolloverDate time.Time `json:"rollover_date" validate:"required"`
+	// DepartingChildIDs lists the children leaving the facility. When omitted, all children whose
+	// expected school enrollment is on or before the rollover date are archived.
+	DepartingChildIDs []int `json:"departing_child_ids"`
+	// TeacherMapping moves open assignments from the key teacher to the value teacher.
+	TeacherMapping map[int]int `json:"teacher_mapping"`
+	// DryRun computes the summary without persisting any change.
+	DryRun bool `json:"dry_run"`
+}
+
+// ValidateRolloverRequest validates the RolloverRequest struct.
+func ValidateRolloverRequest(request RolloverRequest) error {
+	validate := NewValidator()
+	return validate.Struct(request)
+}
+
+// RolloverSummary lists all changes made by a school year rollover.
+type RolloverSummary struct {
+	RolloverDate        time.Time    `json:"rollover_date"`
+	DryRun              bool         `json:"dry_run"`
+	ArchivedChildIDs    []int        `json:"archived_child_ids"`
+	EndedAssignments    []Assignment `json:"ended_assignments"`
+	CreatedAssignments  []Assignment `json:"created_assignments"`
+	ResetPreschoolerIDs []int        `json:"reset_preschooler_ids"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Levels of the status banner, the frontend styles the banner by its level.
+const (
+	BannerLevelInfo        = "info"
+	BannerLevelWarning     = "warning"
+	BannerLevelMaintenance = "maintenance"
+)
+
+// StatusBanner is a message the admins show to all users, also before login, e.g. for planned maintenance.
+type StatusBanner struct {
+	Message         string     `json:"message" validate:"required,max=500"`
+	Level           string     `json:"level" validate:"required,oneof=info warning maintenance"`
+	StartsAt        *time.Time `json:"starts_at"` // Nil shows the banner right away
+	EndsAt          *time.Time `json:"ends_at"`   // Nil shows it until it is removed
+	UpdatedByUserID *int       `json:"updated_by_user_id"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// ValidateStatusBanner validates the StatusBanner struct.
+func ValidateStatusBanner(banner StatusBanner) error {
+	validate := NewValidator()
+	return validate.Struct(banner)
+}
+
+// IsActive reports whether the banner is shown at the given time.
+func (banner *StatusBanner) IsActive(now time.Time) bool {
+	if banner.StartsAt != nil && now.Before(*banner.StartsAt) {
+		return false
+	}
+	return banner.EndsAt == nil || now.Before(*banner.EndsAt)
+}
+
+// BuildInfo identifies the build of the running server, for support requests.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`     // Git commit the server was built from, if known
+	BuildTime string `json:"build_time,omitempty"` // RFC 3339, if known
+	GoVersion string `json:"go_version,omitempty"`
+}
+
+// Generator names the server in the metadata of generated documents, e.g. "Kitadoc 1.4.0 (3fe1609a2b4c)".
+func (info BuildInfo) Generator() string {
+	if info.Commit == "" {
+		return "Kitadoc " + info.Version
+	}
+	return "Kitadoc " + info.Version + " (" + info.Commit + ")"
+}
+
+// SystemStatus is what the frontend needs to know about the server before login.
+type SystemStatus struct {
+	BuildInfo
+	Banner   *StatusBanner   `json:"banner"` // Nil unless a banner is active
+	Features map[string]bool `json:"features"`
+}
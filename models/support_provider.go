@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Support provider types.
+const (
+	SupportProviderTypeSpeechTherapy       = "speech_therapy"       // Logopädie
+	SupportProviderTypeOccupationalTherapy = "occupational_therapy" // Ergotherapie
+	SupportProviderTypePhysiotherapy       = "physiotherapy"        // Physiotherapie
+	SupportProviderTypeEarlyIntervention   = "early_intervention"   // Frühförderung
+	SupportProviderTypeOther               = "other"
+)
+
+// supportProviderTypeLabels are the German names of the provider types used in reports.
+var supportProviderTypeLabels = map[string]string{
+	SupportProviderTypeSpeechTherapy:       "Logopädie",
+	SupportProviderTypeOccupationalTherapy: "Ergotherapie",
+	SupportProviderTypePhysiotherapy:       "Physiotherapie",
+	SupportProviderTypeEarlyIntervention:   "Frühförderung",
+	SupportProviderTypeOther:               "Sonstige Förderung",
+}
+
+// SupportProviderTypeLabel returns the German name of a provider type.
+func SupportProviderTypeLabel(providerType string) string {
+	if label, ok := supportProviderTypeLabels[providerType]; ok {
+		return label
+	}
+	return providerType
+}
+
+// SupportProvider is an entry of the directory of external therapy and support providers, e.g. a Logopädie practice
+// or a Frühförderstelle.
+type SupportProvider struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name" validate:"required,min=1,max=200"`
+	ProviderType  string    `json:"provider_type" validate:"required,oneof=speech_therapy occupational_therapy physiotherapy early_intervention other"`
+	ContactPerson *string   `json:"contact_person" validate:"omitempty,max=200"`
+	Email         *string   `json:"email" validate:"omitempty,email,max=200"`
+	PhoneNumber   *string   `json:"phone_number" validate:"omitempty,max=50"`
+	Address       *string   `json:"address" validate:"omitempty,max=300"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ChildSupport records that a child is supported by an external provider. Only supports with a reference to the
+// consent of the parents, e.g. the date or file number of the signed form, are mentioned in reports.
+type ChildSupport struct {
+	ID               int        `json:"id"`
+	ChildID          int        `json:"child_id"` // Set from the route
+	ProviderID       int        `json:"provider_id" validate:"required"`
+	ProviderName     string     `json:"provider_name"` // Read only
+	ProviderType     string     `json:"provider_type"` // Read only
+	ConsentReference *string    `json:"consent_reference" validate:"omitempty,max=200" pii:"true"`
+	StartDate        time.Time  `json:"start_date" validate:"required"`
+	EndDate          *time.Time `json:"end_date" validate:"omitempty,gtfield=StartDate"`            // Optional, but if present, must be after StartDate
+	AppointmentNotes *string    `json:"appointment_notes" validate:"omitempty,max=2000" pii:"true"` // Internal, never part of reports
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// HasConsent reports whether the parents consented to sharing the support, which is required to mention it in reports.
+func (s *ChildSupport) HasConsent() bool {
+	return s.ConsentReference != nil && *s.ConsentReference != ""
+}
+
+// ValidateSupportProvider validates the SupportProvider struct.
+func ValidateSupportProvider(provider SupportProvider) error {
+	validate := NewValidator()
+	return validate.Struct(provider)
+}
+
+// ValidateChildSupport validates the ChildSupport struct.
+func ValidateChildSupport(support ChildSupport) error {
+	validate := NewValidator()
+	return validate.Struct(support)
+}
@@ -1,34 +1,40 @@
 package models
 
-import (
-	"time"
-
-	"github.com/go-playground/validator/v10"
-)
+import "time"
 
 // Teacher represents a teacher in the system.
 type Teacher struct {
-	ID        int       `json:"id"`
-	FirstName string    `json:"first_name" validate:"required,min=1,max=100" pii:"true"`
-	LastName  string    `json:"last_name" validate:"required,min=1,max=100" pii:"true"`
-	Username  string    `json:"username" validate:"required,min=1,max=100" pii:"true"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name" validate:"required,min=1,max=100" pii:"true"`
+	LastName  string `json:"last_name" validate:"required,min=1,max=100" pii:"true"`
+	Username  string `json:"username" validate:"required,min=1,max=100" pii:"true"`
+	// DeactivatedAt is read only, deactivated teachers are hidden from pickers and get no new assignments.
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	// Usage is read only and only set when listing teachers.
+	Usage     *UsageCounts `json:"usage,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
 }
 
 // TeacherDB is a struct that matches the teachers table in the database.
 // PII fields are stored as encrypted strings.
 type TeacherDB struct {
-	ID        int
-	FirstName string
-	LastName  string
-	Username  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            int
+	FirstName     string
+	LastName      string
+	Username      string
+	DeactivatedAt *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// IsActive reports whether the teacher has not been deactivated.
+func (teacher *Teacher) IsActive() bool {
+	return teacher.DeactivatedAt == nil
 }
 
 // ValidateTeacher validates the Teacher struct.
 func ValidateTeacher(teacher Teacher) error {
-	validate := validator.New()
+	validate := NewValidator()
 	return validate.Struct(teacher)
 }
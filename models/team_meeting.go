@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// TeamMeeting is the minutes of a team meeting (Teambesprechung), e.g. a case discussion about a child.
+type TeamMeeting struct {
+	ID                 int          `json:"id"`
+	MeetingDate        Date         `json:"meeting_date" validate:"required"`
+	Title              string       `json:"title" validate:"required,max=200"`
+	Minutes            string       `json:"minutes" validate:"required,max=20000" pii:"true"`
+	AttendeeTeacherIDs []int        `json:"attendee_teacher_ids"`
+	ChildIDs           []int        `json:"child_ids"`           // The children discussed
+	ActionItems        []ActionItem `json:"action_items"`        // Read only, changed through the action item endpoints
+	RecordedByUserID   *int         `json:"recorded_by_user_id"` // Read only
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
+}
+
+// ActionItem is a task agreed on in a team meeting, assigned to a teacher, e.g. to talk to the parents of a child.
+type ActionItem struct {
+	ID          int        `json:"id"`
+	MeetingID   int        `json:"meeting_id"` // Set from the route
+	Description string     `json:"description" validate:"required,max=1000" pii:"true"`
+	TeacherID   int        `json:"teacher_id" validate:"required"`
+	ChildID     *int       `json:"child_id"` // The child the task is about, if any
+	DueDate     Date       `json:"due_date" validate:"required"`
+	CompletedAt *time.Time `json:"completed_at"` // Read only, set when the item is completed
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// Overdue is set when items are read, for open items due before today.
+	Overdue bool `json:"overdue"`
+}
+
+// IsOpen reports whether the action item has not been completed yet.
+func (a *ActionItem) IsOpen() bool {
+	return a.CompletedAt == nil
+}
+
+// ValidateTeamMeeting validates the TeamMeeting struct.
+func ValidateTeamMeeting(meeting TeamMeeting) error {
+	validate := NewValidator()
+	return validate.Struct(meeting)
+}
+
+// ValidateActionItem validates the ActionItem struct.
+func ValidateActionItem(item ActionItem) error {
+	validate := NewValidator()
+	return validate.Struct(item)
+}
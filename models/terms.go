@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// TermsVersion is a published version of the terms of use together with the privacy policy. Every user has
+// to accept the current version before they can use the API.
+type TermsVersion struct {
+	Version           int       `json:"version"`
+	TermsOfUse        string    `json:"terms_of_use" validate:"required"`
+	PrivacyPolicy     string    `json:"privacy_policy" validate:"required"`
+	ChangeSummary     string    `json:"change_summary" validate:"max=1000"` // What changed compared to the previous version
+	PublishedByUserID *int      `json:"published_by_user_id"`
+	PublishedAt       time.Time `json:"published_at"`
+}
+
+// ValidateTermsVersion validates the TermsVersion struct.
+func ValidateTermsVersion(version TermsVersion) error {
+	validate := NewValidator()
+	return validate.Struct(version)
+}
+
+// TermsAcceptance records that a user has accepted a terms version.
+type TermsAcceptance struct {
+	UserID     int       `json:"user_id"`
+	Version    int       `json:"version"`
+	AcceptedAt time.Time `json:"accepted_at"`
+}
+
+// TermsStatus tells a user which terms version is current and which one they accepted last.
+type TermsStatus struct {
+	Current            *TermsVersion    `json:"current"` // Nil as long as no terms have been published
+	LastAccepted       *TermsAcceptance `json:"last_accepted"`
+	AcceptanceRequired bool             `json:"acceptance_required"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// HealthCheck is the recorded result of a periodic health check of the server.
+type HealthCheck struct {
+	ID        int       `json:"id"`
+	CheckedAt time.Time `json:"checked_at"`
+	Healthy   bool      `json:"healthy"`
+	LatencyMS int       `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"` // Why the check failed
+}
+
+// UptimeIncident is an annotation of the admins explaining an outage or degradation, e.g. a maintenance window.
+type UptimeIncident struct {
+	ID              int        `json:"id"`
+	StartedAt       time.Time  `json:"started_at" validate:"required"`
+	EndedAt         *time.Time `json:"ended_at"` // Nil while the incident is ongoing
+	Note            string     `json:"note" validate:"required,max=1000"`
+	CreatedByUserID *int       `json:"created_by_user_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// ValidateUptimeIncident validates the UptimeIncident struct.
+func ValidateUptimeIncident(incident UptimeIncident) error {
+	validate := NewValidator()
+	return validate.Struct(incident)
+}
+
+// UptimeDay is the availability of the server on a day of the facility.
+type UptimeDay struct {
+	Date           Date `json:"date"`
+	Checks         int  `json:"checks"`
+	HealthyChecks  int  `json:"healthy_checks"`
+	ExpectedChecks int  `json:"expected_checks"` // Checks that would have run if the server had been up all day
+	// AvailabilityPercent is the share of expected checks that were healthy, nil before monitoring started.
+	AvailabilityPercent *float64 `json:"availability_percent"`
+}
+
+// UptimeReport is the availability history of the server over a range of days, oldest day first.
+type UptimeReport struct {
+	From                Date             `json:"from"`
+	To                  Date             `json:"to"`
+	AvailabilityPercent *float64         `json:"availability_percent"` // Over all days, nil without any check
+	Days                []UptimeDay      `json:"days"`
+	Incidents           []UptimeIncident `json:"incidents"` // Incidents overlapping the range
+	LastCheck           *HealthCheck     `json:"last_check"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// UsageCounts counts the records that use a category, teacher or group, so that the admin UI can warn
+// before archiving or deleting it. Counts that do not apply to the kind of reference data stay zero.
+type UsageCounts struct {
+	Entries         int `json:"entries"`          // Documentation entries of the category, by the teacher or of the children of the group
+	OpenAssignments int `json:"open_assignments"` // Assignments without an end date, of the teacher or of the children of the group
+	Children        int `json:"children"`         // Children with entries in the category, assigned to the teacher or in the group
+}
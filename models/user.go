@@ -1,10 +1,6 @@
 package models
 
-import (
-	"time"
-
-	"github.com/go-playground/validator/v10"
-)
+import "time"
 
 // User represents a user in the system.
 type User struct {
@@ -30,6 +26,6 @@ type UserDB struct {
 
 // ValidateUser validates the User struct.
 func ValidateUser(user User) error {
-	validate := validator.New()
+	validate := NewValidator()
 	return validate.Struct(user)
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// NewValidator creates a validator that reports fields by their JSON names, so that validation errors
+// can point clients to the field they sent. Dates are validated as their time, so that rules like required
+// and gtfield apply to them.
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	validate.RegisterCustomTypeFunc(func(value reflect.Value) any {
+		return value.Interface().(Date).Time
+	}, Date{})
+	return validate
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ValidationRules holds the facility-specific business rules that documentation entries
+// and assignments have to satisfy. The zero value matches the built-in behaviour.
+type ValidationRules struct {
+	AllowFutureObservationDates bool `json:"allow_future_observation_dates"`
+	// MaxObservationAgeDays rejects observations older than this many days. 0 disables the rule.
+	MaxObservationAgeDays int `json:"max_observation_age_days" validate:"min=0"`
+	// MinObservationLength is the minimum length of an observation description in characters.
+	// Descriptions always need at least 10 characters, lower values have no effect.
+	MinObservationLength int `json:"min_observation_length" validate:"min=0"`
+	// MinWordCount is the minimum number of words of an observation description, hashtags not counted.
+	MinWordCount int `json:"min_word_count" validate:"min=0"`
+	// DuplicateWindowDays rejects an observation whose text was already recorded for the same child
+	// within this many days of its observation date. 0 disables the rule.
+	DuplicateWindowDays int `json:"duplicate_window_days" validate:"min=0"`
+	// RequiredTagsByCategory lists the hashtags (e.g. "#sprache") an observation text of
+	// the given category has to contain.
+	RequiredTagsByCategory     map[int][]string `json:"required_tags_by_category"`
+	AllowFutureAssignmentStart bool             `json:"allow_future_assignment_start"`
+	// OpeningHours are the regular opening times of the facility. Observations on closed days
+	// are accepted with a warning, as they usually have a typo in the date. Empty disables the check.
+	OpeningHours []OpeningHours `json:"opening_hours" validate:"dive"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// OpeningHours are the opening times of the facility on a weekday. Weekdays without opening hours are closed.
+type OpeningHours struct {
+	Weekday time.Weekday `json:"weekday" validate:"min=0,max=6"` // 0 is Sunday
+	Opens   string       `json:"opens" validate:"required,datetime=15:04"`
+	Closes  string       `json:"closes" validate:"required,datetime=15:04"`
+}
+
+// ValidateValidationRules validates the ValidationRules struct.
+func ValidateValidationRules(rules ValidationRules) error {
+	validate := NewValidator()
+	return validate.Struct(rules)
+}
@@ -0,0 +1,913 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: kitadoc/v1/reporting.proto
+
+package kitadocv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Child struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Id                       int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FirstName                string                 `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName                 string                 `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Birthdate                *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=birthdate,proto3" json:"birthdate,omitempty"`
+	AdmissionDate            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=admission_date,json=admissionDate,proto3" json:"admission_date,omitempty"`
+	ExpectedSchoolEnrollment *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expected_school_enrollment,json=expectedSchoolEnrollment,proto3" json:"expected_school_enrollment,omitempty"`
+	IsPreschooler            bool                   `protobuf:"varint,7,opt,name=is_preschooler,json=isPreschooler,proto3" json:"is_preschooler,omitempty"`
+	CreatedAt                *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt                *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *Child) Reset() {
+	*x = Child{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Child) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Child) ProtoMessage() {}
+
+func (x *Child) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Child.ProtoReflect.Descriptor instead.
+func (*Child) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Child) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Child) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *Child) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *Child) GetBirthdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Birthdate
+	}
+	return nil
+}
+
+func (x *Child) GetAdmissionDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AdmissionDate
+	}
+	return nil
+}
+
+func (x *Child) GetExpectedSchoolEnrollment() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpectedSchoolEnrollment
+	}
+	return nil
+}
+
+func (x *Child) GetIsPreschooler() bool {
+	if x != nil {
+		return x.IsPreschooler
+	}
+	return false
+}
+
+func (x *Child) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Child) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type DocumentationEntry struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Id                     int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChildId                int64                  `protobuf:"varint,2,opt,name=child_id,json=childId,proto3" json:"child_id,omitempty"`
+	TeacherId              int64                  `protobuf:"varint,3,opt,name=teacher_id,json=teacherId,proto3" json:"teacher_id,omitempty"`
+	CategoryId             int64                  `protobuf:"varint,4,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	ObservationDate        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=observation_date,json=observationDate,proto3" json:"observation_date,omitempty"`
+	ObservationDescription string                 `protobuf:"bytes,6,opt,name=observation_description,json=observationDescription,proto3" json:"observation_description,omitempty"`
+	// Values of the category's observation form, if the category has one.
+	StructuredData   *structpb.Struct       `protobuf:"bytes,7,opt,name=structured_data,json=structuredData,proto3" json:"structured_data,omitempty"`
+	IsApproved       bool                   `protobuf:"varint,8,opt,name=is_approved,json=isApproved,proto3" json:"is_approved,omitempty"`
+	ApprovedByUserId *int64                 `protobuf:"varint,9,opt,name=approved_by_user_id,json=approvedByUserId,proto3,oneof" json:"approved_by_user_id,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DocumentationEntry) Reset() {
+	*x = DocumentationEntry{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentationEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentationEntry) ProtoMessage() {}
+
+func (x *DocumentationEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentationEntry.ProtoReflect.Descriptor instead.
+func (*DocumentationEntry) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DocumentationEntry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DocumentationEntry) GetChildId() int64 {
+	if x != nil {
+		return x.ChildId
+	}
+	return 0
+}
+
+func (x *DocumentationEntry) GetTeacherId() int64 {
+	if x != nil {
+		return x.TeacherId
+	}
+	return 0
+}
+
+func (x *DocumentationEntry) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *DocumentationEntry) GetObservationDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ObservationDate
+	}
+	return nil
+}
+
+func (x *DocumentationEntry) GetObservationDescription() string {
+	if x != nil {
+		return x.ObservationDescription
+	}
+	return ""
+}
+
+func (x *DocumentationEntry) GetStructuredData() *structpb.Struct {
+	if x != nil {
+		return x.StructuredData
+	}
+	return nil
+}
+
+func (x *DocumentationEntry) GetIsApproved() bool {
+	if x != nil {
+		return x.IsApproved
+	}
+	return false
+}
+
+func (x *DocumentationEntry) GetApprovedByUserId() int64 {
+	if x != nil && x.ApprovedByUserId != nil {
+		return *x.ApprovedByUserId
+	}
+	return 0
+}
+
+func (x *DocumentationEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *DocumentationEntry) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CategoryCoverage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    int64                  `protobuf:"varint,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	CategoryName  string                 `protobuf:"bytes,2,opt,name=category_name,json=categoryName,proto3" json:"category_name,omitempty"`
+	EntryCount    int32                  `protobuf:"varint,3,opt,name=entry_count,json=entryCount,proto3" json:"entry_count,omitempty"`
+	LastEntryDate *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=last_entry_date,json=lastEntryDate,proto3" json:"last_entry_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryCoverage) Reset() {
+	*x = CategoryCoverage{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryCoverage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryCoverage) ProtoMessage() {}
+
+func (x *CategoryCoverage) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryCoverage.ProtoReflect.Descriptor instead.
+func (*CategoryCoverage) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CategoryCoverage) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *CategoryCoverage) GetCategoryName() string {
+	if x != nil {
+		return x.CategoryName
+	}
+	return ""
+}
+
+func (x *CategoryCoverage) GetEntryCount() int32 {
+	if x != nil {
+		return x.EntryCount
+	}
+	return 0
+}
+
+func (x *CategoryCoverage) GetLastEntryDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastEntryDate
+	}
+	return nil
+}
+
+// ChildStats describes how completely a child has been documented within a period.
+type ChildStats struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ChildId   int64                  `protobuf:"varint,1,opt,name=child_id,json=childId,proto3" json:"child_id,omitempty"`
+	ChildName string                 `protobuf:"bytes,2,opt,name=child_name,json=childName,proto3" json:"child_name,omitempty"`
+	// Share of categories with at least one observation in the period, in percent.
+	Score             int32                  `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	CategoriesTotal   int32                  `protobuf:"varint,4,opt,name=categories_total,json=categoriesTotal,proto3" json:"categories_total,omitempty"`
+	CategoriesCovered int32                  `protobuf:"varint,5,opt,name=categories_covered,json=categoriesCovered,proto3" json:"categories_covered,omitempty"`
+	EntryCount        int32                  `protobuf:"varint,6,opt,name=entry_count,json=entryCount,proto3" json:"entry_count,omitempty"`
+	LastEntryDate     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_entry_date,json=lastEntryDate,proto3" json:"last_entry_date,omitempty"`
+	LastEntryAgeDays  *int32                 `protobuf:"varint,8,opt,name=last_entry_age_days,json=lastEntryAgeDays,proto3,oneof" json:"last_entry_age_days,omitempty"`
+	PeriodStart       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=period_start,json=periodStart,proto3" json:"period_start,omitempty"`
+	PeriodEnd         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=period_end,json=periodEnd,proto3" json:"period_end,omitempty"`
+	Categories        []*CategoryCoverage    `protobuf:"bytes,11,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ChildStats) Reset() {
+	*x = ChildStats{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChildStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChildStats) ProtoMessage() {}
+
+func (x *ChildStats) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChildStats.ProtoReflect.Descriptor instead.
+func (*ChildStats) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChildStats) GetChildId() int64 {
+	if x != nil {
+		return x.ChildId
+	}
+	return 0
+}
+
+func (x *ChildStats) GetChildName() string {
+	if x != nil {
+		return x.ChildName
+	}
+	return ""
+}
+
+func (x *ChildStats) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ChildStats) GetCategoriesTotal() int32 {
+	if x != nil {
+		return x.CategoriesTotal
+	}
+	return 0
+}
+
+func (x *ChildStats) GetCategoriesCovered() int32 {
+	if x != nil {
+		return x.CategoriesCovered
+	}
+	return 0
+}
+
+func (x *ChildStats) GetEntryCount() int32 {
+	if x != nil {
+		return x.EntryCount
+	}
+	return 0
+}
+
+func (x *ChildStats) GetLastEntryDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastEntryDate
+	}
+	return nil
+}
+
+func (x *ChildStats) GetLastEntryAgeDays() int32 {
+	if x != nil && x.LastEntryAgeDays != nil {
+		return *x.LastEntryAgeDays
+	}
+	return 0
+}
+
+func (x *ChildStats) GetPeriodStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PeriodStart
+	}
+	return nil
+}
+
+func (x *ChildStats) GetPeriodEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PeriodEnd
+	}
+	return nil
+}
+
+func (x *ChildStats) GetCategories() []*CategoryCoverage {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type ListChildrenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChildrenRequest) Reset() {
+	*x = ListChildrenRequest{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChildrenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChildrenRequest) ProtoMessage() {}
+
+func (x *ListChildrenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChildrenRequest.ProtoReflect.Descriptor instead.
+func (*ListChildrenRequest) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{4}
+}
+
+type ListChildrenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Children      []*Child               `protobuf:"bytes,1,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChildrenResponse) Reset() {
+	*x = ListChildrenResponse{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChildrenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChildrenResponse) ProtoMessage() {}
+
+func (x *ListChildrenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChildrenResponse.ProtoReflect.Descriptor instead.
+func (*ListChildrenResponse) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListChildrenResponse) GetChildren() []*Child {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type GetChildRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChildId       int64                  `protobuf:"varint,1,opt,name=child_id,json=childId,proto3" json:"child_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChildRequest) Reset() {
+	*x = GetChildRequest{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChildRequest) ProtoMessage() {}
+
+func (x *GetChildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChildRequest.ProtoReflect.Descriptor instead.
+func (*GetChildRequest) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetChildRequest) GetChildId() int64 {
+	if x != nil {
+		return x.ChildId
+	}
+	return 0
+}
+
+type ListDocumentationEntriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChildId       int64                  `protobuf:"varint,1,opt,name=child_id,json=childId,proto3" json:"child_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDocumentationEntriesRequest) Reset() {
+	*x = ListDocumentationEntriesRequest{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDocumentationEntriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDocumentationEntriesRequest) ProtoMessage() {}
+
+func (x *ListDocumentationEntriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDocumentationEntriesRequest.ProtoReflect.Descriptor instead.
+func (*ListDocumentationEntriesRequest) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListDocumentationEntriesRequest) GetChildId() int64 {
+	if x != nil {
+		return x.ChildId
+	}
+	return 0
+}
+
+type ListDocumentationEntriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*DocumentationEntry  `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDocumentationEntriesResponse) Reset() {
+	*x = ListDocumentationEntriesResponse{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDocumentationEntriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDocumentationEntriesResponse) ProtoMessage() {}
+
+func (x *ListDocumentationEntriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDocumentationEntriesResponse.ProtoReflect.Descriptor instead.
+func (*ListDocumentationEntriesResponse) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListDocumentationEntriesResponse) GetEntries() []*DocumentationEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetDocumentationStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDocumentationStatsRequest) Reset() {
+	*x = GetDocumentationStatsRequest{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDocumentationStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDocumentationStatsRequest) ProtoMessage() {}
+
+func (x *GetDocumentationStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDocumentationStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetDocumentationStatsRequest) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{9}
+}
+
+type GetDocumentationStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Children      []*ChildStats          `protobuf:"bytes,1,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDocumentationStatsResponse) Reset() {
+	*x = GetDocumentationStatsResponse{}
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDocumentationStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDocumentationStatsResponse) ProtoMessage() {}
+
+func (x *GetDocumentationStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kitadoc_v1_reporting_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDocumentationStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetDocumentationStatsResponse) Descriptor() ([]byte, []int) {
+	return file_kitadoc_v1_reporting_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetDocumentationStatsResponse) GetChildren() []*ChildStats {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+var File_kitadoc_v1_reporting_proto protoreflect.FileDescriptor
+
+const file_kitadoc_v1_reporting_proto_rawDesc = "" +
+	"\n" +
+	"\x1akitadoc/v1/reporting.proto\x12\n" +
+	"kitadoc.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc7\x03\n" +
+	"\x05Child\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x02 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x03 \x01(\tR\blastName\x128\n" +
+	"\tbirthdate\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tbirthdate\x12A\n" +
+	"\x0eadmission_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\radmissionDate\x12X\n" +
+	"\x1aexpected_school_enrollment\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x18expectedSchoolEnrollment\x12%\n" +
+	"\x0eis_preschooler\x18\a \x01(\bR\risPreschooler\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xa4\x04\n" +
+	"\x12DocumentationEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bchild_id\x18\x02 \x01(\x03R\achildId\x12\x1d\n" +
+	"\n" +
+	"teacher_id\x18\x03 \x01(\x03R\tteacherId\x12\x1f\n" +
+	"\vcategory_id\x18\x04 \x01(\x03R\n" +
+	"categoryId\x12E\n" +
+	"\x10observation_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0fobservationDate\x127\n" +
+	"\x17observation_description\x18\x06 \x01(\tR\x16observationDescription\x12@\n" +
+	"\x0fstructured_data\x18\a \x01(\v2\x17.google.protobuf.StructR\x0estructuredData\x12\x1f\n" +
+	"\vis_approved\x18\b \x01(\bR\n" +
+	"isApproved\x122\n" +
+	"\x13approved_by_user_id\x18\t \x01(\x03H\x00R\x10approvedByUserId\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x16\n" +
+	"\x14_approved_by_user_id\"\xbd\x01\n" +
+	"\x10CategoryCoverage\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\x03R\n" +
+	"categoryId\x12#\n" +
+	"\rcategory_name\x18\x02 \x01(\tR\fcategoryName\x12\x1f\n" +
+	"\ventry_count\x18\x03 \x01(\x05R\n" +
+	"entryCount\x12B\n" +
+	"\x0flast_entry_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rlastEntryDate\"\x9f\x04\n" +
+	"\n" +
+	"ChildStats\x12\x19\n" +
+	"\bchild_id\x18\x01 \x01(\x03R\achildId\x12\x1d\n" +
+	"\n" +
+	"child_name\x18\x02 \x01(\tR\tchildName\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x05R\x05score\x12)\n" +
+	"\x10categories_total\x18\x04 \x01(\x05R\x0fcategoriesTotal\x12-\n" +
+	"\x12categories_covered\x18\x05 \x01(\x05R\x11categoriesCovered\x12\x1f\n" +
+	"\ventry_count\x18\x06 \x01(\x05R\n" +
+	"entryCount\x12B\n" +
+	"\x0flast_entry_date\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rlastEntryDate\x122\n" +
+	"\x13last_entry_age_days\x18\b \x01(\x05H\x00R\x10lastEntryAgeDays\x88\x01\x01\x12=\n" +
+	"\fperiod_start\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vperiodStart\x129\n" +
+	"\n" +
+	"period_end\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tperiodEnd\x12<\n" +
+	"\n" +
+	"categories\x18\v \x03(\v2\x1c.kitadoc.v1.CategoryCoverageR\n" +
+	"categoriesB\x16\n" +
+	"\x14_last_entry_age_days\"\x15\n" +
+	"\x13ListChildrenRequest\"E\n" +
+	"\x14ListChildrenResponse\x12-\n" +
+	"\bchildren\x18\x01 \x03(\v2\x11.kitadoc.v1.ChildR\bchildren\",\n" +
+	"\x0fGetChildRequest\x12\x19\n" +
+	"\bchild_id\x18\x01 \x01(\x03R\achildId\"<\n" +
+	"\x1fListDocumentationEntriesRequest\x12\x19\n" +
+	"\bchild_id\x18\x01 \x01(\x03R\achildId\"\\\n" +
+	" ListDocumentationEntriesResponse\x128\n" +
+	"\aentries\x18\x01 \x03(\v2\x1e.kitadoc.v1.DocumentationEntryR\aentries\"\x1e\n" +
+	"\x1cGetDocumentationStatsRequest\"S\n" +
+	"\x1dGetDocumentationStatsResponse\x122\n" +
+	"\bchildren\x18\x01 \x03(\v2\x16.kitadoc.v1.ChildStatsR\bchildren2\x86\x03\n" +
+	"\x10ReportingService\x12Q\n" +
+	"\fListChildren\x12\x1f.kitadoc.v1.ListChildrenRequest\x1a .kitadoc.v1.ListChildrenResponse\x12:\n" +
+	"\bGetChild\x12\x1b.kitadoc.v1.GetChildRequest\x1a\x11.kitadoc.v1.Child\x12u\n" +
+	"\x18ListDocumentationEntries\x12+.kitadoc.v1.ListDocumentationEntriesRequest\x1a,.kitadoc.v1.ListDocumentationEntriesResponse\x12l\n" +
+	"\x15GetDocumentationStats\x12(.kitadoc.v1.GetDocumentationStatsRequest\x1a).kitadoc.v1.GetDocumentationStatsResponseB,Z*kitadoc-backend/proto/kitadoc/v1;kitadocv1b\x06proto3"
+
+var (
+	file_kitadoc_v1_reporting_proto_rawDescOnce sync.Once
+	file_kitadoc_v1_reporting_proto_rawDescData []byte
+)
+
+func file_kitadoc_v1_reporting_proto_rawDescGZIP() []byte {
+	file_kitadoc_v1_reporting_proto_rawDescOnce.Do(func() {
+		file_kitadoc_v1_reporting_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_kitadoc_v1_reporting_proto_rawDesc), len(file_kitadoc_v1_reporting_proto_rawDesc)))
+	})
+	return file_kitadoc_v1_reporting_proto_rawDescData
+}
+
+var file_kitadoc_v1_reporting_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_kitadoc_v1_reporting_proto_goTypes = []any{
+	(*Child)(nil),                            // 0: kitadoc.v1.Child
+	(*DocumentationEntry)(nil),               // 1: kitadoc.v1.DocumentationEntry
+	(*CategoryCoverage)(nil),                 // 2: kitadoc.v1.CategoryCoverage
+	(*ChildStats)(nil),                       // 3: kitadoc.v1.ChildStats
+	(*ListChildrenRequest)(nil),              // 4: kitadoc.v1.ListChildrenRequest
+	(*ListChildrenResponse)(nil),             // 5: kitadoc.v1.ListChildrenResponse
+	(*GetChildRequest)(nil),                  // 6: kitadoc.v1.GetChildRequest
+	(*ListDocumentationEntriesRequest)(nil),  // 7: kitadoc.v1.ListDocumentationEntriesRequest
+	(*ListDocumentationEntriesResponse)(nil), // 8: kitadoc.v1.ListDocumentationEntriesResponse
+	(*GetDocumentationStatsRequest)(nil),     // 9: kitadoc.v1.GetDocumentationStatsRequest
+	(*GetDocumentationStatsResponse)(nil),    // 10: kitadoc.v1.GetDocumentationStatsResponse
+	(*timestamppb.Timestamp)(nil),            // 11: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),                  // 12: google.protobuf.Struct
+}
+var file_kitadoc_v1_reporting_proto_depIdxs = []int32{
+	11, // 0: kitadoc.v1.Child.birthdate:type_name -> google.protobuf.Timestamp
+	11, // 1: kitadoc.v1.Child.admission_date:type_name -> google.protobuf.Timestamp
+	11, // 2: kitadoc.v1.Child.expected_school_enrollment:type_name -> google.protobuf.Timestamp
+	11, // 3: kitadoc.v1.Child.created_at:type_name -> google.protobuf.Timestamp
+	11, // 4: kitadoc.v1.Child.updated_at:type_name -> google.protobuf.Timestamp
+	11, // 5: kitadoc.v1.DocumentationEntry.observation_date:type_name -> google.protobuf.Timestamp
+	12, // 6: kitadoc.v1.DocumentationEntry.structured_data:type_name -> google.protobuf.Struct
+	11, // 7: kitadoc.v1.DocumentationEntry.created_at:type_name -> google.protobuf.Timestamp
+	11, // 8: kitadoc.v1.DocumentationEntry.updated_at:type_name -> google.protobuf.Timestamp
+	11, // 9: kitadoc.v1.CategoryCoverage.last_entry_date:type_name -> google.protobuf.Timestamp
+	11, // 10: kitadoc.v1.ChildStats.last_entry_date:type_name -> google.protobuf.Timestamp
+	11, // 11: kitadoc.v1.ChildStats.period_start:type_name -> google.protobuf.Timestamp
+	11, // 12: kitadoc.v1.ChildStats.period_end:type_name -> google.protobuf.Timestamp
+	2,  // 13: kitadoc.v1.ChildStats.categories:type_name -> kitadoc.v1.CategoryCoverage
+	0,  // 14: kitadoc.v1.ListChildrenResponse.children:type_name -> kitadoc.v1.Child
+	1,  // 15: kitadoc.v1.ListDocumentationEntriesResponse.entries:type_name -> kitadoc.v1.DocumentationEntry
+	3,  // 16: kitadoc.v1.GetDocumentationStatsResponse.children:type_name -> kitadoc.v1.ChildStats
+	4,  // 17: kitadoc.v1.ReportingService.ListChildren:input_type -> kitadoc.v1.ListChildrenRequest
+	6,  // 18: kitadoc.v1.ReportingService.GetChild:input_type -> kitadoc.v1.GetChildRequest
+	7,  // 19: kitadoc.v1.ReportingService.ListDocumentationEntries:input_type -> kitadoc.v1.ListDocumentationEntriesRequest
+	9,  // 20: kitadoc.v1.ReportingService.GetDocumentationStats:input_type -> kitadoc.v1.GetDocumentationStatsRequest
+	5,  // 21: kitadoc.v1.ReportingService.ListChildren:output_type -> kitadoc.v1.ListChildrenResponse
+	0,  // 22: kitadoc.v1.ReportingService.GetChild:output_type -> kitadoc.v1.Child
+	8,  // 23: kitadoc.v1.ReportingService.ListDocumentationEntries:output_type -> kitadoc.v1.ListDocumentationEntriesResponse
+	10, // 24: kitadoc.v1.ReportingService.GetDocumentationStats:output_type -> kitadoc.v1.GetDocumentationStatsResponse
+	21, // [21:25] is the sub-list for method output_type
+	17, // [17:21] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_kitadoc_v1_reporting_proto_init() }
+func file_kitadoc_v1_reporting_proto_init() {
+	if File_kitadoc_v1_reporting_proto != nil {
+		return
+	}
+	file_kitadoc_v1_reporting_proto_msgTypes[1].OneofWrappers = []any{}
+	file_kitadoc_v1_reporting_proto_msgTypes[3].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kitadoc_v1_reporting_proto_rawDesc), len(file_kitadoc_v1_reporting_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_kitadoc_v1_reporting_proto_goTypes,
+		DependencyIndexes: file_kitadoc_v1_reporting_proto_depIdxs,
+		MessageInfos:      file_kitadoc_v1_reporting_proto_msgTypes,
+	}.Build()
+	File_kitadoc_v1_reporting_proto = out.File
+	file_kitadoc_v1_reporting_proto_goTypes = nil
+	file_kitadoc_v1_reporting_proto_depIdxs = nil
+}
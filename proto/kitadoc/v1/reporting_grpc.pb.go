@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: kitadoc/v1/reporting.proto
+
+package kitadocv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ReportingService_ListChildren_FullMethodName             = "/kitadoc.v1.ReportingService/ListChildren"
+	ReportingService_GetChild_FullMethodName                 = "/kitadoc.v1.ReportingService/GetChild"
+	ReportingService_ListDocumentationEntries_FullMethodName = "/kitadoc.v1.ReportingService/ListDocumentationEntries"
+	ReportingService_GetDocumentationStats_FullMethodName    = "/kitadoc.v1.ReportingService/GetDocumentationStats"
+)
+
+// ReportingServiceClient is the client API for ReportingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ReportingService exposes the core read APIs to the internal reporting pipeline.
+// Calls authenticate with the bearer token of the HTTP API, sent as "authorization" metadata.
+type ReportingServiceClient interface {
+	// ListChildren returns all children that have not been archived.
+	ListChildren(ctx context.Context, in *ListChildrenRequest, opts ...grpc.CallOption) (*ListChildrenResponse, error)
+	// GetChild returns a single child.
+	GetChild(ctx context.Context, in *GetChildRequest, opts ...grpc.CallOption) (*Child, error)
+	// ListDocumentationEntries returns all documentation entries of a child.
+	ListDocumentationEntries(ctx context.Context, in *ListDocumentationEntriesRequest, opts ...grpc.CallOption) (*ListDocumentationEntriesResponse, error)
+	// GetDocumentationStats returns the documentation completeness of all children.
+	GetDocumentationStats(ctx context.Context, in *GetDocumentationStatsRequest, opts ...grpc.CallOption) (*GetDocumentationStatsResponse, error)
+}
+
+type reportingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReportingServiceClient(cc grpc.ClientConnInterface) ReportingServiceClient {
+	return &reportingServiceClient{cc}
+}
+
+func (c *reportingServiceClient) ListChildren(ctx context.Context, in *ListChildrenRequest, opts ...grpc.CallOption) (*ListChildrenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChildrenResponse)
+	err := c.cc.Invoke(ctx, ReportingService_ListChildren_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reportingServiceClient) GetChild(ctx context.Context, in *GetChildRequest, opts ...grpc.CallOption) (*Child, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Child)
+	err := c.cc.Invoke(ctx, ReportingService_GetChild_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reportingServiceClient) ListDocumentationEntries(ctx context.Context, in *ListDocumentationEntriesRequest, opts ...grpc.CallOption) (*ListDocumentationEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDocumentationEntriesResponse)
+	err := c.cc.Invoke(ctx, ReportingService_ListDocumentationEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reportingServiceClient) GetDocumentationStats(ctx context.Context, in *GetDocumentationStatsRequest, opts ...grpc.CallOption) (*GetDocumentationStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDocumentationStatsResponse)
+	err := c.cc.Invoke(ctx, ReportingService_GetDocumentationStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReportingServiceServer is the server API for ReportingService service.
+// All implementations must embed UnimplementedReportingServiceServer
+// for forward compatibility.
+//
+// ReportingService exposes the core read APIs to the internal reporting pipeline.
+// Calls authenticate with the bearer token of the HTTP API, sent as "authorization" metadata.
+type ReportingServiceServer interface {
+	// ListChildren returns all children that have not been archived.
+	ListChildren(context.Context, *ListChildrenRequest) (*ListChildrenResponse, error)
+	// GetChild returns a single child.
+	GetChild(context.Context, *GetChildRequest) (*Child, error)
+	// ListDocumentationEntries returns all documentation entries of a child.
+	ListDocumentationEntries(context.Context, *ListDocumentationEntriesRequest) (*ListDocumentationEntriesResponse, error)
+	// GetDocumentationStats returns the documentation completeness of all children.
+	GetDocumentationStats(context.Context, *GetDocumentationStatsRequest) (*GetDocumentationStatsResponse, error)
+	mustEmbedUnimplementedReportingServiceServer()
+}
+
+// UnimplementedReportingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedReportingServiceServer struct{}
+
+func (UnimplementedReportingServiceServer) ListChildren(context.Context, *ListChildrenRequest) (*ListChildrenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChildren not implemented")
+}
+func (UnimplementedReportingServiceServer) GetChild(context.Context, *GetChildRequest) (*Child, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChild not implemented")
+}
+func (UnimplementedReportingServiceServer) ListDocumentationEntries(context.Context, *ListDocumentationEntriesRequest) (*ListDocumentationEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDocumentationEntries not implemented")
+}
+func (UnimplementedReportingServiceServer) GetDocumentationStats(context.Context, *GetDocumentationStatsRequest) (*GetDocumentationStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDocumentationStats not implemented")
+}
+func (UnimplementedReportingServiceServer) mustEmbedUnimplementedReportingServiceServer() {}
+func (UnimplementedReportingServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeReportingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReportingServiceServer will
+// result in compilation errors.
+type UnsafeReportingServiceServer interface {
+	mustEmbedUnimplementedReportingServiceServer()
+}
+
+func RegisterReportingServiceServer(s grpc.ServiceRegistrar, srv ReportingServiceServer) {
+	// If the following call pancis, it indicates UnimplementedReportingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ReportingService_ServiceDesc, srv)
+}
+
+func _ReportingService_ListChildren_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChildrenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReportingServiceServer).ListChildren(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReportingService_ListChildren_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReportingServiceServer).ListChildren(ctx, req.(*ListChildrenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReportingService_GetChild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReportingServiceServer).GetChild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReportingService_GetChild_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReportingServiceServer).GetChild(ctx, req.(*GetChildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReportingService_ListDocumentationEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDocumentationEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReportingServiceServer).ListDocumentationEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReportingService_ListDocumentationEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReportingServiceServer).ListDocumentationEntries(ctx, req.(*ListDocumentationEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReportingService_GetDocumentationStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDocumentationStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReportingServiceServer).GetDocumentationStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReportingService_GetDocumentationStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReportingServiceServer).GetDocumentationStats(ctx, req.(*GetDocumentationStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ReportingService_ServiceDesc is the grpc.ServiceDesc for ReportingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReportingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kitadoc.v1.ReportingService",
+	HandlerType: (*ReportingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListChildren",
+			Handler:    _ReportingService_ListChildren_Handler,
+		},
+		{
+			MethodName: "GetChild",
+			Handler:    _ReportingService_GetChild_Handler,
+		},
+		{
+			MethodName: "ListDocumentationEntries",
+			Handler:    _ReportingService_ListDocumentationEntries_Handler,
+		},
+		{
+			MethodName: "GetDocumentationStats",
+			Handler:    _ReportingService_GetDocumentationStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "kitadoc/v1/reporting.proto",
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AnnouncementService defines the interface for announcement-related business logic operations.
+type AnnouncementService interface {
+	CreateAnnouncement(logger *logrus.Entry, ctx context.Context, announcement *models.Announcement) (*models.Announcement, error)
+	GetAllAnnouncements(logger *logrus.Entry, ctx context.Context) ([]models.Announcement, error)
+	GetUnreadAnnouncements(logger *logrus.Entry, ctx context.Context, userID int) ([]models.Announcement, error)
+	AcknowledgeAnnouncement(logger *logrus.Entry, ctx context.Context, announcementID int, userID int) error
+	GetAcknowledgements(logger *logrus.Entry, ctx context.Context, announcementID int) ([]models.AnnouncementAcknowledgement, error)
+	DeleteAnnouncement(logger *logrus.Entry, ctx context.Context, id int) error
+}
+
+// AnnouncementServiceImpl implements AnnouncementService.
+type AnnouncementServiceImpl struct {
+	announcementStore data.AnnouncementStore
+}
+
+// NewAnnouncementService creates a new AnnouncementServiceImpl.
+func NewAnnouncementService(announcementStore data.AnnouncementStore) *AnnouncementServiceImpl {
+	return &AnnouncementServiceImpl{
+		announcementStore: announcementStore,
+	}
+}
+
+// CreateAnnouncement creates a new announcement.
+func (service *AnnouncementServiceImpl) CreateAnnouncement(logger *logrus.Entry, ctx context.Context, announcement *models.Announcement) (*models.Announcement, error) {
+	if err := models.ValidateAnnouncement(*announcement); err != nil {
+		logger.WithError(err).Warn("Invalid input for CreateAnnouncement")
+		return nil, invalidInput(err)
+	}
+
+	id, err := service.announcementStore.Create(ctx, announcement)
+	if err != nil {
+		logger.WithError(err).Error("Error creating announcement")
+		return nil, ErrInternal
+	}
+
+	created, err := service.announcementStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("announcement_id", id).Error("Error fetching created announcement")
+		return nil, ErrInternal
+	}
+	logger.WithField("announcement_id", id).Info("Announcement created successfully")
+	return created, nil
+}
+
+// GetAllAnnouncements fetches all announcements.
+func (service *AnnouncementServiceImpl) GetAllAnnouncements(logger *logrus.Entry, ctx context.Context) ([]models.Announcement, error) {
+	announcements, err := service.announcementStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching announcements")
+		return nil, ErrInternal
+	}
+	return announcements, nil
+}
+
+// GetUnreadAnnouncements fetches the announcements a user has not acknowledged yet.
+func (service *AnnouncementServiceImpl) GetUnreadAnnouncements(logger *logrus.Entry, ctx context.Context, userID int) ([]models.Announcement, error) {
+	announcements, err := service.announcementStore.GetUnreadForUser(ctx, userID)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", userID).Error("Error fetching unread announcements")
+		return nil, ErrInternal
+	}
+	return announcements, nil
+}
+
+// AcknowledgeAnnouncement records that a user has read an announcement.
+func (service *AnnouncementServiceImpl) AcknowledgeAnnouncement(logger *logrus.Entry, ctx context.Context, announcementID int, userID int) error {
+	if _, err := service.announcementStore.GetByID(ctx, announcementID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("announcement_id", announcementID).Warn("Announcement not found for acknowledgement")
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("announcement_id", announcementID).Error("Error fetching announcement for acknowledgement")
+		return ErrInternal
+	}
+
+	if err := service.announcementStore.Acknowledge(ctx, announcementID, userID); err != nil {
+		logger.WithError(err).WithField("announcement_id", announcementID).Error("Error acknowledging announcement")
+		return ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"announcement_id": announcementID, "user_id": userID}).Info("Announcement acknowledged")
+	return nil
+}
+
+// GetAcknowledgements fetches the read receipts of an announcement.
+func (service *AnnouncementServiceImpl) GetAcknowledgements(logger *logrus.Entry, ctx context.Context, announcementID int) ([]models.AnnouncementAcknowledgement, error) {
+	if _, err := service.announcementStore.GetByID(ctx, announcementID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("announcement_id", announcementID).Error("Error fetching announcement")
+		return nil, ErrInternal
+	}
+
+	acknowledgements, err := service.announcementStore.GetAcknowledgements(ctx, announcementID)
+	if err != nil {
+		logger.WithError(err).WithField("announcement_id", announcementID).Error("Error fetching acknowledgements")
+		return nil, ErrInternal
+	}
+	return acknowledgements, nil
+}
+
+// DeleteAnnouncement deletes an announcement together with its acknowledgements.
+func (service *AnnouncementServiceImpl) DeleteAnnouncement(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.announcementStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("announcement_id", id).Error("Error deleting announcement")
+		return ErrInternal
+	}
+	logger.WithField("announcement_id", id).Info("Announcement deleted successfully")
+	return nil
+}
@@ -0,0 +1,75 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAnnouncement(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockAnnouncementStore := new(datamocks.MockAnnouncementStore)
+		service := services.NewAnnouncementService(mockAnnouncementStore)
+
+		announcement := &models.Announcement{Title: "Hygieneplan", Body: "Bitte lesen"}
+		mockAnnouncementStore.On("Create", mock.Anything, announcement).Return(1, nil).Once()
+		mockAnnouncementStore.On("GetByID", mock.Anything, 1).Return(&models.Announcement{ID: 1, Title: "Hygieneplan", Body: "Bitte lesen"}, nil).Once()
+
+		created, err := service.CreateAnnouncement(logger, ctx, announcement)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, created.ID)
+		mockAnnouncementStore.AssertExpectations(t)
+	})
+
+	t.Run("missing title", func(t *testing.T) {
+		mockAnnouncementStore := new(datamocks.MockAnnouncementStore)
+		service := services.NewAnnouncementService(mockAnnouncementStore)
+
+		_, err := service.CreateAnnouncement(logger, ctx, &models.Announcement{Body: "Bitte lesen"})
+
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		mockAnnouncementStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAcknowledgeAnnouncement(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockAnnouncementStore := new(datamocks.MockAnnouncementStore)
+		service := services.NewAnnouncementService(mockAnnouncementStore)
+
+		mockAnnouncementStore.On("GetByID", mock.Anything, 1).Return(&models.Announcement{ID: 1}, nil).Once()
+		mockAnnouncementStore.On("Acknowledge", mock.Anything, 1, 2).Return(nil).Once()
+
+		err := service.AcknowledgeAnnouncement(logger, ctx, 1, 2)
+
+		assert.NoError(t, err)
+		mockAnnouncementStore.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockAnnouncementStore := new(datamocks.MockAnnouncementStore)
+		service := services.NewAnnouncementService(mockAnnouncementStore)
+
+		mockAnnouncementStore.On("GetByID", mock.Anything, 1).Return(nil, data.ErrNotFound).Once()
+
+		err := service.AcknowledgeAnnouncement(logger, ctx, 1, 2)
+
+		assert.Equal(t, services.ErrNotFound, err)
+		mockAnnouncementStore.AssertNotCalled(t, "Acknowledge", mock.Anything, 1, 2)
+	})
+}
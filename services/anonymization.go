@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pseudonymFirstNames are the pseudonyms given to the children of an anonymized dataset.
+var pseudonymFirstNames = []string{
+	"Emma", "Noah", "Mia", "Ben", "Hanna", "Paul", "Lina", "Finn", "Ella", "Leon",
+	"Clara", "Elias", "Ida", "Henry", "Lea", "Jonas", "Marie", "Luis", "Frieda", "Theo",
+}
+
+// pseudonymLastNames are the pseudonyms given to the staff of an anonymized dataset.
+var pseudonymLastNames = []string{"Sommer", "Winter", "Berg", "Wald", "Brunnen", "Linde", "Feld", "Bach", "Stein", "Rose"}
+
+// pseudonymFamilyNames are the family names given to children when AnonymizationOptions.FamilyNames is set.
+var pseudonymFamilyNames = []string{
+	"Neumann", "Hofmann", "Krüger", "Lehmann", "Hartmann", "Lange", "Werner", "Krause", "Köhler", "Brandt",
+	"Vogel", "Frank", "Busch", "Kaiser", "Peters", "Graf", "Ludwig", "Kuhn", "Albrecht", "Engel",
+}
+
+// generatedObservations are the sentences observation texts are assembled from when they are replaced, %s is the child's pseudonym.
+var generatedObservations = []string{
+	"%s hat im Morgenkreis aufmerksam zugehört und eigene Ideen eingebracht.",
+	"%s balancierte sicher über den Baumstamm im Garten und half anderen Kindern dabei.",
+	"Beim Freispiel baute %s gemeinsam mit zwei Kindern einen hohen Turm aus Holzklötzen.",
+	"%s zählte beim Tischdecken die Teller und Becher richtig bis zehn ab.",
+	"Im Stuhlkreis erzählte %s ausführlich von einem Ausflug am Wochenende.",
+	"%s malte ein Bild mit kräftigen Farben und beschrieb anschließend, was darauf zu sehen ist.",
+	"Beim Mittagessen probierte %s neues Gemüse und räumte den Platz selbstständig ab.",
+	"%s löste einen Streit um ein Spielzeug mit Worten und fand einen Kompromiss.",
+	"Im Garten beobachtete %s lange die Ameisen und stellte viele Fragen dazu.",
+	"%s sang die neuen Lieder mit und klatschte den Rhythmus sicher mit.",
+	"In der Bringzeit ließ sich %s nach kurzer Zeit von der Fachkraft trösten.",
+	"%s sortierte beim Aufräumen die Bausteine nach Farben und Formen.",
+}
+
+// anonymizedMasterdata replaces the facility's contact details.
+var anonymizedMasterdata = models.KitaMasterdata{
+	Name:        "Demo-Kita Sonnenschein",
+	Street:      "Musterstraße",
+	HouseNumber: "1",
+	PostalCode:  "12345",
+	City:        "Musterstadt",
+	PhoneNumber: "0123 456789",
+	Email:       "demo@example.org",
+}
+
+// AnonymizationOptions controls how thoroughly AnonymizeDataset pseudonymizes a dataset.
+// The zero value is used by the demo mode: names are replaced, the observation texts are kept.
+type AnonymizationOptions struct {
+	IncludeArchived     bool      // Also pseudonymize archived children
+	FamilyNames         bool      // Give children varied family names instead of "Beispiel"
+	ReplaceObservations bool      // Replace observation texts and free-text form fields with generated sentences
+	Users               bool      // Pseudonymize the login names of all user accounts
+	PasswordHash        string    // If set, replaces the password hash of every user account
+	Now                 time.Time // Recorded as the update time of the user accounts
+}
+
+// AnonymizeDataset replaces the names of children and staff with pseudonyms, also inside the observation texts,
+// and replaces the facility's contact details. No mapping between real names and pseudonyms is kept.
+// All data is read through source and written through target; both may be the same DAL, or two DALs on the
+// same database with different encryption keys to re-encrypt the dataset.
+// Photos need no treatment, the dataset does not contain any.
+func AnonymizeDataset(logger *logrus.Entry, ctx context.Context, source *data.DAL, target *data.DAL, options AnonymizationOptions) error {
+	var children []models.Child
+	var err error
+	if options.IncludeArchived {
+		children, err = source.Children.GetAllIncludingArchived(ctx)
+	} else {
+		children, err = source.Children.GetAll(ctx)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Error fetching children for anonymization")
+		return ErrInternal
+	}
+	teachers, err := source.Teachers.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching teachers for anonymization")
+		return ErrInternal
+	}
+	entriesByChild := make(map[int][]models.DocumentationEntry, len(children))
+	for _, child := range children {
+		entries, err := source.DocumentationEntries.GetAllForChild(ctx, child.ID)
+		if err != nil {
+			logger.WithError(err).WithField("child_id", child.ID).Error("Error fetching documentation entries for anonymization")
+			return ErrInternal
+		}
+		entriesByChild[child.ID] = entries
+	}
+	var users []*models.User
+	if options.Users || options.PasswordHash != "" {
+		users, err = source.Users.GetAll(ctx)
+		if err != nil {
+			logger.WithError(err).Error("Error fetching users for anonymization")
+			return ErrInternal
+		}
+	}
+	forms := make(map[int]*models.FormSchema)
+	if options.ReplaceObservations {
+		categories, err := source.Categories.GetAll(ctx)
+		if err != nil {
+			logger.WithError(err).Error("Error fetching categories for anonymization")
+			return ErrInternal
+		}
+		for _, category := range categories {
+			forms[category.ID] = category.FormSchema
+		}
+	}
+
+	replacer := newNameReplacer()
+	pseudonyms := make(map[int]string, len(children))
+	for i := range children {
+		child := &children[i]
+		firstName := numberedPseudonym(pseudonymFirstNames, i)
+		lastName := "Beispiel"
+		if options.FamilyNames {
+			lastName = pseudonymFamilyNames[rand.IntN(len(pseudonymFamilyNames))]
+		}
+		replacer.add(child.FirstName, firstName)
+		replacer.add(child.LastName, lastName)
+		pseudonyms[child.ID] = firstName
+		child.FirstName = firstName
+		child.LastName = lastName
+		child.Birthdate = models.NewDate(child.Birthdate.Year(), child.Birthdate.Month(), 1)
+		if err := target.Children.Update(ctx, child); err != nil {
+			logger.WithError(err).WithField("child_id", child.ID).Error("Error anonymizing child")
+			return ErrInternal
+		}
+	}
+	for i := range teachers {
+		teacher := &teachers[i]
+		lastName := numberedPseudonym(pseudonymLastNames, i)
+		replacer.add(teacher.FirstName, "Fachkraft")
+		replacer.add(teacher.LastName, lastName)
+		teacher.FirstName = "Fachkraft"
+		teacher.LastName = lastName
+		teacher.Username = fmt.Sprintf("fachkraft%d", i+1)
+		if err := target.Teachers.Update(ctx, teacher); err != nil {
+			logger.WithError(err).WithField("teacher_id", teacher.ID).Error("Error anonymizing teacher")
+			return ErrInternal
+		}
+	}
+
+	for _, child := range children {
+		for i := range entriesByChild[child.ID] {
+			entry := &entriesByChild[child.ID][i]
+			if options.ReplaceObservations {
+				entry.ObservationDescription = generateObservation(pseudonyms[child.ID], utf8.RuneCountInString(entry.ObservationDescription))
+			} else {
+				entry.ObservationDescription = replacer.replace(entry.ObservationDescription)
+			}
+			for key, value := range entry.StructuredData {
+				text, ok := value.(string)
+				if !ok {
+					continue
+				}
+				if options.ReplaceObservations {
+					entry.StructuredData[key] = generateFormValue(forms[entry.CategoryID], key, text, pseudonyms[child.ID])
+				} else {
+					entry.StructuredData[key] = replacer.replace(text)
+				}
+			}
+			if err := target.DocumentationEntries.Update(ctx, entry); err != nil {
+				logger.WithError(err).WithField("entry_id", entry.ID).Error("Error anonymizing documentation entry")
+				return ErrInternal
+			}
+		}
+	}
+
+	roleCounts := make(map[string]int)
+	for _, user := range users {
+		if options.Users {
+			roleCounts[user.Role]++
+			prefix := "fachkraft"
+			if user.Role == string(data.RoleAdmin) {
+				prefix = "leitung"
+			}
+			user.Username = fmt.Sprintf("%s%d", prefix, roleCounts[user.Role])
+		}
+		if options.PasswordHash != "" {
+			user.PasswordHash = options.PasswordHash
+		}
+		user.UpdatedAt = options.Now
+		if err := target.Users.Update(ctx, user); err != nil {
+			logger.WithError(err).WithField("user_id", user.ID).Error("Error anonymizing user")
+			return ErrInternal
+		}
+	}
+
+	masterdata := anonymizedMasterdata
+	err = target.KitaMasterdata.Update(ctx, &masterdata)
+	if err != nil && !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).Error("Error anonymizing kita masterdata")
+		return ErrInternal
+	}
+	return nil
+}
+
+// numberedPseudonym picks the i-th pseudonym, numbering them once the list is exhausted.
+func numberedPseudonym(pseudonyms []string, i int) string {
+	pseudonym := pseudonyms[i%len(pseudonyms)]
+	if i >= len(pseudonyms) {
+		pseudonym += " " + strconv.Itoa(i/len(pseudonyms)+1)
+	}
+	return pseudonym
+}
+
+// generateObservation assembles random observation sentences about name until the text is at least minLength runes long.
+func generateObservation(name string, minLength int) string {
+	sentences := []string{fmt.Sprintf(generatedObservations[rand.IntN(len(generatedObservations))], name)}
+	length := utf8.RuneCountInString(sentences[0])
+	for length < minLength && len(sentences) < 20 {
+		sentence := fmt.Sprintf(generatedObservations[rand.IntN(len(generatedObservations))], name)
+		sentences = append(sentences, sentence)
+		length += utf8.RuneCountInString(sentence) + 1
+	}
+	return strings.Join(sentences, " ")
+}
+
+// generateFormValue replaces a free-text form value with a generated sentence.
+// Values of choice fields are kept, they cannot contain personal data.
+func generateFormValue(schema *models.FormSchema, key string, value string, name string) string {
+	var property models.FormSchemaProperty
+	if schema != nil {
+		property = schema.Properties[key]
+	}
+	if len(property.Enum) > 0 {
+		return value
+	}
+	generated := []rune(generateObservation(name, 0))
+	if property.MaxLength != nil && len(generated) > *property.MaxLength {
+		generated = generated[:*property.MaxLength]
+	}
+	return string(generated)
+}
+
+// nameReplacer replaces whole-word occurrences of real names with their pseudonyms in a single pass,
+// so that a pseudonym which happens to be another child's real name is not replaced again.
+type nameReplacer struct {
+	pseudonyms map[string]string
+	pattern    *regexp.Regexp
+}
+
+func newNameReplacer() *nameReplacer {
+	return &nameReplacer{pseudonyms: make(map[string]string)}
+}
+
+func (replacer *nameReplacer) add(name string, pseudonym string) {
+	if name == "" {
+		return
+	}
+	replacer.pseudonyms[name] = pseudonym
+	replacer.pattern = nil
+}
+
+func (replacer *nameReplacer) replace(text string) string {
+	if len(replacer.pseudonyms) == 0 {
+		return text
+	}
+	if replacer.pattern == nil {
+		names := make([]string, 0, len(replacer.pseudonyms))
+		for name := range replacer.pseudonyms {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+		// Longer names first, so that "Annabell" is not replaced as "Anna" + "bell".
+		sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+		replacer.pattern = regexp.MustCompile(strings.Join(names, "|"))
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, match := range replacer.pattern.FindAllStringIndex(text, -1) {
+		if !isWordBoundary(text, match[0], match[1]) {
+			continue
+		}
+		builder.WriteString(text[last:match[0]])
+		builder.WriteString(replacer.pseudonyms[text[match[0]:match[1]]])
+		last = match[1]
+	}
+	builder.WriteString(text[last:])
+	return builder.String()
+}
+
+// isWordBoundary reports whether text[start:end] is neither preceded nor followed by a letter or digit.
+func isWordBoundary(text string, start int, end int) bool {
+	if before, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordRune(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(after) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAnonymizeDataset(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	t.Run("replaces all personal data and writes through the target", func(t *testing.T) {
+		sourceChildren := new(datamocks.MockChildStore)
+		sourceTeachers := new(datamocks.MockTeacherStore)
+		sourceEntries := new(datamocks.MockDocumentationEntryStore)
+		sourceUsers := new(datamocks.MockUserStore)
+		sourceCategories := new(datamocks.MockCategoryStore)
+		source := &data.DAL{
+			Children:             sourceChildren,
+			Teachers:             sourceTeachers,
+			DocumentationEntries: sourceEntries,
+			Users:                sourceUsers,
+			Categories:           sourceCategories,
+		}
+		targetChildren := new(datamocks.MockChildStore)
+		targetTeachers := new(datamocks.MockTeacherStore)
+		targetEntries := new(datamocks.MockDocumentationEntryStore)
+		targetUsers := new(datamocks.MockUserStore)
+		targetMasterdata := new(datamocks.MockKitaMasterdataStore)
+		target := &data.DAL{
+			Children:             targetChildren,
+			Teachers:             targetTeachers,
+			DocumentationEntries: targetEntries,
+			Users:                targetUsers,
+			KitaMasterdata:       targetMasterdata,
+		}
+
+		maxLength := 20
+		archivedAt := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+		sourceChildren.On("GetAllIncludingArchived", mock.Anything).Return([]models.Child{
+			{ID: 1, FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2020, 5, 17), ArchivedAt: &archivedAt},
+		}, nil).Once()
+		sourceTeachers.On("GetAll", mock.Anything).Return([]models.Teacher{{ID: 1, FirstName: "Petra", LastName: "Schmidt", Username: "pschmidt"}}, nil).Once()
+		sourceEntries.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{
+			{ID: 10, ChildID: 1, CategoryID: 3, ObservationDescription: "Anna wohnt in der Lindenstraße 4 und spielt gern.", StructuredData: map[string]any{"note": "Mutter Sabine holt ab", "mood": "fröhlich", "count": 2.0}},
+		}, nil).Once()
+		sourceUsers.On("GetAll", mock.Anything).Return([]*models.User{
+			{ID: 1, Username: "petra.schmidt", Role: "admin"},
+			{ID: 2, Username: "klaus.meier", Role: "teacher"},
+		}, nil).Once()
+		sourceCategories.On("GetAll", mock.Anything).Return([]models.Category{
+			{ID: 3, Name: "Eingewöhnung", FormSchema: &models.FormSchema{Properties: map[string]models.FormSchemaProperty{
+				"note": {Type: models.FormPropertyString, MaxLength: &maxLength},
+				"mood": {Type: models.FormPropertyString, Enum: []string{"fröhlich", "traurig"}},
+			}}},
+		}, nil).Once()
+
+		targetChildren.On("Update", mock.Anything, mock.MatchedBy(func(child *models.Child) bool {
+			return child.FirstName == "Emma" && child.LastName != "Müller" && child.LastName != "Beispiel" &&
+				child.Birthdate.Equal(time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC))
+		})).Return(nil).Once()
+		targetTeachers.On("Update", mock.Anything, mock.MatchedBy(func(teacher *models.Teacher) bool {
+			return teacher.FirstName == "Fachkraft" && teacher.Username == "fachkraft1"
+		})).Return(nil).Once()
+		targetEntries.On("Update", mock.Anything, mock.MatchedBy(func(entry *models.DocumentationEntry) bool {
+			note, _ := entry.StructuredData["note"].(string)
+			return strings.Contains(entry.ObservationDescription, "Emma") &&
+				!strings.Contains(entry.ObservationDescription, "Lindenstraße") &&
+				len(entry.ObservationDescription) >= len("Anna wohnt in der Lindenstraße 4 und spielt gern.") &&
+				note != "Mutter Sabine holt ab" && len([]rune(note)) <= 20 &&
+				entry.StructuredData["mood"] == "fröhlich" && entry.StructuredData["count"] == 2.0
+		})).Return(nil).Once()
+		targetUsers.On("Update", mock.Anything, mock.MatchedBy(func(user *models.User) bool {
+			return user.ID == 1 && user.Username == "leitung1" && user.PasswordHash == "hash"
+		})).Return(nil).Once()
+		targetUsers.On("Update", mock.Anything, mock.MatchedBy(func(user *models.User) bool {
+			return user.ID == 2 && user.Username == "fachkraft1" && user.PasswordHash == "hash"
+		})).Return(nil).Once()
+		targetMasterdata.On("Update", mock.Anything, mock.AnythingOfType("*models.KitaMasterdata")).Return(data.ErrNotFound).Once()
+
+		err := services.AnonymizeDataset(logger, t.Context(), source, target, services.AnonymizationOptions{
+			IncludeArchived:     true,
+			FamilyNames:         true,
+			ReplaceObservations: true,
+			Users:               true,
+			PasswordHash:        "hash",
+		})
+		assert.NoError(t, err)
+		for _, store := range []interface{ AssertExpectations(mock.TestingT) bool }{
+			sourceChildren, sourceTeachers, sourceEntries, sourceUsers, sourceCategories,
+			targetChildren, targetTeachers, targetEntries, targetUsers, targetMasterdata,
+		} {
+			store.AssertExpectations(t)
+		}
+	})
+
+	t.Run("fails before writing when reading fails", func(t *testing.T) {
+		children := new(datamocks.MockChildStore)
+		teachers := new(datamocks.MockTeacherStore)
+		dal := &data.DAL{Children: children, Teachers: teachers}
+		children.On("GetAll", mock.Anything).Return([]models.Child{{ID: 1, FirstName: "Anna", LastName: "Müller"}}, nil).Once()
+		teachers.On("GetAll", mock.Anything).Return(nil, errors.New("db error")).Once()
+
+		err := services.AnonymizeDataset(logger, t.Context(), dal, dal, services.AnonymizationOptions{})
+		assert.ErrorIs(t, err, services.ErrInternal)
+		children.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AnonymousStatisticsService defines the interface for documentation statistics without personal data.
+type AnonymousStatisticsService interface {
+	// GetAnonymousStatistics computes the statistics for an export, the export is recorded in the audit log.
+	GetAnonymousStatistics(logger *logrus.Entry, ctx context.Context, actingUserID int) (*models.AnonymousStatistics, error)
+}
+
+// AnonymousStatisticsServiceImpl implements AnonymousStatisticsService.
+type AnonymousStatisticsServiceImpl struct {
+	childStore              data.ChildStore
+	categoryStore           data.CategoryStore
+	documentationEntryStore data.DocumentationEntryStore
+	auditLogService         AuditLogService
+	pseudonymKey            []byte
+	clock                   clock.Clock
+}
+
+// NewAnonymousStatisticsService creates a new AnonymousStatisticsServiceImpl. The pseudonyms of the
+// children are derived from pseudonymKey, they change when the key changes.
+func NewAnonymousStatisticsService(
+	childStore data.ChildStore,
+	categoryStore data.CategoryStore,
+	documentationEntryStore data.DocumentationEntryStore,
+	auditLogService AuditLogService,
+	pseudonymKey []byte,
+	clock clock.Clock,
+) *AnonymousStatisticsServiceImpl {
+	return &AnonymousStatisticsServiceImpl{
+		childStore:              childStore,
+		categoryStore:           categoryStore,
+		documentationEntryStore: documentationEntryStore,
+		auditLogService:         auditLogService,
+		pseudonymKey:            pseudonymKey,
+		clock:                   clock,
+	}
+}
+
+// GetAnonymousStatistics computes the documentation statistics of every active child over the completeness period.
+// Names, birthdates, observation texts and dates are left out, the children are sorted by pseudonym so that
+// the order does not reveal when they were added.
+func (service *AnonymousStatisticsServiceImpl) GetAnonymousStatistics(logger *logrus.Entry, ctx context.Context, actingUserID int) (*models.AnonymousStatistics, error) {
+	children, err := service.childStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching children for anonymous statistics")
+		return nil, ErrInternal
+	}
+
+	categories, err := service.categoryStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching categories for anonymous statistics")
+		return nil, ErrInternal
+	}
+
+	now := service.clock.Now()
+	statistics := &models.AnonymousStatistics{
+		GeneratedAt: now,
+		PeriodStart: now.AddDate(-completenessPeriod, 0, 0),
+		PeriodEnd:   now,
+		Children:    make([]models.AnonymousChildStatistics, 0, len(children)),
+	}
+	for _, child := range children {
+		if err := checkCanceled(logger, ctx, "Anonymous statistics"); err != nil {
+			return nil, err
+		}
+		entries, err := service.documentationEntryStore.GetAllForChild(ctx, child.ID)
+		if err != nil {
+			logger.WithError(err).WithField("child_id", child.ID).Error("Error fetching documentation entries for anonymous statistics")
+			return nil, ErrInternal
+		}
+		statistics.Children = append(statistics.Children, service.childStatistics(child, entries, categories, statistics.PeriodStart, now))
+	}
+	sort.Slice(statistics.Children, func(i, j int) bool {
+		return statistics.Children[i].Pseudonym < statistics.Children[j].Pseudonym
+	})
+
+	// An export is only handed out once it is recorded.
+	if err := service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:      models.AuditActionExportAnonymousStatistics,
+		EntityType:  "anonymous_statistics",
+		ActorUserID: &actingUserID,
+		Details:     fmt.Sprintf("children=%d", len(statistics.Children)),
+	}); err != nil {
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{"children": len(statistics.Children), "user_id": actingUserID}).Info("Anonymous statistics exported")
+	return statistics, nil
+}
+
+func (service *AnonymousStatisticsServiceImpl) childStatistics(child models.Child, entries []models.DocumentationEntry, categories []models.Category, periodStart, now time.Time) models.AnonymousChildStatistics {
+	stats := models.AnonymousChildStatistics{
+		Pseudonym:  service.pseudonym(child.ID),
+		AgeBand:    ageBand(child.Birthdate, models.Today(now)),
+		Categories: make([]models.AnonymousCategoryCount, len(categories)),
+	}
+	countByCategory := make(map[int]*models.AnonymousCategoryCount, len(categories))
+	for i, category := range categories {
+		stats.Categories[i] = models.AnonymousCategoryCount{CategoryName: category.Name}
+		countByCategory[category.ID] = &stats.Categories[i]
+	}
+
+	for _, entry := range entries {
+		if entry.ObservationDate.Before(periodStart) || entry.ObservationDate.After(now) {
+			continue
+		}
+		count, ok := countByCategory[entry.CategoryID]
+		if !ok {
+			continue
+		}
+		count.EntryCount++
+		stats.EntryCount++
+		if entry.IsApproved {
+			stats.ApprovedEntryCount++
+		}
+	}
+
+	for _, count := range stats.Categories {
+		if count.EntryCount > 0 {
+			stats.CategoriesCovered++
+		}
+	}
+	if len(categories) > 0 {
+		stats.CompletenessScore = stats.CategoriesCovered * 100 / len(categories)
+	}
+	return stats
+}
+
+// pseudonym derives the stable pseudonym of a child. Without the key it can neither be traced back to
+// the child ID nor be computed for a known child.
+func (service *AnonymousStatisticsServiceImpl) pseudonym(childID int) string {
+	mac := hmac.New(sha256.New, service.pseudonymKey)
+	mac.Write([]byte("child:" + strconv.Itoa(childID)))
+	return "K-" + strings.ToUpper(hex.EncodeToString(mac.Sum(nil)[:5]))
+}
+
+// ageBand returns the age band of a child born at birthdate.
+func ageBand(birthdate, today models.Date) string {
+	switch age := models.AgeInMonths(birthdate, today) / 12; {
+	case age < 3:
+		return models.AgeBandUnder3
+	case age < 5:
+		return models.AgeBand3To4
+	case age < 7:
+		return models.AgeBand5To6
+	default:
+		return models.AgeBandOver6
+	}
+}
@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAnonymousStatistics(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	now := time.Now()
+	children := []models.Child{
+		{ID: 1, FirstName: "Max", LastName: "Muster", Birthdate: models.DateOf(now.AddDate(-4, -1, 0))},
+		{ID: 2, FirstName: "Erika", LastName: "Beispiel", Birthdate: models.DateOf(now.AddDate(-1, -6, 0))},
+	}
+	categories := []models.Category{{ID: 1, Name: "Sprache"}, {ID: 2, Name: "Bewegung"}}
+	var auditLogStore *datamocks.MockAuditLogStore
+	newService := func(key string) *services.AnonymousStatisticsServiceImpl {
+		auditLogStore = new(datamocks.MockAuditLogStore)
+		auditLogStore.On("Create", mock.Anything, mock.Anything).Return(1, nil)
+		mockChildStore := new(datamocks.MockChildStore)
+		mockCategoryStore := new(datamocks.MockCategoryStore)
+		mockDocStore := new(datamocks.MockDocumentationEntryStore)
+		mockChildStore.On("GetAll", mock.Anything).Return(children, nil)
+		mockCategoryStore.On("GetAll", mock.Anything).Return(categories, nil)
+		mockDocStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{
+			{ID: 1, ChildID: 1, CategoryID: 1, ObservationDate: models.DateOf(now.AddDate(0, -1, 0)), ObservationDescription: "Max erzählt im Morgenkreis", IsApproved: true},
+			{ID: 2, ChildID: 1, CategoryID: 1, ObservationDate: models.DateOf(now.AddDate(0, 0, -3))},
+			{ID: 3, ChildID: 1, CategoryID: 2, ObservationDate: models.DateOf(now.AddDate(-2, 0, 0))},
+		}, nil)
+		mockDocStore.On("GetAllForChild", mock.Anything, 2).Return([]models.DocumentationEntry{}, nil)
+		return services.NewAnonymousStatisticsService(mockChildStore, mockCategoryStore, mockDocStore, services.NewAuditLogService(auditLogStore), []byte(key), clock.System{})
+	}
+
+	t.Run("counts without personal data", func(t *testing.T) {
+		statistics, err := newService("key").GetAnonymousStatistics(logger, context.Background(), 7)
+		require.NoError(t, err)
+		require.Len(t, statistics.Children, 2)
+
+		byBand := map[string]models.AnonymousChildStatistics{}
+		for _, child := range statistics.Children {
+			assert.Regexp(t, `^K-[0-9A-F]{10}$`, child.Pseudonym)
+			byBand[child.AgeBand] = child
+		}
+		olderChild := byBand[models.AgeBand3To4]
+		assert.Equal(t, 2, olderChild.EntryCount, "entries older than a year do not count")
+		assert.Equal(t, 1, olderChild.ApprovedEntryCount)
+		assert.Equal(t, 1, olderChild.CategoriesCovered)
+		assert.Equal(t, 50, olderChild.CompletenessScore)
+		assert.Equal(t, []models.AnonymousCategoryCount{{CategoryName: "Sprache", EntryCount: 2}, {CategoryName: "Bewegung"}}, olderChild.Categories)
+		assert.Equal(t, 0, byBand[models.AgeBandUnder3].EntryCount)
+		assert.Less(t, statistics.Children[0].Pseudonym, statistics.Children[1].Pseudonym)
+
+		encoded, err := json.Marshal(statistics)
+		require.NoError(t, err)
+		for _, personal := range []string{"Max", "Muster", "Erika", "Morgenkreis", "child_id", "birthdate"} {
+			assert.NotContains(t, string(encoded), personal)
+		}
+	})
+
+	t.Run("exports are recorded in the audit log", func(t *testing.T) {
+		_, err := newService("key").GetAnonymousStatistics(logger, context.Background(), 7)
+		require.NoError(t, err)
+		auditLogStore.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionExportAnonymousStatistics && *entry.ActorUserID == 7 && entry.Details == "children=2"
+		}))
+	})
+
+	t.Run("pseudonyms are stable per key", func(t *testing.T) {
+		first, err := newService("key").GetAnonymousStatistics(logger, context.Background(), 7)
+		require.NoError(t, err)
+		second, err := newService("key").GetAnonymousStatistics(logger, context.Background(), 7)
+		require.NoError(t, err)
+		other, err := newService("other key").GetAnonymousStatistics(logger, context.Background(), 7)
+		require.NoError(t, err)
+
+		assert.Equal(t, first.Children[0].Pseudonym, second.Children[0].Pseudonym)
+		assert.NotContains(t, []string{other.Children[0].Pseudonym, other.Children[1].Pseudonym}, first.Children[0].Pseudonym)
+	})
+}
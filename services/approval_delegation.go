@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ApprovalDelegationService defines the interface for approval delegation business logic operations.
+type ApprovalDelegationService interface {
+	CreateDelegation(logger *logrus.Entry, ctx context.Context, delegation *models.ApprovalDelegation) (*models.ApprovalDelegation, error)
+	GetDelegationsForUser(logger *logrus.Entry, ctx context.Context, userID int) ([]models.ApprovalDelegation, error)
+	DeleteDelegation(logger *logrus.Entry, ctx context.Context, id int) error
+	// GetActiveDelegation returns the delegation that allows delegateUserID to approve on behalf of
+	// delegatorUserID at the given time, or ErrPermissionDenied if there is none.
+	GetActiveDelegation(logger *logrus.Entry, ctx context.Context, delegatorUserID int, delegateUserID int, at time.Time) (*models.ApprovalDelegation, error)
+}
+
+// ApprovalDelegationServiceImpl implements ApprovalDelegationService.
+type ApprovalDelegationServiceImpl struct {
+	delegationStore data.ApprovalDelegationStore
+	userStore       data.UserStore
+}
+
+// NewApprovalDelegationService creates a new ApprovalDelegationServiceImpl.
+func NewApprovalDelegationService(delegationStore data.ApprovalDelegationStore, userStore data.UserStore) *ApprovalDelegationServiceImpl {
+	return &ApprovalDelegationServiceImpl{
+		delegationStore: delegationStore,
+		userStore:       userStore,
+	}
+}
+
+// CreateDelegation creates a new approval delegation.
+func (service *ApprovalDelegationServiceImpl) CreateDelegation(logger *logrus.Entry, ctx context.Context, delegation *models.ApprovalDelegation) (*models.ApprovalDelegation, error) {
+	if err := models.ValidateApprovalDelegation(*delegation); err != nil {
+		logger.WithError(err).Warn("Invalid input for CreateDelegation")
+		return nil, invalidInput(err)
+	}
+
+	if _, err := service.userStore.GetByID(ctx, delegation.DelegateUserID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("delegate_user_id", delegation.DelegateUserID).Warn("Delegate user not found")
+			return nil, ErrInvalidInput
+		}
+		logger.WithError(err).WithField("delegate_user_id", delegation.DelegateUserID).Error("Error fetching delegate user")
+		return nil, ErrInternal
+	}
+
+	id, err := service.delegationStore.Create(ctx, delegation)
+	if err != nil {
+		logger.WithError(err).Error("Error creating approval delegation")
+		return nil, ErrInternal
+	}
+
+	created, err := service.delegationStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("delegation_id", id).Error("Error fetching created approval delegation")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{
+		"delegation_id":     id,
+		"delegator_user_id": created.DelegatorUserID,
+		"delegate_user_id":  created.DelegateUserID,
+	}).Info("Approval delegation created successfully")
+	return created, nil
+}
+
+// GetDelegationsForUser fetches all delegations the user has granted or received.
+func (service *ApprovalDelegationServiceImpl) GetDelegationsForUser(logger *logrus.Entry, ctx context.Context, userID int) ([]models.ApprovalDelegation, error) {
+	delegations, err := service.delegationStore.GetAllForUser(ctx, userID)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", userID).Error("Error fetching approval delegations")
+		return nil, ErrInternal
+	}
+	return delegations, nil
+}
+
+// DeleteDelegation revokes an approval delegation.
+func (service *ApprovalDelegationServiceImpl) DeleteDelegation(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.delegationStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("delegation_id", id).Error("Error deleting approval delegation")
+		return ErrInternal
+	}
+	logger.WithField("delegation_id", id).Info("Approval delegation deleted successfully")
+	return nil
+}
+
+// GetActiveDelegation finds the delegation covering the given time.
+func (service *ApprovalDelegationServiceImpl) GetActiveDelegation(logger *logrus.Entry, ctx context.Context, delegatorUserID int, delegateUserID int, at time.Time) (*models.ApprovalDelegation, error) {
+	delegations, err := service.delegationStore.GetBetween(ctx, delegatorUserID, delegateUserID)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"delegator_user_id": delegatorUserID,
+			"delegate_user_id":  delegateUserID,
+		}).Error("Error fetching approval delegations")
+		return nil, ErrInternal
+	}
+	for i := range delegations {
+		if delegations[i].IsActiveAt(at) {
+			return &delegations[i], nil
+		}
+	}
+	logger.WithFields(logrus.Fields{
+		"delegator_user_id": delegatorUserID,
+		"delegate_user_id":  delegateUserID,
+	}).Warn("No active approval delegation found")
+	return nil, ErrPermissionDenied
+}
@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateDelegation(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mockDelegationStore := new(datamocks.MockApprovalDelegationStore)
+		mockUserStore := new(datamocks.MockUserStore)
+		service := services.NewApprovalDelegationService(mockDelegationStore, mockUserStore)
+
+		delegation := &models.ApprovalDelegation{DelegatorUserID: 1, DelegateUserID: 2, StartDate: start, EndDate: end}
+		mockUserStore.On("GetByID", mock.Anything, 2).Return(&models.User{ID: 2}, nil).Once()
+		mockDelegationStore.On("Create", mock.Anything, delegation).Return(10, nil).Once()
+		mockDelegationStore.On("GetByID", mock.Anything, 10).Return(&models.ApprovalDelegation{ID: 10, DelegatorUserID: 1, DelegateUserID: 2, StartDate: start, EndDate: end}, nil).Once()
+
+		created, err := service.CreateDelegation(logger, ctx, delegation)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, created.ID)
+		mockDelegationStore.AssertExpectations(t)
+		mockUserStore.AssertExpectations(t)
+	})
+
+	t.Run("delegating to oneself is rejected", func(t *testing.T) {
+		service := services.NewApprovalDelegationService(new(datamocks.MockApprovalDelegationStore), new(datamocks.MockUserStore))
+		_, err := service.CreateDelegation(logger, ctx, &models.ApprovalDelegation{DelegatorUserID: 1, DelegateUserID: 1, StartDate: start, EndDate: end})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+
+	t.Run("end before start is rejected", func(t *testing.T) {
+		service := services.NewApprovalDelegationService(new(datamocks.MockApprovalDelegationStore), new(datamocks.MockUserStore))
+		_, err := service.CreateDelegation(logger, ctx, &models.ApprovalDelegation{DelegatorUserID: 1, DelegateUserID: 2, StartDate: end, EndDate: start})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+
+	t.Run("unknown delegate", func(t *testing.T) {
+		mockUserStore := new(datamocks.MockUserStore)
+		service := services.NewApprovalDelegationService(new(datamocks.MockApprovalDelegationStore), mockUserStore)
+		mockUserStore.On("GetByID", mock.Anything, 2).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.CreateDelegation(logger, ctx, &models.ApprovalDelegation{DelegatorUserID: 1, DelegateUserID: 2, StartDate: start, EndDate: end})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+}
+
+func TestGetActiveDelegation(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	mockDelegationStore := new(datamocks.MockApprovalDelegationStore)
+	service := services.NewApprovalDelegationService(mockDelegationStore, new(datamocks.MockUserStore))
+
+	delegations := []models.ApprovalDelegation{
+		{ID: 1, DelegatorUserID: 1, DelegateUserID: 2, StartDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC)},
+	}
+	mockDelegationStore.On("GetBetween", mock.Anything, 1, 2).Return(delegations, nil)
+
+	t.Run("last day of the range is covered", func(t *testing.T) {
+		delegation, err := service.GetActiveDelegation(logger, ctx, 1, 2, time.Date(2025, 7, 21, 17, 30, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delegation.ID)
+	})
+
+	t.Run("outside of the range", func(t *testing.T) {
+		_, err := service.GetActiveDelegation(logger, ctx, 1, 2, time.Date(2025, 7, 22, 8, 0, 0, 0, time.UTC))
+		assert.ErrorIs(t, err, services.ErrPermissionDenied)
+	})
+}
+
+func TestApproveDocumentationEntryOnBehalf(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	onBehalfOf := 1
+	actingUserID := 2
+	approvedByTeacherID := 3
+
+	newService := func(delegations []models.ApprovalDelegation) (*services.DocumentationEntryServiceImpl, *datamocks.MockDocumentationEntryStore, *datamocks.MockAuditLogStore) {
+		mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockTeacherStore := new(datamocks.MockTeacherStore)
+		mockDelegationStore := new(datamocks.MockApprovalDelegationStore)
+		mockAuditLogStore := new(datamocks.MockAuditLogStore)
+		mockDelegationStore.On("GetBetween", mock.Anything, onBehalfOf, actingUserID).Return(delegations, nil)
+		service := services.NewDocumentationEntryService(
+			mockDocumentationEntryStore,
+			new(datamocks.MockChildStore),
+			mockTeacherStore,
+			new(datamocks.MockCategoryStore),
+			new(datamocks.MockUserStore),
+			new(datamocks.MockKitaMasterdataStore),
+			nil,
+			services.NewApprovalDelegationService(mockDelegationStore, new(datamocks.MockUserStore)),
+			services.NewAuditLogService(mockAuditLogStore),
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
+		)
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, 1).Return(&models.DocumentationEntry{ID: 1}, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, approvedByTeacherID).Return(&models.Teacher{ID: approvedByTeacherID}, nil).Once()
+		return service, mockDocumentationEntryStore, mockAuditLogStore
+	}
+
+	t.Run("active delegation is recorded in the audit trail", func(t *testing.T) {
+		now := time.Now()
+		service, mockDocumentationEntryStore, mockAuditLogStore := newService([]models.ApprovalDelegation{
+			{ID: 7, DelegatorUserID: onBehalfOf, DelegateUserID: actingUserID, StartDate: now.AddDate(0, 0, -1), EndDate: now.AddDate(0, 0, 1)},
+		})
+		mockDocumentationEntryStore.On("ApproveEntry", mock.Anything, 1, approvedByTeacherID, mock.Anything).Return(nil).Once()
+		mockAuditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionApproveDocumentationEntry &&
+				*entry.ActorUserID == actingUserID &&
+				*entry.OnBehalfOfUserID == onBehalfOf &&
+				*entry.DelegationID == 7
+		})).Return(1, nil).Once()
+
+		err := service.ApproveDocumentationEntry(logger, ctx, 1, approvedByTeacherID, actingUserID, &onBehalfOf)
+		assert.NoError(t, err)
+		mockDocumentationEntryStore.AssertExpectations(t)
+		mockAuditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("expired delegation is denied", func(t *testing.T) {
+		now := time.Now()
+		service, mockDocumentationEntryStore, mockAuditLogStore := newService([]models.ApprovalDelegation{
+			{ID: 7, DelegatorUserID: onBehalfOf, DelegateUserID: actingUserID, StartDate: now.AddDate(0, 0, -10), EndDate: now.AddDate(0, 0, -3)},
+		})
+
+		err := service.ApproveDocumentationEntry(logger, ctx, 1, approvedByTeacherID, actingUserID, &onBehalfOf)
+		assert.ErrorIs(t, err, services.ErrPermissionDenied)
+		mockDocumentationEntryStore.AssertNotCalled(t, "ApproveEntry", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockAuditLogStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
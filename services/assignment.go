@@ -1,10 +1,11 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"time"
 
 	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/models"
 
@@ -13,35 +14,38 @@ import (
 
 // AssignmentService defines the interface for assignment-related business logic operations.
 type AssignmentService interface {
-	CreateAssignment(assignment *models.Assignment) (*models.Assignment, error)
-	GetAssignmentByID(id int) (*models.Assignment, error)
-	UpdateAssignment(assignment *models.Assignment) error
-	DeleteAssignment(id int) error
-	GetAssignmentHistoryForChild(childID int) ([]models.Assignment, error)
-	GetAllAssignments() ([]models.Assignment, error)
+	CreateAssignment(ctx context.Context, assignment *models.Assignment) (*models.Assignment, error)
+	GetAssignmentByID(ctx context.Context, id int) (*models.Assignment, error)
+	UpdateAssignment(ctx context.Context, assignment *models.Assignment) error
+	DeleteAssignment(ctx context.Context, id int) error
+	GetAssignmentHistoryForChild(ctx context.Context, childID int, query models.ListQuery) ([]models.Assignment, error)
+	GetAllAssignments(ctx context.Context, query models.ListQuery) ([]models.Assignment, error)
 }
 
-// GetAllAssignments fetches all assignments.
-func (s *AssignmentServiceImpl) GetAllAssignments() ([]models.Assignment, error) {
-	assignments, err := s.assignmentStore.GetAllAssignments()
+// GetAllAssignments fetches the assignments matching the query.
+func (s *AssignmentServiceImpl) GetAllAssignments(ctx context.Context, query models.ListQuery) ([]models.Assignment, error) {
+	assignments, err := s.assignmentStore.List(ctx, query)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error fetching all assignments: %v", err)
+		if errors.Is(err, data.ErrInvalidInput) {
+			return nil, ErrInvalidInput
+		}
 		return nil, ErrInternal
 	}
 	return assignments, nil
 }
 
 // UpdateAssignment updates an existing assignment.
-func (s *AssignmentServiceImpl) UpdateAssignment(assignment *models.Assignment) error {
+func (s *AssignmentServiceImpl) UpdateAssignment(ctx context.Context, assignment *models.Assignment) error {
 	// print assignment
 	logger.GetGlobalLogger().Infof("Updating assignment: %v", assignment)
 	if err := models.ValidateAssignment(*assignment); err != nil {
 		logger.GetGlobalLogger().Errorf("Error validating assignment: %v", err)
-		return ErrInvalidInput
+		return invalidInput(err)
 	}
 
 	// Fetch existing assignment to ensure it exists
-	_, err := s.assignmentStore.GetByID(assignment.ID)
+	existing, err := s.assignmentStore.GetByID(ctx, assignment.ID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.GetGlobalLogger().Errorf("Assignment not found: %d", assignment.ID)
@@ -51,9 +55,27 @@ func (s *AssignmentServiceImpl) UpdateAssignment(assignment *models.Assignment)
 		return ErrInternal
 	}
 
-	assignment.UpdatedAt = time.Now()
-	err = s.assignmentStore.Update(assignment)
+	// The assignment type is kept unless it is changed explicitly.
+	if assignment.AssignmentType == "" {
+		assignment.AssignmentType = existing.AssignmentType
+	}
+	if assignment.AssignmentType == models.AssignmentTypePrimary && assignment.IsOpen() {
+		openPrimary, err := s.openPrimaryAssignment(ctx, assignment.ChildID, assignment.ID)
+		if err != nil {
+			return err
+		}
+		if openPrimary != nil {
+			logger.GetGlobalLogger().Warnf("Child %d already has the open primary assignment %d", assignment.ChildID, openPrimary.ID)
+			return ErrAlreadyExists
+		}
+	}
+
+	assignment.UpdatedAt = s.clock.Now()
+	err = s.assignmentStore.Update(ctx, assignment)
 	if err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			return ErrAlreadyExists
+		}
 		if errors.Is(err, data.ErrNotFound) {
 			logger.GetGlobalLogger().Errorf("Error updating assignment: %v", err)
 			return ErrNotFound
@@ -69,71 +91,106 @@ type AssignmentServiceImpl struct {
 	assignmentStore data.AssignmentStore
 	childStore      data.ChildStore
 	teacherStore    data.TeacherStore
+	ruleService     ValidationRuleService
 	validate        *validator.Validate
+	clock           clock.Clock
 }
 
 // NewAssignmentService creates a new AssignmentServiceImpl.
-func NewAssignmentService(assignmentStore data.AssignmentStore, childStore data.ChildStore, teacherStore data.TeacherStore) *AssignmentServiceImpl {
+// A nil ruleService applies the built-in default business rules.
+func NewAssignmentService(assignmentStore data.AssignmentStore, childStore data.ChildStore, teacherStore data.TeacherStore, ruleService ValidationRuleService, clock clock.Clock) *AssignmentServiceImpl {
+	if ruleService == nil {
+		ruleService = NewValidationRuleService(nil, nil, clock)
+	}
 	return &AssignmentServiceImpl{
 		assignmentStore: assignmentStore,
 		childStore:      childStore,
 		teacherStore:    teacherStore,
-		validate:        validator.New(),
+		ruleService:     ruleService,
+		validate:        models.NewValidator(),
+		clock:           clock,
 	}
 }
 
 // CreateAssignment creates a new assignment.
-func (s *AssignmentServiceImpl) CreateAssignment(assignment *models.Assignment) (*models.Assignment, error) {
+func (s *AssignmentServiceImpl) CreateAssignment(ctx context.Context, assignment *models.Assignment) (*models.Assignment, error) {
 	if err := models.ValidateAssignment(*assignment); err != nil {
 		logger.GetGlobalLogger().Errorf("Error validating assignment: %v", err)
-		return nil, ErrInvalidInput
+		return nil, invalidInput(err)
 	}
 
 	// Validate ChildID
-	_, err := s.childStore.GetByID(assignment.ChildID)
+	_, err := s.childStore.GetByID(ctx, assignment.ChildID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
-			return nil, errors.New("child not found")
+			return nil, ErrChildNotFound
 		}
 		logger.GetGlobalLogger().Errorf("Error fetching child by ID %d: %v", assignment.ChildID, err)
 		return nil, ErrInternal
 	}
 
 	// Validate TeacherID
-	_, err = s.teacherStore.GetByID(assignment.TeacherID)
+	teacher, err := s.teacherStore.GetByID(ctx, assignment.TeacherID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
-			return nil, errors.New("teacher not found")
+			return nil, ErrTeacherNotFound
 		}
 		logger.GetGlobalLogger().Errorf("Error fetching teacher by ID %d: %v", assignment.TeacherID, err)
 		return nil, ErrInternal
 	}
+	if !teacher.IsActive() {
+		logger.GetGlobalLogger().Warnf("Teacher %d is deactivated and cannot get new assignments", assignment.TeacherID)
+		return nil, ErrTeacherDeactivated
+	}
 
-	// Business rule: An assignment cannot start in the future.
-	if assignment.StartDate.After(time.Now()) {
-		return nil, errors.New("assignment start date cannot be in the future")
+	// Configurable business rules, e.g. an assignment cannot start in the future.
+	if err := s.ruleService.ValidateAssignment(logger.GetGlobalLogger().GetLogrusEntry(), context.Background(), assignment); err != nil {
+		return nil, err
 	}
 
 	// Business rule: If EndDate is provided, it must be after StartDate.
 	if assignment.EndDate != nil && assignment.EndDate.Before(assignment.StartDate) {
-		return nil, errors.New("assignment end date cannot be before start date")
+		return nil, ErrAssignmentEndBeforeStart
+	}
+
+	// Business rule: A child has exactly one open primary assignment.
+	openPrimary, err := s.openPrimaryAssignment(ctx, assignment.ChildID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if assignment.AssignmentType == "" {
+		assignment.AssignmentType = models.AssignmentTypeSecondary
+		if openPrimary == nil {
+			assignment.AssignmentType = models.AssignmentTypePrimary
+		}
+	}
+	if assignment.AssignmentType == models.AssignmentTypePrimary && assignment.IsOpen() && openPrimary != nil {
+		logger.GetGlobalLogger().Warnf("Child %d already has the open primary assignment %d", assignment.ChildID, openPrimary.ID)
+		return nil, ErrAlreadyExists
 	}
 
-	assignment.CreatedAt = time.Now()
-	assignment.UpdatedAt = time.Now()
+	assignment.CreatedAt = s.clock.Now()
+	assignment.UpdatedAt = s.clock.Now()
 
-	id, err := s.assignmentStore.Create(assignment)
+	id, err := s.assignmentStore.Create(ctx, assignment)
 	if err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			return nil, ErrAlreadyExists
+		}
 		logger.GetGlobalLogger().Errorf("Error creating assignment: %v", err)
 		return nil, ErrInternal
 	}
-	assignment.ID = id
-	return assignment, nil
+	created, err := s.assignmentStore.GetByID(ctx, id)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error fetching created assignment %d: %v", id, err)
+		return nil, ErrInternal
+	}
+	return created, nil
 }
 
 // GetAssignmentByID fetches an assignment by ID.
-func (s *AssignmentServiceImpl) GetAssignmentByID(id int) (*models.Assignment, error) {
-	assignment, err := s.assignmentStore.GetByID(id)
+func (s *AssignmentServiceImpl) GetAssignmentByID(ctx context.Context, id int) (*models.Assignment, error) {
+	assignment, err := s.assignmentStore.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			return nil, ErrNotFound
@@ -144,9 +201,9 @@ func (s *AssignmentServiceImpl) GetAssignmentByID(id int) (*models.Assignment, e
 }
 
 // EndAssignment updates an existing assignment.
-func (s *AssignmentServiceImpl) EndAssignment(assignmentID int) error {
+func (s *AssignmentServiceImpl) EndAssignment(ctx context.Context, assignmentID int) error {
 	// Fetch the assignment to ensure it exists
-	assignment, err := s.assignmentStore.GetByID(assignmentID)
+	assignment, err := s.assignmentStore.GetByID(ctx, assignmentID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			return ErrNotFound
@@ -156,15 +213,15 @@ func (s *AssignmentServiceImpl) EndAssignment(assignmentID int) error {
 
 	// Business rule: An assignment cannot be ended if it has already ended.
 	if assignment.EndDate != nil {
-		return errors.New("assignment has already ended")
+		return ErrAssignmentAlreadyEnded
 	}
 
 	// Set the EndDate to now
-	now := time.Now()
+	now := s.clock.Now()
 	assignment.EndDate = &now
 	assignment.UpdatedAt = now
 
-	err = s.assignmentStore.EndAssignment(assignmentID)
+	err = s.assignmentStore.EndAssignment(ctx, assignmentID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			return ErrNotFound
@@ -175,8 +232,8 @@ func (s *AssignmentServiceImpl) EndAssignment(assignmentID int) error {
 }
 
 // DeleteAssignment deletes an assignment by ID.
-func (s *AssignmentServiceImpl) DeleteAssignment(id int) error {
-	err := s.assignmentStore.Delete(id)
+func (s *AssignmentServiceImpl) DeleteAssignment(ctx context.Context, id int) error {
+	err := s.assignmentStore.Delete(ctx, id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			return ErrNotFound
@@ -186,23 +243,41 @@ func (s *AssignmentServiceImpl) DeleteAssignment(id int) error {
 	return nil
 }
 
-// GetAssignmentHistoryForChild fetches all assignments for a specific child.
-func (s *AssignmentServiceImpl) GetAssignmentHistoryForChild(childID int) ([]models.Assignment, error) {
+// GetAssignmentHistoryForChild fetches the assignments of a specific child matching the query.
+func (s *AssignmentServiceImpl) GetAssignmentHistoryForChild(ctx context.Context, childID int, query models.ListQuery) ([]models.Assignment, error) {
 	// Validate ChildID
-	_, err := s.childStore.GetByID(childID)
+	_, err := s.childStore.GetByID(ctx, childID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.GetGlobalLogger().Errorf("Child with ID %d not found", childID)
-			return nil, errors.New("child not found")
+			return nil, ErrChildNotFound
 		}
 		logger.GetGlobalLogger().Errorf("Error fetching child by ID %d: %v", childID, err)
 		return nil, ErrInternal
 	}
 
-	assignments, err := s.assignmentStore.GetAssignmentHistoryForChild(childID)
+	assignments, err := s.assignmentStore.List(ctx, query.Where("child_id", models.OperatorEqual, childID))
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error fetching assignment history for child ID %d: %v", childID, err)
+		if errors.Is(err, data.ErrInvalidInput) {
+			return nil, ErrInvalidInput
+		}
 		return nil, ErrInternal
 	}
 	return assignments, nil
 }
+
+// openPrimaryAssignment returns the open primary assignment of a child other than the excluded one, or nil if there is none.
+func (s *AssignmentServiceImpl) openPrimaryAssignment(ctx context.Context, childID int, excludeAssignmentID int) (*models.Assignment, error) {
+	assignments, err := s.assignmentStore.GetAssignmentHistoryForChild(ctx, childID)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error fetching assignment history for child ID %d: %v", childID, err)
+		return nil, ErrInternal
+	}
+	for _, assignment := range assignments {
+		if assignment.ID != excludeAssignmentID && assignment.AssignmentType == models.AssignmentTypePrimary && assignment.IsOpen() {
+			return &assignment, nil
+		}
+	}
+	return nil, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"kitadoc-backend/data"
 	"kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
@@ -32,7 +33,7 @@ func TestCreateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ChildID:   1,
@@ -42,15 +43,18 @@ func TestCreateAssignment(t *testing.T) {
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 
-		mockChildStore.On("GetByID", assignment.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", assignment.TeacherID).Return(expectedTeacher, nil).Once()
-		mockAssignmentStore.On("Create", mock.AnythingOfType("*models.Assignment")).Return(1, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, assignment.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, assignment.TeacherID).Return(expectedTeacher, nil).Once()
+		mockAssignmentStore.On("GetAssignmentHistoryForChild", mock.Anything, assignment.ChildID).Return([]models.Assignment{}, nil).Once()
+		mockAssignmentStore.On("Create", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(1, nil).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, 1).Return(&models.Assignment{ID: 1, ChildID: 1, TeacherID: 1, AssignmentType: models.AssignmentTypePrimary}, nil).Once()
 
-		createdAssignment, err := service.CreateAssignment(assignment)
+		createdAssignment, err := service.CreateAssignment(t.Context(), assignment)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, createdAssignment)
 		assert.Equal(t, 1, createdAssignment.ID)
+		assert.Equal(t, models.AssignmentTypePrimary, assignment.AssignmentType, "the first open assignment is stored as primary")
 		mockAssignmentStore.AssertExpectations(t)
 		mockChildStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
@@ -62,20 +66,20 @@ func TestCreateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ChildID: 0, // Invalid ChildID
 		}
 
-		createdAssignment, err := service.CreateAssignment(assignment)
+		createdAssignment, err := service.CreateAssignment(t.Context(), assignment)
 
 		assert.Error(t, err)
-		assert.Equal(t, services.ErrInvalidInput, err)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
 		assert.Nil(t, createdAssignment)
-		mockAssignmentStore.AssertNotCalled(t, "Create")
-		mockChildStore.AssertNotCalled(t, "GetByID")
-		mockTeacherStore.AssertNotCalled(t, "GetByID")
+		mockAssignmentStore.AssertNotCalled(t, "Create", mock.Anything)
+		mockChildStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockTeacherStore.AssertNotCalled(t, "GetByID", mock.Anything)
 	})
 
 	// Test case 3: Child not found
@@ -84,7 +88,7 @@ func TestCreateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ChildID:   99, // Non-existent child
@@ -92,14 +96,14 @@ func TestCreateAssignment(t *testing.T) {
 			StartDate: time.Now().Add(-24 * time.Hour),
 		}
 
-		mockChildStore.On("GetByID", assignment.ChildID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, assignment.ChildID).Return(nil, data.ErrNotFound).Once()
 
-		createdAssignment, err := service.CreateAssignment(assignment)
+		createdAssignment, err := service.CreateAssignment(t.Context(), assignment)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "child not found")
 		assert.Nil(t, createdAssignment)
-		mockAssignmentStore.AssertNotCalled(t, "Create")
+		mockAssignmentStore.AssertNotCalled(t, "Create", mock.Anything)
 		mockChildStore.AssertExpectations(t)
 	})
 
@@ -109,7 +113,7 @@ func TestCreateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ChildID:   1,
@@ -118,26 +122,44 @@ func TestCreateAssignment(t *testing.T) {
 		}
 		expectedChild := &models.Child{ID: 1}
 
-		mockChildStore.On("GetByID", assignment.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", assignment.TeacherID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, assignment.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, assignment.TeacherID).Return(nil, data.ErrNotFound).Once()
 
-		createdAssignment, err := service.CreateAssignment(assignment)
+		createdAssignment, err := service.CreateAssignment(t.Context(), assignment)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "teacher not found")
 		assert.Nil(t, createdAssignment)
-		mockAssignmentStore.AssertNotCalled(t, "Create")
+		mockAssignmentStore.AssertNotCalled(t, "Create", mock.Anything)
 		mockChildStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
 	})
 
+	t.Run("deactivated teacher", func(t *testing.T) {
+		mockAssignmentStore := new(mocks.MockAssignmentStore)
+		mockChildStore := new(mocks.MockChildStore)
+		mockTeacherStore := new(mocks.MockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
+
+		deactivatedAt := time.Now().AddDate(0, -1, 0)
+		assignment := &models.Assignment{ChildID: 1, TeacherID: 2, StartDate: time.Now().Add(-24 * time.Hour)}
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, 2).Return(&models.Teacher{ID: 2, DeactivatedAt: &deactivatedAt}, nil).Once()
+
+		createdAssignment, err := service.CreateAssignment(t.Context(), assignment)
+
+		assert.Equal(t, services.ErrTeacherDeactivated, err)
+		assert.Nil(t, createdAssignment)
+		mockAssignmentStore.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
 	// Test case 5: Assignment start date in the future
 	t.Run("future start date", func(t *testing.T) {
 		// Create fresh mocks for this test case
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ChildID:   1,
@@ -147,15 +169,15 @@ func TestCreateAssignment(t *testing.T) {
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 
-		mockChildStore.On("GetByID", assignment.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", assignment.TeacherID).Return(expectedTeacher, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, assignment.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, assignment.TeacherID).Return(expectedTeacher, nil).Once()
 
-		createdAssignment, err := service.CreateAssignment(assignment)
+		createdAssignment, err := service.CreateAssignment(t.Context(), assignment)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "assignment start date cannot be in the future")
 		assert.Nil(t, createdAssignment)
-		mockAssignmentStore.AssertNotCalled(t, "Create")
+		mockAssignmentStore.AssertNotCalled(t, "Create", mock.Anything)
 		mockChildStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
 	})
@@ -166,7 +188,7 @@ func TestCreateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		startDate := time.Now().Add(-24 * time.Hour)
 		endDate := time.Now().Add(-48 * time.Hour) // Before start date
@@ -179,17 +201,17 @@ func TestCreateAssignment(t *testing.T) {
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 
-		mockChildStore.On("GetByID", assignment.ChildID).Return(expectedChild, nil)
-		mockTeacherStore.On("GetByID", assignment.TeacherID).Return(expectedTeacher, nil)
+		mockChildStore.On("GetByID", mock.Anything, assignment.ChildID).Return(expectedChild, nil)
+		mockTeacherStore.On("GetByID", mock.Anything, assignment.TeacherID).Return(expectedTeacher, nil)
 
-		createdAssignment, err := service.CreateAssignment(assignment)
+		createdAssignment, err := service.CreateAssignment(t.Context(), assignment)
 
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid input")
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
 		assert.Nil(t, createdAssignment)
-		mockAssignmentStore.AssertNotCalled(t, "Create")
-		mockChildStore.AssertNotCalled(t, "GetByID")
-		mockTeacherStore.AssertNotCalled(t, "GetByID")
+		mockAssignmentStore.AssertNotCalled(t, "Create", mock.Anything)
+		mockChildStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockTeacherStore.AssertNotCalled(t, "GetByID", mock.Anything)
 	})
 
 	// Test case 7: Internal error during assignment creation
@@ -198,7 +220,7 @@ func TestCreateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ChildID:   1,
@@ -209,11 +231,12 @@ func TestCreateAssignment(t *testing.T) {
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 
-		mockChildStore.On("GetByID", assignment.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", assignment.TeacherID).Return(expectedTeacher, nil).Once()
-		mockAssignmentStore.On("Create", mock.AnythingOfType("*models.Assignment")).Return(0, errors.New("db error")).Once()
+		mockChildStore.On("GetByID", mock.Anything, assignment.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, assignment.TeacherID).Return(expectedTeacher, nil).Once()
+		mockAssignmentStore.On("GetAssignmentHistoryForChild", mock.Anything, assignment.ChildID).Return([]models.Assignment{}, nil).Once()
+		mockAssignmentStore.On("Create", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(0, errors.New("db error")).Once()
 
-		createdAssignment, err := service.CreateAssignment(assignment)
+		createdAssignment, err := service.CreateAssignment(t.Context(), assignment)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -222,6 +245,50 @@ func TestCreateAssignment(t *testing.T) {
 		mockChildStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
 	})
+
+	t.Run("defaults to secondary if the child has an open primary assignment", func(t *testing.T) {
+		mockAssignmentStore := new(mocks.MockAssignmentStore)
+		mockChildStore := new(mocks.MockChildStore)
+		mockTeacherStore := new(mocks.MockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
+
+		assignment := &models.Assignment{ChildID: 1, TeacherID: 2, StartDate: time.Now().Add(-24 * time.Hour)}
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, 2).Return(&models.Teacher{ID: 2}, nil).Once()
+		mockAssignmentStore.On("GetAssignmentHistoryForChild", mock.Anything, 1).Return([]models.Assignment{
+			{ID: 5, ChildID: 1, TeacherID: 1, AssignmentType: models.AssignmentTypePrimary},
+		}, nil).Once()
+		mockAssignmentStore.On("Create", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(6, nil).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, 6).Return(assignment, nil).Once()
+
+		createdAssignment, err := service.CreateAssignment(t.Context(), assignment)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.AssignmentTypeSecondary, createdAssignment.AssignmentType)
+		mockAssignmentStore.AssertExpectations(t)
+	})
+
+	t.Run("second open primary assignment", func(t *testing.T) {
+		mockAssignmentStore := new(mocks.MockAssignmentStore)
+		mockChildStore := new(mocks.MockChildStore)
+		mockTeacherStore := new(mocks.MockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
+
+		endDate := time.Now().Add(-48 * time.Hour)
+		assignment := &models.Assignment{ChildID: 1, TeacherID: 2, AssignmentType: models.AssignmentTypePrimary, StartDate: time.Now().Add(-24 * time.Hour)}
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, 2).Return(&models.Teacher{ID: 2}, nil).Once()
+		mockAssignmentStore.On("GetAssignmentHistoryForChild", mock.Anything, 1).Return([]models.Assignment{
+			{ID: 4, ChildID: 1, TeacherID: 3, AssignmentType: models.AssignmentTypePrimary, EndDate: &endDate},
+			{ID: 5, ChildID: 1, TeacherID: 1, AssignmentType: models.AssignmentTypePrimary},
+		}, nil).Once()
+
+		createdAssignment, err := service.CreateAssignment(t.Context(), assignment)
+
+		assert.Equal(t, services.ErrAlreadyExists, err)
+		assert.Nil(t, createdAssignment)
+		mockAssignmentStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
 }
 
 func TestGetAssignmentByID(t *testing.T) {
@@ -230,13 +297,13 @@ func TestGetAssignmentByID(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 1
 		expectedAssignment := &models.Assignment{ID: assignmentID}
-		mockAssignmentStore.On("GetByID", assignmentID).Return(expectedAssignment, nil).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignmentID).Return(expectedAssignment, nil).Once()
 
-		assignment, err := service.GetAssignmentByID(assignmentID)
+		assignment, err := service.GetAssignmentByID(t.Context(), assignmentID)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, assignment)
@@ -249,12 +316,12 @@ func TestGetAssignmentByID(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 99
-		mockAssignmentStore.On("GetByID", assignmentID).Return(nil, data.ErrNotFound).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignmentID).Return(nil, data.ErrNotFound).Once()
 
-		assignment, err := service.GetAssignmentByID(assignmentID)
+		assignment, err := service.GetAssignmentByID(t.Context(), assignmentID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
@@ -267,12 +334,12 @@ func TestGetAssignmentByID(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 1
-		mockAssignmentStore.On("GetByID", assignmentID).Return(nil, errors.New("db error")).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignmentID).Return(nil, errors.New("db error")).Once()
 
-		assignment, err := service.GetAssignmentByID(assignmentID)
+		assignment, err := service.GetAssignmentByID(t.Context(), assignmentID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -287,7 +354,7 @@ func TestUpdateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ID:        1,
@@ -297,10 +364,10 @@ func TestUpdateAssignment(t *testing.T) {
 		}
 		existingAssignment := &models.Assignment{ID: 1}
 
-		mockAssignmentStore.On("GetByID", assignment.ID).Return(existingAssignment, nil).Once()
-		mockAssignmentStore.On("Update", mock.AnythingOfType("*models.Assignment")).Return(nil).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignment.ID).Return(existingAssignment, nil).Once()
+		mockAssignmentStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(nil).Once()
 
-		err := service.UpdateAssignment(assignment)
+		err := service.UpdateAssignment(t.Context(), assignment)
 
 		assert.NoError(t, err)
 		mockAssignmentStore.AssertExpectations(t)
@@ -311,19 +378,19 @@ func TestUpdateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ID:      1,
 			ChildID: 0, // Invalid ChildID
 		}
 
-		err := service.UpdateAssignment(assignment)
+		err := service.UpdateAssignment(t.Context(), assignment)
 
 		assert.Error(t, err)
-		assert.Equal(t, services.ErrInvalidInput, err)
-		mockAssignmentStore.AssertNotCalled(t, "GetByID")
-		mockAssignmentStore.AssertNotCalled(t, "Update")
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		mockAssignmentStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockAssignmentStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 3: Assignment not found during fetch
@@ -331,7 +398,7 @@ func TestUpdateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ID:        99, // Non-existent ID
@@ -339,14 +406,14 @@ func TestUpdateAssignment(t *testing.T) {
 			TeacherID: 1,
 			StartDate: time.Now().Add(-24 * time.Hour),
 		}
-		mockAssignmentStore.On("GetByID", assignment.ID).Return(nil, data.ErrNotFound).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignment.ID).Return(nil, data.ErrNotFound).Once()
 
-		err := service.UpdateAssignment(assignment)
+		err := service.UpdateAssignment(t.Context(), assignment)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
 		mockAssignmentStore.AssertExpectations(t)
-		mockAssignmentStore.AssertNotCalled(t, "Update")
+		mockAssignmentStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 4: Internal error during fetch
@@ -354,7 +421,7 @@ func TestUpdateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ID:        1,
@@ -362,14 +429,14 @@ func TestUpdateAssignment(t *testing.T) {
 			TeacherID: 1,
 			StartDate: time.Now().Add(-24 * time.Hour),
 		}
-		mockAssignmentStore.On("GetByID", assignment.ID).Return(nil, errors.New("db error")).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignment.ID).Return(nil, errors.New("db error")).Once()
 
-		err := service.UpdateAssignment(assignment)
+		err := service.UpdateAssignment(t.Context(), assignment)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
 		mockAssignmentStore.AssertExpectations(t)
-		mockAssignmentStore.AssertNotCalled(t, "Update")
+		mockAssignmentStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 5: Internal error during update
@@ -377,7 +444,7 @@ func TestUpdateAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignment := &models.Assignment{
 			ID:        1,
@@ -387,10 +454,10 @@ func TestUpdateAssignment(t *testing.T) {
 		}
 		existingAssignment := &models.Assignment{ID: 1}
 
-		mockAssignmentStore.On("GetByID", assignment.ID).Return(existingAssignment, nil).Once()
-		mockAssignmentStore.On("Update", mock.AnythingOfType("*models.Assignment")).Return(errors.New("db error")).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignment.ID).Return(existingAssignment, nil).Once()
+		mockAssignmentStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Assignment")).Return(errors.New("db error")).Once()
 
-		err := service.UpdateAssignment(assignment)
+		err := service.UpdateAssignment(t.Context(), assignment)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -404,12 +471,12 @@ func TestDeleteAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 1
-		mockAssignmentStore.On("Delete", assignmentID).Return(nil).Once()
+		mockAssignmentStore.On("Delete", mock.Anything, assignmentID).Return(nil).Once()
 
-		err := service.DeleteAssignment(assignmentID)
+		err := service.DeleteAssignment(t.Context(), assignmentID)
 
 		assert.NoError(t, err)
 		mockAssignmentStore.AssertExpectations(t)
@@ -420,12 +487,12 @@ func TestDeleteAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 99
-		mockAssignmentStore.On("Delete", assignmentID).Return(data.ErrNotFound).Once()
+		mockAssignmentStore.On("Delete", mock.Anything, assignmentID).Return(data.ErrNotFound).Once()
 
-		err := service.DeleteAssignment(assignmentID)
+		err := service.DeleteAssignment(t.Context(), assignmentID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
@@ -437,12 +504,12 @@ func TestDeleteAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 1
-		mockAssignmentStore.On("Delete", assignmentID).Return(errors.New("db error")).Once()
+		mockAssignmentStore.On("Delete", mock.Anything, assignmentID).Return(errors.New("db error")).Once()
 
-		err := service.DeleteAssignment(assignmentID)
+		err := service.DeleteAssignment(t.Context(), assignmentID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -456,7 +523,7 @@ func TestEndAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 1
 		assignment := &models.Assignment{
@@ -464,10 +531,10 @@ func TestEndAssignment(t *testing.T) {
 			StartDate: time.Now().Add(-48 * time.Hour),
 			EndDate:   nil, // Not yet ended
 		}
-		mockAssignmentStore.On("GetByID", assignmentID).Return(assignment, nil).Once()
-		mockAssignmentStore.On("EndAssignment", assignmentID).Return(nil).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignmentID).Return(assignment, nil).Once()
+		mockAssignmentStore.On("EndAssignment", mock.Anything, assignmentID).Return(nil).Once()
 
-		err := service.EndAssignment(assignmentID)
+		err := service.EndAssignment(t.Context(), assignmentID)
 
 		assert.NoError(t, err)
 		mockAssignmentStore.AssertExpectations(t)
@@ -478,17 +545,17 @@ func TestEndAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 99
-		mockAssignmentStore.On("GetByID", assignmentID).Return(nil, data.ErrNotFound).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignmentID).Return(nil, data.ErrNotFound).Once()
 
-		err := service.EndAssignment(assignmentID)
+		err := service.EndAssignment(t.Context(), assignmentID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
 		mockAssignmentStore.AssertExpectations(t)
-		mockAssignmentStore.AssertNotCalled(t, "EndAssignment")
+		mockAssignmentStore.AssertNotCalled(t, "EndAssignment", mock.Anything)
 	})
 
 	// Test case 3: Assignment already ended
@@ -496,7 +563,7 @@ func TestEndAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 1
 		now := time.Now()
@@ -505,14 +572,14 @@ func TestEndAssignment(t *testing.T) {
 			StartDate: time.Now().Add(-48 * time.Hour),
 			EndDate:   &now, // Already ended
 		}
-		mockAssignmentStore.On("GetByID", assignmentID).Return(assignment, nil).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignmentID).Return(assignment, nil).Once()
 
-		err := service.EndAssignment(assignmentID)
+		err := service.EndAssignment(t.Context(), assignmentID)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "assignment has already ended")
 		mockAssignmentStore.AssertExpectations(t)
-		mockAssignmentStore.AssertNotCalled(t, "EndAssignment")
+		mockAssignmentStore.AssertNotCalled(t, "EndAssignment", mock.Anything)
 	})
 
 	// Test case 4: Internal error during fetch
@@ -520,17 +587,17 @@ func TestEndAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 1
-		mockAssignmentStore.On("GetByID", assignmentID).Return(nil, errors.New("db error")).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignmentID).Return(nil, errors.New("db error")).Once()
 
-		err := service.EndAssignment(assignmentID)
+		err := service.EndAssignment(t.Context(), assignmentID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
 		mockAssignmentStore.AssertExpectations(t)
-		mockAssignmentStore.AssertNotCalled(t, "EndAssignment")
+		mockAssignmentStore.AssertNotCalled(t, "EndAssignment", mock.Anything)
 	})
 
 	// Test case 5: Internal error during end assignment
@@ -538,7 +605,7 @@ func TestEndAssignment(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		assignmentID := 1
 		assignment := &models.Assignment{
@@ -546,10 +613,10 @@ func TestEndAssignment(t *testing.T) {
 			StartDate: time.Now().Add(-48 * time.Hour),
 			EndDate:   nil,
 		}
-		mockAssignmentStore.On("GetByID", assignmentID).Return(assignment, nil).Once()
-		mockAssignmentStore.On("EndAssignment", assignmentID).Return(errors.New("db error")).Once()
+		mockAssignmentStore.On("GetByID", mock.Anything, assignmentID).Return(assignment, nil).Once()
+		mockAssignmentStore.On("EndAssignment", mock.Anything, assignmentID).Return(errors.New("db error")).Once()
 
-		err := service.EndAssignment(assignmentID)
+		err := service.EndAssignment(t.Context(), assignmentID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -572,7 +639,7 @@ func TestGetAssignmentHistoryForChild(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		childID := 1
 		expectedChild := &models.Child{ID: childID}
@@ -580,10 +647,10 @@ func TestGetAssignmentHistoryForChild(t *testing.T) {
 			{ID: 1, ChildID: childID},
 			{ID: 2, ChildID: childID},
 		}
-		mockChildStore.On("GetByID", childID).Return(expectedChild, nil).Once()
-		mockAssignmentStore.On("GetAssignmentHistoryForChild", childID).Return(expectedAssignments, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(expectedChild, nil).Once()
+		mockAssignmentStore.On("List", mock.Anything, models.ListQuery{}.Where("child_id", models.OperatorEqual, childID)).Return(expectedAssignments, nil).Once()
 
-		assignments, err := service.GetAssignmentHistoryForChild(childID)
+		assignments, err := service.GetAssignmentHistoryForChild(t.Context(), childID, models.ListQuery{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, assignments)
@@ -597,18 +664,18 @@ func TestGetAssignmentHistoryForChild(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		childID := 99
-		mockChildStore.On("GetByID", childID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(nil, data.ErrNotFound).Once()
 
-		assignments, err := service.GetAssignmentHistoryForChild(childID)
+		assignments, err := service.GetAssignmentHistoryForChild(t.Context(), childID, models.ListQuery{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "child not found")
 		assert.Nil(t, assignments)
 		mockChildStore.AssertExpectations(t)
-		mockAssignmentStore.AssertNotCalled(t, "GetAssignmentHistoryForChild")
+		mockAssignmentStore.AssertNotCalled(t, "List", mock.Anything)
 	})
 
 	// Test case 3: Internal error during child fetch
@@ -616,18 +683,18 @@ func TestGetAssignmentHistoryForChild(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		childID := 42
-		mockChildStore.On("GetByID", childID).Return(nil, errors.New("db error")).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(nil, errors.New("db error")).Once()
 
-		assignments, err := service.GetAssignmentHistoryForChild(childID)
+		assignments, err := service.GetAssignmentHistoryForChild(t.Context(), childID, models.ListQuery{})
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
 		assert.Nil(t, assignments)
 		mockChildStore.AssertExpectations(t)
-		mockAssignmentStore.AssertNotCalled(t, "GetAssignmentHistoryForChild")
+		mockAssignmentStore.AssertNotCalled(t, "List", mock.Anything)
 	})
 
 	// Test case 4: Internal error during assignment fetch
@@ -635,14 +702,14 @@ func TestGetAssignmentHistoryForChild(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		childID := 1
 		expectedChild := &models.Child{ID: childID}
-		mockChildStore.On("GetByID", childID).Return(expectedChild, nil).Once()
-		mockAssignmentStore.On("GetAssignmentHistoryForChild", childID).Return(nil, errors.New("db error")).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(expectedChild, nil).Once()
+		mockAssignmentStore.On("List", mock.Anything, models.ListQuery{}.Where("child_id", models.OperatorEqual, childID)).Return(nil, errors.New("db error")).Once()
 
-		assignments, err := service.GetAssignmentHistoryForChild(childID)
+		assignments, err := service.GetAssignmentHistoryForChild(t.Context(), childID, models.ListQuery{})
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -666,15 +733,15 @@ func TestGetAllAssignments(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
 		expectedAssignments := []models.Assignment{
 			{ID: 1, ChildID: 1},
 			{ID: 2, ChildID: 2},
 		}
-		mockAssignmentStore.On("GetAllAssignments").Return(expectedAssignments, nil).Once()
+		mockAssignmentStore.On("List", mock.Anything, models.ListQuery{}).Return(expectedAssignments, nil).Once()
 
-		assignments, err := service.GetAllAssignments()
+		assignments, err := service.GetAllAssignments(t.Context(), models.ListQuery{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, assignments)
@@ -686,11 +753,11 @@ func TestGetAllAssignments(t *testing.T) {
 		mockAssignmentStore := new(mocks.MockAssignmentStore)
 		mockChildStore := new(mocks.MockChildStore)
 		mockTeacherStore := new(mocks.MockTeacherStore)
-		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore)
+		service := services.NewAssignmentService(mockAssignmentStore, mockChildStore, mockTeacherStore, nil, clock.System{})
 
-		mockAssignmentStore.On("GetAllAssignments").Return(nil, errors.New("db error")).Once()
+		mockAssignmentStore.On("List", mock.Anything, models.ListQuery{}).Return(nil, errors.New("db error")).Once()
 
-		assignments, err := service.GetAllAssignments()
+		assignments, err := service.GetAllAssignments(t.Context(), models.ListQuery{})
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
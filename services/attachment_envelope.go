@@ -0,0 +1,32 @@
+package services
+
+import (
+	"kitadoc-backend/config"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// encryptedContentType is the content type client-side encrypted attachments are stored and served with.
+const encryptedContentType = "application/octet-stream"
+
+// checkEnvelope checks the envelope of an uploaded attachment against the client encryption mode: envelopes are
+// refused while client-side encryption is disabled and required in the required mode.
+func checkEnvelope(logger *logrus.Entry, mode string, envelope *models.AttachmentEnvelope) error {
+	if envelope == nil {
+		if mode == config.ClientEncryptionRequired {
+			logger.Warn("Plain attachment uploaded, client-side encryption is required")
+			return ErrClientEncryptionRequired
+		}
+		return nil
+	}
+	if mode == "" {
+		logger.Warn("Encrypted attachment uploaded, client-side encryption is disabled")
+		return ErrClientEncryptionDisabled
+	}
+	if err := models.ValidateAttachmentEnvelope(*envelope); err != nil {
+		logger.WithError(err).Warn("Invalid attachment envelope")
+		return invalidInput(err)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// checkInQRCodeSize is the width and height of the printed check-in QR codes, in pixels.
+const checkInQRCodeSize = 512
+
+// checkInRepeatWindow is how long a repeated scan of the same code is taken for the first one, e.g. when a child
+// holds the card in front of the tablet for a while.
+const checkInRepeatWindow = time.Minute
+
+// AttendanceService defines the interface for the arrivals and departures of children and their check-in QR codes.
+type AttendanceService interface {
+	// RecordAttendance records an arrival or departure entered by a teacher.
+	RecordAttendance(logger *logrus.Entry, ctx context.Context, record *models.AttendanceRecord, user *models.User) (*models.AttendanceRecord, error)
+	// CheckIn records the arrival or departure of the child whose check-in code was scanned. created is false if
+	// the scan repeated the previous one and the previous record is returned.
+	CheckIn(logger *logrus.Entry, ctx context.Context, checkIn *models.CheckIn, user *models.User) (record *models.AttendanceRecord, created bool, err error)
+	GetAttendanceByID(logger *logrus.Entry, ctx context.Context, id int) (*models.AttendanceRecord, error)
+	// DeleteAttendance deletes a wrong record, e.g. a scan of the wrong card.
+	DeleteAttendance(logger *logrus.Entry, ctx context.Context, id int) error
+	GetChildAttendance(logger *logrus.Entry, ctx context.Context, childID int, from models.Date, to models.Date) ([]models.AttendanceRecord, error)
+	GetDayAttendance(logger *logrus.Entry, ctx context.Context, day models.Date) ([]models.AttendanceRecord, error)
+	// GetCheckInQRCode returns the check-in QR code of a child as PNG, a code is issued on first use.
+	GetCheckInQRCode(logger *logrus.Entry, ctx context.Context, childID int) ([]byte, error)
+	// RevokeCheckInCode invalidates the check-in code of a child, e.g. after the card was lost. The next QR code
+	// fetched for the child has a new code.
+	RevokeCheckInCode(logger *logrus.Entry, ctx context.Context, childID int) error
+}
+
+// AttendanceServiceImpl implements AttendanceService.
+type AttendanceServiceImpl struct {
+	attendanceStore data.AttendanceStore
+	childStore      data.ChildStore
+	clock           clock.Clock
+}
+
+// NewAttendanceService creates a new AttendanceServiceImpl.
+func NewAttendanceService(attendanceStore data.AttendanceStore, childStore data.ChildStore, clock clock.Clock) *AttendanceServiceImpl {
+	return &AttendanceServiceImpl{
+		attendanceStore: attendanceStore,
+		childStore:      childStore,
+		clock:           clock,
+	}
+}
+
+// RecordAttendance records an arrival or departure of a child entered by a teacher.
+func (service *AttendanceServiceImpl) RecordAttendance(logger *logrus.Entry, ctx context.Context, record *models.AttendanceRecord, user *models.User) (*models.AttendanceRecord, error) {
+	if err := models.ValidateAttendanceRecord(*record); err != nil {
+		logger.WithError(err).Warn("Invalid input for RecordAttendance")
+		return nil, invalidInput(err)
+	}
+	if _, err := service.getChild(logger, ctx, record.ChildID); err != nil {
+		return nil, err
+	}
+	record.Source = models.AttendanceSourceManual
+	record.DeviceName = nil
+	return service.create(logger, ctx, record, user)
+}
+
+// CheckIn records the arrival or departure of the child of a scanned check-in code. Unknown and revoked codes are
+// rejected with ErrCheckInCodeUnknown.
+func (service *AttendanceServiceImpl) CheckIn(logger *logrus.Entry, ctx context.Context, checkIn *models.CheckIn, user *models.User) (*models.AttendanceRecord, bool, error) {
+	if err := models.ValidateCheckIn(*checkIn); err != nil {
+		logger.WithError(err).Warn("Invalid input for CheckIn")
+		return nil, false, invalidInput(err)
+	}
+	code := strings.TrimPrefix(strings.TrimSpace(checkIn.Code), models.CheckInCodePrefix)
+	childID, err := service.attendanceStore.GetChildIDByCheckInCode(ctx, hashLinkToken(code))
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("device_name", checkIn.DeviceName).Warn("Unknown check-in code scanned")
+			return nil, false, ErrCheckInCodeUnknown
+		}
+		logger.WithError(err).Error("Error looking up check-in code")
+		return nil, false, ErrInternal
+	}
+	child, err := service.getChild(logger, ctx, childID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	scannedAt := service.clock.Now()
+	if checkIn.ScannedAt != nil {
+		scannedAt = *checkIn.ScannedAt
+	}
+	latest, err := service.attendanceStore.GetLatestForChild(ctx, childID, models.Today(scannedAt))
+	if err != nil && !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching latest attendance for check-in")
+		return nil, false, ErrInternal
+	}
+	if latest != nil && latest.Source == models.AttendanceSourceQRScan && !scannedAt.Before(latest.RecordedAt) &&
+		scannedAt.Sub(latest.RecordedAt) < checkInRepeatWindow && (checkIn.EventType == nil || *checkIn.EventType == latest.EventType) {
+		logger.WithFields(logrus.Fields{"child_id": childID, "record_id": latest.ID}).Info("Repeated check-in scan ignored")
+		latest.ChildFirstName = child.FirstName
+		return latest, false, nil
+	}
+
+	record := &models.AttendanceRecord{
+		ChildID:    childID,
+		EventType:  models.AttendanceArrival,
+		RecordedAt: scannedAt,
+		Source:     models.AttendanceSourceQRScan,
+		DeviceName: &checkIn.DeviceName,
+	}
+	switch {
+	case checkIn.EventType != nil:
+		record.EventType = *checkIn.EventType
+	case latest != nil && latest.EventType == models.AttendanceArrival:
+		record.EventType = models.AttendanceDeparture
+	}
+	created, err := service.create(logger, ctx, record, user)
+	if err != nil {
+		return nil, false, err
+	}
+	created.ChildFirstName = child.FirstName
+	return created, true, nil
+}
+
+// GetAttendanceByID fetches an attendance record by ID.
+func (service *AttendanceServiceImpl) GetAttendanceByID(logger *logrus.Entry, ctx context.Context, id int) (*models.AttendanceRecord, error) {
+	record, err := service.attendanceStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrAttendanceNotFound
+		}
+		logger.WithError(err).WithField("record_id", id).Error("Error fetching attendance record")
+		return nil, ErrInternal
+	}
+	return record, nil
+}
+
+// DeleteAttendance deletes an attendance record.
+func (service *AttendanceServiceImpl) DeleteAttendance(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.attendanceStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrAttendanceNotFound
+		}
+		logger.WithError(err).WithField("record_id", id).Error("Error deleting attendance record")
+		return ErrInternal
+	}
+	logger.WithField("record_id", id).Info("Attendance record deleted successfully")
+	return nil
+}
+
+// GetChildAttendance fetches the attendance records of a child between two days, both included.
+func (service *AttendanceServiceImpl) GetChildAttendance(logger *logrus.Entry, ctx context.Context, childID int, from models.Date, to models.Date) ([]models.AttendanceRecord, error) {
+	if from.After(to.Time) {
+		logger.WithFields(logrus.Fields{"from": from, "to": to}).Warn("Invalid attendance range")
+		return nil, ErrInvalidInput
+	}
+	if _, err := service.getChild(logger, ctx, childID); err != nil {
+		return nil, err
+	}
+	records, err := service.attendanceStore.GetForChild(ctx, childID, from, to)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching attendance of child")
+		return nil, ErrInternal
+	}
+	return records, nil
+}
+
+// GetDayAttendance fetches the attendance records of all children on a day.
+func (service *AttendanceServiceImpl) GetDayAttendance(logger *logrus.Entry, ctx context.Context, day models.Date) ([]models.AttendanceRecord, error) {
+	records, err := service.attendanceStore.GetForDay(ctx, day)
+	if err != nil {
+		logger.WithError(err).WithField("day", day).Error("Error fetching attendance of day")
+		return nil, ErrInternal
+	}
+	return records, nil
+}
+
+// GetCheckInQRCode returns the check-in QR code of a child as PNG. The QR code holds a random code, not the name
+// of the child.
+func (service *AttendanceServiceImpl) GetCheckInQRCode(logger *logrus.Entry, ctx context.Context, childID int) ([]byte, error) {
+	if _, err := service.getChild(logger, ctx, childID); err != nil {
+		return nil, err
+	}
+	code, err := service.attendanceStore.GetCheckInCode(ctx, childID)
+	if errors.Is(err, data.ErrNotFound) {
+		code, err = newLinkToken()
+		if err != nil {
+			logger.WithError(err).Error("Error generating check-in code")
+			return nil, ErrInternal
+		}
+		if err := service.attendanceStore.SetCheckInCode(ctx, childID, hashLinkToken(code), code); err != nil {
+			logger.WithError(err).WithField("child_id", childID).Error("Error issuing check-in code")
+			return nil, ErrInternal
+		}
+		logger.WithField("child_id", childID).Info("Check-in code issued")
+	} else if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching check-in code")
+		return nil, ErrInternal
+	}
+
+	png, err := qrcode.Encode(models.CheckInCodePrefix+code, qrcode.Medium, checkInQRCodeSize)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error rendering check-in QR code")
+		return nil, ErrInternal
+	}
+	return png, nil
+}
+
+// RevokeCheckInCode deletes the check-in code of a child.
+func (service *AttendanceServiceImpl) RevokeCheckInCode(logger *logrus.Entry, ctx context.Context, childID int) error {
+	if _, err := service.getChild(logger, ctx, childID); err != nil {
+		return err
+	}
+	if err := service.attendanceStore.DeleteCheckInCode(ctx, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error revoking check-in code")
+		return ErrInternal
+	}
+	logger.WithField("child_id", childID).Info("Check-in code revoked")
+	return nil
+}
+
+// create stores a record dated in the facility, records cannot lie in the future.
+func (service *AttendanceServiceImpl) create(logger *logrus.Entry, ctx context.Context, record *models.AttendanceRecord, user *models.User) (*models.AttendanceRecord, error) {
+	if record.RecordedAt.After(service.clock.Now()) {
+		logger.WithField("recorded_at", record.RecordedAt).Warn("Attendance recorded for the future")
+		return nil, ErrAttendanceInFuture
+	}
+	record.AttendanceDate = models.Today(record.RecordedAt)
+	record.RecordedByUserID = &user.ID
+
+	id, err := service.attendanceStore.Create(ctx, record)
+	if err != nil {
+		if errors.Is(err, data.ErrForeignKeyConstraint) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", record.ChildID).Error("Error recording attendance")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"record_id": id, "child_id": record.ChildID, "event_type": record.EventType, "source": record.Source}).Info("Attendance recorded successfully")
+	return service.GetAttendanceByID(logger, ctx, id)
+}
+
+func (service *AttendanceServiceImpl) getChild(logger *logrus.Entry, ctx context.Context, childID int) (*models.Child, error) {
+	child, err := service.childStore.GetByID(ctx, childID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for attendance")
+		return nil, ErrInternal
+	}
+	return child, nil
+}
@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendanceService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	user := &models.User{ID: 2, Username: "tablet.eingang", Role: string(data.RoleTeacher)}
+	now := time.Date(2024, time.October, 15, 15, 0, 0, 0, time.UTC)
+	today := models.Today(now)
+	codeHash := func(code string) string {
+		sum := sha256.Sum256([]byte(code))
+		return hex.EncodeToString(sum[:])
+	}
+
+	setup := func() (*services.AttendanceServiceImpl, *datamocks.MockAttendanceStore) {
+		attendanceStore := new(datamocks.MockAttendanceStore)
+		childStore := new(datamocks.MockChildStore)
+		childStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1, FirstName: "Anna"}, nil).Maybe()
+		childStore.On("GetByID", mock.Anything, 99).Return(nil, data.ErrNotFound).Maybe()
+		attendanceStore.On("GetChildIDByCheckInCode", mock.Anything, codeHash("secret")).Return(1, nil).Maybe()
+		attendanceStore.On("GetChildIDByCheckInCode", mock.Anything, mock.Anything).Return(0, data.ErrNotFound).Maybe()
+		return services.NewAttendanceService(attendanceStore, childStore, clock.NewFrozen(now)), attendanceStore
+	}
+	expectCreate := func(store *datamocks.MockAttendanceStore, eventType string) {
+		store.On("Create", mock.Anything, mock.MatchedBy(func(record *models.AttendanceRecord) bool {
+			return record.EventType == eventType && record.Source == models.AttendanceSourceQRScan && record.AttendanceDate == today
+		})).Return(7, nil).Once()
+		store.On("GetByID", mock.Anything, 7).Return(&models.AttendanceRecord{ID: 7, ChildID: 1, EventType: eventType}, nil).Once()
+	}
+	scan := &models.CheckIn{Code: models.CheckInCodePrefix + "secret", DeviceName: "Tablet Eingang"}
+
+	t.Run("first scan of the day records the arrival", func(t *testing.T) {
+		service, store := setup()
+		store.On("GetLatestForChild", mock.Anything, 1, today).Return(nil, data.ErrNotFound).Once()
+		expectCreate(store, models.AttendanceArrival)
+
+		record, created, err := service.CheckIn(logger, ctx, scan, user)
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "Anna", record.ChildFirstName)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("scan after the arrival records the departure", func(t *testing.T) {
+		service, store := setup()
+		arrival := &models.AttendanceRecord{ID: 3, EventType: models.AttendanceArrival, RecordedAt: now.Add(-7 * time.Hour), Source: models.AttendanceSourceQRScan}
+		store.On("GetLatestForChild", mock.Anything, 1, today).Return(arrival, nil).Once()
+		expectCreate(store, models.AttendanceDeparture)
+
+		_, created, err := service.CheckIn(logger, ctx, &models.CheckIn{Code: "secret", DeviceName: "Tablet Eingang"}, user)
+		require.NoError(t, err)
+		assert.True(t, created)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("repeated scan returns the previous record", func(t *testing.T) {
+		service, store := setup()
+		arrival := &models.AttendanceRecord{ID: 3, EventType: models.AttendanceArrival, RecordedAt: now.Add(-20 * time.Second), Source: models.AttendanceSourceQRScan}
+		store.On("GetLatestForChild", mock.Anything, 1, today).Return(arrival, nil).Once()
+
+		record, created, err := service.CheckIn(logger, ctx, scan, user)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, 3, record.ID)
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		service, store := setup()
+		_, _, err := service.CheckIn(logger, ctx, &models.CheckIn{Code: "revoked", DeviceName: "Tablet Eingang"}, user)
+		assert.ErrorIs(t, err, services.ErrCheckInCodeUnknown)
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("records cannot lie in the future", func(t *testing.T) {
+		service, store := setup()
+		record := &models.AttendanceRecord{ChildID: 1, EventType: models.AttendanceArrival, RecordedAt: now.Add(time.Hour)}
+		_, err := service.RecordAttendance(logger, ctx, record, user)
+		assert.ErrorIs(t, err, services.ErrAttendanceInFuture)
+
+		record = &models.AttendanceRecord{ChildID: 99, EventType: models.AttendanceArrival, RecordedAt: now}
+		_, err = service.RecordAttendance(logger, ctx, record, user)
+		assert.ErrorIs(t, err, services.ErrChildNotFound)
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("QR code issues a code on first use", func(t *testing.T) {
+		service, store := setup()
+		store.On("GetCheckInCode", mock.Anything, 1).Return("", data.ErrNotFound).Once()
+		store.On("SetCheckInCode", mock.Anything, 1, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil).Once()
+
+		png, err := service.GetCheckInQRCode(logger, ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("\x89PNG"), png[:4])
+		store.AssertExpectations(t)
+	})
+}
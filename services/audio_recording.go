@@ -61,7 +61,7 @@ func (service *AudioAnalysisServiceImpl) ProcessAudio(
 ) ([]models.ChildAnalysisObject, error) {
 	logger.Info("Starting audio transcription")
 
-	service.UpdateProcessStatus(logger, processId, "transcribing")
+	service.UpdateProcessStatus(logger, ctx, processId, "transcribing")
 	transcription, err := service.transcribeAudio(ctx, logger, fileContent)
 	if err != nil {
 		logger.WithError(err).Error("Failed to transcribe audio")
@@ -71,7 +71,7 @@ func (service *AudioAnalysisServiceImpl) ProcessAudio(
 	logger.Debugf("Transcription result: %s", transcription)
 	logger.Info("Starting analysis of transcription")
 
-	service.UpdateProcessStatus(logger, processId, "analysing")
+	service.UpdateProcessStatus(logger, ctx, processId, "analysing")
 	analysis, err := service.analyseTranscription(ctx, logger, transcription)
 	if err != nil {
 		logger.WithError(err).Error("Failed to analyse transcription")
@@ -177,7 +177,7 @@ func (service *AudioAnalysisServiceImpl) analyseTranscription(
 	}
 
 	// Fetch children data
-	children, err := service.childStore.GetAll()
+	children, err := service.childStore.GetAll(ctx)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get all children")
 		return []models.ChildAnalysisObject{}, fmt.Errorf("failed to get all children: %w", err)
@@ -216,7 +216,7 @@ func (service *AudioAnalysisServiceImpl) analyseTranscription(
 	}
 
 	// Fetch category data
-	categories, err := service.categoryStore.GetAll()
+	categories, err := service.categoryStore.GetAll(ctx)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get all categories")
 		return []models.ChildAnalysisObject{}, fmt.Errorf("failed to get all categories: %w", err)
@@ -228,17 +228,21 @@ func (service *AudioAnalysisServiceImpl) analyseTranscription(
 		Description string `json:"description"`
 	}
 
-	categoryData := make([]CategoryData, len(categories))
-	for i, c := range categories {
+	// Archived categories get no new entries, so the analysis must not suggest them.
+	categoryData := make([]CategoryData, 0, len(categories))
+	for _, c := range categories {
+		if c.IsArchived() {
+			continue
+		}
 		var description string
 		if c.Description != nil {
 			description = *c.Description
 		}
-		categoryData[i] = CategoryData{
+		categoryData = append(categoryData, CategoryData{
 			ID:          c.ID,
 			Name:        c.Name,
 			Description: description,
-		}
+		})
 	}
 
 	categoryJSON, err := json.Marshal(categoryData)
@@ -311,9 +315,9 @@ func (service *AudioAnalysisServiceImpl) analyseTranscription(
 }
 
 // Checks if a process entry in the database was created and updates its status.
-func (service *AudioAnalysisServiceImpl) UpdateProcessStatus(logger *logrus.Entry, processId int, status string) {
+func (service *AudioAnalysisServiceImpl) UpdateProcessStatus(logger *logrus.Entry, ctx context.Context, processId int, status string) {
 	if processId != -1 {
-		if err := service.processStore.Update(&models.Process{ProcessId: processId, Status: status}); err != nil {
+		if err := service.processStore.Update(ctx, &models.Process{ProcessId: processId, Status: status}); err != nil {
 			logger.WithError(err).Error("Failed to update process status")
 		}
 	}
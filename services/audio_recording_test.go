@@ -52,15 +52,15 @@ func TestAudioAnalysisService_AnalyzeAudio(t *testing.T) {
 		mockCategoryStore := new(mocks.MockCategoryStore)
 		mockProcessStore := new(mocks.MockProcessStore)
 
-		mockChildStore.On("GetAll").Return([]models.Child{{ID: 1, FirstName: "John", LastName: "Doe"}}, nil)
+		mockChildStore.On("GetAll", mock.Anything).Return([]models.Child{{ID: 1, FirstName: "John", LastName: "Doe"}}, nil)
 		description := ""
-		mockCategoryStore.On("GetAll").Return([]models.Category{{ID: 1, Name: "General", Description: &description}}, nil)
+		mockCategoryStore.On("GetAll", mock.Anything).Return([]models.Category{{ID: 1, Name: "General", Description: &description}}, nil)
 
-		mockProcessStore.On("Update", mock.MatchedBy(func(p *models.Process) bool {
+		mockProcessStore.On("Update", mock.Anything, mock.MatchedBy(func(p *models.Process) bool {
 			return p.ProcessId == 42 && p.Status == "transcribing"
 		})).Return(nil)
 
-		mockProcessStore.On("Update", mock.MatchedBy(func(p *models.Process) bool {
+		mockProcessStore.On("Update", mock.Anything, mock.MatchedBy(func(p *models.Process) bool {
 			return p.ProcessId == 42 && p.Status == "analysing"
 		})).Return(nil)
 
@@ -92,10 +92,10 @@ func TestAudioAnalysisService_AnalyzeAudio(t *testing.T) {
 		mockCategoryStore := new(mocks.MockCategoryStore)
 		mockProcessStore := new(mocks.MockProcessStore)
 
-		mockChildStore.On("GetAll").Return([]models.Child{}, nil)
-		mockCategoryStore.On("GetAll").Return([]models.Category{}, nil)
+		mockChildStore.On("GetAll", mock.Anything).Return([]models.Child{}, nil)
+		mockCategoryStore.On("GetAll", mock.Anything).Return([]models.Category{}, nil)
 
-		mockProcessStore.On("Update", mock.MatchedBy(func(p *models.Process) bool {
+		mockProcessStore.On("Update", mock.Anything, mock.MatchedBy(func(p *models.Process) bool {
 			return p.ProcessId == 42 && p.Status == "transcribing"
 		})).Return(nil)
 
@@ -139,14 +139,14 @@ func TestAudioAnalysisService_AnalyzeAudio(t *testing.T) {
 		mockProcessStore := new(mocks.MockProcessStore)
 
 		// Expectations for AnalyzeTranscription
-		mockChildStore.On("GetAll").Return([]models.Child{}, nil)
-		mockCategoryStore.On("GetAll").Return([]models.Category{}, nil)
+		mockChildStore.On("GetAll", mock.Anything).Return([]models.Child{}, nil)
+		mockCategoryStore.On("GetAll", mock.Anything).Return([]models.Category{}, nil)
 
 		// Expect process updates
-		mockProcessStore.On("Update", mock.MatchedBy(func(p *models.Process) bool {
+		mockProcessStore.On("Update", mock.Anything, mock.MatchedBy(func(p *models.Process) bool {
 			return p.ProcessId == 42 && p.Status == "transcribing"
 		})).Return(nil)
-		mockProcessStore.On("Update", mock.MatchedBy(func(p *models.Process) bool {
+		mockProcessStore.On("Update", mock.Anything, mock.MatchedBy(func(p *models.Process) bool {
 			return p.ProcessId == 42 && p.Status == "analysing"
 		})).Return(nil)
 
@@ -183,7 +183,7 @@ func TestAudioAnalysisService_AnalyzeAudio(t *testing.T) {
 		mockProcessStore := new(mocks.MockProcessStore)
 
 		// Process status update expected for transcription
-		mockProcessStore.On("Update", mock.MatchedBy(func(p *models.Process) bool {
+		mockProcessStore.On("Update", mock.Anything, mock.MatchedBy(func(p *models.Process) bool {
 			return p.ProcessId == 42 && p.Status == "transcribing"
 		})).Return(nil)
 
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AuditLogService defines the interface for audit trail operations.
+type AuditLogService interface {
+	Record(logger *logrus.Entry, ctx context.Context, entry *models.AuditLogEntry) error
+	GetAuditLog(logger *logrus.Entry, ctx context.Context) ([]models.AuditLogEntry, error)
+	GetAuditTrail(logger *logrus.Entry, ctx context.Context, entityType string, entityID int) ([]models.AuditLogEntry, error)
+	GetActorAuditLog(logger *logrus.Entry, ctx context.Context, actorUserID int) ([]models.AuditLogEntry, error)
+}
+
+// AuditLogServiceImpl implements AuditLogService.
+type AuditLogServiceImpl struct {
+	auditLogStore data.AuditLogStore
+}
+
+// NewAuditLogService creates a new AuditLogServiceImpl.
+func NewAuditLogService(auditLogStore data.AuditLogStore) *AuditLogServiceImpl {
+	return &AuditLogServiceImpl{auditLogStore: auditLogStore}
+}
+
+// Record appends an entry to the audit log.
+func (service *AuditLogServiceImpl) Record(logger *logrus.Entry, ctx context.Context, entry *models.AuditLogEntry) error {
+	id, err := service.auditLogStore.Create(ctx, entry)
+	if err != nil {
+		logger.WithError(err).WithField("action", entry.Action).Error("Error writing audit log entry")
+		return ErrInternal
+	}
+	entry.ID = id
+	return nil
+}
+
+// GetAuditLog fetches the complete audit log.
+func (service *AuditLogServiceImpl) GetAuditLog(logger *logrus.Entry, ctx context.Context) ([]models.AuditLogEntry, error) {
+	entries, err := service.auditLogStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching audit log")
+		return nil, ErrInternal
+	}
+	return entries, nil
+}
+
+// GetAuditTrail fetches the audit trail of a single entity.
+func (service *AuditLogServiceImpl) GetAuditTrail(logger *logrus.Entry, ctx context.Context, entityType string, entityID int) ([]models.AuditLogEntry, error) {
+	entries, err := service.auditLogStore.GetForEntity(ctx, entityType, entityID)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"entity_type": entityType, "entity_id": entityID}).Error("Error fetching audit trail")
+		return nil, ErrInternal
+	}
+	return entries, nil
+}
+
+// GetActorAuditLog fetches what a single user did.
+func (service *AuditLogServiceImpl) GetActorAuditLog(logger *logrus.Entry, ctx context.Context, actorUserID int) ([]models.AuditLogEntry, error) {
+	entries, err := service.auditLogStore.GetForActor(ctx, actorUserID)
+	if err != nil {
+		logger.WithError(err).WithField("actor_user_id", actorUserID).Error("Error fetching audit log of user")
+		return nil, ErrInternal
+	}
+	return entries, nil
+}
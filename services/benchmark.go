@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// benchmarkMinChildren is the number of children below which the coverage of a facility is not shared, as it
+// would tell about single children.
+const benchmarkMinChildren = 5
+
+// BenchmarkService defines the interface for the opt-in benchmarks comparing the documentation quality of the
+// facility to other facilities. Facilities exchange anonymized aggregates only, never single entries or children.
+type BenchmarkService interface {
+	// GetOwnAggregate returns the anonymized aggregate of the facility in a month, given as YYYY-MM, to be
+	// shared with other facilities.
+	GetOwnAggregate(logger *logrus.Entry, ctx context.Context, month string) (*models.BenchmarkAggregate, error)
+	// ImportAggregate stores the aggregate another facility shared.
+	ImportAggregate(logger *logrus.Entry, ctx context.Context, aggregate *models.BenchmarkAggregate) error
+	// GetComparison compares the figures of the facility in a month to the imported aggregates of the month.
+	GetComparison(logger *logrus.Entry, ctx context.Context, month string) (*models.BenchmarkComparison, error)
+}
+
+// BenchmarkServiceImpl implements BenchmarkService.
+type BenchmarkServiceImpl struct {
+	qualityReportService QualityReportService
+	benchmarkStore       data.BenchmarkStore
+	pseudonymKey         []byte
+	enabled              bool
+	minPeers             int // Facilities needed before a figure is compared
+	clock                clock.Clock
+}
+
+// NewBenchmarkService creates a new BenchmarkServiceImpl.
+func NewBenchmarkService(
+	qualityReportService QualityReportService,
+	benchmarkStore data.BenchmarkStore,
+	pseudonymKey []byte,
+	enabled bool,
+	minPeers int,
+	clock clock.Clock,
+) *BenchmarkServiceImpl {
+	return &BenchmarkServiceImpl{
+		qualityReportService: qualityReportService,
+		benchmarkStore:       benchmarkStore,
+		pseudonymKey:         pseudonymKey,
+		enabled:              enabled,
+		minPeers:             minPeers,
+		clock:                clock,
+	}
+}
+
+// GetOwnAggregate computes the aggregate from the facility figures of the quality report of the month.
+func (service *BenchmarkServiceImpl) GetOwnAggregate(logger *logrus.Entry, ctx context.Context, month string) (*models.BenchmarkAggregate, error) {
+	if !service.enabled {
+		return nil, ErrBenchmarkingDisabled
+	}
+	report, err := service.qualityReportService.GetQualityReport(logger, ctx, month)
+	if err != nil {
+		return nil, err
+	}
+
+	figures := report.Facility
+	aggregate := &models.BenchmarkAggregate{
+		Facility:            service.facilityPseudonym(),
+		Month:               report.Month,
+		ChildrenBand:        childrenBand(figures.ChildrenAssigned),
+		MedianApprovalHours: figures.MedianApprovalHours,
+	}
+	if figures.ChildrenAssigned >= benchmarkMinChildren {
+		aggregate.Coverage = figures.Coverage
+	}
+	return aggregate, nil
+}
+
+// ImportAggregate validates and stores the aggregate of another facility, replacing an earlier import of the
+// facility in the same month.
+func (service *BenchmarkServiceImpl) ImportAggregate(logger *logrus.Entry, ctx context.Context, aggregate *models.BenchmarkAggregate) error {
+	if !service.enabled {
+		return ErrBenchmarkingDisabled
+	}
+	if err := models.ValidateBenchmarkAggregate(*aggregate); err != nil {
+		logger.WithError(err).Warn("Invalid benchmark aggregate")
+		return invalidInput(err)
+	}
+	if aggregate.Facility == service.facilityPseudonym() {
+		return ErrBenchmarkOwnFacility
+	}
+
+	aggregate.ImportedAt = service.clock.Now().UTC()
+	if err := service.benchmarkStore.Upsert(ctx, aggregate); err != nil {
+		logger.WithError(err).WithField("month", aggregate.Month).Error("Error importing benchmark aggregate")
+		return ErrInternal
+	}
+	return nil
+}
+
+// GetComparison compares the own aggregate of the month to the aggregates of the other facilities. A figure is
+// only compared once at least the configured number of facilities reported it.
+func (service *BenchmarkServiceImpl) GetComparison(logger *logrus.Entry, ctx context.Context, month string) (*models.BenchmarkComparison, error) {
+	own, err := service.GetOwnAggregate(logger, ctx, month)
+	if err != nil {
+		return nil, err
+	}
+	peers, err := service.benchmarkStore.GetForMonth(ctx, own.Month)
+	if err != nil {
+		logger.WithError(err).WithField("month", own.Month).Error("Error fetching benchmark aggregates")
+		return nil, ErrInternal
+	}
+
+	var coverages, approvalHours []float64
+	for _, peer := range peers {
+		if peer.Coverage != nil {
+			coverages = append(coverages, *peer.Coverage)
+		}
+		if peer.MedianApprovalHours != nil {
+			approvalHours = append(approvalHours, *peer.MedianApprovalHours)
+		}
+	}
+	return &models.BenchmarkComparison{
+		Month:         own.Month,
+		Own:           *own,
+		Peers:         len(peers),
+		Coverage:      service.distribution(coverages, own.Coverage),
+		ApprovalHours: service.distribution(approvalHours, own.MedianApprovalHours),
+	}, nil
+}
+
+// distribution returns the quartiles of the figures of the other facilities and the rank of the own figure,
+// nil if too few facilities reported the figure.
+func (service *BenchmarkServiceImpl) distribution(values []float64, own *float64) *models.BenchmarkDistribution {
+	if len(values) == 0 || len(values) < service.minPeers {
+		return nil
+	}
+	slices.Sort(values)
+	distribution := &models.BenchmarkDistribution{
+		Peers:  len(values),
+		P25:    roundToTenth(quantile(values, 0.25)),
+		Median: roundToTenth(quantile(values, 0.5)),
+		P75:    roundToTenth(quantile(values, 0.75)),
+	}
+	if own != nil {
+		lower := 0
+		for _, value := range values {
+			if value < *own {
+				lower++
+			}
+		}
+		rank := roundToTenth(100 * float64(lower) / float64(len(values)))
+		distribution.Rank = &rank
+	}
+	return distribution
+}
+
+// facilityPseudonym returns the pseudonym the facility shares its aggregates under. It is derived from the
+// pseudonym key, so that it stays the same across months.
+func (service *BenchmarkServiceImpl) facilityPseudonym() string {
+	mac := hmac.New(sha256.New, service.pseudonymKey)
+	mac.Write([]byte("facility"))
+	return "E-" + strings.ToUpper(hex.EncodeToString(mac.Sum(nil)[:6]))
+}
+
+// quantile returns the q-quantile of the sorted values, interpolating between neighbouring values.
+func quantile(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	lower := int(position)
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (position-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// childrenBand returns the band of the number of children of a facility.
+func childrenBand(children int) string {
+	switch {
+	case children < 25:
+		return models.ChildrenBandSmall
+	case children < 50:
+		return models.ChildrenBandMedium
+	case children < 100:
+		return models.ChildrenBandLarge
+	default:
+		return models.ChildrenBandHuge
+	}
+}
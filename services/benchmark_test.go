@@ -0,0 +1,99 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubQualityReports returns the same facility figures for every month.
+type stubQualityReports struct {
+	services.QualityReportService
+	figures models.QualityFigures
+}
+
+func (stub *stubQualityReports) GetQualityReport(logger *logrus.Entry, ctx context.Context, month string) (*models.QualityReport, error) {
+	return &models.QualityReport{Month: month, Facility: stub.figures}, nil
+}
+
+func TestBenchmarkService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2024, time.August, 2, 10, 0, 0, 0, time.UTC)
+	float := func(value float64) *float64 { return &value }
+
+	setup := func(enabled bool, figures models.QualityFigures) (*services.BenchmarkServiceImpl, *datamocks.MockBenchmarkStore) {
+		store := new(datamocks.MockBenchmarkStore)
+		service := services.NewBenchmarkService(&stubQualityReports{figures: figures}, store, []byte("pseudonym-key"), enabled, 3, clock.NewFrozen(now))
+		return service, store
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		service, store := setup(false, models.QualityFigures{})
+		_, err := service.GetComparison(logger, ctx, "2024-07")
+		assert.ErrorIs(t, err, services.ErrBenchmarkingDisabled)
+		err = service.ImportAggregate(logger, ctx, &models.BenchmarkAggregate{Facility: "E-1", Month: "2024-07", ChildrenBand: models.ChildrenBandSmall})
+		assert.ErrorIs(t, err, services.ErrBenchmarkingDisabled)
+		store.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("own aggregate hides small facilities", func(t *testing.T) {
+		service, _ := setup(true, models.QualityFigures{ChildrenAssigned: 4, Coverage: float(75), MedianApprovalHours: float(12.5)})
+		aggregate, err := service.GetOwnAggregate(logger, ctx, "2024-07")
+		require.NoError(t, err)
+		assert.Equal(t, models.ChildrenBandSmall, aggregate.ChildrenBand)
+		assert.Nil(t, aggregate.Coverage, "the coverage of four children tells about single children")
+		assert.Equal(t, 12.5, *aggregate.MedianApprovalHours)
+		assert.Regexp(t, `^E-[0-9A-F]{12}$`, aggregate.Facility)
+
+		again, err := service.GetOwnAggregate(logger, ctx, "2024-06")
+		require.NoError(t, err)
+		assert.Equal(t, aggregate.Facility, again.Facility, "the pseudonym stays the same across months")
+	})
+
+	t.Run("import", func(t *testing.T) {
+		service, store := setup(true, models.QualityFigures{ChildrenAssigned: 30, Coverage: float(80)})
+		own, err := service.GetOwnAggregate(logger, ctx, "2024-07")
+		require.NoError(t, err)
+
+		err = service.ImportAggregate(logger, ctx, &models.BenchmarkAggregate{Facility: "E-1", Month: "July", ChildrenBand: "12"})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		err = service.ImportAggregate(logger, ctx, own)
+		assert.ErrorIs(t, err, services.ErrBenchmarkOwnFacility)
+		store.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+
+		store.On("Upsert", mock.Anything, mock.MatchedBy(func(aggregate *models.BenchmarkAggregate) bool {
+			return aggregate.Facility == "E-1" && aggregate.ImportedAt.Equal(now)
+		})).Return(nil).Once()
+		require.NoError(t, service.ImportAggregate(logger, ctx, &models.BenchmarkAggregate{Facility: "E-1", Month: "2024-07",
+			ChildrenBand: models.ChildrenBandMedium, Coverage: float(60)}))
+		store.AssertExpectations(t)
+	})
+
+	t.Run("comparison needs enough peers", func(t *testing.T) {
+		service, store := setup(true, models.QualityFigures{ChildrenAssigned: 30, Coverage: float(80), MedianApprovalHours: float(20)})
+		store.On("GetForMonth", mock.Anything, "2024-07").Return([]models.BenchmarkAggregate{
+			{Facility: "E-1", Coverage: float(60), MedianApprovalHours: float(10)},
+			{Facility: "E-2", Coverage: float(70), MedianApprovalHours: float(30)},
+			{Facility: "E-3", Coverage: float(90)},
+			{Facility: "E-4", Coverage: float(100)},
+		}, nil)
+
+		comparison, err := service.GetComparison(logger, ctx, "2024-07")
+		require.NoError(t, err)
+		assert.Equal(t, 4, comparison.Peers)
+		require.NotNil(t, comparison.Coverage)
+		assert.Equal(t, models.BenchmarkDistribution{Peers: 4, P25: 67.5, Median: 80, P75: 92.5, Rank: float(50)}, *comparison.Coverage)
+		assert.Nil(t, comparison.ApprovalHours, "two facilities reporting approval times are too few to compare")
+	})
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BootstrapService defines the interface for installing the default data of a new instance.
+type BootstrapService interface {
+	Bootstrap(logger *logrus.Entry, ctx context.Context, actingUserID int) (*models.BootstrapResult, error)
+}
+
+// BootstrapServiceImpl implements BootstrapService.
+type BootstrapServiceImpl struct {
+	bootstrapStore data.BootstrapStore
+	clock          clock.Clock
+}
+
+// NewBootstrapService creates a new BootstrapServiceImpl.
+func NewBootstrapService(bootstrapStore data.BootstrapStore, clock clock.Clock) *BootstrapServiceImpl {
+	return &BootstrapServiceImpl{bootstrapStore: bootstrapStore, clock: clock}
+}
+
+// Bootstrap installs the default categories and groups. It succeeds only once per instance,
+// later calls return ErrAlreadyExists so that deleted defaults are not brought back.
+func (service *BootstrapServiceImpl) Bootstrap(logger *logrus.Entry, ctx context.Context, actingUserID int) (*models.BootstrapResult, error) {
+	result, err := service.bootstrapStore.Run(ctx, models.DefaultCategories(), models.DefaultGroups(), &actingUserID, service.clock.Now())
+	if err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			logger.Warn("Instance has already been bootstrapped")
+			return nil, ErrAlreadyExists
+		}
+		logger.WithError(err).Error("Error bootstrapping instance")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{
+		"created_categories": len(result.CreatedCategories),
+		"skipped_categories": len(result.SkippedCategories),
+		"created_groups":     len(result.CreatedGroups),
+		"skipped_groups":     len(result.SkippedGroups),
+	}).Info("Instance bootstrapped successfully")
+	return result, nil
+}
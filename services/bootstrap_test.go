@@ -0,0 +1,63 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBootstrap(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	isActingUser := mock.MatchedBy(func(userID *int) bool { return userID != nil && *userID == 1 })
+	now := time.Date(2025, time.March, 5, 9, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mockBootstrapStore := new(datamocks.MockBootstrapStore)
+		service := services.NewBootstrapService(mockBootstrapStore, clock.NewFrozen(now))
+		expected := &models.BootstrapResult{CreatedCategories: []models.Category{{ID: 1, Name: "Bewegung"}}}
+		mockBootstrapStore.On("Run", mock.Anything, models.DefaultCategories(), models.DefaultGroups(), isActingUser, now).Return(expected, nil).Once()
+
+		result, err := service.Bootstrap(logger, ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+		mockBootstrapStore.AssertExpectations(t)
+	})
+
+	t.Run("default groups are valid", func(t *testing.T) {
+		for _, group := range models.DefaultGroups() {
+			assert.NoError(t, models.ValidateGroup(group), group.Name)
+		}
+	})
+
+	t.Run("already bootstrapped", func(t *testing.T) {
+		mockBootstrapStore := new(datamocks.MockBootstrapStore)
+		service := services.NewBootstrapService(mockBootstrapStore, clock.NewFrozen(now))
+		mockBootstrapStore.On("Run", mock.Anything, mock.Anything, mock.Anything, isActingUser, now).Return(nil, data.ErrConflict).Once()
+
+		result, err := service.Bootstrap(logger, ctx, 1)
+		assert.Nil(t, result)
+		assert.Equal(t, services.ErrAlreadyExists, err)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		mockBootstrapStore := new(datamocks.MockBootstrapStore)
+		service := services.NewBootstrapService(mockBootstrapStore, clock.NewFrozen(now))
+		mockBootstrapStore.On("Run", mock.Anything, mock.Anything, mock.Anything, isActingUser, now).Return(nil, errors.New("db error")).Once()
+
+		result, err := service.Bootstrap(logger, ctx, 1)
+		assert.Nil(t, result)
+		assert.Equal(t, services.ErrInternal, err)
+	})
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BreakGlassService defines the interface for the emergency access of a facility whose only admin has forgotten
+// their password.
+type BreakGlassService interface {
+	// Seal creates a new credential and revokes the one sealed before. The code is only returned here.
+	Seal(logger *logrus.Entry, ctx context.Context, actingUserID int) (*models.BreakGlassSealed, error)
+	GetCredentials(logger *logrus.Entry, ctx context.Context) ([]models.BreakGlassCredential, error)
+	// Revoke revokes a credential, e.g. because the envelope was opened. Revoking an activated credential ends
+	// the emergency access granted by it.
+	Revoke(logger *logrus.Entry, ctx context.Context, id int, actingUserID int) error
+	// Activate checks the code of the sealed credential and grants admin access to the break-glass account until
+	// the configured validity has passed. Every attempt is recorded in the audit log. Without a sealed credential
+	// it returns ErrInvalidBreakGlassCode like for a wrong code, so that callers cannot tell.
+	Activate(logger *logrus.Entry, ctx context.Context, activation models.BreakGlassActivation) (*models.BreakGlassSession, error)
+}
+
+// BreakGlassServiceImpl implements BreakGlassService.
+type BreakGlassServiceImpl struct {
+	breakGlassStore  data.BreakGlassStore
+	userStore        data.UserStore
+	authSessionStore data.AuthSessionStore
+	auditLogService  AuditLogService
+	config           *config.Config
+	clock            clock.Clock
+}
+
+// NewBreakGlassService creates a new BreakGlassServiceImpl.
+func NewBreakGlassService(breakGlassStore data.BreakGlassStore, userStore data.UserStore, authSessionStore data.AuthSessionStore, auditLogService AuditLogService, cfg *config.Config, clock clock.Clock) *BreakGlassServiceImpl {
+	return &BreakGlassServiceImpl{
+		breakGlassStore:  breakGlassStore,
+		userStore:        userStore,
+		authSessionStore: authSessionStore,
+		auditLogService:  auditLogService,
+		config:           cfg,
+		clock:            clock,
+	}
+}
+
+// breakGlassCodeGroups is the number of access code groups of a break-glass code. Three groups of 8 characters
+// from the access code alphabet are 120 bits, too many to guess, so wrong codes do not revoke the credential.
+const breakGlassCodeGroups = 3
+
+// newBreakGlassCode returns a random code, written in groups of four characters to be copied by hand.
+func newBreakGlassCode() (string, error) {
+	var code strings.Builder
+	for range breakGlassCodeGroups {
+		group, err := newAccessCode()
+		if err != nil {
+			return "", err
+		}
+		code.WriteString(group)
+	}
+	var grouped []string
+	for i := 0; i < code.Len(); i += 4 {
+		grouped = append(grouped, code.String()[i:i+4])
+	}
+	return strings.Join(grouped, "-"), nil
+}
+
+// Seal creates a new break-glass credential.
+func (service *BreakGlassServiceImpl) Seal(logger *logrus.Entry, ctx context.Context, actingUserID int) (*models.BreakGlassSealed, error) {
+	code, err := newBreakGlassCode()
+	if err != nil {
+		logger.WithError(err).Error("Error generating break-glass code")
+		return nil, ErrInternal
+	}
+	codeHash, err := bcrypt.GenerateFromPassword([]byte(normalizeAccessCode(code)), bcrypt.DefaultCost)
+	if err != nil {
+		logger.WithError(err).Error("Error hashing break-glass code")
+		return nil, ErrInternal
+	}
+
+	id, err := service.breakGlassStore.Seal(ctx, &models.BreakGlassCredential{
+		CodeHash:       string(codeHash),
+		SealedByUserID: &actingUserID,
+		SealedAt:       service.clock.Now(),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Error sealing break-glass credential")
+		return nil, ErrInternal
+	}
+	credential, err := service.breakGlassStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("credential_id", id).Error("Error fetching sealed break-glass credential")
+		return nil, ErrInternal
+	}
+
+	// The code is only handed out once the credential is recorded.
+	if err := service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:      models.AuditActionSealBreakGlass,
+		EntityType:  "break_glass_credential",
+		EntityID:    &id,
+		ActorUserID: &actingUserID,
+	}); err != nil {
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{"credential_id": id, "user_id": actingUserID}).Warn("Break-glass credential sealed")
+	return &models.BreakGlassSealed{Credential: credential, Code: code}, nil
+}
+
+// GetCredentials fetches all break-glass credentials.
+func (service *BreakGlassServiceImpl) GetCredentials(logger *logrus.Entry, ctx context.Context) ([]models.BreakGlassCredential, error) {
+	credentials, err := service.breakGlassStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching break-glass credentials")
+		return nil, ErrInternal
+	}
+	return credentials, nil
+}
+
+// Revoke revokes a break-glass credential.
+func (service *BreakGlassServiceImpl) Revoke(logger *logrus.Entry, ctx context.Context, id int, actingUserID int) error {
+	credential, err := service.breakGlassStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrBreakGlassNotFound
+		}
+		logger.WithError(err).WithField("credential_id", id).Error("Error fetching break-glass credential")
+		return ErrInternal
+	}
+	if credential.RevokedAt != nil {
+		return ErrBreakGlassRevoked
+	}
+	now := service.clock.Now()
+	if err := service.breakGlassStore.Revoke(ctx, id, now); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrBreakGlassRevoked // Revoked concurrently
+		}
+		logger.WithError(err).WithField("credential_id", id).Error("Error revoking break-glass credential")
+		return ErrInternal
+	}
+	// The break-glass account only logs in by activation, so ending its sessions ends the emergency access.
+	if credential.ActivatedUserID != nil {
+		if _, err := service.authSessionStore.RevokeAllForUser(ctx, *credential.ActivatedUserID, now); err != nil {
+			logger.WithError(err).WithField("credential_id", id).Error("Error ending break-glass sessions")
+			return ErrInternal
+		}
+	}
+
+	// The credential is revoked either way, so a failing audit write is only logged.
+	_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:      models.AuditActionRevokeBreakGlass,
+		EntityType:  "break_glass_credential",
+		EntityID:    &id,
+		ActorUserID: &actingUserID,
+	})
+	logger.WithFields(logrus.Fields{"credential_id": id, "user_id": actingUserID}).Warn("Break-glass credential revoked")
+	return nil
+}
+
+// Activate activates the sealed break-glass credential.
+func (service *BreakGlassServiceImpl) Activate(logger *logrus.Entry, ctx context.Context, activation models.BreakGlassActivation) (*models.BreakGlassSession, error) {
+	activation.Reason = strings.TrimSpace(activation.Reason)
+	if err := models.ValidateBreakGlassActivation(activation); err != nil {
+		logger.WithError(err).Warn("Invalid input for break-glass activation")
+		return nil, invalidInput(err)
+	}
+
+	credential, err := service.breakGlassStore.GetSealed(ctx)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			service.recordFailedActivation(logger, ctx, nil)
+			return nil, ErrInvalidBreakGlassCode
+		}
+		logger.WithError(err).Error("Error fetching sealed break-glass credential")
+		return nil, ErrInternal
+	}
+	logger = logger.WithField("credential_id", credential.ID)
+	if err := bcrypt.CompareHashAndPassword([]byte(credential.CodeHash), []byte(normalizeAccessCode(activation.Code))); err != nil {
+		service.recordFailedActivation(logger, ctx, &credential.ID)
+		return nil, ErrInvalidBreakGlassCode
+	}
+
+	user, err := service.breakGlassUser(logger, ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := service.clock.Now()
+	expiresAt := now.Add(service.config.BreakGlass.Validity)
+	// The access runs in a session like a login, so that a logout or revoking the credential ends it. The
+	// session cannot be refreshed, its refresh token is never handed out.
+	refreshToken, err := newLinkToken()
+	if err != nil {
+		logger.WithError(err).Error("Error generating break-glass refresh token")
+		return nil, ErrInternal
+	}
+	sessionID, err := service.authSessionStore.Create(ctx, &models.AuthSession{
+		UserID:           user.ID,
+		RefreshTokenHash: hashLinkToken(refreshToken),
+		CreatedAt:        now,
+		ExpiresAt:        expiresAt,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Error creating break-glass session")
+		return nil, ErrInternal
+	}
+
+	credential.ActivatedAt = &now
+	credential.ActivationReason = activation.Reason
+	credential.ActivatedUserID = &user.ID
+	credential.ExpiresAt = &expiresAt
+	alerts, err := service.activationAlerts(credential)
+	if err != nil {
+		logger.WithError(err).Error("Error building break-glass activation alert")
+		return nil, ErrInternal
+	}
+	if err := service.breakGlassStore.Activate(ctx, credential, alerts); err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			logger.Warn("Break-glass credential was activated or revoked concurrently")
+			return nil, ErrInvalidBreakGlassCode
+		}
+		logger.WithError(err).Error("Error activating break-glass credential")
+		return nil, ErrInternal
+	}
+
+	// The access is only granted once it is recorded.
+	if err := service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:      models.AuditActionActivateBreakGlass,
+		EntityType:  "break_glass_credential",
+		EntityID:    &credential.ID,
+		ActorUserID: &user.ID,
+		Details:     fmt.Sprintf("expires_at=%s", expiresAt.Format(time.RFC3339)),
+	}); err != nil {
+		return nil, err
+	}
+
+	// The expiry of the token is checked against the real time when it is parsed, like the one of a login, so
+	// it is not taken from the clock of the service.
+	tokenExpiresAt := time.Now().Add(service.config.BreakGlass.Validity)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":  user.ID,
+		"username": user.Username,
+		"role":     user.Role,
+		"sid":      sessionID,
+		"exp":      tokenExpiresAt.Unix(),
+	})
+	tokenString, err := token.SignedString([]byte(service.config.Server.JWTSecret))
+	if err != nil {
+		logger.WithError(err).Error("Error signing break-glass JWT token")
+		return nil, ErrInternal
+	}
+	// Logged at error level on purpose, so that the activation also reaches the error reporting.
+	logger.WithFields(logrus.Fields{"user_id": user.ID, "expires_at": expiresAt}).Error("Break-glass credential activated, emergency admin access granted")
+	return &models.BreakGlassSession{
+		CredentialID: credential.ID,
+		Username:     user.Username,
+		Token:        tokenString,
+		ExpiresAt:    time.Unix(tokenExpiresAt.Unix(), 0).UTC(),
+	}, nil
+}
+
+// breakGlassUser returns the break-glass account, it is created on the first activation. Its password is random
+// and never handed out, the account only logs in by activation.
+func (service *BreakGlassServiceImpl) breakGlassUser(logger *logrus.Entry, ctx context.Context) (*models.User, error) {
+	user, err := service.userStore.GetUserByUsername(ctx, models.BreakGlassUsername)
+	if err == nil {
+		if user.Role != string(data.RoleAdmin) {
+			logger.WithField("user_id", user.ID).Error("The break-glass username is taken by an account that is not an admin")
+			return nil, ErrInternal
+		}
+		return user, nil
+	}
+	if !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).Error("Error fetching break-glass account")
+		return nil, ErrInternal
+	}
+
+	password := make([]byte, 32)
+	if _, err := rand.Read(password); err != nil {
+		logger.WithError(err).Error("Error generating break-glass account password")
+		return nil, ErrInternal
+	}
+	// bcrypt only uses the first 72 bytes, 32 random bytes fit.
+	passwordHash, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
+	if err != nil {
+		logger.WithError(err).Error("Error hashing break-glass account password")
+		return nil, ErrInternal
+	}
+	now := service.clock.Now()
+	id, err := service.userStore.Create(ctx, &models.User{
+		Username:     models.BreakGlassUsername,
+		PasswordHash: string(passwordHash),
+		Role:         string(data.RoleAdmin),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Error creating break-glass account")
+		return nil, ErrInternal
+	}
+	logger.WithField("user_id", id).Warn("Break-glass account created")
+	return &models.User{ID: id, Username: models.BreakGlassUsername, Role: string(data.RoleAdmin)}, nil
+}
+
+// activationAlerts returns the email telling the configured alert address about an activation, none if no
+// address or no mail server is configured.
+func (service *BreakGlassServiceImpl) activationAlerts(credential *models.BreakGlassCredential) ([]models.OutboxMessage, error) {
+	if service.config.BreakGlass.AlertEmail == "" || service.config.Email.SMTPHost == "" {
+		return nil, nil
+	}
+	const timeFormat = "02.01.2006 15:04"
+	location := models.FacilityLocation()
+	var body strings.Builder
+	fmt.Fprintf(&body, "Der Notfallzugang wurde am %s Uhr aktiviert und ist bis %s Uhr gültig.\n\n",
+		credential.ActivatedAt.In(location).Format(timeFormat), credential.ExpiresAt.In(location).Format(timeFormat))
+	fmt.Fprintf(&body, "Angegebene Begründung:\n%s\n\n", credential.ActivationReason)
+	body.WriteString("War die Aktivierung nicht abgesprochen, prüfen Sie bitte umgehend das Audit-Protokoll und ändern Sie die Passwörter der Administratoren.\n")
+
+	payload, err := json.Marshal(models.EmailOutboxPayload{
+		To:      service.config.BreakGlass.AlertEmail,
+		Subject: "Kitadoc: Notfallzugang aktiviert",
+		Body:    body.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []models.OutboxMessage{{Channel: models.OutboxChannelEmail, Payload: payload, NextAttemptAt: service.clock.Now()}}, nil
+}
+
+// recordFailedActivation records an activation with a wrong code, or while no credential is sealed. The
+// activation is denied anyway, so a failing audit write is only logged.
+func (service *BreakGlassServiceImpl) recordFailedActivation(logger *logrus.Entry, ctx context.Context, credentialID *int) {
+	logger.Warn("Break-glass activation with a wrong code")
+	_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:     models.AuditActionFailBreakGlass,
+		EntityType: "break_glass_credential",
+		EntityID:   credentialID,
+	})
+}
@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func breakGlassTestConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Server.JWTSecret = "test_jwt_secret_very_long_and_secure_key_for_testing_purposes"
+	cfg.BreakGlass.Validity = 2 * time.Hour
+	cfg.BreakGlass.AlertEmail = "traeger@kita.example"
+	cfg.Email.SMTPHost = "mail.kita.example"
+	return cfg
+}
+
+func TestBreakGlassService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	// The frozen clock lies in the past, the token of an activation must expire after the real time anyway.
+	now := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+	const reason = "Leitung hat Passwort vergessen, Zugang für die Abrechnung nötig"
+
+	setup := func() (*services.BreakGlassServiceImpl, *datamocks.MockBreakGlassStore, *datamocks.MockUserStore, *datamocks.MockAuthSessionStore, *datamocks.MockAuditLogStore) {
+		mockBreakGlassStore := new(datamocks.MockBreakGlassStore)
+		mockUserStore := new(datamocks.MockUserStore)
+		mockAuthSessionStore := new(datamocks.MockAuthSessionStore)
+		mockAuditLogStore := new(datamocks.MockAuditLogStore)
+		service := services.NewBreakGlassService(mockBreakGlassStore, mockUserStore, mockAuthSessionStore, services.NewAuditLogService(mockAuditLogStore), breakGlassTestConfig(), clock.NewFrozen(now))
+		return service, mockBreakGlassStore, mockUserStore, mockAuthSessionStore, mockAuditLogStore
+	}
+	sealedCredential := func(t *testing.T, code string) *models.BreakGlassCredential {
+		codeHash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.MinCost)
+		require.NoError(t, err)
+		return &models.BreakGlassCredential{ID: 3, CodeHash: string(codeHash), SealedAt: now.Add(-24 * time.Hour)}
+	}
+
+	t.Run("seal returns the code only once and stores its hash", func(t *testing.T) {
+		service, mockBreakGlassStore, _, _, mockAuditLogStore := setup()
+		var stored *models.BreakGlassCredential
+		mockBreakGlassStore.On("Seal", mock.Anything, mock.AnythingOfType("*models.BreakGlassCredential")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.BreakGlassCredential)
+		}).Return(3, nil).Once()
+		mockBreakGlassStore.On("GetByID", mock.Anything, 3).Return(&models.BreakGlassCredential{ID: 3, SealedAt: now}, nil).Once()
+		mockAuditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionSealBreakGlass && *entry.EntityID == 3 && *entry.ActorUserID == 1
+		})).Return(1, nil).Once()
+
+		sealed, err := service.Seal(logger, ctx, 1)
+		require.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^[A-Z2-9]{4}(-[A-Z2-9]{4}){5}$`), sealed.Code)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(stored.CodeHash), []byte(strings.ReplaceAll(sealed.Code, "-", ""))))
+		assert.True(t, stored.SealedAt.Equal(now))
+		mockBreakGlassStore.AssertExpectations(t)
+		mockAuditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("activation creates the account, alerts and grants expiring admin access", func(t *testing.T) {
+		service, mockBreakGlassStore, mockUserStore, mockAuthSessionStore, mockAuditLogStore := setup()
+		mockBreakGlassStore.On("GetSealed", mock.Anything).Return(sealedCredential(t, "ABCDEFGHJKLMNPQRSTUVWXYZ"), nil).Once()
+		mockUserStore.On("GetUserByUsername", mock.Anything, models.BreakGlassUsername).Return(nil, data.ErrNotFound).Once()
+		mockUserStore.On("Create", mock.Anything, mock.MatchedBy(func(user *models.User) bool {
+			return user.Username == models.BreakGlassUsername && user.Role == string(data.RoleAdmin) && user.PasswordHash != ""
+		})).Return(9, nil).Once()
+		mockAuthSessionStore.On("Create", mock.Anything, mock.MatchedBy(func(session *models.AuthSession) bool {
+			return session.UserID == 9 && session.RefreshTokenHash != "" && session.ExpiresAt.Equal(now.Add(2*time.Hour))
+		})).Return(12, nil).Once()
+		var alerts []models.OutboxMessage
+		mockBreakGlassStore.On("Activate", mock.Anything, mock.MatchedBy(func(credential *models.BreakGlassCredential) bool {
+			return credential.ID == 3 && credential.ActivationReason == reason && *credential.ActivatedUserID == 9 &&
+				credential.ExpiresAt.Equal(now.Add(2*time.Hour))
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			alerts = args.Get(2).([]models.OutboxMessage)
+		}).Return(nil).Once()
+		mockAuditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionActivateBreakGlass && *entry.EntityID == 3 && *entry.ActorUserID == 9
+		})).Return(1, nil).Once()
+
+		session, err := service.Activate(logger, ctx, models.BreakGlassActivation{Code: "abcd-efgh-jklm-npqr-stuv-wxyz", Reason: " " + reason + " "})
+		require.NoError(t, err)
+		assert.Equal(t, models.BreakGlassUsername, session.Username)
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), session.ExpiresAt, time.Minute)
+		claims, err := middleware.ParseToken(session.Token, breakGlassTestConfig().Server.JWTSecret)
+		require.NoError(t, err)
+		assert.Equal(t, 9, claims.UserID)
+		assert.Equal(t, data.RoleAdmin, claims.Role)
+		assert.Equal(t, 12, claims.SessionID)
+		assert.True(t, claims.ExpiresAt.Equal(session.ExpiresAt))
+
+		require.Len(t, alerts, 1)
+		var payload models.EmailOutboxPayload
+		require.NoError(t, json.Unmarshal(alerts[0].Payload, &payload))
+		assert.Equal(t, "traeger@kita.example", payload.To)
+		assert.Contains(t, payload.Body, reason)
+		mockBreakGlassStore.AssertExpectations(t)
+		mockUserStore.AssertExpectations(t)
+		mockAuthSessionStore.AssertExpectations(t)
+		mockAuditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("wrong codes and missing credentials are recorded alike", func(t *testing.T) {
+		service, mockBreakGlassStore, _, _, mockAuditLogStore := setup()
+		mockBreakGlassStore.On("GetSealed", mock.Anything).Return(sealedCredential(t, "ABCDEFGHJKLMNPQRSTUVWXYZ"), nil).Once()
+		mockBreakGlassStore.On("GetSealed", mock.Anything).Return(nil, data.ErrNotFound).Once()
+		mockAuditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionFailBreakGlass && entry.ActorUserID == nil
+		})).Return(1, nil).Twice()
+
+		_, err := service.Activate(logger, ctx, models.BreakGlassActivation{Code: "ZZZZ-ZZZZ-ZZZZ-ZZZZ-ZZZZ-ZZZZ", Reason: reason})
+		assert.ErrorIs(t, err, services.ErrInvalidBreakGlassCode)
+		_, err = service.Activate(logger, ctx, models.BreakGlassActivation{Code: "ABCDEFGHJKLMNPQRSTUVWXYZ", Reason: reason})
+		assert.ErrorIs(t, err, services.ErrInvalidBreakGlassCode)
+		mockBreakGlassStore.AssertNotCalled(t, "Activate", mock.Anything, mock.Anything, mock.Anything)
+		mockAuditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("a reason is required", func(t *testing.T) {
+		service, mockBreakGlassStore, _, _, _ := setup()
+
+		_, err := service.Activate(logger, ctx, models.BreakGlassActivation{Code: "ABCDEFGHJKLMNPQRSTUVWXYZ", Reason: "  vergessen  "})
+		var validationError *services.ValidationError
+		require.ErrorAs(t, err, &validationError)
+		assert.Equal(t, "reason", validationError.Violations[0].Field)
+		mockBreakGlassStore.AssertNotCalled(t, "GetSealed", mock.Anything)
+	})
+
+	t.Run("revoking ends the access of an activated credential", func(t *testing.T) {
+		service, mockBreakGlassStore, _, mockAuthSessionStore, mockAuditLogStore := setup()
+		activatedAt := now.Add(-time.Hour)
+		emergencyID := 9
+		mockBreakGlassStore.On("GetByID", mock.Anything, 3).Return(sealedCredential(t, "ABCDEFGHJKLMNPQRSTUVWXYZ"), nil).Once()
+		mockBreakGlassStore.On("Revoke", mock.Anything, 3, now).Return(nil).Once()
+		mockBreakGlassStore.On("GetByID", mock.Anything, 4).Return(&models.BreakGlassCredential{ID: 4, ActivatedAt: &activatedAt, ActivatedUserID: &emergencyID}, nil).Once()
+		mockBreakGlassStore.On("Revoke", mock.Anything, 4, now).Return(nil).Once()
+		mockAuthSessionStore.On("RevokeAllForUser", mock.Anything, emergencyID, now).Return(1, nil).Once()
+		mockAuditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionRevokeBreakGlass && *entry.ActorUserID == 1
+		})).Return(1, nil).Twice()
+		mockBreakGlassStore.On("GetByID", mock.Anything, 5).Return(&models.BreakGlassCredential{ID: 5, RevokedAt: &activatedAt}, nil).Once()
+		mockBreakGlassStore.On("GetByID", mock.Anything, 6).Return(nil, data.ErrNotFound).Once()
+
+		require.NoError(t, service.Revoke(logger, ctx, 3, 1))
+		require.NoError(t, service.Revoke(logger, ctx, 4, 1))
+		assert.ErrorIs(t, service.Revoke(logger, ctx, 5, 1), services.ErrBreakGlassRevoked)
+		assert.ErrorIs(t, service.Revoke(logger, ctx, 6, 1), services.ErrBreakGlassNotFound)
+		mockBreakGlassStore.AssertExpectations(t)
+		mockAuthSessionStore.AssertExpectations(t)
+		mockAuditLogStore.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultCareHoursWeeks is how many past weeks the care hours warnings check by default, MaxCareHoursWeeks how
+// many they can check at most.
+const (
+	DefaultCareHoursWeeks = 4
+	MaxCareHoursWeeks     = 12
+)
+
+// CareContractService defines the interface for the booked care hours of children.
+type CareContractService interface {
+	CreateContract(logger *logrus.Entry, ctx context.Context, contract *models.CareContract) (*models.CareContract, error)
+	GetContractByID(logger *logrus.Entry, ctx context.Context, id int) (*models.CareContract, error)
+	GetContractsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.CareContract, error)
+	UpdateContract(logger *logrus.Entry, ctx context.Context, contract *models.CareContract) error
+	DeleteContract(logger *logrus.Entry, ctx context.Context, id int) error
+	// GetCareHoursWarnings returns the children who attended longer than booked in each of the past weeks, the
+	// current week is not checked as it is not over yet.
+	GetCareHoursWarnings(logger *logrus.Entry, ctx context.Context, weeks int) ([]models.CareHoursWarning, error)
+}
+
+// CareContractServiceImpl implements CareContractService.
+type CareContractServiceImpl struct {
+	careContractStore data.CareContractStore
+	childStore        data.ChildStore
+	attendanceStore   data.AttendanceStore
+	clock             clock.Clock
+}
+
+// NewCareContractService creates a new CareContractServiceImpl.
+func NewCareContractService(careContractStore data.CareContractStore, childStore data.ChildStore, attendanceStore data.AttendanceStore, clock clock.Clock) *CareContractServiceImpl {
+	return &CareContractServiceImpl{
+		careContractStore: careContractStore,
+		childStore:        childStore,
+		attendanceStore:   attendanceStore,
+		clock:             clock,
+	}
+}
+
+// CreateContract creates a new care contract. Its period must not overlap the other contracts of the child.
+func (service *CareContractServiceImpl) CreateContract(logger *logrus.Entry, ctx context.Context, contract *models.CareContract) (*models.CareContract, error) {
+	if err := models.ValidateCareContract(*contract); err != nil {
+		logger.WithError(err).Warn("Invalid input for CreateContract")
+		return nil, invalidInput(err)
+	}
+	if err := service.checkOverlap(logger, ctx, contract); err != nil {
+		return nil, err
+	}
+
+	id, err := service.careContractStore.Create(ctx, contract)
+	if err != nil {
+		if errors.Is(err, data.ErrForeignKeyConstraint) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", contract.ChildID).Error("Error creating care contract")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"contract_id": id, "child_id": contract.ChildID, "weekly_hours": contract.WeeklyHours}).Info("Care contract created successfully")
+	return service.GetContractByID(logger, ctx, id)
+}
+
+// GetContractByID fetches a care contract by ID.
+func (service *CareContractServiceImpl) GetContractByID(logger *logrus.Entry, ctx context.Context, id int) (*models.CareContract, error) {
+	contract, err := service.careContractStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrCareContractNotFound
+		}
+		logger.WithError(err).WithField("contract_id", id).Error("Error fetching care contract")
+		return nil, ErrInternal
+	}
+	return contract, nil
+}
+
+// GetContractsForChild fetches the care contracts of a child, the oldest first.
+func (service *CareContractServiceImpl) GetContractsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.CareContract, error) {
+	if _, err := service.childStore.GetByID(ctx, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for care contracts")
+		return nil, ErrInternal
+	}
+	contracts, err := service.careContractStore.GetForChild(ctx, childID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching care contracts of child")
+		return nil, ErrInternal
+	}
+	return contracts, nil
+}
+
+// UpdateContract updates the hours and period of a care contract, the child cannot be changed.
+func (service *CareContractServiceImpl) UpdateContract(logger *logrus.Entry, ctx context.Context, contract *models.CareContract) error {
+	if err := models.ValidateCareContract(*contract); err != nil {
+		logger.WithError(err).Warn("Invalid input for UpdateContract")
+		return invalidInput(err)
+	}
+	existing, err := service.GetContractByID(logger, ctx, contract.ID)
+	if err != nil {
+		return err
+	}
+	contract.ChildID = existing.ChildID
+	if err := service.checkOverlap(logger, ctx, contract); err != nil {
+		return err
+	}
+
+	if err := service.careContractStore.Update(ctx, contract); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrCareContractNotFound
+		}
+		logger.WithError(err).WithField("contract_id", contract.ID).Error("Error updating care contract")
+		return ErrInternal
+	}
+	logger.WithField("contract_id", contract.ID).Info("Care contract updated successfully")
+	return nil
+}
+
+// DeleteContract deletes a care contract.
+func (service *CareContractServiceImpl) DeleteContract(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.careContractStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrCareContractNotFound
+		}
+		logger.WithError(err).WithField("contract_id", id).Error("Error deleting care contract")
+		return ErrInternal
+	}
+	logger.WithField("contract_id", id).Info("Care contract deleted successfully")
+	return nil
+}
+
+// GetCareHoursWarnings compares the attended hours of every active child with the hours booked on the Monday of
+// each of the past weeks. A child is only reported if it attended longer in every week, a single long week is
+// not a pattern. Weeks without a contract are not compared, so a child without a contract is never reported.
+func (service *CareContractServiceImpl) GetCareHoursWarnings(logger *logrus.Entry, ctx context.Context, weeks int) ([]models.CareHoursWarning, error) {
+	if weeks < 1 || weeks > MaxCareHoursWeeks {
+		logger.WithField("weeks", weeks).Warn("Invalid number of weeks for care hours warnings")
+		return nil, ErrInvalidInput
+	}
+	currentWeek := weekStartOf(models.Today(service.clock.Now()))
+	from, to := currentWeek.AddDays(-7*weeks), currentWeek.AddDays(-1)
+
+	children, err := service.childStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching children for care hours warnings")
+		return nil, ErrInternal
+	}
+	contracts, err := service.careContractStore.GetValidBetween(ctx, from, to)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching care contracts for care hours warnings")
+		return nil, ErrInternal
+	}
+	contractsByChild := make(map[int][]models.CareContract)
+	for _, contract := range contracts {
+		contractsByChild[contract.ChildID] = append(contractsByChild[contract.ChildID], contract)
+	}
+
+	warnings := []models.CareHoursWarning{}
+	for _, child := range children {
+		childContracts := contractsByChild[child.ID]
+		if len(childContracts) == 0 {
+			continue
+		}
+		if err := checkCanceled(logger, ctx, "Care hours warnings"); err != nil {
+			return nil, err
+		}
+		records, err := service.attendanceStore.GetForChild(ctx, child.ID, from, to)
+		if err != nil {
+			logger.WithError(err).WithField("child_id", child.ID).Error("Error fetching attendance for care hours warnings")
+			return nil, ErrInternal
+		}
+		attended := attendedHoursByWeek(records)
+
+		warning := models.CareHoursWarning{ChildID: child.ID, ChildFirstName: child.FirstName, ChildLastName: child.LastName}
+		excess := 0.0
+		for weekStart := from; weekStart.Before(currentWeek.Time); weekStart = weekStart.AddDays(7) {
+			index := slices.IndexFunc(childContracts, func(contract models.CareContract) bool { return contract.IsValidOn(weekStart) })
+			if index < 0 || attended[weekStart] <= float64(childContracts[index].WeeklyHours) {
+				warning.Weeks = nil
+				break
+			}
+			week := models.CareHoursWeek{WeekStart: weekStart, BookedHours: childContracts[index].WeeklyHours, AttendedHours: attended[weekStart]}
+			warning.Weeks = append(warning.Weeks, week)
+			excess += week.AttendedHours - float64(week.BookedHours)
+		}
+		if len(warning.Weeks) == 0 {
+			continue
+		}
+		warning.AverageExcessHours = roundHours(excess / float64(len(warning.Weeks)))
+		warnings = append(warnings, warning)
+	}
+
+	logger.WithFields(logrus.Fields{"weeks": weeks, "warnings": len(warnings)}).Info("Care hours warnings computed")
+	return warnings, nil
+}
+
+// checkOverlap rejects a contract whose period overlaps another contract of the child.
+func (service *CareContractServiceImpl) checkOverlap(logger *logrus.Entry, ctx context.Context, contract *models.CareContract) error {
+	existing, err := service.careContractStore.GetForChild(ctx, contract.ChildID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", contract.ChildID).Error("Error fetching care contracts of child")
+		return ErrInternal
+	}
+	for _, other := range existing {
+		if other.ID != contract.ID && contract.Overlaps(&other) {
+			logger.WithFields(logrus.Fields{"child_id": contract.ChildID, "overlapping_contract_id": other.ID}).Warn("Care contract overlaps another contract")
+			return ErrCareContractOverlap
+		}
+	}
+	return nil
+}
+
+// attendedHoursByWeek sums up the attended hours of each day by the Monday of the week.
+func attendedHoursByWeek(records []models.AttendanceRecord) map[models.Date]float64 {
+	hours := make(map[models.Date]float64)
+	for day, dayHours := range attendedHoursByDay(records) {
+		hours[weekStartOf(day)] += dayHours
+	}
+	for week := range hours {
+		hours[week] = roundHours(hours[week])
+	}
+	return hours
+}
+
+// attendedHoursByDay sums up the time between each arrival and the following departure on the same day. An
+// arrival without a departure, e.g. a forgotten scan, does not count.
+func attendedHoursByDay(records []models.AttendanceRecord) map[models.Date]float64 {
+	hours := make(map[models.Date]float64)
+	var arrival *models.AttendanceRecord
+	for i := range records {
+		record := &records[i]
+		switch {
+		case record.EventType == models.AttendanceArrival:
+			if arrival == nil || arrival.AttendanceDate != record.AttendanceDate {
+				arrival = record
+			}
+		case arrival != nil && arrival.AttendanceDate == record.AttendanceDate:
+			hours[record.AttendanceDate] += record.RecordedAt.Sub(arrival.RecordedAt).Hours()
+			arrival = nil
+		}
+	}
+	return hours
+}
+
+// roundHours rounds hours to two decimals, which is less than a minute.
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCareContractService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	// Wednesday, the two past weeks start on Monday 30 September and 7 October
+	now := time.Date(2024, time.October, 16, 10, 0, 0, 0, time.UTC)
+	august := models.NewDate(2024, time.August, 1)
+
+	setup := func() (*services.CareContractServiceImpl, *datamocks.MockCareContractStore, *datamocks.MockAttendanceStore, *datamocks.MockChildStore) {
+		contractStore := new(datamocks.MockCareContractStore)
+		attendanceStore := new(datamocks.MockAttendanceStore)
+		childStore := new(datamocks.MockChildStore)
+		return services.NewCareContractService(contractStore, childStore, attendanceStore, clock.NewFrozen(now)), contractStore, attendanceStore, childStore
+	}
+
+	t.Run("create rejects overlapping periods", func(t *testing.T) {
+		service, store, _, _ := setup()
+		until := models.NewDate(2024, time.December, 31)
+		store.On("GetForChild", mock.Anything, 1).Return([]models.CareContract{{ID: 3, ChildID: 1, WeeklyHours: 25, ValidFrom: august, ValidUntil: &until}}, nil)
+
+		_, err := service.CreateContract(logger, ctx, &models.CareContract{ChildID: 1, WeeklyHours: 35, ValidFrom: models.NewDate(2024, time.December, 31)})
+		assert.ErrorIs(t, err, services.ErrCareContractOverlap)
+		_, err = service.CreateContract(logger, ctx, &models.CareContract{ChildID: 1, WeeklyHours: 30, ValidFrom: models.NewDate(2025, time.January, 1)})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+		store.On("Create", mock.Anything, mock.Anything).Return(4, nil).Once()
+		store.On("GetByID", mock.Anything, 4).Return(&models.CareContract{ID: 4}, nil).Once()
+		created, err := service.CreateContract(logger, ctx, &models.CareContract{ChildID: 1, WeeklyHours: 35, ValidFrom: models.NewDate(2025, time.January, 1)})
+		require.NoError(t, err)
+		assert.Equal(t, 4, created.ID)
+	})
+
+	t.Run("update keeps the child and ignores its own period", func(t *testing.T) {
+		service, store, _, _ := setup()
+		existing := &models.CareContract{ID: 3, ChildID: 1, WeeklyHours: 25, ValidFrom: august}
+		store.On("GetByID", mock.Anything, 3).Return(existing, nil).Once()
+		store.On("GetForChild", mock.Anything, 1).Return([]models.CareContract{*existing}, nil).Once()
+		store.On("Update", mock.Anything, mock.MatchedBy(func(contract *models.CareContract) bool {
+			return contract.ChildID == 1 && contract.WeeklyHours == 45
+		})).Return(nil).Once()
+
+		require.NoError(t, service.UpdateContract(logger, ctx, &models.CareContract{ID: 3, ChildID: 2, WeeklyHours: 45, ValidFrom: august}))
+		store.AssertExpectations(t)
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		service, store, attendanceStore, childStore := setup()
+		from, to := models.NewDate(2024, time.September, 30), models.NewDate(2024, time.October, 13)
+		childStore.On("GetAll", mock.Anything).Return([]models.Child{{ID: 1, FirstName: "Anna"}, {ID: 2, FirstName: "Ben"}, {ID: 3, FirstName: "Clara"}}, nil).Once()
+		store.On("GetValidBetween", mock.Anything, from, to).Return([]models.CareContract{
+			{ID: 1, ChildID: 1, WeeklyHours: 25, ValidFrom: august},
+			{ID: 2, ChildID: 2, WeeklyHours: 25, ValidFrom: august},
+		}, nil).Once()
+		// attend records a day from 8:00 for the given hours
+		attend := func(day models.Date, hours int) []models.AttendanceRecord {
+			arrival := time.Date(day.Year(), day.Month(), day.Day(), 8, 0, 0, 0, time.UTC)
+			return []models.AttendanceRecord{
+				{EventType: models.AttendanceArrival, RecordedAt: arrival, AttendanceDate: day},
+				{EventType: models.AttendanceDeparture, RecordedAt: arrival.Add(time.Duration(hours) * time.Hour), AttendanceDate: day},
+			}
+		}
+		var anna, ben []models.AttendanceRecord
+		for week := from; week.Before(to.Time); week = week.AddDays(7) {
+			for day := 0; day < 5; day++ {
+				anna = append(anna, attend(week.AddDays(day), 6)...)
+			}
+		}
+		// Ben attends longer only in the first week and forgets the departure scan on one day of it
+		for day := 0; day < 5; day++ {
+			ben = append(ben, attend(from.AddDays(day), 7)...)
+		}
+		ben = append(ben, models.AttendanceRecord{EventType: models.AttendanceArrival, RecordedAt: now, AttendanceDate: to})
+		attendanceStore.On("GetForChild", mock.Anything, 1, from, to).Return(anna, nil).Once()
+		attendanceStore.On("GetForChild", mock.Anything, 2, from, to).Return(ben, nil).Once()
+
+		warnings, err := service.GetCareHoursWarnings(logger, ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, 1, warnings[0].ChildID)
+		assert.Equal(t, []models.CareHoursWeek{
+			{WeekStart: from, BookedHours: 25, AttendedHours: 30},
+			{WeekStart: from.AddDays(7), BookedHours: 25, AttendedHours: 30},
+		}, warnings[0].Weeks)
+		assert.Equal(t, 5.0, warnings[0].AverageExcessHours)
+		attendanceStore.AssertNotCalled(t, "GetForChild", mock.Anything, 3, mock.Anything, mock.Anything)
+
+		_, err = service.GetCareHoursWarnings(logger, ctx, services.MaxCareHoursWeeks+1)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		service, store, _, _ := setup()
+		store.On("Delete", mock.Anything, 9).Return(data.ErrNotFound).Once()
+		assert.ErrorIs(t, service.DeleteContract(logger, ctx, 9), services.ErrCareContractNotFound)
+	})
+}
@@ -1,8 +1,13 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"slices"
+	"time"
+
 	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/models"
 
@@ -11,36 +16,49 @@ import (
 
 // CategoryService defines the interface for category-related business logic operations.
 type CategoryService interface {
-	CreateCategory(category *models.Category) (*models.Category, error)
-	GetCategoryByID(id int) (*models.Category, error)
-	UpdateCategory(category *models.Category) error
-	DeleteCategory(id int) error
-	GetAllCategories() ([]models.Category, error)
+	CreateCategory(ctx context.Context, category *models.Category) (*models.Category, error)
+	GetCategoryByID(ctx context.Context, id int) (*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	GetAllCategories(ctx context.Context, includeArchived bool) ([]models.Category, error)
+	ArchiveCategory(ctx context.Context, id int) error
+	RestoreCategory(ctx context.Context, id int) error
+	ReassignEntries(ctx context.Context, fromCategoryID int, toCategoryID int) (int, error)
+	GetFavoriteCategories(ctx context.Context, user *models.User) ([]models.FavoriteCategory, error)
+	PinCategory(ctx context.Context, user *models.User, id int) error
+	UnpinCategory(ctx context.Context, user *models.User, id int) error
 }
 
+// maxUsedFavorites bounds the categories listed as favorites for being used, pinned categories are always listed.
+const maxUsedFavorites = 10
+
 // CategoryServiceImpl implements CategoryService.
 type CategoryServiceImpl struct {
 	categoryStore data.CategoryStore
+	teacherStore  data.TeacherStore
 	validate      *validator.Validate
+	clock         clock.Clock
 }
 
 // NewCategoryService creates a new CategoryServiceImpl.
-func NewCategoryService(categoryStore data.CategoryStore) *CategoryServiceImpl {
+func NewCategoryService(categoryStore data.CategoryStore, teacherStore data.TeacherStore, clock clock.Clock) *CategoryServiceImpl {
 	return &CategoryServiceImpl{
 		categoryStore: categoryStore,
-		validate:      validator.New(),
+		teacherStore:  teacherStore,
+		validate:      models.NewValidator(),
+		clock:         clock,
 	}
 }
 
 // CreateCategory creates a new category.
-func (s *CategoryServiceImpl) CreateCategory(category *models.Category) (*models.Category, error) {
+func (s *CategoryServiceImpl) CreateCategory(ctx context.Context, category *models.Category) (*models.Category, error) {
 	if err := models.ValidateCategory(*category); err != nil {
 		logger.GetGlobalLogger().Errorf("Invalid category input: %v", err)
-		return nil, ErrInvalidInput
+		return nil, invalidInput(err)
 	}
 
 	// Check for unique category name
-	existingCategory, err := s.categoryStore.GetByName(category.Name)
+	existingCategory, err := s.categoryStore.GetByName(ctx, category.Name)
 	if err == nil && existingCategory != nil {
 		logger.GetGlobalLogger().Errorf("Category already exists: %v", existingCategory)
 		return nil, ErrAlreadyExists
@@ -50,18 +68,22 @@ func (s *CategoryServiceImpl) CreateCategory(category *models.Category) (*models
 		return nil, ErrInternal
 	}
 
-	id, err := s.categoryStore.Create(category)
+	id, err := s.categoryStore.Create(ctx, category)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error creating category: %v", err)
 		return nil, ErrInternal
 	}
-	category.ID = id
-	return category, nil
+	created, err := s.categoryStore.GetByID(ctx, id)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error fetching created category %d: %v", id, err)
+		return nil, ErrInternal
+	}
+	return created, nil
 }
 
 // GetCategoryByID fetches a category by ID.
-func (s *CategoryServiceImpl) GetCategoryByID(id int) (*models.Category, error) {
-	category, err := s.categoryStore.GetByID(id)
+func (s *CategoryServiceImpl) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	category, err := s.categoryStore.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.GetGlobalLogger().Errorf("Category not found: %d", id)
@@ -74,14 +96,14 @@ func (s *CategoryServiceImpl) GetCategoryByID(id int) (*models.Category, error)
 }
 
 // UpdateCategory updates an existing category.
-func (s *CategoryServiceImpl) UpdateCategory(category *models.Category) error {
+func (s *CategoryServiceImpl) UpdateCategory(ctx context.Context, category *models.Category) error {
 	if err := models.ValidateCategory(*category); err != nil {
 		logger.GetGlobalLogger().Errorf("Invalid category input: %v", err)
-		return ErrInvalidInput
+		return invalidInput(err)
 	}
 
 	// Check for unique category name if name is changed
-	existingCategory, err := s.categoryStore.GetByName(category.Name)
+	existingCategory, err := s.categoryStore.GetByName(ctx, category.Name)
 	if err == nil && existingCategory != nil && existingCategory.ID != category.ID {
 		logger.GetGlobalLogger().Errorf("Category already exists: %v", existingCategory)
 		return ErrAlreadyExists
@@ -91,7 +113,7 @@ func (s *CategoryServiceImpl) UpdateCategory(category *models.Category) error {
 		return ErrInternal
 	}
 
-	err = s.categoryStore.Update(category)
+	err = s.categoryStore.Update(ctx, category)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.GetGlobalLogger().Errorf("Category not found: %d", category.ID)
@@ -104,8 +126,8 @@ func (s *CategoryServiceImpl) UpdateCategory(category *models.Category) error {
 }
 
 // DeleteCategory deletes a category by ID.
-func (s *CategoryServiceImpl) DeleteCategory(id int) error {
-	err := s.categoryStore.Delete(id)
+func (s *CategoryServiceImpl) DeleteCategory(ctx context.Context, id int) error {
+	err := s.categoryStore.Delete(ctx, id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.GetGlobalLogger().Errorf("Category with ID %d not found: %v", id, err)
@@ -120,12 +142,152 @@ func (s *CategoryServiceImpl) DeleteCategory(id int) error {
 	return nil
 }
 
-// GetAllCategories fetches all categories.
-func (s *CategoryServiceImpl) GetAllCategories() ([]models.Category, error) {
-	categories, err := s.categoryStore.GetAll()
+// GetAllCategories fetches all categories with their usage counts.
+// Archived categories are left out unless includeArchived is set.
+func (s *CategoryServiceImpl) GetAllCategories(ctx context.Context, includeArchived bool) ([]models.Category, error) {
+	categories, err := s.categoryStore.GetAll(ctx)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error fetching all categories: %v", err)
 		return nil, ErrInternal
 	}
+	if !includeArchived {
+		categories = slices.DeleteFunc(categories, func(category models.Category) bool { return category.IsArchived() })
+	}
+
+	usage, err := s.categoryStore.CountUsage(ctx)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error counting the usage of categories: %v", err)
+		return nil, ErrInternal
+	}
+	for i := range categories {
+		counts := usage[categories[i].ID]
+		categories[i].Usage = &counts
+	}
 	return categories, nil
 }
+
+// ArchiveCategory hides a category from the pickers for new entries, keeping its entries and report sections intact.
+// Archiving an archived category keeps the original date.
+func (s *CategoryServiceImpl) ArchiveCategory(ctx context.Context, id int) error {
+	category, err := s.GetCategoryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if category.IsArchived() {
+		return nil
+	}
+	now := s.clock.Now()
+	return s.setArchived(ctx, id, &now)
+}
+
+// RestoreCategory reverts the archiving of a category.
+func (s *CategoryServiceImpl) RestoreCategory(ctx context.Context, id int) error {
+	return s.setArchived(ctx, id, nil)
+}
+
+func (s *CategoryServiceImpl) setArchived(ctx context.Context, id int, archivedAt *time.Time) error {
+	err := s.categoryStore.SetArchived(ctx, id, archivedAt)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error changing the archiving of category %d: %v", id, err)
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		return ErrInternal
+	}
+	logger.GetGlobalLogger().Infof("Category %d archived: %t", id, archivedAt != nil)
+	return nil
+}
+
+// ReassignEntries moves the documentation entries of a category to another category and returns how many were moved.
+// Entries locked by a generated report keep their category. The target category cannot be archived.
+func (s *CategoryServiceImpl) ReassignEntries(ctx context.Context, fromCategoryID int, toCategoryID int) (int, error) {
+	if fromCategoryID == toCategoryID {
+		logger.GetGlobalLogger().Warnf("Entries of category %d cannot be reassigned to itself", fromCategoryID)
+		return 0, ErrInvalidInput
+	}
+	if _, err := s.GetCategoryByID(ctx, fromCategoryID); err != nil {
+		return 0, err
+	}
+	target, err := s.GetCategoryByID(ctx, toCategoryID)
+	if err != nil {
+		return 0, err
+	}
+	if target.IsArchived() {
+		logger.GetGlobalLogger().Warnf("Entries cannot be reassigned to archived category %d", toCategoryID)
+		return 0, ErrCategoryArchived
+	}
+
+	moved, err := s.categoryStore.ReassignEntries(ctx, fromCategoryID, toCategoryID)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error reassigning entries of category %d to %d: %v", fromCategoryID, toCategoryID, err)
+		return 0, ErrInternal
+	}
+	logger.GetGlobalLogger().Infof("%d entries of category %d reassigned to %d", moved, fromCategoryID, toCategoryID)
+	return moved, nil
+}
+
+// GetFavoriteCategories fetches the categories the user pinned, followed by the ones most used in the entries of
+// the teacher with the username of the user. Archived categories are left out.
+func (s *CategoryServiceImpl) GetFavoriteCategories(ctx context.Context, user *models.User) ([]models.FavoriteCategory, error) {
+	teacherID, err := s.teacherIDOfUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	favorites, err := s.categoryStore.GetFavorites(ctx, user.ID, teacherID)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error fetching the favorite categories of user %d: %v", user.ID, err)
+		return nil, ErrInternal
+	}
+
+	used := 0
+	return slices.DeleteFunc(favorites, func(favorite models.FavoriteCategory) bool {
+		if favorite.Pinned {
+			return false
+		}
+		used++
+		return used > maxUsedFavorites
+	}), nil
+}
+
+// PinCategory pins a category to the favorites of the user. Archived categories cannot be pinned.
+func (s *CategoryServiceImpl) PinCategory(ctx context.Context, user *models.User, id int) error {
+	category, err := s.categoryStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrCategoryNotFound
+		}
+		logger.GetGlobalLogger().Errorf("Error fetching category by ID: %v", err)
+		return ErrInternal
+	}
+	if category.IsArchived() {
+		logger.GetGlobalLogger().Warnf("Archived category %d cannot be pinned", id)
+		return ErrCategoryArchived
+	}
+	return s.setPinned(ctx, user, id, true)
+}
+
+// UnpinCategory removes a category from the pinned favorites of the user.
+func (s *CategoryServiceImpl) UnpinCategory(ctx context.Context, user *models.User, id int) error {
+	return s.setPinned(ctx, user, id, false)
+}
+
+func (s *CategoryServiceImpl) setPinned(ctx context.Context, user *models.User, id int, pinned bool) error {
+	if err := s.categoryStore.SetPinned(ctx, user.ID, id, pinned); err != nil {
+		logger.GetGlobalLogger().Errorf("Error changing the pin of category %d for user %d: %v", id, user.ID, err)
+		return ErrInternal
+	}
+	return nil
+}
+
+// teacherIDOfUser returns the ID of the teacher with the username of the user, 0 if the user is no teacher.
+func (s *CategoryServiceImpl) teacherIDOfUser(ctx context.Context, user *models.User) (int, error) {
+	teacher, err := findTeacherOfUser(ctx, s.teacherStore, user)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error fetching the teacher of user %d: %v", user.ID, err)
+		return 0, ErrInternal
+	}
+	if teacher == nil {
+		return 0, nil
+	}
+	return teacher.ID, nil
+}
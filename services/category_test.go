@@ -3,20 +3,23 @@ package services_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"kitadoc-backend/data"
 	"kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCreateCategory(t *testing.T) {
 	mockCategoryStore := new(mocks.MockCategoryStore)
-	service := services.NewCategoryService(mockCategoryStore)
+	service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
 
 	log_level, _ := logrus.ParseLevel("debug")
 	logger.InitGlobalLogger(
@@ -29,10 +32,11 @@ func TestCreateCategory(t *testing.T) {
 	// Test case 1: Successful creation
 	t.Run("success", func(t *testing.T) {
 		category := &models.Category{Name: "New Category"}
-		mockCategoryStore.On("GetByName", category.Name).Return(nil, data.ErrNotFound).Once()
-		mockCategoryStore.On("Create", category).Return(1, nil).Once()
+		mockCategoryStore.On("GetByName", mock.Anything, category.Name).Return(nil, data.ErrNotFound).Once()
+		mockCategoryStore.On("Create", mock.Anything, category).Return(1, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, 1).Return(&models.Category{ID: 1, Name: "New Category"}, nil).Once()
 
-		createdCategory, err := service.CreateCategory(category)
+		createdCategory, err := service.CreateCategory(t.Context(), category)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, createdCategory)
@@ -44,51 +48,75 @@ func TestCreateCategory(t *testing.T) {
 	// Test case 2: Invalid input (validation error)
 	t.Run("invalid input", func(t *testing.T) {
 		category := &models.Category{Name: ""} // Invalid name
-		createdCategory, err := service.CreateCategory(category)
+		createdCategory, err := service.CreateCategory(t.Context(), category)
 
 		assert.Error(t, err)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		assert.Nil(t, createdCategory)
+		mockCategoryStore.AssertNotCalled(t, "GetByName", mock.Anything)
+		mockCategoryStore.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	// Test case 2b: Invalid form schema
+	t.Run("invalid form schema", func(t *testing.T) {
+		category := &models.Category{Name: "Bewegung", FormSchema: &models.FormSchema{
+			Type:       "object",
+			Properties: map[string]models.FormSchemaProperty{"gross_motor": {Type: "date"}},
+			Required:   []string{"fine_motor"},
+		}}
+		createdCategory, err := service.CreateCategory(t.Context(), category)
+
 		assert.Equal(t, services.ErrInvalidInput, err)
 		assert.Nil(t, createdCategory)
-		mockCategoryStore.AssertNotCalled(t, "GetByName")
-		mockCategoryStore.AssertNotCalled(t, "Create")
+		mockCategoryStore.AssertNotCalled(t, "GetByName", mock.Anything, "Bewegung")
+	})
+
+	// Test case 2c: Inverted age band
+	t.Run("inverted age band", func(t *testing.T) {
+		category := &models.Category{Name: "Schulvorbereitung", MinAgeMonths: intPtr(72), MaxAgeMonths: intPtr(60)}
+		createdCategory, err := service.CreateCategory(t.Context(), category)
+
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		assert.Nil(t, createdCategory)
+		mockCategoryStore.AssertNotCalled(t, "GetByName", mock.Anything, "Schulvorbereitung")
 	})
 
 	// Test case 3: Category with same name already exists
 	t.Run("already exists", func(t *testing.T) {
 		category := &models.Category{Name: "Existing Category"}
 		existingCategory := &models.Category{ID: 1, Name: "Existing Category"}
-		mockCategoryStore.On("GetByName", category.Name).Return(existingCategory, nil).Once()
+		mockCategoryStore.On("GetByName", mock.Anything, category.Name).Return(existingCategory, nil).Once()
 
-		createdCategory, err := service.CreateCategory(category)
+		createdCategory, err := service.CreateCategory(t.Context(), category)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrAlreadyExists, err)
 		assert.Nil(t, createdCategory)
 		mockCategoryStore.AssertExpectations(t)
-		mockCategoryStore.AssertNotCalled(t, "Create")
+		mockCategoryStore.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
 	// Test case 4: Internal error during GetByName check
 	t.Run("internal error on GetByName", func(t *testing.T) {
 		category := &models.Category{Name: "New Category"}
-		mockCategoryStore.On("GetByName", category.Name).Return(nil, errors.New("db error")).Once()
+		mockCategoryStore.On("GetByName", mock.Anything, category.Name).Return(nil, errors.New("db error")).Once()
 
-		createdCategory, err := service.CreateCategory(category)
+		createdCategory, err := service.CreateCategory(t.Context(), category)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
 		assert.Nil(t, createdCategory)
 		mockCategoryStore.AssertExpectations(t)
-		mockCategoryStore.AssertNotCalled(t, "Create")
+		mockCategoryStore.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
 	// Test case 5: Internal error during creation
 	t.Run("internal error on create", func(t *testing.T) {
 		category := &models.Category{Name: "New Category"}
-		mockCategoryStore.On("GetByName", category.Name).Return(nil, data.ErrNotFound).Once()
-		mockCategoryStore.On("Create", category).Return(0, errors.New("db error")).Once()
+		mockCategoryStore.On("GetByName", mock.Anything, category.Name).Return(nil, data.ErrNotFound).Once()
+		mockCategoryStore.On("Create", mock.Anything, category).Return(0, errors.New("db error")).Once()
 
-		createdCategory, err := service.CreateCategory(category)
+		createdCategory, err := service.CreateCategory(t.Context(), category)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -99,15 +127,15 @@ func TestCreateCategory(t *testing.T) {
 
 func TestGetCategoryByID(t *testing.T) {
 	mockCategoryStore := new(mocks.MockCategoryStore)
-	service := services.NewCategoryService(mockCategoryStore)
+	service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
 
 	// Test case 1: Successful retrieval
 	t.Run("success", func(t *testing.T) {
 		categoryID := 1
 		expectedCategory := &models.Category{ID: categoryID, Name: "Test Category"}
-		mockCategoryStore.On("GetByID", categoryID).Return(expectedCategory, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, categoryID).Return(expectedCategory, nil).Once()
 
-		category, err := service.GetCategoryByID(categoryID)
+		category, err := service.GetCategoryByID(t.Context(), categoryID)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, category)
@@ -119,9 +147,9 @@ func TestGetCategoryByID(t *testing.T) {
 	// Test case 2: Category not found
 	t.Run("not found", func(t *testing.T) {
 		categoryID := 99
-		mockCategoryStore.On("GetByID", categoryID).Return(nil, data.ErrNotFound).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, categoryID).Return(nil, data.ErrNotFound).Once()
 
-		category, err := service.GetCategoryByID(categoryID)
+		category, err := service.GetCategoryByID(t.Context(), categoryID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
@@ -132,9 +160,9 @@ func TestGetCategoryByID(t *testing.T) {
 	// Test case 3: Internal error
 	t.Run("internal error", func(t *testing.T) {
 		categoryID := 1
-		mockCategoryStore.On("GetByID", categoryID).Return(nil, errors.New("db error")).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, categoryID).Return(nil, errors.New("db error")).Once()
 
-		category, err := service.GetCategoryByID(categoryID)
+		category, err := service.GetCategoryByID(t.Context(), categoryID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -145,16 +173,16 @@ func TestGetCategoryByID(t *testing.T) {
 
 func TestUpdateCategory(t *testing.T) {
 	mockCategoryStore := new(mocks.MockCategoryStore)
-	service := services.NewCategoryService(mockCategoryStore)
+	service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
 
 	// Test case 1: Successful update
 	t.Run("success", func(t *testing.T) {
 		category := &models.Category{ID: 1, Name: "Updated Category"}
 		// Simulate that the name is unique or the same as the existing one
-		mockCategoryStore.On("GetByName", category.Name).Return(nil, data.ErrNotFound).Once()
-		mockCategoryStore.On("Update", category).Return(nil).Once()
+		mockCategoryStore.On("GetByName", mock.Anything, category.Name).Return(nil, data.ErrNotFound).Once()
+		mockCategoryStore.On("Update", mock.Anything, category).Return(nil).Once()
 
-		err := service.UpdateCategory(category)
+		err := service.UpdateCategory(t.Context(), category)
 
 		assert.NoError(t, err)
 		mockCategoryStore.AssertExpectations(t)
@@ -163,48 +191,48 @@ func TestUpdateCategory(t *testing.T) {
 	// Test case 2: Invalid input (validation error)
 	t.Run("invalid input", func(t *testing.T) {
 		category := &models.Category{ID: 1, Name: ""} // Invalid name
-		err := service.UpdateCategory(category)
+		err := service.UpdateCategory(t.Context(), category)
 
 		assert.Error(t, err)
-		assert.Equal(t, services.ErrInvalidInput, err)
-		mockCategoryStore.AssertNotCalled(t, "GetByName")
-		mockCategoryStore.AssertNotCalled(t, "Update")
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		mockCategoryStore.AssertNotCalled(t, "GetByName", mock.Anything)
+		mockCategoryStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 3: Category name conflict
 	t.Run("name conflict", func(t *testing.T) {
 		category := &models.Category{ID: 1, Name: "New Name"}
 		conflictingCategory := &models.Category{ID: 2, Name: "New Name"}
-		mockCategoryStore.On("GetByName", category.Name).Return(conflictingCategory, nil).Once()
+		mockCategoryStore.On("GetByName", mock.Anything, category.Name).Return(conflictingCategory, nil).Once()
 
-		err := service.UpdateCategory(category)
+		err := service.UpdateCategory(t.Context(), category)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrAlreadyExists, err)
 		mockCategoryStore.AssertExpectations(t)
-		mockCategoryStore.AssertNotCalled(t, "Update")
+		mockCategoryStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 4: Internal error during GetByName check
 	t.Run("internal error on GetByName", func(t *testing.T) {
 		category := &models.Category{ID: 1, Name: "Updated Category"}
-		mockCategoryStore.On("GetByName", category.Name).Return(nil, errors.New("db error")).Once()
+		mockCategoryStore.On("GetByName", mock.Anything, category.Name).Return(nil, errors.New("db error")).Once()
 
-		err := service.UpdateCategory(category)
+		err := service.UpdateCategory(t.Context(), category)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
 		mockCategoryStore.AssertExpectations(t)
-		mockCategoryStore.AssertNotCalled(t, "Update")
+		mockCategoryStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 5: Category not found during update
 	t.Run("not found on update", func(t *testing.T) {
 		category := &models.Category{ID: 99, Name: "Updated Category"}
-		mockCategoryStore.On("GetByName", category.Name).Return(nil, data.ErrNotFound).Once()
-		mockCategoryStore.On("Update", category).Return(data.ErrNotFound).Once()
+		mockCategoryStore.On("GetByName", mock.Anything, category.Name).Return(nil, data.ErrNotFound).Once()
+		mockCategoryStore.On("Update", mock.Anything, category).Return(data.ErrNotFound).Once()
 
-		err := service.UpdateCategory(category)
+		err := service.UpdateCategory(t.Context(), category)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
@@ -214,10 +242,10 @@ func TestUpdateCategory(t *testing.T) {
 	// Test case 6: Internal error during update
 	t.Run("internal error on update", func(t *testing.T) {
 		category := &models.Category{ID: 1, Name: "Updated Category"}
-		mockCategoryStore.On("GetByName", category.Name).Return(nil, data.ErrNotFound).Once()
-		mockCategoryStore.On("Update", category).Return(errors.New("db error")).Once()
+		mockCategoryStore.On("GetByName", mock.Anything, category.Name).Return(nil, data.ErrNotFound).Once()
+		mockCategoryStore.On("Update", mock.Anything, category).Return(errors.New("db error")).Once()
 
-		err := service.UpdateCategory(category)
+		err := service.UpdateCategory(t.Context(), category)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -227,14 +255,14 @@ func TestUpdateCategory(t *testing.T) {
 
 func TestDeleteCategory(t *testing.T) {
 	mockCategoryStore := new(mocks.MockCategoryStore)
-	service := services.NewCategoryService(mockCategoryStore)
+	service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
 
 	// Test case 1: Successful deletion
 	t.Run("success", func(t *testing.T) {
 		categoryID := 1
-		mockCategoryStore.On("Delete", categoryID).Return(nil).Once()
+		mockCategoryStore.On("Delete", mock.Anything, categoryID).Return(nil).Once()
 
-		err := service.DeleteCategory(categoryID)
+		err := service.DeleteCategory(t.Context(), categoryID)
 
 		assert.NoError(t, err)
 		mockCategoryStore.AssertExpectations(t)
@@ -243,9 +271,9 @@ func TestDeleteCategory(t *testing.T) {
 	// Test case 2: Category not found
 	t.Run("not found", func(t *testing.T) {
 		categoryID := 99
-		mockCategoryStore.On("Delete", categoryID).Return(data.ErrNotFound).Once()
+		mockCategoryStore.On("Delete", mock.Anything, categoryID).Return(data.ErrNotFound).Once()
 
-		err := service.DeleteCategory(categoryID)
+		err := service.DeleteCategory(t.Context(), categoryID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
@@ -255,9 +283,9 @@ func TestDeleteCategory(t *testing.T) {
 	// Test case 3: Internal error
 	t.Run("internal error", func(t *testing.T) {
 		categoryID := 1
-		mockCategoryStore.On("Delete", categoryID).Return(errors.New("db error")).Once()
+		mockCategoryStore.On("Delete", mock.Anything, categoryID).Return(errors.New("db error")).Once()
 
-		err := service.DeleteCategory(categoryID)
+		err := service.DeleteCategory(t.Context(), categoryID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -267,17 +295,21 @@ func TestDeleteCategory(t *testing.T) {
 
 func TestGetAllCategories(t *testing.T) {
 	mockCategoryStore := new(mocks.MockCategoryStore)
-	service := services.NewCategoryService(mockCategoryStore)
+	service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
 
 	// Test case 1: Successful retrieval
 	t.Run("success", func(t *testing.T) {
-		expectedCategories := []models.Category{
+		mockCategoryStore.On("GetAll", mock.Anything).Return([]models.Category{
 			{ID: 1, Name: "Category A"},
 			{ID: 2, Name: "Category B"},
+		}, nil).Once()
+		mockCategoryStore.On("CountUsage", mock.Anything).Return(map[int]models.UsageCounts{1: {Entries: 3, Children: 2}}, nil).Once()
+		expectedCategories := []models.Category{
+			{ID: 1, Name: "Category A", Usage: &models.UsageCounts{Entries: 3, Children: 2}},
+			{ID: 2, Name: "Category B", Usage: &models.UsageCounts{}},
 		}
-		mockCategoryStore.On("GetAll").Return(expectedCategories, nil).Once()
 
-		categories, err := service.GetAllCategories()
+		categories, err := service.GetAllCategories(t.Context(), true)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, categories)
@@ -287,13 +319,206 @@ func TestGetAllCategories(t *testing.T) {
 
 	// Test case 2: Internal error
 	t.Run("internal error", func(t *testing.T) {
-		mockCategoryStore.On("GetAll").Return(nil, errors.New("db error")).Once()
+		mockCategoryStore.On("GetAll", mock.Anything).Return(nil, errors.New("db error")).Once()
 
-		categories, err := service.GetAllCategories()
+		categories, err := service.GetAllCategories(t.Context(), true)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
 		assert.Nil(t, categories)
 		mockCategoryStore.AssertExpectations(t)
 	})
+
+	t.Run("usage count error", func(t *testing.T) {
+		mockCategoryStore.On("GetAll", mock.Anything).Return([]models.Category{{ID: 1, Name: "Category A"}}, nil).Once()
+		mockCategoryStore.On("CountUsage", mock.Anything).Return(nil, errors.New("db error")).Once()
+
+		categories, err := service.GetAllCategories(t.Context(), true)
+
+		assert.Equal(t, services.ErrInternal, err)
+		assert.Nil(t, categories)
+		mockCategoryStore.AssertExpectations(t)
+	})
+}
+
+func TestGetAllCategories_HidesArchived(t *testing.T) {
+	mockCategoryStore := new(mocks.MockCategoryStore)
+	service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+	archivedAt := time.Now()
+	mockCategoryStore.On("GetAll", mock.Anything).Return([]models.Category{
+		{ID: 1, Name: "Category A"},
+		{ID: 2, Name: "Category B", ArchivedAt: &archivedAt},
+	}, nil).Once()
+	mockCategoryStore.On("CountUsage", mock.Anything).Return(map[int]models.UsageCounts{2: {Entries: 1, Children: 1}}, nil).Once()
+
+	categories, err := service.GetAllCategories(t.Context(), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Category{{ID: 1, Name: "Category A", Usage: &models.UsageCounts{}}}, categories)
+	mockCategoryStore.AssertExpectations(t)
+}
+
+func TestArchiveCategory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+		mockCategoryStore.On("GetByID", mock.Anything, 1).Return(&models.Category{ID: 1, Name: "Category A"}, nil).Once()
+		mockCategoryStore.On("SetArchived", mock.Anything, 1, mock.AnythingOfType("*time.Time")).Return(nil).Once()
+
+		err := service.ArchiveCategory(t.Context(), 1)
+
+		assert.NoError(t, err)
+		mockCategoryStore.AssertExpectations(t)
+	})
+
+	t.Run("already archived keeps the date", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+		archivedAt := time.Now()
+		mockCategoryStore.On("GetByID", mock.Anything, 1).Return(&models.Category{ID: 1, Name: "Category A", ArchivedAt: &archivedAt}, nil).Once()
+
+		err := service.ArchiveCategory(t.Context(), 1)
+
+		assert.NoError(t, err)
+		mockCategoryStore.AssertNotCalled(t, "SetArchived", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+		mockCategoryStore.On("GetByID", mock.Anything, 1).Return(nil, data.ErrNotFound).Once()
+
+		err := service.ArchiveCategory(t.Context(), 1)
+
+		assert.Equal(t, services.ErrNotFound, err)
+	})
+}
+
+func TestRestoreCategory(t *testing.T) {
+	mockCategoryStore := new(mocks.MockCategoryStore)
+	service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+	mockCategoryStore.On("SetArchived", mock.Anything, 1, (*time.Time)(nil)).Return(nil).Once()
+
+	err := service.RestoreCategory(t.Context(), 1)
+
+	assert.NoError(t, err)
+	mockCategoryStore.AssertExpectations(t)
+}
+
+func TestReassignEntries(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+		archivedAt := time.Now()
+		mockCategoryStore.On("GetByID", mock.Anything, 1).Return(&models.Category{ID: 1, Name: "Old", ArchivedAt: &archivedAt}, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, 2).Return(&models.Category{ID: 2, Name: "New"}, nil).Once()
+		mockCategoryStore.On("ReassignEntries", mock.Anything, 1, 2).Return(3, nil).Once()
+
+		moved, err := service.ReassignEntries(t.Context(), 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, moved)
+		mockCategoryStore.AssertExpectations(t)
+	})
+
+	t.Run("same category", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+
+		_, err := service.ReassignEntries(t.Context(), 1, 1)
+
+		assert.Equal(t, services.ErrInvalidInput, err)
+	})
+
+	t.Run("archived target", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+		archivedAt := time.Now()
+		mockCategoryStore.On("GetByID", mock.Anything, 1).Return(&models.Category{ID: 1, Name: "Old"}, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, 2).Return(&models.Category{ID: 2, Name: "New", ArchivedAt: &archivedAt}, nil).Once()
+
+		_, err := service.ReassignEntries(t.Context(), 1, 2)
+
+		assert.ErrorIs(t, err, services.ErrCategoryArchived)
+		mockCategoryStore.AssertNotCalled(t, "ReassignEntries", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetFavoriteCategories(t *testing.T) {
+	user := &models.User{ID: 7, Username: "mmueller"}
+
+	t.Run("pinned and most used categories of the teacher", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		mockTeacherStore := new(mocks.MockTeacherStore)
+		service := services.NewCategoryService(mockCategoryStore, mockTeacherStore, clock.System{})
+		mockTeacherStore.On("GetAll", mock.Anything).Return([]models.Teacher{{ID: 2, Username: "aschmidt"}, {ID: 3, Username: "mmueller"}}, nil).Once()
+		favorites := []models.FavoriteCategory{{Category: models.Category{ID: 1}, Pinned: true}}
+		for id := 2; id <= 13; id++ {
+			favorites = append(favorites, models.FavoriteCategory{Category: models.Category{ID: id}, UseCount: 20 - id})
+		}
+		mockCategoryStore.On("GetFavorites", mock.Anything, 7, 3).Return(favorites, nil).Once()
+
+		result, err := service.GetFavoriteCategories(t.Context(), user)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 11)
+		assert.True(t, result[0].Pinned)
+		assert.Equal(t, 11, result[10].ID)
+		mockCategoryStore.AssertExpectations(t)
+	})
+
+	t.Run("user without teacher", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		mockTeacherStore := new(mocks.MockTeacherStore)
+		service := services.NewCategoryService(mockCategoryStore, mockTeacherStore, clock.System{})
+		mockTeacherStore.On("GetAll", mock.Anything).Return([]models.Teacher{{ID: 2, Username: "aschmidt"}}, nil).Once()
+		mockCategoryStore.On("GetFavorites", mock.Anything, 7, 0).Return([]models.FavoriteCategory{}, nil).Once()
+
+		result, err := service.GetFavoriteCategories(t.Context(), user)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+		mockCategoryStore.AssertExpectations(t)
+	})
+}
+
+func TestPinCategory(t *testing.T) {
+	user := &models.User{ID: 7, Username: "mmueller"}
+
+	t.Run("success", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+		mockCategoryStore.On("GetByID", mock.Anything, 2).Return(&models.Category{ID: 2, Name: "Motorik"}, nil).Once()
+		mockCategoryStore.On("SetPinned", mock.Anything, 7, 2, true).Return(nil).Once()
+
+		assert.NoError(t, service.PinCategory(t.Context(), user, 2))
+		mockCategoryStore.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+		mockCategoryStore.On("GetByID", mock.Anything, 2).Return(nil, data.ErrNotFound).Once()
+
+		assert.ErrorIs(t, service.PinCategory(t.Context(), user, 2), services.ErrCategoryNotFound)
+	})
+
+	t.Run("archived", func(t *testing.T) {
+		mockCategoryStore := new(mocks.MockCategoryStore)
+		service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+		archivedAt := time.Now()
+		mockCategoryStore.On("GetByID", mock.Anything, 2).Return(&models.Category{ID: 2, Name: "Motorik", ArchivedAt: &archivedAt}, nil).Once()
+
+		assert.ErrorIs(t, service.PinCategory(t.Context(), user, 2), services.ErrCategoryArchived)
+		mockCategoryStore.AssertNotCalled(t, "SetPinned", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUnpinCategory(t *testing.T) {
+	mockCategoryStore := new(mocks.MockCategoryStore)
+	service := services.NewCategoryService(mockCategoryStore, new(mocks.MockTeacherStore), clock.System{})
+	mockCategoryStore.On("SetPinned", mock.Anything, 7, 2, false).Return(nil).Once()
+
+	assert.NoError(t, service.UnpinCategory(t.Context(), &models.User{ID: 7}, 2))
+	mockCategoryStore.AssertExpectations(t)
 }
@@ -1,63 +1,89 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/models"
-	"time"
+	"slices"
 
 	"github.com/go-playground/validator/v10"
 )
 
 // ChildService defines the interface for child-related business logic operations.
 type ChildService interface {
-	CreateChild(child *models.Child) (*models.Child, error)
-	GetChildByID(id int) (*models.Child, error)
-	UpdateChild(child *models.Child) error
-	DeleteChild(id int) error
-	GetAllChildren() ([]models.Child, error)
+	CreateChild(ctx context.Context, child *models.Child) (*models.Child, error)
+	GetChildByID(ctx context.Context, id int) (*models.Child, error)
+	UpdateChild(ctx context.Context, child *models.Child) error
+	DeleteChild(ctx context.Context, id int) error
+	GetAllChildren(ctx context.Context, query models.ListQuery) ([]models.Child, error)
+	GetAllChildrenWithIncludes(ctx context.Context, includes []string, query models.ListQuery) ([]models.ChildListItem, error)
+	CountChildren(ctx context.Context, query models.ListQuery) (int, error)
 	BulkImportChildren(fileContent []byte) error // Placeholder for file processing
 }
 
 // ChildServiceImpl implements ChildService.
 type ChildServiceImpl struct {
 	childStore data.ChildStore
-	validate   *validator.Validate
+	// The stores below are only used for the expansions of the children list.
+	groupStore              data.GroupStore
+	assignmentStore         data.AssignmentStore
+	teacherStore            data.TeacherStore
+	documentationEntryStore data.DocumentationEntryStore
+	validate                *validator.Validate
+	clock                   clock.Clock
 }
 
 // NewChildService creates a new ChildServiceImpl.
-func NewChildService(childStore data.ChildStore) *ChildServiceImpl {
-	validate := validator.New()
+func NewChildService(
+	childStore data.ChildStore,
+	groupStore data.GroupStore,
+	assignmentStore data.AssignmentStore,
+	teacherStore data.TeacherStore,
+	documentationEntryStore data.DocumentationEntryStore,
+	clock clock.Clock,
+) *ChildServiceImpl {
+	validate := models.NewValidator()
 	validate.RegisterValidation("childbirthdate", models.ValidateChildBirthdate) //nolint:errcheck
 	return &ChildServiceImpl{
-		childStore: childStore,
-		validate:   validate,
+		childStore:              childStore,
+		groupStore:              groupStore,
+		assignmentStore:         assignmentStore,
+		teacherStore:            teacherStore,
+		documentationEntryStore: documentationEntryStore,
+		validate:                validate,
+		clock:                   clock,
 	}
 }
 
 // CreateChild creates a new child.
-func (s *ChildServiceImpl) CreateChild(child *models.Child) (*models.Child, error) {
+func (s *ChildServiceImpl) CreateChild(ctx context.Context, child *models.Child) (*models.Child, error) {
 	if err := s.validate.Struct(child); err != nil {
 		logger.GetGlobalLogger().Errorf("Validation error: %v", err)
-		return nil, ErrInvalidInput
+		return nil, invalidInput(err)
 	}
 
-	child.CreatedAt = time.Now()
-	child.UpdatedAt = time.Now()
+	child.CreatedAt = s.clock.Now()
+	child.UpdatedAt = s.clock.Now()
 
-	id, err := s.childStore.Create(child)
+	id, err := s.childStore.Create(ctx, child)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Failed to create child: %v", err)
 		return nil, ErrInternal
 	}
-	child.ID = id
-	return child, nil
+	created, err := s.childStore.GetByID(ctx, id)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Failed to fetch created child %d: %v", id, err)
+		return nil, ErrInternal
+	}
+	return created, nil
 }
 
 // GetChildByID fetches a child by ID.
-func (s *ChildServiceImpl) GetChildByID(id int) (*models.Child, error) {
-	child, err := s.childStore.GetByID(id)
+func (s *ChildServiceImpl) GetChildByID(ctx context.Context, id int) (*models.Child, error) {
+	child, err := s.childStore.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.GetGlobalLogger().Errorf("Child not found: %d", id)
@@ -70,14 +96,14 @@ func (s *ChildServiceImpl) GetChildByID(id int) (*models.Child, error) {
 }
 
 // UpdateChild updates an existing child.
-func (s *ChildServiceImpl) UpdateChild(child *models.Child) error {
+func (s *ChildServiceImpl) UpdateChild(ctx context.Context, child *models.Child) error {
 	if err := s.validate.Struct(child); err != nil {
 		logger.GetGlobalLogger().Errorf("Validation error: %v", err)
-		return ErrInvalidInput
+		return invalidInput(err)
 	}
 
-	child.UpdatedAt = time.Now()
-	err := s.childStore.Update(child)
+	child.UpdatedAt = s.clock.Now()
+	err := s.childStore.Update(ctx, child)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.GetGlobalLogger().Errorf("Child not found: %d", child.ID)
@@ -90,8 +116,8 @@ func (s *ChildServiceImpl) UpdateChild(child *models.Child) error {
 }
 
 // DeleteChild deletes a child by ID.
-func (s *ChildServiceImpl) DeleteChild(id int) error {
-	err := s.childStore.Delete(id)
+func (s *ChildServiceImpl) DeleteChild(ctx context.Context, id int) error {
+	err := s.childStore.Delete(ctx, id)
 	if err != nil {
 		log := logger.GetGlobalLogger()
 		if errors.Is(err, data.ErrNotFound) {
@@ -107,16 +133,113 @@ func (s *ChildServiceImpl) DeleteChild(id int) error {
 	return nil
 }
 
-// GetAllChildren fetches all children.
-func (s *ChildServiceImpl) GetAllChildren() ([]models.Child, error) {
-	children, err := s.childStore.GetAll()
+// GetAllChildren fetches the children matching the query. Archived children are left out.
+func (s *ChildServiceImpl) GetAllChildren(ctx context.Context, query models.ListQuery) ([]models.Child, error) {
+	children, err := s.childStore.List(ctx, query.Where("archived_at", models.OperatorIsNull, nil))
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Failed to get all children: %v", err)
+		if errors.Is(err, data.ErrInvalidInput) {
+			return nil, ErrInvalidInput
+		}
 		return nil, ErrInternal
 	}
 	return children, nil
 }
 
+// CountChildren counts the children matching the conditions of the query. Archived children are left out.
+func (s *ChildServiceImpl) CountChildren(ctx context.Context, query models.ListQuery) (int, error) {
+	count, err := s.childStore.Count(ctx, query.Where("archived_at", models.OperatorIsNull, nil))
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Failed to count children: %v", err)
+		if errors.Is(err, data.ErrInvalidInput) {
+			return 0, ErrInvalidInput
+		}
+		return 0, ErrInternal
+	}
+	return count, nil
+}
+
+// GetAllChildrenWithIncludes fetches the children matching the query with the requested expansions of the list
+// view, see models.ChildIncludes. Every expansion is one batched lookup, independent of the number of children.
+func (s *ChildServiceImpl) GetAllChildrenWithIncludes(ctx context.Context, includes []string, query models.ListQuery) ([]models.ChildListItem, error) {
+	children, err := s.GetAllChildren(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.ChildListItem, len(children))
+	itemsByChild := make(map[int]*models.ChildListItem, len(children))
+	for i, child := range children {
+		items[i] = models.ChildListItem{Child: child}
+		itemsByChild[child.ID] = &items[i]
+	}
+
+	if slices.Contains(includes, models.ChildIncludeGroup) {
+		groups, err := s.groupStore.GetAll(ctx)
+		if err != nil {
+			logger.GetGlobalLogger().Errorf("Failed to get groups for the children list: %v", err)
+			return nil, ErrInternal
+		}
+		for _, group := range groups {
+			for _, childID := range group.ChildIDs {
+				if item, ok := itemsByChild[childID]; ok {
+					item.Group = &models.ChildGroupSummary{ID: group.ID, Name: group.Name}
+				}
+			}
+		}
+	}
+
+	if slices.Contains(includes, models.ChildIncludeCurrentTeacher) {
+		if err := s.includeCurrentTeachers(ctx, itemsByChild); err != nil {
+			return nil, err
+		}
+	}
+
+	if slices.Contains(includes, models.ChildIncludeLastObservation) {
+		dates, err := s.documentationEntryStore.GetLastObservationDates(ctx)
+		if err != nil {
+			logger.GetGlobalLogger().Errorf("Failed to get last observation dates for the children list: %v", err)
+			return nil, ErrInternal
+		}
+		for childID, date := range dates {
+			if item, ok := itemsByChild[childID]; ok {
+				item.LastObservationDate = &date
+			}
+		}
+	}
+	return items, nil
+}
+
+// includeCurrentTeachers sets the teacher of the open primary assignment of every child.
+func (s *ChildServiceImpl) includeCurrentTeachers(ctx context.Context, itemsByChild map[int]*models.ChildListItem) error {
+	assignments, err := s.assignmentStore.GetAllAssignments(ctx)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Failed to get assignments for the children list: %v", err)
+		return ErrInternal
+	}
+	teachers, err := s.teacherStore.GetAll(ctx)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Failed to get teachers for the children list: %v", err)
+		return ErrInternal
+	}
+	teachersByID := make(map[int]models.Teacher, len(teachers))
+	for _, teacher := range teachers {
+		teachersByID[teacher.ID] = teacher
+	}
+
+	for _, assignment := range assignments {
+		if !assignment.IsOpen() || assignment.AssignmentType != models.AssignmentTypePrimary {
+			continue
+		}
+		item, ok := itemsByChild[assignment.ChildID]
+		teacher, found := teachersByID[assignment.TeacherID]
+		if !ok || !found {
+			continue
+		}
+		item.CurrentTeacher = &models.ChildTeacherSummary{ID: teacher.ID, FirstName: teacher.FirstName, LastName: teacher.LastName}
+	}
+	return nil
+}
+
 // BulkImportChildren handles the bulk import of children from a file.
 // This is a placeholder for actual file processing logic.
 func (s *ChildServiceImpl) BulkImportChildren(fileContent []byte) error {
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MaxChildDocumentSize is the largest scanned document that can be uploaded, in bytes.
+const MaxChildDocumentSize = 10 << 20
+
+// pdfContentType is the content type of documents scanned to PDF.
+const pdfContentType = "application/pdf"
+
+// DefaultDocumentReminderDays is how many days before their expiry documents are reminded of by default.
+const DefaultDocumentReminderDays = 30
+
+// ChildDocumentService defines the interface for the scanned paperwork of children.
+type ChildDocumentService interface {
+	// UploadDocument adds a scanned PDF or image to the documents of its child.
+	UploadDocument(logger *logrus.Entry, ctx context.Context, document *models.ChildDocument, user *models.User) (*models.ChildDocument, error)
+	GetDocumentByID(logger *logrus.Entry, ctx context.Context, id int) (*models.ChildDocument, error)
+	// GetDocumentContent fetches a document with its content.
+	GetDocumentContent(logger *logrus.Entry, ctx context.Context, id int) (*models.ChildDocument, error)
+	// UpdateDocument changes the type, the file name and the expiry date of a document.
+	UpdateDocument(logger *logrus.Entry, ctx context.Context, document *models.ChildDocument) error
+	DeleteDocument(logger *logrus.Entry, ctx context.Context, id int) error
+	GetChildDocuments(logger *logrus.Entry, ctx context.Context, childID int) ([]models.ChildDocument, error)
+	// GetReminders lists the missing and expired documents of the children in care, and the documents that expire
+	// within the given number of days.
+	GetReminders(logger *logrus.Entry, ctx context.Context, withinDays int) ([]models.DocumentReminder, error)
+}
+
+// ChildDocumentServiceImpl implements ChildDocumentService.
+type ChildDocumentServiceImpl struct {
+	documentStore    data.ChildDocumentStore
+	childStore       data.ChildStore
+	clock            clock.Clock
+	clientEncryption string
+}
+
+// NewChildDocumentService creates a new ChildDocumentServiceImpl. clientEncryption is the mode of the client-side
+// encryption of documents, empty if it is disabled.
+func NewChildDocumentService(documentStore data.ChildDocumentStore, childStore data.ChildStore, clock clock.Clock, clientEncryption string) *ChildDocumentServiceImpl {
+	return &ChildDocumentServiceImpl{
+		documentStore:    documentStore,
+		childStore:       childStore,
+		clock:            clock,
+		clientEncryption: clientEncryption,
+	}
+}
+
+// UploadDocument adds a scanned document of a child. PDFs and PNG or JPEG images are accepted, unless the document
+// is encrypted by the client. Images are normalized like gallery photos, so that no metadata of the camera is kept.
+func (service *ChildDocumentServiceImpl) UploadDocument(logger *logrus.Entry, ctx context.Context, document *models.ChildDocument, user *models.User) (*models.ChildDocument, error) {
+	if err := models.ValidateChildDocument(*document); err != nil {
+		logger.WithError(err).Warn("Invalid input for UploadDocument")
+		return nil, invalidInput(err)
+	}
+	if err := checkEnvelope(logger, service.clientEncryption, document.Envelope); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 || len(document.Content) > MaxChildDocumentSize {
+		logger.WithField("size", len(document.Content)).Warn("Invalid child document size")
+		return nil, ErrInvalidInput
+	}
+	switch {
+	case document.Envelope != nil:
+		document.ContentType = encryptedContentType
+	case http.DetectContentType(document.Content) == pdfContentType:
+		document.ContentType = pdfContentType
+	default:
+		content, contentType, err := prepareImage(logger, document.Content)
+		if err != nil {
+			return nil, err
+		}
+		document.Content, document.ContentType = content, contentType
+	}
+	if _, err := service.childStore.GetByID(ctx, document.ChildID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", document.ChildID).Error("Error fetching child for document upload")
+		return nil, ErrInternal
+	}
+	document.UploadedByUserID = &user.ID
+
+	id, err := service.documentStore.Create(ctx, document)
+	if err != nil {
+		if errors.Is(err, data.ErrForeignKeyConstraint) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", document.ChildID).Error("Error uploading child document")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"document_id": id, "child_id": document.ChildID, "document_type": document.DocumentType}).Info("Child document uploaded successfully")
+	return service.GetDocumentByID(logger, ctx, id)
+}
+
+// GetDocumentByID fetches a document by ID without its PDF.
+func (service *ChildDocumentServiceImpl) GetDocumentByID(logger *logrus.Entry, ctx context.Context, id int) (*models.ChildDocument, error) {
+	document, err := service.documentStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrChildDocumentNotFound
+		}
+		logger.WithError(err).WithField("document_id", id).Error("Error fetching child document")
+		return nil, ErrInternal
+	}
+	return document, nil
+}
+
+// GetDocumentContent fetches a document with its PDF.
+func (service *ChildDocumentServiceImpl) GetDocumentContent(logger *logrus.Entry, ctx context.Context, id int) (*models.ChildDocument, error) {
+	document, err := service.documentStore.GetWithContent(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrChildDocumentNotFound
+		}
+		logger.WithError(err).WithField("document_id", id).Error("Error fetching child document content")
+		return nil, ErrInternal
+	}
+	return document, nil
+}
+
+// UpdateDocument changes the type, the file name and the expiry date of a document, e.g. after a misfiled upload.
+func (service *ChildDocumentServiceImpl) UpdateDocument(logger *logrus.Entry, ctx context.Context, document *models.ChildDocument) error {
+	if err := models.ValidateChildDocument(*document); err != nil {
+		logger.WithError(err).Warn("Invalid input for UpdateDocument")
+		return invalidInput(err)
+	}
+	if err := service.documentStore.Update(ctx, document); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrChildDocumentNotFound
+		}
+		logger.WithError(err).WithField("document_id", document.ID).Error("Error updating child document")
+		return ErrInternal
+	}
+	logger.WithField("document_id", document.ID).Info("Child document updated successfully")
+	return nil
+}
+
+// DeleteDocument deletes a document.
+func (service *ChildDocumentServiceImpl) DeleteDocument(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.documentStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrChildDocumentNotFound
+		}
+		logger.WithError(err).WithField("document_id", id).Error("Error deleting child document")
+		return ErrInternal
+	}
+	logger.WithField("document_id", id).Info("Child document deleted successfully")
+	return nil
+}
+
+// GetChildDocuments fetches the documents of a child ordered by type, the latest first.
+func (service *ChildDocumentServiceImpl) GetChildDocuments(logger *logrus.Entry, ctx context.Context, childID int) ([]models.ChildDocument, error) {
+	if _, err := service.childStore.GetByID(ctx, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for documents")
+		return nil, ErrInternal
+	}
+	documents, err := service.documentStore.GetForChild(ctx, childID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child documents")
+		return nil, ErrInternal
+	}
+	return documents, nil
+}
+
+// GetReminders lists the document reminders of the children that are not archived, ordered by child and type.
+// For every type a child has documents of, the document that is valid the longest decides, so an expired Attest
+// is not reminded of once its successor has been uploaded.
+func (service *ChildDocumentServiceImpl) GetReminders(logger *logrus.Entry, ctx context.Context, withinDays int) ([]models.DocumentReminder, error) {
+	if withinDays < 0 {
+		logger.WithField("within_days", withinDays).Warn("Invalid reminder period")
+		return nil, ErrInvalidInput
+	}
+	children, err := service.childStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching children for document reminders")
+		return nil, ErrInternal
+	}
+	documents, err := service.documentStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching child documents for reminders")
+		return nil, ErrInternal
+	}
+
+	// Longest valid document per child and type
+	type childType struct {
+		childID      int
+		documentType string
+	}
+	latest := make(map[childType]models.ChildDocument)
+	typesOfChild := make(map[int][]string)
+	for _, document := range documents {
+		key := childType{document.ChildID, document.DocumentType}
+		current, ok := latest[key]
+		if !ok {
+			typesOfChild[document.ChildID] = append(typesOfChild[document.ChildID], document.DocumentType)
+		}
+		if !ok || validLonger(document, current) {
+			latest[key] = document
+		}
+	}
+
+	today := models.Today(service.clock.Now())
+	horizon := today.AddDays(withinDays)
+	reminders := []models.DocumentReminder{}
+	for _, child := range children {
+		types := slices.Clone(models.RequiredChildDocumentTypes)
+		for _, documentType := range typesOfChild[child.ID] {
+			if !slices.Contains(types, documentType) {
+				types = append(types, documentType)
+			}
+		}
+		for _, documentType := range types {
+			reminder := models.DocumentReminder{
+				ChildID:        child.ID,
+				ChildFirstName: child.FirstName,
+				ChildLastName:  child.LastName,
+				DocumentType:   documentType,
+			}
+			document, ok := latest[childType{child.ID, documentType}]
+			switch {
+			case !ok:
+				reminder.Reason = models.DocumentReminderMissing
+			case !document.IsValidOn(today):
+				reminder.Reason = models.DocumentReminderExpired
+			case !document.IsValidOn(horizon.AddDays(1)):
+				reminder.Reason = models.DocumentReminderExpiring
+			default:
+				continue
+			}
+			if ok {
+				reminder.DocumentID = &document.ID
+				reminder.ExpiresOn = document.ExpiresOn
+			}
+			reminders = append(reminders, reminder)
+		}
+	}
+	return reminders, nil
+}
+
+// validLonger reports whether document a is valid longer than document b, documents without expiry date are valid
+// the longest.
+func validLonger(a, b models.ChildDocument) bool {
+	if b.ExpiresOn == nil {
+		return false
+	}
+	return a.ExpiresOn == nil || a.ExpiresOn.After(b.ExpiresOn.Time)
+}
@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChildDocumentService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	user := &models.User{ID: 2, Username: "maria.schmidt", Role: string(data.RoleTeacher)}
+	now := time.Date(2024, time.October, 15, 10, 0, 0, 0, time.UTC)
+	pdf := []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n")
+
+	setup := func() (*services.ChildDocumentServiceImpl, *datamocks.MockChildDocumentStore, *datamocks.MockChildStore) {
+		documentStore := new(datamocks.MockChildDocumentStore)
+		childStore := new(datamocks.MockChildStore)
+		childStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Maybe()
+		childStore.On("GetByID", mock.Anything, 99).Return(nil, data.ErrNotFound).Maybe()
+		return services.NewChildDocumentService(documentStore, childStore, clock.NewFrozen(now), config.ClientEncryptionOptional), documentStore, childStore
+	}
+
+	t.Run("upload", func(t *testing.T) {
+		service, store, _ := setup()
+		store.On("Create", mock.Anything, mock.MatchedBy(func(document *models.ChildDocument) bool {
+			return *document.UploadedByUserID == user.ID && document.ContentType == "application/pdf"
+		})).Return(5, nil).Once()
+		store.On("GetByID", mock.Anything, 5).Return(&models.ChildDocument{ID: 5, ChildID: 1}, nil).Once()
+
+		document := &models.ChildDocument{ChildID: 1, DocumentType: models.ChildDocumentTypeCareContract, FileName: "Vertrag.pdf", Content: pdf}
+		created, err := service.UploadDocument(logger, ctx, document, user)
+		require.NoError(t, err)
+		assert.Equal(t, 5, created.ID)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("upload of scanned images", func(t *testing.T) {
+		service, store, _ := setup()
+		var stored *models.ChildDocument
+		store.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.ChildDocument)
+		}).Return(5, nil).Once()
+		store.On("GetByID", mock.Anything, 5).Return(&models.ChildDocument{ID: 5, ChildID: 1}, nil).Once()
+
+		// Photographed pages are normalized like gallery photos, the position of the camera is dropped
+		document := &models.ChildDocument{ChildID: 1, DocumentType: models.ChildDocumentTypeMeaslesProtection, FileName: "Impfpass.jpg",
+			Content: jpegWithExif(t, 30, 20, 6)}
+		_, err := service.UploadDocument(logger, ctx, document, user)
+		require.NoError(t, err)
+		assert.Equal(t, "image/jpeg", stored.ContentType)
+		assert.NotContains(t, string(stored.Content), "GPS 52.5200N")
+		config, _, err := image.DecodeConfig(bytes.NewReader(stored.Content))
+		require.NoError(t, err)
+		assert.Equal(t, 20, config.Width, "the page is turned upright")
+	})
+
+	t.Run("upload rejects invalid documents", func(t *testing.T) {
+		service, store, _ := setup()
+		valid := models.ChildDocument{ChildID: 1, DocumentType: models.ChildDocumentTypeCareContract, FileName: "Vertrag.pdf", Content: pdf}
+		tests := []struct {
+			name   string
+			modify func(document *models.ChildDocument)
+			want   error
+		}{
+			{"unknown type", func(document *models.ChildDocument) { document.DocumentType = "passport" }, services.ErrInvalidInput},
+			{"no file name", func(document *models.ChildDocument) { document.FileName = "" }, services.ErrInvalidInput},
+			{"neither PDF nor image", func(document *models.ChildDocument) { document.Content = []byte("Betreuungsvertrag") }, services.ErrInvalidInput},
+			{"broken image", func(document *models.ChildDocument) { document.Content = []byte("\x89PNG\r\n\x1a\n") }, services.ErrInvalidInput},
+			{"HEIC", func(document *models.ChildDocument) {
+				document.Content = append([]byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00"), make([]byte, 64)...)
+			}, services.ErrPhotoHEIC},
+			{"too large", func(document *models.ChildDocument) {
+				document.Content = append(pdf, make([]byte, services.MaxChildDocumentSize)...)
+			}, services.ErrInvalidInput},
+			{"unknown child", func(document *models.ChildDocument) { document.ChildID = 99 }, services.ErrChildNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				document := valid
+				tt.modify(&document)
+				_, err := service.UploadDocument(logger, ctx, &document, user)
+				assert.ErrorIs(t, err, tt.want)
+			})
+		}
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("upload of client-side encrypted documents", func(t *testing.T) {
+		service, store, _ := setup()
+		envelope := &models.AttachmentEnvelope{Algorithm: models.EnvelopeAlgorithmAES256GCM, KeyID: "traeger-2024", Nonce: "bm9uY2U=",
+			WrappedKey: "a2V5"}
+		store.On("Create", mock.Anything, mock.MatchedBy(func(document *models.ChildDocument) bool {
+			return document.Envelope == envelope && document.ContentType == "application/octet-stream"
+		})).Return(5, nil).Once()
+		store.On("GetByID", mock.Anything, 5).Return(&models.ChildDocument{ID: 5, ChildID: 1, Envelope: envelope}, nil).Once()
+
+		document := &models.ChildDocument{ChildID: 1, DocumentType: models.ChildDocumentTypeCareContract, FileName: "Vertrag.pdf",
+			Content: []byte("\x00ciphertext"), Envelope: envelope}
+		_, err := service.UploadDocument(logger, ctx, document, user)
+		require.NoError(t, err, "the content of encrypted documents is not checked")
+		store.AssertExpectations(t)
+
+		document.Envelope = nil
+		required := services.NewChildDocumentService(store, new(datamocks.MockChildStore), clock.NewFrozen(now), config.ClientEncryptionRequired)
+		_, err = required.UploadDocument(logger, ctx, document, user)
+		assert.ErrorIs(t, err, services.ErrClientEncryptionRequired)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		service, store, _ := setup()
+		store.On("GetWithContent", mock.Anything, 6).Return(nil, data.ErrNotFound).Once()
+		store.On("Delete", mock.Anything, 6).Return(data.ErrNotFound).Once()
+
+		_, err := service.GetDocumentContent(logger, ctx, 6)
+		assert.ErrorIs(t, err, services.ErrChildDocumentNotFound)
+		assert.ErrorIs(t, service.DeleteDocument(logger, ctx, 6), services.ErrChildDocumentNotFound)
+	})
+
+	t.Run("reminders", func(t *testing.T) {
+		service, store, childStore := setup()
+		date := func(year int, month time.Month, day int) *models.Date {
+			d := models.NewDate(year, month, day)
+			return &d
+		}
+		childStore.On("GetAll", mock.Anything).Return([]models.Child{
+			{ID: 1, FirstName: "Anna", LastName: "Müller"},
+			{ID: 2, FirstName: "Ben", LastName: "Schulz"},
+		}, nil)
+		store.On("GetAll", mock.Anything).Return([]models.ChildDocument{
+			// Anna has everything, her old Attest has a successor that expires in November.
+			{ID: 10, ChildID: 1, DocumentType: models.ChildDocumentTypeCareContract},
+			{ID: 11, ChildID: 1, DocumentType: models.ChildDocumentTypeMeaslesProtection},
+			{ID: 12, ChildID: 1, DocumentType: models.ChildDocumentTypeMedicalCertificate, ExpiresOn: date(2024, time.March, 31)},
+			{ID: 13, ChildID: 1, DocumentType: models.ChildDocumentTypeMedicalCertificate, ExpiresOn: date(2024, time.November, 14)},
+			// Ben's contract has expired, his Masernschutznachweis is missing. Documents of archived children are ignored.
+			{ID: 20, ChildID: 2, DocumentType: models.ChildDocumentTypeCareContract, ExpiresOn: date(2024, time.October, 14)},
+			{ID: 30, ChildID: 3, DocumentType: models.ChildDocumentTypeCareContract, ExpiresOn: date(2024, time.October, 1)},
+		}, nil)
+
+		reminders, err := service.GetReminders(logger, ctx, 30)
+		require.NoError(t, err)
+		require.Len(t, reminders, 3)
+		assert.Equal(t, 1, reminders[0].ChildID)
+		assert.Equal(t, models.ChildDocumentTypeMedicalCertificate, reminders[0].DocumentType)
+		assert.Equal(t, models.DocumentReminderExpiring, reminders[0].Reason)
+		assert.Equal(t, 13, *reminders[0].DocumentID)
+		assert.Equal(t, date(2024, time.November, 14), reminders[0].ExpiresOn)
+		assert.Equal(t, models.DocumentReminder{ChildID: 2, ChildFirstName: "Ben", ChildLastName: "Schulz",
+			DocumentType: models.ChildDocumentTypeCareContract, Reason: models.DocumentReminderExpired,
+			DocumentID: reminders[1].DocumentID, ExpiresOn: date(2024, time.October, 14)}, reminders[1])
+		assert.Equal(t, 20, *reminders[1].DocumentID)
+		assert.Equal(t, models.DocumentReminderMissing, reminders[2].Reason)
+		assert.Equal(t, models.ChildDocumentTypeMeaslesProtection, reminders[2].DocumentType)
+		assert.Nil(t, reminders[2].DocumentID)
+
+		reminders, err = service.GetReminders(logger, ctx, 29)
+		require.NoError(t, err)
+		assert.Len(t, reminders, 2, "the Attest expires after the period")
+		_, err = service.GetReminders(logger, ctx, -1)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+}
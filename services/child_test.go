@@ -7,6 +7,7 @@ import (
 
 	"kitadoc-backend/data"
 	"kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
@@ -22,7 +23,7 @@ func timePtr(t time.Time) *time.Time {
 
 func TestCreateChild(t *testing.T) {
 	mockChildStore := new(mocks.MockChildStore)
-	service := services.NewChildService(mockChildStore)
+	service := services.NewChildService(mockChildStore, nil, nil, nil, nil, clock.System{})
 
 	log_level, _ := logrus.ParseLevel("debug")
 	logger.InitGlobalLogger(
@@ -37,17 +38,18 @@ func TestCreateChild(t *testing.T) {
 		child := &models.Child{
 			FirstName:                "John",
 			LastName:                 "Doe",
-			Birthdate:                time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate:                models.NewDate(2024, 1, 1),
 			AdmissionDate:            timePtr(time.Now()),
 			ExpectedSchoolEnrollment: timePtr(time.Now().AddDate(1, 0, 0)),
 		}
-		mockChildStore.On("Create", mock.AnythingOfType("*models.Child")).Return(1, nil).Once()
+		persisted := &models.Child{ID: 1, FirstName: "John", LastName: "Doe", CreatedAt: time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)}
+		mockChildStore.On("Create", mock.Anything, mock.AnythingOfType("*models.Child")).Return(1, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(persisted, nil).Once()
 
-		createdChild, err := service.CreateChild(child)
+		createdChild, err := service.CreateChild(t.Context(), child)
 
 		assert.NoError(t, err)
-		assert.NotNil(t, createdChild)
-		assert.Equal(t, 1, createdChild.ID)
+		assert.Equal(t, persisted, createdChild, "the persisted child is returned")
 		mockChildStore.AssertExpectations(t)
 	})
 
@@ -56,15 +58,15 @@ func TestCreateChild(t *testing.T) {
 		child := &models.Child{
 			FirstName: "", // Invalid empty first name
 			LastName:  "Doe",
-			Birthdate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate: models.NewDate(2024, 1, 1),
 		}
 
-		createdChild, err := service.CreateChild(child)
+		createdChild, err := service.CreateChild(t.Context(), child)
 
 		assert.Error(t, err)
-		assert.Equal(t, services.ErrInvalidInput, err)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
 		assert.Nil(t, createdChild)
-		mockChildStore.AssertNotCalled(t, "Create")
+		mockChildStore.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
 	// Test case 3: Internal error during child creation
@@ -72,13 +74,13 @@ func TestCreateChild(t *testing.T) {
 		child := &models.Child{
 			FirstName:                "John",
 			LastName:                 "Doe",
-			Birthdate:                time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate:                models.NewDate(2024, 1, 1),
 			AdmissionDate:            timePtr(time.Now()),
 			ExpectedSchoolEnrollment: timePtr(time.Now().AddDate(1, 0, 0)),
 		}
-		mockChildStore.On("Create", mock.AnythingOfType("*models.Child")).Return(0, errors.New("db error")).Once()
+		mockChildStore.On("Create", mock.Anything, mock.AnythingOfType("*models.Child")).Return(0, errors.New("db error")).Once()
 
-		createdChild, err := service.CreateChild(child)
+		createdChild, err := service.CreateChild(t.Context(), child)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -89,15 +91,15 @@ func TestCreateChild(t *testing.T) {
 
 func TestGetChildByID(t *testing.T) {
 	mockChildStore := new(mocks.MockChildStore)
-	service := services.NewChildService(mockChildStore)
+	service := services.NewChildService(mockChildStore, nil, nil, nil, nil, clock.System{})
 
 	// Test case 1: Successful retrieval
 	t.Run("success", func(t *testing.T) {
 		childID := 1
 		expectedChild := &models.Child{ID: childID, FirstName: "Test Child"}
-		mockChildStore.On("GetByID", childID).Return(expectedChild, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(expectedChild, nil).Once()
 
-		child, err := service.GetChildByID(childID)
+		child, err := service.GetChildByID(t.Context(), childID)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, child)
@@ -109,9 +111,9 @@ func TestGetChildByID(t *testing.T) {
 	// Test case 2: Child not found
 	t.Run("not found", func(t *testing.T) {
 		childID := 99
-		mockChildStore.On("GetByID", childID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(nil, data.ErrNotFound).Once()
 
-		child, err := service.GetChildByID(childID)
+		child, err := service.GetChildByID(t.Context(), childID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
@@ -122,9 +124,9 @@ func TestGetChildByID(t *testing.T) {
 	// Test case 3: Internal error
 	t.Run("internal error", func(t *testing.T) {
 		childID := 1
-		mockChildStore.On("GetByID", childID).Return(nil, errors.New("db error")).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(nil, errors.New("db error")).Once()
 
-		child, err := service.GetChildByID(childID)
+		child, err := service.GetChildByID(t.Context(), childID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -135,7 +137,7 @@ func TestGetChildByID(t *testing.T) {
 
 func TestUpdateChild(t *testing.T) {
 	mockChildStore := new(mocks.MockChildStore)
-	service := services.NewChildService(mockChildStore)
+	service := services.NewChildService(mockChildStore, nil, nil, nil, nil, clock.System{})
 
 	// Test case 1: Successful update
 	t.Run("success", func(t *testing.T) {
@@ -143,13 +145,13 @@ func TestUpdateChild(t *testing.T) {
 			ID:                       1,
 			FirstName:                "Updated John",
 			LastName:                 "Doe",
-			Birthdate:                time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate:                models.NewDate(2024, 1, 1),
 			AdmissionDate:            timePtr(time.Now()),
 			ExpectedSchoolEnrollment: timePtr(time.Now().AddDate(1, 0, 0)),
 		}
-		mockChildStore.On("Update", mock.AnythingOfType("*models.Child")).Return(nil).Once()
+		mockChildStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Child")).Return(nil).Once()
 
-		err := service.UpdateChild(child)
+		err := service.UpdateChild(t.Context(), child)
 
 		assert.NoError(t, err)
 		mockChildStore.AssertExpectations(t)
@@ -161,14 +163,14 @@ func TestUpdateChild(t *testing.T) {
 			ID:        1,
 			FirstName: "", // Invalid: empty first name
 			LastName:  "Doe",
-			Birthdate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate: models.NewDate(2024, 1, 1),
 		}
 
-		err := service.UpdateChild(child)
+		err := service.UpdateChild(t.Context(), child)
 
 		assert.Error(t, err)
-		assert.Equal(t, services.ErrInvalidInput, err)
-		mockChildStore.AssertNotCalled(t, "Update")
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		mockChildStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 3: Child not found during update
@@ -177,13 +179,13 @@ func TestUpdateChild(t *testing.T) {
 			ID:                       99, // Non-existent ID
 			FirstName:                "Updated John",
 			LastName:                 "Doe",
-			Birthdate:                time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate:                models.NewDate(2024, 1, 1),
 			AdmissionDate:            timePtr(time.Now()),
 			ExpectedSchoolEnrollment: timePtr(time.Now().AddDate(1, 0, 0)),
 		}
-		mockChildStore.On("Update", mock.AnythingOfType("*models.Child")).Return(data.ErrNotFound).Once()
+		mockChildStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Child")).Return(data.ErrNotFound).Once()
 
-		err := service.UpdateChild(child)
+		err := service.UpdateChild(t.Context(), child)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
@@ -196,13 +198,13 @@ func TestUpdateChild(t *testing.T) {
 			ID:                       1,
 			FirstName:                "Updated John",
 			LastName:                 "Doe",
-			Birthdate:                time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Birthdate:                models.NewDate(2024, 1, 1),
 			AdmissionDate:            timePtr(time.Now()),
 			ExpectedSchoolEnrollment: timePtr(time.Now().AddDate(1, 0, 0)),
 		}
-		mockChildStore.On("Update", mock.AnythingOfType("*models.Child")).Return(errors.New("db error")).Once()
+		mockChildStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Child")).Return(errors.New("db error")).Once()
 
-		err := service.UpdateChild(child)
+		err := service.UpdateChild(t.Context(), child)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -212,14 +214,14 @@ func TestUpdateChild(t *testing.T) {
 
 func TestDeleteChild(t *testing.T) {
 	mockChildStore := new(mocks.MockChildStore)
-	service := services.NewChildService(mockChildStore)
+	service := services.NewChildService(mockChildStore, nil, nil, nil, nil, clock.System{})
 
 	// Test case 1: Successful deletion
 	t.Run("success", func(t *testing.T) {
 		childID := 1
-		mockChildStore.On("Delete", childID).Return(nil).Once()
+		mockChildStore.On("Delete", mock.Anything, childID).Return(nil).Once()
 
-		err := service.DeleteChild(childID)
+		err := service.DeleteChild(t.Context(), childID)
 
 		assert.NoError(t, err)
 		mockChildStore.AssertExpectations(t)
@@ -228,9 +230,9 @@ func TestDeleteChild(t *testing.T) {
 	// Test case 2: Child not found
 	t.Run("not found", func(t *testing.T) {
 		childID := 99
-		mockChildStore.On("Delete", childID).Return(data.ErrNotFound).Once()
+		mockChildStore.On("Delete", mock.Anything, childID).Return(data.ErrNotFound).Once()
 
-		err := service.DeleteChild(childID)
+		err := service.DeleteChild(t.Context(), childID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
@@ -240,9 +242,9 @@ func TestDeleteChild(t *testing.T) {
 	// Test case 3: Internal error
 	t.Run("internal error", func(t *testing.T) {
 		childID := 1
-		mockChildStore.On("Delete", childID).Return(errors.New("db error")).Once()
+		mockChildStore.On("Delete", mock.Anything, childID).Return(errors.New("db error")).Once()
 
-		err := service.DeleteChild(childID)
+		err := service.DeleteChild(t.Context(), childID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -252,7 +254,7 @@ func TestDeleteChild(t *testing.T) {
 
 func TestGetAllChildren(t *testing.T) {
 	mockChildStore := new(mocks.MockChildStore)
-	service := services.NewChildService(mockChildStore)
+	service := services.NewChildService(mockChildStore, nil, nil, nil, nil, clock.System{})
 
 	// Test case 1: Successful retrieval
 	t.Run("success", func(t *testing.T) {
@@ -260,9 +262,9 @@ func TestGetAllChildren(t *testing.T) {
 			{ID: 1, FirstName: "Child A"},
 			{ID: 2, FirstName: "Child B"},
 		}
-		mockChildStore.On("GetAll").Return(expectedChildren, nil).Once()
+		mockChildStore.On("List", mock.Anything, models.ListQuery{}.Where("archived_at", models.OperatorIsNull, nil)).Return(expectedChildren, nil).Once()
 
-		children, err := service.GetAllChildren()
+		children, err := service.GetAllChildren(t.Context(), models.ListQuery{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, children)
@@ -272,9 +274,9 @@ func TestGetAllChildren(t *testing.T) {
 
 	// Test case 2: Internal error
 	t.Run("internal error", func(t *testing.T) {
-		mockChildStore.On("GetAll").Return(nil, errors.New("db error")).Once()
+		mockChildStore.On("List", mock.Anything, models.ListQuery{}.Where("archived_at", models.OperatorIsNull, nil)).Return(nil, errors.New("db error")).Once()
 
-		children, err := service.GetAllChildren()
+		children, err := service.GetAllChildren(t.Context(), models.ListQuery{})
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -283,9 +285,85 @@ func TestGetAllChildren(t *testing.T) {
 	})
 }
 
+func TestCountChildren(t *testing.T) {
+	mockChildStore := new(mocks.MockChildStore)
+	service := services.NewChildService(mockChildStore, nil, nil, nil, nil, clock.System{})
+	query := models.ListQuery{}.Where("group_id", models.OperatorEqual, 3)
+
+	t.Run("success", func(t *testing.T) {
+		mockChildStore.On("Count", mock.Anything, query.Where("archived_at", models.OperatorIsNull, nil)).Return(12, nil).Once()
+
+		count, err := service.CountChildren(t.Context(), query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12, count)
+		mockChildStore.AssertExpectations(t)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		mockChildStore.On("Count", mock.Anything, query.Where("archived_at", models.OperatorIsNull, nil)).Return(0, data.ErrInvalidInput).Once()
+
+		_, err := service.CountChildren(t.Context(), query)
+
+		assert.Equal(t, services.ErrInvalidInput, err)
+		mockChildStore.AssertExpectations(t)
+	})
+}
+
+func TestGetAllChildrenWithIncludes(t *testing.T) {
+	mockChildStore := new(mocks.MockChildStore)
+	mockGroupStore := new(mocks.MockGroupStore)
+	mockAssignmentStore := new(mocks.MockAssignmentStore)
+	mockTeacherStore := new(mocks.MockTeacherStore)
+	mockDocumentationEntryStore := new(mocks.MockDocumentationEntryStore)
+	service := services.NewChildService(mockChildStore, mockGroupStore, mockAssignmentStore, mockTeacherStore, mockDocumentationEntryStore, clock.System{})
+
+	children := []models.Child{{ID: 1, FirstName: "Child A"}, {ID: 2, FirstName: "Child B"}}
+	endDate := time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC)
+	lastObservation := models.NewDate(2025, 3, 1)
+
+	t.Run("all includes with one lookup each", func(t *testing.T) {
+		mockChildStore.On("List", mock.Anything, models.ListQuery{}.Where("archived_at", models.OperatorIsNull, nil)).Return(children, nil).Once()
+		mockGroupStore.On("GetAll", mock.Anything).Return([]models.Group{{ID: 5, Name: "Igel", ChildIDs: []int{1}}}, nil).Once()
+		mockAssignmentStore.On("GetAllAssignments", mock.Anything).Return([]models.Assignment{
+			{ChildID: 1, TeacherID: 10, AssignmentType: models.AssignmentTypePrimary},
+			{ChildID: 1, TeacherID: 11, AssignmentType: models.AssignmentTypeSecondary},
+			{ChildID: 2, TeacherID: 11, AssignmentType: models.AssignmentTypePrimary, EndDate: &endDate},
+		}, nil).Once()
+		mockTeacherStore.On("GetAll", mock.Anything).Return([]models.Teacher{{ID: 10, FirstName: "Anna", LastName: "Schmidt"}, {ID: 11, FirstName: "Ben"}}, nil).Once()
+		mockDocumentationEntryStore.On("GetLastObservationDates", mock.Anything).Return(map[int]models.Date{2: lastObservation}, nil).Once()
+
+		items, err := service.GetAllChildrenWithIncludes(t.Context(), models.ChildIncludes, models.ListQuery{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.ChildListItem{
+			{
+				Child:          children[0],
+				Group:          &models.ChildGroupSummary{ID: 5, Name: "Igel"},
+				CurrentTeacher: &models.ChildTeacherSummary{ID: 10, FirstName: "Anna", LastName: "Schmidt"},
+			},
+			{Child: children[1], LastObservationDate: &lastObservation},
+		}, items)
+		mockGroupStore.AssertExpectations(t)
+		mockAssignmentStore.AssertExpectations(t)
+		mockTeacherStore.AssertExpectations(t)
+		mockDocumentationEntryStore.AssertExpectations(t)
+	})
+
+	t.Run("only the requested lookups", func(t *testing.T) {
+		mockChildStore.On("List", mock.Anything, models.ListQuery{}.Where("archived_at", models.OperatorIsNull, nil)).Return(children, nil).Once()
+		mockDocumentationEntryStore.On("GetLastObservationDates", mock.Anything).Return(nil, errors.New("db error")).Once()
+
+		_, err := service.GetAllChildrenWithIncludes(t.Context(), []string{models.ChildIncludeLastObservation}, models.ListQuery{})
+
+		assert.Equal(t, services.ErrInternal, err)
+		mockGroupStore.AssertNumberOfCalls(t, "GetAll", 1)
+	})
+}
+
 func TestBulkImportChildren(t *testing.T) {
 	mockChildStore := new(mocks.MockChildStore)
-	service := services.NewChildService(mockChildStore)
+	service := services.NewChildService(mockChildStore, nil, nil, nil, nil, clock.System{})
 
 	// Test case 1: Placeholder for bulk import
 	t.Run("placeholder", func(t *testing.T) {
@@ -294,6 +372,6 @@ func TestBulkImportChildren(t *testing.T) {
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrBulkImportFailed, err)
-		mockChildStore.AssertNotCalled(t, "Create") // Should not call Create in this placeholder
+		mockChildStore.AssertNotCalled(t, "Create", mock.Anything) // Should not call Create in this placeholder
 	})
 }
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// completenessPeriod is how far back observations count towards the completeness score.
+const completenessPeriod = 1 // years
+
+// Weights of the category suggestions. A category without observations in the completeness period weighs
+// most, a documented one the more the longer ago its last observation is, up to suggestionGapDays.
+// Categories whose age band the child is outside of are ranked last.
+const (
+	suggestionWeightNotDocumented  = 100
+	suggestionWeightAgeBand        = 50
+	suggestionWeightOutsideAgeBand = -200
+	suggestionGapDays              = 90
+	suggestionMinGapDays           = 30 // Shorter gaps are not given as a reason
+)
+
+// CompletenessService defines the interface for documentation completeness operations.
+type CompletenessService interface {
+	GetChildCompleteness(logger *logrus.Entry, ctx context.Context, childID int) (*models.ChildCompleteness, error)
+	GetAllCompleteness(logger *logrus.Entry, ctx context.Context) ([]models.ChildCompleteness, error)
+	SuggestCategories(logger *logrus.Entry, ctx context.Context, childID int) ([]models.CategorySuggestion, error)
+}
+
+// CompletenessServiceImpl implements CompletenessService.
+type CompletenessServiceImpl struct {
+	childStore              data.ChildStore
+	categoryStore           data.CategoryStore
+	documentationEntryStore data.DocumentationEntryStore
+	clock                   clock.Clock
+}
+
+// NewCompletenessService creates a new CompletenessServiceImpl.
+func NewCompletenessService(childStore data.ChildStore, categoryStore data.CategoryStore, documentationEntryStore data.DocumentationEntryStore, clock clock.Clock) *CompletenessServiceImpl {
+	return &CompletenessServiceImpl{
+		childStore:              childStore,
+		categoryStore:           categoryStore,
+		documentationEntryStore: documentationEntryStore,
+		clock:                   clock,
+	}
+}
+
+// GetChildCompleteness computes the completeness score of a single child.
+func (service *CompletenessServiceImpl) GetChildCompleteness(logger *logrus.Entry, ctx context.Context, childID int) (*models.ChildCompleteness, error) {
+	child, err := service.childStore.GetByID(ctx, childID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("child_id", childID).Warn("Child not found for completeness score")
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for completeness score")
+		return nil, ErrInternal
+	}
+
+	categories, err := service.categoryStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching categories for completeness score")
+		return nil, ErrInternal
+	}
+
+	return service.computeCompleteness(logger, ctx, child, categories, service.clock.Now())
+}
+
+// GetAllCompleteness computes the completeness score of every active child, for the dashboard.
+func (service *CompletenessServiceImpl) GetAllCompleteness(logger *logrus.Entry, ctx context.Context) ([]models.ChildCompleteness, error) {
+	children, err := service.childStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching children for completeness scores")
+		return nil, ErrInternal
+	}
+
+	categories, err := service.categoryStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching categories for completeness scores")
+		return nil, ErrInternal
+	}
+
+	now := service.clock.Now()
+	result := make([]models.ChildCompleteness, 0, len(children))
+	for i := range children {
+		completeness, err := service.computeCompleteness(logger, ctx, &children[i], categories, now)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *completeness)
+	}
+	return result, nil
+}
+
+// SuggestCategories ranks the active categories for the next observation of a child by its age and the gaps
+// in its documentation, for the category picker of the entry form.
+func (service *CompletenessServiceImpl) SuggestCategories(logger *logrus.Entry, ctx context.Context, childID int) ([]models.CategorySuggestion, error) {
+	child, err := service.childStore.GetByID(ctx, childID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("child_id", childID).Warn("Child not found for category suggestions")
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for category suggestions")
+		return nil, ErrInternal
+	}
+
+	allCategories, err := service.categoryStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching categories for category suggestions")
+		return nil, ErrInternal
+	}
+	categories := slices.DeleteFunc(allCategories, func(category models.Category) bool { return category.IsArchived() })
+
+	now := service.clock.Now()
+	completeness, err := service.computeCompleteness(logger, ctx, child, categories, now)
+	if err != nil {
+		return nil, err
+	}
+
+	today := models.Today(now)
+	ageMonths := models.AgeInMonths(child.Birthdate, today)
+	suggestions := make([]models.CategorySuggestion, len(categories))
+	for i, category := range categories {
+		coverage := completeness.Categories[i]
+		suggestion := models.CategorySuggestion{
+			CategoryID:    category.ID,
+			CategoryName:  category.Name,
+			Reasons:       []string{},
+			EntryCount:    coverage.EntryCount,
+			LastEntryDate: coverage.LastEntryDate,
+		}
+		if coverage.LastEntryDate == nil {
+			suggestion.Score += suggestionWeightNotDocumented
+			suggestion.Reasons = append(suggestion.Reasons, models.SuggestionReasonNotDocumented)
+		} else {
+			gapDays := min(int(today.Sub(coverage.LastEntryDate.Time).Hours()/24), suggestionGapDays)
+			suggestion.Score += gapDays * suggestionWeightNotDocumented / suggestionGapDays
+			if gapDays >= suggestionMinGapDays {
+				suggestion.Reasons = append(suggestion.Reasons, models.SuggestionReasonDocumentationGap)
+			}
+		}
+		if category.HasAgeBand() {
+			if category.AcceptsAge(ageMonths) {
+				suggestion.Score += suggestionWeightAgeBand
+				suggestion.Reasons = append(suggestion.Reasons, models.SuggestionReasonAgeBand)
+			} else {
+				suggestion.Score += suggestionWeightOutsideAgeBand
+				suggestion.Reasons = append(suggestion.Reasons, models.SuggestionReasonOutsideAgeBand)
+			}
+		}
+		suggestions[i] = suggestion
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b models.CategorySuggestion) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		return a.CategoryID - b.CategoryID
+	})
+	return suggestions, nil
+}
+
+func (service *CompletenessServiceImpl) computeCompleteness(logger *logrus.Entry, ctx context.Context, child *models.Child, categories []models.Category, now time.Time) (*models.ChildCompleteness, error) {
+	entries, err := service.documentationEntryStore.GetAllForChild(ctx, child.ID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", child.ID).Error("Error fetching documentation entries for completeness score")
+		return nil, ErrInternal
+	}
+
+	completeness := &models.ChildCompleteness{
+		ChildID:         child.ID,
+		ChildName:       fmt.Sprintf("%s %s", child.FirstName, child.LastName),
+		CategoriesTotal: len(categories),
+		PeriodStart:     now.AddDate(-completenessPeriod, 0, 0),
+		PeriodEnd:       now,
+		Categories:      make([]models.CategoryCoverage, len(categories)),
+	}
+	coverageByCategory := make(map[int]*models.CategoryCoverage, len(categories))
+	for i, category := range categories {
+		completeness.Categories[i] = models.CategoryCoverage{CategoryID: category.ID, CategoryName: category.Name}
+		coverageByCategory[category.ID] = &completeness.Categories[i]
+	}
+
+	for _, entry := range entries {
+		if entry.ObservationDate.Before(completeness.PeriodStart) || entry.ObservationDate.After(now) {
+			continue
+		}
+		coverage, ok := coverageByCategory[entry.CategoryID]
+		if !ok {
+			continue
+		}
+		observationDate := entry.ObservationDate
+		coverage.EntryCount++
+		if coverage.LastEntryDate == nil || observationDate.After(coverage.LastEntryDate.Time) {
+			coverage.LastEntryDate = &observationDate
+		}
+		completeness.EntryCount++
+		if completeness.LastEntryDate == nil || observationDate.After(completeness.LastEntryDate.Time) {
+			completeness.LastEntryDate = &observationDate
+		}
+	}
+
+	for _, coverage := range completeness.Categories {
+		if coverage.EntryCount > 0 {
+			completeness.CategoriesCovered++
+		}
+	}
+	if completeness.CategoriesTotal > 0 {
+		completeness.Score = completeness.CategoriesCovered * 100 / completeness.CategoriesTotal
+	}
+	if completeness.LastEntryDate != nil {
+		ageDays := int(models.Today(now).Sub(completeness.LastEntryDate.Time).Hours() / 24)
+		completeness.LastEntryAgeDays = &ageDays
+	}
+	return completeness, nil
+}
@@ -0,0 +1,157 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetChildCompleteness(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	categories := []models.Category{{ID: 1, Name: "Sprache"}, {ID: 2, Name: "Bewegung"}, {ID: 3, Name: "Musik"}, {ID: 4, Name: "Natur"}}
+
+	t.Run("success", func(t *testing.T) {
+		mockChildStore := new(datamocks.MockChildStore)
+		mockCategoryStore := new(datamocks.MockCategoryStore)
+		mockDocStore := new(datamocks.MockDocumentationEntryStore)
+		service := services.NewCompletenessService(mockChildStore, mockCategoryStore, mockDocStore, clock.System{})
+
+		now := time.Now()
+		recent := now.AddDate(0, 0, -10)
+		older := now.AddDate(0, -3, 0)
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1, FirstName: "Max", LastName: "Muster"}, nil).Once()
+		mockCategoryStore.On("GetAll", mock.Anything).Return(categories, nil).Once()
+		mockDocStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{
+			{ID: 1, ChildID: 1, CategoryID: 1, ObservationDate: models.DateOf(older)},
+			{ID: 2, ChildID: 1, CategoryID: 1, ObservationDate: models.DateOf(recent)},
+			{ID: 3, ChildID: 1, CategoryID: 2, ObservationDate: models.DateOf(older)},
+			{ID: 4, ChildID: 1, CategoryID: 3, ObservationDate: models.DateOf(now.AddDate(-2, 0, 0))},
+		}, nil).Once()
+
+		completeness, err := service.GetChildCompleteness(logger, ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "Max Muster", completeness.ChildName)
+		assert.Equal(t, 4, completeness.CategoriesTotal)
+		assert.Equal(t, 2, completeness.CategoriesCovered)
+		assert.Equal(t, 50, completeness.Score)
+		assert.Equal(t, 3, completeness.EntryCount)
+		assert.Equal(t, 2, completeness.Categories[0].EntryCount)
+		assert.Equal(t, models.DateOf(recent), *completeness.Categories[0].LastEntryDate)
+		assert.Equal(t, 0, completeness.Categories[2].EntryCount, "entries older than a year do not count")
+		assert.Nil(t, completeness.Categories[3].LastEntryDate)
+		if assert.NotNil(t, completeness.LastEntryAgeDays) {
+			assert.Equal(t, 10, *completeness.LastEntryAgeDays)
+		}
+		mockChildStore.AssertExpectations(t)
+		mockCategoryStore.AssertExpectations(t)
+		mockDocStore.AssertExpectations(t)
+	})
+
+	t.Run("no entries", func(t *testing.T) {
+		mockChildStore := new(datamocks.MockChildStore)
+		mockCategoryStore := new(datamocks.MockCategoryStore)
+		mockDocStore := new(datamocks.MockDocumentationEntryStore)
+		service := services.NewCompletenessService(mockChildStore, mockCategoryStore, mockDocStore, clock.System{})
+
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Once()
+		mockCategoryStore.On("GetAll", mock.Anything).Return(categories, nil).Once()
+		mockDocStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{}, nil).Once()
+
+		completeness, err := service.GetChildCompleteness(logger, ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, completeness.Score)
+		assert.Nil(t, completeness.LastEntryDate)
+		assert.Nil(t, completeness.LastEntryAgeDays)
+	})
+
+	t.Run("child not found", func(t *testing.T) {
+		mockChildStore := new(datamocks.MockChildStore)
+		service := services.NewCompletenessService(mockChildStore, new(datamocks.MockCategoryStore), new(datamocks.MockDocumentationEntryStore), clock.System{})
+		mockChildStore.On("GetByID", mock.Anything, 99).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.GetChildCompleteness(logger, ctx, 99)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+}
+
+func TestGetAllCompleteness(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	mockChildStore := new(datamocks.MockChildStore)
+	mockCategoryStore := new(datamocks.MockCategoryStore)
+	mockDocStore := new(datamocks.MockDocumentationEntryStore)
+	service := services.NewCompletenessService(mockChildStore, mockCategoryStore, mockDocStore, clock.System{})
+
+	mockChildStore.On("GetAll", mock.Anything).Return([]models.Child{{ID: 1}, {ID: 2}}, nil).Once()
+	mockCategoryStore.On("GetAll", mock.Anything).Return([]models.Category{{ID: 1, Name: "Sprache"}}, nil).Once()
+	mockDocStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{{ID: 1, ChildID: 1, CategoryID: 1, ObservationDate: models.Today(time.Now())}}, nil).Once()
+	mockDocStore.On("GetAllForChild", mock.Anything, 2).Return([]models.DocumentationEntry{}, nil).Once()
+
+	scores, err := service.GetAllCompleteness(logger, context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, scores, 2)
+	assert.Equal(t, 100, scores[0].Score)
+	assert.Equal(t, 0, scores[1].Score)
+	mockDocStore.AssertExpectations(t)
+}
+
+func TestSuggestCategories(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	today := models.Today(time.Now())
+	archivedAt := time.Now()
+
+	t.Run("ranks by documentation gaps and age band", func(t *testing.T) {
+		mockChildStore := new(datamocks.MockChildStore)
+		mockCategoryStore := new(datamocks.MockCategoryStore)
+		mockDocStore := new(datamocks.MockDocumentationEntryStore)
+		service := services.NewCompletenessService(mockChildStore, mockCategoryStore, mockDocStore, clock.System{})
+
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1, Birthdate: models.DateOf(today.AddDate(-4, 0, 0))}, nil).Once()
+		mockCategoryStore.On("GetAll", mock.Anything).Return([]models.Category{
+			{ID: 1, Name: "Sprache"},
+			{ID: 2, Name: "Bewegung"},
+			{ID: 3, Name: "Schulvorbereitung", MinAgeMonths: intPtr(60)},
+			{ID: 4, Name: "Musik", MinAgeMonths: intPtr(36), MaxAgeMonths: intPtr(72)},
+			{ID: 5, Name: "Alt", ArchivedAt: &archivedAt},
+			{ID: 6, Name: "Natur"},
+		}, nil).Once()
+		mockDocStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{
+			{ID: 1, ChildID: 1, CategoryID: 1, ObservationDate: today},
+			{ID: 2, ChildID: 1, CategoryID: 2, ObservationDate: today.AddDays(-60)},
+		}, nil).Once()
+
+		suggestions, err := service.SuggestCategories(logger, ctx, 1)
+		assert.NoError(t, err)
+		ids := []int{}
+		for _, suggestion := range suggestions {
+			ids = append(ids, suggestion.CategoryID)
+		}
+		assert.Equal(t, []int{4, 6, 2, 1, 3}, ids)
+		assert.Equal(t, []string{models.SuggestionReasonNotDocumented, models.SuggestionReasonAgeBand}, suggestions[0].Reasons)
+		assert.Equal(t, []string{models.SuggestionReasonDocumentationGap}, suggestions[2].Reasons)
+		assert.Equal(t, 1, suggestions[2].EntryCount)
+		assert.Equal(t, []string{}, suggestions[3].Reasons)
+		assert.Equal(t, []string{models.SuggestionReasonNotDocumented, models.SuggestionReasonOutsideAgeBand}, suggestions[4].Reasons)
+		mockDocStore.AssertExpectations(t)
+	})
+
+	t.Run("child not found", func(t *testing.T) {
+		mockChildStore := new(datamocks.MockChildStore)
+		service := services.NewCompletenessService(mockChildStore, new(datamocks.MockCategoryStore), new(datamocks.MockDocumentationEntryStore), clock.System{})
+		mockChildStore.On("GetByID", mock.Anything, 99).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.SuggestCategories(logger, ctx, 99)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DailyCareService defines the interface for the daily care logs of U3 children.
+type DailyCareService interface {
+	// RecordDailyCare creates or replaces the log of a child on a day.
+	RecordDailyCare(logger *logrus.Entry, ctx context.Context, log *models.DailyCareLog, user *models.User) (*models.DailyCareLog, error)
+	// RecordGroupDailyCare creates or replaces the logs of several children of a group on a day, all or none of
+	// them are saved. It returns the care of the U3 children of the group on the day.
+	RecordGroupDailyCare(logger *logrus.Entry, ctx context.Context, groupID int, day models.Date, logs []models.DailyCareLog, user *models.User) ([]models.GroupDailyCare, error)
+	// GetGroupDailyCare returns the U3 children of a group with their logs of a day, ordered by name.
+	GetGroupDailyCare(logger *logrus.Entry, ctx context.Context, groupID int, day models.Date) ([]models.GroupDailyCare, error)
+	// GetChildDailyCare returns the logs of a child between two days, both included. The parents view leaves out
+	// the logs hidden from parents.
+	GetChildDailyCare(logger *logrus.Entry, ctx context.Context, childID int, from models.Date, to models.Date, parentsView bool) ([]models.DailyCareLog, error)
+	DeleteDailyCare(logger *logrus.Entry, ctx context.Context, childID int, day models.Date) error
+}
+
+// DailyCareServiceImpl implements DailyCareService.
+type DailyCareServiceImpl struct {
+	dailyCareStore data.DailyCareStore
+	childStore     data.ChildStore
+	groupStore     data.GroupStore
+	clock          clock.Clock
+}
+
+// NewDailyCareService creates a new DailyCareServiceImpl.
+func NewDailyCareService(dailyCareStore data.DailyCareStore, childStore data.ChildStore, groupStore data.GroupStore, clock clock.Clock) *DailyCareServiceImpl {
+	return &DailyCareServiceImpl{
+		dailyCareStore: dailyCareStore,
+		childStore:     childStore,
+		groupStore:     groupStore,
+		clock:          clock,
+	}
+}
+
+// RecordDailyCare creates or replaces the log of a child on a day.
+func (service *DailyCareServiceImpl) RecordDailyCare(logger *logrus.Entry, ctx context.Context, log *models.DailyCareLog, user *models.User) (*models.DailyCareLog, error) {
+	child, err := service.getChild(logger, ctx, log.ChildID)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.checkLog(logger, child, log); err != nil {
+		return nil, err
+	}
+	log.RecordedByUserID = &user.ID
+
+	if err := service.dailyCareStore.Upsert(ctx, []models.DailyCareLog{*log}); err != nil {
+		logger.WithError(err).WithField("child_id", log.ChildID).Error("Error recording daily care")
+		return nil, ErrInternal
+	}
+	recorded, err := service.dailyCareStore.Get(ctx, log.ChildID, log.CareDate)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", log.ChildID).Error("Error fetching recorded daily care")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"child_id": log.ChildID, "care_date": log.CareDate}).Info("Daily care recorded successfully")
+	return recorded, nil
+}
+
+// RecordGroupDailyCare creates or replaces the logs of children of a group on a day.
+func (service *DailyCareServiceImpl) RecordGroupDailyCare(logger *logrus.Entry, ctx context.Context, groupID int, day models.Date, logs []models.DailyCareLog, user *models.User) ([]models.GroupDailyCare, error) {
+	group, err := service.getGroup(logger, ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool, len(logs))
+	for i := range logs {
+		log := &logs[i]
+		if seen[log.ChildID] {
+			logger.WithField("child_id", log.ChildID).Warn("Child listed twice in group daily care")
+			return nil, ErrInvalidInput
+		}
+		seen[log.ChildID] = true
+		if !slices.Contains(group.ChildIDs, log.ChildID) {
+			logger.WithFields(logrus.Fields{"group_id": groupID, "child_id": log.ChildID}).Warn("Child of group daily care is not in the group")
+			return nil, ErrChildNotInGroup
+		}
+		child, err := service.getChild(logger, ctx, log.ChildID)
+		if err != nil {
+			return nil, err
+		}
+		log.CareDate = day
+		if err := service.checkLog(logger, child, log); err != nil {
+			return nil, err
+		}
+		log.RecordedByUserID = &user.ID
+	}
+
+	if err := service.dailyCareStore.Upsert(ctx, logs); err != nil {
+		logger.WithError(err).WithField("group_id", groupID).Error("Error recording group daily care")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"group_id": groupID, "care_date": day, "logs": len(logs)}).Info("Group daily care recorded successfully")
+	return service.GetGroupDailyCare(logger, ctx, groupID, day)
+}
+
+// GetGroupDailyCare returns the U3 children of a group with their logs of a day. Archived children are left out.
+func (service *DailyCareServiceImpl) GetGroupDailyCare(logger *logrus.Entry, ctx context.Context, groupID int, day models.Date) ([]models.GroupDailyCare, error) {
+	group, err := service.getGroup(logger, ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := service.dailyCareStore.GetForDay(ctx, day)
+	if err != nil {
+		logger.WithError(err).WithField("care_date", day).Error("Error fetching daily care of day")
+		return nil, ErrInternal
+	}
+	logsByChild := make(map[int]*models.DailyCareLog, len(logs))
+	for i := range logs {
+		logsByChild[logs[i].ChildID] = &logs[i]
+	}
+
+	care := []models.GroupDailyCare{}
+	for _, childID := range group.ChildIDs {
+		child, err := service.getChild(logger, ctx, childID)
+		if err != nil {
+			return nil, err
+		}
+		if child.ArchivedAt != nil || models.AgeInMonths(child.Birthdate, day) >= models.U3AgeMonths {
+			continue
+		}
+		care = append(care, models.GroupDailyCare{
+			ChildID:   child.ID,
+			FirstName: child.FirstName,
+			LastName:  child.LastName,
+			Log:       logsByChild[child.ID],
+		})
+	}
+	slices.SortFunc(care, func(a, b models.GroupDailyCare) int {
+		return cmp.Or(cmp.Compare(a.FirstName, b.FirstName), cmp.Compare(a.LastName, b.LastName), cmp.Compare(a.ChildID, b.ChildID))
+	})
+	return care, nil
+}
+
+// GetChildDailyCare returns the logs of a child between two days.
+func (service *DailyCareServiceImpl) GetChildDailyCare(logger *logrus.Entry, ctx context.Context, childID int, from models.Date, to models.Date, parentsView bool) ([]models.DailyCareLog, error) {
+	if from.After(to.Time) {
+		logger.WithFields(logrus.Fields{"from": from, "to": to}).Warn("Invalid range for daily care")
+		return nil, ErrInvalidInput
+	}
+	if _, err := service.getChild(logger, ctx, childID); err != nil {
+		return nil, err
+	}
+	logs, err := service.dailyCareStore.GetForChild(ctx, childID, from, to)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching daily care of child")
+		return nil, ErrInternal
+	}
+	if parentsView {
+		logs = slices.DeleteFunc(logs, func(log models.DailyCareLog) bool {
+			return log.HiddenFromParents
+		})
+	}
+	return logs, nil
+}
+
+// DeleteDailyCare deletes the log of a child on a day.
+func (service *DailyCareServiceImpl) DeleteDailyCare(logger *logrus.Entry, ctx context.Context, childID int, day models.Date) error {
+	if err := service.dailyCareStore.Delete(ctx, childID, day); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error deleting daily care")
+		return ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"child_id": childID, "care_date": day}).Info("Daily care deleted successfully")
+	return nil
+}
+
+// checkLog validates a log of a child. Care is only logged for U3 children and not for future days.
+func (service *DailyCareServiceImpl) checkLog(logger *logrus.Entry, child *models.Child, log *models.DailyCareLog) error {
+	if err := models.ValidateDailyCareLog(*log); err != nil {
+		logger.WithError(err).Warn("Invalid input for daily care")
+		return invalidInput(err)
+	}
+	if log.CareDate.IsAfterToday(service.clock.Now()) {
+		logger.WithField("care_date", log.CareDate).Warn("Daily care for a future day")
+		return ErrCareDateInFuture
+	}
+	if child.ArchivedAt != nil {
+		logger.WithField("child_id", child.ID).Warn("Daily care for an archived child")
+		return ErrInvalidInput
+	}
+	if models.AgeInMonths(child.Birthdate, log.CareDate) >= models.U3AgeMonths {
+		logger.WithField("child_id", child.ID).Warn("Daily care for a child older than three years")
+		return ErrChildNotU3
+	}
+	return nil
+}
+
+func (service *DailyCareServiceImpl) getChild(logger *logrus.Entry, ctx context.Context, childID int) (*models.Child, error) {
+	child, err := service.childStore.GetByID(ctx, childID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("child_id", childID).Warn("Child not found for daily care")
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for daily care")
+		return nil, ErrInternal
+	}
+	return child, nil
+}
+
+func (service *DailyCareServiceImpl) getGroup(logger *logrus.Entry, ctx context.Context, groupID int) (*models.Group, error) {
+	group, err := service.groupStore.GetByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("group_id", groupID).Error("Error fetching group for daily care")
+		return nil, ErrInternal
+	}
+	return group, nil
+}
@@ -0,0 +1,163 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyCareService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 5, 15, 0, 0, 0, time.UTC)
+	today := models.Today(now)
+	user := &models.User{ID: 2, Role: string(data.RoleTeacher)}
+	portion := func(p string) *string { return &p }
+	minutes := func(m int) *int { return &m }
+
+	emma := &models.Child{ID: 1, FirstName: "Emma", LastName: "Müller", Birthdate: models.NewDate(2023, time.June, 1)}
+	ben := &models.Child{ID: 2, FirstName: "Ben", LastName: "Schulz", Birthdate: models.NewDate(2023, time.January, 10)}
+	lukas := &models.Child{ID: 3, FirstName: "Lukas", LastName: "Weber", Birthdate: models.NewDate(2021, time.April, 2)}
+	group := &models.Group{ID: 4, Name: "Krippe", ChildIDs: []int{1, 2, 3}}
+
+	setup := func() (*services.DailyCareServiceImpl, *datamocks.MockDailyCareStore) {
+		dailyCareStore := new(datamocks.MockDailyCareStore)
+		childStore := new(datamocks.MockChildStore)
+		groupStore := new(datamocks.MockGroupStore)
+		for _, child := range []*models.Child{emma, ben, lukas} {
+			childStore.On("GetByID", mock.Anything, child.ID).Return(child, nil).Maybe()
+		}
+		childStore.On("GetByID", mock.Anything, 99).Return(nil, data.ErrNotFound).Maybe()
+		groupStore.On("GetByID", mock.Anything, group.ID).Return(group, nil).Maybe()
+		groupStore.On("GetByID", mock.Anything, 98).Return(nil, data.ErrNotFound).Maybe()
+		return services.NewDailyCareService(dailyCareStore, childStore, groupStore, clock.NewFrozen(now)), dailyCareStore
+	}
+
+	t.Run("record for a child", func(t *testing.T) {
+		service, store := setup()
+		recorded := &models.DailyCareLog{ID: 1, ChildID: emma.ID, CareDate: today, Lunch: portion(models.MealPortionMost), RecordedByUserID: &user.ID}
+		store.On("Upsert", mock.Anything, mock.MatchedBy(func(logs []models.DailyCareLog) bool {
+			return len(logs) == 1 && logs[0].ChildID == emma.ID && *logs[0].RecordedByUserID == user.ID
+		})).Return(nil).Once()
+		store.On("Get", mock.Anything, emma.ID, today).Return(recorded, nil).Once()
+
+		log, err := service.RecordDailyCare(logger, ctx, &models.DailyCareLog{ChildID: emma.ID, CareDate: today, Lunch: portion(models.MealPortionMost)}, user)
+		require.NoError(t, err)
+		assert.Equal(t, recorded, log)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("record rejects invalid logs", func(t *testing.T) {
+		service, store := setup()
+		tests := []struct {
+			name string
+			log  models.DailyCareLog
+			want error
+		}{
+			{"unknown portion", models.DailyCareLog{ChildID: emma.ID, CareDate: today, Lunch: portion("plenty")}, services.ErrInvalidInput},
+			{"negative nap", models.DailyCareLog{ChildID: emma.ID, CareDate: today, NapMinutes: minutes(-5)}, services.ErrInvalidInput},
+			{"future day", models.DailyCareLog{ChildID: emma.ID, CareDate: today.AddDays(1)}, services.ErrCareDateInFuture},
+			{"child older than three", models.DailyCareLog{ChildID: lukas.ID, CareDate: today}, services.ErrChildNotU3},
+			{"unknown child", models.DailyCareLog{ChildID: 99, CareDate: today}, services.ErrChildNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				log := tt.log
+				_, err := service.RecordDailyCare(logger, ctx, &log, user)
+				assert.ErrorIs(t, err, tt.want)
+			})
+		}
+		store.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("record for a group", func(t *testing.T) {
+		service, store := setup()
+		store.On("Upsert", mock.Anything, mock.MatchedBy(func(logs []models.DailyCareLog) bool {
+			return len(logs) == 2 && logs[0].CareDate == today && logs[1].CareDate == today
+		})).Return(nil).Once()
+		benLog := models.DailyCareLog{ID: 5, ChildID: ben.ID, CareDate: today, NapMinutes: minutes(90)}
+		store.On("GetForDay", mock.Anything, today).Return([]models.DailyCareLog{benLog}, nil).Once()
+
+		care, err := service.RecordGroupDailyCare(logger, ctx, group.ID, today, []models.DailyCareLog{
+			{ChildID: emma.ID, Breakfast: portion(models.MealPortionAll)},
+			{ChildID: ben.ID, NapMinutes: minutes(90)},
+		}, user)
+		require.NoError(t, err)
+		require.Len(t, care, 2, "Lukas is not U3 and is left out")
+		assert.Equal(t, "Ben", care[0].FirstName)
+		assert.Equal(t, &benLog, care[0].Log)
+		assert.Equal(t, "Emma", care[1].FirstName)
+		assert.Nil(t, care[1].Log)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("record for a group saves nothing on an invalid log", func(t *testing.T) {
+		service, store := setup()
+		tests := []struct {
+			name string
+			logs []models.DailyCareLog
+			want error
+		}{
+			{"child not in group", []models.DailyCareLog{{ChildID: emma.ID}, {ChildID: 99}}, services.ErrChildNotInGroup},
+			{"child listed twice", []models.DailyCareLog{{ChildID: emma.ID}, {ChildID: emma.ID}}, services.ErrInvalidInput},
+			{"child older than three", []models.DailyCareLog{{ChildID: emma.ID}, {ChildID: lukas.ID}}, services.ErrChildNotU3},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := service.RecordGroupDailyCare(logger, ctx, group.ID, today, tt.logs, user)
+				assert.ErrorIs(t, err, tt.want)
+			})
+		}
+		_, err := service.RecordGroupDailyCare(logger, ctx, 98, today, nil, user)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		store.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("parents view leaves out hidden logs", func(t *testing.T) {
+		service, store := setup()
+		from := today.AddDays(-6)
+		logs := func() []models.DailyCareLog {
+			return []models.DailyCareLog{
+				{ID: 1, ChildID: emma.ID, CareDate: today.AddDays(-1)},
+				{ID: 2, ChildID: emma.ID, CareDate: today, HiddenFromParents: true},
+			}
+		}
+		store.On("GetForChild", mock.Anything, emma.ID, from, today).Return(logs(), nil).Once()
+		store.On("GetForChild", mock.Anything, emma.ID, from, today).Return(logs(), nil).Once()
+
+		all, err := service.GetChildDailyCare(logger, ctx, emma.ID, from, today, false)
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+
+		visible, err := service.GetChildDailyCare(logger, ctx, emma.ID, from, today, true)
+		require.NoError(t, err)
+		require.Len(t, visible, 1)
+		assert.Equal(t, 1, visible[0].ID)
+
+		_, err = service.GetChildDailyCare(logger, ctx, emma.ID, today, from, false)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		service, store := setup()
+		store.On("Delete", mock.Anything, emma.ID, today).Return(nil).Once()
+		store.On("Delete", mock.Anything, ben.ID, today).Return(data.ErrNotFound).Once()
+		store.On("Delete", mock.Anything, lukas.ID, today).Return(errors.New("db error")).Once()
+
+		assert.NoError(t, service.DeleteDailyCare(logger, ctx, emma.ID, today))
+		assert.ErrorIs(t, service.DeleteDailyCare(logger, ctx, ben.ID, today), services.ErrNotFound)
+		assert.ErrorIs(t, service.DeleteDailyCare(logger, ctx, lukas.ID, today), services.ErrInternal)
+	})
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DemoModeService defines the interface for the per-session demo mode.
+// Sessions in demo mode are served from an anonymized copy of the data, the production tables stay untouched.
+type DemoModeService interface {
+	Enable(logger *logrus.Entry, ctx context.Context, sessionID string, refresh bool) (*models.DemoModeStatus, error)
+	Disable(logger *logrus.Entry, ctx context.Context, sessionID string) *models.DemoModeStatus
+	GetStatus(sessionID string) *models.DemoModeStatus
+	IsEnabled(sessionID string) bool
+	Dataset() *data.DAL
+}
+
+// DemoModeServiceImpl implements DemoModeService.
+type DemoModeServiceImpl struct {
+	snapshotStore data.DemoSnapshotStore
+	// sessionLifetime matches the lifetime of the login sessions; demo sessions expire with them.
+	sessionLifetime time.Duration
+	clock           clock.Clock
+	mu              sync.RWMutex
+	snapshot        *data.DemoSnapshot
+	sessions        map[string]time.Time // Session ID to expiry
+}
+
+// NewDemoModeService creates a new DemoModeServiceImpl.
+func NewDemoModeService(snapshotStore data.DemoSnapshotStore, sessionLifetime time.Duration, clock clock.Clock) *DemoModeServiceImpl {
+	return &DemoModeServiceImpl{
+		snapshotStore:   snapshotStore,
+		sessionLifetime: sessionLifetime,
+		clock:           clock,
+		sessions:        make(map[string]time.Time),
+	}
+}
+
+// Enable switches a session to the demo dataset. The anonymized snapshot is created on first use
+// and shared by all demo sessions; refresh replaces it with a new copy of the current data.
+func (service *DemoModeServiceImpl) Enable(logger *logrus.Entry, ctx context.Context, sessionID string, refresh bool) (*models.DemoModeStatus, error) {
+	service.mu.RLock()
+	needsSnapshot := service.snapshot == nil || refresh
+	service.mu.RUnlock()
+
+	if needsSnapshot {
+		// The snapshot is built without holding the lock, every request checks IsEnabled in the meantime.
+		snapshot, err := service.snapshotStore.Create(ctx, service.clock.Now())
+		if err != nil {
+			if errors.Is(err, data.ErrUnsupportedDriver) {
+				logger.WithError(err).Warn("Demo mode is not available on this database")
+				return nil, ErrDemoModeUnsupported
+			}
+			logger.WithError(err).Error("Error creating demo snapshot")
+			return nil, ErrInternal
+		}
+		if err := AnonymizeDataset(logger, ctx, snapshot.DAL, snapshot.DAL, AnonymizationOptions{Now: service.clock.Now()}); err != nil {
+			snapshot.Close() //nolint:errcheck
+			return nil, err
+		}
+
+		service.mu.Lock()
+		previous := service.snapshot
+		service.snapshot = snapshot
+		service.mu.Unlock()
+		if previous != nil {
+			if err := previous.Close(); err != nil {
+				logger.WithError(err).Warn("Error closing previous demo snapshot")
+			}
+		}
+		logger.Info("Anonymized demo snapshot created")
+	}
+
+	service.mu.Lock()
+	service.sessions[sessionID] = service.clock.Now().Add(service.sessionLifetime)
+	status := service.statusLocked(sessionID)
+	service.mu.Unlock()
+	logger.Info("Demo mode enabled for session")
+	return status, nil
+}
+
+// Disable switches a session back to the production data.
+// The snapshot is discarded once no session uses it anymore.
+func (service *DemoModeServiceImpl) Disable(logger *logrus.Entry, ctx context.Context, sessionID string) *models.DemoModeStatus {
+	service.mu.Lock()
+	delete(service.sessions, sessionID)
+	service.pruneLocked()
+	var unused *data.DemoSnapshot
+	if len(service.sessions) == 0 {
+		unused = service.snapshot
+		service.snapshot = nil
+	}
+	status := service.statusLocked(sessionID)
+	service.mu.Unlock()
+
+	if unused != nil {
+		if err := unused.Close(); err != nil {
+			logger.WithError(err).Warn("Error closing demo snapshot")
+		}
+	}
+	logger.Info("Demo mode disabled for session")
+	return status
+}
+
+// GetStatus returns the demo mode status of a session.
+func (service *DemoModeServiceImpl) GetStatus(sessionID string) *models.DemoModeStatus {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+	return service.statusLocked(sessionID)
+}
+
+// IsEnabled reports whether a session is in demo mode.
+func (service *DemoModeServiceImpl) IsEnabled(sessionID string) bool {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+	return service.isEnabledLocked(sessionID)
+}
+
+// Dataset returns the data access layer of the current demo snapshot, or nil if there is none.
+func (service *DemoModeServiceImpl) Dataset() *data.DAL {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+	if service.snapshot == nil {
+		return nil
+	}
+	return service.snapshot.DAL
+}
+
+func (service *DemoModeServiceImpl) isEnabledLocked(sessionID string) bool {
+	expiry, ok := service.sessions[sessionID]
+	return ok && service.snapshot != nil && service.clock.Now().Before(expiry)
+}
+
+func (service *DemoModeServiceImpl) statusLocked(sessionID string) *models.DemoModeStatus {
+	status := &models.DemoModeStatus{Enabled: service.isEnabledLocked(sessionID)}
+	if status.Enabled {
+		createdAt := service.snapshot.CreatedAt
+		status.SnapshotCreatedAt = &createdAt
+	}
+	return status
+}
+
+func (service *DemoModeServiceImpl) pruneLocked() {
+	now := service.clock.Now()
+	for sessionID, expiry := range service.sessions {
+		if !now.Before(expiry) {
+			delete(service.sessions, sessionID)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type demoSnapshotMocks struct {
+	children       *datamocks.MockChildStore
+	teachers       *datamocks.MockTeacherStore
+	entries        *datamocks.MockDocumentationEntryStore
+	kitaMasterdata *datamocks.MockKitaMasterdataStore
+}
+
+func newDemoSnapshot() (*data.DemoSnapshot, demoSnapshotMocks) {
+	mocks := demoSnapshotMocks{
+		children:       new(datamocks.MockChildStore),
+		teachers:       new(datamocks.MockTeacherStore),
+		entries:        new(datamocks.MockDocumentationEntryStore),
+		kitaMasterdata: new(datamocks.MockKitaMasterdataStore),
+	}
+	snapshot := &data.DemoSnapshot{
+		DAL: &data.DAL{
+			Children:             mocks.children,
+			Teachers:             mocks.teachers,
+			DocumentationEntries: mocks.entries,
+			KitaMasterdata:       mocks.kitaMasterdata,
+		},
+		CreatedAt: time.Now(),
+	}
+	return snapshot, mocks
+}
+
+func TestDemoModeEnable(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 5, 9, 0, 0, 0, time.UTC)
+
+	t.Run("anonymizes the snapshot", func(t *testing.T) {
+		snapshot, mocks := newDemoSnapshot()
+		mockSnapshotStore := new(datamocks.MockDemoSnapshotStore)
+		mockSnapshotStore.On("Create", mock.Anything, now).Return(snapshot, nil).Once()
+		service := services.NewDemoModeService(mockSnapshotStore, 24*time.Hour, clock.NewFrozen(now))
+
+		mocks.children.On("GetAll", mock.Anything).Return([]models.Child{
+			{ID: 1, FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2020, 5, 17)},
+			{ID: 2, FirstName: "Emma", LastName: "Schulz", Birthdate: models.NewDate(2021, 2, 3)},
+		}, nil).Once()
+		mocks.teachers.On("GetAll", mock.Anything).Return([]models.Teacher{{ID: 1, FirstName: "Petra", LastName: "Schmidt", Username: "pschmidt"}}, nil).Once()
+		var anonymizedChildren []models.Child
+		mocks.children.On("Update", mock.Anything, mock.AnythingOfType("*models.Child")).Run(func(args mock.Arguments) {
+			anonymizedChildren = append(anonymizedChildren, *args.Get(1).(*models.Child))
+		}).Return(nil).Twice()
+		mocks.teachers.On("Update", mock.Anything, mock.MatchedBy(func(teacher *models.Teacher) bool {
+			return teacher.FirstName == "Fachkraft" && teacher.LastName == "Sommer" && teacher.Username == "fachkraft1"
+		})).Return(nil).Once()
+		mocks.entries.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{
+			{ID: 10, ChildID: 1, ObservationDescription: "Anna spielt mit Emma und Frau Schmidt. Annabell schaut zu.", StructuredData: map[string]any{"partner": "Emma", "count": 2.0}},
+		}, nil).Once()
+		mocks.entries.On("GetAllForChild", mock.Anything, 2).Return([]models.DocumentationEntry{}, nil).Once()
+		mocks.entries.On("Update", mock.Anything, mock.MatchedBy(func(entry *models.DocumentationEntry) bool {
+			return entry.ObservationDescription == "Emma spielt mit Noah und Frau Sommer. Annabell schaut zu." &&
+				entry.StructuredData["partner"] == "Noah"
+		})).Return(nil).Once()
+		mocks.kitaMasterdata.On("Update", mock.Anything, mock.AnythingOfType("*models.KitaMasterdata")).Return(nil).Once()
+
+		status, err := service.Enable(logger, ctx, "session-1", false)
+		assert.NoError(t, err)
+		assert.True(t, status.Enabled)
+		assert.True(t, service.IsEnabled("session-1"))
+		assert.False(t, service.IsEnabled("session-2"))
+		assert.Same(t, snapshot.DAL, service.Dataset())
+
+		if assert.Len(t, anonymizedChildren, 2) {
+			assert.Equal(t, "Emma", anonymizedChildren[0].FirstName)
+			assert.Equal(t, "Beispiel", anonymizedChildren[0].LastName)
+			assert.Equal(t, models.NewDate(2020, 5, 1), anonymizedChildren[0].Birthdate)
+			assert.Equal(t, "Noah", anonymizedChildren[1].FirstName)
+		}
+		mockSnapshotStore.AssertExpectations(t)
+		mocks.children.AssertExpectations(t)
+		mocks.teachers.AssertExpectations(t)
+		mocks.entries.AssertExpectations(t)
+		mocks.kitaMasterdata.AssertExpectations(t)
+	})
+
+	t.Run("sessions share the snapshot until the last one disables", func(t *testing.T) {
+		snapshot, mocks := newDemoSnapshot()
+		mocks.children.On("GetAll", mock.Anything).Return([]models.Child{}, nil)
+		mocks.teachers.On("GetAll", mock.Anything).Return([]models.Teacher{}, nil)
+		mocks.kitaMasterdata.On("Update", mock.Anything, mock.Anything).Return(data.ErrNotFound)
+		mockSnapshotStore := new(datamocks.MockDemoSnapshotStore)
+		mockSnapshotStore.On("Create", mock.Anything, now).Return(snapshot, nil).Once()
+		service := services.NewDemoModeService(mockSnapshotStore, 24*time.Hour, clock.NewFrozen(now))
+
+		_, err := service.Enable(logger, ctx, "session-1", false)
+		assert.NoError(t, err)
+		_, err = service.Enable(logger, ctx, "session-2", false)
+		assert.NoError(t, err)
+		mockSnapshotStore.AssertNumberOfCalls(t, "Create", 1)
+
+		status := service.Disable(logger, ctx, "session-1")
+		assert.False(t, status.Enabled)
+		assert.NotNil(t, service.Dataset())
+		assert.True(t, service.GetStatus("session-2").Enabled)
+
+		service.Disable(logger, ctx, "session-2")
+		assert.Nil(t, service.Dataset())
+		assert.False(t, service.IsEnabled("session-2"))
+	})
+
+	t.Run("sessions expire on the clock", func(t *testing.T) {
+		snapshot, mocks := newDemoSnapshot()
+		mocks.children.On("GetAll", mock.Anything).Return([]models.Child{}, nil)
+		mocks.teachers.On("GetAll", mock.Anything).Return([]models.Teacher{}, nil)
+		mocks.kitaMasterdata.On("Update", mock.Anything, mock.Anything).Return(nil)
+		mockSnapshotStore := new(datamocks.MockDemoSnapshotStore)
+		mockSnapshotStore.On("Create", mock.Anything, now).Return(snapshot, nil).Once()
+		frozen := clock.NewFrozen(now)
+		service := services.NewDemoModeService(mockSnapshotStore, 24*time.Hour, frozen)
+
+		_, err := service.Enable(logger, ctx, "session-1", false)
+		assert.NoError(t, err)
+		frozen.Advance(23 * time.Hour)
+		assert.True(t, service.IsEnabled("session-1"))
+		frozen.Advance(time.Hour)
+		assert.False(t, service.IsEnabled("session-1"))
+	})
+
+	t.Run("refresh replaces the snapshot", func(t *testing.T) {
+		first, firstMocks := newDemoSnapshot()
+		second, secondMocks := newDemoSnapshot()
+		for _, mocks := range []demoSnapshotMocks{firstMocks, secondMocks} {
+			mocks.children.On("GetAll", mock.Anything).Return([]models.Child{}, nil)
+			mocks.teachers.On("GetAll", mock.Anything).Return([]models.Teacher{}, nil)
+			mocks.kitaMasterdata.On("Update", mock.Anything, mock.Anything).Return(nil)
+		}
+		mockSnapshotStore := new(datamocks.MockDemoSnapshotStore)
+		mockSnapshotStore.On("Create", mock.Anything, now).Return(first, nil).Once()
+		mockSnapshotStore.On("Create", mock.Anything, now).Return(second, nil).Once()
+		service := services.NewDemoModeService(mockSnapshotStore, 24*time.Hour, clock.NewFrozen(now))
+
+		_, err := service.Enable(logger, ctx, "session-1", false)
+		assert.NoError(t, err)
+		_, err = service.Enable(logger, ctx, "session-1", true)
+		assert.NoError(t, err)
+		assert.Same(t, second.DAL, service.Dataset())
+	})
+
+	t.Run("snapshot error", func(t *testing.T) {
+		mockSnapshotStore := new(datamocks.MockDemoSnapshotStore)
+		mockSnapshotStore.On("Create", mock.Anything, now).Return(nil, errors.New("disk full")).Once()
+		service := services.NewDemoModeService(mockSnapshotStore, 24*time.Hour, clock.NewFrozen(now))
+
+		_, err := service.Enable(logger, ctx, "session-1", false)
+		assert.ErrorIs(t, err, services.ErrInternal)
+		assert.False(t, service.IsEnabled("session-1"))
+	})
+
+	t.Run("database without snapshots", func(t *testing.T) {
+		mockSnapshotStore := new(datamocks.MockDemoSnapshotStore)
+		mockSnapshotStore.On("Create", mock.Anything, now).Return(nil, fmt.Errorf("demo snapshots require the sqlite driver: %w", data.ErrUnsupportedDriver)).Once()
+		service := services.NewDemoModeService(mockSnapshotStore, 24*time.Hour, clock.NewFrozen(now))
+
+		_, err := service.Enable(logger, ctx, "session-1", false)
+		assert.ErrorIs(t, err, services.ErrDemoModeUnsupported)
+		assert.False(t, service.IsEnabled("session-1"))
+	})
+}
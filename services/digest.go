@@ -0,0 +1,348 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// digestSendHour is the hour on Monday, in the time zone of the facility, from which the digest of the past
+	// week is sent.
+	digestSendHour = 7
+	// digestUndocumentedWeeks is the time without an observation after which an assigned child is listed.
+	digestUndocumentedWeeks = 6
+)
+
+// DigestService defines the interface for the weekly documentation digest of teachers.
+type DigestService interface {
+	GetDigest(logger *logrus.Entry, ctx context.Context, user *models.User, day models.Date) (*models.DocumentationDigest, error)
+}
+
+// DigestServiceImpl implements DigestService and emails the digest of the past week to the users who opted in.
+type DigestServiceImpl struct {
+	userStore               data.UserStore
+	teacherStore            data.TeacherStore
+	childStore              data.ChildStore
+	assignmentStore         data.AssignmentStore
+	documentationEntryStore data.DocumentationEntryStore
+	eventStore              data.DocumentationEventStore
+	preferenceStore         data.NotificationPreferenceStore
+	digestStore             data.DigestStore
+	clock                   clock.Clock
+}
+
+// NewDigestService creates a new DigestServiceImpl.
+func NewDigestService(
+	userStore data.UserStore,
+	teacherStore data.TeacherStore,
+	childStore data.ChildStore,
+	assignmentStore data.AssignmentStore,
+	documentationEntryStore data.DocumentationEntryStore,
+	eventStore data.DocumentationEventStore,
+	preferenceStore data.NotificationPreferenceStore,
+	digestStore data.DigestStore,
+	clock clock.Clock,
+) *DigestServiceImpl {
+	return &DigestServiceImpl{
+		userStore:               userStore,
+		teacherStore:            teacherStore,
+		childStore:              childStore,
+		assignmentStore:         assignmentStore,
+		documentationEntryStore: documentationEntryStore,
+		eventStore:              eventStore,
+		preferenceStore:         preferenceStore,
+		digestStore:             digestStore,
+		clock:                   clock,
+	}
+}
+
+// GetDigest builds the digest of the week of the given day for the teacher with the username of the user.
+// A week in progress is summarized up to now.
+func (service *DigestServiceImpl) GetDigest(logger *logrus.Entry, ctx context.Context, user *models.User, day models.Date) (*models.DocumentationDigest, error) {
+	teacher, err := findTeacherOfUser(ctx, service.teacherStore, user)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", user.ID).Error("Error fetching the teacher of the user for the digest")
+		return nil, ErrInternal
+	}
+	if teacher == nil {
+		return nil, ErrTeacherNotFound
+	}
+	digest, err := service.buildDigest(ctx, teacher.ID, weekStartOf(day))
+	if err != nil {
+		logger.WithError(err).WithField("teacher_id", teacher.ID).Error("Error building documentation digest")
+		return nil, ErrInternal
+	}
+	return digest, nil
+}
+
+// SendDue enqueues the digest of the past week for every user who opted in and did not get it yet, and returns
+// how many were enqueued. Failures for one user are logged and do not stop the others.
+func (service *DigestServiceImpl) SendDue(logger *logrus.Entry, ctx context.Context, now time.Time) int {
+	weekStart := dueDigestWeek(now)
+	sent, err := service.digestStore.GetSent(ctx, weekStart)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching sent documentation digests")
+		return 0
+	}
+	users, err := service.userStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching users for documentation digests")
+		return 0
+	}
+	teachers, err := service.teacherStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching teachers for documentation digests")
+		return 0
+	}
+	teachersByUsername := make(map[string]models.Teacher, len(teachers))
+	for _, teacher := range teachers {
+		if teacher.DeactivatedAt == nil {
+			teachersByUsername[teacher.Username] = teacher
+		}
+	}
+
+	enqueued := 0
+	for _, user := range users {
+		if ctx.Err() != nil {
+			break
+		}
+		teacher, ok := teachersByUsername[user.Username]
+		if !ok || sent[user.ID] {
+			continue
+		}
+		userLogger := logger.WithFields(logrus.Fields{"user_id": user.ID, "teacher_id": teacher.ID})
+		preferences, err := service.preferenceStore.Get(ctx, user.ID)
+		if err != nil {
+			// Users that never changed their preferences have no address to send the digest to.
+			if !errors.Is(err, data.ErrNotFound) {
+				userLogger.WithError(err).Error("Error fetching notification preferences for documentation digest")
+			}
+			continue
+		}
+		if !preferences.WantsDigest() {
+			continue
+		}
+
+		digest, err := service.buildDigest(ctx, teacher.ID, weekStart)
+		if err != nil {
+			userLogger.WithError(err).Error("Error building documentation digest")
+			continue
+		}
+		message, err := digestMessage(*preferences.DigestEmail, teacher, digest, now)
+		if err != nil {
+			userLogger.WithError(err).Error("Error encoding documentation digest")
+			continue
+		}
+		recorded, err := service.digestStore.RecordSent(ctx, user.ID, weekStart, message)
+		if err != nil {
+			userLogger.WithError(err).Error("Error enqueueing documentation digest")
+			continue
+		}
+		if recorded {
+			enqueued++
+		}
+	}
+	if enqueued > 0 {
+		logger.WithFields(logrus.Fields{"week_start": weekStart.String(), "digests": enqueued}).Info("Documentation digests enqueued")
+	}
+	return enqueued
+}
+
+// Run enqueues due digests every interval until the context is cancelled. The outbox dispatcher sends them.
+func (service *DigestServiceImpl) Run(logger *logrus.Entry, ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		service.SendDue(logger, ctx, service.clock.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// buildDigest summarizes the week starting on weekStart for a teacher. Entries count as written and approved
+// in the week of the facility they were created and approved in.
+func (service *DigestServiceImpl) buildDigest(ctx context.Context, teacherID int, weekStart models.Date) (*models.DocumentationDigest, error) {
+	from := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, models.FacilityLocation())
+	to := from.AddDate(0, 0, 7)
+	inWeek := func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+	digest := &models.DocumentationDigest{
+		TeacherID:            teacherID,
+		WeekStart:            weekStart,
+		WeekEnd:              weekStart.AddDays(6),
+		RejectedEntryIDs:     []int{},
+		UndocumentedChildren: []models.UndocumentedChild{},
+	}
+
+	// Creating and approving an entry both update it, so entries not updated since the start of the week are
+	// neither written nor approved in it.
+	updated, err := service.documentationEntryStore.List(ctx, models.ListQuery{}.
+		Where("teacher_id", models.OperatorEqual, teacherID).
+		Where("updated_at", models.OperatorGreaterOrEqual, from.UTC()))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range updated {
+		if inWeek(entry.CreatedAt) {
+			digest.EntriesWritten++
+		}
+		if !entry.IsApproved {
+			continue
+		}
+		history, err := service.entryHistory(ctx, entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		if history.ApprovedAt != nil && inWeek(*history.ApprovedAt) {
+			digest.ApprovalsReceived++
+		}
+	}
+
+	unapproved, err := service.documentationEntryStore.List(ctx, models.ListQuery{}.
+		Where("teacher_id", models.OperatorEqual, teacherID).
+		Where("is_approved", models.OperatorEqual, false).
+		OrderBy("id", false))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range unapproved {
+		history, err := service.entryHistory(ctx, entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		if history.Status == models.DocumentationStatusRejected {
+			digest.RejectedEntryIDs = append(digest.RejectedEntryIDs, entry.ID)
+		}
+	}
+
+	undocumented, err := service.undocumentedChildren(ctx, teacherID, digest.WeekEnd.AddDays(-7*digestUndocumentedWeeks))
+	if err != nil {
+		return nil, err
+	}
+	digest.UndocumentedChildren = undocumented
+	return digest, nil
+}
+
+func (service *DigestServiceImpl) entryHistory(ctx context.Context, entryID int) (*models.DocumentationEntryHistory, error) {
+	events, err := service.eventStore.GetForEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	return replayEntryHistory(entryID, events), nil
+}
+
+// undocumentedChildren fetches the children currently assigned to a teacher whose latest observation is not after
+// the cutoff, the ones never observed first, then the longest undocumented.
+func (service *DigestServiceImpl) undocumentedChildren(ctx context.Context, teacherID int, cutoff models.Date) ([]models.UndocumentedChild, error) {
+	assignments, err := service.assignmentStore.List(ctx, models.ListQuery{}.
+		Where("teacher_id", models.OperatorEqual, teacherID).
+		Where("end_date", models.OperatorIsNull, nil))
+	if err != nil {
+		return nil, err
+	}
+	lastObservationDates, err := service.documentationEntryStore.GetLastObservationDates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	undocumented := []models.UndocumentedChild{}
+	seen := make(map[int]bool)
+	for _, assignment := range assignments {
+		if seen[assignment.ChildID] {
+			continue
+		}
+		seen[assignment.ChildID] = true
+		var lastObservationDate *models.Date
+		if last, ok := lastObservationDates[assignment.ChildID]; ok {
+			if last.After(cutoff.Time) {
+				continue
+			}
+			lastObservationDate = &last
+		}
+		child, err := service.childStore.GetByID(ctx, assignment.ChildID)
+		if err != nil {
+			return nil, err
+		}
+		if child.ArchivedAt != nil {
+			continue
+		}
+		undocumented = append(undocumented, models.UndocumentedChild{
+			ChildID:             child.ID,
+			FirstName:           child.FirstName,
+			LastName:            child.LastName,
+			LastObservationDate: lastObservationDate,
+		})
+	}
+	slices.SortFunc(undocumented, func(a, b models.UndocumentedChild) int {
+		if (a.LastObservationDate == nil) != (b.LastObservationDate == nil) {
+			if a.LastObservationDate == nil {
+				return -1
+			}
+			return 1
+		}
+		if a.LastObservationDate != nil {
+			if c := a.LastObservationDate.Compare(b.LastObservationDate.Time); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a.ChildID, b.ChildID)
+	})
+	return undocumented, nil
+}
+
+// weekStartOf returns the Monday of the week of a day.
+func weekStartOf(day models.Date) models.Date {
+	return day.AddDays(-(int(day.Weekday()) + 6) % 7)
+}
+
+// dueDigestWeek returns the start of the latest week whose digest is due at now, which is the past week from
+// Monday at digestSendHour on.
+func dueDigestWeek(now time.Time) models.Date {
+	shifted := now.Add(-digestSendHour * time.Hour)
+	return weekStartOf(models.Today(shifted)).AddDays(-7)
+}
+
+// digestMessage builds the email outbox message of a digest.
+func digestMessage(to string, teacher models.Teacher, digest *models.DocumentationDigest, now time.Time) (models.OutboxMessage, error) {
+	const dateFormat = "02.01.2006"
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hallo %s,\n\n", teacher.FirstName)
+	fmt.Fprintf(&body, "Ihr Wochenrückblick vom %s bis %s:\n\n", digest.WeekStart.Format(dateFormat), digest.WeekEnd.Format(dateFormat))
+	fmt.Fprintf(&body, "- Geschriebene Einträge: %d\n", digest.EntriesWritten)
+	fmt.Fprintf(&body, "- Erhaltene Freigaben: %d\n", digest.ApprovalsReceived)
+	fmt.Fprintf(&body, "- Zurückgewiesene Einträge zum Überarbeiten: %d\n", len(digest.RejectedEntryIDs))
+	if len(digest.UndocumentedChildren) > 0 {
+		fmt.Fprintf(&body, "\nKinder ohne Beobachtung seit mindestens %d Wochen:\n", digestUndocumentedWeeks)
+		for _, child := range digest.UndocumentedChildren {
+			last := "noch keine Beobachtung"
+			if child.LastObservationDate != nil {
+				last = "letzte Beobachtung am " + child.LastObservationDate.Format(dateFormat)
+			}
+			fmt.Fprintf(&body, "- %s %s (%s)\n", child.FirstName, child.LastName, last)
+		}
+	}
+
+	payload, err := json.Marshal(models.EmailOutboxPayload{
+		To:      to,
+		Subject: fmt.Sprintf("Wochenrückblick Dokumentation %s–%s", digest.WeekStart.Format("02.01."), digest.WeekEnd.Format(dateFormat)),
+		Body:    body.String(),
+	})
+	if err != nil {
+		return models.OutboxMessage{}, err
+	}
+	return models.OutboxMessage{Channel: models.OutboxChannelEmail, Payload: payload, NextAttemptAt: now}, nil
+}
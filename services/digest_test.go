@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type digestMocks struct {
+	users       *datamocks.MockUserStore
+	teachers    *datamocks.MockTeacherStore
+	children    *datamocks.MockChildStore
+	assignments *datamocks.MockAssignmentStore
+	entries     *datamocks.MockDocumentationEntryStore
+	events      *datamocks.MockDocumentationEventStore
+	preferences *datamocks.MockNotificationPreferenceStore
+	digests     *datamocks.MockDigestStore
+}
+
+func newDigestService(t *testing.T) (*services.DigestServiceImpl, *digestMocks) {
+	models.SetFacilityLocation(time.UTC)
+	t.Cleanup(func() { models.SetFacilityLocation(time.Local) })
+
+	mocks := &digestMocks{
+		users:       new(datamocks.MockUserStore),
+		teachers:    new(datamocks.MockTeacherStore),
+		children:    new(datamocks.MockChildStore),
+		assignments: new(datamocks.MockAssignmentStore),
+		entries:     new(datamocks.MockDocumentationEntryStore),
+		events:      new(datamocks.MockDocumentationEventStore),
+		preferences: new(datamocks.MockNotificationPreferenceStore),
+		digests:     new(datamocks.MockDigestStore),
+	}
+	service := services.NewDigestService(mocks.users, mocks.teachers, mocks.children, mocks.assignments,
+		mocks.entries, mocks.events, mocks.preferences, mocks.digests, clock.System{})
+	return service, mocks
+}
+
+// expectDigestWeek sets up a week with an entry written and approved in it, a rejected entry and an assigned
+// child that was never observed.
+func expectDigestWeek(mocks *digestMocks, weekStart time.Time) {
+	written := weekStart.Add(26 * time.Hour)
+	mocks.entries.On("List", mock.Anything, mock.MatchedBy(func(query models.ListQuery) bool { return len(query.Sort) == 0 })).
+		Return([]models.DocumentationEntry{{ID: 1, CreatedAt: written, IsApproved: true}}, nil).Once()
+	mocks.entries.On("List", mock.Anything, mock.MatchedBy(func(query models.ListQuery) bool { return len(query.Sort) > 0 })).
+		Return([]models.DocumentationEntry{{ID: 2}, {ID: 3}}, nil).Once()
+	mocks.events.On("GetForEntry", mock.Anything, 1).Return([]models.DocumentationEvent{
+		{Type: models.DocumentationEventCreated, CreatedAt: written},
+		{Type: models.DocumentationEventApproved, CreatedAt: written.Add(time.Hour)},
+	}, nil).Once()
+	mocks.events.On("GetForEntry", mock.Anything, 2).Return([]models.DocumentationEvent{
+		{Type: models.DocumentationEventCreated, CreatedAt: written},
+		{Type: models.DocumentationEventRejected, CreatedAt: written.Add(time.Hour)},
+	}, nil).Once()
+	mocks.events.On("GetForEntry", mock.Anything, 3).Return([]models.DocumentationEvent{
+		{Type: models.DocumentationEventCreated, CreatedAt: written},
+	}, nil).Once()
+	mocks.assignments.On("List", mock.Anything, mock.Anything).Return([]models.Assignment{{ChildID: 7}, {ChildID: 8}}, nil).Once()
+	mocks.entries.On("GetLastObservationDates", mock.Anything).Return(map[int]models.Date{8: models.DateOf(weekStart)}, nil).Once()
+	mocks.children.On("GetByID", mock.Anything, 7).Return(&models.Child{ID: 7, FirstName: "Lena", LastName: "Schmidt"}, nil).Once()
+}
+
+func TestGetDigest(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	user := &models.User{ID: 1, Username: "mmueller"}
+
+	t.Run("success", func(t *testing.T) {
+		service, mocks := newDigestService(t)
+		mocks.teachers.On("GetAll", mock.Anything).Return([]models.Teacher{{ID: 4, Username: "mmueller"}}, nil).Once()
+		expectDigestWeek(mocks, time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC))
+
+		digest, err := service.GetDigest(logger, ctx, user, models.NewDate(2025, time.March, 13))
+
+		require.NoError(t, err)
+		assert.Equal(t, models.NewDate(2025, time.March, 10), digest.WeekStart)
+		assert.Equal(t, models.NewDate(2025, time.March, 16), digest.WeekEnd)
+		assert.Equal(t, 1, digest.EntriesWritten)
+		assert.Equal(t, 1, digest.ApprovalsReceived)
+		assert.Equal(t, []int{2}, digest.RejectedEntryIDs)
+		require.Len(t, digest.UndocumentedChildren, 1)
+		assert.Equal(t, 7, digest.UndocumentedChildren[0].ChildID)
+		assert.Nil(t, digest.UndocumentedChildren[0].LastObservationDate)
+	})
+
+	t.Run("user without teacher", func(t *testing.T) {
+		service, mocks := newDigestService(t)
+		mocks.teachers.On("GetAll", mock.Anything).Return([]models.Teacher{}, nil).Once()
+
+		_, err := service.GetDigest(logger, ctx, user, models.NewDate(2025, time.March, 13))
+
+		assert.ErrorIs(t, err, services.ErrTeacherNotFound)
+	})
+}
+
+func TestSendDueDigests(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	email := "m.mueller@kita.example"
+	users := []*models.User{{ID: 1, Username: "mmueller"}, {ID: 2, Username: "admin"}, {ID: 3, Username: "jschulz"}}
+	teachers := []models.Teacher{{ID: 4, FirstName: "Maria", Username: "mmueller"}, {ID: 5, Username: "jschulz"}}
+
+	t.Run("enqueues the digest of the past week", func(t *testing.T) {
+		service, mocks := newDigestService(t)
+		weekStart := models.NewDate(2025, time.March, 10)
+		mocks.digests.On("GetSent", mock.Anything, weekStart).Return(map[int]bool{}, nil).Once()
+		mocks.users.On("GetAll", mock.Anything).Return(users, nil).Once()
+		mocks.teachers.On("GetAll", mock.Anything).Return(teachers, nil).Once()
+		mocks.preferences.On("Get", mock.Anything, 1).Return(&models.NotificationPreferences{UserID: 1, NotifyDigest: true, DigestEmail: &email}, nil).Once()
+		mocks.preferences.On("Get", mock.Anything, 3).Return(nil, data.ErrNotFound).Once()
+		expectDigestWeek(mocks, weekStart.Time)
+		var message models.OutboxMessage
+		mocks.digests.On("RecordSent", mock.Anything, 1, weekStart, mock.Anything).Run(func(args mock.Arguments) {
+			message = args.Get(3).(models.OutboxMessage)
+		}).Return(true, nil).Once()
+
+		enqueued := service.SendDue(logger, ctx, time.Date(2025, time.March, 17, 8, 0, 0, 0, time.UTC))
+
+		assert.Equal(t, 1, enqueued)
+		assert.Equal(t, models.OutboxChannelEmail, message.Channel)
+		var payload models.EmailOutboxPayload
+		require.NoError(t, json.Unmarshal(message.Payload, &payload))
+		assert.Equal(t, email, payload.To)
+		assert.Equal(t, "Wochenrückblick Dokumentation 10.03.–16.03.2025", payload.Subject)
+		assert.Contains(t, payload.Body, "Geschriebene Einträge: 1")
+		assert.Contains(t, payload.Body, "Lena Schmidt (noch keine Beobachtung)")
+		mocks.digests.AssertExpectations(t)
+	})
+
+	t.Run("skips sent digests and opted out users", func(t *testing.T) {
+		service, mocks := newDigestService(t)
+		weekStart := models.NewDate(2025, time.March, 10)
+		mocks.digests.On("GetSent", mock.Anything, weekStart).Return(map[int]bool{1: true}, nil).Once()
+		mocks.users.On("GetAll", mock.Anything).Return(users, nil).Once()
+		mocks.teachers.On("GetAll", mock.Anything).Return(teachers, nil).Once()
+		mocks.preferences.On("Get", mock.Anything, 3).Return(&models.NotificationPreferences{UserID: 3, NotifyDigest: false, DigestEmail: &email}, nil).Once()
+
+		enqueued := service.SendDue(logger, ctx, time.Date(2025, time.March, 17, 8, 0, 0, 0, time.UTC))
+
+		assert.Equal(t, 0, enqueued)
+		mocks.preferences.AssertNotCalled(t, "Get", mock.Anything, 1)
+		mocks.digests.AssertNotCalled(t, "RecordSent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("before the send hour on Monday the week before is due", func(t *testing.T) {
+		service, mocks := newDigestService(t)
+		mocks.digests.On("GetSent", mock.Anything, models.NewDate(2025, time.March, 3)).Return(map[int]bool{1: true, 3: true}, nil).Once()
+		mocks.users.On("GetAll", mock.Anything).Return(users, nil).Once()
+		mocks.teachers.On("GetAll", mock.Anything).Return(teachers, nil).Once()
+
+		enqueued := service.SendDue(logger, ctx, time.Date(2025, time.March, 17, 6, 30, 0, 0, time.UTC))
+
+		assert.Equal(t, 0, enqueued)
+		mocks.digests.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// DocumentPool limits the documents generated at the same time and the memory they take. The size of a document
+// is estimated and reserved before it is built. Documents wait in line while the pool is full and are rejected
+// with ErrBusy once the line is full, so that a burst of report requests cannot exhaust the memory of the server.
+type DocumentPool struct {
+	mu            sync.Mutex
+	maxConcurrent int
+	memoryLimit   int64
+	maxQueued     int
+	running       int
+	reserved      int64
+	queue         []*documentWaiter
+}
+
+type documentWaiter struct {
+	size  int64
+	ready chan struct{} // Closed once the document is admitted
+}
+
+// NewDocumentPool creates a pool building at most maxConcurrent documents with an estimated size of at most
+// memoryLimit bytes in total, while at most maxQueued further documents wait.
+func NewDocumentPool(maxConcurrent int, memoryLimit int64, maxQueued int) *DocumentPool {
+	return &DocumentPool{maxConcurrent: maxConcurrent, memoryLimit: memoryLimit, maxQueued: maxQueued}
+}
+
+// Acquire reserves the estimated size of a document, waiting in line while the pool is full. A document larger
+// than the memory limit is built alone. It fails with ErrBusy if the line is full and with ErrCanceled if the
+// context ends while waiting. The returned release must be called once the document is built.
+func (pool *DocumentPool) Acquire(ctx context.Context, size int64) (func(), error) {
+	pool.mu.Lock()
+	if len(pool.queue) == 0 && pool.fits(size) {
+		pool.admit(size)
+		pool.mu.Unlock()
+		return pool.releaseFunc(size), nil
+	}
+	if len(pool.queue) >= pool.maxQueued {
+		pool.mu.Unlock()
+		return nil, ErrBusy
+	}
+	waiter := &documentWaiter{size: size, ready: make(chan struct{})}
+	pool.queue = append(pool.queue, waiter)
+	pool.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return pool.releaseFunc(size), nil
+	case <-ctx.Done():
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	select {
+	case <-waiter.ready:
+		// Admitted while giving up, the reservation is handed on to the next in line.
+		pool.running--
+		pool.reserved -= size
+	default:
+		pool.queue = slices.DeleteFunc(pool.queue, func(queued *documentWaiter) bool { return queued == waiter })
+	}
+	pool.admitQueued()
+	return nil, ErrCanceled
+}
+
+// fits reports whether a document of the size can be built right away, the caller holds the lock.
+func (pool *DocumentPool) fits(size int64) bool {
+	if pool.running >= pool.maxConcurrent {
+		return false
+	}
+	return pool.running == 0 || pool.reserved+size <= pool.memoryLimit
+}
+
+func (pool *DocumentPool) admit(size int64) {
+	pool.running++
+	pool.reserved += size
+}
+
+// admitQueued admits the waiting documents in order as long as they fit, the caller holds the lock.
+func (pool *DocumentPool) admitQueued() {
+	for len(pool.queue) > 0 && pool.fits(pool.queue[0].size) {
+		waiter := pool.queue[0]
+		pool.queue = pool.queue[1:]
+		pool.admit(waiter.size)
+		close(waiter.ready)
+	}
+}
+
+func (pool *DocumentPool) releaseFunc(size int64) func() {
+	return sync.OnceFunc(func() {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		pool.running--
+		pool.reserved -= size
+		pool.admitQueued()
+	})
+}
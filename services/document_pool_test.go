@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// acquireAsync acquires in the background, the result is received once the document is admitted or rejected.
+func acquireAsync(pool *services.DocumentPool, ctx context.Context, size int64) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		_, err := pool.Acquire(ctx, size)
+		result <- err
+	}()
+	return result
+}
+
+func assertWaiting(t *testing.T, result <-chan error) {
+	t.Helper()
+	select {
+	case err := <-result:
+		t.Fatalf("expected the document to wait, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestDocumentPool(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("limits concurrent documents", func(t *testing.T) {
+		pool := services.NewDocumentPool(2, 100, 5)
+		releaseFirst, err := pool.Acquire(ctx, 10)
+		require.NoError(t, err)
+		_, err = pool.Acquire(ctx, 10)
+		require.NoError(t, err)
+
+		third := acquireAsync(pool, ctx, 10)
+		assertWaiting(t, third)
+
+		releaseFirst()
+		releaseFirst() // Releasing twice has no effect
+		assert.NoError(t, <-third)
+		fourth := acquireAsync(pool, ctx, 10)
+		assertWaiting(t, fourth)
+	})
+
+	t.Run("limits memory and admits in order", func(t *testing.T) {
+		pool := services.NewDocumentPool(10, 100, 5)
+		release, err := pool.Acquire(ctx, 80)
+		require.NoError(t, err)
+
+		large := acquireAsync(pool, ctx, 50)
+		assertWaiting(t, large)
+		small := acquireAsync(pool, ctx, 10)
+		assertWaiting(t, small) // Fits, but waits behind the large document
+
+		release()
+		assert.NoError(t, <-large)
+		assert.NoError(t, <-small)
+	})
+
+	t.Run("builds a document larger than the limit alone", func(t *testing.T) {
+		pool := services.NewDocumentPool(10, 100, 5)
+		release, err := pool.Acquire(ctx, 500)
+		require.NoError(t, err)
+
+		next := acquireAsync(pool, ctx, 1)
+		assertWaiting(t, next)
+		release()
+		assert.NoError(t, <-next)
+	})
+
+	t.Run("rejects when the line is full", func(t *testing.T) {
+		pool := services.NewDocumentPool(1, 100, 1)
+		_, err := pool.Acquire(ctx, 10)
+		require.NoError(t, err)
+		assertWaiting(t, acquireAsync(pool, ctx, 10))
+
+		_, err = pool.Acquire(ctx, 10)
+		assert.ErrorIs(t, err, services.ErrBusy)
+	})
+
+	t.Run("gives up waiting when canceled", func(t *testing.T) {
+		pool := services.NewDocumentPool(1, 100, 1)
+		release, err := pool.Acquire(ctx, 10)
+		require.NoError(t, err)
+		waitCtx, cancel := context.WithCancel(ctx)
+		waiting := acquireAsync(pool, waitCtx, 10)
+		assertWaiting(t, waiting)
+
+		cancel()
+		assert.ErrorIs(t, <-waiting, services.ErrCanceled)
+		next := acquireAsync(pool, ctx, 10)
+		assertWaiting(t, next) // The canceled document left the line
+		release()
+		assert.NoError(t, <-next)
+	})
+}
@@ -6,27 +6,47 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
 	"time"
 
 	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/docx"
 	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+// maxRejectionReasonLength limits the reason given when rejecting an entry.
+const maxRejectionReasonLength = 1000
+
 // DocumentationEntryService defines the interface for documentation entry-related business logic operations.
 type DocumentationEntryService interface {
 	CreateDocumentationEntry(logger *logrus.Entry, ctx context.Context, entry *models.DocumentationEntry) (*models.DocumentationEntry, error)
 	GetDocumentationEntryByID(logger *logrus.Entry, ctx context.Context, id int) (*models.DocumentationEntry, error)
 	UpdateDocumentationEntry(logger *logrus.Entry, ctx context.Context, entry *models.DocumentationEntry) error
 	DeleteDocumentationEntry(logger *logrus.Entry, ctx context.Context, id int) error
-	GetAllDocumentationForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.DocumentationEntry, error)
-	ApproveDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, approvedByUserID int) error
-	GenerateChildReport(logger *logrus.Entry, ctx context.Context, childID int, assignments []models.Assignment) ([]byte, error) // Returns a byte slice representing the Word document
-	GetDocumentName(ctx context.Context, childID int) (string, error)                                                            // Returns the document name for a child report
+	GetAllDocumentationForChild(logger *logrus.Entry, ctx context.Context, childID int, query models.ListQuery) ([]models.DocumentationEntry, error)
+	CountPendingDocumentation(logger *logrus.Entry, ctx context.Context, query models.ListQuery) (int, error)
+	// WaitForPendingDocumentationChange returns the pending count as soon as it differs from known, at the
+	// latest after the timeout.
+	WaitForPendingDocumentationChange(logger *logrus.Entry, ctx context.Context, query models.ListQuery, known int, timeout time.Duration) (int, error)
+	ApproveDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, approvedByUserID int, actingUserID int, onBehalfOfUserID *int) error
+	SubmitDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, actingUserID int) error
+	RejectDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, actingUserID int, reason string) error
+	GenerateChildReport(logger *logrus.Entry, ctx context.Context, childID int, assignments []models.Assignment, redaction *models.RedactionProfile, completeness *models.ChildCompleteness, supports []models.ChildSupport, photos []models.GalleryPhoto) ([]byte, error) // Returns a byte slice representing the Word document
+	GetDocumentName(ctx context.Context, childID int, redaction *models.RedactionProfile) (string, error)                                                                                                                                                                  // Returns the document name for a child report
+	CreateDocumentationEntryRevision(logger *logrus.Entry, ctx context.Context, entryID int, revision *models.DocumentationEntry) (*models.DocumentationEntry, error)
+	UnlockDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, actingUserID int) error
+	GetGeneratedReportsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.GeneratedReport, error)
+	DownloadGeneratedReport(logger *logrus.Entry, ctx context.Context, childID int, reportID int, actingUserID int) (*models.GeneratedReport, error)
+	// ExportGeneratedReports fetches all archived reports generated at or after since with their content, for a bulk export.
+	ExportGeneratedReports(logger *logrus.Entry, ctx context.Context, since time.Time, actingUserID int) ([]models.GeneratedReport, error)
 }
 
 // DocumentationEntryServiceImpl implements DocumentationEntryService.
@@ -37,7 +57,15 @@ type DocumentationEntryServiceImpl struct {
 	categoryStore           data.CategoryStore
 	userStore               data.UserStore // For ApprovedByUserID validation
 	kitaMasterdataStore     data.KitaMasterdataStore
+	notificationService     NotificationService       // Optional, nil disables push notifications
+	delegationService       ApprovalDelegationService // Optional, nil disables approvals on behalf of another user
+	auditLogService         AuditLogService           // Optional, nil disables the audit trail
+	ruleService             ValidationRuleService
+	eventService            DocumentationEventService // Optional, nil disables the lifecycle event stream
+	documentPool            *DocumentPool             // Optional, nil generates any number of reports at the same time
+	projectStore            data.ProjectStore         // Optional, nil rejects entries referring to a project
 	validate                *validator.Validate
+	clock                   clock.Clock
 }
 
 // NewDocumentationEntryService creates a new DocumentationEntryServiceImpl.
@@ -48,8 +76,19 @@ func NewDocumentationEntryService(
 	categoryStore data.CategoryStore,
 	userStore data.UserStore,
 	kitaMasterdataStore data.KitaMasterdataStore,
+	notificationService NotificationService,
+	delegationService ApprovalDelegationService,
+	auditLogService AuditLogService,
+	ruleService ValidationRuleService,
+	eventService DocumentationEventService,
+	documentPool *DocumentPool,
+	projectStore data.ProjectStore,
+	clock clock.Clock,
 ) *DocumentationEntryServiceImpl {
-	validate := validator.New()
+	if ruleService == nil {
+		ruleService = NewValidationRuleService(nil, nil, clock)
+	}
+	validate := models.NewValidator()
 	validate.RegisterValidation("iso8601date", models.ValidateISO8601Date) //nolint:errcheck
 	return &DocumentationEntryServiceImpl{
 		documentationEntryStore: documentationEntryStore,
@@ -58,72 +97,162 @@ func NewDocumentationEntryService(
 		categoryStore:           categoryStore,
 		userStore:               userStore,
 		kitaMasterdataStore:     kitaMasterdataStore,
+		notificationService:     notificationService,
+		delegationService:       delegationService,
+		auditLogService:         auditLogService,
+		ruleService:             ruleService,
+		eventService:            eventService,
+		documentPool:            documentPool,
+		projectStore:            projectStore,
 		validate:                validate,
+		clock:                   clock,
 	}
 }
 
 // CreateDocumentationEntry creates a new documentation entry.
+// Implausible entries, e.g. observed on a closed day, are created with warnings.
 func (service *DocumentationEntryServiceImpl) CreateDocumentationEntry(logger *logrus.Entry, ctx context.Context, entry *models.DocumentationEntry) (*models.DocumentationEntry, error) {
 	if err := service.validate.Struct(entry); err != nil {
 		logger.WithError(err).Error("Invalid input for CreateDocumentationEntry")
-		return nil, ErrInvalidInput
+		return nil, invalidInput(err)
 	}
 
 	// Validate ChildID
-	_, err := service.childStore.GetByID(entry.ChildID)
+	_, err := service.childStore.GetByID(ctx, entry.ChildID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("child_id", entry.ChildID).Warn("Child not found for documentation entry creation")
-			return nil, errors.New("child not found")
+			return nil, ErrChildNotFound
 		}
 		logger.WithError(err).WithField("child_id", entry.ChildID).Error("Error fetching child by ID for documentation entry creation")
 		return nil, ErrInternal
 	}
 
 	// Validate TeacherID
-	_, err = service.teacherStore.GetByID(entry.TeacherID)
+	_, err = service.teacherStore.GetByID(ctx, entry.TeacherID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("teacher_id", entry.TeacherID).Warn("Teacher not found for documentation entry creation")
-			return nil, errors.New("teacher not found")
+			return nil, ErrTeacherNotFound
 		}
 		logger.WithError(err).WithField("teacher_id", entry.TeacherID).Error("Error fetching teacher by ID for documentation entry creation")
 		return nil, ErrInternal
 	}
 
 	// Validate CategoryID
-	_, err = service.categoryStore.GetByID(entry.CategoryID)
+	category, err := service.categoryStore.GetByID(ctx, entry.CategoryID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("category_id", entry.CategoryID).Warn("Category not found for documentation entry creation")
-			return nil, errors.New("category not found")
+			return nil, ErrCategoryNotFound
 		}
 		logger.WithError(err).WithField("category_id", entry.CategoryID).Error("Error fetching category by ID for documentation entry creation")
 		return nil, ErrInternal
 	}
+	if category.IsArchived() {
+		logger.WithField("category_id", entry.CategoryID).Warn("Category is archived for documentation entry creation")
+		return nil, ErrCategoryArchived
+	}
+
+	if err := service.checkProject(logger, ctx, entry); err != nil {
+		return nil, err
+	}
 
-	// Business rule: EntryDate cannot be in the future.
-	if entry.ObservationDate.After(time.Now()) {
-		logger.WithField("observation_date", entry.ObservationDate).Warn("Observation date cannot be in the future")
-		return nil, errors.New("observation date cannot be in the future")
+	// The category's form and the configurable business rules, e.g. the observation date cannot be in the future.
+	if err := service.validateEntry(logger, ctx, category, entry); err != nil {
+		return nil, err
 	}
 
-	entry.CreatedAt = time.Now()
-	entry.UpdatedAt = time.Now()
+	entry.CreatedAt = service.clock.Now()
+	entry.UpdatedAt = service.clock.Now()
 
-	id, err := service.documentationEntryStore.Create(entry)
+	id, err := service.documentationEntryStore.Create(ctx, entry)
 	if err != nil {
 		logger.WithError(err).Error("Error creating documentation entry in store")
 		return nil, ErrInternal
 	}
-	entry.ID = id
-	logger.WithField("entry_id", entry.ID).Info("Documentation entry created successfully")
-	return entry, nil
+	created, err := service.documentationEntryStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("entry_id", id).Error("Error fetching created documentation entry")
+		return nil, ErrInternal
+	}
+	logger.WithField("entry_id", id).Info("Documentation entry created successfully")
+
+	service.recordEvent(logger, ctx, &models.DocumentationEvent{
+		ChildID: created.ChildID,
+		EntryID: created.ID,
+		Type:    models.DocumentationEventCreated,
+		Payload: models.DocumentationEventData{Entry: created},
+	})
+	created.Warnings = service.ruleService.PlausibilityWarnings(logger, ctx, entry)
+	return created, nil
+}
+
+// validateEntry checks the structured data against the form of the entry's category and applies the business rules.
+// Violations of both are reported together in a single ValidationError.
+func (service *DocumentationEntryServiceImpl) validateEntry(logger *logrus.Entry, ctx context.Context, category *models.Category, entry *models.DocumentationEntry) error {
+	var violations []RuleViolation
+	if category.FormSchema == nil {
+		if len(entry.StructuredData) > 0 {
+			violations = append(violations, RuleViolation{
+				Rule:    RuleStructuredData,
+				Field:   "structured_data",
+				Message: fmt.Sprintf("category %s has no form for structured data", category.Name),
+			})
+		}
+	} else {
+		for _, fieldError := range category.FormSchema.Validate(entry.StructuredData) {
+			violations = append(violations, RuleViolation{
+				Rule:    RuleStructuredData,
+				Field:   "structured_data." + fieldError.Field,
+				Message: fieldError.Message,
+			})
+		}
+	}
+
+	err := service.ruleService.ValidateDocumentationEntry(logger, ctx, entry)
+	if len(violations) == 0 {
+		return err
+	}
+	var ruleErr *ValidationError
+	if err != nil && !errors.As(err, &ruleErr) {
+		return err
+	}
+	if ruleErr != nil {
+		violations = append(violations, ruleErr.Violations...)
+	}
+	logger.WithField("violations", len(violations)).Warn("Documentation entry does not match its category form")
+	return &ValidationError{Violations: violations}
+}
+
+// checkProject checks that the project an entry refers to exists and that the entry was observed during the project.
+func (service *DocumentationEntryServiceImpl) checkProject(logger *logrus.Entry, ctx context.Context, entry *models.DocumentationEntry) error {
+	if entry.ProjectID == nil {
+		return nil
+	}
+	if service.projectStore == nil {
+		logger.WithField("project_id", *entry.ProjectID).Warn("Projects are not available for documentation entries")
+		return ErrProjectNotFound
+	}
+	project, err := service.projectStore.GetByID(ctx, *entry.ProjectID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("project_id", *entry.ProjectID).Warn("Project not found for documentation entry")
+			return ErrProjectNotFound
+		}
+		logger.WithError(err).WithField("project_id", *entry.ProjectID).Error("Error fetching project for documentation entry")
+		return ErrInternal
+	}
+	if !project.Covers(entry.ObservationDate) {
+		logger.WithFields(logrus.Fields{"project_id": project.ID, "observation_date": entry.ObservationDate}).Warn("Documentation entry observed outside the project")
+		return ErrEntryOutsideProject
+	}
+	return nil
 }
 
 // GetDocumentationEntryByID fetches a documentation entry by ID.
 func (service *DocumentationEntryServiceImpl) GetDocumentationEntryByID(logger *logrus.Entry, ctx context.Context, id int) (*models.DocumentationEntry, error) {
-	entry, err := service.documentationEntryStore.GetByID(id)
+	entry, err := service.documentationEntryStore.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("entry_id", id).Warn("Documentation entry not found")
@@ -136,96 +265,140 @@ func (service *DocumentationEntryServiceImpl) GetDocumentationEntryByID(logger *
 	return entry, nil
 }
 
-// UpdateDocumentationEntry updates an existing documentation entry.
+// UpdateDocumentationEntry updates an existing documentation entry. Plausibility warnings are set on the entry.
 func (service *DocumentationEntryServiceImpl) UpdateDocumentationEntry(logger *logrus.Entry, ctx context.Context, entry *models.DocumentationEntry) error {
 	if err := service.validate.Struct(entry); err != nil {
 		logger.WithError(err).Warn("Invalid input for UpdateDocumentationEntry")
-		return ErrInvalidInput
+		return invalidInput(err)
 	}
 
 	// Validate ChildID
-	_, err := service.childStore.GetByID(entry.ChildID)
+	_, err := service.childStore.GetByID(ctx, entry.ChildID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("child_id", entry.ChildID).Warn("Child not found for documentation entry update")
-			return errors.New("child not found")
+			return ErrChildNotFound
 		}
 		logger.WithError(err).WithField("child_id", entry.ChildID).Error("Error fetching child by ID for documentation entry update")
 		return ErrInternal
 	}
 
 	// Validate TeacherID
-	_, err = service.teacherStore.GetByID(entry.TeacherID)
+	_, err = service.teacherStore.GetByID(ctx, entry.TeacherID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("teacher_id", entry.TeacherID).Warn("Teacher not found for documentation entry update")
-			return errors.New("teacher not found")
+			return ErrTeacherNotFound
 		}
 		logger.WithError(err).WithField("teacher_id", entry.TeacherID).Error("Error fetching teacher by ID for documentation entry update")
 		return ErrInternal
 	}
 
 	// Validate CategoryID
-	_, err = service.categoryStore.GetByID(entry.CategoryID)
+	category, err := service.categoryStore.GetByID(ctx, entry.CategoryID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("category_id", entry.CategoryID).Warn("Category not found for documentation entry update")
-			return errors.New("category not found")
+			return ErrCategoryNotFound
 		}
 		logger.WithError(err).WithField("category_id", entry.CategoryID).Error("Error fetching category by ID for documentation entry update")
 		return ErrInternal
 	}
 
-	// Business rule: EntryDate cannot be in the future.
-	if entry.ObservationDate.After(time.Now()) {
-		logger.WithField("observation_date", entry.ObservationDate).Warn("Observation date cannot be in the future for update")
-		return errors.New("entry date cannot be in the future")
+	if err := service.checkProject(logger, ctx, entry); err != nil {
+		return err
 	}
 
-	entry.UpdatedAt = time.Now()
-	err = service.documentationEntryStore.Update(entry)
+	// The category's form and the configurable business rules, e.g. the observation date cannot be in the future.
+	if err := service.validateEntry(logger, ctx, category, entry); err != nil {
+		return err
+	}
+
+	entry.UpdatedAt = service.clock.Now()
+	err = service.documentationEntryStore.Update(ctx, entry)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("entry_id", entry.ID).Warn("Documentation entry not found for update")
 			return ErrNotFound
 		}
+		if errors.Is(err, data.ErrLocked) {
+			logger.WithField("entry_id", entry.ID).Warn("Documentation entry is locked by a generated report")
+			return ErrLocked
+		}
 		logger.WithError(err).WithField("entry_id", entry.ID).Error("Error updating documentation entry in store")
 		return ErrInternal
 	}
 	logger.WithField("entry_id", entry.ID).Info("Documentation entry updated successfully")
+
+	service.recordEvent(logger, ctx, &models.DocumentationEvent{
+		ChildID: entry.ChildID,
+		EntryID: entry.ID,
+		Type:    models.DocumentationEventEdited,
+		Payload: models.DocumentationEventData{Entry: entry},
+	})
+	entry.Warnings = service.ruleService.PlausibilityWarnings(logger, ctx, entry)
 	return nil
 }
 
 // DeleteDocumentationEntry deletes a documentation entry by ID.
 func (service *DocumentationEntryServiceImpl) DeleteDocumentationEntry(logger *logrus.Entry, ctx context.Context, id int) error {
-	err := service.documentationEntryStore.Delete(id)
+	// The child of the entry is needed to append the deletion to its stream.
+	var childID int
+	if service.eventService != nil {
+		entry, err := service.documentationEntryStore.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, data.ErrNotFound) {
+				logger.WithField("entry_id", id).Warn("Documentation entry not found for deletion")
+				return ErrNotFound
+			}
+			logger.WithError(err).WithField("entry_id", id).Error("Error fetching documentation entry by ID for deletion")
+			return ErrInternal
+		}
+		childID = entry.ChildID
+	}
+
+	err := service.documentationEntryStore.Delete(ctx, id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("entry_id", id).Warn("Documentation entry not found for deletion")
 			return ErrNotFound
 		}
+		if errors.Is(err, data.ErrLocked) {
+			logger.WithField("entry_id", id).Warn("Documentation entry is locked by a generated report")
+			return ErrLocked
+		}
 		logger.WithError(err).WithField("entry_id", id).Error("Error deleting documentation entry from store")
 		return ErrInternal
 	}
 	logger.WithField("entry_id", id).Info("Documentation entry deleted successfully")
+
+	service.recordEvent(logger, ctx, &models.DocumentationEvent{
+		ChildID: childID,
+		EntryID: id,
+		Type:    models.DocumentationEventDeleted,
+	})
 	return nil
 }
 
-// GetAllDocumentationForChild fetches all documentation entries for a specific child.
-func (service *DocumentationEntryServiceImpl) GetAllDocumentationForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.DocumentationEntry, error) {
+// GetAllDocumentationForChild fetches the documentation entries of a specific child matching the query.
+func (service *DocumentationEntryServiceImpl) GetAllDocumentationForChild(logger *logrus.Entry, ctx context.Context, childID int, query models.ListQuery) ([]models.DocumentationEntry, error) {
 	// Validate ChildID
-	_, err := service.childStore.GetByID(childID)
+	_, err := service.childStore.GetByID(ctx, childID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("child_id", childID).Warn("Child not found for fetching documentation entries")
-			return nil, errors.New("child not found")
+			return nil, ErrChildNotFound
 		}
 		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child by ID for documentation entries")
 		return nil, ErrInternal
 	}
 
-	entries, err := service.documentationEntryStore.GetAllForChild(childID)
+	entries, err := service.documentationEntryStore.List(ctx, query.Where("child_id", models.OperatorEqual, childID))
 	if err != nil {
+		if errors.Is(err, data.ErrInvalidInput) {
+			logger.WithError(err).WithField("child_id", childID).Warn("Invalid query for documentation entries of child")
+			return nil, ErrInvalidInput
+		}
 		logger.WithError(err).WithField("child_id", childID).Error("Error fetching documentation entries for child ID")
 		return nil, ErrInternal
 	}
@@ -233,10 +406,53 @@ func (service *DocumentationEntryServiceImpl) GetAllDocumentationForChild(logger
 	return entries, nil
 }
 
+// CountPendingDocumentation counts the documentation entries awaiting approval that match the conditions of the query.
+func (service *DocumentationEntryServiceImpl) CountPendingDocumentation(logger *logrus.Entry, ctx context.Context, query models.ListQuery) (int, error) {
+	count, err := service.documentationEntryStore.Count(ctx, query.Where("is_approved", models.OperatorEqual, false))
+	if err != nil {
+		if errors.Is(err, data.ErrInvalidInput) {
+			logger.WithError(err).Warn("Invalid query for counting pending documentation entries")
+			return 0, ErrInvalidInput
+		}
+		logger.WithError(err).Error("Error counting pending documentation entries")
+		return 0, ErrInternal
+	}
+	return count, nil
+}
+
+// pendingPollInterval is how often a waiting client's pending count is compared to the one it knows. The count
+// is cheap, and polling notices changes made by other instances and by the import commands as well.
+const pendingPollInterval = time.Second
+
+// WaitForPendingDocumentationChange counts the entries awaiting approval that match the query until the count
+// differs from known or the timeout elapses, and returns the latest count. It is the long poll behind the
+// approval badge of clients that cannot hold a stream open.
+func (service *DocumentationEntryServiceImpl) WaitForPendingDocumentationChange(logger *logrus.Entry, ctx context.Context, query models.ListQuery, known int, timeout time.Duration) (int, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(pendingPollInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := service.CountPendingDocumentation(logger, ctx, query)
+		if err != nil || count != known {
+			return count, err
+		}
+		select {
+		case <-ctx.Done():
+			return count, checkCanceled(logger, ctx, "Waiting for pending documentation")
+		case <-deadline.C:
+			return count, nil
+		case <-ticker.C:
+		}
+	}
+}
+
 // ApproveDocumentationEntry approves a documentation entry.
-func (service *DocumentationEntryServiceImpl) ApproveDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, approvedByTeacherID int) error {
+// If onBehalfOfUserID is set, the acting user must hold an active approval delegation from that user.
+func (service *DocumentationEntryServiceImpl) ApproveDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, approvedByTeacherID int, actingUserID int, onBehalfOfUserID *int) error {
 	// Check if the entry exists
-	entry, err := service.documentationEntryStore.GetByID(entryID)
+	entry, err := service.documentationEntryStore.GetByID(ctx, entryID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("entry_id", entryID).Warn("Documentation entry not found for approval")
@@ -247,11 +463,11 @@ func (service *DocumentationEntryServiceImpl) ApproveDocumentationEntry(logger *
 	}
 
 	// Check if the approving teacher exists
-	_, err = service.teacherStore.GetByID(approvedByTeacherID)
+	_, err = service.teacherStore.GetByID(ctx, approvedByTeacherID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("teacher_id", approvedByTeacherID).Warn("Approving teacher not found")
-			return errors.New("approving teacher not found")
+			return ErrTeacherNotFound
 		}
 		logger.WithError(err).WithField("teacher_id", approvedByTeacherID).Error("Error fetching teacher by ID for approval")
 		return ErrInternal
@@ -260,10 +476,35 @@ func (service *DocumentationEntryServiceImpl) ApproveDocumentationEntry(logger *
 	// Business rule: Only unapproved entries can be approved.
 	if entry.IsApproved {
 		logger.WithField("entry_id", entryID).Warn("Documentation entry is already approved")
-		return errors.New("documentation entry is already approved")
+		return ErrEntryAlreadyApproved
 	}
 
-	err = service.documentationEntryStore.ApproveEntry(entryID, approvedByTeacherID)
+	var delegation *models.ApprovalDelegation
+	if onBehalfOfUserID != nil {
+		if service.delegationService == nil {
+			logger.WithField("entry_id", entryID).Warn("Approval on behalf of another user requested but delegations are disabled")
+			return ErrPermissionDenied
+		}
+		delegation, err = service.delegationService.GetActiveDelegation(logger, ctx, *onBehalfOfUserID, actingUserID, service.clock.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	outbox, err := service.teacherNotification(logger, entry.TeacherID, models.Notification{
+		Type:  models.NotificationTypeApproval,
+		Title: "Dokumentation freigegeben",
+		Body:  fmt.Sprintf("Ihr Eintrag vom %s wurde freigegeben.", entry.ObservationDate.Format("02.01.2006")),
+		Data: map[string]string{
+			"entry_id": strconv.Itoa(entry.ID),
+			"child_id": strconv.Itoa(entry.ChildID),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = service.documentationEntryStore.ApproveEntry(ctx, entryID, approvedByTeacherID, outbox)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("entry_id", entryID).Warn("Documentation entry not found during approval process")
@@ -273,14 +514,226 @@ func (service *DocumentationEntryServiceImpl) ApproveDocumentationEntry(logger *
 		return ErrInternal
 	}
 	logger.WithField("entry_id", entryID).Info("Documentation entry approved successfully")
+
+	approvedEvent := &models.DocumentationEvent{
+		ChildID:          entry.ChildID,
+		EntryID:          entryID,
+		Type:             models.DocumentationEventApproved,
+		ActorUserID:      &actingUserID,
+		OnBehalfOfUserID: onBehalfOfUserID,
+		Payload:          models.DocumentationEventData{ApprovedByTeacherID: &approvedByTeacherID},
+	}
+	if delegation != nil {
+		approvedEvent.DelegationID = &delegation.ID
+	}
+	service.recordEvent(logger, ctx, approvedEvent)
+
+	if service.auditLogService != nil {
+		auditEntry := &models.AuditLogEntry{
+			Action:           models.AuditActionApproveDocumentationEntry,
+			EntityType:       "documentation_entry",
+			EntityID:         &entryID,
+			ActorUserID:      &actingUserID,
+			OnBehalfOfUserID: onBehalfOfUserID,
+			Details:          fmt.Sprintf("approved_by_teacher_id=%d", approvedByTeacherID),
+		}
+		if delegation != nil {
+			auditEntry.DelegationID = &delegation.ID
+		}
+		// The approval itself has already been stored, so a failing audit write is only logged.
+		_ = service.auditLogService.Record(logger, ctx, auditEntry)
+	}
+	return nil
+}
+
+// SubmitDocumentationEntry submits a draft or rejected documentation entry for review.
+func (service *DocumentationEntryServiceImpl) SubmitDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, actingUserID int) error {
+	entry, status, err := service.getEntryWithStatus(logger, ctx, entryID)
+	if err != nil {
+		return err
+	}
+
+	// Business rule: Only drafts and rejected entries can be submitted.
+	if status != models.DocumentationStatusDraft && status != models.DocumentationStatusRejected {
+		logger.WithFields(logrus.Fields{"entry_id": entryID, "status": status}).Warn("Documentation entry cannot be submitted")
+		return ErrInvalidStateTransition
+	}
+
+	// The event is the state change, so a failing write fails the submission.
+	if service.eventService != nil {
+		err = service.eventService.Record(logger, ctx, &models.DocumentationEvent{
+			ChildID:     entry.ChildID,
+			EntryID:     entryID,
+			Type:        models.DocumentationEventSubmitted,
+			ActorUserID: &actingUserID,
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+	logger.WithField("entry_id", entryID).Info("Documentation entry submitted successfully")
 	return nil
 }
 
+// RejectDocumentationEntry sends a submitted documentation entry back to its author with a reason.
+func (service *DocumentationEntryServiceImpl) RejectDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, actingUserID int, reason string) error {
+	if reason == "" || len(reason) > maxRejectionReasonLength {
+		logger.WithField("entry_id", entryID).Warn("Invalid rejection reason for RejectDocumentationEntry")
+		return ErrInvalidInput
+	}
+
+	entry, status, err := service.getEntryWithStatus(logger, ctx, entryID)
+	if err != nil {
+		return err
+	}
+
+	// Business rule: Only submitted entries can be rejected.
+	if status != models.DocumentationStatusSubmitted {
+		logger.WithFields(logrus.Fields{"entry_id": entryID, "status": status}).Warn("Documentation entry cannot be rejected")
+		return ErrInvalidStateTransition
+	}
+
+	outbox, err := service.teacherNotification(logger, entry.TeacherID, models.Notification{
+		Type:  models.NotificationTypeRejection,
+		Title: "Dokumentation zurückgewiesen",
+		Body:  fmt.Sprintf("Ihr Eintrag vom %s wurde zurückgewiesen: %s", entry.ObservationDate.Format("02.01.2006"), reason),
+		Data: map[string]string{
+			"entry_id": strconv.Itoa(entry.ID),
+			"child_id": strconv.Itoa(entry.ChildID),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// The event is the state change, so a failing write fails the rejection.
+	if service.eventService != nil {
+		err = service.eventService.Record(logger, ctx, &models.DocumentationEvent{
+			ChildID:     entry.ChildID,
+			EntryID:     entryID,
+			Type:        models.DocumentationEventRejected,
+			ActorUserID: &actingUserID,
+			Payload:     models.DocumentationEventData{Reason: reason},
+		}, outbox)
+		if err != nil {
+			return err
+		}
+	}
+	logger.WithField("entry_id", entryID).Info("Documentation entry rejected successfully")
+	return nil
+}
+
+// getEntryWithStatus fetches an entry together with its lifecycle state.
+// Approved entries are approved regardless of their events, entries without events are drafts.
+func (service *DocumentationEntryServiceImpl) getEntryWithStatus(logger *logrus.Entry, ctx context.Context, entryID int) (*models.DocumentationEntry, string, error) {
+	entry, err := service.documentationEntryStore.GetByID(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("entry_id", entryID).Warn("Documentation entry not found")
+			return nil, "", ErrNotFound
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Error fetching documentation entry by ID")
+		return nil, "", ErrInternal
+	}
+	if entry.IsApproved {
+		return entry, models.DocumentationStatusApproved, nil
+	}
+	if service.eventService == nil {
+		return entry, models.DocumentationStatusDraft, nil
+	}
+	history, err := service.eventService.GetEntryHistory(logger, ctx, entryID)
+	if errors.Is(err, ErrNotFound) {
+		return entry, models.DocumentationStatusDraft, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return entry, history.Status, nil
+}
+
+// recordEvent appends an event to the lifecycle stream.
+// The change itself has already been stored, so a failing write is only logged.
+func (service *DocumentationEntryServiceImpl) recordEvent(logger *logrus.Entry, ctx context.Context, event *models.DocumentationEvent) {
+	if service.eventService == nil {
+		return
+	}
+	_ = service.eventService.Record(logger, ctx, event, nil)
+}
+
+// teacherNotification builds the outbox messages that notify a teacher about a change.
+// They are stored together with the change and delivered by the outbox dispatcher.
+func (service *DocumentationEntryServiceImpl) teacherNotification(logger *logrus.Entry, teacherID int, notification models.Notification) ([]models.OutboxMessage, error) {
+	if service.notificationService == nil {
+		return nil, nil
+	}
+	message, err := service.notificationService.TeacherMessage(teacherID, notification)
+	if err != nil {
+		logger.WithError(err).WithField("teacher_id", teacherID).Error("Error building notification message")
+		return nil, ErrInternal
+	}
+	return []models.OutboxMessage{message}, nil
+}
+
+// checkCanceled returns ErrCanceled once the request was cancelled, because the client disconnected or it timed out.
+func checkCanceled(logger *logrus.Entry, ctx context.Context, operation string) error {
+	if err := ctx.Err(); err != nil {
+		logger.WithError(err).Warnf("%s canceled", operation)
+		return ErrCanceled
+	}
+	return nil
+}
+
+// Estimates of the memory taken by generating a child report, the document is held in memory as XML and zipped.
+const (
+	childReportBaseSize  = 4 << 20 // Template, styles and the zip buffer
+	childReportEntrySize = 8 << 10 // Paragraph of an entry without its text
+	childReportFieldSize = 1 << 10 // Line of a structured form field
+)
+
+// estimateChildReportSize estimates the memory generating a child report with the entries, logo and photos takes.
+func estimateChildReportSize(entries []models.DocumentationEntry, logo *models.DocumentLogo, photos []models.GalleryPhoto) int64 {
+	size := int64(childReportBaseSize)
+	for _, entry := range entries {
+		size += childReportEntrySize + 4*int64(len(entry.ObservationDescription)) + childReportFieldSize*int64(len(entry.StructuredData))
+	}
+	if logo != nil {
+		// The image is held decoded for its dimensions and encoded in the document.
+		size += 3 * int64(len(logo.Content))
+	}
+	for _, photo := range photos {
+		size += 3 * int64(len(photo.Content))
+	}
+	return size
+}
+
+// acquireDocument reserves the estimated size of a document in the document pool, waiting while it is full.
+func (service *DocumentationEntryServiceImpl) acquireDocument(logger *logrus.Entry, ctx context.Context, size int64) (func(), error) {
+	if service.documentPool == nil {
+		return func() {}, nil
+	}
+	release, err := service.documentPool.Acquire(ctx, size)
+	if errors.Is(err, ErrBusy) {
+		logger.WithField("estimated_size", size).Warn("Too many documents being generated, rejecting report")
+	} else if err != nil {
+		logger.WithError(err).Warn("Child report generation canceled while waiting for the document pool")
+	}
+	return release, err
+}
+
 // GenerateChildReport generates a Word document with the child's documentation entries.
-func (service *DocumentationEntryServiceImpl) GenerateChildReport(logger *logrus.Entry, ctx context.Context, childID int, assignments []models.Assignment) ([]byte, error) {
+// The optional redaction profile leaves out the information it hides; nil generates the full report.
+// A non-nil completeness score is appended as an internal appendix.
+// External supports of the child are listed in the Inklusion/Förderung section if the parents consented.
+// The photos picked from the gallery are added in the Fotos section, they have to be checked for consent by the caller.
+// Generation stops with ErrCanceled when the request is cancelled, a cancelled report is not archived.
+func (service *DocumentationEntryServiceImpl) GenerateChildReport(logger *logrus.Entry, ctx context.Context, childID int, assignments []models.Assignment, redaction *models.RedactionProfile, completeness *models.ChildCompleteness, supports []models.ChildSupport, photos []models.GalleryPhoto) ([]byte, error) {
+	if redaction == nil {
+		redaction = &models.RedactionProfile{}
+	}
+
 	logger.WithField("child_id", childID).Info("Generating child report")
 
-	child, err := service.childStore.GetByID(childID)
+	child, err := service.childStore.GetByID(ctx, childID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("child_id", childID).Warn("Child not found for report generation")
@@ -290,30 +743,58 @@ func (service *DocumentationEntryServiceImpl) GenerateChildReport(logger *logrus
 		return nil, ErrInternal
 	}
 
-	entries, err := service.documentationEntryStore.GetAllForChild(childID)
+	entries, err := service.documentationEntryStore.GetAllForChild(ctx, childID)
 	if err != nil {
 		logger.WithError(err).WithField("child_id", childID).Error("Error fetching documentation entries for report generation")
 		return nil, ErrInternal
 	}
 
-	masterdata, err := service.kitaMasterdataStore.Get()
+	masterdata, err := service.kitaMasterdataStore.Get(ctx)
 	if err != nil {
 		logger.WithError(err).Error("Error fetching kita masterdata for report generation")
 		return nil, ErrInternal
 	}
 
+	var logo *models.DocumentLogo
+	if masterdata.Theme.HasLogo {
+		logo, err = service.kitaMasterdataStore.GetLogo(ctx)
+		if err != nil {
+			logger.WithError(err).Error("Error fetching document logo for child report")
+			return nil, ErrInternal
+		}
+	}
+
+	if err := checkCanceled(logger, ctx, "Child report generation"); err != nil {
+		return nil, err
+	}
+
+	release, err := service.acquireDocument(logger, ctx, estimateChildReportSize(entries, logo, photos))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	document, err := godocx.NewDocument()
 	if err != nil {
 		logger.WithError(err).Error("Error creating new Word document for child report")
 		return nil, ErrChildReportGenerationFailed
 	}
 
-	assignmentsText, err := service.FormatChildTeacherAssignments(assignments)
+	terminology := masterdata.Terminology
+	assignmentsText, err := service.FormatChildTeacherAssignments(ctx, assignments, redaction.HideTeacherNames, terminology)
 	if err != nil {
 		logger.WithError(err).WithField("child_id", childID).Error("Error formatting child teacher assignments for report")
 		return nil, ErrChildReportGenerationFailed
 	}
 
+	applyDocumentTheme(document, masterdata.Theme)
+	if logo != nil {
+		if err := addDocumentLogo(document, logo); err != nil {
+			logger.WithError(err).Error("Error adding document logo to child report")
+			return nil, ErrChildReportGenerationFailed
+		}
+	}
+
 	breaktype := stypes.BreakTypeTextWrapping
 
 	// Add a title
@@ -325,24 +806,34 @@ func (service *DocumentationEntryServiceImpl) GenerateChildReport(logger *logrus
 	document.AddEmptyParagraph()
 
 	addressParagraph := document.AddEmptyParagraph()
-	addressParagraph.AddText(masterdata.Name).AddBreak(&breaktype)
-	addressParagraph.AddText(fmt.Sprintf("%s %s", masterdata.Street, masterdata.HouseNumber)).AddBreak(&breaktype)
-	addressParagraph.AddText(fmt.Sprintf("%s %s", masterdata.PostalCode, masterdata.City)).AddBreak(&breaktype)
-	addressParagraph.AddText(fmt.Sprintf("Telefonnummer: %s", masterdata.PhoneNumber)).AddBreak(&breaktype)
-	addressParagraph.AddText(fmt.Sprintf("E-Mail-Adresse: %s", masterdata.Email))
+	if redaction.HideKitaContact {
+		addressParagraph.AddText(masterdata.Name)
+	} else {
+		addressParagraph.AddText(masterdata.Name).AddBreak(&breaktype)
+		addressParagraph.AddText(fmt.Sprintf("%s %s", masterdata.Street, masterdata.HouseNumber)).AddBreak(&breaktype)
+		addressParagraph.AddText(fmt.Sprintf("%s %s", masterdata.PostalCode, masterdata.City)).AddBreak(&breaktype)
+		addressParagraph.AddText(fmt.Sprintf("Telefonnummer: %s", masterdata.PhoneNumber)).AddBreak(&breaktype)
+		addressParagraph.AddText(fmt.Sprintf("E-Mail-Adresse: %s", masterdata.Email))
+	}
 
 	document.AddEmptyParagraph()
 
 	childInformationParagraph := document.AddEmptyParagraph()
-	childInformationParagraph.AddText(fmt.Sprintf("Name des Kindes: %s %s", child.FirstName, child.LastName)).AddBreak(&breaktype)
-	childInformationParagraph.AddText(fmt.Sprintf("Geburtsdatum: %s", child.Birthdate.Format("02.01.2006"))).AddBreak(&breaktype)
-	if child.AdmissionDate != nil {
+	if redaction.HideChildLastName {
+		childInformationParagraph.AddText(fmt.Sprintf("Name des Kindes: %s", child.FirstName)).AddBreak(&breaktype)
+	} else {
+		childInformationParagraph.AddText(fmt.Sprintf("Name des Kindes: %s %s", child.FirstName, child.LastName)).AddBreak(&breaktype)
+	}
+	if !redaction.HideBirthdate {
+		childInformationParagraph.AddText(fmt.Sprintf("Geburtsdatum: %s", child.Birthdate.Format("02.01.2006"))).AddBreak(&breaktype)
+	}
+	if child.AdmissionDate != nil && !redaction.HideAdmissionDate {
 		childInformationParagraph.AddText(fmt.Sprintf("Aufnahmedatum: %s", child.AdmissionDate.Format("02.01.2006"))).AddBreak(&breaktype)
 	}
-	if child.ExpectedSchoolEnrollment != nil {
+	if child.ExpectedSchoolEnrollment != nil && !redaction.HideSchoolEnrollment {
 		childInformationParagraph.AddText(fmt.Sprintf("Voraussichtliche Einschulung: %s", child.ExpectedSchoolEnrollment.Format("02.01.2006"))).AddBreak(&breaktype)
 	}
-	childInformationParagraph.AddText("Entwicklungsbegleiter/-innen, Fachkräfte (von - bis):").AddBreak(&breaktype)
+	childInformationParagraph.AddText(fmt.Sprintf("Entwicklungsbegleiter/-innen, %s (von - bis):", terminology.Term(models.TermTeachers))).AddBreak(&breaktype)
 	for _, assignmentText := range assignmentsText {
 		childInformationParagraph.AddText(assignmentText).Style("List Bullet").AddBreak(&breaktype)
 	}
@@ -351,16 +842,26 @@ func (service *DocumentationEntryServiceImpl) GenerateChildReport(logger *logrus
 
 	document.AddHeading("Kindbeobachtungen", 1) //nolint:errcheck
 
+	// An approved revision replaces the entry it revises.
+	superseded := make(map[int]bool)
+	for _, entry := range entries {
+		if entry.IsApproved && entry.RevisionOfEntryID != nil {
+			superseded[*entry.RevisionOfEntryID] = true
+		}
+	}
+
 	// Group entries by category
 	entriesByCategory := make(map[string][]models.DocumentationEntry)
+	formSchemasByCategory := make(map[string]*models.FormSchema)
 	for _, entry := range entries {
-		if entry.IsApproved {
-			category, err := service.categoryStore.GetByID(entry.CategoryID)
+		if entry.IsApproved && !superseded[entry.ID] && !redaction.ExcludesCategory(entry.CategoryID) {
+			category, err := service.categoryStore.GetByID(ctx, entry.CategoryID)
 			if err != nil {
 				logger.WithError(err).WithField("category_id", entry.CategoryID).Warn("Category not found for entry")
 				continue
 			}
 			entriesByCategory[category.Name] = append(entriesByCategory[category.Name], entry)
+			formSchemasByCategory[category.Name] = category.FormSchema
 		}
 	}
 
@@ -375,29 +876,323 @@ func (service *DocumentationEntryServiceImpl) GenerateChildReport(logger *logrus
 
 	// Add entries to the document
 	for categoryName, entries := range entriesByCategory {
-		document.AddHeading(fmt.Sprintf("Bildungsbereich: %s", categoryName), 2) //nolint:errcheck
+		if err := checkCanceled(logger, ctx, "Child report generation"); err != nil {
+			return nil, err
+		}
+		document.AddHeading(fmt.Sprintf("%s: %s", terminology.Term(models.TermCategory), categoryName), 2) //nolint:errcheck
 		for _, entry := range entries {
 			documentation := fmt.Sprintf("%s (%s)",
 				entry.ObservationDescription,
 				entry.ObservationDate.Format("02.01.2006"),
 			)
-			document.AddParagraph(documentation).Style("List Bullet") //nolint:errcheck
+			if redaction.HideObservationDates {
+				documentation = entry.ObservationDescription
+			}
+			paragraph := document.AddEmptyParagraph()
+			paragraph.Style("List Bullet")
+			run := paragraph.AddText(documentation)
+			if schema := formSchemasByCategory[categoryName]; schema != nil {
+				// Structured form fields are listed below the free text, one per line.
+				for _, field := range schema.FormatFields(entry.StructuredData) {
+					run.AddBreak(&breaktype)
+					run = paragraph.AddText(field)
+				}
+			}
 		}
 	}
 
+	if err := addPhotoSection(document, photos); err != nil {
+		logger.WithError(err).Error("Error adding photos to child report")
+		return nil, ErrChildReportGenerationFailed
+	}
+
+	if !redaction.HideSupportProviders {
+		addSupportSection(document, supports)
+	}
+
+	if completeness != nil {
+		addCompletenessAppendix(document, completeness)
+	}
+
+	report := &models.GeneratedReport{
+		ChildID:     childID,
+		DocumentID:  uuid.NewString(),
+		GeneratedAt: service.clock.Now(),
+		EntryIDs:    []int{},
+		FileName:    documentName(child, redaction),
+		Parameters:  models.GeneratedReportParameters{IncludeCompleteness: completeness != nil},
+	}
+	for _, photo := range photos {
+		report.Parameters.PhotoIDs = append(report.Parameters.PhotoIDs, photo.ID)
+	}
+	if redaction.ID != 0 {
+		report.Parameters.RedactionProfileID = &redaction.ID
+		report.Parameters.RedactionProfileName = redaction.Name
+	}
+	stamp := reportStamp{DocumentID: report.DocumentID, Title: terminology.Term(models.TermDocumentation), Facility: masterdata.Name, GeneratedAt: report.GeneratedAt}
+	if user, ok := ctx.Value(middleware.ContextKeyUser).(*models.User); ok {
+		report.GeneratedByUserID = &user.ID
+		// The archive still records who generated the report when the name is hidden in the document.
+		if !redaction.HideTeacherNames {
+			stamp.GeneratedBy = user.Username
+		}
+	}
+	if err := stampReport(document, stamp); err != nil {
+		logger.WithError(err).Error("Error stamping generated document")
+		return nil, ErrChildReportGenerationFailed
+	}
+
 	var buf bytes.Buffer
 	if err := document.Write(&buf); err != nil {
 		logger.WithError(err).Error("Error saving generated document")
 		return nil, ErrChildReportGenerationFailed
 	}
+	report.Content = buf.Bytes()
+	if err := checkCanceled(logger, ctx, "Child report generation"); err != nil {
+		return nil, err
+	}
+
+	// The report is handed out from here on, so it is archived and its entries are locked before it is returned.
+	for _, entries := range entriesByCategory {
+		for _, entry := range entries {
+			report.EntryIDs = append(report.EntryIDs, entry.ID)
+		}
+	}
+	slices.Sort(report.EntryIDs)
+	if err := service.documentationEntryStore.RecordReport(ctx, report); err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error recording generated report")
+		return nil, ErrInternal
+	}
+
+	for _, entries := range entriesByCategory {
+		for _, entry := range entries {
+			service.recordEvent(logger, ctx, &models.DocumentationEvent{
+				ChildID: childID,
+				EntryID: entry.ID,
+				Type:    models.DocumentationEventIncludedInReport,
+			})
+		}
+	}
+	if service.auditLogService != nil {
+		// The report is archived already, so a failing audit write is only logged.
+		_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+			Action:      models.AuditActionGenerateChildReport,
+			EntityType:  "generated_report",
+			EntityID:    &report.ID,
+			ActorUserID: report.GeneratedByUserID,
+			Details:     fmt.Sprintf("child_id=%d", childID),
+		})
+	}
 
 	logger.WithField("child_id", childID).Info("Child report generated successfully")
 	return buf.Bytes(), nil
 }
 
-func (service *DocumentationEntryServiceImpl) GetDocumentName(ctx context.Context, childID int) (string, error) {
+// CreateDocumentationEntryRevision creates a revision of an entry, the way to change an entry that is locked by a generated report.
+// The revision is a new draft of the same child; once approved, it replaces the revised entry in later reports.
+func (service *DocumentationEntryServiceImpl) CreateDocumentationEntryRevision(logger *logrus.Entry, ctx context.Context, entryID int, revision *models.DocumentationEntry) (*models.DocumentationEntry, error) {
+	original, err := service.GetDocumentationEntryByID(logger, ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision.ID = 0
+	revision.ChildID = original.ChildID
+	revision.RevisionOfEntryID = &original.ID
+	revision.IsApproved = false
+	revision.ApprovedByUserID = nil
+	revision.LockedAt = nil
+	created, err := service.CreateDocumentationEntry(logger, ctx, revision)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{"entry_id": created.ID, "revision_of_entry_id": entryID}).Info("Documentation entry revision created successfully")
+	return created, nil
+}
+
+// UnlockDocumentationEntry unlocks an entry locked by a generated report, so it can be edited directly again.
+func (service *DocumentationEntryServiceImpl) UnlockDocumentationEntry(logger *logrus.Entry, ctx context.Context, entryID int, actingUserID int) error {
+	if err := service.documentationEntryStore.Unlock(ctx, entryID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("entry_id", entryID).Warn("Documentation entry not found for unlock")
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Error unlocking documentation entry")
+		return ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"entry_id": entryID, "user_id": actingUserID}).Info("Documentation entry unlocked successfully")
+
+	if service.auditLogService != nil {
+		// The unlock itself has already been stored, so a failing audit write is only logged.
+		_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+			Action:      models.AuditActionUnlockDocumentationEntry,
+			EntityType:  "documentation_entry",
+			EntityID:    &entryID,
+			ActorUserID: &actingUserID,
+		})
+	}
+	return nil
+}
+
+// GetGeneratedReportsForChild fetches the generated reports of a child with the entries they included.
+func (service *DocumentationEntryServiceImpl) GetGeneratedReportsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.GeneratedReport, error) {
+	if _, err := service.childStore.GetByID(ctx, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for generated reports")
+		return nil, ErrInternal
+	}
+	reports, err := service.documentationEntryStore.GetReportsForChild(ctx, childID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching generated reports")
+		return nil, ErrInternal
+	}
+	return reports, nil
+}
+
+// DownloadGeneratedReport fetches an archived report of a child for download and records the download in the audit log.
+func (service *DocumentationEntryServiceImpl) DownloadGeneratedReport(logger *logrus.Entry, ctx context.Context, childID int, reportID int, actingUserID int) (*models.GeneratedReport, error) {
+	report, err := service.documentationEntryStore.GetReportByID(ctx, reportID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("report_id", reportID).Warn("Generated report not found for download")
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("report_id", reportID).Error("Error fetching generated report for download")
+		return nil, ErrInternal
+	}
+	// Reports are addressed through their child, a report of another child is not found.
+	if report.ChildID != childID {
+		logger.WithFields(logrus.Fields{"report_id": reportID, "child_id": childID}).Warn("Generated report does not belong to child")
+		return nil, ErrNotFound
+	}
+	if !report.Archived {
+		logger.WithField("report_id", reportID).Warn("Generated report was not archived and cannot be downloaded")
+		return nil, ErrNotFound
+	}
+
+	if service.auditLogService != nil {
+		// The download is served regardless, so a failing audit write is only logged.
+		_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+			Action:      models.AuditActionDownloadGeneratedReport,
+			EntityType:  "generated_report",
+			EntityID:    &reportID,
+			ActorUserID: &actingUserID,
+			Details:     fmt.Sprintf("child_id=%d", childID),
+		})
+	}
+	logger.WithFields(logrus.Fields{"report_id": reportID, "user_id": actingUserID}).Info("Generated report downloaded")
+	return report, nil
+}
+
+// ExportGeneratedReports fetches the archived reports for a bulk export and records the export in the audit log.
+func (service *DocumentationEntryServiceImpl) ExportGeneratedReports(logger *logrus.Entry, ctx context.Context, since time.Time, actingUserID int) ([]models.GeneratedReport, error) {
+	reportIDs, err := service.documentationEntryStore.GetArchivedReportIDsSince(ctx, since)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching archived reports for export")
+		return nil, ErrInternal
+	}
+
+	reports := make([]models.GeneratedReport, 0, len(reportIDs))
+	for _, reportID := range reportIDs {
+		if err := checkCanceled(logger, ctx, "Generated report export"); err != nil {
+			return nil, err
+		}
+		report, err := service.documentationEntryStore.GetReportByID(ctx, reportID)
+		if err != nil {
+			logger.WithError(err).WithField("report_id", reportID).Error("Error fetching generated report for export")
+			return nil, ErrInternal
+		}
+		reports = append(reports, *report)
+	}
+
+	if service.auditLogService != nil {
+		// An export is only handed out once it is recorded.
+		if err := service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+			Action:      models.AuditActionExportGeneratedReports,
+			EntityType:  "generated_report",
+			ActorUserID: &actingUserID,
+			Details:     fmt.Sprintf("reports=%d since=%s", len(reports), since.Format("2006-01-02")),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	logger.WithFields(logrus.Fields{"reports": len(reports), "user_id": actingUserID}).Info("Generated reports exported in bulk")
+	return reports, nil
+}
+
+// addSupportSection adds the Inklusion/Förderung section listing the external providers supporting the child.
+// Supports without consent of the parents are left out, appointment notes are internal and never printed.
+func addSupportSection(document *docx.RootDoc, supports []models.ChildSupport) {
+	var consented []models.ChildSupport
+	for _, support := range supports {
+		if support.HasConsent() {
+			consented = append(consented, support)
+		}
+	}
+	if len(consented) == 0 {
+		return
+	}
+	document.AddHeading("Inklusion/Förderung", 2) //nolint:errcheck
+	document.AddParagraph("Externe Förderung (von - bis):")
+	for _, support := range consented {
+		period := fmt.Sprintf("seit %s", support.StartDate.Format("02.01.2006"))
+		if support.EndDate != nil {
+			period = fmt.Sprintf("%s - %s", support.StartDate.Format("02.01.2006"), support.EndDate.Format("02.01.2006"))
+		}
+		line := fmt.Sprintf("%s: %s (%s)", models.SupportProviderTypeLabel(support.ProviderType), support.ProviderName, period)
+		document.AddParagraph(line).Style("List Bullet") //nolint:errcheck
+	}
+}
+
+// addPhotoSection adds the Fotos section with the photos and their captions below each other.
+func addPhotoSection(document *docx.RootDoc, photos []models.GalleryPhoto) error {
+	if len(photos) == 0 {
+		return nil
+	}
+	document.AddHeading("Fotos", 2) //nolint:errcheck
+	for _, photo := range photos {
+		picture, err := addImage(document, photo.Content, photo.ContentType, reportPhotoWidth, reportPhotoMaxHeight)
+		if err != nil {
+			return err
+		}
+		picture.Para.Justification(stypes.JustificationCenter)
+		if photo.Caption != nil && *photo.Caption != "" {
+			document.AddParagraph(*photo.Caption).Justification(stypes.JustificationCenter)
+		}
+	}
+	return nil
+}
+
+// addCompletenessAppendix adds the internal completeness appendix on a new page of the report.
+func addCompletenessAppendix(document *docx.RootDoc, completeness *models.ChildCompleteness) {
+	document.AddPageBreak()
+	document.AddHeading("Interner Anhang: Vollständigkeit der Dokumentation", 1) //nolint:errcheck
+	document.AddParagraph(fmt.Sprintf("Zeitraum: %s - %s",
+		completeness.PeriodStart.Format("02.01.2006"),
+		completeness.PeriodEnd.Format("02.01.2006"),
+	))
+	document.AddParagraph(fmt.Sprintf("Vollständigkeit: %d %% (%d von %d Bildungsbereichen dokumentiert)",
+		completeness.Score, completeness.CategoriesCovered, completeness.CategoriesTotal,
+	))
+	if completeness.LastEntryAgeDays != nil {
+		document.AddParagraph(fmt.Sprintf("Letzte Beobachtung vor %d Tagen", *completeness.LastEntryAgeDays))
+	} else {
+		document.AddParagraph("Keine Beobachtungen im Zeitraum")
+	}
+	for _, coverage := range completeness.Categories {
+		line := fmt.Sprintf("%s: %d Beobachtungen", coverage.CategoryName, coverage.EntryCount)
+		if coverage.LastEntryDate != nil {
+			line = fmt.Sprintf("%s, zuletzt am %s", line, coverage.LastEntryDate.Format("02.01.2006"))
+		}
+		document.AddParagraph(line).Style("List Bullet") //nolint:errcheck
+	}
+}
+
+func (service *DocumentationEntryServiceImpl) GetDocumentName(ctx context.Context, childID int, redaction *models.RedactionProfile) (string, error) {
 	// Fetch child details to construct the document name
-	child, err := service.childStore.GetByID(childID)
+	child, err := service.childStore.GetByID(ctx, childID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			return "", fmt.Errorf("child with ID %d not found", childID)
@@ -405,22 +1200,50 @@ func (service *DocumentationEntryServiceImpl) GetDocumentName(ctx context.Contex
 		return "", fmt.Errorf("error fetching child details: %w", err)
 	}
 
-	documentName := fmt.Sprintf("Bildungsdokumentation_%s_%s_%s.docx", child.FirstName, child.LastName, child.Birthdate.Format("2006-01-02"))
+	return documentName(child, redaction), nil
+}
 
-	return documentName, nil
+// documentName is the file name of the report of a child.
+func documentName(child *models.Child, redaction *models.RedactionProfile) string {
+	if redaction != nil && (redaction.HideChildLastName || redaction.HideBirthdate) {
+		// The file name must not leak what the document itself leaves out.
+		return fmt.Sprintf("Bildungsdokumentation_%s_%d.docx", child.FirstName, child.ID)
+	}
+	return fmt.Sprintf("Bildungsdokumentation_%s_%s_%s.docx", child.FirstName, child.LastName, child.Birthdate.Format("2006-01-02"))
 }
 
-func (service *DocumentationEntryServiceImpl) FormatChildTeacherAssignments(assignments []models.Assignment) ([]string, error) {
+// assignmentTypeOrder is the order in which the assignment types are listed in the report.
+var assignmentTypeOrder = []string{models.AssignmentTypePrimary, models.AssignmentTypeSecondary, models.AssignmentTypeIntern}
+
+// assignmentTypeTerms are the terms of the role names of the assignment types in the report.
+var assignmentTypeTerms = map[string]string{
+	models.AssignmentTypePrimary:   models.TermPrimaryTeacher,
+	models.AssignmentTypeSecondary: models.TermSecondaryTeacher,
+	models.AssignmentTypeIntern:    models.TermIntern,
+}
+
+// FormatChildTeacherAssignments formats the assignments for the report, primary assignments first, each with its role
+// in the terminology of the facility.
+func (service *DocumentationEntryServiceImpl) FormatChildTeacherAssignments(ctx context.Context, assignments []models.Assignment, hideTeacherNames bool, terminology models.Terminology) ([]string, error) {
 	if len(assignments) == 0 {
 		return []string{"Keine Zuordnungen gefunden"}, nil
 	}
 
+	assignments = slices.Clone(assignments)
+	slices.SortStableFunc(assignments, func(a, b models.Assignment) int {
+		return slices.Index(assignmentTypeOrder, a.AssignmentType) - slices.Index(assignmentTypeOrder, b.AssignmentType)
+	})
+
 	var formattedAssignments []string
 	for _, assignment := range assignments {
-		// Lookup teacher name for teacher ID
-		teacher, err := service.teacherStore.GetByID(assignment.TeacherID)
-		if err != nil {
-			return nil, err
+		teacherName := terminology.Term(models.TermTeacher)
+		if !hideTeacherNames {
+			// Lookup teacher name for teacher ID
+			teacher, err := service.teacherStore.GetByID(ctx, assignment.TeacherID)
+			if err != nil {
+				return nil, err
+			}
+			teacherName = fmt.Sprintf("%s %s", teacher.FirstName, teacher.LastName)
 		}
 		assignmentStart := assignment.StartDate.Format("02.01.2006")
 		var assignmentEnd string
@@ -429,7 +1252,10 @@ func (service *DocumentationEntryServiceImpl) FormatChildTeacherAssignments(assi
 		} else {
 			assignmentEnd = assignment.EndDate.Format("02.01.2006")
 		}
-		formattedAssignments = append(formattedAssignments, fmt.Sprintf("- %s %s (%s bis %s)", teacher.FirstName, teacher.LastName, assignmentStart, assignmentEnd))
+		if term, ok := assignmentTypeTerms[assignment.AssignmentType]; ok {
+			teacherName = fmt.Sprintf("%s, %s", teacherName, terminology.Term(term))
+		}
+		formattedAssignments = append(formattedAssignments, fmt.Sprintf("- %s (%s bis %s)", teacherName, assignmentStart, assignmentEnd))
 	}
 
 	return formattedAssignments, nil
@@ -1,19 +1,26 @@
 package services_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"slices"
 	"testing"
 	"time"
 
 	"kitadoc-backend/data"
 	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateDocumentationEntry(t *testing.T) {
@@ -35,29 +42,38 @@ func TestCreateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
 			ChildID:                1,
 			TeacherID:              1,
 			CategoryID:             1,
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Test observation",
 		}
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 		expectedCategory := &models.Category{ID: 1}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", entry.TeacherID).Return(expectedTeacher, nil).Once()
-		mockCategoryStore.On("GetByID", entry.CategoryID).Return(expectedCategory, nil).Once()
-		mockDocumentationEntryStore.On("Create", mock.AnythingOfType("*models.DocumentationEntry")).Return(1, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, entry.TeacherID).Return(expectedTeacher, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, entry.CategoryID).Return(expectedCategory, nil).Once()
+		mockDocumentationEntryStore.On("Create", mock.Anything, mock.AnythingOfType("*models.DocumentationEntry")).Return(1, nil).Once()
+		persisted := &models.DocumentationEntry{ID: 1, ChildID: 1, TeacherID: 1, CategoryID: 1, CreatedAt: time.Now()}
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, 1).Return(persisted, nil).Once()
 
 		createdEntry, err := service.CreateDocumentationEntry(logger, ctx, entry)
 
 		assert.NoError(t, err)
-		assert.NotNil(t, createdEntry)
-		assert.Equal(t, 1, createdEntry.ID)
+		assert.Equal(t, persisted, createdEntry, "the persisted entry is returned")
 		mockChildStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
 		mockCategoryStore.AssertExpectations(t)
@@ -79,6 +95,14 @@ func TestCreateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
@@ -88,12 +112,12 @@ func TestCreateDocumentationEntry(t *testing.T) {
 		createdEntry, err := service.CreateDocumentationEntry(logger, ctx, entry)
 
 		assert.Error(t, err)
-		assert.Equal(t, services.ErrInvalidInput, err)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
 		assert.Nil(t, createdEntry)
-		mockChildStore.AssertNotCalled(t, "GetByID")
-		mockTeacherStore.AssertNotCalled(t, "GetByID")
-		mockCategoryStore.AssertNotCalled(t, "GetByID")
-		mockDocumentationEntryStore.AssertNotCalled(t, "Create")
+		mockChildStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockTeacherStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockCategoryStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockDocumentationEntryStore.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
 	// Test case 3: Child not found
@@ -111,17 +135,25 @@ func TestCreateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
 			ChildID:                99, // Non-existent child
 			TeacherID:              1,
 			CategoryID:             1,
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Test observation",
 		}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(nil, data.ErrNotFound).Once()
 
 		createdEntry, err := service.CreateDocumentationEntry(logger, ctx, entry)
 
@@ -129,9 +161,9 @@ func TestCreateDocumentationEntry(t *testing.T) {
 		assert.Contains(t, err.Error(), "child not found")
 		assert.Nil(t, createdEntry)
 		mockChildStore.AssertExpectations(t)
-		mockTeacherStore.AssertNotCalled(t, "GetByID")
-		mockCategoryStore.AssertNotCalled(t, "GetByID")
-		mockDocumentationEntryStore.AssertNotCalled(t, "Create")
+		mockTeacherStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockCategoryStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockDocumentationEntryStore.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
 	// Test case 4: Teacher not found
@@ -149,19 +181,27 @@ func TestCreateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
 			ChildID:                1,
 			TeacherID:              99, // Non-existent teacher
 			CategoryID:             1,
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Test observation",
 		}
 		expectedChild := &models.Child{ID: 1}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", entry.TeacherID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, entry.TeacherID).Return(nil, data.ErrNotFound).Once()
 
 		createdEntry, err := service.CreateDocumentationEntry(logger, ctx, entry)
 
@@ -170,8 +210,8 @@ func TestCreateDocumentationEntry(t *testing.T) {
 		assert.Nil(t, createdEntry)
 		mockChildStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
-		mockCategoryStore.AssertNotCalled(t, "GetByID")
-		mockDocumentationEntryStore.AssertNotCalled(t, "Create")
+		mockCategoryStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockDocumentationEntryStore.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
 	// Test case 5: Category not found
@@ -189,21 +229,29 @@ func TestCreateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
 			ChildID:                1,
 			TeacherID:              1,
 			CategoryID:             99, // Non-existent category
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Test observation",
 		}
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", entry.TeacherID).Return(expectedTeacher, nil).Once()
-		mockCategoryStore.On("GetByID", entry.CategoryID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, entry.TeacherID).Return(expectedTeacher, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, entry.CategoryID).Return(nil, data.ErrNotFound).Once()
 
 		createdEntry, err := service.CreateDocumentationEntry(logger, ctx, entry)
 
@@ -213,7 +261,7 @@ func TestCreateDocumentationEntry(t *testing.T) {
 		mockChildStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
 		mockCategoryStore.AssertExpectations(t)
-		mockDocumentationEntryStore.AssertNotCalled(t, "Create")
+		mockDocumentationEntryStore.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
 	// Test case 6: Internal error during store creation
@@ -231,23 +279,31 @@ func TestCreateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
 			ChildID:                1,
 			TeacherID:              1,
 			CategoryID:             1,
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Test observation",
 		}
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 		expectedCategory := &models.Category{ID: 1}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", entry.TeacherID).Return(expectedTeacher, nil).Once()
-		mockCategoryStore.On("GetByID", entry.CategoryID).Return(expectedCategory, nil).Once()
-		mockDocumentationEntryStore.On("Create", mock.AnythingOfType("*models.DocumentationEntry")).Return(0, errors.New("db error")).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, entry.TeacherID).Return(expectedTeacher, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, entry.CategoryID).Return(expectedCategory, nil).Once()
+		mockDocumentationEntryStore.On("Create", mock.Anything, mock.AnythingOfType("*models.DocumentationEntry")).Return(0, errors.New("db error")).Once()
 
 		createdEntry, err := service.CreateDocumentationEntry(logger, ctx, entry)
 
@@ -275,6 +331,14 @@ func TestGetDocumentationEntryByID(t *testing.T) {
 		mockCategoryStore,
 		mockUserStore,
 		mockKitaMasterdataStore,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
 	)
 
 	logger := logrus.NewEntry(logrus.New())
@@ -284,7 +348,7 @@ func TestGetDocumentationEntryByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		entryID := 1
 		expectedEntry := &models.DocumentationEntry{ID: entryID, ObservationDescription: "Test Content"}
-		mockDocumentationEntryStore.On("GetByID", entryID).Return(expectedEntry, nil).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, entryID).Return(expectedEntry, nil).Once()
 
 		entry, err := service.GetDocumentationEntryByID(logger, ctx, entryID)
 
@@ -298,7 +362,7 @@ func TestGetDocumentationEntryByID(t *testing.T) {
 	// Test case 2: Entry not found
 	t.Run("not found", func(t *testing.T) {
 		entryID := 99
-		mockDocumentationEntryStore.On("GetByID", entryID).Return(nil, data.ErrNotFound).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, entryID).Return(nil, data.ErrNotFound).Once()
 
 		entry, err := service.GetDocumentationEntryByID(logger, ctx, entryID)
 
@@ -311,7 +375,7 @@ func TestGetDocumentationEntryByID(t *testing.T) {
 	// Test case 3: Internal error
 	t.Run("internal error", func(t *testing.T) {
 		entryID := 1
-		mockDocumentationEntryStore.On("GetByID", entryID).Return(nil, errors.New("db error")).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, entryID).Return(nil, errors.New("db error")).Once()
 
 		entry, err := service.GetDocumentationEntryByID(logger, ctx, entryID)
 
@@ -341,6 +405,14 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
@@ -348,17 +420,17 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			ChildID:                1,
 			TeacherID:              1,
 			CategoryID:             1,
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Updated observation",
 		}
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 		expectedCategory := &models.Category{ID: 1}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", entry.TeacherID).Return(expectedTeacher, nil).Once()
-		mockCategoryStore.On("GetByID", entry.CategoryID).Return(expectedCategory, nil).Once()
-		mockDocumentationEntryStore.On("Update", mock.AnythingOfType("*models.DocumentationEntry")).Return(nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, entry.TeacherID).Return(expectedTeacher, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, entry.CategoryID).Return(expectedCategory, nil).Once()
+		mockDocumentationEntryStore.On("Update", mock.Anything, mock.AnythingOfType("*models.DocumentationEntry")).Return(nil).Once()
 
 		err := service.UpdateDocumentationEntry(logger, ctx, entry)
 
@@ -384,6 +456,14 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
@@ -395,11 +475,11 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 		err := service.UpdateDocumentationEntry(logger, ctx, entry)
 
 		assert.Error(t, err)
-		assert.Equal(t, services.ErrInvalidInput, err)
-		mockChildStore.AssertNotCalled(t, "GetByID")
-		mockTeacherStore.AssertNotCalled(t, "GetByID")
-		mockCategoryStore.AssertNotCalled(t, "GetByID")
-		mockDocumentationEntryStore.AssertNotCalled(t, "Update")
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		mockChildStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockTeacherStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockCategoryStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockDocumentationEntryStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 3: Child not found
@@ -417,6 +497,14 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
@@ -424,20 +512,20 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			ChildID:                99, // Non-existent child
 			TeacherID:              1,
 			CategoryID:             1,
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Updated observation",
 		}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(nil, data.ErrNotFound).Once()
 
 		err := service.UpdateDocumentationEntry(logger, ctx, entry)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "child not found")
 		mockChildStore.AssertExpectations(t)
-		mockTeacherStore.AssertNotCalled(t, "GetByID")
-		mockCategoryStore.AssertNotCalled(t, "GetByID")
-		mockDocumentationEntryStore.AssertNotCalled(t, "Update")
+		mockTeacherStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockCategoryStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockDocumentationEntryStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 4: Teacher not found
@@ -455,6 +543,14 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
@@ -462,13 +558,13 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			ChildID:                1,
 			TeacherID:              99, // Non-existent teacher
 			CategoryID:             1,
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Updated observation",
 		}
 		expectedChild := &models.Child{ID: 1}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", entry.TeacherID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, entry.TeacherID).Return(nil, data.ErrNotFound).Once()
 
 		err := service.UpdateDocumentationEntry(logger, ctx, entry)
 
@@ -476,8 +572,8 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 		assert.Contains(t, err.Error(), "teacher not found")
 		mockChildStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
-		mockCategoryStore.AssertNotCalled(t, "GetByID")
-		mockDocumentationEntryStore.AssertNotCalled(t, "Update")
+		mockCategoryStore.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockDocumentationEntryStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 5: Category not found
@@ -495,6 +591,14 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
@@ -502,15 +606,15 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			ChildID:                1,
 			TeacherID:              1,
 			CategoryID:             99, // Non-existent category
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Test observation",
 		}
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", entry.TeacherID).Return(expectedTeacher, nil).Once()
-		mockCategoryStore.On("GetByID", entry.CategoryID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, entry.TeacherID).Return(expectedTeacher, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, entry.CategoryID).Return(nil, data.ErrNotFound).Once()
 
 		err := service.UpdateDocumentationEntry(logger, ctx, entry)
 
@@ -519,7 +623,7 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 		mockChildStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
 		mockCategoryStore.AssertExpectations(t)
-		mockDocumentationEntryStore.AssertNotCalled(t, "Update")
+		mockDocumentationEntryStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 6: Entry not found during update
@@ -537,6 +641,14 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
@@ -544,17 +656,17 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			ChildID:                1,
 			TeacherID:              1,
 			CategoryID:             1,
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Updated observation",
 		}
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 		expectedCategory := &models.Category{ID: 1}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", entry.TeacherID).Return(expectedTeacher, nil).Once()
-		mockCategoryStore.On("GetByID", entry.CategoryID).Return(expectedCategory, nil).Once()
-		mockDocumentationEntryStore.On("Update", mock.AnythingOfType("*models.DocumentationEntry")).Return(data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, entry.TeacherID).Return(expectedTeacher, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, entry.CategoryID).Return(expectedCategory, nil).Once()
+		mockDocumentationEntryStore.On("Update", mock.Anything, mock.AnythingOfType("*models.DocumentationEntry")).Return(data.ErrNotFound).Once()
 
 		err := service.UpdateDocumentationEntry(logger, ctx, entry)
 
@@ -581,6 +693,14 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			mockCategoryStore,
 			mockUserStore,
 			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
 		)
 
 		entry := &models.DocumentationEntry{
@@ -588,17 +708,17 @@ func TestUpdateDocumentationEntry(t *testing.T) {
 			ChildID:                1,
 			TeacherID:              1,
 			CategoryID:             1,
-			ObservationDate:        time.Now().Add(-time.Hour),
+			ObservationDate:        models.Today(time.Now()),
 			ObservationDescription: "Updated observation",
 		}
 		expectedChild := &models.Child{ID: 1}
 		expectedTeacher := &models.Teacher{ID: 1}
 		expectedCategory := &models.Category{ID: 1}
 
-		mockChildStore.On("GetByID", entry.ChildID).Return(expectedChild, nil).Once()
-		mockTeacherStore.On("GetByID", entry.TeacherID).Return(expectedTeacher, nil).Once()
-		mockCategoryStore.On("GetByID", entry.CategoryID).Return(expectedCategory, nil).Once()
-		mockDocumentationEntryStore.On("Update", mock.AnythingOfType("*models.DocumentationEntry")).Return(errors.New("db error")).Once()
+		mockChildStore.On("GetByID", mock.Anything, entry.ChildID).Return(expectedChild, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, entry.TeacherID).Return(expectedTeacher, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, entry.CategoryID).Return(expectedCategory, nil).Once()
+		mockDocumentationEntryStore.On("Update", mock.Anything, mock.AnythingOfType("*models.DocumentationEntry")).Return(errors.New("db error")).Once()
 
 		err := service.UpdateDocumentationEntry(logger, ctx, entry)
 
@@ -625,6 +745,14 @@ func TestDeleteDocumentationEntry(t *testing.T) {
 		mockCategoryStore,
 		mockUserStore,
 		mockKitaMasterdataStore,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
 	)
 
 	logger := logrus.NewEntry(logrus.New())
@@ -633,7 +761,7 @@ func TestDeleteDocumentationEntry(t *testing.T) {
 	// Test case 1: Successful deletion
 	t.Run("success", func(t *testing.T) {
 		entryID := 1
-		mockDocumentationEntryStore.On("Delete", entryID).Return(nil).Once()
+		mockDocumentationEntryStore.On("Delete", mock.Anything, entryID).Return(nil).Once()
 
 		err := service.DeleteDocumentationEntry(logger, ctx, entryID)
 
@@ -644,7 +772,7 @@ func TestDeleteDocumentationEntry(t *testing.T) {
 	// Test case 2: Entry not found
 	t.Run("not found", func(t *testing.T) {
 		entryID := 99
-		mockDocumentationEntryStore.On("Delete", entryID).Return(data.ErrNotFound).Once()
+		mockDocumentationEntryStore.On("Delete", mock.Anything, entryID).Return(data.ErrNotFound).Once()
 
 		err := service.DeleteDocumentationEntry(logger, ctx, entryID)
 
@@ -656,7 +784,7 @@ func TestDeleteDocumentationEntry(t *testing.T) {
 	// Test case 3: Internal error
 	t.Run("internal error", func(t *testing.T) {
 		entryID := 1
-		mockDocumentationEntryStore.On("Delete", entryID).Return(errors.New("db error")).Once()
+		mockDocumentationEntryStore.On("Delete", mock.Anything, entryID).Return(errors.New("db error")).Once()
 
 		err := service.DeleteDocumentationEntry(logger, ctx, entryID)
 
@@ -680,6 +808,14 @@ func TestGetAllDocumentationForChild(t *testing.T) {
 		mockCategoryStore,
 		mockUserStore,
 		mockKitaMasterdataStore,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
 	)
 
 	logger := logrus.NewEntry(logrus.New())
@@ -690,13 +826,13 @@ func TestGetAllDocumentationForChild(t *testing.T) {
 		childID := 1
 		expectedChild := &models.Child{ID: childID}
 		expectedEntries := []models.DocumentationEntry{
-			{ID: 1, ChildID: childID, CategoryID: 1, ObservationDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), ObservationDescription: "Entry 1"},
-			{ID: 2, ChildID: childID, CategoryID: 2, ObservationDate: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), ObservationDescription: "Entry 2"},
+			{ID: 1, ChildID: childID, CategoryID: 1, ObservationDate: models.NewDate(2023, 1, 1), ObservationDescription: "Entry 1"},
+			{ID: 2, ChildID: childID, CategoryID: 2, ObservationDate: models.NewDate(2023, 1, 2), ObservationDescription: "Entry 2"},
 		}
-		mockChildStore.On("GetByID", childID).Return(expectedChild, nil).Once()
-		mockDocumentationEntryStore.On("GetAllForChild", childID).Return(expectedEntries, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(expectedChild, nil).Once()
+		mockDocumentationEntryStore.On("List", mock.Anything, models.ListQuery{}.Where("child_id", models.OperatorEqual, childID)).Return(expectedEntries, nil).Once()
 
-		entries, err := service.GetAllDocumentationForChild(logger, ctx, childID)
+		entries, err := service.GetAllDocumentationForChild(logger, ctx, childID, models.ListQuery{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, entries)
@@ -708,39 +844,39 @@ func TestGetAllDocumentationForChild(t *testing.T) {
 	// Test case 2: Child not found
 	t.Run("child not found", func(t *testing.T) {
 		childID := 99
-		mockChildStore.On("GetByID", childID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(nil, data.ErrNotFound).Once()
 
-		entries, err := service.GetAllDocumentationForChild(logger, ctx, childID)
+		entries, err := service.GetAllDocumentationForChild(logger, ctx, childID, models.ListQuery{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "child not found")
 		assert.Nil(t, entries)
 		mockChildStore.AssertExpectations(t)
-		mockDocumentationEntryStore.AssertNotCalled(t, "GetAllForChild")
+		mockDocumentationEntryStore.AssertNotCalled(t, "List", mock.Anything)
 	})
 
 	// Test case 3: Internal error during child fetch
 	t.Run("internal error on child fetch", func(t *testing.T) {
 		childID := 1
-		mockChildStore.On("GetByID", childID).Return(nil, errors.New("db error")).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(nil, errors.New("db error")).Once()
 
-		entries, err := service.GetAllDocumentationForChild(logger, ctx, childID)
+		entries, err := service.GetAllDocumentationForChild(logger, ctx, childID, models.ListQuery{})
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
 		assert.Nil(t, entries)
 		mockChildStore.AssertExpectations(t)
-		mockDocumentationEntryStore.AssertNotCalled(t, "GetAllForChild")
+		mockDocumentationEntryStore.AssertNotCalled(t, "List", mock.Anything)
 	})
 
 	// Test case 4: Internal error during entry fetch
 	t.Run("internal error on entry fetch", func(t *testing.T) {
 		childID := 1
 		expectedChild := &models.Child{ID: childID}
-		mockChildStore.On("GetByID", childID).Return(expectedChild, nil).Once()
-		mockDocumentationEntryStore.On("GetAllForChild", childID).Return(nil, errors.New("db error")).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(expectedChild, nil).Once()
+		mockDocumentationEntryStore.On("List", mock.Anything, models.ListQuery{}.Where("child_id", models.OperatorEqual, childID)).Return(nil, errors.New("db error")).Once()
 
-		entries, err := service.GetAllDocumentationForChild(logger, ctx, childID)
+		entries, err := service.GetAllDocumentationForChild(logger, ctx, childID, models.ListQuery{})
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -750,6 +886,105 @@ func TestGetAllDocumentationForChild(t *testing.T) {
 	})
 }
 
+func TestCountPendingDocumentation(t *testing.T) {
+	mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+	service := services.NewDocumentationEntryService(
+		mockDocumentationEntryStore,
+		new(datamocks.MockChildStore),
+		new(datamocks.MockTeacherStore),
+		new(datamocks.MockCategoryStore),
+		new(datamocks.MockUserStore),
+		new(datamocks.MockKitaMasterdataStore),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
+	)
+
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	query := models.ListQuery{}.Where("child_id", models.OperatorEqual, 1)
+
+	t.Run("success", func(t *testing.T) {
+		mockDocumentationEntryStore.On("Count", mock.Anything, query.Where("is_approved", models.OperatorEqual, false)).Return(4, nil).Once()
+
+		count, err := service.CountPendingDocumentation(logger, ctx, query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, count)
+		mockDocumentationEntryStore.AssertExpectations(t)
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		mockDocumentationEntryStore.On("Count", mock.Anything, query.Where("is_approved", models.OperatorEqual, false)).Return(0, errors.New("db error")).Once()
+
+		_, err := service.CountPendingDocumentation(logger, ctx, query)
+
+		assert.Equal(t, services.ErrInternal, err)
+		mockDocumentationEntryStore.AssertExpectations(t)
+	})
+}
+
+func TestWaitForPendingDocumentationChange(t *testing.T) {
+	mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+	service := services.NewDocumentationEntryService(
+		mockDocumentationEntryStore,
+		new(datamocks.MockChildStore),
+		new(datamocks.MockTeacherStore),
+		new(datamocks.MockCategoryStore),
+		new(datamocks.MockUserStore),
+		new(datamocks.MockKitaMasterdataStore),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
+	)
+
+	logger := logrus.NewEntry(logrus.New())
+	pending := models.ListQuery{}.Where("is_approved", models.OperatorEqual, false)
+
+	t.Run("returns at once if the count differs", func(t *testing.T) {
+		mockDocumentationEntryStore.On("Count", mock.Anything, pending).Return(5, nil).Once()
+
+		count, err := service.WaitForPendingDocumentationChange(logger, context.Background(), models.ListQuery{}, 4, time.Minute)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, count)
+		mockDocumentationEntryStore.AssertExpectations(t)
+	})
+
+	t.Run("returns the unchanged count after the timeout", func(t *testing.T) {
+		mockDocumentationEntryStore.On("Count", mock.Anything, pending).Return(4, nil)
+		defer func() { mockDocumentationEntryStore.ExpectedCalls = nil }()
+
+		start := time.Now()
+		count, err := service.WaitForPendingDocumentationChange(logger, context.Background(), models.ListQuery{}, 4, 50*time.Millisecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, count)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		mockDocumentationEntryStore.On("Count", mock.Anything, pending).Return(4, nil)
+		defer func() { mockDocumentationEntryStore.ExpectedCalls = nil }()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := service.WaitForPendingDocumentationChange(logger, ctx, models.ListQuery{}, 4, time.Minute)
+
+		assert.Equal(t, services.ErrCanceled, err)
+	})
+}
+
 func TestApproveDocumentationEntry(t *testing.T) {
 	mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
 	mockChildStore := new(datamocks.MockChildStore)
@@ -764,6 +999,14 @@ func TestApproveDocumentationEntry(t *testing.T) {
 		mockCategoryStore,
 		mockUserStore,
 		mockKitaMasterdataStore,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
 	)
 
 	logger := logrus.NewEntry(logrus.New())
@@ -776,11 +1019,11 @@ func TestApproveDocumentationEntry(t *testing.T) {
 		existingEntry := &models.DocumentationEntry{ID: entryID, IsApproved: false}
 		approvingUser := &models.Teacher{ID: approvedByTeacherID}
 
-		mockDocumentationEntryStore.On("GetByID", entryID).Return(existingEntry, nil).Once()
-		mockTeacherStore.On("GetByID", approvedByTeacherID).Return(approvingUser, nil).Once()
-		mockDocumentationEntryStore.On("ApproveEntry", entryID, approvedByTeacherID).Return(nil).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, entryID).Return(existingEntry, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, approvedByTeacherID).Return(approvingUser, nil).Once()
+		mockDocumentationEntryStore.On("ApproveEntry", mock.Anything, entryID, approvedByTeacherID, mock.Anything).Return(nil).Once()
 
-		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByTeacherID)
+		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByTeacherID, approvedByTeacherID, nil)
 
 		assert.NoError(t, err)
 		mockDocumentationEntryStore.AssertExpectations(t)
@@ -791,28 +1034,28 @@ func TestApproveDocumentationEntry(t *testing.T) {
 	t.Run("entry not found", func(t *testing.T) {
 		entryID := 99
 		approvedByUserID := 1
-		mockDocumentationEntryStore.On("GetByID", entryID).Return(nil, data.ErrNotFound).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, entryID).Return(nil, data.ErrNotFound).Once()
 
-		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByUserID)
+		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByUserID, approvedByUserID, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
 		mockDocumentationEntryStore.AssertExpectations(t)
-		mockUserStore.AssertNotCalled(t, "GetByID")
+		mockUserStore.AssertNotCalled(t, "GetByID", mock.Anything)
 	})
 
 	// Test case 3: Internal error during entry fetch
 	t.Run("internal error on entry fetch", func(t *testing.T) {
 		entryID := 1
 		approvedByUserID := 1
-		mockDocumentationEntryStore.On("GetByID", entryID).Return(nil, errors.New("db error")).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, entryID).Return(nil, errors.New("db error")).Once()
 
-		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByUserID)
+		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByUserID, approvedByUserID, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
 		mockDocumentationEntryStore.AssertExpectations(t)
-		mockUserStore.AssertNotCalled(t, "GetByID")
+		mockUserStore.AssertNotCalled(t, "GetByID", mock.Anything)
 	})
 
 	// Test case 4: Approving user not found
@@ -821,16 +1064,15 @@ func TestApproveDocumentationEntry(t *testing.T) {
 		approvedByTeacherID := 99
 		existingEntry := &models.DocumentationEntry{ID: entryID, IsApproved: false}
 
-		mockDocumentationEntryStore.On("GetByID", entryID).Return(existingEntry, nil).Once()
-		mockTeacherStore.On("GetByID", approvedByTeacherID).Return(nil, data.ErrNotFound).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, entryID).Return(existingEntry, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, approvedByTeacherID).Return(nil, data.ErrNotFound).Once()
 
-		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByTeacherID)
+		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByTeacherID, approvedByTeacherID, nil)
 
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "approving teacher not found")
+		assert.Equal(t, services.ErrTeacherNotFound, err)
 		mockDocumentationEntryStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
-		mockDocumentationEntryStore.AssertNotCalled(t, "ApproveEntry")
+		mockDocumentationEntryStore.AssertNotCalled(t, "ApproveEntry", mock.Anything)
 	})
 
 	// Test case 5: Internal error during teacher fetch
@@ -839,16 +1081,16 @@ func TestApproveDocumentationEntry(t *testing.T) {
 		approvedByTeacherID := 1
 		existingEntry := &models.DocumentationEntry{ID: entryID, IsApproved: false}
 
-		mockDocumentationEntryStore.On("GetByID", entryID).Return(existingEntry, nil).Once()
-		mockTeacherStore.On("GetByID", approvedByTeacherID).Return(nil, errors.New("db error")).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, entryID).Return(existingEntry, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, approvedByTeacherID).Return(nil, errors.New("db error")).Once()
 
-		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByTeacherID)
+		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByTeacherID, approvedByTeacherID, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
 		mockDocumentationEntryStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
-		mockDocumentationEntryStore.AssertNotCalled(t, "ApproveEntry")
+		mockDocumentationEntryStore.AssertNotCalled(t, "ApproveEntry", mock.Anything)
 	})
 
 	// Test case 6: Entry already approved
@@ -858,16 +1100,16 @@ func TestApproveDocumentationEntry(t *testing.T) {
 		existingEntry := &models.DocumentationEntry{ID: entryID, IsApproved: true} // Already approved
 		approvingTeacher := &models.Teacher{ID: approvedByTeacherID}
 
-		mockDocumentationEntryStore.On("GetByID", entryID).Return(existingEntry, nil).Once()
-		mockTeacherStore.On("GetByID", approvedByTeacherID).Return(approvingTeacher, nil).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, entryID).Return(existingEntry, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, approvedByTeacherID).Return(approvingTeacher, nil).Once()
 
-		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByTeacherID)
+		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByTeacherID, approvedByTeacherID, nil)
 
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "documentation entry is already approved")
+		assert.Equal(t, services.ErrEntryAlreadyApproved, err)
+		assert.ErrorIs(t, err, services.ErrInvalidStateTransition)
 		mockDocumentationEntryStore.AssertExpectations(t)
 		mockTeacherStore.AssertExpectations(t)
-		mockDocumentationEntryStore.AssertNotCalled(t, "ApproveEntry")
+		mockDocumentationEntryStore.AssertNotCalled(t, "ApproveEntry", mock.Anything)
 	})
 
 	// Test case 7: Internal error during approval
@@ -877,11 +1119,11 @@ func TestApproveDocumentationEntry(t *testing.T) {
 		existingEntry := &models.DocumentationEntry{ID: entryID, IsApproved: false}
 		approvingTeacher := &models.Teacher{ID: approvedByTeacherID}
 
-		mockDocumentationEntryStore.On("GetByID", entryID).Return(existingEntry, nil).Once()
-		mockTeacherStore.On("GetByID", approvedByTeacherID).Return(approvingTeacher, nil).Once()
-		mockDocumentationEntryStore.On("ApproveEntry", entryID, approvedByTeacherID).Return(errors.New("db error")).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, entryID).Return(existingEntry, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, approvedByTeacherID).Return(approvingTeacher, nil).Once()
+		mockDocumentationEntryStore.On("ApproveEntry", mock.Anything, entryID, approvedByTeacherID, mock.Anything).Return(errors.New("db error")).Once()
 
-		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByTeacherID)
+		err := service.ApproveDocumentationEntry(logger, ctx, entryID, approvedByTeacherID, approvedByTeacherID, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -904,6 +1146,14 @@ func TestGenerateChildReport(t *testing.T) {
 		mockCategoryStore,
 		mockUserStore,
 		mockKitaMasterdataStore,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
 	)
 
 	logger := logrus.NewEntry(logrus.New())
@@ -920,8 +1170,8 @@ func TestGenerateChildReport(t *testing.T) {
 			ExpectedSchoolEnrollment: timePtr(time.Now().AddDate(1, 0, 0)),
 		}
 		expectedEntries := []models.DocumentationEntry{
-			{ID: 1, ChildID: childID, CategoryID: 1, ObservationDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), ObservationDescription: "Entry 1"},
-			{ID: 2, ChildID: childID, CategoryID: 2, ObservationDate: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), ObservationDescription: "Entry 2"},
+			{ID: 1, ChildID: childID, CategoryID: 1, ObservationDate: models.NewDate(2023, 1, 1), ObservationDescription: "Entry 1"},
+			{ID: 2, ChildID: childID, CategoryID: 2, ObservationDate: models.NewDate(2023, 1, 2), ObservationDescription: "Entry 2"},
 		}
 		expectedMasterdata := &models.KitaMasterdata{
 			Name:        "Test Kita",
@@ -933,11 +1183,12 @@ func TestGenerateChildReport(t *testing.T) {
 			Email:       "test@example.com",
 		}
 
-		mockChildStore.On("GetByID", childID).Return(expectedChild, nil).Once()
-		mockDocumentationEntryStore.On("GetAllForChild", childID).Return(expectedEntries, nil).Once()
-		mockKitaMasterdataStore.On("Get").Return(expectedMasterdata, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(expectedChild, nil).Once()
+		mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, childID).Return(expectedEntries, nil).Once()
+		mockKitaMasterdataStore.On("Get", mock.Anything).Return(expectedMasterdata, nil).Once()
+		mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.Anything).Return(nil).Once()
 
-		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{})
+		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{}, nil, nil, nil, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, reportBytes)
@@ -967,11 +1218,12 @@ func TestGenerateChildReport(t *testing.T) {
 			Email:       "test@example.com",
 		}
 
-		mockChildStore.On("GetByID", childID).Return(expectedChild, nil).Once()
-		mockDocumentationEntryStore.On("GetAllForChild", childID).Return(expectedEntries, nil).Once()
-		mockKitaMasterdataStore.On("Get").Return(expectedMasterdata, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(expectedChild, nil).Once()
+		mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, childID).Return(expectedEntries, nil).Once()
+		mockKitaMasterdataStore.On("Get", mock.Anything).Return(expectedMasterdata, nil).Once()
+		mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.Anything).Return(nil).Once()
 
-		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{})
+		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{}, nil, nil, nil, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, reportBytes)
@@ -983,39 +1235,39 @@ func TestGenerateChildReport(t *testing.T) {
 	// Test case 3: Child not found
 	t.Run("child not found", func(t *testing.T) {
 		childID := 99
-		mockChildStore.On("GetByID", childID).Return(nil, data.ErrNotFound).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(nil, data.ErrNotFound).Once()
 
-		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{})
+		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{}, nil, nil, nil, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 		assert.Nil(t, reportBytes)
 		mockChildStore.AssertExpectations(t)
-		mockDocumentationEntryStore.AssertNotCalled(t, "GetAllForChild")
+		mockDocumentationEntryStore.AssertNotCalled(t, "GetAllForChild", mock.Anything)
 	})
 
 	// Test case 4: Internal error during child fetch
 	t.Run("internal error on child fetch", func(t *testing.T) {
 		childID := 1
-		mockChildStore.On("GetByID", childID).Return(nil, errors.New("db error")).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(nil, errors.New("db error")).Once()
 
-		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{})
+		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{}, nil, nil, nil, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
 		assert.Nil(t, reportBytes)
 		mockChildStore.AssertExpectations(t)
-		mockDocumentationEntryStore.AssertNotCalled(t, "GetAllForChild")
+		mockDocumentationEntryStore.AssertNotCalled(t, "GetAllForChild", mock.Anything)
 	})
 
 	// Test case 5: Internal error during entries fetch
 	t.Run("internal error on entries fetch", func(t *testing.T) {
 		childID := 1
 		expectedChild := &models.Child{ID: childID}
-		mockChildStore.On("GetByID", childID).Return(expectedChild, nil).Once()
-		mockDocumentationEntryStore.On("GetAllForChild", childID).Return(nil, errors.New("db error")).Once()
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(expectedChild, nil).Once()
+		mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, childID).Return(nil, errors.New("db error")).Once()
 
-		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{})
+		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{}, nil, nil, nil, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -1023,4 +1275,851 @@ func TestGenerateChildReport(t *testing.T) {
 		mockChildStore.AssertExpectations(t)
 		mockDocumentationEntryStore.AssertExpectations(t)
 	})
+
+	t.Run("canceled request is not archived", func(t *testing.T) {
+		mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockChildStore := new(datamocks.MockChildStore)
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		service := services.NewDocumentationEntryService(
+			mockDocumentationEntryStore,
+			mockChildStore,
+			mockTeacherStore,
+			mockCategoryStore,
+			mockUserStore,
+			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
+		)
+		childID := 1
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(&models.Child{ID: childID}, nil).Once()
+		mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, childID).Return([]models.DocumentationEntry{
+			{ID: 1, ChildID: childID, CategoryID: 1, ObservationDescription: "Entry 1"},
+		}, nil).Once()
+		mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Test Kita"}, nil).Once()
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		reportBytes, err := service.GenerateChildReport(logger, canceledCtx, childID, []models.Assignment{}, nil, nil, nil, nil)
+
+		assert.Equal(t, services.ErrCanceled, err)
+		assert.Nil(t, reportBytes)
+		mockDocumentationEntryStore.AssertNotCalled(t, "RecordReport", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejected while too many reports are generated", func(t *testing.T) {
+		mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockChildStore := new(datamocks.MockChildStore)
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		pool := services.NewDocumentPool(1, 64<<20, 0)
+		service := services.NewDocumentationEntryService(
+			mockDocumentationEntryStore,
+			mockChildStore,
+			mockTeacherStore,
+			mockCategoryStore,
+			mockUserStore,
+			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			pool,
+			nil,
+			clock.System{},
+		)
+		childID := 1
+		mockChildStore.On("GetByID", mock.Anything, childID).Return(&models.Child{ID: childID}, nil).Once()
+		mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, childID).Return([]models.DocumentationEntry{}, nil).Once()
+		mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Test Kita"}, nil).Once()
+		release, err := pool.Acquire(ctx, 1<<20)
+		assert.NoError(t, err)
+		defer release()
+
+		reportBytes, err := service.GenerateChildReport(logger, ctx, childID, []models.Assignment{}, nil, nil, nil, nil)
+
+		assert.ErrorIs(t, err, services.ErrBusy)
+		assert.Nil(t, reportBytes)
+		mockDocumentationEntryStore.AssertNotCalled(t, "RecordReport", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGenerateChildReportWithRedaction(t *testing.T) {
+	mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+	mockChildStore := new(datamocks.MockChildStore)
+	mockTeacherStore := new(datamocks.MockTeacherStore)
+	mockCategoryStore := new(datamocks.MockCategoryStore)
+	mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+	service := services.NewDocumentationEntryService(
+		mockDocumentationEntryStore,
+		mockChildStore,
+		mockTeacherStore,
+		mockCategoryStore,
+		new(datamocks.MockUserStore),
+		mockKitaMasterdataStore,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
+	)
+
+	childID := 1
+	mockChildStore.On("GetByID", mock.Anything, childID).Return(&models.Child{ID: childID, FirstName: "Report", LastName: "Secretname"}, nil).Once()
+	mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, childID).Return([]models.DocumentationEntry{
+		{ID: 1, ChildID: childID, CategoryID: 1, IsApproved: true, ObservationDate: models.NewDate(2023, 1, 1), ObservationDescription: "Visible entry"},
+		{ID: 2, ChildID: childID, CategoryID: 2, IsApproved: true, ObservationDate: models.NewDate(2023, 1, 2), ObservationDescription: "Excluded entry"},
+	}, nil).Once()
+	mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Test Kita", Street: "Hidden Street"}, nil).Once()
+	mockCategoryStore.On("GetByID", mock.Anything, 1).Return(&models.Category{ID: 1, Name: "Sprache"}, nil).Once()
+	mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.MatchedBy(func(report *models.GeneratedReport) bool {
+		return slices.Equal(report.EntryIDs, []int{1}) && report.FileName == "Bildungsdokumentation_Report_1.docx"
+	})).Return(nil).Once()
+
+	redaction := &models.RedactionProfile{
+		Name:                "Schule",
+		HideChildLastName:   true,
+		HideTeacherNames:    true,
+		HideKitaContact:     true,
+		ExcludedCategoryIDs: []int{2},
+	}
+	assignments := []models.Assignment{{ChildID: childID, TeacherID: 7, StartDate: time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)}}
+
+	reportBytes, err := service.GenerateChildReport(logrus.NewEntry(logrus.New()), context.Background(), childID, assignments, redaction, nil, nil, nil)
+	assert.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(reportBytes), int64(len(reportBytes)))
+	assert.NoError(t, err)
+	documentFile, err := reader.Open("word/document.xml")
+	assert.NoError(t, err)
+	documentXML, err := io.ReadAll(documentFile)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(documentXML), "Visible entry")
+	assert.NotContains(t, string(documentXML), "Excluded entry")
+	assert.NotContains(t, string(documentXML), "Secretname")
+	assert.NotContains(t, string(documentXML), "Hidden Street")
+	mockTeacherStore.AssertNotCalled(t, "GetByID", mock.Anything, 7)
+	mockCategoryStore.AssertNotCalled(t, "GetByID", mock.Anything, 2)
+}
+
+func TestGenerateChildReportWithSupports(t *testing.T) {
+	consent := "Einwilligung vom 03.02.2025"
+	notes := "Termine dienstags, Mutter holt ab"
+	end := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	supports := []models.ChildSupport{
+		{ID: 1, ProviderName: "Logopädie am Markt", ProviderType: models.SupportProviderTypeSpeechTherapy, ConsentReference: &consent,
+			StartDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), AppointmentNotes: &notes},
+		{ID: 2, ProviderName: "Frühförderstelle Nord", ProviderType: models.SupportProviderTypeEarlyIntervention, ConsentReference: &consent,
+			StartDate: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), EndDate: &end},
+		{ID: 3, ProviderName: "Ergotherapie ohne Einwilligung", ProviderType: models.SupportProviderTypeOccupationalTherapy,
+			StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	generate := func(redaction *models.RedactionProfile) string {
+		mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockChildStore := new(datamocks.MockChildStore)
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		service := services.NewDocumentationEntryService(
+			mockDocumentationEntryStore,
+			mockChildStore,
+			new(datamocks.MockTeacherStore),
+			new(datamocks.MockCategoryStore),
+			new(datamocks.MockUserStore),
+			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
+		)
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1, FirstName: "Anna", LastName: "Müller"}, nil).Once()
+		mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{}, nil).Once()
+		mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Test Kita"}, nil).Once()
+		mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.Anything).Return(nil).Once()
+
+		reportBytes, err := service.GenerateChildReport(logrus.NewEntry(logrus.New()), context.Background(), 1, nil, redaction, nil, supports, nil)
+		require.NoError(t, err)
+		reader, err := zip.NewReader(bytes.NewReader(reportBytes), int64(len(reportBytes)))
+		require.NoError(t, err)
+		documentFile, err := reader.Open("word/document.xml")
+		require.NoError(t, err)
+		documentXML, err := io.ReadAll(documentFile)
+		require.NoError(t, err)
+		return string(documentXML)
+	}
+
+	t.Run("consented supports are listed", func(t *testing.T) {
+		document := generate(nil)
+		assert.Contains(t, document, "Inklusion/Förderung")
+		assert.Contains(t, document, "Logopädie: Logopädie am Markt (seit 01.02.2025)")
+		assert.Contains(t, document, "Frühförderung: Frühförderstelle Nord (01.09.2024 - 31.07.2025)")
+		assert.NotContains(t, document, "ohne Einwilligung")
+		assert.NotContains(t, document, "dienstags", "appointment notes are internal")
+		assert.NotContains(t, document, "Einwilligung vom", "the consent reference is not printed")
+	})
+
+	t.Run("redaction profile hides supports", func(t *testing.T) {
+		document := generate(&models.RedactionProfile{Name: "Schule", HideSupportProviders: true})
+		assert.NotContains(t, document, "Inklusion/Förderung")
+		assert.NotContains(t, document, "Logopädie am Markt")
+	})
+}
+
+func TestGenerateChildReportWithPhotos(t *testing.T) {
+	mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+	mockChildStore := new(datamocks.MockChildStore)
+	mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+	service := services.NewDocumentationEntryService(
+		mockDocumentationEntryStore,
+		mockChildStore,
+		new(datamocks.MockTeacherStore),
+		new(datamocks.MockCategoryStore),
+		new(datamocks.MockUserStore),
+		mockKitaMasterdataStore,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
+	)
+	mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1, FirstName: "Anna", LastName: "Müller"}, nil).Once()
+	mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{}, nil).Once()
+	mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Test Kita"}, nil).Once()
+	mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.MatchedBy(func(report *models.GeneratedReport) bool {
+		return slices.Equal(report.Parameters.PhotoIDs, []int{7})
+	})).Return(nil).Once()
+
+	caption := "Anna beim Bauen im Sandkasten"
+	photos := []models.GalleryPhoto{{ID: 7, Caption: &caption, ContentType: "image/png", ChildIDs: []int{1}, Content: pngLogo(t, 30, 20)}}
+	reportBytes, err := service.GenerateChildReport(logrus.NewEntry(logrus.New()), context.Background(), 1, nil, nil, nil, nil, photos)
+	require.NoError(t, err)
+	mockDocumentationEntryStore.AssertExpectations(t)
+
+	reader, err := zip.NewReader(bytes.NewReader(reportBytes), int64(len(reportBytes)))
+	require.NoError(t, err)
+	documentFile, err := reader.Open("word/document.xml")
+	require.NoError(t, err)
+	documentXML, err := io.ReadAll(documentFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(documentXML), "Fotos")
+	assert.Contains(t, string(documentXML), caption)
+	assert.Contains(t, string(documentXML), "<w:drawing>")
+	_, err = reader.Open("word/media/image1.png")
+	assert.NoError(t, err)
+}
+
+func TestCreateDocumentationEntryWithStructuredData(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	maxLength := 20
+	movementCategory := &models.Category{ID: 1, Name: "Bewegung", FormSchema: &models.FormSchema{
+		Type: "object",
+		Properties: map[string]models.FormSchemaProperty{
+			"gross_motor": {Type: models.FormPropertyBoolean, Title: "Grobmotorik"},
+			"fine_motor":  {Type: models.FormPropertyBoolean, Title: "Feinmotorik"},
+			"hand":        {Type: models.FormPropertyString, Enum: []string{"links", "rechts"}, MaxLength: &maxLength},
+		},
+		Required: []string{"gross_motor"},
+	}}
+
+	newService := func(category *models.Category) (*services.DocumentationEntryServiceImpl, *datamocks.MockDocumentationEntryStore) {
+		mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockChildStore := new(datamocks.MockChildStore)
+		mockTeacherStore := new(datamocks.MockTeacherStore)
+		mockCategoryStore := new(datamocks.MockCategoryStore)
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil)
+		mockTeacherStore.On("GetByID", mock.Anything, 1).Return(&models.Teacher{ID: 1}, nil)
+		mockCategoryStore.On("GetByID", mock.Anything, category.ID).Return(category, nil)
+		service := services.NewDocumentationEntryService(
+			mockDocumentationEntryStore,
+			mockChildStore,
+			mockTeacherStore,
+			mockCategoryStore,
+			new(datamocks.MockUserStore),
+			new(datamocks.MockKitaMasterdataStore),
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
+		)
+		return service, mockDocumentationEntryStore
+	}
+	newEntry := func(categoryID int, structuredData map[string]any) *models.DocumentationEntry {
+		return &models.DocumentationEntry{
+			ChildID:                1,
+			TeacherID:              1,
+			CategoryID:             categoryID,
+			ObservationDate:        models.Today(time.Now()),
+			ObservationDescription: "Klettert sicher auf das Gerüst",
+			StructuredData:         structuredData,
+		}
+	}
+
+	t.Run("valid structured data", func(t *testing.T) {
+		service, mockDocumentationEntryStore := newService(movementCategory)
+		mockDocumentationEntryStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.DocumentationEntry) bool {
+			return entry.StructuredData["gross_motor"] == true
+		})).Return(5, nil).Once()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, 5).Return(&models.DocumentationEntry{ID: 5, ChildID: 1}, nil).Once()
+
+		created, err := service.CreateDocumentationEntry(logger, ctx, newEntry(1, map[string]any{"gross_motor": true, "hand": "links"}))
+		assert.NoError(t, err)
+		assert.Equal(t, 5, created.ID)
+		mockDocumentationEntryStore.AssertExpectations(t)
+	})
+
+	t.Run("invalid structured data", func(t *testing.T) {
+		service, mockDocumentationEntryStore := newService(movementCategory)
+
+		_, err := service.CreateDocumentationEntry(logger, ctx, newEntry(1, map[string]any{"fine_motor": "yes", "hand": "beide", "shoe_size": 30.0}))
+		var validationErr *services.ValidationError
+		if assert.ErrorAs(t, err, &validationErr) {
+			fields := make([]string, len(validationErr.Violations))
+			for i, violation := range validationErr.Violations {
+				assert.Equal(t, services.RuleStructuredData, violation.Rule)
+				fields[i] = violation.Field
+			}
+			assert.Equal(t, []string{"structured_data.gross_motor", "structured_data.fine_motor", "structured_data.hand", "structured_data.shoe_size"}, fields)
+		}
+		mockDocumentationEntryStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("structured data for a category without form", func(t *testing.T) {
+		service, mockDocumentationEntryStore := newService(&models.Category{ID: 2, Name: "Sprache"})
+
+		_, err := service.CreateDocumentationEntry(logger, ctx, newEntry(2, map[string]any{"gross_motor": true}))
+		var validationErr *services.ValidationError
+		if assert.ErrorAs(t, err, &validationErr) {
+			assert.Len(t, validationErr.Violations, 1)
+			assert.Equal(t, "structured_data", validationErr.Violations[0].Field)
+		}
+		mockDocumentationEntryStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGenerateChildReportWithStructuredData(t *testing.T) {
+	mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+	mockChildStore := new(datamocks.MockChildStore)
+	mockCategoryStore := new(datamocks.MockCategoryStore)
+	mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+	service := services.NewDocumentationEntryService(
+		mockDocumentationEntryStore,
+		mockChildStore,
+		new(datamocks.MockTeacherStore),
+		mockCategoryStore,
+		new(datamocks.MockUserStore),
+		mockKitaMasterdataStore,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
+	)
+
+	childID := 1
+	mockChildStore.On("GetByID", mock.Anything, childID).Return(&models.Child{ID: childID, FirstName: "Report", LastName: "Child"}, nil).Once()
+	mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, childID).Return([]models.DocumentationEntry{
+		{ID: 1, ChildID: childID, CategoryID: 1, IsApproved: true, ObservationDate: models.NewDate(2023, 1, 1), ObservationDescription: "Balanciert auf dem Baumstamm",
+			StructuredData: map[string]any{"gross_motor": true, "fine_motor": false}},
+	}, nil).Once()
+	mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Test Kita"}, nil).Once()
+	mockCategoryStore.On("GetByID", mock.Anything, 1).Return(&models.Category{ID: 1, Name: "Bewegung", FormSchema: &models.FormSchema{
+		Type: "object",
+		Properties: map[string]models.FormSchemaProperty{
+			"gross_motor": {Type: models.FormPropertyBoolean, Title: "Grobmotorik"},
+			"fine_motor":  {Type: models.FormPropertyBoolean, Title: "Feinmotorik"},
+		},
+	}}, nil).Once()
+	mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.Anything).Return(nil).Once()
+
+	reportBytes, err := service.GenerateChildReport(logrus.NewEntry(logrus.New()), context.Background(), childID, nil, nil, nil, nil, nil)
+	assert.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(reportBytes), int64(len(reportBytes)))
+	assert.NoError(t, err)
+	documentFile, err := reader.Open("word/document.xml")
+	assert.NoError(t, err)
+	documentXML, err := io.ReadAll(documentFile)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(documentXML), "Balanciert auf dem Baumstamm")
+	assert.Contains(t, string(documentXML), "Grobmotorik: ja")
+	assert.Contains(t, string(documentXML), "Feinmotorik: nein")
+}
+
+func TestSubmitAndRejectDocumentationEntry(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	newService := func() (*services.DocumentationEntryServiceImpl, *datamocks.MockDocumentationEntryStore, *datamocks.MockDocumentationEventStore) {
+		mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockEventStore := new(datamocks.MockDocumentationEventStore)
+		service := services.NewDocumentationEntryService(
+			mockDocumentationEntryStore,
+			new(datamocks.MockChildStore),
+			new(datamocks.MockTeacherStore),
+			new(datamocks.MockCategoryStore),
+			new(datamocks.MockUserStore),
+			new(datamocks.MockKitaMasterdataStore),
+			nil,
+			nil,
+			nil,
+			nil,
+			services.NewDocumentationEventService(mockEventStore, new(datamocks.MockChildStore), clock.System{}),
+			nil,
+			nil,
+			clock.System{},
+		)
+		return service, mockDocumentationEntryStore, mockEventStore
+	}
+	isEvent := func(eventType models.DocumentationEventType) any {
+		return mock.MatchedBy(func(event *models.DocumentationEvent) bool {
+			return event.Type == eventType && event.EntryID == 1 && event.ChildID == 2 && *event.ActorUserID == 5
+		})
+	}
+
+	t.Run("submit draft", func(t *testing.T) {
+		service, mockDocumentationEntryStore, mockEventStore := newService()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, 1).Return(&models.DocumentationEntry{ID: 1, ChildID: 2}, nil).Once()
+		mockEventStore.On("GetForEntry", mock.Anything, 1).Return([]models.DocumentationEvent{{EntryID: 1, ChildID: 2, Type: models.DocumentationEventCreated}}, nil).Once()
+		mockEventStore.On("Append", mock.Anything, isEvent(models.DocumentationEventSubmitted), mock.Anything).Return(nil).Once()
+
+		err := service.SubmitDocumentationEntry(logger, ctx, 1, 5)
+		assert.NoError(t, err)
+		mockEventStore.AssertExpectations(t)
+	})
+
+	t.Run("submit approved entry", func(t *testing.T) {
+		service, mockDocumentationEntryStore, mockEventStore := newService()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, 1).Return(&models.DocumentationEntry{ID: 1, ChildID: 2, IsApproved: true}, nil).Once()
+
+		err := service.SubmitDocumentationEntry(logger, ctx, 1, 5)
+		assert.Equal(t, services.ErrInvalidStateTransition, err)
+		mockEventStore.AssertNotCalled(t, "Append", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reject submitted entry", func(t *testing.T) {
+		service, mockDocumentationEntryStore, mockEventStore := newService()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, 1).Return(&models.DocumentationEntry{ID: 1, ChildID: 2}, nil).Once()
+		mockEventStore.On("GetForEntry", mock.Anything, 1).Return([]models.DocumentationEvent{
+			{EntryID: 1, ChildID: 2, Type: models.DocumentationEventCreated},
+			{EntryID: 1, ChildID: 2, Type: models.DocumentationEventSubmitted},
+		}, nil).Once()
+		mockEventStore.On("Append", mock.Anything, mock.MatchedBy(func(event *models.DocumentationEvent) bool {
+			return event.Type == models.DocumentationEventRejected && event.Payload.Reason == "Bitte konkreter beschreiben"
+		}), mock.Anything).Return(nil).Once()
+
+		err := service.RejectDocumentationEntry(logger, ctx, 1, 5, "Bitte konkreter beschreiben")
+		assert.NoError(t, err)
+		mockEventStore.AssertExpectations(t)
+	})
+
+	t.Run("reject draft", func(t *testing.T) {
+		service, mockDocumentationEntryStore, mockEventStore := newService()
+		mockDocumentationEntryStore.On("GetByID", mock.Anything, 1).Return(&models.DocumentationEntry{ID: 1, ChildID: 2}, nil).Once()
+		mockEventStore.On("GetForEntry", mock.Anything, 1).Return([]models.DocumentationEvent{{EntryID: 1, ChildID: 2, Type: models.DocumentationEventCreated}}, nil).Once()
+
+		err := service.RejectDocumentationEntry(logger, ctx, 1, 5, "Bitte konkreter beschreiben")
+		assert.Equal(t, services.ErrInvalidStateTransition, err)
+	})
+
+	t.Run("reject without reason", func(t *testing.T) {
+		service, _, _ := newService()
+		err := service.RejectDocumentationEntry(logger, ctx, 1, 5, "")
+		assert.Equal(t, services.ErrInvalidInput, err)
+	})
+}
+
+func TestDocumentationEntryLocking(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	newService := func() (*services.DocumentationEntryServiceImpl, *datamocks.MockDocumentationEntryStore, *datamocks.MockChildStore, *datamocks.MockCategoryStore, *datamocks.MockKitaMasterdataStore) {
+		mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockChildStore := new(datamocks.MockChildStore)
+		mockCategoryStore := new(datamocks.MockCategoryStore)
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		service := services.NewDocumentationEntryService(
+			mockDocumentationEntryStore,
+			mockChildStore,
+			new(datamocks.MockTeacherStore),
+			mockCategoryStore,
+			new(datamocks.MockUserStore),
+			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
+		)
+		return service, mockDocumentationEntryStore, mockChildStore, mockCategoryStore, mockKitaMasterdataStore
+	}
+
+	t.Run("delete locked entry", func(t *testing.T) {
+		service, mockDocumentationEntryStore, _, _, _ := newService()
+		mockDocumentationEntryStore.On("Delete", mock.Anything, 1).Return(data.ErrLocked).Once()
+
+		err := service.DeleteDocumentationEntry(logger, ctx, 1)
+		assert.Equal(t, services.ErrLocked, err)
+	})
+
+	t.Run("approved revision replaces the revised entry", func(t *testing.T) {
+		service, mockDocumentationEntryStore, mockChildStore, mockCategoryStore, mockKitaMasterdataStore := newService()
+		lockedAt := time.Now()
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1, FirstName: "Report", LastName: "Child"}, nil).Once()
+		mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{
+			{ID: 1, ChildID: 1, CategoryID: 1, IsApproved: true, LockedAt: &lockedAt, ObservationDescription: "Original"},
+			{ID: 2, ChildID: 1, CategoryID: 1, IsApproved: true, RevisionOfEntryID: intPtr(1), ObservationDescription: "Revision"},
+			{ID: 3, ChildID: 1, CategoryID: 1, RevisionOfEntryID: intPtr(2), ObservationDescription: "Unapproved revision"},
+		}, nil).Once()
+		mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Test Kita"}, nil).Once()
+		mockCategoryStore.On("GetByID", mock.Anything, 1).Return(&models.Category{ID: 1, Name: "Sprache"}, nil)
+		mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.MatchedBy(func(report *models.GeneratedReport) bool {
+			return report.ChildID == 1 && slices.Equal(report.EntryIDs, []int{2}) && len(report.Content) > 0
+		})).Return(nil).Once()
+
+		_, err := service.GenerateChildReport(logger, ctx, 1, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		mockDocumentationEntryStore.AssertExpectations(t)
+	})
+
+	t.Run("recording the report fails", func(t *testing.T) {
+		service, mockDocumentationEntryStore, mockChildStore, _, mockKitaMasterdataStore := newService()
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Once()
+		mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{}, nil).Once()
+		mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Test Kita"}, nil).Once()
+		mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+
+		_, err := service.GenerateChildReport(logger, ctx, 1, nil, nil, nil, nil, nil)
+		assert.Equal(t, services.ErrInternal, err)
+	})
+
+	t.Run("unlock unknown entry", func(t *testing.T) {
+		service, mockDocumentationEntryStore, _, _, _ := newService()
+		mockDocumentationEntryStore.On("Unlock", mock.Anything, 9).Return(data.ErrNotFound).Once()
+
+		err := service.UnlockDocumentationEntry(logger, ctx, 9, 1)
+		assert.Equal(t, services.ErrNotFound, err)
+	})
+}
+
+func TestDownloadGeneratedReport(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	newService := func() (*services.DocumentationEntryServiceImpl, *datamocks.MockDocumentationEntryStore, *datamocks.MockAuditLogStore) {
+		mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockAuditLogStore := new(datamocks.MockAuditLogStore)
+		service := services.NewDocumentationEntryService(
+			mockDocumentationEntryStore,
+			new(datamocks.MockChildStore),
+			new(datamocks.MockTeacherStore),
+			new(datamocks.MockCategoryStore),
+			new(datamocks.MockUserStore),
+			new(datamocks.MockKitaMasterdataStore),
+			nil,
+			nil,
+			services.NewAuditLogService(mockAuditLogStore),
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
+		)
+		return service, mockDocumentationEntryStore, mockAuditLogStore
+	}
+
+	t.Run("archived report", func(t *testing.T) {
+		service, mockDocumentationEntryStore, mockAuditLogStore := newService()
+		mockDocumentationEntryStore.On("GetReportByID", mock.Anything, 9).Return(&models.GeneratedReport{ID: 9, ChildID: 1, Archived: true, Content: []byte("docx")}, nil).Once()
+		mockAuditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionDownloadGeneratedReport && *entry.EntityID == 9 && *entry.ActorUserID == 5
+		})).Return(1, nil).Once()
+
+		report, err := service.DownloadGeneratedReport(logger, ctx, 1, 9, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("docx"), report.Content)
+		mockAuditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("report of another child", func(t *testing.T) {
+		service, mockDocumentationEntryStore, mockAuditLogStore := newService()
+		mockDocumentationEntryStore.On("GetReportByID", mock.Anything, 9).Return(&models.GeneratedReport{ID: 9, ChildID: 2, Archived: true}, nil).Once()
+
+		_, err := service.DownloadGeneratedReport(logger, ctx, 1, 9, 5)
+		assert.Equal(t, services.ErrNotFound, err)
+		mockAuditLogStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("report generated before the archive", func(t *testing.T) {
+		service, mockDocumentationEntryStore, _ := newService()
+		mockDocumentationEntryStore.On("GetReportByID", mock.Anything, 9).Return(&models.GeneratedReport{ID: 9, ChildID: 1}, nil).Once()
+
+		_, err := service.DownloadGeneratedReport(logger, ctx, 1, 9, 5)
+		assert.Equal(t, services.ErrNotFound, err)
+	})
+}
+
+func TestExportGeneratedReports(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newService := func() (*services.DocumentationEntryServiceImpl, *datamocks.MockDocumentationEntryStore, *datamocks.MockAuditLogStore) {
+		mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockAuditLogStore := new(datamocks.MockAuditLogStore)
+		service := services.NewDocumentationEntryService(
+			mockDocumentationEntryStore,
+			new(datamocks.MockChildStore),
+			new(datamocks.MockTeacherStore),
+			new(datamocks.MockCategoryStore),
+			new(datamocks.MockUserStore),
+			new(datamocks.MockKitaMasterdataStore),
+			nil,
+			nil,
+			services.NewAuditLogService(mockAuditLogStore),
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
+		)
+		return service, mockDocumentationEntryStore, mockAuditLogStore
+	}
+
+	t.Run("exports and records the archived reports", func(t *testing.T) {
+		service, mockDocumentationEntryStore, mockAuditLogStore := newService()
+		mockDocumentationEntryStore.On("GetArchivedReportIDsSince", mock.Anything, since).Return([]int{3, 7}, nil).Once()
+		mockDocumentationEntryStore.On("GetReportByID", mock.Anything, 3).Return(&models.GeneratedReport{ID: 3, Archived: true, Content: []byte("a")}, nil).Once()
+		mockDocumentationEntryStore.On("GetReportByID", mock.Anything, 7).Return(&models.GeneratedReport{ID: 7, Archived: true, Content: []byte("b")}, nil).Once()
+		mockAuditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionExportGeneratedReports && *entry.ActorUserID == 5 && entry.Details == "reports=2 since=2025-01-01"
+		})).Return(1, nil).Once()
+
+		reports, err := service.ExportGeneratedReports(logger, context.Background(), since, 5)
+		assert.NoError(t, err)
+		assert.Len(t, reports, 2)
+		assert.Equal(t, []byte("b"), reports[1].Content)
+		mockAuditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("nothing is handed out without an audit record", func(t *testing.T) {
+		service, mockDocumentationEntryStore, mockAuditLogStore := newService()
+		mockDocumentationEntryStore.On("GetArchivedReportIDsSince", mock.Anything, since).Return([]int{}, nil).Once()
+		mockAuditLogStore.On("Create", mock.Anything, mock.Anything).Return(0, errors.New("db error")).Once()
+
+		reports, err := service.ExportGeneratedReports(logger, context.Background(), since, 5)
+		assert.Error(t, err)
+		assert.Nil(t, reports)
+	})
+}
+
+func TestGenerateChildReportStamp(t *testing.T) {
+	newService := func() (*services.DocumentationEntryServiceImpl, *datamocks.MockDocumentationEntryStore) {
+		mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockChildStore := new(datamocks.MockChildStore)
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1, FirstName: "Report", LastName: "Child"}, nil).Once()
+		mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{}, nil).Once()
+		mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Kita Sonnenschein & Co"}, nil).Once()
+		service := services.NewDocumentationEntryService(
+			mockDocumentationEntryStore,
+			mockChildStore,
+			new(datamocks.MockTeacherStore),
+			new(datamocks.MockCategoryStore),
+			new(datamocks.MockUserStore),
+			mockKitaMasterdataStore,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			clock.System{},
+		)
+		return service, mockDocumentationEntryStore
+	}
+	readPart := func(t *testing.T, reportBytes []byte, name string) string {
+		reader, err := zip.NewReader(bytes.NewReader(reportBytes), int64(len(reportBytes)))
+		assert.NoError(t, err)
+		file, err := reader.Open(name)
+		if !assert.NoError(t, err) {
+			return ""
+		}
+		content, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		return string(content)
+	}
+	ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, &models.User{ID: 5, Username: "erzieherin"})
+
+	t.Run("footer and core properties", func(t *testing.T) {
+		service, mockDocumentationEntryStore := newService()
+		var documentID string
+		mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.MatchedBy(func(report *models.GeneratedReport) bool {
+			documentID = report.DocumentID
+			return *report.GeneratedByUserID == 5
+		})).Return(nil).Once()
+
+		reportBytes, err := service.GenerateChildReport(logrus.NewEntry(logrus.New()), ctx, 1, nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, documentID)
+
+		footer := readPart(t, reportBytes, "word/footer1.xml")
+		assert.Contains(t, footer, "Kita Sonnenschein &amp; Co")
+		assert.Contains(t, footer, "von erzieherin")
+		assert.Contains(t, footer, "Dokument-ID: "+documentID)
+		assert.Contains(t, readPart(t, reportBytes, "word/document.xml"), "<w:footerReference w:type=\"default\"")
+		assert.Contains(t, readPart(t, reportBytes, "[Content_Types].xml"), "/word/footer1.xml")
+		assert.Contains(t, readPart(t, reportBytes, "docProps/core.xml"), "<dc:identifier>"+documentID+"</dc:identifier>")
+		assert.Contains(t, readPart(t, reportBytes, "docProps/app.xml"), "<Application>Kitadoc ")
+	})
+
+	t.Run("teacher names hidden", func(t *testing.T) {
+		service, mockDocumentationEntryStore := newService()
+		mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.MatchedBy(func(report *models.GeneratedReport) bool {
+			return *report.GeneratedByUserID == 5
+		})).Return(nil).Once()
+
+		reportBytes, err := service.GenerateChildReport(logrus.NewEntry(logrus.New()), ctx, 1, nil, &models.RedactionProfile{HideTeacherNames: true}, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.NotContains(t, readPart(t, reportBytes, "word/footer1.xml"), "erzieherin")
+		assert.NotContains(t, readPart(t, reportBytes, "docProps/core.xml"), "erzieherin")
+	})
+}
+
+func TestGenerateChildReportTerminology(t *testing.T) {
+	mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+	mockChildStore := new(datamocks.MockChildStore)
+	mockCategoryStore := new(datamocks.MockCategoryStore)
+	mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+	mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1, FirstName: "Report", LastName: "Child"}, nil).Once()
+	mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{
+		{ID: 1, ChildID: 1, CategoryID: 3, IsApproved: true, ObservationDate: models.NewDate(2023, 1, 1), ObservationDescription: "Erzählt vom Wochenende"},
+	}, nil).Once()
+	mockCategoryStore.On("GetByID", mock.Anything, 3).Return(&models.Category{ID: 3, Name: "Sprache"}, nil)
+	mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Kita Sonnenschein", Terminology: models.Terminology{
+		models.TermTeacher:        "pädagogische Fachkraft",
+		models.TermTeachers:       "pädagogische Fachkräfte",
+		models.TermPrimaryTeacher: "Bezugsfachkraft",
+		models.TermCategory:       "Entwicklungsfeld",
+		models.TermDocumentation:  "Entwicklungsportfolio",
+	}}, nil).Once()
+	mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.Anything).Return(nil).Once()
+	service := services.NewDocumentationEntryService(
+		mockDocumentationEntryStore,
+		mockChildStore,
+		new(datamocks.MockTeacherStore),
+		mockCategoryStore,
+		new(datamocks.MockUserStore),
+		mockKitaMasterdataStore,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
+	)
+
+	assignments := []models.Assignment{
+		{TeacherID: 2, AssignmentType: models.AssignmentTypeSecondary, StartDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{TeacherID: 1, AssignmentType: models.AssignmentTypePrimary, StartDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	reportBytes, err := service.GenerateChildReport(logrus.NewEntry(logrus.New()), context.Background(), 1, assignments,
+		&models.RedactionProfile{HideTeacherNames: true}, nil, nil, nil)
+	require.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(reportBytes), int64(len(reportBytes)))
+	require.NoError(t, err)
+	readPart := func(name string) string {
+		file, err := reader.Open(name)
+		require.NoError(t, err)
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		return string(content)
+	}
+	document := readPart("word/document.xml")
+	assert.Contains(t, document, "Entwicklungsbegleiter/-innen, pädagogische Fachkräfte (von - bis):")
+	assert.Contains(t, document, "- pädagogische Fachkraft, Bezugsfachkraft (01.01.2023 bis heute)")
+	assert.Contains(t, document, "- pädagogische Fachkraft, Zweitkraft (01.01.2023 bis heute)", "terms that are not replaced keep their default")
+	assert.Contains(t, document, "Entwicklungsfeld: Sprache")
+	assert.NotContains(t, document, "Bildungsbereich")
+	assert.Contains(t, readPart("docProps/core.xml"), "<dc:title>Entwicklungsportfolio</dc:title>")
+}
+
+func TestGenerateChildReportTheme(t *testing.T) {
+	font := "Open Sans"
+	color := "#1a5276"
+	mockDocumentationEntryStore := new(datamocks.MockDocumentationEntryStore)
+	mockChildStore := new(datamocks.MockChildStore)
+	mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+	mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1, FirstName: "Report", LastName: "Child"}, nil).Once()
+	mockDocumentationEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{}, nil).Once()
+	mockKitaMasterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{
+		Name:  "Kita Sonnenschein",
+		Theme: models.DocumentTheme{FontFamily: &font, AccentColor: &color, HasLogo: true},
+	}, nil).Once()
+	mockKitaMasterdataStore.On("GetLogo", mock.Anything).Return(&models.DocumentLogo{Content: pngLogo(t, 40, 10), ContentType: "image/png"}, nil).Once()
+	mockDocumentationEntryStore.On("RecordReport", mock.Anything, mock.Anything).Return(nil).Once()
+	service := services.NewDocumentationEntryService(
+		mockDocumentationEntryStore,
+		mockChildStore,
+		new(datamocks.MockTeacherStore),
+		new(datamocks.MockCategoryStore),
+		new(datamocks.MockUserStore),
+		mockKitaMasterdataStore,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		clock.System{},
+	)
+
+	reportBytes, err := service.GenerateChildReport(logrus.NewEntry(logrus.New()), context.Background(), 1, nil, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	mockKitaMasterdataStore.AssertExpectations(t)
+
+	reader, err := zip.NewReader(bytes.NewReader(reportBytes), int64(len(reportBytes)))
+	assert.NoError(t, err)
+	parts := make(map[string]string)
+	for _, file := range reader.File {
+		content, err := file.Open()
+		assert.NoError(t, err)
+		partBytes, err := io.ReadAll(content)
+		assert.NoError(t, err)
+		parts[file.Name] = string(partBytes)
+	}
+	assert.Contains(t, parts["word/styles.xml"], `w:ascii="Open Sans"`)
+	assert.Contains(t, parts["word/styles.xml"], `w:val="1A5276"`)
+	assert.Contains(t, parts, "word/media/image1.png")
+	assert.Contains(t, parts["word/document.xml"], "<w:drawing>")
 }
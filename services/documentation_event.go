@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DocumentationEventService defines the interface for the lifecycle event stream of documentation entries.
+// The timeline, the review history and the lifecycle statistics are all derived from the stream.
+type DocumentationEventService interface {
+	Record(logger *logrus.Entry, ctx context.Context, event *models.DocumentationEvent, outbox []models.OutboxMessage) error
+	GetChildTimeline(logger *logrus.Entry, ctx context.Context, childID int) ([]models.DocumentationEvent, error)
+	GetEntryHistory(logger *logrus.Entry, ctx context.Context, entryID int) (*models.DocumentationEntryHistory, error)
+	GetLifecycleStats(logger *logrus.Entry, ctx context.Context, childID *int) (*models.DocumentationLifecycleStats, error)
+}
+
+// DocumentationEventServiceImpl implements DocumentationEventService.
+type DocumentationEventServiceImpl struct {
+	eventStore data.DocumentationEventStore
+	childStore data.ChildStore
+	clock      clock.Clock
+}
+
+// NewDocumentationEventService creates a new DocumentationEventServiceImpl.
+func NewDocumentationEventService(eventStore data.DocumentationEventStore, childStore data.ChildStore, clock clock.Clock) *DocumentationEventServiceImpl {
+	return &DocumentationEventServiceImpl{eventStore: eventStore, childStore: childStore, clock: clock}
+}
+
+// Record appends an event to the stream of its child, together with the outbox messages caused by it.
+// Without an explicit actor, the authenticated user of the request is recorded.
+func (service *DocumentationEventServiceImpl) Record(logger *logrus.Entry, ctx context.Context, event *models.DocumentationEvent, outbox []models.OutboxMessage) error {
+	if event.ActorUserID == nil {
+		if user, ok := ctx.Value(middleware.ContextKeyUser).(*models.User); ok {
+			event.ActorUserID = &user.ID
+		}
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = service.clock.Now()
+	}
+	if err := service.eventStore.Append(ctx, event, outbox); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"entry_id": event.EntryID, "event_type": event.Type}).Error("Error appending documentation event")
+		return ErrInternal
+	}
+	return nil
+}
+
+// GetChildTimeline fetches the complete event stream of a child.
+func (service *DocumentationEventServiceImpl) GetChildTimeline(logger *logrus.Entry, ctx context.Context, childID int) ([]models.DocumentationEvent, error) {
+	if _, err := service.childStore.GetByID(ctx, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("child_id", childID).Warn("Child not found for documentation timeline")
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for documentation timeline")
+		return nil, ErrInternal
+	}
+
+	events, err := service.eventStore.GetForChild(ctx, childID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching documentation events of child")
+		return nil, ErrInternal
+	}
+	if events == nil {
+		events = []models.DocumentationEvent{}
+	}
+	return events, nil
+}
+
+// GetEntryHistory replays the events of an entry. Entries without events are reported as not found.
+func (service *DocumentationEventServiceImpl) GetEntryHistory(logger *logrus.Entry, ctx context.Context, entryID int) (*models.DocumentationEntryHistory, error) {
+	events, err := service.eventStore.GetForEntry(ctx, entryID)
+	if err != nil {
+		logger.WithError(err).WithField("entry_id", entryID).Error("Error fetching documentation events of entry")
+		return nil, ErrInternal
+	}
+	if len(events) == 0 {
+		return nil, ErrNotFound
+	}
+	return replayEntryHistory(entryID, events), nil
+}
+
+// GetLifecycleStats computes the lifecycle statistics of a single child or, if childID is nil, of all children.
+func (service *DocumentationEventServiceImpl) GetLifecycleStats(logger *logrus.Entry, ctx context.Context, childID *int) (*models.DocumentationLifecycleStats, error) {
+	var events []models.DocumentationEvent
+	var err error
+	if childID != nil {
+		events, err = service.GetChildTimeline(logger, ctx, *childID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		events, err = service.eventStore.GetAll(ctx)
+		if err != nil {
+			logger.WithError(err).Error("Error fetching documentation events")
+			return nil, ErrInternal
+		}
+	}
+	stats := lifecycleStats(events)
+	stats.ChildID = childID
+	return stats, nil
+}
+
+// replayEntryHistory folds the events of one entry, oldest first, into its review history.
+// Editing a submitted or rejected entry takes it back to a draft that has to be submitted again.
+func replayEntryHistory(entryID int, events []models.DocumentationEvent) *models.DocumentationEntryHistory {
+	history := &models.DocumentationEntryHistory{
+		EntryID: entryID,
+		Status:  models.DocumentationStatusDraft,
+		Events:  events,
+	}
+	for _, event := range events {
+		history.ChildID = event.ChildID
+		at := event.CreatedAt
+		switch event.Type {
+		case models.DocumentationEventCreated:
+			history.CreatedAt = &at
+		case models.DocumentationEventEdited:
+			history.Revision++
+			if history.Status != models.DocumentationStatusApproved {
+				history.Status = models.DocumentationStatusDraft
+				history.RejectionReason = ""
+			}
+		case models.DocumentationEventSubmitted:
+			history.Status = models.DocumentationStatusSubmitted
+			history.SubmittedAt = &at
+			history.RejectionReason = ""
+		case models.DocumentationEventApproved:
+			history.Status = models.DocumentationStatusApproved
+			history.ApprovedAt = &at
+			history.ApprovedByTeacherID = event.Payload.ApprovedByTeacherID
+			history.RejectionReason = ""
+		case models.DocumentationEventRejected:
+			history.Status = models.DocumentationStatusRejected
+			history.RejectionCount++
+			history.RejectionReason = event.Payload.Reason
+		case models.DocumentationEventIncludedInReport:
+			history.ReportCount++
+		case models.DocumentationEventDeleted:
+			history.Status = models.DocumentationStatusDeleted
+		}
+	}
+	return history
+}
+
+// lifecycleStats counts the events and the current states of the entries in the given streams.
+func lifecycleStats(events []models.DocumentationEvent) *models.DocumentationLifecycleStats {
+	stats := &models.DocumentationLifecycleStats{
+		EventCounts:  make(map[string]int),
+		StatusCounts: make(map[string]int),
+	}
+
+	eventsByEntry := make(map[int][]models.DocumentationEvent)
+	var entryIDs []int
+	for _, event := range events {
+		stats.EventCounts[string(event.Type)]++
+		if _, ok := eventsByEntry[event.EntryID]; !ok {
+			entryIDs = append(entryIDs, event.EntryID)
+		}
+		eventsByEntry[event.EntryID] = append(eventsByEntry[event.EntryID], event)
+	}
+
+	var reviewHours float64
+	reviews := 0
+	for _, entryID := range entryIDs {
+		entryEvents := eventsByEntry[entryID]
+		stats.StatusCounts[replayEntryHistory(entryID, entryEvents).Status]++
+
+		var submittedAt *time.Time
+		for _, event := range entryEvents {
+			switch event.Type {
+			case models.DocumentationEventSubmitted:
+				at := event.CreatedAt
+				submittedAt = &at
+			case models.DocumentationEventApproved:
+				if submittedAt != nil {
+					reviewHours += event.CreatedAt.Sub(*submittedAt).Hours()
+					reviews++
+				}
+				submittedAt = nil
+			}
+		}
+	}
+
+	if reviews > 0 {
+		average := reviewHours / float64(reviews)
+		stats.AverageReviewHours = &average
+	}
+	if decisions := stats.EventCounts[string(models.DocumentationEventApproved)] + stats.EventCounts[string(models.DocumentationEventRejected)]; decisions > 0 {
+		rate := float64(stats.EventCounts[string(models.DocumentationEventRejected)]) / float64(decisions)
+		stats.RejectionRate = &rate
+	}
+	return stats
+}
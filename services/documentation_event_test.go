@@ -0,0 +1,152 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecordDocumentationEvent(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+
+	t.Run("actor from request", func(t *testing.T) {
+		mockEventStore := new(datamocks.MockDocumentationEventStore)
+		service := services.NewDocumentationEventService(mockEventStore, new(datamocks.MockChildStore), clock.System{})
+		ctx := context.WithValue(context.Background(), middleware.ContextKeyUser, &models.User{ID: 7})
+
+		mockEventStore.On("Append", mock.Anything, mock.MatchedBy(func(event *models.DocumentationEvent) bool {
+			return event.ActorUserID != nil && *event.ActorUserID == 7 && !event.CreatedAt.IsZero()
+		}), mock.Anything).Return(nil).Once()
+
+		err := service.Record(logger, ctx, &models.DocumentationEvent{ChildID: 1, EntryID: 2, Type: models.DocumentationEventCreated}, nil)
+		assert.NoError(t, err)
+		mockEventStore.AssertExpectations(t)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		mockEventStore := new(datamocks.MockDocumentationEventStore)
+		service := services.NewDocumentationEventService(mockEventStore, new(datamocks.MockChildStore), clock.System{})
+		mockEventStore.On("Append", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+
+		err := service.Record(logger, context.Background(), &models.DocumentationEvent{ChildID: 1, EntryID: 2, Type: models.DocumentationEventCreated}, nil)
+		assert.Equal(t, services.ErrInternal, err)
+	})
+}
+
+func TestGetEntryHistory(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	start := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	teacherID := 4
+
+	t.Run("replays review", func(t *testing.T) {
+		mockEventStore := new(datamocks.MockDocumentationEventStore)
+		service := services.NewDocumentationEventService(mockEventStore, new(datamocks.MockChildStore), clock.System{})
+		mockEventStore.On("GetForEntry", mock.Anything, 2).Return([]models.DocumentationEvent{
+			{ChildID: 1, EntryID: 2, Sequence: 1, Type: models.DocumentationEventCreated, CreatedAt: start},
+			{ChildID: 1, EntryID: 2, Sequence: 2, Type: models.DocumentationEventSubmitted, CreatedAt: start.Add(time.Hour)},
+			{ChildID: 1, EntryID: 2, Sequence: 4, Type: models.DocumentationEventRejected, CreatedAt: start.Add(2 * time.Hour), Payload: models.DocumentationEventData{Reason: "Bitte konkreter"}},
+			{ChildID: 1, EntryID: 2, Sequence: 5, Type: models.DocumentationEventEdited, CreatedAt: start.Add(3 * time.Hour)},
+			{ChildID: 1, EntryID: 2, Sequence: 6, Type: models.DocumentationEventSubmitted, CreatedAt: start.Add(4 * time.Hour)},
+			{ChildID: 1, EntryID: 2, Sequence: 7, Type: models.DocumentationEventApproved, CreatedAt: start.Add(5 * time.Hour), Payload: models.DocumentationEventData{ApprovedByTeacherID: &teacherID}},
+			{ChildID: 1, EntryID: 2, Sequence: 9, Type: models.DocumentationEventIncludedInReport, CreatedAt: start.Add(6 * time.Hour)},
+		}, nil).Once()
+
+		history, err := service.GetEntryHistory(logger, ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, models.DocumentationStatusApproved, history.Status)
+		assert.Equal(t, 1, history.ChildID)
+		assert.Equal(t, 1, history.Revision)
+		assert.Equal(t, 1, history.RejectionCount)
+		assert.Empty(t, history.RejectionReason)
+		assert.Equal(t, 1, history.ReportCount)
+		assert.True(t, history.SubmittedAt.Equal(start.Add(4*time.Hour)))
+		assert.True(t, history.ApprovedAt.Equal(start.Add(5*time.Hour)))
+		assert.Equal(t, &teacherID, history.ApprovedByTeacherID)
+		assert.Len(t, history.Events, 7)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockEventStore := new(datamocks.MockDocumentationEventStore)
+		service := services.NewDocumentationEventService(mockEventStore, new(datamocks.MockChildStore), clock.System{})
+		mockEventStore.On("GetForEntry", mock.Anything, 2).Return([]models.DocumentationEvent{
+			{ChildID: 1, EntryID: 2, Type: models.DocumentationEventCreated, CreatedAt: start},
+			{ChildID: 1, EntryID: 2, Type: models.DocumentationEventSubmitted, CreatedAt: start},
+			{ChildID: 1, EntryID: 2, Type: models.DocumentationEventRejected, CreatedAt: start, Payload: models.DocumentationEventData{Reason: "Bitte konkreter"}},
+		}, nil).Once()
+
+		history, err := service.GetEntryHistory(logger, ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, models.DocumentationStatusRejected, history.Status)
+		assert.Equal(t, "Bitte konkreter", history.RejectionReason)
+	})
+
+	t.Run("no events", func(t *testing.T) {
+		mockEventStore := new(datamocks.MockDocumentationEventStore)
+		service := services.NewDocumentationEventService(mockEventStore, new(datamocks.MockChildStore), clock.System{})
+		mockEventStore.On("GetForEntry", mock.Anything, 2).Return(nil, nil).Once()
+
+		history, err := service.GetEntryHistory(logger, ctx, 2)
+		assert.Nil(t, history)
+		assert.Equal(t, services.ErrNotFound, err)
+	})
+}
+
+func TestGetLifecycleStats(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	start := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+
+	t.Run("all children", func(t *testing.T) {
+		mockEventStore := new(datamocks.MockDocumentationEventStore)
+		service := services.NewDocumentationEventService(mockEventStore, new(datamocks.MockChildStore), clock.System{})
+		mockEventStore.On("GetAll", mock.Anything).Return([]models.DocumentationEvent{
+			{ChildID: 1, EntryID: 1, Type: models.DocumentationEventCreated, CreatedAt: start},
+			{ChildID: 1, EntryID: 1, Type: models.DocumentationEventSubmitted, CreatedAt: start},
+			{ChildID: 1, EntryID: 1, Type: models.DocumentationEventApproved, CreatedAt: start.Add(2 * time.Hour)},
+			{ChildID: 1, EntryID: 2, Type: models.DocumentationEventCreated, CreatedAt: start},
+			{ChildID: 2, EntryID: 3, Type: models.DocumentationEventCreated, CreatedAt: start},
+			{ChildID: 2, EntryID: 3, Type: models.DocumentationEventSubmitted, CreatedAt: start},
+			{ChildID: 2, EntryID: 3, Type: models.DocumentationEventRejected, CreatedAt: start.Add(time.Hour)},
+			{ChildID: 2, EntryID: 3, Type: models.DocumentationEventSubmitted, CreatedAt: start.Add(2 * time.Hour)},
+			{ChildID: 2, EntryID: 3, Type: models.DocumentationEventApproved, CreatedAt: start.Add(6 * time.Hour)},
+		}, nil).Once()
+
+		stats, err := service.GetLifecycleStats(logger, ctx, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, stats.ChildID)
+		assert.Equal(t, 3, stats.EventCounts["created"])
+		assert.Equal(t, 3, stats.EventCounts["submitted"])
+		assert.Equal(t, 2, stats.StatusCounts[models.DocumentationStatusApproved])
+		assert.Equal(t, 1, stats.StatusCounts[models.DocumentationStatusDraft])
+		if assert.NotNil(t, stats.AverageReviewHours) {
+			assert.InDelta(t, 3.0, *stats.AverageReviewHours, 0.001)
+		}
+		if assert.NotNil(t, stats.RejectionRate) {
+			assert.InDelta(t, 1.0/3.0, *stats.RejectionRate, 0.001)
+		}
+	})
+
+	t.Run("child not found", func(t *testing.T) {
+		mockChildStore := new(datamocks.MockChildStore)
+		service := services.NewDocumentationEventService(new(datamocks.MockDocumentationEventStore), mockChildStore, clock.System{})
+		mockChildStore.On("GetByID", mock.Anything, 99).Return(nil, data.ErrNotFound).Once()
+
+		childID := 99
+		stats, err := service.GetLifecycleStats(logger, ctx, &childID)
+		assert.Nil(t, stats)
+		assert.Equal(t, services.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DocumentationImportService defines the interface for importing historical documentation.
+type DocumentationImportService interface {
+	ImportDocumentation(logger *logrus.Entry, ctx context.Context, request *models.DocumentationImportRequest) (*models.DocumentationImportReport, error)
+}
+
+// DocumentationImportServiceImpl implements DocumentationImportService.
+type DocumentationImportServiceImpl struct {
+	childStore              data.ChildStore
+	teacherStore            data.TeacherStore
+	categoryStore           data.CategoryStore
+	documentationEntryStore data.DocumentationEntryStore
+	eventService            DocumentationEventService
+	clock                   clock.Clock
+}
+
+// NewDocumentationImportService creates a new DocumentationImportServiceImpl.
+func NewDocumentationImportService(
+	childStore data.ChildStore,
+	teacherStore data.TeacherStore,
+	categoryStore data.CategoryStore,
+	documentationEntryStore data.DocumentationEntryStore,
+	eventService DocumentationEventService,
+	clock clock.Clock,
+) *DocumentationImportServiceImpl {
+	return &DocumentationImportServiceImpl{
+		childStore:              childStore,
+		teacherStore:            teacherStore,
+		categoryStore:           categoryStore,
+		documentationEntryStore: documentationEntryStore,
+		eventService:            eventService,
+		clock:                   clock,
+	}
+}
+
+// importReferences are the records the observations of an import are matched against.
+type importReferences struct {
+	children   []models.Child
+	teachers   []nameCandidate
+	categories []nameCandidate
+	// entries caches the existing entries of the matched children to detect observations imported before.
+	entries map[int][]models.DocumentationEntry
+}
+
+// ImportDocumentation matches the observations to children, teachers and categories and imports those that match
+// unambiguously as approved entries, the author having signed them on paper. The entries are created in one
+// transaction, so a failing import does not leave part of the file behind. Observations that are already
+// documented are reported as duplicates, so an import can be repeated after correcting the report.
+// The business rules for new observations, e.g. their maximum age, do not apply to historical ones.
+func (service *DocumentationImportServiceImpl) ImportDocumentation(logger *logrus.Entry, ctx context.Context, request *models.DocumentationImportRequest) (*models.DocumentationImportReport, error) {
+	if err := models.ValidateDocumentationImportRequest(*request); err != nil {
+		logger.WithError(err).Warn("Invalid documentation import request")
+		return nil, invalidInput(err)
+	}
+
+	references, err := service.loadReferences(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error loading records to match the documentation import against")
+		return nil, ErrInternal
+	}
+
+	report := &models.DocumentationImportReport{DryRun: request.DryRun, Items: make([]models.DocumentationImportItem, 0, len(request.Observations))}
+	var entries []models.DocumentationEntry
+	var entryItems []int
+	for i := range request.Observations {
+		observation := &request.Observations[i]
+		item := service.reconcile(references, i, observation)
+		if item.Status == models.ImportItemStatusMatched {
+			duplicate, err := service.isDuplicate(ctx, references, *item.ChildID, observation)
+			if err != nil {
+				logger.WithError(err).WithField("child_id", *item.ChildID).Error("Error fetching documentation to detect duplicates")
+				return nil, ErrInternal
+			}
+			if duplicate {
+				item.Status = models.ImportItemStatusDuplicate
+				item.Problems = append(item.Problems, "observation is already documented")
+			}
+		}
+		if item.Status == models.ImportItemStatusMatched && !request.DryRun {
+			entry := importedEntry(&item, observation, service.clock.Now())
+			entries = append(entries, entry)
+			entryItems = append(entryItems, len(report.Items))
+			references.entries[*item.ChildID] = append(references.entries[*item.ChildID], entry)
+		}
+		if item.Status != models.ImportItemStatusMatched && item.Status != models.ImportItemStatusDuplicate {
+			report.ReviewCount++
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	if len(entries) > 0 {
+		if err := service.createEntries(logger, ctx, entries); err != nil {
+			return nil, err
+		}
+		for i, itemIndex := range entryItems {
+			report.Items[itemIndex].Status = models.ImportItemStatusImported
+			report.Items[itemIndex].EntryID = &entries[i].ID
+		}
+		report.ImportedCount = len(entries)
+	}
+
+	logger.WithField("dry_run", request.DryRun).Infof("Documentation import of %d observations: %d imported, %d to review",
+		len(request.Observations), report.ImportedCount, report.ReviewCount)
+	return report, nil
+}
+
+func (service *DocumentationImportServiceImpl) loadReferences(ctx context.Context) (*importReferences, error) {
+	// Historical documentation may belong to children who have left the Kita since.
+	children, err := service.childStore.GetAllIncludingArchived(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teachers, err := service.teacherStore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := service.categoryStore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	references := &importReferences{children: children, entries: make(map[int][]models.DocumentationEntry)}
+	for _, teacher := range teachers {
+		references.teachers = append(references.teachers, nameCandidate{
+			ID:    teacher.ID,
+			Names: []string{teacher.FirstName + " " + teacher.LastName, teacher.LastName},
+		})
+	}
+	for _, category := range categories {
+		references.categories = append(references.categories, nameCandidate{ID: category.ID, Names: []string{category.Name}})
+	}
+	return references, nil
+}
+
+// reconcile matches an observation. The item is matched if the child, the teacher and the category are all unique.
+func (service *DocumentationImportServiceImpl) reconcile(references *importReferences, index int, observation *models.HistoricalObservation) models.DocumentationImportItem {
+	item := models.DocumentationImportItem{Index: index, Status: models.ImportItemStatusMatched}
+	if err := models.ValidateHistoricalObservation(*observation); err != nil {
+		item.Status = models.ImportItemStatusInvalid
+		var validationError *ValidationError
+		if errors.As(invalidInput(err), &validationError) {
+			for _, violation := range validationError.Violations {
+				item.Problems = append(item.Problems, violation.Message)
+			}
+		} else {
+			item.Problems = append(item.Problems, err.Error())
+		}
+		return item
+	}
+
+	var childCandidates []int
+	if observation.ChildID != nil {
+		childCandidates, item.ChildMatch = service.matchChildID(references, *observation.ChildID)
+	} else {
+		children := []nameCandidate{}
+		for _, child := range references.children {
+			if observation.ChildBirthdate != nil && !child.Birthdate.Equal(observation.ChildBirthdate.Time) {
+				continue
+			}
+			children = append(children, nameCandidate{ID: child.ID, Names: []string{child.FirstName + " " + child.LastName}})
+		}
+		childCandidates, item.ChildMatch = matchName(observation.ChildName, children)
+	}
+	item.ChildID, item.ChildCandidates, item.ChildMatch = resolveCandidates(childCandidates, item.ChildMatch)
+
+	var teacherCandidates []int
+	if observation.TeacherID != nil {
+		teacherCandidates, item.TeacherMatch = matchID(references.teachers, *observation.TeacherID)
+	} else {
+		teacherCandidates, item.TeacherMatch = matchName(observation.AuthorName, references.teachers)
+	}
+	item.TeacherID, item.TeacherCandidates, item.TeacherMatch = resolveCandidates(teacherCandidates, item.TeacherMatch)
+
+	var categoryCandidates []int
+	categoryCandidates, item.CategoryMatch = matchName(observation.CategoryName, references.categories)
+	item.CategoryID, _, item.CategoryMatch = resolveCandidates(categoryCandidates, item.CategoryMatch)
+
+	for _, match := range []struct{ name, quality string }{
+		{"child", item.ChildMatch},
+		{"author", item.TeacherMatch},
+		{"category", item.CategoryMatch},
+	} {
+		switch match.quality {
+		case models.MatchNone:
+			item.Status = models.ImportItemStatusUnmatched
+			item.Problems = append(item.Problems, fmt.Sprintf("no %s matches", match.name))
+		case models.MatchSeveral:
+			if item.Status == models.ImportItemStatusMatched {
+				item.Status = models.ImportItemStatusAmbiguous
+			}
+			item.Problems = append(item.Problems, fmt.Sprintf("several records match the %s", match.name))
+		}
+	}
+	return item
+}
+
+func (service *DocumentationImportServiceImpl) matchChildID(references *importReferences, childID int) ([]int, string) {
+	for _, child := range references.children {
+		if child.ID == childID {
+			return []int{childID}, models.MatchByID
+		}
+	}
+	return nil, models.MatchNone
+}
+
+// matchID checks that an explicitly given ID exists.
+func matchID(candidates []nameCandidate, id int) ([]int, string) {
+	for _, candidate := range candidates {
+		if candidate.ID == id {
+			return []int{id}, models.MatchByID
+		}
+	}
+	return nil, models.MatchNone
+}
+
+// resolveCandidates returns the ID if exactly one candidate matched, otherwise the candidates to choose from.
+func resolveCandidates(candidates []int, quality string) (*int, []int, string) {
+	switch len(candidates) {
+	case 0:
+		return nil, nil, models.MatchNone
+	case 1:
+		return &candidates[0], nil, quality
+	default:
+		return nil, candidates, models.MatchSeveral
+	}
+}
+
+func (service *DocumentationImportServiceImpl) isDuplicate(ctx context.Context, references *importReferences, childID int, observation *models.HistoricalObservation) (bool, error) {
+	entries, ok := references.entries[childID]
+	if !ok {
+		var err error
+		entries, err = service.documentationEntryStore.GetAllForChild(ctx, childID)
+		if err != nil {
+			return false, err
+		}
+		references.entries[childID] = entries
+	}
+	for _, entry := range entries {
+		if entry.ObservationDate.Equal(observation.ObservationDate.Time) &&
+			strings.TrimSpace(entry.ObservationDescription) == strings.TrimSpace(observation.Text) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// importedEntry is the approved entry a matched observation is stored as.
+func importedEntry(item *models.DocumentationImportItem, observation *models.HistoricalObservation, now time.Time) models.DocumentationEntry {
+	return models.DocumentationEntry{
+		ChildID:                *item.ChildID,
+		TeacherID:              *item.TeacherID,
+		CategoryID:             *item.CategoryID,
+		ObservationDate:        observation.ObservationDate,
+		ObservationDescription: strings.TrimSpace(observation.Text),
+		IsApproved:             true,
+		ApprovedByUserID:       item.TeacherID,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+	}
+}
+
+// createEntries stores the entries, sets their IDs and records their creation and approval in the event stream.
+func (service *DocumentationImportServiceImpl) createEntries(logger *logrus.Entry, ctx context.Context, entries []models.DocumentationEntry) error {
+	ids, err := service.documentationEntryStore.CreateMany(ctx, entries)
+	if err != nil {
+		if errors.Is(err, data.ErrForeignKeyConstraint) {
+			return ErrForeignKeyConstraint
+		}
+		logger.WithError(err).Errorf("Error creating %d imported documentation entries", len(entries))
+		return ErrInternal
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		entry.ID = ids[i]
+		if service.eventService == nil {
+			continue
+		}
+		_ = service.eventService.Record(logger, ctx, &models.DocumentationEvent{
+			ChildID: entry.ChildID,
+			EntryID: entry.ID,
+			Type:    models.DocumentationEventCreated,
+			Payload: models.DocumentationEventData{Entry: entry},
+		}, nil)
+		_ = service.eventService.Record(logger, ctx, &models.DocumentationEvent{
+			ChildID: entry.ChildID,
+			EntryID: entry.ID,
+			Type:    models.DocumentationEventApproved,
+			Payload: models.DocumentationEventData{ApprovedByTeacherID: entry.ApprovedByUserID},
+		}, nil)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportDocumentation(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	day := func(month time.Month, d int) models.Date { return models.NewDate(2023, month, d) }
+	children := []models.Child{
+		{ID: 1, FirstName: "Anna", LastName: "Musterkind", Birthdate: models.NewDate(2019, 3, 1)},
+		{ID: 2, FirstName: "Ben", LastName: "Schmidt", Birthdate: models.NewDate(2019, 5, 1)},
+		{ID: 3, FirstName: "Ben", LastName: "Schmidt", Birthdate: models.NewDate(2020, 7, 1)},
+	}
+	teachers := []models.Teacher{
+		{ID: 10, FirstName: "Maria", LastName: "Müller"},
+		{ID: 11, FirstName: "Jonas", LastName: "Weber"},
+	}
+	categories := []models.Category{{ID: 20, Name: "Sprache"}, {ID: 21, Name: "Motorik"}}
+	text := "Anna erzählt ausführlich vom Wochenende."
+
+	setup := func() (*services.DocumentationImportServiceImpl, *datamocks.MockChildStore, *datamocks.MockDocumentationEntryStore) {
+		mockChildStore := new(datamocks.MockChildStore)
+		mockTeacherStore := new(datamocks.MockTeacherStore)
+		mockCategoryStore := new(datamocks.MockCategoryStore)
+		mockEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockChildStore.On("GetAllIncludingArchived", mock.Anything).Return(children, nil)
+		mockTeacherStore.On("GetAll", mock.Anything).Return(teachers, nil)
+		mockCategoryStore.On("GetAll", mock.Anything).Return(categories, nil)
+		service := services.NewDocumentationImportService(mockChildStore, mockTeacherStore, mockCategoryStore, mockEntryStore, nil, clock.System{})
+		return service, mockChildStore, mockEntryStore
+	}
+
+	t.Run("dry run reports the reconciliation", func(t *testing.T) {
+		service, _, mockEntryStore := setup()
+		mockEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{
+			{ChildID: 1, ObservationDate: day(2, 1), ObservationDescription: text},
+		}, nil).Once()
+		mockEntryStore.On("GetAllForChild", mock.Anything, 3).Return([]models.DocumentationEntry{}, nil).Once()
+		birthdate := children[2].Birthdate
+
+		report, err := service.ImportDocumentation(logger, ctx, &models.DocumentationImportRequest{DryRun: true, Observations: []models.HistoricalObservation{
+			{ChildName: "Ana Musterkind", ObservationDate: day(1, 10), CategoryName: "sprache", Text: text, AuthorName: "Frau Mueller"},
+			{ChildName: "Schmidt, Ben", ObservationDate: day(1, 11), CategoryName: "Motorik", Text: "Ben klettert sicher.", AuthorName: "Weber"},
+			{ChildName: "Ben Schmidt", ChildBirthdate: &birthdate, ObservationDate: day(1, 11), CategoryName: "Motorik", Text: "Ben klettert sicher.", AuthorName: "Weber"},
+			{ChildName: "Anna Musterkind", ObservationDate: day(1, 12), CategoryName: "Musik", Text: text, AuthorName: "Weber"},
+			{ChildName: "Anna Musterkind", ObservationDate: day(2, 1), CategoryName: "Sprache", Text: text, AuthorName: "Weber"},
+			{ChildName: "Anna Musterkind", CategoryName: "Sprache", Text: "zu kurz", AuthorName: "Weber"},
+		}})
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 0, report.ImportedCount)
+		assert.Equal(t, 3, report.ReviewCount)
+
+		fuzzy := report.Items[0]
+		assert.Equal(t, models.ImportItemStatusMatched, fuzzy.Status)
+		assert.Equal(t, 1, *fuzzy.ChildID)
+		assert.Equal(t, models.MatchFuzzy, fuzzy.ChildMatch)
+		assert.Equal(t, 10, *fuzzy.TeacherID)
+		assert.Equal(t, models.MatchExact, fuzzy.TeacherMatch)
+		assert.Equal(t, 20, *fuzzy.CategoryID)
+
+		assert.Equal(t, models.ImportItemStatusAmbiguous, report.Items[1].Status)
+		assert.Equal(t, []int{2, 3}, report.Items[1].ChildCandidates)
+		assert.Nil(t, report.Items[1].ChildID)
+
+		assert.Equal(t, models.ImportItemStatusMatched, report.Items[2].Status, "the birthdate resolves the ambiguity")
+		assert.Equal(t, 3, *report.Items[2].ChildID)
+
+		assert.Equal(t, models.ImportItemStatusUnmatched, report.Items[3].Status)
+		assert.Equal(t, models.MatchNone, report.Items[3].CategoryMatch)
+
+		assert.Equal(t, models.ImportItemStatusDuplicate, report.Items[4].Status)
+		assert.Equal(t, models.ImportItemStatusInvalid, report.Items[5].Status)
+		assert.Equal(t, []string{"observation_date is required", "text must be at least 10 characters long"}, report.Items[5].Problems)
+		mockEntryStore.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+		mockEntryStore.AssertExpectations(t)
+	})
+
+	t.Run("imports matched observations as approved entries", func(t *testing.T) {
+		service, _, mockEntryStore := setup()
+		mockEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{}, nil).Once()
+		mockEntryStore.On("CreateMany", mock.Anything, mock.MatchedBy(func(entries []models.DocumentationEntry) bool {
+			return len(entries) == 1 && entries[0].ChildID == 1 && entries[0].TeacherID == 11 && entries[0].CategoryID == 21 &&
+				entries[0].IsApproved && *entries[0].ApprovedByUserID == 11 && entries[0].ObservationDate == day(3, 5)
+		})).Return([]int{100}, nil).Once()
+		teacherID := 11
+
+		report, err := service.ImportDocumentation(logger, ctx, &models.DocumentationImportRequest{Observations: []models.HistoricalObservation{
+			{ChildName: "Anna Musterkind", ObservationDate: day(3, 5), CategoryName: "Motorik", Text: "Anna balanciert über den Baumstamm.", TeacherID: &teacherID},
+			// The same observation twice in one file is only imported once.
+			{ChildName: "Anna Musterkind", ObservationDate: day(3, 5), CategoryName: "Motorik", Text: "Anna balanciert über den Baumstamm.", TeacherID: &teacherID},
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.ImportedCount)
+		assert.Equal(t, 0, report.ReviewCount)
+		assert.Equal(t, models.ImportItemStatusImported, report.Items[0].Status)
+		assert.Equal(t, models.MatchByID, report.Items[0].TeacherMatch)
+		assert.Equal(t, 100, *report.Items[0].EntryID)
+		assert.Equal(t, models.ImportItemStatusDuplicate, report.Items[1].Status)
+		mockEntryStore.AssertExpectations(t)
+	})
+
+	t.Run("empty request", func(t *testing.T) {
+		service, mockChildStore, _ := setup()
+		_, err := service.ImportDocumentation(logger, ctx, &models.DocumentationImportRequest{})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		var validationError *services.ValidationError
+		if assert.ErrorAs(t, err, &validationError) {
+			assert.Equal(t, "observations", validationError.Violations[0].Field)
+			assert.Equal(t, "required", validationError.Violations[0].Rule)
+		}
+		mockChildStore.AssertNotCalled(t, "GetAllIncludingArchived", mock.Anything)
+	})
+}
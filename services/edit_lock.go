@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EditLockTTL is how long an edit lock is held without being renewed. The frontend renews the lock while the
+// editor is open, e.g. every 30 seconds, so that the lock of a closed browser tab expires soon.
+const EditLockTTL = 2 * time.Minute
+
+// EditLockService defines the interface for the advisory locks of documentation entries being edited.
+type EditLockService interface {
+	// GetEditLock returns the active lock of an entry, nil if nobody edits it.
+	GetEditLock(logger *logrus.Entry, ctx context.Context, entryID int) (*models.EditLock, error)
+	// AcquireEditLock takes or renews the lock of an entry for the user. If another user edits the entry, their
+	// lock is returned together with ErrEntryBeingEdited.
+	AcquireEditLock(logger *logrus.Entry, ctx context.Context, entryID int, user *models.User) (*models.EditLock, error)
+	// ReleaseEditLock removes the lock of the user, admins can also remove the lock of another user.
+	ReleaseEditLock(logger *logrus.Entry, ctx context.Context, entryID int, user *models.User) error
+}
+
+// EditLockServiceImpl implements EditLockService.
+type EditLockServiceImpl struct {
+	editLockStore           data.EditLockStore
+	documentationEntryStore data.DocumentationEntryStore
+	userStore               data.UserStore
+	teacherStore            data.TeacherStore
+	clock                   clock.Clock
+}
+
+// NewEditLockService creates a new EditLockServiceImpl.
+func NewEditLockService(editLockStore data.EditLockStore, documentationEntryStore data.DocumentationEntryStore, userStore data.UserStore, teacherStore data.TeacherStore, clock clock.Clock) *EditLockServiceImpl {
+	return &EditLockServiceImpl{
+		editLockStore:           editLockStore,
+		documentationEntryStore: documentationEntryStore,
+		userStore:               userStore,
+		teacherStore:            teacherStore,
+		clock:                   clock,
+	}
+}
+
+// GetEditLock returns the active lock of an entry.
+func (service *EditLockServiceImpl) GetEditLock(logger *logrus.Entry, ctx context.Context, entryID int) (*models.EditLock, error) {
+	if err := service.checkEntry(logger, ctx, entryID); err != nil {
+		return nil, err
+	}
+	lock, err := service.editLockStore.Get(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, nil
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Error fetching edit lock")
+		return nil, ErrInternal
+	}
+	if !lock.IsActive(service.clock.Now()) {
+		return nil, nil
+	}
+	service.setEditorName(logger, ctx, lock)
+	return lock, nil
+}
+
+// AcquireEditLock takes or renews the lock of an entry for EditLockTTL.
+func (service *EditLockServiceImpl) AcquireEditLock(logger *logrus.Entry, ctx context.Context, entryID int, user *models.User) (*models.EditLock, error) {
+	if err := service.checkEntry(logger, ctx, entryID); err != nil {
+		return nil, err
+	}
+	now := service.clock.Now()
+	lock, err := service.editLockStore.Acquire(ctx, &models.EditLock{
+		EntryID:    entryID,
+		UserID:     user.ID,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(EditLockTTL),
+	}, now)
+	if err != nil {
+		logger.WithError(err).WithField("entry_id", entryID).Error("Error acquiring edit lock")
+		return nil, ErrInternal
+	}
+	service.setEditorName(logger, ctx, lock)
+	if lock.UserID != user.ID {
+		logger.WithField("entry_id", entryID).WithField("editor_user_id", lock.UserID).Info("Documentation entry is edited by another user")
+		return lock, ErrEntryBeingEdited
+	}
+	return lock, nil
+}
+
+// ReleaseEditLock removes the lock of an entry. Releasing an entry nobody edits succeeds, so that the frontend
+// can release the lock when the editor is closed without tracking whether it expired.
+func (service *EditLockServiceImpl) ReleaseEditLock(logger *logrus.Entry, ctx context.Context, entryID int, user *models.User) error {
+	lock, err := service.editLockStore.Get(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Error fetching edit lock")
+		return ErrInternal
+	}
+	if lock.UserID != user.ID && lock.IsActive(service.clock.Now()) && user.Role != string(data.RoleAdmin) {
+		logger.WithField("entry_id", entryID).Warn("Attempt to release the edit lock of another user")
+		return ErrPermissionDenied
+	}
+	if err := service.editLockStore.Release(ctx, entryID, lock.UserID); err != nil && !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).WithField("entry_id", entryID).Error("Error releasing edit lock")
+		return ErrInternal
+	}
+	return nil
+}
+
+// checkEntry returns ErrNotFound if the entry does not exist.
+func (service *EditLockServiceImpl) checkEntry(logger *logrus.Entry, ctx context.Context, entryID int) error {
+	if _, err := service.documentationEntryStore.GetByID(ctx, entryID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("entry_id", entryID).Error("Error fetching documentation entry for edit lock")
+		return ErrInternal
+	}
+	return nil
+}
+
+// setEditorName sets the name the other teachers see, e.g. "currently edited by Maria Schmidt". A name that
+// cannot be resolved is left empty, the lock still applies.
+func (service *EditLockServiceImpl) setEditorName(logger *logrus.Entry, ctx context.Context, lock *models.EditLock) {
+	user, err := service.userStore.GetByID(ctx, lock.UserID)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", lock.UserID).Warn("Error fetching the editor of an edit lock")
+		return
+	}
+	lock.EditorName = user.Username
+	teacher, err := findTeacherOfUser(ctx, service.teacherStore, user)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", lock.UserID).Warn("Error fetching the teacher of an edit lock")
+		return
+	}
+	if teacher != nil {
+		lock.EditorName = teacher.FirstName + " " + teacher.LastName
+	}
+}
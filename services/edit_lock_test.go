@@ -0,0 +1,97 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditLockService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+	maria := &models.User{ID: 2, Username: "maria.schmidt", Role: string(data.RoleTeacher)}
+	thomas := &models.User{ID: 3, Username: "thomas.weber", Role: string(data.RoleTeacher)}
+	admin := &models.User{ID: 1, Username: "admin", Role: string(data.RoleAdmin)}
+
+	setup := func() (*services.EditLockServiceImpl, *datamocks.MockEditLockStore, *datamocks.MockDocumentationEntryStore) {
+		lockStore := new(datamocks.MockEditLockStore)
+		entryStore := new(datamocks.MockDocumentationEntryStore)
+		userStore := new(datamocks.MockUserStore)
+		teacherStore := new(datamocks.MockTeacherStore)
+		userStore.On("GetByID", mock.Anything, maria.ID).Return(maria, nil).Maybe()
+		teacherStore.On("GetAll", mock.Anything).Return([]models.Teacher{{ID: 5, FirstName: "Maria", LastName: "Schmidt", Username: "maria.schmidt"}}, nil).Maybe()
+		return services.NewEditLockService(lockStore, entryStore, userStore, teacherStore, clock.NewFrozen(now)), lockStore, entryStore
+	}
+	marias := &models.EditLock{EntryID: 7, UserID: maria.ID, AcquiredAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Minute)}
+
+	t.Run("acquire", func(t *testing.T) {
+		service, lockStore, entryStore := setup()
+		entryStore.On("GetByID", mock.Anything, 7).Return(&models.DocumentationEntry{ID: 7}, nil).Once()
+		lockStore.On("Acquire", mock.Anything, mock.MatchedBy(func(lock *models.EditLock) bool {
+			return lock.UserID == maria.ID && lock.ExpiresAt.Equal(now.Add(services.EditLockTTL))
+		}), now).Return(&models.EditLock{EntryID: 7, UserID: maria.ID, AcquiredAt: now, ExpiresAt: now.Add(services.EditLockTTL)}, nil).Once()
+
+		lock, err := service.AcquireEditLock(logger, ctx, 7, maria)
+		require.NoError(t, err)
+		assert.Equal(t, "Maria Schmidt", lock.EditorName)
+		lockStore.AssertExpectations(t)
+	})
+
+	t.Run("entry edited by another user", func(t *testing.T) {
+		service, lockStore, entryStore := setup()
+		entryStore.On("GetByID", mock.Anything, 7).Return(&models.DocumentationEntry{ID: 7}, nil).Once()
+		lockStore.On("Acquire", mock.Anything, mock.Anything, now).Return(marias, nil).Once()
+
+		lock, err := service.AcquireEditLock(logger, ctx, 7, thomas)
+		assert.ErrorIs(t, err, services.ErrEntryBeingEdited)
+		require.NotNil(t, lock)
+		assert.Equal(t, "Maria Schmidt", lock.EditorName)
+	})
+
+	t.Run("unknown entry", func(t *testing.T) {
+		service, _, entryStore := setup()
+		entryStore.On("GetByID", mock.Anything, 8).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.AcquireEditLock(logger, ctx, 8, maria)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+
+	t.Run("expired locks are not reported", func(t *testing.T) {
+		service, lockStore, entryStore := setup()
+		entryStore.On("GetByID", mock.Anything, 7).Return(&models.DocumentationEntry{ID: 7}, nil).Once()
+		lockStore.On("Get", mock.Anything, 7).Return(&models.EditLock{EntryID: 7, UserID: maria.ID, ExpiresAt: now}, nil).Once()
+
+		lock, err := service.GetEditLock(logger, ctx, 7)
+		require.NoError(t, err)
+		assert.Nil(t, lock)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		service, lockStore, _ := setup()
+		lockStore.On("Get", mock.Anything, 7).Return(marias, nil)
+		assert.ErrorIs(t, service.ReleaseEditLock(logger, ctx, 7, thomas), services.ErrPermissionDenied)
+
+		lockStore.On("Release", mock.Anything, 7, maria.ID).Return(nil).Twice()
+		assert.NoError(t, service.ReleaseEditLock(logger, ctx, 7, maria))
+		assert.NoError(t, service.ReleaseEditLock(logger, ctx, 7, admin), "admins can release abandoned locks")
+		lockStore.AssertExpectations(t)
+	})
+
+	t.Run("releasing an entry nobody edits", func(t *testing.T) {
+		service, lockStore, _ := setup()
+		lockStore.On("Get", mock.Anything, 7).Return(nil, data.ErrNotFound).Once()
+		assert.NoError(t, service.ReleaseEditLock(logger, ctx, 7, maria))
+	})
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RuleKnownPlaceholder is violated by a placeholder that is not a variable of the email template.
+const RuleKnownPlaceholder = "known_placeholder"
+
+// placeholderPattern matches a placeholder like {{child_name}}, blanks inside the braces are allowed.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// defaultEmailTemplates are sent as long as the admins have not published a version of their own.
+var defaultEmailTemplates = map[string]models.EmailTemplate{
+	models.EmailTemplateReminder: {
+		Subject: "Erinnerung an die Dokumentation",
+		Body:    "Hallo {{teacher_name}},\n\n{{message}}\n\nViele Grüße\n{{facility_name}}\n",
+	},
+	models.EmailTemplateRejection: {
+		Subject: "Dokumentation zu {{child_name}} zurückgewiesen",
+		Body:    "Hallo {{teacher_name}},\n\nIhr Eintrag zu {{child_name}} vom {{observation_date}} wurde zurückgewiesen:\n\n{{reason}}\n\nBitte überarbeiten Sie den Eintrag und reichen Sie ihn erneut ein.\n\nViele Grüße\n{{facility_name}}\n",
+	},
+	models.EmailTemplateReportDelivery: {
+		Subject: "Entwicklungsbericht für {{child_name}}",
+		Body:    "Hallo {{recipient_name}},\n\nder Entwicklungsbericht für {{child_name}} vom {{report_date}} steht zum Herunterladen bereit.\n\nViele Grüße\n{{facility_name}}\n",
+	},
+}
+
+// EmailTemplateService defines the interface for the email templates the admins edit without a new release.
+type EmailTemplateService interface {
+	GetEmailTemplates(logger *logrus.Entry, ctx context.Context) ([]models.EmailTemplate, error)
+	GetEmailTemplateVersions(logger *logrus.Entry, ctx context.Context, key string) ([]models.EmailTemplate, error)
+	PublishEmailTemplate(logger *logrus.Entry, ctx context.Context, actingUserID int, template *models.EmailTemplate) (*models.EmailTemplate, error)
+	PreviewEmailTemplate(logger *logrus.Entry, ctx context.Context, template *models.EmailTemplate, variables map[string]string) (*models.RenderedEmail, error)
+	EmailMessage(logger *logrus.Entry, ctx context.Context, key string, to string, variables map[string]string) (models.OutboxMessage, error)
+}
+
+// EmailTemplateServiceImpl implements EmailTemplateService.
+type EmailTemplateServiceImpl struct {
+	templateStore   data.EmailTemplateStore
+	auditLogService AuditLogService // Optional, nil disables the audit trail
+	clock           clock.Clock
+}
+
+// NewEmailTemplateService creates a new EmailTemplateServiceImpl.
+func NewEmailTemplateService(templateStore data.EmailTemplateStore, auditLogService AuditLogService, clock clock.Clock) *EmailTemplateServiceImpl {
+	return &EmailTemplateServiceImpl{
+		templateStore:   templateStore,
+		auditLogService: auditLogService,
+		clock:           clock,
+	}
+}
+
+// GetEmailTemplates fetches the current version of every template, ordered by key.
+func (service *EmailTemplateServiceImpl) GetEmailTemplates(logger *logrus.Entry, ctx context.Context) ([]models.EmailTemplate, error) {
+	keys := make([]string, 0, len(defaultEmailTemplates))
+	for key := range defaultEmailTemplates {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	templates := make([]models.EmailTemplate, 0, len(keys))
+	for _, key := range keys {
+		template, err := service.currentTemplate(ctx, key)
+		if err != nil {
+			logger.WithError(err).WithField("template_key", key).Error("Error fetching current email template")
+			return nil, ErrInternal
+		}
+		templates = append(templates, *template)
+	}
+	return templates, nil
+}
+
+// GetEmailTemplateVersions fetches all versions of a template, newest first and ending with the built-in default.
+func (service *EmailTemplateServiceImpl) GetEmailTemplateVersions(logger *logrus.Entry, ctx context.Context, key string) ([]models.EmailTemplate, error) {
+	if _, ok := defaultEmailTemplates[key]; !ok {
+		return nil, ErrNotFound
+	}
+	versions, err := service.templateStore.GetVersions(ctx, key)
+	if err != nil {
+		logger.WithError(err).WithField("template_key", key).Error("Error fetching email template versions")
+		return nil, ErrInternal
+	}
+	return append(versions, defaultEmailTemplate(key)), nil
+}
+
+// PublishEmailTemplate publishes a new version of a template, which is used for all emails sent from then on.
+// Placeholders must be among the variables of the template.
+func (service *EmailTemplateServiceImpl) PublishEmailTemplate(logger *logrus.Entry, ctx context.Context, actingUserID int, template *models.EmailTemplate) (*models.EmailTemplate, error) {
+	if _, ok := defaultEmailTemplates[template.Key]; !ok {
+		return nil, ErrNotFound
+	}
+	if err := validateEmailTemplate(*template); err != nil {
+		logger.WithError(err).Warn("Invalid input for PublishEmailTemplate")
+		return nil, err
+	}
+	template.PublishedByUserID = &actingUserID
+
+	version, err := service.templateStore.Publish(ctx, template)
+	if err != nil {
+		logger.WithError(err).WithField("template_key", template.Key).Error("Error publishing email template")
+		return nil, ErrInternal
+	}
+	published, err := service.templateStore.GetCurrent(ctx, template.Key)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"template_key": template.Key, "version": version}).Error("Error fetching published email template")
+		return nil, ErrInternal
+	}
+
+	if service.auditLogService != nil {
+		// The version has already been published, so a failing audit write is only logged.
+		_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+			Action:      models.AuditActionPublishEmailTemplate,
+			EntityType:  "email_template",
+			EntityID:    &version,
+			ActorUserID: &actingUserID,
+			Details:     template.Key,
+		})
+	}
+	logger.WithFields(logrus.Fields{"template_key": template.Key, "version": version}).Info("Email template published")
+	return published, nil
+}
+
+// PreviewEmailTemplate renders a template as it would be sent, without publishing it. Variables that are not
+// given are filled with example values. An empty subject and body preview the current version of the key.
+func (service *EmailTemplateServiceImpl) PreviewEmailTemplate(logger *logrus.Entry, ctx context.Context, template *models.EmailTemplate, variables map[string]string) (*models.RenderedEmail, error) {
+	examples, ok := models.EmailTemplateVariables[template.Key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if template.Subject == "" && template.Body == "" {
+		current, err := service.currentTemplate(ctx, template.Key)
+		if err != nil {
+			logger.WithError(err).WithField("template_key", template.Key).Error("Error fetching current email template for preview")
+			return nil, ErrInternal
+		}
+		template = current
+	}
+	if err := validateEmailTemplate(*template); err != nil {
+		logger.WithError(err).Warn("Invalid input for PreviewEmailTemplate")
+		return nil, err
+	}
+
+	values := make(map[string]string, len(examples))
+	for name, example := range examples {
+		values[name] = example
+	}
+	for name, value := range variables {
+		if _, ok := examples[name]; ok {
+			values[name] = value
+		}
+	}
+	return renderEmailTemplate(*template, values), nil
+}
+
+// EmailMessage builds an outbox message from the current version of a template. Variables that are not given
+// are left empty. Senders enqueue it together with the change it reports, the mailer delivers it.
+func (service *EmailTemplateServiceImpl) EmailMessage(logger *logrus.Entry, ctx context.Context, key string, to string, variables map[string]string) (models.OutboxMessage, error) {
+	template, err := service.currentTemplate(ctx, key)
+	if err != nil {
+		logger.WithError(err).WithField("template_key", key).Error("Error fetching current email template")
+		return models.OutboxMessage{}, ErrInternal
+	}
+	rendered := renderEmailTemplate(*template, variables)
+	payload, err := json.Marshal(models.EmailOutboxPayload{To: to, Subject: rendered.Subject, Body: rendered.Body})
+	if err != nil {
+		return models.OutboxMessage{}, err
+	}
+	return models.OutboxMessage{Channel: models.OutboxChannelEmail, Payload: payload, NextAttemptAt: service.clock.Now()}, nil
+}
+
+// currentTemplate fetches the latest published version of a template, falling back to the built-in default.
+func (service *EmailTemplateServiceImpl) currentTemplate(ctx context.Context, key string) (*models.EmailTemplate, error) {
+	template, err := service.templateStore.GetCurrent(ctx, key)
+	if errors.Is(err, data.ErrNotFound) {
+		fallback := defaultEmailTemplate(key)
+		return &fallback, nil
+	}
+	return template, err
+}
+
+// defaultEmailTemplate returns the built-in default of a template as version 0.
+func defaultEmailTemplate(key string) models.EmailTemplate {
+	template := defaultEmailTemplates[key]
+	template.Key = key
+	template.ChangeSummary = "Standardvorlage"
+	return template
+}
+
+// validateEmailTemplate validates the fields of a template and that it only uses the placeholders of its key.
+func validateEmailTemplate(template models.EmailTemplate) error {
+	if err := models.ValidateEmailTemplate(template); err != nil {
+		return invalidInput(err)
+	}
+	variables := models.EmailTemplateVariables[template.Key]
+	var violations []RuleViolation
+	for _, field := range []struct{ name, text string }{{"subject", template.Subject}, {"body", template.Body}} {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(field.text, -1) {
+			if _, ok := variables[match[1]]; ok {
+				continue
+			}
+			violations = append(violations, RuleViolation{
+				Rule:    RuleKnownPlaceholder,
+				Field:   field.name,
+				Message: "unknown placeholder " + match[0],
+				Text:    "Unbekannter Platzhalter " + match[0],
+				Params:  map[string]any{"placeholder": match[1]},
+			})
+		}
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// renderEmailTemplate replaces the placeholders of a template with the values of its variables.
+func renderEmailTemplate(template models.EmailTemplate, values map[string]string) *models.RenderedEmail {
+	replace := func(text string) string {
+		return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+			return values[placeholderPattern.FindStringSubmatch(placeholder)[1]]
+		})
+	}
+	// Line breaks in a value would start new header lines in the subject.
+	subject := strings.Join(strings.Fields(replace(template.Subject)), " ")
+	return &models.RenderedEmail{Subject: subject, Body: replace(template.Body)}
+}
@@ -0,0 +1,136 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// emailTemplateTestTime is the time of the clock of the email template service in the tests.
+var emailTemplateTestTime = time.Date(2025, time.March, 5, 9, 0, 0, 0, time.UTC)
+
+func newEmailTemplateService() (*services.EmailTemplateServiceImpl, *datamocks.MockEmailTemplateStore, *datamocks.MockAuditLogStore) {
+	templateStore := new(datamocks.MockEmailTemplateStore)
+	auditLogStore := new(datamocks.MockAuditLogStore)
+	return services.NewEmailTemplateService(templateStore, services.NewAuditLogService(auditLogStore), clock.NewFrozen(emailTemplateTestTime)), templateStore, auditLogStore
+}
+
+func TestPublishEmailTemplate(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		service, templateStore, auditLogStore := newEmailTemplateService()
+		template := &models.EmailTemplate{Key: models.EmailTemplateRejection, Subject: "Eintrag zu {{ child_name }}", Body: "Grund: {{reason}}"}
+		templateStore.On("Publish", mock.Anything, template).Return(2, nil).Once()
+		templateStore.On("GetCurrent", mock.Anything, models.EmailTemplateRejection).Return(&models.EmailTemplate{Key: models.EmailTemplateRejection, Version: 2}, nil).Once()
+		auditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionPublishEmailTemplate && *entry.EntityID == 2 && entry.Details == models.EmailTemplateRejection
+		})).Return(1, nil).Once()
+
+		published, err := service.PublishEmailTemplate(logger, ctx, 1, template)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, published.Version)
+		assert.Equal(t, 1, *template.PublishedByUserID)
+		auditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("unknown placeholder", func(t *testing.T) {
+		service, templateStore, _ := newEmailTemplateService()
+
+		_, err := service.PublishEmailTemplate(logger, ctx, 1, &models.EmailTemplate{Key: models.EmailTemplateReminder, Subject: "Erinnerung", Body: "Hallo {{child_name}}"})
+
+		var validationError *services.ValidationError
+		require.True(t, errors.As(err, &validationError))
+		require.Len(t, validationError.Violations, 1)
+		assert.Equal(t, services.RuleKnownPlaceholder, validationError.Violations[0].Rule)
+		assert.Equal(t, "body", validationError.Violations[0].Field)
+		templateStore.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		service, templateStore, _ := newEmailTemplateService()
+
+		_, err := service.PublishEmailTemplate(logger, ctx, 1, &models.EmailTemplate{Key: "welcome", Subject: "Willkommen", Body: "Hallo"})
+
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		templateStore.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetEmailTemplates(t *testing.T) {
+	service, templateStore, _ := newEmailTemplateService()
+	templateStore.On("GetCurrent", mock.Anything, models.EmailTemplateRejection).Return(nil, data.ErrNotFound).Once()
+	templateStore.On("GetCurrent", mock.Anything, models.EmailTemplateReminder).Return(&models.EmailTemplate{Key: models.EmailTemplateReminder, Version: 3, Subject: "Erinnerung", Body: "{{message}}"}, nil).Once()
+	templateStore.On("GetCurrent", mock.Anything, models.EmailTemplateReportDelivery).Return(nil, data.ErrNotFound).Once()
+
+	templates, err := service.GetEmailTemplates(logrus.NewEntry(logrus.New()), context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, templates, 3)
+	assert.Equal(t, models.EmailTemplateRejection, templates[0].Key)
+	assert.Equal(t, 0, templates[0].Version) // Built-in default
+	assert.NotEmpty(t, templates[0].Body)
+	assert.Equal(t, 3, templates[1].Version)
+}
+
+func TestPreviewEmailTemplate(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("draft with example values", func(t *testing.T) {
+		service, templateStore, _ := newEmailTemplateService()
+		template := &models.EmailTemplate{Key: models.EmailTemplateReportDelivery, Subject: "Bericht für {{child_name}}", Body: "{{facility_name}}: {{report_date}}"}
+
+		rendered, err := service.PreviewEmailTemplate(logger, ctx, template, map[string]string{"report_date": "01.07.2025", "unknown": "x"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Bericht für Max Mustermann", rendered.Subject)
+		assert.Equal(t, "Kita Sonnenschein: 01.07.2025", rendered.Body)
+		templateStore.AssertNotCalled(t, "GetCurrent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("current version", func(t *testing.T) {
+		service, templateStore, _ := newEmailTemplateService()
+		templateStore.On("GetCurrent", mock.Anything, models.EmailTemplateReminder).Return(&models.EmailTemplate{Key: models.EmailTemplateReminder, Version: 1, Subject: "Erinnerung", Body: "Hallo {{teacher_name}}"}, nil).Once()
+
+		rendered, err := service.PreviewEmailTemplate(logger, ctx, &models.EmailTemplate{Key: models.EmailTemplateReminder}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Hallo Anna Schmidt", rendered.Body)
+	})
+}
+
+func TestEmailMessage(t *testing.T) {
+	service, templateStore, _ := newEmailTemplateService()
+	templateStore.On("GetCurrent", mock.Anything, models.EmailTemplateReminder).Return(&models.EmailTemplate{Key: models.EmailTemplateReminder, Version: 1, Subject: "Erinnerung {{facility_name}}", Body: "Hallo {{teacher_name}},\n{{message}}"}, nil).Once()
+
+	message, err := service.EmailMessage(logrus.NewEntry(logrus.New()), context.Background(), models.EmailTemplateReminder, "anna@example.org", map[string]string{
+		"teacher_name":  "Anna",
+		"message":       "Bitte dokumentieren.",
+		"facility_name": "Kita\r\nBcc: x@example.org",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.OutboxChannelEmail, message.Channel)
+	assert.Equal(t, emailTemplateTestTime, message.NextAttemptAt)
+	var payload models.EmailOutboxPayload
+	require.NoError(t, json.Unmarshal(message.Payload, &payload))
+	assert.Equal(t, "anna@example.org", payload.To)
+	assert.Equal(t, "Erinnerung Kita Bcc: x@example.org", payload.Subject) // No header injection
+	assert.Equal(t, "Hallo Anna,\nBitte dokumentieren.", payload.Body)
+}
@@ -15,4 +15,172 @@ var (
 	ErrBulkImportFailed            = errors.New("bulk import failed")
 	ErrPermissionDenied            = errors.New("permission denied")
 	ErrForeignKeyConstraint        = errors.New("foreign key constraint violation")
+	ErrInvalidStateTransition      = errors.New("invalid state transition")
+	ErrUndeliverable               = errors.New("undeliverable")
+	ErrLocked                      = errors.New("locked")
+	ErrCanceled                    = errors.New("request canceled")
+	ErrBusy                        = errors.New("too many documents being generated")
+	ErrScanUnavailable             = errors.New("virus scanner unavailable")
 )
+
+// DomainError is a business error with a stable code, so that clients do not have to match on the message.
+// It unwraps to the generic error it is a case of, e.g. ErrChildNotFound is ErrNotFound.
+type DomainError struct {
+	Code    string
+	Message string
+	Kind    error
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func (e *DomainError) Unwrap() error {
+	return e.Kind
+}
+
+// Codes of the domain errors, reported in the "code" field of error responses.
+const (
+	CodeChildNotFound            = "CHILD_NOT_FOUND"
+	CodeTeacherNotFound          = "TEACHER_NOT_FOUND"
+	CodeTeacherDeactivated       = "TEACHER_DEACTIVATED"
+	CodeCategoryNotFound         = "CATEGORY_NOT_FOUND"
+	CodeCategoryArchived         = "CATEGORY_ARCHIVED"
+	CodeEntryAlreadyApproved     = "ENTRY_ALREADY_APPROVED"
+	CodeAssignmentAlreadyEnded   = "ASSIGNMENT_ALREADY_ENDED"
+	CodeAssignmentEndBeforeStart = "ASSIGNMENT_END_BEFORE_START"
+	CodeRegistrationClosed       = "REGISTRATION_CLOSED"
+	CodeInvitationNotFound       = "INVITATION_NOT_FOUND"
+	CodeInvitationExpired        = "INVITATION_EXPIRED"
+	CodeInvitationAlreadyUsed    = "INVITATION_ALREADY_USED"
+	CodeEntryBeingEdited         = "ENTRY_BEING_EDITED"
+	CodeShareLinkNotFound        = "SHARE_LINK_NOT_FOUND"
+	CodeShareLinkExpired         = "SHARE_LINK_EXPIRED"
+	CodeShareLinkRevoked         = "SHARE_LINK_REVOKED"
+	CodeShareLinkTooLong         = "SHARE_LINK_VALIDITY_TOO_LONG"
+	CodeInvalidAccessCode        = "INVALID_ACCESS_CODE"
+	CodeChildNotU3               = "CHILD_NOT_U3"
+	CodeChildNotInGroup          = "CHILD_NOT_IN_GROUP"
+	CodeCareDateInFuture         = "CARE_DATE_IN_FUTURE"
+	CodeMedicationConfirmed      = "MEDICATION_ALREADY_CONFIRMED"
+	CodeMedicationSameConfirmer  = "MEDICATION_CONFIRMER_NOT_SECOND_USER"
+	CodePhotoNotFound            = "PHOTO_NOT_FOUND"
+	CodePhotoConsentMissing      = "PHOTO_CONSENT_MISSING"
+	CodePhotoNotOfChild          = "PHOTO_NOT_OF_CHILD"
+	CodeChildDocumentNotFound    = "CHILD_DOCUMENT_NOT_FOUND"
+	CodeAttendanceNotFound       = "ATTENDANCE_NOT_FOUND"
+	CodeAttendanceInFuture       = "ATTENDANCE_IN_FUTURE"
+	CodeCheckInCodeUnknown       = "CHECKIN_CODE_UNKNOWN"
+	CodeCareContractNotFound     = "CARE_CONTRACT_NOT_FOUND"
+	CodeCareContractOverlap      = "CARE_CONTRACT_OVERLAP"
+	CodeProjectNotFound          = "PROJECT_NOT_FOUND"
+	CodeGroupNotFound            = "GROUP_NOT_FOUND"
+	CodeTeamMeetingNotFound      = "TEAM_MEETING_NOT_FOUND"
+	CodeActionItemNotFound       = "ACTION_ITEM_NOT_FOUND"
+	CodeEntryOutsideProject      = "ENTRY_OUTSIDE_PROJECT"
+	CodeHandoverNotFound         = "HANDOVER_NOT_FOUND"
+	CodeHandoverConfirmed        = "HANDOVER_ALREADY_CONFIRMED"
+	CodeHandoverSameGroup        = "HANDOVER_TO_SAME_GROUP"
+	CodeNotReceivingTeacher      = "NOT_RECEIVING_TEACHER"
+	CodeBenchmarkingDisabled     = "BENCHMARKING_DISABLED"
+	CodeBenchmarkOwnFacility     = "BENCHMARK_OWN_FACILITY"
+	CodeUploadInfected           = "UPLOAD_INFECTED"
+	CodePhotoHEIC                = "PHOTO_HEIC_UNSUPPORTED"
+	CodeQuarantineNotFound       = "QUARANTINED_UPLOAD_NOT_FOUND"
+	CodeUploadNotFound           = "UPLOAD_NOT_FOUND"
+	CodeUploadTooLarge           = "UPLOAD_TOO_LARGE"
+	CodeUploadOffsetMismatch     = "UPLOAD_OFFSET_MISMATCH"
+	CodeUploadChecksumMismatch   = "UPLOAD_CHECKSUM_MISMATCH"
+	CodeUploadIncomplete         = "UPLOAD_INCOMPLETE"
+	CodeUploadKindMismatch       = "UPLOAD_KIND_MISMATCH"
+	CodeClientEncryptionDisabled = "CLIENT_ENCRYPTION_DISABLED"
+	CodeClientEncryptionRequired = "CLIENT_ENCRYPTION_REQUIRED"
+	CodePhotoEncrypted           = "PHOTO_ENCRYPTED"
+	CodeBreakGlassNotFound       = "BREAK_GLASS_NOT_FOUND"
+	CodeBreakGlassRevoked        = "BREAK_GLASS_REVOKED"
+	CodeInvalidBreakGlassCode    = "INVALID_BREAK_GLASS_CODE"
+	CodeDeviceTokenTaken         = "DEVICE_TOKEN_TAKEN"
+	CodeDemoModeUnsupported      = "DEMO_MODE_UNSUPPORTED"
+	// CodeValidationFailed is reported together with the violations of a *ValidationError.
+	CodeValidationFailed = "VALIDATION_FAILED"
+)
+
+var (
+	ErrChildNotFound            = &DomainError{Code: CodeChildNotFound, Message: "child not found", Kind: ErrNotFound}
+	ErrTeacherNotFound          = &DomainError{Code: CodeTeacherNotFound, Message: "teacher not found", Kind: ErrNotFound}
+	ErrTeacherDeactivated       = &DomainError{Code: CodeTeacherDeactivated, Message: "teacher is deactivated and cannot get new assignments", Kind: ErrInvalidStateTransition}
+	ErrCategoryNotFound         = &DomainError{Code: CodeCategoryNotFound, Message: "category not found", Kind: ErrNotFound}
+	ErrCategoryArchived         = &DomainError{Code: CodeCategoryArchived, Message: "category is archived and cannot get new entries", Kind: ErrInvalidStateTransition}
+	ErrEntryAlreadyApproved     = &DomainError{Code: CodeEntryAlreadyApproved, Message: "documentation entry is already approved", Kind: ErrInvalidStateTransition}
+	ErrAssignmentAlreadyEnded   = &DomainError{Code: CodeAssignmentAlreadyEnded, Message: "assignment has already ended", Kind: ErrInvalidStateTransition}
+	ErrAssignmentEndBeforeStart = &DomainError{Code: CodeAssignmentEndBeforeStart, Message: "assignment end date cannot be before start date", Kind: ErrInvalidInput}
+	ErrRegistrationClosed       = &DomainError{Code: CodeRegistrationClosed, Message: "open registration is disabled, ask an admin for an invitation", Kind: ErrPermissionDenied}
+	ErrInvitationNotFound       = &DomainError{Code: CodeInvitationNotFound, Message: "invitation not found", Kind: ErrNotFound}
+	ErrInvitationExpired        = &DomainError{Code: CodeInvitationExpired, Message: "invitation has expired", Kind: ErrInvalidStateTransition}
+	ErrInvitationAlreadyUsed    = &DomainError{Code: CodeInvitationAlreadyUsed, Message: "invitation has already been used", Kind: ErrInvalidStateTransition}
+	ErrEntryBeingEdited         = &DomainError{Code: CodeEntryBeingEdited, Message: "documentation entry is being edited by another user", Kind: ErrLocked}
+	ErrShareLinkNotFound        = &DomainError{Code: CodeShareLinkNotFound, Message: "share link not found", Kind: ErrNotFound}
+	ErrShareLinkExpired         = &DomainError{Code: CodeShareLinkExpired, Message: "share link has expired", Kind: ErrInvalidStateTransition}
+	ErrShareLinkRevoked         = &DomainError{Code: CodeShareLinkRevoked, Message: "share link has been revoked", Kind: ErrInvalidStateTransition}
+	ErrShareLinkTooLong         = &DomainError{Code: CodeShareLinkTooLong, Message: "share link validity exceeds the configured maximum", Kind: ErrInvalidInput}
+	ErrInvalidAccessCode        = &DomainError{Code: CodeInvalidAccessCode, Message: "access code is wrong", Kind: ErrPermissionDenied}
+	ErrChildNotU3               = &DomainError{Code: CodeChildNotU3, Message: "daily care is only logged for children under three years", Kind: ErrInvalidInput}
+	ErrChildNotInGroup          = &DomainError{Code: CodeChildNotInGroup, Message: "child is not in the group", Kind: ErrInvalidInput}
+	ErrCareDateInFuture         = &DomainError{Code: CodeCareDateInFuture, Message: "daily care cannot be logged for a future day", Kind: ErrInvalidInput}
+	ErrMedicationConfirmed      = &DomainError{Code: CodeMedicationConfirmed, Message: "medication administration is already confirmed", Kind: ErrInvalidStateTransition}
+	ErrMedicationSameConfirmer  = &DomainError{Code: CodeMedicationSameConfirmer, Message: "medication administration must be confirmed by a second user", Kind: ErrPermissionDenied}
+	ErrPhotoNotFound            = &DomainError{Code: CodePhotoNotFound, Message: "photo not found", Kind: ErrNotFound}
+	ErrPhotoConsentMissing      = &DomainError{Code: CodePhotoConsentMissing, Message: "photo shows a child without photo consent", Kind: ErrPermissionDenied}
+	ErrPhotoNotOfChild          = &DomainError{Code: CodePhotoNotOfChild, Message: "photo does not show the child", Kind: ErrInvalidInput}
+	ErrChildDocumentNotFound    = &DomainError{Code: CodeChildDocumentNotFound, Message: "child document not found", Kind: ErrNotFound}
+	ErrAttendanceNotFound       = &DomainError{Code: CodeAttendanceNotFound, Message: "attendance record not found", Kind: ErrNotFound}
+	ErrAttendanceInFuture       = &DomainError{Code: CodeAttendanceInFuture, Message: "attendance cannot be recorded for the future", Kind: ErrInvalidInput}
+	ErrCheckInCodeUnknown       = &DomainError{Code: CodeCheckInCodeUnknown, Message: "check-in code is unknown or has been revoked", Kind: ErrNotFound}
+	ErrCareContractNotFound     = &DomainError{Code: CodeCareContractNotFound, Message: "care contract not found", Kind: ErrNotFound}
+	ErrCareContractOverlap      = &DomainError{Code: CodeCareContractOverlap, Message: "care contract overlaps another contract of the child", Kind: ErrAlreadyExists}
+	ErrProjectNotFound          = &DomainError{Code: CodeProjectNotFound, Message: "project not found", Kind: ErrNotFound}
+	ErrGroupNotFound            = &DomainError{Code: CodeGroupNotFound, Message: "group not found", Kind: ErrNotFound}
+	ErrTeamMeetingNotFound      = &DomainError{Code: CodeTeamMeetingNotFound, Message: "team meeting not found", Kind: ErrNotFound}
+	ErrActionItemNotFound       = &DomainError{Code: CodeActionItemNotFound, Message: "action item not found", Kind: ErrNotFound}
+	ErrEntryOutsideProject      = &DomainError{Code: CodeEntryOutsideProject, Message: "observation date lies outside the period of the project", Kind: ErrInvalidInput}
+	ErrHandoverNotFound         = &DomainError{Code: CodeHandoverNotFound, Message: "group handover not found", Kind: ErrNotFound}
+	ErrHandoverConfirmed        = &DomainError{Code: CodeHandoverConfirmed, Message: "group handover is already confirmed", Kind: ErrInvalidStateTransition}
+	ErrHandoverSameGroup        = &DomainError{Code: CodeHandoverSameGroup, Message: "child is already in the receiving group", Kind: ErrInvalidInput}
+	ErrNotReceivingTeacher      = &DomainError{Code: CodeNotReceivingTeacher, Message: "only a teacher of the receiving group can confirm the handover", Kind: ErrPermissionDenied}
+	ErrBenchmarkingDisabled     = &DomainError{Code: CodeBenchmarkingDisabled, Message: "cross-facility benchmarking is not enabled", Kind: ErrPermissionDenied}
+	ErrBenchmarkOwnFacility     = &DomainError{Code: CodeBenchmarkOwnFacility, Message: "the aggregate of the own facility cannot be imported", Kind: ErrInvalidInput}
+	ErrUploadInfected           = &DomainError{Code: CodeUploadInfected, Message: "the file contains malware and has been quarantined", Kind: ErrInvalidInput}
+	ErrPhotoHEIC                = &DomainError{Code: CodePhotoHEIC, Message: "HEIC photos cannot be stored, please upload them as JPEG", Kind: ErrInvalidInput}
+	ErrQuarantineNotFound       = &DomainError{Code: CodeQuarantineNotFound, Message: "quarantined upload not found", Kind: ErrNotFound}
+	ErrUploadNotFound           = &DomainError{Code: CodeUploadNotFound, Message: "upload not found or expired", Kind: ErrNotFound}
+	ErrUploadTooLarge           = &DomainError{Code: CodeUploadTooLarge, Message: "the file is larger than allowed for its kind", Kind: ErrInvalidInput}
+	ErrUploadOffsetMismatch     = &DomainError{Code: CodeUploadOffsetMismatch, Message: "the chunk does not continue the upload, resume at the received offset", Kind: ErrInvalidStateTransition}
+	ErrUploadChecksumMismatch   = &DomainError{Code: CodeUploadChecksumMismatch, Message: "the received data does not match its checksum", Kind: ErrInvalidInput}
+	ErrUploadIncomplete         = &DomainError{Code: CodeUploadIncomplete, Message: "the upload has not been completed", Kind: ErrInvalidStateTransition}
+	ErrUploadKindMismatch       = &DomainError{Code: CodeUploadKindMismatch, Message: "the upload is for another kind of file", Kind: ErrInvalidInput}
+	ErrClientEncryptionDisabled = &DomainError{Code: CodeClientEncryptionDisabled, Message: "client-side encrypted attachments are not enabled", Kind: ErrPermissionDenied}
+	ErrClientEncryptionRequired = &DomainError{Code: CodeClientEncryptionRequired, Message: "attachments must be encrypted by the client", Kind: ErrInvalidInput}
+	ErrPhotoEncrypted           = &DomainError{Code: CodePhotoEncrypted, Message: "client-side encrypted photos cannot be embedded in reports", Kind: ErrInvalidInput}
+	ErrBreakGlassNotFound       = &DomainError{Code: CodeBreakGlassNotFound, Message: "break-glass credential not found", Kind: ErrNotFound}
+	ErrBreakGlassRevoked        = &DomainError{Code: CodeBreakGlassRevoked, Message: "break-glass credential has already been revoked", Kind: ErrInvalidStateTransition}
+	ErrInvalidBreakGlassCode    = &DomainError{Code: CodeInvalidBreakGlassCode, Message: "break-glass code is wrong", Kind: ErrPermissionDenied}
+	ErrDeviceTokenTaken         = &DomainError{Code: CodeDeviceTokenTaken, Message: "the device token is registered to another user", Kind: ErrAlreadyExists}
+	ErrDemoModeUnsupported      = &DomainError{Code: CodeDemoModeUnsupported, Message: "the demo mode needs the SQLite database driver", Kind: ErrInvalidStateTransition}
+)
+
+// DomainErrors are all domain errors, the API description lists their codes.
+var DomainErrors = []*DomainError{
+	ErrChildNotFound, ErrTeacherNotFound, ErrTeacherDeactivated, ErrCategoryNotFound, ErrCategoryArchived,
+	ErrEntryAlreadyApproved, ErrAssignmentAlreadyEnded, ErrAssignmentEndBeforeStart, ErrRegistrationClosed,
+	ErrInvitationNotFound, ErrInvitationExpired, ErrInvitationAlreadyUsed, ErrEntryBeingEdited, ErrShareLinkNotFound,
+	ErrShareLinkExpired, ErrShareLinkRevoked, ErrShareLinkTooLong, ErrInvalidAccessCode, ErrChildNotU3,
+	ErrChildNotInGroup, ErrCareDateInFuture, ErrMedicationConfirmed, ErrMedicationSameConfirmer, ErrPhotoNotFound,
+	ErrPhotoConsentMissing, ErrPhotoNotOfChild, ErrChildDocumentNotFound, ErrAttendanceNotFound, ErrAttendanceInFuture,
+	ErrCheckInCodeUnknown, ErrCareContractNotFound, ErrCareContractOverlap, ErrProjectNotFound, ErrGroupNotFound,
+	ErrTeamMeetingNotFound, ErrActionItemNotFound, ErrEntryOutsideProject, ErrHandoverNotFound, ErrHandoverConfirmed,
+	ErrHandoverSameGroup, ErrNotReceivingTeacher, ErrBenchmarkingDisabled, ErrBenchmarkOwnFacility, ErrUploadInfected,
+	ErrPhotoHEIC, ErrQuarantineNotFound, ErrUploadNotFound, ErrUploadTooLarge, ErrUploadOffsetMismatch,
+	ErrUploadChecksumMismatch, ErrUploadIncomplete, ErrUploadKindMismatch, ErrClientEncryptionDisabled,
+	ErrClientEncryptionRequired, ErrPhotoEncrypted, ErrBreakGlassNotFound, ErrBreakGlassRevoked,
+	ErrInvalidBreakGlassCode, ErrDeviceTokenTaken, ErrDemoModeUnsupported,
+}
@@ -0,0 +1,43 @@
+package services_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"kitadoc-backend/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDomainErrorsAreListed makes sure that the API description documents every error code.
+func TestDomainErrorsAreListed(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "errors.go", nil, 0)
+	require.NoError(t, err)
+	declared := []string{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			if !strings.HasPrefix(value.Names[0].Name, "Code") || value.Names[0].Name == "CodeValidationFailed" {
+				continue
+			}
+			code, err := strconv.Unquote(value.Values[0].(*ast.BasicLit).Value)
+			require.NoError(t, err)
+			declared = append(declared, code)
+		}
+	}
+
+	listed := []string{}
+	for _, domainError := range services.DomainErrors {
+		listed = append(listed, domainError.Code)
+	}
+	assert.ElementsMatch(t, declared, listed)
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FeeExportService defines the interface for the export of the care of children for the fee accounting of the
+// municipality.
+type FeeExportService interface {
+	// ExportFees returns the booked and attended hours of the children in the month of the given day as CSV, in
+	// the columns configured for the fee accounting. The export is recorded in the audit log.
+	ExportFees(logger *logrus.Entry, ctx context.Context, month models.Date, actingUserID int) ([]byte, error)
+}
+
+// FeeExportServiceImpl implements FeeExportService.
+type FeeExportServiceImpl struct {
+	childStore        data.ChildStore
+	careContractStore data.CareContractStore
+	attendanceStore   data.AttendanceStore
+	auditLogService   AuditLogService
+	config            *config.Config
+	clock             clock.Clock
+}
+
+// NewFeeExportService creates a new FeeExportServiceImpl.
+func NewFeeExportService(
+	childStore data.ChildStore,
+	careContractStore data.CareContractStore,
+	attendanceStore data.AttendanceStore,
+	auditLogService AuditLogService,
+	cfg *config.Config,
+	clock clock.Clock,
+) *FeeExportServiceImpl {
+	return &FeeExportServiceImpl{
+		childStore:        childStore,
+		careContractStore: careContractStore,
+		attendanceStore:   attendanceStore,
+		auditLogService:   auditLogService,
+		config:            cfg,
+		clock:             clock,
+	}
+}
+
+// ExportFees exports every child that had a care contract or attended in the month, including children who have
+// left since. Months that have not started yet cannot be exported.
+func (service *FeeExportServiceImpl) ExportFees(logger *logrus.Entry, ctx context.Context, month models.Date, actingUserID int) ([]byte, error) {
+	first := models.NewDate(month.Year(), month.Month(), 1)
+	last := models.DateOf(first.AddDate(0, 1, -1))
+	if first.After(models.Today(service.clock.Now()).Time) {
+		logger.WithField("month", first).Warn("Fee export requested for a future month")
+		return nil, ErrInvalidInput
+	}
+
+	rows, err := service.rows(logger, ctx, first, last)
+	if err != nil {
+		return nil, err
+	}
+	content, err := service.writeCSV(rows)
+	if err != nil {
+		logger.WithError(err).Error("Error writing fee export")
+		return nil, ErrInternal
+	}
+
+	// An export is only handed out once it is recorded.
+	if err := service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:      models.AuditActionExportFees,
+		EntityType:  "fee_export",
+		ActorUserID: &actingUserID,
+		Details:     fmt.Sprintf("month=%s children=%d", first.Format("2006-01"), len(rows)),
+	}); err != nil {
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{"month": first, "children": len(rows), "user_id": actingUserID}).Info("Fee export created")
+	return content, nil
+}
+
+func (service *FeeExportServiceImpl) rows(logger *logrus.Entry, ctx context.Context, first, last models.Date) ([]models.FeeExportRow, error) {
+	children, err := service.childStore.GetAllIncludingArchived(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching children for fee export")
+		return nil, ErrInternal
+	}
+	contracts, err := service.careContractStore.GetValidBetween(ctx, first, last)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching care contracts for fee export")
+		return nil, ErrInternal
+	}
+	// Ordered by the start of their period, so the first one is valid at the start of the month or starts first
+	bookedHours := make(map[int]int, len(contracts))
+	for _, contract := range contracts {
+		if _, ok := bookedHours[contract.ChildID]; !ok {
+			bookedHours[contract.ChildID] = contract.WeeklyHours
+		}
+	}
+
+	rows := []models.FeeExportRow{}
+	for _, child := range children {
+		hours, booked := bookedHours[child.ID]
+		// Children who had left before the month cannot have attended in it
+		if !booked && child.ArchivedAt != nil && child.ArchivedAt.Before(first.Time) {
+			continue
+		}
+		if err := checkCanceled(logger, ctx, "Fee export"); err != nil {
+			return nil, err
+		}
+		records, err := service.attendanceStore.GetForChild(ctx, child.ID, first, last)
+		if err != nil {
+			logger.WithError(err).WithField("child_id", child.ID).Error("Error fetching attendance for fee export")
+			return nil, ErrInternal
+		}
+		if !booked && len(records) == 0 {
+			continue
+		}
+
+		row := models.FeeExportRow{ChildID: child.ID, FirstName: child.FirstName, LastName: child.LastName, Birthdate: child.Birthdate, Month: first}
+		if booked {
+			row.BookedWeeklyHours = &hours
+		}
+		days := make(map[models.Date]bool)
+		for _, record := range records {
+			if record.EventType == models.AttendanceArrival {
+				days[record.AttendanceDate] = true
+			}
+		}
+		row.AttendanceDays = len(days)
+		for _, dayHours := range attendedHoursByDay(records) {
+			row.AttendedHours += dayHours
+		}
+		row.AttendedHours = roundHours(row.AttendedHours)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// writeCSV writes the rows in the configured columns, all fields under their names if none are configured.
+func (service *FeeExportServiceImpl) writeCSV(rows []models.FeeExportRow) ([]byte, error) {
+	settings := service.config.FeeExport
+	columns := settings.Columns
+	if len(columns) == 0 {
+		for _, field := range models.FeeExportFields {
+			columns = append(columns, config.FeeExportColumn{Field: field})
+		}
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if settings.Delimiter != "" {
+		writer.Comma = []rune(settings.Delimiter)[0]
+	}
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Header
+		if header[i] == "" {
+			header[i] = column.Field
+		}
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	formatHours := func(hours float64) string {
+		formatted := strconv.FormatFloat(hours, 'f', -1, 64)
+		if settings.DecimalComma {
+			formatted = strings.Replace(formatted, ".", ",", 1)
+		}
+		return formatted
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			switch column.Field {
+			case models.FeeExportFieldChildID:
+				record[i] = strconv.Itoa(row.ChildID)
+			case models.FeeExportFieldFirstName:
+				record[i] = row.FirstName
+			case models.FeeExportFieldLastName:
+				record[i] = row.LastName
+			case models.FeeExportFieldBirthdate:
+				record[i] = row.Birthdate.Format(cmp.Or(settings.DateLayout, time.DateOnly))
+			case models.FeeExportFieldMonth:
+				record[i] = row.Month.Format("2006-01")
+			case models.FeeExportFieldBookedWeeklyHours:
+				if row.BookedWeeklyHours != nil {
+					record[i] = strconv.Itoa(*row.BookedWeeklyHours)
+				}
+			case models.FeeExportFieldAttendanceDays:
+				record[i] = strconv.Itoa(row.AttendanceDays)
+			case models.FeeExportFieldAttendedHours:
+				record[i] = formatHours(row.AttendedHours)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
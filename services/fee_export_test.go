@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeExportService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2024, time.November, 4, 10, 0, 0, 0, time.UTC)
+	first, last := models.NewDate(2024, time.October, 1), models.NewDate(2024, time.October, 31)
+	leftInSeptember := time.Date(2024, time.September, 30, 12, 0, 0, 0, time.UTC)
+
+	setup := func(cfg *config.Config) (*services.FeeExportServiceImpl, *datamocks.MockAuditLogStore) {
+		childStore := new(datamocks.MockChildStore)
+		contractStore := new(datamocks.MockCareContractStore)
+		attendanceStore := new(datamocks.MockAttendanceStore)
+		childStore.On("GetAllIncludingArchived", mock.Anything).Return([]models.Child{
+			{ID: 1, FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.March, 15)},
+			{ID: 2, FirstName: "Ben", LastName: "Schulz", Birthdate: models.NewDate(2020, time.June, 1)},
+			{ID: 3, FirstName: "Clara", LastName: "Weber", Birthdate: models.NewDate(2019, time.May, 2), ArchivedAt: &leftInSeptember},
+			{ID: 4, FirstName: "David", LastName: "Klein", Birthdate: models.NewDate(2022, time.January, 9)},
+		}, nil)
+		contractStore.On("GetValidBetween", mock.Anything, first, last).Return([]models.CareContract{
+			{ID: 1, ChildID: 1, WeeklyHours: 35, ValidFrom: models.NewDate(2024, time.August, 1)},
+			{ID: 2, ChildID: 1, WeeklyHours: 45, ValidFrom: models.NewDate(2024, time.October, 15)},
+		}, nil)
+		arrival := time.Date(2024, time.October, 7, 7, 30, 0, 0, time.UTC)
+		attendanceStore.On("GetForChild", mock.Anything, 1, first, last).Return([]models.AttendanceRecord{
+			{EventType: models.AttendanceArrival, RecordedAt: arrival, AttendanceDate: models.DateOf(arrival)},
+			{EventType: models.AttendanceDeparture, RecordedAt: arrival.Add(8*time.Hour + 15*time.Minute), AttendanceDate: models.DateOf(arrival)},
+			{EventType: models.AttendanceArrival, RecordedAt: arrival.AddDate(0, 0, 1), AttendanceDate: models.DateOf(arrival.AddDate(0, 0, 1))},
+		}, nil)
+		// Ben attended without a contract and is exported for the fee accounting to follow up, David did neither
+		attendanceStore.On("GetForChild", mock.Anything, 2, first, last).Return([]models.AttendanceRecord{
+			{EventType: models.AttendanceArrival, RecordedAt: arrival, AttendanceDate: models.DateOf(arrival)},
+		}, nil)
+		attendanceStore.On("GetForChild", mock.Anything, 4, first, last).Return([]models.AttendanceRecord{}, nil)
+		auditLogStore := new(datamocks.MockAuditLogStore)
+		return services.NewFeeExportService(childStore, contractStore, attendanceStore, services.NewAuditLogService(auditLogStore), cfg, clock.NewFrozen(now)), auditLogStore
+	}
+
+	t.Run("all fields by default", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.FeeExport.Delimiter = ";"
+		cfg.FeeExport.DecimalComma = true
+		cfg.FeeExport.DateLayout = "02.01.2006"
+
+		service, auditLogStore := setup(cfg)
+		auditLogStore.On("Create", mock.Anything, mock.Anything).Return(1, nil).Once()
+
+		content, err := service.ExportFees(logger, ctx, models.NewDate(2024, time.October, 20), 7)
+		require.NoError(t, err)
+		assert.Equal(t, "child_id;first_name;last_name;birthdate;month;booked_weekly_hours;attendance_days;attended_hours\n"+
+			"1;Anna;Müller;15.03.2021;2024-10;35;2;8,25\n"+
+			"2;Ben;Schulz;01.06.2020;2024-10;;1;0\n", string(content))
+	})
+
+	t.Run("configured columns", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.FeeExport.Delimiter = ","
+		cfg.FeeExport.Columns = []config.FeeExportColumn{
+			{Field: models.FeeExportFieldChildID, Header: "Kind-Nr"},
+			{Field: models.FeeExportFieldLastName, Header: "Name, Vorname"},
+			{Field: models.FeeExportFieldAttendedHours},
+		}
+
+		service, auditLogStore := setup(cfg)
+		auditLogStore.On("Create", mock.Anything, mock.Anything).Return(1, nil).Once()
+
+		content, err := service.ExportFees(logger, ctx, first, 7)
+		require.NoError(t, err)
+		assert.Equal(t, "Kind-Nr,\"Name, Vorname\",attended_hours\n1,Müller,8.25\n2,Schulz,0\n", string(content))
+	})
+
+	t.Run("exports are recorded in the audit log", func(t *testing.T) {
+		service, auditLogStore := setup(&config.Config{})
+		auditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionExportFees && *entry.ActorUserID == 7 && entry.Details == "month=2024-10 children=2"
+		})).Return(1, nil).Once()
+
+		_, err := service.ExportFees(logger, ctx, first, 7)
+		require.NoError(t, err)
+		auditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("no export without audit entry", func(t *testing.T) {
+		service, auditLogStore := setup(&config.Config{})
+		auditLogStore.On("Create", mock.Anything, mock.Anything).Return(0, errors.New("database is locked")).Once()
+
+		content, err := service.ExportFees(logger, ctx, first, 7)
+		assert.ErrorIs(t, err, services.ErrInternal)
+		assert.Nil(t, content)
+	})
+
+	t.Run("future month", func(t *testing.T) {
+		service, _ := setup(&config.Config{})
+		_, err := service.ExportFees(logger, ctx, models.NewDate(2024, time.December, 1), 7)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// invalidInput converts the field errors of a failed validation into a *ValidationError, so that clients
+// can highlight the invalid fields. Other errors become ErrInvalidInput.
+func invalidInput(err error) error {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return ErrInvalidInput
+	}
+	violations := make([]RuleViolation, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		violations = append(violations, fieldViolation(fieldError))
+	}
+	return &ValidationError{Violations: violations}
+}
+
+// fieldViolation describes a field error. The field is the JSON path below the validated struct,
+// e.g. "observations[2].text", and the rule is the validator tag.
+func fieldViolation(fieldError validator.FieldError) RuleViolation {
+	field := fieldError.Namespace()
+	if _, path, ok := strings.Cut(field, "."); ok {
+		field = path
+	}
+	violation := RuleViolation{Rule: fieldError.Tag(), Field: field}
+	if fieldError.Param() != "" {
+		violation.Params = map[string]any{"param": fieldError.Param()}
+	}
+	violation.Message, violation.Text = fieldErrorMessages(fieldError)
+	return violation
+}
+
+// fieldErrorMessages returns the English message and the German text shown to users for a field error.
+func fieldErrorMessages(fieldError validator.FieldError) (string, string) {
+	param := fieldError.Param()
+	field := fieldError.Field()
+	isText := fieldError.Kind() == reflect.String
+	isList := fieldError.Kind() == reflect.Slice || fieldError.Kind() == reflect.Map
+
+	switch fieldError.Tag() {
+	case "required", "required_without", "required_if":
+		return field + " is required", "Dieses Feld ist erforderlich."
+	case "min":
+		switch {
+		case isText:
+			return fmt.Sprintf("%s must be at least %s characters long", field, param), fmt.Sprintf("Bitte mindestens %s Zeichen eingeben.", param)
+		case isList:
+			return fmt.Sprintf("%s must contain at least %s items", field, param), fmt.Sprintf("Bitte mindestens %s Einträge angeben.", param)
+		}
+		return fmt.Sprintf("%s must be at least %s", field, param), fmt.Sprintf("Der Wert muss mindestens %s sein.", param)
+	case "max":
+		switch {
+		case isText:
+			return fmt.Sprintf("%s must be at most %s characters long", field, param), fmt.Sprintf("Bitte höchstens %s Zeichen eingeben.", param)
+		case isList:
+			return fmt.Sprintf("%s must contain at most %s items", field, param), fmt.Sprintf("Bitte höchstens %s Einträge angeben.", param)
+		}
+		return fmt.Sprintf("%s must be at most %s", field, param), fmt.Sprintf("Der Wert darf höchstens %s sein.", param)
+	case "len":
+		return fmt.Sprintf("%s must be exactly %s characters long", field, param), fmt.Sprintf("Bitte genau %s Zeichen eingeben.", param)
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, param), fmt.Sprintf("Der Wert muss größer als %s sein.", param)
+	case "gte":
+		return fmt.Sprintf("%s must be at least %s", field, param), fmt.Sprintf("Der Wert muss mindestens %s sein.", param)
+	case "gtfield", "gtefield":
+		return fmt.Sprintf("%s must be after %s", field, param), "Das Datum liegt zu früh."
+	case "nefield":
+		return fmt.Sprintf("%s must differ from %s", field, param), "Der Wert darf nicht mit dem anderen Feld übereinstimmen."
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, param), fmt.Sprintf("Bitte einen der Werte %s wählen.", strings.ReplaceAll(param, " ", ", "))
+	case "email":
+		return field + " must be a valid email address", "Bitte eine gültige E-Mail-Adresse eingeben."
+	case "hexcolor":
+		return field + " must be a hex color like #1a2b3c", "Bitte eine Farbe im Format #1a2b3c angeben."
+	case "iso8601date":
+		return field + " must be a date in the format YYYY-MM-DD", "Bitte ein Datum im Format JJJJ-MM-TT angeben."
+	case "childbirthdate":
+		return field + " must lie within the last 8 years", "Das Geburtsdatum muss in den letzten 8 Jahren liegen."
+	}
+	return fmt.Sprintf("%s is invalid (%s)", field, fieldError.Tag()), "Der Wert ist ungültig."
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// FixtureTime is the time the clock of the fixture server shows after a reset, a Wednesday morning.
+var FixtureTime = time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+
+// FixturePassword is the password of every seeded account.
+const FixturePassword = "fixture-password"
+
+// fixtureUsers are the seeded accounts, teachers have a teacher of the same username.
+var fixtureUsers = []struct {
+	models.FixtureUser
+	FirstName string
+	LastName  string
+}{
+	{FixtureUser: models.FixtureUser{Username: "admin", Password: FixturePassword, Role: string(data.RoleAdmin)}},
+	{FixtureUser: models.FixtureUser{Username: "maria.schmidt", Password: FixturePassword, Role: string(data.RoleTeacher)}, FirstName: "Maria", LastName: "Schmidt"},
+	{FixtureUser: models.FixtureUser{Username: "thomas.weber", Password: FixturePassword, Role: string(data.RoleTeacher)}, FirstName: "Thomas", LastName: "Weber"},
+}
+
+// FixtureService defines the interface for the deterministic data of the fixture server the frontend tests run against.
+type FixtureService interface {
+	GetState() *models.FixtureState
+	Reset(logger *logrus.Entry, ctx context.Context) (*models.FixtureState, error)
+	SetClock(logger *logrus.Entry, now time.Time) *models.FixtureState
+}
+
+// FixtureServiceImpl implements FixtureService. It deletes all data on reset and must only run on the
+// in-memory database of the fixture profile.
+type FixtureServiceImpl struct {
+	dal      *data.DAL
+	clock    *clock.Frozen
+	mu       sync.Mutex // Serializes resets
+	seededAt time.Time
+}
+
+// NewFixtureService creates a new FixtureServiceImpl seeding dal and resetting the frozen clock.
+func NewFixtureService(dal *data.DAL, frozen *clock.Frozen) *FixtureServiceImpl {
+	return &FixtureServiceImpl{dal: dal, clock: frozen}
+}
+
+// GetState returns the seeded accounts and the time of the frozen clock.
+func (service *FixtureServiceImpl) GetState() *models.FixtureState {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	return service.stateLocked()
+}
+
+// Reset deletes all data, seeds the fixture data and sets the clock back to FixtureTime.
+// The seeded rows get the same IDs and timestamps on every reset.
+func (service *FixtureServiceImpl) Reset(logger *logrus.Entry, ctx context.Context) (*models.FixtureState, error) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	service.clock.Set(FixtureTime)
+	if err := service.dal.Fixtures.Clear(ctx); err != nil {
+		logger.WithError(err).Error("Error clearing fixture database")
+		return nil, ErrInternal
+	}
+	if err := service.seed(ctx); err != nil {
+		logger.WithError(err).Error("Error seeding fixture data")
+		return nil, ErrInternal
+	}
+	if err := service.dal.Fixtures.FreezeTimestamps(ctx, FixtureTime); err != nil {
+		logger.WithError(err).Error("Error freezing fixture timestamps")
+		return nil, ErrInternal
+	}
+	service.seededAt = FixtureTime
+	logger.Info("Fixture data reset")
+	return service.stateLocked(), nil
+}
+
+// SetClock moves the frozen clock, e.g. to test what happens on the next day. The data is left as it is.
+func (service *FixtureServiceImpl) SetClock(logger *logrus.Entry, now time.Time) *models.FixtureState {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.clock.Set(now)
+	logger.WithField("now", now).Info("Fixture clock set")
+	return service.stateLocked()
+}
+
+func (service *FixtureServiceImpl) stateLocked() *models.FixtureState {
+	state := &models.FixtureState{Now: service.clock.Now(), SeededAt: service.seededAt, Users: []models.FixtureUser{}}
+	for _, user := range fixtureUsers {
+		state.Users = append(state.Users, user.FixtureUser)
+	}
+	return state
+}
+
+// seed writes the fixture data into the cleared database. IDs start at 1 in the order of creation.
+func (service *FixtureServiceImpl) seed(ctx context.Context) error {
+	dal := service.dal
+	if err := dal.KitaMasterdata.Update(ctx, &models.KitaMasterdata{
+		Name:        "Kita Sonnenschein",
+		Street:      "Lindenstraße",
+		HouseNumber: "12",
+		PostalCode:  "10115",
+		City:        "Berlin",
+		PhoneNumber: "030 1234567",
+		Email:       "kontakt@kita-sonnenschein.example",
+	}); err != nil {
+		return err
+	}
+
+	categories := models.DefaultCategories()
+	categoryIDs := make([]int, len(categories))
+	for i := range categories {
+		id, err := dal.Categories.Create(ctx, &categories[i])
+		if err != nil {
+			return err
+		}
+		categoryIDs[i] = id
+	}
+
+	var teacherIDs []int
+	for _, user := range fixtureUsers {
+		// The minimum cost keeps resets between test runs fast, the passwords are public anyway.
+		hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.MinCost)
+		if err != nil {
+			return err
+		}
+		if _, err := dal.Users.Create(ctx, &models.User{Username: user.Username, PasswordHash: string(hash), Role: user.Role}); err != nil {
+			return err
+		}
+		if user.Role != string(data.RoleTeacher) {
+			continue
+		}
+		id, err := dal.Teachers.Create(ctx, &models.Teacher{FirstName: user.FirstName, LastName: user.LastName, Username: user.Username})
+		if err != nil {
+			return err
+		}
+		teacherIDs = append(teacherIDs, id)
+	}
+
+	admission := time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC)
+	enrollment := time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)
+	childIDs, err := dal.Children.CreateMany(ctx, []models.Child{
+		{FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2020, time.March, 15), AdmissionDate: &admission, ExpectedSchoolEnrollment: &enrollment},
+		{FirstName: "Liam", LastName: "Kowalski", Birthdate: models.NewDate(2020, time.November, 20), AdmissionDate: &admission, ExpectedSchoolEnrollment: &enrollment},
+		{FirstName: "Mia", LastName: "Schneider", Birthdate: models.NewDate(2021, time.June, 1), AdmissionDate: &admission},
+		{FirstName: "Noah", LastName: "Brown", Birthdate: models.NewDate(2020, time.January, 12), AdmissionDate: &admission, ExpectedSchoolEnrollment: &enrollment},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Anna and Liam are with Maria, Mia and Noah with Thomas
+	for i, childID := range childIDs {
+		assignment := &models.Assignment{ChildID: childID, TeacherID: teacherIDs[i/2], AssignmentType: models.AssignmentTypePrimary, StartDate: admission}
+		if _, err := dal.Assignments.Create(ctx, assignment); err != nil {
+			return err
+		}
+	}
+
+	approvedBy := teacherIDs[0]
+	_, err = dal.DocumentationEntries.CreateMany(ctx, []models.DocumentationEntry{
+		{ChildID: childIDs[0], TeacherID: teacherIDs[0], CategoryID: categoryIDs[0], ObservationDate: models.NewDate(2025, time.February, 24), ObservationDescription: "Anna balanciert sicher über den Baumstamm und hilft jüngeren Kindern dabei.", IsApproved: true, ApprovedByUserID: &approvedBy},
+		{ChildID: childIDs[0], TeacherID: teacherIDs[0], CategoryID: categoryIDs[1], ObservationDate: models.NewDate(2025, time.March, 4), ObservationDescription: "Anna probiert beim Mittagessen zum ersten Mal Brokkoli und erzählt davon."},
+		{ChildID: childIDs[1], TeacherID: teacherIDs[0], CategoryID: categoryIDs[0], ObservationDate: models.NewDate(2025, time.February, 27), ObservationDescription: "Liam klettert bis zur obersten Sprosse der Sprossenwand und wieder hinunter."},
+		{ChildID: childIDs[3], TeacherID: teacherIDs[1], CategoryID: categoryIDs[1], ObservationDate: models.NewDate(2025, time.March, 3), ObservationDescription: "Noah wäscht sich vor dem Essen selbstständig die Hände."},
+	})
+	return err
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MaxGalleryPhotoSize is the largest photo that can be uploaded to a gallery, in bytes.
+const MaxGalleryPhotoSize = 5 << 20
+
+// GalleryService defines the interface for the photo galleries of groups and the photo consent of children.
+// Photos showing a child without photo consent are redacted: they are listed, but their image is withheld.
+type GalleryService interface {
+	// UploadPhoto adds the photo with its content to the gallery of its group. Tagged children must be in the group.
+	UploadPhoto(logger *logrus.Entry, ctx context.Context, photo *models.GalleryPhoto, user *models.User) (*models.GalleryPhoto, error)
+	GetPhotoByID(logger *logrus.Entry, ctx context.Context, id int) (*models.GalleryPhoto, error)
+	// GetPhotoContent fetches a photo with its image, ErrPhotoConsentMissing is returned for redacted photos.
+	GetPhotoContent(logger *logrus.Entry, ctx context.Context, id int) (*models.GalleryPhoto, error)
+	// UpdatePhoto changes the caption and the tagged children of a photo.
+	UpdatePhoto(logger *logrus.Entry, ctx context.Context, photo *models.GalleryPhoto) error
+	DeletePhoto(logger *logrus.Entry, ctx context.Context, id int) error
+	GetGroupPhotos(logger *logrus.Entry, ctx context.Context, groupID int) ([]models.GalleryPhoto, error)
+	GetChildPhotos(logger *logrus.Entry, ctx context.Context, childID int) ([]models.GalleryPhoto, error)
+	// GetReportPhotos fetches the picked photos with their images for the report of a child. Every photo has to show
+	// the child and must not be redacted.
+	GetReportPhotos(logger *logrus.Entry, ctx context.Context, childID int, photoIDs []int) ([]models.GalleryPhoto, error)
+	SetPhotoConsent(logger *logrus.Entry, ctx context.Context, consent *models.PhotoConsent, user *models.User) (*models.PhotoConsent, error)
+	GetPhotoConsent(logger *logrus.Entry, ctx context.Context, childID int) (*models.PhotoConsent, error)
+	// RevokePhotoConsent removes the photo consent of a child, the photos showing the child are redacted from then on.
+	RevokePhotoConsent(logger *logrus.Entry, ctx context.Context, childID int) error
+}
+
+// GalleryServiceImpl implements GalleryService.
+type GalleryServiceImpl struct {
+	galleryStore     data.GalleryStore
+	childStore       data.ChildStore
+	groupStore       data.GroupStore
+	clientEncryption string
+}
+
+// NewGalleryService creates a new GalleryServiceImpl. clientEncryption is the mode of the client-side encryption
+// of photos, empty if it is disabled.
+func NewGalleryService(galleryStore data.GalleryStore, childStore data.ChildStore, groupStore data.GroupStore, clientEncryption string) *GalleryServiceImpl {
+	return &GalleryServiceImpl{
+		galleryStore:     galleryStore,
+		childStore:       childStore,
+		groupStore:       groupStore,
+		clientEncryption: clientEncryption,
+	}
+}
+
+// UploadPhoto adds a photo to the gallery of a group.
+// The content type is detected from the content, only PNG and JPEG images are accepted. The photo is normalized
+// before it is stored, so that neither its location nor its camera are kept. Photos encrypted by the client are
+// stored as they are, the client is responsible for stripping their metadata.
+func (service *GalleryServiceImpl) UploadPhoto(logger *logrus.Entry, ctx context.Context, photo *models.GalleryPhoto, user *models.User) (*models.GalleryPhoto, error) {
+	if err := models.ValidateGalleryPhoto(*photo); err != nil {
+		logger.WithError(err).Warn("Invalid input for UploadPhoto")
+		return nil, invalidInput(err)
+	}
+	if err := checkEnvelope(logger, service.clientEncryption, photo.Envelope); err != nil {
+		return nil, err
+	}
+	if len(photo.Content) == 0 || len(photo.Content) > MaxGalleryPhotoSize {
+		logger.WithField("size", len(photo.Content)).Warn("Invalid gallery photo size")
+		return nil, ErrInvalidInput
+	}
+	if photo.Envelope != nil {
+		photo.ContentType = encryptedContentType
+	} else {
+		content, contentType, err := prepareImage(logger, photo.Content)
+		if err != nil {
+			return nil, err
+		}
+		photo.Content, photo.ContentType = content, contentType
+	}
+	group, err := service.getGroup(logger, ctx, photo.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTaggedChildren(logger, group, photo.ChildIDs, nil); err != nil {
+		return nil, err
+	}
+	photo.UploadedByUserID = &user.ID
+
+	id, err := service.galleryStore.Create(ctx, photo)
+	if err != nil {
+		logger.WithError(err).WithField("group_id", photo.GroupID).Error("Error uploading gallery photo")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"photo_id": id, "group_id": photo.GroupID}).Info("Gallery photo uploaded successfully")
+	return service.GetPhotoByID(logger, ctx, id)
+}
+
+// GetPhotoByID fetches a photo by ID without its image.
+func (service *GalleryServiceImpl) GetPhotoByID(logger *logrus.Entry, ctx context.Context, id int) (*models.GalleryPhoto, error) {
+	photo, err := service.galleryStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrPhotoNotFound
+		}
+		logger.WithError(err).WithField("photo_id", id).Error("Error fetching gallery photo")
+		return nil, ErrInternal
+	}
+	photos := []models.GalleryPhoto{*photo}
+	if err := service.redact(logger, ctx, photos); err != nil {
+		return nil, err
+	}
+	return &photos[0], nil
+}
+
+// GetPhotoContent fetches a photo with its image.
+func (service *GalleryServiceImpl) GetPhotoContent(logger *logrus.Entry, ctx context.Context, id int) (*models.GalleryPhoto, error) {
+	photo, err := service.galleryStore.GetWithContent(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrPhotoNotFound
+		}
+		logger.WithError(err).WithField("photo_id", id).Error("Error fetching gallery photo content")
+		return nil, ErrInternal
+	}
+	photos := []models.GalleryPhoto{*photo}
+	if err := service.redact(logger, ctx, photos); err != nil {
+		return nil, err
+	}
+	if photos[0].IsRedacted() {
+		logger.WithFields(logrus.Fields{"photo_id": id, "child_ids": photos[0].RedactedChildIDs}).Info("Gallery photo withheld, children without photo consent")
+		return nil, ErrPhotoConsentMissing
+	}
+	return &photos[0], nil
+}
+
+// UpdatePhoto changes the caption and the tagged children of a photo. Newly tagged children must be in the group,
+// children who have left the group since stay tagged.
+func (service *GalleryServiceImpl) UpdatePhoto(logger *logrus.Entry, ctx context.Context, photo *models.GalleryPhoto) error {
+	if err := models.ValidateGalleryPhoto(*photo); err != nil {
+		logger.WithError(err).Warn("Invalid input for UpdatePhoto")
+		return invalidInput(err)
+	}
+	existing, err := service.galleryStore.GetByID(ctx, photo.ID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrPhotoNotFound
+		}
+		logger.WithError(err).WithField("photo_id", photo.ID).Error("Error fetching gallery photo for update")
+		return ErrInternal
+	}
+	group, err := service.getGroup(logger, ctx, existing.GroupID)
+	if err != nil {
+		return err
+	}
+	if err := checkTaggedChildren(logger, group, photo.ChildIDs, existing.ChildIDs); err != nil {
+		return err
+	}
+
+	if err := service.galleryStore.Update(ctx, photo); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrPhotoNotFound
+		}
+		logger.WithError(err).WithField("photo_id", photo.ID).Error("Error updating gallery photo")
+		return ErrInternal
+	}
+	logger.WithField("photo_id", photo.ID).Info("Gallery photo updated successfully")
+	return nil
+}
+
+// DeletePhoto deletes a photo from its gallery.
+func (service *GalleryServiceImpl) DeletePhoto(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.galleryStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrPhotoNotFound
+		}
+		logger.WithError(err).WithField("photo_id", id).Error("Error deleting gallery photo")
+		return ErrInternal
+	}
+	logger.WithField("photo_id", id).Info("Gallery photo deleted successfully")
+	return nil
+}
+
+// GetGroupPhotos fetches the gallery of a group, the latest photo first.
+func (service *GalleryServiceImpl) GetGroupPhotos(logger *logrus.Entry, ctx context.Context, groupID int) ([]models.GalleryPhoto, error) {
+	if _, err := service.getGroup(logger, ctx, groupID); err != nil {
+		return nil, err
+	}
+	photos, err := service.galleryStore.GetForGroup(ctx, groupID)
+	if err != nil {
+		logger.WithError(err).WithField("group_id", groupID).Error("Error fetching group gallery")
+		return nil, ErrInternal
+	}
+	if err := service.redact(logger, ctx, photos); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// GetChildPhotos fetches the photos a child is tagged on, the latest first. They are the photos reports of the
+// child can embed, unless they are redacted.
+func (service *GalleryServiceImpl) GetChildPhotos(logger *logrus.Entry, ctx context.Context, childID int) ([]models.GalleryPhoto, error) {
+	if err := service.checkChild(logger, ctx, childID); err != nil {
+		return nil, err
+	}
+	photos, err := service.galleryStore.GetForChild(ctx, childID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching photos of child")
+		return nil, ErrInternal
+	}
+	if err := service.redact(logger, ctx, photos); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// GetReportPhotos fetches the photos picked for a report of a child in the given order, photos picked twice are
+// returned once. Photos encrypted by the client cannot be picked, the server cannot decrypt them.
+func (service *GalleryServiceImpl) GetReportPhotos(logger *logrus.Entry, ctx context.Context, childID int, photoIDs []int) ([]models.GalleryPhoto, error) {
+	photos := []models.GalleryPhoto{}
+	seen := make(map[int]bool, len(photoIDs))
+	for _, id := range photoIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		photo, err := service.GetPhotoContent(logger, ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(photo.ChildIDs, childID) {
+			logger.WithFields(logrus.Fields{"photo_id": id, "child_id": childID}).Warn("Photo picked for a report does not show the child")
+			return nil, ErrPhotoNotOfChild
+		}
+		if photo.Envelope != nil {
+			logger.WithField("photo_id", id).Warn("Encrypted photo picked for a report")
+			return nil, ErrPhotoEncrypted
+		}
+		photos = append(photos, *photo)
+	}
+	return photos, nil
+}
+
+// SetPhotoConsent records or replaces the photo consent of a child.
+func (service *GalleryServiceImpl) SetPhotoConsent(logger *logrus.Entry, ctx context.Context, consent *models.PhotoConsent, user *models.User) (*models.PhotoConsent, error) {
+	if err := models.ValidatePhotoConsent(*consent); err != nil {
+		logger.WithError(err).Warn("Invalid input for SetPhotoConsent")
+		return nil, invalidInput(err)
+	}
+	if err := service.checkChild(logger, ctx, consent.ChildID); err != nil {
+		return nil, err
+	}
+	consent.RecordedByUserID = &user.ID
+
+	if err := service.galleryStore.SetConsent(ctx, consent); err != nil {
+		if errors.Is(err, data.ErrForeignKeyConstraint) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", consent.ChildID).Error("Error recording photo consent")
+		return nil, ErrInternal
+	}
+	logger.WithField("child_id", consent.ChildID).Info("Photo consent recorded successfully")
+	return service.GetPhotoConsent(logger, ctx, consent.ChildID)
+}
+
+// GetPhotoConsent fetches the photo consent of a child, ErrNotFound is returned if the parents did not consent.
+func (service *GalleryServiceImpl) GetPhotoConsent(logger *logrus.Entry, ctx context.Context, childID int) (*models.PhotoConsent, error) {
+	if err := service.checkChild(logger, ctx, childID); err != nil {
+		return nil, err
+	}
+	consent, err := service.galleryStore.GetConsent(ctx, childID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching photo consent")
+		return nil, ErrInternal
+	}
+	return consent, nil
+}
+
+// RevokePhotoConsent removes the photo consent of a child.
+func (service *GalleryServiceImpl) RevokePhotoConsent(logger *logrus.Entry, ctx context.Context, childID int) error {
+	if err := service.checkChild(logger, ctx, childID); err != nil {
+		return err
+	}
+	if err := service.galleryStore.DeleteConsent(ctx, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error revoking photo consent")
+		return ErrInternal
+	}
+	logger.WithField("child_id", childID).Info("Photo consent revoked successfully")
+	return nil
+}
+
+// redact sets the tagged children without photo consent of the photos.
+func (service *GalleryServiceImpl) redact(logger *logrus.Entry, ctx context.Context, photos []models.GalleryPhoto) error {
+	consented, err := service.galleryStore.GetConsentedChildIDs(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching photo consents")
+		return ErrInternal
+	}
+	for i := range photos {
+		photos[i].RedactedChildIDs = []int{}
+		for _, childID := range photos[i].ChildIDs {
+			if !consented[childID] {
+				photos[i].RedactedChildIDs = append(photos[i].RedactedChildIDs, childID)
+			}
+		}
+	}
+	return nil
+}
+
+// checkTaggedChildren checks that the tagged children are in the group, the previously tagged children are not checked.
+func checkTaggedChildren(logger *logrus.Entry, group *models.Group, childIDs []int, previous []int) error {
+	for _, childID := range childIDs {
+		if !slices.Contains(group.ChildIDs, childID) && !slices.Contains(previous, childID) {
+			logger.WithFields(logrus.Fields{"group_id": group.ID, "child_id": childID}).Warn("Child tagged on a gallery photo is not in the group")
+			return ErrChildNotInGroup
+		}
+	}
+	return nil
+}
+
+func (service *GalleryServiceImpl) checkChild(logger *logrus.Entry, ctx context.Context, childID int) error {
+	if _, err := service.childStore.GetByID(ctx, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for gallery")
+		return ErrInternal
+	}
+	return nil
+}
+
+func (service *GalleryServiceImpl) getGroup(logger *logrus.Entry, ctx context.Context, groupID int) (*models.Group, error) {
+	group, err := service.groupStore.GetByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("group_id", groupID).Error("Error fetching group for gallery")
+		return nil, ErrInternal
+	}
+	return group, nil
+}
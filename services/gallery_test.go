@@ -0,0 +1,257 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// jpegWithExif encodes a white JPEG photo with a red square in its top left corner and EXIF data holding an
+// orientation and a GPS position.
+func jpegWithExif(t *testing.T, width, height int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.White)
+			if x < 8 && y < 8 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			}
+		}
+	}
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, img, &jpeg.Options{Quality: 95}))
+
+	// The first image file directory holds the orientation, followed by the GPS position as free text.
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	tiff = append(tiff, []byte("GPS 52.5200N 13.4050E")...)
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+
+	content := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	content = binary.BigEndian.AppendUint16(content, uint16(len(app1)+2))
+	content = append(content, app1...)
+	return append(content, encoded.Bytes()[2:]...)
+}
+
+func TestGalleryService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	user := &models.User{ID: 2, Username: "maria.schmidt", Role: string(data.RoleTeacher)}
+	// Anna (1) and Ben (2) are in the group, only Anna has photo consent. Clara (3) is in another group.
+	group := &models.Group{ID: 10, Name: "Sonnengruppe", ChildIDs: []int{1, 2}}
+	photo := func(childIDs ...int) *models.GalleryPhoto {
+		return &models.GalleryPhoto{ID: 5, GroupID: group.ID, ContentType: "image/png", ChildIDs: childIDs, Content: []byte("image")}
+	}
+
+	setup := func() (*services.GalleryServiceImpl, *datamocks.MockGalleryStore) {
+		galleryStore := new(datamocks.MockGalleryStore)
+		childStore := new(datamocks.MockChildStore)
+		groupStore := new(datamocks.MockGroupStore)
+		childStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Maybe()
+		childStore.On("GetByID", mock.Anything, 99).Return(nil, data.ErrNotFound).Maybe()
+		groupStore.On("GetByID", mock.Anything, group.ID).Return(group, nil).Maybe()
+		groupStore.On("GetByID", mock.Anything, 98).Return(nil, data.ErrNotFound).Maybe()
+		galleryStore.On("GetConsentedChildIDs", mock.Anything).Return(map[int]bool{1: true}, nil).Maybe()
+		return services.NewGalleryService(galleryStore, childStore, groupStore, config.ClientEncryptionOptional), galleryStore
+	}
+
+	t.Run("upload", func(t *testing.T) {
+		service, store := setup()
+		store.On("Create", mock.Anything, mock.MatchedBy(func(photo *models.GalleryPhoto) bool {
+			return photo.ContentType == "image/png" && *photo.UploadedByUserID == user.ID
+		})).Return(5, nil).Once()
+		store.On("GetByID", mock.Anything, 5).Return(photo(1), nil).Once()
+
+		uploaded := &models.GalleryPhoto{GroupID: group.ID, ChildIDs: []int{1}, Content: pngLogo(t, 30, 20)}
+		created, err := service.UploadPhoto(logger, ctx, uploaded, user)
+		require.NoError(t, err)
+		assert.False(t, created.IsRedacted())
+		store.AssertExpectations(t)
+	})
+
+	t.Run("upload normalizes photos", func(t *testing.T) {
+		service, store := setup()
+		var stored []byte
+		store.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.GalleryPhoto).Content
+		}).Return(5, nil)
+		store.On("GetByID", mock.Anything, 5).Return(photo(), nil)
+
+		// The photo is turned upright and its metadata is dropped
+		original := jpegWithExif(t, 30, 20, 6)
+		require.Contains(t, string(original), "GPS 52.5200N")
+		_, err := service.UploadPhoto(logger, ctx, &models.GalleryPhoto{GroupID: group.ID, Content: original}, user)
+		require.NoError(t, err)
+		assert.NotContains(t, string(stored), "Exif")
+		assert.NotContains(t, string(stored), "GPS 52.5200N")
+		upright, format, err := image.Decode(bytes.NewReader(stored))
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, image.Rect(0, 0, 20, 30), upright.Bounds())
+		r, g, _, _ := upright.At(16, 3).RGBA()
+		assert.True(t, r > 0xC000 && g < 0x5000, "the red corner is turned to the top right")
+		_, g, _, _ = upright.At(3, 3).RGBA()
+		assert.True(t, g > 0xC000, "the top left is white once turned")
+
+		// Large photos are scaled down, keeping their aspect ratio and format
+		var large bytes.Buffer
+		require.NoError(t, png.Encode(&large, image.NewRGBA(image.Rect(0, 0, 3000, 1500))))
+		_, err = service.UploadPhoto(logger, ctx, &models.GalleryPhoto{GroupID: group.ID, Content: large.Bytes()}, user)
+		require.NoError(t, err)
+		config, format, err := image.DecodeConfig(bytes.NewReader(stored))
+		require.NoError(t, err)
+		assert.Equal(t, "png", format)
+		assert.Equal(t, services.MaxImageDimension, config.Width)
+		assert.Equal(t, services.MaxImageDimension/2, config.Height)
+
+		// HEIC photos cannot be decoded and are rejected with their own code
+		heic := append([]byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00"), make([]byte, 64)...)
+		_, err = service.UploadPhoto(logger, ctx, &models.GalleryPhoto{GroupID: group.ID, Content: heic}, user)
+		assert.ErrorIs(t, err, services.ErrPhotoHEIC)
+	})
+
+	t.Run("upload rejects invalid photos", func(t *testing.T) {
+		service, store := setup()
+		tests := []struct {
+			name  string
+			photo *models.GalleryPhoto
+			want  error
+		}{
+			{"no image", &models.GalleryPhoto{GroupID: group.ID, Content: []byte("not an image")}, services.ErrInvalidInput},
+			{"too large", &models.GalleryPhoto{GroupID: group.ID, Content: make([]byte, services.MaxGalleryPhotoSize+1)}, services.ErrInvalidInput},
+			{"unknown group", &models.GalleryPhoto{GroupID: 98, Content: pngLogo(t, 2, 2)}, services.ErrNotFound},
+			{"child of another group", &models.GalleryPhoto{GroupID: group.ID, ChildIDs: []int{1, 3}, Content: pngLogo(t, 2, 2)}, services.ErrChildNotInGroup},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := service.UploadPhoto(logger, ctx, tt.photo, user)
+				assert.ErrorIs(t, err, tt.want)
+			})
+		}
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("listing redacts children without consent", func(t *testing.T) {
+		service, store := setup()
+		store.On("GetForGroup", mock.Anything, group.ID).Return([]models.GalleryPhoto{*photo(1, 2), *photo(1)}, nil).Once()
+
+		photos, err := service.GetGroupPhotos(logger, ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, photos, 2)
+		assert.Equal(t, []int{2}, photos[0].RedactedChildIDs)
+		assert.Empty(t, photos[1].RedactedChildIDs)
+		assert.NotNil(t, photos[1].RedactedChildIDs, "an empty list is returned for photos without redaction")
+	})
+
+	t.Run("redacted images are withheld", func(t *testing.T) {
+		service, store := setup()
+		store.On("GetWithContent", mock.Anything, 5).Return(photo(1, 2), nil).Once()
+		store.On("GetWithContent", mock.Anything, 6).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.GetPhotoContent(logger, ctx, 5)
+		assert.ErrorIs(t, err, services.ErrPhotoConsentMissing)
+		_, err = service.GetPhotoContent(logger, ctx, 6)
+		assert.ErrorIs(t, err, services.ErrPhotoNotFound)
+	})
+
+	t.Run("update keeps children who left the group", func(t *testing.T) {
+		service, store := setup()
+		store.On("GetByID", mock.Anything, 5).Return(photo(3), nil)
+		store.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		require.NoError(t, service.UpdatePhoto(logger, ctx, photo(3, 1)))
+		assert.ErrorIs(t, service.UpdatePhoto(logger, ctx, photo(3, 4)), services.ErrChildNotInGroup)
+		store.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("report photos", func(t *testing.T) {
+		service, store := setup()
+		store.On("GetWithContent", mock.Anything, 5).Return(photo(1), nil)
+		store.On("GetWithContent", mock.Anything, 6).Return(photo(1, 2), nil)
+		encrypted := photo(1)
+		encrypted.Envelope = &models.AttachmentEnvelope{Algorithm: models.EnvelopeAlgorithmAES256GCM, KeyID: "traeger-2024", Nonce: "bm9uY2U="}
+		store.On("GetWithContent", mock.Anything, 7).Return(encrypted, nil)
+
+		photos, err := service.GetReportPhotos(logger, ctx, 1, []int{5, 5})
+		require.NoError(t, err)
+		assert.Len(t, photos, 1)
+		_, err = service.GetReportPhotos(logger, ctx, 2, []int{5})
+		assert.ErrorIs(t, err, services.ErrPhotoNotOfChild)
+		_, err = service.GetReportPhotos(logger, ctx, 1, []int{5, 6})
+		assert.ErrorIs(t, err, services.ErrPhotoConsentMissing)
+		_, err = service.GetReportPhotos(logger, ctx, 1, []int{7})
+		assert.ErrorIs(t, err, services.ErrPhotoEncrypted)
+	})
+
+	t.Run("client-side encrypted photos", func(t *testing.T) {
+		envelope := &models.AttachmentEnvelope{Algorithm: models.EnvelopeAlgorithmXChaCha20Poly1305, KeyID: "traeger-2024", Nonce: "bm9uY2U="}
+		ciphertext := []byte("\x00\x01ciphertext, neither PNG nor JPEG")
+		newService := func(mode string) (*services.GalleryServiceImpl, *datamocks.MockGalleryStore) {
+			galleryStore := new(datamocks.MockGalleryStore)
+			groupStore := new(datamocks.MockGroupStore)
+			groupStore.On("GetByID", mock.Anything, group.ID).Return(group, nil).Maybe()
+			galleryStore.On("GetConsentedChildIDs", mock.Anything).Return(map[int]bool{1: true}, nil).Maybe()
+			return services.NewGalleryService(galleryStore, new(datamocks.MockChildStore), groupStore, mode), galleryStore
+		}
+
+		service, store := newService(config.ClientEncryptionOptional)
+		store.On("Create", mock.Anything, mock.MatchedBy(func(photo *models.GalleryPhoto) bool {
+			return photo.ContentType == "application/octet-stream" && bytes.Equal(photo.Content, ciphertext) && photo.Envelope == envelope
+		})).Return(5, nil).Once()
+		store.On("GetByID", mock.Anything, 5).Return(photo(1), nil).Once()
+		_, err := service.UploadPhoto(logger, ctx, &models.GalleryPhoto{GroupID: group.ID, ChildIDs: []int{1}, Content: ciphertext, Envelope: envelope}, user)
+		require.NoError(t, err)
+		store.AssertExpectations(t)
+
+		_, err = service.UploadPhoto(logger, ctx, &models.GalleryPhoto{GroupID: group.ID, Content: ciphertext,
+			Envelope: &models.AttachmentEnvelope{Algorithm: "ROT13", KeyID: "traeger-2024", Nonce: "bm9uY2U="}}, user)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+
+		service, _ = newService("")
+		_, err = service.UploadPhoto(logger, ctx, &models.GalleryPhoto{GroupID: group.ID, Content: ciphertext, Envelope: envelope}, user)
+		assert.ErrorIs(t, err, services.ErrClientEncryptionDisabled)
+
+		service, _ = newService(config.ClientEncryptionRequired)
+		_, err = service.UploadPhoto(logger, ctx, &models.GalleryPhoto{GroupID: group.ID, Content: pngLogo(t, 4, 4)}, user)
+		assert.ErrorIs(t, err, services.ErrClientEncryptionRequired)
+	})
+
+	t.Run("photo consent", func(t *testing.T) {
+		service, store := setup()
+		consent := &models.PhotoConsent{ChildID: 1, ConsentReference: "Einwilligung vom 01.08.2024"}
+		store.On("SetConsent", mock.Anything, mock.MatchedBy(func(consent *models.PhotoConsent) bool {
+			return *consent.RecordedByUserID == user.ID
+		})).Return(nil).Once()
+		store.On("GetConsent", mock.Anything, 1).Return(consent, nil).Once()
+		store.On("DeleteConsent", mock.Anything, 1).Return(data.ErrNotFound).Once()
+
+		_, err := service.SetPhotoConsent(logger, ctx, consent, user)
+		require.NoError(t, err)
+		_, err = service.SetPhotoConsent(logger, ctx, &models.PhotoConsent{ChildID: 1}, user)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		_, err = service.SetPhotoConsent(logger, ctx, &models.PhotoConsent{ChildID: 99, ConsentReference: "x"}, user)
+		assert.ErrorIs(t, err, services.ErrChildNotFound)
+		assert.ErrorIs(t, service.RevokePhotoConsent(logger, ctx, 1), services.ErrNotFound)
+		store.AssertExpectations(t)
+	})
+}
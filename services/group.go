@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GroupService defines the interface for group operations.
+type GroupService interface {
+	CreateGroup(logger *logrus.Entry, ctx context.Context, group *models.Group) (*models.Group, error)
+	GetGroupByID(logger *logrus.Entry, ctx context.Context, id int) (*models.Group, error)
+	GetAllGroups(logger *logrus.Entry, ctx context.Context) ([]models.Group, error)
+	UpdateGroup(logger *logrus.Entry, ctx context.Context, group *models.Group) error
+	DeleteGroup(logger *logrus.Entry, ctx context.Context, id int) error
+	AssignChild(logger *logrus.Entry, ctx context.Context, groupID int, childID int) (*models.GroupAssignmentResult, error)
+	RemoveChild(logger *logrus.Entry, ctx context.Context, groupID int, childID int) error
+	GetGroupStatistics(logger *logrus.Entry, ctx context.Context) ([]models.GroupStatistics, error)
+}
+
+// GroupServiceImpl implements GroupService.
+type GroupServiceImpl struct {
+	groupStore        data.GroupStore
+	childStore        data.ChildStore
+	teacherStore      data.TeacherStore
+	careContractStore data.CareContractStore
+	clock             clock.Clock
+}
+
+// NewGroupService creates a new GroupServiceImpl.
+func NewGroupService(groupStore data.GroupStore, childStore data.ChildStore, teacherStore data.TeacherStore, careContractStore data.CareContractStore, clock clock.Clock) *GroupServiceImpl {
+	return &GroupServiceImpl{
+		groupStore:        groupStore,
+		childStore:        childStore,
+		teacherStore:      teacherStore,
+		careContractStore: careContractStore,
+		clock:             clock,
+	}
+}
+
+// CreateGroup creates a new group.
+func (service *GroupServiceImpl) CreateGroup(logger *logrus.Entry, ctx context.Context, group *models.Group) (*models.Group, error) {
+	if err := service.validate(logger, ctx, group); err != nil {
+		return nil, err
+	}
+
+	id, err := service.groupStore.Create(ctx, group)
+	if err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			logger.WithField("name", group.Name).Warn("Group with this name already exists")
+			return nil, ErrAlreadyExists
+		}
+		logger.WithError(err).Error("Error creating group")
+		return nil, ErrInternal
+	}
+
+	created, err := service.groupStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("group_id", id).Error("Error fetching created group")
+		return nil, ErrInternal
+	}
+	logger.WithField("group_id", id).Info("Group created successfully")
+	return created, nil
+}
+
+// GetGroupByID fetches a group by ID.
+func (service *GroupServiceImpl) GetGroupByID(logger *logrus.Entry, ctx context.Context, id int) (*models.Group, error) {
+	group, err := service.groupStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("group_id", id).Error("Error fetching group")
+		return nil, ErrInternal
+	}
+	return group, nil
+}
+
+// GetAllGroups fetches all groups with their usage counts.
+func (service *GroupServiceImpl) GetAllGroups(logger *logrus.Entry, ctx context.Context) ([]models.Group, error) {
+	groups, err := service.groupStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching groups")
+		return nil, ErrInternal
+	}
+
+	usage, err := service.groupStore.CountUsage(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error counting the usage of groups")
+		return nil, ErrInternal
+	}
+	for i := range groups {
+		counts := usage[groups[i].ID]
+		groups[i].Usage = &counts
+	}
+	return groups, nil
+}
+
+// UpdateGroup updates an existing group. Lowering the capacity below the number of children is allowed,
+// the group statistics show the group as over capacity.
+func (service *GroupServiceImpl) UpdateGroup(logger *logrus.Entry, ctx context.Context, group *models.Group) error {
+	if err := service.validate(logger, ctx, group); err != nil {
+		return err
+	}
+
+	if err := service.groupStore.Update(ctx, group); err != nil {
+		switch {
+		case errors.Is(err, data.ErrNotFound):
+			return ErrNotFound
+		case errors.Is(err, data.ErrConflict):
+			logger.WithField("name", group.Name).Warn("Group with this name already exists")
+			return ErrAlreadyExists
+		}
+		logger.WithError(err).WithField("group_id", group.ID).Error("Error updating group")
+		return ErrInternal
+	}
+	logger.WithField("group_id", group.ID).Info("Group updated successfully")
+	return nil
+}
+
+// DeleteGroup deletes a group. Its children remain, without a group.
+func (service *GroupServiceImpl) DeleteGroup(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.groupStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("group_id", id).Error("Error deleting group")
+		return ErrInternal
+	}
+	logger.WithField("group_id", id).Info("Group deleted successfully")
+	return nil
+}
+
+// AssignChild moves a child into a group. The assignment is stored even if the group is full or the child
+// is outside the age band of the group, since both happen in practice; the result carries warnings instead.
+func (service *GroupServiceImpl) AssignChild(logger *logrus.Entry, ctx context.Context, groupID int, childID int) (*models.GroupAssignmentResult, error) {
+	group, err := service.GetGroupByID(logger, ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	child, err := service.childStore.GetByID(ctx, childID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("child_id", childID).Warn("Child not found for group assignment")
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for group assignment")
+		return nil, ErrInternal
+	}
+	if child.ArchivedAt != nil {
+		logger.WithField("child_id", childID).Warn("Archived child cannot be assigned to a group")
+		return nil, ErrInvalidInput
+	}
+
+	if err := service.groupStore.AddChild(ctx, groupID, childID); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"group_id": groupID, "child_id": childID}).Error("Error assigning child to group")
+		return nil, ErrInternal
+	}
+
+	warnings := []string{}
+	childCount := len(group.ChildIDs)
+	if !slices.Contains(group.ChildIDs, childID) {
+		childCount++
+	}
+	if childCount > group.Capacity {
+		warnings = append(warnings, fmt.Sprintf("group is over capacity: %d children for %d places", childCount, group.Capacity))
+	} else if childCount == group.Capacity {
+		warnings = append(warnings, "group has reached its capacity")
+	}
+	ageMonths := models.AgeInMonths(child.Birthdate, models.Today(service.clock.Now()))
+	if !group.AcceptsAge(ageMonths) {
+		warnings = append(warnings, fmt.Sprintf("child is %d months old, outside the age band of the group", ageMonths))
+	}
+
+	updated, err := service.GetGroupByID(logger, ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithFields(logrus.Fields{"group_id": groupID, "child_id": childID, "warnings": len(warnings)}).Info("Child assigned to group successfully")
+	return &models.GroupAssignmentResult{Group: updated, Warnings: warnings}, nil
+}
+
+// RemoveChild removes a child from a group.
+func (service *GroupServiceImpl) RemoveChild(logger *logrus.Entry, ctx context.Context, groupID int, childID int) error {
+	if err := service.groupStore.RemoveChild(ctx, groupID, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithFields(logrus.Fields{"group_id": groupID, "child_id": childID}).Error("Error removing child from group")
+		return ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"group_id": groupID, "child_id": childID}).Info("Child removed from group successfully")
+	return nil
+}
+
+// GetGroupStatistics computes the occupancy and booked care hours of all groups. Archived children are not counted.
+func (service *GroupServiceImpl) GetGroupStatistics(logger *logrus.Entry, ctx context.Context) ([]models.GroupStatistics, error) {
+	groups, err := service.groupStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching groups for group statistics")
+		return nil, ErrInternal
+	}
+	children, err := service.childStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching children for group statistics")
+		return nil, ErrInternal
+	}
+	birthdates := make(map[int]models.Date, len(children))
+	for _, child := range children {
+		birthdates[child.ID] = child.Birthdate
+	}
+
+	today := models.Today(service.clock.Now())
+	contracts, err := service.careContractStore.GetValidBetween(ctx, today, today)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching care contracts for group statistics")
+		return nil, ErrInternal
+	}
+	bookedHours := make(map[int]int, len(contracts))
+	for _, contract := range contracts {
+		bookedHours[contract.ChildID] = contract.WeeklyHours
+	}
+
+	statistics := make([]models.GroupStatistics, 0, len(groups))
+	for _, group := range groups {
+		stats := models.GroupStatistics{
+			GroupID:    group.ID,
+			Name:       group.Name,
+			Capacity:   group.Capacity,
+			StaffCount: len(group.AssistantTeacherIDs),
+		}
+		if group.LeadTeacherID != nil {
+			stats.StaffCount++
+		}
+
+		totalAgeMonths := 0
+		for _, childID := range group.ChildIDs {
+			birthdate, ok := birthdates[childID]
+			if !ok {
+				continue // Archived
+			}
+			stats.ChildCount++
+			ageMonths := models.AgeInMonths(birthdate, today)
+			totalAgeMonths += ageMonths
+			if !group.AcceptsAge(ageMonths) {
+				stats.ChildrenOutsideAgeBand++
+			}
+			if hours, ok := bookedHours[childID]; ok {
+				stats.BookedCareHours += hours
+			} else {
+				stats.ChildrenWithoutCareContract++
+			}
+		}
+
+		stats.FreePlaces = group.Capacity - stats.ChildCount
+		stats.Utilization = float64(stats.ChildCount) / float64(group.Capacity)
+		if stats.ChildCount > 0 {
+			averageAgeMonths := float64(totalAgeMonths) / float64(stats.ChildCount)
+			stats.AverageAgeMonths = &averageAgeMonths
+		}
+		if stats.StaffCount > 0 {
+			childrenPerStaff := float64(stats.ChildCount) / float64(stats.StaffCount)
+			stats.ChildrenPerStaff = &childrenPerStaff
+		}
+		statistics = append(statistics, stats)
+	}
+	return statistics, nil
+}
+
+// validate checks the group and that its staff exists.
+func (service *GroupServiceImpl) validate(logger *logrus.Entry, ctx context.Context, group *models.Group) error {
+	if err := models.ValidateGroup(*group); err != nil {
+		logger.WithError(err).Warn("Invalid input for group")
+		return invalidInput(err)
+	}
+
+	teacherIDs := group.AssistantTeacherIDs
+	if group.LeadTeacherID != nil {
+		teacherIDs = append([]int{*group.LeadTeacherID}, teacherIDs...)
+	}
+	for _, teacherID := range teacherIDs {
+		if _, err := service.teacherStore.GetByID(ctx, teacherID); err != nil {
+			if errors.Is(err, data.ErrNotFound) {
+				logger.WithField("teacher_id", teacherID).Warn("Teacher of group not found")
+				return ErrInvalidInput
+			}
+			logger.WithError(err).WithField("teacher_id", teacherID).Error("Error fetching teacher of group")
+			return ErrInternal
+		}
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Periods before the handover date the care specifics of the handover packet cover, both include the handover date.
+const (
+	handoverDailyCareDays  = 14
+	handoverMedicationDays = 90
+)
+
+// GroupHandoverService defines the interface for moving children into another group with a handover packet.
+type GroupHandoverService interface {
+	// CreateHandover moves a child into another group and returns the handover packet for the receiving group.
+	CreateHandover(logger *logrus.Entry, ctx context.Context, handover *models.GroupHandover, user *models.User) (*models.HandoverPacket, error)
+	// GetHandoverPacket compiles the handover packet of a handover as of its handover date.
+	GetHandoverPacket(logger *logrus.Entry, ctx context.Context, id int) (*models.HandoverPacket, error)
+	GetHandoversForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.GroupHandover, error)
+	GetUnconfirmedHandovers(logger *logrus.Entry, ctx context.Context) ([]models.GroupHandover, error)
+	// ConfirmHandover records the confirmation of the user, who must be a teacher of the receiving group.
+	ConfirmHandover(logger *logrus.Entry, ctx context.Context, id int, confirmation *models.HandoverConfirmation, user *models.User) (*models.GroupHandover, error)
+}
+
+// GroupHandoverServiceImpl implements GroupHandoverService.
+type GroupHandoverServiceImpl struct {
+	handoverStore           data.GroupHandoverStore
+	childStore              data.ChildStore
+	groupStore              data.GroupStore
+	teacherStore            data.TeacherStore
+	categoryStore           data.CategoryStore
+	documentationEntryStore data.DocumentationEntryStore
+	dailyCareStore          data.DailyCareStore
+	medicationStore         data.MedicationStore
+	supportProviderStore    data.SupportProviderStore
+	teamMeetingStore        data.TeamMeetingStore
+	clock                   clock.Clock
+}
+
+// NewGroupHandoverService creates a new GroupHandoverServiceImpl.
+func NewGroupHandoverService(handoverStore data.GroupHandoverStore, childStore data.ChildStore, groupStore data.GroupStore,
+	teacherStore data.TeacherStore, categoryStore data.CategoryStore, documentationEntryStore data.DocumentationEntryStore,
+	dailyCareStore data.DailyCareStore, medicationStore data.MedicationStore, supportProviderStore data.SupportProviderStore,
+	teamMeetingStore data.TeamMeetingStore, clock clock.Clock) *GroupHandoverServiceImpl {
+	return &GroupHandoverServiceImpl{
+		handoverStore:           handoverStore,
+		childStore:              childStore,
+		groupStore:              groupStore,
+		teacherStore:            teacherStore,
+		categoryStore:           categoryStore,
+		documentationEntryStore: documentationEntryStore,
+		dailyCareStore:          dailyCareStore,
+		medicationStore:         medicationStore,
+		supportProviderStore:    supportProviderStore,
+		teamMeetingStore:        teamMeetingStore,
+		clock:                   clock,
+	}
+}
+
+// CreateHandover records the handover from the current group of the child and moves it into the receiving group.
+func (service *GroupHandoverServiceImpl) CreateHandover(logger *logrus.Entry, ctx context.Context, handover *models.GroupHandover, user *models.User) (*models.HandoverPacket, error) {
+	if handover.HandoverDate.IsZero() {
+		handover.HandoverDate = models.Today(service.clock.Now())
+	}
+	if err := models.ValidateGroupHandover(*handover); err != nil {
+		logger.WithError(err).Warn("Invalid input for group handover")
+		return nil, invalidInput(err)
+	}
+	child, err := service.getChild(logger, ctx, handover.ChildID)
+	if err != nil {
+		return nil, err
+	}
+	if child.ArchivedAt != nil {
+		logger.WithField("child_id", child.ID).Warn("Archived child cannot be handed over to a group")
+		return nil, ErrInvalidInput
+	}
+	if _, err := service.groupStore.GetByID(ctx, *handover.ToGroupID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrGroupNotFound
+		}
+		logger.WithError(err).WithField("group_id", *handover.ToGroupID).Error("Error fetching receiving group for handover")
+		return nil, ErrInternal
+	}
+
+	groups, err := service.groupStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching groups for handover")
+		return nil, ErrInternal
+	}
+	handover.FromGroupID = nil
+	for _, group := range groups {
+		if slices.Contains(group.ChildIDs, child.ID) {
+			handover.FromGroupID = &group.ID
+			break
+		}
+	}
+	if handover.FromGroupID != nil && *handover.FromGroupID == *handover.ToGroupID {
+		return nil, ErrHandoverSameGroup
+	}
+	handover.CreatedByUserID = &user.ID
+	handover.Confirmation = nil
+
+	id, err := service.handoverStore.Create(ctx, handover)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", child.ID).Error("Error creating group handover")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"handover_id": id, "child_id": child.ID, "to_group_id": *handover.ToGroupID}).Info("Child handed over to group successfully")
+	return service.GetHandoverPacket(logger, ctx, id)
+}
+
+// GetHandoverPacket compiles the latest observation per category, the care specifics and the open Förderziele of the child.
+func (service *GroupHandoverServiceImpl) GetHandoverPacket(logger *logrus.Entry, ctx context.Context, id int) (*models.HandoverPacket, error) {
+	handover, err := service.getHandover(logger, ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	child, err := service.getChild(logger, ctx, handover.ChildID)
+	if err != nil {
+		return nil, err
+	}
+	day := handover.HandoverDate
+	packet := &models.HandoverPacket{
+		Handover:           *handover,
+		ChildName:          child.FirstName + " " + child.LastName,
+		AgeMonths:          models.AgeInMonths(child.Birthdate, day),
+		LatestObservations: []models.HandoverObservation{},
+		ActiveSupports:     []models.ChildSupport{},
+		OpenActionItems:    []models.ActionItem{},
+	}
+	packetLogger := logger.WithFields(logrus.Fields{"handover_id": id, "child_id": child.ID})
+	if packet.FromGroupName, err = service.groupName(ctx, handover.FromGroupID); err != nil {
+		packetLogger.WithError(err).Error("Error fetching previous group for handover packet")
+		return nil, ErrInternal
+	}
+	if packet.ToGroupName, err = service.groupName(ctx, handover.ToGroupID); err != nil {
+		packetLogger.WithError(err).Error("Error fetching receiving group for handover packet")
+		return nil, ErrInternal
+	}
+
+	categories, err := service.categoryStore.GetAll(ctx)
+	if err != nil {
+		packetLogger.WithError(err).Error("Error fetching categories for handover packet")
+		return nil, ErrInternal
+	}
+	categoryNames := make(map[int]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+	// The entries come latest first, so the first entry of a category is its latest observation.
+	entries, err := service.documentationEntryStore.List(ctx, models.ListQuery{}.
+		Where("child_id", models.OperatorEqual, child.ID).
+		Where("observation_date", models.OperatorLessOrEqual, day))
+	if err != nil {
+		packetLogger.WithError(err).Error("Error fetching documentation entries for handover packet")
+		return nil, ErrInternal
+	}
+	for _, entry := range entries {
+		if slices.ContainsFunc(packet.LatestObservations, func(observation models.HandoverObservation) bool {
+			return observation.CategoryID == entry.CategoryID
+		}) {
+			continue
+		}
+		packet.LatestObservations = append(packet.LatestObservations, models.HandoverObservation{
+			CategoryID: entry.CategoryID, CategoryName: categoryNames[entry.CategoryID], Entry: entry,
+		})
+	}
+	slices.SortStableFunc(packet.LatestObservations, func(a, b models.HandoverObservation) int {
+		return a.CategoryID - b.CategoryID
+	})
+
+	if packet.Care, err = service.careSpecifics(ctx, child.ID, day); err != nil {
+		packetLogger.WithError(err).Error("Error fetching care specifics for handover packet")
+		return nil, ErrInternal
+	}
+
+	supports, err := service.supportProviderStore.GetSupportsForChild(ctx, child.ID)
+	if err != nil {
+		packetLogger.WithError(err).Error("Error fetching supports for handover packet")
+		return nil, ErrInternal
+	}
+	for _, support := range supports {
+		if !models.Today(support.StartDate).After(day.Time) && (support.EndDate == nil || !models.Today(*support.EndDate).Before(day.Time)) {
+			packet.ActiveSupports = append(packet.ActiveSupports, support)
+		}
+	}
+
+	items, err := service.teamMeetingStore.GetOpenActionItems(ctx)
+	if err != nil {
+		packetLogger.WithError(err).Error("Error fetching action items for handover packet")
+		return nil, ErrInternal
+	}
+	today := models.Today(service.clock.Now())
+	for _, item := range items {
+		if item.ChildID != nil && *item.ChildID == child.ID {
+			item.Overdue = item.DueDate.Before(today.Time)
+			packet.OpenActionItems = append(packet.OpenActionItems, item)
+		}
+	}
+	return packet, nil
+}
+
+// GetHandoversForChild fetches the handovers of a child, the latest first.
+func (service *GroupHandoverServiceImpl) GetHandoversForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.GroupHandover, error) {
+	if _, err := service.getChild(logger, ctx, childID); err != nil {
+		return nil, err
+	}
+	handovers, err := service.handoverStore.GetForChild(ctx, childID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching handovers of child")
+		return nil, ErrInternal
+	}
+	return handovers, nil
+}
+
+// GetUnconfirmedHandovers fetches the handovers waiting for the confirmation of a receiving teacher, the oldest first.
+func (service *GroupHandoverServiceImpl) GetUnconfirmedHandovers(logger *logrus.Entry, ctx context.Context) ([]models.GroupHandover, error) {
+	handovers, err := service.handoverStore.GetUnconfirmed(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching unconfirmed handovers")
+		return nil, ErrInternal
+	}
+	return handovers, nil
+}
+
+// ConfirmHandover confirms a handover as the teacher of the user. Only the lead and assistant teachers of the
+// receiving group can confirm it, once.
+func (service *GroupHandoverServiceImpl) ConfirmHandover(logger *logrus.Entry, ctx context.Context, id int, confirmation *models.HandoverConfirmation, user *models.User) (*models.GroupHandover, error) {
+	handover, err := service.getHandover(logger, ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if handover.IsConfirmed() {
+		return nil, ErrHandoverConfirmed
+	}
+	if err := models.ValidateHandoverConfirmation(*confirmation); err != nil {
+		logger.WithError(err).Warn("Invalid input for handover confirmation")
+		return nil, invalidInput(err)
+	}
+
+	teacher, err := findTeacherOfUser(ctx, service.teacherStore, user)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", user.ID).Error("Error fetching teacher for handover confirmation")
+		return nil, ErrInternal
+	}
+	if teacher == nil || handover.ToGroupID == nil {
+		logger.WithFields(logrus.Fields{"handover_id": id, "user_id": user.ID}).Warn("Handover confirmed by a user who is no receiving teacher")
+		return nil, ErrNotReceivingTeacher
+	}
+	group, err := service.groupStore.GetByID(ctx, *handover.ToGroupID)
+	if err != nil {
+		logger.WithError(err).WithField("group_id", *handover.ToGroupID).Error("Error fetching receiving group for handover confirmation")
+		return nil, ErrInternal
+	}
+	if (group.LeadTeacherID == nil || *group.LeadTeacherID != teacher.ID) && !slices.Contains(group.AssistantTeacherIDs, teacher.ID) {
+		logger.WithFields(logrus.Fields{"handover_id": id, "teacher_id": teacher.ID}).Warn("Handover confirmed by a teacher of another group")
+		return nil, ErrNotReceivingTeacher
+	}
+
+	confirmation.ConfirmedByTeacherID = &teacher.ID
+	confirmation.ConfirmedAt = service.clock.Now()
+	if err := service.handoverStore.Confirm(ctx, id, confirmation); err != nil {
+		switch {
+		case errors.Is(err, data.ErrNotFound):
+			return nil, ErrHandoverNotFound
+		case errors.Is(err, data.ErrConflict):
+			return nil, ErrHandoverConfirmed
+		}
+		logger.WithError(err).WithField("handover_id", id).Error("Error confirming group handover")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"handover_id": id, "teacher_id": teacher.ID}).Info("Group handover confirmed successfully")
+	return service.getHandover(logger, ctx, id)
+}
+
+// careSpecifics collects the daily care logs of the last two weeks and the medications of the last three months
+// up to the given day.
+func (service *GroupHandoverServiceImpl) careSpecifics(ctx context.Context, childID int, day models.Date) (models.HandoverCare, error) {
+	care := models.HandoverCare{Medications: []models.MedicationAdministration{}}
+	logs, err := service.dailyCareStore.GetForChild(ctx, childID, day.AddDays(-(handoverDailyCareDays - 1)), day)
+	if err != nil {
+		return care, err
+	}
+	care.DailyCareLogs = logs
+	if care.DailyCareLogs == nil {
+		care.DailyCareLogs = []models.DailyCareLog{}
+	}
+	napMinutes, naps := 0, 0
+	for _, log := range logs {
+		if log.NapMinutes != nil {
+			napMinutes += *log.NapMinutes
+			naps++
+		}
+	}
+	if naps > 0 {
+		average := napMinutes / naps
+		care.AverageNapMinutes = &average
+	}
+
+	administrations, err := service.medicationStore.GetForChild(ctx, childID)
+	if err != nil {
+		return care, err
+	}
+	from := day.AddDays(-(handoverMedicationDays - 1))
+	for _, administration := range administrations {
+		administered := models.Today(administration.AdministeredAt)
+		if !administered.Before(from.Time) && !administered.After(day.Time) {
+			care.Medications = append(care.Medications, administration)
+		}
+	}
+	return care, nil
+}
+
+// groupName returns the name of a group, nil for no group or a deleted one.
+func (service *GroupHandoverServiceImpl) groupName(ctx context.Context, groupID *int) (*string, error) {
+	if groupID == nil {
+		return nil, nil
+	}
+	group, err := service.groupStore.GetByID(ctx, *groupID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &group.Name, nil
+}
+
+func (service *GroupHandoverServiceImpl) getHandover(logger *logrus.Entry, ctx context.Context, id int) (*models.GroupHandover, error) {
+	handover, err := service.handoverStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrHandoverNotFound
+		}
+		logger.WithError(err).WithField("handover_id", id).Error("Error fetching group handover")
+		return nil, ErrInternal
+	}
+	return handover, nil
+}
+
+func (service *GroupHandoverServiceImpl) getChild(logger *logrus.Entry, ctx context.Context, childID int) (*models.Child, error) {
+	child, err := service.childStore.GetByID(ctx, childID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for group handover")
+		return nil, ErrInternal
+	}
+	return child, nil
+}
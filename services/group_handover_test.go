@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupHandoverService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2024, time.August, 2, 10, 0, 0, 0, time.UTC)
+	lead, assistant, other := 1, 2, 3
+	krippe := models.Group{ID: 10, Name: "Krippe", ChildIDs: []int{5}}
+	sonne := models.Group{ID: 11, Name: "Sonnengruppe", LeadTeacherID: &lead, AssistantTeacherIDs: []int{assistant}}
+
+	setup := func() (*services.GroupHandoverServiceImpl, *datamocks.MockGroupHandoverStore, *datamocks.MockTeacherStore) {
+		handoverStore := new(datamocks.MockGroupHandoverStore)
+		childStore := new(datamocks.MockChildStore)
+		groupStore := new(datamocks.MockGroupStore)
+		teacherStore := new(datamocks.MockTeacherStore)
+		childStore.On("GetByID", mock.Anything, 5).Return(&models.Child{ID: 5}, nil).Maybe()
+		groupStore.On("GetByID", mock.Anything, 10).Return(&krippe, nil).Maybe()
+		groupStore.On("GetByID", mock.Anything, 11).Return(&sonne, nil).Maybe()
+		groupStore.On("GetAll", mock.Anything).Return([]models.Group{krippe, sonne}, nil).Maybe()
+		teacherStore.On("GetAll", mock.Anything).Return([]models.Teacher{
+			{ID: lead, Username: "maria"}, {ID: assistant, Username: "lena"}, {ID: other, Username: "jonas"},
+		}, nil).Maybe()
+		service := services.NewGroupHandoverService(handoverStore, childStore, groupStore, teacherStore, nil, nil, nil, nil, nil, nil, clock.NewFrozen(now))
+		return service, handoverStore, teacherStore
+	}
+
+	t.Run("handover into the current group is rejected", func(t *testing.T) {
+		service, handoverStore, _ := setup()
+		_, err := service.CreateHandover(logger, ctx, &models.GroupHandover{ChildID: 5, ToGroupID: &krippe.ID}, &models.User{ID: 1})
+		assert.ErrorIs(t, err, services.ErrHandoverSameGroup)
+		_, err = service.CreateHandover(logger, ctx, &models.GroupHandover{ChildID: 5}, &models.User{ID: 1})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		handoverStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("only teachers of the receiving group confirm", func(t *testing.T) {
+		service, handoverStore, _ := setup()
+		handoverStore.On("GetByID", mock.Anything, 7).Return(&models.GroupHandover{ID: 7, ChildID: 5, FromGroupID: &krippe.ID, ToGroupID: &sonne.ID}, nil)
+		handoverStore.On("GetByID", mock.Anything, 8).Return(&models.GroupHandover{ID: 8, Confirmation: &models.HandoverConfirmation{}}, nil)
+		confirmation := func() *models.HandoverConfirmation {
+			return &models.HandoverConfirmation{ObservationsReviewed: true, CareSpecificsReviewed: true, SupportGoalsReviewed: true}
+		}
+
+		_, err := service.ConfirmHandover(logger, ctx, 7, confirmation(), &models.User{ID: 3, Username: "jonas"})
+		assert.ErrorIs(t, err, services.ErrNotReceivingTeacher)
+		_, err = service.ConfirmHandover(logger, ctx, 7, confirmation(), &models.User{ID: 4, Username: "admin"})
+		assert.ErrorIs(t, err, services.ErrNotReceivingTeacher)
+		_, err = service.ConfirmHandover(logger, ctx, 7, &models.HandoverConfirmation{ObservationsReviewed: true}, &models.User{ID: 1, Username: "maria"})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		_, err = service.ConfirmHandover(logger, ctx, 8, confirmation(), &models.User{ID: 1, Username: "maria"})
+		assert.ErrorIs(t, err, services.ErrHandoverConfirmed)
+		handoverStore.AssertNotCalled(t, "Confirm", mock.Anything, mock.Anything, mock.Anything)
+
+		handoverStore.On("Confirm", mock.Anything, 7, mock.MatchedBy(func(confirmation *models.HandoverConfirmation) bool {
+			return *confirmation.ConfirmedByTeacherID == assistant && confirmation.ConfirmedAt.Equal(now)
+		})).Return(nil).Once()
+		_, err = service.ConfirmHandover(logger, ctx, 7, confirmation(), &models.User{ID: 2, Username: "lena"})
+		require.NoError(t, err)
+		handoverStore.AssertExpectations(t)
+	})
+}
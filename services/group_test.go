@@ -0,0 +1,198 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func intPtr(i int) *int { return &i }
+
+func newGroupService() (*services.GroupServiceImpl, *datamocks.MockGroupStore, *datamocks.MockChildStore, *datamocks.MockTeacherStore) {
+	mockGroupStore := new(datamocks.MockGroupStore)
+	mockChildStore := new(datamocks.MockChildStore)
+	mockTeacherStore := new(datamocks.MockTeacherStore)
+	return services.NewGroupService(mockGroupStore, mockChildStore, mockTeacherStore, new(datamocks.MockCareContractStore), clock.System{}), mockGroupStore, mockChildStore, mockTeacherStore
+}
+
+func TestCreateGroup(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		service, mockGroupStore, _, mockTeacherStore := newGroupService()
+		group := &models.Group{Name: "Igel", Capacity: 20, LeadTeacherID: intPtr(1), AssistantTeacherIDs: []int{2}}
+		mockTeacherStore.On("GetByID", mock.Anything, 1).Return(&models.Teacher{ID: 1}, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, 2).Return(&models.Teacher{ID: 2}, nil).Once()
+		mockGroupStore.On("Create", mock.Anything, group).Return(3, nil).Once()
+		mockGroupStore.On("GetByID", mock.Anything, 3).Return(&models.Group{ID: 3, Name: "Igel", Capacity: 20}, nil).Once()
+
+		created, err := service.CreateGroup(logger, ctx, group)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, created.ID)
+		mockGroupStore.AssertExpectations(t)
+		mockTeacherStore.AssertExpectations(t)
+	})
+
+	t.Run("inverted age band", func(t *testing.T) {
+		service, _, _, _ := newGroupService()
+		group := &models.Group{Name: "Igel", Capacity: 20, MinAgeMonths: intPtr(72), MaxAgeMonths: intPtr(24)}
+
+		_, err := service.CreateGroup(logger, ctx, group)
+		assert.Equal(t, services.ErrInvalidInput, err)
+	})
+
+	t.Run("lead teacher is also assistant", func(t *testing.T) {
+		service, _, _, _ := newGroupService()
+		group := &models.Group{Name: "Igel", Capacity: 20, LeadTeacherID: intPtr(1), AssistantTeacherIDs: []int{1}}
+
+		_, err := service.CreateGroup(logger, ctx, group)
+		assert.Equal(t, services.ErrInvalidInput, err)
+	})
+
+	t.Run("unknown teacher", func(t *testing.T) {
+		service, _, _, mockTeacherStore := newGroupService()
+		group := &models.Group{Name: "Igel", Capacity: 20, LeadTeacherID: intPtr(9)}
+		mockTeacherStore.On("GetByID", mock.Anything, 9).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.CreateGroup(logger, ctx, group)
+		assert.Equal(t, services.ErrInvalidInput, err)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		service, mockGroupStore, _, _ := newGroupService()
+		group := &models.Group{Name: "Igel", Capacity: 20}
+		mockGroupStore.On("Create", mock.Anything, group).Return(0, data.ErrConflict).Once()
+
+		_, err := service.CreateGroup(logger, ctx, group)
+		assert.Equal(t, services.ErrAlreadyExists, err)
+	})
+}
+
+func TestAssignChildToGroup(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	fourYearsOld := models.DateOf(time.Now().AddDate(-4, 0, -1))
+
+	t.Run("within capacity and age band", func(t *testing.T) {
+		service, mockGroupStore, mockChildStore, _ := newGroupService()
+		group := &models.Group{ID: 1, Capacity: 3, MinAgeMonths: intPtr(36), MaxAgeMonths: intPtr(72), ChildIDs: []int{5}}
+		mockGroupStore.On("GetByID", mock.Anything, 1).Return(group, nil).Twice()
+		mockChildStore.On("GetByID", mock.Anything, 7).Return(&models.Child{ID: 7, Birthdate: fourYearsOld}, nil).Once()
+		mockGroupStore.On("AddChild", mock.Anything, 1, 7).Return(nil).Once()
+
+		result, err := service.AssignChild(logger, ctx, 1, 7)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Warnings)
+		mockGroupStore.AssertExpectations(t)
+	})
+
+	t.Run("over capacity and outside age band", func(t *testing.T) {
+		service, mockGroupStore, mockChildStore, _ := newGroupService()
+		group := &models.Group{ID: 1, Capacity: 1, MaxAgeMonths: intPtr(36), ChildIDs: []int{5}}
+		mockGroupStore.On("GetByID", mock.Anything, 1).Return(group, nil).Twice()
+		mockChildStore.On("GetByID", mock.Anything, 7).Return(&models.Child{ID: 7, Birthdate: fourYearsOld}, nil).Once()
+		mockGroupStore.On("AddChild", mock.Anything, 1, 7).Return(nil).Once()
+
+		result, err := service.AssignChild(logger, ctx, 1, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"group is over capacity: 2 children for 1 places",
+			"child is 48 months old, outside the age band of the group",
+		}, result.Warnings)
+	})
+
+	t.Run("archived child", func(t *testing.T) {
+		service, mockGroupStore, mockChildStore, _ := newGroupService()
+		archivedAt := time.Now()
+		mockGroupStore.On("GetByID", mock.Anything, 1).Return(&models.Group{ID: 1, Capacity: 3}, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, 7).Return(&models.Child{ID: 7, ArchivedAt: &archivedAt}, nil).Once()
+
+		_, err := service.AssignChild(logger, ctx, 1, 7)
+		assert.Equal(t, services.ErrInvalidInput, err)
+		mockGroupStore.AssertNotCalled(t, "AddChild", mock.Anything, 1, 7)
+	})
+
+	t.Run("group not found", func(t *testing.T) {
+		service, mockGroupStore, _, _ := newGroupService()
+		mockGroupStore.On("GetByID", mock.Anything, 1).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.AssignChild(logger, ctx, 1, 7)
+		assert.Equal(t, services.ErrNotFound, err)
+	})
+}
+
+func TestGetAllGroups(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	service, mockGroupStore, _, _ := newGroupService()
+
+	mockGroupStore.On("GetAll", mock.Anything).Return([]models.Group{
+		{ID: 1, Name: "Igel", Capacity: 4, ChildIDs: []int{5, 6}},
+		{ID: 2, Name: "Füchse", Capacity: 2, ChildIDs: []int{}},
+	}, nil).Once()
+	mockGroupStore.On("CountUsage", mock.Anything).Return(map[int]models.UsageCounts{1: {Entries: 7, OpenAssignments: 3, Children: 2}}, nil).Once()
+
+	groups, err := service.GetAllGroups(logger, ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, &models.UsageCounts{Entries: 7, OpenAssignments: 3, Children: 2}, groups[0].Usage)
+		assert.Equal(t, &models.UsageCounts{}, groups[1].Usage)
+	}
+	mockGroupStore.AssertExpectations(t)
+}
+
+func TestGetGroupStatistics(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	mockGroupStore := new(datamocks.MockGroupStore)
+	mockChildStore := new(datamocks.MockChildStore)
+	mockCareContractStore := new(datamocks.MockCareContractStore)
+	service := services.NewGroupService(mockGroupStore, mockChildStore, new(datamocks.MockTeacherStore), mockCareContractStore, clock.System{})
+
+	mockGroupStore.On("GetAll", mock.Anything).Return([]models.Group{
+		{ID: 1, Name: "Igel", Capacity: 4, MinAgeMonths: intPtr(36), LeadTeacherID: intPtr(1), AssistantTeacherIDs: []int{2}, ChildIDs: []int{5, 6, 7}},
+		{ID: 2, Name: "Füchse", Capacity: 2, ChildIDs: []int{}},
+	}, nil).Once()
+	// Child 7 is archived and therefore not returned.
+	mockChildStore.On("GetAll", mock.Anything).Return([]models.Child{
+		{ID: 5, Birthdate: models.DateOf(time.Now().AddDate(-4, 0, -1))},
+		{ID: 6, Birthdate: models.DateOf(time.Now().AddDate(-2, 0, -1))},
+	}, nil).Once()
+	today := models.Today(time.Now())
+	mockCareContractStore.On("GetValidBetween", mock.Anything, today, today).Return([]models.CareContract{
+		{ID: 1, ChildID: 5, WeeklyHours: models.CareHours35, ValidFrom: today.AddDays(-100)},
+		{ID: 2, ChildID: 7, WeeklyHours: models.CareHours45, ValidFrom: today.AddDays(-100)},
+	}, nil).Once()
+
+	statistics, err := service.GetGroupStatistics(logger, ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, statistics, 2) {
+		igel := statistics[0]
+		assert.Equal(t, 2, igel.ChildCount)
+		assert.Equal(t, 2, igel.FreePlaces)
+		assert.Equal(t, 0.5, igel.Utilization)
+		assert.Equal(t, 2, igel.StaffCount)
+		assert.Equal(t, 1.0, *igel.ChildrenPerStaff)
+		assert.Equal(t, 36.0, *igel.AverageAgeMonths)
+		assert.Equal(t, 1, igel.ChildrenOutsideAgeBand)
+		assert.Equal(t, 35, igel.BookedCareHours, "the contract of the archived child is not counted")
+		assert.Equal(t, 1, igel.ChildrenWithoutCareContract)
+
+		foxes := statistics[1]
+		assert.Equal(t, 0, foxes.ChildCount)
+		assert.Nil(t, foxes.ChildrenPerStaff)
+		assert.Nil(t, foxes.AverageAgeMonths)
+		assert.Equal(t, 0, foxes.BookedCareHours)
+	}
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ImportJobService defines the interface for persisted, resumable bulk imports.
+type ImportJobService interface {
+	ImportChildren(logger *logrus.Entry, ctx context.Context, fileName string, rows []models.ImportJobRow) (*models.ImportJob, error)
+	GetImportJobs(logger *logrus.Entry, ctx context.Context) ([]models.ImportJob, error)
+	GetImportJobByID(logger *logrus.Entry, ctx context.Context, id int) (*models.ImportJob, error)
+	ResumeImportJob(logger *logrus.Entry, ctx context.Context, id int) (*models.ImportJob, error)
+	RollbackImportJob(logger *logrus.Entry, ctx context.Context, id int, rowNumbers []int) (*models.ImportRollbackResult, error)
+}
+
+// ImportJobServiceImpl implements ImportJobService.
+type ImportJobServiceImpl struct {
+	importJobStore data.ImportJobStore
+}
+
+// NewImportJobService creates a new ImportJobServiceImpl.
+func NewImportJobService(importJobStore data.ImportJobStore) *ImportJobServiceImpl {
+	return &ImportJobServiceImpl{importJobStore: importJobStore}
+}
+
+// ImportChildren persists the parsed rows as an import job and imports the valid ones.
+// Rows without a child are stored as invalid with their error. If the import stops on an error,
+// the returned job has the failed status and can be resumed.
+func (service *ImportJobServiceImpl) ImportChildren(logger *logrus.Entry, ctx context.Context, fileName string, rows []models.ImportJobRow) (*models.ImportJob, error) {
+	job := &models.ImportJob{FileName: fileName, Status: models.ImportJobStatusRunning, Rows: make([]models.ImportJobRow, len(rows))}
+	if user, ok := ctx.Value(middleware.ContextKeyUser).(*models.User); ok {
+		job.CreatedByUserID = &user.ID
+	}
+	for i, row := range rows {
+		row.Status = models.ImportRowStatusPending
+		if row.Child == nil {
+			row.Status = models.ImportRowStatusInvalid
+		}
+		job.Rows[i] = row
+	}
+
+	id, err := service.importJobStore.Create(ctx, job)
+	if err != nil {
+		logger.WithError(err).Error("Error creating import job")
+		return nil, ErrInternal
+	}
+	logger.WithField("import_job_id", id).Infof("Import job created with %d rows", len(rows))
+	return service.run(logger, ctx, id)
+}
+
+// GetImportJobs fetches the history of import jobs.
+func (service *ImportJobServiceImpl) GetImportJobs(logger *logrus.Entry, ctx context.Context) ([]models.ImportJob, error) {
+	jobs, err := service.importJobStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching import jobs")
+		return nil, ErrInternal
+	}
+	return jobs, nil
+}
+
+// GetImportJobByID fetches an import job with its rows.
+func (service *ImportJobServiceImpl) GetImportJobByID(logger *logrus.Entry, ctx context.Context, id int) (*models.ImportJob, error) {
+	job, err := service.importJobStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("import_job_id", id).Error("Error fetching import job")
+		return nil, ErrInternal
+	}
+	return job, nil
+}
+
+// ResumeImportJob imports the pending rows of a failed or interrupted import job.
+// Completed and rolled back jobs cannot be resumed.
+func (service *ImportJobServiceImpl) ResumeImportJob(logger *logrus.Entry, ctx context.Context, id int) (*models.ImportJob, error) {
+	job, err := service.GetImportJobByID(logger, ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == models.ImportJobStatusCompleted || job.Status == models.ImportJobStatusRolledBack {
+		logger.WithField("import_job_id", id).WithField("status", job.Status).Warn("Import job cannot be resumed")
+		return nil, ErrInvalidStateTransition
+	}
+
+	if err := service.importJobStore.UpdateStatus(ctx, id, models.ImportJobStatusRunning); err != nil {
+		logger.WithError(err).WithField("import_job_id", id).Error("Error resuming import job")
+		return nil, ErrInternal
+	}
+	logger.WithField("import_job_id", id).Info("Resuming import job")
+	return service.run(logger, ctx, id)
+}
+
+// RollbackImportJob deletes the children created by the given rows of an import job, or by all of its rows if none are given.
+// Children that have been documented since are kept and reported as skipped.
+// A complete rollback without skipped rows marks the job as rolled back, so that it cannot be resumed.
+func (service *ImportJobServiceImpl) RollbackImportJob(logger *logrus.Entry, ctx context.Context, id int, rowNumbers []int) (*models.ImportRollbackResult, error) {
+	job, err := service.GetImportJobByID(logger, ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == models.ImportJobStatusRunning {
+		logger.WithField("import_job_id", id).Warn("Running import job cannot be rolled back")
+		return nil, ErrInvalidStateTransition
+	}
+
+	imported := []int{}
+	for _, row := range job.Rows {
+		if row.Status == models.ImportRowStatusImported {
+			imported = append(imported, row.RowNumber)
+		}
+	}
+	completeRollback := len(rowNumbers) == 0
+	if completeRollback {
+		rowNumbers = imported
+	}
+	for _, rowNumber := range rowNumbers {
+		if !slices.Contains(imported, rowNumber) {
+			logger.WithField("import_job_id", id).WithField("row_number", rowNumber).Warn("Row to roll back has not been imported")
+			return nil, ErrInvalidInput
+		}
+	}
+
+	result := &models.ImportRollbackResult{RolledBackRows: []int{}, SkippedRows: []int{}}
+	for _, rowNumber := range rowNumbers {
+		err := service.importJobStore.RollbackRow(ctx, id, rowNumber)
+		switch {
+		case err == nil:
+			result.RolledBackRows = append(result.RolledBackRows, rowNumber)
+		case errors.Is(err, data.ErrForeignKeyConstraint):
+			logger.WithField("import_job_id", id).WithField("row_number", rowNumber).Warn("Imported child has been documented, row is not rolled back")
+			result.SkippedRows = append(result.SkippedRows, rowNumber)
+		case errors.Is(err, data.ErrConflict):
+			// Rolled back concurrently.
+		default:
+			logger.WithError(err).WithField("import_job_id", id).WithField("row_number", rowNumber).Error("Error rolling back import row")
+			return nil, ErrInternal
+		}
+	}
+
+	if completeRollback && len(result.SkippedRows) == 0 {
+		if err := service.importJobStore.UpdateStatus(ctx, id, models.ImportJobStatusRolledBack); err != nil {
+			logger.WithError(err).WithField("import_job_id", id).Error("Error updating status of rolled back import job")
+			return nil, ErrInternal
+		}
+	}
+
+	result.Job, err = service.GetImportJobByID(logger, ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithField("import_job_id", id).Infof("Rolled back %d rows of import job, skipped %d", len(result.RolledBackRows), len(result.SkippedRows))
+	return result, nil
+}
+
+// importBatchSize is the number of rows imported in one transaction.
+const importBatchSize = 200
+
+// run imports the pending rows of a job in batches. Each batch is committed on its own. A batch that fails is
+// retried row by row, so an import that stops on an error keeps the rows before the failing one and marks the
+// job as failed.
+func (service *ImportJobServiceImpl) run(logger *logrus.Entry, ctx context.Context, id int) (*models.ImportJob, error) {
+	job, err := service.importJobStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("import_job_id", id).Error("Error fetching import job to run")
+		return nil, ErrInternal
+	}
+
+	pending := []models.ImportJobRow{}
+	for _, row := range job.Rows {
+		if row.Status == models.ImportRowStatusPending && row.Child != nil {
+			pending = append(pending, row)
+		}
+	}
+
+	status := models.ImportJobStatusCompleted
+	for batch := range slices.Chunk(pending, importBatchSize) {
+		if _, err := service.importJobStore.ImportRows(ctx, id, batch); err == nil {
+			continue
+		}
+		if !service.importOneByOne(logger, ctx, id, batch) {
+			status = models.ImportJobStatusFailed
+			break
+		}
+	}
+
+	if err := service.importJobStore.UpdateStatus(ctx, id, status); err != nil {
+		logger.WithError(err).WithField("import_job_id", id).Error("Error updating import job status")
+		return nil, ErrInternal
+	}
+	job, err = service.importJobStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("import_job_id", id).Error("Error fetching finished import job")
+		return nil, ErrInternal
+	}
+	logger.WithField("import_job_id", id).WithField("status", status).Info("Import job finished")
+	return job, nil
+}
+
+// importOneByOne imports the rows of a failed batch one by one and reports whether all of them were imported.
+func (service *ImportJobServiceImpl) importOneByOne(logger *logrus.Entry, ctx context.Context, id int, rows []models.ImportJobRow) bool {
+	for i := range rows {
+		row := &rows[i]
+		_, err := service.importJobStore.ImportRow(ctx, id, row)
+		if err != nil {
+			if errors.Is(err, data.ErrConflict) {
+				// Imported concurrently by another run of the job.
+				continue
+			}
+			logger.WithError(err).WithField("import_job_id", id).WithField("row_number", row.RowNumber).Error("Import stopped on error")
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportChildren(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	rowError := "Reihe 2: Ungültiges Format für Geburtsdatum"
+
+	t.Run("stops on error and can be resumed", func(t *testing.T) {
+		mockImportJobStore := new(datamocks.MockImportJobStore)
+		service := services.NewImportJobService(mockImportJobStore)
+		rows := []models.ImportJobRow{
+			{RowNumber: 1, ChildName: "Anna", Child: &models.Child{FirstName: "Anna"}},
+			{RowNumber: 2, ChildName: "Bilbo", Error: &rowError},
+			{RowNumber: 3, ChildName: "Carla", Child: &models.Child{FirstName: "Carla"}},
+		}
+		mockImportJobStore.On("Create", mock.Anything, mock.MatchedBy(func(job *models.ImportJob) bool {
+			return job.Status == models.ImportJobStatusRunning && job.FileName == "Kindliste.xlsx" &&
+				job.Rows[0].Status == models.ImportRowStatusPending && job.Rows[1].Status == models.ImportRowStatusInvalid
+		})).Return(5, nil).Once()
+		pending := &models.ImportJob{ID: 5, Status: models.ImportJobStatusRunning, Rows: []models.ImportJobRow{
+			{RowNumber: 1, Status: models.ImportRowStatusPending, Child: rows[0].Child},
+			{RowNumber: 2, Status: models.ImportRowStatusInvalid},
+			{RowNumber: 3, Status: models.ImportRowStatusPending, Child: rows[2].Child},
+		}}
+		mockImportJobStore.On("GetByID", mock.Anything, 5).Return(pending, nil).Twice()
+		// The batch fails as a whole and is retried row by row to import the rows before the failing one.
+		mockImportJobStore.On("ImportRows", mock.Anything, 5, []models.ImportJobRow{pending.Rows[0], pending.Rows[2]}).Return(0, errors.New("disk I/O error")).Once()
+		mockImportJobStore.On("ImportRow", mock.Anything, 5, mock.MatchedBy(func(row *models.ImportJobRow) bool { return row.RowNumber == 1 })).Return(10, nil).Once()
+		mockImportJobStore.On("ImportRow", mock.Anything, 5, mock.MatchedBy(func(row *models.ImportJobRow) bool { return row.RowNumber == 3 })).Return(0, errors.New("disk I/O error")).Once()
+		mockImportJobStore.On("UpdateStatus", mock.Anything, 5, models.ImportJobStatusFailed).Return(nil).Once()
+
+		job, err := service.ImportChildren(logger, ctx, "Kindliste.xlsx", rows)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, job.ID)
+		mockImportJobStore.AssertExpectations(t)
+
+		// Row 1 was imported before the failure, only row 3 is imported on resume.
+		failed := &models.ImportJob{ID: 5, Status: models.ImportJobStatusFailed, Rows: []models.ImportJobRow{
+			{RowNumber: 1, Status: models.ImportRowStatusImported, Child: rows[0].Child},
+			{RowNumber: 2, Status: models.ImportRowStatusInvalid},
+			{RowNumber: 3, Status: models.ImportRowStatusPending, Child: rows[2].Child},
+		}}
+		mockImportJobStore.On("GetByID", mock.Anything, 5).Return(failed, nil).Times(3)
+		mockImportJobStore.On("UpdateStatus", mock.Anything, 5, models.ImportJobStatusRunning).Return(nil).Once()
+		mockImportJobStore.On("ImportRows", mock.Anything, 5, []models.ImportJobRow{failed.Rows[2]}).Return(1, nil).Once()
+		mockImportJobStore.On("UpdateStatus", mock.Anything, 5, models.ImportJobStatusCompleted).Return(nil).Once()
+
+		_, err = service.ResumeImportJob(logger, ctx, 5)
+		assert.NoError(t, err)
+		mockImportJobStore.AssertExpectations(t)
+		mockImportJobStore.AssertNumberOfCalls(t, "ImportRow", 2)
+	})
+
+	t.Run("completed job cannot be resumed", func(t *testing.T) {
+		mockImportJobStore := new(datamocks.MockImportJobStore)
+		service := services.NewImportJobService(mockImportJobStore)
+		mockImportJobStore.On("GetByID", mock.Anything, 5).Return(&models.ImportJob{ID: 5, Status: models.ImportJobStatusCompleted}, nil).Once()
+
+		_, err := service.ResumeImportJob(logger, ctx, 5)
+		assert.Equal(t, services.ErrInvalidStateTransition, err)
+	})
+}
+
+func TestRollbackImportJob(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	job := &models.ImportJob{ID: 5, Status: models.ImportJobStatusCompleted, Rows: []models.ImportJobRow{
+		{RowNumber: 1, Status: models.ImportRowStatusImported},
+		{RowNumber: 2, Status: models.ImportRowStatusInvalid},
+		{RowNumber: 3, Status: models.ImportRowStatusImported},
+	}}
+
+	t.Run("complete rollback", func(t *testing.T) {
+		mockImportJobStore := new(datamocks.MockImportJobStore)
+		service := services.NewImportJobService(mockImportJobStore)
+		mockImportJobStore.On("GetByID", mock.Anything, 5).Return(job, nil)
+		mockImportJobStore.On("RollbackRow", mock.Anything, 5, 1).Return(nil).Once()
+		mockImportJobStore.On("RollbackRow", mock.Anything, 5, 3).Return(nil).Once()
+		mockImportJobStore.On("UpdateStatus", mock.Anything, 5, models.ImportJobStatusRolledBack).Return(nil).Once()
+
+		result, err := service.RollbackImportJob(logger, ctx, 5, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 3}, result.RolledBackRows)
+		assert.Empty(t, result.SkippedRows)
+		mockImportJobStore.AssertExpectations(t)
+	})
+
+	t.Run("documented child is skipped", func(t *testing.T) {
+		mockImportJobStore := new(datamocks.MockImportJobStore)
+		service := services.NewImportJobService(mockImportJobStore)
+		mockImportJobStore.On("GetByID", mock.Anything, 5).Return(job, nil)
+		mockImportJobStore.On("RollbackRow", mock.Anything, 5, 1).Return(data.ErrForeignKeyConstraint).Once()
+		mockImportJobStore.On("RollbackRow", mock.Anything, 5, 3).Return(nil).Once()
+
+		result, err := service.RollbackImportJob(logger, ctx, 5, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3}, result.RolledBackRows)
+		assert.Equal(t, []int{1}, result.SkippedRows)
+		mockImportJobStore.AssertNotCalled(t, "UpdateStatus", mock.Anything, 5, models.ImportJobStatusRolledBack)
+	})
+
+	t.Run("partial rollback of an invalid row", func(t *testing.T) {
+		mockImportJobStore := new(datamocks.MockImportJobStore)
+		service := services.NewImportJobService(mockImportJobStore)
+		mockImportJobStore.On("GetByID", mock.Anything, 5).Return(job, nil)
+
+		_, err := service.RollbackImportJob(logger, ctx, 5, []int{2})
+		assert.Equal(t, services.ErrInvalidInput, err)
+		mockImportJobStore.AssertNotCalled(t, "RollbackRow", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
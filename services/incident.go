@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// IncidentService defines the interface for the incidents and accidents of children.
+type IncidentService interface {
+	CreateIncident(logger *logrus.Entry, ctx context.Context, incident *models.Incident, user *models.User) (*models.Incident, error)
+	GetIncidentByID(logger *logrus.Entry, ctx context.Context, id int) (*models.Incident, error)
+	GetIncidentsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.Incident, error)
+	UpdateIncident(logger *logrus.Entry, ctx context.Context, incident *models.Incident) error
+	DeleteIncident(logger *logrus.Entry, ctx context.Context, id int) error
+	// GetIncidentReportPDF renders the Unfallanzeige of an incident for the accident insurer. Incidents missing
+	// fields the Unfallanzeige asks for are rejected with a *ValidationError listing them.
+	GetIncidentReportPDF(logger *logrus.Entry, ctx context.Context, id int) ([]byte, error)
+}
+
+// IncidentServiceImpl implements IncidentService.
+type IncidentServiceImpl struct {
+	incidentStore       data.IncidentStore
+	childStore          data.ChildStore
+	kitaMasterdataStore data.KitaMasterdataStore
+	clock               clock.Clock
+}
+
+// NewIncidentService creates a new IncidentServiceImpl.
+func NewIncidentService(incidentStore data.IncidentStore, childStore data.ChildStore, kitaMasterdataStore data.KitaMasterdataStore, clock clock.Clock) *IncidentServiceImpl {
+	return &IncidentServiceImpl{
+		incidentStore:       incidentStore,
+		childStore:          childStore,
+		kitaMasterdataStore: kitaMasterdataStore,
+		clock:               clock,
+	}
+}
+
+// CreateIncident records an incident of a child, the details can still be incomplete.
+func (service *IncidentServiceImpl) CreateIncident(logger *logrus.Entry, ctx context.Context, incident *models.Incident, user *models.User) (*models.Incident, error) {
+	if err := service.checkIncident(logger, incident); err != nil {
+		return nil, err
+	}
+	if _, err := service.getChild(logger, ctx, incident.ChildID); err != nil {
+		return nil, err
+	}
+	incident.ReportedByUserID = &user.ID
+
+	id, err := service.incidentStore.Create(ctx, incident)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", incident.ChildID).Error("Error creating incident")
+		return nil, ErrInternal
+	}
+	created, err := service.incidentStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("incident_id", id).Error("Error fetching created incident")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"incident_id": id, "child_id": incident.ChildID}).Info("Incident created successfully")
+	return created, nil
+}
+
+// GetIncidentByID fetches an incident by ID.
+func (service *IncidentServiceImpl) GetIncidentByID(logger *logrus.Entry, ctx context.Context, id int) (*models.Incident, error) {
+	incident, err := service.incidentStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("incident_id", id).Error("Error fetching incident")
+		return nil, ErrInternal
+	}
+	return incident, nil
+}
+
+// GetIncidentsForChild fetches the incidents of a child, the latest first.
+func (service *IncidentServiceImpl) GetIncidentsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.Incident, error) {
+	if _, err := service.getChild(logger, ctx, childID); err != nil {
+		return nil, err
+	}
+	incidents, err := service.incidentStore.GetForChild(ctx, childID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching incidents of child")
+		return nil, ErrInternal
+	}
+	return incidents, nil
+}
+
+// UpdateIncident updates the details of an incident, the child stays the same.
+func (service *IncidentServiceImpl) UpdateIncident(logger *logrus.Entry, ctx context.Context, incident *models.Incident) error {
+	existing, err := service.GetIncidentByID(logger, ctx, incident.ID)
+	if err != nil {
+		return err
+	}
+	incident.ChildID = existing.ChildID
+	if err := service.checkIncident(logger, incident); err != nil {
+		return err
+	}
+
+	if err := service.incidentStore.Update(ctx, incident); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("incident_id", incident.ID).Error("Error updating incident")
+		return ErrInternal
+	}
+	logger.WithField("incident_id", incident.ID).Info("Incident updated successfully")
+	return nil
+}
+
+// DeleteIncident deletes an incident.
+func (service *IncidentServiceImpl) DeleteIncident(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.incidentStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("incident_id", id).Error("Error deleting incident")
+		return ErrInternal
+	}
+	logger.WithField("incident_id", id).Info("Incident deleted successfully")
+	return nil
+}
+
+// GetIncidentReportPDF renders the Unfallanzeige of a complete incident.
+func (service *IncidentServiceImpl) GetIncidentReportPDF(logger *logrus.Entry, ctx context.Context, id int) ([]byte, error) {
+	incident, err := service.GetIncidentByID(logger, ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := models.ValidateIncidentComplete(*incident); err != nil {
+		logger.WithError(err).WithField("incident_id", id).Warn("Incident is incomplete for the Unfallanzeige")
+		return nil, invalidInput(err)
+	}
+	child, err := service.getChild(logger, ctx, incident.ChildID)
+	if err != nil {
+		return nil, err
+	}
+	masterdata, err := service.kitaMasterdataStore.Get(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching masterdata for Unfallanzeige")
+		return nil, ErrInternal
+	}
+
+	stamp := reportStamp{DocumentID: uuid.NewString(), Facility: masterdata.Name, GeneratedAt: service.clock.Now()}
+	if user, ok := ctx.Value(middleware.ContextKeyUser).(*models.User); ok {
+		stamp.GeneratedBy = user.Username
+	}
+	content, err := renderIncidentReport(incident, child, masterdata, stamp)
+	if err != nil {
+		logger.WithError(err).WithField("incident_id", id).Error("Error rendering Unfallanzeige")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"incident_id": id, "document_id": stamp.DocumentID}).Info("Unfallanzeige generated successfully")
+	return content, nil
+}
+
+// checkIncident validates an incident. Neither the incident nor informing the parents lie in the future.
+func (service *IncidentServiceImpl) checkIncident(logger *logrus.Entry, incident *models.Incident) error {
+	if err := models.ValidateIncident(*incident); err != nil {
+		logger.WithError(err).Warn("Invalid input for incident")
+		return invalidInput(err)
+	}
+	now := service.clock.Now()
+	if incident.OccurredAt.After(now) || (incident.ParentInformedAt != nil && incident.ParentInformedAt.After(now)) {
+		logger.WithField("occurred_at", incident.OccurredAt).Warn("Incident in the future")
+		return fmt.Errorf("%w: the incident and informing the parents cannot lie in the future", ErrInvalidInput)
+	}
+	return nil
+}
+
+func (service *IncidentServiceImpl) getChild(logger *logrus.Entry, ctx context.Context, childID int) (*models.Child, error) {
+	child, err := service.childStore.GetByID(ctx, childID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for incident")
+		return nil, ErrInternal
+	}
+	return child, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+
+	"kitadoc-backend/internal/buildinfo"
+	"kitadoc-backend/models"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// incidentField is a numbered field of the Unfallanzeige.
+type incidentField struct {
+	label string
+	value string
+}
+
+// renderIncidentReport renders the Unfallanzeige of an incident as a portrait A4 PDF in the layout of the form of
+// the accident insurers for children in day care: numbered, boxed fields followed by the signature of the management.
+// The insurer is left blank, the facility fills it in when signing.
+func renderIncidentReport(incident *models.Incident, child *models.Child, masterdata *models.KitaMasterdata, stamp reportStamp) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	title := "Unfallanzeige für Kinder in Tageseinrichtungen"
+
+	pdf.SetTitle(title, true)
+	pdf.SetAuthor(stamp.Facility, true)
+	pdf.SetCreator(buildinfo.Get().Generator(), true)
+	pdf.SetSubject(stamp.footerText(), true)
+	pdf.SetKeywords(stamp.DocumentID, true)
+	pdf.SetCreationDate(stamp.GeneratedAt)
+	pdf.SetModificationDate(stamp.GeneratedAt)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.CellFormat(0, 5, translate(stamp.footerText()), "", 0, "L", false, 0, "")
+		left, _, _, _ := pdf.GetMargins()
+		pdf.SetX(left)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Seite %d/{nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, translate(title), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	location := models.FacilityLocation()
+	value := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	medicalTreatment := "Nein"
+	if incident.MedicalTreatment {
+		medicalTreatment = "Ja, " + value(incident.Physician)
+	}
+	fields := []incidentField{
+		{"Name und Anschrift der Einrichtung", fmt.Sprintf("%s, %s %s, %s %s", masterdata.Name, masterdata.Street, masterdata.HouseNumber,
+			masterdata.PostalCode, masterdata.City)},
+		{"Telefon und E-Mail der Einrichtung", masterdata.PhoneNumber + ", " + masterdata.Email},
+		{"Empfänger (Unfallversicherungsträger)", ""},
+		{"Name, Vorname des Kindes", child.LastName + ", " + child.FirstName},
+		{"Geburtsdatum", child.Birthdate.Format("02.01.2006")},
+		{"Unfallzeitpunkt", incident.OccurredAt.In(location).Format("02.01.2006, 15:04 Uhr")},
+		{"Unfallort", value(incident.Location)},
+		{"Ausführliche Schilderung des Unfallhergangs", value(incident.Description)},
+		{"Verletzte Körperteile, Art der Verletzung", value(incident.Injuries)},
+		{"Erste Hilfe (Maßnahmen, durch wen)", value(incident.FirstAid)},
+		{"Zeugen des Unfalls", value(incident.Witnesses)},
+		{"Ärztliche Behandlung, Name und Anschrift des Arztes", medicalTreatment},
+		{"Eltern informiert am", incident.ParentInformedAt.In(location).Format("02.01.2006, 15:04 Uhr")},
+	}
+	for i, field := range fields {
+		renderIncidentField(pdf, translate, i+1, field)
+	}
+
+	pdf.Ln(12)
+	pdf.SetFont("Helvetica", "", 9)
+	width, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	half := (width - left - right) / 2
+	pdf.CellFormat(half-5, 5, "", "B", 0, "L", false, 0, "")
+	pdf.CellFormat(10, 5, "", "", 0, "L", false, 0, "")
+	pdf.CellFormat(half-5, 5, "", "B", 1, "L", false, 0, "")
+	pdf.CellFormat(half+5, 5, "Datum", "", 0, "L", false, 0, "")
+	pdf.CellFormat(half-5, 5, translate("Leitung der Einrichtung (Unterschrift)"), "", 1, "L", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderIncidentField renders a numbered field as a box with the label on top and the value below it.
+func renderIncidentField(pdf *gofpdf.Fpdf, translate func(string) string, number int, field incidentField) {
+	pdf.SetFont("Helvetica", "B", 8)
+	pdf.SetFillColor(230, 230, 230)
+	pdf.CellFormat(0, 5, translate(fmt.Sprintf("%d  %s", number, field.label)), "LTR", 1, "L", true, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	text := field.value
+	if text == "" {
+		text = " "
+	}
+	pdf.MultiCell(0, 6, translate(text), "LRB", "L", false)
+	pdf.Ln(1)
+}
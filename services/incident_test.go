@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 5, 15, 0, 0, 0, time.UTC)
+	occurredAt := now.Add(-4 * time.Hour)
+	informedAt := now.Add(-3 * time.Hour)
+	user := &models.User{ID: 2, Role: string(data.RoleTeacher)}
+	text := func(s string) *string { return &s }
+	child := &models.Child{ID: 1, FirstName: "Anna", LastName: "Müller", Birthdate: models.NewDate(2021, time.June, 1)}
+	complete := func() *models.Incident {
+		return &models.Incident{
+			ID:               7,
+			ChildID:          child.ID,
+			OccurredAt:       occurredAt,
+			Location:         text("Garten, Klettergerüst"),
+			Description:      text("Beim Klettern abgerutscht und auf das Knie gefallen"),
+			Injuries:         text("Schürfwunde am linken Knie"),
+			FirstAid:         text("Wunde gereinigt und verbunden durch Frau Schmidt"),
+			Witnesses:        text("Frau Schmidt"),
+			ParentInformedAt: &informedAt,
+		}
+	}
+
+	setup := func() (*services.IncidentServiceImpl, *datamocks.MockIncidentStore) {
+		incidentStore := new(datamocks.MockIncidentStore)
+		childStore := new(datamocks.MockChildStore)
+		masterdataStore := new(datamocks.MockKitaMasterdataStore)
+		childStore.On("GetByID", mock.Anything, child.ID).Return(child, nil).Maybe()
+		childStore.On("GetByID", mock.Anything, 99).Return(nil, data.ErrNotFound).Maybe()
+		masterdataStore.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Kita Sonnenschein", Street: "Hauptstraße", HouseNumber: "1",
+			PostalCode: "12345", City: "Teststadt", PhoneNumber: "0123 456789", Email: "kita@example.com"}, nil).Maybe()
+		return services.NewIncidentService(incidentStore, childStore, masterdataStore, clock.NewFrozen(now)), incidentStore
+	}
+
+	t.Run("create an incomplete incident", func(t *testing.T) {
+		service, store := setup()
+		incident := &models.Incident{ChildID: child.ID, OccurredAt: occurredAt, Location: text("Garten")}
+		store.On("Create", mock.Anything, mock.MatchedBy(func(incident *models.Incident) bool {
+			return *incident.ReportedByUserID == user.ID
+		})).Return(7, nil).Once()
+		store.On("GetByID", mock.Anything, 7).Return(incident, nil).Once()
+
+		created, err := service.CreateIncident(logger, ctx, incident, user)
+		require.NoError(t, err)
+		assert.Equal(t, incident, created)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("create rejects invalid incidents", func(t *testing.T) {
+		service, store := setup()
+		before := occurredAt.Add(-time.Hour)
+		tests := []struct {
+			name     string
+			incident models.Incident
+			want     error
+		}{
+			{"missing time", models.Incident{ChildID: child.ID}, services.ErrInvalidInput},
+			{"future incident", models.Incident{ChildID: child.ID, OccurredAt: now.Add(time.Hour)}, services.ErrInvalidInput},
+			{"parents informed before the incident", models.Incident{ChildID: child.ID, OccurredAt: occurredAt, ParentInformedAt: &before}, services.ErrInvalidInput},
+			{"unknown child", models.Incident{ChildID: 99, OccurredAt: occurredAt}, services.ErrChildNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				incident := tt.incident
+				_, err := service.CreateIncident(logger, ctx, &incident, user)
+				assert.ErrorIs(t, err, tt.want)
+			})
+		}
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("update keeps the child", func(t *testing.T) {
+		service, store := setup()
+		store.On("GetByID", mock.Anything, 7).Return(complete(), nil).Once()
+		store.On("Update", mock.Anything, mock.MatchedBy(func(incident *models.Incident) bool {
+			return incident.ChildID == child.ID
+		})).Return(nil).Once()
+
+		update := complete()
+		update.ChildID = 42
+		require.NoError(t, service.UpdateIncident(logger, ctx, update))
+		store.AssertExpectations(t)
+	})
+
+	t.Run("report of a complete incident", func(t *testing.T) {
+		service, store := setup()
+		store.On("GetByID", mock.Anything, 7).Return(complete(), nil).Once()
+
+		content, err := service.GetIncidentReportPDF(logger, ctx, 7)
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(content, []byte("%PDF-")))
+	})
+
+	t.Run("report lists the missing fields", func(t *testing.T) {
+		service, store := setup()
+		incident := complete()
+		incident.FirstAid = nil
+		incident.ParentInformedAt = nil
+		incident.MedicalTreatment = true
+		store.On("GetByID", mock.Anything, 7).Return(incident, nil).Once()
+
+		_, err := service.GetIncidentReportPDF(logger, ctx, 7)
+		var validationError *services.ValidationError
+		require.True(t, errors.As(err, &validationError))
+		fields := []string{}
+		for _, violation := range validationError.Violations {
+			fields = append(fields, violation.Field)
+		}
+		assert.ElementsMatch(t, []string{"first_aid", "physician", "parent_informed_at"}, fields)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		service, store := setup()
+		store.On("Delete", mock.Anything, 7).Return(nil).Once()
+		store.On("Delete", mock.Anything, 8).Return(data.ErrNotFound).Once()
+
+		assert.NoError(t, service.DeleteIncident(logger, ctx, 7))
+		assert.ErrorIs(t, service.DeleteIncident(logger, ctx, 8), services.ErrNotFound)
+	})
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InvitationService defines the interface for invitation-only account creation.
+type InvitationService interface {
+	// CreateInvitation creates a single-use invitation for an account with the role. The token is only returned here.
+	CreateInvitation(logger *logrus.Entry, ctx context.Context, role string, createdByUserID int) (*models.InvitationLink, error)
+	GetAllInvitations(logger *logrus.Entry, ctx context.Context) ([]models.Invitation, error)
+	DeleteInvitation(logger *logrus.Entry, ctx context.Context, id int) error
+	// AcceptInvitation creates the account of an invitation and uses the invitation up.
+	AcceptInvitation(logger *logrus.Entry, ctx context.Context, token string, username string, password string) (*models.User, error)
+}
+
+// InvitationServiceImpl implements InvitationService.
+type InvitationServiceImpl struct {
+	invitationStore data.InvitationStore
+	userService     UserService
+	config          *config.Config
+	clock           clock.Clock
+}
+
+// NewInvitationService creates a new InvitationServiceImpl.
+func NewInvitationService(invitationStore data.InvitationStore, userService UserService, cfg *config.Config, clock clock.Clock) *InvitationServiceImpl {
+	return &InvitationServiceImpl{
+		invitationStore: invitationStore,
+		userService:     userService,
+		config:          cfg,
+		clock:           clock,
+	}
+}
+
+// linkTokenBytes is the number of random bytes of the token of an invitation or a share link.
+const linkTokenBytes = 32
+
+// newLinkToken returns a random token for an invitation or a share link.
+func newLinkToken() (string, error) {
+	tokenBytes := make([]byte, linkTokenBytes)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// hashLinkToken returns the hash the token of an invitation or a share link is stored as.
+func hashLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateInvitation creates a new invitation.
+func (service *InvitationServiceImpl) CreateInvitation(logger *logrus.Entry, ctx context.Context, role string, createdByUserID int) (*models.InvitationLink, error) {
+	token, err := newLinkToken()
+	if err != nil {
+		logger.WithError(err).Error("Error generating invitation token")
+		return nil, ErrInternal
+	}
+
+	invitation := &models.Invitation{
+		Role:            role,
+		CreatedByUserID: &createdByUserID,
+		ExpiresAt:       service.clock.Now().Add(service.config.Registration.InvitationValidity),
+		TokenHash:       hashLinkToken(token),
+	}
+	if err := models.ValidateInvitation(*invitation); err != nil {
+		logger.WithError(err).Warn("Invalid input for CreateInvitation")
+		return nil, invalidInput(err)
+	}
+
+	id, err := service.invitationStore.Create(ctx, invitation)
+	if err != nil {
+		logger.WithError(err).Error("Error creating invitation")
+		return nil, ErrInternal
+	}
+	created, err := service.invitationStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("invitation_id", id).Error("Error fetching created invitation")
+		return nil, ErrInternal
+	}
+
+	link := &models.InvitationLink{Invitation: created, Token: token}
+	if service.config.Registration.SignupURL != "" {
+		signupURL, err := url.Parse(service.config.Registration.SignupURL)
+		if err != nil {
+			logger.WithError(err).Error("Invalid signup URL configured for invitations")
+			return nil, ErrInternal
+		}
+		query := signupURL.Query()
+		query.Set("token", token)
+		signupURL.RawQuery = query.Encode()
+		link.SignupURL = signupURL.String()
+	}
+	logger.WithFields(logrus.Fields{
+		"invitation_id":      id,
+		"role":               role,
+		"created_by_user_id": createdByUserID,
+	}).Info("Invitation created successfully")
+	return link, nil
+}
+
+// GetAllInvitations fetches all invitations, used and expired ones included.
+func (service *InvitationServiceImpl) GetAllInvitations(logger *logrus.Entry, ctx context.Context) ([]models.Invitation, error) {
+	invitations, err := service.invitationStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching invitations")
+		return nil, ErrInternal
+	}
+	return invitations, nil
+}
+
+// DeleteInvitation revokes an invitation.
+func (service *InvitationServiceImpl) DeleteInvitation(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.invitationStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrInvitationNotFound
+		}
+		logger.WithError(err).WithField("invitation_id", id).Error("Error deleting invitation")
+		return ErrInternal
+	}
+	logger.WithField("invitation_id", id).Info("Invitation deleted successfully")
+	return nil
+}
+
+// AcceptInvitation creates an account with the role of the invitation.
+// The invitation is claimed before the account is created, so that a token cannot be used twice concurrently,
+// and released again if the account cannot be created, e.g. because the username is taken.
+func (service *InvitationServiceImpl) AcceptInvitation(logger *logrus.Entry, ctx context.Context, token string, username string, password string) (*models.User, error) {
+	invitation, err := service.invitationStore.GetByTokenHash(ctx, hashLinkToken(token))
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.Warn("Registration attempt with an unknown invitation token")
+			return nil, ErrInvitationNotFound
+		}
+		logger.WithError(err).Error("Error fetching invitation by token")
+		return nil, ErrInternal
+	}
+	logger = logger.WithField("invitation_id", invitation.ID)
+	if invitation.UsedAt != nil {
+		logger.Warn("Registration attempt with a used invitation")
+		return nil, ErrInvitationAlreadyUsed
+	}
+	now := service.clock.Now()
+	if now.After(invitation.ExpiresAt) {
+		logger.Warn("Registration attempt with an expired invitation")
+		return nil, ErrInvitationExpired
+	}
+
+	if err := service.invitationStore.Claim(ctx, invitation.ID, now); err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			logger.Warn("Invitation was used concurrently")
+			return nil, ErrInvitationAlreadyUsed
+		}
+		logger.WithError(err).Error("Error claiming invitation")
+		return nil, ErrInternal
+	}
+
+	user, err := service.userService.CreateUser(logger, ctx, username, password, invitation.Role)
+	if err != nil {
+		if releaseErr := service.invitationStore.Release(ctx, invitation.ID); releaseErr != nil {
+			logger.WithError(releaseErr).Error("Error releasing invitation after failed registration")
+		}
+		return nil, err
+	}
+
+	if err := service.invitationStore.SetUsedBy(ctx, invitation.ID, user.ID); err != nil {
+		// The account exists and the invitation is used up, only the link between them is missing.
+		logger.WithError(err).WithField("user_id", user.ID).Error("Error recording the user created from invitation")
+	}
+	logger.WithField("user_id", user.ID).Info("Account created from invitation")
+	return user, nil
+}
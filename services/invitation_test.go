@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func invitationTestConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Registration.InvitationValidity = 48 * time.Hour
+	cfg.Registration.SignupURL = "https://kita.example/signup"
+	return cfg
+}
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCreateInvitation(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockInvitationStore := new(datamocks.MockInvitationStore)
+		cfg := invitationTestConfig()
+		service := services.NewInvitationService(mockInvitationStore, services.NewUserService(new(datamocks.MockUserStore), new(datamocks.MockAuthSessionStore), cfg, clock.System{}), cfg, clock.System{})
+
+		var stored *models.Invitation
+		mockInvitationStore.On("Create", mock.Anything, mock.AnythingOfType("*models.Invitation")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.Invitation)
+		}).Return(3, nil).Once()
+		mockInvitationStore.On("GetByID", mock.Anything, 3).Return(&models.Invitation{ID: 3, Role: "teacher"}, nil).Once()
+
+		link, err := service.CreateInvitation(logger, ctx, "teacher", 1)
+		require.NoError(t, err)
+		assert.Equal(t, 3, link.Invitation.ID)
+		assert.NotEmpty(t, link.Token)
+		assert.Equal(t, tokenHash(link.Token), stored.TokenHash, "only the hash of the token is stored")
+		assert.WithinDuration(t, time.Now().Add(48*time.Hour), stored.ExpiresAt, time.Minute)
+		signupURL, err := url.Parse(link.SignupURL)
+		require.NoError(t, err)
+		assert.Equal(t, "kita.example", signupURL.Host)
+		assert.Equal(t, link.Token, signupURL.Query().Get("token"))
+		mockInvitationStore.AssertExpectations(t)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		cfg := invitationTestConfig()
+		service := services.NewInvitationService(new(datamocks.MockInvitationStore), services.NewUserService(new(datamocks.MockUserStore), new(datamocks.MockAuthSessionStore), cfg, clock.System{}), cfg, clock.System{})
+
+		_, err := service.CreateInvitation(logger, ctx, "parent", 1)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	token := "invitation-token"
+
+	setup := func() (*services.InvitationServiceImpl, *datamocks.MockInvitationStore, *datamocks.MockUserStore) {
+		mockInvitationStore := new(datamocks.MockInvitationStore)
+		mockUserStore := new(datamocks.MockUserStore)
+		cfg := invitationTestConfig() // Open registration is disabled, invitations must work regardless
+		return services.NewInvitationService(mockInvitationStore, services.NewUserService(mockUserStore, new(datamocks.MockAuthSessionStore), cfg, clock.System{}), cfg, clock.System{}), mockInvitationStore, mockUserStore
+	}
+
+	t.Run("creates the account with the preset role", func(t *testing.T) {
+		service, mockInvitationStore, mockUserStore := setup()
+		mockInvitationStore.On("GetByTokenHash", mock.Anything, tokenHash(token)).Return(&models.Invitation{ID: 3, Role: "admin", ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+		mockInvitationStore.On("Claim", mock.Anything, 3, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		mockUserStore.On("GetUserByUsername", mock.Anything, "newadmin").Return(nil, data.ErrNotFound).Once()
+		mockUserStore.On("Create", mock.Anything, mock.MatchedBy(func(user *models.User) bool { return user.Role == "admin" })).Return(7, nil).Once()
+		mockUserStore.On("GetByID", mock.Anything, 7).Return(&models.User{ID: 7, Username: "newadmin", Role: "admin"}, nil).Once()
+		mockInvitationStore.On("SetUsedBy", mock.Anything, 3, 7).Return(nil).Once()
+
+		user, err := service.AcceptInvitation(logger, ctx, token, "newadmin", "password123")
+		require.NoError(t, err)
+		assert.Equal(t, 7, user.ID)
+		assert.Equal(t, "admin", user.Role)
+		mockInvitationStore.AssertExpectations(t)
+		mockUserStore.AssertExpectations(t)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		service, mockInvitationStore, _ := setup()
+		mockInvitationStore.On("GetByTokenHash", mock.Anything, tokenHash(token)).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.AcceptInvitation(logger, ctx, token, "newuser", "password123")
+		assert.Equal(t, services.ErrInvitationNotFound, err)
+	})
+
+	t.Run("used invitation", func(t *testing.T) {
+		service, mockInvitationStore, _ := setup()
+		usedAt := time.Now().Add(-time.Hour)
+		mockInvitationStore.On("GetByTokenHash", mock.Anything, tokenHash(token)).Return(&models.Invitation{ID: 3, Role: "teacher", ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}, nil).Once()
+
+		_, err := service.AcceptInvitation(logger, ctx, token, "newuser", "password123")
+		assert.Equal(t, services.ErrInvitationAlreadyUsed, err)
+		mockInvitationStore.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("expired invitation", func(t *testing.T) {
+		service, mockInvitationStore, _ := setup()
+		mockInvitationStore.On("GetByTokenHash", mock.Anything, tokenHash(token)).Return(&models.Invitation{ID: 3, Role: "teacher", ExpiresAt: time.Now().Add(-time.Minute)}, nil).Once()
+
+		_, err := service.AcceptInvitation(logger, ctx, token, "newuser", "password123")
+		assert.Equal(t, services.ErrInvitationExpired, err)
+	})
+
+	t.Run("invitation expires at the end of its validity", func(t *testing.T) {
+		expiresAt := time.Date(2025, time.March, 7, 8, 0, 0, 0, time.UTC)
+		frozen := clock.NewFrozen(expiresAt)
+		mockInvitationStore := new(datamocks.MockInvitationStore)
+		mockUserStore := new(datamocks.MockUserStore)
+		cfg := invitationTestConfig()
+		service := services.NewInvitationService(mockInvitationStore, services.NewUserService(mockUserStore, new(datamocks.MockAuthSessionStore), cfg, frozen), cfg, frozen)
+		mockInvitationStore.On("GetByTokenHash", mock.Anything, tokenHash(token)).Return(&models.Invitation{ID: 3, Role: "teacher", ExpiresAt: expiresAt}, nil)
+		mockInvitationStore.On("Claim", mock.Anything, 3, expiresAt).Return(data.ErrConflict).Once()
+
+		_, err := service.AcceptInvitation(logger, ctx, token, "newuser", "password123")
+		assert.Equal(t, services.ErrInvitationAlreadyUsed, err, "the invitation is still valid at its expiry time")
+
+		frozen.Advance(time.Second)
+		_, err = service.AcceptInvitation(logger, ctx, token, "newuser", "password123")
+		assert.Equal(t, services.ErrInvitationExpired, err)
+		mockInvitationStore.AssertExpectations(t)
+	})
+
+	t.Run("invitation used concurrently", func(t *testing.T) {
+		service, mockInvitationStore, mockUserStore := setup()
+		mockInvitationStore.On("GetByTokenHash", mock.Anything, tokenHash(token)).Return(&models.Invitation{ID: 3, Role: "teacher", ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+		mockInvitationStore.On("Claim", mock.Anything, 3, mock.AnythingOfType("time.Time")).Return(data.ErrConflict).Once()
+
+		_, err := service.AcceptInvitation(logger, ctx, token, "newuser", "password123")
+		assert.Equal(t, services.ErrInvitationAlreadyUsed, err)
+		mockUserStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("taken username releases the invitation", func(t *testing.T) {
+		service, mockInvitationStore, mockUserStore := setup()
+		mockInvitationStore.On("GetByTokenHash", mock.Anything, tokenHash(token)).Return(&models.Invitation{ID: 3, Role: "teacher", ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+		mockInvitationStore.On("Claim", mock.Anything, 3, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		mockUserStore.On("GetUserByUsername", mock.Anything, "existing").Return(&models.User{ID: 1}, nil).Once()
+		mockInvitationStore.On("Release", mock.Anything, 3).Return(nil).Once()
+
+		_, err := service.AcceptInvitation(logger, ctx, token, "existing", "password123")
+		assert.Equal(t, services.ErrAlreadyExists, err)
+		mockInvitationStore.AssertExpectations(t)
+	})
+}
@@ -1,16 +1,28 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"kitadoc-backend/data"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/models"
 )
 
+// MaxDocumentLogoSize is the largest logo that can be uploaded, in bytes.
+const MaxDocumentLogoSize = 1 << 20
+
+// allowedImageContentTypes are the image formats Word documents can embed without conversion.
+var allowedImageContentTypes = map[string]bool{"image/png": true, "image/jpeg": true}
+
 // KitaMasterdataService defines the interface for Kita master data-related business logic operations.
 type KitaMasterdataService interface {
-	GetKitaMasterdata() (*models.KitaMasterdata, error)
-	UpdateKitaMasterdata(masterdata *models.KitaMasterdata) error
+	GetKitaMasterdata(ctx context.Context) (*models.KitaMasterdata, error)
+	UpdateKitaMasterdata(ctx context.Context, masterdata *models.KitaMasterdata) error
+	UpdateDocumentTheme(ctx context.Context, theme *models.DocumentTheme) error
+	UpdateTerminology(ctx context.Context, terminology models.Terminology) error
+	GetDocumentLogo(ctx context.Context) (*models.DocumentLogo, error)
+	UpdateDocumentLogo(ctx context.Context, content []byte) error
+	DeleteDocumentLogo(ctx context.Context) error
 }
 
 // KitaMasterdataServiceImpl implements KitaMasterdataService.
@@ -26,8 +38,8 @@ func NewKitaMasterdataService(kitaMasterdataStore data.KitaMasterdataStore) *Kit
 }
 
 // GetKitaMasterdata fetches the Kita master data.
-func (s *KitaMasterdataServiceImpl) GetKitaMasterdata() (*models.KitaMasterdata, error) {
-	masterdata, err := s.kitaMasterdataStore.Get()
+func (s *KitaMasterdataServiceImpl) GetKitaMasterdata(ctx context.Context) (*models.KitaMasterdata, error) {
+	masterdata, err := s.kitaMasterdataStore.Get(ctx)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.GetGlobalLogger().Info("Kita master data not found")
@@ -40,13 +52,13 @@ func (s *KitaMasterdataServiceImpl) GetKitaMasterdata() (*models.KitaMasterdata,
 }
 
 // UpdateKitaMasterdata updates the Kita master data.
-func (s *KitaMasterdataServiceImpl) UpdateKitaMasterdata(masterdata *models.KitaMasterdata) error {
+func (s *KitaMasterdataServiceImpl) UpdateKitaMasterdata(ctx context.Context, masterdata *models.KitaMasterdata) error {
 	if err := models.ValidateKitaMasterdata(*masterdata); err != nil {
 		logger.GetGlobalLogger().Errorf("Invalid Kita master data input: %v", err)
-		return ErrInvalidInput
+		return invalidInput(err)
 	}
 
-	err := s.kitaMasterdataStore.Update(masterdata)
+	err := s.kitaMasterdataStore.Update(ctx, masterdata)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error updating Kita master data: %v", err)
 		return ErrInternal
@@ -54,3 +66,96 @@ func (s *KitaMasterdataServiceImpl) UpdateKitaMasterdata(masterdata *models.Kita
 	logger.GetGlobalLogger().Info("Kita master data updated successfully")
 	return nil
 }
+
+// UpdateDocumentTheme updates the fonts and colors used for generated documents.
+func (s *KitaMasterdataServiceImpl) UpdateDocumentTheme(ctx context.Context, theme *models.DocumentTheme) error {
+	if err := models.ValidateDocumentTheme(*theme); err != nil {
+		logger.GetGlobalLogger().Errorf("Invalid document theme input: %v", err)
+		return invalidInput(err)
+	}
+
+	err := s.kitaMasterdataStore.UpdateTheme(ctx, theme)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.GetGlobalLogger().Info("Kita master data not found for document theme")
+			return ErrNotFound
+		}
+		logger.GetGlobalLogger().Errorf("Error updating document theme: %v", err)
+		return ErrInternal
+	}
+	logger.GetGlobalLogger().Info("Document theme updated successfully")
+	return nil
+}
+
+// UpdateTerminology replaces the terms generated documents use instead of the defaults.
+func (s *KitaMasterdataServiceImpl) UpdateTerminology(ctx context.Context, terminology models.Terminology) error {
+	if err := models.ValidateTerminology(terminology); err != nil {
+		logger.GetGlobalLogger().Errorf("Invalid terminology input: %v", err)
+		return invalidInput(err)
+	}
+
+	err := s.kitaMasterdataStore.UpdateTerminology(ctx, terminology)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.GetGlobalLogger().Info("Kita master data not found for terminology")
+			return ErrNotFound
+		}
+		logger.GetGlobalLogger().Errorf("Error updating terminology: %v", err)
+		return ErrInternal
+	}
+	logger.GetGlobalLogger().Info("Terminology updated successfully")
+	return nil
+}
+
+// GetDocumentLogo fetches the logo printed on generated documents.
+func (s *KitaMasterdataServiceImpl) GetDocumentLogo(ctx context.Context) (*models.DocumentLogo, error) {
+	logo, err := s.kitaMasterdataStore.GetLogo(ctx)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.GetGlobalLogger().Errorf("Error fetching document logo: %v", err)
+		return nil, ErrInternal
+	}
+	return logo, nil
+}
+
+// UpdateDocumentLogo replaces the logo printed on generated documents.
+// The content type is detected from the content, only PNG and JPEG images are accepted. The logo is normalized
+// like gallery photos.
+func (s *KitaMasterdataServiceImpl) UpdateDocumentLogo(ctx context.Context, content []byte) error {
+	if len(content) == 0 || len(content) > MaxDocumentLogoSize {
+		logger.GetGlobalLogger().Errorf("Invalid document logo size: %d bytes", len(content))
+		return ErrInvalidInput
+	}
+	content, contentType, err := prepareImage(logger.GetGlobalLogger().GetLogrusEntry(), content)
+	if err != nil {
+		return err
+	}
+
+	err = s.kitaMasterdataStore.UpdateLogo(ctx, &models.DocumentLogo{Content: content, ContentType: contentType})
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.GetGlobalLogger().Info("Kita master data not found for document logo")
+			return ErrNotFound
+		}
+		logger.GetGlobalLogger().Errorf("Error updating document logo: %v", err)
+		return ErrInternal
+	}
+	logger.GetGlobalLogger().Info("Document logo updated successfully")
+	return nil
+}
+
+// DeleteDocumentLogo removes the logo from generated documents.
+func (s *KitaMasterdataServiceImpl) DeleteDocumentLogo(ctx context.Context) error {
+	err := s.kitaMasterdataStore.UpdateLogo(ctx, nil)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.GetGlobalLogger().Errorf("Error deleting document logo: %v", err)
+		return ErrInternal
+	}
+	logger.GetGlobalLogger().Info("Document logo deleted successfully")
+	return nil
+}
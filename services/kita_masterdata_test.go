@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/logger"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// pngLogo encodes a blank PNG image of the given size.
+func pngLogo(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("failed to encode logo: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestUpdateDocumentTheme(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{FullTimestamp: true})
+	font := "Open Sans"
+
+	t.Run("success", func(t *testing.T) {
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		service := services.NewKitaMasterdataService(mockKitaMasterdataStore)
+		color := "#1a5276"
+		theme := &models.DocumentTheme{FontFamily: &font, AccentColor: &color}
+		mockKitaMasterdataStore.On("UpdateTheme", mock.Anything, theme).Return(nil).Once()
+
+		assert.NoError(t, service.UpdateDocumentTheme(t.Context(), theme))
+		mockKitaMasterdataStore.AssertExpectations(t)
+	})
+
+	t.Run("short color", func(t *testing.T) {
+		service := services.NewKitaMasterdataService(new(datamocks.MockKitaMasterdataStore))
+		color := "#fff"
+
+		err := service.UpdateDocumentTheme(t.Context(), &models.DocumentTheme{AccentColor: &color})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+
+	t.Run("no master data", func(t *testing.T) {
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		service := services.NewKitaMasterdataService(mockKitaMasterdataStore)
+		mockKitaMasterdataStore.On("UpdateTheme", mock.Anything, mock.Anything).Return(data.ErrNotFound).Once()
+
+		err := service.UpdateDocumentTheme(t.Context(), &models.DocumentTheme{FontFamily: &font})
+		assert.Equal(t, services.ErrNotFound, err)
+	})
+}
+
+func TestUpdateTerminology(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{FullTimestamp: true})
+
+	t.Run("success", func(t *testing.T) {
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		service := services.NewKitaMasterdataService(mockKitaMasterdataStore)
+		terminology := models.Terminology{models.TermTeachers: "pädagogische Fachkräfte"}
+		mockKitaMasterdataStore.On("UpdateTerminology", mock.Anything, terminology).Return(nil).Once()
+
+		assert.NoError(t, service.UpdateTerminology(t.Context(), terminology))
+		mockKitaMasterdataStore.AssertExpectations(t)
+		assert.Equal(t, "pädagogische Fachkräfte", terminology.Term(models.TermTeachers))
+		assert.Equal(t, "Fachkraft", terminology.Term(models.TermTeacher))
+	})
+
+	t.Run("unknown term", func(t *testing.T) {
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		service := services.NewKitaMasterdataService(mockKitaMasterdataStore)
+
+		err := service.UpdateTerminology(t.Context(), models.Terminology{"parents": "Sorgeberechtigte"})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		err = service.UpdateTerminology(t.Context(), models.Terminology{models.TermTeacher: ""})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		mockKitaMasterdataStore.AssertNotCalled(t, "UpdateTerminology", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateDocumentLogo(t *testing.T) {
+	logger.InitGlobalLogger(logrus.DebugLevel, &logrus.TextFormatter{FullTimestamp: true})
+	t.Run("png", func(t *testing.T) {
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		service := services.NewKitaMasterdataService(mockKitaMasterdataStore)
+		content := pngLogo(t, 20, 10)
+		mockKitaMasterdataStore.On("UpdateLogo", mock.Anything, &models.DocumentLogo{Content: content, ContentType: "image/png"}).Return(nil).Once()
+
+		assert.NoError(t, service.UpdateDocumentLogo(t.Context(), content))
+		mockKitaMasterdataStore.AssertExpectations(t)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		mockKitaMasterdataStore := new(datamocks.MockKitaMasterdataStore)
+		service := services.NewKitaMasterdataService(mockKitaMasterdataStore)
+
+		err := service.UpdateDocumentLogo(t.Context(), []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+		assert.Equal(t, services.ErrInvalidInput, err)
+		mockKitaMasterdataStore.AssertNotCalled(t, "UpdateLogo", mock.Anything, mock.Anything)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		service := services.NewKitaMasterdataService(new(datamocks.MockKitaMasterdataStore))
+
+		err := service.UpdateDocumentLogo(t.Context(), make([]byte, services.MaxDocumentLogoSize+1))
+		assert.Equal(t, services.ErrInvalidInput, err)
+	})
+}
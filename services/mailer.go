@@ -0,0 +1,71 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SMTPMailer delivers email outbox messages through an SMTP server. The connection is upgraded with STARTTLS
+// when the server offers it.
+type SMTPMailer struct {
+	address string
+	auth    smtp.Auth
+	from    mail.Address
+}
+
+// NewSMTPMailer creates a new SMTPMailer. Without a username, mails are sent without authentication.
+func NewSMTPMailer(host string, port int, username string, password string, from string) (*SMTPMailer, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	mailer := &SMTPMailer{address: net.JoinHostPort(host, strconv.Itoa(port)), from: *sender}
+	if username != "" {
+		mailer.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return mailer, nil
+}
+
+// Deliver sends an email outbox message. Messages with an invalid payload or recipient are undeliverable.
+func (mailer *SMTPMailer) Deliver(logger *logrus.Entry, ctx context.Context, message models.OutboxMessage) error {
+	var payload models.EmailOutboxPayload
+	if err := json.Unmarshal(message.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: invalid payload: %v", ErrUndeliverable, err)
+	}
+	recipient, err := mail.ParseAddress(payload.To)
+	if err != nil {
+		return fmt.Errorf("%w: invalid recipient: %v", ErrUndeliverable, err)
+	}
+
+	if err := smtp.SendMail(mailer.address, mailer.auth, mailer.from.Address, []string{recipient.Address}, mailer.compose(recipient, payload, time.Now())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	logger.Debug("Email sent")
+	return nil
+}
+
+// compose formats a plain text mail in UTF-8.
+func (mailer *SMTPMailer) compose(recipient *mail.Address, payload models.EmailOutboxPayload, now time.Time) []byte {
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", mailer.from.String())
+	fmt.Fprintf(&message, "To: %s\r\n", recipient.String())
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", payload.Subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	message.Write(bytes.ReplaceAll([]byte(payload.Body), []byte("\n"), []byte("\r\n")))
+	return message.Bytes()
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/image/draw"
+)
+
+// Limits of the images normalized on upload.
+const (
+	// MaxImageDimension is the longest side of a stored photo in pixels, larger photos are scaled down.
+	MaxImageDimension = 2048
+	// maxDecodedPixels guards against images that are small as a file but huge once decoded.
+	maxDecodedPixels      = 50_000_000
+	normalizedJPEGQuality = 85
+)
+
+// errImageTooLarge is returned by normalizeImage for images with more than maxDecodedPixels.
+var errImageTooLarge = fmt.Errorf("image exceeds %d pixels", maxDecodedPixels)
+
+// isHEIC reports whether the content is a HEIC or HEIF image, as iPhones take them by default.
+func isHEIC(content []byte) bool {
+	if len(content) < 12 || string(content[4:8]) != "ftyp" {
+		return false
+	}
+	switch string(content[8:12]) {
+	case "heic", "heix", "hevc", "hevx", "heim", "heis", "mif1", "msf1":
+		return true
+	}
+	return false
+}
+
+// prepareImage checks that an unencrypted upload is a PNG or JPEG image and normalizes it, every image upload goes
+// through it. It returns the normalized image and its content type. HEIC images are rejected with ErrPhotoHEIC,
+// there is no decoder for them without cgo.
+func prepareImage(logger *logrus.Entry, content []byte) ([]byte, string, error) {
+	if isHEIC(content) {
+		logger.Warn("HEIC image uploaded")
+		return nil, "", ErrPhotoHEIC
+	}
+	contentType := http.DetectContentType(content)
+	if !allowedImageContentTypes[contentType] {
+		logger.WithField("content_type", contentType).Warn("Disallowed image type")
+		return nil, "", ErrInvalidInput
+	}
+	normalized, err := normalizeImage(content)
+	if err != nil {
+		logger.WithError(err).Warn("Invalid image")
+		return nil, "", ErrInvalidInput
+	}
+	return normalized, contentType, nil
+}
+
+// normalizeImage prepares an uploaded PNG or JPEG image for storage: it is turned upright according to its EXIF
+// orientation, scaled down to MaxImageDimension and encoded again in its format. Encoding again drops all
+// metadata, like the GPS position and the camera of a photo.
+func normalizeImage(content []byte) ([]byte, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > maxDecodedPixels {
+		return nil, errImageTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	if longest := max(width, height); longest > MaxImageDimension {
+		width = max(1, width*MaxImageDimension/longest)
+		height = max(1, height*MaxImageDimension/longest)
+	}
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	if width == src.Bounds().Dx() && height == src.Bounds().Dy() {
+		draw.Draw(scaled, scaled.Bounds(), src, src.Bounds().Min, draw.Src)
+	} else {
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), src, src.Bounds(), draw.Src, nil)
+	}
+	upright := scaled
+	if format == "jpeg" {
+		upright = orientImage(scaled, jpegOrientation(content))
+	}
+
+	var buf bytes.Buffer
+	if format == "png" {
+		err = png.Encode(&buf, upright)
+	} else {
+		err = jpeg.Encode(&buf, upright, &jpeg.Options{Quality: normalizedJPEGQuality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// orientImage applies an EXIF orientation from 2 to 8 to an image, turning it upright. Other orientations leave
+// the image as it is.
+func orientImage(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if orientation >= 5 {
+		// Orientations 5 to 8 swap the sides.
+		dst = image.NewRGBA(image.Rect(0, 0, height, width))
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirrored horizontally
+				dx, dy = width-1-x, y
+			case 3: // Rotated by 180°
+				dx, dy = width-1-x, height-1-y
+			case 4: // Mirrored vertically
+				dx, dy = x, height-1-y
+			case 5: // Mirrored along the top-left diagonal
+				dx, dy = y, x
+			case 6: // Turned by 90° clockwise
+				dx, dy = height-1-y, x
+			case 7: // Mirrored along the top-right diagonal
+				dx, dy = height-1-y, width-1-x
+			case 8: // Turned by 90° counterclockwise
+				dx, dy = y, width-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):dst.PixOffset(dx, dy)+4], src.Pix[src.PixOffset(x, y):src.PixOffset(x, y)+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG image, 1 (upright) if it has none.
+func jpegOrientation(content []byte) int {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != 0xD8 {
+		return 1
+	}
+	for offset := 2; offset+4 <= len(content); {
+		if content[offset] != 0xFF {
+			return 1
+		}
+		marker := content[offset+1]
+		if marker == 0xDA || marker == 0xD9 {
+			// The image data starts, metadata segments only come before.
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(content[offset+2:]))
+		if length < 2 || offset+2+length > len(content) {
+			return 1
+		}
+		segment := content[offset+4 : offset+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		offset += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag of the first image file directory of EXIF data in TIFF layout.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + 12*i
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
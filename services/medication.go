@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MedicationService defines the interface for the medication administration log of children.
+type MedicationService interface {
+	// RecordAdministration adds an administration to the log of a child. It stays unconfirmed until a second
+	// user confirms it.
+	RecordAdministration(logger *logrus.Entry, ctx context.Context, administration *models.MedicationAdministration, user *models.User) (*models.MedicationAdministration, error)
+	GetAdministrationByID(logger *logrus.Entry, ctx context.Context, id int) (*models.MedicationAdministration, error)
+	GetAdministrationsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.MedicationAdministration, error)
+	// ConfirmAdministration confirms an administration. The confirming user must be neither the user who recorded
+	// it nor the administering teacher.
+	ConfirmAdministration(logger *logrus.Entry, ctx context.Context, id int, user *models.User) (*models.MedicationAdministration, error)
+}
+
+// MedicationServiceImpl implements MedicationService.
+type MedicationServiceImpl struct {
+	medicationStore data.MedicationStore
+	childStore      data.ChildStore
+	teacherStore    data.TeacherStore
+	clock           clock.Clock
+}
+
+// NewMedicationService creates a new MedicationServiceImpl.
+func NewMedicationService(medicationStore data.MedicationStore, childStore data.ChildStore, teacherStore data.TeacherStore, clock clock.Clock) *MedicationServiceImpl {
+	return &MedicationServiceImpl{
+		medicationStore: medicationStore,
+		childStore:      childStore,
+		teacherStore:    teacherStore,
+		clock:           clock,
+	}
+}
+
+// RecordAdministration adds an administration to the log of a child.
+func (service *MedicationServiceImpl) RecordAdministration(logger *logrus.Entry, ctx context.Context, administration *models.MedicationAdministration, user *models.User) (*models.MedicationAdministration, error) {
+	if err := models.ValidateMedicationAdministration(*administration); err != nil {
+		logger.WithError(err).Warn("Invalid input for RecordAdministration")
+		return nil, invalidInput(err)
+	}
+	if administration.AdministeredAt.After(service.clock.Now()) {
+		logger.WithField("administered_at", administration.AdministeredAt).Warn("Medication administration in the future")
+		return nil, fmt.Errorf("%w: medication cannot be recorded before it is administered", ErrInvalidInput)
+	}
+	if _, err := service.childStore.GetByID(ctx, administration.ChildID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", administration.ChildID).Error("Error fetching child for medication administration")
+		return nil, ErrInternal
+	}
+	if _, err := service.getTeacher(logger, ctx, administration.AdministeredByTeacherID); err != nil {
+		return nil, err
+	}
+	administration.RecordedByUserID = &user.ID
+
+	id, err := service.medicationStore.Create(ctx, administration)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", administration.ChildID).Error("Error recording medication administration")
+		return nil, ErrInternal
+	}
+	created, err := service.medicationStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("administration_id", id).Error("Error fetching recorded medication administration")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"administration_id": id, "child_id": administration.ChildID}).Info("Medication administration recorded successfully")
+	return created, nil
+}
+
+// GetAdministrationByID fetches an administration by ID.
+func (service *MedicationServiceImpl) GetAdministrationByID(logger *logrus.Entry, ctx context.Context, id int) (*models.MedicationAdministration, error) {
+	administration, err := service.medicationStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("administration_id", id).Error("Error fetching medication administration")
+		return nil, ErrInternal
+	}
+	return administration, nil
+}
+
+// GetAdministrationsForChild fetches the log of a child, the latest administration first.
+func (service *MedicationServiceImpl) GetAdministrationsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.MedicationAdministration, error) {
+	if _, err := service.childStore.GetByID(ctx, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for medication log")
+		return nil, ErrInternal
+	}
+	administrations, err := service.medicationStore.GetForChild(ctx, childID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching medication log")
+		return nil, ErrInternal
+	}
+	return administrations, nil
+}
+
+// ConfirmAdministration confirms an administration by a second user.
+func (service *MedicationServiceImpl) ConfirmAdministration(logger *logrus.Entry, ctx context.Context, id int, user *models.User) (*models.MedicationAdministration, error) {
+	administration, err := service.GetAdministrationByID(logger, ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if administration.IsConfirmed() {
+		return nil, ErrMedicationConfirmed
+	}
+	teacher, err := service.getTeacher(logger, ctx, administration.AdministeredByTeacherID)
+	if err != nil {
+		return nil, err
+	}
+	if (administration.RecordedByUserID != nil && *administration.RecordedByUserID == user.ID) || teacher.Username == user.Username {
+		logger.WithFields(logrus.Fields{"administration_id": id, "user_id": user.ID}).Warn("Medication administration confirmed by the same user")
+		return nil, ErrMedicationSameConfirmer
+	}
+
+	if err := service.medicationStore.Confirm(ctx, id, user.ID, service.clock.Now()); err != nil {
+		switch {
+		case errors.Is(err, data.ErrNotFound):
+			return nil, ErrNotFound
+		case errors.Is(err, data.ErrConflict):
+			return nil, ErrMedicationConfirmed
+		}
+		logger.WithError(err).WithField("administration_id", id).Error("Error confirming medication administration")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"administration_id": id, "user_id": user.ID}).Info("Medication administration confirmed successfully")
+	return service.GetAdministrationByID(logger, ctx, id)
+}
+
+func (service *MedicationServiceImpl) getTeacher(logger *logrus.Entry, ctx context.Context, teacherID int) (*models.Teacher, error) {
+	teacher, err := service.teacherStore.GetByID(ctx, teacherID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrTeacherNotFound
+		}
+		logger.WithError(err).WithField("teacher_id", teacherID).Error("Error fetching teacher for medication administration")
+		return nil, ErrInternal
+	}
+	return teacher, nil
+}
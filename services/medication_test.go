@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMedicationService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 5, 15, 0, 0, 0, time.UTC)
+	maria := &models.User{ID: 2, Username: "maria.schmidt", Role: string(data.RoleTeacher)}
+	thomas := &models.User{ID: 3, Username: "thomas.weber", Role: string(data.RoleTeacher)}
+	lena := &models.User{ID: 4, Username: "lena.becker", Role: string(data.RoleTeacher)}
+	teacher := &models.Teacher{ID: 5, FirstName: "Thomas", LastName: "Weber", Username: thomas.Username}
+	pending := func() *models.MedicationAdministration {
+		return &models.MedicationAdministration{ID: 7, ChildID: 1, Medication: "Ibuprofen Saft", Dose: "2,5 ml",
+			InstructionReference: "Vollmacht vom 01.03.2025", AdministeredAt: now.Add(-time.Hour), AdministeredByTeacherID: teacher.ID, RecordedByUserID: &maria.ID}
+	}
+
+	setup := func() (*services.MedicationServiceImpl, *datamocks.MockMedicationStore) {
+		medicationStore := new(datamocks.MockMedicationStore)
+		childStore := new(datamocks.MockChildStore)
+		teacherStore := new(datamocks.MockTeacherStore)
+		childStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Maybe()
+		childStore.On("GetByID", mock.Anything, 99).Return(nil, data.ErrNotFound).Maybe()
+		teacherStore.On("GetByID", mock.Anything, teacher.ID).Return(teacher, nil).Maybe()
+		teacherStore.On("GetByID", mock.Anything, 98).Return(nil, data.ErrNotFound).Maybe()
+		return services.NewMedicationService(medicationStore, childStore, teacherStore, clock.NewFrozen(now)), medicationStore
+	}
+
+	t.Run("record", func(t *testing.T) {
+		service, store := setup()
+		store.On("Create", mock.Anything, mock.MatchedBy(func(administration *models.MedicationAdministration) bool {
+			return *administration.RecordedByUserID == maria.ID
+		})).Return(7, nil).Once()
+		store.On("GetByID", mock.Anything, 7).Return(pending(), nil).Once()
+
+		administration := pending()
+		administration.ID, administration.RecordedByUserID = 0, nil
+		created, err := service.RecordAdministration(logger, ctx, administration, maria)
+		require.NoError(t, err)
+		assert.False(t, created.IsConfirmed())
+		store.AssertExpectations(t)
+	})
+
+	t.Run("record rejects invalid administrations", func(t *testing.T) {
+		service, store := setup()
+		tests := []struct {
+			name   string
+			change func(*models.MedicationAdministration)
+			want   error
+		}{
+			{"missing dose", func(a *models.MedicationAdministration) { a.Dose = "" }, services.ErrInvalidInput},
+			{"missing instruction", func(a *models.MedicationAdministration) { a.InstructionReference = "" }, services.ErrInvalidInput},
+			{"in the future", func(a *models.MedicationAdministration) { a.AdministeredAt = now.Add(time.Hour) }, services.ErrInvalidInput},
+			{"unknown child", func(a *models.MedicationAdministration) { a.ChildID = 99 }, services.ErrChildNotFound},
+			{"unknown teacher", func(a *models.MedicationAdministration) { a.AdministeredByTeacherID = 98 }, services.ErrTeacherNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				administration := pending()
+				tt.change(administration)
+				_, err := service.RecordAdministration(logger, ctx, administration, maria)
+				assert.ErrorIs(t, err, tt.want)
+			})
+		}
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("confirm by a second user", func(t *testing.T) {
+		service, store := setup()
+		confirmed := pending()
+		confirmedAt := now
+		confirmed.ConfirmedByUserID, confirmed.ConfirmedAt = &lena.ID, &confirmedAt
+		store.On("GetByID", mock.Anything, 7).Return(pending(), nil).Once()
+		store.On("Confirm", mock.Anything, 7, lena.ID, now).Return(nil).Once()
+		store.On("GetByID", mock.Anything, 7).Return(confirmed, nil).Once()
+
+		administration, err := service.ConfirmAdministration(logger, ctx, 7, lena)
+		require.NoError(t, err)
+		assert.True(t, administration.IsConfirmed())
+		store.AssertExpectations(t)
+	})
+
+	t.Run("confirm requires a second user", func(t *testing.T) {
+		service, store := setup()
+		store.On("GetByID", mock.Anything, 7).Return(pending(), nil)
+
+		_, err := service.ConfirmAdministration(logger, ctx, 7, maria)
+		assert.ErrorIs(t, err, services.ErrMedicationSameConfirmer, "the recording user")
+		_, err = service.ConfirmAdministration(logger, ctx, 7, thomas)
+		assert.ErrorIs(t, err, services.ErrMedicationSameConfirmer, "the administering teacher")
+		store.AssertNotCalled(t, "Confirm", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("confirm only once", func(t *testing.T) {
+		service, store := setup()
+		confirmed := pending()
+		confirmed.ConfirmedAt = &now
+		store.On("GetByID", mock.Anything, 7).Return(confirmed, nil).Once()
+		store.On("GetByID", mock.Anything, 8).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.ConfirmAdministration(logger, ctx, 7, lena)
+		assert.ErrorIs(t, err, services.ErrMedicationConfirmed)
+		_, err = service.ConfirmAdministration(logger, ctx, 8, lena)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+}
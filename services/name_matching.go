@@ -0,0 +1,102 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"kitadoc-backend/models"
+)
+
+// nameTitles are left out when comparing names, paper records often refer to teachers as "Frau Müller".
+var nameTitles = []string{"frau", "herr", "fr", "hr", "dr"}
+
+// umlautReplacer spells out the German special characters, so that "Müller" and "Mueller" compare equal.
+var umlautReplacer = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss", "é", "e", "è", "e", "á", "a", "à", "a", "ç", "c")
+
+// nameTokens normalizes a name into its sorted lower case parts, so that "Musterkind, Anna" matches "Anna Musterkind".
+func nameTokens(name string) []string {
+	name = umlautReplacer.Replace(strings.ToLower(name))
+	tokens := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	})
+	tokens = slices.DeleteFunc(tokens, func(token string) bool {
+		return slices.Contains(nameTitles, strings.TrimSuffix(token, "."))
+	})
+	slices.Sort(tokens)
+	return tokens
+}
+
+// nameCandidate is an existing record a name can be matched to. Names lists the spellings to compare with,
+// e.g. the full name and the last name alone.
+type nameCandidate struct {
+	ID    int
+	Names []string
+}
+
+// matchName returns the IDs of the candidates matching the name and how they were matched. Exact matches of the
+// normalized name win; otherwise the candidates with the fewest typos are returned, allowing one typo per four letters.
+func matchName(name string, candidates []nameCandidate) ([]int, string) {
+	key := strings.Join(nameTokens(name), " ")
+	if key == "" {
+		return nil, models.MatchNone
+	}
+
+	exact := []int{}
+	bestDistance := -1
+	fuzzy := []int{}
+	for _, candidate := range candidates {
+		candidateDistance := -1
+		for _, candidateName := range candidate.Names {
+			candidateKey := strings.Join(nameTokens(candidateName), " ")
+			if candidateKey == "" {
+				continue
+			}
+			distance := levenshtein(key, candidateKey)
+			if candidateDistance == -1 || distance < candidateDistance {
+				candidateDistance = distance
+			}
+		}
+		switch {
+		case candidateDistance == 0:
+			exact = append(exact, candidate.ID)
+		case candidateDistance > 0 && candidateDistance <= len([]rune(key))/4:
+			if bestDistance == -1 || candidateDistance < bestDistance {
+				bestDistance = candidateDistance
+				fuzzy = fuzzy[:0]
+			}
+			if candidateDistance == bestDistance {
+				fuzzy = append(fuzzy, candidate.ID)
+			}
+		}
+	}
+	if len(exact) > 0 {
+		return exact, models.MatchExact
+	}
+	if len(fuzzy) > 0 {
+		return fuzzy, models.MatchFuzzy
+	}
+	return nil, models.MatchNone
+}
+
+// levenshtein returns the number of single character edits needed to turn a into b.
+func levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
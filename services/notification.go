@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pushDeliveryTimeout bounds the time spent delivering one notification to all devices of a user.
+const pushDeliveryTimeout = 30 * time.Second
+
+// NotificationService defines the interface for device registration and push notification operations.
+type NotificationService interface {
+	RegisterDevice(logger *logrus.Entry, ctx context.Context, device *models.Device) (*models.Device, error)
+	GetDevicesForUser(logger *logrus.Entry, ctx context.Context, userID int) ([]models.Device, error)
+	UnregisterDevice(logger *logrus.Entry, ctx context.Context, userID int, deviceID int) error
+	GetPreferences(logger *logrus.Entry, ctx context.Context, userID int) (*models.NotificationPreferences, error)
+	UpdatePreferences(logger *logrus.Entry, ctx context.Context, preferences *models.NotificationPreferences) error
+	NotifyUser(logger *logrus.Entry, ctx context.Context, userID int, notification models.Notification)
+	NotifyTeacher(logger *logrus.Entry, ctx context.Context, teacherID int, notification models.Notification)
+	SendReminder(logger *logrus.Entry, ctx context.Context, actingUserID int, teacherID int, message string)
+	TeacherMessage(teacherID int, notification models.Notification) (models.OutboxMessage, error)
+}
+
+// NotificationServiceImpl implements NotificationService.
+type NotificationServiceImpl struct {
+	deviceStore     data.DeviceStore
+	preferenceStore data.NotificationPreferenceStore
+	teacherStore    data.TeacherStore
+	userStore       data.UserStore
+	auditLogService AuditLogService        // Optional, nil disables recording reminders
+	gateways        map[string]PushGateway // Keyed by device platform
+	clock           clock.Clock
+}
+
+// NewNotificationService creates a new NotificationServiceImpl.
+// Devices of platforms without a gateway are still registered but never notified.
+func NewNotificationService(
+	deviceStore data.DeviceStore,
+	preferenceStore data.NotificationPreferenceStore,
+	teacherStore data.TeacherStore,
+	userStore data.UserStore,
+	auditLogService AuditLogService,
+	gateways map[string]PushGateway,
+	clock clock.Clock,
+) *NotificationServiceImpl {
+	return &NotificationServiceImpl{
+		deviceStore:     deviceStore,
+		preferenceStore: preferenceStore,
+		teacherStore:    teacherStore,
+		userStore:       userStore,
+		auditLogService: auditLogService,
+		gateways:        gateways,
+		clock:           clock,
+	}
+}
+
+// RegisterDevice registers a push token for a user.
+func (service *NotificationServiceImpl) RegisterDevice(logger *logrus.Entry, ctx context.Context, device *models.Device) (*models.Device, error) {
+	if err := models.ValidateDevice(*device); err != nil {
+		logger.WithError(err).Warn("Invalid input for RegisterDevice")
+		return nil, invalidInput(err)
+	}
+
+	id, err := service.deviceStore.Upsert(ctx, device)
+	if err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			logger.WithField("user_id", device.UserID).Warn("Device token is registered to another user")
+			return nil, ErrDeviceTokenTaken
+		}
+		logger.WithError(err).WithField("user_id", device.UserID).Error("Error registering device")
+		return nil, ErrInternal
+	}
+	registered, err := service.deviceStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("device_id", id).Error("Error fetching registered device")
+		return nil, ErrInternal
+	}
+	logger.WithField("device_id", id).Info("Device registered successfully")
+	return registered, nil
+}
+
+// GetDevicesForUser fetches all devices registered by a user.
+func (service *NotificationServiceImpl) GetDevicesForUser(logger *logrus.Entry, ctx context.Context, userID int) ([]models.Device, error) {
+	devices, err := service.deviceStore.GetAllForUser(ctx, userID)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", userID).Error("Error fetching devices for user")
+		return nil, ErrInternal
+	}
+	return devices, nil
+}
+
+// UnregisterDevice removes a device. Users can only remove their own devices.
+func (service *NotificationServiceImpl) UnregisterDevice(logger *logrus.Entry, ctx context.Context, userID int, deviceID int) error {
+	device, err := service.deviceStore.GetByID(ctx, deviceID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("device_id", deviceID).Warn("Device not found for unregistration")
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("device_id", deviceID).Error("Error fetching device for unregistration")
+		return ErrInternal
+	}
+	if device.UserID != userID {
+		// Do not reveal that the device exists.
+		logger.WithField("device_id", deviceID).Warn("User attempted to unregister a device of another user")
+		return ErrNotFound
+	}
+
+	if err := service.deviceStore.Delete(ctx, deviceID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("device_id", deviceID).Error("Error deleting device")
+		return ErrInternal
+	}
+	logger.WithField("device_id", deviceID).Info("Device unregistered successfully")
+	return nil
+}
+
+// GetPreferences fetches the notification preferences of a user, falling back to the defaults.
+func (service *NotificationServiceImpl) GetPreferences(logger *logrus.Entry, ctx context.Context, userID int) (*models.NotificationPreferences, error) {
+	preferences, err := service.preferenceStore.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return models.DefaultNotificationPreferences(userID), nil
+		}
+		logger.WithError(err).WithField("user_id", userID).Error("Error fetching notification preferences")
+		return nil, ErrInternal
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences stores the notification preferences of a user.
+func (service *NotificationServiceImpl) UpdatePreferences(logger *logrus.Entry, ctx context.Context, preferences *models.NotificationPreferences) error {
+	if err := models.ValidateNotificationPreferences(*preferences); err != nil {
+		logger.WithError(err).Warn("Invalid input for UpdatePreferences")
+		return invalidInput(err)
+	}
+	if err := service.preferenceStore.Upsert(ctx, preferences); err != nil {
+		logger.WithError(err).WithField("user_id", preferences.UserID).Error("Error updating notification preferences")
+		return ErrInternal
+	}
+	logger.WithField("user_id", preferences.UserID).Info("Notification preferences updated successfully")
+	return nil
+}
+
+// NotifyUser sends a notification to all devices of a user, honouring the user's preferences.
+// Delivery happens in the background; failures are logged and never reported to the caller.
+func (service *NotificationServiceImpl) NotifyUser(logger *logrus.Entry, ctx context.Context, userID int, notification models.Notification) {
+	preferences, err := service.GetPreferences(logger, ctx, userID)
+	if err != nil {
+		return
+	}
+	if !preferences.Allows(notification.Type) {
+		logger.WithFields(logrus.Fields{"user_id": userID, "type": notification.Type}).Debug("Notification suppressed by user preferences")
+		return
+	}
+
+	devices, err := service.GetDevicesForUser(logger, ctx, userID)
+	if err != nil || len(devices) == 0 {
+		return
+	}
+
+	go service.deliver(logger, devices, notification) //nolint:errcheck
+}
+
+// NotifyTeacher sends a notification to the user account belonging to a teacher.
+func (service *NotificationServiceImpl) NotifyTeacher(logger *logrus.Entry, ctx context.Context, teacherID int, notification models.Notification) {
+	teacher, err := service.teacherStore.GetByID(ctx, teacherID)
+	if err != nil {
+		logger.WithError(err).WithField("teacher_id", teacherID).Warn("Could not resolve teacher for notification")
+		return
+	}
+	user, err := service.userStore.GetUserByUsername(ctx, teacher.Username)
+	if err != nil {
+		logger.WithError(err).WithField("teacher_id", teacherID).Debug("Teacher has no user account, skipping notification")
+		return
+	}
+	service.NotifyUser(logger, ctx, user.ID, notification)
+}
+
+// SendReminder reminds a teacher to document and records the reminder in the audit log, where the quality
+// report counts the reminders that were ignored.
+func (service *NotificationServiceImpl) SendReminder(logger *logrus.Entry, ctx context.Context, actingUserID int, teacherID int, message string) {
+	service.NotifyTeacher(logger, ctx, teacherID, models.Notification{
+		Type:  models.NotificationTypeReminder,
+		Title: "Erinnerung",
+		Body:  message,
+	})
+	if service.auditLogService != nil {
+		// The reminder is on its way already, so a failing audit write is only logged.
+		_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+			Action:      models.AuditActionSendReminder,
+			EntityType:  "teacher",
+			EntityID:    &teacherID,
+			ActorUserID: &actingUserID,
+		})
+	}
+}
+
+// TeacherMessage builds an outbox message that notifies the user account belonging to a teacher.
+// Stores enqueue it together with the change it reports, the outbox dispatcher delivers it via Deliver.
+func (service *NotificationServiceImpl) TeacherMessage(teacherID int, notification models.Notification) (models.OutboxMessage, error) {
+	payload, err := json.Marshal(models.PushOutboxPayload{TeacherID: &teacherID, Notification: notification})
+	if err != nil {
+		return models.OutboxMessage{}, err
+	}
+	return models.OutboxMessage{Channel: models.OutboxChannelPush, Payload: payload, NextAttemptAt: service.clock.Now()}, nil
+}
+
+// Deliver sends a push outbox message synchronously, honouring the preferences of the recipient at delivery time.
+// It returns an error if any device could not be reached so that the dispatcher retries the message,
+// and ErrUndeliverable if the message can never be delivered.
+func (service *NotificationServiceImpl) Deliver(logger *logrus.Entry, ctx context.Context, message models.OutboxMessage) error {
+	var payload models.PushOutboxPayload
+	if err := json.Unmarshal(message.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: invalid payload: %v", ErrUndeliverable, err)
+	}
+
+	var userID int
+	switch {
+	case payload.UserID != nil:
+		userID = *payload.UserID
+	case payload.TeacherID != nil:
+		teacher, err := service.teacherStore.GetByID(ctx, *payload.TeacherID)
+		if errors.Is(err, data.ErrNotFound) {
+			return fmt.Errorf("%w: teacher %d not found", ErrUndeliverable, *payload.TeacherID)
+		}
+		if err != nil {
+			return err
+		}
+		user, err := service.userStore.GetUserByUsername(ctx, teacher.Username)
+		if errors.Is(err, data.ErrNotFound) {
+			// Teachers without a user account have no devices.
+			logger.WithField("teacher_id", teacher.ID).Debug("Teacher has no user account, skipping notification")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		userID = user.ID
+	default:
+		return fmt.Errorf("%w: no recipient", ErrUndeliverable)
+	}
+
+	preferences, err := service.GetPreferences(logger, ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !preferences.Allows(payload.Notification.Type) {
+		logger.WithFields(logrus.Fields{"user_id": userID, "type": payload.Notification.Type}).Debug("Notification suppressed by user preferences")
+		return nil
+	}
+	devices, err := service.GetDevicesForUser(logger, ctx, userID)
+	if err != nil {
+		return err
+	}
+	return service.deliver(logger, devices, payload.Notification)
+}
+
+// deliver sends a notification to the given devices and prunes devices the push service no longer knows.
+// It returns the last error of a device that could not be reached.
+func (service *NotificationServiceImpl) deliver(logger *logrus.Entry, devices []models.Device, notification models.Notification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pushDeliveryTimeout)
+	defer cancel()
+
+	var lastErr error
+	for _, device := range devices {
+		gateway, ok := service.gateways[device.Platform]
+		if !ok {
+			continue
+		}
+		err := gateway.Send(ctx, device, notification)
+		if errors.Is(err, ErrDeviceGone) {
+			logger.WithField("device_id", device.ID).Info("Removing device that is no longer registered with the push service")
+			if err := service.deviceStore.DeleteByToken(ctx, device.Token); err != nil {
+				logger.WithError(err).WithField("device_id", device.ID).Error("Error removing stale device")
+			}
+			continue
+		}
+		if err != nil {
+			logger.WithError(err).WithField("device_id", device.ID).Error("Error sending push notification")
+			lastErr = err
+		}
+	}
+	return lastErr
+}
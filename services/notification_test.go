@@ -0,0 +1,237 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordingGateway is a PushGateway that records sent notifications.
+type recordingGateway struct {
+	sent chan models.Device
+	err  error
+}
+
+func (g *recordingGateway) Send(ctx context.Context, device models.Device, notification models.Notification) error {
+	g.sent <- device
+	return g.err
+}
+
+func TestRegisterDevice(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockDeviceStore := new(datamocks.MockDeviceStore)
+		service := services.NewNotificationService(mockDeviceStore, nil, nil, nil, nil, nil, clock.System{})
+
+		device := &models.Device{UserID: 1, Platform: models.DevicePlatformFCM, Token: "token"}
+		mockDeviceStore.On("Upsert", mock.Anything, device).Return(5, nil).Once()
+		mockDeviceStore.On("GetByID", mock.Anything, 5).Return(&models.Device{ID: 5, UserID: 1, Platform: models.DevicePlatformFCM, Token: "token"}, nil).Once()
+
+		registered, err := service.RegisterDevice(logger, ctx, device)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, registered.ID)
+		mockDeviceStore.AssertExpectations(t)
+	})
+
+	t.Run("token of another user", func(t *testing.T) {
+		mockDeviceStore := new(datamocks.MockDeviceStore)
+		service := services.NewNotificationService(mockDeviceStore, nil, nil, nil, nil, nil, clock.System{})
+
+		device := &models.Device{UserID: 2, Platform: models.DevicePlatformFCM, Token: "token"}
+		mockDeviceStore.On("Upsert", mock.Anything, device).Return(0, data.ErrConflict).Once()
+
+		_, err := service.RegisterDevice(logger, ctx, device)
+
+		assert.ErrorIs(t, err, services.ErrDeviceTokenTaken)
+		mockDeviceStore.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid platform", func(t *testing.T) {
+		mockDeviceStore := new(datamocks.MockDeviceStore)
+		service := services.NewNotificationService(mockDeviceStore, nil, nil, nil, nil, nil, clock.System{})
+
+		_, err := service.RegisterDevice(logger, ctx, &models.Device{UserID: 1, Platform: "sms", Token: "token"})
+
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		mockDeviceStore.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUnregisterDevice(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockDeviceStore := new(datamocks.MockDeviceStore)
+		service := services.NewNotificationService(mockDeviceStore, nil, nil, nil, nil, nil, clock.System{})
+
+		mockDeviceStore.On("GetByID", mock.Anything, 3).Return(&models.Device{ID: 3, UserID: 1}, nil).Once()
+		mockDeviceStore.On("Delete", mock.Anything, 3).Return(nil).Once()
+
+		err := service.UnregisterDevice(logger, ctx, 1, 3)
+
+		assert.NoError(t, err)
+		mockDeviceStore.AssertExpectations(t)
+	})
+
+	t.Run("device of another user", func(t *testing.T) {
+		mockDeviceStore := new(datamocks.MockDeviceStore)
+		service := services.NewNotificationService(mockDeviceStore, nil, nil, nil, nil, nil, clock.System{})
+
+		mockDeviceStore.On("GetByID", mock.Anything, 3).Return(&models.Device{ID: 3, UserID: 2}, nil).Once()
+
+		err := service.UnregisterDevice(logger, ctx, 1, 3)
+
+		assert.Equal(t, services.ErrNotFound, err)
+		mockDeviceStore.AssertNotCalled(t, "Delete", mock.Anything, 3)
+	})
+}
+
+func TestGetPreferencesDefaults(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	mockPreferenceStore := new(datamocks.MockNotificationPreferenceStore)
+	service := services.NewNotificationService(nil, mockPreferenceStore, nil, nil, nil, nil, clock.System{})
+
+	mockPreferenceStore.On("Get", mock.Anything, 1).Return(nil, data.ErrNotFound).Once()
+
+	preferences, err := service.GetPreferences(logger, context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.DefaultNotificationPreferences(1), preferences)
+}
+
+func TestNotifyUser(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	notification := models.Notification{Type: models.NotificationTypeApproval, Title: "title", Body: "body"}
+
+	t.Run("delivers to devices and prunes gone devices", func(t *testing.T) {
+		mockDeviceStore := new(datamocks.MockDeviceStore)
+		mockPreferenceStore := new(datamocks.MockNotificationPreferenceStore)
+		gateway := &recordingGateway{sent: make(chan models.Device, 1), err: services.ErrDeviceGone}
+		service := services.NewNotificationService(mockDeviceStore, mockPreferenceStore, nil, nil, nil, map[string]services.PushGateway{
+			models.DevicePlatformFCM: gateway,
+		}, clock.System{})
+
+		device := models.Device{ID: 1, UserID: 1, Platform: models.DevicePlatformFCM, Token: "gone"}
+		mockPreferenceStore.On("Get", mock.Anything, 1).Return(nil, data.ErrNotFound).Once()
+		mockDeviceStore.On("GetAllForUser", mock.Anything, 1).Return([]models.Device{device}, nil).Once()
+		deleted := make(chan string, 1)
+		mockDeviceStore.On("DeleteByToken", mock.Anything, "gone").Run(func(args mock.Arguments) {
+			deleted <- args.String(1)
+		}).Return(nil).Once()
+
+		service.NotifyUser(logger, ctx, 1, notification)
+
+		select {
+		case sent := <-gateway.sent:
+			assert.Equal(t, device, sent)
+		case <-time.After(time.Second):
+			t.Fatal("notification was not sent")
+		}
+		select {
+		case token := <-deleted:
+			assert.Equal(t, "gone", token)
+		case <-time.After(time.Second):
+			t.Fatal("gone device was not removed")
+		}
+	})
+
+	t.Run("suppressed by preferences", func(t *testing.T) {
+		mockDeviceStore := new(datamocks.MockDeviceStore)
+		mockPreferenceStore := new(datamocks.MockNotificationPreferenceStore)
+		service := services.NewNotificationService(mockDeviceStore, mockPreferenceStore, nil, nil, nil, nil, clock.System{})
+
+		preferences := models.DefaultNotificationPreferences(1)
+		preferences.NotifyApprovals = false
+		mockPreferenceStore.On("Get", mock.Anything, 1).Return(preferences, nil).Once()
+
+		service.NotifyUser(logger, ctx, 1, notification)
+
+		mockDeviceStore.AssertNotCalled(t, "GetAllForUser", mock.Anything, 1)
+	})
+}
+
+func TestDeliverPushMessage(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	notification := models.Notification{Type: models.NotificationTypeApproval, Title: "title", Body: "body"}
+
+	t.Run("resolves teacher and reports gateway errors", func(t *testing.T) {
+		mockDeviceStore := new(datamocks.MockDeviceStore)
+		mockPreferenceStore := new(datamocks.MockNotificationPreferenceStore)
+		mockTeacherStore := new(datamocks.MockTeacherStore)
+		mockUserStore := new(datamocks.MockUserStore)
+		gateway := &recordingGateway{sent: make(chan models.Device, 1), err: errors.New("unavailable")}
+		service := services.NewNotificationService(mockDeviceStore, mockPreferenceStore, mockTeacherStore, mockUserStore, nil, map[string]services.PushGateway{
+			models.DevicePlatformFCM: gateway,
+		}, clock.System{})
+
+		message, err := service.TeacherMessage(3, notification)
+		assert.NoError(t, err)
+		assert.Equal(t, models.OutboxChannelPush, message.Channel)
+
+		device := models.Device{ID: 1, UserID: 1, Platform: models.DevicePlatformFCM, Token: "token"}
+		mockTeacherStore.On("GetByID", mock.Anything, 3).Return(&models.Teacher{ID: 3, Username: "teacher"}, nil).Once()
+		mockUserStore.On("GetUserByUsername", mock.Anything, "teacher").Return(&models.User{ID: 1}, nil).Once()
+		mockPreferenceStore.On("Get", mock.Anything, 1).Return(nil, data.ErrNotFound).Once()
+		mockDeviceStore.On("GetAllForUser", mock.Anything, 1).Return([]models.Device{device}, nil).Once()
+
+		err = service.Deliver(logger, ctx, message)
+		assert.EqualError(t, err, "unavailable")
+		assert.Equal(t, device, <-gateway.sent)
+	})
+
+	t.Run("unknown teacher", func(t *testing.T) {
+		mockTeacherStore := new(datamocks.MockTeacherStore)
+		service := services.NewNotificationService(nil, nil, mockTeacherStore, nil, nil, nil, clock.System{})
+		mockTeacherStore.On("GetByID", mock.Anything, 3).Return(nil, data.ErrNotFound).Once()
+
+		message, err := service.TeacherMessage(3, notification)
+		assert.NoError(t, err)
+		err = service.Deliver(logger, ctx, message)
+		assert.ErrorIs(t, err, services.ErrUndeliverable)
+	})
+}
+
+func TestFCMGatewaySend(t *testing.T) {
+	device := models.Device{Platform: models.DevicePlatformFCM, Token: "token"}
+	notification := models.Notification{Type: models.NotificationTypeReminder, Title: "title", Body: "body"}
+
+	t.Run("success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "key=secret", r.Header.Get("Authorization"))
+			w.Write([]byte(`{"success":1,"failure":0,"results":[{"message_id":"1"}]}`)) //nolint:errcheck
+		}))
+		defer server.Close()
+
+		gateway := services.NewFCMGateway(server.Client(), server.URL, "secret")
+		assert.NoError(t, gateway.Send(context.Background(), device, notification))
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"success":0,"failure":1,"results":[{"error":"NotRegistered"}]}`)) //nolint:errcheck
+		}))
+		defer server.Close()
+
+		gateway := services.NewFCMGateway(server.Client(), server.URL, "secret")
+		assert.ErrorIs(t, gateway.Send(context.Background(), device, notification), services.ErrDeviceGone)
+	})
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	outboxBatchSize          = 50
+	outboxMaxRetryBackoff    = time.Hour
+	defaultOutboxMaxAttempts = 8
+)
+
+// OutboxService defines the interface for inspecting the outbox.
+type OutboxService interface {
+	GetMessages(logger *logrus.Entry, ctx context.Context, status string) ([]models.OutboxMessage, error)
+}
+
+// OutboxDeliverer delivers the outbox messages of one channel.
+// Returning an error wrapping ErrUndeliverable gives up on the message immediately, other errors are retried.
+type OutboxDeliverer interface {
+	Deliver(logger *logrus.Entry, ctx context.Context, message models.OutboxMessage) error
+}
+
+// OutboxDispatcher implements OutboxService and delivers due outbox messages, recording the outcome.
+// Delivery is at least once: a message whose delivery succeeds but cannot be marked is sent again.
+type OutboxDispatcher struct {
+	outboxStore data.OutboxStore
+	deliverers  map[string]OutboxDeliverer // Keyed by channel
+	maxAttempts int
+	clock       clock.Clock
+}
+
+// NewOutboxDispatcher creates a new OutboxDispatcher. A non-positive maxAttempts uses the default.
+func NewOutboxDispatcher(outboxStore data.OutboxStore, deliverers map[string]OutboxDeliverer, maxAttempts int, clock clock.Clock) *OutboxDispatcher {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultOutboxMaxAttempts
+	}
+	return &OutboxDispatcher{outboxStore: outboxStore, deliverers: deliverers, maxAttempts: maxAttempts, clock: clock}
+}
+
+// GetMessages fetches the outbox messages in a state.
+func (dispatcher *OutboxDispatcher) GetMessages(logger *logrus.Entry, ctx context.Context, status string) ([]models.OutboxMessage, error) {
+	switch status {
+	case models.OutboxStatusPending, models.OutboxStatusDelivered, models.OutboxStatusFailed:
+	default:
+		logger.WithField("status", status).Warn("Invalid outbox status")
+		return nil, ErrInvalidInput
+	}
+	messages, err := dispatcher.outboxStore.GetByStatus(ctx, status)
+	if err != nil {
+		logger.WithError(err).WithField("status", status).Error("Error fetching outbox messages")
+		return nil, ErrInternal
+	}
+	return messages, nil
+}
+
+// DispatchDue delivers one batch of due messages and returns how many of them were delivered.
+func (dispatcher *OutboxDispatcher) DispatchDue(logger *logrus.Entry, ctx context.Context) int {
+	now := dispatcher.clock.Now()
+	messages, err := dispatcher.outboxStore.GetDue(ctx, now, outboxBatchSize)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching due outbox messages")
+		return 0
+	}
+
+	delivered := 0
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			break
+		}
+		messageLogger := logger.WithFields(logrus.Fields{"message_id": message.ID, "channel": message.Channel})
+		err := dispatcher.deliver(messageLogger, ctx, message)
+		switch {
+		case err == nil:
+			if err := dispatcher.outboxStore.MarkDelivered(ctx, message.ID, dispatcher.clock.Now()); err != nil {
+				messageLogger.WithError(err).Error("Error marking outbox message as delivered")
+				continue
+			}
+			delivered++
+		case errors.Is(err, ErrUndeliverable) || message.Attempts+1 >= dispatcher.maxAttempts:
+			messageLogger.WithError(err).Error("Giving up on outbox message")
+			if err := dispatcher.outboxStore.MarkFailed(ctx, message.ID, err.Error()); err != nil {
+				messageLogger.WithError(err).Error("Error marking outbox message as failed")
+			}
+		default:
+			messageLogger.WithError(err).Warn("Outbox message delivery failed, retrying later")
+			if err := dispatcher.outboxStore.MarkRetry(ctx, message.ID, err.Error(), now.Add(outboxRetryBackoff(message.Attempts+1))); err != nil {
+				messageLogger.WithError(err).Error("Error scheduling outbox message retry")
+			}
+		}
+	}
+	return delivered
+}
+
+// Run dispatches due messages every interval until the context is cancelled.
+func (dispatcher *OutboxDispatcher) Run(logger *logrus.Entry, ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Drain the backlog before waiting for the next tick.
+		for dispatcher.DispatchDue(logger, ctx) == outboxBatchSize {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (dispatcher *OutboxDispatcher) deliver(logger *logrus.Entry, ctx context.Context, message models.OutboxMessage) error {
+	deliverer, ok := dispatcher.deliverers[message.Channel]
+	if !ok {
+		return ErrUndeliverable
+	}
+	return deliverer.Deliver(logger, ctx, message)
+}
+
+// outboxRetryBackoff returns the delay before the given attempt, growing quadratically up to an hour.
+func outboxRetryBackoff(attempts int) time.Duration {
+	backoff := time.Duration(attempts*attempts) * time.Minute
+	if backoff > outboxMaxRetryBackoff {
+		return outboxMaxRetryBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// stubDeliverer is an OutboxDeliverer that returns a fixed error.
+type stubDeliverer struct {
+	err error
+}
+
+func (d *stubDeliverer) Deliver(logger *logrus.Entry, ctx context.Context, message models.OutboxMessage) error {
+	return d.err
+}
+
+func TestDispatchDue(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	message := models.OutboxMessage{ID: 1, Channel: models.OutboxChannelPush, Attempts: 2}
+	now := time.Date(2025, time.March, 5, 9, 0, 0, 0, time.UTC)
+
+	t.Run("delivered", func(t *testing.T) {
+		mockOutboxStore := new(datamocks.MockOutboxStore)
+		dispatcher := services.NewOutboxDispatcher(mockOutboxStore, map[string]services.OutboxDeliverer{
+			models.OutboxChannelPush: &stubDeliverer{},
+		}, 5, clock.NewFrozen(now))
+		mockOutboxStore.On("GetDue", mock.Anything, now, mock.Anything).Return([]models.OutboxMessage{message}, nil).Once()
+		mockOutboxStore.On("MarkDelivered", mock.Anything, 1, now).Return(nil).Once()
+
+		assert.Equal(t, 1, dispatcher.DispatchDue(logger, ctx))
+		mockOutboxStore.AssertExpectations(t)
+	})
+
+	t.Run("retried", func(t *testing.T) {
+		mockOutboxStore := new(datamocks.MockOutboxStore)
+		dispatcher := services.NewOutboxDispatcher(mockOutboxStore, map[string]services.OutboxDeliverer{
+			models.OutboxChannelPush: &stubDeliverer{err: errors.New("unavailable")},
+		}, 5, clock.NewFrozen(now))
+		mockOutboxStore.On("GetDue", mock.Anything, now, mock.Anything).Return([]models.OutboxMessage{message}, nil).Once()
+		mockOutboxStore.On("MarkRetry", mock.Anything, 1, "unavailable", mock.Anything).Return(nil).Once()
+
+		assert.Equal(t, 0, dispatcher.DispatchDue(logger, ctx))
+		mockOutboxStore.AssertExpectations(t)
+	})
+
+	t.Run("failed after max attempts", func(t *testing.T) {
+		mockOutboxStore := new(datamocks.MockOutboxStore)
+		dispatcher := services.NewOutboxDispatcher(mockOutboxStore, map[string]services.OutboxDeliverer{
+			models.OutboxChannelPush: &stubDeliverer{err: errors.New("unavailable")},
+		}, 3, clock.NewFrozen(now))
+		mockOutboxStore.On("GetDue", mock.Anything, now, mock.Anything).Return([]models.OutboxMessage{message}, nil).Once()
+		mockOutboxStore.On("MarkFailed", mock.Anything, 1, "unavailable").Return(nil).Once()
+
+		assert.Equal(t, 0, dispatcher.DispatchDue(logger, ctx))
+		mockOutboxStore.AssertExpectations(t)
+	})
+
+	t.Run("undeliverable", func(t *testing.T) {
+		mockOutboxStore := new(datamocks.MockOutboxStore)
+		dispatcher := services.NewOutboxDispatcher(mockOutboxStore, map[string]services.OutboxDeliverer{
+			models.OutboxChannelPush: &stubDeliverer{err: fmt.Errorf("%w: no recipient", services.ErrUndeliverable)},
+		}, 5, clock.NewFrozen(now))
+		mockOutboxStore.On("GetDue", mock.Anything, now, mock.Anything).Return([]models.OutboxMessage{message, {ID: 2, Channel: "email"}}, nil).Once()
+		mockOutboxStore.On("MarkFailed", mock.Anything, 1, "undeliverable: no recipient").Return(nil).Once()
+		mockOutboxStore.On("MarkFailed", mock.Anything, 2, "undeliverable").Return(nil).Once()
+
+		assert.Equal(t, 0, dispatcher.DispatchDue(logger, ctx))
+		mockOutboxStore.AssertExpectations(t)
+	})
+}
+
+func TestGetOutboxMessages(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	dispatcher := services.NewOutboxDispatcher(new(datamocks.MockOutboxStore), nil, 0, clock.System{})
+
+	messages, err := dispatcher.GetMessages(logger, context.Background(), "unknown")
+	assert.Nil(t, messages)
+	assert.Equal(t, services.ErrInvalidInput, err)
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"kitadoc-backend/data"
 	"kitadoc-backend/internal/logger"
@@ -8,9 +9,9 @@ import (
 )
 
 type ProcessService interface {
-	Create(status string) (*models.Process, error)
-	Update(process *models.Process) error
-	GetByID(id int) (*models.Process, error)
+	Create(ctx context.Context, status string) (*models.Process, error)
+	Update(ctx context.Context, process *models.Process) error
+	GetByID(ctx context.Context, id int) (*models.Process, error)
 }
 
 type ProcessServiceImpl struct {
@@ -23,8 +24,8 @@ func NewProcessService(store data.ProcessStore) *ProcessServiceImpl {
 	}
 }
 
-func (s *ProcessServiceImpl) Create(status string) (*models.Process, error) {
-	process, err := s.store.Create(&models.Process{Status: status})
+func (s *ProcessServiceImpl) Create(ctx context.Context, status string) (*models.Process, error) {
+	process, err := s.store.Create(ctx, &models.Process{Status: status})
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Failed to create process: %v", err)
 		return nil, err
@@ -32,16 +33,16 @@ func (s *ProcessServiceImpl) Create(status string) (*models.Process, error) {
 	return process, nil
 }
 
-func (s *ProcessServiceImpl) Update(process *models.Process) error {
-	if err := s.store.Update(process); err != nil {
+func (s *ProcessServiceImpl) Update(ctx context.Context, process *models.Process) error {
+	if err := s.store.Update(ctx, process); err != nil {
 		logger.GetGlobalLogger().Errorf("Failed to update process: %v", err)
 		return err
 	}
 	return nil
 }
 
-func (s *ProcessServiceImpl) GetByID(id int) (*models.Process, error) {
-	process, err := s.store.GetByID(id)
+func (s *ProcessServiceImpl) GetByID(ctx context.Context, id int) (*models.Process, error) {
+	process, err := s.store.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.GetGlobalLogger().Errorf("Process not found: %v", err)
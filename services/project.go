@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProjectService defines the interface for planning projects and summing up the observations made in them.
+type ProjectService interface {
+	CreateProject(logger *logrus.Entry, ctx context.Context, project *models.Project) (*models.Project, error)
+	GetProjectByID(logger *logrus.Entry, ctx context.Context, id int) (*models.Project, error)
+	GetAllProjects(logger *logrus.Entry, ctx context.Context) ([]models.Project, error)
+	UpdateProject(logger *logrus.Entry, ctx context.Context, project *models.Project) error
+	// DeleteProject deletes a project, its documentation entries are kept without a project.
+	DeleteProject(logger *logrus.Entry, ctx context.Context, id int) error
+	// GetProjectDocumentation fetches the documentation entries of all children referring to a project matching the query.
+	GetProjectDocumentation(logger *logrus.Entry, ctx context.Context, projectID int, query models.ListQuery) ([]models.DocumentationEntry, error)
+	// GetProjectSummary counts the entries of a project per goal and the documented children per participating group.
+	GetProjectSummary(logger *logrus.Entry, ctx context.Context, projectID int) (*models.ProjectSummary, error)
+}
+
+// ProjectServiceImpl implements ProjectService.
+type ProjectServiceImpl struct {
+	projectStore            data.ProjectStore
+	groupStore              data.GroupStore
+	categoryStore           data.CategoryStore
+	documentationEntryStore data.DocumentationEntryStore
+}
+
+// NewProjectService creates a new ProjectServiceImpl.
+func NewProjectService(projectStore data.ProjectStore, groupStore data.GroupStore, categoryStore data.CategoryStore, documentationEntryStore data.DocumentationEntryStore) *ProjectServiceImpl {
+	return &ProjectServiceImpl{
+		projectStore:            projectStore,
+		groupStore:              groupStore,
+		categoryStore:           categoryStore,
+		documentationEntryStore: documentationEntryStore,
+	}
+}
+
+// CreateProject creates a new project with its groups and goals.
+func (service *ProjectServiceImpl) CreateProject(logger *logrus.Entry, ctx context.Context, project *models.Project) (*models.Project, error) {
+	if err := service.validate(logger, ctx, project); err != nil {
+		return nil, err
+	}
+
+	id, err := service.projectStore.Create(ctx, project)
+	if err != nil {
+		logger.WithError(err).Error("Error creating project")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"project_id": id, "groups": len(project.GroupIDs), "goals": len(project.Goals)}).Info("Project created successfully")
+	return service.GetProjectByID(logger, ctx, id)
+}
+
+// GetProjectByID fetches a project by ID.
+func (service *ProjectServiceImpl) GetProjectByID(logger *logrus.Entry, ctx context.Context, id int) (*models.Project, error) {
+	project, err := service.projectStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		logger.WithError(err).WithField("project_id", id).Error("Error fetching project")
+		return nil, ErrInternal
+	}
+	return project, nil
+}
+
+// GetAllProjects fetches all projects, the latest first.
+func (service *ProjectServiceImpl) GetAllProjects(logger *logrus.Entry, ctx context.Context) ([]models.Project, error) {
+	projects, err := service.projectStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching projects")
+		return nil, ErrInternal
+	}
+	return projects, nil
+}
+
+// UpdateProject updates a project and replaces its groups and goals.
+func (service *ProjectServiceImpl) UpdateProject(logger *logrus.Entry, ctx context.Context, project *models.Project) error {
+	if err := service.validate(logger, ctx, project); err != nil {
+		return err
+	}
+
+	if err := service.projectStore.Update(ctx, project); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrProjectNotFound
+		}
+		logger.WithError(err).WithField("project_id", project.ID).Error("Error updating project")
+		return ErrInternal
+	}
+	logger.WithField("project_id", project.ID).Info("Project updated successfully")
+	return nil
+}
+
+// DeleteProject deletes a project.
+func (service *ProjectServiceImpl) DeleteProject(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.projectStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrProjectNotFound
+		}
+		logger.WithError(err).WithField("project_id", id).Error("Error deleting project")
+		return ErrInternal
+	}
+	logger.WithField("project_id", id).Info("Project deleted successfully")
+	return nil
+}
+
+// GetProjectDocumentation fetches the documentation entries referring to a project.
+func (service *ProjectServiceImpl) GetProjectDocumentation(logger *logrus.Entry, ctx context.Context, projectID int, query models.ListQuery) ([]models.DocumentationEntry, error) {
+	if _, err := service.GetProjectByID(logger, ctx, projectID); err != nil {
+		return nil, err
+	}
+	entries, err := service.documentationEntryStore.List(ctx, query.Where("project_id", models.OperatorEqual, projectID))
+	if err != nil {
+		if errors.Is(err, data.ErrInvalidInput) {
+			logger.WithError(err).WithField("project_id", projectID).Warn("Invalid query for documentation entries of project")
+			return nil, ErrInvalidInput
+		}
+		logger.WithError(err).WithField("project_id", projectID).Error("Error fetching documentation entries of project")
+		return nil, ErrInternal
+	}
+	return entries, nil
+}
+
+// GetProjectSummary sums up the documentation entries of a project. An entry counts for every goal mapped to its
+// category. The participating groups are compared with their current children.
+func (service *ProjectServiceImpl) GetProjectSummary(logger *logrus.Entry, ctx context.Context, projectID int) (*models.ProjectSummary, error) {
+	project, err := service.GetProjectByID(logger, ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := service.GetProjectDocumentation(logger, ctx, projectID, models.ListQuery{})
+	if err != nil {
+		return nil, err
+	}
+	categories, err := service.categoryStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching categories for project summary")
+		return nil, ErrInternal
+	}
+	categoryNames := make(map[int]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	summary := &models.ProjectSummary{
+		ProjectID:  project.ID,
+		Title:      project.Title,
+		StartDate:  project.StartDate,
+		EndDate:    project.EndDate,
+		EntryCount: len(entries),
+		Goals:      []models.ProjectGoalSummary{},
+		Groups:     []models.ProjectGroupSummary{},
+	}
+	documented := map[int]bool{}
+	entriesByCategory := map[int]int{}
+	childrenByCategory := map[int]map[int]bool{}
+	for _, entry := range entries {
+		if entry.IsApproved {
+			summary.ApprovedEntryCount++
+		}
+		documented[entry.ChildID] = true
+		entriesByCategory[entry.CategoryID]++
+		if childrenByCategory[entry.CategoryID] == nil {
+			childrenByCategory[entry.CategoryID] = map[int]bool{}
+		}
+		childrenByCategory[entry.CategoryID][entry.ChildID] = true
+	}
+	summary.ChildCount = len(documented)
+
+	goalCategories := map[int]bool{}
+	for _, goal := range project.Goals {
+		goalCategories[goal.CategoryID] = true
+		summary.Goals = append(summary.Goals, models.ProjectGoalSummary{
+			GoalID:       goal.ID,
+			CategoryID:   goal.CategoryID,
+			CategoryName: categoryNames[goal.CategoryID],
+			Description:  goal.Description,
+			EntryCount:   entriesByCategory[goal.CategoryID],
+			ChildCount:   len(childrenByCategory[goal.CategoryID]),
+		})
+	}
+	for categoryID, count := range entriesByCategory {
+		if !goalCategories[categoryID] {
+			summary.EntriesWithoutGoal += count
+		}
+	}
+
+	for _, groupID := range project.GroupIDs {
+		group, err := service.groupStore.GetByID(ctx, groupID)
+		if err != nil {
+			if errors.Is(err, data.ErrNotFound) {
+				continue
+			}
+			logger.WithError(err).WithField("group_id", groupID).Error("Error fetching group for project summary")
+			return nil, ErrInternal
+		}
+		groupSummary := models.ProjectGroupSummary{GroupID: group.ID, GroupName: group.Name, ChildCount: len(group.ChildIDs)}
+		for _, childID := range group.ChildIDs {
+			if documented[childID] {
+				groupSummary.DocumentedChildren++
+			}
+		}
+		summary.Groups = append(summary.Groups, groupSummary)
+	}
+	return summary, nil
+}
+
+// validate checks the project and that its groups and the categories of its goals exist.
+func (service *ProjectServiceImpl) validate(logger *logrus.Entry, ctx context.Context, project *models.Project) error {
+	if err := models.ValidateProject(*project); err != nil {
+		logger.WithError(err).Warn("Invalid input for project")
+		return invalidInput(err)
+	}
+	for _, groupID := range project.GroupIDs {
+		if _, err := service.groupStore.GetByID(ctx, groupID); err != nil {
+			if errors.Is(err, data.ErrNotFound) {
+				logger.WithField("group_id", groupID).Warn("Group not found for project")
+				return ErrGroupNotFound
+			}
+			logger.WithError(err).WithField("group_id", groupID).Error("Error fetching group for project")
+			return ErrInternal
+		}
+	}
+	for _, goal := range project.Goals {
+		if _, err := service.categoryStore.GetByID(ctx, goal.CategoryID); err != nil {
+			if errors.Is(err, data.ErrNotFound) {
+				logger.WithField("category_id", goal.CategoryID).Warn("Category not found for project goal")
+				return ErrCategoryNotFound
+			}
+			logger.WithError(err).WithField("category_id", goal.CategoryID).Error("Error fetching category for project goal")
+			return ErrInternal
+		}
+	}
+	return nil
+}
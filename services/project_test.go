@@ -0,0 +1,142 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	autumn := &models.Project{
+		ID:        1,
+		Title:     "Herbstwoche",
+		StartDate: models.NewDate(2024, time.October, 7),
+		EndDate:   models.NewDate(2024, time.October, 11),
+		GroupIDs:  []int{5},
+		Goals: []models.ProjectGoal{
+			{ID: 1, CategoryID: 2, Description: "Sammelt Blätter beim Waldspaziergang"},
+			{ID: 2, CategoryID: 3, Description: "Benennt die Farben der Blätter"},
+		},
+	}
+
+	setup := func() (*services.ProjectServiceImpl, *datamocks.MockProjectStore, *datamocks.MockGroupStore, *datamocks.MockCategoryStore, *datamocks.MockDocumentationEntryStore) {
+		projectStore := new(datamocks.MockProjectStore)
+		groupStore := new(datamocks.MockGroupStore)
+		categoryStore := new(datamocks.MockCategoryStore)
+		entryStore := new(datamocks.MockDocumentationEntryStore)
+		return services.NewProjectService(projectStore, groupStore, categoryStore, entryStore), projectStore, groupStore, categoryStore, entryStore
+	}
+
+	t.Run("create checks groups and categories", func(t *testing.T) {
+		service, projectStore, groupStore, categoryStore, _ := setup()
+		groupStore.On("GetByID", mock.Anything, 5).Return(&models.Group{ID: 5}, nil)
+		groupStore.On("GetByID", mock.Anything, 6).Return(nil, data.ErrNotFound)
+		categoryStore.On("GetByID", mock.Anything, 2).Return(&models.Category{ID: 2}, nil)
+		categoryStore.On("GetByID", mock.Anything, 9).Return(nil, data.ErrNotFound)
+		project := func(groupID int, categoryID int) *models.Project {
+			return &models.Project{Title: "Herbstwoche", StartDate: autumn.StartDate, EndDate: autumn.EndDate, GroupIDs: []int{groupID},
+				Goals: []models.ProjectGoal{{CategoryID: categoryID, Description: "Sammelt Blätter"}}}
+		}
+
+		_, err := service.CreateProject(logger, ctx, project(6, 2))
+		assert.ErrorIs(t, err, services.ErrGroupNotFound)
+		_, err = service.CreateProject(logger, ctx, project(5, 9))
+		assert.ErrorIs(t, err, services.ErrCategoryNotFound)
+		invalid := project(5, 2)
+		invalid.EndDate = models.NewDate(2024, time.October, 1)
+		_, err = service.CreateProject(logger, ctx, invalid)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		projectStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+		projectStore.On("Create", mock.Anything, mock.Anything).Return(1, nil).Once()
+		projectStore.On("GetByID", mock.Anything, 1).Return(autumn, nil).Once()
+		created, err := service.CreateProject(logger, ctx, project(5, 2))
+		require.NoError(t, err)
+		assert.Equal(t, 1, created.ID)
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		service, projectStore, _, _, _ := setup()
+		projectStore.On("GetByID", mock.Anything, 9).Return(nil, data.ErrNotFound)
+		projectStore.On("Delete", mock.Anything, 9).Return(data.ErrNotFound)
+
+		_, err := service.GetProjectSummary(logger, ctx, 9)
+		assert.ErrorIs(t, err, services.ErrProjectNotFound)
+		assert.ErrorIs(t, service.DeleteProject(logger, ctx, 9), services.ErrProjectNotFound)
+	})
+
+	t.Run("summary counts entries per goal and documented children per group", func(t *testing.T) {
+		service, projectStore, groupStore, categoryStore, entryStore := setup()
+		projectStore.On("GetByID", mock.Anything, 1).Return(autumn, nil)
+		entryStore.On("List", mock.Anything, models.ListQuery{}.Where("project_id", models.OperatorEqual, 1)).Return([]models.DocumentationEntry{
+			{ID: 1, ChildID: 10, CategoryID: 2, IsApproved: true},
+			{ID: 2, ChildID: 10, CategoryID: 2},
+			{ID: 3, ChildID: 11, CategoryID: 2, IsApproved: true},
+			{ID: 4, ChildID: 12, CategoryID: 4},
+		}, nil)
+		categoryStore.On("GetAll", mock.Anything).Return([]models.Category{{ID: 2, Name: "Motorik"}, {ID: 3, Name: "Sprache"}, {ID: 4, Name: "Sozialverhalten"}}, nil)
+		groupStore.On("GetByID", mock.Anything, 5).Return(&models.Group{ID: 5, Name: "Sonnengruppe", ChildIDs: []int{10, 11, 13}}, nil)
+
+		summary, err := service.GetProjectSummary(logger, ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 4, summary.EntryCount)
+		assert.Equal(t, 2, summary.ApprovedEntryCount)
+		assert.Equal(t, 3, summary.ChildCount)
+		assert.Equal(t, []models.ProjectGoalSummary{
+			{GoalID: 1, CategoryID: 2, CategoryName: "Motorik", Description: "Sammelt Blätter beim Waldspaziergang", EntryCount: 3, ChildCount: 2},
+			{GoalID: 2, CategoryID: 3, CategoryName: "Sprache", Description: "Benennt die Farben der Blätter"},
+		}, summary.Goals)
+		assert.Equal(t, 1, summary.EntriesWithoutGoal)
+		assert.Equal(t, []models.ProjectGroupSummary{{GroupID: 5, GroupName: "Sonnengruppe", ChildCount: 3, DocumentedChildren: 2}}, summary.Groups)
+	})
+}
+
+func TestCreateDocumentationEntryInProject(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2024, time.October, 16, 10, 0, 0, 0, time.UTC)
+
+	entryStore := new(datamocks.MockDocumentationEntryStore)
+	childStore := new(datamocks.MockChildStore)
+	teacherStore := new(datamocks.MockTeacherStore)
+	categoryStore := new(datamocks.MockCategoryStore)
+	projectStore := new(datamocks.MockProjectStore)
+	service := services.NewDocumentationEntryService(entryStore, childStore, teacherStore, categoryStore, nil, nil, nil, nil, nil, nil, nil, nil,
+		projectStore, clock.NewFrozen(now))
+	childStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil)
+	teacherStore.On("GetByID", mock.Anything, 1).Return(&models.Teacher{ID: 1}, nil)
+	categoryStore.On("GetByID", mock.Anything, 1).Return(&models.Category{ID: 1}, nil)
+	projectStore.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, StartDate: models.NewDate(2024, time.October, 7), EndDate: models.NewDate(2024, time.October, 11)}, nil)
+	projectStore.On("GetByID", mock.Anything, 9).Return(nil, data.ErrNotFound)
+	entry := func(projectID int, day int) *models.DocumentationEntry {
+		return &models.DocumentationEntry{ChildID: 1, TeacherID: 1, CategoryID: 1, ObservationDate: models.NewDate(2024, time.October, day),
+			ObservationDescription: "Sammelt Kastanien im Garten.", ProjectID: &projectID}
+	}
+
+	_, err := service.CreateDocumentationEntry(logger, ctx, entry(9, 8))
+	assert.ErrorIs(t, err, services.ErrProjectNotFound)
+	_, err = service.CreateDocumentationEntry(logger, ctx, entry(1, 14))
+	assert.ErrorIs(t, err, services.ErrEntryOutsideProject)
+	entryStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	entryStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.DocumentationEntry) bool {
+		return entry.ProjectID != nil && *entry.ProjectID == 1
+	})).Return(3, nil).Once()
+	entryStore.On("GetByID", mock.Anything, 3).Return(&models.DocumentationEntry{ID: 3}, nil).Once()
+	created, err := service.CreateDocumentationEntry(logger, ctx, entry(1, 11))
+	require.NoError(t, err)
+	assert.Equal(t, 3, created.ID)
+}
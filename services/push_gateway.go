@@ -0,0 +1,198 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"kitadoc-backend/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrDeviceGone is returned by a PushGateway when the push service reports that a device token is no longer valid.
+var ErrDeviceGone = errors.New("device is no longer registered with the push service")
+
+// DefaultFCMEndpoint is the legacy HTTP endpoint of Firebase Cloud Messaging.
+const DefaultFCMEndpoint = "https://fcm.googleapis.com/fcm/send"
+
+// PushGateway delivers a notification to a single device.
+type PushGateway interface {
+	Send(ctx context.Context, device models.Device, notification models.Notification) error
+}
+
+// FCMGateway sends notifications through Firebase Cloud Messaging.
+type FCMGateway struct {
+	httpClient *http.Client
+	endpoint   string
+	serverKey  string
+}
+
+// NewFCMGateway creates a new FCMGateway. An empty endpoint falls back to DefaultFCMEndpoint.
+func NewFCMGateway(httpClient *http.Client, endpoint string, serverKey string) *FCMGateway {
+	if endpoint == "" {
+		endpoint = DefaultFCMEndpoint
+	}
+	return &FCMGateway{
+		httpClient: httpClient,
+		endpoint:   endpoint,
+		serverKey:  serverKey,
+	}
+}
+
+// Send delivers a notification to an FCM registration token.
+func (gateway *FCMGateway) Send(ctx context.Context, device models.Device, notification models.Notification) error {
+	payload := map[string]interface{}{
+		"to": device.Token,
+		"notification": map[string]string{
+			"title": notification.Title,
+			"body":  notification.Body,
+		},
+		"data": notificationData(notification),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal FCM payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gateway.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create FCM request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "key="+gateway.serverKey)
+
+	resp, err := gateway.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send FCM request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("FCM returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Failure int `json:"failure"`
+		Results []struct {
+			Error string `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode FCM response: %w", err)
+	}
+	if result.Failure > 0 && len(result.Results) > 0 {
+		switch result.Results[0].Error {
+		case "NotRegistered", "InvalidRegistration":
+			return ErrDeviceGone
+		default:
+			return fmt.Errorf("FCM delivery failed: %s", result.Results[0].Error)
+		}
+	}
+	return nil
+}
+
+// WebPushGateway sends notifications to Web Push endpoints authenticated with VAPID (RFC 8292).
+// Messages are sent without payload; the service worker fetches the details when it wakes up.
+type WebPushGateway struct {
+	httpClient *http.Client
+	privateKey *ecdsa.PrivateKey
+	publicKey  string
+	subject    string
+	ttl        time.Duration
+}
+
+// NewWebPushGateway creates a new WebPushGateway from a base64url encoded VAPID private key.
+func NewWebPushGateway(httpClient *http.Client, vapidPrivateKey string, subject string) (*WebPushGateway, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(vapidPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode VAPID private key: %w", err)
+	}
+	ecdhKey, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	publicKey := ecdhKey.PublicKey().Bytes()
+	privateKey := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(publicKey[1:33]),
+			Y:     new(big.Int).SetBytes(publicKey[33:]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}
+	return &WebPushGateway{
+		httpClient: httpClient,
+		privateKey: privateKey,
+		publicKey:  base64.RawURLEncoding.EncodeToString(publicKey),
+		subject:    subject,
+		ttl:        24 * time.Hour,
+	}, nil
+}
+
+// PublicKey returns the base64url encoded application server key clients need to subscribe.
+func (gateway *WebPushGateway) PublicKey() string {
+	return gateway.publicKey
+}
+
+// Send delivers a notification to a Web Push subscription endpoint.
+func (gateway *WebPushGateway) Send(ctx context.Context, device models.Device, notification models.Notification) error {
+	endpoint, err := url.Parse(device.Token)
+	if err != nil || endpoint.Scheme != "https" {
+		return ErrDeviceGone
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": endpoint.Scheme + "://" + endpoint.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": gateway.subject,
+	})
+	signed, err := token.SignedString(gateway.privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, device.Token, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create web push request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", signed, gateway.publicKey))
+	req.Header.Set("TTL", fmt.Sprintf("%d", int(gateway.ttl.Seconds())))
+	req.Header.Set("Topic", string(notification.Type))
+
+	resp, err := gateway.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send web push request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrDeviceGone
+	case resp.StatusCode >= 300:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("web push returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// notificationData flattens a notification into the string map expected by push data payloads.
+func notificationData(notification models.Notification) map[string]string {
+	result := map[string]string{"type": string(notification.Type)}
+	for key, value := range notification.Data {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
+	"kitadoc-backend/models"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// reminderResponseDays is the time a teacher has to write an entry after a reminder before it counts as ignored.
+const reminderResponseDays = 7
+
+// QualityReportService defines the interface for the monthly documentation quality report of the management.
+type QualityReportService interface {
+	GetQualityReport(logger *logrus.Entry, ctx context.Context, month string) (*models.QualityReport, error)
+	// GetQualityReportPDF returns the archived PDF of a completed month, or generates it for a month without one.
+	GetQualityReportPDF(logger *logrus.Entry, ctx context.Context, month string) (*models.ArchivedQualityReport, error)
+	GetArchivedQualityReports(logger *logrus.Entry, ctx context.Context) ([]models.ArchivedQualityReport, error)
+}
+
+// QualityReportServiceImpl implements QualityReportService and archives the report of every completed month.
+type QualityReportServiceImpl struct {
+	groupStore              data.GroupStore
+	teacherStore            data.TeacherStore
+	assignmentStore         data.AssignmentStore
+	documentationEntryStore data.DocumentationEntryStore
+	eventStore              data.DocumentationEventStore
+	auditLogStore           data.AuditLogStore
+	kitaMasterdataStore     data.KitaMasterdataStore
+	qualityReportStore      data.QualityReportStore
+	clock                   clock.Clock
+}
+
+// NewQualityReportService creates a new QualityReportServiceImpl.
+func NewQualityReportService(
+	groupStore data.GroupStore,
+	teacherStore data.TeacherStore,
+	assignmentStore data.AssignmentStore,
+	documentationEntryStore data.DocumentationEntryStore,
+	eventStore data.DocumentationEventStore,
+	auditLogStore data.AuditLogStore,
+	kitaMasterdataStore data.KitaMasterdataStore,
+	qualityReportStore data.QualityReportStore,
+	clock clock.Clock,
+) *QualityReportServiceImpl {
+	return &QualityReportServiceImpl{
+		groupStore:              groupStore,
+		teacherStore:            teacherStore,
+		assignmentStore:         assignmentStore,
+		documentationEntryStore: documentationEntryStore,
+		eventStore:              eventStore,
+		auditLogStore:           auditLogStore,
+		kitaMasterdataStore:     kitaMasterdataStore,
+		qualityReportStore:      qualityReportStore,
+		clock:                   clock,
+	}
+}
+
+// GetQualityReport computes the quality figures of a month, given as YYYY-MM. A month in progress is
+// summarized up to now.
+func (service *QualityReportServiceImpl) GetQualityReport(logger *logrus.Entry, ctx context.Context, month string) (*models.QualityReport, error) {
+	now := service.clock.Now()
+	monthStart, err := parseReportMonth(month, now)
+	if err != nil {
+		return nil, err
+	}
+	report, err := service.buildQualityReport(ctx, monthStart, now)
+	if err != nil {
+		logger.WithError(err).WithField("month", month).Error("Error building quality report")
+		return nil, ErrInternal
+	}
+	return report, nil
+}
+
+// GetQualityReportPDF returns the archived PDF of a month, or generates one that is not archived.
+func (service *QualityReportServiceImpl) GetQualityReportPDF(logger *logrus.Entry, ctx context.Context, month string) (*models.ArchivedQualityReport, error) {
+	now := service.clock.Now()
+	monthStart, err := parseReportMonth(month, now)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := service.qualityReportStore.Get(ctx, month)
+	if err == nil {
+		return archived, nil
+	}
+	if !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).WithField("month", month).Error("Error fetching archived quality report")
+		return nil, ErrInternal
+	}
+
+	generatedBy := ""
+	if user, ok := ctx.Value(middleware.ContextKeyUser).(*models.User); ok {
+		generatedBy = user.Username
+	}
+	report, err := service.generatePDF(ctx, monthStart, now, generatedBy)
+	if err != nil {
+		logger.WithError(err).WithField("month", month).Error("Error generating quality report")
+		return nil, ErrInternal
+	}
+	return report, nil
+}
+
+// GetArchivedQualityReports fetches the archived reports without their content, newest month first.
+func (service *QualityReportServiceImpl) GetArchivedQualityReports(logger *logrus.Entry, ctx context.Context) ([]models.ArchivedQualityReport, error) {
+	reports, err := service.qualityReportStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching archived quality reports")
+		return nil, ErrInternal
+	}
+	return reports, nil
+}
+
+// ArchiveDue archives the report of the past month unless it has been archived already. A reminder of the
+// past month can still be answered in the first days of the month, so the report is archived once the
+// response time of the last reminders has passed.
+func (service *QualityReportServiceImpl) ArchiveDue(logger *logrus.Entry, ctx context.Context, now time.Time) {
+	today := models.Today(now)
+	if today.Day() <= reminderResponseDays {
+		return
+	}
+	monthStart := models.NewDate(today.Year(), today.Month(), 1).AddDate(0, -1, 0)
+	month := monthStart.Format("2006-01")
+	if _, err := service.qualityReportStore.Get(ctx, month); err == nil {
+		return
+	} else if !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).WithField("month", month).Error("Error fetching archived quality report")
+		return
+	}
+
+	report, err := service.generatePDF(ctx, models.Date{Time: monthStart}, now, "")
+	if err != nil {
+		logger.WithError(err).WithField("month", month).Error("Error generating quality report")
+		return
+	}
+	if err := service.qualityReportStore.Create(ctx, report); err != nil {
+		// Another instance archived the month in the meantime.
+		if !errors.Is(err, data.ErrConflict) {
+			logger.WithError(err).WithField("month", month).Error("Error archiving quality report")
+		}
+		return
+	}
+	logger.WithFields(logrus.Fields{"month": month, "document_id": report.DocumentID}).Info("Quality report archived")
+}
+
+// Run archives due reports every interval until the context is cancelled.
+func (service *QualityReportServiceImpl) Run(logger *logrus.Entry, ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		service.ArchiveDue(logger, ctx, service.clock.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (service *QualityReportServiceImpl) generatePDF(ctx context.Context, monthStart models.Date, now time.Time, generatedBy string) (*models.ArchivedQualityReport, error) {
+	report, err := service.buildQualityReport(ctx, monthStart, now)
+	if err != nil {
+		return nil, err
+	}
+	masterdata, err := service.kitaMasterdataStore.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stamp := reportStamp{DocumentID: uuid.NewString(), Facility: masterdata.Name, GeneratedAt: report.GeneratedAt, GeneratedBy: generatedBy}
+	content, err := renderQualityReport(report, stamp, masterdata.Terminology)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ArchivedQualityReport{
+		Month:       report.Month,
+		DocumentID:  stamp.DocumentID,
+		GeneratedAt: report.GeneratedAt,
+		SizeBytes:   len(content),
+		Content:     content,
+	}, nil
+}
+
+// parseReportMonth parses a month given as YYYY-MM that is not in the future and returns its first day.
+func parseReportMonth(month string, now time.Time) (models.Date, error) {
+	parsed, err := time.Parse("2006-01", month)
+	if err != nil {
+		return models.Date{}, fmt.Errorf("%w: invalid month %q, must be YYYY-MM", ErrInvalidInput, month)
+	}
+	if parsed.IsZero() || models.DateOf(parsed).IsAfterToday(now) {
+		return models.Date{}, fmt.Errorf("%w: month %s is in the future", ErrInvalidInput, month)
+	}
+	return models.DateOf(parsed), nil
+}
+
+// qualityTally accumulates the quality figures of the facility, a group or a teacher.
+type qualityTally struct {
+	children         map[int]bool // Assigned children
+	documented       map[int]bool // Children observed in the month, assigned or not
+	written          int
+	approved         int
+	approvalHours    []float64
+	remindersSent    int
+	remindersIgnored int
+}
+
+func newQualityTally() *qualityTally {
+	return &qualityTally{children: map[int]bool{}, documented: map[int]bool{}}
+}
+
+func (tally *qualityTally) empty() bool {
+	return len(tally.children) == 0 && tally.written == 0 && tally.approved == 0 && tally.remindersSent == 0
+}
+
+func (tally *qualityTally) figures() models.QualityFigures {
+	figures := models.QualityFigures{
+		ChildrenAssigned: len(tally.children),
+		EntriesWritten:   tally.written,
+		EntriesApproved:  tally.approved,
+		RemindersSent:    tally.remindersSent,
+		RemindersIgnored: tally.remindersIgnored,
+	}
+	for childID := range tally.children {
+		if tally.documented[childID] {
+			figures.ChildrenDocumented++
+		}
+	}
+	if figures.ChildrenAssigned > 0 {
+		coverage := roundToTenth(100 * float64(figures.ChildrenDocumented) / float64(figures.ChildrenAssigned))
+		figures.Coverage = &coverage
+	}
+	if len(tally.approvalHours) > 0 {
+		hours := slices.Sorted(slices.Values(tally.approvalHours))
+		median := hours[len(hours)/2]
+		if len(hours)%2 == 0 {
+			median = (hours[len(hours)/2-1] + median) / 2
+		}
+		median = roundToTenth(median)
+		figures.MedianApprovalHours = &median
+	}
+	return figures
+}
+
+func roundToTenth(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+// buildQualityReport computes the figures of the month starting on monthStart. Children count for the
+// teachers they were assigned to during the month and for the group they are in now, reminders count for
+// the teacher and the groups the teacher is staff of.
+func (service *QualityReportServiceImpl) buildQualityReport(ctx context.Context, monthStart models.Date, now time.Time) (*models.QualityReport, error) {
+	from := time.Date(monthStart.Year(), monthStart.Month(), 1, 0, 0, 0, 0, models.FacilityLocation())
+	to := from.AddDate(0, 1, 0)
+	inMonth := func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+	periodEnd := models.Date{Time: monthStart.AddDate(0, 1, -1)}
+	report := &models.QualityReport{
+		Month:       monthStart.Format("2006-01"),
+		PeriodStart: monthStart,
+		PeriodEnd:   periodEnd,
+		GeneratedAt: now,
+		Groups:      []models.GroupQuality{},
+		Teachers:    []models.TeacherQuality{},
+	}
+
+	groups, err := service.groupStore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teachers, err := service.teacherStore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := service.assignmentStore.GetAllAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Writing, approving and answering a reminder all update an entry, and observations of the month cannot be
+	// written before it, so entries not updated since the start of the month do not count.
+	entries, err := service.documentationEntryStore.List(ctx, models.ListQuery{}.
+		Where("updated_at", models.OperatorGreaterOrEqual, from.UTC()))
+	if err != nil {
+		return nil, err
+	}
+	reminders, err := service.auditLogStore.GetForAction(ctx, models.AuditActionSendReminder, from)
+	if err != nil {
+		return nil, err
+	}
+
+	facility := newQualityTally()
+	groupTallies := make(map[int]*qualityTally, len(groups))
+	groupOfChild := make(map[int]int)
+	groupsOfTeacher := make(map[int][]int)
+	for _, group := range groups {
+		tally := newQualityTally()
+		groupTallies[group.ID] = tally
+		for _, childID := range group.ChildIDs {
+			tally.children[childID] = true
+			groupOfChild[childID] = group.ID
+		}
+		if group.LeadTeacherID != nil {
+			groupsOfTeacher[*group.LeadTeacherID] = append(groupsOfTeacher[*group.LeadTeacherID], group.ID)
+		}
+		for _, teacherID := range group.AssistantTeacherIDs {
+			groupsOfTeacher[teacherID] = append(groupsOfTeacher[teacherID], group.ID)
+		}
+	}
+	teacherTallies := make(map[int]*qualityTally, len(teachers))
+	teacherTally := func(teacherID int) *qualityTally {
+		tally, ok := teacherTallies[teacherID]
+		if !ok {
+			tally = newQualityTally()
+			teacherTallies[teacherID] = tally
+		}
+		return tally
+	}
+	// forEntry returns the tallies an entry of a child by a teacher counts for.
+	forEntry := func(childID int, teacherID int) []*qualityTally {
+		tallies := []*qualityTally{facility, teacherTally(teacherID)}
+		if groupID, ok := groupOfChild[childID]; ok {
+			tallies = append(tallies, groupTallies[groupID])
+		}
+		return tallies
+	}
+
+	for _, assignment := range assignments {
+		if models.DateOf(assignment.StartDate).After(periodEnd.Time) ||
+			(assignment.EndDate != nil && models.DateOf(*assignment.EndDate).Before(monthStart.Time)) {
+			continue
+		}
+		facility.children[assignment.ChildID] = true
+		teacherTally(assignment.TeacherID).children[assignment.ChildID] = true
+	}
+
+	for _, entry := range entries {
+		if !entry.ObservationDate.Before(monthStart.Time) && !entry.ObservationDate.After(periodEnd.Time) {
+			facility.documented[entry.ChildID] = true
+			teacherTally(entry.TeacherID).documented[entry.ChildID] = true
+			if groupID, ok := groupOfChild[entry.ChildID]; ok {
+				groupTallies[groupID].documented[entry.ChildID] = true
+			}
+		}
+		if inMonth(entry.CreatedAt) {
+			for _, tally := range forEntry(entry.ChildID, entry.TeacherID) {
+				tally.written++
+			}
+		}
+		if !entry.IsApproved {
+			continue
+		}
+		events, err := service.eventStore.GetForEntry(ctx, entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		approvedAt, hours, ok := approvalLatency(entry, events)
+		if !ok || !inMonth(approvedAt) {
+			continue
+		}
+		for _, tally := range forEntry(entry.ChildID, entry.TeacherID) {
+			tally.approved++
+			tally.approvalHours = append(tally.approvalHours, hours)
+		}
+	}
+
+	for _, reminder := range reminders {
+		if reminder.EntityID == nil || !inMonth(reminder.CreatedAt) {
+			continue
+		}
+		teacherID := *reminder.EntityID
+		tallies := []*qualityTally{facility, teacherTally(teacherID)}
+		for _, groupID := range groupsOfTeacher[teacherID] {
+			tallies = append(tallies, groupTallies[groupID])
+		}
+		ignored := reminderIgnored(reminder.CreatedAt, teacherID, entries, now)
+		for _, tally := range tallies {
+			tally.remindersSent++
+			if ignored {
+				tally.remindersIgnored++
+			}
+		}
+	}
+
+	report.Facility = facility.figures()
+	for _, group := range groups {
+		report.Groups = append(report.Groups, models.GroupQuality{GroupID: group.ID, Name: group.Name, QualityFigures: groupTallies[group.ID].figures()})
+	}
+	for _, teacher := range teachers {
+		tally := teacherTally(teacher.ID)
+		// Deactivated teachers are only listed for months they were active in.
+		if teacher.DeactivatedAt != nil && tally.empty() {
+			continue
+		}
+		report.Teachers = append(report.Teachers, models.TeacherQuality{
+			TeacherID:      teacher.ID,
+			FirstName:      teacher.FirstName,
+			LastName:       teacher.LastName,
+			QualityFigures: tally.figures(),
+		})
+	}
+	slices.SortFunc(report.Teachers, func(a, b models.TeacherQuality) int {
+		return cmp.Or(cmp.Compare(a.LastName, b.LastName), cmp.Compare(a.FirstName, b.FirstName), cmp.Compare(a.TeacherID, b.TeacherID))
+	})
+	return report, nil
+}
+
+// approvalLatency returns when an entry was last approved and the hours since it was last submitted before,
+// or since it was created if it was approved without being submitted.
+func approvalLatency(entry models.DocumentationEntry, events []models.DocumentationEvent) (time.Time, float64, bool) {
+	var approvedAt time.Time
+	approved := false
+	start := entry.CreatedAt
+	for _, event := range events {
+		switch event.Type {
+		case models.DocumentationEventCreated:
+			start = event.CreatedAt
+		case models.DocumentationEventSubmitted:
+			start = event.CreatedAt
+		case models.DocumentationEventApproved:
+			approvedAt = event.CreatedAt
+			approved = true
+		}
+	}
+	if !approved || approvedAt.Before(start) {
+		return approvedAt, 0, approved
+	}
+	return approvedAt, approvedAt.Sub(start).Hours(), true
+}
+
+// reminderIgnored reports whether a teacher wrote no entry within reminderResponseDays after a reminder.
+// A reminder whose response time has not passed yet is not ignored.
+func reminderIgnored(remindedAt time.Time, teacherID int, entries []models.DocumentationEntry, now time.Time) bool {
+	deadline := remindedAt.AddDate(0, 0, reminderResponseDays)
+	for _, entry := range entries {
+		if entry.TeacherID == teacherID && entry.CreatedAt.After(remindedAt) && !entry.CreatedAt.After(deadline) {
+			return false
+		}
+	}
+	return now.After(deadline)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"kitadoc-backend/internal/buildinfo"
+	"kitadoc-backend/models"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// germanMonths are the month names in the title of the quality report.
+var germanMonths = [...]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}
+
+// qualityColumns are the columns of the tables of the quality report after the name, with their width in mm.
+var qualityColumns = []struct {
+	title string
+	width float64
+}{
+	{"Kinder", 20},
+	{"Dokumentiert", 26},
+	{"Abdeckung", 24},
+	{"Einträge", 22},
+	{"Freigaben", 22},
+	{"Median bis Freigabe", 36},
+	{"Erinnerungen", 26},
+	{"Ignoriert", 22},
+}
+
+const (
+	qualityNameWidth = 79
+	qualityRowHeight = 7
+)
+
+// renderQualityReport renders the quality report as a landscape A4 PDF with the stamp in the footer of every page,
+// naming the staff in the terminology of the facility.
+// The standard fonts of PDF only cover Windows-1252, which is enough for German names.
+func renderQualityReport(report *models.QualityReport, stamp reportStamp, terminology models.Terminology) ([]byte, error) {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	title := fmt.Sprintf("Qualitätsbericht Dokumentation %s %d", germanMonths[report.PeriodStart.Month()-1], report.PeriodStart.Year())
+
+	pdf.SetTitle(title, true)
+	pdf.SetAuthor(stamp.Facility, true)
+	pdf.SetCreator(buildinfo.Get().Generator(), true)
+	pdf.SetSubject(stamp.footerText(), true)
+	pdf.SetKeywords(stamp.DocumentID, true)
+	pdf.SetCreationDate(stamp.GeneratedAt)
+	pdf.SetModificationDate(stamp.GeneratedAt)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.CellFormat(0, 5, translate(stamp.footerText()), "", 0, "L", false, 0, "")
+		left, _, _, _ := pdf.GetMargins()
+		pdf.SetX(left)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Seite %d/{nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, translate(title), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.MultiCell(0, 5, translate(fmt.Sprintf("Zeitraum %s bis %s. Abdeckung: Anteil der zugeordneten Kinder mit einer Beobachtung im Monat. "+
+		"Median bis Freigabe: Stunden vom Einreichen bis zur Freigabe. Ignoriert: Erinnerungen ohne neuen Eintrag innerhalb von %d Tagen.",
+		report.PeriodStart.Format("02.01.2006"), report.PeriodEnd.Format("02.01.2006"), reminderResponseDays)), "", "L", false)
+	pdf.Ln(2)
+
+	facility := stamp.Facility
+	if facility == "" {
+		facility = "Einrichtung"
+	}
+	renderQualityTable(pdf, translate, "Einrichtung", []qualityRow{{name: facility, figures: report.Facility}})
+	groupRows := make([]qualityRow, 0, len(report.Groups))
+	for _, group := range report.Groups {
+		groupRows = append(groupRows, qualityRow{name: group.Name, figures: group.QualityFigures})
+	}
+	renderQualityTable(pdf, translate, "Gruppen", groupRows)
+	teacherRows := make([]qualityRow, 0, len(report.Teachers))
+	for _, teacher := range report.Teachers {
+		teacherRows = append(teacherRows, qualityRow{name: teacher.LastName + ", " + teacher.FirstName, figures: teacher.QualityFigures})
+	}
+	renderQualityTable(pdf, translate, terminology.Term(models.TermTeachers), teacherRows)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type qualityRow struct {
+	name    string
+	figures models.QualityFigures
+}
+
+// renderQualityTable renders a table with a row of figures per row, repeating the header on new pages.
+func renderQualityTable(pdf *gofpdf.Fpdf, translate func(string) string, heading string, rows []qualityRow) {
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 8, translate(heading), "", 1, "L", false, 0, "")
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		pdf.CellFormat(qualityNameWidth, qualityRowHeight, "Name", "1", 0, "L", true, 0, "")
+		for _, column := range qualityColumns {
+			pdf.CellFormat(column.width, qualityRowHeight, translate(column.title), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 9)
+	}
+	header()
+	if len(rows) == 0 {
+		pdf.CellFormat(0, qualityRowHeight, "Keine Daten", "1", 1, "L", false, 0, "")
+		return
+	}
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottomMargin := pdf.GetMargins()
+	for _, row := range rows {
+		if pdf.GetY()+qualityRowHeight > pageHeight-bottomMargin {
+			pdf.AddPage()
+			header()
+		}
+		pdf.CellFormat(qualityNameWidth, qualityRowHeight, translate(row.name), "1", 0, "L", false, 0, "")
+		for i, value := range qualityCells(row.figures) {
+			pdf.CellFormat(qualityColumns[i].width, qualityRowHeight, value, "1", 0, "R", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
+
+// qualityCells formats the figures in the order of qualityColumns.
+func qualityCells(figures models.QualityFigures) []string {
+	optional := func(value *float64, unit string) string {
+		if value == nil {
+			return "-"
+		}
+		return strings.Replace(strconv.FormatFloat(*value, 'f', 1, 64), ".", ",", 1) + unit
+	}
+	return []string{
+		strconv.Itoa(figures.ChildrenAssigned),
+		strconv.Itoa(figures.ChildrenDocumented),
+		optional(figures.Coverage, " %"),
+		strconv.Itoa(figures.EntriesWritten),
+		strconv.Itoa(figures.EntriesApproved),
+		optional(figures.MedianApprovalHours, " h"),
+		strconv.Itoa(figures.RemindersSent),
+		strconv.Itoa(figures.RemindersIgnored),
+	}
+}
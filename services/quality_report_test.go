@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type qualityReportMocks struct {
+	groups      *datamocks.MockGroupStore
+	teachers    *datamocks.MockTeacherStore
+	assignments *datamocks.MockAssignmentStore
+	entries     *datamocks.MockDocumentationEntryStore
+	events      *datamocks.MockDocumentationEventStore
+	auditLog    *datamocks.MockAuditLogStore
+	masterdata  *datamocks.MockKitaMasterdataStore
+	reports     *datamocks.MockQualityReportStore
+}
+
+func newQualityReportService(t *testing.T) (*services.QualityReportServiceImpl, *qualityReportMocks) {
+	models.SetFacilityLocation(time.UTC)
+	t.Cleanup(func() { models.SetFacilityLocation(time.Local) })
+
+	mocks := &qualityReportMocks{
+		groups:      new(datamocks.MockGroupStore),
+		teachers:    new(datamocks.MockTeacherStore),
+		assignments: new(datamocks.MockAssignmentStore),
+		entries:     new(datamocks.MockDocumentationEntryStore),
+		events:      new(datamocks.MockDocumentationEventStore),
+		auditLog:    new(datamocks.MockAuditLogStore),
+		masterdata:  new(datamocks.MockKitaMasterdataStore),
+		reports:     new(datamocks.MockQualityReportStore),
+	}
+	service := services.NewQualityReportService(mocks.groups, mocks.teachers, mocks.assignments, mocks.entries,
+		mocks.events, mocks.auditLog, mocks.masterdata, mocks.reports, clock.System{})
+	return service, mocks
+}
+
+// expectQualityMonth sets up March 2025 with a group of two children, of which one is assigned to and
+// documented by its teacher, an approval after a day and two reminders, of which one was ignored.
+func expectQualityMonth(mocks *qualityReportMocks) {
+	at := func(day int, hour int) time.Time { return time.Date(2025, time.March, day, hour, 0, 0, 0, time.UTC) }
+	endDate := time.Date(2025, time.February, 15, 0, 0, 0, 0, time.UTC)
+	deactivatedAt := at(1, 0)
+	teacherID := 1
+
+	mocks.groups.On("GetAll", mock.Anything).Return([]models.Group{{ID: 5, Name: "Sonnengruppe", ChildIDs: []int{10, 11}, LeadTeacherID: &teacherID}}, nil).Once()
+	mocks.teachers.On("GetAll", mock.Anything).Return([]models.Teacher{
+		{ID: 1, FirstName: "Maria", LastName: "Müller"},
+		{ID: 2, FirstName: "Jonas", LastName: "Schulz", DeactivatedAt: &deactivatedAt},
+	}, nil).Once()
+	mocks.assignments.On("GetAllAssignments", mock.Anything).Return([]models.Assignment{
+		{ChildID: 10, TeacherID: 1, StartDate: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)},
+		{ChildID: 11, TeacherID: 1, StartDate: time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{ChildID: 12, TeacherID: 1, StartDate: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC), EndDate: &endDate},
+	}, nil).Once()
+	mocks.entries.On("List", mock.Anything, mock.Anything).Return([]models.DocumentationEntry{
+		{ID: 1, ChildID: 10, TeacherID: 1, ObservationDate: models.NewDate(2025, time.March, 5), CreatedAt: at(5, 10), IsApproved: true},
+		{ID: 2, ChildID: 11, TeacherID: 1, ObservationDate: models.NewDate(2025, time.February, 27), CreatedAt: at(2, 9)},
+	}, nil).Once()
+	mocks.events.On("GetForEntry", mock.Anything, 1).Return([]models.DocumentationEvent{
+		{Type: models.DocumentationEventCreated, CreatedAt: at(5, 10)},
+		{Type: models.DocumentationEventSubmitted, CreatedAt: at(5, 12)},
+		{Type: models.DocumentationEventApproved, CreatedAt: at(6, 12)},
+	}, nil).Once()
+	mocks.auditLog.On("GetForAction", mock.Anything, models.AuditActionSendReminder, at(1, 0)).Return([]models.AuditLogEntry{
+		{Action: models.AuditActionSendReminder, EntityID: &teacherID, CreatedAt: at(1, 8)},
+		{Action: models.AuditActionSendReminder, EntityID: &teacherID, CreatedAt: at(20, 8)},
+	}, nil).Once()
+}
+
+func TestGetQualityReport(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		service, mocks := newQualityReportService(t)
+		expectQualityMonth(mocks)
+
+		report, err := service.GetQualityReport(logger, ctx, "2025-03")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.NewDate(2025, time.March, 1), report.PeriodStart)
+		assert.Equal(t, models.NewDate(2025, time.March, 31), report.PeriodEnd)
+
+		facility := report.Facility
+		assert.Equal(t, 1, facility.ChildrenAssigned)
+		assert.Equal(t, 1, facility.ChildrenDocumented)
+		assert.Equal(t, 100.0, *facility.Coverage)
+		assert.Equal(t, 2, facility.EntriesWritten)
+		assert.Equal(t, 1, facility.EntriesApproved)
+		assert.Equal(t, 24.0, *facility.MedianApprovalHours)
+		assert.Equal(t, 2, facility.RemindersSent)
+		assert.Equal(t, 1, facility.RemindersIgnored)
+
+		require.Len(t, report.Groups, 1)
+		assert.Equal(t, 2, report.Groups[0].ChildrenAssigned)
+		assert.Equal(t, 50.0, *report.Groups[0].Coverage)
+		assert.Equal(t, 2, report.Groups[0].RemindersSent)
+
+		require.Len(t, report.Teachers, 1, "deactivated teachers without activity are left out")
+		assert.Equal(t, 1, report.Teachers[0].TeacherID)
+		assert.Equal(t, 1, report.Teachers[0].ChildrenAssigned)
+		assert.Equal(t, 1, report.Teachers[0].RemindersIgnored)
+	})
+
+	t.Run("invalid month", func(t *testing.T) {
+		service, _ := newQualityReportService(t)
+
+		_, err := service.GetQualityReport(logger, ctx, "03/2025")
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+
+		_, err = service.GetQualityReport(logger, ctx, time.Now().AddDate(0, 2, 0).Format("2006-01"))
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+}
+
+func TestGetQualityReportPDF(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("archived", func(t *testing.T) {
+		service, mocks := newQualityReportService(t)
+		archived := &models.ArchivedQualityReport{Month: "2025-03", Content: []byte("%PDF-1.3")}
+		mocks.reports.On("Get", mock.Anything, "2025-03").Return(archived, nil).Once()
+
+		report, err := service.GetQualityReportPDF(logger, ctx, "2025-03")
+
+		assert.NoError(t, err)
+		assert.Same(t, archived, report)
+	})
+
+	t.Run("generated on demand", func(t *testing.T) {
+		service, mocks := newQualityReportService(t)
+		mocks.reports.On("Get", mock.Anything, "2025-03").Return(nil, data.ErrNotFound).Once()
+		mocks.masterdata.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Kita Sonnenschein"}, nil).Once()
+		expectQualityMonth(mocks)
+
+		report, err := service.GetQualityReportPDF(logger, ctx, "2025-03")
+
+		require.NoError(t, err)
+		assert.Equal(t, "2025-03", report.Month)
+		assert.NotEmpty(t, report.DocumentID)
+		assert.True(t, bytes.HasPrefix(report.Content, []byte("%PDF-")))
+		mocks.reports.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestArchiveDueQualityReports(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("archives the past month", func(t *testing.T) {
+		service, mocks := newQualityReportService(t)
+		mocks.reports.On("Get", mock.Anything, "2025-03").Return(nil, data.ErrNotFound).Once()
+		mocks.masterdata.On("Get", mock.Anything).Return(&models.KitaMasterdata{Name: "Kita Sonnenschein"}, nil).Once()
+		expectQualityMonth(mocks)
+		mocks.reports.On("Create", mock.Anything, mock.MatchedBy(func(report *models.ArchivedQualityReport) bool {
+			return report.Month == "2025-03" && bytes.HasPrefix(report.Content, []byte("%PDF-"))
+		})).Return(nil).Once()
+
+		service.ArchiveDue(logger, ctx, time.Date(2025, time.April, 10, 6, 0, 0, 0, time.UTC))
+
+		mocks.reports.AssertExpectations(t)
+	})
+
+	t.Run("waits for the response time of reminders", func(t *testing.T) {
+		service, mocks := newQualityReportService(t)
+
+		service.ArchiveDue(logger, ctx, time.Date(2025, time.April, 3, 6, 0, 0, 0, time.UTC))
+
+		mocks.reports.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+
+	t.Run("already archived", func(t *testing.T) {
+		service, mocks := newQualityReportService(t)
+		mocks.reports.On("Get", mock.Anything, "2025-03").Return(&models.ArchivedQualityReport{Month: "2025-03"}, nil).Once()
+
+		service.ArchiveDue(logger, ctx, time.Date(2025, time.April, 10, 6, 0, 0, 0, time.UTC))
+
+		mocks.reports.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// QueryPlanService defines the interface for reviewing how the database executes the hot queries.
+type QueryPlanService interface {
+	GetQueryPlans(logger *logrus.Entry, ctx context.Context) ([]models.QueryPlan, error)
+}
+
+// QueryPlanServiceImpl implements QueryPlanService.
+type QueryPlanServiceImpl struct {
+	queryPlanStore data.QueryPlanStore
+}
+
+// NewQueryPlanService creates a new QueryPlanServiceImpl.
+func NewQueryPlanService(queryPlanStore data.QueryPlanStore) *QueryPlanServiceImpl {
+	return &QueryPlanServiceImpl{queryPlanStore: queryPlanStore}
+}
+
+// GetQueryPlans fetches the query plans of the hot queries. Plans that read a whole table are logged,
+// they usually point to a missing index.
+func (service *QueryPlanServiceImpl) GetQueryPlans(logger *logrus.Entry, ctx context.Context) ([]models.QueryPlan, error) {
+	plans, err := service.queryPlanStore.ExplainHotQueries(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error explaining hot queries")
+		return nil, ErrInternal
+	}
+	for _, plan := range plans {
+		if plan.FullScan {
+			logger.WithField("query", plan.Name).Warn("Hot query scans a whole table")
+		}
+	}
+	return plans, nil
+}
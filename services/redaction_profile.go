@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactionProfileService defines the interface for report redaction profile operations.
+type RedactionProfileService interface {
+	CreateRedactionProfile(logger *logrus.Entry, ctx context.Context, profile *models.RedactionProfile) (*models.RedactionProfile, error)
+	GetRedactionProfileByID(logger *logrus.Entry, ctx context.Context, id int) (*models.RedactionProfile, error)
+	GetAllRedactionProfiles(logger *logrus.Entry, ctx context.Context) ([]models.RedactionProfile, error)
+	UpdateRedactionProfile(logger *logrus.Entry, ctx context.Context, profile *models.RedactionProfile) error
+	DeleteRedactionProfile(logger *logrus.Entry, ctx context.Context, id int) error
+}
+
+// RedactionProfileServiceImpl implements RedactionProfileService.
+type RedactionProfileServiceImpl struct {
+	redactionProfileStore data.RedactionProfileStore
+}
+
+// NewRedactionProfileService creates a new RedactionProfileServiceImpl.
+func NewRedactionProfileService(redactionProfileStore data.RedactionProfileStore) *RedactionProfileServiceImpl {
+	return &RedactionProfileServiceImpl{
+		redactionProfileStore: redactionProfileStore,
+	}
+}
+
+// CreateRedactionProfile creates a new redaction profile.
+func (service *RedactionProfileServiceImpl) CreateRedactionProfile(logger *logrus.Entry, ctx context.Context, profile *models.RedactionProfile) (*models.RedactionProfile, error) {
+	if err := models.ValidateRedactionProfile(*profile); err != nil {
+		logger.WithError(err).Warn("Invalid input for CreateRedactionProfile")
+		return nil, invalidInput(err)
+	}
+
+	id, err := service.redactionProfileStore.Create(ctx, profile)
+	if err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			logger.WithField("name", profile.Name).Warn("Redaction profile with this name already exists")
+			return nil, ErrAlreadyExists
+		}
+		logger.WithError(err).Error("Error creating redaction profile")
+		return nil, ErrInternal
+	}
+
+	created, err := service.redactionProfileStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("redaction_profile_id", id).Error("Error fetching created redaction profile")
+		return nil, ErrInternal
+	}
+	logger.WithField("redaction_profile_id", id).Info("Redaction profile created successfully")
+	return created, nil
+}
+
+// GetRedactionProfileByID fetches a redaction profile by ID.
+func (service *RedactionProfileServiceImpl) GetRedactionProfileByID(logger *logrus.Entry, ctx context.Context, id int) (*models.RedactionProfile, error) {
+	profile, err := service.redactionProfileStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("redaction_profile_id", id).Error("Error fetching redaction profile")
+		return nil, ErrInternal
+	}
+	return profile, nil
+}
+
+// GetAllRedactionProfiles fetches all redaction profiles.
+func (service *RedactionProfileServiceImpl) GetAllRedactionProfiles(logger *logrus.Entry, ctx context.Context) ([]models.RedactionProfile, error) {
+	profiles, err := service.redactionProfileStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching redaction profiles")
+		return nil, ErrInternal
+	}
+	return profiles, nil
+}
+
+// UpdateRedactionProfile updates an existing redaction profile.
+func (service *RedactionProfileServiceImpl) UpdateRedactionProfile(logger *logrus.Entry, ctx context.Context, profile *models.RedactionProfile) error {
+	if err := models.ValidateRedactionProfile(*profile); err != nil {
+		logger.WithError(err).Warn("Invalid input for UpdateRedactionProfile")
+		return invalidInput(err)
+	}
+
+	if err := service.redactionProfileStore.Update(ctx, profile); err != nil {
+		switch {
+		case errors.Is(err, data.ErrNotFound):
+			return ErrNotFound
+		case errors.Is(err, data.ErrConflict):
+			return ErrAlreadyExists
+		}
+		logger.WithError(err).WithField("redaction_profile_id", profile.ID).Error("Error updating redaction profile")
+		return ErrInternal
+	}
+	logger.WithField("redaction_profile_id", profile.ID).Info("Redaction profile updated successfully")
+	return nil
+}
+
+// DeleteRedactionProfile deletes a redaction profile.
+func (service *RedactionProfileServiceImpl) DeleteRedactionProfile(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.redactionProfileStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("redaction_profile_id", id).Error("Error deleting redaction profile")
+		return ErrInternal
+	}
+	logger.WithField("redaction_profile_id", id).Info("Redaction profile deleted successfully")
+	return nil
+}
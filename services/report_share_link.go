@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ReportShareLinkService defines the interface for sharing archived reports read-only, e.g. with a
+// Frühförderstelle, instead of sending them as email attachments.
+type ReportShareLinkService interface {
+	// CreateShareLink creates a share link to an archived report of a child, valid for validity or, if 0, the
+	// configured maximum. The token and the access code are only returned here.
+	CreateShareLink(logger *logrus.Entry, ctx context.Context, childID int, reportID int, recipient string, validity time.Duration, createdByUserID int) (*models.ReportShareLinkCreated, error)
+	GetShareLinksForReport(logger *logrus.Entry, ctx context.Context, childID int, reportID int) ([]models.ReportShareLink, error)
+	GetShareLinkAccesses(logger *logrus.Entry, ctx context.Context, id int) ([]models.ReportShareLinkAccess, error)
+	RevokeShareLink(logger *logrus.Entry, ctx context.Context, id int, actingUserID int) error
+	// OpenSharedReport returns the report of a share link if the access code matches. Every attempt is logged,
+	// and the link is revoked after too many wrong access codes.
+	OpenSharedReport(logger *logrus.Entry, ctx context.Context, token string, accessCode string) (*models.GeneratedReport, error)
+}
+
+// ReportShareLinkServiceImpl implements ReportShareLinkService.
+type ReportShareLinkServiceImpl struct {
+	shareLinkStore          data.ReportShareLinkStore
+	documentationEntryStore data.DocumentationEntryStore
+	auditLogService         AuditLogService
+	config                  *config.Config
+	clock                   clock.Clock
+}
+
+// NewReportShareLinkService creates a new ReportShareLinkServiceImpl.
+func NewReportShareLinkService(shareLinkStore data.ReportShareLinkStore, documentationEntryStore data.DocumentationEntryStore, auditLogService AuditLogService, cfg *config.Config, clock clock.Clock) *ReportShareLinkServiceImpl {
+	return &ReportShareLinkServiceImpl{
+		shareLinkStore:          shareLinkStore,
+		documentationEntryStore: documentationEntryStore,
+		auditLogService:         auditLogService,
+		config:                  cfg,
+		clock:                   clock,
+	}
+}
+
+// accessCodeAlphabet leaves out characters that are easily confused when the code is read out on the phone.
+const accessCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// accessCodeLength is the number of characters of an access code.
+const accessCodeLength = 8
+
+// newAccessCode returns a random access code.
+func newAccessCode() (string, error) {
+	randomBytes := make([]byte, accessCodeLength)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	code := make([]byte, accessCodeLength)
+	for i, randomByte := range randomBytes {
+		// 256 is a multiple of the 32 characters, so every character is equally likely.
+		code[i] = accessCodeAlphabet[int(randomByte)%len(accessCodeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// normalizeAccessCode accepts codes typed in lower case or with spaces and dashes.
+func normalizeAccessCode(accessCode string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(accessCode))
+}
+
+// CreateShareLink creates a new share link.
+func (service *ReportShareLinkServiceImpl) CreateShareLink(logger *logrus.Entry, ctx context.Context, childID int, reportID int, recipient string, validity time.Duration, createdByUserID int) (*models.ReportShareLinkCreated, error) {
+	if _, err := service.archivedReport(logger, ctx, childID, reportID); err != nil {
+		return nil, err
+	}
+	if validity == 0 {
+		validity = service.config.Sharing.LinkValidity
+	}
+	if validity < 0 {
+		return nil, ErrInvalidInput
+	}
+	if validity > service.config.Sharing.LinkValidity {
+		logger.WithField("validity", validity).Warn("Share link validity exceeds the configured maximum")
+		return nil, ErrShareLinkTooLong
+	}
+
+	token, err := newLinkToken()
+	if err != nil {
+		logger.WithError(err).Error("Error generating share link token")
+		return nil, ErrInternal
+	}
+	accessCode, err := newAccessCode()
+	if err != nil {
+		logger.WithError(err).Error("Error generating share link access code")
+		return nil, ErrInternal
+	}
+	accessCodeHash, err := bcrypt.GenerateFromPassword([]byte(accessCode), bcrypt.DefaultCost)
+	if err != nil {
+		logger.WithError(err).Error("Error hashing share link access code")
+		return nil, ErrInternal
+	}
+
+	now := service.clock.Now()
+	link := &models.ReportShareLink{
+		ReportID:        reportID,
+		Recipient:       strings.TrimSpace(recipient),
+		CreatedByUserID: &createdByUserID,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(validity),
+		TokenHash:       hashLinkToken(token),
+		AccessCodeHash:  string(accessCodeHash),
+	}
+	if err := models.ValidateReportShareLink(*link); err != nil {
+		logger.WithError(err).Warn("Invalid input for CreateShareLink")
+		return nil, invalidInput(err)
+	}
+
+	id, err := service.shareLinkStore.Create(ctx, link)
+	if err != nil {
+		logger.WithError(err).Error("Error creating share link")
+		return nil, ErrInternal
+	}
+	created, err := service.shareLinkStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("share_link_id", id).Error("Error fetching created share link")
+		return nil, ErrInternal
+	}
+
+	// The link is only handed out once it is recorded.
+	if err := service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:      models.AuditActionCreateShareLink,
+		EntityType:  "report_share_link",
+		EntityID:    &id,
+		ActorUserID: &createdByUserID,
+		Details:     fmt.Sprintf("report_id=%d child_id=%d expires_at=%s", reportID, childID, created.ExpiresAt.Format(time.RFC3339)),
+	}); err != nil {
+		return nil, err
+	}
+
+	result := &models.ReportShareLinkCreated{ShareLink: created, Token: token, AccessCode: accessCode}
+	if service.config.Sharing.ShareURL != "" {
+		shareURL, err := url.Parse(service.config.Sharing.ShareURL)
+		if err != nil {
+			logger.WithError(err).Error("Invalid share URL configured for share links")
+			return nil, ErrInternal
+		}
+		query := shareURL.Query()
+		query.Set("token", token)
+		shareURL.RawQuery = query.Encode()
+		result.ShareURL = shareURL.String()
+	}
+	logger.WithFields(logrus.Fields{
+		"share_link_id":      id,
+		"report_id":          reportID,
+		"created_by_user_id": createdByUserID,
+	}).Info("Share link created successfully")
+	return result, nil
+}
+
+// GetShareLinksForReport fetches the share links of an archived report, revoked and expired ones included.
+func (service *ReportShareLinkServiceImpl) GetShareLinksForReport(logger *logrus.Entry, ctx context.Context, childID int, reportID int) ([]models.ReportShareLink, error) {
+	if _, err := service.archivedReport(logger, ctx, childID, reportID); err != nil {
+		return nil, err
+	}
+	links, err := service.shareLinkStore.GetForReport(ctx, reportID)
+	if err != nil {
+		logger.WithError(err).WithField("report_id", reportID).Error("Error fetching share links")
+		return nil, ErrInternal
+	}
+	return links, nil
+}
+
+// GetShareLinkAccesses fetches the access log of a share link.
+func (service *ReportShareLinkServiceImpl) GetShareLinkAccesses(logger *logrus.Entry, ctx context.Context, id int) ([]models.ReportShareLinkAccess, error) {
+	if _, err := service.shareLinkStore.GetByID(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrShareLinkNotFound
+		}
+		logger.WithError(err).WithField("share_link_id", id).Error("Error fetching share link")
+		return nil, ErrInternal
+	}
+	accesses, err := service.shareLinkStore.GetAccesses(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("share_link_id", id).Error("Error fetching share link accesses")
+		return nil, ErrInternal
+	}
+	return accesses, nil
+}
+
+// RevokeShareLink revokes a share link. Revoking a revoked link succeeds, the first revocation is kept.
+func (service *ReportShareLinkServiceImpl) RevokeShareLink(logger *logrus.Entry, ctx context.Context, id int, actingUserID int) error {
+	link, err := service.shareLinkStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrShareLinkNotFound
+		}
+		logger.WithError(err).WithField("share_link_id", id).Error("Error fetching share link")
+		return ErrInternal
+	}
+	if link.RevokedAt != nil {
+		return nil
+	}
+	if err := service.shareLinkStore.Revoke(ctx, id, service.clock.Now()); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil // Revoked concurrently
+		}
+		logger.WithError(err).WithField("share_link_id", id).Error("Error revoking share link")
+		return ErrInternal
+	}
+
+	// The link is revoked either way, so a failing audit write is only logged.
+	_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:      models.AuditActionRevokeShareLink,
+		EntityType:  "report_share_link",
+		EntityID:    &id,
+		ActorUserID: &actingUserID,
+		Details:     fmt.Sprintf("report_id=%d", link.ReportID),
+	})
+	logger.WithFields(logrus.Fields{"share_link_id": id, "user_id": actingUserID}).Info("Share link revoked")
+	return nil
+}
+
+// OpenSharedReport checks the token and the access code of a share link and returns its report.
+func (service *ReportShareLinkServiceImpl) OpenSharedReport(logger *logrus.Entry, ctx context.Context, token string, accessCode string) (*models.GeneratedReport, error) {
+	link, err := service.shareLinkStore.GetByTokenHash(ctx, hashLinkToken(token))
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.Warn("Attempt to open a share link with an unknown token")
+			return nil, ErrShareLinkNotFound
+		}
+		logger.WithError(err).Error("Error fetching share link by token")
+		return nil, ErrInternal
+	}
+	logger = logger.WithField("share_link_id", link.ID)
+	now := service.clock.Now()
+
+	switch {
+	case link.RevokedAt != nil:
+		service.recordAccess(logger, ctx, link.ID, now, models.ShareLinkAccessRevoked)
+		logger.Warn("Attempt to open a revoked share link")
+		return nil, ErrShareLinkRevoked
+	case !now.Before(link.ExpiresAt):
+		service.recordAccess(logger, ctx, link.ID, now, models.ShareLinkAccessExpired)
+		logger.Warn("Attempt to open an expired share link")
+		return nil, ErrShareLinkExpired
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(link.AccessCodeHash), []byte(normalizeAccessCode(accessCode))); err != nil {
+		service.recordAccess(logger, ctx, link.ID, now, models.ShareLinkAccessWrongCode)
+		logger.Warn("Attempt to open a share link with a wrong access code")
+		service.revokeAfterFailedAttempts(logger, ctx, link.ID, now)
+		return nil, ErrInvalidAccessCode
+	}
+
+	report, err := service.documentationEntryStore.GetReportByID(ctx, link.ReportID)
+	if err != nil {
+		logger.WithError(err).WithField("report_id", link.ReportID).Error("Error fetching shared report")
+		return nil, ErrInternal
+	}
+	// The report is only handed out once the access is logged.
+	if err := service.shareLinkStore.RecordAccess(ctx, &models.ReportShareLinkAccess{ShareLinkID: link.ID, AccessedAt: now, Outcome: models.ShareLinkAccessGranted}); err != nil {
+		logger.WithError(err).Error("Error logging share link access")
+		return nil, ErrInternal
+	}
+	logger.WithField("report_id", link.ReportID).Info("Shared report opened")
+	return report, nil
+}
+
+// archivedReport fetches an archived report of a child, reports of other children and reports that were not
+// archived are not found.
+func (service *ReportShareLinkServiceImpl) archivedReport(logger *logrus.Entry, ctx context.Context, childID int, reportID int) (*models.GeneratedReport, error) {
+	report, err := service.documentationEntryStore.GetReportByID(ctx, reportID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("report_id", reportID).Error("Error fetching generated report for sharing")
+		return nil, ErrInternal
+	}
+	if report.ChildID != childID || !report.Archived {
+		logger.WithFields(logrus.Fields{"report_id": reportID, "child_id": childID}).Warn("Generated report cannot be shared for child")
+		return nil, ErrNotFound
+	}
+	return report, nil
+}
+
+// recordAccess logs a denied access. The access is denied anyway, so a failing write is only logged.
+func (service *ReportShareLinkServiceImpl) recordAccess(logger *logrus.Entry, ctx context.Context, id int, now time.Time, outcome string) {
+	if err := service.shareLinkStore.RecordAccess(ctx, &models.ReportShareLinkAccess{ShareLinkID: id, AccessedAt: now, Outcome: outcome}); err != nil {
+		logger.WithError(err).WithField("outcome", outcome).Error("Error logging share link access")
+	}
+}
+
+// revokeAfterFailedAttempts revokes a link once the access code was wrong too often, so that it cannot be guessed.
+func (service *ReportShareLinkServiceImpl) revokeAfterFailedAttempts(logger *logrus.Entry, ctx context.Context, id int, now time.Time) {
+	failed, err := service.shareLinkStore.CountFailedAccesses(ctx, id)
+	if err != nil {
+		logger.WithError(err).Error("Error counting failed share link accesses")
+		return
+	}
+	if failed < service.config.Sharing.MaxFailedAttempts {
+		return
+	}
+	if err := service.shareLinkStore.Revoke(ctx, id, now); err != nil && !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).Error("Error revoking share link after failed accesses")
+		return
+	}
+	logger.WithField("failed_attempts", failed).Warn("Share link revoked after too many wrong access codes")
+}
@@ -0,0 +1,214 @@
+package services_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func shareLinkTestConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Sharing.LinkValidity = 14 * 24 * time.Hour
+	cfg.Sharing.MaxFailedAttempts = 3
+	cfg.Sharing.ShareURL = "https://kita.example/shared"
+	return cfg
+}
+
+func TestCreateShareLink(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+	report := &models.GeneratedReport{ID: 7, ChildID: 2, Archived: true}
+
+	setup := func() (*services.ReportShareLinkServiceImpl, *datamocks.MockReportShareLinkStore, *datamocks.MockDocumentationEntryStore, *datamocks.MockAuditLogStore) {
+		mockShareLinkStore := new(datamocks.MockReportShareLinkStore)
+		mockEntryStore := new(datamocks.MockDocumentationEntryStore)
+		mockAuditLogStore := new(datamocks.MockAuditLogStore)
+		service := services.NewReportShareLinkService(mockShareLinkStore, mockEntryStore, services.NewAuditLogService(mockAuditLogStore), shareLinkTestConfig(), clock.NewFrozen(now))
+		return service, mockShareLinkStore, mockEntryStore, mockAuditLogStore
+	}
+
+	t.Run("success", func(t *testing.T) {
+		service, mockShareLinkStore, mockEntryStore, mockAuditLogStore := setup()
+		mockEntryStore.On("GetReportByID", mock.Anything, 7).Return(report, nil).Once()
+		var stored *models.ReportShareLink
+		mockShareLinkStore.On("Create", mock.Anything, mock.AnythingOfType("*models.ReportShareLink")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.ReportShareLink)
+		}).Return(4, nil).Once()
+		mockShareLinkStore.On("GetByID", mock.Anything, 4).Return(&models.ReportShareLink{ID: 4, ReportID: 7, ExpiresAt: now.Add(7 * 24 * time.Hour)}, nil).Once()
+		mockAuditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionCreateShareLink && *entry.EntityID == 4 && *entry.ActorUserID == 1
+		})).Return(1, nil).Once()
+
+		created, err := service.CreateShareLink(logger, ctx, 2, 7, " Frühförderstelle Musterstadt ", 7*24*time.Hour, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Frühförderstelle Musterstadt", stored.Recipient)
+		assert.Equal(t, tokenHash(created.Token), stored.TokenHash, "only the hash of the token is stored")
+		assert.Len(t, created.AccessCode, 8)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(stored.AccessCodeHash), []byte(created.AccessCode)))
+		assert.True(t, stored.ExpiresAt.Equal(now.Add(7*24*time.Hour)))
+		shareURL, err := url.Parse(created.ShareURL)
+		require.NoError(t, err)
+		assert.Equal(t, created.Token, shareURL.Query().Get("token"))
+		mockShareLinkStore.AssertExpectations(t)
+		mockAuditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("validity defaults to and is limited by the configuration", func(t *testing.T) {
+		service, mockShareLinkStore, mockEntryStore, mockAuditLogStore := setup()
+		mockEntryStore.On("GetReportByID", mock.Anything, 7).Return(report, nil)
+		var stored *models.ReportShareLink
+		mockShareLinkStore.On("Create", mock.Anything, mock.AnythingOfType("*models.ReportShareLink")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.ReportShareLink)
+		}).Return(4, nil).Once()
+		mockShareLinkStore.On("GetByID", mock.Anything, 4).Return(&models.ReportShareLink{ID: 4}, nil).Once()
+		mockAuditLogStore.On("Create", mock.Anything, mock.Anything).Return(1, nil).Once()
+
+		_, err := service.CreateShareLink(logger, ctx, 2, 7, "Logopädie Praxis Weber", 0, 1)
+		require.NoError(t, err)
+		assert.True(t, stored.ExpiresAt.Equal(now.Add(14*24*time.Hour)))
+
+		_, err = service.CreateShareLink(logger, ctx, 2, 7, "Logopädie Praxis Weber", 15*24*time.Hour, 1)
+		assert.ErrorIs(t, err, services.ErrShareLinkTooLong)
+	})
+
+	t.Run("only archived reports of the child are shared", func(t *testing.T) {
+		service, _, mockEntryStore, _ := setup()
+		mockEntryStore.On("GetReportByID", mock.Anything, 7).Return(report, nil).Once()
+		mockEntryStore.On("GetReportByID", mock.Anything, 8).Return(&models.GeneratedReport{ID: 8, ChildID: 2}, nil).Once()
+		mockEntryStore.On("GetReportByID", mock.Anything, 9).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.CreateShareLink(logger, ctx, 3, 7, "Frühförderstelle", 0, 1)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		_, err = service.CreateShareLink(logger, ctx, 2, 8, "Frühförderstelle", 0, 1)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+		_, err = service.CreateShareLink(logger, ctx, 2, 9, "Frühförderstelle", 0, 1)
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+
+	t.Run("recipient is required", func(t *testing.T) {
+		service, _, mockEntryStore, _ := setup()
+		mockEntryStore.On("GetReportByID", mock.Anything, 7).Return(report, nil).Once()
+
+		_, err := service.CreateShareLink(logger, ctx, 2, 7, "  ", 0, 1)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+}
+
+func TestOpenSharedReport(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+	token := "share-token"
+	accessCodeHash, err := bcrypt.GenerateFromPassword([]byte("ABCD2345"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	setup := func(link *models.ReportShareLink) (*services.ReportShareLinkServiceImpl, *datamocks.MockReportShareLinkStore, *datamocks.MockDocumentationEntryStore) {
+		mockShareLinkStore := new(datamocks.MockReportShareLinkStore)
+		mockEntryStore := new(datamocks.MockDocumentationEntryStore)
+		service := services.NewReportShareLinkService(mockShareLinkStore, mockEntryStore, services.NewAuditLogService(new(datamocks.MockAuditLogStore)), shareLinkTestConfig(), clock.NewFrozen(now))
+		mockShareLinkStore.On("GetByTokenHash", mock.Anything, tokenHash(token)).Return(link, nil).Maybe()
+		return service, mockShareLinkStore, mockEntryStore
+	}
+	activeLink := func() *models.ReportShareLink {
+		return &models.ReportShareLink{ID: 4, ReportID: 7, ExpiresAt: now.Add(time.Hour), AccessCodeHash: string(accessCodeHash)}
+	}
+	accessWith := func(outcome string) any {
+		return mock.MatchedBy(func(access *models.ReportShareLinkAccess) bool {
+			return access.ShareLinkID == 4 && access.Outcome == outcome && access.AccessedAt.Equal(now)
+		})
+	}
+
+	t.Run("access code is checked and the access logged", func(t *testing.T) {
+		service, mockShareLinkStore, mockEntryStore := setup(activeLink())
+		mockEntryStore.On("GetReportByID", mock.Anything, 7).Return(&models.GeneratedReport{ID: 7, Content: []byte("report")}, nil).Once()
+		mockShareLinkStore.On("RecordAccess", mock.Anything, accessWith(models.ShareLinkAccessGranted)).Return(nil).Once()
+
+		report, err := service.OpenSharedReport(logger, ctx, token, "abcd-2345")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("report"), report.Content)
+		mockShareLinkStore.AssertExpectations(t)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		service, mockShareLinkStore, _ := setup(nil)
+		mockShareLinkStore.On("GetByTokenHash", mock.Anything, tokenHash("unknown")).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.OpenSharedReport(logger, ctx, "unknown", "ABCD2345")
+		assert.ErrorIs(t, err, services.ErrShareLinkNotFound)
+	})
+
+	t.Run("revoked and expired links are logged and denied", func(t *testing.T) {
+		revoked := activeLink()
+		revokedAt := now.Add(-time.Minute)
+		revoked.RevokedAt = &revokedAt
+		service, mockShareLinkStore, _ := setup(revoked)
+		mockShareLinkStore.On("RecordAccess", mock.Anything, accessWith(models.ShareLinkAccessRevoked)).Return(nil).Once()
+		_, err := service.OpenSharedReport(logger, ctx, token, "ABCD2345")
+		assert.ErrorIs(t, err, services.ErrShareLinkRevoked)
+		mockShareLinkStore.AssertExpectations(t)
+
+		expired := activeLink()
+		expired.ExpiresAt = now
+		service, mockShareLinkStore, _ = setup(expired)
+		mockShareLinkStore.On("RecordAccess", mock.Anything, accessWith(models.ShareLinkAccessExpired)).Return(nil).Once()
+		_, err = service.OpenSharedReport(logger, ctx, token, "ABCD2345")
+		assert.ErrorIs(t, err, services.ErrShareLinkExpired)
+		mockShareLinkStore.AssertExpectations(t)
+	})
+
+	t.Run("wrong access codes revoke the link", func(t *testing.T) {
+		service, mockShareLinkStore, _ := setup(activeLink())
+		mockShareLinkStore.On("RecordAccess", mock.Anything, accessWith(models.ShareLinkAccessWrongCode)).Return(nil).Twice()
+		mockShareLinkStore.On("CountFailedAccesses", mock.Anything, 4).Return(2, nil).Once()
+		_, err := service.OpenSharedReport(logger, ctx, token, "ABCD2346")
+		assert.ErrorIs(t, err, services.ErrInvalidAccessCode)
+		mockShareLinkStore.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+
+		mockShareLinkStore.On("CountFailedAccesses", mock.Anything, 4).Return(3, nil).Once()
+		mockShareLinkStore.On("Revoke", mock.Anything, 4, now).Return(nil).Once()
+		_, err = service.OpenSharedReport(logger, ctx, token, "ABCD2347")
+		assert.ErrorIs(t, err, services.ErrInvalidAccessCode)
+		mockShareLinkStore.AssertExpectations(t)
+	})
+}
+
+func TestRevokeShareLink(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+
+	mockShareLinkStore := new(datamocks.MockReportShareLinkStore)
+	mockAuditLogStore := new(datamocks.MockAuditLogStore)
+	service := services.NewReportShareLinkService(mockShareLinkStore, new(datamocks.MockDocumentationEntryStore), services.NewAuditLogService(mockAuditLogStore), shareLinkTestConfig(), clock.NewFrozen(now))
+
+	mockShareLinkStore.On("GetByID", mock.Anything, 4).Return(&models.ReportShareLink{ID: 4, ReportID: 7}, nil).Once()
+	mockShareLinkStore.On("Revoke", mock.Anything, 4, now).Return(nil).Once()
+	mockAuditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+		return entry.Action == models.AuditActionRevokeShareLink && *entry.EntityID == 4 && *entry.ActorUserID == 1
+	})).Return(1, nil).Once()
+	require.NoError(t, service.RevokeShareLink(logger, ctx, 4, 1))
+
+	// Revoking again keeps the first revocation
+	mockShareLinkStore.On("GetByID", mock.Anything, 4).Return(&models.ReportShareLink{ID: 4, ReportID: 7, RevokedAt: &now}, nil).Once()
+	require.NoError(t, service.RevokeShareLink(logger, ctx, 4, 1))
+
+	mockShareLinkStore.On("GetByID", mock.Anything, 5).Return(nil, data.ErrNotFound).Once()
+	assert.ErrorIs(t, service.RevokeShareLink(logger, ctx, 5, 1), services.ErrShareLinkNotFound)
+	mockShareLinkStore.AssertExpectations(t)
+	mockAuditLogStore.AssertExpectations(t)
+}
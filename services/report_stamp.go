@@ -0,0 +1,109 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"kitadoc-backend/internal/buildinfo"
+
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+const (
+	footerPartName         = "word/footer1.xml"
+	footerContentType      = "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"
+	footerRelationshipType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer"
+	corePropertiesPartName = "docProps/core.xml"
+	appPropertiesPartName  = "docProps/app.xml"
+)
+
+// reportStamp identifies a generated report, so that circulating copies can be traced back to the archive.
+type reportStamp struct {
+	DocumentID  string
+	Title       string // Title in the core properties of Word documents
+	Facility    string
+	GeneratedAt time.Time
+	// GeneratedBy is the username of the generating user, empty if it must not appear in the document.
+	GeneratedBy string
+}
+
+// footerText is the line printed in the footer of every page.
+func (stamp reportStamp) footerText() string {
+	parts := []string{}
+	if stamp.Facility != "" {
+		parts = append(parts, stamp.Facility)
+	}
+	generated := "Erstellt am " + stamp.GeneratedAt.Format("02.01.2006 15:04")
+	if stamp.GeneratedBy != "" {
+		generated += " von " + stamp.GeneratedBy
+	}
+	parts = append(parts, generated, "Dokument-ID: "+stamp.DocumentID)
+	return strings.Join(parts, " · ")
+}
+
+// stampReport adds the stamp to the footer and the core properties of the document, and the build of the
+// server to the application properties.
+// godocx has no API for either, so the parts are written directly into the package.
+func stampReport(document *docx.RootDoc, stamp reportStamp) error {
+	footer, err := xmlDocument(`<w:ftr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+		`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:rPr><w:sz w:val="16"/></w:rPr><w:t xml:space="preserve">%s</w:t></w:r></w:p></w:ftr>`,
+		stamp.footerText())
+	if err != nil {
+		return err
+	}
+	document.FileMap.Store(footerPartName, footer)
+	if err := document.ContentType.AddOverride("/"+footerPartName, footerContentType); err != nil {
+		return err
+	}
+	relationshipID := fmt.Sprintf("rId%d", document.Document.IncRelationID())
+	document.Document.DocRels.Relationships = append(document.Document.DocRels.Relationships, &docx.Relationship{
+		ID:     relationshipID,
+		Type:   footerRelationshipType,
+		Target: "footer1.xml",
+	})
+	if document.Document.Body.SectPr == nil {
+		document.Document.Body.SectPr = ctypes.NewSectionProper()
+	}
+	document.Document.Body.SectPr.FooterReference = &ctypes.FooterReference{Type: stypes.HdrFtrDefault, ID: relationshipID}
+
+	generatedAt := stamp.GeneratedAt.UTC().Format(time.RFC3339)
+	coreProperties, err := xmlDocument(`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" `+
+		`xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">`+
+		`<dc:title>%s</dc:title><dc:creator>%s</dc:creator><cp:lastModifiedBy>%s</cp:lastModifiedBy>`+
+		`<dc:description>%s</dc:description><dc:identifier>%s</dc:identifier><cp:revision>1</cp:revision>`+
+		`<dcterms:created xsi:type="dcterms:W3CDTF">%s</dcterms:created><dcterms:modified xsi:type="dcterms:W3CDTF">%s</dcterms:modified>`+
+		`</cp:coreProperties>`,
+		stamp.Title, stamp.Facility, stamp.GeneratedBy, stamp.footerText(), stamp.DocumentID, generatedAt, generatedAt)
+	if err != nil {
+		return err
+	}
+	document.FileMap.Store(corePropertiesPartName, coreProperties)
+
+	// Replaces the properties of the template, which name the word processor it was created with.
+	appProperties, err := xmlDocument(`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties">`+
+		`<Application>%s</Application><Company>%s</Company></Properties>`,
+		buildinfo.Get().Generator(), stamp.Facility)
+	if err != nil {
+		return err
+	}
+	document.FileMap.Store(appPropertiesPartName, appProperties)
+	return nil
+}
+
+// xmlDocument formats an XML part with the escaped values and prepends the XML declaration.
+func xmlDocument(format string, values ...string) ([]byte, error) {
+	escaped := make([]any, len(values))
+	for i, value := range values {
+		var buf bytes.Buffer
+		if err := xml.EscapeText(&buf, []byte(value)); err != nil {
+			return nil, err
+		}
+		escaped[i] = buf.String()
+	}
+	return []byte(xml.Header + fmt.Sprintf(format, escaped...)), nil
+}
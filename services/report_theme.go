@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"os"
+	"slices"
+	"strings"
+
+	"kitadoc-backend/models"
+
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+const (
+	logoWidth     = units.Inch(1.5)
+	logoMaxHeight = units.Inch(1)
+
+	reportPhotoWidth     = units.Inch(4.5)
+	reportPhotoMaxHeight = units.Inch(3.5)
+)
+
+// accentStyleIDs are the styles of the template that take the accent color of the theme.
+var accentStyleIDs = []string{"Title", "TitleChar", "Heading1", "Heading1Char", "Heading2", "Heading2Char", "Heading3", "Heading3Char"}
+
+// applyDocumentTheme applies the fonts and colors of the theme to the styles of the document.
+func applyDocumentTheme(document *docx.RootDoc, theme models.DocumentTheme) {
+	styles := document.DocStyles
+	if styles == nil {
+		return
+	}
+	if theme.FontFamily != nil {
+		fonts := &ctypes.RunFonts{Ascii: *theme.FontFamily, HAnsi: *theme.FontFamily, EastAsia: *theme.FontFamily, CS: *theme.FontFamily}
+		if styles.DocDefaults == nil {
+			styles.DocDefaults = &ctypes.DocDefault{}
+		}
+		if styles.DocDefaults.RunProp == nil {
+			styles.DocDefaults.RunProp = &ctypes.RunPropDefault{}
+		}
+		if styles.DocDefaults.RunProp.RunProp == nil {
+			styles.DocDefaults.RunProp.RunProp = &ctypes.RunProperty{}
+		}
+		styles.DocDefaults.RunProp.RunProp.Fonts = fonts
+		// Styles with their own font, like the headings, would otherwise keep the template font.
+		for i := range styles.StyleList {
+			if styles.StyleList[i].RunProp != nil && styles.StyleList[i].RunProp.Fonts != nil {
+				styles.StyleList[i].RunProp.Fonts = fonts
+			}
+		}
+	}
+	if theme.AccentColor != nil {
+		color := strings.ToUpper(strings.TrimPrefix(*theme.AccentColor, "#"))
+		for i := range styles.StyleList {
+			style := &styles.StyleList[i]
+			if style.ID == nil || !slices.Contains(accentStyleIDs, *style.ID) {
+				continue
+			}
+			if style.RunProp == nil {
+				style.RunProp = &ctypes.RunProperty{}
+			}
+			style.RunProp.Color = ctypes.NewColor(color)
+		}
+	}
+}
+
+// addDocumentLogo adds the logo right-aligned at the current end of the document, keeping its aspect ratio.
+func addDocumentLogo(document *docx.RootDoc, logo *models.DocumentLogo) error {
+	picture, err := addImage(document, logo.Content, logo.ContentType, logoWidth, logoMaxHeight)
+	if err != nil {
+		return err
+	}
+	picture.Para.Justification(stypes.JustificationRight)
+	return nil
+}
+
+// addImage adds a PNG or JPEG image at the current end of the document, at most width wide and maxHeight high,
+// keeping its aspect ratio.
+func addImage(document *docx.RootDoc, content []byte, contentType string, width units.Inch, maxHeight units.Inch) (*docx.PicMeta, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	height := width * units.Inch(config.Height) / units.Inch(config.Width)
+	if height > maxHeight {
+		width = width * maxHeight / height
+		height = maxHeight
+	}
+
+	// godocx only embeds pictures from files, so the image is written to a temporary file first.
+	extension := ".png"
+	if contentType == "image/jpeg" {
+		extension = ".jpeg"
+	}
+	file, err := os.CreateTemp("", "kitadoc-image-*"+extension)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name()) //nolint:errcheck
+	if _, err := file.Write(content); err != nil {
+		file.Close() //nolint:errcheck
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	return document.AddPicture(file.Name(), width, height)
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxUploadChunkSize is the largest chunk of a resumable upload, in bytes. Clients on a weak connection should
+	// send smaller chunks, so that less has to be sent again after an interruption.
+	MaxUploadChunkSize = 4 << 20
+	// ResumableUploadTTL is how long an unfinished upload can be resumed.
+	ResumableUploadTTL = 24 * time.Hour
+)
+
+// ResumableUploadService defines the interface for uploading files in chunks. A finished upload is passed to the
+// upload endpoint of its kind by its ID instead of the file.
+type ResumableUploadService interface {
+	// CreateUpload starts an upload of a file with the size and SHA-256 checksum announced by the client.
+	CreateUpload(logger *logrus.Entry, ctx context.Context, upload *models.ResumableUpload, actingUserID int) (*models.ResumableUpload, error)
+	// GetUpload returns the upload with the offset to resume at.
+	GetUpload(logger *logrus.Entry, ctx context.Context, id string, actingUserID int) (*models.ResumableUpload, error)
+	// AppendChunk stores the chunk at the offset. The checksum of the chunk is optional. With the last chunk the
+	// file is checked against its announced checksum.
+	AppendChunk(logger *logrus.Entry, ctx context.Context, id string, offset int, chunk []byte, chunkChecksum []byte, actingUserID int) (*models.ResumableUpload, error)
+	// GetCompletedUpload returns a complete upload of the kind with its content.
+	GetCompletedUpload(logger *logrus.Entry, ctx context.Context, id string, kind string, actingUserID int) (*models.ResumableUpload, error)
+	DeleteUpload(logger *logrus.Entry, ctx context.Context, id string, actingUserID int) error
+}
+
+// ResumableUploadServiceImpl implements ResumableUploadService.
+type ResumableUploadServiceImpl struct {
+	resumableUploadStore data.ResumableUploadStore
+	config               *config.Config
+	clock                clock.Clock
+}
+
+// NewResumableUploadService creates a new ResumableUploadServiceImpl.
+func NewResumableUploadService(resumableUploadStore data.ResumableUploadStore, cfg *config.Config, clock clock.Clock) *ResumableUploadServiceImpl {
+	return &ResumableUploadServiceImpl{resumableUploadStore: resumableUploadStore, config: cfg, clock: clock}
+}
+
+// maxUploadSize returns the largest file of a kind the upload endpoints accept.
+func (service *ResumableUploadServiceImpl) maxUploadSize(kind string) int {
+	switch kind {
+	case models.UploadKindPhoto:
+		return MaxGalleryPhotoSize
+	case models.UploadKindChildDocument:
+		return MaxChildDocumentSize
+	default:
+		return service.config.FileStorage.MaxSizeMB << 20
+	}
+}
+
+// CreateUpload validates the announced file and stores the upload under a random ID. Expired uploads are
+// deleted on the way, so that abandoned chunks do not pile up.
+func (service *ResumableUploadServiceImpl) CreateUpload(logger *logrus.Entry, ctx context.Context, upload *models.ResumableUpload, actingUserID int) (*models.ResumableUpload, error) {
+	upload.SHA256 = strings.ToLower(upload.SHA256)
+	if err := models.ValidateResumableUpload(*upload); err != nil {
+		logger.WithError(err).Warn("Invalid input for resumable upload")
+		return nil, invalidInput(err)
+	}
+	if upload.SizeBytes > service.maxUploadSize(upload.Kind) {
+		logger.WithFields(logrus.Fields{"kind": upload.Kind, "size_bytes": upload.SizeBytes}).Warn("Resumable upload is too large")
+		return nil, ErrUploadTooLarge
+	}
+
+	now := service.clock.Now().UTC()
+	if deleted, err := service.resumableUploadStore.DeleteExpired(ctx, now); err != nil {
+		logger.WithError(err).Error("Error deleting expired resumable uploads")
+	} else if deleted > 0 {
+		logger.WithField("count", deleted).Info("Deleted expired resumable uploads")
+	}
+
+	id, err := newLinkToken()
+	if err != nil {
+		logger.WithError(err).Error("Error generating resumable upload ID")
+		return nil, ErrInternal
+	}
+	upload.ID = id
+	upload.Offset = 0
+	upload.CreatedByUserID = actingUserID
+	upload.CreatedAt = now
+	upload.ExpiresAt = now.Add(ResumableUploadTTL)
+	if err := service.resumableUploadStore.Create(ctx, upload); err != nil {
+		logger.WithError(err).Error("Error creating resumable upload")
+		return nil, ErrInternal
+	}
+	return upload, nil
+}
+
+// GetUpload returns an upload of the acting user. Uploads of other users are reported as not found.
+func (service *ResumableUploadServiceImpl) GetUpload(logger *logrus.Entry, ctx context.Context, id string, actingUserID int) (*models.ResumableUpload, error) {
+	upload, err := service.resumableUploadStore.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		logger.WithError(err).Error("Error fetching resumable upload")
+		return nil, ErrInternal
+	}
+	if upload.CreatedByUserID != actingUserID || !service.clock.Now().Before(upload.ExpiresAt) {
+		return nil, ErrUploadNotFound
+	}
+	return upload, nil
+}
+
+// AppendChunk checks and stores a chunk. A complete file that does not match its announced checksum is deleted,
+// the client has to upload it again.
+func (service *ResumableUploadServiceImpl) AppendChunk(logger *logrus.Entry, ctx context.Context, id string, offset int, chunk []byte, chunkChecksum []byte, actingUserID int) (*models.ResumableUpload, error) {
+	upload, err := service.GetUpload(logger, ctx, id, actingUserID)
+	if err != nil {
+		return nil, err
+	}
+	if offset != upload.Offset {
+		return upload, ErrUploadOffsetMismatch
+	}
+	if len(chunk) == 0 || len(chunk) > MaxUploadChunkSize || offset+len(chunk) > upload.SizeBytes {
+		logger.WithFields(logrus.Fields{"offset": offset, "chunk_size": len(chunk)}).Warn("Invalid chunk for resumable upload")
+		return nil, ErrInvalidInput
+	}
+	if chunkChecksum != nil {
+		sum := sha256.Sum256(chunk)
+		if !bytes.Equal(sum[:], chunkChecksum) {
+			logger.WithField("offset", offset).Warn("Chunk of resumable upload does not match its checksum")
+			return nil, ErrUploadChecksumMismatch
+		}
+	}
+
+	if err := service.resumableUploadStore.AppendChunk(ctx, id, offset, chunk); err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			// Another request stored a chunk in between, e.g. the retry of a request that only seemed to fail.
+			return service.offsetMismatch(logger, ctx, id, actingUserID)
+		}
+		logger.WithError(err).Error("Error storing chunk of resumable upload")
+		return nil, ErrInternal
+	}
+	upload.Offset += len(chunk)
+	if !upload.Complete() {
+		return upload, nil
+	}
+
+	if _, err := service.content(logger, ctx, upload); err != nil {
+		if errors.Is(err, ErrUploadChecksumMismatch) {
+			if err := service.resumableUploadStore.Delete(ctx, id); err != nil {
+				logger.WithError(err).Error("Error deleting corrupted resumable upload")
+			}
+		}
+		return nil, err
+	}
+	return upload, nil
+}
+
+// offsetMismatch returns the current state of an upload with ErrUploadOffsetMismatch, so that the client learns
+// where to resume.
+func (service *ResumableUploadServiceImpl) offsetMismatch(logger *logrus.Entry, ctx context.Context, id string, actingUserID int) (*models.ResumableUpload, error) {
+	upload, err := service.GetUpload(logger, ctx, id, actingUserID)
+	if err != nil {
+		return nil, err
+	}
+	return upload, ErrUploadOffsetMismatch
+}
+
+// GetCompletedUpload joins the chunks of a complete upload and checks them against the announced checksum again.
+func (service *ResumableUploadServiceImpl) GetCompletedUpload(logger *logrus.Entry, ctx context.Context, id string, kind string, actingUserID int) (*models.ResumableUpload, error) {
+	upload, err := service.GetUpload(logger, ctx, id, actingUserID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Kind != kind {
+		return nil, ErrUploadKindMismatch
+	}
+	if !upload.Complete() {
+		return nil, ErrUploadIncomplete
+	}
+	content, err := service.content(logger, ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+	upload.Content = content
+	return upload, nil
+}
+
+// content returns the joined chunks of a complete upload if they match the announced checksum.
+func (service *ResumableUploadServiceImpl) content(logger *logrus.Entry, ctx context.Context, upload *models.ResumableUpload) ([]byte, error) {
+	content, err := service.resumableUploadStore.GetContent(ctx, upload.ID)
+	if err != nil {
+		logger.WithError(err).Error("Error joining chunks of resumable upload")
+		return nil, ErrInternal
+	}
+	sum := sha256.Sum256(content)
+	if len(content) != upload.SizeBytes || hex.EncodeToString(sum[:]) != upload.SHA256 {
+		logger.WithField("size_bytes", len(content)).Warn("Resumable upload does not match its checksum")
+		return nil, ErrUploadChecksumMismatch
+	}
+	return content, nil
+}
+
+// DeleteUpload deletes an upload of the acting user with its chunks, when it has been used or is abandoned.
+func (service *ResumableUploadServiceImpl) DeleteUpload(logger *logrus.Entry, ctx context.Context, id string, actingUserID int) error {
+	if _, err := service.GetUpload(logger, ctx, id, actingUserID); err != nil {
+		return err
+	}
+	if err := service.resumableUploadStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrUploadNotFound
+		}
+		logger.WithError(err).Error("Error deleting resumable upload")
+		return ErrInternal
+	}
+	return nil
+}
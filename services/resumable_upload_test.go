@@ -0,0 +1,108 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumableUploadService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	content := []byte("recording")
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	newService := func() (*services.ResumableUploadServiceImpl, *datamocks.MockResumableUploadStore) {
+		store := new(datamocks.MockResumableUploadStore)
+		cfg := &config.Config{}
+		cfg.FileStorage.MaxSizeMB = 1
+		return services.NewResumableUploadService(store, cfg, clock.NewFrozen(now)), store
+	}
+	stored := func(offset int) *models.ResumableUpload {
+		return &models.ResumableUpload{ID: "abc", Kind: models.UploadKindAudio, FileName: "a.mp3", ContentType: "audio/mpeg",
+			SizeBytes: len(content), SHA256: checksum, Offset: offset, CreatedByUserID: 3, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	}
+
+	t.Run("create checks the size of the kind", func(t *testing.T) {
+		service, store := newService()
+		_, err := service.CreateUpload(logger, ctx, &models.ResumableUpload{Kind: models.UploadKindAudio, FileName: "a.mp3",
+			ContentType: "audio/mpeg", SizeBytes: 2 << 20, SHA256: checksum}, 3)
+		assert.ErrorIs(t, err, services.ErrUploadTooLarge)
+
+		store.On("DeleteExpired", mock.Anything, now).Return(2, nil).Once()
+		store.On("Create", mock.Anything, mock.MatchedBy(func(upload *models.ResumableUpload) bool {
+			return upload.ID != "" && upload.CreatedByUserID == 3 && upload.ExpiresAt.Equal(now.Add(services.ResumableUploadTTL))
+		})).Return(nil).Once()
+		created, err := service.CreateUpload(logger, ctx, &models.ResumableUpload{Kind: models.UploadKindAudio, FileName: "a.mp3",
+			ContentType: "audio/mpeg", SizeBytes: len(content), SHA256: checksum}, 3)
+		require.NoError(t, err)
+		assert.Len(t, created.ID, 43)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("uploads of other users are not found", func(t *testing.T) {
+		service, store := newService()
+		store.On("Get", mock.Anything, "abc").Return(stored(0), nil)
+		_, err := service.GetUpload(logger, ctx, "abc", 4)
+		assert.ErrorIs(t, err, services.ErrUploadNotFound)
+	})
+
+	t.Run("chunks must continue the upload", func(t *testing.T) {
+		service, store := newService()
+		store.On("Get", mock.Anything, "abc").Return(stored(4), nil)
+		upload, err := service.AppendChunk(logger, ctx, "abc", 0, content[:4], nil, 3)
+		assert.ErrorIs(t, err, services.ErrUploadOffsetMismatch)
+		assert.Equal(t, 4, upload.Offset, "the offset to resume at is returned")
+
+		store.On("AppendChunk", mock.Anything, "abc", 4, content[4:]).Return(data.ErrConflict).Once()
+		_, err = service.AppendChunk(logger, ctx, "abc", 4, content[4:], nil, 3)
+		assert.ErrorIs(t, err, services.ErrUploadOffsetMismatch)
+
+		chunkSum := sha256.Sum256([]byte("other"))
+		_, err = service.AppendChunk(logger, ctx, "abc", 4, content[4:], chunkSum[:], 3)
+		assert.ErrorIs(t, err, services.ErrUploadChecksumMismatch)
+	})
+
+	t.Run("a complete file must match its checksum", func(t *testing.T) {
+		service, store := newService()
+		store.On("Get", mock.Anything, "abc").Return(stored(4), nil)
+		store.On("AppendChunk", mock.Anything, "abc", 4, []byte("xxxxx")).Return(nil).Once()
+		store.On("GetContent", mock.Anything, "abc").Return([]byte("recoxxxxx"), nil).Once()
+		store.On("Delete", mock.Anything, "abc").Return(nil).Once()
+		_, err := service.AppendChunk(logger, ctx, "abc", 4, []byte("xxxxx"), nil, 3)
+		assert.ErrorIs(t, err, services.ErrUploadChecksumMismatch)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("completed uploads are returned with their content", func(t *testing.T) {
+		service, store := newService()
+		store.On("Get", mock.Anything, "abc").Return(stored(len(content)), nil)
+		store.On("GetContent", mock.Anything, "abc").Return(content, nil)
+		_, err := service.GetCompletedUpload(logger, ctx, "abc", models.UploadKindPhoto, 3)
+		assert.ErrorIs(t, err, services.ErrUploadKindMismatch)
+		upload, err := service.GetCompletedUpload(logger, ctx, "abc", models.UploadKindAudio, 3)
+		require.NoError(t, err)
+		assert.Equal(t, content, upload.Content)
+
+		service, store = newService()
+		store.On("Get", mock.Anything, "abc").Return(stored(4), nil)
+		_, err = service.GetCompletedUpload(logger, ctx, "abc", models.UploadKindAudio, 3)
+		assert.ErrorIs(t, err, services.ErrUploadIncomplete)
+	})
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SchoolService defines the interface for school directory operations.
+type SchoolService interface {
+	CreateSchool(logger *logrus.Entry, ctx context.Context, school *models.School) (*models.School, error)
+	GetSchoolByID(logger *logrus.Entry, ctx context.Context, id int) (*models.School, error)
+	GetAllSchools(logger *logrus.Entry, ctx context.Context) ([]models.School, error)
+	UpdateSchool(logger *logrus.Entry, ctx context.Context, school *models.School) error
+	DeleteSchool(logger *logrus.Entry, ctx context.Context, id int) error
+	LinkChild(logger *logrus.Entry, ctx context.Context, schoolID int, childID int) (*models.School, error)
+	UnlinkChild(logger *logrus.Entry, ctx context.Context, schoolID int, childID int) error
+	GetSchoolChildren(logger *logrus.Entry, ctx context.Context, schoolID int, preschoolersOnly bool) ([]models.Child, error)
+}
+
+// SchoolServiceImpl implements SchoolService.
+type SchoolServiceImpl struct {
+	schoolStore data.SchoolStore
+	childStore  data.ChildStore
+}
+
+// NewSchoolService creates a new SchoolServiceImpl.
+func NewSchoolService(schoolStore data.SchoolStore, childStore data.ChildStore) *SchoolServiceImpl {
+	return &SchoolServiceImpl{
+		schoolStore: schoolStore,
+		childStore:  childStore,
+	}
+}
+
+// CreateSchool creates a new school.
+func (service *SchoolServiceImpl) CreateSchool(logger *logrus.Entry, ctx context.Context, school *models.School) (*models.School, error) {
+	if err := models.ValidateSchool(*school); err != nil {
+		logger.WithError(err).Warn("Invalid input for CreateSchool")
+		return nil, invalidInput(err)
+	}
+
+	id, err := service.schoolStore.Create(ctx, school)
+	if err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			logger.WithField("name", school.Name).Warn("School with this name already exists")
+			return nil, ErrAlreadyExists
+		}
+		logger.WithError(err).Error("Error creating school")
+		return nil, ErrInternal
+	}
+
+	created, err := service.schoolStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("school_id", id).Error("Error fetching created school")
+		return nil, ErrInternal
+	}
+	logger.WithField("school_id", id).Info("School created successfully")
+	return created, nil
+}
+
+// GetSchoolByID fetches a school by ID.
+func (service *SchoolServiceImpl) GetSchoolByID(logger *logrus.Entry, ctx context.Context, id int) (*models.School, error) {
+	school, err := service.schoolStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("school_id", id).Error("Error fetching school")
+		return nil, ErrInternal
+	}
+	return school, nil
+}
+
+// GetAllSchools fetches all schools.
+func (service *SchoolServiceImpl) GetAllSchools(logger *logrus.Entry, ctx context.Context) ([]models.School, error) {
+	schools, err := service.schoolStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching schools")
+		return nil, ErrInternal
+	}
+	return schools, nil
+}
+
+// UpdateSchool updates an existing school.
+func (service *SchoolServiceImpl) UpdateSchool(logger *logrus.Entry, ctx context.Context, school *models.School) error {
+	if err := models.ValidateSchool(*school); err != nil {
+		logger.WithError(err).Warn("Invalid input for UpdateSchool")
+		return invalidInput(err)
+	}
+
+	if err := service.schoolStore.Update(ctx, school); err != nil {
+		switch {
+		case errors.Is(err, data.ErrNotFound):
+			return ErrNotFound
+		case errors.Is(err, data.ErrConflict):
+			logger.WithField("name", school.Name).Warn("School with this name already exists")
+			return ErrAlreadyExists
+		}
+		logger.WithError(err).WithField("school_id", school.ID).Error("Error updating school")
+		return ErrInternal
+	}
+	logger.WithField("school_id", school.ID).Info("School updated successfully")
+	return nil
+}
+
+// DeleteSchool deletes a school. Its children remain, without an expected school.
+func (service *SchoolServiceImpl) DeleteSchool(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.schoolStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("school_id", id).Error("Error deleting school")
+		return ErrInternal
+	}
+	logger.WithField("school_id", id).Info("School deleted successfully")
+	return nil
+}
+
+// LinkChild records the school a child is expected to enroll at, replacing its previous school.
+// Only children with an expected school enrollment date can be linked.
+func (service *SchoolServiceImpl) LinkChild(logger *logrus.Entry, ctx context.Context, schoolID int, childID int) (*models.School, error) {
+	if _, err := service.GetSchoolByID(logger, ctx, schoolID); err != nil {
+		return nil, err
+	}
+	child, err := service.childStore.GetByID(ctx, childID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("child_id", childID).Warn("Child not found for school link")
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for school link")
+		return nil, ErrInternal
+	}
+	if child.ArchivedAt != nil || child.ExpectedSchoolEnrollment == nil {
+		logger.WithField("child_id", childID).Warn("Child is archived or has no expected school enrollment")
+		return nil, ErrInvalidInput
+	}
+
+	if err := service.schoolStore.AddChild(ctx, schoolID, childID); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"school_id": schoolID, "child_id": childID}).Error("Error linking child to school")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"school_id": schoolID, "child_id": childID}).Info("Child linked to school successfully")
+	return service.GetSchoolByID(logger, ctx, schoolID)
+}
+
+// UnlinkChild removes the link between a child and a school.
+func (service *SchoolServiceImpl) UnlinkChild(logger *logrus.Entry, ctx context.Context, schoolID int, childID int) error {
+	if err := service.schoolStore.RemoveChild(ctx, schoolID, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithFields(logrus.Fields{"school_id": schoolID, "child_id": childID}).Error("Error unlinking child from school")
+		return ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"school_id": schoolID, "child_id": childID}).Info("Child unlinked from school successfully")
+	return nil
+}
+
+// GetSchoolChildren fetches the children expected to enroll at a school, ordered by their expected enrollment.
+// With preschoolersOnly, only the Vorschul cohort of the current school year is returned. Archived children are left out.
+func (service *SchoolServiceImpl) GetSchoolChildren(logger *logrus.Entry, ctx context.Context, schoolID int, preschoolersOnly bool) ([]models.Child, error) {
+	school, err := service.GetSchoolByID(logger, ctx, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	children, err := service.childStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).WithField("school_id", schoolID).Error("Error fetching children of school")
+		return nil, ErrInternal
+	}
+
+	schoolChildren := []models.Child{}
+	for _, child := range children {
+		if slices.Contains(school.ChildIDs, child.ID) && (!preschoolersOnly || child.IsPreschooler) {
+			schoolChildren = append(schoolChildren, child)
+		}
+	}
+	// The enrollment date can be cleared after linking, such children are listed last.
+	slices.SortStableFunc(schoolChildren, func(a, b models.Child) int {
+		switch {
+		case a.ExpectedSchoolEnrollment == nil && b.ExpectedSchoolEnrollment == nil:
+			return 0
+		case a.ExpectedSchoolEnrollment == nil:
+			return 1
+		case b.ExpectedSchoolEnrollment == nil:
+			return -1
+		}
+		return a.ExpectedSchoolEnrollment.Compare(*b.ExpectedSchoolEnrollment)
+	})
+	return schoolChildren, nil
+}
@@ -0,0 +1,137 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSchoolService() (*services.SchoolServiceImpl, *datamocks.MockSchoolStore, *datamocks.MockChildStore) {
+	mockSchoolStore := new(datamocks.MockSchoolStore)
+	mockChildStore := new(datamocks.MockChildStore)
+	return services.NewSchoolService(mockSchoolStore, mockChildStore), mockSchoolStore, mockChildStore
+}
+
+func TestCreateSchool(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		service, mockSchoolStore, _ := newSchoolService()
+		school := &models.School{Name: "Grundschule am Park"}
+		mockSchoolStore.On("Create", mock.Anything, school).Return(3, nil).Once()
+		mockSchoolStore.On("GetByID", mock.Anything, 3).Return(&models.School{ID: 3, Name: "Grundschule am Park", ChildIDs: []int{}}, nil).Once()
+
+		created, err := service.CreateSchool(logger, ctx, school)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, created.ID)
+		mockSchoolStore.AssertExpectations(t)
+	})
+
+	t.Run("invalid email", func(t *testing.T) {
+		service, _, _ := newSchoolService()
+		email := "sekretariat"
+
+		_, err := service.CreateSchool(logger, ctx, &models.School{Name: "Grundschule am Park", Email: &email})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		var validationError *services.ValidationError
+		if assert.ErrorAs(t, err, &validationError) {
+			assert.Equal(t, []services.RuleViolation{{
+				Rule:    "email",
+				Field:   "email",
+				Message: "email must be a valid email address",
+				Text:    "Bitte eine gültige E-Mail-Adresse eingeben.",
+			}}, validationError.Violations)
+		}
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		service, mockSchoolStore, _ := newSchoolService()
+		school := &models.School{Name: "Grundschule am Park"}
+		mockSchoolStore.On("Create", mock.Anything, school).Return(0, data.ErrConflict).Once()
+
+		_, err := service.CreateSchool(logger, ctx, school)
+		assert.Equal(t, services.ErrAlreadyExists, err)
+	})
+}
+
+func TestLinkChildToSchool(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	enrollment := time.Date(2027, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("child with expected enrollment", func(t *testing.T) {
+		service, mockSchoolStore, mockChildStore := newSchoolService()
+		mockSchoolStore.On("GetByID", mock.Anything, 3).Return(&models.School{ID: 3, ChildIDs: []int{}}, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, 7).Return(&models.Child{ID: 7, ExpectedSchoolEnrollment: &enrollment}, nil).Once()
+		mockSchoolStore.On("AddChild", mock.Anything, 3, 7).Return(nil).Once()
+		mockSchoolStore.On("GetByID", mock.Anything, 3).Return(&models.School{ID: 3, ChildIDs: []int{7}}, nil).Once()
+
+		school, err := service.LinkChild(logger, ctx, 3, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{7}, school.ChildIDs)
+		mockSchoolStore.AssertExpectations(t)
+	})
+
+	t.Run("child without expected enrollment", func(t *testing.T) {
+		service, mockSchoolStore, mockChildStore := newSchoolService()
+		mockSchoolStore.On("GetByID", mock.Anything, 3).Return(&models.School{ID: 3, ChildIDs: []int{}}, nil).Once()
+		mockChildStore.On("GetByID", mock.Anything, 7).Return(&models.Child{ID: 7}, nil).Once()
+
+		_, err := service.LinkChild(logger, ctx, 3, 7)
+		assert.Equal(t, services.ErrInvalidInput, err)
+		mockSchoolStore.AssertNotCalled(t, "AddChild", mock.Anything, 3, 7)
+	})
+
+	t.Run("school not found", func(t *testing.T) {
+		service, mockSchoolStore, _ := newSchoolService()
+		mockSchoolStore.On("GetByID", mock.Anything, 3).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.LinkChild(logger, ctx, 3, 7)
+		assert.Equal(t, services.ErrNotFound, err)
+	})
+}
+
+func TestGetSchoolChildren(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	early := time.Date(2027, 8, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2028, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	service, mockSchoolStore, mockChildStore := newSchoolService()
+	mockSchoolStore.On("GetByID", mock.Anything, 3).Return(&models.School{ID: 3, ChildIDs: []int{5, 6, 7, 9}}, nil)
+	// Child 9 is archived and therefore not returned, child 8 is expected at another school.
+	mockChildStore.On("GetAll", mock.Anything).Return([]models.Child{
+		{ID: 5, ExpectedSchoolEnrollment: &late},
+		{ID: 6},
+		{ID: 7, ExpectedSchoolEnrollment: &early, IsPreschooler: true},
+		{ID: 8, ExpectedSchoolEnrollment: &early, IsPreschooler: true},
+	}, nil)
+
+	t.Run("all children", func(t *testing.T) {
+		children, err := service.GetSchoolChildren(logger, ctx, 3, false)
+		assert.NoError(t, err)
+		ids := []int{}
+		for _, child := range children {
+			ids = append(ids, child.ID)
+		}
+		assert.Equal(t, []int{7, 5, 6}, ids)
+	})
+
+	t.Run("preschool cohort", func(t *testing.T) {
+		children, err := service.GetSchoolChildren(logger, ctx, 3, true)
+		assert.NoError(t, err)
+		if assert.Len(t, children, 1) {
+			assert.Equal(t, 7, children[0].ID)
+		}
+	})
+}
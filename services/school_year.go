@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SchoolYearService defines the interface for school year transition operations.
+type SchoolYearService interface {
+	Rollover(logger *logrus.Entry, ctx context.Context, request *models.RolloverRequest) (*models.RolloverSummary, error)
+}
+
+// SchoolYearServiceImpl implements SchoolYearService.
+type SchoolYearServiceImpl struct {
+	schoolYearStore data.SchoolYearStore
+	teacherStore    data.TeacherStore
+}
+
+// NewSchoolYearService creates a new SchoolYearServiceImpl.
+func NewSchoolYearService(schoolYearStore data.SchoolYearStore, teacherStore data.TeacherStore) *SchoolYearServiceImpl {
+	return &SchoolYearServiceImpl{
+		schoolYearStore: schoolYearStore,
+		teacherStore:    teacherStore,
+	}
+}
+
+// Rollover performs the end-of-year rollover and returns a summary of all changes.
+func (service *SchoolYearServiceImpl) Rollover(logger *logrus.Entry, ctx context.Context, request *models.RolloverRequest) (*models.RolloverSummary, error) {
+	if err := models.ValidateRolloverRequest(*request); err != nil {
+		logger.WithError(err).Warn("Invalid input for Rollover")
+		return nil, invalidInput(err)
+	}
+
+	// Teachers receiving assignments must exist and be active; otherwise the rollover would fail half way through.
+	for _, teacherID := range request.TeacherMapping {
+		teacher, err := service.teacherStore.GetByID(ctx, teacherID)
+		if err != nil {
+			if errors.Is(err, data.ErrNotFound) {
+				logger.WithField("teacher_id", teacherID).Warn("Target teacher of rollover mapping not found")
+				return nil, ErrInvalidInput
+			}
+			logger.WithError(err).WithField("teacher_id", teacherID).Error("Error fetching teacher for rollover")
+			return nil, ErrInternal
+		}
+		if !teacher.IsActive() {
+			logger.WithField("teacher_id", teacherID).Warn("Target teacher of rollover mapping is deactivated")
+			return nil, ErrInvalidInput
+		}
+	}
+
+	summary, err := service.schoolYearStore.Rollover(ctx, request)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithError(err).Warn("Departing child not found or already archived")
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).Error("Error performing school year rollover")
+		return nil, ErrInternal
+	}
+
+	logger.WithFields(logrus.Fields{
+		"dry_run":             summary.DryRun,
+		"archived_children":   len(summary.ArchivedChildIDs),
+		"ended_assignments":   len(summary.EndedAssignments),
+		"created_assignments": len(summary.CreatedAssignments),
+		"reset_preschoolers":  len(summary.ResetPreschoolerIDs),
+	}).Info("School year rollover completed")
+	return summary, nil
+}
@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRollover(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	rolloverDate := time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mockSchoolYearStore := new(datamocks.MockSchoolYearStore)
+		mockTeacherStore := new(datamocks.MockTeacherStore)
+		service := services.NewSchoolYearService(mockSchoolYearStore, mockTeacherStore)
+
+		request := &models.RolloverRequest{RolloverDate: rolloverDate, TeacherMapping: map[int]int{1: 2}}
+		summary := &models.RolloverSummary{RolloverDate: rolloverDate, ArchivedChildIDs: []int{3}}
+		mockTeacherStore.On("GetByID", mock.Anything, 2).Return(&models.Teacher{ID: 2}, nil).Once()
+		mockSchoolYearStore.On("Rollover", mock.Anything, request).Return(summary, nil).Once()
+
+		result, err := service.Rollover(logger, ctx, request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, summary, result)
+		mockSchoolYearStore.AssertExpectations(t)
+		mockTeacherStore.AssertExpectations(t)
+	})
+
+	t.Run("unknown target teacher", func(t *testing.T) {
+		mockSchoolYearStore := new(datamocks.MockSchoolYearStore)
+		mockTeacherStore := new(datamocks.MockTeacherStore)
+		service := services.NewSchoolYearService(mockSchoolYearStore, mockTeacherStore)
+
+		mockTeacherStore.On("GetByID", mock.Anything, 2).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.Rollover(logger, ctx, &models.RolloverRequest{RolloverDate: rolloverDate, TeacherMapping: map[int]int{1: 2}})
+
+		assert.Equal(t, services.ErrInvalidInput, err)
+		mockSchoolYearStore.AssertNotCalled(t, "Rollover", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing rollover date", func(t *testing.T) {
+		mockSchoolYearStore := new(datamocks.MockSchoolYearStore)
+		service := services.NewSchoolYearService(mockSchoolYearStore, new(datamocks.MockTeacherStore))
+
+		_, err := service.Rollover(logger, ctx, &models.RolloverRequest{})
+
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+
+	t.Run("departing child not found", func(t *testing.T) {
+		mockSchoolYearStore := new(datamocks.MockSchoolYearStore)
+		service := services.NewSchoolYearService(mockSchoolYearStore, new(datamocks.MockTeacherStore))
+
+		request := &models.RolloverRequest{RolloverDate: rolloverDate, DepartingChildIDs: []int{9}}
+		mockSchoolYearStore.On("Rollover", mock.Anything, request).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.Rollover(logger, ctx, request)
+
+		assert.Equal(t, services.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/buildinfo"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StatusService defines the interface for the public server status and the banner configured by the admins.
+type StatusService interface {
+	GetStatus(logger *logrus.Entry, ctx context.Context) *models.SystemStatus
+	GetBanner(logger *logrus.Entry, ctx context.Context) (*models.StatusBanner, error)
+	SetBanner(logger *logrus.Entry, ctx context.Context, actingUserID int, banner *models.StatusBanner) (*models.StatusBanner, error)
+	DeleteBanner(logger *logrus.Entry, ctx context.Context) error
+}
+
+// StatusServiceImpl implements StatusService.
+type StatusServiceImpl struct {
+	bannerStore data.StatusBannerStore
+	features    map[string]bool // Features enabled by the configuration, e.g. open registration
+	clock       clock.Clock
+}
+
+// NewStatusService creates a new StatusServiceImpl reporting the given features.
+func NewStatusService(bannerStore data.StatusBannerStore, features map[string]bool, clock clock.Clock) *StatusServiceImpl {
+	return &StatusServiceImpl{
+		bannerStore: bannerStore,
+		features:    features,
+		clock:       clock,
+	}
+}
+
+// GetStatus returns the version of the server, the enabled features and the banner if it is active.
+// It is served before login, so a banner that cannot be read is left out instead of failing.
+func (service *StatusServiceImpl) GetStatus(logger *logrus.Entry, ctx context.Context) *models.SystemStatus {
+	status := &models.SystemStatus{
+		BuildInfo: buildinfo.Get(),
+		Features:  service.features,
+	}
+	if status.Features == nil {
+		status.Features = map[string]bool{}
+	}
+
+	banner, err := service.bannerStore.Get(ctx)
+	switch {
+	case err == nil:
+		if banner.IsActive(service.clock.Now()) {
+			banner.UpdatedByUserID = nil // Not for the public
+			status.Banner = banner
+		}
+	case !errors.Is(err, data.ErrNotFound):
+		logger.WithError(err).Error("Error fetching status banner, leaving it out of the status")
+	}
+	return status
+}
+
+// GetBanner fetches the configured banner, also when it is not active.
+func (service *StatusServiceImpl) GetBanner(logger *logrus.Entry, ctx context.Context) (*models.StatusBanner, error) {
+	banner, err := service.bannerStore.Get(ctx)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).Error("Error fetching status banner")
+		return nil, ErrInternal
+	}
+	return banner, nil
+}
+
+// SetBanner replaces the banner.
+func (service *StatusServiceImpl) SetBanner(logger *logrus.Entry, ctx context.Context, actingUserID int, banner *models.StatusBanner) (*models.StatusBanner, error) {
+	if err := models.ValidateStatusBanner(*banner); err != nil {
+		logger.WithError(err).Warn("Invalid input for SetBanner")
+		return nil, invalidInput(err)
+	}
+	if banner.StartsAt != nil && banner.EndsAt != nil && !banner.EndsAt.After(*banner.StartsAt) {
+		logger.Warn("Status banner ends before it starts")
+		return nil, ErrInvalidInput
+	}
+	banner.UpdatedByUserID = &actingUserID
+
+	if err := service.bannerStore.Set(ctx, banner); err != nil {
+		logger.WithError(err).Error("Error setting status banner")
+		return nil, ErrInternal
+	}
+	logger.WithField("level", banner.Level).Info("Status banner set")
+	return service.GetBanner(logger, ctx)
+}
+
+// DeleteBanner removes the banner.
+func (service *StatusServiceImpl) DeleteBanner(logger *logrus.Entry, ctx context.Context) error {
+	if err := service.bannerStore.Delete(ctx); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).Error("Error deleting status banner")
+		return ErrInternal
+	}
+	logger.Info("Status banner deleted")
+	return nil
+}
@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatus(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	features := map[string]bool{"open_registration": true}
+	adminID := 1
+
+	t.Run("active banner", func(t *testing.T) {
+		bannerStore := new(datamocks.MockStatusBannerStore)
+		startsAt := time.Now().Add(-time.Hour)
+		bannerStore.On("Get", mock.Anything).Return(&models.StatusBanner{Message: "Wartung", Level: models.BannerLevelMaintenance, StartsAt: &startsAt, UpdatedByUserID: &adminID}, nil).Once()
+		service := services.NewStatusService(bannerStore, features, clock.System{})
+
+		status := service.GetStatus(logger, ctx)
+
+		assert.NotEmpty(t, status.Version)
+		assert.True(t, status.Features["open_registration"])
+		require.NotNil(t, status.Banner)
+		assert.Equal(t, "Wartung", status.Banner.Message)
+		assert.Nil(t, status.Banner.UpdatedByUserID, "the admin is not shown before login")
+	})
+
+	t.Run("banner not active yet or anymore", func(t *testing.T) {
+		later := time.Now().Add(time.Hour)
+		earlier := time.Now().Add(-time.Hour)
+		for _, banner := range []*models.StatusBanner{
+			{Message: "Wartung", Level: models.BannerLevelMaintenance, StartsAt: &later},
+			{Message: "Wartung", Level: models.BannerLevelMaintenance, EndsAt: &earlier},
+		} {
+			bannerStore := new(datamocks.MockStatusBannerStore)
+			bannerStore.On("Get", mock.Anything).Return(banner, nil).Once()
+			service := services.NewStatusService(bannerStore, features, clock.System{})
+
+			assert.Nil(t, service.GetStatus(logger, ctx).Banner)
+		}
+	})
+
+	t.Run("status is served when the banner cannot be read", func(t *testing.T) {
+		bannerStore := new(datamocks.MockStatusBannerStore)
+		bannerStore.On("Get", mock.Anything).Return(nil, errors.New("database locked")).Once()
+		service := services.NewStatusService(bannerStore, nil, clock.System{})
+
+		status := service.GetStatus(logger, ctx)
+
+		assert.Nil(t, status.Banner)
+		assert.NotNil(t, status.Features)
+	})
+}
+
+func TestSetBanner(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		bannerStore := new(datamocks.MockStatusBannerStore)
+		service := services.NewStatusService(bannerStore, nil, clock.System{})
+		banner := &models.StatusBanner{Message: "Neue Funktion: Wochenübersicht", Level: models.BannerLevelInfo}
+		bannerStore.On("Set", mock.Anything, mock.MatchedBy(func(banner *models.StatusBanner) bool {
+			return *banner.UpdatedByUserID == 1
+		})).Return(nil).Once()
+		bannerStore.On("Get", mock.Anything).Return(banner, nil).Once()
+
+		updated, err := service.SetBanner(logger, ctx, 1, banner)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.BannerLevelInfo, updated.Level)
+		bannerStore.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		startsAt := time.Date(2025, time.June, 6, 18, 0, 0, 0, time.UTC)
+		endsAt := startsAt.Add(-time.Hour)
+		for _, banner := range []*models.StatusBanner{
+			{Message: "Wartung", Level: "urgent"},
+			{Level: models.BannerLevelInfo},
+			{Message: "Wartung", Level: models.BannerLevelMaintenance, StartsAt: &startsAt, EndsAt: &endsAt},
+		} {
+			bannerStore := new(datamocks.MockStatusBannerStore)
+			service := services.NewStatusService(bannerStore, nil, clock.System{})
+
+			_, err := service.SetBanner(logger, ctx, 1, banner)
+
+			assert.ErrorIs(t, err, services.ErrInvalidInput)
+			bannerStore.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestDeleteBanner(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	bannerStore := new(datamocks.MockStatusBannerStore)
+	bannerStore.On("Delete", mock.Anything).Return(data.ErrNotFound).Once()
+	service := services.NewStatusService(bannerStore, nil, clock.System{})
+
+	assert.ErrorIs(t, service.DeleteBanner(logger, context.Background()), services.ErrNotFound)
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SupportProviderService defines the interface for the external therapy and support providers of children.
+type SupportProviderService interface {
+	CreateSupportProvider(logger *logrus.Entry, ctx context.Context, provider *models.SupportProvider) (*models.SupportProvider, error)
+	GetSupportProviderByID(logger *logrus.Entry, ctx context.Context, id int) (*models.SupportProvider, error)
+	GetAllSupportProviders(logger *logrus.Entry, ctx context.Context) ([]models.SupportProvider, error)
+	UpdateSupportProvider(logger *logrus.Entry, ctx context.Context, provider *models.SupportProvider) error
+	DeleteSupportProvider(logger *logrus.Entry, ctx context.Context, id int) error
+	CreateChildSupport(logger *logrus.Entry, ctx context.Context, support *models.ChildSupport) (*models.ChildSupport, error)
+	GetChildSupports(logger *logrus.Entry, ctx context.Context, childID int) ([]models.ChildSupport, error)
+	UpdateChildSupport(logger *logrus.Entry, ctx context.Context, support *models.ChildSupport) error
+	DeleteChildSupport(logger *logrus.Entry, ctx context.Context, childID int, supportID int) error
+}
+
+// SupportProviderServiceImpl implements SupportProviderService.
+type SupportProviderServiceImpl struct {
+	supportProviderStore data.SupportProviderStore
+	childStore           data.ChildStore
+}
+
+// NewSupportProviderService creates a new SupportProviderServiceImpl.
+func NewSupportProviderService(supportProviderStore data.SupportProviderStore, childStore data.ChildStore) *SupportProviderServiceImpl {
+	return &SupportProviderServiceImpl{
+		supportProviderStore: supportProviderStore,
+		childStore:           childStore,
+	}
+}
+
+// CreateSupportProvider creates a new support provider.
+func (service *SupportProviderServiceImpl) CreateSupportProvider(logger *logrus.Entry, ctx context.Context, provider *models.SupportProvider) (*models.SupportProvider, error) {
+	if err := models.ValidateSupportProvider(*provider); err != nil {
+		logger.WithError(err).Warn("Invalid input for CreateSupportProvider")
+		return nil, invalidInput(err)
+	}
+
+	id, err := service.supportProviderStore.Create(ctx, provider)
+	if err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			logger.WithField("name", provider.Name).Warn("Support provider with this name already exists")
+			return nil, ErrAlreadyExists
+		}
+		logger.WithError(err).Error("Error creating support provider")
+		return nil, ErrInternal
+	}
+
+	created, err := service.supportProviderStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("provider_id", id).Error("Error fetching created support provider")
+		return nil, ErrInternal
+	}
+	logger.WithField("provider_id", id).Info("Support provider created successfully")
+	return created, nil
+}
+
+// GetSupportProviderByID fetches a support provider by ID.
+func (service *SupportProviderServiceImpl) GetSupportProviderByID(logger *logrus.Entry, ctx context.Context, id int) (*models.SupportProvider, error) {
+	provider, err := service.supportProviderStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("provider_id", id).Error("Error fetching support provider")
+		return nil, ErrInternal
+	}
+	return provider, nil
+}
+
+// GetAllSupportProviders fetches all support providers.
+func (service *SupportProviderServiceImpl) GetAllSupportProviders(logger *logrus.Entry, ctx context.Context) ([]models.SupportProvider, error) {
+	providers, err := service.supportProviderStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching support providers")
+		return nil, ErrInternal
+	}
+	return providers, nil
+}
+
+// UpdateSupportProvider updates an existing support provider.
+func (service *SupportProviderServiceImpl) UpdateSupportProvider(logger *logrus.Entry, ctx context.Context, provider *models.SupportProvider) error {
+	if err := models.ValidateSupportProvider(*provider); err != nil {
+		logger.WithError(err).Warn("Invalid input for UpdateSupportProvider")
+		return invalidInput(err)
+	}
+
+	if err := service.supportProviderStore.Update(ctx, provider); err != nil {
+		switch {
+		case errors.Is(err, data.ErrNotFound):
+			return ErrNotFound
+		case errors.Is(err, data.ErrConflict):
+			logger.WithField("name", provider.Name).Warn("Support provider with this name already exists")
+			return ErrAlreadyExists
+		}
+		logger.WithError(err).WithField("provider_id", provider.ID).Error("Error updating support provider")
+		return ErrInternal
+	}
+	logger.WithField("provider_id", provider.ID).Info("Support provider updated successfully")
+	return nil
+}
+
+// DeleteSupportProvider deletes a support provider. Providers that support children cannot be deleted,
+// their supports have to be removed first.
+func (service *SupportProviderServiceImpl) DeleteSupportProvider(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.supportProviderStore.Delete(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, data.ErrNotFound):
+			return ErrNotFound
+		case errors.Is(err, data.ErrForeignKeyConstraint):
+			logger.WithField("provider_id", id).Warn("Cannot delete support provider with supported children")
+			return ErrForeignKeyConstraint
+		}
+		logger.WithError(err).WithField("provider_id", id).Error("Error deleting support provider")
+		return ErrInternal
+	}
+	logger.WithField("provider_id", id).Info("Support provider deleted successfully")
+	return nil
+}
+
+// CreateChildSupport records that a child is supported by a provider.
+func (service *SupportProviderServiceImpl) CreateChildSupport(logger *logrus.Entry, ctx context.Context, support *models.ChildSupport) (*models.ChildSupport, error) {
+	if err := service.checkChildSupport(logger, ctx, support); err != nil {
+		return nil, err
+	}
+
+	id, err := service.supportProviderStore.CreateSupport(ctx, support)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", support.ChildID).Error("Error creating child support")
+		return nil, ErrInternal
+	}
+
+	created, err := service.supportProviderStore.GetSupportByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("support_id", id).Error("Error fetching created child support")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"support_id": id, "child_id": support.ChildID}).Info("Child support created successfully")
+	return created, nil
+}
+
+// GetChildSupports fetches the supports of a child, ended ones included.
+func (service *SupportProviderServiceImpl) GetChildSupports(logger *logrus.Entry, ctx context.Context, childID int) ([]models.ChildSupport, error) {
+	if err := service.checkChild(logger, ctx, childID); err != nil {
+		return nil, err
+	}
+	supports, err := service.supportProviderStore.GetSupportsForChild(ctx, childID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child supports")
+		return nil, ErrInternal
+	}
+	return supports, nil
+}
+
+// UpdateChildSupport updates a support of a child. Supports of another child are not found.
+func (service *SupportProviderServiceImpl) UpdateChildSupport(logger *logrus.Entry, ctx context.Context, support *models.ChildSupport) error {
+	if _, err := service.getChildSupport(logger, ctx, support.ChildID, support.ID); err != nil {
+		return err
+	}
+	if err := service.checkChildSupport(logger, ctx, support); err != nil {
+		return err
+	}
+
+	if err := service.supportProviderStore.UpdateSupport(ctx, support); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("support_id", support.ID).Error("Error updating child support")
+		return ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"support_id": support.ID, "child_id": support.ChildID}).Info("Child support updated successfully")
+	return nil
+}
+
+// DeleteChildSupport deletes a support of a child.
+func (service *SupportProviderServiceImpl) DeleteChildSupport(logger *logrus.Entry, ctx context.Context, childID int, supportID int) error {
+	if _, err := service.getChildSupport(logger, ctx, childID, supportID); err != nil {
+		return err
+	}
+	if err := service.supportProviderStore.DeleteSupport(ctx, supportID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("support_id", supportID).Error("Error deleting child support")
+		return ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"support_id": supportID, "child_id": childID}).Info("Child support deleted successfully")
+	return nil
+}
+
+// checkChildSupport validates a support and checks that its child exists. An unknown provider is invalid input.
+func (service *SupportProviderServiceImpl) checkChildSupport(logger *logrus.Entry, ctx context.Context, support *models.ChildSupport) error {
+	if err := models.ValidateChildSupport(*support); err != nil {
+		logger.WithError(err).Warn("Invalid input for child support")
+		return invalidInput(err)
+	}
+	if err := service.checkChild(logger, ctx, support.ChildID); err != nil {
+		return err
+	}
+	if _, err := service.supportProviderStore.GetByID(ctx, support.ProviderID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("provider_id", support.ProviderID).Warn("Support provider not found for child support")
+			return ErrInvalidInput
+		}
+		logger.WithError(err).WithField("provider_id", support.ProviderID).Error("Error fetching support provider for child support")
+		return ErrInternal
+	}
+	return nil
+}
+
+// checkChild returns ErrNotFound if the child does not exist.
+func (service *SupportProviderServiceImpl) checkChild(logger *logrus.Entry, ctx context.Context, childID int) error {
+	if _, err := service.childStore.GetByID(ctx, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for child support")
+		return ErrInternal
+	}
+	return nil
+}
+
+// getChildSupport fetches a support and returns ErrNotFound if it belongs to another child.
+func (service *SupportProviderServiceImpl) getChildSupport(logger *logrus.Entry, ctx context.Context, childID int, supportID int) (*models.ChildSupport, error) {
+	support, err := service.supportProviderStore.GetSupportByID(ctx, supportID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).WithField("support_id", supportID).Error("Error fetching child support")
+		return nil, ErrInternal
+	}
+	if support.ChildID != childID {
+		return nil, ErrNotFound
+	}
+	return support, nil
+}
@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSupportProviderService() (*services.SupportProviderServiceImpl, *datamocks.MockSupportProviderStore, *datamocks.MockChildStore) {
+	mockSupportProviderStore := new(datamocks.MockSupportProviderStore)
+	mockChildStore := new(datamocks.MockChildStore)
+	return services.NewSupportProviderService(mockSupportProviderStore, mockChildStore), mockSupportProviderStore, mockChildStore
+}
+
+func TestCreateSupportProvider(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		service, mockStore, _ := newSupportProviderService()
+		provider := &models.SupportProvider{Name: "Logopädie am Markt", ProviderType: models.SupportProviderTypeSpeechTherapy}
+		mockStore.On("Create", mock.Anything, provider).Return(2, nil).Once()
+		mockStore.On("GetByID", mock.Anything, 2).Return(&models.SupportProvider{ID: 2, Name: "Logopädie am Markt", ProviderType: models.SupportProviderTypeSpeechTherapy}, nil).Once()
+
+		created, err := service.CreateSupportProvider(logger, ctx, provider)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, created.ID)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("unknown provider type", func(t *testing.T) {
+		service, _, _ := newSupportProviderService()
+
+		_, err := service.CreateSupportProvider(logger, ctx, &models.SupportProvider{Name: "Praxis", ProviderType: "massage"})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		service, mockStore, _ := newSupportProviderService()
+		provider := &models.SupportProvider{Name: "Logopädie am Markt", ProviderType: models.SupportProviderTypeSpeechTherapy}
+		mockStore.On("Create", mock.Anything, provider).Return(0, data.ErrConflict).Once()
+
+		_, err := service.CreateSupportProvider(logger, ctx, provider)
+		assert.ErrorIs(t, err, services.ErrAlreadyExists)
+	})
+}
+
+func TestDeleteSupportProviderWithSupports(t *testing.T) {
+	service, mockStore, _ := newSupportProviderService()
+	mockStore.On("Delete", mock.Anything, 2).Return(data.ErrForeignKeyConstraint).Once()
+
+	err := service.DeleteSupportProvider(logrus.NewEntry(logrus.New()), context.Background(), 2)
+	assert.ErrorIs(t, err, services.ErrForeignKeyConstraint)
+}
+
+func TestCreateChildSupport(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		service, mockStore, mockChildStore := newSupportProviderService()
+		support := &models.ChildSupport{ChildID: 1, ProviderID: 2, StartDate: start}
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Once()
+		mockStore.On("GetByID", mock.Anything, 2).Return(&models.SupportProvider{ID: 2}, nil).Once()
+		mockStore.On("CreateSupport", mock.Anything, support).Return(5, nil).Once()
+		mockStore.On("GetSupportByID", mock.Anything, 5).Return(&models.ChildSupport{ID: 5, ChildID: 1, ProviderID: 2, ProviderName: "Logopädie am Markt", StartDate: start}, nil).Once()
+
+		created, err := service.CreateChildSupport(logger, ctx, support)
+		assert.NoError(t, err)
+		assert.Equal(t, "Logopädie am Markt", created.ProviderName)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("end before start", func(t *testing.T) {
+		service, _, _ := newSupportProviderService()
+		end := start.AddDate(0, -1, 0)
+
+		_, err := service.CreateChildSupport(logger, ctx, &models.ChildSupport{ChildID: 1, ProviderID: 2, StartDate: start, EndDate: &end})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		service, mockStore, mockChildStore := newSupportProviderService()
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Once()
+		mockStore.On("GetByID", mock.Anything, 9).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.CreateChildSupport(logger, ctx, &models.ChildSupport{ChildID: 1, ProviderID: 9, StartDate: start})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		mockStore.AssertNotCalled(t, "CreateSupport", mock.Anything)
+	})
+
+	t.Run("unknown child", func(t *testing.T) {
+		service, _, mockChildStore := newSupportProviderService()
+		mockChildStore.On("GetByID", mock.Anything, 1).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.CreateChildSupport(logger, ctx, &models.ChildSupport{ChildID: 1, ProviderID: 2, StartDate: start})
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+}
+
+func TestDeleteChildSupportOfAnotherChild(t *testing.T) {
+	service, mockStore, _ := newSupportProviderService()
+	mockStore.On("GetSupportByID", mock.Anything, 5).Return(&models.ChildSupport{ID: 5, ChildID: 2}, nil).Once()
+
+	err := service.DeleteChildSupport(logrus.NewEntry(logrus.New()), context.Background(), 1, 5)
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	mockStore.AssertNotCalled(t, "DeleteSupport", mock.Anything, 5)
+}
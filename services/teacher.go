@@ -1,10 +1,13 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/models"
 
@@ -13,48 +16,57 @@ import (
 
 // TeacherService defines the interface for teacher-related business logic operations.
 type TeacherService interface {
-	CreateTeacher(teacher *models.Teacher) (*models.Teacher, error)
-	GetTeacherByID(id int) (*models.Teacher, error)
-	UpdateTeacher(teacher *models.Teacher) error
-	DeleteTeacher(id int) error
-	GetAllTeachers() ([]models.Teacher, error)
+	CreateTeacher(ctx context.Context, teacher *models.Teacher) (*models.Teacher, error)
+	GetTeacherByID(ctx context.Context, id int) (*models.Teacher, error)
+	UpdateTeacher(ctx context.Context, teacher *models.Teacher) error
+	DeleteTeacher(ctx context.Context, id int) error
+	GetAllTeachers(ctx context.Context, includeDeactivated bool) ([]models.Teacher, error)
+	DeactivateTeacher(ctx context.Context, id int) error
+	ReactivateTeacher(ctx context.Context, id int) error
+	MergeTeachers(ctx context.Context, duplicateID int, teacherID int) error
 }
 
 // TeacherServiceImpl implements TeacherService.
 type TeacherServiceImpl struct {
 	teacherStore data.TeacherStore
 	validate     *validator.Validate
+	clock        clock.Clock
 }
 
 // NewTeacherService creates a new TeacherServiceImpl.
-func NewTeacherService(teacherStore data.TeacherStore) *TeacherServiceImpl {
+func NewTeacherService(teacherStore data.TeacherStore, clock clock.Clock) *TeacherServiceImpl {
 	return &TeacherServiceImpl{
 		teacherStore: teacherStore,
-		validate:     validator.New(),
+		validate:     models.NewValidator(),
+		clock:        clock,
 	}
 }
 
 // CreateTeacher creates a new teacher.
-func (s *TeacherServiceImpl) CreateTeacher(teacher *models.Teacher) (*models.Teacher, error) {
+func (s *TeacherServiceImpl) CreateTeacher(ctx context.Context, teacher *models.Teacher) (*models.Teacher, error) {
 	if err := models.ValidateTeacher(*teacher); err != nil {
-		return nil, ErrInvalidInput
+		return nil, invalidInput(err)
 	}
 
-	teacher.CreatedAt = time.Now()
-	teacher.UpdatedAt = time.Now()
+	teacher.CreatedAt = s.clock.Now()
+	teacher.UpdatedAt = s.clock.Now()
 
-	id, err := s.teacherStore.Create(teacher)
+	id, err := s.teacherStore.Create(ctx, teacher)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error creating teacher: %v", err)
 		return nil, ErrInternal
 	}
-	teacher.ID = id
-	return teacher, nil
+	created, err := s.teacherStore.GetByID(ctx, id)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error fetching created teacher %d: %v", id, err)
+		return nil, ErrInternal
+	}
+	return created, nil
 }
 
 // GetTeacherByID fetches a teacher by ID.
-func (s *TeacherServiceImpl) GetTeacherByID(id int) (*models.Teacher, error) {
-	teacher, err := s.teacherStore.GetByID(id)
+func (s *TeacherServiceImpl) GetTeacherByID(ctx context.Context, id int) (*models.Teacher, error) {
+	teacher, err := s.teacherStore.GetByID(ctx, id)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error fetching teacher with ID %d: %v", id, err)
 		if errors.Is(err, data.ErrNotFound) {
@@ -66,14 +78,14 @@ func (s *TeacherServiceImpl) GetTeacherByID(id int) (*models.Teacher, error) {
 }
 
 // UpdateTeacher updates an existing teacher.
-func (s *TeacherServiceImpl) UpdateTeacher(teacher *models.Teacher) error {
+func (s *TeacherServiceImpl) UpdateTeacher(ctx context.Context, teacher *models.Teacher) error {
 	if err := models.ValidateTeacher(*teacher); err != nil {
 		logger.GetGlobalLogger().Errorf("Invalid teacher data: %v", err)
-		return ErrInvalidInput
+		return invalidInput(err)
 	}
 
-	teacher.UpdatedAt = time.Now()
-	err := s.teacherStore.Update(teacher)
+	teacher.UpdatedAt = s.clock.Now()
+	err := s.teacherStore.Update(ctx, teacher)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error updating teacher with ID %d: %v", teacher.ID, err)
 		if errors.Is(err, data.ErrNotFound) {
@@ -85,8 +97,8 @@ func (s *TeacherServiceImpl) UpdateTeacher(teacher *models.Teacher) error {
 }
 
 // DeleteTeacher deletes a teacher by ID.
-func (s *TeacherServiceImpl) DeleteTeacher(id int) error {
-	err := s.teacherStore.Delete(id)
+func (s *TeacherServiceImpl) DeleteTeacher(ctx context.Context, id int) error {
+	err := s.teacherStore.Delete(ctx, id)
 	if err != nil {
 		log := logger.GetGlobalLogger()
 		if errors.Is(err, data.ErrNotFound) {
@@ -102,14 +114,97 @@ func (s *TeacherServiceImpl) DeleteTeacher(id int) error {
 	return nil
 }
 
-// GetAllTeachers fetches all teachers.
-func (s *TeacherServiceImpl) GetAllTeachers() ([]models.Teacher, error) {
-	// The data layer's GetAll method for teachers doesn't have pagination/filtering,
-	// so we can directly call it.
-	teachers, err := s.teacherStore.GetAll()
+// GetAllTeachers fetches all teachers with their usage counts.
+// Deactivated teachers are left out unless includeDeactivated is set.
+func (s *TeacherServiceImpl) GetAllTeachers(ctx context.Context, includeDeactivated bool) ([]models.Teacher, error) {
+	teachers, err := s.teacherStore.GetAll(ctx)
 	if err != nil {
 		logger.GetGlobalLogger().Errorf("Error fetching all teachers: %v", err)
 		return nil, ErrInternal
 	}
+	if !includeDeactivated {
+		teachers = slices.DeleteFunc(teachers, func(teacher models.Teacher) bool { return !teacher.IsActive() })
+	}
+
+	usage, err := s.teacherStore.CountUsage(ctx)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error counting the usage of teachers: %v", err)
+		return nil, ErrInternal
+	}
+	for i := range teachers {
+		counts := usage[teachers[i].ID]
+		teachers[i].Usage = &counts
+	}
 	return teachers, nil
 }
+
+// DeactivateTeacher hides a teacher from pickers and blocks new assignments, keeping the history intact.
+// Deactivating a deactivated teacher keeps the original date.
+func (s *TeacherServiceImpl) DeactivateTeacher(ctx context.Context, id int) error {
+	teacher, err := s.GetTeacherByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !teacher.IsActive() {
+		return nil
+	}
+	now := s.clock.Now()
+	return s.setDeactivated(ctx, id, &now)
+}
+
+// ReactivateTeacher reverts the deactivation of a teacher.
+func (s *TeacherServiceImpl) ReactivateTeacher(ctx context.Context, id int) error {
+	return s.setDeactivated(ctx, id, nil)
+}
+
+func (s *TeacherServiceImpl) setDeactivated(ctx context.Context, id int, deactivatedAt *time.Time) error {
+	err := s.teacherStore.SetDeactivated(ctx, id, deactivatedAt)
+	if err != nil {
+		logger.GetGlobalLogger().Errorf("Error changing the deactivation of teacher %d: %v", id, err)
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		return ErrInternal
+	}
+	logger.GetGlobalLogger().Infof("Teacher %d deactivated: %t", id, deactivatedAt != nil)
+	return nil
+}
+
+// MergeTeachers merges an accidentally duplicated teacher record into the teacher it duplicates.
+// The assignments, documentation entries and groups of the duplicate are moved over and the duplicate is deleted.
+func (s *TeacherServiceImpl) MergeTeachers(ctx context.Context, duplicateID int, teacherID int) error {
+	if duplicateID == teacherID {
+		logger.GetGlobalLogger().Warnf("Teacher %d cannot be merged into itself", teacherID)
+		return ErrInvalidInput
+	}
+	for _, id := range []int{teacherID, duplicateID} {
+		if _, err := s.GetTeacherByID(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.teacherStore.Merge(ctx, duplicateID, teacherID); err != nil {
+		logger.GetGlobalLogger().Errorf("Error merging teacher %d into %d: %v", duplicateID, teacherID, err)
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		return ErrInternal
+	}
+	logger.GetGlobalLogger().Infof("Teacher %d merged into %d", duplicateID, teacherID)
+	return nil
+}
+
+// findTeacherOfUser returns the teacher with the username of the user, nil if the user is no teacher.
+// Usernames of teachers are encrypted, so all teachers are compared.
+func findTeacherOfUser(ctx context.Context, teacherStore data.TeacherStore, user *models.User) (*models.Teacher, error) {
+	teachers, err := teacherStore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range teachers {
+		if teachers[i].Username == user.Username {
+			return &teachers[i], nil
+		}
+	}
+	return nil, nil
+}
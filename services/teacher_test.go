@@ -3,9 +3,11 @@ package services_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"kitadoc-backend/data"
 	"kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
 	"kitadoc-backend/internal/logger"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
@@ -17,7 +19,7 @@ import (
 
 func TestCreateTeacher(t *testing.T) {
 	mockTeacherStore := new(mocks.MockTeacherStore)
-	service := services.NewTeacherService(mockTeacherStore)
+	service := services.NewTeacherService(mockTeacherStore, clock.System{})
 
 	log_level, _ := logrus.ParseLevel("debug")
 	logger.InitGlobalLogger(
@@ -34,9 +36,10 @@ func TestCreateTeacher(t *testing.T) {
 			LastName:  "Doe",
 			Username:  "johndoe",
 		}
-		mockTeacherStore.On("Create", mock.AnythingOfType("*models.Teacher")).Return(1, nil).Once()
+		mockTeacherStore.On("Create", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(1, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, 1).Return(&models.Teacher{ID: 1, FirstName: "John", LastName: "Doe", Username: "johndoe"}, nil).Once()
 
-		createdTeacher, err := service.CreateTeacher(teacher)
+		createdTeacher, err := service.CreateTeacher(t.Context(), teacher)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, createdTeacher)
@@ -53,12 +56,12 @@ func TestCreateTeacher(t *testing.T) {
 			Username:  "johndoe",
 		}
 
-		createdTeacher, err := service.CreateTeacher(teacher)
+		createdTeacher, err := service.CreateTeacher(t.Context(), teacher)
 
 		assert.Error(t, err)
-		assert.Equal(t, services.ErrInvalidInput, err)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
 		assert.Nil(t, createdTeacher)
-		mockTeacherStore.AssertNotCalled(t, "Create")
+		mockTeacherStore.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
 	// Test case 3: Internal error during creation
@@ -68,9 +71,9 @@ func TestCreateTeacher(t *testing.T) {
 			LastName:  "Doe",
 			Username:  "johndoe",
 		}
-		mockTeacherStore.On("Create", mock.AnythingOfType("*models.Teacher")).Return(0, errors.New("db error")).Once()
+		mockTeacherStore.On("Create", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(0, errors.New("db error")).Once()
 
-		createdTeacher, err := service.CreateTeacher(teacher)
+		createdTeacher, err := service.CreateTeacher(t.Context(), teacher)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -81,15 +84,15 @@ func TestCreateTeacher(t *testing.T) {
 
 func TestGetTeacherByID(t *testing.T) {
 	mockTeacherStore := new(mocks.MockTeacherStore)
-	service := services.NewTeacherService(mockTeacherStore)
+	service := services.NewTeacherService(mockTeacherStore, clock.System{})
 
 	// Test case 1: Successful retrieval
 	t.Run("success", func(t *testing.T) {
 		teacherID := 1
 		expectedTeacher := &models.Teacher{ID: teacherID, FirstName: "Test Teacher", Username: "testteacher"}
-		mockTeacherStore.On("GetByID", teacherID).Return(expectedTeacher, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, teacherID).Return(expectedTeacher, nil).Once()
 
-		teacher, err := service.GetTeacherByID(teacherID)
+		teacher, err := service.GetTeacherByID(t.Context(), teacherID)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, teacher)
@@ -102,9 +105,9 @@ func TestGetTeacherByID(t *testing.T) {
 	// Test case 2: Teacher not found
 	t.Run("not found", func(t *testing.T) {
 		teacherID := 99
-		mockTeacherStore.On("GetByID", teacherID).Return(nil, data.ErrNotFound).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, teacherID).Return(nil, data.ErrNotFound).Once()
 
-		teacher, err := service.GetTeacherByID(teacherID)
+		teacher, err := service.GetTeacherByID(t.Context(), teacherID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
@@ -115,9 +118,9 @@ func TestGetTeacherByID(t *testing.T) {
 	// Test case 3: Internal error
 	t.Run("internal error", func(t *testing.T) {
 		teacherID := 1
-		mockTeacherStore.On("GetByID", teacherID).Return(nil, errors.New("db error")).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, teacherID).Return(nil, errors.New("db error")).Once()
 
-		teacher, err := service.GetTeacherByID(teacherID)
+		teacher, err := service.GetTeacherByID(t.Context(), teacherID)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -128,7 +131,7 @@ func TestGetTeacherByID(t *testing.T) {
 
 func TestUpdateTeacher(t *testing.T) {
 	mockTeacherStore := new(mocks.MockTeacherStore)
-	service := services.NewTeacherService(mockTeacherStore)
+	service := services.NewTeacherService(mockTeacherStore, clock.System{})
 
 	// Test case 1: Successful update
 	t.Run("success", func(t *testing.T) {
@@ -138,9 +141,9 @@ func TestUpdateTeacher(t *testing.T) {
 			LastName:  "Doe",
 			Username:  "johndoe",
 		}
-		mockTeacherStore.On("Update", mock.AnythingOfType("*models.Teacher")).Return(nil).Once()
+		mockTeacherStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(nil).Once()
 
-		err := service.UpdateTeacher(teacher)
+		err := service.UpdateTeacher(t.Context(), teacher)
 
 		assert.NoError(t, err)
 		mockTeacherStore.AssertExpectations(t)
@@ -155,11 +158,11 @@ func TestUpdateTeacher(t *testing.T) {
 			Username:  "johndoe",
 		}
 
-		err := service.UpdateTeacher(teacher)
+		err := service.UpdateTeacher(t.Context(), teacher)
 
 		assert.Error(t, err)
-		assert.Equal(t, services.ErrInvalidInput, err)
-		mockTeacherStore.AssertNotCalled(t, "Update")
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		mockTeacherStore.AssertNotCalled(t, "Update", mock.Anything)
 	})
 
 	// Test case 3: Teacher not found during update
@@ -170,9 +173,9 @@ func TestUpdateTeacher(t *testing.T) {
 			LastName:  "Doe",
 			Username:  "johndoe",
 		}
-		mockTeacherStore.On("Update", mock.AnythingOfType("*models.Teacher")).Return(data.ErrNotFound).Once()
+		mockTeacherStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(data.ErrNotFound).Once()
 
-		err := service.UpdateTeacher(teacher)
+		err := service.UpdateTeacher(t.Context(), teacher)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrNotFound, err)
@@ -187,9 +190,9 @@ func TestUpdateTeacher(t *testing.T) {
 			LastName:  "Doe",
 			Username:  "johndoe",
 		}
-		mockTeacherStore.On("Update", mock.AnythingOfType("*models.Teacher")).Return(errors.New("db error")).Once()
+		mockTeacherStore.On("Update", mock.Anything, mock.AnythingOfType("*models.Teacher")).Return(errors.New("db error")).Once()
 
-		err := service.UpdateTeacher(teacher)
+		err := service.UpdateTeacher(t.Context(), teacher)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -199,17 +202,21 @@ func TestUpdateTeacher(t *testing.T) {
 
 func TestGetAllTeachers(t *testing.T) {
 	mockTeacherStore := new(mocks.MockTeacherStore)
-	service := services.NewTeacherService(mockTeacherStore)
+	service := services.NewTeacherService(mockTeacherStore, clock.System{})
 
 	// Test case 1: Successful retrieval
 	t.Run("success", func(t *testing.T) {
-		expectedTeachers := []models.Teacher{
+		mockTeacherStore.On("GetAll", mock.Anything).Return([]models.Teacher{
 			{ID: 1, FirstName: "Teacher A", Username: "teachera"},
 			{ID: 2, FirstName: "Teacher B", Username: "teacherb"},
+		}, nil).Once()
+		mockTeacherStore.On("CountUsage", mock.Anything).Return(map[int]models.UsageCounts{2: {Entries: 4, OpenAssignments: 2, Children: 2}}, nil).Once()
+		expectedTeachers := []models.Teacher{
+			{ID: 1, FirstName: "Teacher A", Username: "teachera", Usage: &models.UsageCounts{}},
+			{ID: 2, FirstName: "Teacher B", Username: "teacherb", Usage: &models.UsageCounts{Entries: 4, OpenAssignments: 2, Children: 2}},
 		}
-		mockTeacherStore.On("GetAll").Return(expectedTeachers, nil).Once()
 
-		teachers, err := service.GetAllTeachers()
+		teachers, err := service.GetAllTeachers(t.Context(), false)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, teachers)
@@ -217,11 +224,34 @@ func TestGetAllTeachers(t *testing.T) {
 		mockTeacherStore.AssertExpectations(t)
 	})
 
+	t.Run("deactivated teachers are left out unless requested", func(t *testing.T) {
+		deactivatedAt := time.Now()
+		allTeachers := func(usage *models.UsageCounts) []models.Teacher {
+			return []models.Teacher{
+				{ID: 1, FirstName: "Teacher A", Username: "teachera", Usage: usage},
+				{ID: 2, FirstName: "Teacher B", Username: "teacherb", DeactivatedAt: &deactivatedAt, Usage: usage},
+			}
+		}
+		mockTeacherStore.On("GetAll", mock.Anything).Return(allTeachers(nil), nil).Once()
+		mockTeacherStore.On("CountUsage", mock.Anything).Return(map[int]models.UsageCounts{}, nil).Twice()
+
+		teachers, err := service.GetAllTeachers(t.Context(), false)
+		assert.NoError(t, err)
+		assert.Equal(t, allTeachers(&models.UsageCounts{})[:1], teachers)
+
+		mockTeacherStore.On("GetAll", mock.Anything).Return(allTeachers(nil), nil).Once()
+
+		teachers, err = service.GetAllTeachers(t.Context(), true)
+		assert.NoError(t, err)
+		assert.Equal(t, allTeachers(&models.UsageCounts{}), teachers)
+		mockTeacherStore.AssertExpectations(t)
+	})
+
 	// Test case 2: Internal error
 	t.Run("internal error", func(t *testing.T) {
-		mockTeacherStore.On("GetAll").Return(nil, errors.New("db error")).Once()
+		mockTeacherStore.On("GetAll", mock.Anything).Return(nil, errors.New("db error")).Once()
 
-		teachers, err := service.GetAllTeachers()
+		teachers, err := service.GetAllTeachers(t.Context(), false)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInternal, err)
@@ -229,3 +259,63 @@ func TestGetAllTeachers(t *testing.T) {
 		mockTeacherStore.AssertExpectations(t)
 	})
 }
+
+func TestDeactivateTeacher(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockTeacherStore := new(mocks.MockTeacherStore)
+		service := services.NewTeacherService(mockTeacherStore, clock.System{})
+		mockTeacherStore.On("GetByID", mock.Anything, 1).Return(&models.Teacher{ID: 1}, nil).Once()
+		mockTeacherStore.On("SetDeactivated", mock.Anything, 1, mock.AnythingOfType("*time.Time")).Return(nil).Once()
+
+		assert.NoError(t, service.DeactivateTeacher(t.Context(), 1))
+		mockTeacherStore.AssertExpectations(t)
+	})
+
+	t.Run("already deactivated keeps the date", func(t *testing.T) {
+		mockTeacherStore := new(mocks.MockTeacherStore)
+		service := services.NewTeacherService(mockTeacherStore, clock.System{})
+		deactivatedAt := time.Now().AddDate(0, -1, 0)
+		mockTeacherStore.On("GetByID", mock.Anything, 1).Return(&models.Teacher{ID: 1, DeactivatedAt: &deactivatedAt}, nil).Once()
+
+		assert.NoError(t, service.DeactivateTeacher(t.Context(), 1))
+		mockTeacherStore.AssertExpectations(t)
+	})
+
+	t.Run("reactivate unknown teacher", func(t *testing.T) {
+		mockTeacherStore := new(mocks.MockTeacherStore)
+		service := services.NewTeacherService(mockTeacherStore, clock.System{})
+		mockTeacherStore.On("SetDeactivated", mock.Anything, 99, (*time.Time)(nil)).Return(data.ErrNotFound).Once()
+
+		assert.Equal(t, services.ErrNotFound, service.ReactivateTeacher(t.Context(), 99))
+		mockTeacherStore.AssertExpectations(t)
+	})
+}
+
+func TestMergeTeachers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockTeacherStore := new(mocks.MockTeacherStore)
+		service := services.NewTeacherService(mockTeacherStore, clock.System{})
+		mockTeacherStore.On("GetByID", mock.Anything, 1).Return(&models.Teacher{ID: 1}, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, 2).Return(&models.Teacher{ID: 2}, nil).Once()
+		mockTeacherStore.On("Merge", mock.Anything, 2, 1).Return(nil).Once()
+
+		assert.NoError(t, service.MergeTeachers(t.Context(), 2, 1))
+		mockTeacherStore.AssertExpectations(t)
+	})
+
+	t.Run("into itself", func(t *testing.T) {
+		service := services.NewTeacherService(new(mocks.MockTeacherStore), clock.System{})
+
+		assert.Equal(t, services.ErrInvalidInput, service.MergeTeachers(t.Context(), 1, 1))
+	})
+
+	t.Run("unknown duplicate", func(t *testing.T) {
+		mockTeacherStore := new(mocks.MockTeacherStore)
+		service := services.NewTeacherService(mockTeacherStore, clock.System{})
+		mockTeacherStore.On("GetByID", mock.Anything, 1).Return(&models.Teacher{ID: 1}, nil).Once()
+		mockTeacherStore.On("GetByID", mock.Anything, 99).Return(nil, data.ErrNotFound).Once()
+
+		assert.Equal(t, services.ErrNotFound, service.MergeTeachers(t.Context(), 99, 1))
+		mockTeacherStore.AssertExpectations(t)
+	})
+}
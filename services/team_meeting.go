@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TeamMeetingService defines the interface for the minutes of team meetings and the action items agreed on in them.
+type TeamMeetingService interface {
+	CreateMeeting(logger *logrus.Entry, ctx context.Context, meeting *models.TeamMeeting, user *models.User) (*models.TeamMeeting, error)
+	GetMeetingByID(logger *logrus.Entry, ctx context.Context, id int) (*models.TeamMeeting, error)
+	GetAllMeetings(logger *logrus.Entry, ctx context.Context) ([]models.TeamMeeting, error)
+	// GetMeetingsForChild fetches the meetings the child was discussed in or got an action item in, so that the
+	// decisions about a child can be traced alongside its documentation.
+	GetMeetingsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.TeamMeeting, error)
+	UpdateMeeting(logger *logrus.Entry, ctx context.Context, meeting *models.TeamMeeting) error
+	DeleteMeeting(logger *logrus.Entry, ctx context.Context, id int) error
+	CreateActionItem(logger *logrus.Entry, ctx context.Context, item *models.ActionItem) (*models.ActionItem, error)
+	GetActionItemByID(logger *logrus.Entry, ctx context.Context, id int) (*models.ActionItem, error)
+	UpdateActionItem(logger *logrus.Entry, ctx context.Context, item *models.ActionItem) error
+	// SetActionItemCompleted completes an action item now, or reopens it.
+	SetActionItemCompleted(logger *logrus.Entry, ctx context.Context, id int, completed bool) error
+	DeleteActionItem(logger *logrus.Entry, ctx context.Context, id int) error
+	// GetOpenActionItems fetches the open action items, the earliest due first, for the dashboard. A teacher ID
+	// narrows them down to the items assigned to the teacher.
+	GetOpenActionItems(logger *logrus.Entry, ctx context.Context, teacherID *int) ([]models.ActionItem, error)
+}
+
+// TeamMeetingServiceImpl implements TeamMeetingService.
+type TeamMeetingServiceImpl struct {
+	teamMeetingStore data.TeamMeetingStore
+	teacherStore     data.TeacherStore
+	childStore       data.ChildStore
+	clock            clock.Clock
+}
+
+// NewTeamMeetingService creates a new TeamMeetingServiceImpl.
+func NewTeamMeetingService(teamMeetingStore data.TeamMeetingStore, teacherStore data.TeacherStore, childStore data.ChildStore, clock clock.Clock) *TeamMeetingServiceImpl {
+	return &TeamMeetingServiceImpl{
+		teamMeetingStore: teamMeetingStore,
+		teacherStore:     teacherStore,
+		childStore:       childStore,
+		clock:            clock,
+	}
+}
+
+// CreateMeeting records the minutes of a meeting, the user is recorded as their author.
+func (service *TeamMeetingServiceImpl) CreateMeeting(logger *logrus.Entry, ctx context.Context, meeting *models.TeamMeeting, user *models.User) (*models.TeamMeeting, error) {
+	if err := service.validateMeeting(logger, ctx, meeting); err != nil {
+		return nil, err
+	}
+	meeting.RecordedByUserID = &user.ID
+
+	id, err := service.teamMeetingStore.Create(ctx, meeting)
+	if err != nil {
+		logger.WithError(err).Error("Error creating team meeting")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"meeting_id": id, "children": len(meeting.ChildIDs)}).Info("Team meeting created successfully")
+	return service.GetMeetingByID(logger, ctx, id)
+}
+
+// GetMeetingByID fetches a meeting with its action items by ID.
+func (service *TeamMeetingServiceImpl) GetMeetingByID(logger *logrus.Entry, ctx context.Context, id int) (*models.TeamMeeting, error) {
+	meeting, err := service.teamMeetingStore.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrTeamMeetingNotFound
+		}
+		logger.WithError(err).WithField("meeting_id", id).Error("Error fetching team meeting")
+		return nil, ErrInternal
+	}
+	service.markOverdue(meeting.ActionItems)
+	return meeting, nil
+}
+
+// GetAllMeetings fetches all meetings, the latest first.
+func (service *TeamMeetingServiceImpl) GetAllMeetings(logger *logrus.Entry, ctx context.Context) ([]models.TeamMeeting, error) {
+	meetings, err := service.teamMeetingStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching team meetings")
+		return nil, ErrInternal
+	}
+	for i := range meetings {
+		service.markOverdue(meetings[i].ActionItems)
+	}
+	return meetings, nil
+}
+
+// GetMeetingsForChild fetches the meetings concerning a child, the latest first.
+func (service *TeamMeetingServiceImpl) GetMeetingsForChild(logger *logrus.Entry, ctx context.Context, childID int) ([]models.TeamMeeting, error) {
+	if err := service.checkChild(logger, ctx, childID); err != nil {
+		return nil, err
+	}
+	meetings, err := service.teamMeetingStore.GetForChild(ctx, childID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching team meetings of child")
+		return nil, ErrInternal
+	}
+	for i := range meetings {
+		service.markOverdue(meetings[i].ActionItems)
+	}
+	return meetings, nil
+}
+
+// UpdateMeeting updates the minutes of a meeting and replaces its attendees and children.
+func (service *TeamMeetingServiceImpl) UpdateMeeting(logger *logrus.Entry, ctx context.Context, meeting *models.TeamMeeting) error {
+	if err := service.validateMeeting(logger, ctx, meeting); err != nil {
+		return err
+	}
+	if err := service.teamMeetingStore.Update(ctx, meeting); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrTeamMeetingNotFound
+		}
+		logger.WithError(err).WithField("meeting_id", meeting.ID).Error("Error updating team meeting")
+		return ErrInternal
+	}
+	logger.WithField("meeting_id", meeting.ID).Info("Team meeting updated successfully")
+	return nil
+}
+
+// DeleteMeeting deletes a meeting with its action items.
+func (service *TeamMeetingServiceImpl) DeleteMeeting(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.teamMeetingStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrTeamMeetingNotFound
+		}
+		logger.WithError(err).WithField("meeting_id", id).Error("Error deleting team meeting")
+		return ErrInternal
+	}
+	logger.WithField("meeting_id", id).Info("Team meeting deleted successfully")
+	return nil
+}
+
+// CreateActionItem adds an action item to a meeting. Deactivated teachers cannot be assigned new items.
+func (service *TeamMeetingServiceImpl) CreateActionItem(logger *logrus.Entry, ctx context.Context, item *models.ActionItem) (*models.ActionItem, error) {
+	if _, err := service.GetMeetingByID(logger, ctx, item.MeetingID); err != nil {
+		return nil, err
+	}
+	if err := service.validateActionItem(logger, ctx, item); err != nil {
+		return nil, err
+	}
+
+	id, err := service.teamMeetingStore.CreateActionItem(ctx, item)
+	if err != nil {
+		logger.WithError(err).WithField("meeting_id", item.MeetingID).Error("Error creating action item")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"action_item_id": id, "meeting_id": item.MeetingID, "teacher_id": item.TeacherID}).Info("Action item created successfully")
+	return service.GetActionItemByID(logger, ctx, id)
+}
+
+// GetActionItemByID fetches an action item by ID.
+func (service *TeamMeetingServiceImpl) GetActionItemByID(logger *logrus.Entry, ctx context.Context, id int) (*models.ActionItem, error) {
+	item, err := service.teamMeetingStore.GetActionItemByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrActionItemNotFound
+		}
+		logger.WithError(err).WithField("action_item_id", id).Error("Error fetching action item")
+		return nil, ErrInternal
+	}
+	items := []models.ActionItem{*item}
+	service.markOverdue(items)
+	return &items[0], nil
+}
+
+// UpdateActionItem updates the description, assignee, child and due date of an action item, its meeting cannot be changed.
+func (service *TeamMeetingServiceImpl) UpdateActionItem(logger *logrus.Entry, ctx context.Context, item *models.ActionItem) error {
+	existing, err := service.GetActionItemByID(logger, ctx, item.ID)
+	if err != nil {
+		return err
+	}
+	item.MeetingID = existing.MeetingID
+	if existing.TeacherID == item.TeacherID {
+		// The assignee of an existing item may have been deactivated since, the item can still be changed.
+		if err := models.ValidateActionItem(*item); err != nil {
+			logger.WithError(err).Warn("Invalid input for action item")
+			return invalidInput(err)
+		}
+		if item.ChildID != nil {
+			if err := service.checkChild(logger, ctx, *item.ChildID); err != nil {
+				return err
+			}
+		}
+	} else if err := service.validateActionItem(logger, ctx, item); err != nil {
+		return err
+	}
+
+	if err := service.teamMeetingStore.UpdateActionItem(ctx, item); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrActionItemNotFound
+		}
+		logger.WithError(err).WithField("action_item_id", item.ID).Error("Error updating action item")
+		return ErrInternal
+	}
+	logger.WithField("action_item_id", item.ID).Info("Action item updated successfully")
+	return nil
+}
+
+// SetActionItemCompleted completes or reopens an action item.
+func (service *TeamMeetingServiceImpl) SetActionItemCompleted(logger *logrus.Entry, ctx context.Context, id int, completed bool) error {
+	var completedAt *time.Time
+	if completed {
+		now := service.clock.Now()
+		completedAt = &now
+	}
+	if err := service.teamMeetingStore.SetActionItemCompleted(ctx, id, completedAt); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrActionItemNotFound
+		}
+		logger.WithError(err).WithField("action_item_id", id).Error("Error completing action item")
+		return ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"action_item_id": id, "completed": completed}).Info("Action item completion changed")
+	return nil
+}
+
+// DeleteActionItem deletes an action item.
+func (service *TeamMeetingServiceImpl) DeleteActionItem(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.teamMeetingStore.DeleteActionItem(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrActionItemNotFound
+		}
+		logger.WithError(err).WithField("action_item_id", id).Error("Error deleting action item")
+		return ErrInternal
+	}
+	logger.WithField("action_item_id", id).Info("Action item deleted successfully")
+	return nil
+}
+
+// GetOpenActionItems fetches the open action items, optionally only those of a teacher.
+func (service *TeamMeetingServiceImpl) GetOpenActionItems(logger *logrus.Entry, ctx context.Context, teacherID *int) ([]models.ActionItem, error) {
+	items, err := service.teamMeetingStore.GetOpenActionItems(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching open action items")
+		return nil, ErrInternal
+	}
+	open := []models.ActionItem{}
+	for _, item := range items {
+		if teacherID == nil || item.TeacherID == *teacherID {
+			open = append(open, item)
+		}
+	}
+	service.markOverdue(open)
+	return open, nil
+}
+
+// markOverdue flags the open items due before today in the facility.
+func (service *TeamMeetingServiceImpl) markOverdue(items []models.ActionItem) {
+	today := models.Today(service.clock.Now())
+	for i := range items {
+		items[i].Overdue = items[i].IsOpen() && items[i].DueDate.Before(today.Time)
+	}
+}
+
+// validateMeeting checks the meeting and that its attendees and children exist.
+func (service *TeamMeetingServiceImpl) validateMeeting(logger *logrus.Entry, ctx context.Context, meeting *models.TeamMeeting) error {
+	if err := models.ValidateTeamMeeting(*meeting); err != nil {
+		logger.WithError(err).Warn("Invalid input for team meeting")
+		return invalidInput(err)
+	}
+	for _, teacherID := range meeting.AttendeeTeacherIDs {
+		if _, err := service.teacherStore.GetByID(ctx, teacherID); err != nil {
+			if errors.Is(err, data.ErrNotFound) {
+				logger.WithField("teacher_id", teacherID).Warn("Attendee not found for team meeting")
+				return ErrTeacherNotFound
+			}
+			logger.WithError(err).WithField("teacher_id", teacherID).Error("Error fetching attendee of team meeting")
+			return ErrInternal
+		}
+	}
+	for _, childID := range meeting.ChildIDs {
+		if err := service.checkChild(logger, ctx, childID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateActionItem checks the action item and that it is assigned to an active teacher and about an existing child.
+func (service *TeamMeetingServiceImpl) validateActionItem(logger *logrus.Entry, ctx context.Context, item *models.ActionItem) error {
+	if err := models.ValidateActionItem(*item); err != nil {
+		logger.WithError(err).Warn("Invalid input for action item")
+		return invalidInput(err)
+	}
+	teacher, err := service.teacherStore.GetByID(ctx, item.TeacherID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("teacher_id", item.TeacherID).Warn("Teacher not found for action item")
+			return ErrTeacherNotFound
+		}
+		logger.WithError(err).WithField("teacher_id", item.TeacherID).Error("Error fetching teacher for action item")
+		return ErrInternal
+	}
+	if !teacher.IsActive() {
+		logger.WithField("teacher_id", item.TeacherID).Warn("Action item assigned to a deactivated teacher")
+		return ErrTeacherDeactivated
+	}
+	if item.ChildID != nil {
+		return service.checkChild(logger, ctx, *item.ChildID)
+	}
+	return nil
+}
+
+func (service *TeamMeetingServiceImpl) checkChild(logger *logrus.Entry, ctx context.Context, childID int) error {
+	if _, err := service.childStore.GetByID(ctx, childID); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.WithField("child_id", childID).Warn("Child not found for team meeting")
+			return ErrChildNotFound
+		}
+		logger.WithError(err).WithField("child_id", childID).Error("Error fetching child for team meeting")
+		return ErrInternal
+	}
+	return nil
+}
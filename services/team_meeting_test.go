@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamMeetingService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2024, time.October, 16, 10, 0, 0, 0, time.UTC)
+	deactivatedAt := now.AddDate(0, -1, 0)
+
+	setup := func() (*services.TeamMeetingServiceImpl, *datamocks.MockTeamMeetingStore, *datamocks.MockTeacherStore, *datamocks.MockChildStore) {
+		meetingStore := new(datamocks.MockTeamMeetingStore)
+		teacherStore := new(datamocks.MockTeacherStore)
+		childStore := new(datamocks.MockChildStore)
+		teacherStore.On("GetByID", mock.Anything, 1).Return(&models.Teacher{ID: 1}, nil).Maybe()
+		teacherStore.On("GetByID", mock.Anything, 2).Return(&models.Teacher{ID: 2, DeactivatedAt: &deactivatedAt}, nil).Maybe()
+		teacherStore.On("GetByID", mock.Anything, 9).Return(nil, data.ErrNotFound).Maybe()
+		childStore.On("GetByID", mock.Anything, 1).Return(&models.Child{ID: 1}, nil).Maybe()
+		childStore.On("GetByID", mock.Anything, 9).Return(nil, data.ErrNotFound).Maybe()
+		return services.NewTeamMeetingService(meetingStore, teacherStore, childStore, clock.NewFrozen(now)), meetingStore, teacherStore, childStore
+	}
+
+	t.Run("create checks attendees and children and records the author", func(t *testing.T) {
+		service, meetingStore, _, _ := setup()
+		meeting := func(teacherID int, childID int) *models.TeamMeeting {
+			return &models.TeamMeeting{MeetingDate: models.NewDate(2024, time.October, 14), Title: "Fallbesprechung",
+				Minutes: "Gespräch mit den Eltern vereinbart.", AttendeeTeacherIDs: []int{teacherID}, ChildIDs: []int{childID}}
+		}
+		user := &models.User{ID: 4}
+
+		_, err := service.CreateMeeting(logger, ctx, meeting(9, 1), user)
+		assert.ErrorIs(t, err, services.ErrTeacherNotFound)
+		_, err = service.CreateMeeting(logger, ctx, meeting(1, 9), user)
+		assert.ErrorIs(t, err, services.ErrChildNotFound)
+		_, err = service.CreateMeeting(logger, ctx, &models.TeamMeeting{Title: "Ohne Protokoll"}, user)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		meetingStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+		meetingStore.On("Create", mock.Anything, mock.MatchedBy(func(meeting *models.TeamMeeting) bool {
+			return meeting.RecordedByUserID != nil && *meeting.RecordedByUserID == 4
+		})).Return(3, nil).Once()
+		meetingStore.On("GetByID", mock.Anything, 3).Return(&models.TeamMeeting{ID: 3}, nil).Once()
+		created, err := service.CreateMeeting(logger, ctx, meeting(1, 1), user)
+		require.NoError(t, err)
+		assert.Equal(t, 3, created.ID)
+	})
+
+	t.Run("action items are not assigned to deactivated teachers", func(t *testing.T) {
+		service, meetingStore, _, _ := setup()
+		meetingStore.On("GetByID", mock.Anything, 3).Return(&models.TeamMeeting{ID: 3}, nil)
+		meetingStore.On("GetByID", mock.Anything, 9).Return(nil, data.ErrNotFound)
+		item := func(meetingID int, teacherID int) *models.ActionItem {
+			return &models.ActionItem{MeetingID: meetingID, Description: "Elterngespräch führen", TeacherID: teacherID,
+				DueDate: models.NewDate(2024, time.October, 21)}
+		}
+
+		_, err := service.CreateActionItem(logger, ctx, item(9, 1))
+		assert.ErrorIs(t, err, services.ErrTeamMeetingNotFound)
+		_, err = service.CreateActionItem(logger, ctx, item(3, 2))
+		assert.ErrorIs(t, err, services.ErrTeacherDeactivated)
+		_, err = service.CreateActionItem(logger, ctx, item(3, 9))
+		assert.ErrorIs(t, err, services.ErrTeacherNotFound)
+		meetingStore.AssertNotCalled(t, "CreateActionItem", mock.Anything, mock.Anything)
+	})
+
+	t.Run("open items are filtered by teacher and flagged overdue", func(t *testing.T) {
+		service, meetingStore, _, _ := setup()
+		meetingStore.On("GetOpenActionItems", mock.Anything).Return([]models.ActionItem{
+			{ID: 1, TeacherID: 1, DueDate: models.NewDate(2024, time.October, 15)},
+			{ID: 2, TeacherID: 2, DueDate: models.NewDate(2024, time.October, 15)},
+			{ID: 3, TeacherID: 1, DueDate: models.NewDate(2024, time.October, 16)},
+		}, nil)
+
+		teacherID := 1
+		items, err := service.GetOpenActionItems(logger, ctx, &teacherID)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.True(t, items[0].Overdue)
+		assert.False(t, items[1].Overdue, "an item due today is not overdue yet")
+
+		items, err = service.GetOpenActionItems(logger, ctx, nil)
+		require.NoError(t, err)
+		assert.Len(t, items, 3)
+	})
+
+	t.Run("completing stamps the current time", func(t *testing.T) {
+		service, meetingStore, _, _ := setup()
+		meetingStore.On("SetActionItemCompleted", mock.Anything, 1, &now).Return(nil).Once()
+		meetingStore.On("SetActionItemCompleted", mock.Anything, 1, (*time.Time)(nil)).Return(nil).Once()
+		meetingStore.On("SetActionItemCompleted", mock.Anything, 9, mock.Anything).Return(data.ErrNotFound)
+
+		require.NoError(t, service.SetActionItemCompleted(logger, ctx, 1, true))
+		require.NoError(t, service.SetActionItemCompleted(logger, ctx, 1, false))
+		assert.ErrorIs(t, service.SetActionItemCompleted(logger, ctx, 9, true), services.ErrActionItemNotFound)
+		meetingStore.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TermsService defines the interface for the versioned terms of use and privacy policy.
+type TermsService interface {
+	PublishTerms(logger *logrus.Entry, ctx context.Context, actingUserID int, version *models.TermsVersion) (*models.TermsVersion, error)
+	GetTermsVersions(logger *logrus.Entry, ctx context.Context) ([]models.TermsVersion, error)
+	GetTermsStatus(logger *logrus.Entry, ctx context.Context, userID int) (*models.TermsStatus, error)
+	AcceptTerms(logger *logrus.Entry, ctx context.Context, userID int, version int) (*models.TermsStatus, error)
+	GetPendingTermsVersion(logger *logrus.Entry, ctx context.Context, userID int) (*models.TermsVersion, error)
+}
+
+// TermsServiceImpl implements TermsService.
+type TermsServiceImpl struct {
+	termsStore      data.TermsStore
+	auditLogService AuditLogService // Optional, nil disables the audit trail
+}
+
+// NewTermsService creates a new TermsServiceImpl.
+func NewTermsService(termsStore data.TermsStore, auditLogService AuditLogService) *TermsServiceImpl {
+	return &TermsServiceImpl{
+		termsStore:      termsStore,
+		auditLogService: auditLogService,
+	}
+}
+
+// PublishTerms publishes a new terms version. From then on every user has to accept it before using the API.
+func (service *TermsServiceImpl) PublishTerms(logger *logrus.Entry, ctx context.Context, actingUserID int, version *models.TermsVersion) (*models.TermsVersion, error) {
+	if err := models.ValidateTermsVersion(*version); err != nil {
+		logger.WithError(err).Warn("Invalid input for PublishTerms")
+		return nil, invalidInput(err)
+	}
+	version.PublishedByUserID = &actingUserID
+
+	number, err := service.termsStore.Publish(ctx, version)
+	if err != nil {
+		logger.WithError(err).Error("Error publishing terms version")
+		return nil, ErrInternal
+	}
+	published, err := service.termsStore.GetCurrent(ctx)
+	if err != nil {
+		logger.WithError(err).WithField("version", number).Error("Error fetching published terms version")
+		return nil, ErrInternal
+	}
+
+	if service.auditLogService != nil {
+		// The version has already been published, so a failing audit write is only logged.
+		_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+			Action:      models.AuditActionPublishTerms,
+			EntityType:  "terms_version",
+			EntityID:    &number,
+			ActorUserID: &actingUserID,
+		})
+	}
+	logger.WithField("version", number).Info("Terms version published")
+	return published, nil
+}
+
+// GetTermsVersions fetches all published terms versions, newest first.
+func (service *TermsServiceImpl) GetTermsVersions(logger *logrus.Entry, ctx context.Context) ([]models.TermsVersion, error) {
+	versions, err := service.termsStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching terms versions")
+		return nil, ErrInternal
+	}
+	return versions, nil
+}
+
+// GetTermsStatus fetches the current terms version and the last one the user accepted.
+func (service *TermsServiceImpl) GetTermsStatus(logger *logrus.Entry, ctx context.Context, userID int) (*models.TermsStatus, error) {
+	status := &models.TermsStatus{}
+	current, err := service.termsStore.GetCurrent(ctx)
+	if err != nil && !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).Error("Error fetching current terms version")
+		return nil, ErrInternal
+	}
+	status.Current = current
+
+	acceptance, err := service.termsStore.GetLastAcceptance(ctx, userID)
+	if err != nil && !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).WithField("user_id", userID).Error("Error fetching policy acceptance")
+		return nil, ErrInternal
+	}
+	status.LastAccepted = acceptance
+	status.AcceptanceRequired = current != nil && (acceptance == nil || acceptance.Version < current.Version)
+	return status, nil
+}
+
+// AcceptTerms records that a user has accepted the current terms version. Only the current version can be
+// accepted, so that a client showing an outdated text cannot accept on the user's behalf.
+func (service *TermsServiceImpl) AcceptTerms(logger *logrus.Entry, ctx context.Context, userID int, version int) (*models.TermsStatus, error) {
+	current, err := service.termsStore.GetCurrent(ctx)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		logger.WithError(err).Error("Error fetching current terms version")
+		return nil, ErrInternal
+	}
+	if version != current.Version {
+		logger.WithFields(logrus.Fields{"version": version, "current_version": current.Version}).Warn("Acceptance of a terms version that is not current")
+		return nil, fmt.Errorf("%w: version %d is not the current terms version %d", ErrInvalidInput, version, current.Version)
+	}
+
+	accepted, err := service.termsStore.Accept(ctx, userID, version)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{"user_id": userID, "version": version}).Error("Error accepting terms version")
+		return nil, ErrInternal
+	}
+	if accepted && service.auditLogService != nil {
+		// The acceptance has already been stored, so a failing audit write is only logged.
+		_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+			Action:      models.AuditActionAcceptTerms,
+			EntityType:  "terms_version",
+			EntityID:    &version,
+			ActorUserID: &userID,
+		})
+	}
+	logger.WithFields(logrus.Fields{"user_id": userID, "version": version}).Info("Terms version accepted")
+	return service.GetTermsStatus(logger, ctx, userID)
+}
+
+// GetPendingTermsVersion fetches the current terms version if the user still has to accept it, nil otherwise.
+func (service *TermsServiceImpl) GetPendingTermsVersion(logger *logrus.Entry, ctx context.Context, userID int) (*models.TermsVersion, error) {
+	pending, err := service.termsStore.GetPendingForUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, nil
+		}
+		logger.WithError(err).WithField("user_id", userID).Error("Error fetching pending terms version")
+		return nil, ErrInternal
+	}
+	return pending, nil
+}
@@ -0,0 +1,150 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTermsService() (*services.TermsServiceImpl, *datamocks.MockTermsStore, *datamocks.MockAuditLogStore) {
+	termsStore := new(datamocks.MockTermsStore)
+	auditLogStore := new(datamocks.MockAuditLogStore)
+	return services.NewTermsService(termsStore, services.NewAuditLogService(auditLogStore)), termsStore, auditLogStore
+}
+
+func TestPublishTerms(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		service, termsStore, auditLogStore := newTermsService()
+		version := &models.TermsVersion{TermsOfUse: "Nutzung", PrivacyPolicy: "Datenschutz"}
+		termsStore.On("Publish", mock.Anything, version).Return(2, nil).Once()
+		termsStore.On("GetCurrent", mock.Anything).Return(&models.TermsVersion{Version: 2, TermsOfUse: "Nutzung", PrivacyPolicy: "Datenschutz"}, nil).Once()
+		auditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionPublishTerms && *entry.EntityID == 2 && *entry.ActorUserID == 1
+		})).Return(1, nil).Once()
+
+		published, err := service.PublishTerms(logger, ctx, 1, version)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, published.Version)
+		assert.Equal(t, 1, *version.PublishedByUserID)
+		auditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("both documents are required", func(t *testing.T) {
+		service, termsStore, _ := newTermsService()
+
+		_, err := service.PublishTerms(logger, ctx, 1, &models.TermsVersion{TermsOfUse: "Nutzung"})
+
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		termsStore.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetTermsStatus(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("updated terms are pending", func(t *testing.T) {
+		service, termsStore, _ := newTermsService()
+		termsStore.On("GetCurrent", mock.Anything).Return(&models.TermsVersion{Version: 3}, nil).Once()
+		termsStore.On("GetLastAcceptance", mock.Anything, 7).Return(&models.TermsAcceptance{UserID: 7, Version: 2}, nil).Once()
+
+		status, err := service.GetTermsStatus(logger, ctx, 7)
+
+		require.NoError(t, err)
+		assert.True(t, status.AcceptanceRequired)
+		assert.Equal(t, 2, status.LastAccepted.Version)
+	})
+
+	t.Run("nothing published", func(t *testing.T) {
+		service, termsStore, _ := newTermsService()
+		termsStore.On("GetCurrent", mock.Anything).Return(nil, data.ErrNotFound).Once()
+		termsStore.On("GetLastAcceptance", mock.Anything, 7).Return(nil, data.ErrNotFound).Once()
+
+		status, err := service.GetTermsStatus(logger, ctx, 7)
+
+		require.NoError(t, err)
+		assert.False(t, status.AcceptanceRequired)
+		assert.Nil(t, status.Current)
+	})
+}
+
+func TestAcceptTerms(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("accepts and audits the current version", func(t *testing.T) {
+		service, termsStore, auditLogStore := newTermsService()
+		termsStore.On("GetCurrent", mock.Anything).Return(&models.TermsVersion{Version: 3}, nil)
+		termsStore.On("Accept", mock.Anything, 7, 3).Return(true, nil).Once()
+		termsStore.On("GetLastAcceptance", mock.Anything, 7).Return(&models.TermsAcceptance{UserID: 7, Version: 3}, nil).Once()
+		auditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionAcceptTerms && entry.EntityType == "terms_version" && *entry.EntityID == 3 && *entry.ActorUserID == 7
+		})).Return(1, nil).Once()
+
+		status, err := service.AcceptTerms(logger, ctx, 7, 3)
+
+		require.NoError(t, err)
+		assert.False(t, status.AcceptanceRequired)
+		auditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("accepting again is not audited twice", func(t *testing.T) {
+		service, termsStore, auditLogStore := newTermsService()
+		termsStore.On("GetCurrent", mock.Anything).Return(&models.TermsVersion{Version: 3}, nil)
+		termsStore.On("Accept", mock.Anything, 7, 3).Return(false, nil).Once()
+		termsStore.On("GetLastAcceptance", mock.Anything, 7).Return(&models.TermsAcceptance{UserID: 7, Version: 3}, nil).Once()
+
+		_, err := service.AcceptTerms(logger, ctx, 7, 3)
+
+		require.NoError(t, err)
+		auditLogStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("outdated version", func(t *testing.T) {
+		service, termsStore, _ := newTermsService()
+		termsStore.On("GetCurrent", mock.Anything).Return(&models.TermsVersion{Version: 3}, nil).Once()
+
+		_, err := service.AcceptTerms(logger, ctx, 7, 2)
+
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		termsStore.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("nothing published", func(t *testing.T) {
+		service, termsStore, _ := newTermsService()
+		termsStore.On("GetCurrent", mock.Anything).Return(nil, data.ErrNotFound).Once()
+
+		_, err := service.AcceptTerms(logger, ctx, 7, 1)
+
+		assert.ErrorIs(t, err, services.ErrNotFound)
+	})
+}
+
+func TestGetPendingTermsVersion(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	service, termsStore, _ := newTermsService()
+	termsStore.On("GetPendingForUser", mock.Anything, 7).Return(&models.TermsVersion{Version: 3}, nil).Once()
+	termsStore.On("GetPendingForUser", mock.Anything, 8).Return(nil, data.ErrNotFound).Once()
+
+	pending, err := service.GetPendingTermsVersion(logger, ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 3, pending.Version)
+
+	pending, err = service.GetPendingTermsVersion(logger, ctx, 8)
+	require.NoError(t, err)
+	assert.Nil(t, pending)
+}
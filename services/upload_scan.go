@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UploadScanService defines the interface for scanning uploaded files for malware and for the quarantine of the
+// rejected files.
+type UploadScanService interface {
+	// ScanUpload checks an uploaded file of a kind before it is stored. A file with malware is quarantined and
+	// rejected with ErrUploadInfected, a file that cannot be scanned is rejected with ErrScanUnavailable.
+	ScanUpload(logger *logrus.Entry, ctx context.Context, kind string, fileName string, content []byte, user *models.User) error
+	// GetQuarantinedUploads returns the rejected uploads without their content, the latest first.
+	GetQuarantinedUploads(logger *logrus.Entry, ctx context.Context) ([]models.QuarantinedUpload, error)
+	// GetQuarantinedUpload returns a rejected upload with its content, e.g. to report a false positive.
+	GetQuarantinedUpload(logger *logrus.Entry, ctx context.Context, id int, actingUserID int) (*models.QuarantinedUpload, error)
+	DeleteQuarantinedUpload(logger *logrus.Entry, ctx context.Context, id int, actingUserID int) error
+}
+
+// UploadScanServiceImpl implements UploadScanService.
+type UploadScanServiceImpl struct {
+	scanner                VirusScanner
+	quarantinedUploadStore data.QuarantinedUploadStore
+	auditLogService        AuditLogService
+	clock                  clock.Clock
+}
+
+// NewUploadScanService creates a new UploadScanServiceImpl.
+func NewUploadScanService(
+	scanner VirusScanner,
+	quarantinedUploadStore data.QuarantinedUploadStore,
+	auditLogService AuditLogService,
+	clock clock.Clock,
+) *UploadScanServiceImpl {
+	return &UploadScanServiceImpl{
+		scanner:                scanner,
+		quarantinedUploadStore: quarantinedUploadStore,
+		auditLogService:        auditLogService,
+		clock:                  clock,
+	}
+}
+
+// ScanUpload scans the content and moves a file with malware into the quarantine, recording it in the audit log.
+// Scanning fails closed, a file is never stored unscanned while the scanner is unreachable.
+func (service *UploadScanServiceImpl) ScanUpload(logger *logrus.Entry, ctx context.Context, kind string, fileName string, content []byte, user *models.User) error {
+	signature, err := service.scanner.Scan(ctx, content)
+	if err != nil {
+		logger.WithError(err).WithField("kind", kind).Error("Error scanning upload for malware")
+		return ErrScanUnavailable
+	}
+	if signature == "" {
+		return nil
+	}
+
+	upload := &models.QuarantinedUpload{
+		Kind:          kind,
+		FileName:      fileName,
+		Signature:     signature,
+		Content:       content,
+		QuarantinedAt: service.clock.Now().UTC(),
+	}
+	var actorUserID *int
+	if user != nil {
+		actorUserID = &user.ID
+		upload.UploadedByUserID = &user.ID
+	}
+	id, err := service.quarantinedUploadStore.Create(ctx, upload)
+	if err != nil {
+		logger.WithError(err).WithField("kind", kind).Error("Error quarantining infected upload")
+		return ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"kind": kind, "signature": signature, "quarantine_id": id}).Warn("Infected upload quarantined")
+
+	// The file has already been quarantined, so a failing audit write is only logged.
+	_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:      models.AuditActionQuarantineUpload,
+		EntityType:  "quarantined_upload",
+		EntityID:    &id,
+		ActorUserID: actorUserID,
+		Details:     fmt.Sprintf("kind=%s signature=%s", kind, signature),
+	})
+	return ErrUploadInfected
+}
+
+// GetQuarantinedUploads returns all rejected uploads.
+func (service *UploadScanServiceImpl) GetQuarantinedUploads(logger *logrus.Entry, ctx context.Context) ([]models.QuarantinedUpload, error) {
+	uploads, err := service.quarantinedUploadStore.GetAll(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching quarantined uploads")
+		return nil, ErrInternal
+	}
+	return uploads, nil
+}
+
+// GetQuarantinedUpload returns a rejected upload with its content and records the download in the audit log.
+func (service *UploadScanServiceImpl) GetQuarantinedUpload(logger *logrus.Entry, ctx context.Context, id int, actingUserID int) (*models.QuarantinedUpload, error) {
+	upload, err := service.quarantinedUploadStore.GetWithContent(ctx, id)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrQuarantineNotFound
+		}
+		logger.WithError(err).WithField("quarantine_id", id).Error("Error fetching quarantined upload")
+		return nil, ErrInternal
+	}
+	if err := service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:      models.AuditActionDownloadQuarantinedUpload,
+		EntityType:  "quarantined_upload",
+		EntityID:    &id,
+		ActorUserID: &actingUserID,
+		Details:     fmt.Sprintf("kind=%s signature=%s", upload.Kind, upload.Signature),
+	}); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// DeleteQuarantinedUpload deletes a rejected upload for good and records it in the audit log.
+func (service *UploadScanServiceImpl) DeleteQuarantinedUpload(logger *logrus.Entry, ctx context.Context, id int, actingUserID int) error {
+	if err := service.quarantinedUploadStore.Delete(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrQuarantineNotFound
+		}
+		logger.WithError(err).WithField("quarantine_id", id).Error("Error deleting quarantined upload")
+		return ErrInternal
+	}
+	_ = service.auditLogService.Record(logger, ctx, &models.AuditLogEntry{
+		Action:      models.AuditActionDeleteQuarantinedUpload,
+		EntityType:  "quarantined_upload",
+		EntityID:    &id,
+		ActorUserID: &actingUserID,
+	})
+	return nil
+}
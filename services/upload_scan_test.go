@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubScanner returns the same verdict for every file.
+type stubScanner struct {
+	signature string
+	err       error
+}
+
+func (stub stubScanner) Scan(ctx context.Context, content []byte) (string, error) {
+	return stub.signature, stub.err
+}
+
+func TestUploadScanService(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	user := &models.User{ID: 3}
+
+	newService := func(scanner services.VirusScanner) (*services.UploadScanServiceImpl, *datamocks.MockQuarantinedUploadStore, *datamocks.MockAuditLogStore) {
+		store := new(datamocks.MockQuarantinedUploadStore)
+		auditLogStore := new(datamocks.MockAuditLogStore)
+		return services.NewUploadScanService(scanner, store, services.NewAuditLogService(auditLogStore), clock.NewFrozen(now)), store, auditLogStore
+	}
+
+	t.Run("clean files pass", func(t *testing.T) {
+		service, store, auditLogStore := newService(stubScanner{})
+		err := service.ScanUpload(logger, ctx, models.UploadKindPhoto, "photo.jpg", []byte("photo"), user)
+		require.NoError(t, err)
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		auditLogStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("infected files are quarantined and audited", func(t *testing.T) {
+		service, store, auditLogStore := newService(stubScanner{signature: "Eicar-Signature"})
+		store.On("Create", mock.Anything, mock.MatchedBy(func(upload *models.QuarantinedUpload) bool {
+			return upload.Kind == models.UploadKindChildDocument && upload.FileName == "scan.pdf" && upload.Signature == "Eicar-Signature" &&
+				string(upload.Content) == "malware" && *upload.UploadedByUserID == user.ID && upload.QuarantinedAt.Equal(now)
+		})).Return(7, nil).Once()
+		auditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionQuarantineUpload && *entry.EntityID == 7 && *entry.ActorUserID == user.ID &&
+				entry.Details == "kind=child_document signature=Eicar-Signature"
+		})).Return(1, nil).Once()
+
+		err := service.ScanUpload(logger, ctx, models.UploadKindChildDocument, "scan.pdf", []byte("malware"), user)
+		assert.ErrorIs(t, err, services.ErrUploadInfected)
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		store.AssertExpectations(t)
+		auditLogStore.AssertExpectations(t)
+	})
+
+	t.Run("files are rejected while the scanner fails", func(t *testing.T) {
+		service, store, _ := newService(stubScanner{err: errors.New("connection refused")})
+		err := service.ScanUpload(logger, ctx, models.UploadKindAudio, "memo.wav", []byte("audio"), user)
+		assert.ErrorIs(t, err, services.ErrScanUnavailable)
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("deleting a quarantined upload", func(t *testing.T) {
+		service, store, auditLogStore := newService(stubScanner{})
+		store.On("Delete", mock.Anything, 7).Return(nil).Once()
+		store.On("Delete", mock.Anything, 8).Return(data.ErrNotFound).Once()
+		auditLogStore.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLogEntry) bool {
+			return entry.Action == models.AuditActionDeleteQuarantinedUpload && *entry.EntityID == 7 && *entry.ActorUserID == 1
+		})).Return(2, nil).Once()
+
+		require.NoError(t, service.DeleteQuarantinedUpload(logger, ctx, 7, 1))
+		assert.ErrorIs(t, service.DeleteQuarantinedUpload(logger, ctx, 8, 1), services.ErrQuarantineNotFound)
+		auditLogStore.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// healthCheckTimeout bounds a single health check, a database that does not answer in time is unhealthy.
+	healthCheckTimeout = 5 * time.Second
+	// uptimePruneInterval is how often health checks older than the retention are deleted.
+	uptimePruneInterval = 24 * time.Hour
+	// MaxUptimeDays is the longest history that can be requested at once.
+	MaxUptimeDays = 366
+)
+
+// UptimeService defines the interface for the uptime history of the server.
+type UptimeService interface {
+	GetUptime(logger *logrus.Entry, ctx context.Context, now time.Time, days int) (*models.UptimeReport, error)
+	CreateIncident(logger *logrus.Entry, ctx context.Context, actingUserID int, incident *models.UptimeIncident) (*models.UptimeIncident, error)
+	DeleteIncident(logger *logrus.Entry, ctx context.Context, id int) error
+}
+
+// UptimeServiceImpl implements UptimeService and records the periodic health checks the history is built from.
+type UptimeServiceImpl struct {
+	uptimeStore data.UptimeStore
+	interval    time.Duration // Between two health checks, 0 if they are disabled
+	retention   time.Duration
+	clock       clock.Clock
+}
+
+// NewUptimeService creates a new UptimeServiceImpl checking the health every interval.
+func NewUptimeService(uptimeStore data.UptimeStore, interval time.Duration, retention time.Duration, clock clock.Clock) *UptimeServiceImpl {
+	return &UptimeServiceImpl{
+		uptimeStore: uptimeStore,
+		interval:    interval,
+		retention:   retention,
+		clock:       clock,
+	}
+}
+
+// Check checks whether the database answers and records the result.
+func (service *UptimeServiceImpl) Check(logger *logrus.Entry, ctx context.Context, now time.Time) *models.HealthCheck {
+	checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	started := time.Now()
+	err := service.uptimeStore.Ping(checkCtx)
+	check := &models.HealthCheck{
+		CheckedAt: now,
+		Healthy:   err == nil,
+		LatencyMS: int(time.Since(started).Milliseconds()),
+	}
+	if err != nil {
+		check.Error = err.Error()
+		logger.WithError(err).Warn("Health check failed")
+	}
+	if err := service.uptimeStore.RecordCheck(ctx, check); err != nil {
+		// A database that cannot be written to leaves a gap, which counts as unavailable.
+		logger.WithError(err).Error("Error recording health check")
+	}
+	return check
+}
+
+// Run checks the health every interval and prunes old checks until the context is cancelled.
+func (service *UptimeServiceImpl) Run(logger *logrus.Entry, ctx context.Context) {
+	ticker := time.NewTicker(service.interval)
+	defer ticker.Stop()
+	var lastPrune time.Time
+	for {
+		now := service.clock.Now()
+		service.Check(logger, ctx, now)
+		if now.Sub(lastPrune) >= uptimePruneInterval {
+			deleted, err := service.uptimeStore.DeleteChecksBefore(ctx, now.Add(-service.retention))
+			if err != nil {
+				logger.WithError(err).Error("Error pruning old health checks")
+			} else if deleted > 0 {
+				logger.WithField("deleted", deleted).Info("Old health checks pruned")
+			}
+			lastPrune = now
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetUptime builds the availability of the given number of days up to today. A day's availability is the share
+// of healthy checks among the checks that should have run since monitoring started, so that times the server
+// was down and could not check itself count as unavailable.
+func (service *UptimeServiceImpl) GetUptime(logger *logrus.Entry, ctx context.Context, now time.Time, days int) (*models.UptimeReport, error) {
+	if days < 1 || days > MaxUptimeDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidInput, MaxUptimeDays)
+	}
+	to := models.Today(now)
+	report := &models.UptimeReport{From: to.AddDays(1 - days), To: to, Days: []models.UptimeDay{}}
+	from := dayStart(report.From)
+	until := dayStart(to.AddDays(1))
+
+	first, err := service.uptimeStore.GetFirstCheck(ctx)
+	if errors.Is(err, data.ErrNotFound) {
+		first = nil
+	} else if err != nil {
+		logger.WithError(err).Error("Error fetching first health check")
+		return nil, ErrInternal
+	}
+	last, err := service.uptimeStore.GetLastCheck(ctx)
+	if err != nil && !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).Error("Error fetching last health check")
+		return nil, ErrInternal
+	}
+	report.LastCheck = last
+	checks, err := service.uptimeStore.GetChecks(ctx, from, until)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching health checks")
+		return nil, ErrInternal
+	}
+	incidents, err := service.uptimeStore.GetIncidents(ctx, from, until)
+	if err != nil {
+		logger.WithError(err).Error("Error fetching uptime incidents")
+		return nil, ErrInternal
+	}
+	report.Incidents = incidents
+	if report.Incidents == nil {
+		report.Incidents = []models.UptimeIncident{}
+	}
+
+	checksByDay := make(map[models.Date][]models.HealthCheck)
+	for _, check := range checks {
+		day := models.DateOf(check.CheckedAt.In(models.FacilityLocation()))
+		checksByDay[day] = append(checksByDay[day], check)
+	}
+	var healthyTotal, expectedTotal int
+	for day := report.From; !day.After(to.Time); day = day.AddDays(1) {
+		uptimeDay := models.UptimeDay{Date: day}
+		for _, check := range checksByDay[day] {
+			uptimeDay.Checks++
+			if check.Healthy {
+				uptimeDay.HealthyChecks++
+			}
+		}
+		uptimeDay.ExpectedChecks = max(service.expectedChecks(first, dayStart(day), dayStart(day.AddDays(1)), now), uptimeDay.Checks)
+		if uptimeDay.ExpectedChecks > 0 {
+			uptimeDay.AvailabilityPercent = availabilityPercent(uptimeDay.HealthyChecks, uptimeDay.ExpectedChecks)
+		}
+		healthyTotal += uptimeDay.HealthyChecks
+		expectedTotal += uptimeDay.ExpectedChecks
+		report.Days = append(report.Days, uptimeDay)
+	}
+	if expectedTotal > 0 {
+		report.AvailabilityPercent = availabilityPercent(healthyTotal, expectedTotal)
+	}
+	return report, nil
+}
+
+// expectedChecks returns how many checks should have run between start and end, counting from the first
+// check ever recorded up to now.
+func (service *UptimeServiceImpl) expectedChecks(first *models.HealthCheck, start time.Time, end time.Time, now time.Time) int {
+	if first == nil || service.interval <= 0 {
+		return 0
+	}
+	if first.CheckedAt.After(start) {
+		start = first.CheckedAt
+	}
+	if now.Before(end) {
+		end = now
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return int(end.Sub(start) / service.interval)
+}
+
+// dayStart returns the start of a day in the time zone of the facility.
+func dayStart(day models.Date) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, models.FacilityLocation())
+}
+
+// availabilityPercent returns the share of healthy checks in percent, rounded to two decimals.
+func availabilityPercent(healthy int, expected int) *float64 {
+	percent := math.Round(float64(healthy)/float64(expected)*10000) / 100
+	return &percent
+}
+
+// CreateIncident annotates the uptime history with an incident.
+func (service *UptimeServiceImpl) CreateIncident(logger *logrus.Entry, ctx context.Context, actingUserID int, incident *models.UptimeIncident) (*models.UptimeIncident, error) {
+	if err := models.ValidateUptimeIncident(*incident); err != nil {
+		logger.WithError(err).Warn("Invalid input for CreateIncident")
+		return nil, invalidInput(err)
+	}
+	if incident.EndedAt != nil && incident.EndedAt.Before(incident.StartedAt) {
+		logger.Warn("Uptime incident ends before it starts")
+		return nil, fmt.Errorf("%w: ended_at must not be before started_at", ErrInvalidInput)
+	}
+	incident.CreatedByUserID = &actingUserID
+
+	id, err := service.uptimeStore.CreateIncident(ctx, incident)
+	if err != nil {
+		logger.WithError(err).Error("Error creating uptime incident")
+		return nil, ErrInternal
+	}
+	created, err := service.uptimeStore.GetIncidentByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("incident_id", id).Error("Error fetching created uptime incident")
+		return nil, ErrInternal
+	}
+	logger.WithField("incident_id", id).Info("Uptime incident created")
+	return created, nil
+}
+
+// DeleteIncident removes an incident annotation.
+func (service *UptimeServiceImpl) DeleteIncident(logger *logrus.Entry, ctx context.Context, id int) error {
+	if err := service.uptimeStore.DeleteIncident(ctx, id); err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrNotFound
+		}
+		logger.WithError(err).WithField("incident_id", id).Error("Error deleting uptime incident")
+		return ErrInternal
+	}
+	logger.WithField("incident_id", id).Info("Uptime incident deleted")
+	return nil
+}
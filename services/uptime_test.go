@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUptimeCheck(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	now := time.Date(2025, time.May, 2, 8, 0, 0, 0, time.UTC)
+
+	t.Run("healthy", func(t *testing.T) {
+		store := new(datamocks.MockUptimeStore)
+		service := services.NewUptimeService(store, time.Minute, time.Hour, clock.System{})
+		store.On("Ping", mock.Anything).Return(nil).Once()
+		store.On("RecordCheck", mock.Anything, mock.MatchedBy(func(check *models.HealthCheck) bool {
+			return check.Healthy && check.CheckedAt.Equal(now)
+		})).Return(nil).Once()
+
+		check := service.Check(logger, context.Background(), now)
+
+		assert.True(t, check.Healthy)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("database not answering", func(t *testing.T) {
+		store := new(datamocks.MockUptimeStore)
+		service := services.NewUptimeService(store, time.Minute, time.Hour, clock.System{})
+		store.On("Ping", mock.Anything).Return(errors.New("database is locked")).Once()
+		store.On("RecordCheck", mock.Anything, mock.Anything).Return(nil).Once()
+
+		check := service.Check(logger, context.Background(), now)
+
+		assert.False(t, check.Healthy)
+		assert.Equal(t, "database is locked", check.Error)
+	})
+}
+
+func TestGetUptime(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	// Checks are assigned to the days of the facility, which is UTC unless configured otherwise.
+	day := func(d int, hour int, minute int) time.Time {
+		return time.Date(2025, time.May, d, hour, minute, 0, 0, models.FacilityLocation())
+	}
+	now := day(3, 12, 0)
+
+	t.Run("downtime without checks counts as unavailable", func(t *testing.T) {
+		store := new(datamocks.MockUptimeStore)
+		service := services.NewUptimeService(store, time.Hour, 24*time.Hour, clock.System{})
+		// Monitoring started on May 2nd at noon, the server was down from 18:00 on.
+		var checks []models.HealthCheck
+		for hour := 12; hour < 18; hour++ {
+			checks = append(checks, models.HealthCheck{CheckedAt: day(2, hour, 0), Healthy: hour != 13})
+		}
+		for hour := 0; hour < 12; hour++ {
+			checks = append(checks, models.HealthCheck{CheckedAt: day(3, hour, 0), Healthy: true})
+		}
+		store.On("GetFirstCheck", mock.Anything).Return(&checks[0], nil).Once()
+		store.On("GetLastCheck", mock.Anything).Return(&checks[len(checks)-1], nil).Once()
+		store.On("GetChecks", mock.Anything, day(1, 0, 0), day(4, 0, 0)).Return(checks, nil).Once()
+		store.On("GetIncidents", mock.Anything, day(1, 0, 0), day(4, 0, 0)).Return([]models.UptimeIncident{{ID: 1, StartedAt: day(2, 18, 0), Note: "Update"}}, nil).Once()
+
+		report, err := service.GetUptime(logger, ctx, now, 3)
+
+		require.NoError(t, err)
+		require.Len(t, report.Days, 3)
+		assert.Equal(t, models.NewDate(2025, time.May, 1), report.From)
+		assert.Nil(t, report.Days[0].AvailabilityPercent) // Before monitoring started
+		assert.Equal(t, 12, report.Days[1].ExpectedChecks)
+		assert.Equal(t, 5, report.Days[1].HealthyChecks)
+		assert.InDelta(t, 41.67, *report.Days[1].AvailabilityPercent, 0.001)
+		assert.InDelta(t, 100, *report.Days[2].AvailabilityPercent, 0.001)
+		assert.InDelta(t, 70.83, *report.AvailabilityPercent, 0.001)
+		assert.Len(t, report.Incidents, 1)
+	})
+
+	t.Run("nothing recorded", func(t *testing.T) {
+		store := new(datamocks.MockUptimeStore)
+		service := services.NewUptimeService(store, time.Minute, 24*time.Hour, clock.System{})
+		store.On("GetFirstCheck", mock.Anything).Return(nil, data.ErrNotFound).Once()
+		store.On("GetLastCheck", mock.Anything).Return(nil, data.ErrNotFound).Once()
+		store.On("GetChecks", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		store.On("GetIncidents", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+		report, err := service.GetUptime(logger, ctx, now, 30)
+
+		require.NoError(t, err)
+		assert.Len(t, report.Days, 30)
+		assert.Nil(t, report.AvailabilityPercent)
+		assert.NotNil(t, report.Incidents)
+	})
+
+	t.Run("invalid number of days", func(t *testing.T) {
+		service := services.NewUptimeService(new(datamocks.MockUptimeStore), time.Minute, 24*time.Hour, clock.System{})
+
+		_, err := service.GetUptime(logger, ctx, now, 0)
+
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+}
+
+func TestCreateUptimeIncident(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	start := time.Date(2025, time.May, 2, 8, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		store := new(datamocks.MockUptimeStore)
+		service := services.NewUptimeService(store, time.Minute, time.Hour, clock.System{})
+		incident := &models.UptimeIncident{StartedAt: start, Note: "Wartung"}
+		store.On("CreateIncident", mock.Anything, incident).Return(4, nil).Once()
+		store.On("GetIncidentByID", mock.Anything, 4).Return(&models.UptimeIncident{ID: 4, StartedAt: start, Note: "Wartung"}, nil).Once()
+
+		created, err := service.CreateIncident(logger, ctx, 1, incident)
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, created.ID)
+		assert.Equal(t, 1, *incident.CreatedByUserID)
+	})
+
+	t.Run("ends before it starts", func(t *testing.T) {
+		store := new(datamocks.MockUptimeStore)
+		service := services.NewUptimeService(store, time.Minute, time.Hour, clock.System{})
+		end := start.Add(-time.Hour)
+
+		_, err := service.CreateIncident(logger, ctx, 1, &models.UptimeIncident{StartedAt: start, EndedAt: &end, Note: "Wartung"})
+
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+		store.AssertNotCalled(t, "CreateIncident", mock.Anything, mock.Anything)
+	})
+}
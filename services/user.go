@@ -9,6 +9,8 @@ import (
 
 	"kitadoc-backend/config"
 	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
 
 	"github.com/go-playground/validator/v10"
@@ -18,36 +20,64 @@ import (
 
 // UserService defines the interface for user-related business logic operations.
 type UserService interface {
-	RegisterUser(logger *logrus.Entry, username, password, role string) (*models.User, error)
-	LoginUser(logger *logrus.Entry, username, password string) (string, error) // Returns JWT token
+	// RegisterUser creates an account through open registration, it returns ErrRegistrationClosed if that is disabled.
+	RegisterUser(logger *logrus.Entry, ctx context.Context, username, password, role string) (*models.User, error)
+	// CreateUser creates an account regardless of the registration setting, e.g. from an invitation.
+	CreateUser(logger *logrus.Entry, ctx context.Context, username, password, role string) (*models.User, error)
+	// LoginUser checks the password and starts a session, it returns a short-lived access token and the refresh
+	// token of the session.
+	LoginUser(logger *logrus.Entry, ctx context.Context, username, password string) (*models.AuthTokens, error)
+	// RefreshSession exchanges the refresh token of an active session for new tokens. The refresh token can only
+	// be used once, it returns ErrAuthenticationFailed for unknown, used, expired and revoked tokens.
+	RefreshSession(logger *logrus.Entry, ctx context.Context, refreshToken string) (*models.AuthTokens, error)
+	// EndSession revokes a session on logout, its access tokens are rejected from then on.
+	EndSession(logger *logrus.Entry, ctx context.Context, sessionID int) error
+	// ValidateSession returns ErrAuthenticationFailed if the session of an access token has been revoked.
+	ValidateSession(logger *logrus.Entry, ctx context.Context, userID, sessionID int) error
 	GetCurrentUser(logger *logrus.Entry, tokenString string) (*models.User, error)
 	GetUserByID(logger *logrus.Entry, ctx context.Context, id int) (*models.User, error)
-	UpdateUser(logger *logrus.Entry, user *models.User) error
-	DeleteUser(logger *logrus.Entry, id int) error
-	GetAllUsers(logger *logrus.Entry) ([]*models.User, error)
-	ChangePassword(logger *logrus.Entry, actor *models.User, userID int, oldPassword, newPassword string) error
+	UpdateUser(logger *logrus.Entry, ctx context.Context, user *models.User) error
+	DeleteUser(logger *logrus.Entry, ctx context.Context, id int) error
+	GetAllUsers(logger *logrus.Entry, ctx context.Context) ([]*models.User, error)
+	// ChangePassword sets a new password and revokes the sessions of the user, the current one included.
+	ChangePassword(logger *logrus.Entry, ctx context.Context, actor *models.User, userID int, oldPassword, newPassword string) error
+	// Reauthenticate confirms the password of a logged in user and returns a short-lived token for bulk exports.
+	Reauthenticate(logger *logrus.Entry, ctx context.Context, userID int, password string) (string, time.Time, error)
 }
 
 // UserServiceImpl implements UserService.
 type UserServiceImpl struct {
-	userStore data.UserStore
-	validate  *validator.Validate
-	config    *config.Config // Add config to service
+	userStore        data.UserStore
+	authSessionStore data.AuthSessionStore
+	validate         *validator.Validate
+	config           *config.Config // Add config to service
+	clock            clock.Clock
 }
 
 // NewUserService creates a new UserServiceImpl.
-func NewUserService(userStore data.UserStore, cfg *config.Config) *UserServiceImpl {
+func NewUserService(userStore data.UserStore, authSessionStore data.AuthSessionStore, cfg *config.Config, clock clock.Clock) *UserServiceImpl {
 	return &UserServiceImpl{
-		userStore: userStore,
-		validate:  validator.New(),
-		config:    cfg,
+		userStore:        userStore,
+		authSessionStore: authSessionStore,
+		validate:         models.NewValidator(),
+		config:           cfg,
+		clock:            clock,
 	}
 }
 
-// RegisterUser registers a new user after hashing the password.
-func (s *UserServiceImpl) RegisterUser(logger *logrus.Entry, username, password, role string) (*models.User, error) {
+// RegisterUser registers a new user if open registration is enabled.
+func (s *UserServiceImpl) RegisterUser(logger *logrus.Entry, ctx context.Context, username, password, role string) (*models.User, error) {
+	if !s.config.Registration.Open {
+		logger.WithField("username", username).Warn("Registration attempt while open registration is disabled")
+		return nil, ErrRegistrationClosed
+	}
+	return s.CreateUser(logger, ctx, username, password, role)
+}
+
+// CreateUser creates a new user after hashing the password.
+func (s *UserServiceImpl) CreateUser(logger *logrus.Entry, ctx context.Context, username, password, role string) (*models.User, error) {
 	// Check if user already exists
-	_, err := s.userStore.GetUserByUsername(username)
+	_, err := s.userStore.GetUserByUsername(ctx, username)
 	if err == nil {
 		logger.WithField("username", username).Warn("User already exists during registration attempt")
 		return nil, ErrAlreadyExists
@@ -73,58 +103,220 @@ func (s *UserServiceImpl) RegisterUser(logger *logrus.Entry, username, password,
 		Username:     username,
 		PasswordHash: string(hashedPassword),
 		Role:         role,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		CreatedAt:    s.clock.Now(),
+		UpdatedAt:    s.clock.Now(),
 	}
 
 	if err := models.ValidateUser(*user); err != nil {
 		logger.WithError(err).Warn("Invalid user data provided during registration")
-		return nil, ErrInvalidInput
+		return nil, invalidInput(err)
 	}
 
-	id, err := s.userStore.Create(user)
+	id, err := s.userStore.Create(ctx, user)
 	if err != nil {
 		logger.WithError(err).Error("Error creating user in store")
 		return nil, ErrInternal
 	}
-	user.ID = id
-	logger.WithField("user_id", user.ID).Info("User registered successfully")
-	return user, nil
+	created, err := s.userStore.GetByID(ctx, id)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", id).Error("Error fetching created user")
+		return nil, ErrInternal
+	}
+	logger.WithField("user_id", id).Info("User registered successfully")
+	return created, nil
 }
 
-// LoginUser authenticates a user and generates a JWT token.
-func (s *UserServiceImpl) LoginUser(logger *logrus.Entry, username, password string) (string, error) {
-	user, err := s.userStore.GetUserByUsername(username)
+// LoginUser authenticates a user and starts a session.
+func (s *UserServiceImpl) LoginUser(logger *logrus.Entry, ctx context.Context, username, password string) (*models.AuthTokens, error) {
+	if username == models.BreakGlassUsername {
+		logger.WithField("username", username).Warn("Login attempt with the break-glass account, it is only accessible by activation")
+		return nil, ErrInvalidCredentials
+	}
+	user, err := s.userStore.GetUserByUsername(ctx, username)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("username", username).Warn("Login attempt with invalid credentials: user not found")
-			return "", ErrInvalidCredentials
+			return nil, ErrInvalidCredentials
 		}
 		logger.WithError(err).WithField("username", username).Error("Error fetching user by username during login")
-		return "", ErrInternal
+		return nil, ErrInternal
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if err != nil {
 		logger.WithField("username", username).Warn("Login attempt with invalid credentials: password mismatch")
-		return "", ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
+	}
+
+	now := s.clock.Now().UTC()
+	if deleted, err := s.authSessionStore.DeleteExpired(ctx, now); err != nil {
+		logger.WithError(err).Error("Error deleting expired sessions")
+	} else if deleted > 0 {
+		logger.WithField("count", deleted).Info("Deleted expired sessions")
+	}
+
+	refreshToken, err := newLinkToken()
+	if err != nil {
+		logger.WithError(err).Error("Error generating refresh token")
+		return nil, ErrInternal
+	}
+	session := &models.AuthSession{
+		UserID:           user.ID,
+		RefreshTokenHash: hashLinkToken(refreshToken),
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(s.config.Session.RefreshTokenValidity),
+	}
+	session.ID, err = s.authSessionStore.Create(ctx, session)
+	if err != nil {
+		logger.WithError(err).WithField("user_id", user.ID).Error("Error creating session")
+		return nil, ErrInternal
+	}
+
+	tokens, err := s.issueTokens(user, session, refreshToken)
+	if err != nil {
+		logger.WithError(err).Error("Error signing JWT token")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"user_id": user.ID, "session_id": session.ID}).Info("User logged in successfully, session started")
+	return tokens, nil
+}
+
+// RefreshSession rotates the refresh token of a session and issues a new access token.
+func (s *UserServiceImpl) RefreshSession(logger *logrus.Entry, ctx context.Context, refreshToken string) (*models.AuthTokens, error) {
+	refreshTokenHash := hashLinkToken(refreshToken)
+	session, err := s.authSessionStore.GetByRefreshTokenHash(ctx, refreshTokenHash)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			logger.Warn("Refresh attempt with an unknown or already used refresh token")
+			return nil, ErrAuthenticationFailed
+		}
+		logger.WithError(err).Error("Error fetching session for refresh")
+		return nil, ErrInternal
+	}
+	now := s.clock.Now().UTC()
+	if !session.IsActive(now) {
+		logger.WithField("session_id", session.ID).Warn("Refresh attempt for an expired or revoked session")
+		return nil, ErrAuthenticationFailed
+	}
+	user, err := s.userStore.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return nil, ErrAuthenticationFailed
+		}
+		logger.WithError(err).WithField("user_id", session.UserID).Error("Error fetching user for refresh")
+		return nil, ErrInternal
 	}
 
-	// Generate JWT token
+	newRefreshToken, err := newLinkToken()
+	if err != nil {
+		logger.WithError(err).Error("Error generating refresh token")
+		return nil, ErrInternal
+	}
+	newRefreshTokenHash := hashLinkToken(newRefreshToken)
+	if err := s.authSessionStore.Rotate(ctx, session.ID, refreshTokenHash, newRefreshTokenHash, now); err != nil {
+		if errors.Is(err, data.ErrConflict) {
+			logger.WithField("session_id", session.ID).Warn("Refresh token was used concurrently")
+			return nil, ErrAuthenticationFailed
+		}
+		logger.WithError(err).WithField("session_id", session.ID).Error("Error rotating refresh token")
+		return nil, ErrInternal
+	}
+	session.RefreshTokenHash = newRefreshTokenHash
+
+	tokens, err := s.issueTokens(user, session, newRefreshToken)
+	if err != nil {
+		logger.WithError(err).Error("Error signing JWT token")
+		return nil, ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"user_id": user.ID, "session_id": session.ID}).Info("Session refreshed")
+	return tokens, nil
+}
+
+// EndSession revokes a session. Sessions that are revoked already are left alone.
+func (s *UserServiceImpl) EndSession(logger *logrus.Entry, ctx context.Context, sessionID int) error {
+	if err := s.authSessionStore.Revoke(ctx, sessionID, s.clock.Now().UTC()); err != nil && !errors.Is(err, data.ErrNotFound) {
+		logger.WithError(err).WithField("session_id", sessionID).Error("Error revoking session")
+		return ErrInternal
+	}
+	logger.WithField("session_id", sessionID).Info("Session ended")
+	return nil
+}
+
+// ValidateSession checks that the session of an access token belongs to the user and has not been revoked. The
+// expiry of the session is not checked, it only limits the refresh token.
+func (s *UserServiceImpl) ValidateSession(logger *logrus.Entry, ctx context.Context, userID, sessionID int) error {
+	session, err := s.authSessionStore.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return ErrAuthenticationFailed
+		}
+		logger.WithError(err).WithField("session_id", sessionID).Error("Error fetching session")
+		return ErrInternal
+	}
+	if session.UserID != userID || session.RevokedAt != nil {
+		return ErrAuthenticationFailed
+	}
+	return nil
+}
+
+// issueTokens signs an access token of the session. Its expiry is checked against the real time when the token is
+// parsed, so it is not taken from the clock of the service.
+func (s *UserServiceImpl) issueTokens(user *models.User, session *models.AuthSession, refreshToken string) (*models.AuthTokens, error) {
+	expiresAt := time.Now().Add(s.config.Session.AccessTokenValidity)
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id":  user.ID,
 		"username": user.Username,
 		"role":     user.Role,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(),
+		"sid":      session.ID,
+		"exp":      expiresAt.Unix(),
 	})
 
 	tokenString, err := token.SignedString([]byte(s.config.Server.JWTSecret)) // Use JWTSecret from config
 	if err != nil {
-		logger.WithError(err).Error("Error signing JWT token")
-		return "", ErrInternal
+		return nil, err
 	}
-	logger.WithField("user_id", user.ID).Info("User logged in successfully, JWT generated")
-	return tokenString, nil
+	return &models.AuthTokens{
+		AccessToken:           tokenString,
+		ExpiresAt:             time.Unix(expiresAt.Unix(), 0).UTC(),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// revokeSessions ends all sessions of a user after the password was changed.
+func (s *UserServiceImpl) revokeSessions(logger *logrus.Entry, ctx context.Context, userID int) error {
+	revoked, err := s.authSessionStore.RevokeAllForUser(ctx, userID, s.clock.Now().UTC())
+	if err != nil {
+		logger.WithError(err).WithField("user_id", userID).Error("Error revoking sessions")
+		return ErrInternal
+	}
+	logger.WithFields(logrus.Fields{"user_id": userID, "count": revoked}).Info("Sessions revoked")
+	return nil
+}
+
+// Reauthenticate checks the password of the user again and issues a re-authentication token.
+func (s *UserServiceImpl) Reauthenticate(logger *logrus.Entry, ctx context.Context, userID int, password string) (string, time.Time, error) {
+	user, err := s.userStore.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return "", time.Time{}, ErrNotFound
+		}
+		logger.WithError(err).WithField("user_id", userID).Error("Error fetching user for re-authentication")
+		return "", time.Time{}, ErrInternal
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		logger.WithField("user_id", userID).Warn("Re-authentication failed: password mismatch")
+		return "", time.Time{}, ErrInvalidCredentials
+	}
+
+	token, expiresAt, err := middleware.IssueReauthenticationToken(user.ID, s.config.Server.JWTSecret, s.config.Exports.ReauthenticationValidity)
+	if err != nil {
+		logger.WithError(err).Error("Error signing re-authentication token")
+		return "", time.Time{}, ErrInternal
+	}
+	logger.WithField("user_id", userID).Info("User re-authenticated")
+	return token, expiresAt, nil
 }
 
 // GetCurrentUser parses a JWT token and returns the corresponding user.
@@ -162,14 +354,14 @@ func (s *UserServiceImpl) GetCurrentUser(logger *logrus.Entry, tokenString strin
 }
 
 // UpdateUser updates an existing user.
-func (s *UserServiceImpl) UpdateUser(logger *logrus.Entry, user *models.User) error {
+func (s *UserServiceImpl) UpdateUser(logger *logrus.Entry, ctx context.Context, user *models.User) error {
 	if err := models.ValidateUser(*user); err != nil {
 		logger.WithError(err).Warn("Invalid input for UpdateUser")
-		return ErrInvalidInput
+		return invalidInput(err)
 	}
 
 	// Fetch existing user to preserve password hash if not updated
-	existingUser, err := s.userStore.GetByID(user.ID)
+	existingUser, err := s.userStore.GetByID(ctx, user.ID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("user_id", user.ID).Warn("User not found for update")
@@ -191,8 +383,8 @@ func (s *UserServiceImpl) UpdateUser(logger *logrus.Entry, user *models.User) er
 		user.PasswordHash = existingUser.PasswordHash
 	}
 
-	user.UpdatedAt = time.Now()
-	err = s.userStore.Update(user)
+	user.UpdatedAt = s.clock.Now()
+	err = s.userStore.Update(ctx, user)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("user_id", user.ID).Warn("User not found during update in store")
@@ -201,13 +393,18 @@ func (s *UserServiceImpl) UpdateUser(logger *logrus.Entry, user *models.User) er
 		logger.WithError(err).WithField("user_id", user.ID).Error("Error updating user in store")
 		return ErrInternal
 	}
+	if user.PasswordHash != existingUser.PasswordHash {
+		if err := s.revokeSessions(logger, ctx, user.ID); err != nil {
+			return err
+		}
+	}
 	logger.WithField("user_id", user.ID).Info("User updated successfully")
 	return nil
 }
 
 // DeleteUser deletes a user by ID.
-func (s *UserServiceImpl) DeleteUser(logger *logrus.Entry, id int) error {
-	err := s.userStore.Delete(id)
+func (s *UserServiceImpl) DeleteUser(logger *logrus.Entry, ctx context.Context, id int) error {
+	err := s.userStore.Delete(ctx, id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("user_id", id).Warn("User not found for deletion")
@@ -222,7 +419,7 @@ func (s *UserServiceImpl) DeleteUser(logger *logrus.Entry, id int) error {
 
 // GetUserByID fetches a user by ID.
 func (s *UserServiceImpl) GetUserByID(logger *logrus.Entry, ctx context.Context, id int) (*models.User, error) {
-	user, err := s.userStore.GetByID(id)
+	user, err := s.userStore.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("user_id", id).Warn("User not found by ID")
@@ -236,8 +433,8 @@ func (s *UserServiceImpl) GetUserByID(logger *logrus.Entry, ctx context.Context,
 }
 
 // GetAllUsers fetches all users.
-func (s *UserServiceImpl) GetAllUsers(logger *logrus.Entry) ([]*models.User, error) {
-	users, err := s.userStore.GetAll()
+func (s *UserServiceImpl) GetAllUsers(logger *logrus.Entry, ctx context.Context) ([]*models.User, error) {
+	users, err := s.userStore.GetAll(ctx)
 	if err != nil {
 		logger.WithError(err).Error("Error fetching all users from store")
 		return nil, ErrInternal
@@ -247,8 +444,8 @@ func (s *UserServiceImpl) GetAllUsers(logger *logrus.Entry) ([]*models.User, err
 }
 
 // ChangePassword changes a user's password.
-func (s *UserServiceImpl) ChangePassword(logger *logrus.Entry, actor *models.User, userID int, oldPassword, newPassword string) error {
-	user, err := s.userStore.GetByID(userID)
+func (s *UserServiceImpl) ChangePassword(logger *logrus.Entry, ctx context.Context, actor *models.User, userID int, oldPassword, newPassword string) error {
+	user, err := s.userStore.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, data.ErrNotFound) {
 			logger.WithField("user_id", userID).Warn("User not found for password change")
@@ -267,13 +464,13 @@ func (s *UserServiceImpl) ChangePassword(logger *logrus.Entry, actor *models.Use
 			return ErrInternal
 		}
 
-		err = s.userStore.UpdatePassword(userID, string(hashedPassword))
+		err = s.userStore.UpdatePassword(ctx, userID, string(hashedPassword))
 		if err != nil {
 			logger.WithError(err).WithField("user_id", userID).Error("Error updating password in store")
 			return ErrInternal
 		}
 		logger.WithField("user_id", userID).Info("Password changed successfully by admin")
-		return nil
+		return s.revokeSessions(logger, ctx, userID)
 	}
 
 	// Regular user can only change their own password
@@ -299,12 +496,12 @@ func (s *UserServiceImpl) ChangePassword(logger *logrus.Entry, actor *models.Use
 		return ErrInternal
 	}
 
-	err = s.userStore.UpdatePassword(userID, string(hashedPassword))
+	err = s.userStore.UpdatePassword(ctx, userID, string(hashedPassword))
 	if err != nil {
 		logger.WithError(err).WithField("user_id", userID).Error("Error updating password in store")
 		return ErrInternal
 	}
 
 	logger.WithField("user_id", userID).Info("Password changed successfully")
-	return nil
+	return s.revokeSessions(logger, ctx, userID)
 }
@@ -14,6 +14,8 @@ import (
 	"kitadoc-backend/config"
 	"kitadoc-backend/data"
 	"kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/middleware"
 	"kitadoc-backend/models"
 	"kitadoc-backend/services"
 )
@@ -28,19 +30,25 @@ func TestUserService_RegisterUser(t *testing.T) {
 			WriteTimeout time.Duration "mapstructure:\"write_timeout\""
 			IdleTimeout  time.Duration "mapstructure:\"idle_timeout\""
 			JWTSecret    string        "mapstructure:\"jwt_secret\""
+			// RequestTimeout bounds the handling of a request, answered with 504 when exceeded. 0 disables it.
+			RequestTimeout time.Duration "mapstructure:\"request_timeout\""
+			// LongRequestTimeout applies instead to report generation, imports and uploads.
+			LongRequestTimeout time.Duration "mapstructure:\"long_request_timeout\""
 		}{
 			JWTSecret: "test_secret",
 		},
 	}
-	userService := services.NewUserService(mockStore, testConfig)
+	testConfig.Registration.Open = true
+	userService := services.NewUserService(mockStore, new(mocks.MockAuthSessionStore), testConfig, clock.System{})
 	logger := logrus.NewEntry(logrus.New()) // Create a new logger entry for testing
 
 	// Test case 1: Successful registration
 	t.Run("Successful Registration", func(t *testing.T) {
-		mockStore.On("GetUserByUsername", "newuser").Return(&models.User{}, data.ErrNotFound).Once()
-		mockStore.On("Create", mock.AnythingOfType("*models.User")).Return(1, nil).Once()
+		mockStore.On("GetUserByUsername", mock.Anything, "newuser").Return(&models.User{}, data.ErrNotFound).Once()
+		mockStore.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(1, nil).Once()
+		mockStore.On("GetByID", mock.Anything, 1).Return(&models.User{ID: 1, Username: "newuser", Role: "teacher"}, nil).Once()
 
-		user, err := userService.RegisterUser(logger, "newuser", "password123", "teacher")
+		user, err := userService.RegisterUser(logger, t.Context(), "newuser", "password123", "teacher")
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
 		assert.Equal(t, 1, user.ID)
@@ -50,9 +58,9 @@ func TestUserService_RegisterUser(t *testing.T) {
 
 	// Test case 2: User already exists
 	t.Run("User Already Exists", func(t *testing.T) {
-		mockStore.On("GetUserByUsername", "existinguser").Return(&models.User{ID: 1, Username: "existinguser"}, nil).Once()
+		mockStore.On("GetUserByUsername", mock.Anything, "existinguser").Return(&models.User{ID: 1, Username: "existinguser"}, nil).Once()
 
-		user, err := userService.RegisterUser(logger, "existinguser", "password123", "teacher")
+		user, err := userService.RegisterUser(logger, t.Context(), "existinguser", "password123", "teacher")
 		assert.Error(t, err)
 		assert.Nil(t, user)
 		assert.Equal(t, services.ErrAlreadyExists, err)
@@ -61,14 +69,27 @@ func TestUserService_RegisterUser(t *testing.T) {
 
 	// Test case 3: Invalid input (e.g., short password)
 	t.Run("Invalid Input", func(t *testing.T) {
-		mockStore.On("GetUserByUsername", "invaliduser").Return(&models.User{}, data.ErrNotFound).Once()
+		mockStore.On("GetUserByUsername", mock.Anything, "invaliduser").Return(&models.User{}, data.ErrNotFound).Once()
 
-		user, err := userService.RegisterUser(logger, "invaliduser", "short", "teacher") // Password too short
+		user, err := userService.RegisterUser(logger, t.Context(), "invaliduser", "short", "teacher") // Password too short
 		assert.Error(t, err)
 		assert.Nil(t, user)
 		assert.Equal(t, services.ErrInvalidInput, err)
 		mockStore.AssertExpectations(t)
 	})
+
+	// Test case 4: Open registration disabled
+	t.Run("Registration Closed", func(t *testing.T) {
+		closedConfig := *testConfig
+		closedConfig.Registration.Open = false
+		closedStore := new(mocks.MockUserStore)
+		closedService := services.NewUserService(closedStore, new(mocks.MockAuthSessionStore), &closedConfig, clock.System{})
+
+		user, err := closedService.RegisterUser(logger, t.Context(), "newuser", "password123", "admin")
+		assert.Nil(t, user)
+		assert.Equal(t, services.ErrRegistrationClosed, err)
+		closedStore.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
 }
 
 // TestUserService_LoginUser tests the LoginUser method of UserService.
@@ -81,11 +102,18 @@ func TestUserService_LoginUser(t *testing.T) {
 			WriteTimeout time.Duration "mapstructure:\"write_timeout\""
 			IdleTimeout  time.Duration "mapstructure:\"idle_timeout\""
 			JWTSecret    string        "mapstructure:\"jwt_secret\""
+			// RequestTimeout bounds the handling of a request, answered with 504 when exceeded. 0 disables it.
+			RequestTimeout time.Duration "mapstructure:\"request_timeout\""
+			// LongRequestTimeout applies instead to report generation, imports and uploads.
+			LongRequestTimeout time.Duration "mapstructure:\"long_request_timeout\""
 		}{
 			JWTSecret: "test_secret",
 		},
 	}
-	userService := services.NewUserService(mockStore, testConfig)
+	testConfig.Session.AccessTokenValidity = 15 * time.Minute
+	testConfig.Session.RefreshTokenValidity = 7 * 24 * time.Hour
+	mockSessionStore := new(mocks.MockAuthSessionStore)
+	userService := services.NewUserService(mockStore, mockSessionStore, testConfig, clock.System{})
 	logger := logrus.NewEntry(logrus.New())
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.DefaultCost)
@@ -98,35 +126,158 @@ func TestUserService_LoginUser(t *testing.T) {
 
 	// Test case 1: Successful login
 	t.Run("Successful Login", func(t *testing.T) {
-		mockStore.On("GetUserByUsername", "testuser").Return(testUser, nil).Once()
+		mockStore.On("GetUserByUsername", mock.Anything, "testuser").Return(testUser, nil).Once()
+		mockSessionStore.On("DeleteExpired", mock.Anything, mock.AnythingOfType("time.Time")).Return(0, nil).Once()
+		mockSessionStore.On("Create", mock.Anything, mock.MatchedBy(func(session *models.AuthSession) bool {
+			return session.UserID == 1 && len(session.RefreshTokenHash) == 64 && session.ExpiresAt.Sub(session.CreatedAt) == 7*24*time.Hour
+		})).Return(5, nil).Once()
 
-		token, err := userService.LoginUser(logger, "testuser", "correctpassword")
+		tokens, err := userService.LoginUser(logger, t.Context(), "testuser", "correctpassword")
 		assert.NoError(t, err)
-		assert.NotEmpty(t, token)
+		assert.NotEmpty(t, tokens.RefreshToken)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), tokens.ExpiresAt, time.Second)
+		claims, err := middleware.ParseToken(tokens.AccessToken, "test_secret")
+		assert.NoError(t, err)
+		assert.Equal(t, 5, claims.SessionID)
 		mockStore.AssertExpectations(t)
+		mockSessionStore.AssertExpectations(t)
 	})
 
 	// Test case 2: Invalid password
 	t.Run("Invalid Password", func(t *testing.T) {
-		mockStore.On("GetUserByUsername", "testuser").Return(testUser, nil).Once()
+		mockStore.On("GetUserByUsername", mock.Anything, "testuser").Return(testUser, nil).Once()
 
-		token, err := userService.LoginUser(logger, "testuser", "wrongpassword")
+		tokens, err := userService.LoginUser(logger, t.Context(), "testuser", "wrongpassword")
 		assert.Error(t, err)
-		assert.Empty(t, token)
+		assert.Nil(t, tokens)
 		assert.Equal(t, services.ErrInvalidCredentials, err)
 		mockStore.AssertExpectations(t)
 	})
 
 	// Test case 3: User not found
 	t.Run("User Not Found", func(t *testing.T) {
-		mockStore.On("GetUserByUsername", "nonexistent").Return(&models.User{}, data.ErrNotFound).Once()
+		mockStore.On("GetUserByUsername", mock.Anything, "nonexistent").Return(&models.User{}, data.ErrNotFound).Once()
 
-		token, err := userService.LoginUser(logger, "nonexistent", "password")
+		tokens, err := userService.LoginUser(logger, t.Context(), "nonexistent", "password")
 		assert.Error(t, err)
-		assert.Empty(t, token)
+		assert.Nil(t, tokens)
 		assert.Equal(t, services.ErrInvalidCredentials, err)
 		mockStore.AssertExpectations(t)
 	})
+
+	testConfig.Exports.ReauthenticationValidity = 5 * time.Minute
+
+	t.Run("Successful Reauthentication", func(t *testing.T) {
+		mockStore.On("GetByID", mock.Anything, 1).Return(testUser, nil).Once()
+
+		token, expiresAt, err := userService.Reauthenticate(logger, t.Context(), 1, "correctpassword")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), expiresAt, time.Second)
+		_, err = middleware.ParseToken(token, "test_secret")
+		assert.Error(t, err, "re-authentication tokens must not be usable as login tokens")
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("Reauthentication With Invalid Password", func(t *testing.T) {
+		mockStore.On("GetByID", mock.Anything, 1).Return(testUser, nil).Once()
+
+		token, _, err := userService.Reauthenticate(logger, t.Context(), 1, "wrongpassword")
+		assert.Equal(t, services.ErrInvalidCredentials, err)
+		assert.Empty(t, token)
+		mockStore.AssertExpectations(t)
+	})
+}
+
+// TestUserService_Sessions tests refreshing, ending and revoking sessions.
+func TestUserService_Sessions(t *testing.T) {
+	testConfig := &config.Config{}
+	testConfig.Server.JWTSecret = "test_secret"
+	testConfig.Session.AccessTokenValidity = 15 * time.Minute
+	testConfig.Session.RefreshTokenValidity = 7 * 24 * time.Hour
+	now := time.Date(2025, time.March, 5, 8, 0, 0, 0, time.UTC)
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	testUser := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
+
+	setup := func() (*services.UserServiceImpl, *mocks.MockUserStore, *mocks.MockAuthSessionStore) {
+		mockStore := new(mocks.MockUserStore)
+		mockSessionStore := new(mocks.MockAuthSessionStore)
+		return services.NewUserService(mockStore, mockSessionStore, testConfig, clock.NewFrozen(now)), mockStore, mockSessionStore
+	}
+	activeSession := func() *models.AuthSession {
+		return &models.AuthSession{ID: 5, UserID: 1, CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+	}
+
+	t.Run("Refresh Rotates The Refresh Token", func(t *testing.T) {
+		userService, mockStore, mockSessionStore := setup()
+		mockSessionStore.On("GetByRefreshTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(activeSession(), nil).Once()
+		mockStore.On("GetByID", mock.Anything, 1).Return(testUser, nil).Once()
+		mockSessionStore.On("Rotate", mock.Anything, 5, mock.AnythingOfType("string"), mock.AnythingOfType("string"), now).Return(nil).Once()
+
+		tokens, err := userService.RefreshSession(logger, ctx, "refresh-token")
+		assert.NoError(t, err)
+		assert.NotEqual(t, "refresh-token", tokens.RefreshToken)
+		assert.True(t, tokens.RefreshTokenExpiresAt.Equal(now.Add(time.Hour)), "refreshing does not extend the session")
+		claims, err := middleware.ParseToken(tokens.AccessToken, "test_secret")
+		assert.NoError(t, err)
+		assert.Equal(t, 5, claims.SessionID)
+		mockSessionStore.AssertExpectations(t)
+	})
+
+	t.Run("Used, Expired And Revoked Refresh Tokens Are Rejected", func(t *testing.T) {
+		userService, _, mockSessionStore := setup()
+		expired := activeSession()
+		expired.ExpiresAt = now
+		revoked := activeSession()
+		revoked.RevokedAt = &now
+		mockSessionStore.On("GetByRefreshTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(nil, data.ErrNotFound).Once()
+		mockSessionStore.On("GetByRefreshTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(expired, nil).Once()
+		mockSessionStore.On("GetByRefreshTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(revoked, nil).Once()
+
+		for range 3 {
+			_, err := userService.RefreshSession(logger, ctx, "refresh-token")
+			assert.Equal(t, services.ErrAuthenticationFailed, err)
+		}
+		mockSessionStore.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Concurrent Refreshes Are Rejected", func(t *testing.T) {
+		userService, mockStore, mockSessionStore := setup()
+		mockSessionStore.On("GetByRefreshTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(activeSession(), nil).Once()
+		mockStore.On("GetByID", mock.Anything, 1).Return(testUser, nil).Once()
+		mockSessionStore.On("Rotate", mock.Anything, 5, mock.Anything, mock.Anything, now).Return(data.ErrConflict).Once()
+
+		_, err := userService.RefreshSession(logger, ctx, "refresh-token")
+		assert.Equal(t, services.ErrAuthenticationFailed, err)
+	})
+
+	t.Run("Ended Sessions Are Invalid", func(t *testing.T) {
+		userService, _, mockSessionStore := setup()
+		mockSessionStore.On("Revoke", mock.Anything, 5, now).Return(data.ErrNotFound).Once()
+		assert.NoError(t, userService.EndSession(logger, ctx, 5), "ending a session twice is not an error")
+
+		revoked := activeSession()
+		revoked.RevokedAt = &now
+		mockSessionStore.On("GetByID", mock.Anything, 5).Return(revoked, nil).Once()
+		mockSessionStore.On("GetByID", mock.Anything, 6).Return(&models.AuthSession{ID: 6, UserID: 2}, nil).Once()
+		mockSessionStore.On("GetByID", mock.Anything, 7).Return(nil, data.ErrNotFound).Once()
+		assert.Equal(t, services.ErrAuthenticationFailed, userService.ValidateSession(logger, ctx, 1, 5))
+		assert.Equal(t, services.ErrAuthenticationFailed, userService.ValidateSession(logger, ctx, 1, 6), "the session belongs to another user")
+		assert.Equal(t, services.ErrAuthenticationFailed, userService.ValidateSession(logger, ctx, 1, 7))
+		mockSessionStore.AssertExpectations(t)
+	})
+
+	t.Run("Password Changes Revoke The Sessions", func(t *testing.T) {
+		userService, mockStore, mockSessionStore := setup()
+		admin := &models.User{ID: 9, Role: string(data.RoleAdmin)}
+		mockStore.On("GetByID", mock.Anything, 1).Return(testUser, nil).Once()
+		mockStore.On("UpdatePassword", mock.Anything, 1, mock.AnythingOfType("string")).Return(nil).Once()
+		mockSessionStore.On("RevokeAllForUser", mock.Anything, 1, now).Return(2, nil).Once()
+
+		assert.NoError(t, userService.ChangePassword(logger, ctx, admin, 1, "", "newpassword"))
+		mockSessionStore.AssertExpectations(t)
+	})
 }
 
 // TestUserService_GetUserByID tests the GetUserByID method.
@@ -139,18 +290,22 @@ func TestUserService_GetUserByID(t *testing.T) {
 			WriteTimeout time.Duration "mapstructure:\"write_timeout\""
 			IdleTimeout  time.Duration "mapstructure:\"idle_timeout\""
 			JWTSecret    string        "mapstructure:\"jwt_secret\""
+			// RequestTimeout bounds the handling of a request, answered with 504 when exceeded. 0 disables it.
+			RequestTimeout time.Duration "mapstructure:\"request_timeout\""
+			// LongRequestTimeout applies instead to report generation, imports and uploads.
+			LongRequestTimeout time.Duration "mapstructure:\"long_request_timeout\""
 		}{
 			JWTSecret: "test_secret",
 		},
 	}
-	userService := services.NewUserService(mockStore, testConfig)
+	userService := services.NewUserService(mockStore, new(mocks.MockAuthSessionStore), testConfig, clock.System{})
 	logger := logrus.NewEntry(logrus.New())
 	ctx := context.Background()
 
 	expectedUser := &models.User{ID: 1, Username: "testuser", Role: "teacher"}
 
 	t.Run("User Found", func(t *testing.T) {
-		mockStore.On("GetByID", 1).Return(expectedUser, nil).Once()
+		mockStore.On("GetByID", mock.Anything, 1).Return(expectedUser, nil).Once()
 		user, err := userService.GetUserByID(logger, ctx, 1)
 		assert.NoError(t, err)
 		assert.Equal(t, expectedUser, user)
@@ -158,7 +313,7 @@ func TestUserService_GetUserByID(t *testing.T) {
 	})
 
 	t.Run("User Not Found", func(t *testing.T) {
-		mockStore.On("GetByID", 2).Return(&models.User{}, data.ErrNotFound).Once()
+		mockStore.On("GetByID", mock.Anything, 2).Return(&models.User{}, data.ErrNotFound).Once()
 		user, err := userService.GetUserByID(logger, ctx, 2)
 		assert.Error(t, err)
 		assert.Nil(t, user)
@@ -167,7 +322,7 @@ func TestUserService_GetUserByID(t *testing.T) {
 	})
 
 	t.Run("Internal Error", func(t *testing.T) {
-		mockStore.On("GetByID", 3).Return(&models.User{}, errors.New("db error")).Once()
+		mockStore.On("GetByID", mock.Anything, 3).Return(&models.User{}, errors.New("db error")).Once()
 		user, err := userService.GetUserByID(logger, ctx, 3)
 		assert.Error(t, err)
 		assert.Nil(t, user)
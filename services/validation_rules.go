@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RuleViolation describes a single broken business rule in a form clients can explain to the user.
+type RuleViolation struct {
+	Rule    string         `json:"rule"`
+	Field   string         `json:"field"`
+	Message string         `json:"message"`
+	Text    string         `json:"text,omitempty"` // German text for users, set for field errors
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// ValidationError is returned when an input has invalid fields or breaks one or more business rules.
+// It is an ErrInvalidInput for errors.Is.
+type ValidationError struct {
+	Violations []RuleViolation `json:"violations"`
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Names of the business rules, reported in RuleViolation.Rule.
+const (
+	RuleObservationDateNotInFuture = "observation_date_not_in_future"
+	RuleMaxObservationAge          = "max_observation_age"
+	RuleMinObservationLength       = "min_observation_length"
+	RuleRequiredCategoryTags       = "required_category_tags"
+	RuleMinWordCount               = "min_word_count"
+	RuleDuplicateObservation       = "duplicate_observation"
+	RuleAssignmentStartNotInFuture = "assignment_start_not_in_future"
+	RuleStructuredData             = "structured_data"
+)
+
+// entryRule checks a single business rule for a documentation entry and returns nil if it holds.
+type entryRule func(rules *models.ValidationRules, entry *models.DocumentationEntry, now time.Time) *RuleViolation
+
+// assignmentRule checks a single business rule for an assignment and returns nil if it holds.
+type assignmentRule func(rules *models.ValidationRules, assignment *models.Assignment, now time.Time) *RuleViolation
+
+var entryRules = []entryRule{
+	observationDateNotInFuture,
+	maxObservationAge,
+	minObservationLength,
+	minWordCount,
+	requiredCategoryTags,
+}
+
+var assignmentRules = []assignmentRule{
+	assignmentStartNotInFuture,
+}
+
+// ValidationRuleService defines the interface for the configurable business rules.
+type ValidationRuleService interface {
+	GetRules(logger *logrus.Entry, ctx context.Context) (*models.ValidationRules, error)
+	UpdateRules(logger *logrus.Entry, ctx context.Context, rules *models.ValidationRules) (*models.ValidationRules, error)
+	ValidateDocumentationEntry(logger *logrus.Entry, ctx context.Context, entry *models.DocumentationEntry) error
+	ValidateAssignment(logger *logrus.Entry, ctx context.Context, assignment *models.Assignment) error
+	// PlausibilityWarnings checks a documentation entry for likely typos that do not prevent saving it.
+	PlausibilityWarnings(logger *logrus.Entry, ctx context.Context, entry *models.DocumentationEntry) []string
+}
+
+// ValidationRuleServiceImpl implements ValidationRuleService.
+type ValidationRuleServiceImpl struct {
+	rulesStore              data.ValidationRulesStore
+	documentationEntryStore data.DocumentationEntryStore // For the duplicate check, nil disables it
+	clock                   clock.Clock
+}
+
+// NewValidationRuleService creates a new ValidationRuleServiceImpl.
+// A nil rulesStore makes the service apply the built-in default rules.
+func NewValidationRuleService(rulesStore data.ValidationRulesStore, documentationEntryStore data.DocumentationEntryStore, clock clock.Clock) *ValidationRuleServiceImpl {
+	return &ValidationRuleServiceImpl{
+		rulesStore:              rulesStore,
+		documentationEntryStore: documentationEntryStore,
+		clock:                   clock,
+	}
+}
+
+// GetRules fetches the configured rules, falling back to the defaults if none are stored.
+func (service *ValidationRuleServiceImpl) GetRules(logger *logrus.Entry, ctx context.Context) (*models.ValidationRules, error) {
+	if service.rulesStore == nil {
+		return &models.ValidationRules{}, nil
+	}
+	rules, err := service.rulesStore.Get(ctx)
+	if err != nil {
+		if errors.Is(err, data.ErrNotFound) {
+			return &models.ValidationRules{}, nil
+		}
+		logger.WithError(err).Error("Error fetching validation rules")
+		return nil, ErrInternal
+	}
+	return rules, nil
+}
+
+// UpdateRules replaces the configured rules.
+func (service *ValidationRuleServiceImpl) UpdateRules(logger *logrus.Entry, ctx context.Context, rules *models.ValidationRules) (*models.ValidationRules, error) {
+	if err := models.ValidateValidationRules(*rules); err != nil {
+		logger.WithError(err).Warn("Invalid input for UpdateRules")
+		return nil, invalidInput(err)
+	}
+	if service.rulesStore == nil {
+		logger.Error("Validation rules cannot be updated without a store")
+		return nil, ErrInternal
+	}
+	for categoryID, tags := range rules.RequiredTagsByCategory {
+		for i, tag := range tags {
+			tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+			if tag == "" || strings.ContainsAny(tag, " \t\n#") {
+				logger.WithField("category_id", categoryID).Warn("Invalid required tag in validation rules")
+				return nil, ErrInvalidInput
+			}
+			tags[i] = "#" + tag
+		}
+	}
+	openDays := make(map[time.Weekday]bool)
+	for _, hours := range rules.OpeningHours {
+		if openDays[hours.Weekday] || hours.Opens >= hours.Closes {
+			logger.WithField("weekday", hours.Weekday).Warn("Invalid opening hours in validation rules")
+			return nil, ErrInvalidInput
+		}
+		openDays[hours.Weekday] = true
+	}
+
+	if err := service.rulesStore.Upsert(ctx, rules); err != nil {
+		logger.WithError(err).Error("Error storing validation rules")
+		return nil, ErrInternal
+	}
+	logger.Info("Validation rules updated successfully")
+	return service.GetRules(logger, ctx)
+}
+
+// ValidateDocumentationEntry checks a documentation entry against all entry rules.
+// All violations are reported together in a *ValidationError.
+func (service *ValidationRuleServiceImpl) ValidateDocumentationEntry(logger *logrus.Entry, ctx context.Context, entry *models.DocumentationEntry) error {
+	rules, err := service.GetRules(logger, ctx)
+	if err != nil {
+		return err
+	}
+	now := service.clock.Now()
+	var violations []RuleViolation
+	for _, rule := range entryRules {
+		if violation := rule(rules, entry, now); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+
+	violation, err := service.duplicateObservation(logger, ctx, rules, entry)
+	if err != nil {
+		return err
+	}
+	if violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	if len(violations) > 0 {
+		logger.WithField("violations", len(violations)).Warn("Documentation entry violates business rules")
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// ValidateAssignment checks an assignment against all assignment rules.
+// All violations are reported together in a *ValidationError.
+func (service *ValidationRuleServiceImpl) ValidateAssignment(logger *logrus.Entry, ctx context.Context, assignment *models.Assignment) error {
+	rules, err := service.GetRules(logger, ctx)
+	if err != nil {
+		return err
+	}
+	now := service.clock.Now()
+	var violations []RuleViolation
+	for _, rule := range assignmentRules {
+		if violation := rule(rules, assignment, now); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+	if len(violations) > 0 {
+		logger.WithField("violations", len(violations)).Warn("Assignment violates business rules")
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// PlausibilityWarnings checks the observation date against the opening days of the facility.
+// Observation dates have no time of day, so only the weekday is checked.
+// The check must not prevent saving an entry, so an error fetching the rules is only logged.
+func (service *ValidationRuleServiceImpl) PlausibilityWarnings(logger *logrus.Entry, ctx context.Context, entry *models.DocumentationEntry) []string {
+	rules, err := service.GetRules(logger, ctx)
+	if err != nil || len(rules.OpeningHours) == 0 {
+		return nil
+	}
+	weekday := entry.ObservationDate.Weekday()
+	if !slices.ContainsFunc(rules.OpeningHours, func(hours models.OpeningHours) bool { return hours.Weekday == weekday }) {
+		return []string{fmt.Sprintf("observation date is a %s, the facility is closed on that day", weekday)}
+	}
+	return nil
+}
+
+// duplicateObservation rejects an observation whose text was already recorded for the same child
+// within the configured number of days, ignoring case and whitespace.
+func (service *ValidationRuleServiceImpl) duplicateObservation(logger *logrus.Entry, ctx context.Context, rules *models.ValidationRules, entry *models.DocumentationEntry) (*RuleViolation, error) {
+	if rules.DuplicateWindowDays == 0 || service.documentationEntryStore == nil {
+		return nil, nil
+	}
+	existingEntries, err := service.documentationEntryStore.GetAllForChild(ctx, entry.ChildID)
+	if err != nil {
+		logger.WithError(err).WithField("child_id", entry.ChildID).Error("Error fetching documentation entries for duplicate check")
+		return nil, ErrInternal
+	}
+
+	text := normalizeObservationText(entry.ObservationDescription)
+	window := time.Duration(rules.DuplicateWindowDays) * 24 * time.Hour
+	for _, existing := range existingEntries {
+		if existing.ID == entry.ID {
+			continue
+		}
+		distance := entry.ObservationDate.Sub(existing.ObservationDate.Time)
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance <= window && normalizeObservationText(existing.ObservationDescription) == text {
+			return &RuleViolation{
+				Rule:    RuleDuplicateObservation,
+				Field:   "observation_description",
+				Message: fmt.Sprintf("the same observation was already recorded for this child within %d days", rules.DuplicateWindowDays),
+				Params: map[string]any{
+					"window_days":       rules.DuplicateWindowDays,
+					"duplicate_of_id":   existing.ID,
+					"duplicate_of_date": existing.ObservationDate.String(),
+				},
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+func normalizeObservationText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+func observationDateNotInFuture(rules *models.ValidationRules, entry *models.DocumentationEntry, now time.Time) *RuleViolation {
+	if rules.AllowFutureObservationDates || !entry.ObservationDate.IsAfterToday(now) {
+		return nil
+	}
+	return &RuleViolation{
+		Rule:    RuleObservationDateNotInFuture,
+		Field:   "observation_date",
+		Message: "observation date cannot be in the future",
+	}
+}
+
+func maxObservationAge(rules *models.ValidationRules, entry *models.DocumentationEntry, now time.Time) *RuleViolation {
+	if rules.MaxObservationAgeDays == 0 || !entry.ObservationDate.Before(models.Today(now).AddDays(-rules.MaxObservationAgeDays).Time) {
+		return nil
+	}
+	return &RuleViolation{
+		Rule:    RuleMaxObservationAge,
+		Field:   "observation_date",
+		Message: fmt.Sprintf("observation date cannot be more than %d days in the past", rules.MaxObservationAgeDays),
+		Params:  map[string]any{"max_days": rules.MaxObservationAgeDays},
+	}
+}
+
+func minObservationLength(rules *models.ValidationRules, entry *models.DocumentationEntry, now time.Time) *RuleViolation {
+	length := utf8.RuneCountInString(strings.TrimSpace(entry.ObservationDescription))
+	if length >= rules.MinObservationLength {
+		return nil
+	}
+	return &RuleViolation{
+		Rule:    RuleMinObservationLength,
+		Field:   "observation_description",
+		Message: fmt.Sprintf("observation description must be at least %d characters long", rules.MinObservationLength),
+		Params:  map[string]any{"min": rules.MinObservationLength, "actual": length},
+	}
+}
+
+func minWordCount(rules *models.ValidationRules, entry *models.DocumentationEntry, now time.Time) *RuleViolation {
+	words := 0
+	for _, field := range strings.Fields(entry.ObservationDescription) {
+		if !strings.HasPrefix(field, "#") {
+			words++
+		}
+	}
+	if words >= rules.MinWordCount {
+		return nil
+	}
+	return &RuleViolation{
+		Rule:    RuleMinWordCount,
+		Field:   "observation_description",
+		Message: fmt.Sprintf("observation description must contain at least %d words", rules.MinWordCount),
+		Params:  map[string]any{"min": rules.MinWordCount, "actual": words},
+	}
+}
+
+var hashtagPattern = regexp.MustCompile(`#[^\s#.,;:!?()]+`)
+
+func requiredCategoryTags(rules *models.ValidationRules, entry *models.DocumentationEntry, now time.Time) *RuleViolation {
+	required := rules.RequiredTagsByCategory[entry.CategoryID]
+	if len(required) == 0 {
+		return nil
+	}
+	present := make(map[string]bool)
+	for _, tag := range hashtagPattern.FindAllString(entry.ObservationDescription, -1) {
+		present[strings.ToLower(tag)] = true
+	}
+	var missing []string
+	for _, tag := range required {
+		if !present[strings.ToLower(tag)] {
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &RuleViolation{
+		Rule:    RuleRequiredCategoryTags,
+		Field:   "observation_description",
+		Message: fmt.Sprintf("observation description is missing required tags: %s", strings.Join(missing, ", ")),
+		Params:  map[string]any{"missing_tags": missing},
+	}
+}
+
+func assignmentStartNotInFuture(rules *models.ValidationRules, assignment *models.Assignment, now time.Time) *RuleViolation {
+	if rules.AllowFutureAssignmentStart || !models.DateOf(assignment.StartDate).IsAfterToday(now) {
+		return nil
+	}
+	return &RuleViolation{
+		Rule:    RuleAssignmentStartNotInFuture,
+		Field:   "start_date",
+		Message: "assignment start date cannot be in the future",
+	}
+}
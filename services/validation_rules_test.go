@@ -0,0 +1,319 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kitadoc-backend/data"
+	datamocks "kitadoc-backend/data/mocks"
+	"kitadoc-backend/internal/clock"
+	"kitadoc-backend/models"
+	"kitadoc-backend/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateDocumentationEntryRules(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	validEntry := func() *models.DocumentationEntry {
+		return &models.DocumentationEntry{
+			CategoryID:             1,
+			ObservationDate:        models.Today(time.Now()).AddDays(-3),
+			ObservationDescription: "Spielt konzentriert mit Bausteinen #motorik",
+		}
+	}
+
+	tests := []struct {
+		name         string
+		rules        *models.ValidationRules
+		modify       func(entry *models.DocumentationEntry)
+		expectedRule string
+	}{
+		{
+			name:  "defaults accept a valid entry",
+			rules: &models.ValidationRules{},
+		},
+		{
+			name:         "future observation date is rejected by default",
+			rules:        &models.ValidationRules{},
+			modify:       func(entry *models.DocumentationEntry) { entry.ObservationDate = models.Today(time.Now()).AddDays(2) },
+			expectedRule: services.RuleObservationDateNotInFuture,
+		},
+		{
+			name:   "future observation date can be allowed",
+			rules:  &models.ValidationRules{AllowFutureObservationDates: true},
+			modify: func(entry *models.DocumentationEntry) { entry.ObservationDate = models.Today(time.Now()).AddDays(2) },
+		},
+		{
+			name:         "observation older than the configured age",
+			rules:        &models.ValidationRules{MaxObservationAgeDays: 2},
+			expectedRule: services.RuleMaxObservationAge,
+		},
+		{
+			name:         "observation text too short",
+			rules:        &models.ValidationRules{MinObservationLength: 100},
+			expectedRule: services.RuleMinObservationLength,
+		},
+		{
+			name:         "required tag missing",
+			rules:        &models.ValidationRules{RequiredTagsByCategory: map[int][]string{1: {"#motorik", "#spiel"}}},
+			expectedRule: services.RuleRequiredCategoryTags,
+		},
+		{
+			name:         "too few words, hashtags not counted",
+			rules:        &models.ValidationRules{MinWordCount: 5},
+			expectedRule: services.RuleMinWordCount,
+		},
+		{
+			name:  "required tags match case-insensitively",
+			rules: &models.ValidationRules{RequiredTagsByCategory: map[int][]string{1: {"#Motorik"}, 2: {"#sprache"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRulesStore := new(datamocks.MockValidationRulesStore)
+			mockRulesStore.On("Get", mock.Anything).Return(tt.rules, nil).Once()
+			service := services.NewValidationRuleService(mockRulesStore, nil, clock.System{})
+
+			entry := validEntry()
+			if tt.modify != nil {
+				tt.modify(entry)
+			}
+			err := service.ValidateDocumentationEntry(logger, ctx, entry)
+
+			if tt.expectedRule == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationError *services.ValidationError
+			if assert.True(t, errors.As(err, &validationError)) {
+				assert.Equal(t, tt.expectedRule, validationError.Violations[0].Rule)
+			}
+		})
+	}
+}
+
+func TestObservationDateInFacilityTimeZone(t *testing.T) {
+	// Kiritimati is 14 hours ahead of UTC, most of the day its date is already the next one.
+	location, err := time.LoadLocation("Pacific/Kiritimati")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+	models.SetFacilityLocation(location)
+	t.Cleanup(func() { models.SetFacilityLocation(time.Local) })
+
+	today := models.Today(time.Now())
+	tests := []struct {
+		name            string
+		observationDate models.Date
+		expectedRule    string
+	}{
+		{name: "today in the facility", observationDate: today},
+		{name: "today in the facility given with its offset", observationDate: models.DateOf(time.Date(today.Year(), today.Month(), today.Day(), 23, 0, 0, 0, location))},
+		{name: "tomorrow in the facility", observationDate: today.AddDays(1), expectedRule: services.RuleObservationDateNotInFuture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRulesStore := new(datamocks.MockValidationRulesStore)
+			mockRulesStore.On("Get", mock.Anything).Return(&models.ValidationRules{}, nil).Once()
+			service := services.NewValidationRuleService(mockRulesStore, nil, clock.System{})
+
+			err := service.ValidateDocumentationEntry(logrus.NewEntry(logrus.New()), context.Background(), &models.DocumentationEntry{
+				CategoryID:             1,
+				ObservationDate:        tt.observationDate,
+				ObservationDescription: "Spielt konzentriert mit Bausteinen",
+			})
+
+			if tt.expectedRule == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationError *services.ValidationError
+			if assert.True(t, errors.As(err, &validationError)) {
+				assert.Equal(t, tt.expectedRule, validationError.Violations[0].Rule)
+			}
+		})
+	}
+}
+
+func TestValidationRulesFallBackToDefaults(t *testing.T) {
+	mockRulesStore := new(datamocks.MockValidationRulesStore)
+	mockRulesStore.On("Get", mock.Anything).Return(nil, data.ErrNotFound).Once()
+	service := services.NewValidationRuleService(mockRulesStore, nil, clock.System{})
+
+	err := service.ValidateAssignment(logrus.NewEntry(logrus.New()), context.Background(), &models.Assignment{StartDate: time.Now().Add(48 * time.Hour)})
+
+	var validationError *services.ValidationError
+	if assert.True(t, errors.As(err, &validationError)) {
+		assert.Equal(t, services.RuleAssignmentStartNotInFuture, validationError.Violations[0].Rule)
+	}
+}
+
+func TestUpdateValidationRules(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+
+	t.Run("tags are normalized", func(t *testing.T) {
+		mockRulesStore := new(datamocks.MockValidationRulesStore)
+		service := services.NewValidationRuleService(mockRulesStore, nil, clock.System{})
+		mockRulesStore.On("Upsert", mock.Anything, mock.MatchedBy(func(rules *models.ValidationRules) bool {
+			return rules.RequiredTagsByCategory[1][0] == "#sprache"
+		})).Return(nil).Once()
+		mockRulesStore.On("Get", mock.Anything).Return(&models.ValidationRules{}, nil).Once()
+
+		_, err := service.UpdateRules(logger, ctx, &models.ValidationRules{RequiredTagsByCategory: map[int][]string{1: {" Sprache "}}})
+		assert.NoError(t, err)
+		mockRulesStore.AssertExpectations(t)
+	})
+
+	t.Run("invalid opening hours are rejected", func(t *testing.T) {
+		service := services.NewValidationRuleService(new(datamocks.MockValidationRulesStore), nil, clock.System{})
+		for _, openingHours := range [][]models.OpeningHours{
+			{{Weekday: time.Monday, Opens: "17:00", Closes: "07:00"}},
+			{{Weekday: time.Monday, Opens: "7:00", Closes: "17:00"}},
+			{{Weekday: time.Monday, Opens: "07:00", Closes: "12:00"}, {Weekday: time.Monday, Opens: "13:00", Closes: "17:00"}},
+			{{Weekday: 7, Opens: "07:00", Closes: "17:00"}},
+		} {
+			_, err := service.UpdateRules(logger, ctx, &models.ValidationRules{OpeningHours: openingHours})
+			assert.ErrorIs(t, err, services.ErrInvalidInput, "%v", openingHours)
+		}
+	})
+
+	t.Run("negative values are rejected", func(t *testing.T) {
+		service := services.NewValidationRuleService(new(datamocks.MockValidationRulesStore), nil, clock.System{})
+		_, err := service.UpdateRules(logger, ctx, &models.ValidationRules{MaxObservationAgeDays: -1})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+}
+
+func TestValidateDocumentationEntryQualityChecks(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	observationDate := models.Today(time.Now()).AddDays(-1)
+
+	t.Run("duplicate text for the same child within the window", func(t *testing.T) {
+		mockRulesStore := new(datamocks.MockValidationRulesStore)
+		mockEntryStore := new(datamocks.MockDocumentationEntryStore)
+		service := services.NewValidationRuleService(mockRulesStore, mockEntryStore, clock.System{})
+		mockRulesStore.On("Get", mock.Anything).Return(&models.ValidationRules{DuplicateWindowDays: 7}, nil).Once()
+		mockEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{
+			{ID: 3, ChildID: 1, ObservationDate: observationDate.AddDays(-20), ObservationDescription: "Baut einen hohen Turm."},
+			{ID: 4, ChildID: 1, ObservationDate: observationDate.AddDays(-2), ObservationDescription: "Baut einen  hohen Turm. "},
+		}, nil).Once()
+
+		err := service.ValidateDocumentationEntry(logger, ctx, &models.DocumentationEntry{
+			ChildID:                1,
+			ObservationDate:        observationDate,
+			ObservationDescription: "baut einen hohen Turm.",
+		})
+
+		var validationError *services.ValidationError
+		if assert.True(t, errors.As(err, &validationError)) {
+			assert.Len(t, validationError.Violations, 1)
+			assert.Equal(t, services.RuleDuplicateObservation, validationError.Violations[0].Rule)
+			assert.Equal(t, 4, validationError.Violations[0].Params["duplicate_of_id"])
+		}
+	})
+
+	t.Run("updating an entry does not count it as its own duplicate", func(t *testing.T) {
+		mockRulesStore := new(datamocks.MockValidationRulesStore)
+		mockEntryStore := new(datamocks.MockDocumentationEntryStore)
+		service := services.NewValidationRuleService(mockRulesStore, mockEntryStore, clock.System{})
+		mockRulesStore.On("Get", mock.Anything).Return(&models.ValidationRules{DuplicateWindowDays: 7}, nil).Once()
+		mockEntryStore.On("GetAllForChild", mock.Anything, 1).Return([]models.DocumentationEntry{
+			{ID: 4, ChildID: 1, ObservationDate: observationDate, ObservationDescription: "Baut einen hohen Turm."},
+		}, nil).Once()
+
+		err := service.ValidateDocumentationEntry(logger, ctx, &models.DocumentationEntry{
+			ID:                     4,
+			ChildID:                1,
+			ObservationDate:        observationDate,
+			ObservationDescription: "Baut einen hohen Turm.",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("all violations are reported together", func(t *testing.T) {
+		mockRulesStore := new(datamocks.MockValidationRulesStore)
+		service := services.NewValidationRuleService(mockRulesStore, nil, clock.System{})
+		mockRulesStore.On("Get", mock.Anything).Return(&models.ValidationRules{MinWordCount: 10, MinObservationLength: 200}, nil).Once()
+
+		err := service.ValidateDocumentationEntry(logger, ctx, &models.DocumentationEntry{
+			ObservationDate:        models.Today(time.Now()).AddDays(2),
+			ObservationDescription: "Zu kurz.",
+		})
+
+		var validationError *services.ValidationError
+		if assert.True(t, errors.As(err, &validationError)) {
+			var rules []string
+			for _, violation := range validationError.Violations {
+				rules = append(rules, violation.Rule)
+			}
+			assert.ElementsMatch(t, []string{services.RuleObservationDateNotInFuture, services.RuleMinObservationLength, services.RuleMinWordCount}, rules)
+		}
+	})
+}
+
+func TestPlausibilityWarnings(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	ctx := context.Background()
+	weekdays := &models.ValidationRules{OpeningHours: []models.OpeningHours{
+		{Weekday: time.Monday, Opens: "07:00", Closes: "17:00"},
+		{Weekday: time.Tuesday, Opens: "07:00", Closes: "17:00"},
+		{Weekday: time.Wednesday, Opens: "07:00", Closes: "17:00"},
+		{Weekday: time.Thursday, Opens: "07:00", Closes: "17:00"},
+		{Weekday: time.Friday, Opens: "07:00", Closes: "14:00"},
+	}}
+
+	tests := []struct {
+		name             string
+		rules            *models.ValidationRules
+		observationDate  models.Date
+		expectedWarnings []string
+	}{
+		{
+			name:            "no opening hours configured",
+			rules:           &models.ValidationRules{},
+			observationDate: models.NewDate(2025, time.March, 2),
+		},
+		{
+			name:            "open day",
+			rules:           weekdays,
+			observationDate: models.NewDate(2025, time.March, 3),
+		},
+		{
+			name:             "closed day",
+			rules:            weekdays,
+			observationDate:  models.NewDate(2025, time.March, 2),
+			expectedWarnings: []string{"observation date is a Sunday, the facility is closed on that day"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRulesStore := new(datamocks.MockValidationRulesStore)
+			mockRulesStore.On("Get", mock.Anything).Return(tt.rules, nil).Once()
+			service := services.NewValidationRuleService(mockRulesStore, nil, clock.System{})
+
+			warnings := service.PlausibilityWarnings(logger, ctx, &models.DocumentationEntry{ObservationDate: tt.observationDate})
+
+			assert.Equal(t, tt.expectedWarnings, warnings)
+		})
+	}
+
+	t.Run("errors fetching the rules produce no warnings", func(t *testing.T) {
+		mockRulesStore := new(datamocks.MockValidationRulesStore)
+		mockRulesStore.On("Get", mock.Anything).Return(nil, errors.New("database error")).Once()
+		service := services.NewValidationRuleService(mockRulesStore, nil, clock.System{})
+
+		assert.Empty(t, service.PlausibilityWarnings(logger, ctx, &models.DocumentationEntry{ObservationDate: models.Today(time.Now())}))
+	})
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunkSize is the size of the chunks a file is streamed to clamd in, well below its StreamMaxLength.
+const clamdChunkSize = 64 << 10
+
+// VirusScanner checks the content of an uploaded file for malware.
+type VirusScanner interface {
+	// Scan returns the name of the malware found in the content, empty if it is clean. An error means the content
+	// could not be scanned.
+	Scan(ctx context.Context, content []byte) (string, error)
+}
+
+// NoopVirusScanner accepts every file, it is used when no scanner is configured.
+type NoopVirusScanner struct{}
+
+// Scan reports every content as clean.
+func (NoopVirusScanner) Scan(ctx context.Context, content []byte) (string, error) {
+	return "", nil
+}
+
+// ClamAVScanner scans files with a clamd daemon, streaming them with the INSTREAM command.
+type ClamAVScanner struct {
+	network string // "tcp" or "unix"
+	address string
+	timeout time.Duration
+}
+
+// NewClamAVScanner creates a new ClamAVScanner for a daemon address like tcp://127.0.0.1:3310 or
+// unix:///run/clamav/clamd.ctl.
+func NewClamAVScanner(address string, timeout time.Duration) (*ClamAVScanner, error) {
+	network, host, ok := strings.Cut(address, "://")
+	if !ok || (network != "tcp" && network != "unix") || host == "" {
+		return nil, fmt.Errorf("invalid clamd address %q", address)
+	}
+	return &ClamAVScanner{network: network, address: host, timeout: timeout}, nil
+}
+
+// Scan streams the content to clamd and parses its verdict, e.g. "stream: OK" or "stream: Eicar-Signature FOUND".
+func (scanner *ClamAVScanner) Scan(ctx context.Context, content []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, scanner.timeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, scanner.network, scanner.address)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close() //nolint:errcheck
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return "", err
+		}
+	}
+
+	writer := bufio.NewWriter(conn)
+	if _, err := writer.WriteString("zINSTREAM\x00"); err != nil {
+		return "", fmt.Errorf("failed to send clamd command: %w", err)
+	}
+	for start := 0; start < len(content); start += clamdChunkSize {
+		chunk := content[start:min(start+clamdChunkSize, len(content))]
+		if err := binary.Write(writer, binary.BigEndian, uint32(len(chunk))); err != nil {
+			return "", fmt.Errorf("failed to stream file to clamd: %w", err)
+		}
+		if _, err := writer.Write(chunk); err != nil {
+			return "", fmt.Errorf("failed to stream file to clamd: %w", err)
+		}
+	}
+	// A chunk of length zero ends the stream.
+	if err := binary.Write(writer, binary.BigEndian, uint32(0)); err != nil {
+		return "", fmt.Errorf("failed to stream file to clamd: %w", err)
+	}
+	if err := writer.Flush(); err != nil {
+		return "", fmt.Errorf("failed to stream file to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\x00')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return "", nil
+	case strings.HasSuffix(result, " FOUND"):
+		return strings.TrimSuffix(result, " FOUND"), nil
+	default:
+		return "", fmt.Errorf("clamd could not scan the file: %s", reply)
+	}
+}
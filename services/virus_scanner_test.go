@@ -0,0 +1,106 @@
+package services_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"kitadoc-backend/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClamd answers INSTREAM commands like clamd, reporting streams containing "EICAR" as infected.
+func fakeClamd(t *testing.T, reply string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() }) //nolint:errcheck
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close() //nolint:errcheck
+				reader := bufio.NewReader(conn)
+				command, err := reader.ReadString('\x00')
+				if err != nil || command != "zINSTREAM\x00" {
+					return
+				}
+				var content strings.Builder
+				for {
+					var length uint32
+					if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+						return
+					}
+					if length == 0 {
+						break
+					}
+					if _, err := io.CopyN(&content, reader, int64(length)); err != nil {
+						return
+					}
+				}
+				switch {
+				case reply != "":
+					conn.Write([]byte(reply + "\x00")) //nolint:errcheck
+				case strings.Contains(content.String(), "EICAR"):
+					conn.Write([]byte("stream: Eicar-Signature FOUND\x00")) //nolint:errcheck
+				default:
+					conn.Write([]byte("stream: OK\x00")) //nolint:errcheck
+				}
+			}()
+		}
+	}()
+	return "tcp://" + listener.Addr().String()
+}
+
+func TestClamAVScanner(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("clean and infected files", func(t *testing.T) {
+		scanner, err := services.NewClamAVScanner(fakeClamd(t, ""), 5*time.Second)
+		require.NoError(t, err)
+
+		signature, err := scanner.Scan(ctx, []byte("a harmless photo"))
+		require.NoError(t, err)
+		assert.Empty(t, signature)
+
+		// The file is streamed in chunks, the signature may span them
+		large := append([]byte(strings.Repeat("x", 64<<10-2)), []byte("EICAR")...)
+		signature, err = scanner.Scan(ctx, large)
+		require.NoError(t, err)
+		assert.Equal(t, "Eicar-Signature", signature)
+	})
+
+	t.Run("scan errors", func(t *testing.T) {
+		scanner, err := services.NewClamAVScanner(fakeClamd(t, "INSTREAM size limit exceeded. ERROR"), 5*time.Second)
+		require.NoError(t, err)
+		_, err = scanner.Scan(ctx, []byte("a large file"))
+		assert.ErrorContains(t, err, "size limit exceeded")
+	})
+
+	t.Run("unreachable daemon", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		scanner, err := services.NewClamAVScanner("tcp://"+address, time.Second)
+		require.NoError(t, err)
+		_, err = scanner.Scan(ctx, []byte("a photo"))
+		assert.ErrorContains(t, err, "failed to connect to clamd")
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		_, err := services.NewClamAVScanner("127.0.0.1:3310", time.Second)
+		assert.Error(t, err)
+	})
+}
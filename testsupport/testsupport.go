@@ -0,0 +1,295 @@
+// Package testsupport starts the full application on an in-memory database for integration and e2e tests.
+//
+// Every Harness gets its own database, empty except for the masterdata of the facility. The builders create the data a test needs through the services
+// of the application and return the created records, so tests use the IDs they got instead of assuming the
+// order in which other tests inserted rows.
+package testsupport
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	_ "modernc.org/sqlite"
+
+	"kitadoc-backend/app"
+	"kitadoc-backend/config"
+	"kitadoc-backend/data"
+	"kitadoc-backend/internal/logger"
+	"kitadoc-backend/migrations"
+	"kitadoc-backend/models"
+)
+
+// Password is the password of every account created by a Harness.
+const Password = "testsupport-password"
+
+// databases numbers the in-memory databases, each Harness gets its own.
+var databases atomic.Int64
+
+// initLogger initializes the global logger the services log to, unless the test did already.
+var initLogger sync.Once
+
+// Harness is the application running on an in-memory database behind a test server.
+type Harness struct {
+	App    *app.Application
+	Server *httptest.Server
+	DB     *sql.DB
+	DAL    *data.DAL
+	Config config.Config
+
+	t     testing.TB
+	log   *logrus.Entry
+	names atomic.Int64 // Makes the generated names unique
+}
+
+// New starts the application on a new in-memory database, which is closed at the end of the test.
+// The clock of the application is frozen at the current time and is moved with SetClock.
+// The options adjust the configuration before the application is created.
+func New(t testing.TB, options ...func(cfg *config.Config)) *Harness {
+	t.Helper()
+	cfg, err := config.LoadConfig(config.ProfileFixture)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	cfg.Database.DSN = fmt.Sprintf("file:testsupport-%d?mode=memory&cache=shared&_pragma=foreign_keys(1)", databases.Add(1))
+	for _, option := range options {
+		option(cfg)
+	}
+	initLogger.Do(func() {
+		if logger.GetGlobalLogger() != nil {
+			return
+		}
+		level, err := logrus.ParseLevel(cfg.Log.Level)
+		if err != nil {
+			level = logrus.InfoLevel
+		}
+		logger.InitGlobalLogger(level, &logrus.TextFormatter{FullTimestamp: true})
+	})
+
+	db, err := sql.Open("sqlite", cfg.Database.DSN)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	// The in-memory database lives as long as its connection, writes are serialized anyway.
+	db.SetMaxOpenConns(1)
+	if err := data.MigrateDB(db, migrations.Files); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	if _, err := data.RunDataMigrations(db, data.DataMigrations, []byte(cfg.Database.EncryptionKey)); err != nil {
+		t.Fatalf("Failed to run data migrations: %v", err)
+	}
+
+	dal := data.NewDAL(db, data.DriverSQLite, []byte(cfg.Database.EncryptionKey))
+	// Every installation has the masterdata of its facility after the setup, e.g. the reports print it.
+	if err := dal.KitaMasterdata.Update(t.Context(), &models.KitaMasterdata{
+		Name:        "Test Kita",
+		Street:      "Teststraße",
+		HouseNumber: "1",
+		PostalCode:  "12345",
+		City:        "Teststadt",
+		PhoneNumber: "0123 456789",
+		Email:       "kita@example.com",
+	}); err != nil {
+		t.Fatalf("Failed to create the masterdata: %v", err)
+	}
+	harness := &Harness{
+		App:    app.NewApplication(*cfg, dal),
+		DB:     db,
+		DAL:    dal,
+		Config: *cfg,
+		t:      t,
+		log:    logger.GetGlobalLogger().GetLogrusEntry(),
+	}
+	harness.SetClock(time.Now().UTC().Truncate(time.Second))
+	harness.Server = httptest.NewServer(harness.App.Routes())
+	t.Cleanup(harness.Server.Close)
+	return harness
+}
+
+// Now returns the time of the frozen clock of the application.
+func (h *Harness) Now() time.Time {
+	return h.App.Fixtures.GetState().Now
+}
+
+// SetClock moves the frozen clock of the application, e.g. to test what happens on the next day.
+func (h *Harness) SetClock(now time.Time) {
+	h.App.Fixtures.SetClock(h.log, now)
+}
+
+// uniqueName returns prefix with a number not used before by this Harness.
+func (h *Harness) uniqueName(prefix string) string {
+	return fmt.Sprintf("%s%d", prefix, h.names.Add(1))
+}
+
+// MustCreateUser creates an account with role and the password Password.
+func (h *Harness) MustCreateUser(role string) *models.User {
+	h.t.Helper()
+	user, err := h.App.AuthHandler.UserService.CreateUser(h.log, h.t.Context(), h.uniqueName(role), Password, role)
+	if err != nil {
+		h.t.Fatalf("Failed to create %s user: %v", role, err)
+	}
+	return user
+}
+
+// MustLogin logs in an account created by the Harness and returns its token.
+func (h *Harness) MustLogin(username string) string {
+	h.t.Helper()
+	tokens, err := h.App.AuthHandler.UserService.LoginUser(h.log, h.t.Context(), username, Password)
+	if err != nil {
+		h.t.Fatalf("Failed to log in %s: %v", username, err)
+	}
+	return tokens.AccessToken
+}
+
+// MustCreateTeacher creates teacher together with the account of the same username. Empty names are generated.
+func (h *Harness) MustCreateTeacher(teacher models.Teacher) *models.Teacher {
+	h.t.Helper()
+	if teacher.Username == "" {
+		teacher.Username = h.uniqueName("teacher")
+	}
+	if teacher.FirstName == "" {
+		teacher.FirstName = "Test"
+	}
+	if teacher.LastName == "" {
+		teacher.LastName = h.uniqueName("Teacher")
+	}
+	if _, err := h.App.AuthHandler.UserService.CreateUser(h.log, h.t.Context(), teacher.Username, Password, string(data.RoleTeacher)); err != nil {
+		h.t.Fatalf("Failed to create the account of teacher %s: %v", teacher.Username, err)
+	}
+	created, err := h.App.TeacherHandler.TeacherService.CreateTeacher(h.t.Context(), &teacher)
+	if err != nil {
+		h.t.Fatalf("Failed to create teacher: %v", err)
+	}
+	return created
+}
+
+// MustCreateCategory creates category. An empty name is generated.
+func (h *Harness) MustCreateCategory(category models.Category) *models.Category {
+	h.t.Helper()
+	if category.Name == "" {
+		category.Name = h.uniqueName("Category ")
+	}
+	created, err := h.App.CategoryHandler.CategoryService.CreateCategory(h.t.Context(), &category)
+	if err != nil {
+		h.t.Fatalf("Failed to create category: %v", err)
+	}
+	return created
+}
+
+// MustCreateChild creates child. Empty names are generated, a missing birthdate makes the child four years old.
+func (h *Harness) MustCreateChild(child models.Child) *models.Child {
+	h.t.Helper()
+	if child.FirstName == "" {
+		child.FirstName = "Test"
+	}
+	if child.LastName == "" {
+		child.LastName = h.uniqueName("Child")
+	}
+	if child.Birthdate.IsZero() {
+		child.Birthdate = models.Today(h.Now()).AddDays(-4 * 365)
+	}
+	created, err := h.App.ChildHandler.ChildService.CreateChild(h.t.Context(), &child)
+	if err != nil {
+		h.t.Fatalf("Failed to create child: %v", err)
+	}
+	return created
+}
+
+// MustCreateAssignment assigns the child to the teacher from today on.
+func (h *Harness) MustCreateAssignment(childID, teacherID int) *models.Assignment {
+	h.t.Helper()
+	created, err := h.App.AssignmentHandler.AssignmentService.CreateAssignment(h.t.Context(), &models.Assignment{
+		ChildID:        childID,
+		TeacherID:      teacherID,
+		AssignmentType: models.AssignmentTypePrimary,
+		StartDate:      h.Now(),
+	})
+	if err != nil {
+		h.t.Fatalf("Failed to assign child %d to teacher %d: %v", childID, teacherID, err)
+	}
+	return created
+}
+
+// MustCreateEntry creates entry. A child, teacher or category that is not set is created, a missing
+// observation is observed today.
+func (h *Harness) MustCreateEntry(entry models.DocumentationEntry) *models.DocumentationEntry {
+	h.t.Helper()
+	if entry.ChildID == 0 {
+		entry.ChildID = h.MustCreateChild(models.Child{}).ID
+	}
+	if entry.TeacherID == 0 {
+		entry.TeacherID = h.MustCreateTeacher(models.Teacher{}).ID
+	}
+	if entry.CategoryID == 0 {
+		entry.CategoryID = h.MustCreateCategory(models.Category{}).ID
+	}
+	if entry.ObservationDate.IsZero() {
+		entry.ObservationDate = models.Today(h.Now())
+	}
+	if entry.ObservationDescription == "" {
+		entry.ObservationDescription = "Spielt ausdauernd mit den Bausteinen in der Bauecke."
+	}
+	created, err := h.App.DocumentationEntryHandler.DocumentationEntryService.CreateDocumentationEntry(h.log, context.Background(), &entry)
+	if err != nil {
+		h.t.Fatalf("Failed to create documentation entry: %v", err)
+	}
+	return created
+}
+
+// Do sends a request with body encoded as JSON to the test server. An empty token sends no Authorization header.
+func (h *Harness) Do(method, path, token string, body any) *http.Response {
+	h.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			h.t.Fatalf("Failed to marshal request body: %v", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	request, err := http.NewRequest(method, h.Server.URL+path, reader)
+	if err != nil {
+		h.t.Fatalf("Failed to create request: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := h.Server.Client().Do(request)
+	if err != nil {
+		h.t.Fatalf("Failed to send %s %s: %v", method, path, err)
+	}
+	return response
+}
+
+// MustDo sends a request like Do, fails the test unless the response has wantStatus and decodes the
+// response into out, if it is not nil.
+func (h *Harness) MustDo(method, path, token string, body any, wantStatus int, out any) {
+	h.t.Helper()
+	response := h.Do(method, path, token, body)
+	defer response.Body.Close() //nolint:errcheck
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		h.t.Fatalf("Failed to read the response of %s %s: %v", method, path, err)
+	}
+	if response.StatusCode != wantStatus {
+		h.t.Fatalf("%s %s: expected status %d, got %d: %s", method, path, wantStatus, response.StatusCode, responseBody)
+	}
+	if out == nil {
+		return
+	}
+	if err := json.Unmarshal(responseBody, out); err != nil {
+		h.t.Fatalf("Failed to unmarshal the response of %s %s: %v", method, path, err)
+	}
+}
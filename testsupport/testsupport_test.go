@@ -0,0 +1,65 @@
+package testsupport_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"kitadoc-backend/data"
+	"kitadoc-backend/models"
+	"kitadoc-backend/testsupport"
+)
+
+func TestHarness(t *testing.T) {
+	h := testsupport.New(t)
+	admin := h.MustCreateUser(string(data.RoleAdmin))
+	adminToken := h.MustLogin(admin.Username)
+
+	teacher := h.MustCreateTeacher(models.Teacher{FirstName: "Maria"})
+	child := h.MustCreateChild(models.Child{FirstName: "Anna"})
+	h.MustCreateAssignment(child.ID, teacher.ID)
+	entry := h.MustCreateEntry(models.DocumentationEntry{ChildID: child.ID, TeacherID: teacher.ID})
+	other := h.MustCreateEntry(models.DocumentationEntry{})
+
+	t.Run("Builders Return The Created Records", func(t *testing.T) {
+		if entry.ChildID != child.ID || entry.TeacherID != teacher.ID || entry.CategoryID == 0 {
+			t.Errorf("Unexpected entry %+v", entry)
+		}
+		if other.ChildID == child.ID || other.TeacherID == teacher.ID || other.CategoryID == entry.CategoryID {
+			t.Errorf("Expected the second entry to get its own child, teacher and category, got %+v", other)
+		}
+		if !entry.ObservationDate.Equal(models.Today(h.Now()).Time) {
+			t.Errorf("Expected an observation of today, got %s", entry.ObservationDate)
+		}
+	})
+
+	t.Run("Requests Use The Created Accounts", func(t *testing.T) {
+		var fetched models.Child
+		h.MustDo(http.MethodGet, fmt.Sprintf("/api/v1/children/%d", child.ID), h.MustLogin(teacher.Username), nil, http.StatusOK, &fetched)
+		if fetched.FirstName != "Anna" {
+			t.Errorf("Expected child Anna, got %+v", fetched)
+		}
+		h.MustDo(http.MethodGet, "/api/v1/children", "", nil, http.StatusUnauthorized, nil)
+
+		var children []models.Child
+		h.MustDo(http.MethodGet, "/api/v1/children", adminToken, nil, http.StatusOK, &children)
+		if len(children) != 2 {
+			t.Errorf("Expected the 2 created children, got %d", len(children))
+		}
+	})
+
+	t.Run("Harnesses Do Not Share Data", func(t *testing.T) {
+		if _, err := testsupport.New(t).DAL.Children.GetByID(t.Context(), child.ID); !errors.Is(err, data.ErrNotFound) {
+			t.Errorf("Expected no child in a new harness, got %v", err)
+		}
+	})
+
+	t.Run("Clock Can Be Moved", func(t *testing.T) {
+		tomorrow := h.Now().AddDate(0, 0, 1)
+		h.SetClock(tomorrow)
+		if !h.Now().Equal(tomorrow) {
+			t.Errorf("Expected clock at %s, got %s", tomorrow, h.Now())
+		}
+	})
+}